@@ -3,32 +3,80 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	"tixgo/components"
 	"tixgo/config"
+	"tixgo/jobs"
+	bookingPort "tixgo/modules/booking/ports"
+	compliancePort "tixgo/modules/compliance/ports"
+	dashboardPort "tixgo/modules/dashboard/ports"
+	eventDomain "tixgo/modules/event/domain"
+	eventPort "tixgo/modules/event/ports"
+	notificationPort "tixgo/modules/notification/ports"
+	orderPort "tixgo/modules/order/ports"
+	organizerDomain "tixgo/modules/organizer/domain"
+	organizerPort "tixgo/modules/organizer/ports"
+	promotionPort "tixgo/modules/promotion/ports"
+	schedulerPort "tixgo/modules/scheduler/ports"
+	supportPort "tixgo/modules/support/ports"
+	templateDomain "tixgo/modules/template/domain"
 	templatePort "tixgo/modules/template/ports"
+	ticketPort "tixgo/modules/ticket/ports"
+	userAdapters "tixgo/modules/user/adapters"
+	userDomain "tixgo/modules/user/domain"
 	userPort "tixgo/modules/user/ports"
-
-	"github.com/IBM/sarama"
-	"github.com/ThreeDotsLabs/watermill"
-	"github.com/ThreeDotsLabs/watermill-kafka/v3/pkg/kafka"
-	"github.com/duongptryu/gox/auth"
+	venuePort "tixgo/modules/venue/ports"
+	"tixgo/schemas"
+	"tixgo/shared/apiversion"
+	"tixgo/shared/assets"
+	"tixgo/shared/authz"
+	"tixgo/shared/barcode"
+	"tixgo/shared/dailycount"
+	"tixgo/shared/dedup"
+	"tixgo/shared/dryrun"
+	"tixgo/shared/eventbus"
+	sharedSMS "tixgo/shared/events/sms"
+	"tixgo/shared/feature"
+	"tixgo/shared/health"
+	"tixgo/shared/httpdump"
+	"tixgo/shared/httpguard"
+	"tixgo/shared/httpresponse"
+	"tixgo/shared/jsonschema"
+	sharedKafka "tixgo/shared/kafka"
+	"tixgo/shared/migrationlint"
+	"tixgo/shared/ratelimit"
+	"tixgo/shared/readonly"
+	"tixgo/shared/requestid"
+	"tixgo/shared/secheaders"
+	"tixgo/shared/session"
+	"tixgo/shared/shortlink"
+	"tixgo/shared/webhook"
+
+	pkgContext "github.com/duongptryu/gox/context"
 	"github.com/duongptryu/gox/database"
 	"github.com/duongptryu/gox/logger"
 	"github.com/duongptryu/gox/messaging"
 	"github.com/duongptryu/gox/server/httpserver"
 	"github.com/duongptryu/gox/syserr"
 
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 func main() {
+	allowUnsafe := flag.Bool("allow-unsafe", false, "apply pending migrations even when they contain unsafe operations")
+	flag.Parse()
+
 	// Initialize logger first
 	logger.Init(&logger.Config{
 		Level:     slog.LevelInfo,
@@ -44,13 +92,30 @@ func main() {
 	if err != nil {
 		logger.Fatal(ctx, "Failed to load configuration", logger.F("error", err))
 	}
+	components.LogConfigSources(ctx, cfg)
+	cfg.MustDisableDebugInProd()
 
 	logger.Info(ctx, "Configuration loaded successfully",
 		logger.F("environment", cfg.App.Environment),
 		logger.F("debug_mode", cfg.App.DebugMode))
+	if toggles := cfg.DebugToggles(); len(toggles) > 0 {
+		logger.Warning(ctx, "Debugging aids are on, they expose verification codes, drafts or request bodies",
+			logger.F("toggles", toggles))
+	}
+
+	// Keys signing the QR codes of tickets
+	ticketKeys, err := ticketKeyring(cfg.Orders.TicketQR)
+	if err != nil {
+		logger.Fatal(ctx, "Invalid ticket QR configuration", logger.F("error", err))
+	}
+
+	// Variables every template renders with unless given
+	if err := templatePort.ConfigureDefaults(cfg.Templates); err != nil {
+		logger.Fatal(ctx, "Invalid template defaults configuration", logger.F("error", err))
+	}
 
 	// Connect to database
-	db, err := connectDatabase(ctx, &cfg.Database)
+	db, err := components.ConnectDatabase(ctx, &cfg.Database)
 	if err != nil {
 		logger.Fatal(ctx, "Failed to connect to database", logger.F("error", err))
 	}
@@ -59,52 +124,44 @@ func main() {
 	logger.Info(ctx, "Database connected successfully")
 
 	// Run migrations
-	if err := runMigrations(ctx, db, &cfg.Database); err != nil {
+	if err := runMigrations(ctx, db, &cfg.Database, *allowUnsafe); err != nil {
 		logger.Fatal(ctx, "Failed to run migrations", logger.F("error", err))
 	}
 
-	// Initialize app context
-	appCtx, err := setupAppCtx(ctx, cfg, db)
+	// Connect to redis
+	redisClient, err := components.ConnectRedis(ctx, &cfg.Redis)
 	if err != nil {
-		logger.Fatal(ctx, "Failed to initialize app context", logger.F("error", err))
+		logger.Fatal(ctx, "Failed to connect to redis", logger.F("error", err))
 	}
+	defer redisClient.Close()
 
-	// register event handlers
-	startMessagingHandler(ctx, appCtx)
+	logger.Info(ctx, "Redis connected successfully")
 
-	// Setup HTTP server using server package
-	srv := setupHTTPServer(ctx, cfg, appCtx)
-
-	// Start server with graceful shutdown
-	startServer(ctx, srv)
-}
-
-func connectDatabase(ctx context.Context, cfg *config.Database) (*sqlx.DB, error) {
-	// Build connection string
-	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, cfg.SSLMode)
-
-	// Connect to database
-	db, err := sqlx.Connect("postgres", dsn)
+	// Initialize app context, waiting a while for kafka before starting degraded without it
+	appCtx, err := components.SetupAppCtx(ctx, cfg, db, redisClient)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
+		logger.Fatal(ctx, "Failed to initialize app context", logger.F("error", err))
 	}
 
-	// Configure connection pool
-	db.SetMaxOpenConns(cfg.MaxOpenConns)
-	db.SetMaxIdleConns(cfg.MaxIdleConns)
-	db.SetConnMaxLifetime(cfg.MaxLifetime)
-	db.SetConnMaxIdleTime(cfg.MaxIdleTime)
+	// register event handlers, consumed once kafka is reachable
+	startMessagingHandler(ctx, cfg, appCtx, ticketKeys)
 
-	// Test connection
-	if err := db.Ping(); err != nil {
-		return nil, fmt.Errorf("failed to ping database: %w", err)
-	}
+	// Watch how far the consumers are behind
+	lagMonitor := startLagMonitor(ctx, cfg)
+	defer lagMonitor.Close()
 
-	return db, nil
+	// Pick up the read-only mode admins set on any instance
+	readOnly := readonly.NewSwitch(redisClient, cfg.Server.ReadOnly)
+	go readOnly.Run(ctx, readonly.DefaultRefreshInterval)
+
+	// Setup HTTP server using server package
+	srv := setupHTTPServer(ctx, cfg, appCtx, lagMonitor, readOnly, ticketKeys)
+
+	// Start server with graceful shutdown
+	startServer(ctx, srv)
 }
 
-func runMigrations(ctx context.Context, db *sqlx.DB, cfg *config.Database) error {
+func runMigrations(ctx context.Context, db *sqlx.DB, cfg *config.Database, allowUnsafe bool) error {
 	logger.Info(ctx, "Running database migrations...")
 
 	// Get SQL database instance for migrations
@@ -128,6 +185,11 @@ func runMigrations(ctx context.Context, db *sqlx.DB, cfg *config.Database) error
 		return fmt.Errorf("failed to create migration manager: %w", err)
 	}
 
+	// Refuse unsafe pending migrations unless explicitly allowed
+	if err := lintPendingMigrations(ctx, migrationManager, cfg.MigrationPath, allowUnsafe); err != nil {
+		return err
+	}
+
 	// Run migrations up
 	if err := migrationManager.Up(); err != nil {
 		// Check if it's "no change" error, which is acceptable
@@ -142,53 +204,50 @@ func runMigrations(ctx context.Context, db *sqlx.DB, cfg *config.Database) error
 	return nil
 }
 
-func setupAppCtx(ctx context.Context, cfg *config.AppConfig, db *sqlx.DB) (components.AppContext, error) {
-	jwtService := auth.NewJWTService(
-		cfg.JWT.SecretKey,
-		cfg.JWT.AccessTokenExpiry,
-		cfg.JWT.RefreshTokenExpiry,
-	)
+// lintPendingMigrations checks the migrations the database has yet to apply for operations that lock
+// or rewrite tables, or break the running release
+func lintPendingMigrations(ctx context.Context, migrationManager *database.MigrationManager, migrationPath string, allowUnsafe bool) error {
+	version, _, err := migrationManager.Version()
+	if err != nil && !errors.Is(syserr.UnwrapError(err), migrate.ErrNilVersion) {
+		return fmt.Errorf("failed to get migration version: %w", err)
+	}
 
-	// init publisher
-	saramaSubscriberConfig := kafka.DefaultSaramaSubscriberConfig()
-	saramaSubscriberConfig.Consumer.Offsets.Initial = sarama.OffsetOldest
-	kafkaSub, err := kafka.NewSubscriber(
-		kafka.SubscriberConfig{
-			Brokers:               cfg.Kafka.Brokers,
-			Unmarshaler:           kafka.DefaultMarshaler{},
-			OverwriteSaramaConfig: saramaSubscriberConfig,
-			ConsumerGroup:         "tixgo_consumer_group",
-		},
-		watermill.NewSlogLogger(logger.GetLogger()),
-	)
+	findings, err := migrationlint.LintDir(strings.TrimPrefix(migrationPath, "file://"), version)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create kafka subscriber: %w", err)
+		return fmt.Errorf("failed to lint migrations: %w", err)
+	}
+	if len(findings) == 0 {
+		return nil
 	}
 
-	kafkaPub, err := kafka.NewPublisher(
-		kafka.PublisherConfig{
-			Brokers:   cfg.Kafka.Brokers,
-			Marshaler: kafka.DefaultMarshaler{},
-		},
-		watermill.NewSlogLogger(logger.GetLogger()),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create kafka publisher: %w", err)
+	for _, finding := range findings {
+		logger.Warning(ctx, "Unsafe migration statement", logger.F("finding", finding.String()))
+	}
+	if !allowUnsafe {
+		return fmt.Errorf("%d unsafe statements in pending migrations, review them and restart with --allow-unsafe to apply", len(findings))
 	}
 
-	messagingBus, err := messaging.NewBus(messaging.Config{
-		Publisher:  kafkaPub,
-		Subscriber: kafkaSub,
-		Logger:     logger.GetLogger(),
-	})
+	logger.Warning(ctx, "Applying unsafe migrations", logger.F("count", len(findings)))
+	return nil
+}
+
+func provisionKafkaTopics(ctx context.Context, cfg *config.AppConfig) error {
+	if !cfg.Kafka.ProvisionTopics {
+		return nil
+	}
+
+	logger.Info(ctx, "Provisioning kafka topics...")
+
+	topicManager, err := sharedKafka.NewTopicManager(cfg.Kafka.Brokers, components.NewKafkaTopology(cfg.Kafka))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create messaging bus: %w", err)
+		return err
 	}
+	defer topicManager.Close()
 
-	return components.NewAppContext(db, jwtService, messagingBus, messagingBus, messagingBus), nil
+	return topicManager.EnsureTopics(ctx, false)
 }
 
-func setupHTTPServer(ctx context.Context, cfg *config.AppConfig, appCtx components.AppContext) *httpserver.Server {
+func setupHTTPServer(ctx context.Context, cfg *config.AppConfig, appCtx components.AppContext, lagMonitor *sharedKafka.LagMonitor, readOnly *readonly.Switch, ticketKeys *barcode.Keyring) *httpserver.Server {
 	logger.Info(ctx, "Setting up HTTP server...")
 
 	// Setup router with configuration
@@ -199,8 +258,53 @@ func setupHTTPServer(ctx context.Context, cfg *config.AppConfig, appCtx componen
 		EnableAuth:  true,
 	})
 
+	// Only trust the request ID and client address sent by our own proxies.
+	// Their format is checked when the configuration is validated.
+	trustedProxies, _ := requestid.ParseTrustedProxies(cfg.Server.TrustedProxies)
+	_ = router.SetTrustedProxies(cfg.Server.TrustedProxies)
+	router.Use(requestid.Middleware(trustedProxies))
+
+	// Count the status of every response, once the error middleware answered, for the admin dashboard
+	errorRates := httpguard.NewErrorRates(httpguard.DefaultErrorRateWindow)
+	router.Use(errorRates.Middleware("/metrics", "/health/deep"))
+
+	// Log whole requests and responses when debugging clients, never in prod
+	if cfg.Debug.DumpRequests {
+		router.Use(httpdump.Middleware())
+	}
+
+	// Harden every response against browsers misusing it
+	router.Use(secheaders.Middleware(securityHeaders(cfg)))
+
+	// Wrap every response in the envelope carrying request ID and timing
+	router.Use(httpresponse.Middleware(), httpresponse.ErrorHandler(errorDebugPolicy(cfg)))
+
+	// Answer handler panics in the envelope and record them with their stack
+	router.Use(httpguard.Recovery())
+
+	// Let supporting command handlers run as dry runs
+	router.Use(dryrun.Middleware())
+
+	// Expose prometheus metrics
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	// The router's /ready is static, the deep check reports the dependencies
+	router.GET("/health/deep", deepHealthCheck(appCtx, lagMonitor).Handler())
+
 	// Register module routes
-	registerRoutes(router, appCtx)
+	registerRoutes(ctx, router, cfg, appCtx, readOnly, ticketKeys, lagMonitor, errorRates)
+
+	// Provider callbacks are not versioned: their URLs are registered with the providers
+	registerWebhooks(ctx, router, cfg, appCtx)
+
+	// Short links are not versioned either, they are printed in messages already sent
+	router.GET(shortlink.PathPrefix+":code", shortlink.Handler(appCtx.GetShortLinkService()))
+
+	// Nor are the images of templates, their URLs are in mails already sent
+	router.GET(assets.PathPrefix+":file", assets.Handler(appCtx.GetAssetService()))
+
+	// Crawlers look for the sitemap at the root
+	router.GET("/sitemap.xml", eventPort.Sitemap(appCtx, eventDomain.Site{URL: cfg.SEO.SiteURL}))
 
 	// Create server with configuration
 	srv := httpserver.New(httpserver.Config{
@@ -217,23 +321,365 @@ func setupHTTPServer(ctx context.Context, cfg *config.AppConfig, appCtx componen
 	return srv
 }
 
-func registerRoutes(router *gin.Engine, appCtx components.AppContext) {
-	v1 := router.Group("/v1")
-	// Register user module routes
-	{
-		userPort.RegisterUserRoutes(v1, appCtx)
-		templatePort.RegisterTemplateRoutes(v1, appCtx)
+func registerRoutes(ctx context.Context, router *gin.Engine, cfg *config.AppConfig, appCtx components.AppContext, readOnly *readonly.Switch, ticketKeys *barcode.Keyring, lagMonitor *sharedKafka.LagMonitor, errorRates *httpguard.ErrorRates) {
+	scheduledJobs := jobs.All(appCtx, cfg)
+	senderPlatform := organizerDomain.SenderPlatform{SPFInclude: cfg.Mail.SPFInclude, DKIMHost: cfg.Mail.DKIMHost}
+	emailPolicy := userDomain.NewEmailPolicy(cfg.Registration.FoldGmail, cfg.Registration.DisposableDomains)
+	consentPolicy := userDomain.ConsentPolicy{
+		TermsVersion:   cfg.Legal.TermsVersion,
+		TermsURL:       cfg.Legal.TermsURL,
+		PrivacyVersion: cfg.Legal.PrivacyVersion,
+		PrivacyURL:     cfg.Legal.PrivacyURL,
+	}
+	features := featureFlags(cfg.Features, appCtx)
+	payloadSchemas := jsonschema.NewRegistry(schemas.All()...)
+	site := eventDomain.Site{URL: cfg.SEO.SiteURL}
+	screening, err := registrationScreening(cfg.Registration)
+	if err != nil {
+		logger.Fatal(ctx, "Invalid registration risk configuration", logger.F("error", err))
+	}
+
+	// Every API version serves the module routes; modules register version specific routes
+	// and shim responses for older versions themselves
+	for _, version := range apiversion.Versions {
+		api := apiversion.NewGroup(router, version, apiDeprecation(cfg, version))
+		api.Use(httpguard.Timeout(api.BasePath(), httpguard.Timeouts{
+			Default: cfg.Server.RequestTimeout,
+			Routes:  cfg.Server.RouteTimeouts,
+		}))
+		// Writes are refused while the database fails over, except the one lifting the read-only mode
+		api.Use(readonly.Middleware(readOnly, api.BasePath(), "/admin/read-only"))
+		// Organizers calling with an API key rather than a session, metered against its quota
+		api.Use(organizerPort.AuthenticateAPIKey(appCtx))
+		{
+			userPort.RegisterUserRoutes(api, appCtx, cfg.App.ExposeOTP, emailPolicy, consentPolicy, screening)
+			templatePort.RegisterTemplateRoutes(api, appCtx, templateRenderPolicy(cfg))
+			schedulerPort.RegisterSchedulerRoutes(api, appCtx, scheduledJobs)
+			eventPort.RegisterEventRoutes(api, appCtx, site)
+			bookingPort.RegisterBookingRoutes(api, appCtx)
+			organizerPort.RegisterOrganizerRoutes(api, appCtx, senderPlatform, site)
+			notificationPort.RegisterNotificationRoutes(api, appCtx)
+			orderPort.RegisterOrderRoutes(api, appCtx, cfg.Orders, ticketKeys)
+			compliancePort.RegisterComplianceRoutes(api, appCtx)
+			supportPort.RegisterSupportRoutes(api, appCtx, features)
+			ticketPort.RegisterTicketRoutes(api, appCtx)
+			promotionPort.RegisterPromotionRoutes(api, appCtx)
+			venuePort.RegisterVenueRoutes(api, appCtx)
+			dashboardPort.RegisterDashboardRoutes(api, appCtx, lagMonitor, errorRates)
+		}
+
+		// Clients validate and generate their requests from the schemas of the payloads
+		api.GET("/schemas", payloadSchemas.ListHandler())
+		api.GET("/schemas/:name", payloadSchemas.GetHandler())
+
+		// Clients show the entry points of the soft-launched modules the user is let into
+		featureGroup := api.Group("/features")
+		{
+			featureGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+			featureGroup.GET("", feature.EnabledHandler(features))
+		}
+
+		// Consumers of the buses discover the topics and payloads of the messages
+		catalogGroup := api.Group("/event-catalog")
+		{
+			catalogGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+			catalogGroup.GET("", eventbus.Handler(sharedKafka.NewTopicNaming(cfg.Kafka.TopicPrefix)))
+		}
+
+		// Debugging aids, compiled in but only served when switched on outside prod
+		if cfg.Debug.EchoOTP {
+			userPort.RegisterUserDebugRoutes(api, appCtx, emailPolicy)
+		}
+		if cfg.Debug.TemplatePreviews {
+			templatePort.RegisterTemplateDebugRoutes(api, appCtx)
+		}
+
+		// Admins debugging which file or variable set a value get the effective configuration
+		configGroup := api.Group("/admin/config")
+		{
+			configGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+			configGroup.Use(authz.RequireUserType(string(userDomain.UserTypeAdmin)))
+			configGroup.GET("", getEffectiveConfig(cfg))
+		}
+
+		// Admins turn the API read-only during failovers of the primary and migrations locking tables
+		readOnlyGroup := api.Group("/admin/read-only")
+		{
+			readOnlyGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+			readOnlyGroup.Use(authz.RequireUserType(string(userDomain.UserTypeAdmin)))
+			readOnlyGroup.GET("", readonly.GetHandler(readOnly))
+			readOnlyGroup.PUT("", readonly.SetHandler(readOnly))
+		}
 	}
 
 	// Add any additional module routes here
 }
 
-func startMessagingHandler(ctx context.Context, appCtx components.AppContext) {
+// featureFlags creates the allowlists of the soft-launched modules, looking the emails of users up in
+// the user module
+func featureFlags(cfg map[string]config.Feature, appCtx components.AppContext) *feature.Flags {
+	rules := make(map[string]feature.Rule, len(cfg))
+	for name, f := range cfg {
+		rules[name] = feature.Rule{
+			Enabled:      f.Enabled,
+			UserIDs:      f.UserIDs,
+			EmailDomains: f.EmailDomains,
+			Percentage:   f.Percentage,
+		}
+	}
+
+	users := userAdapters.NewCachedUserRepository(userAdapters.NewUserPostgresRepository(appCtx.GetDB()), appCtx.GetCache())
+	return feature.NewFlags(rules, func(ctx context.Context, userID int64) (string, error) {
+		user, err := users.GetByID(ctx, userID)
+		if err != nil {
+			return "", err
+		}
+		return user.Email, nil
+	})
+}
+
+// getEffectiveConfig returns the merged configuration the server runs with, each value with the file or
+// environment variable that supplied it, secrets masked
+func getEffectiveConfig(cfg *config.AppConfig) gin.HandlerFunc {
+	entries := cfg.Effective()
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "no-store")
+		httpresponse.Success(c, http.StatusOK, entries)
+	}
+}
+
+// apiDeprecation returns the configured deprecation of an API version, nil if it is not deprecated
+func apiDeprecation(cfg *config.AppConfig, version string) *apiversion.Deprecation {
+	dates, ok := cfg.API.Deprecations[version]
+	if !ok {
+		return nil
+	}
+
+	// the date format is checked when the configuration is validated
+	deprecation := &apiversion.Deprecation{}
+	deprecation.DeprecatedAt, _ = time.Parse(time.DateOnly, dates.DeprecatedAt)
+	if dates.SunsetAt != "" {
+		deprecation.SunsetAt, _ = time.Parse(time.DateOnly, dates.SunsetAt)
+	}
+	return deprecation
+}
+
+// templateRenderPolicy returns what non-admins may render and the quota of every caller, the default
+// one when the config has none
+func templateRenderPolicy(cfg *config.AppConfig) templatePort.RenderPolicy {
+	render := cfg.Templates.Render
+	policy := templatePort.RenderPolicy{Limit: templatePort.DefaultRenderLimit}
+	for _, templateType := range render.PublicTypes {
+		policy.PublicTypes = append(policy.PublicTypes, templateDomain.TemplateType(templateType))
+	}
+	if render.RateLimit.Rate > 0 {
+		policy.Limit = ratelimit.Limit{Rate: render.RateLimit.Rate, Per: render.RateLimit.Per, Burst: render.RateLimit.Burst}
+	}
+	return policy
+}
+
+// registrationScreening returns how registrations are scored for spam, challenged with a CAPTCHA when a
+// provider is configured
+func registrationScreening(cfg config.Registration) (userPort.RegistrationScreening, error) {
+	risk := cfg.Risk
+	policy, err := userDomain.NewRiskPolicy(userDomain.RiskPolicy{
+		ChallengeScore: risk.ChallengeScore,
+		ReviewScore:    risk.ReviewScore,
+		BlockScore:     risk.BlockScore,
+		VelocityWindow: risk.VelocityWindow,
+		IPLimit:        risk.IPLimit,
+		DeviceLimit:    risk.DeviceLimit,
+	}, risk.RiskyNetworks)
+	if err != nil {
+		return userPort.RegistrationScreening{}, err
+	}
+
+	screening := userPort.RegistrationScreening{Policy: policy}
+	if cfg.Captcha.VerifyURL != "" {
+		screening.Captcha = userAdapters.NewHTTPCaptchaVerifier(cfg.Captcha.VerifyURL, cfg.Captcha.Secret)
+	}
+	return screening, nil
+}
+
+// ticketKeyring returns the keyring signing the QR codes of tickets with the current key of the config
+// and verifying them with its previous keys too
+func ticketKeyring(cfg config.TicketQR) (*barcode.Keyring, error) {
+	current, err := barcode.NewHMACKey(cfg.KeyID, []byte(cfg.Secret))
+	if err != nil {
+		return nil, err
+	}
+
+	previous := make([]barcode.Verifier, 0, len(cfg.Previous))
+	for _, key := range cfg.Previous {
+		verifier, err := barcode.NewHMACKey(key.KeyID, []byte(key.Secret))
+		if err != nil {
+			return nil, err
+		}
+		previous = append(previous, verifier)
+	}
+
+	return barcode.NewKeyring(current, previous...)
+}
+
+// ticketQRBaseURL is where the confirmation mails load the QR codes of tickets from, the API itself
+// behind the host of the short links unless configured
+func ticketQRBaseURL(cfg *config.AppConfig) string {
+	if cfg.Orders.TicketQR.BaseURL != "" {
+		return cfg.Orders.TicketQR.BaseURL
+	}
+	return cfg.ShortLinks.BaseURL
+}
+
+// errorDebugPolicy exposes the cause chain and stack of errors in debug mode. In production only admins
+// get them, and only when they ask with the debug header.
+func errorDebugPolicy(cfg *config.AppConfig) httpresponse.DebugPolicy {
+	if !cfg.App.DebugMode {
+		return nil
+	}
+
+	if cfg.App.Environment != "prod" {
+		return func(*gin.Context) bool { return true }
+	}
+
+	return func(c *gin.Context) bool {
+		return c.GetHeader(httpresponse.DebugHeader) == "true" &&
+			pkgContext.GetUserTypeFromContext(c.Request.Context()) == string(userDomain.UserTypeAdmin)
+	}
+}
+
+// securityHeaders returns the security headers of the responses, the defaults overridden by the config
+func securityHeaders(cfg *config.AppConfig) secheaders.Config {
+	headers := secheaders.DefaultConfig()
+	if cfg.App.Environment == "dev" {
+		headers.HSTSMaxAge = 0
+	}
+
+	security := cfg.Security
+	if security.HSTSMaxAge > 0 {
+		headers.HSTSMaxAge = security.HSTSMaxAge
+	}
+	if security.FrameOptions != "" {
+		headers.FrameOptions = security.FrameOptions
+	}
+	if security.ReferrerPolicy != "" {
+		headers.ReferrerPolicy = security.ReferrerPolicy
+	}
+	if security.ContentSecurityPolicy != "" {
+		headers.ContentSecurityPolicy = security.ContentSecurityPolicy
+	}
+	return headers
+}
+
+// registerWebhooks serves the callbacks of the providers whose secret is configured
+func registerWebhooks(ctx context.Context, router gin.IRouter, cfg *config.AppConfig, appCtx components.AppContext) {
+	webhooks := cfg.Webhooks
+
+	var endpoints []webhook.Config
+	if webhooks.Payment.Secret != "" {
+		endpoints = append(endpoints, webhook.Config{
+			Source:   "payment",
+			Verifier: webhook.NewHMACVerifier(webhooks.Payment.Secret, webhooks.Payment.SignatureHeader, webhooks.Payment.TimestampHeader),
+		})
+	}
+	if webhooks.Bank.Secret != "" {
+		endpoints = append(endpoints, webhook.Config{
+			Source:   "bank",
+			Verifier: webhook.NewHMACVerifier(webhooks.Bank.Secret, webhooks.Bank.SignatureHeader, webhooks.Bank.TimestampHeader),
+		})
+	}
+	if webhooks.SendGrid.PublicKey != "" {
+		verifier, err := webhook.NewSendGridVerifier(webhooks.SendGrid.PublicKey)
+		if err != nil {
+			logger.Fatal(ctx, "Invalid sendgrid webhook configuration", logger.F("error", err))
+		}
+		endpoints = append(endpoints, webhook.Config{Source: "sendgrid", Verifier: verifier})
+	}
+	if webhooks.SMS.AuthToken != "" {
+		endpoints = append(endpoints, webhook.Config{
+			Source:   "sms",
+			Verifier: webhook.NewTwilioVerifier(webhooks.SMS.AuthToken, webhooks.PublicURL),
+		})
+	}
+
+	deduplicator := dedup.NewRedisDeduplicator(appCtx.GetRedis())
+	webhookGroup := router.Group("/webhooks")
+	for _, endpoint := range endpoints {
+		endpoint.ReplayWindow = webhooks.ReplayWindow
+		webhookGroup.POST("/"+endpoint.Source, webhook.Handler(endpoint, deduplicator, appCtx.GetEventBus()))
+		logger.Info(ctx, "Webhook registered", logger.F("source", endpoint.Source))
+	}
+}
+
+func startMessagingHandler(ctx context.Context, cfg *config.AppConfig, appCtx components.AppContext, ticketKeys *barcode.Keyring) {
 	dispatcher := appCtx.GetDispatcher()
 
-	userPort.NewUserMessagingHandlers(dispatcher, appCtx).RegisterUserMessagingHandlers()
+	userPort.NewUserMessagingHandlers(dispatcher, appCtx, cfg.App.ExposeOTP).RegisterUserMessagingHandlers()
+	eventPort.NewEventMessagingHandlers(dispatcher, appCtx).RegisterEventMessagingHandlers()
+	notificationPort.NewNotificationMessagingHandlers(dispatcher, appCtx).RegisterNotificationMessagingHandlers()
+	templatePort.NewTemplateMessagingHandlers(dispatcher, appCtx).RegisterTemplateMessagingHandlers()
+	organizerPort.NewOrganizerMessagingHandlers(dispatcher, appCtx).RegisterOrganizerMessagingHandlers()
+	orderPort.NewOrderMessagingHandlers(dispatcher, appCtx, ticketKeys, ticketQRBaseURL(cfg), orderPort.NewReceiptOptions(cfg.Orders)).RegisterOrderMessagingHandlers()
+	registerSMSSender(ctx, cfg, appCtx, dispatcher)
+
+	go runDispatcher(ctx, cfg, appCtx)
+}
+
+// runDispatcher declares the kafka topics and consumes the messages once the brokers are reachable,
+// right away unless the API started degraded. Topics that cannot be declared at startup stop the
+// server; once degraded the server keeps serving and only logs it.
+func runDispatcher(ctx context.Context, cfg *config.AppConfig, appCtx components.AppContext) {
+	publisher := appCtx.GetKafkaPublisher()
+	select {
+	case <-publisher.Connected():
+		if err := provisionKafkaTopics(ctx, cfg); err != nil {
+			logger.Fatal(ctx, "Failed to provision kafka topics", logger.F("error", err))
+		}
+	default:
+		if err := publisher.Wait(ctx); err != nil {
+			return
+		}
+		logger.Info(ctx, "Kafka brokers are reachable, leaving degraded mode")
+		if err := provisionKafkaTopics(ctx, cfg); err != nil {
+			logger.Error(ctx, "Failed to provision kafka topics", logger.F("error", err))
+		}
+	}
+
+	if err := appCtx.GetDispatcher().Run(ctx); err != nil {
+		logger.Error(ctx, "Message dispatcher stopped", logger.F("error", err))
+	}
+}
+
+// registerSMSSender sends the requested text messages with the configured Twilio account, within its quota
+func registerSMSSender(ctx context.Context, cfg *config.AppConfig, appCtx components.AppContext, dispatcher messaging.Dispatcher) {
+	if cfg.SMS.AccountSID == "" {
+		logger.Warning(ctx, "No SMS account configured, text messages are not sent")
+		return
+	}
+
+	handler := sharedSMS.NewEventSendSMSHandler(
+		sharedSMS.NewTwilioSender(cfg.SMS.AccountSID, cfg.SMS.AuthToken, cfg.SMS.From),
+		components.NewSendThrottle(cfg, appCtx.GetRedis(), sharedSMS.TwilioProvider),
+	)
+	// Sends the provider refused are counted for the admin dashboard
+	handle := dailycount.CountFailures(dashboardPort.FailedNotifications(appCtx), handler.Handle)
+	dispatcher.GetEventProcessor().AddHandler(cqrs.NewEventHandler("events.EventSendSMS", handle))
+}
+
+// startLagMonitor collects the lag of the consumer group on the consumed topics in the background
+func startLagMonitor(ctx context.Context, cfg *config.AppConfig) *sharedKafka.LagMonitor {
+	lagMonitor := sharedKafka.NewLagMonitor(cfg.Kafka.Brokers, components.KafkaConsumerGroup(cfg.Kafka), components.NewKafkaTopology(cfg.Kafka))
+	go lagMonitor.Run(ctx, cfg.Kafka.LagCheckInterval)
+	return lagMonitor
+}
 
-	go dispatcher.Run(ctx)
+// deepHealthCheck checks the database, redis and that the consumers keep up with their topics. Kafka
+// being unreachable leaves the server degraded: requests are served, events parked in the outbox.
+func deepHealthCheck(appCtx components.AppContext, lagMonitor *sharedKafka.LagMonitor) *health.Checker {
+	return health.NewChecker(health.DefaultTimeout).
+		Add("database", appCtx.GetDB().PingContext).
+		Add("redis", func(ctx context.Context) error { return appCtx.GetRedis().Ping(ctx).Err() }).
+		AddDegrading("kafka", appCtx.GetKafkaPublisher().Check).
+		Add("kafka_lag", lagMonitor.Check)
 }
 
 func startServer(ctx context.Context, srv *httpserver.Server) {
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	_ "tixgo/jobs" // registers the message catalog
+	"tixgo/shared/eventbus"
+	sharedKafka "tixgo/shared/kafka"
+)
+
+// event_catalog writes the markdown documentation of the messages of the buses, see docs/events.md
+func main() {
+	output := flag.String("output", "docs/events.md", "file to write the catalog to, - for stdout")
+	flag.Parse()
+
+	out := os.Stdout
+	if *output != "-" {
+		file, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to create the catalog:", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if err := eventbus.WriteMarkdown(out, eventbus.Catalog(sharedKafka.NewTopicNaming(""))); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to write the catalog:", err)
+		os.Exit(1)
+	}
+}
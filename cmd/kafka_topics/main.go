@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"os"
+
+	"tixgo/components"
+	"tixgo/config"
+	sharedKafka "tixgo/shared/kafka"
+
+	"github.com/duongptryu/gox/logger"
+)
+
+// kafka_topics declares the configured Kafka topics (partitions, retention, cleanup policy) on the cluster
+func main() {
+	dryRun := flag.Bool("dry-run", false, "validate topic changes on the broker without applying them")
+	flag.Parse()
+
+	logger.Init(&logger.Config{
+		Level:     slog.LevelInfo,
+		Output:    os.Stdout,
+		AddSource: false,
+	})
+
+	ctx := context.Background()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		logger.Fatal(ctx, "Failed to load configuration", logger.F("error", err))
+	}
+
+	topicManager, err := sharedKafka.NewTopicManager(cfg.Kafka.Brokers, components.NewKafkaTopology(cfg.Kafka))
+	if err != nil {
+		logger.Fatal(ctx, "Failed to create topic manager", logger.F("error", err))
+	}
+	defer topicManager.Close()
+
+	if err := topicManager.EnsureTopics(ctx, *dryRun); err != nil {
+		logger.Fatal(ctx, "Failed to provision kafka topics", logger.F("error", err))
+	}
+
+	logger.Info(ctx, "Kafka topics provisioned", logger.F("dry_run", *dryRun))
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"tixgo/components"
+	"tixgo/config"
+	"tixgo/jobs"
+	schedulerAdapters "tixgo/modules/scheduler/adapters"
+	schedulerPort "tixgo/modules/scheduler/ports"
+	templatePort "tixgo/modules/template/ports"
+	"tixgo/shared/readonly"
+	"tixgo/shared/scheduler"
+
+	"github.com/duongptryu/gox/logger"
+)
+
+// shutdownTimeout is how long running jobs are given to finish on shutdown
+const shutdownTimeout = 30 * time.Second
+
+// worker hosts the background jobs (scheduler) of the platform
+func main() {
+	// Initialize logger first
+	logger.Init(&logger.Config{
+		Level:     slog.LevelInfo,
+		Output:    os.Stdout,
+		AddSource: false,
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger.Info(ctx, "Starting TixGo worker...")
+
+	// Load configuration
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		logger.Fatal(ctx, "Failed to load configuration", logger.F("error", err))
+	}
+	components.LogConfigSources(ctx, cfg)
+	cfg.MustDisableDebugInProd()
+
+	// Variables every template renders with unless given
+	if err := templatePort.ConfigureDefaults(cfg.Templates); err != nil {
+		logger.Fatal(ctx, "Invalid template defaults configuration", logger.F("error", err))
+	}
+
+	// Connect to database
+	db, err := components.ConnectDatabase(ctx, &cfg.Database)
+	if err != nil {
+		logger.Fatal(ctx, "Failed to connect to database", logger.F("error", err))
+	}
+	defer db.Close()
+
+	// Connect to redis
+	redisClient, err := components.ConnectRedis(ctx, &cfg.Redis)
+	if err != nil {
+		logger.Fatal(ctx, "Failed to connect to redis", logger.F("error", err))
+	}
+	defer redisClient.Close()
+
+	// Initialize app context
+	appCtx, err := components.SetupAppCtx(ctx, cfg, db, redisClient)
+	if err != nil {
+		logger.Fatal(ctx, "Failed to initialize app context", logger.F("error", err))
+	}
+
+	// Register jobs
+	sched := scheduler.New(appCtx.GetLocker(), schedulerAdapters.NewJobRunRecorder(schedulerAdapters.NewJobRunPostgresRepository(db)))
+	for _, job := range jobs.All(appCtx, cfg) {
+		if err := sched.Register(job); err != nil {
+			logger.Fatal(ctx, "Failed to register job", logger.F("job", job.Name), logger.F("error", err))
+		}
+	}
+
+	// Jobs write, they wait for the end of the read-only mode
+	readOnly := readonly.NewSwitch(redisClient, cfg.Server.ReadOnly)
+	go readOnly.Run(ctx, readonly.DefaultRefreshInterval)
+	sched.PauseWhile(readOnly.Enabled)
+
+	// register command handlers
+	dispatcher := appCtx.GetDispatcher()
+	schedulerPort.NewSchedulerMessagingHandlers(dispatcher, sched).RegisterSchedulerMessagingHandlers()
+	go func() {
+		// subscribing fails for good without the brokers, the jobs run meanwhile
+		if err := appCtx.GetKafkaPublisher().Wait(ctx); err != nil {
+			return
+		}
+		if err := dispatcher.Run(ctx); err != nil {
+			logger.Error(ctx, "Message dispatcher stopped", logger.F("error", err))
+		}
+	}()
+
+	sched.Start()
+	logger.Info(ctx, "Scheduler started", logger.F("jobs", len(sched.Jobs())))
+
+	<-ctx.Done()
+	logger.Info(ctx, "Shutting down worker...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := sched.Stop(shutdownCtx); err != nil {
+		logger.Error(ctx, "Scheduler did not stop gracefully", logger.F("error", err))
+	}
+
+	logger.Info(ctx, "Worker stopped")
+}
@@ -1,38 +1,108 @@
 package components
 
 import (
-	"github.com/duongptryu/gox/auth"
+	"tixgo/shared/assets"
+	"tixgo/shared/cache"
+	sharedKafka "tixgo/shared/kafka"
+	"tixgo/shared/lock"
+	"tixgo/shared/outbox"
+	"tixgo/shared/session"
+	"tixgo/shared/shortlink"
+	"tixgo/shared/storage"
+
 	"github.com/duongptryu/gox/messaging"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
 )
 
 type AppContext interface {
 	GetDB() *sqlx.DB
-	GetJWTService() *auth.JWTService
+	GetRedis() redis.UniversalClient
+	GetLocker() lock.Locker
+	GetCache() *cache.Cache
+	// GetSessionService issues and validates the tokens of the sessions
+	GetSessionService() *session.Service
+	// GetShortLinkService shortens the links sent where characters are counted
+	GetShortLinkService() *shortlink.Service
+	// GetStorage keeps the uploaded files
+	GetStorage() storage.Store
+	// GetAssetService hosts the images templates reference
+	GetAssetService() *assets.Service
 	GetCommandBus() messaging.CommandBus
 	GetEventBus() messaging.EventBus
+	// GetReliableEventBus retries failed publishes and parks the events that still fail in the outbox,
+	// for handlers that must not lose an event nor fail after their change is committed
+	GetReliableEventBus() messaging.EventBus
 	GetDispatcher() messaging.Dispatcher
+	// GetKafkaPublisher tells whether the buses reached the brokers, the dispatcher consuming once they did
+	GetKafkaPublisher() *sharedKafka.LazyPublisher
 }
 
 type appCtx struct {
-	db         *sqlx.DB
-	jwtService *auth.JWTService
-	commandBus messaging.CommandBus
-	eventBus   messaging.EventBus
-	dispatcher messaging.Dispatcher
+	db               *sqlx.DB
+	redis            redis.UniversalClient
+	locker           lock.Locker
+	cache            *cache.Cache
+	sessionService   *session.Service
+	shortLinkService *shortlink.Service
+	storage          storage.Store
+	assetService     *assets.Service
+	commandBus       messaging.CommandBus
+	eventBus         messaging.EventBus
+	reliableEventBus messaging.EventBus
+	dispatcher       messaging.Dispatcher
+	kafkaPublisher   *sharedKafka.LazyPublisher
 }
 
-func NewAppContext(db *sqlx.DB, jwtService *auth.JWTService, commandBus messaging.CommandBus, eventBus messaging.EventBus, dispatcher messaging.Dispatcher) AppContext {
-	return &appCtx{db: db, jwtService: jwtService, commandBus: commandBus, eventBus: eventBus, dispatcher: dispatcher}
+func NewAppContext(db *sqlx.DB, redisClient redis.UniversalClient, sessionService *session.Service, shortLinkService *shortlink.Service, store storage.Store, assetService *assets.Service, commandBus messaging.CommandBus, eventBus messaging.EventBus, dispatcher messaging.Dispatcher, kafkaPublisher *sharedKafka.LazyPublisher) AppContext {
+	return &appCtx{
+		db:               db,
+		redis:            redisClient,
+		locker:           lock.NewRedisLocker(redisClient),
+		cache:            cache.New(redisClient, cache.DefaultConfig()),
+		sessionService:   sessionService,
+		shortLinkService: shortLinkService,
+		storage:          store,
+		assetService:     assetService,
+		commandBus:       commandBus,
+		eventBus:         eventBus,
+		reliableEventBus: outbox.NewPublisher(eventBus, outbox.NewPostgresStore(db)),
+		dispatcher:       dispatcher,
+		kafkaPublisher:   kafkaPublisher,
+	}
 }
 
 func (c *appCtx) GetDB() *sqlx.DB {
 	return c.db
 }
 
-func (c *appCtx) GetJWTService() *auth.JWTService {
-	return c.jwtService
+func (c *appCtx) GetRedis() redis.UniversalClient {
+	return c.redis
+}
+
+func (c *appCtx) GetLocker() lock.Locker {
+	return c.locker
+}
+
+func (c *appCtx) GetCache() *cache.Cache {
+	return c.cache
+}
+
+func (c *appCtx) GetSessionService() *session.Service {
+	return c.sessionService
+}
+
+func (c *appCtx) GetShortLinkService() *shortlink.Service {
+	return c.shortLinkService
+}
+
+func (c *appCtx) GetStorage() storage.Store {
+	return c.storage
+}
+
+func (c *appCtx) GetAssetService() *assets.Service {
+	return c.assetService
 }
 
 func (c *appCtx) GetCommandBus() messaging.CommandBus {
@@ -43,6 +113,14 @@ func (c *appCtx) GetEventBus() messaging.EventBus {
 	return c.eventBus
 }
 
+func (c *appCtx) GetReliableEventBus() messaging.EventBus {
+	return c.reliableEventBus
+}
+
 func (c *appCtx) GetDispatcher() messaging.Dispatcher {
 	return c.dispatcher
 }
+
+func (c *appCtx) GetKafkaPublisher() *sharedKafka.LazyPublisher {
+	return c.kafkaPublisher
+}
@@ -0,0 +1,198 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"tixgo/config"
+	"tixgo/shared/assets"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/htmlsanitize"
+	sharedKafka "tixgo/shared/kafka"
+	"tixgo/shared/session"
+	"tixgo/shared/shortlink"
+	"tixgo/shared/storage"
+	"tixgo/shared/strictjson"
+
+	"github.com/IBM/sarama"
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill-kafka/v3/pkg/kafka"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+)
+
+// ConnectDatabase opens and configures the postgres connection pool
+func ConnectDatabase(ctx context.Context, cfg *config.Database) (*sqlx.DB, error) {
+	// Build connection string
+	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, cfg.SSLMode)
+
+	// Connect to database
+	db, err := sqlx.Connect("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	// Configure connection pool
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.MaxLifetime)
+	db.SetConnMaxIdleTime(cfg.MaxIdleTime)
+
+	// Bound every repository call
+	dbtimeout.SetQueryTimeout(cfg.QueryTimeout)
+
+	// Test connection
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return db, nil
+}
+
+// ConnectRedis opens the redis client
+func ConnectRedis(ctx context.Context, cfg *config.Redis) (*redis.Client, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+
+	// Test connection
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to ping redis: %w", err)
+	}
+
+	return client, nil
+}
+
+// SetupAppCtx wires the session and short link services, the file storage, the asset hosting and the
+// kafka messaging bus into the app context, and applies the template sanitization policy
+func SetupAppCtx(ctx context.Context, cfg *config.AppConfig, db *sqlx.DB, redisClient *redis.Client) (AppContext, error) {
+	sessionService := session.NewService(cfg.JWT.SecretKey, cfg.JWT.Issuer, cfg.JWT.Audience, newSessionPolicy(cfg.JWT))
+	shortLinkService := shortlink.NewService(shortlink.NewPostgresStore(db), cfg.ShortLinks.BaseURL)
+
+	// Templates and their safeHTML variables are cleaned with the configured allow-list
+	sanitizer := cfg.Templates.Sanitizer
+	htmlsanitize.SetPolicy(htmlsanitize.NewPolicy(sanitizer.AllowedTags, sanitizer.AllowedAttributes, sanitizer.URLSchemes))
+
+	// JSON bodies are bound with field paths in their errors, rejecting unknown fields when strict
+	strictjson.Install(cfg.Server.StrictJSON)
+
+	topology := NewKafkaTopology(cfg.Kafka)
+	partitioningMarshaler := sharedKafka.NewPartitioningMarshaler(topology.Consumers, topology.Naming)
+
+	consumerGroup := KafkaConsumerGroup(cfg.Kafka)
+
+	// init publisher
+	saramaSubscriberConfig := kafka.DefaultSaramaSubscriberConfig()
+	saramaSubscriberConfig.Consumer.Offsets.Initial = sarama.OffsetOldest
+	saramaSubscriberConfig.Metadata.AllowAutoTopicCreation = !cfg.Kafka.ProvisionTopics
+	kafkaSub, err := kafka.NewSubscriber(
+		kafka.SubscriberConfig{
+			Brokers:               cfg.Kafka.Brokers,
+			Unmarshaler:           partitioningMarshaler,
+			OverwriteSaramaConfig: saramaSubscriberConfig,
+			ConsumerGroup:         consumerGroup,
+		},
+		watermill.NewSlogLogger(logger.GetLogger()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka subscriber: %w", err)
+	}
+
+	// The publisher connects in the background so the API serves without the brokers, publishes
+	// failing meanwhile and the reliable event bus parking them in the outbox
+	saramaPublisherConfig := kafka.DefaultSaramaSyncPublisherConfig()
+	saramaPublisherConfig.Metadata.AllowAutoTopicCreation = !cfg.Kafka.ProvisionTopics
+	kafkaPub := sharedKafka.NewLazyPublisher(func() (message.Publisher, error) {
+		publisher, err := kafka.NewPublisher(
+			kafka.PublisherConfig{
+				Brokers:               cfg.Kafka.Brokers,
+				Marshaler:             partitioningMarshaler,
+				OverwriteSaramaConfig: saramaPublisherConfig,
+			},
+			watermill.NewSlogLogger(logger.GetLogger()),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create kafka publisher: %w", err)
+		}
+		return publisher, nil
+	})
+	go kafkaPub.Run(ctx, cfg.Kafka.ReconnectInterval)
+	waitForKafka(ctx, kafkaPub, cfg.Kafka.ConnectTimeout)
+
+	messagingBus, err := messaging.NewBus(messaging.Config{
+		Publisher: sharedKafka.NewRequestIDPublisher(sharedKafka.NewNamingPublisher(kafkaPub, topology.Naming)),
+		Subscriber: sharedKafka.NewDeadlineSubscriber(
+			sharedKafka.NewConcurrentSubscriber(
+				sharedKafka.NewRequestIDSubscriber(sharedKafka.NewNamingSubscriber(kafkaSub, topology.Naming)),
+				topology.Consumers,
+			),
+			topology.Consumers,
+			cfg.Kafka.HandlerTimeout,
+		),
+		Logger: logger.GetLogger(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create messaging bus: %w", err)
+	}
+
+	store := storage.NewDiskStore(cfg.Storage.Path)
+	assetService := assets.NewService(assets.NewPostgresStore(db), store, assetBaseURL(cfg))
+
+	return NewAppContext(db, redisClient, sessionService, shortLinkService, store, assetService, messagingBus, messagingBus, messagingBus, kafkaPub), nil
+}
+
+// waitForKafka gives the publisher up to timeout to reach the brokers, so a healthy startup does not
+// begin degraded
+func waitForKafka(ctx context.Context, publisher *sharedKafka.LazyPublisher, timeout time.Duration) {
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := publisher.Wait(waitCtx); err != nil {
+		logger.Warning(ctx, "Kafka brokers are unreachable, starting degraded: events are parked in the outbox and messages are consumed once they are reachable",
+			logger.F("error", publisher.Check(ctx)))
+	}
+}
+
+// assetBaseURL is where the hosted images are served from, the API itself without a CDN
+func assetBaseURL(cfg *config.AppConfig) string {
+	if cfg.Assets.BaseURL != "" {
+		return cfg.Assets.BaseURL
+	}
+	return cfg.ShortLinks.BaseURL
+}
+
+// newSessionPolicy builds the token lifetimes of the sessions from the jwt config
+func newSessionPolicy(cfg config.JWT) session.Policy {
+	policy := session.Policy{
+		Default:    session.Lifetime{Access: cfg.AccessTokenExpiry, Refresh: cfg.RefreshTokenExpiry},
+		Clients:    make(map[session.Client]session.Lifetime, len(cfg.Clients)),
+		RememberMe: session.Lifetime{Access: cfg.RememberMe.AccessTokenExpiry, Refresh: cfg.RememberMe.RefreshTokenExpiry},
+		Max:        session.Lifetime{Access: cfg.MaxAccessTokenExpiry, Refresh: cfg.MaxRefreshTokenExpiry},
+	}
+	for client, lifetime := range cfg.Clients {
+		policy.Clients[session.Client(client)] = session.Lifetime{Access: lifetime.AccessTokenExpiry, Refresh: lifetime.RefreshTokenExpiry}
+	}
+	return policy
+}
+
+// LogConfigSources logs the values that did not come from the base config file and where they came
+// from, to tell which layer of the merge won. Values are left out, they may be secrets.
+func LogConfigSources(ctx context.Context, cfg *config.AppConfig) {
+	overrides := cfg.Overrides()
+	for _, entry := range overrides {
+		logger.Info(ctx, "Configuration value overridden", logger.F("key", entry.Key), logger.F("source", entry.Source))
+	}
+	logger.Info(ctx, "Configuration sources resolved",
+		logger.F("values", len(cfg.Effective())),
+		logger.F("overridden", len(overrides)))
+}
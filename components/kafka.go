@@ -0,0 +1,45 @@
+package components
+
+import (
+	"tixgo/config"
+	sharedKafka "tixgo/shared/kafka"
+)
+
+// KafkaConsumerGroup returns the consumer group of the subscribers
+func KafkaConsumerGroup(cfg config.Kafka) string {
+	if cfg.ConsumerGroup == "" {
+		return "tixgo_consumer_group"
+	}
+	return cfg.ConsumerGroup
+}
+
+// NewKafkaTopology builds the Kafka topology (naming, consumers, topics) from configuration
+func NewKafkaTopology(cfg config.Kafka) sharedKafka.Topology {
+	consumers := make([]sharedKafka.ConsumerConfig, len(cfg.Consumers))
+	for i, consumer := range cfg.Consumers {
+		consumers[i] = sharedKafka.ConsumerConfig{
+			Topic:       consumer.Topic,
+			Concurrency: consumer.Concurrency,
+			Ordered:     consumer.Ordered,
+			Timeout:     consumer.Timeout,
+			MaxLag:      consumer.MaxLag,
+		}
+	}
+
+	topics := make([]sharedKafka.TopicConfig, len(cfg.Topics))
+	for i, topic := range cfg.Topics {
+		topics[i] = sharedKafka.TopicConfig{
+			Name:              topic.Name,
+			Partitions:        topic.Partitions,
+			ReplicationFactor: topic.ReplicationFactor,
+			Retention:         topic.Retention,
+			CleanupPolicy:     topic.CleanupPolicy,
+		}
+	}
+
+	return sharedKafka.Topology{
+		Naming:    sharedKafka.NewTopicNaming(cfg.TopicPrefix),
+		Consumers: sharedKafka.NewConsumerSettings(consumers),
+		Topics:    topics,
+	}
+}
@@ -0,0 +1,23 @@
+package components
+
+import (
+	"tixgo/config"
+	"tixgo/shared/ratelimit"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// NewSendThrottle returns the throttle holding the sends to provider to its quota in send_limits, nil
+// when it has none. provider is the name the mail provider reports in GetProviderInfo, or "twilio".
+func NewSendThrottle(cfg *config.AppConfig, redisClient redis.UniversalClient, provider string) *ratelimit.Throttle {
+	limit, ok := cfg.SendLimits[provider]
+	if !ok {
+		return nil
+	}
+
+	return ratelimit.NewThrottle(ratelimit.NewRedisLimiter(redisClient), provider, ratelimit.Limit{
+		Rate:  limit.Rate,
+		Per:   limit.Per,
+		Burst: limit.Burst,
+	})
+}
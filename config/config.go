@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/viper"
@@ -13,7 +14,8 @@ func LoadConfig() (*AppConfig, error) {
 	v := viper.New()
 	setupViper(v)
 
-	if err := loadConfigurations(v); err != nil {
+	s := newSources()
+	if err := loadConfigurations(v, s); err != nil {
 		return nil, err
 	}
 
@@ -26,19 +28,23 @@ func LoadConfig() (*AppConfig, error) {
 		return nil, err
 	}
 
+	config.baseFile = s.baseFile
+	config.entries = s.entries(v)
+
 	return config, nil
 }
 
-func loadConfigurations(v *viper.Viper) error {
-	if err := loadBaseConfig(v); err != nil {
+func loadConfigurations(v *viper.Viper, s *sources) error {
+	if err := loadBaseConfig(v, s); err != nil {
 		return err
 	}
 
-	if err := loadEnvConfig(v); err != nil {
+	if err := loadEnvConfig(v, s); err != nil {
 		return err
 	}
 
 	setupEnvVars(v)
+	s.addEnvVars(v)
 
 	return nil
 }
@@ -53,7 +59,7 @@ func setupViper(v *viper.Viper) {
 	v.SetConfigName("config")
 }
 
-func loadBaseConfig(v *viper.Viper) error {
+func loadBaseConfig(v *viper.Viper, s *sources) error {
 	if err := v.ReadInConfig(); err != nil {
 		if ok := errors.As(err, &viper.ConfigFileNotFoundError{}); !ok {
 			return fmt.Errorf("config file not found: %w", err)
@@ -62,14 +68,19 @@ func loadBaseConfig(v *viper.Viper) error {
 		fmt.Println("Base config file not found, replying on environment variables")
 	} else {
 		fmt.Println("Base config file is loaded")
+		if err := s.addFile(v.ConfigFileUsed()); err != nil {
+			return fmt.Errorf("failed to read config file %s: %w", v.ConfigFileUsed(), err)
+		}
+		s.baseFile = filepath.Base(v.ConfigFileUsed())
 	}
 
 	return nil
 }
 
-func loadEnvConfig(v *viper.Viper) error {
+func loadEnvConfig(v *viper.Viper, s *sources) error {
 	env := getEnvironment()
 	v.Set("environment", env)
+	s.byKey["environment"] = "APP_ENV"
 
 	v.SetConfigName("config." + env)
 	if err := v.MergeInConfig(); err != nil {
@@ -80,6 +91,9 @@ func loadEnvConfig(v *viper.Viper) error {
 		fmt.Printf("Environment config file config.%s.yaml not found, replying on base config\n", env)
 	} else {
 		fmt.Printf("Merge config in file config.%s.yaml to base config\n", env)
+		if err := s.addFile(v.ConfigFileUsed()); err != nil {
+			return fmt.Errorf("failed to read config file %s: %w", v.ConfigFileUsed(), err)
+		}
 	}
 
 	return nil
@@ -2,6 +2,7 @@ package config_test
 
 import (
 	"os"
+	"strings"
 	"testing"
 	"time"
 	"tixgo/config"
@@ -50,6 +51,33 @@ database:
   max_idle_conns: 5
   max_lifetime: 3600s
   max_idle_time: 3600s
+  migration_path: file://migrations
+jwt:
+  secret_key: secret
+  access_token_expiry: 900s
+  refresh_token_expiry: 604800s
+  issuer: tixgo-test
+  audience: tixgo-api
+redis:
+  host: localhost
+  port: 6379
+kafka:
+  brokers:
+    - localhost:9092
+  consumers:
+    - topic: commands.SendOTPVerifyMail
+      concurrency: 4
+      ordered: true
+short_links:
+  base_url: http://localhost:8000
+storage:
+  path: ./data/storage
+orders:
+  ticket_qr:
+    key_id: test
+    secret: test-ticket-qr-secret-0123456789abcdef
+seo:
+  site_url: http://localhost:3000
 `
 	invalidYaml := `app: [name: tixgo` // malformed YAML
 	invalidValues := `
@@ -93,6 +121,9 @@ database:
 			if cfg.Server.ReadTimeout != 10*time.Second {
 				t.Errorf("expected 10s, got %v", cfg.Server.ReadTimeout)
 			}
+			if len(cfg.Kafka.Consumers) != 1 || cfg.Kafka.Consumers[0].Concurrency != 4 || !cfg.Kafka.Consumers[0].Ordered {
+				t.Errorf("unexpected kafka consumers: %+v", cfg.Kafka.Consumers)
+			}
 		})
 	})
 
@@ -184,4 +215,133 @@ database:
 			}
 		})
 	})
+
+	t.Run("expose otp only in dev", func(t *testing.T) {
+		withTempDir(t, func(tmpDir string) {
+			exposed := strings.Replace(validConfig, "  debug_mode: true\n", "  debug_mode: true\n  expose_otp: true\n", 1)
+			if err := writeTempFile(tmpDir, "config.yaml", exposed); err != nil {
+				t.Fatalf("write config: %v", err)
+			}
+			cfg, err := config.LoadConfig()
+			if err != nil {
+				t.Fatalf("LoadConfig failed: %v", err)
+			}
+			if !cfg.App.ExposeOTP {
+				t.Error("expected expose_otp in dev")
+			}
+
+			staging := strings.Replace(exposed, "environment: dev", "environment: stg", 1)
+			if err := writeTempFile(tmpDir, "config.yaml", staging); err != nil {
+				t.Fatalf("write config: %v", err)
+			}
+			if _, err := config.LoadConfig(); err == nil {
+				t.Error("expected validation error for expose_otp outside dev, got nil")
+			}
+		})
+	})
+
+	t.Run("debug toggles panic in prod", func(t *testing.T) {
+		withTempDir(t, func(tmpDir string) {
+			debugging := validConfig + "debug:\n  echo_otp: true\n  dump_requests: true\n"
+			if err := writeTempFile(tmpDir, "config.yaml", debugging); err != nil {
+				t.Fatalf("write config: %v", err)
+			}
+			cfg, err := config.LoadConfig()
+			if err != nil {
+				t.Fatalf("LoadConfig failed: %v", err)
+			}
+			if toggles := strings.Join(cfg.DebugToggles(), ","); toggles != "debug.echo_otp,debug.dump_requests" {
+				t.Errorf("expected the echo_otp and dump_requests toggles, got %q", toggles)
+			}
+			cfg.MustDisableDebugInProd()
+
+			production := strings.Replace(debugging, "environment: dev", "environment: prod", 1)
+			if err := writeTempFile(tmpDir, "config.yaml", production); err != nil {
+				t.Fatalf("write config: %v", err)
+			}
+			cfg, err = config.LoadConfig()
+			if err != nil {
+				t.Fatalf("LoadConfig failed: %v", err)
+			}
+			defer func() {
+				if recover() == nil {
+					t.Error("expected a panic for debug toggles in prod")
+				}
+			}()
+			cfg.MustDisableDebugInProd()
+		})
+	})
+
+	t.Run("effective config records sources and masks secrets", func(t *testing.T) {
+		withTempDir(t, func(tmpDir string) {
+			t.Setenv("APP_ENV", "testenv")
+			t.Setenv("APP_DATABASE_PASSWORD", "fromenv")
+			if err := writeTempFile(tmpDir, "config.yaml", validConfig); err != nil {
+				t.Fatalf("write config: %v", err)
+			}
+			if err := writeTempFile(tmpDir, "config.testenv.yaml", "server:\n  port: 8082\n"); err != nil {
+				t.Fatalf("write config.env: %v", err)
+			}
+			cfg, err := config.LoadConfig()
+			if err != nil {
+				t.Fatalf("LoadConfig failed: %v", err)
+			}
+
+			effective := map[string]config.Entry{}
+			for _, entry := range cfg.Effective() {
+				effective[entry.Key] = entry
+			}
+			expected := map[string]config.Entry{
+				"app.name":                {Key: "app.name", Value: "tixgo", Source: "config.yaml"},
+				"server.port":             {Key: "server.port", Value: 8082, Source: "config.testenv.yaml"},
+				"database.password":       {Key: "database.password", Value: "********", Source: "APP_DATABASE_PASSWORD"},
+				"jwt.secret_key":          {Key: "jwt.secret_key", Value: "********", Source: "config.yaml"},
+				"jwt.access_token_expiry": {Key: "jwt.access_token_expiry", Value: "900s", Source: "config.yaml"},
+				"environment":             {Key: "environment", Value: "testenv", Source: "APP_ENV"},
+			}
+			for key, want := range expected {
+				if got := effective[key]; got != want {
+					t.Errorf("expected %+v, got %+v", want, got)
+				}
+			}
+
+			var overridden []string
+			for _, entry := range cfg.Overrides() {
+				overridden = append(overridden, entry.Key)
+			}
+			if strings.Join(overridden, ",") != "database.password,environment,server.port" {
+				t.Errorf("unexpected overrides: %v", overridden)
+			}
+		})
+	})
+
+	t.Run("request timeouts", func(t *testing.T) {
+		withTempDir(t, func(tmpDir string) {
+			timeouts := strings.Replace(validConfig, "  idle_timeout: 10s\n",
+				"  idle_timeout: 10s\n  request_timeout: 8s\n  route_timeouts:\n    /events/:id/seats/stream: 0s\n    /templates/render-batch: 9s\n", 1)
+			if err := writeTempFile(tmpDir, "config.yaml", timeouts); err != nil {
+				t.Fatalf("write config: %v", err)
+			}
+			cfg, err := config.LoadConfig()
+			if err != nil {
+				t.Fatalf("LoadConfig failed: %v", err)
+			}
+			if cfg.Server.RequestTimeout != 8*time.Second {
+				t.Errorf("expected 8s, got %v", cfg.Server.RequestTimeout)
+			}
+			stream, ok := cfg.Server.RouteTimeouts["/events/:id/seats/stream"]
+			if !ok || stream != 0 || cfg.Server.RouteTimeouts["/templates/render-batch"] != 9*time.Second {
+				t.Errorf("unexpected route timeouts: %v", cfg.Server.RouteTimeouts)
+			}
+
+			// the 504 could not be written past the write timeout
+			tooLong := strings.Replace(timeouts, "request_timeout: 8s", "request_timeout: 10s", 1)
+			if err := writeTempFile(tmpDir, "config.yaml", tooLong); err != nil {
+				t.Fatalf("write config: %v", err)
+			}
+			if _, err := config.LoadConfig(); err == nil {
+				t.Error("expected validation error for a request timeout reaching the write timeout, got nil")
+			}
+		})
+	})
 }
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DebugToggles returns the keys of the debugging aids switched on, app.expose_otp included
+func (c *AppConfig) DebugToggles() []string {
+	var toggles []string
+	if c.App.ExposeOTP {
+		toggles = append(toggles, "app.expose_otp")
+	}
+	if c.Debug.TemplatePreviews {
+		toggles = append(toggles, "debug.template_previews")
+	}
+	if c.Debug.EchoOTP {
+		toggles = append(toggles, "debug.echo_otp")
+	}
+	if c.Debug.DumpRequests {
+		toggles = append(toggles, "debug.dump_requests")
+	}
+	return toggles
+}
+
+// MustDisableDebugInProd panics when a debugging aid is switched on in the prod environment. The
+// servers call it at startup: an override leaking from a staging setup must not get served.
+func (c *AppConfig) MustDisableDebugInProd() {
+	if c.App.Environment != "prod" {
+		return
+	}
+	if toggles := c.DebugToggles(); len(toggles) > 0 {
+		panic(fmt.Sprintf("debugging aids are not allowed in prod, switch off %s", strings.Join(toggles, ", ")))
+	}
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// maskedValue replaces the values of secrets wherever the configuration is shown
+const maskedValue = "********"
+
+// Entry is an effective configuration value and the source that supplied it: a config file,
+// an environment variable, or APP_ENV for the environment
+type Entry struct {
+	Key    string      `json:"key"`
+	Value  interface{} `json:"value"`
+	Source string      `json:"source"`
+}
+
+// Effective returns the merged configuration as loaded, sorted by key, with secrets masked
+func (c *AppConfig) Effective() []Entry {
+	return append([]Entry(nil), c.entries...)
+}
+
+// Overrides returns the effective values that did not come from the base config file
+func (c *AppConfig) Overrides() []Entry {
+	var overrides []Entry
+	for _, entry := range c.entries {
+		if entry.Source != c.baseFile {
+			overrides = append(overrides, entry)
+		}
+	}
+	return overrides
+}
+
+// sources records which layer of the merge supplied each key, the later layers overriding the earlier
+type sources struct {
+	baseFile string
+	byKey    map[string]string
+}
+
+func newSources() *sources {
+	return &sources{byKey: make(map[string]string)}
+}
+
+// addFile records the keys of a config file
+func (s *sources) addFile(path string) error {
+	file := viper.New()
+	file.SetConfigFile(path)
+	if err := file.ReadInConfig(); err != nil {
+		return err
+	}
+
+	for _, key := range file.AllKeys() {
+		s.byKey[key] = filepath.Base(path)
+	}
+	return nil
+}
+
+// addEnvVars records the keys of v set by an environment variable
+func (s *sources) addEnvVars(v *viper.Viper) {
+	for _, key := range v.AllKeys() {
+		name := envVarName(key)
+		if _, ok := os.LookupEnv(name); ok {
+			s.byKey[key] = name
+		}
+	}
+}
+
+// entries lists the effective values of v with their source
+func (s *sources) entries(v *viper.Viper) []Entry {
+	keys := v.AllKeys()
+	sort.Strings(keys)
+
+	entries := make([]Entry, 0, len(keys))
+	for _, key := range keys {
+		value := v.Get(key)
+		if isSecret(key) && value != "" && value != nil {
+			value = maskedValue
+		}
+		entries = append(entries, Entry{Key: key, Value: value, Source: s.byKey[key]})
+	}
+	return entries
+}
+
+// envVarName is the environment variable overriding key, e.g. APP_DATABASE_PASSWORD
+func envVarName(key string) string {
+	return "APP_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// isSecret tells whether the value of key must not be shown, by the name of the key
+func isSecret(key string) bool {
+	name := key[strings.LastIndex(key, ".")+1:]
+	return strings.Contains(name, "password") ||
+		strings.Contains(name, "secret") ||
+		strings.HasSuffix(name, "token") ||
+		strings.HasSuffix(name, "private_key") ||
+		strings.HasSuffix(name, "api_key")
+}
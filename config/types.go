@@ -7,17 +7,82 @@ import (
 )
 
 type AppConfig struct {
-	App      App      `mapstructure:"app"`
-	Server   Server   `mapstructure:"server"`
-	Database Database `mapstructure:"database"`
-	JWT      JWT      `mapstructure:"jwt"`
-	Kafka    Kafka    `mapstructure:"kafka"`
+	App        App        `mapstructure:"app"`
+	Server     Server     `mapstructure:"server"`
+	Database   Database   `mapstructure:"database"`
+	JWT        JWT        `mapstructure:"jwt"`
+	Redis      Redis      `mapstructure:"redis"`
+	Kafka      Kafka      `mapstructure:"kafka"`
+	Scheduler  Scheduler  `mapstructure:"scheduler"`
+	API        API        `mapstructure:"api"`
+	Webhooks   Webhooks   `mapstructure:"webhooks"`
+	Mail       Mail       `mapstructure:"mail"`
+	SMS        SMS        `mapstructure:"sms"`
+	Security   Security   `mapstructure:"security"`
+	ShortLinks ShortLinks `mapstructure:"short_links"`
+	Storage    Storage    `mapstructure:"storage"`
+	Debug      Debug      `mapstructure:"debug"`
+	// SendLimits are the send quotas of the mail and SMS providers, by provider name
+	SendLimits map[string]SendLimit `mapstructure:"send_limits" validate:"dive"`
+	// Registration decides how the emails of accounts are compared and which can register one
+	Registration Registration `mapstructure:"registration"`
+	// Templates configures the allow-list the HTML of templates and their variables is sanitized with,
+	// and the variables they render with by default
+	Templates Templates `mapstructure:"templates"`
+	// Compliance configures the exports of the audit logs and notification history to object storage
+	Compliance Compliance `mapstructure:"compliance"`
+	// Orders configures the checkouts
+	Orders Orders `mapstructure:"orders"`
+	// Assets configures where the hosted images of templates are served from
+	Assets Assets `mapstructure:"assets"`
+	// SEO configures what search engines are told of the public event pages
+	SEO SEO `mapstructure:"seo"`
+	// Support configures the help desk the problems users report are sent to
+	Support Support `mapstructure:"support"`
+	// Legal configures the versions of the terms of service and privacy policy users accept
+	Legal Legal `mapstructure:"legal"`
+	// Features soft-launch new modules to an allowlist of users, by feature name
+	Features map[string]Feature `mapstructure:"features" validate:"dive"`
+
+	// baseFile and entries record where the loaded values came from, see Effective
+	baseFile string
+	entries  []Entry
 }
 
 type App struct {
 	Name        string `mapstructure:"name"`
 	Environment string `mapstructure:"environment" validate:"required,oneof=dev stg prod"`
 	DebugMode   bool   `mapstructure:"debug_mode" validate:"required"`
+	// ExposeOTP logs the verification codes so a local setup can verify accounts without a mail server.
+	// Only allowed in the dev environment.
+	ExposeOTP bool `mapstructure:"expose_otp" validate:"excluded_unless=Environment dev"`
+}
+
+// Debug switches on the debugging aids compiled into the servers, for local and staging setups.
+// They are hard-disabled in prod: the servers panic at startup when one is on there, see MustDisableDebugInProd.
+type Debug struct {
+	// TemplatePreviews serves admins the rendering of every draft template at /debug/templates/drafts
+	TemplatePreviews bool `mapstructure:"template_previews"`
+	// EchoOTP serves the pending verification code of an email at /debug/otp, for end to end tests
+	EchoOTP bool `mapstructure:"echo_otp"`
+	// DumpRequests logs the headers and body of every request and its response
+	DumpRequests bool `mapstructure:"dump_requests"`
+}
+
+// SendLimit is the quota of a provider: Rate sends every Per, Burst of them at once (default 1).
+// Sends over it wait for their slot, the quota being shared by every instance through redis.
+type SendLimit struct {
+	Rate  int           `mapstructure:"rate" validate:"required,min=1"`
+	Per   time.Duration `mapstructure:"per" validate:"required,min=1ms"`
+	Burst int           `mapstructure:"burst" validate:"omitempty,min=1"`
+}
+
+// RequestLimit is the quota of an API caller: Rate requests every Per, Burst of them at once (default
+// 1). Requests over it are refused with 429. Without a rate the default quota of the routes applies.
+type RequestLimit struct {
+	Rate  int           `mapstructure:"rate" validate:"omitempty,min=1"`
+	Per   time.Duration `mapstructure:"per" validate:"required_with=Rate,omitempty,min=1ms"`
+	Burst int           `mapstructure:"burst" validate:"omitempty,min=1"`
 }
 
 type Server struct {
@@ -26,6 +91,27 @@ type Server struct {
 	ReadTimeout  time.Duration `mapstructure:"read_timeout" validate:"required,min=1s"`
 	WriteTimeout time.Duration `mapstructure:"write_timeout" validate:"required,min=1s"`
 	IdleTimeout  time.Duration `mapstructure:"idle_timeout" validate:"required,min=1s"`
+	// RequestTimeout bounds the handling of API requests, below WriteTimeout so the 504 still goes out;
+	// zero leaves them unbounded. RouteTimeouts override it for the routes under a path relative to the
+	// API version, like "/templates/render-batch", zero lifting the limit as streams need.
+	RequestTimeout time.Duration            `mapstructure:"request_timeout" validate:"omitempty,min=1s,ltfield=WriteTimeout"`
+	RouteTimeouts  map[string]time.Duration `mapstructure:"route_timeouts" validate:"dive,keys,startswith=/,endkeys,min=0"`
+	// TrustedProxies are the addresses and CIDR ranges of the proxies in front of the API,
+	// whose X-Request-ID and X-Forwarded-For headers are trusted
+	TrustedProxies []string `mapstructure:"trusted_proxies" validate:"dive,cidr|ip"`
+	// StrictJSON rejects unknown fields in every JSON body, otherwise only the routes asking for it do
+	StrictJSON bool `mapstructure:"strict_json"`
+	// ReadOnly refuses writes whatever admins set, for failovers during which redis is unavailable too
+	ReadOnly bool `mapstructure:"read_only"`
+}
+
+// Security configures the security headers of the responses, empty fields keep the defaults
+type Security struct {
+	// HSTSMaxAge defaults to a year, except in the dev environment which is served over http
+	HSTSMaxAge            time.Duration `mapstructure:"hsts_max_age" validate:"omitempty,min=1s"`
+	FrameOptions          string        `mapstructure:"frame_options" validate:"omitempty,oneof=DENY SAMEORIGIN"`
+	ReferrerPolicy        string        `mapstructure:"referrer_policy" validate:"omitempty,oneof=no-referrer same-origin strict-origin strict-origin-when-cross-origin"`
+	ContentSecurityPolicy string        `mapstructure:"content_security_policy"`
 }
 
 type Database struct {
@@ -41,23 +127,346 @@ type Database struct {
 	MaxLifetime   time.Duration `mapstructure:"max_lifetime" validate:"required,min=1s"`
 	MaxIdleTime   time.Duration `mapstructure:"max_idle_time" validate:"required,min=1s"`
 	MigrationPath string        `mapstructure:"migration_path" validate:"required"`
+	// QueryTimeout bounds every repository call, including the wait for a pooled connection (default 5s)
+	QueryTimeout time.Duration `mapstructure:"query_timeout" validate:"omitempty,min=1ms"`
 }
 
 type JWT struct {
 	SecretKey          string        `mapstructure:"secret_key" validate:"required"`
 	AccessTokenExpiry  time.Duration `mapstructure:"access_token_expiry" validate:"required,min=1s"`
 	RefreshTokenExpiry time.Duration `mapstructure:"refresh_token_expiry" validate:"required,min=1s"`
+	// Issuer and Audience are set in every token and checked when validating one, they must differ
+	// per environment and service so their tokens are not accepted by one another
+	Issuer   string `mapstructure:"issuer" validate:"required"`
+	Audience string `mapstructure:"audience" validate:"required"`
+	// Clients overrides the token lifetimes per client type (web, mobile) negotiated at login
+	Clients map[string]TokenLifetime `mapstructure:"clients" validate:"omitempty,dive,keys,oneof=web mobile,endkeys"`
+	// RememberMe extends the lifetimes of the sessions whose user asks to be remembered
+	RememberMe TokenLifetime `mapstructure:"remember_me"`
+	// MaxAccessTokenExpiry and MaxRefreshTokenExpiry cap every lifetime, zero caps nothing
+	MaxAccessTokenExpiry  time.Duration `mapstructure:"max_access_token_expiry" validate:"omitempty,min=1s"`
+	MaxRefreshTokenExpiry time.Duration `mapstructure:"max_refresh_token_expiry" validate:"omitempty,min=1s"`
 }
 
-// type Redis struct {
-// 	Host     string `mapstructure:"host" validate:"required,hostname"`
-// 	Port     int    `mapstructure:"port" validate:"required,min=1,max=65535"`
-// 	Password string `mapstructure:"password"`
-// 	DB       int    `mapstructure:"db"` // default 0
-// }
+// TokenLifetime is how long access and refresh tokens are valid, zero keeps the default
+type TokenLifetime struct {
+	AccessTokenExpiry  time.Duration `mapstructure:"access_token_expiry" validate:"omitempty,min=1s"`
+	RefreshTokenExpiry time.Duration `mapstructure:"refresh_token_expiry" validate:"omitempty,min=1s"`
+}
+
+type Redis struct {
+	Host     string `mapstructure:"host" validate:"required,hostname"`
+	Port     int    `mapstructure:"port" validate:"required,min=1,max=65535"`
+	Password string `mapstructure:"password"`
+	DB       int    `mapstructure:"db"` // default 0
+}
 
 type Kafka struct {
-	Brokers []string `mapstructure:"brokers" validate:"required,min=1"`
+	Brokers       []string        `mapstructure:"brokers" validate:"required,min=1"`
+	ConsumerGroup string          `mapstructure:"consumer_group"`
+	Consumers     []KafkaConsumer `mapstructure:"consumers" validate:"dive"`
+	// TopicPrefix namespaces every topic per environment, e.g. "dev" turns "events.X" into "dev.events.X"
+	TopicPrefix string `mapstructure:"topic_prefix"`
+	// ProvisionTopics declares Topics at startup and disables broker auto-creation
+	ProvisionTopics bool         `mapstructure:"provision_topics"`
+	Topics          []KafkaTopic `mapstructure:"topics" validate:"dive"`
+	// HandlerTimeout is the deadline of handling a single message, retries included (default 30s)
+	HandlerTimeout time.Duration `mapstructure:"handler_timeout" validate:"omitempty,min=1ms"`
+	// LagCheckInterval is how often the API server collects the consumer group lag (default 30s)
+	LagCheckInterval time.Duration `mapstructure:"lag_check_interval" validate:"omitempty,min=1s"`
+	// ConnectTimeout is how long startup waits for the brokers before serving degraded without them,
+	// zero not waiting. The brokers are retried every ReconnectInterval (default 5s) meanwhile.
+	ConnectTimeout    time.Duration `mapstructure:"connect_timeout" validate:"min=0"`
+	ReconnectInterval time.Duration `mapstructure:"reconnect_interval" validate:"omitempty,min=1s"`
+}
+
+// KafkaTopic declares a topic and its settings
+type KafkaTopic struct {
+	Name              string        `mapstructure:"name" validate:"required"`
+	Partitions        int32         `mapstructure:"partitions" validate:"omitempty,min=1"`
+	ReplicationFactor int16         `mapstructure:"replication_factor" validate:"omitempty,min=1"`
+	Retention         time.Duration `mapstructure:"retention"`
+	CleanupPolicy     string        `mapstructure:"cleanup_policy" validate:"omitempty,oneof=delete compact"`
+}
+
+// KafkaConsumer configures how a single topic is consumed
+type KafkaConsumer struct {
+	Topic string `mapstructure:"topic" validate:"required"`
+	// Concurrency is the number of consumer group members run per instance, bounded by the topic partitions
+	Concurrency int `mapstructure:"concurrency" validate:"omitempty,min=1"`
+	// Ordered partitions messages by their partition key so a single key is never processed out of order
+	Ordered bool `mapstructure:"ordered"`
+	// Timeout overrides the handler timeout of the topic
+	Timeout time.Duration `mapstructure:"timeout" validate:"omitempty,min=1ms"`
+	// MaxLag is the consumer group lag past which the API server reports unavailable, zero only reports it
+	MaxLag int64 `mapstructure:"max_lag" validate:"omitempty,min=1"`
+}
+
+type Scheduler struct {
+	// JobRunRetention is how long the run history of scheduled jobs is kept
+	JobRunRetention time.Duration `mapstructure:"job_run_retention" validate:"omitempty,min=1h"`
+}
+
+type API struct {
+	// Deprecations announces the end of life of older API versions, keyed by version, e.g. "v1"
+	Deprecations map[string]APIDeprecation `mapstructure:"deprecations" validate:"dive"`
+}
+
+// APIDeprecation dates are formatted 2006-01-02
+type APIDeprecation struct {
+	DeprecatedAt string `mapstructure:"deprecated_at" validate:"required,datetime=2006-01-02"`
+	SunsetAt     string `mapstructure:"sunset_at" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// Webhooks configures the inbound provider callbacks. The endpoint of a provider is served only
+// once its secret is set.
+type Webhooks struct {
+	// PublicURL is the scheme and host providers call, which some of them sign
+	PublicURL string `mapstructure:"public_url" validate:"required_with=SMS.AuthToken,omitempty,url"`
+	// ReplayWindow bounds how old a delivery may be and how long deliveries are deduplicated
+	ReplayWindow time.Duration  `mapstructure:"replay_window" validate:"omitempty,min=1s"`
+	Payment      PaymentWebhook `mapstructure:"payment"`
+	// Bank reports the transfers received for the orders paid by bank transfer, signed like the
+	// payment provider signs its callbacks
+	Bank     PaymentWebhook  `mapstructure:"bank"`
+	SendGrid SendGridWebhook `mapstructure:"sendgrid"`
+	SMS      SMSWebhook      `mapstructure:"sms"`
+}
+
+type PaymentWebhook struct {
+	Secret          string `mapstructure:"secret"`
+	SignatureHeader string `mapstructure:"signature_header" validate:"required_with=Secret"`
+	// TimestampHeader is left empty if the provider does not sign a timestamp
+	TimestampHeader string `mapstructure:"timestamp_header"`
+}
+
+type SendGridWebhook struct {
+	// PublicKey is the base64 verification key of the signed event webhook
+	PublicKey string `mapstructure:"public_key" validate:"omitempty,base64"`
+}
+
+type SMSWebhook struct {
+	AuthToken string `mapstructure:"auth_token"`
+}
+
+// SMS configures the Twilio account text messages are sent with. The API server sends none while
+// AccountSID is empty, the messages then wait in their topic.
+type SMS struct {
+	AccountSID string `mapstructure:"account_sid"`
+	AuthToken  string `mapstructure:"auth_token" validate:"required_with=AccountSID"`
+	// From is the number messages are sent from, in E.164 format
+	From string `mapstructure:"from" validate:"required_with=AccountSID,omitempty,e164"`
+}
+
+// ShortLinks configures the short URLs sent where characters are counted, e.g. SMS
+type ShortLinks struct {
+	// BaseURL is the public scheme and host of the short URLs, whose redirects the API serves
+	BaseURL string `mapstructure:"base_url" validate:"required,url"`
+}
+
+// Storage configures where uploaded files are kept
+type Storage struct {
+	// Path is the directory of the files, shared by every API server instance
+	Path string `mapstructure:"path" validate:"required"`
+}
+
+// Assets configures the URLs of the hosted images, whose files are kept in the storage
+type Assets struct {
+	// BaseURL is the public scheme and host of the images, e.g. a CDN in front of the API, the one of
+	// the short links when empty
+	BaseURL string `mapstructure:"base_url" validate:"omitempty,url"`
+}
+
+// SEO configures the canonical URLs of the public event pages in their meta tags, structured data and
+// the sitemap
+type SEO struct {
+	// SiteURL is the public scheme and host of the site showing the event pages at /events/<slug>
+	SiteURL string `mapstructure:"site_url" validate:"required,url"`
+}
+
+// Legal configures the current versions of the terms of service and privacy policy, which users accept
+// when registering and again at their next login once a version changes. A document without a
+// version is not asked for.
+type Legal struct {
+	TermsVersion   string `mapstructure:"terms_version" validate:"max=50"`
+	TermsURL       string `mapstructure:"terms_url" validate:"required_with=TermsVersion,omitempty,url"`
+	PrivacyVersion string `mapstructure:"privacy_version" validate:"max=50"`
+	PrivacyURL     string `mapstructure:"privacy_url" validate:"required_with=PrivacyVersion,omitempty,url"`
+}
+
+// Feature is the allowlist of a soft-launched module: its routes are only served to the users of
+// UserIDs, of EmailDomains or among the Percentage of users let in, and answer 404 to the others.
+// Enabled opens it to everyone once launched.
+type Feature struct {
+	Enabled      bool     `mapstructure:"enabled"`
+	UserIDs      []int64  `mapstructure:"user_ids" validate:"dive,min=1"`
+	EmailDomains []string `mapstructure:"email_domains" validate:"dive,required"`
+	Percentage   int      `mapstructure:"percentage" validate:"min=0,max=100"`
+}
+
+// Support configures the help desk agents answer the problems users report in. Without a provider,
+// reported problems are kept until one is set.
+type Support struct {
+	Provider string `mapstructure:"provider" validate:"omitempty,oneof=zendesk freshdesk"`
+	// BaseURL is the address of the account, e.g. https://acme.zendesk.com
+	BaseURL string `mapstructure:"base_url" validate:"required_with=Provider,omitempty,url"`
+	// Email is the agent the Zendesk API token belongs to, Freshdesk keys need none
+	Email    string `mapstructure:"email" validate:"required_if=Provider zendesk,omitempty,email"`
+	APIToken string `mapstructure:"api_token" validate:"required_with=Provider"`
+}
+
+// Mail configures the DNS records organizers publish to send mails from their own domain
+type Mail struct {
+	// SPFInclude is the domain the SPF record of sender domains must include, not checked if empty
+	SPFInclude string `mapstructure:"spf_include" validate:"omitempty,fqdn"`
+	// DKIMHost is the target of the DKIM CNAME record of sender domains, not checked if empty
+	DKIMHost string `mapstructure:"dkim_host" validate:"omitempty,fqdn"`
+}
+
+// Registration configures how the emails of accounts are compared and which can register one
+type Registration struct {
+	// FoldGmail drops the dots and +tag of Gmail addresses, which all reach the same mailbox
+	FoldGmail bool `mapstructure:"fold_gmail"`
+	// DisposableDomains are the domains of throwaway mailboxes, whose emails and the ones of their
+	// subdomains cannot register
+	DisposableDomains []string `mapstructure:"disposable_domains" validate:"dive,fqdn"`
+	// Risk scores registrations for spam, the zero values taking the defaults of the risk policy
+	Risk RegistrationRisk `mapstructure:"risk"`
+	// Captcha verifies the CAPTCHAs risky registrations are challenged with
+	Captcha Captcha `mapstructure:"captcha"`
+}
+
+// RegistrationRisk configures from which score registrations are challenged, queued for review or
+// blocked, and how fast an IP or a device may register
+type RegistrationRisk struct {
+	ChallengeScore int `mapstructure:"challenge_score" validate:"omitempty,min=1,max=100"`
+	ReviewScore    int `mapstructure:"review_score" validate:"omitempty,min=1,max=100"`
+	BlockScore     int `mapstructure:"block_score" validate:"omitempty,min=1,max=100"`
+	// VelocityWindow is the window registrations are counted in per IP and per device, 1 hour when zero
+	VelocityWindow time.Duration `mapstructure:"velocity_window" validate:"omitempty,min=1m,max=24h"`
+	// IPLimit and DeviceLimit are the registrations of the window that score nothing
+	IPLimit     int `mapstructure:"ip_limit" validate:"min=0"`
+	DeviceLimit int `mapstructure:"device_limit" validate:"min=0"`
+	// RiskyNetworks are the networks of bad reputation, in CIDR notation, e.g. of abusive hosting providers
+	RiskyNetworks []string `mapstructure:"risky_networks" validate:"dive,cidr"`
+}
+
+// Captcha configures the verification of CAPTCHA tokens against a reCAPTCHA or hCaptcha compatible
+// siteverify endpoint. Without one, registrations to challenge are queued for review instead.
+type Captcha struct {
+	VerifyURL string `mapstructure:"verify_url" validate:"omitempty,url"`
+	Secret    string `mapstructure:"secret" validate:"required_with=VerifyURL"`
+}
+
+// Templates configures the HTML sanitization policy of the templates, who may render them and the
+// variables every render has
+type Templates struct {
+	Sanitizer TemplateSanitizer `mapstructure:"sanitizer"`
+	Render    TemplateRender    `mapstructure:"render"`
+	// Defaults are the variables of the environment templates render with when their caller leaves them
+	// out, e.g. {{.AppName}}. Admins override them for the platform or an organizer.
+	Defaults []TemplateDefault `mapstructure:"defaults" validate:"dive"`
+}
+
+// TemplateDefault is the default value of a template variable. A list rather than a map keeps the case
+// of the names, which the configuration keys lose.
+type TemplateDefault struct {
+	Name  string `mapstructure:"name" validate:"required,max=100"`
+	Value string `mapstructure:"value" validate:"max=2000"`
+}
+
+// TemplateRender restricts the render endpoints, which only signed in callers reach
+type TemplateRender struct {
+	// PublicTypes are the template types non-admins may render, admins render every type
+	PublicTypes []string `mapstructure:"public_types" validate:"dive,oneof=email sms push"`
+	// RateLimit is the quota of every caller across the render endpoints
+	RateLimit RequestLimit `mapstructure:"rate_limit"`
+}
+
+// TemplateSanitizer is the allow-list of the HTML non-admins write email templates with and of the
+// variables inserted with safeHTML. An empty list allows the defaults of htmlsanitize.
+type TemplateSanitizer struct {
+	AllowedTags       []string `mapstructure:"allowed_tags"`
+	AllowedAttributes []string `mapstructure:"allowed_attributes"`
+	// URLSchemes are the schemes of links and images, e.g. https and mailto
+	URLSchemes []string `mapstructure:"url_schemes"`
+}
+
+// Compliance configures the daily exports of the audit logs and notification history, so that the
+// retention compliance asks for does not have to be kept in Postgres
+type Compliance struct {
+	// Prefix is the key prefix of the exported files, "compliance" when empty
+	Prefix string `mapstructure:"prefix"`
+	// Retention is how long the exported files must be kept, tagged on them for the lifecycle rules
+	// of the bucket; 7 years when zero
+	Retention time.Duration `mapstructure:"retention" validate:"omitempty,min=24h"`
+	// PurgeAfter deletes the exported rows from Postgres once they are that old, zero keeps them
+	PurgeAfter time.Duration `mapstructure:"purge_after" validate:"omitempty,min=24h"`
+	// S3 is the bucket of the exports, which go to the storage of uploaded files when it has none
+	S3 S3 `mapstructure:"s3"`
+}
+
+// Orders configures the checkouts of tickets
+type Orders struct {
+	// CheckoutHold is how long a checkout holds its tickets for the buyer to pay, 15 minutes when zero
+	CheckoutHold time.Duration `mapstructure:"checkout_hold" validate:"omitempty,min=1m,max=24h"`
+	// SeatHold is how long a buyer holds the seats they select before checking out, 10 minutes when zero
+	SeatHold time.Duration `mapstructure:"seat_hold" validate:"omitempty,min=1m,max=1h"`
+	// BankTransfer configures the orders paid by bank transfer against a pro-forma invoice
+	BankTransfer BankTransfer `mapstructure:"bank_transfer"`
+	// TicketQR signs the QR codes of the tickets confirmed orders sell
+	TicketQR TicketQR `mapstructure:"ticket_qr"`
+	// Receipt is the company the PDF receipts and pro-forma invoices of orders are issued by
+	Receipt Receipt `mapstructure:"receipt"`
+}
+
+// Receipt identifies the company issuing the receipts of orders
+type Receipt struct {
+	// Issuer is the legal name of the company, "TixGo" when empty
+	Issuer string `mapstructure:"issuer"`
+	// Address is printed under the name, one line per line of the address
+	Address string `mapstructure:"address"`
+	TaxID   string `mapstructure:"tax_id"`
+}
+
+// TicketQR are the HMAC keys signing the QR codes of tickets. Rotating the key is moving the current one
+// to Previous, where it still verifies the tickets it signed, until they are used.
+type TicketQR struct {
+	TicketQRKey `mapstructure:",squash"`
+	Previous    []TicketQRKey `mapstructure:"previous" validate:"dive"`
+	// BaseURL is the public scheme and host of the API serving the QR code images the confirmation mails
+	// embed, the one of the short links when empty
+	BaseURL string `mapstructure:"base_url" validate:"omitempty,url"`
+}
+
+// TicketQRKey is an HMAC key signing the QR codes of tickets
+type TicketQRKey struct {
+	// KeyID names the key in the payloads it signs
+	KeyID  string `mapstructure:"key_id" validate:"required,max=32,excludesall=."`
+	Secret string `mapstructure:"secret" validate:"required,min=32"`
+}
+
+// BankTransfer configures the orders of companies paying by bank transfer, which are refused while no
+// IBAN is set
+type BankTransfer struct {
+	// Hold is how long an order holds its tickets waiting for the transfer, 7 days when zero
+	Hold        time.Duration `mapstructure:"hold" validate:"omitempty,min=1h,max=720h"`
+	Beneficiary string        `mapstructure:"beneficiary" validate:"required_with=IBAN"`
+	BankName    string        `mapstructure:"bank_name"`
+	IBAN        string        `mapstructure:"iban"`
+	BIC         string        `mapstructure:"bic" validate:"required_with=IBAN"`
+}
+
+// S3 locates a bucket of an S3 compatible object storage
+type S3 struct {
+	// Endpoint is the URL of the storage, the AWS endpoint of Region when empty
+	Endpoint        string `mapstructure:"endpoint" validate:"omitempty,url"`
+	Region          string `mapstructure:"region" validate:"required_with=Bucket"`
+	Bucket          string `mapstructure:"bucket"`
+	AccessKeyID     string `mapstructure:"access_key_id" validate:"required_with=Bucket"`
+	SecretAccessKey string `mapstructure:"secret_access_key" validate:"required_with=Bucket"`
+	// PathStyle addresses the bucket in the path rather than the host, as MinIO needs
+	PathStyle bool `mapstructure:"path_style"`
 }
 
 func (c *AppConfig) Validate() error {
@@ -0,0 +1,81 @@
+package jobs
+
+import (
+	eventDomain "tixgo/modules/event/domain"
+	organizerDomain "tixgo/modules/organizer/domain"
+	schedulerCommand "tixgo/modules/scheduler/app/command"
+	templateDomain "tixgo/modules/template/domain"
+	userCommand "tixgo/modules/user/app/command"
+	userDomain "tixgo/modules/user/domain"
+	"tixgo/shared/eventbus"
+	sharedActivity "tixgo/shared/events/activity"
+	sharedMail "tixgo/shared/events/mail"
+	sharedNotification "tixgo/shared/events/notification"
+	sharedOrder "tixgo/shared/events/order"
+	sharedSMS "tixgo/shared/events/sms"
+	"tixgo/shared/webhook"
+)
+
+// The message catalog served at /event-catalog and documented in docs/events.md. A message published
+// or sent on the buses must be listed here with the modules producing it; run make event_catalog after
+// changing it.
+func init() {
+	eventbus.RegisterEvent(sharedMail.EventSendMail{},
+		"Asks for a mail to be sent. Attendee-facing mails carry the organizer, whose verified domain they are sent from. Attachments, like the receipts of confirmed orders, are carried base64 encoded in the message.",
+		"user", "notification", "event", "booking")
+	eventbus.RegisterEvent(sharedSMS.EventSendSMS{},
+		"Asks for a text message to a phone number, sent within the quota of the SMS provider.",
+		"user")
+	eventbus.RegisterEvent(sharedActivity.EventAccountActivity{},
+		"Records something that happened on the account of a user, for their activity feed; redeliveries are deduplicated by id.",
+		"user", "booking")
+	eventbus.RegisterEvent(sharedNotification.EventNotificationRequested{},
+		"Asks for a notification to a user. Low priority notifications are batched into the digest of the user, the others are mailed at once.",
+		"template", "organizer")
+	eventbus.RegisterEvent(sharedOrder.EventOrdersChanged{},
+		"Tells the read models built from orders that the listed orders, or every order of an event, changed.",
+		"booking", "event", "order")
+	eventbus.RegisterEvent(sharedOrder.EventOrderCreated{},
+		"A buyer checked out: the order is pending, or awaiting a bank transfer, its tickets held until it expires.",
+		"order")
+	eventbus.RegisterEvent(sharedOrder.EventOrderConfirmed{},
+		"An order was paid and its tickets sold to the buyer.",
+		"order")
+	eventbus.RegisterEvent(sharedOrder.EventOrderRefunded{},
+		"Tickets of an order were refunded and given back to sale; Full is set once no ticket of the order is left.",
+		"order")
+	eventbus.RegisterEvent(userDomain.EventUserRegistered{},
+		"A registration was started and awaits the verification of its email.",
+		"user")
+	eventbus.RegisterEvent(eventDomain.EventRefundRequested{},
+		"A refund the payment integration must issue. It may be published more than once, consumers deduplicate by RefundID.",
+		"event", "order")
+	eventbus.RegisterEvent(eventDomain.EventSeatStatusChanged{},
+		"A seat was held, released or sold.",
+		"booking", "event", "order")
+	eventbus.RegisterEvent(eventDomain.EventTicketAvailabilityChanged{},
+		"Organizers paused or resumed ticket sales, scheduled a pause or changed the capacity of a category, or a scheduled pause applied. Capacity changes may be delivered more than once, consumers apply them once by ChangeID.",
+		"event")
+	eventbus.RegisterEvent(eventDomain.EventConfigurationChanged{},
+		"An organizer or their API key changed the details, page, status, capacity or sales of an event. Recorded once by ChangeID in the change feed of the event.",
+		"event")
+	eventbus.RegisterEvent(templateDomain.EventTemplateReviewed{},
+		"An admin approved or rejected a template revision.",
+		"template")
+	eventbus.RegisterEvent(organizerDomain.EventKYCReviewed{},
+		"An admin approved or rejected the KYC submission of an organizer, who is told by mail.",
+		"organizer")
+	eventbus.RegisterEvent(organizerDomain.EventAPIQuotaWarning{},
+		"An API key used 80% of its monthly request quota, its organizer is warned by mail. Published once per key and month.",
+		"organizer")
+	eventbus.RegisterEvent(webhook.EventWebhookReceived{},
+		"A verified provider webhook delivery, with its raw payload. The provider already got its acknowledgement.",
+		"webhook")
+
+	eventbus.RegisterCommand(userCommand.SendOTPVerifyMailCommand{},
+		"Generates the verification code of a registration and mails it.",
+		"user")
+	eventbus.RegisterCommand(schedulerCommand.TriggerJobCommand{},
+		"Asks the worker to run a scheduled job right away.",
+		"scheduler")
+}
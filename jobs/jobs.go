@@ -0,0 +1,36 @@
+package jobs
+
+import (
+	"tixgo/components"
+	"tixgo/config"
+	bookingPort "tixgo/modules/booking/ports"
+	compliancePort "tixgo/modules/compliance/ports"
+	eventPort "tixgo/modules/event/ports"
+	notificationPort "tixgo/modules/notification/ports"
+	orderPort "tixgo/modules/order/ports"
+	organizerPort "tixgo/modules/organizer/ports"
+	schedulerPort "tixgo/modules/scheduler/ports"
+	supportPort "tixgo/modules/support/ports"
+	"tixgo/shared/scheduler"
+	"tixgo/shared/shortlink"
+)
+
+// All returns every scheduled job hosted by the worker. The API server uses the same list
+// to expose and trigger the jobs from the admin endpoints.
+func All(appCtx components.AppContext, cfg *config.AppConfig) []scheduler.Job {
+	var jobs []scheduler.Job
+
+	// Add any additional module jobs here
+	jobs = append(jobs, schedulerPort.Jobs(appCtx, cfg.Scheduler.JobRunRetention)...)
+	jobs = append(jobs, eventPort.Jobs(appCtx)...)
+	jobs = append(jobs, bookingPort.Jobs(appCtx)...)
+	jobs = append(jobs, notificationPort.Jobs(appCtx)...)
+	jobs = append(jobs, orderPort.Jobs(appCtx)...)
+	jobs = append(jobs, organizerPort.Jobs(appCtx)...)
+	jobs = append(jobs, compliancePort.Jobs(appCtx, cfg.Compliance)...)
+	jobs = append(jobs, supportPort.Jobs(appCtx, cfg.Support)...)
+	jobs = append(jobs, outboxJobs(appCtx)...)
+	jobs = append(jobs, shortlink.NewPurgeJob(shortlink.NewPostgresStore(appCtx.GetDB())))
+
+	return jobs
+}
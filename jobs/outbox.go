@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	"tixgo/components"
+	eventDomain "tixgo/modules/event/domain"
+	organizerDomain "tixgo/modules/organizer/domain"
+	templateDomain "tixgo/modules/template/domain"
+	userDomain "tixgo/modules/user/domain"
+	sharedActivity "tixgo/shared/events/activity"
+	sharedMail "tixgo/shared/events/mail"
+	sharedNotification "tixgo/shared/events/notification"
+	sharedOrder "tixgo/shared/events/order"
+	sharedSMS "tixgo/shared/events/sms"
+	"tixgo/shared/outbox"
+	"tixgo/shared/scheduler"
+)
+
+// Both binaries import jobs, so the events are registered wherever they are parked or relayed.
+// An event published through the reliable event bus must be listed here, it fails to park otherwise.
+func init() {
+	outbox.Register(
+		sharedMail.EventSendMail{},
+		sharedSMS.EventSendSMS{},
+		sharedActivity.EventAccountActivity{},
+		sharedNotification.EventNotificationRequested{},
+		sharedOrder.EventOrdersChanged{},
+		sharedOrder.EventOrderCreated{},
+		sharedOrder.EventOrderConfirmed{},
+		sharedOrder.EventOrderRefunded{},
+		userDomain.EventUserRegistered{},
+		eventDomain.EventRefundRequested{},
+		eventDomain.EventSeatStatusChanged{},
+		eventDomain.EventTicketAvailabilityChanged{},
+		eventDomain.EventConfigurationChanged{},
+		templateDomain.EventTemplateReviewed{},
+		organizerDomain.EventKYCReviewed{},
+		organizerDomain.EventAPIQuotaWarning{},
+	)
+}
+
+// outboxJobs returns the job relaying the parked events on the raw event bus
+func outboxJobs(appCtx components.AppContext) []scheduler.Job {
+	return []scheduler.Job{
+		outbox.NewRelayJob(appCtx.GetEventBus(), outbox.NewPostgresStore(appCtx.GetDB())),
+	}
+}
@@ -0,0 +1,6 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_job_runs_finished_at;
+DROP INDEX IF EXISTS idx_job_runs_job_name_started_at;
+
+-- Drop job_runs table
+DROP TABLE IF EXISTS job_runs;
@@ -0,0 +1,21 @@
+-- Create job_runs table
+CREATE TABLE IF NOT EXISTS job_runs (
+    id BIGSERIAL PRIMARY KEY,
+    job_name VARCHAR(255) NOT NULL,
+    trigger VARCHAR(50) NOT NULL CHECK (trigger IN ('schedule', 'manual')),
+    status VARCHAR(50) NOT NULL DEFAULT 'running' CHECK (status IN ('running', 'succeeded', 'failed')),
+    error TEXT,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS idx_job_runs_job_name_started_at ON job_runs(job_name, started_at DESC);
+CREATE INDEX IF NOT EXISTS idx_job_runs_finished_at ON job_runs(finished_at);
+
+-- Add comments for documentation
+COMMENT ON TABLE job_runs IS 'Run history of the jobs hosted by the worker scheduler';
+COMMENT ON COLUMN job_runs.job_name IS 'Name of the scheduled job';
+COMMENT ON COLUMN job_runs.trigger IS 'How the run was started: schedule or manual';
+COMMENT ON COLUMN job_runs.status IS 'Run status: running, succeeded, or failed';
+COMMENT ON COLUMN job_runs.error IS 'Error message of a failed run';
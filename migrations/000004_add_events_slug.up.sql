@@ -0,0 +1,4 @@
+-- Public event pages are addressed by slug
+ALTER TABLE events ADD COLUMN IF NOT EXISTS slug VARCHAR(255);
+
+COMMENT ON COLUMN events.slug IS 'Unique slug of the public event page';
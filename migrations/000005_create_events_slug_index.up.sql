@@ -0,0 +1,2 @@
+-- Built concurrently in a migration of its own: events is written to during on-sales
+CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS idx_events_slug ON events(slug);
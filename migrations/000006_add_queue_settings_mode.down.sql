@@ -0,0 +1 @@
+ALTER TABLE queue_settings DROP COLUMN IF EXISTS mode;
@@ -0,0 +1,2 @@
+-- On-sale queues admit buyers in join order (fifo) or draw the early ones at random (lottery)
+ALTER TABLE queue_settings ADD COLUMN IF NOT EXISTS mode VARCHAR(20) NOT NULL DEFAULT 'fifo';
@@ -0,0 +1,28 @@
+-- Group bookings: one buyer holds several seats and invites others to claim and pay for one each
+CREATE TABLE IF NOT EXISTS group_bookings (
+    id BIGSERIAL PRIMARY KEY,
+    event_id BIGINT NOT NULL REFERENCES events(id),
+    owner_id BIGINT NOT NULL REFERENCES users(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'completed', 'expired', 'cancelled')),
+    hold_expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS group_booking_seats (
+    id BIGSERIAL PRIMARY KEY,
+    group_booking_id BIGINT NOT NULL REFERENCES group_bookings(id) ON DELETE CASCADE,
+    ticket_id BIGINT NOT NULL REFERENCES tickets(id),
+    invitee_email VARCHAR(255) NOT NULL,
+    claim_token VARCHAR(64) NOT NULL UNIQUE,
+    status VARCHAR(20) NOT NULL DEFAULT 'invited' CHECK (status IN ('invited', 'claimed', 'paid', 'released')),
+    claimed_by BIGINT REFERENCES users(id),
+    order_id BIGINT REFERENCES orders(id),
+    claimed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_group_bookings_owner_id ON group_bookings(owner_id);
+CREATE INDEX IF NOT EXISTS idx_group_bookings_open_hold ON group_bookings(hold_expires_at) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_group_booking_seats_group_booking_id ON group_booking_seats(group_booking_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS event_cancellation_orders;
+DROP TABLE IF EXISTS event_cancellations;
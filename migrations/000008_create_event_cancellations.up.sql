@@ -0,0 +1,27 @@
+-- Event cancellations: the paid orders of a cancelled event are refunded and their holders notified in batches
+CREATE TABLE IF NOT EXISTS event_cancellations (
+    id BIGSERIAL PRIMARY KEY,
+    event_id BIGINT NOT NULL UNIQUE REFERENCES events(id),
+    cancelled_by BIGINT NOT NULL REFERENCES users(id),
+    reason TEXT NOT NULL,
+    status VARCHAR(30) NOT NULL DEFAULT 'running' CHECK (status IN ('running', 'completed', 'completed_with_failures')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE IF NOT EXISTS event_cancellation_orders (
+    id BIGSERIAL PRIMARY KEY,
+    cancellation_id BIGINT NOT NULL REFERENCES event_cancellations(id) ON DELETE CASCADE,
+    order_id BIGINT NOT NULL REFERENCES orders(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'processing', 'done', 'failed')),
+    refund_id BIGINT REFERENCES refunds(id),
+    refund_status VARCHAR(20) CHECK (refund_status IN ('requested', 'failed')),
+    notification_status VARCHAR(20) CHECK (notification_status IN ('sent', 'failed')),
+    refund_error TEXT,
+    notification_error TEXT,
+    claimed_at TIMESTAMP WITH TIME ZONE,
+    processed_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (cancellation_id, order_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_cancellation_orders_unprocessed ON event_cancellation_orders(id) WHERE status IN ('pending', 'processing');
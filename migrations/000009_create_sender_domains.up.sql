@@ -0,0 +1,17 @@
+-- Sender domains: organizers send attendee-facing mails from their own domain once its DNS records are verified
+CREATE TABLE IF NOT EXISTS sender_domains (
+    id BIGSERIAL PRIMARY KEY,
+    organizer_id BIGINT NOT NULL UNIQUE REFERENCES users(id),
+    domain VARCHAR(253) NOT NULL,
+    from_email VARCHAR(255) NOT NULL,
+    from_name VARCHAR(100) NOT NULL,
+    verification_token VARCHAR(64) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'verified', 'failed')),
+    verified_at TIMESTAMP WITH TIME ZONE,
+    checked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- a domain is verified for one organizer at most
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sender_domains_verified_domain ON sender_domains(domain) WHERE status = 'verified';
@@ -0,0 +1,13 @@
+-- Account activity shown to users in their activity feed
+CREATE TABLE IF NOT EXISTS user_activities (
+    id BIGSERIAL PRIMARY KEY,
+    event_id VARCHAR(32) NOT NULL UNIQUE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(30) NOT NULL,
+    metadata JSONB NOT NULL DEFAULT '{}',
+    ip_address VARCHAR(45),
+    user_agent VARCHAR(500),
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_activities_user_occurred ON user_activities(user_id, occurred_at DESC, id DESC);
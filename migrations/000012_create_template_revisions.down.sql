@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS template_revisions;
+ALTER TABLE templates DROP COLUMN IF EXISTS approved;
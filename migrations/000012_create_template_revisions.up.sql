@@ -0,0 +1,22 @@
+-- Template review: edits by non-admins are submitted as revisions that an admin approves or rejects
+ALTER TABLE templates ADD COLUMN IF NOT EXISTS approved BOOLEAN NOT NULL DEFAULT TRUE;
+
+CREATE TABLE IF NOT EXISTS template_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    template_id BIGINT NOT NULL REFERENCES templates(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    subject VARCHAR(500),
+    content TEXT NOT NULL,
+    variables TEXT[],
+    description TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending_review' CHECK (status IN ('pending_review', 'approved', 'rejected', 'superseded')),
+    submitted_by BIGINT NOT NULL,
+    reviewed_by BIGINT,
+    review_comment TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    reviewed_at TIMESTAMP WITH TIME ZONE
+);
+
+-- A template has one revision pending review at most
+CREATE UNIQUE INDEX IF NOT EXISTS idx_template_revisions_pending ON template_revisions(template_id) WHERE status = 'pending_review';
+CREATE INDEX IF NOT EXISTS idx_template_revisions_status_created ON template_revisions(status, created_at DESC);
@@ -0,0 +1,5 @@
+UPDATE users u SET last_login = l.last_login
+FROM (SELECT user_id, MAX(created_at) AS last_login FROM login_events GROUP BY user_id) l
+WHERE u.id = l.user_id;
+
+DROP TABLE IF EXISTS login_events;
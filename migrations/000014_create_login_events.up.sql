@@ -0,0 +1,15 @@
+-- Successful logins, recorded instead of updating users.last_login on every login.
+-- users.last_login is no longer written, it stays for the instances still running the previous release.
+CREATE TABLE IF NOT EXISTS login_events (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    ip_address VARCHAR(45),
+    user_agent VARCHAR(500),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_events_user_created ON login_events(user_id, created_at DESC);
+
+-- keep the last login known so far
+INSERT INTO login_events (user_id, created_at)
+SELECT id, last_login FROM users WHERE last_login IS NOT NULL;
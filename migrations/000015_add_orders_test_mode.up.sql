@@ -0,0 +1,5 @@
+-- Orders placed in test mode take no real payment: their refunds are settled without the payment
+-- provider and their mails are watermarked
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS test_mode BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN orders.test_mode IS 'Whether the order was placed in test mode, with fake payments';
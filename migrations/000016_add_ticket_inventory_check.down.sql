@@ -0,0 +1,3 @@
+ALTER TABLE ticket_categories DROP CONSTRAINT IF EXISTS ticket_categories_inventory_check;
+ALTER TABLE ticket_categories DROP CONSTRAINT IF EXISTS ticket_categories_quantity_sold_not_null;
+ALTER TABLE ticket_categories DROP COLUMN IF EXISTS quantity_reserved;
@@ -0,0 +1,13 @@
+-- Tickets held during checkout are counted next to the sold ones, and together they can never exceed
+-- the capacity of the category: inventory updates are conditional and this constraint backs them up
+ALTER TABLE ticket_categories ADD COLUMN IF NOT EXISTS quantity_reserved INT NOT NULL DEFAULT 0;
+
+-- added without a scan under the table lock, they hold for the rows written from now on; the rows
+-- without quantity_sold are backfilled and the checks validated by later migrations, which do not
+-- block writes
+ALTER TABLE ticket_categories ADD CONSTRAINT ticket_categories_quantity_sold_not_null
+    CHECK (quantity_sold IS NOT NULL) NOT VALID;
+ALTER TABLE ticket_categories ADD CONSTRAINT ticket_categories_inventory_check
+    CHECK (quantity_sold >= 0 AND quantity_reserved >= 0 AND quantity_sold + quantity_reserved <= quantity_available) NOT VALID;
+
+COMMENT ON COLUMN ticket_categories.quantity_reserved IS 'Tickets held by buyers checking out, not sold yet';
@@ -0,0 +1,20 @@
+-- Order history read model: one denormalized row per order, projected from orders, their items, events
+-- and users by the order module, so the history of a customer is listed without joins
+CREATE TABLE IF NOT EXISTS order_summaries (
+    order_id BIGINT PRIMARY KEY REFERENCES orders(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL,
+    order_number VARCHAR(50) NOT NULL,
+    customer_name VARCHAR(201) NOT NULL,
+    customer_email VARCHAR(255) NOT NULL,
+    event_id BIGINT,
+    event_title VARCHAR(255),
+    event_start_date TIMESTAMP,
+    ticket_count INT NOT NULL DEFAULT 0,
+    total_amount DECIMAL(10, 2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(30) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    projected_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_summaries_user_created ON order_summaries(user_id, created_at DESC, order_id DESC);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS widget_origins;
@@ -0,0 +1,9 @@
+-- Checkout widget origins: the sites an organizer embeds the checkout widget on, the only ones allowed
+-- to get widget tokens for their events
+CREATE TABLE IF NOT EXISTS widget_origins (
+    id BIGSERIAL PRIMARY KEY,
+    organizer_id BIGINT NOT NULL REFERENCES users(id),
+    origin VARCHAR(300) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (organizer_id, origin)
+);
@@ -0,0 +1,11 @@
+-- Short links: compact URLs for SMS and shared links, redirecting to their target while counting clicks
+CREATE TABLE IF NOT EXISTS short_links (
+    code VARCHAR(16) PRIMARY KEY,
+    target_url TEXT NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    clicks BIGINT NOT NULL DEFAULT 0,
+    last_clicked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_short_links_expires_at ON short_links(expires_at) WHERE expires_at IS NOT NULL;
@@ -0,0 +1,4 @@
+-- Users confirm their phone number with a code sent by SMS before high-risk actions
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN users.phone_verified IS 'Whether the user confirmed the phone number with a code sent to it';
@@ -0,0 +1,36 @@
+-- Organizer KYC: the business details and documents organizers submit, reviewed by an admin before
+-- they can receive payouts
+CREATE TABLE IF NOT EXISTS kyc_submissions (
+    id BIGSERIAL PRIMARY KEY,
+    organizer_id BIGINT NOT NULL REFERENCES users(id),
+    legal_name VARCHAR(255) NOT NULL,
+    registration_number VARCHAR(100) NOT NULL,
+    tax_id VARCHAR(100),
+    address TEXT NOT NULL,
+    country CHAR(2) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    reviewed_by BIGINT,
+    rejection_reason TEXT,
+    submitted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    reviewed_at TIMESTAMP WITH TIME ZONE
+);
+
+-- An organizer has one submission pending or approved at most, a rejected one is followed by a new one
+CREATE UNIQUE INDEX IF NOT EXISTS idx_kyc_submissions_open ON kyc_submissions(organizer_id) WHERE status IN ('pending', 'approved');
+CREATE INDEX IF NOT EXISTS idx_kyc_submissions_organizer_submitted ON kyc_submissions(organizer_id, submitted_at DESC);
+CREATE INDEX IF NOT EXISTS idx_kyc_submissions_status_submitted ON kyc_submissions(status, submitted_at);
+
+-- Uploaded documents, kept in the file storage; submission_id is set once they are submitted
+CREATE TABLE IF NOT EXISTS kyc_documents (
+    id BIGSERIAL PRIMARY KEY,
+    organizer_id BIGINT NOT NULL REFERENCES users(id),
+    submission_id BIGINT REFERENCES kyc_submissions(id) ON DELETE CASCADE,
+    type VARCHAR(30) NOT NULL CHECK (type IN ('identity', 'business_registration', 'proof_of_address')),
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key VARCHAR(500) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_kyc_documents_submission ON kyc_documents(submission_id);
@@ -0,0 +1,11 @@
+-- Event templates: the structure of an event (details, ticket categories, seat map) an organizer saved
+-- to create the editions of a recurring event from
+CREATE TABLE IF NOT EXISTS event_templates (
+    id BIGSERIAL PRIMARY KEY,
+    organizer_id BIGINT NOT NULL REFERENCES users(id),
+    name VARCHAR(255) NOT NULL,
+    source_event_id BIGINT REFERENCES events(id) ON DELETE SET NULL,
+    blueprint JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (organizer_id, name)
+);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS complimentary_orders;
+DROP TABLE IF EXISTS ticket_allotments;
+
+ALTER TABLE ticket_categories DROP CONSTRAINT IF EXISTS ticket_categories_inventory_check;
+ALTER TABLE ticket_categories ADD CONSTRAINT ticket_categories_inventory_check
+    CHECK (quantity_sold >= 0 AND quantity_reserved >= 0 AND quantity_sold + quantity_reserved <= quantity_available) NOT VALID;
+ALTER TABLE ticket_categories DROP COLUMN IF EXISTS quantity_allotted;
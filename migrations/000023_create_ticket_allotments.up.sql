@@ -0,0 +1,36 @@
+-- Allotments carve tickets of a category out of public sale for press, sponsors or a guest list. The
+-- tickets of an allotment not issued yet are counted next to the sold and reserved ones.
+ALTER TABLE ticket_categories ADD COLUMN IF NOT EXISTS quantity_allotted INT NOT NULL DEFAULT 0;
+
+-- added NOT VALID without a scan under the table lock, 000063 validates it in a transaction of its own
+ALTER TABLE ticket_categories DROP CONSTRAINT IF EXISTS ticket_categories_inventory_check;
+ALTER TABLE ticket_categories ADD CONSTRAINT ticket_categories_inventory_check
+    CHECK (quantity_sold >= 0 AND quantity_reserved >= 0 AND quantity_allotted >= 0
+           AND quantity_sold + quantity_reserved + quantity_allotted <= quantity_available) NOT VALID;
+
+COMMENT ON COLUMN ticket_categories.quantity_allotted IS 'Tickets of allotments not issued yet, off public sale';
+
+CREATE TABLE IF NOT EXISTS ticket_allotments (
+    id BIGSERIAL PRIMARY KEY,
+    ticket_category_id BIGINT NOT NULL REFERENCES ticket_categories(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    quantity INT NOT NULL,
+    quantity_issued INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (ticket_category_id, name),
+    CHECK (kind IN ('press', 'sponsor', 'guest_list')),
+    CHECK (quantity_issued >= 0 AND quantity_issued <= quantity)
+);
+
+-- Complimentary orders are the zero-amount orders of tickets an organizer issued by email
+CREATE TABLE IF NOT EXISTS complimentary_orders (
+    order_id BIGINT PRIMARY KEY REFERENCES orders(id) ON DELETE CASCADE,
+    allotment_id BIGINT REFERENCES ticket_allotments(id) ON DELETE SET NULL,
+    issued_by BIGINT NOT NULL REFERENCES users(id),
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_complimentary_orders_allotment ON complimentary_orders(allotment_id);
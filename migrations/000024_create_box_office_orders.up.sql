@@ -0,0 +1,13 @@
+-- Box office orders are the orders an organizer sold on the spot to walk-up customers, paid outside the
+-- payment provider
+CREATE TABLE IF NOT EXISTS box_office_orders (
+    order_id BIGINT PRIMARY KEY REFERENCES orders(id) ON DELETE CASCADE,
+    payment_method VARCHAR(20) NOT NULL,
+    card_reference VARCHAR(100),
+    customer_name VARCHAR(255),
+    sold_by BIGINT NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (payment_method IN ('cash', 'card_present'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_box_office_orders_sold_by ON box_office_orders(sold_by);
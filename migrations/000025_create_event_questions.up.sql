@@ -0,0 +1,29 @@
+-- Event questions are the attendee questionnaire of an event, answered for every ticket at checkout
+CREATE TABLE IF NOT EXISTS event_questions (
+    id BIGSERIAL PRIMARY KEY,
+    event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    label VARCHAR(255) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    options TEXT[] NOT NULL DEFAULT '{}',
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    position INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (event_id, label),
+    CHECK (kind IN ('text', 'select', 'checkbox'))
+);
+
+-- Answers are kept per order and ticket, so a ticket sold again starts without the answers of its
+-- previous holder
+CREATE TABLE IF NOT EXISTS attendee_answers (
+    order_id BIGINT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    ticket_id BIGINT NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+    question_id BIGINT NOT NULL REFERENCES event_questions(id) ON DELETE CASCADE,
+    value TEXT NOT NULL,
+    answered_by BIGINT NOT NULL REFERENCES users(id),
+    answered_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (order_id, ticket_id, question_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_attendee_answers_question ON attendee_answers(question_id);
+CREATE INDEX IF NOT EXISTS idx_attendee_answers_ticket ON attendee_answers(ticket_id);
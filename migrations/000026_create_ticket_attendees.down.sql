@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS ticket_attendees;
+
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_attendee_mode_check;
+ALTER TABLE events DROP COLUMN IF EXISTS attendee_edit_cutoff_minutes;
+ALTER TABLE events DROP COLUMN IF EXISTS attendee_mode;
@@ -0,0 +1,24 @@
+ALTER TABLE events ADD COLUMN IF NOT EXISTS attendee_mode VARCHAR(20) NOT NULL DEFAULT 'optional';
+ALTER TABLE events ADD COLUMN IF NOT EXISTS attendee_edit_cutoff_minutes INT NOT NULL DEFAULT 0;
+
+-- added NOT VALID without a scan under the table lock, 000064 validates it in a transaction of its own
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_attendee_mode_check;
+ALTER TABLE events ADD CONSTRAINT events_attendee_mode_check
+    CHECK (attendee_mode IN ('optional', 'required') AND attendee_edit_cutoff_minutes >= 0) NOT VALID;
+
+COMMENT ON COLUMN events.attendee_edit_cutoff_minutes IS 'How long before the start attendees of tickets stop being editable';
+
+-- Ticket attendees are who the tickets of an order are assigned to, kept per order and ticket like
+-- attendee answers so a ticket sold again starts unassigned
+CREATE TABLE IF NOT EXISTS ticket_attendees (
+    order_id BIGINT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    ticket_id BIGINT NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+    name VARCHAR(200) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    assigned_by BIGINT NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (order_id, ticket_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ticket_attendees_ticket ON ticket_attendees(ticket_id);
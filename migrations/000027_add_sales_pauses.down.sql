@@ -0,0 +1,5 @@
+ALTER TABLE ticket_categories DROP COLUMN IF EXISTS sales_pause_at;
+ALTER TABLE ticket_categories DROP COLUMN IF EXISTS sales_paused;
+
+ALTER TABLE events DROP COLUMN IF EXISTS sales_pause_at;
+ALTER TABLE events DROP COLUMN IF EXISTS sales_paused;
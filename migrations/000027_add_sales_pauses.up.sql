@@ -0,0 +1,9 @@
+-- Organizers pause the online sales of an event or of a ticket category, at once or from a scheduled time
+ALTER TABLE events ADD COLUMN IF NOT EXISTS sales_paused BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS sales_pause_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE ticket_categories ADD COLUMN IF NOT EXISTS sales_paused BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE ticket_categories ADD COLUMN IF NOT EXISTS sales_pause_at TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN events.sales_pause_at IS 'When the sales pause on their own, the job applying it sets sales_paused';
+COMMENT ON COLUMN ticket_categories.sales_pause_at IS 'When the sales pause on their own, the job applying it sets sales_paused';
@@ -0,0 +1 @@
+DROP INDEX CONCURRENTLY IF EXISTS idx_events_sales_pause_at;
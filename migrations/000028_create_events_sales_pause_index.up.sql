@@ -0,0 +1,2 @@
+-- Built concurrently in a migration of its own: events is written to during on-sales
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_events_sales_pause_at ON events(sales_pause_at) WHERE sales_pause_at IS NOT NULL;
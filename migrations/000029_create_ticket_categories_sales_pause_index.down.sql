@@ -0,0 +1 @@
+DROP INDEX CONCURRENTLY IF EXISTS idx_ticket_categories_sales_pause_at;
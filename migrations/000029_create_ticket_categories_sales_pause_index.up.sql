@@ -0,0 +1,2 @@
+-- Built concurrently in a migration of its own: ticket_categories is written to by every reservation
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_ticket_categories_sales_pause_at ON ticket_categories(sales_pause_at) WHERE sales_pause_at IS NOT NULL;
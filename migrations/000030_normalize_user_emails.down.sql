@@ -0,0 +1,2 @@
+-- The original spelling of the emails is not kept, normalized emails stay as they are
+SELECT 1;
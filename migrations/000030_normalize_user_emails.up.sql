@@ -0,0 +1,6 @@
+-- Emails are stored trimmed and lowercased, which lookups now rely on. An account whose normalized
+-- email is already taken by another one keeps its email to be merged by hand.
+UPDATE users u
+SET email = LOWER(TRIM(u.email))
+WHERE u.email <> LOWER(TRIM(u.email))
+  AND NOT EXISTS (SELECT 1 FROM users o WHERE o.id <> u.id AND LOWER(TRIM(o.email)) = LOWER(TRIM(u.email)));
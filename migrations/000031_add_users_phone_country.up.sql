@@ -0,0 +1,3 @@
+-- Phone numbers are stored in E.164 format with the ISO 3166-1 alpha-2 region they belong to. Numbers
+-- confirmed before have no country until they are confirmed again.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone_country VARCHAR(2);
@@ -0,0 +1,28 @@
+-- Organizer API keys: the keys organizers call the API with instead of a session, each on a plan
+-- bounding its requests per calendar month (UTC). Only the SHA-256 of a key is stored.
+CREATE TABLE IF NOT EXISTS api_keys (
+    id BIGSERIAL PRIMARY KEY,
+    organizer_id BIGINT NOT NULL REFERENCES users(id),
+    name VARCHAR(100) NOT NULL,
+    key_prefix VARCHAR(20) NOT NULL,
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    plan VARCHAR(20) NOT NULL DEFAULT 'free' CHECK (plan IN ('free', 'standard', 'business')),
+    -- monthly_quota overrides the quota of the plan when set by an admin
+    monthly_quota BIGINT CHECK (monthly_quota > 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_organizer ON api_keys(organizer_id);
+
+-- Requests made with a key per month, counted in redis and saved here by the organizer.flush_api_usage job
+CREATE TABLE IF NOT EXISTS api_key_usage (
+    api_key_id BIGINT NOT NULL REFERENCES api_keys(id),
+    period DATE NOT NULL,
+    requests BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (api_key_id, period)
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_key_usage_period ON api_key_usage(period);
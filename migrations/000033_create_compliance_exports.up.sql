@@ -0,0 +1,29 @@
+-- Exports of the audit logs and notification history to object storage, kept for compliance.
+-- The compliance.schedule_exports job adds an export of every dataset for each past day, admins add
+-- the others, and the compliance.run_exports job uploads the pending ones as gzipped CSV.
+CREATE TABLE IF NOT EXISTS compliance_exports (
+    id BIGSERIAL PRIMARY KEY,
+    dataset VARCHAR(50) NOT NULL CHECK (dataset IN ('user_activities', 'login_events', 'notifications')),
+    -- the rows recorded from period_start until period_end, excluded
+    period_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    period_end TIMESTAMP WITH TIME ZONE NOT NULL CHECK (period_end > period_start),
+    trigger VARCHAR(50) NOT NULL CHECK (trigger IN ('schedule', 'manual')),
+    requested_by BIGINT REFERENCES users(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'succeeded', 'failed')),
+    object_key VARCHAR(500),
+    rows_exported BIGINT NOT NULL DEFAULT 0,
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    sha256 CHAR(64),
+    retain_until TIMESTAMP WITH TIME ZONE,
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    -- purged_at is when the exported rows were deleted from their table
+    purged_at TIMESTAMP WITH TIME ZONE
+);
+
+-- a day is exported once by the schedule, admins may export any period again
+CREATE UNIQUE INDEX IF NOT EXISTS idx_compliance_exports_scheduled ON compliance_exports(dataset, period_start) WHERE trigger = 'schedule';
+CREATE INDEX IF NOT EXISTS idx_compliance_exports_pending ON compliance_exports(id) WHERE status IN ('pending', 'running');
+CREATE INDEX IF NOT EXISTS idx_compliance_exports_unpurged ON compliance_exports(period_end) WHERE status = 'succeeded' AND purged_at IS NULL;
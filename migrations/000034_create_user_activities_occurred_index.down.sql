@@ -0,0 +1 @@
+DROP INDEX CONCURRENTLY IF EXISTS idx_user_activities_occurred_at;
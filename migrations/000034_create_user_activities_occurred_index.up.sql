@@ -0,0 +1,3 @@
+-- Built concurrently in a migration of its own: user_activities is written to on every account change.
+-- Compliance exports and purges read the activities of a period.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_user_activities_occurred_at ON user_activities(occurred_at);
@@ -0,0 +1 @@
+DROP INDEX CONCURRENTLY IF EXISTS idx_login_events_created_at;
@@ -0,0 +1,3 @@
+-- Built concurrently in a migration of its own: login_events is written to on every login.
+-- Compliance exports and purges read the logins of a period.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_login_events_created_at ON login_events(created_at);
@@ -0,0 +1 @@
+DROP INDEX CONCURRENTLY IF EXISTS idx_notification_digest_items_created_at;
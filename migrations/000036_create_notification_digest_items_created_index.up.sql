@@ -0,0 +1,3 @@
+-- Built concurrently in a migration of its own: notification_digest_items is written to by every
+-- low priority notification. Compliance exports and purges read the notifications of a period.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_notification_digest_items_created_at ON notification_digest_items(created_at);
@@ -0,0 +1,4 @@
+-- The most attendees an event admits, set by its organizer
+ALTER TABLE events ADD COLUMN IF NOT EXISTS capacity INT;
+
+COMMENT ON COLUMN events.capacity IS 'Most attendees the event admits, NULL for events created before capacities';
@@ -0,0 +1,12 @@
+-- Order reservations are the tickets a checkout holds while its order is pending, by category, counted
+-- in quantity_reserved until the order is confirmed or expires
+CREATE TABLE IF NOT EXISTS order_reservations (
+    order_id BIGINT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    ticket_category_id BIGINT NOT NULL REFERENCES ticket_categories(id) ON DELETE CASCADE,
+    quantity INT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (order_id, ticket_category_id),
+    CHECK (quantity > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_reservations_ticket_category_id ON order_reservations(ticket_category_id);
@@ -0,0 +1,2 @@
+-- Built concurrently in a migration of its own: orders is written to by every checkout
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_orders_pending_expires_at ON orders(expires_at) WHERE status = 'pending';
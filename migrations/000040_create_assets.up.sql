@@ -0,0 +1,12 @@
+-- Assets: the public images templates reference by name, e.g. logos and banners. The files are stored
+-- under the hash of their content; a name points to its current file, older files stay for the mails
+-- already sent
+CREATE TABLE IF NOT EXISTS assets (
+    name VARCHAR(100) PRIMARY KEY,
+    hash CHAR(64) NOT NULL,
+    content_type VARCHAR(50) NOT NULL,
+    size BIGINT NOT NULL CHECK (size > 0),
+    uploaded_by BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,13 @@
+-- Refund tickets are the tickets a refund of an order gave back. A ticket is refunded once per order; a
+-- refunded seat may be sold again, in another order. The refunds of cancelled events refund whole
+-- payments and have none.
+CREATE TABLE IF NOT EXISTS refund_tickets (
+    order_id BIGINT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    ticket_id BIGINT NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+    refund_id BIGINT NOT NULL REFERENCES refunds(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (order_id, ticket_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_refund_tickets_refund_id ON refund_tickets(refund_id);
+CREATE INDEX IF NOT EXISTS idx_refund_tickets_ticket_id ON refund_tickets(ticket_id);
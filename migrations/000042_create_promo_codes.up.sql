@@ -0,0 +1,36 @@
+-- Promo codes discount the orders placed with them, for every event or the one of event_id. Codes are
+-- stored upper case. redemption_count counts the orders holding the code; it is only incremented by a
+-- conditional update checking max_redemptions, so concurrent checkouts never redeem a code past it.
+CREATE TABLE IF NOT EXISTS promo_codes (
+    id BIGSERIAL PRIMARY KEY,
+    code VARCHAR(50) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    discount_type VARCHAR(20) NOT NULL CHECK (discount_type IN ('percentage', 'fixed_amount')),
+    discount_value DECIMAL(10, 2) NOT NULL CHECK (discount_value > 0),
+    event_id BIGINT REFERENCES events(id) ON DELETE CASCADE,
+    max_redemptions INT CHECK (max_redemptions > 0),
+    max_per_user INT CHECK (max_per_user > 0),
+    redemption_count INT NOT NULL DEFAULT 0,
+    starts_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT promo_codes_code_key UNIQUE (code),
+    CONSTRAINT promo_codes_redemptions_check CHECK (redemption_count >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_promo_codes_event_id ON promo_codes(event_id);
+
+-- Promo redemptions are the orders placed with a promo code and the discount they got, one code per
+-- order. Redeemed codes cannot be deleted.
+CREATE TABLE IF NOT EXISTS promo_redemptions (
+    order_id BIGINT PRIMARY KEY REFERENCES orders(id) ON DELETE CASCADE,
+    promo_code_id BIGINT NOT NULL REFERENCES promo_codes(id),
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    discount_amount DECIMAL(10, 2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_promo_redemptions_promo_code_id_user_id ON promo_redemptions(promo_code_id, user_id);
@@ -0,0 +1,2 @@
+-- enum values cannot be dropped, awaiting_transfer stays unused
+DROP TABLE IF EXISTS order_invoices;
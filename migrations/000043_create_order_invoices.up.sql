@@ -0,0 +1,19 @@
+-- Orders paid by bank transfer wait in awaiting_transfer until the transfer is received. The new value
+-- is not used in this migration, which adding it in a transaction requires.
+ALTER TYPE order_status_enum ADD VALUE IF NOT EXISTS 'awaiting_transfer';
+
+-- Order invoices are the pro-forma invoices of the orders paid by bank transfer, made out to the
+-- company buying. The payment reference is what the transfer must carry to be matched with its order.
+CREATE TABLE IF NOT EXISTS order_invoices (
+    order_id BIGINT PRIMARY KEY REFERENCES orders(id) ON DELETE CASCADE,
+    invoice_number VARCHAR(30) NOT NULL,
+    payment_reference VARCHAR(30) NOT NULL,
+    company_name VARCHAR(255) NOT NULL,
+    tax_id VARCHAR(50) NOT NULL DEFAULT '',
+    billing_address VARCHAR(500) NOT NULL,
+    billing_email VARCHAR(255) NOT NULL,
+    due_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    issued_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT order_invoices_invoice_number_key UNIQUE (invoice_number),
+    CONSTRAINT order_invoices_payment_reference_key UNIQUE (payment_reference)
+);
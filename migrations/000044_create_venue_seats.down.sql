@@ -0,0 +1,3 @@
+ALTER TABLE tickets DROP COLUMN IF EXISTS venue_seat_id;
+DROP TABLE IF EXISTS venue_seats;
+ALTER TABLE venues DROP COLUMN IF EXISTS organizer_id;
@@ -0,0 +1,22 @@
+-- Venues are defined by organizers for their own events; the venues without an organizer are shared by
+-- every organizer.
+ALTER TABLE venues ADD COLUMN IF NOT EXISTS organizer_id BIGINT REFERENCES users(id) ON DELETE CASCADE;
+
+-- Venue seats are the seat map of a venue, its seats by section, row and number. The seats of a
+-- reserved-seating ticket type are bound to the venue seats they stand for.
+CREATE TABLE IF NOT EXISTS venue_seats (
+    id BIGSERIAL PRIMARY KEY,
+    venue_id BIGINT NOT NULL REFERENCES venues(id) ON DELETE CASCADE,
+    section VARCHAR(50) NOT NULL,
+    row_label VARCHAR(10) NOT NULL,
+    seat_number VARCHAR(10) NOT NULL,
+    position INT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT venue_seats_seat_key UNIQUE (venue_id, section, row_label, seat_number)
+);
+
+ALTER TABLE tickets ADD COLUMN IF NOT EXISTS venue_seat_id BIGINT REFERENCES venue_seats(id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN venues.organizer_id IS 'The organizer owning the venue, NULL for a venue shared by every organizer';
+COMMENT ON COLUMN venue_seats.position IS 'The order of the seat in the seat map';
+COMMENT ON COLUMN tickets.venue_seat_id IS 'The venue seat a reserved-seating ticket stands for';
@@ -0,0 +1,2 @@
+-- Built concurrently in a migration of its own: events reference venues while they are written to
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_venues_organizer_id ON venues(organizer_id);
@@ -0,0 +1 @@
+DROP INDEX CONCURRENTLY IF EXISTS idx_tickets_venue_seat_id;
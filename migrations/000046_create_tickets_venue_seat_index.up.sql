@@ -0,0 +1,2 @@
+-- Built concurrently in a migration of its own: tickets is written to by every checkout
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_tickets_venue_seat_id ON tickets(venue_seat_id) WHERE venue_seat_id IS NOT NULL;
@@ -0,0 +1,10 @@
+-- Order accommodations are the accessibility and dietary needs a buyer asks the organizer of the event
+-- to accommodate for the attendees of an order. They may reveal health conditions, so they are kept
+-- apart from the orders and only read for the buyer and the attendee manifest of the organizer.
+CREATE TABLE IF NOT EXISTS order_accommodations (
+    order_id BIGINT PRIMARY KEY REFERENCES orders(id) ON DELETE CASCADE,
+    access_needs TEXT[] NOT NULL DEFAULT '{}',
+    dietary_needs TEXT[] NOT NULL DEFAULT '{}',
+    notes VARCHAR(500) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,11 @@
+-- Organizer themes brand the tickets and mails organizers send their attendees; an empty field keeps
+-- the platform default
+CREATE TABLE IF NOT EXISTS organizer_themes (
+    organizer_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    primary_color VARCHAR(7) NOT NULL DEFAULT '',
+    accent_color VARCHAR(7) NOT NULL DEFAULT '',
+    logo_url VARCHAR(500) NOT NULL DEFAULT '',
+    footer_text VARCHAR(300) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS event_slug_redirects;
+
+ALTER TABLE events DROP COLUMN IF EXISTS meta_description;
+ALTER TABLE events DROP COLUMN IF EXISTS meta_title;
@@ -0,0 +1,14 @@
+-- Organizers write the meta tags search engines show for the public page of their events; the title
+-- and description of the event are shown when empty
+ALTER TABLE events ADD COLUMN IF NOT EXISTS meta_title VARCHAR(70);
+ALTER TABLE events ADD COLUMN IF NOT EXISTS meta_description VARCHAR(160);
+
+-- The former slugs of public event pages redirect permanently to their current slug. A slug is never
+-- given to another event, so links shared and pages indexed keep leading to their event.
+CREATE TABLE IF NOT EXISTS event_slug_redirects (
+    slug VARCHAR(255) PRIMARY KEY,
+    event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_slug_redirects_event_id ON event_slug_redirects(event_id);
@@ -0,0 +1,13 @@
+-- Tickets checked in at the door of their event by scanning their QR code. A check-in belongs to the
+-- sale of the ticket, like its attendee; a ticket is checked in once and is then used, so it can no
+-- longer be refunded.
+CREATE TABLE IF NOT EXISTS ticket_check_ins (
+    order_id BIGINT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    ticket_id BIGINT NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+    event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    checked_in_by BIGINT NOT NULL REFERENCES users(id),
+    checked_in_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (order_id, ticket_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ticket_check_ins_event ON ticket_check_ins(event_id, checked_in_at);
@@ -0,0 +1,24 @@
+-- The spam assessments of registration attempts, whatever their decision: the signals scored are
+-- kept to train models on later. The attempts queued for review wait for an admin here; the email
+-- links an assessment to the account once it is verified.
+CREATE TABLE IF NOT EXISTS registration_risks (
+    id BIGSERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    ip_address VARCHAR(45) NOT NULL,
+    device_id VARCHAR(255),
+    user_agent TEXT,
+    signals JSONB NOT NULL DEFAULT '{}',
+    score INT NOT NULL CHECK (score BETWEEN 0 AND 100),
+    decision VARCHAR(20) NOT NULL CHECK (decision IN ('allow', 'challenge', 'review', 'block')),
+    reasons TEXT[] NOT NULL DEFAULT '{}',
+    review_status VARCHAR(20) CHECK (review_status IN ('pending', 'approved', 'rejected')),
+    reviewed_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    review_reason TEXT,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK ((decision = 'review') = (review_status IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_registration_risks_ip ON registration_risks(ip_address, created_at);
+CREATE INDEX IF NOT EXISTS idx_registration_risks_email ON registration_risks(email);
+CREATE INDEX IF NOT EXISTS idx_registration_risks_pending ON registration_risks(created_at) WHERE review_status = 'pending';
@@ -0,0 +1,26 @@
+-- Problems users report from the app, with the context of their account and order. A ticket is stored
+-- here first and created in the help desk by the support.push_tickets job, which sets its external_id;
+-- the support.sync_tickets job then copies the status agents give it back, for the user to follow.
+CREATE TABLE IF NOT EXISTS support_tickets (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    order_id BIGINT REFERENCES orders(id) ON DELETE SET NULL,
+    category VARCHAR(20) NOT NULL CHECK (category IN ('order', 'payment', 'account', 'event', 'other')),
+    subject VARCHAR(200) NOT NULL,
+    description TEXT NOT NULL,
+    context JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'submitted' CHECK (status IN ('submitted', 'open', 'pending', 'solved', 'closed')),
+    provider VARCHAR(20),
+    external_id VARCHAR(64),
+    push_attempts INT NOT NULL DEFAULT 0,
+    push_error TEXT,
+    synced_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK ((external_id IS NULL) = (provider IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_support_tickets_user ON support_tickets(user_id, created_at DESC, id DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_support_tickets_external ON support_tickets(provider, external_id);
+CREATE INDEX IF NOT EXISTS idx_support_tickets_unpushed ON support_tickets(id) WHERE external_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_support_tickets_unsolved ON support_tickets(synced_at) WHERE external_id IS NOT NULL AND status <> 'closed';
@@ -0,0 +1,3 @@
+-- Built concurrently in a migration of its own: events is written to during on-sales. The expression
+-- must stay the one the public event search matches against.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_events_search ON events USING GIN (to_tsvector('simple', title || ' ' || COALESCE(description, '')));
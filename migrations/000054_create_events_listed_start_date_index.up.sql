@@ -0,0 +1,3 @@
+-- Built concurrently in a migration of its own: events is written to during on-sales. The public event
+-- search lists the published and postponed events by start date.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_events_listed_start_date ON events(start_date) WHERE status IN ('published', 'postponed');
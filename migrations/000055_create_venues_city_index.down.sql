@@ -0,0 +1 @@
+DROP INDEX CONCURRENTLY IF EXISTS idx_venues_lower_city;
@@ -0,0 +1,3 @@
+-- Built concurrently in a migration of its own: events reference venues while they are written to. The
+-- public event search matches cities case insensitively.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_venues_lower_city ON venues(LOWER(city));
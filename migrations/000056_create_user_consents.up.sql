@@ -0,0 +1,18 @@
+-- The history of the terms of service and privacy policy versions users accepted, and of their
+-- marketing consent. Rows are never updated: the latest of each kind is the consent in force, the
+-- others are kept for compliance audits.
+CREATE TABLE IF NOT EXISTS user_consents (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('terms', 'privacy', 'marketing')),
+    -- the version of the document accepted, empty for marketing
+    version VARCHAR(50) NOT NULL DEFAULT '',
+    granted BOOLEAN NOT NULL,
+    source VARCHAR(20) NOT NULL CHECK (source IN ('registration', 'login', 'settings')),
+    ip_address VARCHAR(45),
+    user_agent VARCHAR(500),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_consents_user_kind_created ON user_consents(user_id, kind, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_user_consents_user_created ON user_consents(user_id, created_at DESC);
@@ -0,0 +1 @@
+DELETE FROM templates WHERE slug = 'order-confirmation' AND created_by = 0;
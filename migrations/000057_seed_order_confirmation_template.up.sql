@@ -0,0 +1,30 @@
+-- The mail confirming an order to its buyer, with the QR code of each ticket. Admins edit it like any
+-- template; an order-confirmation template created beforehand is kept as it is.
+INSERT INTO templates (name, slug, subject, content, type, status, variables, description, approved, created_by)
+VALUES (
+    'Order confirmation',
+    'order-confirmation',
+    'Your tickets for {{.event_title}} - order {{.order_number}}',
+    '<html>
+<body>
+<h1>Thank you for your order</h1>
+<p>Your order <strong>{{.order_number}}</strong> for <strong>{{.event_title}}</strong> is confirmed.</p>
+<table>
+<tr><th>Tickets</th><th>Quantity</th><th>Price</th><th>Subtotal</th></tr>
+{{range .lines}}<tr><td>{{.name}}</td><td>{{.quantity}}</td><td>{{formatMoney .unit_price $.currency}}</td><td>{{formatMoney .subtotal $.currency}}</td></tr>
+{{end}}</table>
+{{if .promo_code}}<p>Discount ({{.promo_code}}): -{{formatMoney .discount_amount .currency}}</p>
+{{end}}<p>Total paid: <strong>{{formatMoney .final_amount .currency}}</strong></p>
+<h2>Your tickets</h2>
+<p>Show the QR code of each ticket at the entrance.</p>
+{{range .tickets}}<p>{{.category}} - ticket #{{.id}}<br><img src="{{.qr_url}}" alt="QR code of ticket #{{.id}}" width="240" height="240"></p>
+{{end}}</body>
+</html>',
+    'email',
+    'active',
+    ARRAY['event_title', 'order_number', 'lines', 'tickets', 'ticket_count', 'total_amount', 'promo_code', 'discount_amount', 'final_amount', 'currency', 'confirmed_at'],
+    'Mailed to the buyer of an order once it is confirmed, with the QR codes of its tickets',
+    TRUE,
+    0
+)
+ON CONFLICT (slug) DO NOTHING;
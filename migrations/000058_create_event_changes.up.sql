@@ -0,0 +1,18 @@
+-- The change feed of events: who changed the configuration of an event, what and when. Rows are never
+-- updated, they are the audit trail of the collaborators of an organizer.
+CREATE TABLE IF NOT EXISTS event_changes (
+    id BIGSERIAL PRIMARY KEY,
+    change_id VARCHAR(32) NOT NULL UNIQUE,
+    event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    -- the ticket category changed, null when the whole event changed
+    ticket_category_id BIGINT,
+    action VARCHAR(30) NOT NULL CHECK (action IN ('details_updated', 'published', 'cancelled', 'seo_updated',
+        'capacity_changed', 'sales_paused', 'sales_resumed', 'pause_scheduled')),
+    -- the fields changed with their values before and after
+    fields JSONB NOT NULL DEFAULT '[]',
+    actor_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    api_key_id BIGINT,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_changes_event_occurred ON event_changes(event_id, occurred_at DESC, id DESC);
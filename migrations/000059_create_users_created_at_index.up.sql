@@ -0,0 +1,3 @@
+-- Built concurrently in a migration of its own: users is written to at every registration. The admin
+-- dashboard counts the signups of the day.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_users_created_at ON users(created_at);
@@ -0,0 +1,3 @@
+-- Built concurrently in a migration of its own: orders is written to during on-sales. The admin
+-- dashboard sums the orders confirmed during the day.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_orders_confirmed_at ON orders(confirmed_at) WHERE confirmed_at IS NOT NULL;
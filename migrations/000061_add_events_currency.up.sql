@@ -0,0 +1,5 @@
+-- Ticket prices are in the currency of their event, which orders are placed in. Events created before
+-- had their prices in dollars.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'USD';
+
+COMMENT ON COLUMN events.currency IS 'ISO 4217 code of the currency of the ticket prices, fixed once the event is published';
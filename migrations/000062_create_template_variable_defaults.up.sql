@@ -0,0 +1,16 @@
+-- Overrides of the default variables templates render with, e.g. {{.AppName}}: the configuration of
+-- the environment sets the defaults, these rows replace them for every render (organizer_id NULL) or
+-- for the renders of an organizer
+CREATE TABLE IF NOT EXISTS template_variable_defaults (
+    id BIGSERIAL PRIMARY KEY,
+    organizer_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    value TEXT NOT NULL DEFAULT '',
+    updated_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- one override of a name for the platform and for each organizer
+CREATE UNIQUE INDEX IF NOT EXISTS idx_template_variable_defaults_scope
+    ON template_variable_defaults (COALESCE(organizer_id, 0), name);
@@ -0,0 +1,2 @@
+-- The categories backfilled had no count of their sold tickets, zero stays
+SELECT 1;
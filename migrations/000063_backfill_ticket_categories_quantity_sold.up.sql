@@ -0,0 +1,21 @@
+-- Backfills quantity_sold of the ticket categories created without one, so the checks added NOT VALID
+-- by 000016 can be validated. The file is a single DO statement, which golang-migrate sends on its own
+-- outside of a transaction block, so the block commits each batch of ids: no row lock is held for
+-- long and the table stays writable throughout.
+DO $$
+DECLARE
+    batch_size CONSTANT BIGINT := 1000;
+    last_id BIGINT := 0;
+    max_id BIGINT;
+BEGIN
+    SELECT COALESCE(MAX(id), 0) INTO max_id FROM ticket_categories;
+
+    WHILE last_id < max_id LOOP
+        UPDATE ticket_categories
+        SET quantity_sold = 0
+        WHERE id > last_id AND id <= last_id + batch_size AND quantity_sold IS NULL;
+
+        last_id := last_id + batch_size;
+        COMMIT;
+    END LOOP;
+END $$;
@@ -0,0 +1,2 @@
+-- A validated constraint cannot be marked NOT VALID again, the check stays as it is
+SELECT 1;
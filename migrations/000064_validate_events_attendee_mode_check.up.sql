@@ -0,0 +1,3 @@
+-- Validates the attendee settings check of events added NOT VALID, in a transaction of its own so
+-- the scan only holds a SHARE UPDATE EXCLUSIVE lock and events stay writable
+ALTER TABLE events VALIDATE CONSTRAINT events_attendee_mode_check;
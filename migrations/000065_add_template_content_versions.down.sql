@@ -0,0 +1,2 @@
+ALTER TABLE template_revisions DROP COLUMN IF EXISTS base_version;
+ALTER TABLE templates DROP COLUMN IF EXISTS content_version;
@@ -0,0 +1,5 @@
+-- Template content versions: content_version counts the content changes of a template, and a revision
+-- records the one it was written against in base_version, so approving it after the template changed
+-- again is refused instead of overwriting the newer content. Constant defaults add no table rewrite.
+ALTER TABLE templates ADD COLUMN IF NOT EXISTS content_version INT NOT NULL DEFAULT 0;
+ALTER TABLE template_revisions ADD COLUMN IF NOT EXISTS base_version INT NOT NULL DEFAULT 0;
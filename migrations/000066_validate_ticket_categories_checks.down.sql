@@ -0,0 +1,2 @@
+-- A validated constraint cannot be marked NOT VALID again, the checks stay as they are
+SELECT 1;
@@ -0,0 +1,5 @@
+-- Validates the checks of ticket_categories added NOT VALID: the scan only takes a SHARE UPDATE
+-- EXCLUSIVE lock, so tickets keep selling while it runs. It has a migration of its own because a
+-- migration runs in one transaction, which would hold the lock of the ADD CONSTRAINT through it.
+ALTER TABLE ticket_categories VALIDATE CONSTRAINT ticket_categories_quantity_sold_not_null;
+ALTER TABLE ticket_categories VALIDATE CONSTRAINT ticket_categories_inventory_check;
@@ -0,0 +1,2 @@
+ALTER TABLE events DROP COLUMN IF EXISTS test_mode;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS test_mode;
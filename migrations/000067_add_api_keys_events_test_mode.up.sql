@@ -0,0 +1,7 @@
+-- Test mode API keys create test mode events, whose orders are placed in test mode: paid with fake
+-- payments and left out of the sales reports
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS test_mode BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS test_mode BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN api_keys.test_mode IS 'Whether the events created with the key are in test mode';
+COMMENT ON COLUMN events.test_mode IS 'Whether the orders of the event are placed in test mode, fixed at creation';
@@ -0,0 +1,509 @@
+package adapters
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"tixgo/modules/booking/domain"
+	eventDomain "tixgo/modules/event/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/pgerr"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// GroupBookingPostgresRepository implements the GroupBookingRepository interface using PostgreSQL
+type GroupBookingPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewGroupBookingPostgresRepository creates a new PostgreSQL group booking repository
+func NewGroupBookingPostgresRepository(db *sqlx.DB) *GroupBookingPostgresRepository {
+	return &GroupBookingPostgresRepository{db: db}
+}
+
+// Create holds the tickets of the booking and saves it, atomically. Concurrent bookings of overlapping
+// tickets may deadlock while holding them, the losing transaction is run again.
+func (r *GroupBookingPostgresRepository) Create(ctx context.Context, booking *domain.GroupBooking) error {
+	return pgerr.Retry(ctx, func(ctx context.Context) error {
+		return r.create(ctx, booking)
+	})
+}
+
+func (r *GroupBookingPostgresRepository) create(ctx context.Context, booking *domain.GroupBooking) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	// seats are held like tickets sold online, not while the organizer paused their sales
+	var paused bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM tickets t
+			JOIN ticket_categories c ON c.id = t.ticket_category_id
+			JOIN events e ON e.id = c.event_id
+			WHERE c.event_id = $1 AND t.id = ANY($2)
+			  AND (e.sales_paused OR c.sales_paused OR e.sales_pause_at <= NOW() OR c.sales_pause_at <= NOW()))`,
+		booking.EventID, pq.Array(booking.TicketIDs())).Scan(&paused)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get sales pauses")
+	}
+	if paused {
+		return eventDomain.ErrSalesPaused
+	}
+
+	// reservations past their expiry are available again
+	holdQuery := `
+		UPDATE tickets t
+		SET status = 'reserved', reserved_at = NOW(), reserved_expires_at = $3, updated_at = NOW()
+		FROM ticket_categories c
+		WHERE c.id = t.ticket_category_id AND c.event_id = $1 AND t.id = ANY($2)
+		  AND (t.status = 'available' OR (t.status = 'reserved' AND t.reserved_expires_at <= NOW()))`
+
+	result, err := tx.ExecContext(ctx, holdQuery, booking.EventID, pq.Array(booking.TicketIDs()), booking.HoldExpiresAt)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to hold tickets")
+	}
+	held, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to hold tickets")
+	}
+	if held != int64(len(booking.Seats)) {
+		return domain.ErrSeatUnavailable
+	}
+
+	if err := reserveSeats(ctx, tx, booking.TicketIDs()); err != nil {
+		return err
+	}
+
+	err = tx.QueryRowContext(ctx, `SELECT organizer_id FROM events WHERE id = $1`, booking.EventID).Scan(&booking.OrganizerID)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get event organizer")
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO group_bookings (event_id, owner_id, status, hold_expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at`,
+		booking.EventID, booking.OwnerID, booking.Status, booking.HoldExpiresAt,
+	).Scan(&booking.ID, &booking.CreatedAt, &booking.UpdatedAt)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create group booking")
+	}
+
+	for _, seat := range booking.Seats {
+		seat.GroupBookingID = booking.ID
+		if seat.ClaimToken, err = newClaimToken(); err != nil {
+			return err
+		}
+
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO group_booking_seats (group_booking_id, ticket_id, invitee_email, claim_token, status)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id`,
+			seat.GroupBookingID, seat.TicketID, seat.InviteeEmail, seat.ClaimToken, seat.Status,
+		).Scan(&seat.ID)
+		if err != nil {
+			return syserr.Wrap(err, syserr.InternalCode, "failed to create group booking seat")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to commit group booking")
+	}
+	return nil
+}
+
+// reserveSeats counts the held tickets in quantity_reserved of their categories, like the tickets of a
+// checkout, ErrSoldOut if a category has fewer left. Claiming a seat hands its reservation to the order.
+func reserveSeats(ctx context.Context, tx *sqlx.Tx, ticketIDs []int64) error {
+	var reserved bool
+	err := tx.QueryRowContext(ctx, `
+		WITH held AS (
+			SELECT ticket_category_id, COUNT(*) AS quantity
+			FROM tickets
+			WHERE id = ANY($1)
+			GROUP BY ticket_category_id
+		), reserved AS (
+			UPDATE ticket_categories c
+			SET quantity_reserved = c.quantity_reserved + held.quantity, updated_at = NOW()
+			FROM held
+			WHERE c.id = held.ticket_category_id
+			  AND c.quantity_sold + c.quantity_reserved + c.quantity_allotted + held.quantity <= c.quantity_available
+			RETURNING c.id
+		)
+		SELECT (SELECT COUNT(*) FROM held) = (SELECT COUNT(*) FROM reserved)`, pq.Array(ticketIDs)).Scan(&reserved)
+	if err != nil {
+		if pgerr.Constraint(err) == pgerr.ConstraintTicketInventory {
+			return eventDomain.ErrSoldOut
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to reserve seats")
+	}
+	if !reserved {
+		return eventDomain.ErrSoldOut
+	}
+
+	return nil
+}
+
+// GetByID retrieves a group booking with its seats
+func (r *GroupBookingPostgresRepository) GetByID(ctx context.Context, id int64) (*domain.GroupBooking, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	booking, err := r.getBooking(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT id, group_booking_id, ticket_id, invitee_email, claim_token, status, claimed_by, order_id, claimed_at
+		FROM group_booking_seats
+		WHERE group_booking_id = $1
+		ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get group booking seats")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		seat, err := scanGroupSeat(rows)
+		if err != nil {
+			return nil, err
+		}
+		booking.Seats = append(booking.Seats, seat)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to iterate group booking seats")
+	}
+
+	return booking, nil
+}
+
+// GetByClaimToken retrieves the seat of a claim token along with its booking
+func (r *GroupBookingPostgresRepository) GetByClaimToken(ctx context.Context, token string) (*domain.GroupBooking, *domain.GroupSeat, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT id, group_booking_id, ticket_id, invitee_email, claim_token, status, claimed_by, order_id, claimed_at
+		FROM group_booking_seats
+		WHERE claim_token = $1`
+
+	seat, err := scanGroupSeat(r.db.QueryRowContext(ctx, query, token))
+	if err != nil {
+		if err == domain.ErrGroupBookingNotFound {
+			return nil, nil, domain.ErrGroupSeatNotFound
+		}
+		return nil, nil, err
+	}
+
+	booking, err := r.getBooking(ctx, seat.GroupBookingID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return booking, seat, nil
+}
+
+// Claim assigns an invited seat to userID and creates the pending order the participant pays
+func (r *GroupBookingPostgresRepository) Claim(ctx context.Context, booking *domain.GroupBooking, seat *domain.GroupSeat, userID int64) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	var claimedAt time.Time
+	err = tx.QueryRowContext(ctx, `
+		UPDATE group_booking_seats
+		SET status = $2, claimed_by = $3, claimed_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = $4
+		RETURNING claimed_at`,
+		seat.ID, domain.GroupSeatStatusClaimed, userID, domain.GroupSeatStatusInvited,
+	).Scan(&claimedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrSeatAlreadyClaimed
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to claim seat")
+	}
+
+	orderNumber, err := newOrderNumber()
+	if err != nil {
+		return err
+	}
+
+	var orderID int64
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO orders (user_id, order_number, status, total_amount, final_amount, currency, email_received, expires_at,
+		                    test_mode)
+		SELECT $1, $2, 'pending', c.price, c.price, e.currency, $3, $4, e.test_mode
+		FROM tickets t
+		JOIN ticket_categories c ON c.id = t.ticket_category_id
+		JOIN events e ON e.id = c.event_id
+		WHERE t.id = $5
+		RETURNING id`,
+		userID, orderNumber, seat.InviteeEmail, booking.HoldExpiresAt, seat.TicketID,
+	).Scan(&orderID)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create order")
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO order_items (order_id, ticket_id, unit_price, quantity, subtotal)
+		SELECT $1, $2, total_amount, 1, total_amount FROM orders WHERE id = $1`,
+		orderID, seat.TicketID)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create order item")
+	}
+
+	// the reservation of the seat is the order's from now on: its confirmation sells the ticket like a
+	// checkout, or the expiry of the order gives it back
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO order_reservations (order_id, ticket_category_id, quantity)
+		SELECT $1, ticket_category_id, 1 FROM tickets WHERE id = $2`,
+		orderID, seat.TicketID)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to record reservation")
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE group_booking_seats SET order_id = $2 WHERE id = $1`, seat.ID, orderID)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to link order to seat")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to commit seat claim")
+	}
+
+	seat.Status = domain.GroupSeatStatusClaimed
+	seat.ClaimedBy = &userID
+	seat.ClaimedAt = &claimedAt
+	seat.OrderID = &orderID
+	return nil
+}
+
+// ReleaseExpired settles up to limit seats of bookings whose hold expired before the given time.
+// Seats being settled by another instance are skipped.
+func (r *GroupBookingPostgresRepository) ReleaseExpired(ctx context.Context, before time.Time, limit int) ([]*domain.ReleasedSeat, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	type expiredSeat struct {
+		domain.ReleasedSeat
+		seatID      int64
+		orderID     *int64
+		orderStatus string
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT s.id, s.group_booking_id, b.event_id, e.organizer_id, s.ticket_id, s.invitee_email, s.order_id, COALESCE(o.status::TEXT, '')
+		FROM group_booking_seats s
+		JOIN group_bookings b ON b.id = s.group_booking_id
+		JOIN events e ON e.id = b.event_id
+		LEFT JOIN orders o ON o.id = s.order_id
+		WHERE b.status = 'open' AND b.hold_expires_at <= $1 AND s.status IN ('invited', 'claimed')
+		ORDER BY s.id
+		LIMIT $2
+		FOR UPDATE OF s SKIP LOCKED`, before, limit)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get expired group booking seats")
+	}
+
+	var expired []*expiredSeat
+	for rows.Next() {
+		seat := &expiredSeat{}
+		err := rows.Scan(&seat.seatID, &seat.GroupBookingID, &seat.EventID, &seat.OrganizerID, &seat.TicketID, &seat.InviteeEmail, &seat.orderID, &seat.orderStatus)
+		if err != nil {
+			rows.Close()
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan expired group booking seat")
+		}
+		expired = append(expired, seat)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to iterate expired group booking seats")
+	}
+
+	var released []*domain.ReleasedSeat
+	for _, seat := range expired {
+		if seat.orderStatus == "confirmed" {
+			if _, err := tx.ExecContext(ctx, `UPDATE group_booking_seats SET status = 'paid', updated_at = NOW() WHERE id = $1`, seat.seatID); err != nil {
+				return nil, syserr.Wrap(err, syserr.InternalCode, "failed to settle paid seat")
+			}
+			continue
+		}
+
+		// only release the hold of this booking, the ticket may have been held again since it expired
+		_, err := tx.ExecContext(ctx, `
+			UPDATE tickets t
+			SET status = 'available', reserved_at = NULL, reserved_expires_at = NULL, updated_at = NOW()
+			FROM group_bookings b
+			WHERE t.id = $1 AND b.id = $2 AND t.status = 'reserved' AND t.reserved_expires_at = b.hold_expires_at`,
+			seat.TicketID, seat.GroupBookingID)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to release ticket")
+		}
+
+		if seat.orderID == nil {
+			// the seat was never claimed, it still holds its reservation
+			_, err := tx.ExecContext(ctx, `
+				UPDATE ticket_categories c
+				SET quantity_reserved = c.quantity_reserved - 1, updated_at = NOW()
+				FROM tickets t
+				WHERE t.id = $1 AND c.id = t.ticket_category_id`, seat.TicketID)
+			if err != nil {
+				return nil, syserr.Wrap(err, syserr.InternalCode, "failed to release reserved seat")
+			}
+		} else {
+			result, err := tx.ExecContext(ctx, `
+				UPDATE orders SET status = 'cancelled', cancelled_at = NOW(), updated_at = NOW()
+				WHERE id = $1 AND status = 'pending'`, *seat.orderID)
+			if err != nil {
+				return nil, syserr.Wrap(err, syserr.InternalCode, "failed to cancel order")
+			}
+			cancelled, err := result.RowsAffected()
+			if err != nil {
+				return nil, syserr.Wrap(err, syserr.InternalCode, "failed to cancel order")
+			}
+			if cancelled > 0 {
+				seat.OrderID = *seat.orderID
+			}
+
+			// the reservation went to the order, which the expiry of orders may have released already
+			if cancelled > 0 || seat.orderStatus == "cancelled" {
+				_, err = tx.ExecContext(ctx, `
+					WITH released AS (
+						DELETE FROM order_reservations WHERE order_id = $1
+						RETURNING ticket_category_id, quantity
+					)
+					UPDATE ticket_categories c
+					SET quantity_reserved = c.quantity_reserved - released.quantity, updated_at = NOW()
+					FROM released
+					WHERE c.id = released.ticket_category_id`, *seat.orderID)
+				if err != nil {
+					return nil, syserr.Wrap(err, syserr.InternalCode, "failed to release reserved seat")
+				}
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, `UPDATE group_booking_seats SET status = 'released', updated_at = NOW() WHERE id = $1`, seat.seatID); err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to release seat")
+		}
+		released = append(released, &seat.ReleasedSeat)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE group_bookings b
+		SET status = CASE
+		        WHEN EXISTS (SELECT 1 FROM group_booking_seats s WHERE s.group_booking_id = b.id AND s.status = 'released') THEN 'expired'
+		        ELSE 'completed'
+		    END,
+		    updated_at = NOW()
+		WHERE b.status = 'open' AND b.hold_expires_at <= $1
+		  AND NOT EXISTS (SELECT 1 FROM group_booking_seats s WHERE s.group_booking_id = b.id AND s.status IN ('invited', 'claimed'))`,
+		before)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to close group bookings")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to commit released seats")
+	}
+	return released, nil
+}
+
+func (r *GroupBookingPostgresRepository) getBooking(ctx context.Context, id int64) (*domain.GroupBooking, error) {
+	query := `
+		SELECT b.id, b.event_id, b.owner_id, e.organizer_id, b.status, b.hold_expires_at, b.created_at, b.updated_at
+		FROM group_bookings b
+		JOIN events e ON e.id = b.event_id
+		WHERE b.id = $1`
+
+	booking := &domain.GroupBooking{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&booking.ID,
+		&booking.EventID,
+		&booking.OwnerID,
+		&booking.OrganizerID,
+		&booking.Status,
+		&booking.HoldExpiresAt,
+		&booking.CreatedAt,
+		&booking.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrGroupBookingNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get group booking")
+	}
+
+	return booking, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanGroupSeat(row rowScanner) (*domain.GroupSeat, error) {
+	seat := &domain.GroupSeat{}
+	err := row.Scan(
+		&seat.ID,
+		&seat.GroupBookingID,
+		&seat.TicketID,
+		&seat.InviteeEmail,
+		&seat.ClaimToken,
+		&seat.Status,
+		&seat.ClaimedBy,
+		&seat.OrderID,
+		&seat.ClaimedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrGroupBookingNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan group booking seat")
+	}
+	return seat, nil
+}
+
+func newClaimToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", syserr.Wrap(err, syserr.InternalCode, "failed to generate claim token")
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func newOrderNumber() (string, error) {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", syserr.Wrap(err, syserr.InternalCode, "failed to generate order number")
+	}
+	return "GRP-" + strings.ToUpper(hex.EncodeToString(b)), nil
+}
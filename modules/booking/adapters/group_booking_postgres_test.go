@@ -0,0 +1,228 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"tixgo/modules/booking/domain"
+	eventDomain "tixgo/modules/event/domain"
+	"tixgo/shared/pgerr"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// answer is what a scripted database answers to the statements containing match
+type answer struct {
+	match    string
+	columns  []string
+	rows     [][]driver.Value
+	affected int64
+	err      error
+	// once answers are used for a single statement, the next matching answer takes the following ones
+	once bool
+}
+
+// scriptedDB answers each statement with the first answer matching it and records the statements run
+// and whether their transaction committed. Statements without an answer affect no row.
+type scriptedDB struct {
+	answers    []*answer
+	statements []string
+	committed  bool
+}
+
+func (db *scriptedDB) open() *sqlx.DB {
+	return sqlx.NewDb(sql.OpenDB(scriptedConnector{db}), "postgres")
+}
+
+func (db *scriptedDB) answer(query string) *answer {
+	db.statements = append(db.statements, query)
+	for i, a := range db.answers {
+		if strings.Contains(query, a.match) {
+			if a.once {
+				db.answers = append(db.answers[:i:i], db.answers[i+1:]...)
+			}
+			return a
+		}
+	}
+	return &answer{}
+}
+
+// ran counts the statements run containing match
+func (db *scriptedDB) ran(match string) int {
+	count := 0
+	for _, statement := range db.statements {
+		if strings.Contains(statement, match) {
+			count++
+		}
+	}
+	return count
+}
+
+type scriptedConnector struct{ db *scriptedDB }
+
+func (c scriptedConnector) Connect(context.Context) (driver.Conn, error) { return scriptedConn(c), nil }
+func (c scriptedConnector) Driver() driver.Driver                        { return nil }
+
+type scriptedConn struct{ db *scriptedDB }
+
+func (c scriptedConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	a := c.db.answer(query)
+	if a.err != nil {
+		return nil, a.err
+	}
+	return &scriptedRows{columns: a.columns, rows: a.rows}, nil
+}
+
+func (c scriptedConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	a := c.db.answer(query)
+	if a.err != nil {
+		return nil, a.err
+	}
+	return driver.RowsAffected(a.affected), nil
+}
+
+func (c scriptedConn) Begin() (driver.Tx, error)           { return scriptedTx(c), nil }
+func (c scriptedConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c scriptedConn) Close() error                        { return nil }
+
+type scriptedTx struct{ db *scriptedDB }
+
+func (tx scriptedTx) Commit() error   { tx.db.committed = true; return nil }
+func (tx scriptedTx) Rollback() error { return nil }
+
+type scriptedRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *scriptedRows) Columns() []string { return r.columns }
+func (r *scriptedRows) Close() error      { return nil }
+
+func (r *scriptedRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+// row answers a query with a single row of values
+func row(match string, values ...driver.Value) *answer {
+	columns := make([]string, len(values))
+	for i := range columns {
+		columns[i] = "column"
+	}
+	return &answer{match: match, columns: columns, rows: [][]driver.Value{values}}
+}
+
+func newTestBooking(t *testing.T) *domain.GroupBooking {
+	t.Helper()
+	seats := []*domain.GroupSeat{
+		{TicketID: 11, InviteeEmail: "a@example.com"},
+		{TicketID: 12, InviteeEmail: "b@example.com"},
+	}
+	booking, err := domain.NewGroupBooking(1, 2, seats, domain.DefaultHoldWindow, time.Now())
+	require.NoError(t, err)
+	return booking
+}
+
+// creatableDB answers the statements of a booking creation, reserve answering the reservation
+func creatableDB(reserve *answer) *scriptedDB {
+	now := time.Now()
+	return &scriptedDB{answers: []*answer{
+		row("sales_paused", false),
+		{match: "UPDATE tickets t", affected: 2},
+		reserve,
+		row("SELECT organizer_id", int64(7)),
+		row("INSERT INTO group_bookings", int64(3), now, now),
+		row("INSERT INTO group_booking_seats", int64(4)),
+	}}
+}
+
+func TestGroupBookingPostgresRepository_CreateReservesSeats(t *testing.T) {
+	db := creatableDB(row("quantity_reserved = c.quantity_reserved + held.quantity", true))
+	booking := newTestBooking(t)
+
+	require.NoError(t, NewGroupBookingPostgresRepository(db.open()).Create(context.Background(), booking))
+	assert.True(t, db.committed)
+	assert.Equal(t, 1, db.ran("quantity_reserved = c.quantity_reserved + held.quantity"), "the seats count in the stock like a checkout")
+	assert.Equal(t, int64(3), booking.ID)
+	assert.Equal(t, int64(7), booking.OrganizerID)
+}
+
+func TestGroupBookingPostgresRepository_CreateSoldOut(t *testing.T) {
+	create := func(reserve *answer) (*scriptedDB, error) {
+		db := creatableDB(reserve)
+		return db, NewGroupBookingPostgresRepository(db.open()).Create(context.Background(), newTestBooking(t))
+	}
+
+	db, err := create(row("quantity_reserved = c.quantity_reserved + held.quantity", false))
+	assert.ErrorIs(t, err, eventDomain.ErrSoldOut, "a category has fewer tickets left than its held seats")
+	assert.False(t, db.committed)
+	assert.Zero(t, db.ran("INSERT INTO group_bookings"))
+
+	constraint := &pq.Error{Code: "23514", Constraint: pgerr.ConstraintTicketInventory}
+	db, err = create(&answer{match: "quantity_reserved = c.quantity_reserved + held.quantity", err: constraint})
+	assert.ErrorIs(t, err, eventDomain.ErrSoldOut)
+	assert.False(t, db.committed)
+}
+
+func TestGroupBookingPostgresRepository_ClaimHandsReservationToOrder(t *testing.T) {
+	db := &scriptedDB{answers: []*answer{
+		row("UPDATE group_booking_seats", time.Now()),
+		row("INSERT INTO orders", int64(9)),
+	}}
+	booking := newTestBooking(t)
+	seat := booking.Seats[0]
+	seat.ID = 5
+
+	require.NoError(t, NewGroupBookingPostgresRepository(db.open()).Claim(context.Background(), booking, seat, 42))
+	assert.True(t, db.committed)
+	assert.Equal(t, 1, db.ran("INSERT INTO order_reservations"), "the confirmation of the order sells the ticket")
+	assert.Zero(t, db.ran("quantity_reserved"), "the seat is already counted as reserved")
+	assert.Equal(t, domain.GroupSeatStatusClaimed, seat.Status)
+	assert.Equal(t, int64(9), *seat.OrderID)
+}
+
+func TestGroupBookingPostgresRepository_ReleaseExpired(t *testing.T) {
+	// seats: 1 never claimed, 2 claimed with a pending order, 3 paid for, 4 claimed with an order the
+	// expiry of orders already cancelled
+	expired := &answer{
+		match:   "FROM group_booking_seats s",
+		columns: []string{"id", "group_booking_id", "event_id", "organizer_id", "ticket_id", "invitee_email", "order_id", "status"},
+		rows: [][]driver.Value{
+			{int64(1), int64(3), int64(1), int64(7), int64(11), "a@example.com", nil, ""},
+			{int64(2), int64(3), int64(1), int64(7), int64(12), "b@example.com", int64(20), "pending"},
+			{int64(3), int64(3), int64(1), int64(7), int64(13), "c@example.com", int64(21), "confirmed"},
+			{int64(4), int64(3), int64(1), int64(7), int64(14), "d@example.com", int64(22), "cancelled"},
+		},
+	}
+	db := &scriptedDB{answers: []*answer{
+		expired,
+		{match: "UPDATE orders SET status = 'cancelled'", affected: 1, once: true},
+	}}
+
+	released, err := NewGroupBookingPostgresRepository(db.open()).ReleaseExpired(context.Background(), time.Now(), 10)
+	require.NoError(t, err)
+	assert.True(t, db.committed)
+
+	require.Len(t, released, 3)
+	assert.Equal(t, int64(11), released[0].TicketID)
+	assert.Zero(t, released[0].OrderID)
+	assert.Equal(t, int64(20), released[1].OrderID, "the pending order is cancelled with the seat")
+	assert.Zero(t, released[2].OrderID, "the order was cancelled before")
+
+	assert.Equal(t, 1, db.ran("quantity_reserved = c.quantity_reserved - 1"), "the unclaimed seat gives its reservation back")
+	assert.Equal(t, 2, db.ran("DELETE FROM order_reservations"), "the orders give theirs back, once whoever cancels them")
+	assert.Equal(t, 1, db.ran("SET status = 'paid'"))
+}
@@ -0,0 +1,87 @@
+package command
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"tixgo/modules/booking/domain"
+	sharedActivity "tixgo/shared/events/activity"
+	sharedKafka "tixgo/shared/kafka"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ClaimGroupSeatCommand represents the command to claim an invited seat of a group booking
+type ClaimGroupSeatCommand struct {
+	Token  string
+	UserID int64
+}
+
+// ClaimGroupSeatResult is the claimed seat and the order the participant pays for it
+type ClaimGroupSeatResult struct {
+	GroupBookingID int64  `json:"group_booking_id"`
+	EventID        int64  `json:"event_id"`
+	TicketID       int64  `json:"ticket_id"`
+	OrderID        int64  `json:"order_id"`
+	PayBefore      string `json:"pay_before"`
+}
+
+// ClaimGroupSeatHandler handles group seat claims
+type ClaimGroupSeatHandler struct {
+	bookingRepo domain.GroupBookingRepository
+	eventBus    messaging.EventBus
+}
+
+// NewClaimGroupSeatHandler creates a new claim group seat handler
+func NewClaimGroupSeatHandler(bookingRepo domain.GroupBookingRepository, eventBus messaging.EventBus) *ClaimGroupSeatHandler {
+	return &ClaimGroupSeatHandler{
+		bookingRepo: bookingRepo,
+		eventBus:    eventBus,
+	}
+}
+
+// Handle executes the claim group seat command. The claim token is the proof of the invitation, so any
+// authenticated user holding it can claim the seat; they must pay its order before the hold expires.
+func (h *ClaimGroupSeatHandler) Handle(ctx context.Context, cmd ClaimGroupSeatCommand) (*ClaimGroupSeatResult, error) {
+	booking, seat, err := h.bookingRepo.GetByClaimToken(ctx, cmd.Token)
+	if err != nil {
+		if err == domain.ErrGroupSeatNotFound {
+			return nil, domain.ErrGroupSeatNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get group booking seat")
+	}
+
+	if err := booking.CanClaim(seat, time.Now()); err != nil {
+		return nil, err
+	}
+
+	err = h.bookingRepo.Claim(ctx, booking, seat, cmd.UserID)
+	if err != nil {
+		if err == domain.ErrSeatAlreadyClaimed {
+			return nil, domain.ErrSeatAlreadyClaimed
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to claim group booking seat")
+	}
+
+	// The claim created the order of the participant, record the purchase in their activity feed
+	activity := sharedActivity.NewEventAccountActivity(cmd.UserID, sharedActivity.TypePurchase, map[string]string{
+		"order_id":         strconv.FormatInt(*seat.OrderID, 10),
+		"event_id":         strconv.FormatInt(booking.EventID, 10),
+		"group_booking_id": strconv.FormatInt(booking.ID, 10),
+	})
+	err = h.eventBus.PublishEvent(sharedKafka.WithPartitionKey(ctx, strconv.FormatInt(cmd.UserID, 10)), activity)
+	if err != nil {
+		logger.Warning(ctx, "Failed to publish purchase activity", logger.F("user_id", cmd.UserID), logger.F("error", err))
+	}
+
+	return &ClaimGroupSeatResult{
+		GroupBookingID: booking.ID,
+		EventID:        booking.EventID,
+		TicketID:       seat.TicketID,
+		OrderID:        *seat.OrderID,
+		PayBefore:      booking.HoldExpiresAt.Format("2006-01-02T15:04:05Z"),
+	}, nil
+}
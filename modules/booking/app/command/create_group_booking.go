@@ -0,0 +1,140 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/booking/domain"
+	eventDomain "tixgo/modules/event/domain"
+	templateDomain "tixgo/modules/template/domain"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// CreateGroupBookingCommand represents the command to hold seats for a group
+type CreateGroupBookingCommand struct {
+	EventID int64             `json:"event_id" binding:"required"`
+	OwnerID int64             `json:"-"`
+	Seats   []GroupSeatInvite `json:"seats" binding:"required,dive"`
+}
+
+// GroupSeatInvite is a seat of a group booking and the participant invited to claim it
+type GroupSeatInvite struct {
+	TicketID int64  `json:"ticket_id" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
+}
+
+// CreateGroupBookingHandler handles group booking creation
+type CreateGroupBookingHandler struct {
+	bookingRepo domain.GroupBookingRepository
+	notifier    participantNotifier
+	holdWindow  time.Duration
+}
+
+// NewCreateGroupBookingHandler creates a new create group booking handler
+func NewCreateGroupBookingHandler(bookingRepo domain.GroupBookingRepository, templateRepo templateDomain.TemplateRepository, templateRenderer templateDomain.TemplateRenderer, eventBus messaging.EventBus, holdWindow time.Duration) *CreateGroupBookingHandler {
+	if holdWindow <= 0 {
+		holdWindow = domain.DefaultHoldWindow
+	}
+
+	return &CreateGroupBookingHandler{
+		bookingRepo: bookingRepo,
+		notifier: participantNotifier{
+			templateRepo:     templateRepo,
+			templateRenderer: templateRenderer,
+			eventBus:         eventBus,
+		},
+		holdWindow: holdWindow,
+	}
+}
+
+// Handle executes the create group booking command: the seats are held for the hold window and every
+// participant is mailed the link to claim their seat. The booking stands once the seats are held, so a
+// failed invite mail is logged; the owner can share the claim links from the booking instead.
+func (h *CreateGroupBookingHandler) Handle(ctx context.Context, cmd CreateGroupBookingCommand) (*GroupBookingResult, error) {
+	seats := make([]*domain.GroupSeat, len(cmd.Seats))
+	for i, invite := range cmd.Seats {
+		seats[i] = &domain.GroupSeat{TicketID: invite.TicketID, InviteeEmail: invite.Email}
+	}
+
+	booking, err := domain.NewGroupBooking(cmd.EventID, cmd.OwnerID, seats, h.holdWindow, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.bookingRepo.Create(ctx, booking)
+	if err != nil {
+		switch err {
+		case domain.ErrSeatUnavailable, eventDomain.ErrSalesPaused, eventDomain.ErrSoldOut:
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to create group booking")
+	}
+
+	for _, seat := range booking.Seats {
+		h.notifier.seatChanged(ctx, booking.EventID, seat.TicketID, eventDomain.SeatStatusHeld)
+
+		vars := bookingVars(booking)
+		vars["ticket_id"] = seat.TicketID
+		vars["claim_token"] = seat.ClaimToken
+		if err := h.notifier.mail(ctx, SlugGroupBookingInvite, seat.InviteeEmail, booking.OrganizerID, vars); err != nil {
+			logger.Error(ctx, "Failed to send group booking invite",
+				logger.F("group_booking_id", booking.ID), logger.F("email", seat.InviteeEmail), logger.F("error", err))
+		}
+	}
+
+	return ToGroupBookingResult(booking), nil
+}
+
+// GroupBookingResult represents a group booking as shown to its owner
+type GroupBookingResult struct {
+	ID            int64              `json:"id"`
+	EventID       int64              `json:"event_id"`
+	Status        string             `json:"status"`
+	HoldExpiresAt string             `json:"hold_expires_at"`
+	Seats         []*GroupSeatResult `json:"seats"`
+	CreatedAt     string             `json:"created_at"`
+}
+
+// GroupSeatResult represents a seat of a group booking. The owner sees the claim tokens to share
+// the invitations again.
+type GroupSeatResult struct {
+	TicketID     int64   `json:"ticket_id"`
+	InviteeEmail string  `json:"invitee_email"`
+	ClaimToken   string  `json:"claim_token,omitempty"`
+	Status       string  `json:"status"`
+	ClaimedAt    *string `json:"claimed_at,omitempty"`
+}
+
+// ToGroupBookingResult converts a group booking to its result. Claim tokens are only shown while
+// the seat can still be claimed.
+func ToGroupBookingResult(booking *domain.GroupBooking) *GroupBookingResult {
+	result := &GroupBookingResult{
+		ID:            booking.ID,
+		EventID:       booking.EventID,
+		Status:        string(booking.Status),
+		HoldExpiresAt: booking.HoldExpiresAt.Format("2006-01-02T15:04:05Z"),
+		Seats:         make([]*GroupSeatResult, len(booking.Seats)),
+		CreatedAt:     booking.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+
+	for i, seat := range booking.Seats {
+		seatResult := &GroupSeatResult{
+			TicketID:     seat.TicketID,
+			InviteeEmail: seat.InviteeEmail,
+			Status:       string(seat.Status),
+		}
+		if seat.Status == domain.GroupSeatStatusInvited {
+			seatResult.ClaimToken = seat.ClaimToken
+		}
+		if seat.ClaimedAt != nil {
+			claimedAt := seat.ClaimedAt.Format("2006-01-02T15:04:05Z")
+			seatResult.ClaimedAt = &claimedAt
+		}
+		result.Seats[i] = seatResult
+	}
+
+	return result
+}
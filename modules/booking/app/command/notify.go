@@ -0,0 +1,92 @@
+package command
+
+import (
+	"context"
+	"strconv"
+
+	"tixgo/modules/booking/domain"
+	eventDomain "tixgo/modules/event/domain"
+	templateDomain "tixgo/modules/template/domain"
+	sharedMail "tixgo/shared/events/mail"
+	sharedOrder "tixgo/shared/events/order"
+	sharedKafka "tixgo/shared/kafka"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/notification/mail"
+	"github.com/duongptryu/gox/syserr"
+)
+
+const (
+	SlugGroupBookingInvite       = "group-booking-invite"
+	SlugGroupBookingSeatReleased = "group-booking-seat-released"
+)
+
+// participantNotifier mails group booking participants and announces the seat changes
+type participantNotifier struct {
+	templateRepo     templateDomain.TemplateRepository
+	templateRenderer templateDomain.TemplateRenderer
+	eventBus         messaging.EventBus
+}
+
+// mail renders the template of slug for a participant and publishes the mail, sent with the identity of the organizer
+func (n *participantNotifier) mail(ctx context.Context, slug, email string, organizerID int64, vars map[string]interface{}) error {
+	template, err := n.templateRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get template")
+	}
+
+	rendered, err := n.templateRenderer.Render(ctx, template, vars, templateDomain.RenderOptions{OrganizerID: organizerID})
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to render template")
+	}
+
+	err = n.eventBus.PublishEvent(sharedKafka.WithPartitionKey(ctx, email), &sharedMail.EventSendMail{
+		ToMail: []mail.EmailAddress{
+			{
+				Email: email,
+				Name:  "",
+			},
+		},
+		Subject:     rendered.Subject,
+		HTMLBody:    rendered.Content,
+		Priority:    mail.PriorityNormal,
+		OrganizerID: organizerID,
+	})
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to publish send mail event")
+	}
+
+	return nil
+}
+
+// seatChanged announces the new status of a seat to the seat maps. The change is already committed,
+// a failure only leaves seat maps stale until their next snapshot so it is logged.
+func (n *participantNotifier) seatChanged(ctx context.Context, eventID, ticketID int64, status eventDomain.SeatStatus) {
+	key := strconv.FormatInt(eventID, 10)
+	err := n.eventBus.PublishEvent(sharedKafka.WithPartitionKey(ctx, key), eventDomain.NewEventSeatStatusChanged(eventID, ticketID, status))
+	if err != nil {
+		logger.Warning(ctx, "Failed to publish seat status change", logger.F("event_id", eventID), logger.F("ticket_id", ticketID), logger.F("error", err))
+	}
+}
+
+// bookingVars are the template variables shared by every group booking mail
+func bookingVars(booking *domain.GroupBooking) map[string]interface{} {
+	return map[string]interface{}{
+		"group_booking_id": booking.ID,
+		"event_id":         booking.EventID,
+		"expires_at":       booking.HoldExpiresAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+// ordersChanged announces orders changed along with their seats to the order read models. A failure
+// leaves their summaries stale until the next rebuild so it is logged.
+func (n *participantNotifier) ordersChanged(ctx context.Context, orderIDs []int64) {
+	if len(orderIDs) == 0 {
+		return
+	}
+
+	if err := n.eventBus.PublishEvent(ctx, sharedOrder.NewEventOrdersChanged(orderIDs...)); err != nil {
+		logger.Warning(ctx, "Failed to publish orders change", logger.F("order_ids", orderIDs), logger.F("error", err))
+	}
+}
@@ -0,0 +1,78 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/booking/domain"
+	eventDomain "tixgo/modules/event/domain"
+	templateDomain "tixgo/modules/template/domain"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// releaseBatchSize bounds the seats settled per transaction
+const releaseBatchSize = 200
+
+// ReleaseExpiredGroupSeatsHandler gives the unpaid seats of expired group bookings back to sale
+type ReleaseExpiredGroupSeatsHandler struct {
+	bookingRepo domain.GroupBookingRepository
+	notifier    participantNotifier
+}
+
+// NewReleaseExpiredGroupSeatsHandler creates a new release expired group seats handler
+func NewReleaseExpiredGroupSeatsHandler(bookingRepo domain.GroupBookingRepository, templateRepo templateDomain.TemplateRepository, templateRenderer templateDomain.TemplateRenderer, eventBus messaging.EventBus) *ReleaseExpiredGroupSeatsHandler {
+	return &ReleaseExpiredGroupSeatsHandler{
+		bookingRepo: bookingRepo,
+		notifier: participantNotifier{
+			templateRepo:     templateRepo,
+			templateRenderer: templateRenderer,
+			eventBus:         eventBus,
+		},
+	}
+}
+
+// Handle releases the expired seats batch by batch, telling each participant their seat is gone.
+// Releases are committed before the mails go out, so a failed mail is logged rather than retried.
+func (h *ReleaseExpiredGroupSeatsHandler) Handle(ctx context.Context) error {
+	now := time.Now()
+	total := 0
+
+	for {
+		released, err := h.bookingRepo.ReleaseExpired(ctx, now, releaseBatchSize)
+		if err != nil {
+			return syserr.Wrap(err, syserr.InternalCode, "failed to release expired group seats")
+		}
+
+		var cancelledOrders []int64
+		for _, seat := range released {
+			h.notifier.seatChanged(ctx, seat.EventID, seat.TicketID, eventDomain.SeatStatusAvailable)
+			if seat.OrderID != 0 {
+				cancelledOrders = append(cancelledOrders, seat.OrderID)
+			}
+
+			err := h.notifier.mail(ctx, SlugGroupBookingSeatReleased, seat.InviteeEmail, seat.OrganizerID, map[string]interface{}{
+				"group_booking_id": seat.GroupBookingID,
+				"event_id":         seat.EventID,
+				"ticket_id":        seat.TicketID,
+			})
+			if err != nil {
+				logger.Error(ctx, "Failed to send group seat release notice",
+					logger.F("group_booking_id", seat.GroupBookingID), logger.F("email", seat.InviteeEmail), logger.F("error", err))
+			}
+		}
+		h.notifier.ordersChanged(ctx, cancelledOrders)
+
+		total += len(released)
+		if len(released) < releaseBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		logger.Info(ctx, "Released expired group booking seats", logger.F("count", total))
+	}
+	return nil
+}
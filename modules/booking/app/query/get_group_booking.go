@@ -0,0 +1,45 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/booking/app/command"
+	"tixgo/modules/booking/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// GetGroupBookingQuery represents the query to get a group booking of its owner
+type GetGroupBookingQuery struct {
+	ID     int64
+	UserID int64
+}
+
+// GetGroupBookingHandler handles group booking queries
+type GetGroupBookingHandler struct {
+	bookingRepo domain.GroupBookingRepository
+}
+
+// NewGetGroupBookingHandler creates a new get group booking handler
+func NewGetGroupBookingHandler(bookingRepo domain.GroupBookingRepository) *GetGroupBookingHandler {
+	return &GetGroupBookingHandler{
+		bookingRepo: bookingRepo,
+	}
+}
+
+// Handle executes the get group booking query. Bookings of other users are reported as not found.
+func (h *GetGroupBookingHandler) Handle(ctx context.Context, query GetGroupBookingQuery) (*command.GroupBookingResult, error) {
+	booking, err := h.bookingRepo.GetByID(ctx, query.ID)
+	if err != nil {
+		if err == domain.ErrGroupBookingNotFound {
+			return nil, domain.ErrGroupBookingNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get group booking")
+	}
+
+	if booking.OwnerID != query.UserID {
+		return nil, domain.ErrGroupBookingNotFound
+	}
+
+	return command.ToGroupBookingResult(booking), nil
+}
@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"tixgo/shared/errstack"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// Booking domain errors
+var (
+	ErrGroupBookingNotFound = errstack.Sentinel(syserr.NotFoundCode, "group booking not found")
+	ErrGroupSeatNotFound    = errstack.Sentinel(syserr.NotFoundCode, "group booking seat not found")
+	ErrSeatUnavailable      = errstack.Sentinel(syserr.ConflictCode, "some seats are no longer available")
+	ErrSeatAlreadyClaimed   = errstack.Sentinel(syserr.ConflictCode, "the seat was already claimed")
+	ErrGroupBookingExpired  = errstack.Sentinel(syserr.ConflictCode, "the group booking hold has expired")
+	ErrInvalidGroupSize     = errstack.Sentinel(syserr.InvalidArgumentCode, "invalid number of seats for a group booking")
+	ErrDuplicateGroupSeat   = errstack.Sentinel(syserr.InvalidArgumentCode, "a seat is listed more than once")
+)
@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// MinGroupSize and MaxGroupSize bound the seats of a group booking
+	MinGroupSize = 2
+	MaxGroupSize = 20
+	// DefaultHoldWindow is how long participants have to claim and pay for their seat
+	DefaultHoldWindow = 24 * time.Hour
+)
+
+// GroupBookingStatus represents the lifecycle of a group booking
+type GroupBookingStatus string
+
+const (
+	// GroupBookingStatusOpen bookings hold their seats until the hold expires
+	GroupBookingStatusOpen GroupBookingStatus = "open"
+	// GroupBookingStatusCompleted bookings had every seat paid for
+	GroupBookingStatusCompleted GroupBookingStatus = "completed"
+	// GroupBookingStatusExpired bookings released some seats unpaid
+	GroupBookingStatusExpired   GroupBookingStatus = "expired"
+	GroupBookingStatusCancelled GroupBookingStatus = "cancelled"
+)
+
+// GroupSeatStatus represents the lifecycle of a seat of a group booking
+type GroupSeatStatus string
+
+const (
+	GroupSeatStatusInvited  GroupSeatStatus = "invited"
+	GroupSeatStatusClaimed  GroupSeatStatus = "claimed"
+	GroupSeatStatusPaid     GroupSeatStatus = "paid"
+	GroupSeatStatusReleased GroupSeatStatus = "released"
+)
+
+// GroupBooking is a set of seats held by one buyer for a group; each participant claims
+// and pays for their own seat within the hold window
+type GroupBooking struct {
+	ID      int64
+	EventID int64
+	OwnerID int64
+	// OrganizerID is the organizer of the event, whose identity participant mails are sent with
+	OrganizerID   int64
+	Status        GroupBookingStatus
+	HoldExpiresAt time.Time
+	Seats         []*GroupSeat
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// GroupSeat is a seat of a group booking and the participant it is meant for
+type GroupSeat struct {
+	ID             int64
+	GroupBookingID int64
+	TicketID       int64
+	InviteeEmail   string
+	// ClaimToken is sent to the invitee only, it is the proof of the invitation
+	ClaimToken string
+	Status     GroupSeatStatus
+	ClaimedBy  *int64
+	OrderID    *int64
+	ClaimedAt  *time.Time
+}
+
+// NewGroupBooking creates an open group booking holding seats until now plus holdWindow
+func NewGroupBooking(eventID, ownerID int64, seats []*GroupSeat, holdWindow time.Duration, now time.Time) (*GroupBooking, error) {
+	if len(seats) < MinGroupSize || len(seats) > MaxGroupSize {
+		return nil, ErrInvalidGroupSize
+	}
+
+	tickets := make(map[int64]bool, len(seats))
+	for _, seat := range seats {
+		if tickets[seat.TicketID] {
+			return nil, ErrDuplicateGroupSeat
+		}
+		tickets[seat.TicketID] = true
+		seat.Status = GroupSeatStatusInvited
+	}
+
+	return &GroupBooking{
+		EventID:       eventID,
+		OwnerID:       ownerID,
+		Status:        GroupBookingStatusOpen,
+		HoldExpiresAt: now.Add(holdWindow),
+		Seats:         seats,
+	}, nil
+}
+
+// TicketIDs returns the tickets held by the booking
+func (b *GroupBooking) TicketIDs() []int64 {
+	ids := make([]int64, len(b.Seats))
+	for i, seat := range b.Seats {
+		ids[i] = seat.TicketID
+	}
+	return ids
+}
+
+// CanClaim checks that seat can be claimed now
+func (b *GroupBooking) CanClaim(seat *GroupSeat, now time.Time) error {
+	if b.Status != GroupBookingStatusOpen || !now.Before(b.HoldExpiresAt) {
+		return ErrGroupBookingExpired
+	}
+	if seat.Status != GroupSeatStatusInvited {
+		return ErrSeatAlreadyClaimed
+	}
+	return nil
+}
+
+// ReleasedSeat is a seat given back to sale because it was not paid for within the hold window
+type ReleasedSeat struct {
+	GroupBookingID int64
+	EventID        int64
+	OrganizerID    int64
+	TicketID       int64
+	InviteeEmail   string
+	// OrderID is the pending order of the seat cancelled with its release, zero when there was none
+	OrderID int64
+}
+
+// GroupBookingRepository defines the interface for group booking persistence
+type GroupBookingRepository interface {
+	// Create holds the tickets of the booking, counted as reserved in the stock of their categories,
+	// and saves it, atomically. It fails with ErrSeatUnavailable if any ticket of the event is not
+	// available, and with the ErrSoldOut of the event domain if a category has fewer tickets left.
+	Create(ctx context.Context, booking *GroupBooking) error
+
+	// GetByID retrieves a group booking with its seats
+	GetByID(ctx context.Context, id int64) (*GroupBooking, error)
+
+	// GetByClaimToken retrieves the seat of a claim token along with its booking
+	GetByClaimToken(ctx context.Context, token string) (*GroupBooking, *GroupSeat, error)
+
+	// Claim assigns an invited seat to userID and creates the pending order the participant pays,
+	// at the price of the ticket and expiring with the hold. The order takes over the reservation of
+	// the seat, sold when it is confirmed. It fails with ErrSeatAlreadyClaimed if the seat was claimed
+	// concurrently.
+	Claim(ctx context.Context, booking *GroupBooking, seat *GroupSeat, userID int64) error
+
+	// ReleaseExpired settles up to limit seats of bookings whose hold expired before the given time:
+	// paid seats are kept, the others are given back to sale with their reservation, and their pending
+	// order cancelled.
+	// Bookings without unsettled seats are closed. It returns the released seats.
+	ReleaseExpired(ctx context.Context, before time.Time, limit int) ([]*ReleasedSeat, error)
+}
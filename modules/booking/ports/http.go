@@ -0,0 +1,112 @@
+package ports
+
+import (
+	"net/http"
+	"strconv"
+
+	"tixgo/components"
+	"tixgo/modules/booking/adapters"
+	"tixgo/modules/booking/app/command"
+	"tixgo/modules/booking/app/query"
+	"tixgo/modules/booking/domain"
+	organizerAdapters "tixgo/modules/organizer/adapters"
+	organizerQuery "tixgo/modules/organizer/app/query"
+	templateAdapters "tixgo/modules/template/adapters"
+	"tixgo/shared/apiversion"
+	"tixgo/shared/httpresponse"
+	"tixgo/shared/session"
+
+	"github.com/duongptryu/gox/context"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterBookingRoutes(router *apiversion.Group, appCtx components.AppContext) {
+	groupBookingGroup := router.Group("/group-bookings")
+	{
+		groupBookingGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		groupBookingGroup.POST("", CreateGroupBooking(appCtx))
+		groupBookingGroup.GET("/:id", GetGroupBooking(appCtx))
+		groupBookingGroup.POST("/claims/:token", ClaimGroupSeat(appCtx))
+	}
+}
+
+func CreateGroupBooking(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.CreateGroupBookingCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		userID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.OwnerID = userID
+
+		bookingRepo := adapters.NewGroupBookingPostgresRepository(appCtx.GetDB())
+		templateRepo := templateAdapters.NewCachedTemplateRepository(templateAdapters.NewTemplatePostgresRepository(appCtx.GetDB()), appCtx.GetCache())
+		themes := organizerQuery.NewResolveThemeHandler(organizerAdapters.NewThemePostgresRepository(appCtx.GetDB()))
+		templateRenderer := templateAdapters.NewHTMLTemplateRenderer(appCtx.GetAssetService(), themes, templateAdapters.NewVariableDefaultPostgresRepository(appCtx.GetDB()))
+		handler := command.NewCreateGroupBookingHandler(bookingRepo, templateRepo, templateRenderer, appCtx.GetReliableEventBus(), domain.DefaultHoldWindow)
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusCreated, result)
+	}
+}
+
+func GetGroupBooking(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		userID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		handler := query.NewGetGroupBookingHandler(adapters.NewGroupBookingPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), query.GetGroupBookingQuery{ID: id, UserID: userID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func ClaimGroupSeat(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		handler := command.NewClaimGroupSeatHandler(adapters.NewGroupBookingPostgresRepository(appCtx.GetDB()), appCtx.GetReliableEventBus())
+
+		result, err := handler.Handle(c.Request.Context(), command.ClaimGroupSeatCommand{
+			Token:  c.Param("token"),
+			UserID: userID,
+		})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusCreated, result)
+	}
+}
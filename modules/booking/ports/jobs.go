@@ -0,0 +1,36 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"tixgo/components"
+	"tixgo/modules/booking/adapters"
+	"tixgo/modules/booking/app/command"
+	organizerAdapters "tixgo/modules/organizer/adapters"
+	organizerQuery "tixgo/modules/organizer/app/query"
+	templateAdapters "tixgo/modules/template/adapters"
+	"tixgo/shared/scheduler"
+)
+
+const (
+	JobReleaseExpiredGroupSeats = "booking.release_expired_group_seats"
+)
+
+// Jobs returns the jobs of the booking module
+func Jobs(appCtx components.AppContext) []scheduler.Job {
+	return []scheduler.Job{
+		{
+			Name:     JobReleaseExpiredGroupSeats,
+			Schedule: "@every 1m",
+			Timeout:  5 * time.Minute,
+			Run: func(ctx context.Context) error {
+				bookingRepo := adapters.NewGroupBookingPostgresRepository(appCtx.GetDB())
+				templateRepo := templateAdapters.NewCachedTemplateRepository(templateAdapters.NewTemplatePostgresRepository(appCtx.GetDB()), appCtx.GetCache())
+				themes := organizerQuery.NewResolveThemeHandler(organizerAdapters.NewThemePostgresRepository(appCtx.GetDB()))
+				templateRenderer := templateAdapters.NewHTMLTemplateRenderer(appCtx.GetAssetService(), themes, templateAdapters.NewVariableDefaultPostgresRepository(appCtx.GetDB()))
+				return command.NewReleaseExpiredGroupSeatsHandler(bookingRepo, templateRepo, templateRenderer, appCtx.GetReliableEventBus()).Handle(ctx)
+			},
+		},
+	}
+}
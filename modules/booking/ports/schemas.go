@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"tixgo/modules/booking/app/command"
+	"tixgo/shared/jsonschema"
+)
+
+// Schemas returns the request payloads of the booking module
+func Schemas() []jsonschema.Payload {
+	return []jsonschema.Payload{
+		{Name: "group-bookings.create", In: jsonschema.Body, Example: command.CreateGroupBookingCommand{}},
+	}
+}
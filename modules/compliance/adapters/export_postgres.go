@@ -0,0 +1,234 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"tixgo/modules/compliance/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/listing"
+	"tixgo/shared/pgerr"
+	"tixgo/shared/pgquery"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+const exportColumns = `id, dataset, period_start, period_end, trigger, requested_by, status, object_key,
+	rows_exported, size_bytes, sha256, retain_until, error, created_at, started_at, finished_at, purged_at`
+
+// ExportPostgresRepository implements the ExportRepository interface using PostgreSQL
+type ExportPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewExportPostgresRepository creates a new PostgreSQL compliance export repository
+func NewExportPostgresRepository(db *sqlx.DB) *ExportPostgresRepository {
+	return &ExportPostgresRepository{db: db}
+}
+
+// Create creates a pending export
+func (r *ExportPostgresRepository) Create(ctx context.Context, export *domain.Export) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		INSERT INTO compliance_exports (dataset, period_start, period_end, trigger, requested_by, status)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`
+
+	err := r.db.QueryRowContext(ctx, query, export.Dataset, export.PeriodStart, export.PeriodEnd,
+		export.Trigger, export.RequestedBy, export.Status).Scan(&export.ID, &export.CreatedAt)
+	if pgerr.IsUniqueViolation(err) {
+		return domain.ErrExportAlreadyCreated
+	}
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create compliance export")
+	}
+
+	return nil
+}
+
+// Claim starts the oldest pending or stale export, skipping the ones other workers are claiming
+func (r *ExportPostgresRepository) Claim(ctx context.Context, now, staleBefore time.Time) (*domain.Export, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := fmt.Sprintf(`
+		UPDATE compliance_exports SET status = 'running', started_at = $1, error = NULL
+		WHERE id = (
+			SELECT id FROM compliance_exports
+			WHERE status = 'pending' OR (status = 'running' AND started_at < $2)
+			ORDER BY id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING %s`, exportColumns)
+
+	rows, err := r.db.QueryContext(ctx, query, now, staleBefore)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to claim compliance export")
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to claim compliance export")
+		}
+		return nil, nil
+	}
+
+	return scanExport(rows)
+}
+
+// Save updates the status and the result of an export
+func (r *ExportPostgresRepository) Save(ctx context.Context, export *domain.Export) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		UPDATE compliance_exports
+		SET status = $2, object_key = NULLIF($3, ''), rows_exported = $4, size_bytes = $5, sha256 = NULLIF($6, ''),
+			retain_until = $7, error = NULLIF($8, ''), started_at = $9, finished_at = $10, purged_at = $11
+		WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, export.ID, export.Status, export.Key, export.Rows, export.Bytes,
+		export.SHA256, export.RetainUntil, export.Error, export.StartedAt, export.FinishedAt, export.PurgedAt)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to save compliance export")
+	}
+
+	return nil
+}
+
+// GetByID retrieves an export
+func (r *ExportPostgresRepository) GetByID(ctx context.Context, id int64) (*domain.Export, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := fmt.Sprintf(`SELECT %s FROM compliance_exports WHERE id = $1`, exportColumns)
+
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get compliance export")
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get compliance export")
+		}
+		return nil, domain.ErrExportNotFound
+	}
+
+	return scanExport(rows)
+}
+
+// List retrieves exports with pagination and filters, newest first
+func (r *ExportPostgresRepository) List(ctx context.Context, filters domain.ListExportFilters, paging *listing.Paging) ([]*domain.Export, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	filter := &pgquery.Filter{}
+
+	if filters.Dataset != nil {
+		filter.Where("dataset = ?", *filters.Dataset)
+	}
+
+	if filters.Status != nil {
+		filter.Where("status = ?", *filters.Status)
+	}
+
+	// Set total in paging
+	err := pgquery.Count(ctx, r.db, "compliance_exports", filter, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to count compliance exports")
+	}
+
+	pageClause, args := filter.Paged(paging)
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM compliance_exports
+		%s
+		ORDER BY id DESC
+		%s`, exportColumns, filter.Clause(), pageClause)
+
+	exports, err := r.queryExports(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return exports[:paging.Fetched(len(exports))], nil
+}
+
+// ListUnpurged retrieves the succeeded exports of periods ended before the given time whose records
+// are still in Postgres, oldest first
+func (r *ExportPostgresRepository) ListUnpurged(ctx context.Context, endedBefore time.Time) ([]*domain.Export, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM compliance_exports
+		WHERE status = 'succeeded' AND purged_at IS NULL AND period_end <= $1
+		ORDER BY period_end, id`, exportColumns)
+
+	return r.queryExports(ctx, query, endedBefore)
+}
+
+func (r *ExportPostgresRepository) queryExports(ctx context.Context, query string, args ...interface{}) ([]*domain.Export, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list compliance exports")
+	}
+	defer rows.Close()
+
+	var exports []*domain.Export
+	for rows.Next() {
+		export, err := scanExport(rows)
+		if err != nil {
+			return nil, err
+		}
+		exports = append(exports, export)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "error iterating compliance export rows")
+	}
+
+	return exports, nil
+}
+
+func scanExport(rows *sql.Rows) (*domain.Export, error) {
+	export := &domain.Export{}
+	var key, sha256, exportError sql.NullString
+	err := rows.Scan(
+		&export.ID,
+		&export.Dataset,
+		&export.PeriodStart,
+		&export.PeriodEnd,
+		&export.Trigger,
+		&export.RequestedBy,
+		&export.Status,
+		&key,
+		&export.Rows,
+		&export.Bytes,
+		&sha256,
+		&export.RetainUntil,
+		&exportError,
+		&export.CreatedAt,
+		&export.StartedAt,
+		&export.FinishedAt,
+		&export.PurgedAt,
+	)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan compliance export")
+	}
+	export.Key = key.String
+	export.SHA256 = sha256.String
+	export.Error = exportError.String
+
+	return export, nil
+}
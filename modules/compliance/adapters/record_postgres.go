@@ -0,0 +1,164 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"tixgo/modules/compliance/domain"
+	"tixgo/shared/dbtimeout"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+// purgeBatchSize bounds the rows deleted by a statement, so a purge does not lock a whole day of rows
+const purgeBatchSize = 5000
+
+// datasetTable is where the records of a dataset are kept
+type datasetTable struct {
+	table string
+	// timeColumn is the time the records are exported and purged by
+	timeColumn string
+	columns    []string
+	// purgeCondition keeps the records that are still in use when purging
+	purgeCondition string
+}
+
+var datasetTables = map[domain.Dataset]datasetTable{
+	domain.DatasetUserActivities: {
+		table:      "user_activities",
+		timeColumn: "occurred_at",
+		columns:    []string{"id", "event_id", "user_id", "type", "metadata", "ip_address", "user_agent", "occurred_at"},
+	},
+	domain.DatasetLoginEvents: {
+		table:      "login_events",
+		timeColumn: "created_at",
+		columns:    []string{"id", "user_id", "ip_address", "user_agent", "created_at"},
+	},
+	domain.DatasetNotifications: {
+		table:          "notification_digest_items",
+		timeColumn:     "created_at",
+		columns:        []string{"id", "user_id", "category", "title", "summary", "url", "created_at", "digested_at"},
+		purgeCondition: "digested_at IS NOT NULL",
+	},
+}
+
+// RecordPostgresSource implements the RecordSource interface, reading the tables of the user and
+// notification modules
+type RecordPostgresSource struct {
+	db *sqlx.DB
+}
+
+// NewRecordPostgresSource creates a new PostgreSQL record source
+func NewRecordPostgresSource(db *sqlx.DB) *RecordPostgresSource {
+	return &RecordPostgresSource{db: db}
+}
+
+// Columns returns the columns of the records of a dataset
+func (s *RecordPostgresSource) Columns(dataset domain.Dataset) []string {
+	return datasetTables[dataset].columns
+}
+
+// Stream calls fn with every record of a dataset in the period as rows are read from the cursor.
+// Times are formatted RFC 3339 in UTC.
+func (s *RecordPostgresSource) Stream(ctx context.Context, dataset domain.Dataset, start, end time.Time, fn func(record []string) error) error {
+	table, ok := datasetTables[dataset]
+	if !ok {
+		return domain.ErrInvalidDataset
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE %s >= $1 AND %s < $2
+		ORDER BY %s, id`, strings.Join(table.columns, ", "), table.table, table.timeColumn, table.timeColumn, table.timeColumn)
+
+	rows, err := s.db.QueryContext(ctx, query, start, end)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to read "+table.table)
+	}
+	defer rows.Close()
+
+	values := make([]interface{}, len(table.columns))
+	for i := range values {
+		values[i] = new(interface{})
+	}
+	record := make([]string, len(table.columns))
+
+	for rows.Next() {
+		if err := rows.Scan(values...); err != nil {
+			return syserr.Wrap(err, syserr.InternalCode, "failed to scan "+table.table)
+		}
+		for i, value := range values {
+			record[i] = formatValue(*value.(*interface{}))
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "error iterating "+table.table+" rows")
+	}
+
+	return nil
+}
+
+// formatValue formats a value scanned by the driver as text
+func formatValue(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return value.UTC().Format(time.RFC3339Nano)
+	case []byte:
+		return string(value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// Purge deletes the records of a dataset in the period by batches
+func (s *RecordPostgresSource) Purge(ctx context.Context, dataset domain.Dataset, start, end time.Time) (int64, error) {
+	table, ok := datasetTables[dataset]
+	if !ok {
+		return 0, domain.ErrInvalidDataset
+	}
+
+	condition := ""
+	if table.purgeCondition != "" {
+		condition = " AND " + table.purgeCondition
+	}
+	query := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE id IN (
+			SELECT id FROM %s
+			WHERE %s >= $1 AND %s < $2%s
+			LIMIT %d
+		)`, table.table, table.table, table.timeColumn, table.timeColumn, condition, purgeBatchSize)
+
+	var purged int64
+	for {
+		deleted, err := s.purgeBatch(ctx, query, start, end)
+		if err != nil {
+			return purged, syserr.Wrap(err, syserr.InternalCode, "failed to purge "+table.table)
+		}
+		purged += deleted
+		if deleted < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+func (s *RecordPostgresSource) purgeBatch(ctx context.Context, query string, start, end time.Time) (int64, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	result, err := s.db.ExecContext(ctx, query, start, end)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
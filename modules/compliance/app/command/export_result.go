@@ -0,0 +1,61 @@
+package command
+
+import "tixgo/modules/compliance/domain"
+
+// ExportResult represents a compliance export
+type ExportResult struct {
+	ID          int64                `json:"id"`
+	Dataset     domain.Dataset       `json:"dataset"`
+	PeriodStart string               `json:"period_start"`
+	PeriodEnd   string               `json:"period_end"`
+	Trigger     domain.ExportTrigger `json:"trigger"`
+	RequestedBy *int64               `json:"requested_by"`
+	Status      domain.ExportStatus  `json:"status"`
+	Key         string               `json:"key,omitempty"`
+	ManifestKey string               `json:"manifest_key,omitempty"`
+	Rows        int64                `json:"rows"`
+	Bytes       int64                `json:"bytes"`
+	SHA256      string               `json:"sha256,omitempty"`
+	RetainUntil *string              `json:"retain_until"`
+	Error       string               `json:"error,omitempty"`
+	CreatedAt   string               `json:"created_at"`
+	FinishedAt  *string              `json:"finished_at"`
+	PurgedAt    *string              `json:"purged_at"`
+}
+
+// ToExportResult converts an export to its result
+func ToExportResult(export *domain.Export) *ExportResult {
+	result := &ExportResult{
+		ID:          export.ID,
+		Dataset:     export.Dataset,
+		PeriodStart: export.PeriodStart.UTC().Format("2006-01-02T15:04:05Z"),
+		PeriodEnd:   export.PeriodEnd.UTC().Format("2006-01-02T15:04:05Z"),
+		Trigger:     export.Trigger,
+		RequestedBy: export.RequestedBy,
+		Status:      export.Status,
+		Key:         export.Key,
+		Rows:        export.Rows,
+		Bytes:       export.Bytes,
+		SHA256:      export.SHA256,
+		Error:       export.Error,
+		CreatedAt:   export.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+	}
+
+	if export.Key != "" {
+		result.ManifestKey = export.ManifestKey()
+	}
+	if export.RetainUntil != nil {
+		retainUntil := export.RetainUntil.UTC().Format("2006-01-02T15:04:05Z")
+		result.RetainUntil = &retainUntil
+	}
+	if export.FinishedAt != nil {
+		finishedAt := export.FinishedAt.UTC().Format("2006-01-02T15:04:05Z")
+		result.FinishedAt = &finishedAt
+	}
+	if export.PurgedAt != nil {
+		purgedAt := export.PurgedAt.UTC().Format("2006-01-02T15:04:05Z")
+		result.PurgedAt = &purgedAt
+	}
+
+	return result
+}
@@ -0,0 +1,54 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/compliance/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// RequestExportCommand asks for the export of the records of a dataset from the day From to the day
+// To, both included, uploaded by the next run of the compliance.run_exports job
+type RequestExportCommand struct {
+	Dataset     string `json:"dataset" binding:"required"`
+	From        string `json:"from" binding:"required,datetime=2006-01-02"`
+	To          string `json:"to" binding:"required,datetime=2006-01-02"`
+	RequestedBy int64  `json:"-"`
+}
+
+// RequestExportHandler handles the exports asked by admins
+type RequestExportHandler struct {
+	exportRepo domain.ExportRepository
+}
+
+// NewRequestExportHandler creates a new request export handler
+func NewRequestExportHandler(exportRepo domain.ExportRepository) *RequestExportHandler {
+	return &RequestExportHandler{
+		exportRepo: exportRepo,
+	}
+}
+
+// Handle creates the pending export
+func (h *RequestExportHandler) Handle(ctx context.Context, cmd RequestExportCommand) (*ExportResult, error) {
+	from, err := time.Parse(time.DateOnly, cmd.From)
+	if err != nil {
+		return nil, domain.ErrInvalidExportPeriod
+	}
+	to, err := time.Parse(time.DateOnly, cmd.To)
+	if err != nil {
+		return nil, domain.ErrInvalidExportPeriod
+	}
+
+	export, err := domain.NewManualExport(domain.Dataset(cmd.Dataset), from, to, cmd.RequestedBy, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.exportRepo.Create(ctx, export); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to create compliance export")
+	}
+
+	return ToExportResult(export), nil
+}
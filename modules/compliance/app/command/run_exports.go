@@ -0,0 +1,198 @@
+package command
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"tixgo/modules/compliance/domain"
+	"tixgo/shared/storage"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ExportSettings configures where exports are stored and how long they and the exported records are kept
+type ExportSettings struct {
+	// Prefix is the key prefix of the exported files
+	Prefix string
+	// Retention is how long the exported files are kept past their period
+	Retention time.Duration
+	// PurgeAfter deletes the exported records from Postgres once they are that old, zero keeps them
+	PurgeAfter time.Duration
+	// StaleAfter is how long an export may run before another worker takes it over
+	StaleAfter time.Duration
+}
+
+// RunExportsHandler uploads the pending exports, then purges the exported records past PurgeAfter
+type RunExportsHandler struct {
+	exportRepo domain.ExportRepository
+	source     domain.RecordSource
+	store      storage.Store
+	settings   ExportSettings
+	now        func() time.Time
+}
+
+// NewRunExportsHandler creates a new run exports handler
+func NewRunExportsHandler(exportRepo domain.ExportRepository, source domain.RecordSource, store storage.Store, settings ExportSettings) *RunExportsHandler {
+	return &RunExportsHandler{
+		exportRepo: exportRepo,
+		source:     source,
+		store:      store,
+		settings:   settings,
+		now:        time.Now,
+	}
+}
+
+// Handle runs the pending exports one after the other until there are none left. A failed export is
+// recorded and the others still run, the run failing with the errors of all.
+func (h *RunExportsHandler) Handle(ctx context.Context) error {
+	var errs []error
+	for ctx.Err() == nil {
+		now := h.now()
+		export, err := h.exportRepo.Claim(ctx, now, now.Add(-h.settings.StaleAfter))
+		if err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		if export == nil {
+			break
+		}
+
+		if err := h.export(ctx, export); err != nil {
+			logger.Error(ctx, "Compliance export failed", logger.F("export_id", export.ID), logger.F("dataset", export.Dataset), logger.F("error", err))
+			export.Fail(h.now(), err)
+			errs = append(errs, fmt.Errorf("export %d: %w", export.ID, err))
+		}
+
+		if err := h.exportRepo.Save(ctx, export); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+	}
+
+	if h.settings.PurgeAfter > 0 {
+		if err := h.purge(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// export writes the records of the export to a gzipped CSV file, then uploads it along with its manifest
+func (h *RunExportsHandler) export(ctx context.Context, export *domain.Export) error {
+	file, err := os.CreateTemp("", "compliance-export-*")
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create export file")
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	hash := sha256.New()
+	gz := gzip.NewWriter(io.MultiWriter(file, hash))
+	writer := csv.NewWriter(gz)
+
+	columns := h.source.Columns(export.Dataset)
+	if err := writer.Write(columns); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to write export")
+	}
+
+	var rows int64
+	err = h.source.Stream(ctx, export.Dataset, export.PeriodStart, export.PeriodEnd, func(record []string) error {
+		rows++
+		return writer.Write(record)
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to write export")
+	}
+	if err := gz.Close(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to compress export")
+	}
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to size export")
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to rewind export")
+	}
+
+	export.Succeed(h.now(), export.ObjectKey(h.settings.Prefix), rows, size, hex.EncodeToString(hash.Sum(nil)), h.settings.Retention)
+
+	metadata := storage.Metadata{
+		ContentType: domain.ExportContentType,
+		Tags:        export.Tags(),
+		Values: map[string]string{
+			"export-id": strconv.FormatInt(export.ID, 10),
+			"rows":      strconv.FormatInt(rows, 10),
+			"sha256":    export.SHA256,
+		},
+	}
+	if err := h.put(ctx, export.Key, file, metadata); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to upload export")
+	}
+
+	manifest, err := json.MarshalIndent(domain.NewManifest(export, columns), "", "  ")
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to encode export manifest")
+	}
+	metadata.ContentType = "application/json"
+	metadata.Values = map[string]string{"export-id": strconv.FormatInt(export.ID, 10)}
+	if err := h.put(ctx, export.ManifestKey(), bytes.NewReader(manifest), metadata); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to upload export manifest")
+	}
+
+	logger.Info(ctx, "Exported compliance records", logger.F("export_id", export.ID), logger.F("dataset", export.Dataset),
+		logger.F("key", export.Key), logger.F("rows", rows), logger.F("bytes", size))
+	return nil
+}
+
+// put stores a file with its metadata when the store keeps metadata, the lifecycle rules of S3 buckets
+// need its tags
+func (h *RunExportsHandler) put(ctx context.Context, key string, r io.Reader, metadata storage.Metadata) error {
+	if store, ok := h.store.(storage.MetadataStore); ok {
+		_, err := store.PutWithMetadata(ctx, key, r, metadata)
+		return err
+	}
+	_, err := h.store.Put(ctx, key, r)
+	return err
+}
+
+// purge deletes the records of the succeeded exports of the periods ended PurgeAfter ago
+func (h *RunExportsHandler) purge(ctx context.Context) error {
+	exports, err := h.exportRepo.ListUnpurged(ctx, h.now().Add(-h.settings.PurgeAfter))
+	if err != nil {
+		return err
+	}
+
+	for _, export := range exports {
+		purged, err := h.source.Purge(ctx, export.Dataset, export.PeriodStart, export.PeriodEnd)
+		if err != nil {
+			return err
+		}
+
+		now := h.now()
+		export.PurgedAt = &now
+		if err := h.exportRepo.Save(ctx, export); err != nil {
+			return err
+		}
+
+		logger.Info(ctx, "Purged exported compliance records", logger.F("export_id", export.ID), logger.F("dataset", export.Dataset), logger.F("purged", purged))
+	}
+
+	return nil
+}
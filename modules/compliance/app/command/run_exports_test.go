@@ -0,0 +1,155 @@
+package command
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"tixgo/modules/compliance/domain"
+	"tixgo/shared/storage"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init(&logger.Config{Output: io.Discard})
+	os.Exit(m.Run())
+}
+
+// memoryExportRepository hands out the pending exports in order
+type memoryExportRepository struct {
+	domain.ExportRepository
+	exports []*domain.Export
+}
+
+func (r *memoryExportRepository) Claim(_ context.Context, now, _ time.Time) (*domain.Export, error) {
+	for _, export := range r.exports {
+		if export.Status == domain.ExportStatusPending {
+			export.Status = domain.ExportStatusRunning
+			export.StartedAt = &now
+			return export, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryExportRepository) Save(context.Context, *domain.Export) error {
+	return nil
+}
+
+func (r *memoryExportRepository) ListUnpurged(_ context.Context, endedBefore time.Time) ([]*domain.Export, error) {
+	var exports []*domain.Export
+	for _, export := range r.exports {
+		if export.Status == domain.ExportStatusSucceeded && export.PurgedAt == nil && !export.PeriodEnd.After(endedBefore) {
+			exports = append(exports, export)
+		}
+	}
+	return exports, nil
+}
+
+// memoryRecordSource serves records by dataset, failing for the datasets of err
+type memoryRecordSource struct {
+	records map[domain.Dataset][][]string
+	err     map[domain.Dataset]error
+	purged  []domain.Dataset
+}
+
+func (s *memoryRecordSource) Columns(domain.Dataset) []string {
+	return []string{"id", "user_id", "created_at"}
+}
+
+func (s *memoryRecordSource) Stream(_ context.Context, dataset domain.Dataset, _, _ time.Time, fn func(record []string) error) error {
+	if err := s.err[dataset]; err != nil {
+		return err
+	}
+	for _, record := range s.records[dataset] {
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *memoryRecordSource) Purge(_ context.Context, dataset domain.Dataset, _, _ time.Time) (int64, error) {
+	s.purged = append(s.purged, dataset)
+	return int64(len(s.records[dataset])), nil
+}
+
+func TestRunExportsHandler(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 16, 6, 5, 0, 0, time.UTC)
+
+	newExport := func(id int64, dataset domain.Dataset) *domain.Export {
+		export, err := domain.NewScheduledExport(dataset, time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		export.ID = id
+		return export
+	}
+
+	repo := &memoryExportRepository{exports: []*domain.Export{
+		newExport(1, domain.DatasetLoginEvents),
+		newExport(2, domain.DatasetUserActivities),
+	}}
+	source := &memoryRecordSource{
+		records: map[domain.Dataset][][]string{
+			domain.DatasetLoginEvents: {{"1", "7", "2026-10-15T08:00:00Z"}, {"2", "8", "2026-10-15T09:30:00Z"}},
+		},
+		err: map[domain.Dataset]error{domain.DatasetUserActivities: errors.New("connection reset")},
+	}
+	store := storage.NewDiskStore(t.TempDir())
+
+	handler := NewRunExportsHandler(repo, source, store, ExportSettings{
+		Prefix:     "compliance",
+		Retention:  domain.DefaultRetention,
+		PurgeAfter: 24 * time.Hour,
+	})
+	handler.now = func() time.Time { return now }
+
+	err := handler.Handle(ctx)
+	require.Error(t, err, "the failed export fails the run")
+	assert.Contains(t, err.Error(), "connection reset")
+
+	exported, failed := repo.exports[0], repo.exports[1]
+	assert.Equal(t, domain.ExportStatusSucceeded, exported.Status)
+	assert.Equal(t, int64(2), exported.Rows)
+	assert.Equal(t, "compliance/login_events/2026/login_events_2026-10-15_2026-10-15_1.csv.gz", exported.Key)
+	assert.Equal(t, domain.ExportStatusFailed, failed.Status)
+	assert.Equal(t, "connection reset", failed.Error)
+
+	file, err := store.Open(ctx, exported.Key)
+	require.NoError(t, err)
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	require.NoError(t, err)
+	records, err := csv.NewReader(gz).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"id", "user_id", "created_at"},
+		{"1", "7", "2026-10-15T08:00:00Z"},
+		{"2", "8", "2026-10-15T09:30:00Z"},
+	}, records)
+
+	manifestFile, err := store.Open(ctx, exported.ManifestKey())
+	require.NoError(t, err)
+	defer manifestFile.Close()
+	var manifest domain.Manifest
+	require.NoError(t, json.NewDecoder(manifestFile).Decode(&manifest))
+	assert.Equal(t, exported.SHA256, manifest.SHA256)
+	assert.Equal(t, int64(2), manifest.Rows)
+	assert.Equal(t, exported.Bytes, manifest.Bytes)
+
+	assert.Empty(t, source.purged, "the day is not over for PurgeAfter yet")
+
+	now = now.Add(24 * time.Hour)
+	require.NoError(t, handler.Handle(ctx))
+	assert.Equal(t, []domain.Dataset{domain.DatasetLoginEvents}, source.purged, "only exported records are purged")
+	require.NotNil(t, exported.PurgedAt)
+}
@@ -0,0 +1,51 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/compliance/domain"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ScheduleExportsHandler adds the export of every dataset for each of the last days, the ones already
+// added being skipped, so the days missed while the worker was down are caught up
+type ScheduleExportsHandler struct {
+	exportRepo domain.ExportRepository
+}
+
+// NewScheduleExportsHandler creates a new schedule exports handler
+func NewScheduleExportsHandler(exportRepo domain.ExportRepository) *ScheduleExportsHandler {
+	return &ScheduleExportsHandler{
+		exportRepo: exportRepo,
+	}
+}
+
+// Handle adds the missing exports of the days ended by now
+func (h *ScheduleExportsHandler) Handle(ctx context.Context, now time.Time) error {
+	scheduled := 0
+	for _, day := range domain.ScheduledExportDays(now) {
+		for _, dataset := range domain.Datasets {
+			export, err := domain.NewScheduledExport(dataset, day)
+			if err != nil {
+				return err
+			}
+
+			err = h.exportRepo.Create(ctx, export)
+			if err == domain.ErrExportAlreadyCreated {
+				continue
+			}
+			if err != nil {
+				return syserr.Wrap(err, syserr.InternalCode, "failed to schedule compliance export")
+			}
+			scheduled++
+		}
+	}
+
+	if scheduled > 0 {
+		logger.Info(ctx, "Scheduled compliance exports", logger.F("exports", scheduled))
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/compliance/app/command"
+	"tixgo/modules/compliance/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// GetExportQuery represents the query to get a compliance export
+type GetExportQuery struct {
+	ID int64
+}
+
+// GetExportHandler handles getting compliance exports
+type GetExportHandler struct {
+	exportRepo domain.ExportRepository
+}
+
+// NewGetExportHandler creates a new get export handler
+func NewGetExportHandler(exportRepo domain.ExportRepository) *GetExportHandler {
+	return &GetExportHandler{
+		exportRepo: exportRepo,
+	}
+}
+
+// Handle executes the get export query
+func (h *GetExportHandler) Handle(ctx context.Context, q GetExportQuery) (*command.ExportResult, error) {
+	export, err := h.exportRepo.GetByID(ctx, q.ID)
+	if err != nil {
+		if err == domain.ErrExportNotFound {
+			return nil, domain.ErrExportNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get compliance export")
+	}
+
+	return command.ToExportResult(export), nil
+}
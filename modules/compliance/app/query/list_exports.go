@@ -0,0 +1,68 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/compliance/app/command"
+	"tixgo/modules/compliance/domain"
+	"tixgo/shared/listing"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// FilterExportsQuery represents the filters for listing compliance exports
+type FilterExportsQuery struct {
+	Dataset *string `json:"dataset" form:"dataset"`
+	Status  *string `json:"status" form:"status"`
+}
+
+// ListExportsHandler handles listing compliance exports
+type ListExportsHandler struct {
+	exportRepo domain.ExportRepository
+}
+
+// NewListExportsHandler creates a new list exports handler
+func NewListExportsHandler(exportRepo domain.ExportRepository) *ListExportsHandler {
+	return &ListExportsHandler{
+		exportRepo: exportRepo,
+	}
+}
+
+// Handle executes the list exports query
+func (h *ListExportsHandler) Handle(ctx context.Context, filters *FilterExportsQuery, paging *listing.Paging) ([]*command.ExportResult, error) {
+	// Ensure paging is not nil (should already be handled in HTTP layer)
+	if paging == nil {
+		paging = &listing.Paging{}
+		paging.Fulfill()
+	}
+
+	domainFilters := domain.ListExportFilters{}
+
+	if filters.Dataset != nil && *filters.Dataset != "" {
+		if !domain.IsValidDataset(*filters.Dataset) {
+			return nil, domain.ErrInvalidDataset
+		}
+		dataset := domain.Dataset(*filters.Dataset)
+		domainFilters.Dataset = &dataset
+	}
+
+	if filters.Status != nil && *filters.Status != "" {
+		if !domain.IsValidExportStatus(*filters.Status) {
+			return nil, domain.ErrInvalidExportStatus
+		}
+		status := domain.ExportStatus(*filters.Status)
+		domainFilters.Status = &status
+	}
+
+	exports, err := h.exportRepo.List(ctx, domainFilters, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list compliance exports")
+	}
+
+	results := make([]*command.ExportResult, len(exports))
+	for i, export := range exports {
+		results[i] = command.ToExportResult(export)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"tixgo/shared/errstack"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// Compliance domain errors
+var (
+	ErrInvalidDataset       = errstack.Sentinel(syserr.InvalidArgumentCode, "invalid dataset, expected user_activities, login_events or notifications")
+	ErrInvalidExportStatus  = errstack.Sentinel(syserr.InvalidArgumentCode, "invalid export status")
+	ErrInvalidExportPeriod  = errstack.Sentinel(syserr.InvalidArgumentCode, "the export period must end after it starts and span 92 days at most")
+	ErrExportPeriodNotOver  = errstack.Sentinel(syserr.InvalidArgumentCode, "the export period must be over")
+	ErrExportNotFound       = errstack.Sentinel(syserr.NotFoundCode, "compliance export not found")
+	ErrExportAlreadyCreated = errstack.Sentinel(syserr.ConflictCode, "the period is already exported by the schedule")
+)
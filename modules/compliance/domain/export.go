@@ -0,0 +1,243 @@
+package domain
+
+import (
+	"fmt"
+	"path"
+	"time"
+)
+
+// Dataset is a kind of records exported for compliance
+type Dataset string
+
+const (
+	// DatasetUserActivities is the audit log of account changes, from user_activities
+	DatasetUserActivities Dataset = "user_activities"
+	// DatasetLoginEvents is the audit log of logins, from login_events
+	DatasetLoginEvents Dataset = "login_events"
+	// DatasetNotifications is the history of the notifications batched into digests, from notification_digest_items
+	DatasetNotifications Dataset = "notifications"
+)
+
+// Datasets are the datasets exported every day, in export order
+var Datasets = []Dataset{DatasetUserActivities, DatasetLoginEvents, DatasetNotifications}
+
+// IsValidDataset checks if the dataset is one of Datasets
+func IsValidDataset(dataset string) bool {
+	for _, d := range Datasets {
+		if string(d) == dataset {
+			return true
+		}
+	}
+	return false
+}
+
+// ExportStatus is where an export is at
+type ExportStatus string
+
+const (
+	ExportStatusPending   ExportStatus = "pending"
+	ExportStatusRunning   ExportStatus = "running"
+	ExportStatusSucceeded ExportStatus = "succeeded"
+	ExportStatusFailed    ExportStatus = "failed"
+)
+
+// IsValidExportStatus checks if the export status is valid
+func IsValidExportStatus(status string) bool {
+	switch ExportStatus(status) {
+	case ExportStatusPending, ExportStatusRunning, ExportStatusSucceeded, ExportStatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// ExportTrigger tells who asked for an export, named like the triggers of scheduled jobs
+type ExportTrigger string
+
+const (
+	ExportTriggerSchedule ExportTrigger = "schedule"
+	ExportTriggerManual   ExportTrigger = "manual"
+)
+
+const (
+	// MaxExportPeriod bounds the period of an export asked by an admin, a file per quarter at most
+	MaxExportPeriod = 92 * 24 * time.Hour
+
+	// ScheduledExportDelay is how long the schedule waits after the end of a day to export it, leaving
+	// the time to the records of the day still in the queues to be written
+	ScheduledExportDelay = 6 * time.Hour
+	// ScheduledExportCatchUp is how many days back the schedule exports the days it missed
+	ScheduledExportCatchUp = 7
+
+	// DefaultRetention is how long exported files are kept when the configuration sets no retention
+	DefaultRetention = 7 * 365 * 24 * time.Hour
+
+	// DefaultPrefix is the key prefix of exported files when the configuration sets none
+	DefaultPrefix = "compliance"
+
+	// ExportFormat is the format of exported files, gzipped CSV with a header row
+	ExportFormat = "csv"
+	// ExportContentType is the content type exported files are stored with
+	ExportContentType = "application/gzip"
+)
+
+// Export is the upload of the records of a dataset from a period to object storage
+type Export struct {
+	ID      int64
+	Dataset Dataset
+	// PeriodStart and PeriodEnd bound the records exported, PeriodEnd excluded
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	Trigger     ExportTrigger
+	RequestedBy *int64
+	Status      ExportStatus
+	// Key is the storage key of the exported file, its manifest is stored under ManifestKey
+	Key         string
+	Rows        int64
+	Bytes       int64
+	SHA256      string
+	RetainUntil *time.Time
+	Error       string
+	CreatedAt   time.Time
+	StartedAt   *time.Time
+	FinishedAt  *time.Time
+	// PurgedAt is when the exported records were deleted from Postgres
+	PurgedAt *time.Time
+}
+
+// NewExport creates a pending export of the records of a dataset from start until end, excluded
+func NewExport(dataset Dataset, start, end time.Time, trigger ExportTrigger, requestedBy *int64) (*Export, error) {
+	if !IsValidDataset(string(dataset)) {
+		return nil, ErrInvalidDataset
+	}
+	if !end.After(start) {
+		return nil, ErrInvalidExportPeriod
+	}
+
+	return &Export{
+		Dataset:     dataset,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Trigger:     trigger,
+		RequestedBy: requestedBy,
+		Status:      ExportStatusPending,
+	}, nil
+}
+
+// NewManualExport creates the export an admin asks for of the days from first to last, both included,
+// which must have ended by now
+func NewManualExport(dataset Dataset, first, last time.Time, requestedBy int64, now time.Time) (*Export, error) {
+	start := Day(first)
+	end := Day(last).AddDate(0, 0, 1)
+	if !end.After(start) || end.Sub(start) > MaxExportPeriod {
+		return nil, ErrInvalidExportPeriod
+	}
+	if end.After(now) {
+		return nil, ErrExportPeriodNotOver
+	}
+
+	return NewExport(dataset, start, end, ExportTriggerManual, &requestedBy)
+}
+
+// NewScheduledExport creates the export of the UTC day starting at day, added by the schedule
+func NewScheduledExport(dataset Dataset, day time.Time) (*Export, error) {
+	start := Day(day)
+	return NewExport(dataset, start, start.AddDate(0, 0, 1), ExportTriggerSchedule, nil)
+}
+
+// ScheduledExportDays returns the days the schedule exports at now, oldest first: the last
+// ScheduledExportCatchUp days that ended ScheduledExportDelay ago at least
+func ScheduledExportDays(now time.Time) []time.Time {
+	last := Day(now.Add(-ScheduledExportDelay)).AddDate(0, 0, -1)
+
+	days := make([]time.Time, ScheduledExportCatchUp)
+	for i := range days {
+		days[i] = last.AddDate(0, 0, i-ScheduledExportCatchUp+1)
+	}
+	return days
+}
+
+// Day returns the start of the UTC day of t, the days exports are cut in
+func Day(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// ObjectKey is where the file of the export is stored under prefix, by dataset and year, e.g.
+// "compliance/login_events/2026/login_events_2026-10-15_2026-10-15_42.csv.gz". The ID keeps the
+// files of the exports of a same period apart.
+func (e *Export) ObjectKey(prefix string) string {
+	last := e.PeriodEnd.Add(-time.Nanosecond)
+	name := fmt.Sprintf("%s_%s_%s_%d.%s.gz", e.Dataset, e.PeriodStart.UTC().Format(time.DateOnly),
+		last.UTC().Format(time.DateOnly), e.ID, ExportFormat)
+	return path.Join(prefix, string(e.Dataset), e.PeriodStart.UTC().Format("2006"), name)
+}
+
+// ManifestKey is where the manifest of the file of the export is stored
+func (e *Export) ManifestKey() string {
+	return e.Key + ".manifest.json"
+}
+
+// Succeed records the file the export was uploaded to, kept for the retention past the period
+func (e *Export) Succeed(now time.Time, key string, rows, bytes int64, sha256 string, retention time.Duration) {
+	retainUntil := e.PeriodEnd.Add(retention)
+	e.Status = ExportStatusSucceeded
+	e.Key = key
+	e.Rows = rows
+	e.Bytes = bytes
+	e.SHA256 = sha256
+	e.RetainUntil = &retainUntil
+	e.FinishedAt = &now
+}
+
+// Fail records why the export failed, admins ask for it again once the cause is fixed
+func (e *Export) Fail(now time.Time, err error) {
+	e.Status = ExportStatusFailed
+	e.Key, e.Rows, e.Bytes, e.SHA256, e.RetainUntil = "", 0, 0, "", nil
+	e.Error = err.Error()
+	e.FinishedAt = &now
+}
+
+// Manifest describes an exported file, stored next to it so the file is understood without the database
+type Manifest struct {
+	ExportID    int64     `json:"export_id"`
+	Dataset     Dataset   `json:"dataset"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	Format      string    `json:"format"`
+	Compression string    `json:"compression"`
+	Columns     []string  `json:"columns"`
+	Rows        int64     `json:"rows"`
+	Bytes       int64     `json:"bytes"`
+	SHA256      string    `json:"sha256"`
+	RetainUntil time.Time `json:"retain_until"`
+	ExportedAt  time.Time `json:"exported_at"`
+}
+
+// NewManifest describes the file of a succeeded export
+func NewManifest(e *Export, columns []string) *Manifest {
+	return &Manifest{
+		ExportID:    e.ID,
+		Dataset:     e.Dataset,
+		PeriodStart: e.PeriodStart,
+		PeriodEnd:   e.PeriodEnd,
+		Format:      ExportFormat,
+		Compression: "gzip",
+		Columns:     columns,
+		Rows:        e.Rows,
+		Bytes:       e.Bytes,
+		SHA256:      e.SHA256,
+		RetainUntil: *e.RetainUntil,
+		ExportedAt:  *e.FinishedAt,
+	}
+}
+
+// Tags are the object tags of the file of a succeeded export, which the lifecycle rules of the bucket
+// filter on to expire it once retain-until has passed
+func (e *Export) Tags() map[string]string {
+	return map[string]string{
+		"dataset":      string(e.Dataset),
+		"retention":    "compliance",
+		"retain-until": e.RetainUntil.UTC().Format(time.DateOnly),
+	}
+}
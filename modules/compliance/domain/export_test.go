@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewManualExport(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC) }
+
+	export, err := NewManualExport(DatasetLoginEvents, day(1), day(15), 3, now)
+	require.NoError(t, err)
+	assert.Equal(t, day(1), export.PeriodStart)
+	assert.Equal(t, day(16), export.PeriodEnd, "the last day is included")
+	assert.Equal(t, ExportTriggerManual, export.Trigger)
+	assert.Equal(t, ExportStatusPending, export.Status)
+
+	_, err = NewManualExport(DatasetLoginEvents, day(1), day(16), 3, now)
+	assert.Equal(t, ErrExportPeriodNotOver, err)
+
+	_, err = NewManualExport(DatasetLoginEvents, day(15), day(1), 3, now)
+	assert.Equal(t, ErrInvalidExportPeriod, err)
+
+	_, err = NewManualExport(DatasetLoginEvents, day(1).AddDate(-1, 0, 0), day(1), 3, now)
+	assert.Equal(t, ErrInvalidExportPeriod, err)
+
+	_, err = NewManualExport("orders", day(1), day(2), 3, now)
+	assert.Equal(t, ErrInvalidDataset, err)
+}
+
+func TestScheduledExportDays(t *testing.T) {
+	days := ScheduledExportDays(time.Date(2026, 10, 16, 5, 0, 0, 0, time.UTC))
+	require.Len(t, days, ScheduledExportCatchUp)
+	assert.Equal(t, time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC), days[len(days)-1],
+		"the 15th is exported once it ended ScheduledExportDelay ago")
+	assert.Equal(t, time.Date(2026, 10, 8, 0, 0, 0, 0, time.UTC), days[0])
+
+	days = ScheduledExportDays(time.Date(2026, 10, 16, 6, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC), days[len(days)-1])
+}
+
+func TestExport_Lifecycle(t *testing.T) {
+	export, err := NewScheduledExport(DatasetUserActivities, time.Date(2026, 10, 15, 13, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	export.ID = 42
+
+	key := export.ObjectKey("compliance")
+	assert.Equal(t, "compliance/user_activities/2026/user_activities_2026-10-15_2026-10-15_42.csv.gz", key)
+
+	now := time.Date(2026, 10, 16, 6, 5, 0, 0, time.UTC)
+	export.Succeed(now, key, 10, 512, "abc", 7*365*24*time.Hour)
+	assert.Equal(t, ExportStatusSucceeded, export.Status)
+	assert.Equal(t, key+".manifest.json", export.ManifestKey())
+	assert.Equal(t, "2033-10-14", export.Tags()["retain-until"])
+
+	export.Fail(now, errors.New("upload failed"))
+	assert.Equal(t, ExportStatusFailed, export.Status)
+	assert.Equal(t, "upload failed", export.Error)
+	assert.Empty(t, export.Key, "a failed export has no file")
+	assert.Nil(t, export.RetainUntil)
+}
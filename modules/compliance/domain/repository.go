@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"tixgo/shared/listing"
+)
+
+// ExportRepository defines the interface for compliance export persistence
+type ExportRepository interface {
+	// Create creates a pending export, failing with ErrExportAlreadyCreated for a period the schedule
+	// exported already
+	Create(ctx context.Context, export *Export) error
+
+	// Claim starts the oldest pending export, or one running since before staleBefore whose worker
+	// died, and returns it; nil when there is none
+	Claim(ctx context.Context, now, staleBefore time.Time) (*Export, error)
+
+	// Save updates the status and the result of an export
+	Save(ctx context.Context, export *Export) error
+
+	// GetByID retrieves an export
+	GetByID(ctx context.Context, id int64) (*Export, error)
+
+	// List retrieves exports with pagination and filters, newest first
+	List(ctx context.Context, filters ListExportFilters, paging *listing.Paging) ([]*Export, error)
+
+	// ListUnpurged retrieves the succeeded exports of periods ended before the given time whose records
+	// are still in Postgres, oldest first
+	ListUnpurged(ctx context.Context, endedBefore time.Time) ([]*Export, error)
+}
+
+// ListExportFilters represents filters for listing exports
+type ListExportFilters struct {
+	Dataset *Dataset
+	Status  *ExportStatus
+}
+
+// RecordSource reads the records of the datasets from Postgres
+type RecordSource interface {
+	// Columns returns the columns of the records of a dataset, in the order Stream gives them
+	Columns(dataset Dataset) []string
+
+	// Stream calls fn with every record of a dataset from start until end, excluded, oldest first,
+	// values formatted as text and empty for nulls. It is bounded by the context of the caller rather
+	// than the query timeout.
+	Stream(ctx context.Context, dataset Dataset, start, end time.Time, fn func(record []string) error) error
+
+	// Purge deletes the records of a dataset from start until end, excluded, and returns how many
+	// were removed. Notifications still waiting for their digest are kept.
+	Purge(ctx context.Context, dataset Dataset, start, end time.Time) (int64, error)
+}
@@ -0,0 +1,110 @@
+package ports
+
+import (
+	"net/http"
+	"strconv"
+
+	"tixgo/components"
+	"tixgo/modules/compliance/adapters"
+	"tixgo/modules/compliance/app/command"
+	"tixgo/modules/compliance/app/query"
+	userDomain "tixgo/modules/user/domain"
+	"tixgo/shared/apiversion"
+	"tixgo/shared/authz"
+	"tixgo/shared/httpresponse"
+	"tixgo/shared/listing"
+	"tixgo/shared/session"
+	"tixgo/shared/strictjson"
+
+	"github.com/duongptryu/gox/context"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterComplianceRoutes(router *apiversion.Group, appCtx components.AppContext) {
+	exportGroup := router.Group("/admin/compliance/exports")
+	{
+		exportGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		exportGroup.Use(authz.RequireUserType(string(userDomain.UserTypeAdmin)))
+		exportGroup.POST("", strictjson.Require(), RequestExport(appCtx))
+		exportGroup.GET("", ListExports(appCtx))
+		exportGroup.GET("/:id", GetExport(appCtx))
+	}
+}
+
+// RequestExport adds an export of the days asked, uploaded by the next run of the compliance.run_exports job
+func RequestExport(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.RequestExportCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		adminID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.RequestedBy = adminID
+
+		handler := command.NewRequestExportHandler(adapters.NewExportPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusAccepted, result)
+	}
+}
+
+func ListExports(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var filters query.FilterExportsQuery
+		if err := c.ShouldBind(&filters); err != nil {
+			c.Error(err)
+			return
+		}
+
+		var paging listing.Paging
+		if err := c.ShouldBind(&paging); err != nil {
+			c.Error(err)
+			return
+		}
+
+		// Apply pagination defaults in HTTP layer
+		paging.Fulfill()
+
+		handler := query.NewListExportsHandler(adapters.NewExportPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), &filters, &paging)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.List(c, result, paging, filters)
+	}
+}
+
+func GetExport(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		handler := query.NewGetExportHandler(adapters.NewExportPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), query.GetExportQuery{ID: id})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
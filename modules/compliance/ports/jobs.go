@@ -0,0 +1,79 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"tixgo/components"
+	"tixgo/config"
+	"tixgo/modules/compliance/adapters"
+	"tixgo/modules/compliance/app/command"
+	"tixgo/modules/compliance/domain"
+	"tixgo/shared/scheduler"
+	"tixgo/shared/storage"
+)
+
+const (
+	JobScheduleExports = "compliance.schedule_exports"
+	JobRunExports      = "compliance.run_exports"
+
+	// runExportsTimeout bounds a run of the exports, an export still running twice as long after it
+	// started was left by a dead worker and is taken over
+	runExportsTimeout = time.Hour
+)
+
+// Jobs returns the jobs of the compliance module
+func Jobs(appCtx components.AppContext, cfg config.Compliance) []scheduler.Job {
+	settings := command.ExportSettings{
+		Prefix:     cfg.Prefix,
+		Retention:  cfg.Retention,
+		PurgeAfter: cfg.PurgeAfter,
+		StaleAfter: 2 * runExportsTimeout,
+	}
+	if settings.Prefix == "" {
+		settings.Prefix = domain.DefaultPrefix
+	}
+	if settings.Retention <= 0 {
+		settings.Retention = domain.DefaultRetention
+	}
+	store := exportStore(appCtx, cfg.S3)
+
+	return []scheduler.Job{
+		{
+			Name:     JobScheduleExports,
+			Schedule: "@hourly",
+			Timeout:  time.Minute,
+			Run: func(ctx context.Context) error {
+				exportRepo := adapters.NewExportPostgresRepository(appCtx.GetDB())
+				return command.NewScheduleExportsHandler(exportRepo).Handle(ctx, time.Now())
+			},
+		},
+		{
+			Name:     JobRunExports,
+			Schedule: "@every 5m",
+			Timeout:  runExportsTimeout,
+			Run: func(ctx context.Context) error {
+				exportRepo := adapters.NewExportPostgresRepository(appCtx.GetDB())
+				source := adapters.NewRecordPostgresSource(appCtx.GetDB())
+				return command.NewRunExportsHandler(exportRepo, source, store, settings).Handle(ctx)
+			},
+		},
+	}
+}
+
+// exportStore is where the exports go: the S3 bucket of the configuration, or the storage of the
+// uploaded files when it has none
+func exportStore(appCtx components.AppContext, cfg config.S3) storage.Store {
+	if cfg.Bucket == "" {
+		return appCtx.GetStorage()
+	}
+
+	return storage.NewS3Store(storage.S3Config{
+		Endpoint:        cfg.Endpoint,
+		Region:          cfg.Region,
+		Bucket:          cfg.Bucket,
+		AccessKeyID:     cfg.AccessKeyID,
+		SecretAccessKey: cfg.SecretAccessKey,
+		PathStyle:       cfg.PathStyle,
+	})
+}
@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"tixgo/modules/compliance/app/command"
+	"tixgo/modules/compliance/app/query"
+	"tixgo/shared/jsonschema"
+	"tixgo/shared/listing"
+)
+
+// Schemas returns the request payloads of the compliance module
+func Schemas() []jsonschema.Payload {
+	return []jsonschema.Payload{
+		{Name: "admin.compliance.exports.create", In: jsonschema.Body, Example: command.RequestExportCommand{}},
+		{Name: "admin.compliance.exports", In: jsonschema.Query, Example: struct {
+			query.FilterExportsQuery
+			listing.Paging
+		}{}},
+	}
+}
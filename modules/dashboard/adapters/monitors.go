@@ -0,0 +1,52 @@
+package adapters
+
+import (
+	"tixgo/modules/dashboard/domain"
+	"tixgo/shared/httpguard"
+	sharedKafka "tixgo/shared/kafka"
+)
+
+// LagMonitorBus implements the BusMonitor interface with the kafka lag monitor, which checks the poison
+// queue along with the lag of the consumers
+type LagMonitorBus struct {
+	monitor *sharedKafka.LagMonitor
+}
+
+// NewLagMonitorBus creates a bus monitor reading the reports of monitor
+func NewLagMonitorBus(monitor *sharedKafka.LagMonitor) *LagMonitorBus {
+	return &LagMonitorBus{monitor: monitor}
+}
+
+// DeadLetters returns the depth of the poison queue at the last check of the monitor
+func (b *LagMonitorBus) DeadLetters() domain.DeadLetters {
+	report := b.monitor.Report()
+
+	deadLetters := domain.DeadLetters{CheckedAt: report.CheckedAt, Error: report.Error}
+	// a failed check keeps the depth of the last one that succeeded, which set the topics
+	if !report.CheckedAt.IsZero() && (report.Error == "" || report.Topics != nil) {
+		deadLetters.Depth = &report.PoisonQueue
+	}
+	return deadLetters
+}
+
+// HTTPErrorRates implements the ErrorRateSource interface with the error rates counted by the HTTP
+// middleware of this instance
+type HTTPErrorRates struct {
+	rates *httpguard.ErrorRates
+}
+
+// NewHTTPErrorRates creates an error rate source reading rates
+func NewHTTPErrorRates(rates *httpguard.ErrorRates) *HTTPErrorRates {
+	return &HTTPErrorRates{rates: rates}
+}
+
+// ErrorRates returns the error rates of the responses of the window up to now
+func (r *HTTPErrorRates) ErrorRates() domain.ErrorRates {
+	report := r.rates.Report()
+	return domain.ErrorRates{
+		Window:          report.Window,
+		Requests:        report.Requests,
+		ClientErrorRate: report.ClientErrorRate,
+		ServerErrorRate: report.ServerErrorRate,
+	}
+}
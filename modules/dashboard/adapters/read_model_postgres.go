@@ -0,0 +1,73 @@
+package adapters
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/dashboard/domain"
+	"tixgo/shared/dbtimeout"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+// ReadModelPostgresRepository implements the ReadModelRepository interface, reading the tables of the
+// user and order modules
+type ReadModelPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewReadModelPostgresRepository creates a new PostgreSQL read model repository
+func NewReadModelPostgresRepository(db *sqlx.DB) *ReadModelPostgresRepository {
+	return &ReadModelPostgresRepository{db: db}
+}
+
+// CountSignups counts the users registered since, whatever their type
+func (r *ReadModelPostgresRepository) CountSignups(ctx context.Context, since time.Time) (int64, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var count int64
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users WHERE created_at >= $1`, since.UTC()).Scan(&count)
+	if err != nil {
+		return 0, syserr.Wrap(err, syserr.InternalCode, "failed to count signups")
+	}
+
+	return count, nil
+}
+
+// ListSales sums the orders confirmed since by currency. Orders refunded since are counted, they were
+// sold; test orders are left out.
+func (r *ReadModelPostgresRepository) ListSales(ctx context.Context, since time.Time) ([]domain.Sales, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT o.currency, COUNT(*), COALESCE(SUM(i.tickets), 0), SUM(o.final_amount)::TEXT
+		FROM orders o
+		LEFT JOIN LATERAL (
+			SELECT SUM(oi.quantity) AS tickets FROM order_items oi WHERE oi.order_id = o.id
+		) i ON TRUE
+		WHERE o.confirmed_at >= $1 AND NOT o.test_mode
+		GROUP BY o.currency
+		ORDER BY o.currency`, since.UTC())
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list sales")
+	}
+	defer rows.Close()
+
+	sales := []domain.Sales{}
+	for rows.Next() {
+		var s domain.Sales
+		if err := rows.Scan(&s.Currency, &s.Orders, &s.Tickets, &s.Revenue); err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan sales")
+		}
+		sales = append(sales, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "error iterating sales rows")
+	}
+
+	return sales, nil
+}
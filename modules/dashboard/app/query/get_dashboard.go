@@ -0,0 +1,137 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/dashboard/domain"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// SalesItem represents the orders confirmed today in a currency
+type SalesItem struct {
+	Currency string `json:"currency"`
+	Orders   int64  `json:"orders"`
+	Tickets  int64  `json:"tickets"`
+	Revenue  string `json:"revenue"`
+}
+
+// DeadLettersItem represents the state of the poison queue
+type DeadLettersItem struct {
+	// Depth is null until the bus was checked once
+	Depth     *int64 `json:"depth"`
+	CheckedAt string `json:"checked_at,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ErrorRatesItem represents the error rates of the API over its window
+type ErrorRatesItem struct {
+	WindowSeconds   int64   `json:"window_seconds"`
+	Requests        int64   `json:"requests"`
+	ClientErrorRate float64 `json:"client_error_rate"`
+	ServerErrorRate float64 `json:"server_error_rate"`
+}
+
+// DashboardResult represents the live metrics of the backoffice dashboard
+type DashboardResult struct {
+	Day     string      `json:"day"`
+	Signups int64       `json:"signups"`
+	Sales   []SalesItem `json:"sales"`
+	// FailedNotifications is null when its counter could not be read
+	FailedNotifications *int64          `json:"failed_notifications"`
+	DeadLetters         DeadLettersItem `json:"dead_letters"`
+	ErrorRates          ErrorRatesItem  `json:"error_rates"`
+	GeneratedAt         string          `json:"generated_at"`
+}
+
+// GetDashboardHandler handles getting the backoffice dashboard
+type GetDashboardHandler struct {
+	readModels          domain.ReadModelRepository
+	failedNotifications domain.FailureCounter
+	bus                 domain.BusMonitor
+	errorRates          domain.ErrorRateSource
+	now                 func() time.Time
+}
+
+// NewGetDashboardHandler creates a new get dashboard handler
+func NewGetDashboardHandler(readModels domain.ReadModelRepository, failedNotifications domain.FailureCounter, bus domain.BusMonitor, errorRates domain.ErrorRateSource) *GetDashboardHandler {
+	return &GetDashboardHandler{
+		readModels:          readModels,
+		failedNotifications: failedNotifications,
+		bus:                 bus,
+		errorRates:          errorRates,
+		now:                 time.Now,
+	}
+}
+
+// Handle gathers the metrics of the current UTC day. The dashboard is read during incidents too, so a
+// counter that cannot be read is left null instead of failing the whole of it.
+func (h *GetDashboardHandler) Handle(ctx context.Context) (*DashboardResult, error) {
+	now := h.now().UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	dashboard := &domain.Dashboard{
+		Day:         day,
+		DeadLetters: h.bus.DeadLetters(),
+		ErrorRates:  h.errorRates.ErrorRates(),
+		GeneratedAt: now,
+	}
+
+	var err error
+	dashboard.Signups, err = h.readModels.CountSignups(ctx, day)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to count signups")
+	}
+
+	dashboard.Sales, err = h.readModels.ListSales(ctx, day)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list sales")
+	}
+
+	failed, err := h.failedNotifications.Get(ctx, day)
+	if err != nil {
+		logger.Warning(ctx, "Failed to read the failed notifications of the day", logger.F("error", err))
+	} else {
+		dashboard.FailedNotifications = &failed
+	}
+
+	return ToDashboardResult(dashboard), nil
+}
+
+// ToDashboardResult converts a dashboard to its result
+func ToDashboardResult(dashboard *domain.Dashboard) *DashboardResult {
+	sales := make([]SalesItem, len(dashboard.Sales))
+	for i, s := range dashboard.Sales {
+		sales[i] = SalesItem{
+			Currency: s.Currency,
+			Orders:   s.Orders,
+			Tickets:  s.Tickets,
+			Revenue:  s.Revenue,
+		}
+	}
+
+	deadLetters := DeadLettersItem{
+		Depth: dashboard.DeadLetters.Depth,
+		Error: dashboard.DeadLetters.Error,
+	}
+	if !dashboard.DeadLetters.CheckedAt.IsZero() {
+		deadLetters.CheckedAt = dashboard.DeadLetters.CheckedAt.UTC().Format("2006-01-02T15:04:05Z")
+	}
+
+	return &DashboardResult{
+		Day:                 dashboard.Day.Format(time.DateOnly),
+		Signups:             dashboard.Signups,
+		Sales:               sales,
+		FailedNotifications: dashboard.FailedNotifications,
+		DeadLetters:         deadLetters,
+		ErrorRates: ErrorRatesItem{
+			WindowSeconds:   int64(dashboard.ErrorRates.Window.Seconds()),
+			Requests:        dashboard.ErrorRates.Requests,
+			ClientErrorRate: dashboard.ErrorRates.ClientErrorRate,
+			ServerErrorRate: dashboard.ErrorRates.ServerErrorRate,
+		},
+		GeneratedAt: dashboard.GeneratedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
@@ -0,0 +1,90 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"tixgo/modules/dashboard/domain"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init(&logger.Config{Output: io.Discard})
+	os.Exit(m.Run())
+}
+
+// fixedReadModels reports the same activity whatever the day, keeping the day asked for
+type fixedReadModels struct {
+	since time.Time
+}
+
+func (r *fixedReadModels) CountSignups(_ context.Context, since time.Time) (int64, error) {
+	r.since = since
+	return 12, nil
+}
+
+func (r *fixedReadModels) ListSales(_ context.Context, _ time.Time) ([]domain.Sales, error) {
+	return []domain.Sales{{Currency: "USD", Orders: 3, Tickets: 7, Revenue: "175.00"}}, nil
+}
+
+type failureCounter struct {
+	count int64
+	err   error
+}
+
+func (c failureCounter) Get(_ context.Context, _ time.Time) (int64, error) {
+	return c.count, c.err
+}
+
+type fixedBus domain.DeadLetters
+
+func (b fixedBus) DeadLetters() domain.DeadLetters {
+	return domain.DeadLetters(b)
+}
+
+type fixedErrorRates domain.ErrorRates
+
+func (r fixedErrorRates) ErrorRates() domain.ErrorRates {
+	return domain.ErrorRates(r)
+}
+
+func TestGetDashboardHandler(t *testing.T) {
+	now := time.Date(2026, 11, 1, 1, 30, 0, 0, time.FixedZone("ICT", 7*60*60))
+	checkedAt := now.Add(-time.Minute)
+	depth := int64(4)
+
+	readModels := &fixedReadModels{}
+	handler := NewGetDashboardHandler(readModels, failureCounter{count: 2},
+		fixedBus{Depth: &depth, CheckedAt: checkedAt},
+		fixedErrorRates{Window: 15 * time.Minute, Requests: 200, ClientErrorRate: 0.1, ServerErrorRate: 0.005})
+	handler.now = func() time.Time { return now }
+
+	result, err := handler.Handle(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC), readModels.since, "the day is the UTC one")
+
+	failed := int64(2)
+	assert.Equal(t, &DashboardResult{
+		Day:                 "2026-10-31",
+		Signups:             12,
+		Sales:               []SalesItem{{Currency: "USD", Orders: 3, Tickets: 7, Revenue: "175.00"}},
+		FailedNotifications: &failed,
+		DeadLetters:         DeadLettersItem{Depth: &depth, CheckedAt: "2026-10-31T18:29:00Z"},
+		ErrorRates:          ErrorRatesItem{WindowSeconds: 900, Requests: 200, ClientErrorRate: 0.1, ServerErrorRate: 0.005},
+		GeneratedAt:         "2026-10-31T18:30:00Z",
+	}, result)
+
+	handler.failedNotifications = failureCounter{err: errors.New("redis unreachable")}
+	handler.bus = fixedBus{}
+	result, err = handler.Handle(context.Background())
+	require.NoError(t, err, "a counter that cannot be read does not fail the dashboard")
+	assert.Nil(t, result.FailedNotifications)
+	assert.Equal(t, DeadLettersItem{}, result.DeadLetters, "the poison queue is unknown until checked")
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// CounterFailedNotifications is the daily count of the notifications their provider failed to send
+const CounterFailedNotifications = "notifications_failed"
+
+// Sales are the orders confirmed in a currency
+type Sales struct {
+	Currency string
+	Orders   int64
+	Tickets  int64
+	// Revenue is the final amount of the orders, in the currency
+	Revenue string
+}
+
+// DeadLetters is the state of the poison queue, where the messages failing every retry of the bus end up
+type DeadLetters struct {
+	// Depth is the number of messages retained, nil until the bus was checked once
+	Depth     *int64
+	CheckedAt time.Time
+	// Error is why the last check failed, Depth then being that of the last one that succeeded
+	Error string
+}
+
+// ErrorRates are the shares of the responses of the API that were errors over a recent window
+type ErrorRates struct {
+	Window          time.Duration
+	Requests        int64
+	ClientErrorRate float64
+	ServerErrorRate float64
+}
+
+// Dashboard is the live state of the platform for the backoffice
+type Dashboard struct {
+	// Day is the UTC day the signups, sales and failed notifications are counted over
+	Day     time.Time
+	Signups int64
+	Sales   []Sales
+	// FailedNotifications is nil when its counter could not be read
+	FailedNotifications *int64
+	DeadLetters         DeadLetters
+	ErrorRates          ErrorRates
+	GeneratedAt         time.Time
+}
+
+// ReadModelRepository reads the activity of a day from the tables of the other modules
+type ReadModelRepository interface {
+	// CountSignups counts the users registered since
+	CountSignups(ctx context.Context, since time.Time) (int64, error)
+
+	// ListSales sums the orders confirmed since by currency, test orders left out
+	ListSales(ctx context.Context, since time.Time) ([]Sales, error)
+}
+
+// FailureCounter counts the failures of a day
+type FailureCounter interface {
+	Get(ctx context.Context, day time.Time) (int64, error)
+}
+
+// BusMonitor reports the state of the poison queue as of its last check
+type BusMonitor interface {
+	DeadLetters() DeadLetters
+}
+
+// ErrorRateSource reports the error rates of the API
+type ErrorRateSource interface {
+	ErrorRates() ErrorRates
+}
@@ -0,0 +1,55 @@
+package ports
+
+import (
+	"net/http"
+
+	"tixgo/components"
+	"tixgo/modules/dashboard/adapters"
+	"tixgo/modules/dashboard/app/query"
+	"tixgo/modules/dashboard/domain"
+	userDomain "tixgo/modules/user/domain"
+	"tixgo/shared/apiversion"
+	"tixgo/shared/authz"
+	"tixgo/shared/dailycount"
+	"tixgo/shared/httpguard"
+	"tixgo/shared/httpresponse"
+	sharedKafka "tixgo/shared/kafka"
+	"tixgo/shared/session"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterDashboardRoutes(router *apiversion.Group, appCtx components.AppContext, lagMonitor *sharedKafka.LagMonitor, errorRates *httpguard.ErrorRates) {
+	dashboardGroup := router.Group("/admin/dashboard")
+	{
+		dashboardGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		dashboardGroup.Use(authz.RequireUserType(string(userDomain.UserTypeAdmin)))
+		dashboardGroup.GET("", GetDashboard(appCtx, lagMonitor, errorRates))
+	}
+}
+
+// FailedNotifications returns the daily count of the notifications their provider failed to send, which
+// the senders of notifications count into
+func FailedNotifications(appCtx components.AppContext) *dailycount.Counter {
+	return dailycount.New(appCtx.GetRedis(), domain.CounterFailedNotifications)
+}
+
+// GetDashboard gathers the live metrics of the platform in one call for the backoffice dashboard
+func GetDashboard(appCtx components.AppContext, lagMonitor *sharedKafka.LagMonitor, errorRates *httpguard.ErrorRates) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		handler := query.NewGetDashboardHandler(
+			adapters.NewReadModelPostgresRepository(appCtx.GetDB()),
+			FailedNotifications(appCtx),
+			adapters.NewLagMonitorBus(lagMonitor),
+			adapters.NewHTTPErrorRates(errorRates),
+		)
+
+		result, err := handler.Handle(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
@@ -0,0 +1,309 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+
+	"tixgo/modules/event/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/pgerr"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+// AllotmentPostgresRepository implements the AllotmentRepository interface using PostgreSQL. The tickets
+// set aside by allotments are counted in quantity_allotted of their category, under the inventory constraint.
+type AllotmentPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewAllotmentPostgresRepository creates a new PostgreSQL allotment repository
+func NewAllotmentPostgresRepository(db *sqlx.DB) *AllotmentPostgresRepository {
+	return &AllotmentPostgresRepository{db: db}
+}
+
+const selectAllotment = `
+	SELECT a.id, c.event_id, a.ticket_category_id, a.name, a.kind, a.quantity, a.quantity_issued, a.created_at, a.updated_at
+	FROM ticket_allotments a
+	JOIN ticket_categories c ON c.id = a.ticket_category_id
+	JOIN events e ON e.id = c.event_id`
+
+// Create sets the tickets of the allotment aside from its category and saves it, atomically
+func (r *AllotmentPostgresRepository) Create(ctx context.Context, allotment *domain.TicketAllotment, organizerID int64) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	if err := checkTicketCategory(ctx, tx, allotment.EventID, allotment.TicketCategoryID, organizerID); err != nil {
+		return err
+	}
+
+	if err := allot(ctx, tx, allotment.TicketCategoryID, allotment.Quantity); err != nil {
+		return err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO ticket_allotments (ticket_category_id, name, kind, quantity)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at`,
+		allotment.TicketCategoryID,
+		allotment.Name,
+		allotment.Kind,
+		allotment.Quantity,
+	).Scan(&allotment.ID, &allotment.CreatedAt, &allotment.UpdatedAt)
+	if err != nil {
+		if pgerr.IsUniqueViolation(err) {
+			return domain.ErrAllotmentNameTaken
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create allotment")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// GetByID retrieves an allotment of an event of the organizer
+func (r *AllotmentPostgresRepository) GetByID(ctx context.Context, id, eventID, organizerID int64) (*domain.TicketAllotment, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	allotment, err := scanAllotment(r.db.QueryRowContext(ctx, selectAllotment+`
+		WHERE a.id = $1 AND c.event_id = $2 AND e.organizer_id = $3`, id, eventID, organizerID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrAllotmentNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get allotment")
+	}
+
+	return allotment, nil
+}
+
+// List retrieves the allotments of an event of the organizer, by category and name
+func (r *AllotmentPostgresRepository) List(ctx context.Context, eventID, organizerID int64) ([]*domain.TicketAllotment, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	if err := checkEventOwner(ctx, r.db, eventID, organizerID); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, selectAllotment+`
+		WHERE c.event_id = $1
+		ORDER BY a.ticket_category_id, a.name`, eventID)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list allotments")
+	}
+	defer rows.Close()
+
+	allotments := []*domain.TicketAllotment{}
+	for rows.Next() {
+		allotment, err := scanAllotment(rows)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan allotment")
+		}
+		allotments = append(allotments, allotment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "error iterating allotment rows")
+	}
+
+	return allotments, nil
+}
+
+// Resize changes the size of the allotment to quantity and moves the difference of tickets of its
+// category between public sale and the allotment. The allotment is locked meanwhile so concurrent
+// resizes and issues apply one after the other.
+func (r *AllotmentPostgresRepository) Resize(ctx context.Context, allotment *domain.TicketAllotment, quantity int) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		SELECT quantity, quantity_issued
+		FROM ticket_allotments
+		WHERE id = $1
+		FOR UPDATE`, allotment.ID,
+	).Scan(&allotment.Quantity, &allotment.Issued)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrAllotmentNotFound
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get allotment")
+	}
+
+	delta, err := allotment.Resize(quantity)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case delta > 0:
+		err = allot(ctx, tx, allotment.TicketCategoryID, delta)
+	case delta < 0:
+		err = releaseAllotted(ctx, tx, allotment.TicketCategoryID, -delta)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE ticket_allotments
+		SET quantity = $2, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at`, allotment.ID, allotment.Quantity,
+	).Scan(&allotment.UpdatedAt)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to resize allotment")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// Delete deletes an allotment and gives the tickets not issued back to sale
+func (r *AllotmentPostgresRepository) Delete(ctx context.Context, allotment *domain.TicketAllotment) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	var remaining int
+	err = tx.QueryRowContext(ctx, `
+		DELETE FROM ticket_allotments
+		WHERE id = $1
+		RETURNING quantity - quantity_issued`, allotment.ID,
+	).Scan(&remaining)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrAllotmentNotFound
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to delete allotment")
+	}
+
+	if remaining > 0 {
+		if err := releaseAllotted(ctx, tx, allotment.TicketCategoryID, remaining); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// allot sets quantity available tickets of a category aside, ErrSoldOut if fewer are available
+func allot(ctx context.Context, tx *sqlx.Tx, ticketCategoryID int64, quantity int) error {
+	result, err := tx.ExecContext(ctx, `
+		UPDATE ticket_categories
+		SET quantity_allotted = quantity_allotted + $2, updated_at = NOW()
+		WHERE id = $1 AND quantity_sold + quantity_reserved + quantity_allotted + $2 <= quantity_available`,
+		ticketCategoryID, quantity)
+	if err != nil {
+		if pgerr.Constraint(err) == pgerr.ConstraintTicketInventory {
+			return domain.ErrSoldOut
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to allot tickets")
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get rows affected")
+	}
+	if updated == 0 {
+		return domain.ErrSoldOut
+	}
+
+	return nil
+}
+
+// releaseAllotted gives quantity allotted tickets of a category back to sale
+func releaseAllotted(ctx context.Context, tx *sqlx.Tx, ticketCategoryID int64, quantity int) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE ticket_categories
+		SET quantity_allotted = quantity_allotted - $2, updated_at = NOW()
+		WHERE id = $1`, ticketCategoryID, quantity)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to release allotted tickets")
+	}
+
+	return nil
+}
+
+// checkEventOwner checks that the event belongs to the organizer, ErrEventNotFound otherwise
+func checkEventOwner(ctx context.Context, db sqlx.QueryerContext, eventID, organizerID int64) error {
+	var eventOrganizerID int64
+	err := db.QueryRowxContext(ctx, `SELECT organizer_id FROM events WHERE id = $1`, eventID).Scan(&eventOrganizerID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrEventNotFound
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get event")
+	}
+	if eventOrganizerID != organizerID {
+		return domain.ErrEventNotFound
+	}
+
+	return nil
+}
+
+// checkTicketCategory checks that the category is one of the event and the event belongs to the organizer
+func checkTicketCategory(ctx context.Context, tx *sqlx.Tx, eventID, ticketCategoryID, organizerID int64) error {
+	if err := checkEventOwner(ctx, tx, eventID, organizerID); err != nil {
+		return err
+	}
+
+	var exists bool
+	err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM ticket_categories WHERE id = $1 AND event_id = $2)`, ticketCategoryID, eventID).Scan(&exists)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get ticket category")
+	}
+	if !exists {
+		return domain.ErrTicketCategoryNotFound
+	}
+
+	return nil
+}
+
+func scanAllotment(row rowScanner) (*domain.TicketAllotment, error) {
+	allotment := &domain.TicketAllotment{}
+	err := row.Scan(
+		&allotment.ID,
+		&allotment.EventID,
+		&allotment.TicketCategoryID,
+		&allotment.Name,
+		&allotment.Kind,
+		&allotment.Quantity,
+		&allotment.Issued,
+		&allotment.CreatedAt,
+		&allotment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return allotment, nil
+}
@@ -0,0 +1,357 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"tixgo/modules/event/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/listing"
+	"tixgo/shared/pgquery"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// AttendeePostgresRepository implements the AttendeeRepository interface using PostgreSQL. Attendees are
+// the tickets of confirmed orders, their assignment and answers are joined by order and ticket.
+type AttendeePostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewAttendeePostgresRepository creates a new PostgreSQL attendee repository
+func NewAttendeePostgresRepository(db *sqlx.DB) *AttendeePostgresRepository {
+	return &AttendeePostgresRepository{db: db}
+}
+
+// attendeesFrom joins the tickets of the orders of an event to their holder, attendee and the
+// accommodations of their order. The name of the holder is the one of the account of the order when it
+// was delivered to it, the one given at the box office otherwise.
+const attendeesFrom = `
+	order_items i
+	JOIN orders o ON o.id = i.order_id
+	JOIN tickets t ON t.id = i.ticket_id
+	JOIN ticket_categories c ON c.id = t.ticket_category_id
+	LEFT JOIN users u ON u.id = o.user_id AND LOWER(u.email) = LOWER(o.email_received)
+	LEFT JOIN box_office_orders b ON b.order_id = o.id
+	LEFT JOIN ticket_attendees ta ON ta.order_id = o.id AND ta.ticket_id = t.id
+	LEFT JOIN order_accommodations acc ON acc.order_id = o.id`
+
+const selectAttendee = `
+	SELECT t.id, t.ticket_number, t.status, c.name, COALESCE(t.seat_section, ''), COALESCE(t.seat_row, ''),
+	       COALESCE(t.seat_number, ''), o.order_number, o.email_received,
+	       COALESCE(NULLIF(TRIM(CONCAT(u.first_name, ' ', u.last_name)), ''), b.customer_name, ''),
+	       COALESCE(ta.name, ''), COALESCE(ta.email, ''),
+	       COALESCE((SELECT jsonb_object_agg(q.label, a.value)
+	                 FROM attendee_answers a
+	                 JOIN event_questions q ON q.id = a.question_id
+	                 WHERE a.order_id = o.id AND a.ticket_id = t.id), '{}'),
+	       COALESCE(acc.access_needs, '{}'), COALESCE(acc.dietary_needs, '{}'), COALESCE(acc.notes, '')
+	FROM` + attendeesFrom
+
+// ticketOrder is the current order of a ticket of an event and what decides whether its holder can
+// still change it
+type ticketOrder struct {
+	orderID      int64
+	organizerID  int64
+	eventTitle   string
+	attendeeMode domain.AttendeeMode
+	// started tells the event started, editClosed that its attendee edit cutoff passed
+	started    bool
+	editClosed bool
+	ticket     domain.IssuedTicket
+}
+
+// lockTicketOrder gets and locks the pending or confirmed order of the user holding a ticket of the
+// event, ErrTicketNotFound if there is none. The lock makes concurrent changes to the attendee and
+// answers of the ticket apply one after the other.
+func lockTicketOrder(ctx context.Context, tx *sqlx.Tx, eventID, ticketID, userID int64) (*ticketOrder, error) {
+	order := &ticketOrder{}
+	err := tx.QueryRowContext(ctx, `
+		SELECT o.id, e.organizer_id, e.title, e.attendee_mode, e.start_date <= NOW(),
+		       e.start_date - make_interval(mins => e.attendee_edit_cutoff_minutes) <= NOW(),
+		       t.id, t.ticket_category_id, c.name, t.ticket_number, COALESCE(t.seat_section, ''),
+		       COALESCE(t.seat_row, ''), COALESCE(t.seat_number, '')
+		FROM order_items i
+		JOIN orders o ON o.id = i.order_id
+		JOIN tickets t ON t.id = i.ticket_id
+		JOIN ticket_categories c ON c.id = t.ticket_category_id
+		JOIN events e ON e.id = c.event_id
+		WHERE i.ticket_id = $1 AND c.event_id = $2 AND o.user_id = $3 AND o.status IN ('pending', 'awaiting_transfer', 'confirmed')
+		ORDER BY o.id DESC
+		LIMIT 1
+		FOR UPDATE OF o`, ticketID, eventID, userID,
+	).Scan(
+		&order.orderID,
+		&order.organizerID,
+		&order.eventTitle,
+		&order.attendeeMode,
+		&order.started,
+		&order.editClosed,
+		&order.ticket.ID,
+		&order.ticket.TicketCategoryID,
+		&order.ticket.TicketCategoryName,
+		&order.ticket.TicketNumber,
+		&order.ticket.SeatSection,
+		&order.ticket.SeatRow,
+		&order.ticket.SeatNumber,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrTicketNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get ticket order")
+	}
+
+	return order, nil
+}
+
+// Assign assigns a ticket of the event to the attendee in its current order, keeping the email it was
+// assigned to before in the attendee
+func (r *AttendeePostgresRepository) Assign(ctx context.Context, attendee *domain.TicketAttendee) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	order, err := lockTicketOrder(ctx, tx, attendee.EventID, attendee.TicketID, attendee.UserID)
+	if err != nil {
+		return err
+	}
+	if order.editClosed {
+		return domain.ErrAttendeeEditClosed
+	}
+
+	err = tx.QueryRowContext(ctx, `SELECT email FROM ticket_attendees WHERE order_id = $1 AND ticket_id = $2`,
+		order.orderID, attendee.TicketID).Scan(&attendee.PreviousEmail)
+	if err != nil && err != sql.ErrNoRows {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get ticket attendee")
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO ticket_attendees (order_id, ticket_id, name, email, assigned_by)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (order_id, ticket_id) DO UPDATE
+		SET name = EXCLUDED.name, email = EXCLUDED.email, assigned_by = EXCLUDED.assigned_by, updated_at = NOW()
+		RETURNING updated_at`,
+		order.orderID, attendee.TicketID, attendee.Name, attendee.Email, attendee.UserID,
+	).Scan(&attendee.AssignedAt)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to assign ticket")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to commit transaction")
+	}
+
+	attendee.OrderID = order.orderID
+	attendee.OrganizerID = order.organizerID
+	attendee.EventTitle = order.eventTitle
+	attendee.Ticket = order.ticket
+	return nil
+}
+
+// Unassign removes the attendee of a ticket of the event in its current order
+func (r *AttendeePostgresRepository) Unassign(ctx context.Context, eventID, ticketID, userID int64) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	order, err := lockTicketOrder(ctx, tx, eventID, ticketID, userID)
+	if err != nil {
+		return err
+	}
+	if order.attendeeMode == domain.AttendeeModeRequired {
+		return domain.ErrAttendeeRequired
+	}
+	if order.editClosed {
+		return domain.ErrAttendeeEditClosed
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM ticket_attendees WHERE order_id = $1 AND ticket_id = $2`, order.orderID, ticketID)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to unassign ticket")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// SaveAnswers replaces the answers given for a ticket of the event in its current order
+func (r *AttendeePostgresRepository) SaveAnswers(ctx context.Context, eventID, ticketID, userID int64, answers []domain.AttendeeAnswer) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	order, err := lockTicketOrder(ctx, tx, eventID, ticketID, userID)
+	if err != nil {
+		return err
+	}
+	if order.started {
+		return domain.ErrAnswersClosed
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM attendee_answers WHERE order_id = $1 AND ticket_id = $2`, order.orderID, ticketID)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to clear answers")
+	}
+
+	if len(answers) > 0 {
+		questionIDs := make([]int64, len(answers))
+		values := make([]string, len(answers))
+		for i, answer := range answers {
+			questionIDs[i] = answer.QuestionID
+			values[i] = answer.Value
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO attendee_answers (order_id, ticket_id, question_id, value, answered_by)
+			SELECT $1, $2, a.question_id, a.value, $5
+			FROM unnest($3::bigint[], $4::text[]) AS a(question_id, value)`,
+			order.orderID, ticketID, pq.Array(questionIDs), pq.Array(values), userID)
+		if err != nil {
+			return syserr.Wrap(err, syserr.InternalCode, "failed to save answers")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// List retrieves the attendees of an event of the organizer with pagination, by ticket number
+func (r *AttendeePostgresRepository) List(ctx context.Context, eventID, organizerID int64, paging *listing.Paging) ([]*domain.Attendee, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	if err := checkEventOwner(ctx, r.db, eventID, organizerID); err != nil {
+		return nil, err
+	}
+
+	filter := attendeeFilter(eventID)
+
+	// Set total in paging
+	err := pgquery.Count(ctx, r.db, attendeesFrom, filter, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to count attendees")
+	}
+
+	pageClause, args := filter.Paged(paging)
+	query := fmt.Sprintf(`%s
+		%s
+		ORDER BY t.ticket_number
+		%s`, selectAttendee, filter.Clause(), pageClause)
+
+	var attendees []*domain.Attendee
+	err = r.queryAttendees(ctx, query, args, func(attendee *domain.Attendee) error {
+		attendees = append(attendees, attendee)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return attendees[:paging.Fetched(len(attendees))], nil
+}
+
+// Stream calls fn for every attendee of an event of the organizer as rows are read from the cursor. It
+// is bounded by the context of the caller rather than the query timeout, since the cursor stays open for
+// as long as fn takes to write the rows out.
+func (r *AttendeePostgresRepository) Stream(ctx context.Context, eventID, organizerID int64, fn func(attendee *domain.Attendee) error) error {
+	if err := checkEventOwner(ctx, r.db, eventID, organizerID); err != nil {
+		return err
+	}
+
+	filter := attendeeFilter(eventID)
+	query := fmt.Sprintf(`%s
+		%s
+		ORDER BY t.ticket_number`, selectAttendee, filter.Clause())
+
+	return r.queryAttendees(ctx, query, filter.Args(), fn)
+}
+
+func (r *AttendeePostgresRepository) queryAttendees(ctx context.Context, query string, args []interface{}, fn func(attendee *domain.Attendee) error) error {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to list attendees")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		attendee, err := scanAttendee(rows)
+		if err != nil {
+			return syserr.Wrap(err, syserr.InternalCode, "failed to scan attendee")
+		}
+		if err := fn(attendee); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "error iterating attendee rows")
+	}
+
+	return nil
+}
+
+// attendeeFilter selects the tickets of the confirmed orders of the event
+func attendeeFilter(eventID int64) *pgquery.Filter {
+	filter := &pgquery.Filter{}
+	filter.Where("c.event_id = ?", eventID)
+	filter.Where("o.status = 'confirmed'")
+	return filter
+}
+
+func scanAttendee(row rowScanner) (*domain.Attendee, error) {
+	attendee := &domain.Attendee{}
+	var answers []byte
+	err := row.Scan(
+		&attendee.TicketID,
+		&attendee.TicketNumber,
+		&attendee.TicketStatus,
+		&attendee.TicketCategoryName,
+		&attendee.SeatSection,
+		&attendee.SeatRow,
+		&attendee.SeatNumber,
+		&attendee.OrderNumber,
+		&attendee.Email,
+		&attendee.Name,
+		&attendee.AttendeeName,
+		&attendee.AttendeeEmail,
+		&answers,
+		pq.Array(&attendee.AccessNeeds),
+		pq.Array(&attendee.DietaryNeeds),
+		&attendee.AccommodationNotes,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(answers, &attendee.Answers); err != nil {
+		return nil, err
+	}
+
+	return attendee, nil
+}
@@ -0,0 +1,69 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"tixgo/modules/event/domain"
+	"tixgo/shared/dbtimeout"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+// AttendeeSettingsPostgresRepository implements the AttendeeSettingsRepository interface using
+// PostgreSQL. The settings are columns of the event.
+type AttendeeSettingsPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewAttendeeSettingsPostgresRepository creates a new PostgreSQL attendee settings repository
+func NewAttendeeSettingsPostgresRepository(db *sqlx.DB) *AttendeeSettingsPostgresRepository {
+	return &AttendeeSettingsPostgresRepository{db: db}
+}
+
+// Get retrieves the attendee settings of an event
+func (r *AttendeeSettingsPostgresRepository) Get(ctx context.Context, eventID int64) (*domain.AttendeeSettings, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	settings := &domain.AttendeeSettings{EventID: eventID}
+	var cutoffMinutes int
+	err := r.db.QueryRowContext(ctx, `SELECT attendee_mode, attendee_edit_cutoff_minutes FROM events WHERE id = $1`, eventID).
+		Scan(&settings.Mode, &cutoffMinutes)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrEventNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get attendee settings")
+	}
+	settings.EditCutoff = time.Duration(cutoffMinutes) * time.Minute
+
+	return settings, nil
+}
+
+// Save stores the attendee settings of an event of the organizer
+func (r *AttendeeSettingsPostgresRepository) Save(ctx context.Context, settings *domain.AttendeeSettings, organizerID int64) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE events
+		SET attendee_mode = $3, attendee_edit_cutoff_minutes = $4, updated_at = NOW()
+		WHERE id = $1 AND organizer_id = $2`,
+		settings.EventID, organizerID, settings.Mode, int(settings.EditCutoff/time.Minute))
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to save attendee settings")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return domain.ErrEventNotFound
+	}
+
+	return nil
+}
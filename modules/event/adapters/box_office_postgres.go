@@ -0,0 +1,185 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+
+	"tixgo/modules/event/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/pgerr"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// BoxOfficePostgresRepository implements the BoxOfficeRepository interface using PostgreSQL. Box office
+// sales are confirmed orders with no payments row, the money was taken at the venue; how it was paid
+// is kept in box_office_orders.
+type BoxOfficePostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewBoxOfficePostgresRepository creates a new PostgreSQL box office repository
+func NewBoxOfficePostgresRepository(db *sqlx.DB) *BoxOfficePostgresRepository {
+	return &BoxOfficePostgresRepository{db: db}
+}
+
+// Sell takes the tickets of the sale and creates the confirmed order holding them at the price of their
+// categories, atomically. Concurrent sales taking the same tickets may deadlock, the losing transaction
+// is run again.
+func (r *BoxOfficePostgresRepository) Sell(ctx context.Context, sale *domain.BoxOfficeSale) error {
+	return pgerr.Retry(ctx, func(ctx context.Context) error {
+		return r.sell(ctx, sale)
+	})
+}
+
+func (r *BoxOfficePostgresRepository) sell(ctx context.Context, sale *domain.BoxOfficeSale) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	var (
+		organizerID int64
+		status      domain.EventStatus
+	)
+	err = tx.QueryRowContext(ctx, `SELECT organizer_id, status, title FROM events WHERE id = $1`, sale.EventID).
+		Scan(&organizerID, &status, &sale.EventTitle)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrEventNotFound
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get event")
+	}
+	if organizerID != sale.SellerID {
+		return domain.ErrEventNotFound
+	}
+	switch status {
+	case domain.EventStatusDraft:
+		return domain.ErrEventNotPublished
+	case domain.EventStatusCancelled, domain.EventStatusCompleted:
+		return domain.ErrEventClosed
+	}
+
+	names, err := ticketCategoryNames(ctx, tx, sale)
+	if err != nil {
+		return err
+	}
+
+	// the sales windows of the categories are not checked, the box office sells up to the doors
+	for _, item := range sale.Items {
+		if err := sell(ctx, tx, item.TicketCategoryID, item.Quantity); err != nil {
+			return err
+		}
+	}
+
+	if sale.OrderNumber, err = newOrderNumber("BOX"); err != nil {
+		return err
+	}
+
+	// the order belongs to the customer when they have an account, to the organizer otherwise; its
+	// amounts are set from the items below, in the currency and the mode of the event
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO orders (user_id, order_number, status, total_amount, final_amount, currency, email_received, confirmed_at,
+		                    test_mode)
+		SELECT COALESCE((SELECT id FROM users WHERE LOWER(email) = NULLIF($1, '')), $2), $3, 'confirmed', 0, 0,
+		       e.currency, $1, NOW(), e.test_mode
+		FROM events e
+		WHERE e.id = $4
+		RETURNING id, confirmed_at`,
+		sale.Email, sale.SellerID, sale.OrderNumber, sale.EventID,
+	).Scan(&sale.OrderID, &sale.SoldAt)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create order")
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO box_office_orders (order_id, payment_method, card_reference, customer_name, sold_by)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), $5)`,
+		sale.OrderID, sale.PaymentMethod, sale.CardReference, sale.CustomerName, sale.SellerID)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to record box office order")
+	}
+
+	sale.Tickets = nil
+	for _, item := range sale.Items {
+		tickets, err := takeTickets(ctx, tx, item.TicketCategoryID, item.Quantity, sale.OrderNumber, len(sale.Tickets))
+		if err != nil {
+			return err
+		}
+		for _, ticket := range tickets {
+			ticket.TicketCategoryName = names[item.TicketCategoryID]
+		}
+		sale.Tickets = append(sale.Tickets, tickets...)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO order_items (order_id, ticket_id, unit_price, quantity, subtotal)
+		SELECT $1, t.id, c.price, 1, c.price
+		FROM tickets t
+		JOIN ticket_categories c ON c.id = t.ticket_category_id
+		WHERE t.id = ANY($2)`, sale.OrderID, pq.Array(ticketIDs(sale.Tickets)))
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create order items")
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE orders
+		SET total_amount = items.total, final_amount = items.total, updated_at = NOW()
+		FROM (SELECT COALESCE(SUM(subtotal), 0) AS total FROM order_items WHERE order_id = $1) items
+		WHERE id = $1
+		RETURNING total_amount::TEXT, COALESCE(currency, 'USD')`, sale.OrderID,
+	).Scan(&sale.TotalAmount, &sale.Currency)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to total order")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to commit box office sale")
+	}
+
+	return nil
+}
+
+// ticketCategoryNames returns the name of the categories of the sale by ID, ErrTicketCategoryNotFound if
+// one is not a category of the event
+func ticketCategoryNames(ctx context.Context, tx *sqlx.Tx, sale *domain.BoxOfficeSale) (map[int64]string, error) {
+	ids := make([]int64, len(sale.Items))
+	for i, item := range sale.Items {
+		ids[i] = item.TicketCategoryID
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT id, name FROM ticket_categories WHERE event_id = $1 AND id = ANY($2)`,
+		sale.EventID, pq.Array(ids))
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get ticket categories")
+	}
+	defer rows.Close()
+
+	names := make(map[int64]string, len(ids))
+	for rows.Next() {
+		var (
+			id   int64
+			name string
+		)
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan ticket category")
+		}
+		names[id] = name
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to iterate ticket categories")
+	}
+
+	if len(names) < len(ids) {
+		return nil, domain.ErrTicketCategoryNotFound
+	}
+
+	return names, nil
+}
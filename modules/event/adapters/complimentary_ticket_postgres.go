@@ -0,0 +1,278 @@
+package adapters
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"tixgo/modules/event/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/pgerr"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// ComplimentaryTicketPostgresRepository implements the ComplimentaryTicketRepository interface using
+// PostgreSQL. Complimentary tickets are issued as a confirmed order at zero amount, so they show in the
+// order history of the recipient and are checked in like any sold ticket.
+type ComplimentaryTicketPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewComplimentaryTicketPostgresRepository creates a new PostgreSQL complimentary ticket repository
+func NewComplimentaryTicketPostgresRepository(db *sqlx.DB) *ComplimentaryTicketPostgresRepository {
+	return &ComplimentaryTicketPostgresRepository{db: db}
+}
+
+// Issue takes the tickets of the issue and creates the confirmed zero-amount order holding them,
+// atomically. Concurrent issues taking the same tickets may deadlock, the losing transaction is run again.
+func (r *ComplimentaryTicketPostgresRepository) Issue(ctx context.Context, issue *domain.ComplimentaryIssue) error {
+	return pgerr.Retry(ctx, func(ctx context.Context) error {
+		return r.issue(ctx, issue)
+	})
+}
+
+func (r *ComplimentaryTicketPostgresRepository) issue(ctx context.Context, issue *domain.ComplimentaryIssue) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	var (
+		organizerID int64
+		status      domain.EventStatus
+	)
+	err = tx.QueryRowContext(ctx, `
+		SELECT e.organizer_id, e.status, e.title, c.name
+		FROM ticket_categories c
+		JOIN events e ON e.id = c.event_id
+		WHERE c.id = $1 AND c.event_id = $2`, issue.TicketCategoryID, issue.EventID,
+	).Scan(&organizerID, &status, &issue.EventTitle, &issue.TicketCategoryName)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			if err := checkEventOwner(ctx, tx, issue.EventID, issue.OrganizerID); err != nil {
+				return err
+			}
+			return domain.ErrTicketCategoryNotFound
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get ticket category")
+	}
+	if organizerID != issue.OrganizerID {
+		return domain.ErrEventNotFound
+	}
+	if status == domain.EventStatusCancelled || status == domain.EventStatusCompleted {
+		return domain.ErrEventClosed
+	}
+
+	if issue.AllotmentID != nil {
+		err = issueAllotted(ctx, tx, issue)
+	} else {
+		err = sell(ctx, tx, issue.TicketCategoryID, issue.Quantity)
+	}
+	if err != nil {
+		return err
+	}
+
+	orderNumber, err := newOrderNumber("CMP")
+	if err != nil {
+		return err
+	}
+	issue.OrderNumber = orderNumber
+
+	// the order belongs to the recipient when they have an account, to the organizer otherwise; it is
+	// delivered to the email either way, and placed in the mode of the event
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO orders (user_id, order_number, status, total_amount, final_amount, currency, email_received, notes,
+		                    confirmed_at, test_mode)
+		SELECT COALESCE((SELECT id FROM users WHERE LOWER(email) = $1), $2), $3, 'confirmed', 0, 0,
+		       e.currency, $1, NULLIF($4, ''), NOW(), e.test_mode
+		FROM events e
+		WHERE e.id = $5
+		RETURNING id, confirmed_at`,
+		issue.Email, issue.OrganizerID, issue.OrderNumber, issue.Note, issue.EventID,
+	).Scan(&issue.OrderID, &issue.IssuedAt)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create order")
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO complimentary_orders (order_id, allotment_id, issued_by, note)
+		VALUES ($1, $2, $3, NULLIF($4, ''))`,
+		issue.OrderID, issue.AllotmentID, issue.OrganizerID, issue.Note)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to record complimentary order")
+	}
+
+	if issue.Tickets, err = takeTickets(ctx, tx, issue.TicketCategoryID, issue.Quantity, issue.OrderNumber, 0); err != nil {
+		return err
+	}
+	for _, ticket := range issue.Tickets {
+		ticket.TicketCategoryName = issue.TicketCategoryName
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO order_items (order_id, ticket_id, unit_price, quantity, subtotal)
+		SELECT $1, unnest($2::bigint[]), 0, 1, 0`, issue.OrderID, pq.Array(ticketIDs(issue.Tickets)))
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create order items")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to commit complimentary tickets")
+	}
+
+	return nil
+}
+
+// issueAllotted takes the tickets of the issue from its allotment, which must be one of its category
+func issueAllotted(ctx context.Context, tx *sqlx.Tx, issue *domain.ComplimentaryIssue) error {
+	result, err := tx.ExecContext(ctx, `
+		UPDATE ticket_allotments
+		SET quantity_issued = quantity_issued + $3, updated_at = NOW()
+		WHERE id = $1 AND ticket_category_id = $2 AND quantity_issued + $3 <= quantity`,
+		*issue.AllotmentID, issue.TicketCategoryID, issue.Quantity)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to issue allotted tickets")
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get rows affected")
+	}
+	if updated == 0 {
+		var exists bool
+		err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM ticket_allotments WHERE id = $1 AND ticket_category_id = $2)`,
+			*issue.AllotmentID, issue.TicketCategoryID).Scan(&exists)
+		if err != nil {
+			return syserr.Wrap(err, syserr.InternalCode, "failed to get allotment")
+		}
+		if !exists {
+			return domain.ErrAllotmentNotFound
+		}
+		return domain.ErrAllotmentExhausted
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE ticket_categories
+		SET quantity_allotted = quantity_allotted - $2, quantity_sold = quantity_sold + $2, updated_at = NOW()
+		WHERE id = $1`, issue.TicketCategoryID, issue.Quantity)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to issue allotted tickets")
+	}
+
+	return nil
+}
+
+// sell counts quantity available tickets of a category as sold, ErrSoldOut if fewer are available
+func sell(ctx context.Context, tx *sqlx.Tx, ticketCategoryID int64, quantity int) error {
+	result, err := tx.ExecContext(ctx, `
+		UPDATE ticket_categories
+		SET quantity_sold = quantity_sold + $2, updated_at = NOW()
+		WHERE id = $1 AND quantity_sold + quantity_reserved + quantity_allotted + $2 <= quantity_available`,
+		ticketCategoryID, quantity)
+	if err != nil {
+		if pgerr.Constraint(err) == pgerr.ConstraintTicketInventory {
+			return domain.ErrSoldOut
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to sell tickets")
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get rows affected")
+	}
+	if updated == 0 {
+		return domain.ErrSoldOut
+	}
+
+	return nil
+}
+
+// takeTickets marks quantity tickets of a category sold for an order. Seated categories give their best
+// available seats, ErrSoldOut if fewer are left; the tickets of general admission categories are
+// created, numbered after the order from offset on.
+func takeTickets(ctx context.Context, tx *sqlx.Tx, ticketCategoryID int64, quantity int, orderNumber string, offset int) ([]*domain.IssuedTicket, error) {
+	var seated bool
+	err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM tickets WHERE ticket_category_id = $1 AND seat_section IS NOT NULL)`,
+		ticketCategoryID).Scan(&seated)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get seat map")
+	}
+
+	var rows *sql.Rows
+	if seated {
+		// reservations past their expiry are available again
+		rows, err = tx.QueryContext(ctx, `
+			UPDATE tickets
+			SET status = 'sold', reserved_at = NULL, reserved_expires_at = NULL, updated_at = NOW()
+			WHERE id IN (
+				SELECT id FROM tickets
+				WHERE ticket_category_id = $1
+				  AND (status = 'available' OR (status = 'reserved' AND reserved_expires_at <= NOW()))
+				ORDER BY seat_section, seat_row, seat_number, id
+				LIMIT $2
+				FOR UPDATE SKIP LOCKED)
+			RETURNING id, ticket_number, seat_section, COALESCE(seat_row, ''), COALESCE(seat_number, '')`,
+			ticketCategoryID, quantity)
+	} else {
+		numbers := make([]string, quantity)
+		for i := range numbers {
+			numbers[i] = fmt.Sprintf("%s-%d", orderNumber, offset+i+1)
+		}
+
+		rows, err = tx.QueryContext(ctx, `
+			INSERT INTO tickets (ticket_category_id, ticket_number, status)
+			SELECT $1, unnest($2::text[]), 'sold'
+			RETURNING id, ticket_number, '', '', ''`,
+			ticketCategoryID, pq.Array(numbers))
+	}
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to take tickets")
+	}
+	defer rows.Close()
+
+	var tickets []*domain.IssuedTicket
+	for rows.Next() {
+		ticket := &domain.IssuedTicket{TicketCategoryID: ticketCategoryID}
+		if err := rows.Scan(&ticket.ID, &ticket.TicketNumber, &ticket.SeatSection, &ticket.SeatRow, &ticket.SeatNumber); err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan ticket")
+		}
+		tickets = append(tickets, ticket)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to iterate tickets")
+	}
+
+	if len(tickets) < quantity {
+		return nil, domain.ErrSoldOut
+	}
+
+	return tickets, nil
+}
+
+func ticketIDs(tickets []*domain.IssuedTicket) []int64 {
+	ids := make([]int64, len(tickets))
+	for i, ticket := range tickets {
+		ids[i] = ticket.ID
+	}
+	return ids
+}
+
+// newOrderNumber generates the number of an order created by the organizer, prefix telling how
+func newOrderNumber(prefix string) (string, error) {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", syserr.Wrap(err, syserr.InternalCode, "failed to generate order number")
+	}
+	return prefix + "-" + strings.ToUpper(hex.EncodeToString(b)), nil
+}
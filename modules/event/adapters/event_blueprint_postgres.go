@@ -0,0 +1,282 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"tixgo/modules/event/domain"
+	"tixgo/shared/dbtimeout"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// EventBlueprintPostgresRepository implements the EventBlueprintRepository interface using PostgreSQL
+type EventBlueprintPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewEventBlueprintPostgresRepository creates a new PostgreSQL event blueprint repository
+func NewEventBlueprintPostgresRepository(db *sqlx.DB) *EventBlueprintPostgresRepository {
+	return &EventBlueprintPostgresRepository{db: db}
+}
+
+// GetBlueprint retrieves the blueprint of an event of the organizer with its start date. The seats of
+// seated categories are taken from their tickets, cancelled ones left out.
+func (r *EventBlueprintPostgresRepository) GetBlueprint(ctx context.Context, eventID, organizerID int64) (*domain.EventBlueprint, time.Time, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var (
+		blueprint                           domain.EventBlueprint
+		startDate                           time.Time
+		endDate, saleStartDate, saleEndDate *time.Time
+		eventOrganizerID                    int64
+	)
+	err := r.db.QueryRowContext(ctx, `
+		SELECT organizer_id, venue_id, title, COALESCE(description, ''), event_type, timezone, currency,
+		       COALESCE(is_recurring, FALSE), COALESCE(max_tickets_per_order, 10), COALESCE(image_url, ''),
+		       COALESCE(terms_and_conditions, ''), age_restriction, start_date, end_date, sale_start_date, sale_end_date
+		FROM events
+		WHERE id = $1`, eventID).Scan(
+		&eventOrganizerID,
+		&blueprint.VenueID,
+		&blueprint.Title,
+		&blueprint.Description,
+		&blueprint.EventType,
+		&blueprint.Timezone,
+		&blueprint.Currency,
+		&blueprint.IsRecurring,
+		&blueprint.MaxTicketsPerOrder,
+		&blueprint.ImageURL,
+		&blueprint.TermsAndConditions,
+		&blueprint.AgeRestriction,
+		&startDate,
+		&endDate,
+		&saleStartDate,
+		&saleEndDate,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, time.Time{}, domain.ErrEventNotFound
+		}
+		return nil, time.Time{}, syserr.Wrap(err, syserr.InternalCode, "failed to get event")
+	}
+	if eventOrganizerID != organizerID {
+		return nil, time.Time{}, domain.ErrEventNotFound
+	}
+
+	if endDate != nil {
+		duration := endDate.Sub(startDate)
+		blueprint.Duration = &duration
+	}
+	blueprint.Sales = domain.NewRelativeWindow(startDate, saleStartDate, saleEndDate)
+
+	categoryIDs, err := r.getTicketCategories(ctx, eventID, startDate, &blueprint)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if err := r.getSeats(ctx, eventID, categoryIDs, &blueprint); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return &blueprint, startDate, nil
+}
+
+// getTicketCategories adds the ticket categories of the event to the blueprint, returning the index of
+// each by ID
+func (r *EventBlueprintPostgresRepository) getTicketCategories(ctx context.Context, eventID int64, startDate time.Time, blueprint *domain.EventBlueprint) (map[int64]int, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, name, COALESCE(description, ''), COALESCE(category_type::text, 'general'), price::text,
+		       quantity_available, COALESCE(max_per_order, 10), COALESCE(is_transferable, TRUE),
+		       COALESCE(is_refundable, TRUE), sale_start_date, sale_end_date
+		FROM ticket_categories
+		WHERE event_id = $1
+		ORDER BY id`, eventID)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get ticket categories")
+	}
+	defer rows.Close()
+
+	categoryIDs := make(map[int64]int)
+	for rows.Next() {
+		var (
+			id                         int64
+			category                   domain.BlueprintTicketCategory
+			saleStartDate, saleEndDate *time.Time
+		)
+		err := rows.Scan(
+			&id,
+			&category.Name,
+			&category.Description,
+			&category.CategoryType,
+			&category.Price,
+			&category.Quantity,
+			&category.MaxPerOrder,
+			&category.IsTransferable,
+			&category.IsRefundable,
+			&saleStartDate,
+			&saleEndDate,
+		)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan ticket category")
+		}
+		category.Sales = domain.NewRelativeWindow(startDate, saleStartDate, saleEndDate)
+
+		categoryIDs[id] = len(blueprint.TicketCategories)
+		blueprint.TicketCategories = append(blueprint.TicketCategories, category)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to iterate ticket categories")
+	}
+
+	return categoryIDs, nil
+}
+
+// getSeats adds the seat map of the event to the ticket categories of the blueprint
+func (r *EventBlueprintPostgresRepository) getSeats(ctx context.Context, eventID int64, categoryIDs map[int64]int, blueprint *domain.EventBlueprint) error {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT t.ticket_category_id, t.seat_section, COALESCE(t.seat_row, ''), COALESCE(t.seat_number, '')
+		FROM tickets t
+		JOIN ticket_categories c ON c.id = t.ticket_category_id
+		WHERE c.event_id = $1 AND t.seat_section IS NOT NULL AND t.status <> 'cancelled'
+		ORDER BY t.seat_section, t.seat_row, t.seat_number`, eventID)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get seat map")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var categoryID int64
+		var seat domain.BlueprintSeat
+		if err := rows.Scan(&categoryID, &seat.Section, &seat.Row, &seat.Number); err != nil {
+			return syserr.Wrap(err, syserr.InternalCode, "failed to scan seat")
+		}
+
+		category := &blueprint.TicketCategories[categoryIDs[categoryID]]
+		category.Seats = append(category.Seats, seat)
+	}
+
+	if err := rows.Err(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to iterate seats")
+	}
+
+	return nil
+}
+
+// CreateDraft creates the draft event with its ticket categories and seats in one transaction. Drafts
+// get no slug, they have no public page until published.
+func (r *EventBlueprintPostgresRepository) CreateDraft(ctx context.Context, draft *domain.EventDraft) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	blueprint := draft.Blueprint
+	saleStartDate, saleEndDate := blueprint.Sales.At(draft.StartDate)
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO events (organizer_id, venue_id, title, description, event_type, status, start_date, end_date,
+		                    timezone, is_recurring, max_tickets_per_order, sale_start_date, sale_end_date,
+		                    image_url, terms_and_conditions, age_restriction, currency, test_mode)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8, $9, $10, $11, $12, $13, NULLIF($14, ''), NULLIF($15, ''), $16,
+		        COALESCE(NULLIF($17, ''), 'USD'), $18)
+		RETURNING id, created_at`,
+		draft.OrganizerID,
+		blueprint.VenueID,
+		blueprint.Title,
+		blueprint.Description,
+		blueprint.EventType,
+		domain.EventStatusDraft,
+		draft.StartDate,
+		draft.EndDate,
+		blueprint.Timezone,
+		blueprint.IsRecurring,
+		blueprint.MaxTicketsPerOrder,
+		saleStartDate,
+		saleEndDate,
+		blueprint.ImageURL,
+		blueprint.TermsAndConditions,
+		blueprint.AgeRestriction,
+		blueprint.Currency,
+		draft.TestMode,
+	).Scan(&draft.ID, &draft.CreatedAt)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create event")
+	}
+
+	for _, category := range blueprint.TicketCategories {
+		if err := createTicketCategory(ctx, tx, draft, category); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// createTicketCategory creates a ticket category of a draft with an available ticket per seat, numbered
+// after the new category so the numbers are unique
+func createTicketCategory(ctx context.Context, tx *sqlx.Tx, draft *domain.EventDraft, category domain.BlueprintTicketCategory) error {
+	saleStartDate, saleEndDate := category.Sales.At(draft.StartDate)
+
+	var categoryID int64
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO ticket_categories (event_id, name, description, price, quantity_available, max_per_order,
+		                               sale_start_date, sale_end_date, is_transferable, is_refundable, category_type)
+		VALUES ($1, $2, NULLIF($3, ''), $4::numeric, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id`,
+		draft.ID,
+		category.Name,
+		category.Description,
+		category.Price,
+		category.Quantity,
+		category.MaxPerOrder,
+		saleStartDate,
+		saleEndDate,
+		category.IsTransferable,
+		category.IsRefundable,
+		category.CategoryType,
+	).Scan(&categoryID)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create ticket category")
+	}
+
+	if len(category.Seats) == 0 {
+		return nil
+	}
+
+	numbers := make([]string, len(category.Seats))
+	sections := make([]string, len(category.Seats))
+	seatRows := make([]string, len(category.Seats))
+	seatNumbers := make([]string, len(category.Seats))
+	for i, seat := range category.Seats {
+		numbers[i] = fmt.Sprintf("C%d-%d", categoryID, i+1)
+		sections[i] = seat.Section
+		seatRows[i] = seat.Row
+		seatNumbers[i] = seat.Number
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO tickets (ticket_category_id, ticket_number, seat_section, seat_row, seat_number, status)
+		SELECT $1, s.ticket_number, s.section, NULLIF(s.seat_row, ''), NULLIF(s.seat_number, ''), 'available'
+		FROM unnest($2::text[], $3::text[], $4::text[], $5::text[]) AS s(ticket_number, section, seat_row, seat_number)`,
+		categoryID, pq.Array(numbers), pq.Array(sections), pq.Array(seatRows), pq.Array(seatNumbers))
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create seats")
+	}
+
+	return nil
+}
@@ -0,0 +1,401 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"tixgo/modules/event/domain"
+	"tixgo/shared/dbtimeout"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// EventCancellationPostgresRepository implements the EventCancellationRepository interface using PostgreSQL
+type EventCancellationPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewEventCancellationPostgresRepository creates a new PostgreSQL event cancellation repository
+func NewEventCancellationPostgresRepository(db *sqlx.DB) *EventCancellationPostgresRepository {
+	return &EventCancellationPostgresRepository{db: db}
+}
+
+// Cancel stops the sales of an event and queues its confirmed orders for refund, atomically
+func (r *EventCancellationPostgresRepository) Cancel(ctx context.Context, cancellation *domain.EventCancellation) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	var organizerID int64
+	var status domain.EventStatus
+	err = tx.QueryRowContext(ctx, `SELECT organizer_id, status FROM events WHERE id = $1 FOR UPDATE`, cancellation.EventID).
+		Scan(&organizerID, &status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrEventNotFound
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get event")
+	}
+	if organizerID != cancellation.CancelledBy {
+		return domain.ErrEventNotFound
+	}
+	if status != domain.EventStatusPublished && status != domain.EventStatusPostponed {
+		return domain.ErrEventNotCancellable
+	}
+
+	statements := []struct {
+		query   string
+		failure string
+	}{
+		{
+			query:   `UPDATE events SET status = 'cancelled', updated_at = NOW() WHERE id = $1`,
+			failure: "failed to cancel event",
+		},
+		{
+			query: `
+				UPDATE tickets t
+				SET status = 'cancelled', reserved_at = NULL, reserved_expires_at = NULL, updated_at = NOW()
+				FROM ticket_categories c
+				WHERE c.id = t.ticket_category_id AND c.event_id = $1 AND t.status IN ('available', 'reserved')`,
+			failure: "failed to withdraw tickets",
+		},
+		{
+			query: `
+				UPDATE orders o
+				SET status = 'cancelled', cancelled_at = NOW(), updated_at = NOW()
+				WHERE o.status IN ('pending', 'awaiting_transfer') AND EXISTS (
+					SELECT 1 FROM order_items i
+					JOIN tickets t ON t.id = i.ticket_id
+					JOIN ticket_categories c ON c.id = t.ticket_category_id
+					WHERE i.order_id = o.id AND c.event_id = $1
+				)`,
+			failure: "failed to cancel pending orders",
+		},
+		{
+			// their seats are withdrawn with the other tickets, closing the bookings keeps the release
+			// job from telling participants their seat went back on sale
+			query:   `UPDATE group_bookings SET status = 'cancelled', updated_at = NOW() WHERE event_id = $1 AND status = 'open'`,
+			failure: "failed to cancel group bookings",
+		},
+	}
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement.query, cancellation.EventID); err != nil {
+			return syserr.Wrap(err, syserr.InternalCode, statement.failure)
+		}
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO event_cancellations (event_id, cancelled_by, reason, status)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`,
+		cancellation.EventID, cancellation.CancelledBy, cancellation.Reason, cancellation.Status,
+	).Scan(&cancellation.ID, &cancellation.CreatedAt)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create event cancellation")
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO event_cancellation_orders (cancellation_id, order_id)
+		SELECT DISTINCT $1::BIGINT, o.id
+		FROM orders o
+		JOIN order_items i ON i.order_id = o.id
+		JOIN tickets t ON t.id = i.ticket_id
+		JOIN ticket_categories c ON c.id = t.ticket_category_id
+		WHERE c.event_id = $2 AND o.status = 'confirmed'`,
+		cancellation.ID, cancellation.EventID)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to queue orders for refund")
+	}
+	queued, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to queue orders for refund")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to commit event cancellation")
+	}
+
+	cancellation.Progress = domain.CancellationProgress{TotalOrders: int(queued)}
+	return nil
+}
+
+// GetByEventID retrieves the cancellation of an event with its progress
+func (r *EventCancellationPostgresRepository) GetByEventID(ctx context.Context, eventID int64) (*domain.EventCancellation, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT c.id, c.event_id, c.cancelled_by, c.reason, c.status, c.created_at, c.completed_at,
+		       COUNT(o.id),
+		       COUNT(o.id) FILTER (WHERE o.status IN ('done', 'failed')),
+		       COUNT(o.id) FILTER (WHERE o.refund_status = 'requested'),
+		       COUNT(o.id) FILTER (WHERE o.refund_status = 'failed'),
+		       COUNT(o.id) FILTER (WHERE o.notification_status = 'sent'),
+		       COUNT(o.id) FILTER (WHERE o.notification_status = 'failed')
+		FROM event_cancellations c
+		LEFT JOIN event_cancellation_orders o ON o.cancellation_id = c.id
+		WHERE c.event_id = $1
+		GROUP BY c.id`
+
+	cancellation := &domain.EventCancellation{}
+	progress := &cancellation.Progress
+	err := r.db.QueryRowContext(ctx, query, eventID).Scan(
+		&cancellation.ID,
+		&cancellation.EventID,
+		&cancellation.CancelledBy,
+		&cancellation.Reason,
+		&cancellation.Status,
+		&cancellation.CreatedAt,
+		&cancellation.CompletedAt,
+		&progress.TotalOrders,
+		&progress.ProcessedOrders,
+		&progress.RefundsRequested,
+		&progress.RefundsFailed,
+		&progress.HoldersNotified,
+		&progress.NotificationsFailed,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrCancellationNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get event cancellation")
+	}
+
+	return cancellation, nil
+}
+
+// ListFailures retrieves up to limit failed orders of a cancellation, the latest first
+func (r *EventCancellationPostgresRepository) ListFailures(ctx context.Context, cancellationID int64, limit int) ([]*domain.CancellationFailure, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT co.order_id, o.order_number, COALESCE(co.refund_error, ''), COALESCE(co.notification_error, ''), co.processed_at
+		FROM event_cancellation_orders co
+		JOIN orders o ON o.id = co.order_id
+		WHERE co.cancellation_id = $1 AND co.status = 'failed'
+		ORDER BY co.processed_at DESC, co.id DESC
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, cancellationID, limit)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list cancellation failures")
+	}
+	defer rows.Close()
+
+	var failures []*domain.CancellationFailure
+	for rows.Next() {
+		var orderID int64
+		var orderNumber, refundError, notificationError string
+		var processedAt time.Time
+		if err := rows.Scan(&orderID, &orderNumber, &refundError, &notificationError, &processedAt); err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan cancellation failure")
+		}
+
+		// an order fails at one step or both, each is reported
+		for _, failure := range []struct {
+			stage domain.CancellationStage
+			err   string
+		}{
+			{domain.CancellationStageRefund, refundError},
+			{domain.CancellationStageNotification, notificationError},
+		} {
+			if failure.err == "" {
+				continue
+			}
+			failures = append(failures, &domain.CancellationFailure{
+				OrderID:     orderID,
+				OrderNumber: orderNumber,
+				Stage:       failure.stage,
+				Error:       failure.err,
+				ProcessedAt: processedAt,
+			})
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to iterate cancellation failures")
+	}
+
+	return failures, nil
+}
+
+// ClaimOrders claims up to limit queued orders and records a pending refund for the completed payment of each
+func (r *EventCancellationPostgresRepository) ClaimOrders(ctx context.Context, limit int, staleAfter time.Duration) ([]*domain.CancellationOrder, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	// the refund of an order claimed again is kept so that it is never issued twice
+	rows, err := tx.QueryContext(ctx, `
+		SELECT co.id, co.cancellation_id, c.event_id, e.title, e.organizer_id, c.reason, co.order_id, o.order_number, o.email_received,
+		       co.refund_id, COALESCE(r.payment_id, p.id), COALESCE(r.amount, p.amount, 0)::TEXT,
+		       COALESCE(p.currency, o.currency, 'USD'), o.test_mode
+		FROM event_cancellation_orders co
+		JOIN event_cancellations c ON c.id = co.cancellation_id
+		JOIN events e ON e.id = c.event_id
+		JOIN orders o ON o.id = co.order_id
+		LEFT JOIN refunds r ON r.id = co.refund_id
+		LEFT JOIN LATERAL (
+			SELECT id, amount, currency FROM payments
+			WHERE order_id = o.id AND status = 'completed'
+			ORDER BY id DESC
+			LIMIT 1
+		) p ON TRUE
+		WHERE c.status = 'running'
+		  AND (co.status = 'pending' OR (co.status = 'processing' AND co.claimed_at <= $2))
+		ORDER BY co.id
+		LIMIT $1
+		FOR UPDATE OF co SKIP LOCKED`, limit, time.Now().Add(-staleAfter))
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to claim cancellation orders")
+	}
+
+	var orders []*domain.CancellationOrder
+	for rows.Next() {
+		order := &domain.CancellationOrder{}
+		err := rows.Scan(
+			&order.ID,
+			&order.CancellationID,
+			&order.EventID,
+			&order.EventTitle,
+			&order.OrganizerID,
+			&order.Reason,
+			&order.OrderID,
+			&order.OrderNumber,
+			&order.Email,
+			&order.RefundID,
+			&order.PaymentID,
+			&order.RefundAmount,
+			&order.Currency,
+			&order.TestMode,
+		)
+		if err != nil {
+			rows.Close()
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan cancellation order")
+		}
+		orders = append(orders, order)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to iterate cancellation orders")
+	}
+
+	ids := make([]int64, len(orders))
+	for i, order := range orders {
+		ids[i] = order.ID
+		if order.RefundID != nil || order.PaymentID == nil {
+			continue
+		}
+
+		var refundID int64
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO refunds (payment_id, amount, reason, status)
+			VALUES ($1, $2, $3, 'pending')
+			RETURNING id`,
+			*order.PaymentID, order.RefundAmount, order.Reason,
+		).Scan(&refundID)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to create refund")
+		}
+
+		if _, err := tx.ExecContext(ctx, `UPDATE event_cancellation_orders SET refund_id = $2 WHERE id = $1`, order.ID, refundID); err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to link refund")
+		}
+		order.RefundID = &refundID
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE event_cancellation_orders SET status = 'processing', claimed_at = NOW()
+		WHERE id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to claim cancellation orders")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to commit claimed cancellation orders")
+	}
+	return orders, nil
+}
+
+// CompleteOrders records the outcome of claimed orders and completes the cancellations left without queued orders
+func (r *EventCancellationPostgresRepository) CompleteOrders(ctx context.Context, results []*domain.CancellationOrderResult) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	for _, result := range results {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE event_cancellation_orders
+			SET status = CASE WHEN $2 = '' AND $3 = '' THEN 'done' ELSE 'failed' END,
+			    refund_status = CASE WHEN $2 = '' THEN 'requested' ELSE 'failed' END,
+			    notification_status = CASE WHEN $3 = '' THEN 'sent' ELSE 'failed' END,
+			    refund_error = NULLIF($2, ''),
+			    notification_error = NULLIF($3, ''),
+			    processed_at = NOW()
+			WHERE id = $1`,
+			result.ID, result.RefundError, result.NotificationError)
+		if err != nil {
+			return syserr.Wrap(err, syserr.InternalCode, "failed to complete cancellation order")
+		}
+
+		if result.RefundError == "" {
+			if !result.TestMode {
+				continue
+			}
+			// the fake payment of a test order is refunded as soon as it is asked for
+			_, err = tx.ExecContext(ctx, `
+				UPDATE refunds SET status = 'completed', gateway_response = 'test mode', processed_at = NOW()
+				WHERE status = 'pending' AND id = (SELECT refund_id FROM event_cancellation_orders WHERE id = $1)`,
+				result.ID)
+			if err != nil {
+				return syserr.Wrap(err, syserr.InternalCode, "failed to complete test refund")
+			}
+			continue
+		}
+		// a refund that was never requested must not be picked up by the payment integration
+		_, err = tx.ExecContext(ctx, `
+			UPDATE refunds SET status = 'failed'
+			WHERE status = 'pending' AND id = (SELECT refund_id FROM event_cancellation_orders WHERE id = $1)`,
+			result.ID)
+		if err != nil {
+			return syserr.Wrap(err, syserr.InternalCode, "failed to fail refund")
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE event_cancellations c
+		SET status = CASE
+		        WHEN EXISTS (SELECT 1 FROM event_cancellation_orders o WHERE o.cancellation_id = c.id AND o.status = 'failed') THEN 'completed_with_failures'
+		        ELSE 'completed'
+		    END,
+		    completed_at = NOW()
+		WHERE c.status = 'running'
+		  AND NOT EXISTS (SELECT 1 FROM event_cancellation_orders o WHERE o.cancellation_id = c.id AND o.status IN ('pending', 'processing'))`)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to complete event cancellations")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to commit cancellation orders")
+	}
+	return nil
+}
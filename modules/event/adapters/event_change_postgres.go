@@ -0,0 +1,148 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"tixgo/modules/event/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/listing"
+	"tixgo/shared/pgquery"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+// EventChangePostgresRepository implements the EventChangeRepository interface using PostgreSQL
+type EventChangePostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewEventChangePostgresRepository creates a new PostgreSQL event change repository
+func NewEventChangePostgresRepository(db *sqlx.DB) *EventChangePostgresRepository {
+	return &EventChangePostgresRepository{db: db}
+}
+
+// Record stores a change, doing nothing if its ChangeID was already recorded or its event is gone
+func (r *EventChangePostgresRepository) Record(ctx context.Context, change *domain.EventConfigurationChanged) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	fields := []domain.FieldChange{}
+	if change.Fields != nil {
+		fields = change.Fields
+	}
+	encoded, err := json.Marshal(fields)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to encode change fields")
+	}
+
+	query := `
+		INSERT INTO event_changes (change_id, event_id, ticket_category_id, action, fields, actor_id, api_key_id, occurred_at)
+		SELECT $1, e.id, NULLIF($3, 0), $4, $5, (SELECT id FROM users WHERE id = $6), NULLIF($7, 0), $8
+		FROM events e
+		WHERE e.id = $2
+		ON CONFLICT (change_id) DO NOTHING`
+
+	_, err = r.db.ExecContext(ctx, query,
+		change.ChangeID,
+		change.EventID,
+		change.TicketCategoryID,
+		change.Action,
+		encoded,
+		change.ActorID,
+		change.APIKeyID,
+		change.OccurredAt,
+	)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to record event change")
+	}
+
+	return nil
+}
+
+// List retrieves a page of the change feed of an event of the organizer, most recent first
+func (r *EventChangePostgresRepository) List(ctx context.Context, filters domain.ChangeFilters, paging *listing.Paging) ([]*domain.EventChange, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	if err := checkEventOwner(ctx, r.db, filters.EventID, filters.OrganizerID); err != nil {
+		return nil, err
+	}
+
+	filter := &pgquery.Filter{}
+	filter.Where("ch.event_id = ?", filters.EventID)
+	if filters.Action != "" {
+		filter.Where("ch.action = ?", filters.Action)
+	}
+	if filters.TicketCategoryID != 0 {
+		filter.Where("ch.ticket_category_id = ?", filters.TicketCategoryID)
+	}
+
+	// Set total in paging
+	err := pgquery.Count(ctx, r.db, "event_changes ch", filter, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to count event changes")
+	}
+
+	pageClause, args := filter.Paged(paging)
+	query := fmt.Sprintf(`
+		SELECT ch.id, ch.event_id, COALESCE(ch.ticket_category_id, 0), COALESCE(c.name, ''), ch.action, ch.fields,
+		       COALESCE(ch.actor_id, 0), COALESCE(u.first_name || ' ' || u.last_name, ''), COALESCE(u.email, ''),
+		       COALESCE(ch.api_key_id, 0), ch.occurred_at
+		FROM event_changes ch
+		LEFT JOIN ticket_categories c ON c.id = ch.ticket_category_id
+		LEFT JOIN users u ON u.id = ch.actor_id
+		%s
+		ORDER BY ch.occurred_at DESC, ch.id DESC
+		%s`, filter.Clause(), pageClause)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list event changes")
+	}
+	defer rows.Close()
+
+	changes := []*domain.EventChange{}
+	for rows.Next() {
+		change, err := scanEventChange(rows)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan event change")
+		}
+		changes = append(changes, change)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "error iterating event change rows")
+	}
+
+	return changes[:paging.Fetched(len(changes))], nil
+}
+
+func scanEventChange(rows *sql.Rows) (*domain.EventChange, error) {
+	change := &domain.EventChange{}
+	var fields []byte
+	err := rows.Scan(
+		&change.ID,
+		&change.EventID,
+		&change.TicketCategoryID,
+		&change.TicketCategoryName,
+		&change.Action,
+		&fields,
+		&change.Actor.ID,
+		&change.Actor.Name,
+		&change.Actor.Email,
+		&change.APIKeyID,
+		&change.OccurredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(fields, &change.Fields); err != nil {
+		return nil, err
+	}
+	return change, nil
+}
@@ -0,0 +1,261 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"tixgo/modules/event/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/listing"
+	"tixgo/shared/pgerr"
+	"tixgo/shared/pgquery"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+// EventPostgresRepository implements the EventRepository interface using PostgreSQL
+type EventPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewEventPostgresRepository creates a new PostgreSQL event repository
+func NewEventPostgresRepository(db *sqlx.DB) *EventPostgresRepository {
+	return &EventPostgresRepository{db: db}
+}
+
+const selectEvent = `
+	SELECT id, organizer_id, venue_id, title, COALESCE(description, ''), event_type, status, COALESCE(slug, ''),
+	       start_date, end_date, timezone, capacity, currency, test_mode, created_at, updated_at
+	FROM events`
+
+// Create stores a new draft event, ErrVenueNotFound if its venue does not exist or is another organizer's
+func (r *EventPostgresRepository) Create(ctx context.Context, event *domain.Event) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	if err := checkVenue(ctx, r.db, event.VenueID, event.OrganizerID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO events (organizer_id, venue_id, title, description, event_type, status, start_date, end_date,
+		                    timezone, capacity, currency, test_mode)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		event.OrganizerID,
+		event.VenueID,
+		event.Title,
+		event.Description,
+		event.EventType,
+		event.Status,
+		event.StartDate,
+		event.EndDate,
+		event.Timezone,
+		event.Capacity,
+		event.Currency,
+		event.TestMode,
+	).Scan(&event.ID, &event.CreatedAt, &event.UpdatedAt)
+	if err != nil {
+		if pgerr.IsForeignKeyViolation(err) {
+			return domain.ErrVenueNotFound
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create event")
+	}
+
+	return nil
+}
+
+// GetByID retrieves an event of the organizer
+func (r *EventPostgresRepository) GetByID(ctx context.Context, id, organizerID int64) (*domain.Event, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	event, err := scanEvent(r.db.QueryRowContext(ctx, selectEvent+`
+		WHERE id = $1 AND organizer_id = $2`, id, organizerID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrEventNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get event")
+	}
+
+	return event, nil
+}
+
+// List retrieves the events of the organizer with pagination, soonest first
+func (r *EventPostgresRepository) List(ctx context.Context, filters domain.EventFilters, paging *listing.Paging) ([]*domain.Event, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	filter := &pgquery.Filter{}
+	filter.Where("organizer_id = ?", filters.OrganizerID)
+	if filters.Status != "" {
+		filter.Where("status = ?", filters.Status)
+	}
+
+	// Set total in paging
+	err := pgquery.Count(ctx, r.db, "events", filter, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to count events")
+	}
+
+	pageClause, args := filter.Paged(paging)
+	query := fmt.Sprintf(`%s
+		%s
+		ORDER BY start_date, id
+		%s`, selectEvent, filter.Clause(), pageClause)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list events")
+	}
+	defer rows.Close()
+
+	var events []*domain.Event
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan event")
+		}
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "error iterating event rows")
+	}
+
+	return events[:paging.Fetched(len(events))], nil
+}
+
+// Update saves the details of an event. The status guard keeps an update from racing a cancellation.
+func (r *EventPostgresRepository) Update(ctx context.Context, event *domain.Event) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	if err := checkVenue(ctx, r.db, event.VenueID, event.OrganizerID); err != nil {
+		return err
+	}
+
+	// the seats bound to ticket types are seats of the venue of the event
+	var bound bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM events e
+			JOIN ticket_categories c ON c.event_id = e.id
+			JOIN tickets t ON t.ticket_category_id = c.id
+			WHERE e.id = $1 AND e.venue_id IS DISTINCT FROM $2 AND t.venue_seat_id IS NOT NULL AND t.status <> 'cancelled')`,
+		event.ID, event.VenueID).Scan(&bound)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to check bound seats")
+	}
+	if bound {
+		return domain.ErrVenueSeatsBound
+	}
+
+	query := `
+		UPDATE events
+		SET venue_id = $3, title = $4, description = NULLIF($5, ''), event_type = $6, start_date = $7,
+		    end_date = $8, timezone = $9, capacity = $10, currency = $11, updated_at = NOW()
+		WHERE id = $1 AND organizer_id = $2 AND status NOT IN ('cancelled', 'completed')
+		RETURNING updated_at`
+
+	err = r.db.QueryRowContext(ctx, query,
+		event.ID,
+		event.OrganizerID,
+		event.VenueID,
+		event.Title,
+		event.Description,
+		event.EventType,
+		event.StartDate,
+		event.EndDate,
+		event.Timezone,
+		event.Capacity,
+		event.Currency,
+	).Scan(&event.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrEventClosed
+		}
+		if pgerr.IsForeignKeyViolation(err) {
+			return domain.ErrVenueNotFound
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to update event")
+	}
+
+	return nil
+}
+
+// checkVenue fails with ErrVenueNotFound unless the venue, if any, is one of the organizer or a shared
+// one. Venues do not change hands, so the check needs no lock.
+func checkVenue(ctx context.Context, db sqlx.QueryerContext, venueID *int64, organizerID int64) error {
+	if venueID == nil {
+		return nil
+	}
+
+	var usable bool
+	err := db.QueryRowxContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM venues WHERE id = $1 AND (organizer_id = $2 OR organizer_id IS NULL))`,
+		*venueID, organizerID).Scan(&usable)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to check venue")
+	}
+	if !usable {
+		return domain.ErrVenueNotFound
+	}
+
+	return nil
+}
+
+// Publish saves the publication of a draft with its slug
+func (r *EventPostgresRepository) Publish(ctx context.Context, event *domain.Event) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		UPDATE events
+		SET status = $3, slug = $4, updated_at = NOW()
+		WHERE id = $1 AND organizer_id = $2 AND status = 'draft'
+		RETURNING updated_at`
+
+	err := r.db.QueryRowContext(ctx, query, event.ID, event.OrganizerID, event.Status, event.Slug).Scan(&event.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrEventNotDraft
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to publish event")
+	}
+
+	return nil
+}
+
+func scanEvent(row rowScanner) (*domain.Event, error) {
+	event := &domain.Event{}
+	err := row.Scan(
+		&event.ID,
+		&event.OrganizerID,
+		&event.VenueID,
+		&event.Title,
+		&event.Description,
+		&event.EventType,
+		&event.Status,
+		&event.Slug,
+		&event.StartDate,
+		&event.EndDate,
+		&event.Timezone,
+		&event.Capacity,
+		&event.Currency,
+		&event.TestMode,
+		&event.CreatedAt,
+		&event.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
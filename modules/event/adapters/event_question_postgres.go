@@ -0,0 +1,209 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+
+	"tixgo/modules/event/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/pgerr"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// EventQuestionPostgresRepository implements the EventQuestionRepository interface using PostgreSQL
+type EventQuestionPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewEventQuestionPostgresRepository creates a new PostgreSQL event question repository
+func NewEventQuestionPostgresRepository(db *sqlx.DB) *EventQuestionPostgresRepository {
+	return &EventQuestionPostgresRepository{db: db}
+}
+
+const selectEventQuestion = `
+	SELECT q.id, q.event_id, q.label, q.kind, q.options, q.required, q.position, q.created_at, q.updated_at
+	FROM event_questions q`
+
+// List retrieves the questions of an event, by position
+func (r *EventQuestionPostgresRepository) List(ctx context.Context, eventID int64) ([]*domain.EventQuestion, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM events WHERE id = $1)`, eventID).Scan(&exists); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get event")
+	}
+	if !exists {
+		return nil, domain.ErrEventNotFound
+	}
+
+	rows, err := r.db.QueryContext(ctx, selectEventQuestion+`
+		WHERE q.event_id = $1
+		ORDER BY q.position, q.id`, eventID)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list event questions")
+	}
+	defer rows.Close()
+
+	questions := []*domain.EventQuestion{}
+	for rows.Next() {
+		question, err := scanEventQuestion(rows)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan event question")
+		}
+		questions = append(questions, question)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "error iterating event question rows")
+	}
+
+	return questions, nil
+}
+
+// Create adds a question to an event of the organizer. The event is locked meanwhile so concurrent
+// creates cannot go past MaxEventQuestions.
+func (r *EventQuestionPostgresRepository) Create(ctx context.Context, question *domain.EventQuestion, organizerID int64) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	var eventOrganizerID int64
+	err = tx.QueryRowContext(ctx, `SELECT organizer_id FROM events WHERE id = $1 FOR UPDATE`, question.EventID).Scan(&eventOrganizerID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrEventNotFound
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get event")
+	}
+	if eventOrganizerID != organizerID {
+		return domain.ErrEventNotFound
+	}
+
+	var count int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM event_questions WHERE event_id = $1`, question.EventID).Scan(&count); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to count event questions")
+	}
+	if count >= domain.MaxEventQuestions {
+		return domain.ErrTooManyQuestions
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO event_questions (event_id, label, kind, options, required, position)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at`,
+		question.EventID,
+		question.Label,
+		question.Kind,
+		pq.Array(question.Options),
+		question.Required,
+		question.Position,
+	).Scan(&question.ID, &question.CreatedAt, &question.UpdatedAt)
+	if err != nil {
+		if pgerr.IsUniqueViolation(err) {
+			return domain.ErrQuestionLabelTaken
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create event question")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// GetByID retrieves a question of an event of the organizer
+func (r *EventQuestionPostgresRepository) GetByID(ctx context.Context, id, eventID, organizerID int64) (*domain.EventQuestion, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	question, err := scanEventQuestion(r.db.QueryRowContext(ctx, selectEventQuestion+`
+		JOIN events e ON e.id = q.event_id
+		WHERE q.id = $1 AND q.event_id = $2 AND e.organizer_id = $3`, id, eventID, organizerID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrQuestionNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get event question")
+	}
+
+	return question, nil
+}
+
+// Update saves the changes to a question
+func (r *EventQuestionPostgresRepository) Update(ctx context.Context, question *domain.EventQuestion) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE event_questions
+		SET label = $2, options = $3, required = $4, position = $5, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at`,
+		question.ID,
+		question.Label,
+		pq.Array(question.Options),
+		question.Required,
+		question.Position,
+	).Scan(&question.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrQuestionNotFound
+		}
+		if pgerr.IsUniqueViolation(err) {
+			return domain.ErrQuestionLabelTaken
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to update event question")
+	}
+
+	return nil
+}
+
+// Delete deletes a question, its answers are deleted with it
+func (r *EventQuestionPostgresRepository) Delete(ctx context.Context, question *domain.EventQuestion) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM event_questions WHERE id = $1`, question.ID)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to delete event question")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return domain.ErrQuestionNotFound
+	}
+
+	return nil
+}
+
+func scanEventQuestion(row rowScanner) (*domain.EventQuestion, error) {
+	question := &domain.EventQuestion{}
+	err := row.Scan(
+		&question.ID,
+		&question.EventID,
+		&question.Label,
+		&question.Kind,
+		pq.Array(&question.Options),
+		&question.Required,
+		&question.Position,
+		&question.CreatedAt,
+		&question.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return question, nil
+}
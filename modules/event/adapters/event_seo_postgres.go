@@ -0,0 +1,174 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+
+	"tixgo/modules/event/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/pgerr"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// EventSEOPostgresRepository implements the EventSEORepository interface using PostgreSQL
+type EventSEOPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewEventSEOPostgresRepository creates a new PostgreSQL event SEO repository
+func NewEventSEOPostgresRepository(db *sqlx.DB) *EventSEOPostgresRepository {
+	return &EventSEOPostgresRepository{db: db}
+}
+
+// GetByEventID retrieves the slug, meta tags and former slugs of an event of the organizer
+func (r *EventSEOPostgresRepository) GetByEventID(ctx context.Context, eventID, organizerID int64) (*domain.EventSEO, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	seo := &domain.EventSEO{}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT e.id, e.organizer_id, e.status, COALESCE(e.slug, ''), COALESCE(e.meta_title, ''),
+		       COALESCE(e.meta_description, ''),
+		       COALESCE(ARRAY(SELECT r.slug FROM event_slug_redirects r WHERE r.event_id = e.id
+		                      ORDER BY r.created_at DESC, r.slug), '{}')
+		FROM events e
+		WHERE e.id = $1 AND e.organizer_id = $2`, eventID, organizerID).Scan(
+		&seo.EventID,
+		&seo.OrganizerID,
+		&seo.Status,
+		&seo.Slug,
+		&seo.MetaTitle,
+		&seo.MetaDescription,
+		pq.Array(&seo.FormerSlugs),
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrEventNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get event SEO")
+	}
+
+	return seo, nil
+}
+
+// Save saves the slug, meta tags and former slugs of an event. A slug is never given to another event:
+// neither the current slug of one nor a former slug still redirecting to it.
+func (r *EventSEOPostgresRepository) Save(ctx context.Context, seo *domain.EventSEO) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	if seo.Slug != "" {
+		var taken bool
+		err = tx.QueryRowContext(ctx, `
+			SELECT EXISTS (SELECT 1 FROM event_slug_redirects WHERE slug = $1 AND event_id <> $2)`,
+			seo.Slug, seo.EventID).Scan(&taken)
+		if err != nil {
+			return syserr.Wrap(err, syserr.InternalCode, "failed to check slug")
+		}
+		if taken {
+			return domain.ErrSlugTaken
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE events
+		SET slug = NULLIF($3, ''), meta_title = NULLIF($4, ''), meta_description = NULLIF($5, ''), updated_at = NOW()
+		WHERE id = $1 AND organizer_id = $2`,
+		seo.EventID, seo.OrganizerID, seo.Slug, seo.MetaTitle, seo.MetaDescription)
+	if err != nil {
+		if pgerr.IsUniqueViolation(err) {
+			return domain.ErrSlugTaken
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to save event SEO")
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get rows affected")
+	}
+	if updated == 0 {
+		return domain.ErrEventNotFound
+	}
+
+	// the former slugs are the ones of this event, which no other event can hold
+	_, err = tx.ExecContext(ctx, `DELETE FROM event_slug_redirects WHERE event_id = $1 AND slug <> ALL(COALESCE($2::TEXT[], '{}'))`,
+		seo.EventID, pq.Array(seo.FormerSlugs))
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to remove former slugs")
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO event_slug_redirects (slug, event_id)
+		SELECT unnest($2::TEXT[]), $1
+		ON CONFLICT (slug) DO NOTHING`, seo.EventID, pq.Array(seo.FormerSlugs))
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to record former slugs")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to commit event SEO")
+	}
+
+	return nil
+}
+
+// GetCanonicalSlug returns the current slug of the public page a former slug redirects to
+func (r *EventSEOPostgresRepository) GetCanonicalSlug(ctx context.Context, formerSlug string) (string, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var slug string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT e.slug
+		FROM event_slug_redirects r
+		JOIN events e ON e.id = r.event_id
+		WHERE r.slug = $1 AND e.status <> $2 AND e.slug IS NOT NULL`, formerSlug, domain.EventStatusDraft).Scan(&slug)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", domain.ErrEventNotFound
+		}
+		return "", syserr.Wrap(err, syserr.InternalCode, "failed to get canonical slug")
+	}
+
+	return slug, nil
+}
+
+// ListSitemapEntries retrieves up to limit published or postponed event pages, last updated first,
+// test mode events left out
+func (r *EventSEOPostgresRepository) ListSitemapEntries(ctx context.Context, limit int) ([]*domain.SitemapEntry, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT slug, COALESCE(updated_at, created_at)
+		FROM events
+		WHERE status IN ($1, $2) AND slug IS NOT NULL AND NOT test_mode
+		ORDER BY COALESCE(updated_at, created_at) DESC, id DESC
+		LIMIT $3`, domain.EventStatusPublished, domain.EventStatusPostponed, limit)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list sitemap entries")
+	}
+	defer rows.Close()
+
+	var entries []*domain.SitemapEntry
+	for rows.Next() {
+		entry := &domain.SitemapEntry{}
+		if err := rows.Scan(&entry.Slug, &entry.UpdatedAt); err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan sitemap entry")
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to iterate sitemap entries")
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,169 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"tixgo/modules/event/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/listing"
+	"tixgo/shared/pgerr"
+	"tixgo/shared/pgquery"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+// EventTemplatePostgresRepository implements the EventTemplateRepository interface using PostgreSQL. The
+// blueprint of a template is stored as JSON, it is only ever read whole.
+type EventTemplatePostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewEventTemplatePostgresRepository creates a new PostgreSQL event template repository
+func NewEventTemplatePostgresRepository(db *sqlx.DB) *EventTemplatePostgresRepository {
+	return &EventTemplatePostgresRepository{db: db}
+}
+
+const selectEventTemplate = `
+	SELECT id, organizer_id, name, source_event_id, blueprint, created_at
+	FROM event_templates`
+
+// Create stores a template, ErrEventTemplateNameTaken if the organizer has one with the same name
+func (r *EventTemplatePostgresRepository) Create(ctx context.Context, template *domain.EventTemplate) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	blueprint, err := json.Marshal(template.Blueprint)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to marshal event blueprint")
+	}
+
+	query := `
+		INSERT INTO event_templates (organizer_id, name, source_event_id, blueprint)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`
+
+	err = r.db.QueryRowContext(ctx, query,
+		template.OrganizerID,
+		template.Name,
+		template.SourceEventID,
+		blueprint,
+	).Scan(&template.ID, &template.CreatedAt)
+	if err != nil {
+		if pgerr.IsUniqueViolation(err) {
+			return domain.ErrEventTemplateNameTaken
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create event template")
+	}
+
+	return nil
+}
+
+// GetByID retrieves a template of the organizer
+func (r *EventTemplatePostgresRepository) GetByID(ctx context.Context, id, organizerID int64) (*domain.EventTemplate, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	template, err := scanEventTemplate(r.db.QueryRowContext(ctx, selectEventTemplate+`
+		WHERE id = $1 AND organizer_id = $2`, id, organizerID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrEventTemplateNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get event template")
+	}
+
+	return template, nil
+}
+
+// List retrieves the templates of the organizer with pagination, by name
+func (r *EventTemplatePostgresRepository) List(ctx context.Context, organizerID int64, paging *listing.Paging) ([]*domain.EventTemplate, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	filter := &pgquery.Filter{}
+	filter.Where("organizer_id = ?", organizerID)
+
+	// Set total in paging
+	err := pgquery.Count(ctx, r.db, "event_templates", filter, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to count event templates")
+	}
+
+	pageClause, args := filter.Paged(paging)
+	query := fmt.Sprintf(`%s
+		%s
+		ORDER BY name, id
+		%s`, selectEventTemplate, filter.Clause(), pageClause)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list event templates")
+	}
+	defer rows.Close()
+
+	var templates []*domain.EventTemplate
+	for rows.Next() {
+		template, err := scanEventTemplate(rows)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan event template")
+		}
+		templates = append(templates, template)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "error iterating event template rows")
+	}
+
+	return templates[:paging.Fetched(len(templates))], nil
+}
+
+// Delete deletes a template of the organizer
+func (r *EventTemplatePostgresRepository) Delete(ctx context.Context, id, organizerID int64) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM event_templates WHERE id = $1 AND organizer_id = $2`, id, organizerID)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to delete event template")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return domain.ErrEventTemplateNotFound
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanEventTemplate(row rowScanner) (*domain.EventTemplate, error) {
+	template := &domain.EventTemplate{}
+	var blueprint []byte
+	err := row.Scan(
+		&template.ID,
+		&template.OrganizerID,
+		&template.Name,
+		&template.SourceEventID,
+		&blueprint,
+		&template.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(blueprint, &template.Blueprint); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
@@ -0,0 +1,330 @@
+package adapters
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	mathrand "math/rand/v2"
+	"strconv"
+	"time"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/redis/go-redis/v9"
+)
+
+// onSaleKeyTTL bounds how long the queue of an on-sale stays in redis after it was last joined
+const onSaleKeyTTL = 24 * time.Hour
+
+// releaseLua returns the tickets held by a token to the stock. ARGV[1] is the key prefix of the event.
+const releaseLua = `
+local function release(prefix, token)
+	local held = redis.call("HGETALL", prefix .. "res:" .. token)
+	for i = 1, #held, 2 do
+		redis.call("INCRBY", prefix .. "stock:" .. held[i], held[i + 1])
+	end
+	redis.call("DEL", prefix .. "res:" .. token)
+end
+`
+
+// joinScript returns the live token of the user, or queues the new token.
+// KEYS: users, owners, waiting, active. ARGV: user id, new token, score, ttl ms.
+var joinScript = redis.NewScript(`
+local token = redis.call("HGET", KEYS[1], ARGV[1])
+if token and (redis.call("ZSCORE", KEYS[3], token) or redis.call("ZSCORE", KEYS[4], token)) then
+	return token
+end
+redis.call("HSET", KEYS[1], ARGV[1], ARGV[2])
+redis.call("HSET", KEYS[2], ARGV[2], ARGV[1])
+redis.call("ZADD", KEYS[3], ARGV[3], ARGV[2])
+for _, key in ipairs(KEYS) do
+	redis.call("PEXPIRE", key, ARGV[4])
+end
+return ARGV[2]`)
+
+// gateScript expires checkout slots, returning their tickets, admits waiting buyers while there is room
+// and returns {position, expiry ms} of a token, position being 0 once admitted and -1 if unknown.
+// KEYS: waiting, active. ARGV: prefix, now ms, capacity, slot ttl ms, token.
+var gateScript = redis.NewScript(releaseLua + `
+local now = tonumber(ARGV[2])
+for _, token in ipairs(redis.call("ZRANGEBYSCORE", KEYS[2], "-inf", now)) do
+	release(ARGV[1], token)
+	redis.call("ZREM", KEYS[2], token)
+end
+
+local free = tonumber(ARGV[3]) - redis.call("ZCARD", KEYS[2])
+if free > 0 then
+	for _, token in ipairs(redis.call("ZRANGE", KEYS[1], 0, free - 1)) do
+		redis.call("ZREM", KEYS[1], token)
+		redis.call("ZADD", KEYS[2], now + tonumber(ARGV[4]), token)
+	end
+end
+
+local expiry = redis.call("ZSCORE", KEYS[2], ARGV[5])
+if expiry then
+	return {0, tonumber(expiry)}
+end
+local rank = redis.call("ZRANK", KEYS[1], ARGV[5])
+if rank then
+	return {rank + 1, 0}
+end
+return {-1, 0}`)
+
+// reserveScript holds tickets for an admitted token and returns the stock left, or a negative status.
+// KEYS: active, stock, reservations of the token. ARGV: token, now ms, category id, quantity, ticket limit.
+var reserveScript = redis.NewScript(`
+local expiry = redis.call("ZSCORE", KEYS[1], ARGV[1])
+if not expiry or tonumber(expiry) <= tonumber(ARGV[2]) then
+	return -1
+end
+local stock = redis.call("GET", KEYS[2])
+if not stock then
+	return -2
+end
+
+local quantity = tonumber(ARGV[4])
+local held = 0
+for _, count in ipairs(redis.call("HVALS", KEYS[3])) do
+	held = held + tonumber(count)
+end
+if held + quantity > tonumber(ARGV[5]) then
+	return -3
+end
+if tonumber(stock) < quantity then
+	return -4
+end
+
+redis.call("DECRBY", KEYS[2], quantity)
+redis.call("HINCRBY", KEYS[3], ARGV[3], quantity)
+return tonumber(stock) - quantity`)
+
+const (
+	reserveNotAdmitted  = -1
+	reserveStockMissing = -2
+	reserveOverLimit    = -3
+	reserveSoldOut      = -4
+)
+
+// leaveScript drops a token and returns the tickets it held.
+// KEYS: waiting, active, owners, users. ARGV: prefix, token.
+var leaveScript = redis.NewScript(releaseLua + `
+release(ARGV[1], ARGV[2])
+redis.call("ZREM", KEYS[1], ARGV[2])
+redis.call("ZREM", KEYS[2], ARGV[2])
+local user = redis.call("HGET", KEYS[3], ARGV[2])
+if user then
+	redis.call("HDEL", KEYS[3], ARGV[2])
+	if redis.call("HGET", KEYS[4], user) == ARGV[2] then
+		redis.call("HDEL", KEYS[4], user)
+	end
+end
+return 1`)
+
+// adjustStockScript adds tickets to a loaded stock, once per change; a stock not loaded yet is loaded with
+// the change from the database. KEYS: stock, change marker. ARGV: delta, ttl ms.
+var adjustStockScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return 0
+end
+if not redis.call("SET", KEYS[2], 1, "NX", "PX", ARGV[2]) then
+	return 0
+end
+redis.call("INCRBY", KEYS[1], ARGV[1])
+return 1`)
+
+// OnSaleQueueRedis implements the OnSaleQueue interface with redis. Every step runs in a lua script
+// so admission and inventory stay consistent under any number of concurrent buyers; the keys of an
+// event share a hash tag to live on one cluster slot.
+type OnSaleQueueRedis struct {
+	client redis.UniversalClient
+	now    func() time.Time
+}
+
+// NewOnSaleQueueRedis creates a new redis on-sale queue
+func NewOnSaleQueueRedis(client redis.UniversalClient) *OnSaleQueueRedis {
+	return &OnSaleQueueRedis{client: client, now: time.Now}
+}
+
+// Join puts the user in the queue, or returns the ticket the user already has
+func (q *OnSaleQueueRedis) Join(ctx context.Context, settings *domain.QueueSettings, userID int64) (*domain.QueueTicket, error) {
+	token, err := newQueueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := newOnSaleKeys(settings.EventID)
+	userKey := strconv.FormatInt(userID, 10)
+	token, err = joinScript.Run(ctx, q.client, []string{keys.users, keys.owners, keys.waiting, keys.active},
+		userKey, token, q.score(settings), onSaleKeyTTL.Milliseconds()).Text()
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to join the queue")
+	}
+
+	return q.Status(ctx, settings, userID, token)
+}
+
+// score orders the queue: the join time, or a random time before the opening for lottery entrants
+func (q *OnSaleQueueRedis) score(settings *domain.QueueSettings) float64 {
+	now := q.now()
+	if settings.Mode == domain.QueueModeLottery && !settings.IsOpen(now) {
+		return mathrand.Float64() * float64(settings.SaleStartDate.UnixMilli())
+	}
+	return float64(now.UnixMilli())
+}
+
+// Status admits the buyers the gate has room for and returns the ticket of token
+func (q *OnSaleQueueRedis) Status(ctx context.Context, settings *domain.QueueSettings, userID int64, token string) (*domain.QueueTicket, error) {
+	keys := newOnSaleKeys(settings.EventID)
+	if err := q.checkOwner(ctx, keys, userID, token); err != nil {
+		return nil, err
+	}
+
+	now := q.now()
+	capacity := settings.MaxConcurrentUsers
+	if !settings.IsOpen(now) {
+		capacity = 0
+	}
+
+	result, err := gateScript.Run(ctx, q.client, []string{keys.waiting, keys.active},
+		keys.prefix, now.UnixMilli(), capacity, settings.ReservationTimeout.Milliseconds(), token).Int64Slice()
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get the queue status")
+	}
+
+	position, expiry := result[0], result[1]
+	if position < 0 {
+		return nil, domain.ErrQueueTicketNotFound
+	}
+
+	ticket := &domain.QueueTicket{Token: token, Position: position, Admitted: position == 0}
+	if ticket.Admitted {
+		expiresAt := time.UnixMilli(expiry).UTC()
+		ticket.ExpiresAt = &expiresAt
+	}
+	return ticket, nil
+}
+
+// Reserve holds quantity tickets of a category for an admitted buyer and returns how many are left
+func (q *OnSaleQueueRedis) Reserve(ctx context.Context, settings *domain.QueueSettings, userID int64, token string, ticketCategoryID int64, quantity int, loadStock func(ctx context.Context) (int, error)) (int, error) {
+	keys := newOnSaleKeys(settings.EventID)
+	if err := q.checkOwner(ctx, keys, userID, token); err != nil {
+		return 0, err
+	}
+
+	categoryKey := strconv.FormatInt(ticketCategoryID, 10)
+	scriptKeys := []string{keys.active, keys.stock(categoryKey), keys.reservations(token)}
+
+	for attempt := 0; ; attempt++ {
+		left, err := reserveScript.Run(ctx, q.client, scriptKeys,
+			token, q.now().UnixMilli(), categoryKey, quantity, settings.MaxTicketsPerOrder).Int()
+		if err != nil {
+			return 0, syserr.Wrap(err, syserr.InternalCode, "failed to reserve tickets")
+		}
+
+		switch left {
+		case reserveNotAdmitted:
+			return 0, domain.ErrNotAdmitted
+		case reserveOverLimit:
+			return 0, domain.ErrTicketLimitExceeded
+		case reserveSoldOut:
+			return 0, domain.ErrSoldOut
+		case reserveStockMissing:
+			if attempt > 0 {
+				return 0, syserr.New(syserr.InternalCode, "failed to load the ticket stock")
+			}
+			// first reservation of the category: seed the stock, the first instance to do so wins
+			stock, err := loadStock(ctx)
+			if err != nil {
+				return 0, err
+			}
+			if err := q.client.SetNX(ctx, keys.stock(categoryKey), stock, onSaleKeyTTL).Err(); err != nil {
+				return 0, syserr.Wrap(err, syserr.InternalCode, "failed to load the ticket stock")
+			}
+			continue
+		}
+
+		return left, nil
+	}
+}
+
+// Leave gives up the place or checkout slot of token along with the tickets it reserved
+func (q *OnSaleQueueRedis) Leave(ctx context.Context, eventID, userID int64, token string) error {
+	keys := newOnSaleKeys(eventID)
+	if err := q.checkOwner(ctx, keys, userID, token); err != nil {
+		return err
+	}
+
+	err := leaveScript.Run(ctx, q.client, []string{keys.waiting, keys.active, keys.owners, keys.users}, keys.prefix, token).Err()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to leave the queue")
+	}
+	return nil
+}
+
+// AdjustStock adds delta tickets to the stock of a category once per changeID. A negative stock makes the
+// category sold out until enough reserved tickets come back.
+func (q *OnSaleQueueRedis) AdjustStock(ctx context.Context, eventID, ticketCategoryID int64, delta int, changeID string) error {
+	keys := newOnSaleKeys(eventID)
+	categoryKey := strconv.FormatInt(ticketCategoryID, 10)
+
+	err := adjustStockScript.Run(ctx, q.client, []string{keys.stock(categoryKey), keys.change(changeID)},
+		delta, onSaleKeyTTL.Milliseconds()).Err()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to adjust the ticket stock")
+	}
+	return nil
+}
+
+// checkOwner makes sure a token is only used by the user it was issued to
+func (q *OnSaleQueueRedis) checkOwner(ctx context.Context, keys onSaleKeys, userID int64, token string) error {
+	owner, err := q.client.HGet(ctx, keys.owners, token).Result()
+	if err == redis.Nil || (err == nil && owner != strconv.FormatInt(userID, 10)) {
+		return domain.ErrQueueTicketNotFound
+	}
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get the queue ticket")
+	}
+	return nil
+}
+
+// onSaleKeys are the redis keys of the queue of an event
+type onSaleKeys struct {
+	prefix  string
+	waiting string
+	active  string
+	owners  string
+	users   string
+}
+
+func newOnSaleKeys(eventID int64) onSaleKeys {
+	prefix := fmt.Sprintf("onsale:{%d}:", eventID)
+	return onSaleKeys{
+		prefix:  prefix,
+		waiting: prefix + "waiting",
+		active:  prefix + "active",
+		owners:  prefix + "owners",
+		users:   prefix + "users",
+	}
+}
+
+func (k onSaleKeys) stock(ticketCategoryID string) string {
+	return k.prefix + "stock:" + ticketCategoryID
+}
+
+func (k onSaleKeys) reservations(token string) string {
+	return k.prefix + "res:" + token
+}
+
+func (k onSaleKeys) change(changeID string) string {
+	return k.prefix + "change:" + changeID
+}
+
+func newQueueToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", syserr.Wrap(err, syserr.InternalCode, "failed to generate queue token")
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,213 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestOnSaleQueue(t *testing.T) (*OnSaleQueueRedis, *time.Time) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	now := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+	queue := NewOnSaleQueueRedis(client)
+	queue.now = func() time.Time { return now }
+	return queue, &now
+}
+
+func newTestQueueSettings(saleStart time.Time, mode domain.QueueMode) *domain.QueueSettings {
+	return &domain.QueueSettings{
+		EventID:              1,
+		Enabled:              true,
+		Mode:                 mode,
+		MaxConcurrentUsers:   2,
+		ReservationTimeout:   10 * time.Minute,
+		EstimatedServiceTime: 5 * time.Minute,
+		MaxTicketsPerOrder:   4,
+		SaleStartDate:        &saleStart,
+	}
+}
+
+func TestOnSaleQueueRedis_Gate(t *testing.T) {
+	ctx := context.Background()
+	queue, now := newTestOnSaleQueue(t)
+	settings := newTestQueueSettings(now.Add(time.Minute), domain.QueueModeLottery)
+
+	tokens := make([]string, 3)
+	for i := range tokens {
+		ticket, err := queue.Join(ctx, settings, int64(i+1))
+		require.NoError(t, err)
+		assert.False(t, ticket.Admitted, "nobody is admitted before the sale opens")
+		tokens[i] = ticket.Token
+	}
+
+	again, err := queue.Join(ctx, settings, 1)
+	require.NoError(t, err)
+	assert.Equal(t, tokens[0], again.Token, "joining again keeps the place")
+
+	*now = now.Add(time.Minute)
+	admitted := 0
+	for i, token := range tokens {
+		ticket, err := queue.Status(ctx, settings, int64(i+1), token)
+		require.NoError(t, err)
+		if ticket.Admitted {
+			admitted++
+			assert.Equal(t, now.Add(10*time.Minute), *ticket.ExpiresAt)
+		} else {
+			assert.Equal(t, int64(1), ticket.Position)
+		}
+	}
+	assert.Equal(t, 2, admitted, "the gate admits MaxConcurrentUsers buyers")
+
+	_, err = queue.Status(ctx, settings, 2, tokens[0])
+	assert.ErrorIs(t, err, domain.ErrQueueTicketNotFound, "tokens only work for their user")
+}
+
+func TestOnSaleQueueRedis_Reserve(t *testing.T) {
+	ctx := context.Background()
+	queue, now := newTestOnSaleQueue(t)
+	settings := newTestQueueSettings(*now, domain.QueueModeFIFO)
+
+	loads := 0
+	loadStock := func(context.Context) (int, error) {
+		loads++
+		return 5, nil
+	}
+
+	first, err := queue.Join(ctx, settings, 1)
+	require.NoError(t, err)
+	second, err := queue.Join(ctx, settings, 2)
+	require.NoError(t, err)
+	require.True(t, first.Admitted)
+	require.True(t, second.Admitted)
+
+	settings.MaxConcurrentUsers = 1
+	third, err := queue.Join(ctx, settings, 3)
+	require.NoError(t, err)
+	require.False(t, third.Admitted)
+
+	_, err = queue.Reserve(ctx, settings, 3, third.Token, 10, 1, loadStock)
+	assert.ErrorIs(t, err, domain.ErrNotAdmitted)
+
+	left, err := queue.Reserve(ctx, settings, 1, first.Token, 10, 3, loadStock)
+	require.NoError(t, err)
+	assert.Equal(t, 2, left)
+
+	_, err = queue.Reserve(ctx, settings, 1, first.Token, 10, 2, loadStock)
+	assert.ErrorIs(t, err, domain.ErrTicketLimitExceeded)
+
+	_, err = queue.Reserve(ctx, settings, 2, second.Token, 10, 3, loadStock)
+	assert.ErrorIs(t, err, domain.ErrSoldOut)
+	assert.Equal(t, 1, loads, "the stock is seeded once")
+
+	// leaving returns the tickets, expiring returns them and frees the slot
+	require.NoError(t, queue.Leave(ctx, settings.EventID, 2, second.Token))
+	*now = now.Add(10 * time.Minute)
+
+	ticket, err := queue.Status(ctx, settings, 3, third.Token)
+	require.NoError(t, err)
+	assert.True(t, ticket.Admitted)
+
+	left, err = queue.Reserve(ctx, settings, 3, third.Token, 10, 4, loadStock)
+	require.NoError(t, err)
+	assert.Equal(t, 1, left)
+
+	_, err = queue.Status(ctx, settings, 1, first.Token)
+	assert.ErrorIs(t, err, domain.ErrQueueTicketNotFound)
+}
+
+// TestOnSaleQueueRedis_ReserveConcurrently hammers a category with concurrent reservations
+// and checks that exactly its stock is handed out
+func TestOnSaleQueueRedis_ReserveConcurrently(t *testing.T) {
+	ctx := context.Background()
+	queue, now := newTestOnSaleQueue(t)
+
+	const buyers, stock = 50, 60
+	settings := newTestQueueSettings(*now, domain.QueueModeFIFO)
+	settings.MaxConcurrentUsers = buyers
+
+	tokens := make([]string, buyers)
+	for i := range tokens {
+		ticket, err := queue.Join(ctx, settings, int64(i+1))
+		require.NoError(t, err)
+		require.True(t, ticket.Admitted)
+		tokens[i] = ticket.Token
+	}
+
+	loadStock := func(context.Context) (int, error) { return stock, nil }
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		reserved int
+		soldOut  int
+	)
+	for i, token := range tokens {
+		wg.Add(1)
+		go func(userID int64, token string, quantity int) {
+			defer wg.Done()
+			_, err := queue.Reserve(ctx, settings, userID, token, 10, quantity, loadStock)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				reserved += quantity
+			case errors.Is(err, domain.ErrSoldOut):
+				soldOut++
+			default:
+				t.Errorf("unexpected reservation error: %v", err)
+			}
+		}(int64(i+1), token, 1+i%settings.MaxTicketsPerOrder)
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, reserved, stock, "never more tickets than the stock")
+	assert.Positive(t, soldOut, "the demand exceeds the stock")
+
+	// the tickets left are exactly the stock minus the reservations
+	left, err := queue.client.Get(ctx, newOnSaleKeys(settings.EventID).stock("10")).Int()
+	require.NoError(t, err)
+	assert.Equal(t, stock-reserved, left)
+}
+
+func TestOnSaleQueueRedis_AdjustStock(t *testing.T) {
+	ctx := context.Background()
+	queue, now := newTestOnSaleQueue(t)
+	settings := newTestQueueSettings(*now, domain.QueueModeFIFO)
+	settings.MaxTicketsPerOrder = 10
+
+	loadStock := func(context.Context) (int, error) { return 5, nil }
+
+	// a stock not loaded yet is loaded with the change
+	require.NoError(t, queue.AdjustStock(ctx, settings.EventID, 10, 3, "change-1"))
+
+	ticket, err := queue.Join(ctx, settings, 1)
+	require.NoError(t, err)
+	left, err := queue.Reserve(ctx, settings, 1, ticket.Token, 10, 1, loadStock)
+	require.NoError(t, err)
+	assert.Equal(t, 4, left)
+
+	require.NoError(t, queue.AdjustStock(ctx, settings.EventID, 10, 2, "change-2"))
+	require.NoError(t, queue.AdjustStock(ctx, settings.EventID, 10, 2, "change-2"), "a redelivered change applies once")
+
+	left, err = queue.Reserve(ctx, settings, 1, ticket.Token, 10, 1, loadStock)
+	require.NoError(t, err)
+	assert.Equal(t, 5, left)
+
+	require.NoError(t, queue.AdjustStock(ctx, settings.EventID, 10, -6, "change-3"))
+	_, err = queue.Reserve(ctx, settings, 1, ticket.Token, 10, 1, loadStock)
+	assert.ErrorIs(t, err, domain.ErrSoldOut)
+}
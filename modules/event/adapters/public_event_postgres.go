@@ -0,0 +1,297 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"tixgo/modules/event/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/listing"
+	"tixgo/shared/pgquery"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+// PublicEventPostgresRepository implements the PublicEventRepository interface using PostgreSQL
+type PublicEventPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewPublicEventPostgresRepository creates a new PostgreSQL public event repository
+func NewPublicEventPostgresRepository(db *sqlx.DB) *PublicEventPostgresRepository {
+	return &PublicEventPostgresRepository{db: db}
+}
+
+// GetPublicBySlug retrieves the public event page of a non draft event by slug
+func (r *PublicEventPostgresRepository) GetPublicBySlug(ctx context.Context, slug string) (*domain.PublicEvent, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT e.id, e.slug, e.title, COALESCE(e.description, ''), COALESCE(e.meta_title, ''),
+		       COALESCE(e.meta_description, ''), e.event_type, e.status,
+		       e.start_date, e.end_date, e.timezone, e.currency, COALESCE(e.image_url, ''), e.age_restriction,
+		       COALESCE(e.max_tickets_per_order, 10), e.sale_start_date, e.sale_end_date,
+		       e.sales_paused, e.sales_pause_at, COALESCE(e.updated_at, e.created_at),
+		       u.id, u.first_name || ' ' || u.last_name,
+		       v.name, v.address, v.city, v.state, v.country, v.venue_type, v.latitude, v.longitude
+		FROM events e
+		JOIN users u ON u.id = e.organizer_id
+		LEFT JOIN venues v ON v.id = e.venue_id
+		WHERE e.slug = $1 AND e.status <> $2`
+
+	event := &domain.PublicEvent{}
+	session := domain.PublicSession{}
+	var (
+		venueName, venueAddress, venueCity, venueState, venueCountry, venueType sql.NullString
+		latitude, longitude                                                     sql.NullFloat64
+		ageRestriction                                                          sql.NullInt64
+	)
+
+	err := r.db.QueryRowContext(ctx, query, slug, domain.EventStatusDraft).Scan(
+		&event.ID,
+		&event.Slug,
+		&event.Title,
+		&event.Description,
+		&event.MetaTitle,
+		&event.MetaDescription,
+		&event.EventType,
+		&event.Status,
+		&session.StartDate,
+		&session.EndDate,
+		&event.Timezone,
+		&event.Currency,
+		&event.ImageURL,
+		&ageRestriction,
+		&event.MaxTicketsPerOrder,
+		&event.SaleStartDate,
+		&event.SaleEndDate,
+		&event.SalesPause.Paused,
+		&event.SalesPause.PauseAt,
+		&event.UpdatedAt,
+		&event.Organizer.ID,
+		&event.Organizer.Name,
+		&venueName,
+		&venueAddress,
+		&venueCity,
+		&venueState,
+		&venueCountry,
+		&venueType,
+		&latitude,
+		&longitude,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrEventNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get public event")
+	}
+
+	event.Sessions = []domain.PublicSession{session}
+	if ageRestriction.Valid {
+		age := int(ageRestriction.Int64)
+		event.AgeRestriction = &age
+	}
+	if venueName.Valid {
+		event.Venue = &domain.PublicVenue{
+			Name:      venueName.String,
+			Address:   venueAddress.String,
+			City:      venueCity.String,
+			State:     venueState.String,
+			Country:   venueCountry.String,
+			VenueType: venueType.String,
+		}
+		if latitude.Valid && longitude.Valid {
+			event.Venue.Latitude = &latitude.Float64
+			event.Venue.Longitude = &longitude.Float64
+		}
+	}
+
+	event.TicketCategories, err = r.getTicketCategories(ctx, event.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// eventSearchDocument is the text search document of an event, the expression of the
+// idx_events_search index: the search uses the index only as long as both are the same
+const eventSearchDocument = `to_tsvector('simple', e.title || ' ' || COALESCE(e.description, ''))`
+
+// Search retrieves a page of the published and postponed events matching the search, soonest first.
+// Test mode events are only reached by their page. The statuses are written out in the query for the planner to use the partial index of listed events.
+func (r *PublicEventPostgresRepository) Search(ctx context.Context, search domain.EventSearch, paging *listing.Paging) ([]*domain.PublicEventSummary, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	filter := &pgquery.Filter{}
+	filter.Where("e.status IN ('published', 'postponed')")
+	filter.Where("NOT e.test_mode")
+	filter.Where("e.start_date >= ? AND e.start_date < ?", search.From, search.To)
+	if search.EventType != "" {
+		filter.Where("e.event_type = ?", search.EventType)
+	}
+	if search.City != "" {
+		filter.Where("LOWER(v.city) = LOWER(?)", search.City)
+	}
+	if search.VenueID != 0 {
+		filter.Where("e.venue_id = ?", search.VenueID)
+	}
+	if search.Currency != "" {
+		filter.Where("e.currency = ?", search.Currency)
+	}
+	if search.MinPrice != nil || search.MaxPrice != nil {
+		var conditions []string
+		var args []interface{}
+		if search.MinPrice != nil {
+			conditions = append(conditions, "tc.price >= ?")
+			args = append(args, *search.MinPrice)
+		}
+		if search.MaxPrice != nil {
+			conditions = append(conditions, "tc.price <= ?")
+			args = append(args, *search.MaxPrice)
+		}
+		filter.Where("EXISTS (SELECT 1 FROM ticket_categories tc WHERE tc.event_id = e.id AND "+strings.Join(conditions, " AND ")+")", args...)
+	}
+	if search.Text != "" {
+		filter.Where(eventSearchDocument+" @@ websearch_to_tsquery('simple', ?)", search.Text)
+	}
+
+	// Set total in paging
+	from := "events e LEFT JOIN venues v ON v.id = e.venue_id"
+	if err := pgquery.Count(ctx, r.db, from, filter, paging); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to count public events")
+	}
+
+	pageClause, args := filter.Paged(paging)
+	query := fmt.Sprintf(`
+		SELECT e.id, e.slug, e.title, e.event_type, e.status, e.timezone, e.currency, COALESCE(e.image_url, ''),
+		       e.start_date, e.end_date, u.id, u.first_name || ' ' || u.last_name,
+		       v.name, v.address, v.city, v.state, v.country, v.venue_type, v.latitude, v.longitude,
+		       COALESCE(p.min_price::TEXT, ''), COALESCE(p.max_price::TEXT, '')
+		FROM events e
+		JOIN users u ON u.id = e.organizer_id
+		LEFT JOIN venues v ON v.id = e.venue_id
+		LEFT JOIN LATERAL (
+			SELECT MIN(price) AS min_price, MAX(price) AS max_price
+			FROM ticket_categories
+			WHERE event_id = e.id
+		) p ON TRUE
+		%s
+		ORDER BY e.start_date, e.id
+		%s`, filter.Clause(), pageClause)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to search public events")
+	}
+	defer rows.Close()
+
+	events := []*domain.PublicEventSummary{}
+	for rows.Next() {
+		event := &domain.PublicEventSummary{}
+		var (
+			venueName, venueAddress, venueCity, venueState, venueCountry, venueType sql.NullString
+			latitude, longitude                                                     sql.NullFloat64
+		)
+		err := rows.Scan(
+			&event.ID,
+			&event.Slug,
+			&event.Title,
+			&event.EventType,
+			&event.Status,
+			&event.Timezone,
+			&event.Currency,
+			&event.ImageURL,
+			&event.StartDate,
+			&event.EndDate,
+			&event.Organizer.ID,
+			&event.Organizer.Name,
+			&venueName,
+			&venueAddress,
+			&venueCity,
+			&venueState,
+			&venueCountry,
+			&venueType,
+			&latitude,
+			&longitude,
+			&event.MinPrice,
+			&event.MaxPrice,
+		)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan public event")
+		}
+		if venueName.Valid {
+			event.Venue = &domain.PublicVenue{
+				Name:      venueName.String,
+				Address:   venueAddress.String,
+				City:      venueCity.String,
+				State:     venueState.String,
+				Country:   venueCountry.String,
+				VenueType: venueType.String,
+			}
+			if latitude.Valid && longitude.Valid {
+				event.Venue.Latitude = &latitude.Float64
+				event.Venue.Longitude = &longitude.Float64
+			}
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to iterate public events")
+	}
+
+	return events[:paging.Fetched(len(events))], nil
+}
+
+func (r *PublicEventPostgresRepository) getTicketCategories(ctx context.Context, eventID int64) ([]domain.PublicTicketCategory, error) {
+	query := `
+		SELECT id, name, COALESCE(description, ''), COALESCE(category_type::TEXT, 'general'), price::TEXT,
+		       quantity_available, quantity_sold, quantity_reserved, quantity_allotted, COALESCE(max_per_order, 10),
+		       sale_start_date, sale_end_date, sales_paused, sales_pause_at
+		FROM ticket_categories
+		WHERE event_id = $1
+		ORDER BY price, id`
+
+	rows, err := r.db.QueryContext(ctx, query, eventID)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get ticket categories")
+	}
+	defer rows.Close()
+
+	categories := []domain.PublicTicketCategory{}
+	for rows.Next() {
+		var category domain.PublicTicketCategory
+		err := rows.Scan(
+			&category.ID,
+			&category.Name,
+			&category.Description,
+			&category.CategoryType,
+			&category.Price,
+			&category.Quantity,
+			&category.QuantitySold,
+			&category.QuantityReserved,
+			&category.QuantityAllotted,
+			&category.MaxPerOrder,
+			&category.SaleStartDate,
+			&category.SaleEndDate,
+			&category.SalesPause.Paused,
+			&category.SalesPause.PauseAt,
+		)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan ticket category")
+		}
+		categories = append(categories, category)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to iterate ticket categories")
+	}
+
+	return categories, nil
+}
@@ -0,0 +1,44 @@
+package adapters
+
+import (
+	"context"
+	"strconv"
+
+	"tixgo/modules/event/domain"
+	"tixgo/shared/cache"
+
+	"github.com/duongptryu/gox/logger"
+)
+
+// CachedQueueSettingsRepository serves GetQueueSettings from the cache: every poll of a queued buyer
+// needs the settings, which must not cost a database query during an on-sale. Settings have no write
+// path yet, so changes apply once the cached entry expires; cancelling an event and changes to its
+// sales pauses invalidate them.
+type CachedQueueSettingsRepository struct {
+	domain.QueueSettingsRepository
+	cache *cache.Cache
+}
+
+// NewCachedQueueSettingsRepository wraps repo with a read-through cache
+func NewCachedQueueSettingsRepository(repo domain.QueueSettingsRepository, c *cache.Cache) *CachedQueueSettingsRepository {
+	return &CachedQueueSettingsRepository{QueueSettingsRepository: repo, cache: c}
+}
+
+// GetQueueSettings retrieves the queue settings of an event
+func (r *CachedQueueSettingsRepository) GetQueueSettings(ctx context.Context, eventID int64) (*domain.QueueSettings, error) {
+	return cache.GetOrLoad(ctx, r.cache, queueSettingsCacheKey(eventID), func(ctx context.Context) (*domain.QueueSettings, error) {
+		return r.QueueSettingsRepository.GetQueueSettings(ctx, eventID)
+	})
+}
+
+// Invalidate drops the cached settings of an event. The change already succeeded, so a failure is
+// only logged: the entry then expires with the cache TTL.
+func (r *CachedQueueSettingsRepository) Invalidate(ctx context.Context, eventID int64) {
+	if err := r.cache.Delete(ctx, queueSettingsCacheKey(eventID)); err != nil {
+		logger.Error(ctx, "Failed to invalidate cached queue settings", logger.F("event_id", eventID), logger.F("error", err))
+	}
+}
+
+func queueSettingsCacheKey(eventID int64) string {
+	return "queue_settings:" + strconv.FormatInt(eventID, 10)
+}
@@ -0,0 +1,122 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"tixgo/modules/event/domain"
+	"tixgo/shared/dbtimeout"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+// QueueSettingsPostgresRepository implements the QueueSettingsRepository interface using PostgreSQL
+type QueueSettingsPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewQueueSettingsPostgresRepository creates a new PostgreSQL queue settings repository
+func NewQueueSettingsPostgresRepository(db *sqlx.DB) *QueueSettingsPostgresRepository {
+	return &QueueSettingsPostgresRepository{db: db}
+}
+
+// GetQueueSettings retrieves the queue settings of an event. The queue of an event no longer on sale is disabled.
+func (r *QueueSettingsPostgresRepository) GetQueueSettings(ctx context.Context, eventID int64) (*domain.QueueSettings, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT q.event_id, COALESCE(q.is_enabled, FALSE) AND e.status = 'published', q.mode,
+		       COALESCE(q.max_concurrent_users, 1000), COALESCE(q.reservation_timeout_minutes, 10),
+		       COALESCE(q.estimated_service_time_seconds, 300),
+		       COALESCE(e.max_tickets_per_order, 10), e.sale_start_date, e.sales_paused, e.sales_pause_at
+		FROM queue_settings q
+		JOIN events e ON e.id = q.event_id
+		WHERE q.event_id = $1`
+
+	settings := &domain.QueueSettings{}
+	var reservationTimeoutMinutes, estimatedServiceTimeSeconds int
+	err := r.db.QueryRowContext(ctx, query, eventID).Scan(
+		&settings.EventID,
+		&settings.Enabled,
+		&settings.Mode,
+		&settings.MaxConcurrentUsers,
+		&reservationTimeoutMinutes,
+		&estimatedServiceTimeSeconds,
+		&settings.MaxTicketsPerOrder,
+		&settings.SaleStartDate,
+		&settings.SalesPause.Paused,
+		&settings.SalesPause.PauseAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrQueueDisabled
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get queue settings")
+	}
+
+	settings.ReservationTimeout = time.Duration(reservationTimeoutMinutes) * time.Minute
+	settings.EstimatedServiceTime = time.Duration(estimatedServiceTimeSeconds) * time.Second
+
+	settings.CategorySalesPauses, err = r.getCategorySalesPauses(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// getCategorySalesPauses retrieves the sales pauses of the categories of an event paused or with a
+// pause scheduled
+func (r *QueueSettingsPostgresRepository) getCategorySalesPauses(ctx context.Context, eventID int64) (map[int64]domain.SalesPause, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, sales_paused, sales_pause_at
+		FROM ticket_categories
+		WHERE event_id = $1 AND (sales_paused OR sales_pause_at IS NOT NULL)`, eventID)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get category sales pauses")
+	}
+	defer rows.Close()
+
+	pauses := map[int64]domain.SalesPause{}
+	for rows.Next() {
+		var (
+			ticketCategoryID int64
+			pause            domain.SalesPause
+		)
+		if err := rows.Scan(&ticketCategoryID, &pause.Paused, &pause.PauseAt); err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan category sales pause")
+		}
+		pauses[ticketCategoryID] = pause
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to iterate category sales pauses")
+	}
+
+	return pauses, nil
+}
+
+// GetRemainingTickets retrieves how many tickets of a category of the event are left
+func (r *QueueSettingsPostgresRepository) GetRemainingTickets(ctx context.Context, eventID, ticketCategoryID int64) (int, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT GREATEST(quantity_available - quantity_sold - quantity_reserved - quantity_allotted, 0)
+		FROM ticket_categories
+		WHERE id = $1 AND event_id = $2`
+
+	var remaining int
+	err := r.db.QueryRowContext(ctx, query, ticketCategoryID, eventID).Scan(&remaining)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, domain.ErrTicketCategoryNotFound
+		}
+		return 0, syserr.Wrap(err, syserr.InternalCode, "failed to get remaining tickets")
+	}
+
+	return remaining, nil
+}
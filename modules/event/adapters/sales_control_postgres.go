@@ -0,0 +1,239 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"tixgo/modules/event/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/pgerr"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+// SalesControlPostgresRepository implements the SalesControlRepository interface using PostgreSQL
+type SalesControlPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewSalesControlPostgresRepository creates a new PostgreSQL sales control repository
+func NewSalesControlPostgresRepository(db *sqlx.DB) *SalesControlPostgresRepository {
+	return &SalesControlPostgresRepository{db: db}
+}
+
+// Get retrieves the sales controls of an event of the organizer, its categories by price
+func (r *SalesControlPostgresRepository) Get(ctx context.Context, eventID, organizerID int64) (*domain.SalesControls, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	controls := &domain.SalesControls{EventID: eventID}
+	var eventOrganizerID int64
+	err := r.db.QueryRowContext(ctx, `SELECT organizer_id, sales_paused, sales_pause_at FROM events WHERE id = $1`, eventID).
+		Scan(&eventOrganizerID, &controls.SalesPause.Paused, &controls.SalesPause.PauseAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrEventNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get event")
+	}
+	if eventOrganizerID != organizerID {
+		return nil, domain.ErrEventNotFound
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, name, quantity_available, quantity_sold, quantity_reserved, quantity_allotted,
+		       sales_paused, sales_pause_at
+		FROM ticket_categories
+		WHERE event_id = $1
+		ORDER BY price, id`, eventID)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get ticket categories")
+	}
+	defer rows.Close()
+
+	controls.TicketCategories = []domain.CategorySalesControls{}
+	for rows.Next() {
+		var category domain.CategorySalesControls
+		err := rows.Scan(
+			&category.Inventory.TicketCategoryID,
+			&category.Name,
+			&category.Inventory.Capacity,
+			&category.Inventory.Sold,
+			&category.Inventory.Reserved,
+			&category.Inventory.Allotted,
+			&category.SalesPause.Paused,
+			&category.SalesPause.PauseAt,
+		)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan ticket category")
+		}
+		controls.TicketCategories = append(controls.TicketCategories, category)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to iterate ticket categories")
+	}
+
+	return controls, nil
+}
+
+// SetPaused pauses or resumes the sales of the target, dropping the pause scheduled for it
+func (r *SalesControlPostgresRepository) SetPaused(ctx context.Context, target domain.SalesTarget, organizerID int64, paused bool) error {
+	return r.updateSalesPause(ctx, target, organizerID, `sales_paused = $2, sales_pause_at = NULL`, paused)
+}
+
+// SchedulePause schedules the sales of the target to pause at pauseAt, nil dropping the schedule
+func (r *SalesControlPostgresRepository) SchedulePause(ctx context.Context, target domain.SalesTarget, organizerID int64, pauseAt *time.Time) error {
+	return r.updateSalesPause(ctx, target, organizerID, `sales_pause_at = $2`, pauseAt)
+}
+
+// updateSalesPause sets the sales pause columns of the event or category of the target, $1 being its id
+// and $2 value
+func (r *SalesControlPostgresRepository) updateSalesPause(ctx context.Context, target domain.SalesTarget, organizerID int64, set string, value any) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	if err := checkEventOwner(ctx, r.db, target.EventID, organizerID); err != nil {
+		return err
+	}
+
+	var (
+		result sql.Result
+		err    error
+	)
+	if target.TicketCategoryID == 0 {
+		result, err = r.db.ExecContext(ctx, `UPDATE events SET `+set+`, updated_at = NOW() WHERE id = $1`, target.EventID, value)
+	} else {
+		result, err = r.db.ExecContext(ctx, `UPDATE ticket_categories SET `+set+`, updated_at = NOW() WHERE id = $1 AND event_id = $3`,
+			target.TicketCategoryID, value, target.EventID)
+	}
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to update sales pause")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get rows affected")
+	}
+	if rowsAffected == 0 {
+		if target.TicketCategoryID == 0 {
+			return domain.ErrEventNotFound
+		}
+		return domain.ErrTicketCategoryNotFound
+	}
+
+	return nil
+}
+
+// Resize changes the capacity of a ticket category. The category is locked meanwhile so the capacity
+// is checked against its current stock.
+func (r *SalesControlPostgresRepository) Resize(ctx context.Context, target domain.SalesTarget, organizerID int64, capacity int) (*domain.TicketInventory, int, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, 0, syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	if err := checkEventOwner(ctx, tx, target.EventID, organizerID); err != nil {
+		return nil, 0, err
+	}
+
+	inventory := &domain.TicketInventory{}
+	err = tx.QueryRowContext(ctx, `
+		SELECT id, quantity_available, quantity_sold, quantity_reserved, quantity_allotted
+		FROM ticket_categories
+		WHERE id = $1 AND event_id = $2
+		FOR UPDATE`, target.TicketCategoryID, target.EventID,
+	).Scan(&inventory.TicketCategoryID, &inventory.Capacity, &inventory.Sold, &inventory.Reserved, &inventory.Allotted)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, 0, domain.ErrTicketCategoryNotFound
+		}
+		return nil, 0, syserr.Wrap(err, syserr.InternalCode, "failed to get ticket inventory")
+	}
+
+	// the tickets of a seated category are its seats, bound from the seat map of the venue
+	var seats int
+	err = tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM tickets WHERE ticket_category_id = $1 AND seat_section IS NOT NULL AND status <> 'cancelled'`,
+		target.TicketCategoryID).Scan(&seats)
+	if err != nil {
+		return nil, 0, syserr.Wrap(err, syserr.InternalCode, "failed to get seat map")
+	}
+	if seats > 0 && capacity > seats {
+		return nil, 0, domain.ErrCapacityExceedsSeats
+	}
+
+	delta, err := inventory.Resize(capacity)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE ticket_categories SET quantity_available = $2, updated_at = NOW() WHERE id = $1`,
+		inventory.TicketCategoryID, inventory.Capacity)
+	if err != nil {
+		if pgerr.Constraint(err) == pgerr.ConstraintTicketInventory {
+			return nil, 0, domain.ErrCapacityBelowSold
+		}
+		return nil, 0, syserr.Wrap(err, syserr.InternalCode, "failed to resize ticket category")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, 0, syserr.Wrap(err, syserr.InternalCode, "failed to commit transaction")
+	}
+
+	return inventory, delta, nil
+}
+
+// ApplyScheduledPauses pauses the sales of the events and categories whose scheduled pause is due
+func (r *SalesControlPostgresRepository) ApplyScheduledPauses(ctx context.Context) ([]domain.SalesTarget, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	targets := []domain.SalesTarget{}
+	for _, query := range []string{`
+		UPDATE events
+		SET sales_paused = TRUE, sales_pause_at = NULL, updated_at = NOW()
+		WHERE sales_pause_at <= NOW()
+		RETURNING id, 0`, `
+		UPDATE ticket_categories
+		SET sales_paused = TRUE, sales_pause_at = NULL, updated_at = NOW()
+		WHERE sales_pause_at <= NOW()
+		RETURNING event_id, id`,
+	} {
+		rows, err := tx.QueryContext(ctx, query)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to apply scheduled sales pauses")
+		}
+
+		for rows.Next() {
+			var target domain.SalesTarget
+			if err := rows.Scan(&target.EventID, &target.TicketCategoryID); err != nil {
+				rows.Close()
+				return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan sales pause")
+			}
+			targets = append(targets, target)
+		}
+		rows.Close()
+
+		if err := rows.Err(); err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to iterate sales pauses")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to commit transaction")
+	}
+
+	return targets, nil
+}
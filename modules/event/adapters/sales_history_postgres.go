@@ -0,0 +1,77 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+
+	"tixgo/modules/event/domain"
+	"tixgo/shared/dbtimeout"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+// SalesHistoryPostgresRepository implements the SalesHistoryRepository interface using PostgreSQL
+type SalesHistoryPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewSalesHistoryPostgresRepository creates a new PostgreSQL sales history repository
+func NewSalesHistoryPostgresRepository(db *sqlx.DB) *SalesHistoryPostgresRepository {
+	return &SalesHistoryPostgresRepository{db: db}
+}
+
+// GetSalesHistory retrieves the sales of an event of the organizer. Its stock comes from the counters of
+// its ticket categories and its daily sales from the confirmed orders; fully refunded orders gave their
+// tickets back so they are left out.
+func (r *SalesHistoryPostgresRepository) GetSalesHistory(ctx context.Context, eventID, organizerID int64) (*domain.SalesHistory, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	history := &domain.SalesHistory{EventID: eventID}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT e.start_date,
+		       COALESCE(SUM(tc.quantity_available), 0),
+		       COALESCE(SUM(tc.quantity_sold), 0),
+		       COALESCE(SUM(GREATEST(tc.quantity_available - tc.quantity_sold - tc.quantity_reserved - tc.quantity_allotted, 0)), 0)
+		FROM events e
+		LEFT JOIN ticket_categories tc ON tc.event_id = e.id
+		WHERE e.id = $1 AND e.organizer_id = $2
+		GROUP BY e.id`, eventID, organizerID,
+	).Scan(&history.SalesEnd, &history.Capacity, &history.Sold, &history.Remaining)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrEventNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get event stock")
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT date_trunc('day', o.confirmed_at) AS day, SUM(oi.quantity), (SUM(oi.subtotal) * 100)::BIGINT
+		FROM order_items oi
+		JOIN orders o ON o.id = oi.order_id
+		JOIN tickets t ON t.id = oi.ticket_id
+		JOIN ticket_categories tc ON tc.id = t.ticket_category_id
+		WHERE tc.event_id = $1 AND o.confirmed_at IS NOT NULL AND NOT o.test_mode
+		  AND o.status IN ('confirmed', 'partially_refunded')
+		GROUP BY day
+		ORDER BY day`, eventID)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get daily sales")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var day domain.DailySales
+		if err := rows.Scan(&day.Day, &day.Tickets, &day.Revenue); err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan daily sales")
+		}
+		history.Daily = append(history.Daily, day)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to iterate daily sales")
+	}
+
+	return history, nil
+}
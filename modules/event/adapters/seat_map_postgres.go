@@ -0,0 +1,75 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+
+	"tixgo/modules/event/domain"
+	"tixgo/shared/dbtimeout"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+// SeatMapPostgresRepository implements the SeatMapRepository interface using PostgreSQL
+type SeatMapPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewSeatMapPostgresRepository creates a new PostgreSQL seat map repository
+func NewSeatMapPostgresRepository(db *sqlx.DB) *SeatMapPostgresRepository {
+	return &SeatMapPostgresRepository{db: db}
+}
+
+// GetSeatMap retrieves the seats of a non draft event, ordered by section, row and number.
+// Reservations past their expiry count as available; cancelled tickets are left out.
+func (r *SeatMapPostgresRepository) GetSeatMap(ctx context.Context, eventID int64) ([]domain.Seat, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var status domain.EventStatus
+	err := r.db.QueryRowContext(ctx, `SELECT status FROM events WHERE id = $1`, eventID).Scan(&status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrEventNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get event")
+	}
+	if status == domain.EventStatusDraft {
+		return nil, domain.ErrEventNotFound
+	}
+
+	query := `
+		SELECT t.id, t.ticket_category_id, t.seat_section, COALESCE(t.seat_row, ''), COALESCE(t.seat_number, ''),
+		       CASE
+		           WHEN t.status IN ('sold', 'used') THEN 'sold'
+		           WHEN t.status = 'reserved' AND (t.reserved_expires_at IS NULL OR t.reserved_expires_at > NOW()) THEN 'held'
+		           ELSE 'available'
+		       END
+		FROM tickets t
+		JOIN ticket_categories c ON c.id = t.ticket_category_id
+		WHERE c.event_id = $1 AND t.seat_section IS NOT NULL AND t.status <> 'cancelled'
+		ORDER BY t.seat_section, t.seat_row, t.seat_number`
+
+	rows, err := r.db.QueryContext(ctx, query, eventID)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get seat map")
+	}
+	defer rows.Close()
+
+	seats := []domain.Seat{}
+	for rows.Next() {
+		var seat domain.Seat
+		err := rows.Scan(&seat.TicketID, &seat.TicketCategoryID, &seat.Section, &seat.Row, &seat.Number, &seat.Status)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan seat")
+		}
+		seats = append(seats, seat)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to iterate seats")
+	}
+
+	return seats, nil
+}
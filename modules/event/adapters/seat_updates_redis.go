@@ -0,0 +1,86 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/syserr"
+	"github.com/redis/go-redis/v9"
+)
+
+// seatUpdatesBuffer is how many changes a subscriber may lag behind before it is cut off
+const seatUpdatesBuffer = 256
+
+// SeatUpdatesRedis implements the SeatUpdates interface with redis pub/sub, which reaches every
+// instance, unlike a consumer group
+type SeatUpdatesRedis struct {
+	client redis.UniversalClient
+}
+
+// NewSeatUpdatesRedis creates new redis seat updates
+func NewSeatUpdatesRedis(client redis.UniversalClient) *SeatUpdatesRedis {
+	return &SeatUpdatesRedis{client: client}
+}
+
+// Publish sends a change to the subscribers of its event on every instance
+func (u *SeatUpdatesRedis) Publish(ctx context.Context, change *domain.EventSeatStatusChanged) error {
+	payload, err := json.Marshal(change)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to encode seat change")
+	}
+
+	if err := u.client.Publish(ctx, seatUpdatesChannel(change.EventID), payload).Err(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to publish seat change")
+	}
+	return nil
+}
+
+// Subscribe returns the changes of an event from now on, until ctx is done. The subscription is
+// confirmed before it returns, so a snapshot loaded afterwards misses no change. The channel is closed
+// when the subscriber falls too far behind, rather than skipping changes: it has to start over.
+func (u *SeatUpdatesRedis) Subscribe(ctx context.Context, eventID int64) (<-chan *domain.EventSeatStatusChanged, error) {
+	pubsub := u.client.Subscribe(ctx, seatUpdatesChannel(eventID))
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to subscribe to seat changes")
+	}
+
+	changes := make(chan *domain.EventSeatStatusChanged, seatUpdatesBuffer)
+	go func() {
+		defer close(changes)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+				change := &domain.EventSeatStatusChanged{}
+				if err := json.Unmarshal([]byte(message.Payload), change); err != nil {
+					logger.Warning(ctx, "Dropped malformed seat change", logger.F("event_id", eventID), logger.F("error", err))
+					continue
+				}
+				select {
+				case changes <- change:
+				default:
+					logger.Warning(ctx, "Seat change subscriber too slow, closing", logger.F("event_id", eventID))
+					return
+				}
+			}
+		}
+	}()
+
+	return changes, nil
+}
+
+func seatUpdatesChannel(eventID int64) string {
+	return fmt.Sprintf("seats:%d", eventID)
+}
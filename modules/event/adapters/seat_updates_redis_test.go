@@ -0,0 +1,45 @@
+package adapters
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeatUpdatesRedis(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	updates := NewSeatUpdatesRedis(client)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changes, err := updates.Subscribe(ctx, 1)
+	require.NoError(t, err)
+
+	require.NoError(t, updates.Publish(ctx, domain.NewEventSeatStatusChanged(2, 20, domain.SeatStatusHeld)))
+	require.NoError(t, updates.Publish(ctx, domain.NewEventSeatStatusChanged(1, 10, domain.SeatStatusSold)))
+
+	select {
+	case change := <-changes:
+		assert.Equal(t, int64(1), change.EventID, "changes of other events are not received")
+		assert.Equal(t, int64(10), change.TicketID)
+		assert.Equal(t, domain.SeatStatusSold, change.Status)
+	case <-time.After(time.Second):
+		t.Fatal("no change received")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-changes:
+		assert.False(t, ok, "the channel is closed once the context is done")
+	case <-time.After(time.Second):
+		t.Fatal("channel not closed")
+	}
+}
@@ -0,0 +1,31 @@
+package command
+
+import "tixgo/modules/event/domain"
+
+// AllotmentResult represents an allotment and how many of its tickets were issued
+type AllotmentResult struct {
+	ID               int64                `json:"id"`
+	TicketCategoryID int64                `json:"ticket_category_id"`
+	Name             string               `json:"name"`
+	Kind             domain.AllotmentKind `json:"kind"`
+	Quantity         int                  `json:"quantity"`
+	Issued           int                  `json:"issued"`
+	Remaining        int                  `json:"remaining"`
+	CreatedAt        string               `json:"created_at"`
+	UpdatedAt        string               `json:"updated_at"`
+}
+
+// ToAllotmentResult converts an allotment to its result
+func ToAllotmentResult(allotment *domain.TicketAllotment) *AllotmentResult {
+	return &AllotmentResult{
+		ID:               allotment.ID,
+		TicketCategoryID: allotment.TicketCategoryID,
+		Name:             allotment.Name,
+		Kind:             allotment.Kind,
+		Quantity:         allotment.Quantity,
+		Issued:           allotment.Issued,
+		Remaining:        allotment.Remaining(),
+		CreatedAt:        allotment.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:        allotment.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
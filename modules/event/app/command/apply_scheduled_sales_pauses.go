@@ -0,0 +1,44 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ApplyScheduledSalesPausesHandler records the sales pauses whose cutoff time passed
+type ApplyScheduledSalesPausesHandler struct {
+	salesRepo domain.SalesControlRepository
+	eventBus  messaging.EventBus
+}
+
+// NewApplyScheduledSalesPausesHandler creates a new apply scheduled sales pauses handler
+func NewApplyScheduledSalesPausesHandler(salesRepo domain.SalesControlRepository, eventBus messaging.EventBus) *ApplyScheduledSalesPausesHandler {
+	return &ApplyScheduledSalesPausesHandler{
+		salesRepo: salesRepo,
+		eventBus:  eventBus,
+	}
+}
+
+// Handle pauses the sales whose scheduled pause is due and announces them. Reservations already stopped
+// at the cutoff time, this makes the pause last until the organizer resumes the sales.
+func (h *ApplyScheduledSalesPausesHandler) Handle(ctx context.Context) error {
+	targets, err := h.salesRepo.ApplyScheduledPauses(ctx)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to apply scheduled sales pauses")
+	}
+
+	for _, target := range targets {
+		publishAvailabilityChange(ctx, h.eventBus, target, domain.AvailabilityChangePaused, 0)
+	}
+
+	if len(targets) > 0 {
+		logger.Info(ctx, "Applied scheduled sales pauses", logger.F("count", len(targets)))
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/event/domain"
+	templateDomain "tixgo/modules/template/domain"
+	sharedMail "tixgo/shared/events/mail"
+	sharedKafka "tixgo/shared/kafka"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/notification/mail"
+	"github.com/duongptryu/gox/syserr"
+)
+
+const (
+	SlugTicketAssigned = "ticket-assigned"
+)
+
+// AssignTicketAttendeeCommand represents the command of a buyer to assign one of their tickets to the
+// person attending with it
+type AssignTicketAttendeeCommand struct {
+	EventID  int64  `json:"-"`
+	TicketID int64  `json:"-"`
+	UserID   int64  `json:"-"`
+	Name     string `json:"name" binding:"required,max=200"`
+	Email    string `json:"email" binding:"required,email"`
+}
+
+// TicketAttendeeResult represents the attendee a ticket is assigned to
+type TicketAttendeeResult struct {
+	TicketID     int64  `json:"ticket_id"`
+	TicketNumber string `json:"ticket_number"`
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	// DeliveryFailed tells that the ticket was assigned but could not be mailed to the attendee
+	DeliveryFailed bool   `json:"delivery_failed"`
+	AssignedAt     string `json:"assigned_at"`
+}
+
+// AssignTicketAttendeeHandler handles ticket attendee assignments
+type AssignTicketAttendeeHandler struct {
+	attendeeRepo     domain.AttendeeRepository
+	templateRepo     templateDomain.TemplateRepository
+	templateRenderer templateDomain.TemplateRenderer
+	eventBus         messaging.EventBus
+}
+
+// NewAssignTicketAttendeeHandler creates a new assign ticket attendee handler
+func NewAssignTicketAttendeeHandler(attendeeRepo domain.AttendeeRepository, templateRepo templateDomain.TemplateRepository, templateRenderer templateDomain.TemplateRenderer, eventBus messaging.EventBus) *AssignTicketAttendeeHandler {
+	return &AssignTicketAttendeeHandler{
+		attendeeRepo:     attendeeRepo,
+		templateRepo:     templateRepo,
+		templateRenderer: templateRenderer,
+		eventBus:         eventBus,
+	}
+}
+
+// Handle executes the assign ticket attendee command. The ticket is mailed to the attendee when it is
+// assigned to a new email, renaming the attendee alone sends nothing; a failed delivery is logged and
+// reported in the result for the buyer to assign again.
+func (h *AssignTicketAttendeeHandler) Handle(ctx context.Context, cmd AssignTicketAttendeeCommand) (*TicketAttendeeResult, error) {
+	attendee, err := domain.NewTicketAttendee(cmd.EventID, cmd.TicketID, cmd.UserID, cmd.Name, cmd.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.attendeeRepo.Assign(ctx, attendee)
+	if err != nil {
+		switch err {
+		case domain.ErrTicketNotFound, domain.ErrAttendeeEditClosed:
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to assign ticket")
+	}
+
+	result := &TicketAttendeeResult{
+		TicketID:     attendee.TicketID,
+		TicketNumber: attendee.Ticket.TicketNumber,
+		Name:         attendee.Name,
+		Email:        attendee.Email,
+		AssignedAt:   attendee.AssignedAt.Format("2006-01-02T15:04:05Z"),
+	}
+
+	if attendee.Redelivered() {
+		if err := h.deliver(ctx, attendee); err != nil {
+			logger.Error(ctx, "Failed to deliver assigned ticket", logger.F("ticket_id", attendee.TicketID), logger.F("error", err))
+			result.DeliveryFailed = true
+		}
+	}
+
+	return result, nil
+}
+
+// deliver mails the ticket to the attendee with the identity of the organizer
+func (h *AssignTicketAttendeeHandler) deliver(ctx context.Context, attendee *domain.TicketAttendee) error {
+	template, err := h.templateRepo.GetBySlug(ctx, SlugTicketAssigned)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get template")
+	}
+
+	rendered, err := h.templateRenderer.Render(ctx, template, map[string]interface{}{
+		"event_title":   attendee.EventTitle,
+		"attendee_name": attendee.Name,
+		"tickets":       ticketsTemplateData([]*domain.IssuedTicket{&attendee.Ticket}),
+	}, templateDomain.RenderOptions{OrganizerID: attendee.OrganizerID})
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to render template")
+	}
+
+	err = h.eventBus.PublishEvent(sharedKafka.WithPartitionKey(ctx, attendee.Email), &sharedMail.EventSendMail{
+		ToMail: []mail.EmailAddress{
+			{
+				Email: attendee.Email,
+				Name:  attendee.Name,
+			},
+		},
+		Subject:     rendered.Subject,
+		HTMLBody:    rendered.Content,
+		Priority:    mail.PriorityHigh,
+		OrganizerID: attendee.OrganizerID,
+	})
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to publish send mail event")
+	}
+
+	return nil
+}
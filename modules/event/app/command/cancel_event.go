@@ -0,0 +1,136 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/event/domain"
+	sharedOrder "tixgo/shared/events/order"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// CancelEventCommand represents the command of an organizer to cancel their event
+type CancelEventCommand struct {
+	EventID     int64  `json:"-"`
+	OrganizerID int64  `json:"-"`
+	Reason      string `json:"reason" binding:"required,max=1000"`
+}
+
+// queueSettingsInvalidator drops cached queue settings so a cancelled event stops admitting buyers at once
+type queueSettingsInvalidator interface {
+	Invalidate(ctx context.Context, eventID int64)
+}
+
+// CancelEventHandler handles event cancellations
+type CancelEventHandler struct {
+	cancellationRepo domain.EventCancellationRepository
+	queueSettings    queueSettingsInvalidator
+	eventBus         messaging.EventBus
+}
+
+// NewCancelEventHandler creates a new cancel event handler
+func NewCancelEventHandler(cancellationRepo domain.EventCancellationRepository, queueSettings queueSettingsInvalidator, eventBus messaging.EventBus) *CancelEventHandler {
+	return &CancelEventHandler{
+		cancellationRepo: cancellationRepo,
+		queueSettings:    queueSettings,
+		eventBus:         eventBus,
+	}
+}
+
+// Handle executes the cancel event command. Sales stop at once; the refunds and the notices to ticket
+// holders are sent in batches by the process event cancellations job.
+func (h *CancelEventHandler) Handle(ctx context.Context, cmd CancelEventCommand) (*EventCancellationResult, error) {
+	cancellation := domain.NewEventCancellation(cmd.EventID, cmd.OrganizerID, cmd.Reason)
+
+	err := h.cancellationRepo.Cancel(ctx, cancellation)
+	if err != nil {
+		if err == domain.ErrEventNotFound || err == domain.ErrEventNotCancellable {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to cancel event")
+	}
+
+	h.queueSettings.Invalidate(ctx, cmd.EventID)
+
+	fields := []domain.FieldChange{
+		domain.NewFieldChange("status", "", string(domain.EventStatusCancelled)),
+		domain.NewFieldChange("reason", "", cancellation.Reason),
+	}
+	publishConfigurationChange(ctx, h.eventBus, domain.SalesTarget{EventID: cmd.EventID}, domain.ChangeActionCancelled, fields, cmd.OrganizerID)
+
+	// the pending orders of the event were cancelled with it, their summaries catch up at the next rebuild otherwise
+	if err := h.eventBus.PublishEvent(ctx, sharedOrder.NewEventOrdersOfEventChanged(cmd.EventID)); err != nil {
+		logger.Warning(ctx, "Failed to publish orders change", logger.F("event_id", cmd.EventID), logger.F("error", err))
+	}
+
+	return ToEventCancellationResult(cancellation, nil), nil
+}
+
+// EventCancellationResult represents the progress of an event cancellation on the organizer dashboard
+type EventCancellationResult struct {
+	EventID     int64                        `json:"event_id"`
+	Status      domain.CancellationStatus    `json:"status"`
+	Reason      string                       `json:"reason"`
+	Progress    CancellationProgressResult   `json:"progress"`
+	Failures    []*CancellationFailureResult `json:"failures"`
+	CreatedAt   string                       `json:"created_at"`
+	CompletedAt *string                      `json:"completed_at,omitempty"`
+}
+
+// CancellationProgressResult counts the paid orders of the event by outcome
+type CancellationProgressResult struct {
+	TotalOrders         int `json:"total_orders"`
+	ProcessedOrders     int `json:"processed_orders"`
+	RefundsRequested    int `json:"refunds_requested"`
+	RefundsFailed       int `json:"refunds_failed"`
+	HoldersNotified     int `json:"holders_notified"`
+	NotificationsFailed int `json:"notifications_failed"`
+}
+
+// CancellationFailureResult is an order the organizer must settle by hand
+type CancellationFailureResult struct {
+	OrderID     int64                    `json:"order_id"`
+	OrderNumber string                   `json:"order_number"`
+	Stage       domain.CancellationStage `json:"stage"`
+	Error       string                   `json:"error"`
+	ProcessedAt string                   `json:"processed_at"`
+}
+
+// ToEventCancellationResult converts a cancellation and its failures to its result
+func ToEventCancellationResult(cancellation *domain.EventCancellation, failures []*domain.CancellationFailure) *EventCancellationResult {
+	progress := cancellation.Progress
+	result := &EventCancellationResult{
+		EventID: cancellation.EventID,
+		Status:  cancellation.Status,
+		Reason:  cancellation.Reason,
+		Progress: CancellationProgressResult{
+			TotalOrders:         progress.TotalOrders,
+			ProcessedOrders:     progress.ProcessedOrders,
+			RefundsRequested:    progress.RefundsRequested,
+			RefundsFailed:       progress.RefundsFailed,
+			HoldersNotified:     progress.HoldersNotified,
+			NotificationsFailed: progress.NotificationsFailed,
+		},
+		Failures:  make([]*CancellationFailureResult, len(failures)),
+		CreatedAt: cancellation.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+
+	if cancellation.CompletedAt != nil {
+		completedAt := cancellation.CompletedAt.Format("2006-01-02T15:04:05Z")
+		result.CompletedAt = &completedAt
+	}
+
+	for i, failure := range failures {
+		result.Failures[i] = &CancellationFailureResult{
+			OrderID:     failure.OrderID,
+			OrderNumber: failure.OrderNumber,
+			Stage:       failure.Stage,
+			Error:       failure.Error,
+			ProcessedAt: failure.ProcessedAt.Format("2006-01-02T15:04:05Z"),
+		}
+	}
+
+	return result
+}
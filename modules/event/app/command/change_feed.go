@@ -0,0 +1,27 @@
+package command
+
+import (
+	"context"
+	"strconv"
+
+	"tixgo/modules/event/domain"
+	sharedKafka "tixgo/shared/kafka"
+	"tixgo/shared/session"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+)
+
+// publishConfigurationChange announces a change of the configuration of the target to the change feed of
+// its event, made by actorID with the API key of the request if any, keyed by event so the feed records
+// the changes of an event in order. The change already succeeded, so a failure is only logged.
+func publishConfigurationChange(ctx context.Context, eventBus messaging.EventBus, target domain.SalesTarget, action domain.ChangeAction, fields []domain.FieldChange, actorID int64) {
+	apiKeyID, _ := session.APIKeyID(ctx)
+	change := domain.NewEventConfigurationChanged(target, action, fields, actorID, apiKeyID)
+
+	err := eventBus.PublishEvent(sharedKafka.WithPartitionKey(ctx, strconv.FormatInt(target.EventID, 10)), change)
+	if err != nil {
+		logger.Error(ctx, "Failed to publish event configuration change", logger.F("event_id", target.EventID),
+			logger.F("action", action), logger.F("error", err))
+	}
+}
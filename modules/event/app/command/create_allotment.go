@@ -0,0 +1,52 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// CreateAllotmentCommand represents the command of an organizer to set tickets of a category of their
+// event aside for press, sponsors or a guest list
+type CreateAllotmentCommand struct {
+	EventID          int64  `json:"-"`
+	OrganizerID      int64  `json:"-"`
+	TicketCategoryID int64  `json:"ticket_category_id" binding:"required"`
+	Name             string `json:"name" binding:"required,max=100"`
+	Kind             string `json:"kind" binding:"required"`
+	Quantity         int    `json:"quantity" binding:"required,min=1"`
+}
+
+// CreateAllotmentHandler handles allotment creation
+type CreateAllotmentHandler struct {
+	allotmentRepo domain.AllotmentRepository
+}
+
+// NewCreateAllotmentHandler creates a new create allotment handler
+func NewCreateAllotmentHandler(allotmentRepo domain.AllotmentRepository) *CreateAllotmentHandler {
+	return &CreateAllotmentHandler{
+		allotmentRepo: allotmentRepo,
+	}
+}
+
+// Handle executes the create allotment command. The tickets leave public sale at once, so only
+// tickets still available can be allotted.
+func (h *CreateAllotmentHandler) Handle(ctx context.Context, cmd CreateAllotmentCommand) (*AllotmentResult, error) {
+	allotment, err := domain.NewTicketAllotment(cmd.EventID, cmd.TicketCategoryID, cmd.Name, domain.AllotmentKind(cmd.Kind), cmd.Quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.allotmentRepo.Create(ctx, allotment, cmd.OrganizerID)
+	if err != nil {
+		switch err {
+		case domain.ErrEventNotFound, domain.ErrTicketCategoryNotFound, domain.ErrSoldOut, domain.ErrAllotmentNameTaken:
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to create allotment")
+	}
+
+	return ToAllotmentResult(allotment), nil
+}
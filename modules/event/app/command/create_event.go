@@ -0,0 +1,77 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// EventDetailsInput are the details of an event sent to create and update it
+type EventDetailsInput struct {
+	VenueID     *int64     `json:"venue_id"`
+	Title       string     `json:"title" binding:"required,max=255"`
+	Description string     `json:"description" binding:"max=10000"`
+	EventType   string     `json:"event_type" binding:"required"`
+	StartDate   time.Time  `json:"start_date" binding:"required"`
+	EndDate     *time.Time `json:"end_date"`
+	Timezone    string     `json:"timezone" binding:"required,max=50"`
+	Capacity    *int       `json:"capacity" binding:"required,min=1"`
+	// Currency is USD for new events and kept on updates when empty
+	Currency string `json:"currency" binding:"omitempty,len=3"`
+}
+
+// Details converts the input to the details of a domain event
+func (in EventDetailsInput) Details() domain.EventDetails {
+	return domain.EventDetails{
+		VenueID:     in.VenueID,
+		Title:       in.Title,
+		Description: in.Description,
+		EventType:   domain.EventType(in.EventType),
+		StartDate:   in.StartDate,
+		EndDate:     in.EndDate,
+		Timezone:    in.Timezone,
+		Capacity:    in.Capacity,
+		Currency:    in.Currency,
+	}
+}
+
+// CreateEventCommand represents the command of an organizer to create an event, as a draft
+type CreateEventCommand struct {
+	OrganizerID int64 `json:"-"`
+	// TestMode is set for the events created with a test mode API key
+	TestMode bool `json:"-"`
+	EventDetailsInput
+}
+
+// CreateEventHandler handles event creation
+type CreateEventHandler struct {
+	eventRepo domain.EventRepository
+}
+
+// NewCreateEventHandler creates a new create event handler
+func NewCreateEventHandler(eventRepo domain.EventRepository) *CreateEventHandler {
+	return &CreateEventHandler{
+		eventRepo: eventRepo,
+	}
+}
+
+// Handle executes the create event command
+func (h *CreateEventHandler) Handle(ctx context.Context, cmd CreateEventCommand) (*EventResult, error) {
+	event, err := domain.NewEvent(cmd.OrganizerID, cmd.Details(), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	event.TestMode = cmd.TestMode
+
+	if err := h.eventRepo.Create(ctx, event); err != nil {
+		if err == domain.ErrVenueNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to create event")
+	}
+
+	return ToEventResult(event), nil
+}
@@ -0,0 +1,59 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// CreateEventFromTemplateCommand represents the command of an organizer to schedule a new edition of
+// an event from one of their templates
+type CreateEventFromTemplateCommand struct {
+	TemplateID  int64  `json:"-"`
+	OrganizerID int64  `json:"-"`
+	Title       string `json:"title" binding:"omitempty,max=255"`
+	// StartDate is when the edition starts, the sales windows of the template follow it
+	StartDate time.Time `json:"start_date" binding:"required"`
+	// TestMode is set for the drafts created with a test mode API key
+	TestMode bool `json:"-"`
+}
+
+// CreateEventFromTemplateHandler handles creating events from templates
+type CreateEventFromTemplateHandler struct {
+	templateRepo  domain.EventTemplateRepository
+	blueprintRepo domain.EventBlueprintRepository
+}
+
+// NewCreateEventFromTemplateHandler creates a new create event from template handler
+func NewCreateEventFromTemplateHandler(templateRepo domain.EventTemplateRepository, blueprintRepo domain.EventBlueprintRepository) *CreateEventFromTemplateHandler {
+	return &CreateEventFromTemplateHandler{
+		templateRepo:  templateRepo,
+		blueprintRepo: blueprintRepo,
+	}
+}
+
+// Handle executes the create event from template command, creating a draft
+func (h *CreateEventFromTemplateHandler) Handle(ctx context.Context, cmd CreateEventFromTemplateCommand) (*EventDraftResult, error) {
+	template, err := h.templateRepo.GetByID(ctx, cmd.TemplateID, cmd.OrganizerID)
+	if err != nil {
+		if err == domain.ErrEventTemplateNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get event template")
+	}
+
+	draft, err := domain.NewEventDraft(cmd.OrganizerID, template.Blueprint, cmd.Title, cmd.StartDate)
+	if err != nil {
+		return nil, err
+	}
+	draft.TestMode = cmd.TestMode
+
+	if err := h.blueprintRepo.CreateDraft(ctx, draft); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to create event draft")
+	}
+
+	return ToEventDraftResult(draft), nil
+}
@@ -0,0 +1,52 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// CreateEventQuestionCommand represents the command of an organizer to add a question to the attendee
+// questionnaire of their event
+type CreateEventQuestionCommand struct {
+	EventID     int64    `json:"-"`
+	OrganizerID int64    `json:"-"`
+	Label       string   `json:"label" binding:"required,max=255"`
+	Kind        string   `json:"kind" binding:"required"`
+	Options     []string `json:"options"`
+	Required    bool     `json:"required"`
+	Position    int      `json:"position" binding:"min=0"`
+}
+
+// CreateEventQuestionHandler handles event question creation
+type CreateEventQuestionHandler struct {
+	questionRepo domain.EventQuestionRepository
+}
+
+// NewCreateEventQuestionHandler creates a new create event question handler
+func NewCreateEventQuestionHandler(questionRepo domain.EventQuestionRepository) *CreateEventQuestionHandler {
+	return &CreateEventQuestionHandler{
+		questionRepo: questionRepo,
+	}
+}
+
+// Handle executes the create event question command
+func (h *CreateEventQuestionHandler) Handle(ctx context.Context, cmd CreateEventQuestionCommand) (*EventQuestionResult, error) {
+	question, err := domain.NewEventQuestion(cmd.EventID, cmd.Label, domain.QuestionKind(cmd.Kind), cmd.Options, cmd.Required, cmd.Position)
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.questionRepo.Create(ctx, question, cmd.OrganizerID)
+	if err != nil {
+		switch err {
+		case domain.ErrEventNotFound, domain.ErrTooManyQuestions, domain.ErrQuestionLabelTaken:
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to create event question")
+	}
+
+	return ToEventQuestionResult(question), nil
+}
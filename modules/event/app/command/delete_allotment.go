@@ -0,0 +1,50 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// DeleteAllotmentCommand represents the command of an organizer to delete an allotment
+type DeleteAllotmentCommand struct {
+	ID          int64
+	EventID     int64
+	OrganizerID int64
+}
+
+// DeleteAllotmentHandler handles allotment deletions
+type DeleteAllotmentHandler struct {
+	allotmentRepo domain.AllotmentRepository
+}
+
+// NewDeleteAllotmentHandler creates a new delete allotment handler
+func NewDeleteAllotmentHandler(allotmentRepo domain.AllotmentRepository) *DeleteAllotmentHandler {
+	return &DeleteAllotmentHandler{
+		allotmentRepo: allotmentRepo,
+	}
+}
+
+// Handle executes the delete allotment command. The tickets not issued go back on sale, the issued
+// ones stay valid.
+func (h *DeleteAllotmentHandler) Handle(ctx context.Context, cmd DeleteAllotmentCommand) error {
+	allotment, err := h.allotmentRepo.GetByID(ctx, cmd.ID, cmd.EventID, cmd.OrganizerID)
+	if err != nil {
+		if err == domain.ErrAllotmentNotFound {
+			return err
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get allotment")
+	}
+
+	err = h.allotmentRepo.Delete(ctx, allotment)
+	if err != nil {
+		if err == domain.ErrAllotmentNotFound {
+			return err
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to delete allotment")
+	}
+
+	return nil
+}
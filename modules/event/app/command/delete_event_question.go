@@ -0,0 +1,50 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// DeleteEventQuestionCommand represents the command of an organizer to delete a question of their event
+type DeleteEventQuestionCommand struct {
+	ID          int64
+	EventID     int64
+	OrganizerID int64
+}
+
+// DeleteEventQuestionHandler handles event question deletions
+type DeleteEventQuestionHandler struct {
+	questionRepo domain.EventQuestionRepository
+}
+
+// NewDeleteEventQuestionHandler creates a new delete event question handler
+func NewDeleteEventQuestionHandler(questionRepo domain.EventQuestionRepository) *DeleteEventQuestionHandler {
+	return &DeleteEventQuestionHandler{
+		questionRepo: questionRepo,
+	}
+}
+
+// Handle executes the delete event question command. The answers given to the question are deleted
+// with it.
+func (h *DeleteEventQuestionHandler) Handle(ctx context.Context, cmd DeleteEventQuestionCommand) error {
+	question, err := h.questionRepo.GetByID(ctx, cmd.ID, cmd.EventID, cmd.OrganizerID)
+	if err != nil {
+		if err == domain.ErrQuestionNotFound {
+			return err
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get event question")
+	}
+
+	err = h.questionRepo.Delete(ctx, question)
+	if err != nil {
+		if err == domain.ErrQuestionNotFound {
+			return err
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to delete event question")
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// DeleteEventTemplateCommand represents the command of an organizer to delete one of their templates
+type DeleteEventTemplateCommand struct {
+	ID          int64
+	OrganizerID int64
+}
+
+// DeleteEventTemplateHandler handles deleting event templates
+type DeleteEventTemplateHandler struct {
+	templateRepo domain.EventTemplateRepository
+}
+
+// NewDeleteEventTemplateHandler creates a new delete event template handler
+func NewDeleteEventTemplateHandler(templateRepo domain.EventTemplateRepository) *DeleteEventTemplateHandler {
+	return &DeleteEventTemplateHandler{
+		templateRepo: templateRepo,
+	}
+}
+
+// Handle executes the delete event template command, the events created from the template are kept
+func (h *DeleteEventTemplateHandler) Handle(ctx context.Context, cmd DeleteEventTemplateCommand) error {
+	err := h.templateRepo.Delete(ctx, cmd.ID, cmd.OrganizerID)
+	if err != nil {
+		if err == domain.ErrEventTemplateNotFound {
+			return err
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to delete event template")
+	}
+
+	return nil
+}
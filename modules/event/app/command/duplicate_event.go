@@ -0,0 +1,66 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// DuplicateEventCommand represents the command of an organizer to copy the structure of their event
+// into a new draft
+type DuplicateEventCommand struct {
+	EventID     int64  `json:"-"`
+	OrganizerID int64  `json:"-"`
+	Title       string `json:"title" binding:"omitempty,max=255"`
+	// StartDate defaults to the start of the event copied, which must then be in the future
+	StartDate *time.Time `json:"start_date"`
+	// TestMode is set for the drafts created with a test mode API key
+	TestMode bool `json:"-"`
+}
+
+// DuplicateEventHandler handles event duplications
+type DuplicateEventHandler struct {
+	blueprintRepo domain.EventBlueprintRepository
+}
+
+// NewDuplicateEventHandler creates a new duplicate event handler
+func NewDuplicateEventHandler(blueprintRepo domain.EventBlueprintRepository) *DuplicateEventHandler {
+	return &DuplicateEventHandler{
+		blueprintRepo: blueprintRepo,
+	}
+}
+
+// Handle executes the duplicate event command. The details, ticket categories and seat map are copied,
+// nothing of the sales: the draft starts with all its tickets available.
+func (h *DuplicateEventHandler) Handle(ctx context.Context, cmd DuplicateEventCommand) (*EventDraftResult, error) {
+	blueprint, startDate, err := h.blueprintRepo.GetBlueprint(ctx, cmd.EventID, cmd.OrganizerID)
+	if err != nil {
+		if err == domain.ErrEventNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get event blueprint")
+	}
+
+	title := cmd.Title
+	if title == "" {
+		title = domain.CopyTitle(blueprint.Title)
+	}
+	if cmd.StartDate != nil {
+		startDate = *cmd.StartDate
+	}
+
+	draft, err := domain.NewEventDraft(cmd.OrganizerID, *blueprint, title, startDate)
+	if err != nil {
+		return nil, err
+	}
+	draft.TestMode = cmd.TestMode
+
+	if err := h.blueprintRepo.CreateDraft(ctx, draft); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to create event draft")
+	}
+
+	return ToEventDraftResult(draft), nil
+}
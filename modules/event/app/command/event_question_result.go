@@ -0,0 +1,29 @@
+package command
+
+import "tixgo/modules/event/domain"
+
+// EventQuestionResult represents a question of the questionnaire of an event
+type EventQuestionResult struct {
+	ID        int64               `json:"id"`
+	Label     string              `json:"label"`
+	Kind      domain.QuestionKind `json:"kind"`
+	Options   []string            `json:"options"`
+	Required  bool                `json:"required"`
+	Position  int                 `json:"position"`
+	CreatedAt string              `json:"created_at"`
+	UpdatedAt string              `json:"updated_at"`
+}
+
+// ToEventQuestionResult converts a question to its result
+func ToEventQuestionResult(question *domain.EventQuestion) *EventQuestionResult {
+	return &EventQuestionResult{
+		ID:        question.ID,
+		Label:     question.Label,
+		Kind:      question.Kind,
+		Options:   question.Options,
+		Required:  question.Required,
+		Position:  question.Position,
+		CreatedAt: question.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt: question.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
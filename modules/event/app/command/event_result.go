@@ -0,0 +1,53 @@
+package command
+
+import (
+	"tixgo/modules/event/domain"
+)
+
+// EventResult represents an event of an organizer
+type EventResult struct {
+	ID          int64              `json:"id"`
+	VenueID     *int64             `json:"venue_id"`
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	EventType   domain.EventType   `json:"event_type"`
+	Status      domain.EventStatus `json:"status"`
+	Slug        *string            `json:"slug"`
+	StartDate   string             `json:"start_date"`
+	EndDate     *string            `json:"end_date"`
+	Timezone    string             `json:"timezone"`
+	Capacity    *int               `json:"capacity"`
+	Currency    string             `json:"currency"`
+	TestMode    bool               `json:"test_mode"`
+	CreatedAt   string             `json:"created_at"`
+	UpdatedAt   string             `json:"updated_at"`
+}
+
+// ToEventResult converts an event to its result
+func ToEventResult(event *domain.Event) *EventResult {
+	result := &EventResult{
+		ID:          event.ID,
+		VenueID:     event.VenueID,
+		Title:       event.Title,
+		Description: event.Description,
+		EventType:   event.EventType,
+		Status:      event.Status,
+		StartDate:   event.StartDate.Format("2006-01-02T15:04:05Z"),
+		Timezone:    event.Timezone,
+		Capacity:    event.Capacity,
+		Currency:    event.Currency,
+		TestMode:    event.TestMode,
+		CreatedAt:   event.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:   event.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+
+	if event.Slug != "" {
+		result.Slug = &event.Slug
+	}
+	if event.EndDate != nil {
+		endDate := event.EndDate.Format("2006-01-02T15:04:05Z")
+		result.EndDate = &endDate
+	}
+
+	return result
+}
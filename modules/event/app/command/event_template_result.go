@@ -0,0 +1,82 @@
+package command
+
+import (
+	"tixgo/modules/event/domain"
+)
+
+// EventDraftResult represents a draft event created from a blueprint
+type EventDraftResult struct {
+	ID               int64                            `json:"id"`
+	Title            string                           `json:"title"`
+	Status           domain.EventStatus               `json:"status"`
+	StartDate        string                           `json:"start_date"`
+	EndDate          *string                          `json:"end_date"`
+	TicketCategories []*BlueprintTicketCategoryResult `json:"ticket_categories"`
+	CreatedAt        string                           `json:"created_at"`
+}
+
+// EventTemplateResult represents an event template of an organizer
+type EventTemplateResult struct {
+	ID               int64                            `json:"id"`
+	Name             string                           `json:"name"`
+	SourceEventID    *int64                           `json:"source_event_id"`
+	Title            string                           `json:"title"`
+	EventType        string                           `json:"event_type"`
+	TicketCategories []*BlueprintTicketCategoryResult `json:"ticket_categories"`
+	CreatedAt        string                           `json:"created_at"`
+}
+
+// BlueprintTicketCategoryResult summarizes a ticket category of a blueprint, its seats counted
+type BlueprintTicketCategoryResult struct {
+	Name         string `json:"name"`
+	CategoryType string `json:"category_type"`
+	Price        string `json:"price"`
+	Quantity     int    `json:"quantity"`
+	Seats        int    `json:"seats"`
+}
+
+// ToEventDraftResult converts a draft to its result
+func ToEventDraftResult(draft *domain.EventDraft) *EventDraftResult {
+	result := &EventDraftResult{
+		ID:               draft.ID,
+		Title:            draft.Blueprint.Title,
+		Status:           domain.EventStatusDraft,
+		StartDate:        draft.StartDate.Format("2006-01-02T15:04:05Z"),
+		TicketCategories: toBlueprintTicketCategoryResults(&draft.Blueprint),
+		CreatedAt:        draft.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+
+	if draft.EndDate != nil {
+		endDate := draft.EndDate.Format("2006-01-02T15:04:05Z")
+		result.EndDate = &endDate
+	}
+
+	return result
+}
+
+// ToEventTemplateResult converts a template to its result
+func ToEventTemplateResult(template *domain.EventTemplate) *EventTemplateResult {
+	return &EventTemplateResult{
+		ID:               template.ID,
+		Name:             template.Name,
+		SourceEventID:    template.SourceEventID,
+		Title:            template.Blueprint.Title,
+		EventType:        template.Blueprint.EventType,
+		TicketCategories: toBlueprintTicketCategoryResults(&template.Blueprint),
+		CreatedAt:        template.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+func toBlueprintTicketCategoryResults(blueprint *domain.EventBlueprint) []*BlueprintTicketCategoryResult {
+	results := make([]*BlueprintTicketCategoryResult, len(blueprint.TicketCategories))
+	for i, category := range blueprint.TicketCategories {
+		results[i] = &BlueprintTicketCategoryResult{
+			Name:         category.Name,
+			CategoryType: category.CategoryType,
+			Price:        category.Price,
+			Quantity:     category.Quantity,
+			Seats:        len(category.Seats),
+		}
+	}
+	return results
+}
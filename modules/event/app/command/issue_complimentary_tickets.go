@@ -0,0 +1,145 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/event/domain"
+	templateDomain "tixgo/modules/template/domain"
+	sharedMail "tixgo/shared/events/mail"
+	sharedKafka "tixgo/shared/kafka"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/notification/mail"
+	"github.com/duongptryu/gox/syserr"
+)
+
+const (
+	SlugComplimentaryTickets = "complimentary-tickets"
+)
+
+// IssueComplimentaryTicketsCommand represents the command of an organizer to send free tickets of their
+// event to someone by email
+type IssueComplimentaryTicketsCommand struct {
+	EventID          int64 `json:"-"`
+	OrganizerID      int64 `json:"-"`
+	TicketCategoryID int64 `json:"ticket_category_id" binding:"required"`
+	// AllotmentID is the allotment of the category the tickets are taken from, the public stock otherwise
+	AllotmentID *int64 `json:"allotment_id"`
+	Email       string `json:"email" binding:"required,email"`
+	Quantity    int    `json:"quantity" binding:"required,min=1"`
+	Note        string `json:"note" binding:"max=1000"`
+}
+
+// IssueComplimentaryTicketsHandler handles complimentary ticket issues
+type IssueComplimentaryTicketsHandler struct {
+	complimentaryRepo domain.ComplimentaryTicketRepository
+	templateRepo      templateDomain.TemplateRepository
+	templateRenderer  templateDomain.TemplateRenderer
+	eventBus          messaging.EventBus
+}
+
+// NewIssueComplimentaryTicketsHandler creates a new issue complimentary tickets handler
+func NewIssueComplimentaryTicketsHandler(complimentaryRepo domain.ComplimentaryTicketRepository, templateRepo templateDomain.TemplateRepository, templateRenderer templateDomain.TemplateRenderer, eventBus messaging.EventBus) *IssueComplimentaryTicketsHandler {
+	return &IssueComplimentaryTicketsHandler{
+		complimentaryRepo: complimentaryRepo,
+		templateRepo:      templateRepo,
+		templateRenderer:  templateRenderer,
+		eventBus:          eventBus,
+	}
+}
+
+// Handle executes the issue complimentary tickets command. The tickets are issued as a confirmed order
+// at zero amount, then go through what follows any confirmed order: the order read models and seat maps
+// are updated and the tickets are mailed to the recipient. The tickets stand once issued, so a failed
+// delivery is logged and reported in the result for the organizer to send them again.
+func (h *IssueComplimentaryTicketsHandler) Handle(ctx context.Context, cmd IssueComplimentaryTicketsCommand) (*ComplimentaryIssueResult, error) {
+	issue, err := domain.NewComplimentaryIssue(cmd.EventID, cmd.OrganizerID, cmd.TicketCategoryID, cmd.AllotmentID, cmd.Email, cmd.Quantity, cmd.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.complimentaryRepo.Issue(ctx, issue)
+	if err != nil {
+		switch err {
+		case domain.ErrEventNotFound, domain.ErrTicketCategoryNotFound, domain.ErrEventClosed,
+			domain.ErrAllotmentNotFound, domain.ErrAllotmentExhausted, domain.ErrSoldOut:
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to issue complimentary tickets")
+	}
+
+	publishSoldTickets(ctx, h.eventBus, issue.EventID, issue.OrderID, issue.Tickets)
+
+	result := ToComplimentaryIssueResult(issue)
+	if err := h.deliver(ctx, issue); err != nil {
+		logger.Error(ctx, "Failed to deliver complimentary tickets", logger.F("order_id", issue.OrderID), logger.F("error", err))
+		result.DeliveryFailed = true
+	}
+
+	return result, nil
+}
+
+// deliver mails the tickets to the recipient with the identity of the organizer
+func (h *IssueComplimentaryTicketsHandler) deliver(ctx context.Context, issue *domain.ComplimentaryIssue) error {
+	template, err := h.templateRepo.GetBySlug(ctx, SlugComplimentaryTickets)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get template")
+	}
+
+	rendered, err := h.templateRenderer.Render(ctx, template, map[string]interface{}{
+		"event_title":     issue.EventTitle,
+		"ticket_category": issue.TicketCategoryName,
+		"order_number":    issue.OrderNumber,
+		"quantity":        len(issue.Tickets),
+		"tickets":         ticketsTemplateData(issue.Tickets),
+		"note":            issue.Note,
+	}, templateDomain.RenderOptions{OrganizerID: issue.OrganizerID})
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to render template")
+	}
+
+	err = h.eventBus.PublishEvent(sharedKafka.WithPartitionKey(ctx, issue.Email), &sharedMail.EventSendMail{
+		ToMail: []mail.EmailAddress{
+			{
+				Email: issue.Email,
+				Name:  "",
+			},
+		},
+		Subject:     rendered.Subject,
+		HTMLBody:    rendered.Content,
+		Priority:    mail.PriorityHigh,
+		OrganizerID: issue.OrganizerID,
+	})
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to publish send mail event")
+	}
+
+	return nil
+}
+
+// ComplimentaryIssueResult represents the order of issued complimentary tickets
+type ComplimentaryIssueResult struct {
+	OrderID          int64                 `json:"order_id"`
+	OrderNumber      string                `json:"order_number"`
+	TicketCategoryID int64                 `json:"ticket_category_id"`
+	AllotmentID      *int64                `json:"allotment_id"`
+	Email            string                `json:"email"`
+	Tickets          []*IssuedTicketResult `json:"tickets"`
+	// DeliveryFailed tells that the tickets were issued but could not be mailed
+	DeliveryFailed bool   `json:"delivery_failed"`
+	IssuedAt       string `json:"issued_at"`
+}
+
+// ToComplimentaryIssueResult converts an issue to its result
+func ToComplimentaryIssueResult(issue *domain.ComplimentaryIssue) *ComplimentaryIssueResult {
+	return &ComplimentaryIssueResult{
+		OrderID:          issue.OrderID,
+		OrderNumber:      issue.OrderNumber,
+		TicketCategoryID: issue.TicketCategoryID,
+		AllotmentID:      issue.AllotmentID,
+		Email:            issue.Email,
+		Tickets:          ToIssuedTicketResults(issue.Tickets),
+		IssuedAt:         issue.IssuedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
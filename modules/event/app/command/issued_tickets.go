@@ -0,0 +1,76 @@
+package command
+
+import (
+	"context"
+	"strconv"
+
+	"tixgo/modules/event/domain"
+	sharedOrder "tixgo/shared/events/order"
+	sharedKafka "tixgo/shared/kafka"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+)
+
+// IssuedTicketResult represents a ticket of an order created by the organizer with its seat, if any
+type IssuedTicketResult struct {
+	ID               int64  `json:"id"`
+	TicketCategoryID int64  `json:"ticket_category_id"`
+	TicketCategory   string `json:"ticket_category"`
+	TicketNumber     string `json:"ticket_number"`
+	SeatSection      string `json:"seat_section,omitempty"`
+	SeatRow          string `json:"seat_row,omitempty"`
+	SeatNumber       string `json:"seat_number,omitempty"`
+}
+
+// ToIssuedTicketResults converts issued tickets to their results
+func ToIssuedTicketResults(tickets []*domain.IssuedTicket) []*IssuedTicketResult {
+	results := make([]*IssuedTicketResult, len(tickets))
+	for i, ticket := range tickets {
+		results[i] = &IssuedTicketResult{
+			ID:               ticket.ID,
+			TicketCategoryID: ticket.TicketCategoryID,
+			TicketCategory:   ticket.TicketCategoryName,
+			TicketNumber:     ticket.TicketNumber,
+			SeatSection:      ticket.SeatSection,
+			SeatRow:          ticket.SeatRow,
+			SeatNumber:       ticket.SeatNumber,
+		}
+	}
+	return results
+}
+
+// publishSoldTickets publishes what follows any confirmed order for an order created by the organizer:
+// the order read models are updated and the seats taken shown sold on the seat map. The order stands
+// either way, so failures are only logged.
+func publishSoldTickets(ctx context.Context, eventBus messaging.EventBus, eventID, orderID int64, tickets []*domain.IssuedTicket) {
+	if err := eventBus.PublishEvent(ctx, sharedOrder.NewEventOrdersChanged(orderID)); err != nil {
+		logger.Warning(ctx, "Failed to publish orders change", logger.F("order_id", orderID), logger.F("error", err))
+	}
+
+	key := strconv.FormatInt(eventID, 10)
+	for _, ticket := range tickets {
+		if ticket.SeatSection == "" {
+			continue
+		}
+		err := eventBus.PublishEvent(sharedKafka.WithPartitionKey(ctx, key), domain.NewEventSeatStatusChanged(eventID, ticket.ID, domain.SeatStatusSold))
+		if err != nil {
+			logger.Warning(ctx, "Failed to publish seat status change", logger.F("event_id", eventID), logger.F("ticket_id", ticket.ID), logger.F("error", err))
+		}
+	}
+}
+
+// ticketsTemplateData returns the tickets as the variables of a mail template
+func ticketsTemplateData(tickets []*domain.IssuedTicket) []map[string]interface{} {
+	data := make([]map[string]interface{}, len(tickets))
+	for i, ticket := range tickets {
+		data[i] = map[string]interface{}{
+			"ticket_category": ticket.TicketCategoryName,
+			"ticket_number":   ticket.TicketNumber,
+			"seat_section":    ticket.SeatSection,
+			"seat_row":        ticket.SeatRow,
+			"seat_number":     ticket.SeatNumber,
+		}
+	}
+	return data
+}
@@ -0,0 +1,84 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// JoinQueueCommand represents the command to join the on-sale queue of an event
+type JoinQueueCommand struct {
+	EventID int64
+	UserID  int64
+}
+
+// QueueTicketResult is the place of the buyer in the queue. The token identifies it in later calls.
+type QueueTicketResult struct {
+	Token                string  `json:"token"`
+	Position             int64   `json:"position"`
+	Admitted             bool    `json:"admitted"`
+	ExpiresAt            *string `json:"expires_at,omitempty"`
+	EstimatedWaitSeconds int64   `json:"estimated_wait_seconds"`
+}
+
+// JoinQueueHandler handles joining on-sale queues
+type JoinQueueHandler struct {
+	settingsRepo domain.QueueSettingsRepository
+	queue        domain.OnSaleQueue
+}
+
+// NewJoinQueueHandler creates a new join queue handler
+func NewJoinQueueHandler(settingsRepo domain.QueueSettingsRepository, queue domain.OnSaleQueue) *JoinQueueHandler {
+	return &JoinQueueHandler{
+		settingsRepo: settingsRepo,
+		queue:        queue,
+	}
+}
+
+// Handle executes the join queue command. Joining again returns the place the buyer already has.
+func (h *JoinQueueHandler) Handle(ctx context.Context, cmd JoinQueueCommand) (*QueueTicketResult, error) {
+	settings, err := getQueueSettings(ctx, h.settingsRepo, cmd.EventID)
+	if err != nil {
+		return nil, err
+	}
+
+	ticket, err := h.queue.Join(ctx, settings, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToQueueTicketResult(settings, ticket), nil
+}
+
+// getQueueSettings returns the settings of an event whose queue is enabled
+func getQueueSettings(ctx context.Context, settingsRepo domain.QueueSettingsRepository, eventID int64) (*domain.QueueSettings, error) {
+	settings, err := settingsRepo.GetQueueSettings(ctx, eventID)
+	if err != nil {
+		if err == domain.ErrQueueDisabled {
+			return nil, domain.ErrQueueDisabled
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get queue settings")
+	}
+	if !settings.Enabled {
+		return nil, domain.ErrQueueDisabled
+	}
+	return settings, nil
+}
+
+// ToQueueTicketResult converts a queue ticket to its result
+func ToQueueTicketResult(settings *domain.QueueSettings, ticket *domain.QueueTicket) *QueueTicketResult {
+	result := &QueueTicketResult{
+		Token:                ticket.Token,
+		Position:             ticket.Position,
+		Admitted:             ticket.Admitted,
+		EstimatedWaitSeconds: int64(settings.EstimatedWait(ticket.Position) / time.Second),
+	}
+	if ticket.ExpiresAt != nil {
+		expiresAt := ticket.ExpiresAt.Format("2006-01-02T15:04:05Z")
+		result.ExpiresAt = &expiresAt
+	}
+	return result
+}
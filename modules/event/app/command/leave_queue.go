@@ -0,0 +1,32 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/event/domain"
+)
+
+// LeaveQueueCommand represents the command to leave an on-sale queue
+type LeaveQueueCommand struct {
+	EventID int64
+	UserID  int64
+	Token   string
+}
+
+// LeaveQueueHandler handles leaving on-sale queues
+type LeaveQueueHandler struct {
+	queue domain.OnSaleQueue
+}
+
+// NewLeaveQueueHandler creates a new leave queue handler
+func NewLeaveQueueHandler(queue domain.OnSaleQueue) *LeaveQueueHandler {
+	return &LeaveQueueHandler{
+		queue: queue,
+	}
+}
+
+// Handle executes the leave queue command: the place or checkout slot goes to the next buyer
+// and the reserved tickets back to the stock
+func (h *LeaveQueueHandler) Handle(ctx context.Context, cmd LeaveQueueCommand) error {
+	return h.queue.Leave(ctx, cmd.EventID, cmd.UserID, cmd.Token)
+}
@@ -0,0 +1,147 @@
+package command
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"tixgo/modules/event/domain"
+	templateDomain "tixgo/modules/template/domain"
+	sharedMail "tixgo/shared/events/mail"
+	sharedKafka "tixgo/shared/kafka"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/notification/mail"
+	"github.com/duongptryu/gox/syserr"
+)
+
+const (
+	SlugEventCancelled = "event-cancelled"
+
+	// cancellationBatchSize is how many orders are refunded per batch
+	cancellationBatchSize = 100
+	// cancellationClaimTimeout is how long a claimed batch may take before another run takes it over
+	cancellationClaimTimeout = 10 * time.Minute
+
+	// testModeSubjectPrefix watermarks the mails about test mode orders
+	testModeSubjectPrefix = "[TEST] "
+)
+
+// ProcessEventCancellationsHandler refunds the paid orders of cancelled events and notifies their holders
+type ProcessEventCancellationsHandler struct {
+	cancellationRepo domain.EventCancellationRepository
+	templateRepo     templateDomain.TemplateRepository
+	templateRenderer templateDomain.TemplateRenderer
+	eventBus         messaging.EventBus
+}
+
+// NewProcessEventCancellationsHandler creates a new process event cancellations handler
+func NewProcessEventCancellationsHandler(cancellationRepo domain.EventCancellationRepository, templateRepo templateDomain.TemplateRepository, templateRenderer templateDomain.TemplateRenderer, eventBus messaging.EventBus) *ProcessEventCancellationsHandler {
+	return &ProcessEventCancellationsHandler{
+		cancellationRepo: cancellationRepo,
+		templateRepo:     templateRepo,
+		templateRenderer: templateRenderer,
+		eventBus:         eventBus,
+	}
+}
+
+// Handle processes the queued orders batch by batch until none is left or ctx is done. Each order gets
+// its refund requested from the payment integration and its holder mailed; failures are recorded on
+// the order for the organizer instead of failing the batch. Test mode orders are refunded without
+// the payment integration.
+func (h *ProcessEventCancellationsHandler) Handle(ctx context.Context) error {
+	for ctx.Err() == nil {
+		orders, err := h.cancellationRepo.ClaimOrders(ctx, cancellationBatchSize, cancellationClaimTimeout)
+		if err != nil {
+			return syserr.Wrap(err, syserr.InternalCode, "failed to claim cancellation orders")
+		}
+
+		results := make([]*domain.CancellationOrderResult, len(orders))
+		for i, order := range orders {
+			results[i] = h.process(ctx, order)
+		}
+
+		// completing also closes the cancellations without orders left, so it runs for empty batches too
+		if err := h.cancellationRepo.CompleteOrders(ctx, results); err != nil {
+			return syserr.Wrap(err, syserr.InternalCode, "failed to complete cancellation orders")
+		}
+
+		if len(orders) > 0 {
+			logger.Info(ctx, "Processed cancelled event orders", logger.F("count", len(orders)))
+		}
+		if len(orders) < cancellationBatchSize {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (h *ProcessEventCancellationsHandler) process(ctx context.Context, order *domain.CancellationOrder) *domain.CancellationOrderResult {
+	result := &domain.CancellationOrderResult{ID: order.ID, TestMode: order.TestMode}
+
+	if order.RefundID == nil {
+		result.RefundError = "the order has no completed payment"
+	} else if !order.TestMode {
+		key := strconv.FormatInt(order.OrderID, 10)
+		if err := h.eventBus.PublishEvent(sharedKafka.WithPartitionKey(ctx, key), domain.NewEventRefundRequested(order)); err != nil {
+			logger.Error(ctx, "Failed to request refund", logger.F("order_id", order.OrderID), logger.F("error", err))
+			result.RefundError = "failed to request the refund"
+		}
+	}
+
+	if err := h.notify(ctx, order, result.RefundError == ""); err != nil {
+		logger.Error(ctx, "Failed to notify ticket holder", logger.F("order_id", order.OrderID), logger.F("error", err))
+		result.NotificationError = "failed to send the cancellation notice"
+	}
+
+	return result
+}
+
+// notify mails the holder of an order that the event is cancelled and whether a refund is on its way
+func (h *ProcessEventCancellationsHandler) notify(ctx context.Context, order *domain.CancellationOrder, refunded bool) error {
+	// box office customers may have left no email, there is no one to notify
+	if order.Email == "" {
+		return nil
+	}
+
+	template, err := h.templateRepo.GetBySlug(ctx, SlugEventCancelled)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get template")
+	}
+
+	rendered, err := h.templateRenderer.Render(ctx, template, map[string]interface{}{
+		"event_title":   order.EventTitle,
+		"reason":        order.Reason,
+		"order_number":  order.OrderNumber,
+		"refunded":      refunded,
+		"refund_amount": order.RefundAmount,
+		"currency":      order.Currency,
+	}, templateDomain.RenderOptions{OrganizerID: order.OrganizerID})
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to render template")
+	}
+
+	subject := rendered.Subject
+	if order.TestMode {
+		subject = testModeSubjectPrefix + subject
+	}
+
+	err = h.eventBus.PublishEvent(sharedKafka.WithPartitionKey(ctx, order.Email), &sharedMail.EventSendMail{
+		ToMail: []mail.EmailAddress{
+			{
+				Email: order.Email,
+				Name:  "",
+			},
+		},
+		Subject:     subject,
+		HTMLBody:    rendered.Content,
+		Priority:    mail.PriorityHigh,
+		OrganizerID: order.OrganizerID,
+	})
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to publish send mail event")
+	}
+
+	return nil
+}
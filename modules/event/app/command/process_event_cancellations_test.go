@@ -0,0 +1,237 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"tixgo/modules/event/domain"
+	templateAdapters "tixgo/modules/template/adapters"
+	templateDomain "tixgo/modules/template/domain"
+	sharedMail "tixgo/shared/events/mail"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init(&logger.Config{Output: io.Discard})
+	os.Exit(m.Run())
+}
+
+// queuedCancellations hands out its batches of orders in turn and keeps the results completed
+type queuedCancellations struct {
+	domain.EventCancellationRepository
+	batches   [][]*domain.CancellationOrder
+	limits    []int
+	completed [][]*domain.CancellationOrderResult
+}
+
+func (r *queuedCancellations) ClaimOrders(_ context.Context, limit int, _ time.Duration) ([]*domain.CancellationOrder, error) {
+	r.limits = append(r.limits, limit)
+	if len(r.batches) == 0 {
+		return nil, nil
+	}
+	batch := r.batches[0]
+	r.batches = r.batches[1:]
+	return batch, nil
+}
+
+func (r *queuedCancellations) CompleteOrders(_ context.Context, results []*domain.CancellationOrderResult) error {
+	r.completed = append(r.completed, results)
+	return nil
+}
+
+// cancelledTemplateRepository serves the cancellation notice
+type cancelledTemplateRepository struct {
+	templateDomain.TemplateRepository
+}
+
+func (cancelledTemplateRepository) GetBySlug(_ context.Context, slug string) (*templateDomain.Template, error) {
+	if slug != SlugEventCancelled {
+		return nil, templateDomain.ErrTemplateNotFound
+	}
+	return &templateDomain.Template{
+		Slug:    slug,
+		Subject: "{{.event_title}} is cancelled",
+		Content: `{{.order_number}}: {{if .refunded}}{{.refund_amount}} {{.currency}} refunded{{else}}no refund{{end}}`,
+	}, nil
+}
+
+// recordingBus keeps the events it is asked to publish, failing the ones fail picks
+type recordingBus struct {
+	published []any
+	fail      func(event any) bool
+}
+
+func (b *recordingBus) PublishEvent(_ context.Context, event any) error {
+	if b.fail != nil && b.fail(event) {
+		return errors.New("broker unavailable")
+	}
+	b.published = append(b.published, event)
+	return nil
+}
+
+func (b *recordingBus) refunds() []*domain.EventRefundRequested {
+	var refunds []*domain.EventRefundRequested
+	for _, event := range b.published {
+		if refund, ok := event.(*domain.EventRefundRequested); ok {
+			refunds = append(refunds, refund)
+		}
+	}
+	return refunds
+}
+
+func (b *recordingBus) mails() []*sharedMail.EventSendMail {
+	var mails []*sharedMail.EventSendMail
+	for _, event := range b.published {
+		if mail, ok := event.(*sharedMail.EventSendMail); ok {
+			mails = append(mails, mail)
+		}
+	}
+	return mails
+}
+
+// cancellationOrder is a paid order of a cancelled concert
+func cancellationOrder(id int64, email string) *domain.CancellationOrder {
+	refundID, paymentID := id*10, id*100
+	return &domain.CancellationOrder{
+		ID:           id,
+		EventTitle:   "Jazz Night",
+		OrganizerID:  3,
+		Reason:       "storm",
+		OrderID:      id + 1000,
+		OrderNumber:  fmt.Sprintf("ORD-%d", id),
+		Email:        email,
+		RefundID:     &refundID,
+		PaymentID:    &paymentID,
+		RefundAmount: "75.50",
+		Currency:     "EUR",
+	}
+}
+
+func newProcessFixture(batches ...[]*domain.CancellationOrder) (*ProcessEventCancellationsHandler, *queuedCancellations, *recordingBus) {
+	repo := &queuedCancellations{batches: batches}
+	bus := &recordingBus{}
+	handler := NewProcessEventCancellationsHandler(repo, cancelledTemplateRepository{}, templateAdapters.NewHTMLTemplateRenderer(nil, nil, nil), bus)
+	return handler, repo, bus
+}
+
+func TestProcessEventCancellationsHandler(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("requests the refund of the payment and notifies the holder", func(t *testing.T) {
+		handler, repo, bus := newProcessFixture([]*domain.CancellationOrder{cancellationOrder(1, "fan@example.com")})
+
+		require.NoError(t, handler.Handle(ctx))
+
+		refunds := bus.refunds()
+		require.Len(t, refunds, 1)
+		assert.Equal(t, int64(10), refunds[0].RefundID)
+		assert.Equal(t, int64(100), refunds[0].PaymentID)
+		assert.Equal(t, int64(1001), refunds[0].OrderID)
+		assert.Equal(t, "75.50", refunds[0].Amount, "the payment is refunded in full")
+		assert.Equal(t, "EUR", refunds[0].Currency)
+		assert.Equal(t, "storm", refunds[0].Reason)
+
+		mails := bus.mails()
+		require.Len(t, mails, 1)
+		assert.Equal(t, "fan@example.com", mails[0].ToMail[0].Email)
+		assert.Equal(t, "Jazz Night is cancelled", mails[0].Subject)
+		assert.Contains(t, mails[0].HTMLBody, "75.50 EUR refunded")
+		assert.Equal(t, int64(3), mails[0].OrganizerID)
+
+		require.Len(t, repo.completed, 1)
+		assert.Equal(t, []*domain.CancellationOrderResult{{ID: 1}}, repo.completed[0])
+	})
+
+	t.Run("settles test mode refunds without the payment integration", func(t *testing.T) {
+		order := cancellationOrder(2, "tester@example.com")
+		order.TestMode = true
+		handler, repo, bus := newProcessFixture([]*domain.CancellationOrder{order})
+
+		require.NoError(t, handler.Handle(ctx))
+
+		assert.Empty(t, bus.refunds())
+		mails := bus.mails()
+		require.Len(t, mails, 1)
+		assert.Equal(t, "[TEST] Jazz Night is cancelled", mails[0].Subject)
+		assert.Contains(t, mails[0].HTMLBody, "75.50 EUR refunded")
+		assert.Equal(t, []*domain.CancellationOrderResult{{ID: 2, TestMode: true}}, repo.completed[0])
+	})
+
+	t.Run("reports orders without a completed payment", func(t *testing.T) {
+		order := cancellationOrder(3, "fan@example.com")
+		order.RefundID, order.PaymentID, order.RefundAmount = nil, nil, "0"
+		handler, repo, bus := newProcessFixture([]*domain.CancellationOrder{order})
+
+		require.NoError(t, handler.Handle(ctx))
+
+		assert.Empty(t, bus.refunds())
+		require.Len(t, bus.mails(), 1)
+		assert.Contains(t, bus.mails()[0].HTMLBody, "no refund", "the holder is not promised a refund")
+		assert.Equal(t, "the order has no completed payment", repo.completed[0][0].RefundError)
+		assert.Empty(t, repo.completed[0][0].NotificationError)
+	})
+
+	t.Run("records the failures of an order without failing the batch", func(t *testing.T) {
+		failing, other := cancellationOrder(4, "fan@example.com"), cancellationOrder(5, "other@example.com")
+		handler, repo, bus := newProcessFixture([]*domain.CancellationOrder{failing, other})
+		bus.fail = func(event any) bool {
+			switch event := event.(type) {
+			case *domain.EventRefundRequested:
+				return event.OrderID == failing.OrderID
+			case *sharedMail.EventSendMail:
+				return event.ToMail[0].Email == "fan@example.com"
+			}
+			return false
+		}
+
+		require.NoError(t, handler.Handle(ctx))
+
+		require.Len(t, bus.refunds(), 1)
+		assert.Equal(t, other.OrderID, bus.refunds()[0].OrderID)
+		assert.Equal(t, []*domain.CancellationOrderResult{
+			{ID: 4, RefundError: "failed to request the refund", NotificationError: "failed to send the cancellation notice"},
+			{ID: 5},
+		}, repo.completed[0])
+	})
+
+	t.Run("skips the notice of orders without an email", func(t *testing.T) {
+		handler, repo, bus := newProcessFixture([]*domain.CancellationOrder{cancellationOrder(6, "")})
+
+		require.NoError(t, handler.Handle(ctx))
+
+		assert.Len(t, bus.refunds(), 1)
+		assert.Empty(t, bus.mails())
+		assert.Equal(t, []*domain.CancellationOrderResult{{ID: 6}}, repo.completed[0])
+	})
+
+	t.Run("processes batches until one is not full", func(t *testing.T) {
+		full := make([]*domain.CancellationOrder, cancellationBatchSize)
+		for i := range full {
+			full[i] = cancellationOrder(int64(i+10), "")
+		}
+		handler, repo, bus := newProcessFixture(full, []*domain.CancellationOrder{cancellationOrder(7, "")})
+
+		require.NoError(t, handler.Handle(ctx))
+
+		assert.Equal(t, []int{cancellationBatchSize, cancellationBatchSize}, repo.limits)
+		assert.Len(t, repo.completed, 2)
+		assert.Len(t, bus.refunds(), cancellationBatchSize+1)
+	})
+
+	t.Run("completes the cancellations left without orders", func(t *testing.T) {
+		handler, repo, _ := newProcessFixture()
+
+		require.NoError(t, handler.Handle(ctx))
+
+		require.Len(t, repo.completed, 1, "an empty batch still closes the cancellations")
+		assert.Empty(t, repo.completed[0])
+	})
+}
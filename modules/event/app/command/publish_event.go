@@ -0,0 +1,59 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// PublishEventCommand represents the command of an organizer to publish their draft event
+type PublishEventCommand struct {
+	ID          int64
+	OrganizerID int64
+}
+
+// PublishEventHandler handles event publications
+type PublishEventHandler struct {
+	eventRepo domain.EventRepository
+	eventBus  messaging.EventBus
+}
+
+// NewPublishEventHandler creates a new publish event handler
+func NewPublishEventHandler(eventRepo domain.EventRepository, eventBus messaging.EventBus) *PublishEventHandler {
+	return &PublishEventHandler{
+		eventRepo: eventRepo,
+		eventBus:  eventBus,
+	}
+}
+
+// Handle executes the publish event command. The event gets its public page at its new slug.
+func (h *PublishEventHandler) Handle(ctx context.Context, cmd PublishEventCommand) (*EventResult, error) {
+	event, err := h.eventRepo.GetByID(ctx, cmd.ID, cmd.OrganizerID)
+	if err != nil {
+		if err == domain.ErrEventNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get event")
+	}
+
+	if err := event.Publish(time.Now()); err != nil {
+		return nil, err
+	}
+
+	err = h.eventRepo.Publish(ctx, event)
+	if err != nil {
+		if err == domain.ErrEventNotDraft {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to publish event")
+	}
+
+	fields := []domain.FieldChange{domain.NewFieldChange("status", string(domain.EventStatusDraft), string(event.Status))}
+	publishConfigurationChange(ctx, h.eventBus, domain.SalesTarget{EventID: event.ID}, domain.ChangeActionPublished, fields, cmd.OrganizerID)
+
+	return ToEventResult(event), nil
+}
@@ -0,0 +1,52 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ResizeAllotmentCommand represents the command of an organizer to change the size of an allotment
+type ResizeAllotmentCommand struct {
+	ID          int64 `json:"-"`
+	EventID     int64 `json:"-"`
+	OrganizerID int64 `json:"-"`
+	Quantity    int   `json:"quantity" binding:"min=0"`
+}
+
+// ResizeAllotmentHandler handles allotment resizes
+type ResizeAllotmentHandler struct {
+	allotmentRepo domain.AllotmentRepository
+}
+
+// NewResizeAllotmentHandler creates a new resize allotment handler
+func NewResizeAllotmentHandler(allotmentRepo domain.AllotmentRepository) *ResizeAllotmentHandler {
+	return &ResizeAllotmentHandler{
+		allotmentRepo: allotmentRepo,
+	}
+}
+
+// Handle executes the resize allotment command. Growing an allotment takes tickets off public sale,
+// shrinking it puts the tickets not issued back on sale.
+func (h *ResizeAllotmentHandler) Handle(ctx context.Context, cmd ResizeAllotmentCommand) (*AllotmentResult, error) {
+	allotment, err := h.allotmentRepo.GetByID(ctx, cmd.ID, cmd.EventID, cmd.OrganizerID)
+	if err != nil {
+		if err == domain.ErrAllotmentNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get allotment")
+	}
+
+	err = h.allotmentRepo.Resize(ctx, allotment, cmd.Quantity)
+	if err != nil {
+		switch err {
+		case domain.ErrAllotmentNotFound, domain.ErrAllotmentBelowIssued, domain.ErrSoldOut:
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to resize allotment")
+	}
+
+	return ToAllotmentResult(allotment), nil
+}
@@ -0,0 +1,57 @@
+package command
+
+import (
+	"context"
+	"strconv"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ResizeTicketCategoryCommand represents the command of an organizer to change the capacity of a ticket
+// category of their event
+type ResizeTicketCategoryCommand struct {
+	EventID          int64 `json:"-"`
+	TicketCategoryID int64 `json:"-"`
+	OrganizerID      int64 `json:"-"`
+	Capacity         int   `json:"capacity" binding:"min=0"`
+}
+
+// ResizeTicketCategoryHandler handles ticket category capacity changes
+type ResizeTicketCategoryHandler struct {
+	salesRepo domain.SalesControlRepository
+	eventBus  messaging.EventBus
+}
+
+// NewResizeTicketCategoryHandler creates a new resize ticket category handler
+func NewResizeTicketCategoryHandler(salesRepo domain.SalesControlRepository, eventBus messaging.EventBus) *ResizeTicketCategoryHandler {
+	return &ResizeTicketCategoryHandler{
+		salesRepo: salesRepo,
+		eventBus:  eventBus,
+	}
+}
+
+// Handle executes the resize ticket category command. The capacity never goes below the tickets sold,
+// reserved or allotted; the on-sale stock of the category grows or shrinks by the same amount.
+func (h *ResizeTicketCategoryHandler) Handle(ctx context.Context, cmd ResizeTicketCategoryCommand) (*SalesControlsResult, error) {
+	target := domain.SalesTarget{EventID: cmd.EventID, TicketCategoryID: cmd.TicketCategoryID}
+
+	stock, delta, err := h.salesRepo.Resize(ctx, target, cmd.OrganizerID, cmd.Capacity)
+	if err != nil {
+		switch err {
+		case domain.ErrEventNotFound, domain.ErrTicketCategoryNotFound, domain.ErrCapacityBelowSold, domain.ErrCapacityExceedsSeats:
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to resize ticket category")
+	}
+
+	if delta != 0 {
+		publishAvailabilityChange(ctx, h.eventBus, target, domain.AvailabilityChangeCapacity, delta)
+		fields := []domain.FieldChange{domain.NewFieldChange("capacity", strconv.Itoa(stock.Capacity-delta), strconv.Itoa(stock.Capacity))}
+		publishConfigurationChange(ctx, h.eventBus, target, domain.ChangeActionCapacityChanged, fields, cmd.OrganizerID)
+	}
+
+	return getSalesControls(ctx, h.salesRepo, cmd.EventID, cmd.OrganizerID)
+}
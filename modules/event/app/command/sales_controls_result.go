@@ -0,0 +1,80 @@
+package command
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"tixgo/modules/event/domain"
+	sharedKafka "tixgo/shared/kafka"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+)
+
+// SalesControlsResult represents the sales state of an event and its ticket categories
+type SalesControlsResult struct {
+	EventID          int64                        `json:"event_id"`
+	Paused           bool                         `json:"paused"`
+	PauseAt          *string                      `json:"pause_at,omitempty"`
+	TicketCategories []*TicketCategorySalesResult `json:"ticket_categories"`
+}
+
+// TicketCategorySalesResult represents the sales state and stock of a ticket category
+type TicketCategorySalesResult struct {
+	ID        int64   `json:"id"`
+	Name      string  `json:"name"`
+	Paused    bool    `json:"paused"`
+	PauseAt   *string `json:"pause_at,omitempty"`
+	Capacity  int     `json:"capacity"`
+	Sold      int     `json:"sold"`
+	Reserved  int     `json:"reserved"`
+	Allotted  int     `json:"allotted"`
+	Available int     `json:"available"`
+}
+
+// ToSalesControlsResult converts sales controls to their result
+func ToSalesControlsResult(controls *domain.SalesControls) *SalesControlsResult {
+	result := &SalesControlsResult{
+		EventID:          controls.EventID,
+		Paused:           controls.SalesPause.Paused,
+		PauseAt:          formatPauseAt(controls.SalesPause.PauseAt),
+		TicketCategories: make([]*TicketCategorySalesResult, len(controls.TicketCategories)),
+	}
+
+	for i, category := range controls.TicketCategories {
+		result.TicketCategories[i] = &TicketCategorySalesResult{
+			ID:        category.Inventory.TicketCategoryID,
+			Name:      category.Name,
+			Paused:    category.SalesPause.Paused,
+			PauseAt:   formatPauseAt(category.SalesPause.PauseAt),
+			Capacity:  category.Inventory.Capacity,
+			Sold:      category.Inventory.Sold,
+			Reserved:  category.Inventory.Reserved,
+			Allotted:  category.Inventory.Allotted,
+			Available: category.Inventory.Available(),
+		}
+	}
+
+	return result
+}
+
+func formatPauseAt(pauseAt *time.Time) *string {
+	if pauseAt == nil {
+		return nil
+	}
+	formatted := pauseAt.UTC().Format("2006-01-02T15:04:05Z")
+	return &formatted
+}
+
+// publishAvailabilityChange tells the caches of ticket availability that the target changed, keyed by
+// event so the changes of an event are consumed in order. The change already succeeded, so a failure is
+// only logged: cached queue settings then catch up when they expire.
+func publishAvailabilityChange(ctx context.Context, eventBus messaging.EventBus, target domain.SalesTarget, change domain.AvailabilityChange, capacityDelta int) {
+	key := strconv.FormatInt(target.EventID, 10)
+	err := eventBus.PublishEvent(sharedKafka.WithPartitionKey(ctx, key), domain.NewEventTicketAvailabilityChanged(target, change, capacityDelta))
+	if err != nil {
+		logger.Error(ctx, "Failed to publish ticket availability change", logger.F("event_id", target.EventID),
+			logger.F("ticket_category_id", target.TicketCategoryID), logger.F("error", err))
+	}
+}
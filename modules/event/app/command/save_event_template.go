@@ -0,0 +1,59 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// SaveEventTemplateCommand represents the command of an organizer to save the structure of their event
+// as a template for its next editions
+type SaveEventTemplateCommand struct {
+	OrganizerID int64  `json:"-"`
+	EventID     int64  `json:"event_id" binding:"required"`
+	Name        string `json:"name" binding:"required,max=255"`
+}
+
+// SaveEventTemplateHandler handles saving event templates
+type SaveEventTemplateHandler struct {
+	blueprintRepo domain.EventBlueprintRepository
+	templateRepo  domain.EventTemplateRepository
+}
+
+// NewSaveEventTemplateHandler creates a new save event template handler
+func NewSaveEventTemplateHandler(blueprintRepo domain.EventBlueprintRepository, templateRepo domain.EventTemplateRepository) *SaveEventTemplateHandler {
+	return &SaveEventTemplateHandler{
+		blueprintRepo: blueprintRepo,
+		templateRepo:  templateRepo,
+	}
+}
+
+// Handle executes the save event template command. The template is a snapshot, later changes to the
+// event do not reach it.
+func (h *SaveEventTemplateHandler) Handle(ctx context.Context, cmd SaveEventTemplateCommand) (*EventTemplateResult, error) {
+	blueprint, _, err := h.blueprintRepo.GetBlueprint(ctx, cmd.EventID, cmd.OrganizerID)
+	if err != nil {
+		if err == domain.ErrEventNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get event blueprint")
+	}
+
+	template := &domain.EventTemplate{
+		OrganizerID:   cmd.OrganizerID,
+		Name:          cmd.Name,
+		SourceEventID: &cmd.EventID,
+		Blueprint:     *blueprint,
+	}
+
+	if err := h.templateRepo.Create(ctx, template); err != nil {
+		if err == domain.ErrEventTemplateNameTaken {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to save event template")
+	}
+
+	return ToEventTemplateResult(template), nil
+}
@@ -0,0 +1,166 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/event/domain"
+	templateDomain "tixgo/modules/template/domain"
+	sharedMail "tixgo/shared/events/mail"
+	sharedKafka "tixgo/shared/kafka"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/notification/mail"
+	"github.com/duongptryu/gox/syserr"
+)
+
+const (
+	SlugBoxOfficeTickets = "box-office-tickets"
+)
+
+// SellBoxOfficeTicketsCommand represents the command of an organizer to sell tickets of their event to a
+// walk-up customer who paid at the venue
+type SellBoxOfficeTicketsCommand struct {
+	EventID       int64               `json:"-"`
+	SellerID      int64               `json:"-"`
+	Items         []BoxOfficeItemLine `json:"items" binding:"required,min=1,dive"`
+	PaymentMethod string              `json:"payment_method" binding:"required"`
+	// CardReference is the reference of the card terminal transaction, for card_present payments
+	CardReference string `json:"card_reference" binding:"max=100"`
+	// Email is where the tickets are mailed, left empty when the customer only takes them printed
+	Email        string `json:"email" binding:"omitempty,email"`
+	CustomerName string `json:"customer_name" binding:"max=255"`
+}
+
+// BoxOfficeItemLine is a number of tickets of a category sold at the box office
+type BoxOfficeItemLine struct {
+	TicketCategoryID int64 `json:"ticket_category_id" binding:"required"`
+	Quantity         int   `json:"quantity" binding:"required,min=1"`
+}
+
+// SellBoxOfficeTicketsHandler handles box office sales
+type SellBoxOfficeTicketsHandler struct {
+	boxOfficeRepo    domain.BoxOfficeRepository
+	templateRepo     templateDomain.TemplateRepository
+	templateRenderer templateDomain.TemplateRenderer
+	eventBus         messaging.EventBus
+}
+
+// NewSellBoxOfficeTicketsHandler creates a new sell box office tickets handler
+func NewSellBoxOfficeTicketsHandler(boxOfficeRepo domain.BoxOfficeRepository, templateRepo templateDomain.TemplateRepository, templateRenderer templateDomain.TemplateRenderer, eventBus messaging.EventBus) *SellBoxOfficeTicketsHandler {
+	return &SellBoxOfficeTicketsHandler{
+		boxOfficeRepo:    boxOfficeRepo,
+		templateRepo:     templateRepo,
+		templateRenderer: templateRenderer,
+		eventBus:         eventBus,
+	}
+}
+
+// Handle executes the sell box office tickets command. The sale skips the payment provider, it is
+// recorded as a confirmed order at the price of the categories with how the customer paid, then goes
+// through what follows any confirmed order. The tickets are returned for the box office to print, and
+// mailed when the customer left an email; a failed delivery is logged and reported in the result.
+func (h *SellBoxOfficeTicketsHandler) Handle(ctx context.Context, cmd SellBoxOfficeTicketsCommand) (*BoxOfficeSaleResult, error) {
+	items := make([]domain.BoxOfficeItem, len(cmd.Items))
+	for i, item := range cmd.Items {
+		items[i] = domain.BoxOfficeItem{TicketCategoryID: item.TicketCategoryID, Quantity: item.Quantity}
+	}
+
+	sale, err := domain.NewBoxOfficeSale(cmd.EventID, cmd.SellerID, items, domain.BoxOfficePaymentMethod(cmd.PaymentMethod), cmd.CardReference, cmd.Email, cmd.CustomerName)
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.boxOfficeRepo.Sell(ctx, sale)
+	if err != nil {
+		switch err {
+		case domain.ErrEventNotFound, domain.ErrTicketCategoryNotFound, domain.ErrEventNotPublished,
+			domain.ErrEventClosed, domain.ErrSoldOut:
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to sell box office tickets")
+	}
+
+	publishSoldTickets(ctx, h.eventBus, sale.EventID, sale.OrderID, sale.Tickets)
+
+	result := ToBoxOfficeSaleResult(sale)
+	if sale.Email != "" {
+		if err := h.deliver(ctx, sale); err != nil {
+			logger.Error(ctx, "Failed to deliver box office tickets", logger.F("order_id", sale.OrderID), logger.F("error", err))
+			result.DeliveryFailed = true
+		}
+	}
+
+	return result, nil
+}
+
+// deliver mails the tickets to the customer with the identity of the organizer
+func (h *SellBoxOfficeTicketsHandler) deliver(ctx context.Context, sale *domain.BoxOfficeSale) error {
+	template, err := h.templateRepo.GetBySlug(ctx, SlugBoxOfficeTickets)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get template")
+	}
+
+	rendered, err := h.templateRenderer.Render(ctx, template, map[string]interface{}{
+		"event_title":   sale.EventTitle,
+		"customer_name": sale.CustomerName,
+		"order_number":  sale.OrderNumber,
+		"total_amount":  sale.TotalAmount,
+		"currency":      sale.Currency,
+		"quantity":      len(sale.Tickets),
+		"tickets":       ticketsTemplateData(sale.Tickets),
+	}, templateDomain.RenderOptions{OrganizerID: sale.SellerID})
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to render template")
+	}
+
+	err = h.eventBus.PublishEvent(sharedKafka.WithPartitionKey(ctx, sale.Email), &sharedMail.EventSendMail{
+		ToMail: []mail.EmailAddress{
+			{
+				Email: sale.Email,
+				Name:  sale.CustomerName,
+			},
+		},
+		Subject:     rendered.Subject,
+		HTMLBody:    rendered.Content,
+		Priority:    mail.PriorityHigh,
+		OrganizerID: sale.SellerID,
+	})
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to publish send mail event")
+	}
+
+	return nil
+}
+
+// BoxOfficeSaleResult represents the order of a box office sale with the tickets to print
+type BoxOfficeSaleResult struct {
+	OrderID       int64                 `json:"order_id"`
+	OrderNumber   string                `json:"order_number"`
+	EventTitle    string                `json:"event_title"`
+	PaymentMethod string                `json:"payment_method"`
+	TotalAmount   string                `json:"total_amount"`
+	Currency      string                `json:"currency"`
+	Email         string                `json:"email,omitempty"`
+	CustomerName  string                `json:"customer_name,omitempty"`
+	Tickets       []*IssuedTicketResult `json:"tickets"`
+	// DeliveryFailed tells that the tickets were sold but could not be mailed
+	DeliveryFailed bool   `json:"delivery_failed"`
+	SoldAt         string `json:"sold_at"`
+}
+
+// ToBoxOfficeSaleResult converts a sale to its result
+func ToBoxOfficeSaleResult(sale *domain.BoxOfficeSale) *BoxOfficeSaleResult {
+	return &BoxOfficeSaleResult{
+		OrderID:       sale.OrderID,
+		OrderNumber:   sale.OrderNumber,
+		EventTitle:    sale.EventTitle,
+		PaymentMethod: string(sale.PaymentMethod),
+		TotalAmount:   sale.TotalAmount,
+		Currency:      sale.Currency,
+		Email:         sale.Email,
+		CustomerName:  sale.CustomerName,
+		Tickets:       ToIssuedTicketResults(sale.Tickets),
+		SoldAt:        sale.SoldAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
@@ -0,0 +1,70 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// SetSalesPausedCommand represents the command of an organizer to pause or resume the online sales of
+// their event, or of one of its ticket categories
+type SetSalesPausedCommand struct {
+	EventID          int64
+	TicketCategoryID int64
+	OrganizerID      int64
+	Paused           bool
+}
+
+// SetSalesPausedHandler handles sales pauses and resumptions
+type SetSalesPausedHandler struct {
+	salesRepo domain.SalesControlRepository
+	eventBus  messaging.EventBus
+}
+
+// NewSetSalesPausedHandler creates a new set sales paused handler
+func NewSetSalesPausedHandler(salesRepo domain.SalesControlRepository, eventBus messaging.EventBus) *SetSalesPausedHandler {
+	return &SetSalesPausedHandler{
+		salesRepo: salesRepo,
+		eventBus:  eventBus,
+	}
+}
+
+// Handle executes the set sales paused command. Pausing or resuming drops the pause scheduled for the
+// same sales. Buyers already holding tickets keep them, the pause only stops new reservations.
+func (h *SetSalesPausedHandler) Handle(ctx context.Context, cmd SetSalesPausedCommand) (*SalesControlsResult, error) {
+	target := domain.SalesTarget{EventID: cmd.EventID, TicketCategoryID: cmd.TicketCategoryID}
+
+	err := h.salesRepo.SetPaused(ctx, target, cmd.OrganizerID, cmd.Paused)
+	if err != nil {
+		switch err {
+		case domain.ErrEventNotFound, domain.ErrTicketCategoryNotFound:
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to pause sales")
+	}
+
+	change, action := domain.AvailabilityChangeResumed, domain.ChangeActionSalesResumed
+	if cmd.Paused {
+		change, action = domain.AvailabilityChangePaused, domain.ChangeActionSalesPaused
+	}
+	publishAvailabilityChange(ctx, h.eventBus, target, change, 0)
+	publishConfigurationChange(ctx, h.eventBus, target, action, nil, cmd.OrganizerID)
+
+	return getSalesControls(ctx, h.salesRepo, cmd.EventID, cmd.OrganizerID)
+}
+
+// getSalesControls returns the sales controls of an event of the organizer
+func getSalesControls(ctx context.Context, salesRepo domain.SalesControlRepository, eventID, organizerID int64) (*SalesControlsResult, error) {
+	controls, err := salesRepo.Get(ctx, eventID, organizerID)
+	if err != nil {
+		if err == domain.ErrEventNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get sales controls")
+	}
+
+	return ToSalesControlsResult(controls), nil
+}
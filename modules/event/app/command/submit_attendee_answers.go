@@ -0,0 +1,72 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// SubmitAttendeeAnswersCommand represents the command of a buyer to answer the questionnaire of an
+// event for one of their tickets
+type SubmitAttendeeAnswersCommand struct {
+	EventID  int64              `json:"-"`
+	TicketID int64              `json:"-"`
+	UserID   int64              `json:"-"`
+	Answers  []AttendeeAnswerIn `json:"answers" binding:"dive"`
+}
+
+// AttendeeAnswerIn is the answer to a question of the questionnaire
+type AttendeeAnswerIn struct {
+	QuestionID int64  `json:"question_id" binding:"required"`
+	Value      string `json:"value"`
+}
+
+// SubmitAttendeeAnswersHandler handles attendee answers
+type SubmitAttendeeAnswersHandler struct {
+	questionRepo domain.EventQuestionRepository
+	attendeeRepo domain.AttendeeRepository
+}
+
+// NewSubmitAttendeeAnswersHandler creates a new submit attendee answers handler
+func NewSubmitAttendeeAnswersHandler(questionRepo domain.EventQuestionRepository, attendeeRepo domain.AttendeeRepository) *SubmitAttendeeAnswersHandler {
+	return &SubmitAttendeeAnswersHandler{
+		questionRepo: questionRepo,
+		attendeeRepo: attendeeRepo,
+	}
+}
+
+// Handle executes the submit attendee answers command. The answers are checked against the current
+// questionnaire of the event and replace those given before for the ticket, so the whole questionnaire
+// is answered at once.
+func (h *SubmitAttendeeAnswersHandler) Handle(ctx context.Context, cmd SubmitAttendeeAnswersCommand) error {
+	questions, err := h.questionRepo.List(ctx, cmd.EventID)
+	if err != nil {
+		if err == domain.ErrEventNotFound {
+			return err
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to list event questions")
+	}
+
+	answers := make([]domain.AttendeeAnswer, len(cmd.Answers))
+	for i, answer := range cmd.Answers {
+		answers[i] = domain.AttendeeAnswer{QuestionID: answer.QuestionID, Value: answer.Value}
+	}
+
+	answers, err = domain.ValidateAnswers(questions, answers)
+	if err != nil {
+		return err
+	}
+
+	err = h.attendeeRepo.SaveAnswers(ctx, cmd.EventID, cmd.TicketID, cmd.UserID, answers)
+	if err != nil {
+		switch err {
+		case domain.ErrTicketNotFound, domain.ErrAnswersClosed:
+			return err
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to save answers")
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// UnassignTicketAttendeeCommand represents the command of a buyer to take back one of their tickets from
+// its attendee
+type UnassignTicketAttendeeCommand struct {
+	EventID  int64
+	TicketID int64
+	UserID   int64
+}
+
+// UnassignTicketAttendeeHandler handles ticket attendee removals
+type UnassignTicketAttendeeHandler struct {
+	attendeeRepo domain.AttendeeRepository
+}
+
+// NewUnassignTicketAttendeeHandler creates a new unassign ticket attendee handler
+func NewUnassignTicketAttendeeHandler(attendeeRepo domain.AttendeeRepository) *UnassignTicketAttendeeHandler {
+	return &UnassignTicketAttendeeHandler{
+		attendeeRepo: attendeeRepo,
+	}
+}
+
+// Handle executes the unassign ticket attendee command. Events requiring attendees only let buyers
+// assign their tickets to someone else.
+func (h *UnassignTicketAttendeeHandler) Handle(ctx context.Context, cmd UnassignTicketAttendeeCommand) error {
+	err := h.attendeeRepo.Unassign(ctx, cmd.EventID, cmd.TicketID, cmd.UserID)
+	if err != nil {
+		switch err {
+		case domain.ErrTicketNotFound, domain.ErrAttendeeRequired, domain.ErrAttendeeEditClosed:
+			return err
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to unassign ticket")
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// UpdateAttendeeSettingsCommand represents the command of an organizer to set how the tickets of their
+// event are assigned to attendees
+type UpdateAttendeeSettingsCommand struct {
+	EventID     int64  `json:"-"`
+	OrganizerID int64  `json:"-"`
+	Mode        string `json:"mode" binding:"required"`
+	// EditCutoffMinutes is how long before the start of the event attendees stop being editable
+	EditCutoffMinutes int `json:"edit_cutoff_minutes" binding:"min=0"`
+}
+
+// AttendeeSettingsResult represents the attendee settings of an event
+type AttendeeSettingsResult struct {
+	EventID           int64               `json:"event_id"`
+	Mode              domain.AttendeeMode `json:"mode"`
+	EditCutoffMinutes int                 `json:"edit_cutoff_minutes"`
+}
+
+// ToAttendeeSettingsResult converts attendee settings to their result
+func ToAttendeeSettingsResult(settings *domain.AttendeeSettings) *AttendeeSettingsResult {
+	return &AttendeeSettingsResult{
+		EventID:           settings.EventID,
+		Mode:              settings.Mode,
+		EditCutoffMinutes: int(settings.EditCutoff / time.Minute),
+	}
+}
+
+// UpdateAttendeeSettingsHandler handles attendee settings updates
+type UpdateAttendeeSettingsHandler struct {
+	settingsRepo domain.AttendeeSettingsRepository
+}
+
+// NewUpdateAttendeeSettingsHandler creates a new update attendee settings handler
+func NewUpdateAttendeeSettingsHandler(settingsRepo domain.AttendeeSettingsRepository) *UpdateAttendeeSettingsHandler {
+	return &UpdateAttendeeSettingsHandler{
+		settingsRepo: settingsRepo,
+	}
+}
+
+// Handle executes the update attendee settings command. Requiring attendees applies to the tickets sold
+// before too: their buyers can no longer remove the attendee they named.
+func (h *UpdateAttendeeSettingsHandler) Handle(ctx context.Context, cmd UpdateAttendeeSettingsCommand) (*AttendeeSettingsResult, error) {
+	settings, err := domain.NewAttendeeSettings(cmd.EventID, domain.AttendeeMode(cmd.Mode), time.Duration(cmd.EditCutoffMinutes)*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.settingsRepo.Save(ctx, settings, cmd.OrganizerID)
+	if err != nil {
+		if err == domain.ErrEventNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to save attendee settings")
+	}
+
+	return ToAttendeeSettingsResult(settings), nil
+}
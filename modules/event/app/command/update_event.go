@@ -0,0 +1,64 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// UpdateEventCommand represents the command of an organizer to change the details of their event
+type UpdateEventCommand struct {
+	ID          int64 `json:"-"`
+	OrganizerID int64 `json:"-"`
+	EventDetailsInput
+}
+
+// UpdateEventHandler handles event updates
+type UpdateEventHandler struct {
+	eventRepo domain.EventRepository
+	eventBus  messaging.EventBus
+}
+
+// NewUpdateEventHandler creates a new update event handler
+func NewUpdateEventHandler(eventRepo domain.EventRepository, eventBus messaging.EventBus) *UpdateEventHandler {
+	return &UpdateEventHandler{
+		eventRepo: eventRepo,
+		eventBus:  eventBus,
+	}
+}
+
+// Handle executes the update event command. Published events keep their slug when renamed.
+func (h *UpdateEventHandler) Handle(ctx context.Context, cmd UpdateEventCommand) (*EventResult, error) {
+	event, err := h.eventRepo.GetByID(ctx, cmd.ID, cmd.OrganizerID)
+	if err != nil {
+		if err == domain.ErrEventNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get event")
+	}
+
+	before := event.EventDetails
+	if err := event.Update(cmd.Details(), time.Now()); err != nil {
+		return nil, err
+	}
+
+	err = h.eventRepo.Update(ctx, event)
+	if err != nil {
+		switch err {
+		case domain.ErrEventClosed, domain.ErrVenueNotFound, domain.ErrVenueSeatsBound:
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to update event")
+	}
+
+	if fields := domain.DiffEventDetails(before, event.EventDetails); len(fields) > 0 {
+		target := domain.SalesTarget{EventID: event.ID}
+		publishConfigurationChange(ctx, h.eventBus, target, domain.ChangeActionDetailsUpdated, fields, cmd.OrganizerID)
+	}
+
+	return ToEventResult(event), nil
+}
@@ -0,0 +1,59 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// UpdateEventQuestionCommand represents the command of an organizer to change a question of their event
+type UpdateEventQuestionCommand struct {
+	ID          int64    `json:"-"`
+	EventID     int64    `json:"-"`
+	OrganizerID int64    `json:"-"`
+	Label       string   `json:"label" binding:"required,max=255"`
+	Options     []string `json:"options"`
+	Required    bool     `json:"required"`
+	Position    int      `json:"position" binding:"min=0"`
+}
+
+// UpdateEventQuestionHandler handles event question updates
+type UpdateEventQuestionHandler struct {
+	questionRepo domain.EventQuestionRepository
+}
+
+// NewUpdateEventQuestionHandler creates a new update event question handler
+func NewUpdateEventQuestionHandler(questionRepo domain.EventQuestionRepository) *UpdateEventQuestionHandler {
+	return &UpdateEventQuestionHandler{
+		questionRepo: questionRepo,
+	}
+}
+
+// Handle executes the update event question command. The kind of a question cannot change; answers
+// already given are kept as they are, they are only checked against the question when given again.
+func (h *UpdateEventQuestionHandler) Handle(ctx context.Context, cmd UpdateEventQuestionCommand) (*EventQuestionResult, error) {
+	question, err := h.questionRepo.GetByID(ctx, cmd.ID, cmd.EventID, cmd.OrganizerID)
+	if err != nil {
+		if err == domain.ErrQuestionNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get event question")
+	}
+
+	if err := question.Update(cmd.Label, cmd.Options, cmd.Required, cmd.Position); err != nil {
+		return nil, err
+	}
+
+	err = h.questionRepo.Update(ctx, question)
+	if err != nil {
+		switch err {
+		case domain.ErrQuestionNotFound, domain.ErrQuestionLabelTaken:
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to update event question")
+	}
+
+	return ToEventQuestionResult(question), nil
+}
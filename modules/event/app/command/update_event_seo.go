@@ -0,0 +1,93 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// UpdateEventSEOCommand represents the command of an organizer to set the slug and meta tags of the
+// public page of their event
+type UpdateEventSEOCommand struct {
+	EventID     int64 `json:"-"`
+	OrganizerID int64 `json:"-"`
+	// Slug renames the page, the former slug redirecting to the new one; empty keeps it
+	Slug            string `json:"slug" binding:"max=200"`
+	MetaTitle       string `json:"meta_title" binding:"max=70"`
+	MetaDescription string `json:"meta_description" binding:"max=160"`
+}
+
+// EventSEOResult represents the slug and meta tags of the public page of an event
+type EventSEOResult struct {
+	EventID         int64    `json:"event_id"`
+	Slug            string   `json:"slug,omitempty"`
+	MetaTitle       string   `json:"meta_title"`
+	MetaDescription string   `json:"meta_description"`
+	FormerSlugs     []string `json:"former_slugs"`
+	// URL is the canonical URL of the page, set once the event has a slug
+	URL string `json:"url,omitempty"`
+}
+
+// ToEventSEOResult converts the SEO of an event to its result
+func ToEventSEOResult(seo *domain.EventSEO, site domain.Site) *EventSEOResult {
+	result := &EventSEOResult{
+		EventID:         seo.EventID,
+		Slug:            seo.Slug,
+		MetaTitle:       seo.MetaTitle,
+		MetaDescription: seo.MetaDescription,
+		FormerSlugs:     append([]string{}, seo.FormerSlugs...),
+	}
+	if seo.Slug != "" {
+		result.URL = site.EventURL(seo.Slug)
+	}
+	return result
+}
+
+// UpdateEventSEOHandler handles the updates of the slugs and meta tags of events
+type UpdateEventSEOHandler struct {
+	seoRepo  domain.EventSEORepository
+	site     domain.Site
+	eventBus messaging.EventBus
+}
+
+// NewUpdateEventSEOHandler creates a new update event SEO handler
+func NewUpdateEventSEOHandler(seoRepo domain.EventSEORepository, site domain.Site, eventBus messaging.EventBus) *UpdateEventSEOHandler {
+	return &UpdateEventSEOHandler{
+		seoRepo:  seoRepo,
+		site:     site,
+		eventBus: eventBus,
+	}
+}
+
+// Handle executes the update event SEO command
+func (h *UpdateEventSEOHandler) Handle(ctx context.Context, cmd UpdateEventSEOCommand) (*EventSEOResult, error) {
+	seo, err := h.seoRepo.GetByEventID(ctx, cmd.EventID, cmd.OrganizerID)
+	if err != nil {
+		if err == domain.ErrEventNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get event SEO")
+	}
+
+	before := *seo
+	if err := seo.Update(cmd.Slug, cmd.MetaTitle, cmd.MetaDescription); err != nil {
+		return nil, err
+	}
+
+	if err := h.seoRepo.Save(ctx, seo); err != nil {
+		if err == domain.ErrEventNotFound || err == domain.ErrSlugTaken {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to save event SEO")
+	}
+
+	if fields := domain.DiffEventSEO(before, *seo); len(fields) > 0 {
+		target := domain.SalesTarget{EventID: cmd.EventID}
+		publishConfigurationChange(ctx, h.eventBus, target, domain.ChangeActionSEOUpdated, fields, cmd.OrganizerID)
+	}
+
+	return ToEventSEOResult(seo, h.site), nil
+}
@@ -0,0 +1,22 @@
+package event
+
+import (
+	"context"
+
+	"tixgo/modules/event/domain"
+)
+
+type broadcastSeatStatus struct {
+	seatUpdates domain.SeatUpdates
+}
+
+func NewBroadcastSeatStatus(seatUpdates domain.SeatUpdates) *broadcastSeatStatus {
+	return &broadcastSeatStatus{
+		seatUpdates: seatUpdates,
+	}
+}
+
+// Broadcast forwards a seat change consumed by one instance to the seat map streams of all of them
+func (h *broadcastSeatStatus) Broadcast(ctx context.Context, event *domain.EventSeatStatusChanged) error {
+	return h.seatUpdates.Publish(ctx, event)
+}
@@ -0,0 +1,22 @@
+package event
+
+import (
+	"context"
+
+	"tixgo/modules/event/domain"
+)
+
+type recordChange struct {
+	changeRepo domain.EventChangeRepository
+}
+
+func NewRecordChange(changeRepo domain.EventChangeRepository) *recordChange {
+	return &recordChange{
+		changeRepo: changeRepo,
+	}
+}
+
+// Record adds a change to the change feed of its event, once however often it is delivered
+func (h *recordChange) Record(ctx context.Context, event *domain.EventConfigurationChanged) error {
+	return h.changeRepo.Record(ctx, event)
+}
@@ -0,0 +1,35 @@
+package event
+
+import (
+	"context"
+
+	"tixgo/modules/event/domain"
+)
+
+// queueSettingsInvalidator drops the cached queue settings of an event
+type queueSettingsInvalidator interface {
+	Invalidate(ctx context.Context, eventID int64)
+}
+
+type refreshTicketAvailability struct {
+	queueSettings queueSettingsInvalidator
+	queue         domain.OnSaleQueue
+}
+
+func NewRefreshTicketAvailability(queueSettings queueSettingsInvalidator, queue domain.OnSaleQueue) *refreshTicketAvailability {
+	return &refreshTicketAvailability{
+		queueSettings: queueSettings,
+		queue:         queue,
+	}
+}
+
+// Refresh brings the availability cached for on-sales up to date with a change: the queue settings,
+// which carry the sales pauses, are loaded again and a capacity change is applied to the on-sale stock
+func (h *refreshTicketAvailability) Refresh(ctx context.Context, event *domain.EventTicketAvailabilityChanged) error {
+	h.queueSettings.Invalidate(ctx, event.EventID)
+
+	if event.Change != domain.AvailabilityChangeCapacity || event.CapacityDelta == 0 {
+		return nil
+	}
+	return h.queue.AdjustStock(ctx, event.EventID, event.TicketCategoryID, event.CapacityDelta, event.ChangeID)
+}
@@ -0,0 +1,41 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/event/app/command"
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// GetAttendeeSettingsQuery represents the query for the attendee settings of an event
+type GetAttendeeSettingsQuery struct {
+	EventID int64
+}
+
+// GetAttendeeSettingsHandler handles getting attendee settings
+type GetAttendeeSettingsHandler struct {
+	settingsRepo domain.AttendeeSettingsRepository
+}
+
+// NewGetAttendeeSettingsHandler creates a new get attendee settings handler
+func NewGetAttendeeSettingsHandler(settingsRepo domain.AttendeeSettingsRepository) *GetAttendeeSettingsHandler {
+	return &GetAttendeeSettingsHandler{
+		settingsRepo: settingsRepo,
+	}
+}
+
+// Handle executes the get attendee settings query. Buyers read them to know whether they must name
+// the attendees of their tickets and until when.
+func (h *GetAttendeeSettingsHandler) Handle(ctx context.Context, query GetAttendeeSettingsQuery) (*command.AttendeeSettingsResult, error) {
+	settings, err := h.settingsRepo.Get(ctx, query.EventID)
+	if err != nil {
+		if err == domain.ErrEventNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get attendee settings")
+	}
+
+	return command.ToAttendeeSettingsResult(settings), nil
+}
@@ -0,0 +1,41 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/event/app/command"
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// GetEventQuery represents the query of an organizer for one of their events
+type GetEventQuery struct {
+	ID          int64
+	OrganizerID int64
+}
+
+// GetEventHandler handles get event queries
+type GetEventHandler struct {
+	eventRepo domain.EventRepository
+}
+
+// NewGetEventHandler creates a new get event handler
+func NewGetEventHandler(eventRepo domain.EventRepository) *GetEventHandler {
+	return &GetEventHandler{
+		eventRepo: eventRepo,
+	}
+}
+
+// Handle executes the get event query. Events of other organizers are reported as not found.
+func (h *GetEventHandler) Handle(ctx context.Context, query GetEventQuery) (*command.EventResult, error) {
+	event, err := h.eventRepo.GetByID(ctx, query.ID, query.OrganizerID)
+	if err != nil {
+		if err == domain.ErrEventNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get event")
+	}
+
+	return command.ToEventResult(event), nil
+}
@@ -0,0 +1,53 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/event/app/command"
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// cancellationFailureLimit bounds the failed orders shown on the organizer dashboard
+const cancellationFailureLimit = 100
+
+// GetEventCancellationQuery represents the query of an organizer for the progress of the cancellation of their event
+type GetEventCancellationQuery struct {
+	EventID     int64
+	OrganizerID int64
+}
+
+// GetEventCancellationHandler handles event cancellation progress queries
+type GetEventCancellationHandler struct {
+	cancellationRepo domain.EventCancellationRepository
+}
+
+// NewGetEventCancellationHandler creates a new get event cancellation handler
+func NewGetEventCancellationHandler(cancellationRepo domain.EventCancellationRepository) *GetEventCancellationHandler {
+	return &GetEventCancellationHandler{
+		cancellationRepo: cancellationRepo,
+	}
+}
+
+// Handle executes the get event cancellation query. Cancellations of other organizers are reported as not found.
+func (h *GetEventCancellationHandler) Handle(ctx context.Context, query GetEventCancellationQuery) (*command.EventCancellationResult, error) {
+	cancellation, err := h.cancellationRepo.GetByEventID(ctx, query.EventID)
+	if err != nil {
+		if err == domain.ErrCancellationNotFound {
+			return nil, domain.ErrCancellationNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get event cancellation")
+	}
+
+	if cancellation.CancelledBy != query.OrganizerID {
+		return nil, domain.ErrCancellationNotFound
+	}
+
+	failures, err := h.cancellationRepo.ListFailures(ctx, cancellation.ID, cancellationFailureLimit)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list cancellation failures")
+	}
+
+	return command.ToEventCancellationResult(cancellation, failures), nil
+}
@@ -0,0 +1,43 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/event/app/command"
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// GetEventSEOQuery represents the query of an organizer for the slug and meta tags of their event
+type GetEventSEOQuery struct {
+	EventID     int64
+	OrganizerID int64
+}
+
+// GetEventSEOHandler handles getting the slugs and meta tags of events
+type GetEventSEOHandler struct {
+	seoRepo domain.EventSEORepository
+	site    domain.Site
+}
+
+// NewGetEventSEOHandler creates a new get event SEO handler
+func NewGetEventSEOHandler(seoRepo domain.EventSEORepository, site domain.Site) *GetEventSEOHandler {
+	return &GetEventSEOHandler{
+		seoRepo: seoRepo,
+		site:    site,
+	}
+}
+
+// Handle executes the get event SEO query
+func (h *GetEventSEOHandler) Handle(ctx context.Context, query GetEventSEOQuery) (*command.EventSEOResult, error) {
+	seo, err := h.seoRepo.GetByEventID(ctx, query.EventID, query.OrganizerID)
+	if err != nil {
+		if err == domain.ErrEventNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get event SEO")
+	}
+
+	return command.ToEventSEOResult(seo, h.site), nil
+}
@@ -0,0 +1,229 @@
+package query
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"tixgo/modules/event/domain"
+)
+
+// GetEventStructuredDataQuery represents the query of the structured data of a public event page
+type GetEventStructuredDataQuery struct {
+	// Slug is the current slug of the page or a former one
+	Slug string
+}
+
+// EventStructuredData is the schema.org Event of a public event page, embedded in the page as JSON-LD
+// for search engines to show the event in their results
+type EventStructuredData struct {
+	Context             string                  `json:"@context"`
+	Type                string                  `json:"@type"`
+	Name                string                  `json:"name"`
+	Description         string                  `json:"description,omitempty"`
+	URL                 string                  `json:"url,omitempty"`
+	Image               []string                `json:"image,omitempty"`
+	StartDate           string                  `json:"startDate"`
+	EndDate             string                  `json:"endDate,omitempty"`
+	EventStatus         string                  `json:"eventStatus"`
+	EventAttendanceMode string                  `json:"eventAttendanceMode"`
+	TypicalAgeRange     string                  `json:"typicalAgeRange,omitempty"`
+	Location            *StructuredDataPlace    `json:"location,omitempty"`
+	Organizer           StructuredDataOrganizer `json:"organizer"`
+	Offers              []StructuredDataOffer   `json:"offers"`
+	// Slug is the current slug of the page, which a former slug redirects to
+	Slug string `json:"-"`
+}
+
+// StructuredDataPlace is the schema.org Place of the venue
+type StructuredDataPlace struct {
+	Type    string                     `json:"@type"`
+	Name    string                     `json:"name"`
+	Address StructuredDataAddress      `json:"address"`
+	Geo     *StructuredDataCoordinates `json:"geo,omitempty"`
+}
+
+// StructuredDataAddress is the schema.org PostalAddress of the venue
+type StructuredDataAddress struct {
+	Type            string `json:"@type"`
+	StreetAddress   string `json:"streetAddress"`
+	AddressLocality string `json:"addressLocality"`
+	AddressRegion   string `json:"addressRegion,omitempty"`
+	AddressCountry  string `json:"addressCountry"`
+}
+
+// StructuredDataCoordinates are the schema.org GeoCoordinates of the venue
+type StructuredDataCoordinates struct {
+	Type      string  `json:"@type"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// StructuredDataOrganizer is the schema.org Organization selling the tickets
+type StructuredDataOrganizer struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+}
+
+// StructuredDataOffer is the schema.org Offer of a ticket category
+type StructuredDataOffer struct {
+	Type          string `json:"@type"`
+	Name          string `json:"name"`
+	Price         string `json:"price"`
+	PriceCurrency string `json:"priceCurrency"`
+	Availability  string `json:"availability"`
+	URL           string `json:"url,omitempty"`
+	ValidFrom     string `json:"validFrom,omitempty"`
+	ValidThrough  string `json:"validThrough,omitempty"`
+}
+
+// GetEventStructuredDataHandler handles getting the structured data of public event pages
+type GetEventStructuredDataHandler struct {
+	publicEventRepo domain.PublicEventRepository
+	seoRepo         domain.EventSEORepository
+	site            domain.Site
+}
+
+// NewGetEventStructuredDataHandler creates a new get event structured data handler
+func NewGetEventStructuredDataHandler(publicEventRepo domain.PublicEventRepository, seoRepo domain.EventSEORepository, site domain.Site) *GetEventStructuredDataHandler {
+	return &GetEventStructuredDataHandler{
+		publicEventRepo: publicEventRepo,
+		seoRepo:         seoRepo,
+		site:            site,
+	}
+}
+
+// Handle executes the get event structured data query. Dates are in the time zone of the event.
+func (h *GetEventStructuredDataHandler) Handle(ctx context.Context, query GetEventStructuredDataQuery) (*EventStructuredData, error) {
+	event, err := getPublicEvent(ctx, h.publicEventRepo, h.seoRepo, query.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	return toEventStructuredData(event, h.site, time.Now()), nil
+}
+
+func toEventStructuredData(event *domain.PublicEvent, site domain.Site, now time.Time) *EventStructuredData {
+	location, err := time.LoadLocation(event.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	format := func(t time.Time) string {
+		return t.In(location).Format(time.RFC3339)
+	}
+
+	data := &EventStructuredData{
+		Context:             "https://schema.org",
+		Type:                "Event",
+		Name:                event.Title,
+		Description:         domain.MetaDescription(event.MetaDescription, event.Description),
+		URL:                 site.EventURL(event.Slug),
+		EventStatus:         structuredEventStatus(event.Status),
+		EventAttendanceMode: "https://schema.org/OfflineEventAttendanceMode",
+		Organizer:           StructuredDataOrganizer{Type: "Organization", Name: event.Organizer.Name},
+		Offers:              make([]StructuredDataOffer, len(event.TicketCategories)),
+		Slug:                event.Slug,
+	}
+	if site.URL == "" {
+		data.URL = ""
+	}
+	if event.ImageURL != "" {
+		data.Image = []string{event.ImageURL}
+	}
+	if len(event.Sessions) > 0 {
+		data.StartDate = format(event.Sessions[0].StartDate)
+		if end := event.Sessions[0].EndDate; end != nil {
+			data.EndDate = format(*end)
+		}
+	}
+	if event.AgeRestriction != nil {
+		data.TypicalAgeRange = strconv.Itoa(*event.AgeRestriction) + "-"
+	}
+
+	if venue := event.Venue; venue != nil {
+		data.Location = &StructuredDataPlace{
+			Type: "Place",
+			Name: venue.Name,
+			Address: StructuredDataAddress{
+				Type:            "PostalAddress",
+				StreetAddress:   venue.Address,
+				AddressLocality: venue.City,
+				AddressRegion:   venue.State,
+				AddressCountry:  venue.Country,
+			},
+		}
+		if venue.Latitude != nil && venue.Longitude != nil {
+			data.Location.Geo = &StructuredDataCoordinates{Type: "GeoCoordinates", Latitude: *venue.Latitude, Longitude: *venue.Longitude}
+		}
+	}
+
+	for i, category := range event.TicketCategories {
+		offer := StructuredDataOffer{
+			Type:          "Offer",
+			Name:          category.Name,
+			Price:         category.Price,
+			PriceCurrency: event.Currency,
+			Availability:  structuredAvailability(availability(event, &category, now)),
+			URL:           data.URL,
+		}
+		// the sale window of the category narrows the one of the event
+		if start := latest(event.SaleStartDate, category.SaleStartDate); start != nil {
+			offer.ValidFrom = format(*start)
+		}
+		if end := earliest(event.SaleEndDate, category.SaleEndDate); end != nil {
+			offer.ValidThrough = format(*end)
+		}
+		data.Offers[i] = offer
+	}
+
+	return data
+}
+
+// structuredEventStatus returns the schema.org EventStatusType of the status; completed events took
+// place as scheduled
+func structuredEventStatus(status domain.EventStatus) string {
+	switch status {
+	case domain.EventStatusCancelled:
+		return "https://schema.org/EventCancelled"
+	case domain.EventStatusPostponed:
+		return "https://schema.org/EventPostponed"
+	default:
+		return "https://schema.org/EventScheduled"
+	}
+}
+
+// structuredAvailability returns the schema.org ItemAvailability of the availability of a category
+func structuredAvailability(availability string) string {
+	switch availability {
+	case AvailabilityOnSale:
+		return "https://schema.org/InStock"
+	case AvailabilityNotStarted:
+		return "https://schema.org/PreOrder"
+	case AvailabilitySoldOut:
+		return "https://schema.org/SoldOut"
+	case AvailabilityPaused:
+		return "https://schema.org/OutOfStock"
+	default:
+		return "https://schema.org/Discontinued"
+	}
+}
+
+func latest(times ...*time.Time) *time.Time {
+	var result *time.Time
+	for _, t := range times {
+		if t != nil && (result == nil || t.After(*result)) {
+			result = t
+		}
+	}
+	return result
+}
+
+func earliest(times ...*time.Time) *time.Time {
+	var result *time.Time
+	for _, t := range times {
+		if t != nil && (result == nil || t.Before(*result)) {
+			result = t
+		}
+	}
+	return result
+}
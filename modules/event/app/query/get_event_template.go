@@ -0,0 +1,41 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/event/app/command"
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// GetEventTemplateQuery represents the query of an organizer for one of their templates
+type GetEventTemplateQuery struct {
+	ID          int64
+	OrganizerID int64
+}
+
+// GetEventTemplateHandler handles get event template queries
+type GetEventTemplateHandler struct {
+	templateRepo domain.EventTemplateRepository
+}
+
+// NewGetEventTemplateHandler creates a new get event template handler
+func NewGetEventTemplateHandler(templateRepo domain.EventTemplateRepository) *GetEventTemplateHandler {
+	return &GetEventTemplateHandler{
+		templateRepo: templateRepo,
+	}
+}
+
+// Handle executes the get event template query. Templates of other organizers are reported as not found.
+func (h *GetEventTemplateHandler) Handle(ctx context.Context, query GetEventTemplateQuery) (*command.EventTemplateResult, error) {
+	template, err := h.templateRepo.GetByID(ctx, query.ID, query.OrganizerID)
+	if err != nil {
+		if err == domain.ErrEventTemplateNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get event template")
+	}
+
+	return command.ToEventTemplateResult(template), nil
+}
@@ -0,0 +1,254 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// Ticket availability of a category on the public event page
+const (
+	AvailabilityOnSale     = "on_sale"
+	AvailabilityNotStarted = "not_started"
+	AvailabilityEnded      = "ended"
+	AvailabilitySoldOut    = "sold_out"
+	AvailabilityPaused     = "paused"
+)
+
+// GetPublicEventQuery represents the query to get a public event page
+type GetPublicEventQuery struct {
+	// Slug is the current slug of the page or a former one
+	Slug string
+}
+
+// PublicEventResult is the public event page
+type PublicEventResult struct {
+	ID                 int64                        `json:"id"`
+	Slug               string                       `json:"slug"`
+	Title              string                       `json:"title"`
+	Description        string                       `json:"description"`
+	EventType          string                       `json:"event_type"`
+	Status             domain.EventStatus           `json:"status"`
+	Timezone           string                       `json:"timezone"`
+	Currency           string                       `json:"currency"`
+	ImageURL           string                       `json:"image_url,omitempty"`
+	AgeRestriction     *int                         `json:"age_restriction,omitempty"`
+	MaxTicketsPerOrder int                          `json:"max_tickets_per_order"`
+	Sessions           []PublicSessionResult        `json:"sessions"`
+	Venue              *PublicVenueResult           `json:"venue,omitempty"`
+	Organizer          PublicOrganizerResult        `json:"organizer"`
+	TicketCategories   []PublicTicketCategoryResult `json:"ticket_categories"`
+	SEO                PublicSEOResult              `json:"seo"`
+	UpdatedAt          string                       `json:"updated_at"`
+}
+
+// PublicSEOResult are the meta tags of the page, the title and start of the description of the event
+// when the organizer wrote none
+type PublicSEOResult struct {
+	MetaTitle       string `json:"meta_title"`
+	MetaDescription string `json:"meta_description"`
+	// CanonicalURL is the URL of the page on the site under its current slug
+	CanonicalURL string `json:"canonical_url,omitempty"`
+}
+
+// PublicSessionResult is an occurrence of the event
+type PublicSessionResult struct {
+	StartDate string  `json:"start_date"`
+	EndDate   *string `json:"end_date,omitempty"`
+}
+
+// PublicVenueResult is where the event takes place
+type PublicVenueResult struct {
+	Name      string   `json:"name"`
+	Address   string   `json:"address"`
+	City      string   `json:"city"`
+	State     string   `json:"state,omitempty"`
+	Country   string   `json:"country"`
+	VenueType string   `json:"venue_type"`
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+}
+
+// PublicOrganizerResult is the organizer of the event
+type PublicOrganizerResult struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// PublicTicketCategoryResult is a ticket category with its availability
+type PublicTicketCategoryResult struct {
+	ID            int64   `json:"id"`
+	Name          string  `json:"name"`
+	Description   string  `json:"description,omitempty"`
+	CategoryType  string  `json:"category_type"`
+	Price         string  `json:"price"`
+	MaxPerOrder   int     `json:"max_per_order"`
+	Remaining     int     `json:"remaining"`
+	Availability  string  `json:"availability"`
+	SaleStartDate *string `json:"sale_start_date,omitempty"`
+	SaleEndDate   *string `json:"sale_end_date,omitempty"`
+}
+
+// GetPublicEventHandler handles getting public event pages
+type GetPublicEventHandler struct {
+	publicEventRepo domain.PublicEventRepository
+	seoRepo         domain.EventSEORepository
+	site            domain.Site
+}
+
+// NewGetPublicEventHandler creates a new get public event handler. Pages are given no canonical URL
+// when site has none.
+func NewGetPublicEventHandler(publicEventRepo domain.PublicEventRepository, seoRepo domain.EventSEORepository, site domain.Site) *GetPublicEventHandler {
+	return &GetPublicEventHandler{
+		publicEventRepo: publicEventRepo,
+		seoRepo:         seoRepo,
+		site:            site,
+	}
+}
+
+// Handle executes the get public event query. A former slug gets the page at its current slug, which
+// the result tells.
+func (h *GetPublicEventHandler) Handle(ctx context.Context, query GetPublicEventQuery) (*PublicEventResult, error) {
+	event, err := getPublicEvent(ctx, h.publicEventRepo, h.seoRepo, query.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPublicEventResult(event, h.site, time.Now()), nil
+}
+
+// getPublicEvent loads the public event page of the slug, following the redirect of a former slug
+func getPublicEvent(ctx context.Context, publicEventRepo domain.PublicEventRepository, seoRepo domain.EventSEORepository, slug string) (*domain.PublicEvent, error) {
+	if slug == "" {
+		return nil, syserr.New(syserr.InvalidArgumentCode, "slug is required")
+	}
+
+	event, err := publicEventRepo.GetPublicBySlug(ctx, slug)
+	if err == domain.ErrEventNotFound {
+		slug, err = seoRepo.GetCanonicalSlug(ctx, slug)
+		if err != nil {
+			if err == domain.ErrEventNotFound {
+				return nil, err
+			}
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get canonical slug")
+		}
+		event, err = publicEventRepo.GetPublicBySlug(ctx, slug)
+	}
+	if err != nil {
+		if err == domain.ErrEventNotFound {
+			return nil, domain.ErrEventNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get public event")
+	}
+
+	return event, nil
+}
+
+func toPublicEventResult(event *domain.PublicEvent, site domain.Site, now time.Time) *PublicEventResult {
+	result := &PublicEventResult{
+		ID:                 event.ID,
+		Slug:               event.Slug,
+		Title:              event.Title,
+		Description:        event.Description,
+		EventType:          event.EventType,
+		Status:             event.Status,
+		Timezone:           event.Timezone,
+		Currency:           event.Currency,
+		ImageURL:           event.ImageURL,
+		AgeRestriction:     event.AgeRestriction,
+		MaxTicketsPerOrder: event.MaxTicketsPerOrder,
+		Sessions:           make([]PublicSessionResult, len(event.Sessions)),
+		Organizer:          PublicOrganizerResult{ID: event.Organizer.ID, Name: event.Organizer.Name},
+		TicketCategories:   make([]PublicTicketCategoryResult, len(event.TicketCategories)),
+		SEO:                toPublicSEOResult(event, site),
+		UpdatedAt:          event.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+
+	for i, session := range event.Sessions {
+		result.Sessions[i] = PublicSessionResult{
+			StartDate: session.StartDate.Format("2006-01-02T15:04:05Z"),
+			EndDate:   formatOptionalTime(session.EndDate),
+		}
+	}
+
+	if venue := event.Venue; venue != nil {
+		result.Venue = &PublicVenueResult{
+			Name:      venue.Name,
+			Address:   venue.Address,
+			City:      venue.City,
+			State:     venue.State,
+			Country:   venue.Country,
+			VenueType: venue.VenueType,
+			Latitude:  venue.Latitude,
+			Longitude: venue.Longitude,
+		}
+	}
+
+	for i, category := range event.TicketCategories {
+		result.TicketCategories[i] = PublicTicketCategoryResult{
+			ID:            category.ID,
+			Name:          category.Name,
+			Description:   category.Description,
+			CategoryType:  category.CategoryType,
+			Price:         category.Price,
+			MaxPerOrder:   category.MaxPerOrder,
+			Remaining:     category.Remaining(),
+			Availability:  availability(event, &category, now),
+			SaleStartDate: formatOptionalTime(category.SaleStartDate),
+			SaleEndDate:   formatOptionalTime(category.SaleEndDate),
+		}
+	}
+
+	return result
+}
+
+func toPublicSEOResult(event *domain.PublicEvent, site domain.Site) PublicSEOResult {
+	result := PublicSEOResult{
+		MetaTitle:       event.MetaTitle,
+		MetaDescription: domain.MetaDescription(event.MetaDescription, event.Description),
+	}
+	if result.MetaTitle == "" {
+		result.MetaTitle = event.Title
+	}
+	if site.URL != "" {
+		result.CanonicalURL = site.EventURL(event.Slug)
+	}
+	return result
+}
+
+// availability tells whether a category can be bought now; the sale window of the category
+// narrows the one of the event, and pausing the sales of either stops them
+func availability(event *domain.PublicEvent, category *domain.PublicTicketCategory, now time.Time) string {
+	if event.Status != domain.EventStatusPublished {
+		return AvailabilityEnded
+	}
+	if event.SalesPause.IsPaused(now) || category.SalesPause.IsPaused(now) {
+		return AvailabilityPaused
+	}
+	if category.Remaining() == 0 {
+		return AvailabilitySoldOut
+	}
+
+	for _, start := range []*time.Time{event.SaleStartDate, category.SaleStartDate} {
+		if start != nil && now.Before(*start) {
+			return AvailabilityNotStarted
+		}
+	}
+	for _, end := range []*time.Time{event.SaleEndDate, category.SaleEndDate} {
+		if end != nil && !now.Before(*end) {
+			return AvailabilityEnded
+		}
+	}
+	return AvailabilityOnSale
+}
+
+func formatOptionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := t.Format("2006-01-02T15:04:05Z")
+	return &formatted
+}
@@ -0,0 +1,50 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/event/app/command"
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// GetQueueStatusQuery represents the query to get the place of a buyer in an on-sale queue
+type GetQueueStatusQuery struct {
+	EventID int64
+	UserID  int64
+	Token   string
+}
+
+// GetQueueStatusHandler handles queue status polls
+type GetQueueStatusHandler struct {
+	settingsRepo domain.QueueSettingsRepository
+	queue        domain.OnSaleQueue
+}
+
+// NewGetQueueStatusHandler creates a new get queue status handler
+func NewGetQueueStatusHandler(settingsRepo domain.QueueSettingsRepository, queue domain.OnSaleQueue) *GetQueueStatusHandler {
+	return &GetQueueStatusHandler{
+		settingsRepo: settingsRepo,
+		queue:        queue,
+	}
+}
+
+// Handle executes the get queue status query. Polls drive the gate: each one admits the buyers
+// freed checkout slots make room for.
+func (h *GetQueueStatusHandler) Handle(ctx context.Context, query GetQueueStatusQuery) (*command.QueueTicketResult, error) {
+	settings, err := h.settingsRepo.GetQueueSettings(ctx, query.EventID)
+	if err != nil {
+		if err == domain.ErrQueueDisabled {
+			return nil, domain.ErrQueueDisabled
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get queue settings")
+	}
+
+	ticket, err := h.queue.Status(ctx, settings, query.UserID, query.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	return command.ToQueueTicketResult(settings, ticket), nil
+}
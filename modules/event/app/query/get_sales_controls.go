@@ -0,0 +1,41 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/event/app/command"
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// GetSalesControlsQuery represents the query of an organizer for the sales state of their event
+type GetSalesControlsQuery struct {
+	EventID     int64
+	OrganizerID int64
+}
+
+// GetSalesControlsHandler handles getting sales controls
+type GetSalesControlsHandler struct {
+	salesRepo domain.SalesControlRepository
+}
+
+// NewGetSalesControlsHandler creates a new get sales controls handler
+func NewGetSalesControlsHandler(salesRepo domain.SalesControlRepository) *GetSalesControlsHandler {
+	return &GetSalesControlsHandler{
+		salesRepo: salesRepo,
+	}
+}
+
+// Handle executes the get sales controls query. Events of other organizers are reported as not found.
+func (h *GetSalesControlsHandler) Handle(ctx context.Context, query GetSalesControlsQuery) (*command.SalesControlsResult, error) {
+	controls, err := h.salesRepo.Get(ctx, query.EventID, query.OrganizerID)
+	if err != nil {
+		if err == domain.ErrEventNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get sales controls")
+	}
+
+	return command.ToSalesControlsResult(controls), nil
+}
@@ -0,0 +1,105 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// GetSalesForecastQuery represents the query of an organizer for the projected sales of their event
+type GetSalesForecastQuery struct {
+	EventID     int64
+	OrganizerID int64
+}
+
+// SalesForecastResult represents the sales of an event so far and their projection until its sales end.
+// Amounts are decimal strings in the currency of the orders.
+type SalesForecastResult struct {
+	EventID   int64  `json:"event_id"`
+	Model     string `json:"model"`
+	Capacity  int    `json:"capacity"`
+	Sold      int    `json:"sold"`
+	Remaining int    `json:"remaining"`
+	Revenue   string `json:"revenue"`
+	SoldOut   bool   `json:"sold_out"`
+	// Velocity is the tickets expected to sell per day
+	Velocity float64 `json:"velocity"`
+	// SellOutAt is nil when the event is not expected to sell out before SalesEndAt
+	SellOutAt        *string            `json:"sell_out_at"`
+	SalesEndAt       string             `json:"sales_end_at"`
+	ProjectedSold    int                `json:"projected_sold"`
+	ProjectedRevenue string             `json:"projected_revenue"`
+	Daily            []DailySalesResult `json:"daily"`
+}
+
+// DailySalesResult represents the tickets sold on a day
+type DailySalesResult struct {
+	Date    string `json:"date"`
+	Tickets int    `json:"tickets"`
+	Revenue string `json:"revenue"`
+}
+
+// GetSalesForecastHandler handles forecasting the sales of events
+type GetSalesForecastHandler struct {
+	historyRepo domain.SalesHistoryRepository
+	forecaster  domain.SalesForecaster
+}
+
+// NewGetSalesForecastHandler creates a new get sales forecast handler projecting with forecaster
+func NewGetSalesForecastHandler(historyRepo domain.SalesHistoryRepository, forecaster domain.SalesForecaster) *GetSalesForecastHandler {
+	return &GetSalesForecastHandler{
+		historyRepo: historyRepo,
+		forecaster:  forecaster,
+	}
+}
+
+// Handle executes the get sales forecast query. Events of other organizers are reported as not found.
+func (h *GetSalesForecastHandler) Handle(ctx context.Context, query GetSalesForecastQuery) (*SalesForecastResult, error) {
+	history, err := h.historyRepo.GetSalesHistory(ctx, query.EventID, query.OrganizerID)
+	if err != nil {
+		if err == domain.ErrEventNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get sales history")
+	}
+
+	forecast := h.forecaster.Forecast(history, time.Now())
+
+	result := &SalesForecastResult{
+		EventID:          history.EventID,
+		Model:            forecast.Model,
+		Capacity:         history.Capacity,
+		Sold:             history.Sold,
+		Remaining:        history.Remaining,
+		Revenue:          formatCents(history.Revenue()),
+		SoldOut:          forecast.SoldOut,
+		Velocity:         math.Round(forecast.Velocity*100) / 100,
+		SalesEndAt:       history.SalesEnd.Format("2006-01-02T15:04:05Z"),
+		ProjectedSold:    forecast.ProjectedSold,
+		ProjectedRevenue: formatCents(forecast.ProjectedRevenue),
+		Daily:            make([]DailySalesResult, len(history.Daily)),
+	}
+	if forecast.SellOutAt != nil {
+		sellOutAt := forecast.SellOutAt.UTC().Format("2006-01-02T15:04:05Z")
+		result.SellOutAt = &sellOutAt
+	}
+	for i, day := range history.Daily {
+		result.Daily[i] = DailySalesResult{
+			Date:    day.Day.Format("2006-01-02"),
+			Tickets: day.Tickets,
+			Revenue: formatCents(day.Revenue),
+		}
+	}
+
+	return result, nil
+}
+
+// formatCents writes an amount in cents as a decimal string with 2 decimals
+func formatCents(cents int64) string {
+	return fmt.Sprintf("%d.%02d", cents/100, cents%100)
+}
@@ -0,0 +1,83 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// GetSeatMapQuery represents the query to get the seat map of an event
+type GetSeatMapQuery struct {
+	EventID int64
+}
+
+// SeatMapResult is the seat map of an event
+type SeatMapResult struct {
+	EventID int64        `json:"event_id"`
+	Seats   []SeatResult `json:"seats"`
+}
+
+// SeatResult is a seat with its status
+type SeatResult struct {
+	TicketID         int64             `json:"ticket_id"`
+	TicketCategoryID int64             `json:"ticket_category_id"`
+	Section          string            `json:"section"`
+	Row              string            `json:"row"`
+	Number           string            `json:"number"`
+	Status           domain.SeatStatus `json:"status"`
+}
+
+// SeatChangeResult is an incremental update of a seat map
+type SeatChangeResult struct {
+	TicketID   int64             `json:"ticket_id"`
+	Status     domain.SeatStatus `json:"status"`
+	OccurredAt string            `json:"occurred_at"`
+}
+
+// GetSeatMapHandler handles getting seat maps
+type GetSeatMapHandler struct {
+	seatMapRepo domain.SeatMapRepository
+}
+
+// NewGetSeatMapHandler creates a new get seat map handler
+func NewGetSeatMapHandler(seatMapRepo domain.SeatMapRepository) *GetSeatMapHandler {
+	return &GetSeatMapHandler{
+		seatMapRepo: seatMapRepo,
+	}
+}
+
+// Handle executes the get seat map query
+func (h *GetSeatMapHandler) Handle(ctx context.Context, query GetSeatMapQuery) (*SeatMapResult, error) {
+	seats, err := h.seatMapRepo.GetSeatMap(ctx, query.EventID)
+	if err != nil {
+		if err == domain.ErrEventNotFound {
+			return nil, domain.ErrEventNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get seat map")
+	}
+
+	result := &SeatMapResult{EventID: query.EventID, Seats: make([]SeatResult, len(seats))}
+	for i, seat := range seats {
+		result.Seats[i] = SeatResult{
+			TicketID:         seat.TicketID,
+			TicketCategoryID: seat.TicketCategoryID,
+			Section:          seat.Section,
+			Row:              seat.Row,
+			Number:           seat.Number,
+			Status:           seat.Status,
+		}
+	}
+
+	return result, nil
+}
+
+// ToSeatChangeResult converts a seat change to its result
+func ToSeatChangeResult(change *domain.EventSeatStatusChanged) *SeatChangeResult {
+	return &SeatChangeResult{
+		TicketID:   change.TicketID,
+		Status:     change.Status,
+		OccurredAt: change.OccurredAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
@@ -0,0 +1,58 @@
+package query
+
+import (
+	"context"
+	"encoding/xml"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// MaxSitemapURLs is the most URLs the sitemap protocol allows in a sitemap
+const MaxSitemapURLs = 50000
+
+// Sitemap is the sitemap of the public event pages, in the sitemaps.org protocol
+type Sitemap struct {
+	XMLName xml.Name     `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []SitemapURL `xml:"url"`
+}
+
+// SitemapURL is a page of the sitemap
+type SitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+// GetSitemapHandler handles generating the sitemap of the public event pages
+type GetSitemapHandler struct {
+	seoRepo domain.EventSEORepository
+	site    domain.Site
+}
+
+// NewGetSitemapHandler creates a new get sitemap handler
+func NewGetSitemapHandler(seoRepo domain.EventSEORepository, site domain.Site) *GetSitemapHandler {
+	return &GetSitemapHandler{
+		seoRepo: seoRepo,
+		site:    site,
+	}
+}
+
+// Handle generates the sitemap of the published and postponed event pages, the most recently updated
+// ones when there are more than a sitemap holds
+func (h *GetSitemapHandler) Handle(ctx context.Context) (*Sitemap, error) {
+	entries, err := h.seoRepo.ListSitemapEntries(ctx, MaxSitemapURLs)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list sitemap entries")
+	}
+
+	sitemap := &Sitemap{URLs: make([]SitemapURL, len(entries))}
+	for i, entry := range entries {
+		sitemap.URLs[i] = SitemapURL{
+			Loc:     h.site.EventURL(entry.Slug),
+			LastMod: entry.UpdatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		}
+	}
+
+	return sitemap, nil
+}
@@ -0,0 +1,46 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/event/app/command"
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ListAllotmentsQuery represents the query of an organizer for the allotments of their event
+type ListAllotmentsQuery struct {
+	EventID     int64
+	OrganizerID int64
+}
+
+// ListAllotmentsHandler handles listing allotments
+type ListAllotmentsHandler struct {
+	allotmentRepo domain.AllotmentRepository
+}
+
+// NewListAllotmentsHandler creates a new list allotments handler
+func NewListAllotmentsHandler(allotmentRepo domain.AllotmentRepository) *ListAllotmentsHandler {
+	return &ListAllotmentsHandler{
+		allotmentRepo: allotmentRepo,
+	}
+}
+
+// Handle executes the list allotments query. Events of other organizers are reported as not found.
+func (h *ListAllotmentsHandler) Handle(ctx context.Context, query ListAllotmentsQuery) ([]*command.AllotmentResult, error) {
+	allotments, err := h.allotmentRepo.List(ctx, query.EventID, query.OrganizerID)
+	if err != nil {
+		if err == domain.ErrEventNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list allotments")
+	}
+
+	items := make([]*command.AllotmentResult, len(allotments))
+	for i, allotment := range allotments {
+		items[i] = command.ToAllotmentResult(allotment)
+	}
+
+	return items, nil
+}
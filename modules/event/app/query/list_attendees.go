@@ -0,0 +1,101 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/event/domain"
+	"tixgo/shared/listing"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ListAttendeesQuery represents the query of an organizer for the attendee manifest of their event
+type ListAttendeesQuery struct {
+	EventID     int64
+	OrganizerID int64
+}
+
+// AttendeeItem represents a ticket of the manifest with its holder, its attendee when assigned, its
+// answers and the accommodations asked for with its order
+type AttendeeItem struct {
+	TicketID           int64             `json:"ticket_id"`
+	TicketNumber       string            `json:"ticket_number"`
+	TicketStatus       string            `json:"ticket_status"`
+	TicketCategory     string            `json:"ticket_category"`
+	SeatSection        string            `json:"seat_section"`
+	SeatRow            string            `json:"seat_row"`
+	SeatNumber         string            `json:"seat_number"`
+	OrderNumber        string            `json:"order_number"`
+	Email              string            `json:"email"`
+	Name               string            `json:"name"`
+	AttendeeName       string            `json:"attendee_name"`
+	AttendeeEmail      string            `json:"attendee_email"`
+	Answers            map[string]string `json:"answers"`
+	AccessNeeds        []string          `json:"access_needs"`
+	DietaryNeeds       []string          `json:"dietary_needs"`
+	AccommodationNotes string            `json:"accommodation_notes"`
+}
+
+// ListAttendeesHandler handles listing attendees
+type ListAttendeesHandler struct {
+	attendeeRepo domain.AttendeeRepository
+}
+
+// NewListAttendeesHandler creates a new list attendees handler
+func NewListAttendeesHandler(attendeeRepo domain.AttendeeRepository) *ListAttendeesHandler {
+	return &ListAttendeesHandler{
+		attendeeRepo: attendeeRepo,
+	}
+}
+
+// Handle executes the list attendees query. Events of other organizers are reported as not found.
+func (h *ListAttendeesHandler) Handle(ctx context.Context, query ListAttendeesQuery, paging *listing.Paging) ([]*AttendeeItem, error) {
+	// Ensure paging is not nil (should already be handled in HTTP layer)
+	if paging == nil {
+		paging = &listing.Paging{}
+		paging.Fulfill()
+	}
+
+	attendees, err := h.attendeeRepo.List(ctx, query.EventID, query.OrganizerID, paging)
+	if err != nil {
+		if err == domain.ErrEventNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list attendees")
+	}
+
+	items := make([]*AttendeeItem, len(attendees))
+	for i, attendee := range attendees {
+		items[i] = toAttendeeItem(attendee)
+	}
+
+	return items, nil
+}
+
+// Stream calls fn with every attendee of the event, reading them from the database as fn consumes them
+func (h *ListAttendeesHandler) Stream(ctx context.Context, query ListAttendeesQuery, fn func(item *AttendeeItem) error) error {
+	return h.attendeeRepo.Stream(ctx, query.EventID, query.OrganizerID, func(attendee *domain.Attendee) error {
+		return fn(toAttendeeItem(attendee))
+	})
+}
+
+func toAttendeeItem(attendee *domain.Attendee) *AttendeeItem {
+	return &AttendeeItem{
+		TicketID:           attendee.TicketID,
+		TicketNumber:       attendee.TicketNumber,
+		TicketStatus:       attendee.TicketStatus,
+		TicketCategory:     attendee.TicketCategoryName,
+		SeatSection:        attendee.SeatSection,
+		SeatRow:            attendee.SeatRow,
+		SeatNumber:         attendee.SeatNumber,
+		OrderNumber:        attendee.OrderNumber,
+		Email:              attendee.Email,
+		Name:               attendee.Name,
+		AttendeeName:       attendee.AttendeeName,
+		AttendeeEmail:      attendee.AttendeeEmail,
+		Answers:            attendee.Answers,
+		AccessNeeds:        attendee.AccessNeeds,
+		DietaryNeeds:       attendee.DietaryNeeds,
+		AccommodationNotes: attendee.AccommodationNotes,
+	}
+}
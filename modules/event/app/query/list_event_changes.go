@@ -0,0 +1,103 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/event/domain"
+	"tixgo/shared/listing"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// maxEventChangePageSize bounds the changes returned per page
+const maxEventChangePageSize = 100
+
+// ListEventChangesQuery represents the query of an organizer for the change feed of their event
+type ListEventChangesQuery struct {
+	EventID          int64  `json:"-" form:"-"`
+	OrganizerID      int64  `json:"-" form:"-"`
+	Action           string `json:"action" form:"action" binding:"omitempty,oneof=details_updated published cancelled seo_updated capacity_changed sales_paused sales_resumed pause_scheduled"`
+	TicketCategoryID int64  `json:"ticket_category_id" form:"ticket_category_id" binding:"omitempty,min=1"`
+}
+
+// ChangeActorItem represents the collaborator who made a change, empty when their account is gone
+type ChangeActorItem struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// EventChangeItem represents a change of the change feed of an event
+type EventChangeItem struct {
+	ID                 int64                `json:"id"`
+	Action             string               `json:"action"`
+	TicketCategoryID   int64                `json:"ticket_category_id,omitempty"`
+	TicketCategoryName string               `json:"ticket_category_name,omitempty"`
+	Fields             []domain.FieldChange `json:"fields"`
+	Actor              ChangeActorItem      `json:"actor"`
+	// APIKeyID is the API key the change was made with, 0 when made from a session
+	APIKeyID   int64  `json:"api_key_id,omitempty"`
+	OccurredAt string `json:"occurred_at"`
+}
+
+// ListEventChangesHandler handles listing the change feed of an event
+type ListEventChangesHandler struct {
+	changeRepo domain.EventChangeRepository
+}
+
+// NewListEventChangesHandler creates a new list event changes handler
+func NewListEventChangesHandler(changeRepo domain.EventChangeRepository) *ListEventChangesHandler {
+	return &ListEventChangesHandler{
+		changeRepo: changeRepo,
+	}
+}
+
+// Handle executes the list event changes query. Events of other organizers are reported as not found.
+func (h *ListEventChangesHandler) Handle(ctx context.Context, query ListEventChangesQuery, paging *listing.Paging) ([]EventChangeItem, error) {
+	// Ensure paging is not nil (should already be handled in HTTP layer)
+	if paging == nil {
+		paging = &listing.Paging{}
+		paging.Fulfill()
+	}
+	if paging.Limit > maxEventChangePageSize {
+		paging.Limit = maxEventChangePageSize
+	}
+
+	filters := domain.ChangeFilters{
+		EventID:          query.EventID,
+		OrganizerID:      query.OrganizerID,
+		Action:           domain.ChangeAction(query.Action),
+		TicketCategoryID: query.TicketCategoryID,
+	}
+	changes, err := h.changeRepo.List(ctx, filters, paging)
+	if err != nil {
+		if err == domain.ErrEventNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list event changes")
+	}
+
+	items := make([]EventChangeItem, len(changes))
+	for i, change := range changes {
+		fields := change.Fields
+		if fields == nil {
+			fields = []domain.FieldChange{}
+		}
+		items[i] = EventChangeItem{
+			ID:                 change.ID,
+			Action:             string(change.Action),
+			TicketCategoryID:   change.TicketCategoryID,
+			TicketCategoryName: change.TicketCategoryName,
+			Fields:             fields,
+			Actor: ChangeActorItem{
+				ID:    change.Actor.ID,
+				Name:  change.Actor.Name,
+				Email: change.Actor.Email,
+			},
+			APIKeyID:   change.APIKeyID,
+			OccurredAt: change.OccurredAt.UTC().Format("2006-01-02T15:04:05Z"),
+		}
+	}
+
+	return items, nil
+}
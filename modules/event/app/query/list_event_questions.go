@@ -0,0 +1,46 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/event/app/command"
+	"tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ListEventQuestionsQuery represents the query for the attendee questionnaire of an event
+type ListEventQuestionsQuery struct {
+	EventID int64
+}
+
+// ListEventQuestionsHandler handles listing event questions
+type ListEventQuestionsHandler struct {
+	questionRepo domain.EventQuestionRepository
+}
+
+// NewListEventQuestionsHandler creates a new list event questions handler
+func NewListEventQuestionsHandler(questionRepo domain.EventQuestionRepository) *ListEventQuestionsHandler {
+	return &ListEventQuestionsHandler{
+		questionRepo: questionRepo,
+	}
+}
+
+// Handle executes the list event questions query. The questionnaire is what buyers fill in at
+// checkout, so it is not restricted to the organizer.
+func (h *ListEventQuestionsHandler) Handle(ctx context.Context, query ListEventQuestionsQuery) ([]*command.EventQuestionResult, error) {
+	questions, err := h.questionRepo.List(ctx, query.EventID)
+	if err != nil {
+		if err == domain.ErrEventNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list event questions")
+	}
+
+	items := make([]*command.EventQuestionResult, len(questions))
+	for i, question := range questions {
+		items[i] = command.ToEventQuestionResult(question)
+	}
+
+	return items, nil
+}
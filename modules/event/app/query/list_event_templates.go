@@ -0,0 +1,49 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/event/app/command"
+	"tixgo/modules/event/domain"
+	"tixgo/shared/listing"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ListEventTemplatesQuery represents the query of an organizer for their templates
+type ListEventTemplatesQuery struct {
+	OrganizerID int64
+}
+
+// ListEventTemplatesHandler handles listing event templates
+type ListEventTemplatesHandler struct {
+	templateRepo domain.EventTemplateRepository
+}
+
+// NewListEventTemplatesHandler creates a new list event templates handler
+func NewListEventTemplatesHandler(templateRepo domain.EventTemplateRepository) *ListEventTemplatesHandler {
+	return &ListEventTemplatesHandler{
+		templateRepo: templateRepo,
+	}
+}
+
+// Handle executes the list event templates query
+func (h *ListEventTemplatesHandler) Handle(ctx context.Context, query ListEventTemplatesQuery, paging *listing.Paging) ([]*command.EventTemplateResult, error) {
+	// Ensure paging is not nil (should already be handled in HTTP layer)
+	if paging == nil {
+		paging = &listing.Paging{}
+		paging.Fulfill()
+	}
+
+	templates, err := h.templateRepo.List(ctx, query.OrganizerID, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list event templates")
+	}
+
+	items := make([]*command.EventTemplateResult, len(templates))
+	for i, template := range templates {
+		items[i] = command.ToEventTemplateResult(template)
+	}
+
+	return items, nil
+}
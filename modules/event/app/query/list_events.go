@@ -0,0 +1,51 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/event/app/command"
+	"tixgo/modules/event/domain"
+	"tixgo/shared/listing"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ListEventsQuery represents the query of an organizer for their events
+type ListEventsQuery struct {
+	OrganizerID int64  `json:"-" form:"-"`
+	Status      string `json:"status" form:"status" binding:"omitempty,oneof=draft published cancelled postponed completed"`
+}
+
+// ListEventsHandler handles listing events
+type ListEventsHandler struct {
+	eventRepo domain.EventRepository
+}
+
+// NewListEventsHandler creates a new list events handler
+func NewListEventsHandler(eventRepo domain.EventRepository) *ListEventsHandler {
+	return &ListEventsHandler{
+		eventRepo: eventRepo,
+	}
+}
+
+// Handle executes the list events query
+func (h *ListEventsHandler) Handle(ctx context.Context, query ListEventsQuery, paging *listing.Paging) ([]*command.EventResult, error) {
+	// Ensure paging is not nil (should already be handled in HTTP layer)
+	if paging == nil {
+		paging = &listing.Paging{}
+		paging.Fulfill()
+	}
+
+	filters := domain.EventFilters{OrganizerID: query.OrganizerID, Status: domain.EventStatus(query.Status)}
+	events, err := h.eventRepo.List(ctx, filters, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list events")
+	}
+
+	items := make([]*command.EventResult, len(events))
+	for i, event := range events {
+		items[i] = command.ToEventResult(event)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,163 @@
+package query
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"tixgo/modules/event/domain"
+	"tixgo/shared/listing"
+	"tixgo/shared/money"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// SearchEventsQuery represents the filters of the public event search
+type SearchEventsQuery struct {
+	// From and To are the first and last days the events start on (UTC, both included). From is now by
+	// default, and To a year after From.
+	From     string   `json:"from,omitempty" form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To       string   `json:"to,omitempty" form:"to" binding:"omitempty,datetime=2006-01-02"`
+	Category string   `json:"category,omitempty" form:"category" binding:"omitempty,oneof=concert sports theater conference festival other"`
+	City     string   `json:"city,omitempty" form:"city" binding:"max=100"`
+	VenueID  int64    `json:"venue_id,omitempty" form:"venue_id" binding:"omitempty,min=1"`
+	MinPrice *float64 `json:"min_price,omitempty" form:"min_price" binding:"omitempty,min=0"`
+	MaxPrice *float64 `json:"max_price,omitempty" form:"max_price" binding:"omitempty,min=0"`
+	// Currency keeps the events priced in it; prices are compared in the currency of each event
+	Currency string `json:"currency,omitempty" form:"currency" binding:"omitempty,len=3"`
+	// Q is searched for in the title and description of the events: words, "quoted phrases", or and
+	// -excluded words
+	Q string `json:"q,omitempty" form:"q" binding:"max=200"`
+}
+
+// PublicEventSummaryResult is an event found by the public search
+type PublicEventSummaryResult struct {
+	ID        int64                 `json:"id"`
+	Slug      string                `json:"slug"`
+	Title     string                `json:"title"`
+	EventType string                `json:"event_type"`
+	Status    domain.EventStatus    `json:"status"`
+	Timezone  string                `json:"timezone"`
+	Currency  string                `json:"currency"`
+	ImageURL  string                `json:"image_url,omitempty"`
+	StartDate string                `json:"start_date"`
+	EndDate   *string               `json:"end_date,omitempty"`
+	Venue     *PublicVenueResult    `json:"venue,omitempty"`
+	Organizer PublicOrganizerResult `json:"organizer"`
+	// MinPrice and MaxPrice are the prices of the cheapest and dearest ticket categories
+	MinPrice string `json:"min_price,omitempty"`
+	MaxPrice string `json:"max_price,omitempty"`
+}
+
+// SearchEventsHandler handles the public event search
+type SearchEventsHandler struct {
+	publicEventRepo domain.PublicEventRepository
+}
+
+// NewSearchEventsHandler creates a new search events handler
+func NewSearchEventsHandler(publicEventRepo domain.PublicEventRepository) *SearchEventsHandler {
+	return &SearchEventsHandler{
+		publicEventRepo: publicEventRepo,
+	}
+}
+
+// Handle executes the search events query
+func (h *SearchEventsHandler) Handle(ctx context.Context, query SearchEventsQuery, paging *listing.Paging) ([]*PublicEventSummaryResult, error) {
+	// Ensure paging is not nil (should already be handled in HTTP layer)
+	if paging == nil {
+		paging = &listing.Paging{}
+		paging.Fulfill()
+	}
+
+	search, err := query.toEventSearch(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := h.publicEventRepo.Search(ctx, search, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to search events")
+	}
+
+	items := make([]*PublicEventSummaryResult, len(events))
+	for i, event := range events {
+		items[i] = toPublicEventSummaryResult(event)
+	}
+
+	return items, nil
+}
+
+// toEventSearch turns the days of the query into the period of the search
+func (q SearchEventsQuery) toEventSearch(now time.Time) (domain.EventSearch, error) {
+	search := domain.EventSearch{
+		From:      now.UTC(),
+		EventType: q.Category,
+		City:      strings.TrimSpace(q.City),
+		VenueID:   q.VenueID,
+		MinPrice:  q.MinPrice,
+		MaxPrice:  q.MaxPrice,
+		Text:      strings.TrimSpace(q.Q),
+	}
+
+	if q.Currency != "" {
+		currency, err := money.Normalize(q.Currency)
+		if err != nil {
+			return domain.EventSearch{}, domain.ErrInvalidCurrency
+		}
+		search.Currency = currency
+	}
+
+	if q.From != "" {
+		from, err := time.Parse("2006-01-02", q.From)
+		if err != nil {
+			return domain.EventSearch{}, domain.ErrInvalidSearchPeriod
+		}
+		search.From = from
+	}
+	search.To = search.From.Add(domain.MaxSearchPeriod)
+	if q.To != "" {
+		to, err := time.Parse("2006-01-02", q.To)
+		if err != nil {
+			return domain.EventSearch{}, domain.ErrInvalidSearchPeriod
+		}
+		search.To = to.AddDate(0, 0, 1)
+	}
+
+	if err := search.Validate(); err != nil {
+		return domain.EventSearch{}, err
+	}
+	return search, nil
+}
+
+func toPublicEventSummaryResult(event *domain.PublicEventSummary) *PublicEventSummaryResult {
+	result := &PublicEventSummaryResult{
+		ID:        event.ID,
+		Slug:      event.Slug,
+		Title:     event.Title,
+		EventType: event.EventType,
+		Status:    event.Status,
+		Timezone:  event.Timezone,
+		Currency:  event.Currency,
+		ImageURL:  event.ImageURL,
+		StartDate: event.StartDate.Format("2006-01-02T15:04:05Z"),
+		EndDate:   formatOptionalTime(event.EndDate),
+		Organizer: PublicOrganizerResult{ID: event.Organizer.ID, Name: event.Organizer.Name},
+		MinPrice:  event.MinPrice,
+		MaxPrice:  event.MaxPrice,
+	}
+
+	if venue := event.Venue; venue != nil {
+		result.Venue = &PublicVenueResult{
+			Name:      venue.Name,
+			Address:   venue.Address,
+			City:      venue.City,
+			State:     venue.State,
+			Country:   venue.Country,
+			VenueType: venue.VenueType,
+			Latitude:  venue.Latitude,
+			Longitude: venue.Longitude,
+		}
+	}
+
+	return result
+}
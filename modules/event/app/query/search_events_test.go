@@ -0,0 +1,38 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"tixgo/modules/event/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchEventsQueryToEventSearch(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+
+	search, err := SearchEventsQuery{Category: "concert", City: " Hanoi ", Q: " jazz "}.toEventSearch(now)
+	require.NoError(t, err)
+	assert.Equal(t, now, search.From, "upcoming events by default")
+	assert.Equal(t, now.Add(domain.MaxSearchPeriod), search.To)
+	assert.Equal(t, "concert", search.EventType)
+	assert.Equal(t, "Hanoi", search.City)
+	assert.Equal(t, "jazz", search.Text)
+
+	search, err = SearchEventsQuery{From: "2026-12-24", To: "2026-12-31"}.toEventSearch(now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 12, 24, 0, 0, 0, 0, time.UTC), search.From)
+	assert.Equal(t, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), search.To, "the last day is included")
+
+	search, err = SearchEventsQuery{From: "2026-12-24", To: "2026-12-24"}.toEventSearch(now)
+	require.NoError(t, err, "a single day")
+	assert.Equal(t, 24*time.Hour, search.To.Sub(search.From))
+
+	_, err = SearchEventsQuery{From: "2026-12-31", To: "2026-12-24"}.toEventSearch(now)
+	assert.Equal(t, domain.ErrInvalidSearchPeriod, err)
+
+	_, err = SearchEventsQuery{To: "2026-10-01"}.toEventSearch(now)
+	assert.Equal(t, domain.ErrInvalidSearchPeriod, err, "past events are not listed by default")
+}
@@ -0,0 +1,168 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// AllotmentKind tells who the tickets of an allotment are set aside for
+type AllotmentKind string
+
+const (
+	AllotmentKindPress     AllotmentKind = "press"
+	AllotmentKindSponsor   AllotmentKind = "sponsor"
+	AllotmentKindGuestList AllotmentKind = "guest_list"
+)
+
+// IsValidAllotmentKind checks if the allotment kind is valid
+func IsValidAllotmentKind(kind string) bool {
+	switch AllotmentKind(kind) {
+	case AllotmentKindPress, AllotmentKindSponsor, AllotmentKindGuestList:
+		return true
+	default:
+		return false
+	}
+}
+
+const (
+	// MaxComplimentaryTickets bounds the tickets issued to one recipient at once
+	MaxComplimentaryTickets = 10
+)
+
+// TicketAllotment is a number of tickets of a category carved out of public sale, issued by the
+// organizer as complimentary tickets
+type TicketAllotment struct {
+	ID               int64
+	EventID          int64
+	TicketCategoryID int64
+	Name             string
+	Kind             AllotmentKind
+	Quantity         int
+	// Issued is how many tickets of the allotment were issued, they count as sold in the category
+	Issued    int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewTicketAllotment creates an allotment of quantity tickets of a category of the event
+func NewTicketAllotment(eventID, ticketCategoryID int64, name string, kind AllotmentKind, quantity int) (*TicketAllotment, error) {
+	if !IsValidAllotmentKind(string(kind)) {
+		return nil, ErrInvalidAllotmentKind
+	}
+	if err := ValidateTicketQuantity(quantity); err != nil {
+		return nil, err
+	}
+
+	return &TicketAllotment{
+		EventID:          eventID,
+		TicketCategoryID: ticketCategoryID,
+		Name:             name,
+		Kind:             kind,
+		Quantity:         quantity,
+	}, nil
+}
+
+// Remaining is how many tickets of the allotment can still be issued
+func (a *TicketAllotment) Remaining() int {
+	return max(a.Quantity-a.Issued, 0)
+}
+
+// Resize changes the size of the allotment, never below the tickets already issued. It returns how
+// many tickets the category must set aside, negative when they go back on sale.
+func (a *TicketAllotment) Resize(quantity int) (int, error) {
+	if quantity < a.Issued {
+		return 0, ErrAllotmentBelowIssued
+	}
+
+	delta := quantity - a.Quantity
+	a.Quantity = quantity
+	return delta, nil
+}
+
+// ComplimentaryIssue is the issuance of free tickets to a recipient by email, as a zero-amount order
+// confirmed at once. The tickets come from an allotment, or from the public stock of the category
+// when AllotmentID is nil.
+type ComplimentaryIssue struct {
+	EventID          int64
+	OrganizerID      int64
+	TicketCategoryID int64
+	AllotmentID      *int64
+	Email            string
+	Quantity         int
+	Note             string
+
+	// set once issued
+	OrderID            int64
+	OrderNumber        string
+	EventTitle         string
+	TicketCategoryName string
+	Tickets            []*IssuedTicket
+	IssuedAt           time.Time
+}
+
+// IssuedTicket is a ticket taken for an order created by the organizer, with its seat when the
+// category is seated
+type IssuedTicket struct {
+	ID                 int64
+	TicketCategoryID   int64
+	TicketCategoryName string
+	TicketNumber       string
+	SeatSection        string
+	SeatRow            string
+	SeatNumber         string
+}
+
+// NewComplimentaryIssue creates the issuance of quantity free tickets of a category to email
+func NewComplimentaryIssue(eventID, organizerID, ticketCategoryID int64, allotmentID *int64, email string, quantity int, note string) (*ComplimentaryIssue, error) {
+	if err := ValidateTicketQuantity(quantity); err != nil {
+		return nil, err
+	}
+	if quantity > MaxComplimentaryTickets {
+		return nil, ErrTicketLimitExceeded
+	}
+
+	return &ComplimentaryIssue{
+		EventID:          eventID,
+		OrganizerID:      organizerID,
+		TicketCategoryID: ticketCategoryID,
+		AllotmentID:      allotmentID,
+		Email:            strings.ToLower(strings.TrimSpace(email)),
+		Quantity:         quantity,
+		Note:             note,
+	}, nil
+}
+
+// AllotmentRepository defines the interface for allotment persistence. Allotments of events of other
+// organizers are reported as not found.
+type AllotmentRepository interface {
+	// Create sets the tickets of the allotment aside from its category and saves it, atomically. It fails
+	// with ErrSoldOut if fewer tickets are available and ErrAllotmentNameTaken if the category has an
+	// allotment with the same name.
+	Create(ctx context.Context, allotment *TicketAllotment, organizerID int64) error
+
+	// GetByID retrieves an allotment of an event of the organizer
+	GetByID(ctx context.Context, id, eventID, organizerID int64) (*TicketAllotment, error)
+
+	// List retrieves the allotments of an event of the organizer, by category and name
+	List(ctx context.Context, eventID, organizerID int64) ([]*TicketAllotment, error)
+
+	// Resize changes the size of the allotment to quantity, setting more tickets of its category aside or
+	// giving them back to sale. It fails with ErrSoldOut if fewer tickets are available and
+	// ErrAllotmentBelowIssued if more tickets were issued from the allotment.
+	Resize(ctx context.Context, allotment *TicketAllotment, quantity int) error
+
+	// Delete deletes an allotment and gives the tickets not issued back to sale. The tickets issued
+	// from it are kept.
+	Delete(ctx context.Context, allotment *TicketAllotment) error
+}
+
+// ComplimentaryTicketRepository issues complimentary tickets
+type ComplimentaryTicketRepository interface {
+	// Issue takes the tickets of the issue from its allotment, or the public stock of its category,
+	// and creates the confirmed zero-amount order holding them, atomically. It fails with
+	// ErrTicketCategoryNotFound if the category is not one of the event, ErrEventNotFound if the event
+	// belongs to someone else, ErrEventClosed if it is cancelled or over, ErrAllotmentNotFound,
+	// ErrAllotmentExhausted or ErrSoldOut if not enough tickets are left.
+	Issue(ctx context.Context, issue *ComplimentaryIssue) error
+}
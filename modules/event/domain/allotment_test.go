@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTicketAllotment(t *testing.T) {
+	_, err := NewTicketAllotment(1, 2, "Press", AllotmentKind("vip"), 5)
+	assert.ErrorIs(t, err, ErrInvalidAllotmentKind)
+
+	_, err = NewTicketAllotment(1, 2, "Press", AllotmentKindPress, 0)
+	assert.Error(t, err)
+
+	allotment, err := NewTicketAllotment(1, 2, "Press", AllotmentKindPress, 5)
+	require.NoError(t, err)
+	assert.Equal(t, 5, allotment.Remaining())
+}
+
+func TestTicketAllotment_Resize(t *testing.T) {
+	allotment := &TicketAllotment{Quantity: 10, Issued: 4}
+
+	delta, err := allotment.Resize(12)
+	require.NoError(t, err)
+	assert.Equal(t, 2, delta)
+
+	delta, err = allotment.Resize(4)
+	require.NoError(t, err)
+	assert.Equal(t, -8, delta)
+	assert.Equal(t, 0, allotment.Remaining())
+
+	_, err = allotment.Resize(3)
+	assert.ErrorIs(t, err, ErrAllotmentBelowIssued)
+	assert.Equal(t, 4, allotment.Quantity)
+}
+
+func TestNewComplimentaryIssue(t *testing.T) {
+	issue, err := NewComplimentaryIssue(1, 7, 2, nil, "  Guest@Example.com ", 2, "")
+	require.NoError(t, err)
+	assert.Equal(t, "guest@example.com", issue.Email)
+
+	_, err = NewComplimentaryIssue(1, 7, 2, nil, "guest@example.com", MaxComplimentaryTickets+1, "")
+	assert.ErrorIs(t, err, ErrTicketLimitExceeded)
+}
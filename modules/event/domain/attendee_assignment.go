@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// AttendeeMode tells whether the tickets of an event must be assigned to a named attendee
+type AttendeeMode string
+
+const (
+	AttendeeModeOptional AttendeeMode = "optional"
+	AttendeeModeRequired AttendeeMode = "required"
+)
+
+// IsValidAttendeeMode checks if the attendee mode is valid
+func IsValidAttendeeMode(mode string) bool {
+	switch AttendeeMode(mode) {
+	case AttendeeModeOptional, AttendeeModeRequired:
+		return true
+	default:
+		return false
+	}
+}
+
+const (
+	// MaxAttendeeEditCutoff bounds how long before the start of an event attendees stop being editable
+	MaxAttendeeEditCutoff = 30 * 24 * time.Hour
+)
+
+// AttendeeSettings tells how the tickets of an event are assigned to attendees. Buyers can name and
+// rename the attendee of their tickets until EditCutoff before the event starts.
+type AttendeeSettings struct {
+	EventID    int64
+	Mode       AttendeeMode
+	EditCutoff time.Duration
+}
+
+// NewAttendeeSettings creates the attendee settings of the event
+func NewAttendeeSettings(eventID int64, mode AttendeeMode, editCutoff time.Duration) (*AttendeeSettings, error) {
+	if !IsValidAttendeeMode(string(mode)) {
+		return nil, ErrInvalidAttendeeMode
+	}
+	if editCutoff < 0 || editCutoff > MaxAttendeeEditCutoff {
+		return nil, syserr.New(syserr.InvalidArgumentCode, "the edit cutoff must be between 0 and 30 days")
+	}
+
+	return &AttendeeSettings{EventID: eventID, Mode: mode, EditCutoff: editCutoff.Truncate(time.Minute)}, nil
+}
+
+// TicketAttendee is the person a ticket of an order is assigned to, who gets the ticket by email
+type TicketAttendee struct {
+	EventID  int64
+	TicketID int64
+	// UserID is the buyer assigning the ticket, the owner of its order
+	UserID int64
+	Name   string
+	Email  string
+
+	// set once assigned
+	OrderID       int64
+	OrganizerID   int64
+	EventTitle    string
+	Ticket        IssuedTicket
+	PreviousEmail string
+	AssignedAt    time.Time
+}
+
+// NewTicketAttendee creates the assignment of a ticket of the event to the attendee named name
+func NewTicketAttendee(eventID, ticketID, userID int64, name, email string) (*TicketAttendee, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, syserr.New(syserr.InvalidArgumentCode, "name is required")
+	}
+
+	return &TicketAttendee{
+		EventID:  eventID,
+		TicketID: ticketID,
+		UserID:   userID,
+		Name:     name,
+		Email:    strings.ToLower(strings.TrimSpace(email)),
+	}, nil
+}
+
+// Redelivered tells whether the ticket goes to a new email with this assignment
+func (a *TicketAttendee) Redelivered() bool {
+	return a.Email != a.PreviousEmail
+}
+
+// AttendeeSettingsRepository defines the interface for attendee settings persistence
+type AttendeeSettingsRepository interface {
+	// Get retrieves the attendee settings of an event, ErrEventNotFound if it does not exist
+	Get(ctx context.Context, eventID int64) (*AttendeeSettings, error)
+
+	// Save stores the attendee settings of an event of the organizer
+	Save(ctx context.Context, settings *AttendeeSettings, organizerID int64) error
+}
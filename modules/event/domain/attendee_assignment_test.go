@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAttendeeSettings(t *testing.T) {
+	_, err := NewAttendeeSettings(1, AttendeeMode("mandatory"), 0)
+	assert.ErrorIs(t, err, ErrInvalidAttendeeMode)
+
+	_, err = NewAttendeeSettings(1, AttendeeModeRequired, -time.Minute)
+	assert.Error(t, err)
+
+	_, err = NewAttendeeSettings(1, AttendeeModeRequired, MaxAttendeeEditCutoff+time.Minute)
+	assert.Error(t, err)
+
+	settings, err := NewAttendeeSettings(1, AttendeeModeRequired, 90*time.Minute+30*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Minute, settings.EditCutoff)
+}
+
+func TestNewTicketAttendee(t *testing.T) {
+	_, err := NewTicketAttendee(1, 2, 3, "  ", "jane@example.com")
+	assert.Error(t, err)
+
+	attendee, err := NewTicketAttendee(1, 2, 3, " Jane Doe ", " Jane@Example.com ")
+	require.NoError(t, err)
+	assert.Equal(t, "Jane Doe", attendee.Name)
+	assert.Equal(t, "jane@example.com", attendee.Email)
+	assert.True(t, attendee.Redelivered())
+
+	attendee.PreviousEmail = "jane@example.com"
+	assert.False(t, attendee.Redelivered())
+}
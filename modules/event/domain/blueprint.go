@@ -0,0 +1,174 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"tixgo/shared/listing"
+)
+
+const (
+	// copyTitleSuffix marks the title of a duplicated event until the organizer renames it
+	copyTitleSuffix = " (copy)"
+	// maxTitleLength is the length of the title column
+	maxTitleLength = 255
+)
+
+// EventBlueprint is the structure of an event without its sales: the details, ticket categories and
+// seat map an organizer sets up again for every edition. Dates are relative to the start of the event
+// so the blueprint can be scheduled at any date.
+type EventBlueprint struct {
+	VenueID     *int64 `json:"venue_id,omitempty"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	EventType   string `json:"event_type"`
+	Timezone    string `json:"timezone"`
+	// Currency is empty in the blueprints saved before events had a currency, which were in dollars
+	Currency           string `json:"currency,omitempty"`
+	IsRecurring        bool   `json:"is_recurring"`
+	MaxTicketsPerOrder int    `json:"max_tickets_per_order"`
+	ImageURL           string `json:"image_url,omitempty"`
+	TermsAndConditions string `json:"terms_and_conditions,omitempty"`
+	AgeRestriction     *int   `json:"age_restriction,omitempty"`
+	// Duration is how long the event lasts, nil if it has no end date
+	Duration *time.Duration `json:"duration,omitempty"`
+	// Sales is when tickets are sold, relative to the start of the event
+	Sales            RelativeWindow            `json:"sales"`
+	TicketCategories []BlueprintTicketCategory `json:"ticket_categories"`
+}
+
+// RelativeWindow is a period relative to the start of an event, usually before it. Nil bounds are open.
+type RelativeWindow struct {
+	Start *time.Duration `json:"start,omitempty"`
+	End   *time.Duration `json:"end,omitempty"`
+}
+
+// NewRelativeWindow returns the window of start and end relative to anchor
+func NewRelativeWindow(anchor time.Time, start, end *time.Time) RelativeWindow {
+	return RelativeWindow{Start: relative(anchor, start), End: relative(anchor, end)}
+}
+
+// At returns the bounds of the window for an event starting at anchor
+func (w RelativeWindow) At(anchor time.Time) (start, end *time.Time) {
+	return absolute(anchor, w.Start), absolute(anchor, w.End)
+}
+
+// BlueprintTicketCategory is a ticket category of a blueprint, with its seats when it is seated
+type BlueprintTicketCategory struct {
+	Name           string          `json:"name"`
+	Description    string          `json:"description,omitempty"`
+	CategoryType   string          `json:"category_type"`
+	Price          string          `json:"price"`
+	Quantity       int             `json:"quantity"`
+	MaxPerOrder    int             `json:"max_per_order"`
+	IsTransferable bool            `json:"is_transferable"`
+	IsRefundable   bool            `json:"is_refundable"`
+	Sales          RelativeWindow  `json:"sales"`
+	Seats          []BlueprintSeat `json:"seats,omitempty"`
+}
+
+// BlueprintSeat is a seat of the seat map, referenced by section, row and number
+type BlueprintSeat struct {
+	Section string `json:"section"`
+	Row     string `json:"row,omitempty"`
+	Number  string `json:"number,omitempty"`
+}
+
+// SeatCount returns the number of seats of the seat map
+func (b *EventBlueprint) SeatCount() int {
+	count := 0
+	for _, category := range b.TicketCategories {
+		count += len(category.Seats)
+	}
+	return count
+}
+
+// EventDraft is a new draft event scheduled from a blueprint, nothing sold yet
+type EventDraft struct {
+	ID          int64
+	OrganizerID int64
+	Blueprint   EventBlueprint
+	StartDate   time.Time
+	EndDate     *time.Time
+	// TestMode drafts are created with test mode API keys, like test mode events
+	TestMode  bool
+	CreatedAt time.Time
+}
+
+// NewEventDraft schedules blueprint at startDate as a draft of the organizer, titled title or the title
+// of the blueprint when empty. Drafts must start in the future.
+func NewEventDraft(organizerID int64, blueprint EventBlueprint, title string, startDate time.Time) (*EventDraft, error) {
+	if !startDate.After(time.Now()) {
+		return nil, ErrEventStartInPast
+	}
+	if title != "" {
+		blueprint.Title = title
+	}
+
+	return &EventDraft{
+		OrganizerID: organizerID,
+		Blueprint:   blueprint,
+		StartDate:   startDate,
+		EndDate:     absolute(startDate, blueprint.Duration),
+	}, nil
+}
+
+// CopyTitle returns the title of a copy of an event titled title
+func CopyTitle(title string) string {
+	if runes := []rune(title); len(runes)+len(copyTitleSuffix) > maxTitleLength {
+		title = string(runes[:maxTitleLength-len(copyTitleSuffix)])
+	}
+	return title + copyTitleSuffix
+}
+
+// EventTemplate is a blueprint an organizer saved to create the recurring editions of an event from
+type EventTemplate struct {
+	ID          int64
+	OrganizerID int64
+	Name        string
+	// SourceEventID is the event the template was saved from, nil once it is deleted
+	SourceEventID *int64
+	Blueprint     EventBlueprint
+	CreatedAt     time.Time
+}
+
+// EventBlueprintRepository reads the structure of events and creates drafts from it
+type EventBlueprintRepository interface {
+	// GetBlueprint retrieves the blueprint of an event of the organizer with its start date,
+	// ErrEventNotFound if the event does not exist or belongs to someone else
+	GetBlueprint(ctx context.Context, eventID, organizerID int64) (*EventBlueprint, time.Time, error)
+
+	// CreateDraft creates the draft event with its ticket categories and seats in one transaction
+	CreateDraft(ctx context.Context, draft *EventDraft) error
+}
+
+// EventTemplateRepository defines the interface for event template persistence
+type EventTemplateRepository interface {
+	// Create stores a template, ErrEventTemplateNameTaken if the organizer has one with the same name
+	Create(ctx context.Context, template *EventTemplate) error
+
+	// GetByID retrieves a template of the organizer, ErrEventTemplateNotFound if it belongs to someone else
+	GetByID(ctx context.Context, id, organizerID int64) (*EventTemplate, error)
+
+	// List retrieves the templates of the organizer with pagination, by name
+	List(ctx context.Context, organizerID int64, paging *listing.Paging) ([]*EventTemplate, error)
+
+	// Delete deletes a template of the organizer
+	Delete(ctx context.Context, id, organizerID int64) error
+}
+
+func relative(anchor time.Time, t *time.Time) *time.Duration {
+	if t == nil {
+		return nil
+	}
+	offset := t.Sub(anchor)
+	return &offset
+}
+
+func absolute(anchor time.Time, offset *time.Duration) *time.Time {
+	if offset == nil {
+		return nil
+	}
+	t := anchor.Add(*offset)
+	return &t
+}
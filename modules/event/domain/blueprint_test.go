@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEventDraft_ShiftsDatesWithTheStart(t *testing.T) {
+	start := time.Date(2026, 3, 14, 19, 0, 0, 0, time.UTC)
+	end := start.Add(3 * time.Hour)
+	salesStart := start.AddDate(0, -1, 0)
+	salesEnd := start.Add(-time.Hour)
+
+	duration := end.Sub(start)
+	blueprint := EventBlueprint{
+		Title:    "Spring concert",
+		Duration: &duration,
+		Sales:    NewRelativeWindow(start, &salesStart, nil),
+		TicketCategories: []BlueprintTicketCategory{
+			{Name: "Early bird", Sales: NewRelativeWindow(start, nil, &salesEnd)},
+		},
+	}
+
+	next := time.Now().Add(30 * 24 * time.Hour).Truncate(time.Second)
+	draft, err := NewEventDraft(7, blueprint, "", next)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Spring concert", draft.Blueprint.Title)
+	assert.Equal(t, next.Add(3*time.Hour), *draft.EndDate)
+
+	saleStart, saleEnd := draft.Blueprint.Sales.At(draft.StartDate)
+	assert.Equal(t, next.Add(salesStart.Sub(start)), *saleStart)
+	assert.Nil(t, saleEnd)
+
+	categoryStart, categoryEnd := draft.Blueprint.TicketCategories[0].Sales.At(draft.StartDate)
+	assert.Nil(t, categoryStart)
+	assert.Equal(t, next.Add(-time.Hour), *categoryEnd)
+}
+
+func TestNewEventDraft_MustStartInTheFuture(t *testing.T) {
+	_, err := NewEventDraft(7, EventBlueprint{Title: "Past"}, "", time.Now().Add(-time.Minute))
+	assert.ErrorIs(t, err, ErrEventStartInPast)
+
+	draft, err := NewEventDraft(7, EventBlueprint{Title: "Past"}, "Renamed", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", draft.Blueprint.Title)
+	assert.Nil(t, draft.EndDate)
+}
+
+func TestCopyTitle_FitsTheColumn(t *testing.T) {
+	assert.Equal(t, "Gala (copy)", CopyTitle("Gala"))
+
+	long := CopyTitle(strings.Repeat("é", maxTitleLength))
+	assert.Len(t, []rune(long), maxTitleLength)
+	assert.True(t, strings.HasSuffix(long, copyTitleSuffix))
+}
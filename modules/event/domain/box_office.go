@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// BoxOfficePaymentMethod is how a walk-up customer paid at the box office
+type BoxOfficePaymentMethod string
+
+const (
+	BoxOfficePaymentCash        BoxOfficePaymentMethod = "cash"
+	BoxOfficePaymentCardPresent BoxOfficePaymentMethod = "card_present"
+)
+
+// IsValidBoxOfficePaymentMethod checks if the box office payment method is valid
+func IsValidBoxOfficePaymentMethod(method string) bool {
+	switch BoxOfficePaymentMethod(method) {
+	case BoxOfficePaymentCash, BoxOfficePaymentCardPresent:
+		return true
+	default:
+		return false
+	}
+}
+
+const (
+	// MaxBoxOfficeTickets bounds the tickets of one box office sale
+	MaxBoxOfficeTickets = 20
+)
+
+// BoxOfficeItem is a number of tickets of a category in a box office sale
+type BoxOfficeItem struct {
+	TicketCategoryID int64
+	Quantity         int
+}
+
+// BoxOfficeSale is the sale of tickets by the organizer to a walk-up customer, paid on the spot. It is
+// a confirmed order at the price of the categories without going through the payment provider; the
+// customer may leave an email to get the tickets mailed, the organizer prints them otherwise.
+type BoxOfficeSale struct {
+	EventID       int64
+	SellerID      int64
+	Items         []BoxOfficeItem
+	PaymentMethod BoxOfficePaymentMethod
+	// CardReference is the reference of the card terminal transaction, if paid by card
+	CardReference string
+	Email         string
+	CustomerName  string
+
+	// set once sold
+	OrderID     int64
+	OrderNumber string
+	EventTitle  string
+	TotalAmount string
+	Currency    string
+	Tickets     []*IssuedTicket
+	SoldAt      time.Time
+}
+
+// NewBoxOfficeSale creates the sale of the items to a walk-up customer paying with method. Each
+// category is listed once, with no more than MaxBoxOfficeTickets in all.
+func NewBoxOfficeSale(eventID, sellerID int64, items []BoxOfficeItem, method BoxOfficePaymentMethod, cardReference, email, customerName string) (*BoxOfficeSale, error) {
+	if !IsValidBoxOfficePaymentMethod(string(method)) {
+		return nil, ErrInvalidBoxOfficePayment
+	}
+	if len(items) == 0 {
+		return nil, ErrEmptyBoxOfficeSale
+	}
+
+	total := 0
+	categories := make(map[int64]bool, len(items))
+	for _, item := range items {
+		if err := ValidateTicketQuantity(item.Quantity); err != nil {
+			return nil, err
+		}
+		if categories[item.TicketCategoryID] {
+			return nil, ErrDuplicateTicketCategory
+		}
+		categories[item.TicketCategoryID] = true
+		total += item.Quantity
+	}
+	if total > MaxBoxOfficeTickets {
+		return nil, ErrTicketLimitExceeded
+	}
+
+	return &BoxOfficeSale{
+		EventID:       eventID,
+		SellerID:      sellerID,
+		Items:         items,
+		PaymentMethod: method,
+		CardReference: strings.TrimSpace(cardReference),
+		Email:         strings.ToLower(strings.TrimSpace(email)),
+		CustomerName:  strings.TrimSpace(customerName),
+	}, nil
+}
+
+// BoxOfficeRepository records box office sales
+type BoxOfficeRepository interface {
+	// Sell takes the tickets of the sale from the public stock of their categories and creates the
+	// confirmed order holding them with its payment method, atomically. It fails with
+	// ErrTicketCategoryNotFound if a category is not one of the event, ErrEventNotFound if the event
+	// belongs to someone else, ErrEventNotPublished if it is a draft, ErrEventClosed if it is cancelled
+	// or over, and ErrSoldOut if not enough tickets are left.
+	Sell(ctx context.Context, sale *BoxOfficeSale) error
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBoxOfficeSale(t *testing.T) {
+	items := []BoxOfficeItem{{TicketCategoryID: 2, Quantity: 3}, {TicketCategoryID: 3, Quantity: 1}}
+
+	sale, err := NewBoxOfficeSale(1, 7, items, BoxOfficePaymentCash, "", "  Walkup@Example.com ", " Jo ")
+	require.NoError(t, err)
+	assert.Equal(t, "walkup@example.com", sale.Email)
+	assert.Equal(t, "Jo", sale.CustomerName)
+
+	_, err = NewBoxOfficeSale(1, 7, items, BoxOfficePaymentMethod("voucher"), "", "", "")
+	assert.ErrorIs(t, err, ErrInvalidBoxOfficePayment)
+
+	_, err = NewBoxOfficeSale(1, 7, nil, BoxOfficePaymentCash, "", "", "")
+	assert.ErrorIs(t, err, ErrEmptyBoxOfficeSale)
+
+	_, err = NewBoxOfficeSale(1, 7, []BoxOfficeItem{{TicketCategoryID: 2, Quantity: 0}}, BoxOfficePaymentCash, "", "", "")
+	assert.Error(t, err)
+
+	_, err = NewBoxOfficeSale(1, 7, []BoxOfficeItem{{TicketCategoryID: 2, Quantity: 1}, {TicketCategoryID: 2, Quantity: 1}}, BoxOfficePaymentCardPresent, "T-42", "", "")
+	assert.ErrorIs(t, err, ErrDuplicateTicketCategory)
+
+	_, err = NewBoxOfficeSale(1, 7, []BoxOfficeItem{{TicketCategoryID: 2, Quantity: MaxBoxOfficeTickets}, {TicketCategoryID: 3, Quantity: 1}}, BoxOfficePaymentCash, "", "", "")
+	assert.ErrorIs(t, err, ErrTicketLimitExceeded)
+}
@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// CancellationStatus represents the progress of the refunds of a cancelled event
+type CancellationStatus string
+
+const (
+	CancellationStatusRunning   CancellationStatus = "running"
+	CancellationStatusCompleted CancellationStatus = "completed"
+	// CancellationStatusCompletedWithFailures cancellations have orders the organizer must settle by hand
+	CancellationStatusCompletedWithFailures CancellationStatus = "completed_with_failures"
+)
+
+// CancellationStage is the step of the processing of an order that failed
+type CancellationStage string
+
+const (
+	CancellationStageRefund       CancellationStage = "refund"
+	CancellationStageNotification CancellationStage = "notification"
+)
+
+// EventCancellation is the cancellation of an event and the progress of the refund of its paid orders
+type EventCancellation struct {
+	ID          int64
+	EventID     int64
+	CancelledBy int64
+	Reason      string
+	Status      CancellationStatus
+	Progress    CancellationProgress
+	CreatedAt   time.Time
+	CompletedAt *time.Time
+}
+
+// CancellationProgress counts the paid orders of a cancelled event by outcome
+type CancellationProgress struct {
+	TotalOrders         int
+	ProcessedOrders     int
+	RefundsRequested    int
+	RefundsFailed       int
+	HoldersNotified     int
+	NotificationsFailed int
+}
+
+// NewEventCancellation creates the cancellation of an event by its organizer
+func NewEventCancellation(eventID, organizerID int64, reason string) *EventCancellation {
+	return &EventCancellation{
+		EventID:     eventID,
+		CancelledBy: organizerID,
+		Reason:      reason,
+		Status:      CancellationStatusRunning,
+	}
+}
+
+// CancellationOrder is a paid order of a cancelled event to refund and whose holder to notify
+type CancellationOrder struct {
+	ID             int64
+	CancellationID int64
+	EventID        int64
+	EventTitle     string
+	OrganizerID    int64
+	Reason         string
+	OrderID        int64
+	OrderNumber    string
+	Email          string
+	// RefundID is the refund recorded for the completed payment of the order, nil if it has none
+	RefundID     *int64
+	PaymentID    *int64
+	RefundAmount string
+	Currency     string
+	// TestMode orders were paid with fake payments, their refund is never requested from the provider
+	TestMode bool
+}
+
+// CancellationOrderResult is the outcome of the processing of a cancellation order, an empty error
+// meaning the step succeeded
+type CancellationOrderResult struct {
+	ID                int64
+	RefundError       string
+	NotificationError string
+	// TestMode settles the refund in place, as no payment provider is involved
+	TestMode bool
+}
+
+// CancellationFailure is an order whose refund or notification failed
+type CancellationFailure struct {
+	OrderID     int64
+	OrderNumber string
+	Stage       CancellationStage
+	Error       string
+	ProcessedAt time.Time
+}
+
+// EventRefundRequested is published for every refund the payment integration must issue. It may be
+// published more than once for a refund, consumers deduplicate by RefundID.
+type EventRefundRequested struct {
+	RefundID   int64
+	PaymentID  int64
+	OrderID    int64
+	Amount     string
+	Currency   string
+	Reason     string
+	OccurredAt time.Time
+}
+
+func NewEventRefundRequested(order *CancellationOrder) *EventRefundRequested {
+	return &EventRefundRequested{
+		RefundID:   *order.RefundID,
+		PaymentID:  *order.PaymentID,
+		OrderID:    order.OrderID,
+		Amount:     order.RefundAmount,
+		Currency:   order.Currency,
+		Reason:     order.Reason,
+		OccurredAt: time.Now(),
+	}
+}
+
+// EventCancellationRepository defines the interface for event cancellation persistence
+type EventCancellationRepository interface {
+	// Cancel stops the sales of a published or postponed event of the organizer and records its
+	// cancellation, atomically: the event is cancelled, its unsold tickets and pending orders are
+	// withdrawn and every confirmed order is queued for refund. It fails with ErrEventNotFound if the
+	// organizer does not own the event and ErrEventNotCancellable if it cannot be cancelled.
+	Cancel(ctx context.Context, cancellation *EventCancellation) error
+
+	// GetByEventID retrieves the cancellation of an event with its progress
+	GetByEventID(ctx context.Context, eventID int64) (*EventCancellation, error)
+
+	// ListFailures retrieves up to limit failed orders of a cancellation, the latest first
+	ListFailures(ctx context.Context, cancellationID int64, limit int) ([]*CancellationFailure, error)
+
+	// ClaimOrders claims up to limit queued orders of running cancellations and records a pending refund
+	// for the completed payment of each. Orders claimed longer than staleAfter ago are claimed again,
+	// keeping their refund.
+	ClaimOrders(ctx context.Context, limit int, staleAfter time.Duration) ([]*CancellationOrder, error)
+
+	// CompleteOrders records the outcome of claimed orders and completes the cancellations left
+	// without queued orders
+	CompleteOrders(ctx context.Context, results []*CancellationOrderResult) error
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEventCancellation(t *testing.T) {
+	cancellation := NewEventCancellation(12, 3, "storm")
+	assert.Equal(t, int64(12), cancellation.EventID)
+	assert.Equal(t, int64(3), cancellation.CancelledBy)
+	assert.Equal(t, CancellationStatusRunning, cancellation.Status)
+	assert.Zero(t, cancellation.Progress)
+}
+
+func TestNewEventRefundRequested(t *testing.T) {
+	refundID, paymentID := int64(40), int64(41)
+	order := &CancellationOrder{
+		ID:           7,
+		OrderID:      42,
+		Reason:       "storm",
+		RefundID:     &refundID,
+		PaymentID:    &paymentID,
+		RefundAmount: "120.00",
+		Currency:     "VND",
+	}
+
+	before := time.Now()
+	event := NewEventRefundRequested(order)
+	assert.Equal(t, refundID, event.RefundID, "consumers deduplicate by the refund")
+	assert.Equal(t, paymentID, event.PaymentID)
+	assert.Equal(t, int64(42), event.OrderID)
+	assert.Equal(t, "120.00", event.Amount, "the completed payment is refunded in full")
+	assert.Equal(t, "VND", event.Currency, "refunded in the currency it was paid in")
+	assert.Equal(t, "storm", event.Reason)
+	assert.False(t, event.OccurredAt.Before(before))
+}
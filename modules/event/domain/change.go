@@ -0,0 +1,199 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"tixgo/shared/listing"
+)
+
+// ChangeAction is what an organizer changed in the configuration of an event
+type ChangeAction string
+
+const (
+	ChangeActionDetailsUpdated  ChangeAction = "details_updated"
+	ChangeActionPublished       ChangeAction = "published"
+	ChangeActionCancelled       ChangeAction = "cancelled"
+	ChangeActionSEOUpdated      ChangeAction = "seo_updated"
+	ChangeActionCapacityChanged ChangeAction = "capacity_changed"
+	ChangeActionSalesPaused     ChangeAction = "sales_paused"
+	ChangeActionSalesResumed    ChangeAction = "sales_resumed"
+	ChangeActionPauseScheduled  ChangeAction = "pause_scheduled"
+)
+
+// IsValidChangeAction checks if a change action is known
+func IsValidChangeAction(action string) bool {
+	switch ChangeAction(action) {
+	case ChangeActionDetailsUpdated, ChangeActionPublished, ChangeActionCancelled, ChangeActionSEOUpdated,
+		ChangeActionCapacityChanged, ChangeActionSalesPaused, ChangeActionSalesResumed, ChangeActionPauseScheduled:
+		return true
+	}
+	return false
+}
+
+// FieldChange is a field of an event changing From a value To another. Delta is the difference of
+// numeric fields, like capacities and prices, signed: "+50", "-5.00". Long texts are recorded without
+// their values.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from,omitempty"`
+	To    string `json:"to,omitempty"`
+	Delta string `json:"delta,omitempty"`
+}
+
+// NewFieldChange creates the change of a field, with its delta when both values are numbers
+func NewFieldChange(field, from, to string) FieldChange {
+	change := FieldChange{Field: field, From: from, To: to}
+
+	before, errFrom := strconv.ParseFloat(from, 64)
+	after, errTo := strconv.ParseFloat(to, 64)
+	if errFrom == nil && errTo == nil {
+		change.Delta = fmt.Sprintf("%+.*f", max(decimals(from), decimals(to)), after-before)
+	}
+	return change
+}
+
+// decimals counts the digits of a number after its decimal point
+func decimals(number string) int {
+	if _, fraction, ok := strings.Cut(number, "."); ok {
+		return len(fraction)
+	}
+	return 0
+}
+
+// DiffEventDetails returns the fields of the details of an event that differ, in a stable order
+func DiffEventDetails(before, after EventDetails) []FieldChange {
+	var changes []FieldChange
+	diff := func(field, from, to string) {
+		if from != to {
+			changes = append(changes, NewFieldChange(field, from, to))
+		}
+	}
+
+	diff("title", before.Title, after.Title)
+	if before.Description != after.Description {
+		changes = append(changes, FieldChange{Field: "description"})
+	}
+	diff("event_type", string(before.EventType), string(after.EventType))
+	diff("start_date", formatChangeTime(&before.StartDate), formatChangeTime(&after.StartDate))
+	diff("end_date", formatChangeTime(before.EndDate), formatChangeTime(after.EndDate))
+	diff("timezone", before.Timezone, after.Timezone)
+	diff("venue_id", formatChangeInt64(before.VenueID), formatChangeInt64(after.VenueID))
+	diff("capacity", formatChangeInt(before.Capacity), formatChangeInt(after.Capacity))
+	diff("currency", before.Currency, after.Currency)
+	return changes
+}
+
+// DiffEventSEO returns the fields of the page of an event that differ
+func DiffEventSEO(before, after EventSEO) []FieldChange {
+	var changes []FieldChange
+	for _, field := range []struct{ name, from, to string }{
+		{"slug", before.Slug, after.Slug},
+		{"meta_title", before.MetaTitle, after.MetaTitle},
+		{"meta_description", before.MetaDescription, after.MetaDescription},
+	} {
+		if field.from != field.to {
+			changes = append(changes, FieldChange{Field: field.name, From: field.from, To: field.to})
+		}
+	}
+	return changes
+}
+
+func formatChangeTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatChangeInt64(n *int64) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.FormatInt(*n, 10)
+}
+
+func formatChangeInt(n *int) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.Itoa(*n)
+}
+
+// EventConfigurationChanged is published whenever an organizer changes the configuration of an event,
+// for its change feed. TicketCategoryID is 0 when the whole event changed. It may be delivered more
+// than once, the feed records it once by ChangeID.
+type EventConfigurationChanged struct {
+	ChangeID         string
+	EventID          int64
+	TicketCategoryID int64
+	Action           ChangeAction
+	Fields           []FieldChange
+	// ActorID is the user who made the change and APIKeyID the API key they made it with, if any
+	ActorID    int64
+	APIKeyID   int64
+	OccurredAt time.Time
+}
+
+// NewEventConfigurationChanged creates the change of the configuration of the target that occurred now
+func NewEventConfigurationChanged(target SalesTarget, action ChangeAction, fields []FieldChange, actorID, apiKeyID int64) *EventConfigurationChanged {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+
+	return &EventConfigurationChanged{
+		ChangeID:         hex.EncodeToString(id),
+		EventID:          target.EventID,
+		TicketCategoryID: target.TicketCategoryID,
+		Action:           action,
+		Fields:           fields,
+		ActorID:          actorID,
+		APIKeyID:         apiKeyID,
+		OccurredAt:       time.Now(),
+	}
+}
+
+// ChangeActor is the user who made a change
+type ChangeActor struct {
+	ID    int64
+	Name  string
+	Email string
+}
+
+// EventChange is a change of the change feed of an event
+type EventChange struct {
+	ID               int64
+	EventID          int64
+	TicketCategoryID int64
+	// TicketCategoryName is the name of the category changed, empty when the whole event changed
+	TicketCategoryName string
+	Action             ChangeAction
+	Fields             []FieldChange
+	Actor              ChangeActor
+	APIKeyID           int64
+	OccurredAt         time.Time
+}
+
+// ChangeFilters are the filters of the change feed of an event of an organizer
+type ChangeFilters struct {
+	EventID     int64
+	OrganizerID int64
+	// Action keeps the changes of this action, all of them when empty
+	Action ChangeAction
+	// TicketCategoryID keeps the changes of this category, all of them when zero
+	TicketCategoryID int64
+}
+
+// EventChangeRepository defines the interface for the change feed persistence
+type EventChangeRepository interface {
+	// Record stores a change once, redeliveries of the same ChangeID are ignored
+	Record(ctx context.Context, change *EventConfigurationChanged) error
+
+	// List retrieves a page of the change feed of an event of the organizer, newest first. Events of
+	// other organizers are reported as not found.
+	List(ctx context.Context, filters ChangeFilters, paging *listing.Paging) ([]*EventChange, error)
+}
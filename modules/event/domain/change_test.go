@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFieldChange(t *testing.T) {
+	assert.Equal(t, FieldChange{Field: "capacity", From: "100", To: "150", Delta: "+50"}, NewFieldChange("capacity", "100", "150"))
+	assert.Equal(t, "-5.00", NewFieldChange("price", "25.00", "20").Delta, "the delta keeps the decimals of the prices")
+	assert.Equal(t, "+0", NewFieldChange("capacity", "10", "10").Delta)
+	assert.Empty(t, NewFieldChange("title", "Jazz", "Jazz Night").Delta, "texts have no delta")
+	assert.Empty(t, NewFieldChange("pause_at", "", "2026-11-01T10:00:00Z").Delta)
+}
+
+func TestDiffEventDetails(t *testing.T) {
+	start := time.Date(2026, 11, 1, 19, 0, 0, 0, time.UTC)
+	capacity, venueID := 200, int64(4)
+	before := EventDetails{Title: "Jazz Night", Description: "Live", EventType: EventTypeConcert, StartDate: start, Timezone: "UTC"}
+
+	assert.Empty(t, DiffEventDetails(before, before))
+
+	after := before
+	after.Description = "Live, outdoors"
+	after.StartDate = start.Add(time.Hour)
+	after.VenueID = &venueID
+	after.Capacity = &capacity
+
+	assert.Equal(t, []FieldChange{
+		{Field: "description"},
+		{Field: "start_date", From: "2026-11-01T19:00:00Z", To: "2026-11-01T20:00:00Z"},
+		{Field: "venue_id", To: "4"},
+		{Field: "capacity", To: "200"},
+	}, DiffEventDetails(before, after), "descriptions are recorded without their text")
+}
+
+func TestDiffEventSEO(t *testing.T) {
+	before := EventSEO{Slug: "jazz-night", MetaTitle: "Jazz Night"}
+	after := before
+	after.Slug = "jazz-night-2026"
+
+	assert.Equal(t, []FieldChange{{Field: "slug", From: "jazz-night", To: "jazz-night-2026"}}, DiffEventSEO(before, after))
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"tixgo/shared/errstack"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// Event domain errors
+var (
+	ErrEventNotFound           = errstack.Sentinel(syserr.NotFoundCode, "event not found")
+	ErrTicketCategoryNotFound  = errstack.Sentinel(syserr.NotFoundCode, "ticket category not found")
+	ErrQueueDisabled           = errstack.Sentinel(syserr.ConflictCode, "the event has no on-sale queue")
+	ErrQueueTicketNotFound     = errstack.Sentinel(syserr.NotFoundCode, "queue ticket not found or expired")
+	ErrNotAdmitted             = errstack.Sentinel(syserr.ForbiddenCode, "not admitted to checkout yet")
+	ErrTicketLimitExceeded     = errstack.Sentinel(syserr.InvalidArgumentCode, "too many tickets for one order")
+	ErrSoldOut                 = errstack.Sentinel(syserr.ConflictCode, "not enough tickets left")
+	ErrReservationNotHeld      = errstack.Sentinel(syserr.ConflictCode, "fewer tickets are reserved than requested")
+	ErrEventNotCancellable     = errstack.Sentinel(syserr.ConflictCode, "only published or postponed events can be cancelled")
+	ErrCancellationNotFound    = errstack.Sentinel(syserr.NotFoundCode, "the event is not cancelled")
+	ErrEventStartInPast        = errstack.Sentinel(syserr.InvalidArgumentCode, "the event must start in the future")
+	ErrEventTemplateNotFound   = errstack.Sentinel(syserr.NotFoundCode, "event template not found")
+	ErrEventTemplateNameTaken  = errstack.Sentinel(syserr.ConflictCode, "an event template with this name already exists")
+	ErrInvalidAllotmentKind    = errstack.Sentinel(syserr.InvalidArgumentCode, "allotment kind must be press, sponsor or guest_list")
+	ErrAllotmentNotFound       = errstack.Sentinel(syserr.NotFoundCode, "allotment not found")
+	ErrAllotmentNameTaken      = errstack.Sentinel(syserr.ConflictCode, "the ticket category has an allotment with this name already")
+	ErrAllotmentExhausted      = errstack.Sentinel(syserr.ConflictCode, "not enough tickets left in the allotment")
+	ErrAllotmentBelowIssued    = errstack.Sentinel(syserr.ConflictCode, "an allotment cannot be smaller than the tickets issued from it")
+	ErrEventClosed             = errstack.Sentinel(syserr.ConflictCode, "the event is cancelled or over")
+	ErrEventNotPublished       = errstack.Sentinel(syserr.ConflictCode, "the event is not published")
+	ErrInvalidBoxOfficePayment = errstack.Sentinel(syserr.InvalidArgumentCode, "payment method must be cash or card_present")
+	ErrEmptyBoxOfficeSale      = errstack.Sentinel(syserr.InvalidArgumentCode, "a sale needs at least one ticket")
+	ErrDuplicateTicketCategory = errstack.Sentinel(syserr.InvalidArgumentCode, "each ticket category can be listed once")
+	ErrInvalidQuestionKind     = errstack.Sentinel(syserr.InvalidArgumentCode, "question kind must be text, select or checkbox")
+	ErrInvalidQuestionOptions  = errstack.Sentinel(syserr.InvalidArgumentCode, "select questions need 2 to 20 different options, other questions none")
+	ErrQuestionNotFound        = errstack.Sentinel(syserr.NotFoundCode, "question not found")
+	ErrQuestionLabelTaken      = errstack.Sentinel(syserr.ConflictCode, "the event has a question with this label already")
+	ErrTooManyQuestions        = errstack.Sentinel(syserr.ConflictCode, "the event has too many questions")
+	ErrUnknownQuestion         = errstack.Sentinel(syserr.InvalidArgumentCode, "an answer is for a question the event does not ask")
+	ErrInvalidAnswer           = errstack.Sentinel(syserr.InvalidArgumentCode, "an answer is not valid for its question")
+	ErrAnswerRequired          = errstack.Sentinel(syserr.InvalidArgumentCode, "a required question is not answered")
+	ErrTicketNotFound          = errstack.Sentinel(syserr.NotFoundCode, "ticket not found")
+	ErrAnswersClosed           = errstack.Sentinel(syserr.ConflictCode, "answers cannot be changed once the event started")
+	ErrInvalidAttendeeMode     = errstack.Sentinel(syserr.InvalidArgumentCode, "attendee mode must be optional or required")
+	ErrAttendeeEditClosed      = errstack.Sentinel(syserr.ConflictCode, "the attendees of this event can no longer be changed")
+	ErrAttendeeRequired        = errstack.Sentinel(syserr.ConflictCode, "the tickets of this event must be assigned to an attendee")
+	ErrSalesPaused             = errstack.Sentinel(syserr.ConflictCode, "sales of these tickets are paused")
+	ErrInvalidPauseTime        = errstack.Sentinel(syserr.InvalidArgumentCode, "a sales pause must be scheduled in the future")
+	ErrCapacityBelowSold       = errstack.Sentinel(syserr.ConflictCode, "capacity cannot go below the tickets sold, reserved or allotted")
+	ErrCapacityExceedsSeats    = errstack.Sentinel(syserr.ConflictCode, "the capacity of a seated category cannot exceed its seats")
+	ErrInvalidEventType        = errstack.Sentinel(syserr.InvalidArgumentCode, "event type must be concert, sports, theater, conference, festival or other")
+	ErrInvalidTimezone         = errstack.Sentinel(syserr.InvalidArgumentCode, "timezone must be an IANA time zone such as Asia/Ho_Chi_Minh")
+	ErrInvalidCurrency         = errstack.Sentinel(syserr.InvalidArgumentCode, "currency must be an ISO 4217 code such as USD or VND, with at most 2 decimals")
+	ErrCurrencyLocked          = errstack.Sentinel(syserr.ConflictCode, "the currency of an event cannot change once it is published")
+	ErrEventEndBeforeStart     = errstack.Sentinel(syserr.InvalidArgumentCode, "the event must end after it starts")
+	ErrEventNotDraft           = errstack.Sentinel(syserr.ConflictCode, "only draft events can be published")
+	ErrVenueNotFound           = errstack.Sentinel(syserr.NotFoundCode, "venue not found")
+	ErrVenueSeatsBound         = errstack.Sentinel(syserr.ConflictCode, "the venue cannot change while seats of its seat map are bound to ticket types")
+	ErrInvalidSlug             = errstack.Sentinel(syserr.InvalidArgumentCode, "slugs are 3 to 200 lowercase letters, digits and single dashes, and end with the event id if they end with a number")
+	ErrSlugTaken               = errstack.Sentinel(syserr.ConflictCode, "the slug is or was the slug of another event")
+	ErrMetaTitleTooLong        = errstack.Sentinel(syserr.InvalidArgumentCode, "the meta title must not exceed 70 characters")
+	ErrMetaDescriptionTooLong  = errstack.Sentinel(syserr.InvalidArgumentCode, "the meta description must not exceed 160 characters")
+	ErrInvalidSearchPeriod     = errstack.Sentinel(syserr.InvalidArgumentCode, "to must be after from, a year at most later")
+	ErrInvalidPriceRange       = errstack.Sentinel(syserr.InvalidArgumentCode, "min_price must not exceed max_price")
+)
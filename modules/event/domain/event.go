@@ -0,0 +1,207 @@
+package domain
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"tixgo/shared/listing"
+	"tixgo/shared/money"
+
+	"github.com/duongptryu/gox/syserr"
+	"golang.org/x/text/unicode/norm"
+)
+
+// EventType is the kind of an event
+type EventType string
+
+const (
+	EventTypeConcert    EventType = "concert"
+	EventTypeSports     EventType = "sports"
+	EventTypeTheater    EventType = "theater"
+	EventTypeConference EventType = "conference"
+	EventTypeFestival   EventType = "festival"
+	EventTypeOther      EventType = "other"
+)
+
+// IsValidEventType checks if the event type is valid
+func IsValidEventType(eventType string) bool {
+	switch EventType(eventType) {
+	case EventTypeConcert, EventTypeSports, EventTypeTheater, EventTypeConference, EventTypeFestival, EventTypeOther:
+		return true
+	default:
+		return false
+	}
+}
+
+// maxSlugTitleLength bounds the part of a slug taken from the title, leaving room for the event id
+const maxSlugTitleLength = 200
+
+// EventDetails are the details of an event its organizer sets when creating and updating it
+type EventDetails struct {
+	VenueID     *int64
+	Title       string
+	Description string
+	EventType   EventType
+	StartDate   time.Time
+	EndDate     *time.Time
+	Timezone    string
+	// Capacity is the most attendees the event admits. Events created before capacities were set have
+	// none until their organizer updates them.
+	Capacity *int
+	// Currency is the ISO 4217 code of the currency the tickets are priced and sold in
+	Currency string
+}
+
+// Event is an event an organizer sells tickets for. It is created as a draft, seen by its organizer
+// only, and gets its public page once published.
+type Event struct {
+	ID          int64
+	OrganizerID int64
+	EventDetails
+	Status EventStatus
+	// Slug addresses the public page, set when the event is first published and kept afterwards so
+	// shared links keep working
+	Slug string
+	// TestMode events are created with test mode API keys, their orders are paid with fake payments
+	// and left out of the sales reports. It is fixed at creation.
+	TestMode  bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewEvent creates a draft event of the organizer
+func NewEvent(organizerID int64, details EventDetails, now time.Time) (*Event, error) {
+	event := &Event{OrganizerID: organizerID, Status: EventStatusDraft}
+	if err := event.Update(details, now); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// Update changes the details of the event. Cancelled and completed events cannot change, and the
+// event must still start in the future. The currency is kept when none is given, it defaults to dollars
+// for new events; it can only change while the event is a draft, as prices are not converted.
+func (e *Event) Update(details EventDetails, now time.Time) error {
+	if e.Status == EventStatusCancelled || e.Status == EventStatusCompleted {
+		return ErrEventClosed
+	}
+
+	details.Title = strings.TrimSpace(details.Title)
+	if details.Title == "" {
+		return syserr.New(syserr.InvalidArgumentCode, "title is required")
+	}
+	if !IsValidEventType(string(details.EventType)) {
+		return ErrInvalidEventType
+	}
+	if _, err := time.LoadLocation(details.Timezone); err != nil || details.Timezone == "" {
+		return ErrInvalidTimezone
+	}
+	if !details.StartDate.After(now) {
+		return ErrEventStartInPast
+	}
+	if details.EndDate != nil && !details.EndDate.After(details.StartDate) {
+		return ErrEventEndBeforeStart
+	}
+	if details.Capacity == nil || *details.Capacity < 1 {
+		return syserr.New(syserr.InvalidArgumentCode, "capacity must be at least 1")
+	}
+
+	switch {
+	case details.Currency == "" && e.Currency != "":
+		details.Currency = e.Currency
+	case details.Currency == "":
+		details.Currency = money.DefaultCurrency
+	default:
+		currency, err := money.Normalize(details.Currency)
+		if err != nil {
+			return ErrInvalidCurrency
+		}
+		details.Currency = currency
+	}
+	if e.Currency != "" && details.Currency != e.Currency && e.Status != EventStatusDraft {
+		return ErrCurrencyLocked
+	}
+
+	e.EventDetails = details
+	return nil
+}
+
+// Publish publishes a draft, giving it its public page. It must still start in the future.
+func (e *Event) Publish(now time.Time) error {
+	if e.Status != EventStatusDraft {
+		return ErrEventNotDraft
+	}
+	if !e.StartDate.After(now) {
+		return ErrEventStartInPast
+	}
+
+	e.Status = EventStatusPublished
+	if e.Slug == "" {
+		e.Slug = EventSlug(e.Title, e.ID)
+	}
+	return nil
+}
+
+// EventSlug returns the slug of the public page of the event: its title in lowercase ASCII words
+// joined by dashes, then its id, which keeps the slugs of events with the same title unique
+func EventSlug(title string, id int64) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range norm.NFD.String(strings.ToLower(title)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r == 'đ':
+			r = 'd'
+		case r > unicode.MaxASCII || !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			dash = b.Len() > 0
+			continue
+		}
+		if b.Len() >= maxSlugTitleLength {
+			break
+		}
+		if dash {
+			b.WriteByte('-')
+			dash = false
+		}
+		b.WriteRune(r)
+	}
+
+	if b.Len() > 0 {
+		b.WriteByte('-')
+	}
+	b.WriteString(strconv.FormatInt(id, 10))
+	return b.String()
+}
+
+// EventFilters narrows down the events of an organizer
+type EventFilters struct {
+	OrganizerID int64
+	// Status keeps the events in this status, all of them when empty
+	Status EventStatus
+}
+
+// EventRepository defines the interface for event persistence. Events of other organizers are reported
+// as not found.
+type EventRepository interface {
+	// Create stores a new draft event, ErrVenueNotFound if its venue does not exist or is another
+	// organizer's
+	Create(ctx context.Context, event *Event) error
+
+	// GetByID retrieves an event of the organizer
+	GetByID(ctx context.Context, id, organizerID int64) (*Event, error)
+
+	// List retrieves the events of the organizer with pagination, soonest first
+	List(ctx context.Context, filters EventFilters, paging *listing.Paging) ([]*Event, error)
+
+	// Update saves the details of an event, ErrEventClosed if it was cancelled or completed meanwhile and
+	// ErrVenueNotFound if its venue does not exist or is another organizer's. ErrVenueSeatsBound if its
+	// venue changes while seats of the seat map of the former one are bound to its ticket types.
+	Update(ctx context.Context, event *Event) error
+
+	// Publish saves the publication of a draft with its slug, ErrEventNotDraft if it is no longer a draft
+	Publish(ctx context.Context, event *Event) error
+}
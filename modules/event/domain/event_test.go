@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func eventDetails(start time.Time) EventDetails {
+	capacity := 500
+	return EventDetails{
+		Title:     "  Spring concert ",
+		EventType: EventTypeConcert,
+		StartDate: start,
+		Timezone:  "Asia/Ho_Chi_Minh",
+		Capacity:  &capacity,
+	}
+}
+
+func TestNewEvent(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	start := now.AddDate(0, 1, 0)
+
+	event, err := NewEvent(7, eventDetails(start), now)
+	require.NoError(t, err)
+	assert.Equal(t, EventStatusDraft, event.Status)
+	assert.Equal(t, "Spring concert", event.Title)
+	assert.Empty(t, event.Slug)
+
+	tests := []struct {
+		name   string
+		modify func(d *EventDetails)
+		want   error
+	}{
+		{name: "unknown type", modify: func(d *EventDetails) { d.EventType = "opera" }, want: ErrInvalidEventType},
+		{name: "unknown timezone", modify: func(d *EventDetails) { d.Timezone = "Mars/Olympus" }, want: ErrInvalidTimezone},
+		{name: "no timezone", modify: func(d *EventDetails) { d.Timezone = "" }, want: ErrInvalidTimezone},
+		{name: "start in the past", modify: func(d *EventDetails) { d.StartDate = now.Add(-time.Minute) }, want: ErrEventStartInPast},
+		{name: "end before start", modify: func(d *EventDetails) { d.EndDate = &now }, want: ErrEventEndBeforeStart},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			details := eventDetails(start)
+			tt.modify(&details)
+			_, err := NewEvent(7, details, now)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+
+	details := eventDetails(start)
+	details.Capacity = nil
+	_, err = NewEvent(7, details, now)
+	assert.Error(t, err)
+}
+
+func TestEvent_Publish(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	event, err := NewEvent(7, eventDetails(now.AddDate(0, 1, 0)), now)
+	require.NoError(t, err)
+	event.ID = 42
+
+	require.NoError(t, event.Publish(now))
+	assert.Equal(t, EventStatusPublished, event.Status)
+	assert.Equal(t, "spring-concert-42", event.Slug)
+	assert.ErrorIs(t, event.Publish(now), ErrEventNotDraft)
+
+	// renaming keeps the slug so shared links keep working
+	details := eventDetails(event.StartDate)
+	details.Title = "Spring concert, second night"
+	require.NoError(t, event.Update(details, now))
+	assert.Equal(t, "spring-concert-42", event.Slug)
+
+	event.Status = EventStatusCancelled
+	assert.ErrorIs(t, event.Update(details, now), ErrEventClosed)
+}
+
+func TestEvent_PublishMustStartInTheFuture(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	event, err := NewEvent(7, eventDetails(now.Add(time.Hour)), now)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, event.Publish(now.Add(2*time.Hour)), ErrEventStartInPast)
+	assert.Equal(t, EventStatusDraft, event.Status)
+}
+
+func TestEvent_Currency(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	details := eventDetails(now.AddDate(0, 1, 0))
+
+	event, err := NewEvent(7, details, now)
+	require.NoError(t, err)
+	assert.Equal(t, "USD", event.Currency, "defaults to dollars")
+
+	details.Currency = "vnd"
+	require.NoError(t, event.Update(details, now))
+	assert.Equal(t, "VND", event.Currency)
+
+	// leaving it out keeps it
+	details.Currency = ""
+	require.NoError(t, event.Update(details, now))
+	assert.Equal(t, "VND", event.Currency)
+
+	details.Currency = "XYZ"
+	assert.ErrorIs(t, event.Update(details, now), ErrInvalidCurrency)
+
+	// published prices are not converted
+	require.NoError(t, event.Publish(now))
+	details.Currency = "EUR"
+	assert.ErrorIs(t, event.Update(details, now), ErrCurrencyLocked)
+	details.Currency = "VND"
+	assert.NoError(t, event.Update(details, now))
+}
+
+func TestEventSlug(t *testing.T) {
+	assert.Equal(t, "dem-nhac-mua-he-2026-7", EventSlug("Đêm nhạc mùa hè 2026!", 7))
+	assert.Equal(t, "rock-roll-8", EventSlug("  Rock & Roll  ", 8))
+	assert.Equal(t, "9", EventSlug("東京", 9))
+
+	long := EventSlug(strings.Repeat("a", 300), 10)
+	assert.Equal(t, strings.Repeat("a", maxSlugTitleLength)+"-10", long)
+}
@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// DefaultForecastWindow is how many recent days of sales the velocity forecast averages: long enough to
+// smooth out weekdays, short enough to follow a campaign
+const DefaultForecastWindow = 14
+
+// DailySales are the tickets of an event confirmed on a day, in UTC, and what they were sold for
+type DailySales struct {
+	Day     time.Time
+	Tickets int
+	// Revenue is in cents of the currency of the orders
+	Revenue int64
+}
+
+// SalesHistory is what an event sold so far, the input of its forecast
+type SalesHistory struct {
+	EventID int64
+	// Capacity is the tickets of the ticket categories, Remaining the ones left to sell
+	Capacity  int
+	Sold      int
+	Remaining int
+	// SalesEnd is when the sales stop, the start of the event
+	SalesEnd time.Time
+	// Daily are the days with sales, oldest first
+	Daily []DailySales
+}
+
+// Revenue returns what the tickets were sold for so far, in cents
+func (h *SalesHistory) Revenue() int64 {
+	var revenue int64
+	for _, day := range h.Daily {
+		revenue += day.Revenue
+	}
+	return revenue
+}
+
+// SalesForecast projects the sales of an event until its sales end
+type SalesForecast struct {
+	// Model names the forecaster, so clients can tell forecasts of different models apart
+	Model string
+	// Velocity is the tickets expected to sell per day
+	Velocity float64
+	// SellOutAt is when the remaining tickets are expected to be sold, nil when they are not expected to
+	// sell out before the sales end. It is nil and SoldOut set for sold out events.
+	SellOutAt *time.Time
+	SoldOut   bool
+	// ProjectedSold and ProjectedRevenue are the tickets expected to be sold, and what for in cents, when
+	// the sales end
+	ProjectedSold    int
+	ProjectedRevenue int64
+}
+
+// SalesForecaster projects the sales of an event from its history. VelocityForecaster is a simple
+// heuristic; a smarter model replaces it by implementing this interface.
+type SalesForecaster interface {
+	Forecast(history *SalesHistory, now time.Time) *SalesForecast
+}
+
+// VelocityForecaster extrapolates the average daily sales of the last Window days, at their average price
+type VelocityForecaster struct {
+	Window int
+}
+
+// NewVelocityForecaster creates a forecaster averaging the sales of the last window days
+func NewVelocityForecaster(window int) *VelocityForecaster {
+	return &VelocityForecaster{Window: window}
+}
+
+// Forecast extrapolates the recent sales linearly until the remaining tickets or the sales run out. The
+// window starts at the first sale when the event has sold for fewer days, so a new event is not
+// averaged with days it was not on sale.
+func (f *VelocityForecaster) Forecast(history *SalesHistory, now time.Time) *SalesForecast {
+	forecast := &SalesForecast{
+		Model:            "velocity",
+		SoldOut:          history.Remaining == 0 && history.Capacity > 0,
+		ProjectedSold:    history.Sold,
+		ProjectedRevenue: history.Revenue(),
+	}
+	if len(history.Daily) == 0 {
+		return forecast
+	}
+
+	since := now.AddDate(0, 0, -f.Window)
+	if first := history.Daily[0].Day; first.After(since) {
+		since = first
+	}
+	days := math.Max(now.Sub(since).Hours()/24, 1)
+
+	tickets, revenue := 0, int64(0)
+	for _, day := range history.Daily {
+		if !day.Day.Before(since.Truncate(24 * time.Hour)) {
+			tickets += day.Tickets
+			revenue += day.Revenue
+		}
+	}
+	forecast.Velocity = float64(tickets) / days
+	if forecast.Velocity == 0 || forecast.SoldOut || !history.SalesEnd.After(now) {
+		return forecast
+	}
+
+	daysLeft := history.SalesEnd.Sub(now).Hours() / 24
+	expected := int(math.Min(forecast.Velocity*daysLeft, float64(history.Remaining)))
+	forecast.ProjectedSold += expected
+	forecast.ProjectedRevenue += int64(math.Round(float64(expected) * float64(revenue) / float64(tickets)))
+
+	sellOutAt := now.Add(time.Duration(float64(history.Remaining) / forecast.Velocity * float64(24*time.Hour)))
+	if !sellOutAt.After(history.SalesEnd) {
+		forecast.SellOutAt = &sellOutAt
+	}
+	return forecast
+}
+
+// SalesHistoryRepository loads the sales of events. Events of other organizers are reported as not found.
+type SalesHistoryRepository interface {
+	// GetSalesHistory retrieves the sales of an event of the organizer, the ones of test orders left out
+	GetSalesHistory(ctx context.Context, eventID, organizerID int64) (*SalesHistory, error)
+}
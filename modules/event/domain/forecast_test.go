@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func day(now time.Time, daysAgo, tickets int, revenue int64) DailySales {
+	return DailySales{Day: now.AddDate(0, 0, -daysAgo).Truncate(24 * time.Hour), Tickets: tickets, Revenue: revenue}
+}
+
+func TestVelocityForecaster(t *testing.T) {
+	now := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+	forecaster := NewVelocityForecaster(DefaultForecastWindow)
+
+	// 10 tickets a day over the last two weeks, an older burst falls outside the window
+	history := &SalesHistory{Capacity: 1000, Sold: 300, Remaining: 700, SalesEnd: now.AddDate(0, 0, 30)}
+	history.Daily = append(history.Daily, day(now, 40, 160, 160_000))
+	for i := 14; i >= 1; i-- {
+		history.Daily = append(history.Daily, day(now, i, 10, 10_000))
+	}
+
+	forecast := forecaster.Forecast(history, now)
+	assert.Equal(t, "velocity", forecast.Model)
+	assert.InDelta(t, 10, forecast.Velocity, 0.001)
+	assert.Nil(t, forecast.SellOutAt, "700 tickets at 10 a day outlast the 30 days of sales left")
+	assert.Equal(t, 600, forecast.ProjectedSold)
+	assert.Equal(t, int64(600_000), forecast.ProjectedRevenue)
+
+	history.Remaining = 200
+	forecast = forecaster.Forecast(history, now)
+	require.NotNil(t, forecast.SellOutAt)
+	assert.Equal(t, now.AddDate(0, 0, 20), *forecast.SellOutAt)
+	assert.Equal(t, 500, forecast.ProjectedSold)
+}
+
+func TestVelocityForecaster_NewEvent(t *testing.T) {
+	now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	// on sale for 2 days, averaged over them rather than the whole window
+	history := &SalesHistory{Capacity: 100, Sold: 40, Remaining: 60, SalesEnd: now.AddDate(0, 0, 10),
+		Daily: []DailySales{day(now, 1, 30, 30_000), day(now, 0, 10, 10_000)}}
+
+	forecast := NewVelocityForecaster(DefaultForecastWindow).Forecast(history, now)
+	assert.InDelta(t, 40/1.5, forecast.Velocity, 0.001)
+	require.NotNil(t, forecast.SellOutAt)
+	assert.Equal(t, 100, forecast.ProjectedSold)
+	assert.Equal(t, int64(100_000), forecast.ProjectedRevenue)
+}
+
+func TestVelocityForecaster_NothingToProject(t *testing.T) {
+	now := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+	forecaster := NewVelocityForecaster(DefaultForecastWindow)
+
+	forecast := forecaster.Forecast(&SalesHistory{Capacity: 100, Remaining: 100, SalesEnd: now.AddDate(0, 0, 10)}, now)
+	assert.Zero(t, forecast.Velocity)
+	assert.Nil(t, forecast.SellOutAt)
+	assert.False(t, forecast.SoldOut)
+
+	soldOut := &SalesHistory{Capacity: 10, Sold: 10, SalesEnd: now.AddDate(0, 0, 10), Daily: []DailySales{day(now, 1, 10, 5_000)}}
+	forecast = forecaster.Forecast(soldOut, now)
+	assert.True(t, forecast.SoldOut)
+	assert.Nil(t, forecast.SellOutAt)
+	assert.Equal(t, 10, forecast.ProjectedSold)
+	assert.Equal(t, int64(5_000), forecast.ProjectedRevenue)
+
+	ended := &SalesHistory{Capacity: 10, Sold: 4, Remaining: 6, SalesEnd: now.Add(-time.Hour), Daily: []DailySales{day(now, 1, 4, 2_000)}}
+	forecast = forecaster.Forecast(ended, now)
+	assert.Equal(t, 4, forecast.ProjectedSold)
+	assert.Nil(t, forecast.SellOutAt)
+}
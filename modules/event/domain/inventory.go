@@ -0,0 +1,107 @@
+package domain
+
+import "github.com/duongptryu/gox/syserr"
+
+// TicketInventory is the stock of a ticket category. Tickets are reserved while a buyer checks out,
+// then sold or released; allotted tickets are off public sale until issued. Sold + Reserved + Allotted
+// never exceeds Capacity, which the database enforces too.
+type TicketInventory struct {
+	TicketCategoryID int64
+	Capacity         int
+	Sold             int
+	Reserved         int
+	Allotted         int
+}
+
+// Available is how many tickets can still be reserved
+func (i *TicketInventory) Available() int {
+	return max(i.Capacity-i.Sold-i.Reserved-i.Allotted, 0)
+}
+
+// Reserve holds quantity tickets, failing with ErrSoldOut if fewer are available
+func (i *TicketInventory) Reserve(quantity int) error {
+	if err := ValidateTicketQuantity(quantity); err != nil {
+		return err
+	}
+	if quantity > i.Available() {
+		return ErrSoldOut
+	}
+
+	i.Reserved += quantity
+	return nil
+}
+
+// Sell turns quantity reserved tickets into sold ones
+func (i *TicketInventory) Sell(quantity int) error {
+	if err := ValidateTicketQuantity(quantity); err != nil {
+		return err
+	}
+	if quantity > i.Reserved {
+		return ErrReservationNotHeld
+	}
+
+	i.Reserved -= quantity
+	i.Sold += quantity
+	return nil
+}
+
+// Release returns quantity reserved tickets to the stock
+func (i *TicketInventory) Release(quantity int) error {
+	if err := ValidateTicketQuantity(quantity); err != nil {
+		return err
+	}
+	if quantity > i.Reserved {
+		return ErrReservationNotHeld
+	}
+
+	i.Reserved -= quantity
+	return nil
+}
+
+// Allot sets quantity available tickets aside for an allotment, failing with ErrSoldOut if fewer are available
+func (i *TicketInventory) Allot(quantity int) error {
+	if err := ValidateTicketQuantity(quantity); err != nil {
+		return err
+	}
+	if quantity > i.Available() {
+		return ErrSoldOut
+	}
+
+	i.Allotted += quantity
+	return nil
+}
+
+// IssueAllotted turns quantity allotted tickets into sold ones, issued as complimentary tickets
+func (i *TicketInventory) IssueAllotted(quantity int) error {
+	if err := ValidateTicketQuantity(quantity); err != nil {
+		return err
+	}
+	if quantity > i.Allotted {
+		return ErrAllotmentExhausted
+	}
+
+	i.Allotted -= quantity
+	i.Sold += quantity
+	return nil
+}
+
+// ReleaseAllotted returns quantity allotted tickets to public sale
+func (i *TicketInventory) ReleaseAllotted(quantity int) error {
+	if err := ValidateTicketQuantity(quantity); err != nil {
+		return err
+	}
+	if quantity > i.Allotted {
+		return ErrAllotmentExhausted
+	}
+
+	i.Allotted -= quantity
+	return nil
+}
+
+// ValidateTicketQuantity checks that quantity tickets can be reserved, sold or released
+func ValidateTicketQuantity(quantity int) error {
+	if quantity <= 0 {
+		return syserr.New(syserr.InvalidArgumentCode, "quantity must be positive")
+	}
+	return nil
+}
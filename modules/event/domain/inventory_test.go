@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"math/rand"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTicketInventory(t *testing.T) {
+	inventory := &TicketInventory{Capacity: 5}
+
+	assert.NoError(t, inventory.Reserve(3))
+	assert.ErrorIs(t, inventory.Reserve(3), ErrSoldOut)
+	assert.NoError(t, inventory.Sell(2))
+	assert.ErrorIs(t, inventory.Sell(2), ErrReservationNotHeld)
+	assert.NoError(t, inventory.Release(1))
+	assert.Equal(t, 3, inventory.Available())
+	assert.Error(t, inventory.Reserve(0))
+	assert.Error(t, inventory.Reserve(-1))
+}
+
+func TestTicketInventory_Allotments(t *testing.T) {
+	inventory := &TicketInventory{Capacity: 10, Sold: 4}
+
+	assert.NoError(t, inventory.Allot(5))
+	assert.Equal(t, 1, inventory.Available())
+	assert.ErrorIs(t, inventory.Reserve(2), ErrSoldOut)
+	assert.ErrorIs(t, inventory.Allot(2), ErrSoldOut)
+
+	assert.NoError(t, inventory.IssueAllotted(2))
+	assert.Equal(t, 6, inventory.Sold)
+	assert.Equal(t, 1, inventory.Available())
+
+	assert.NoError(t, inventory.ReleaseAllotted(3))
+	assert.ErrorIs(t, inventory.IssueAllotted(1), ErrAllotmentExhausted)
+	assert.Equal(t, 4, inventory.Available())
+}
+
+// TestTicketInventory_NeverOversells applies random sequences of reservations, sales, releases and allotments
+// and checks that no sequence ever takes the inventory beyond its capacity nor loses a ticket
+func TestTicketInventory_NeverOversells(t *testing.T) {
+	property := func(capacity uint8, seed int64) bool {
+		rng := rand.New(rand.NewSource(seed))
+		inventory := &TicketInventory{Capacity: int(capacity)}
+
+		for range 200 {
+			quantity := rng.Intn(10) - 1
+			before := *inventory
+
+			var err error
+			switch rng.Intn(6) {
+			case 0:
+				err = inventory.Reserve(quantity)
+			case 1:
+				err = inventory.Sell(quantity)
+			case 2:
+				err = inventory.Release(quantity)
+			case 3:
+				err = inventory.Allot(quantity)
+			case 4:
+				err = inventory.IssueAllotted(quantity)
+			case 5:
+				err = inventory.ReleaseAllotted(quantity)
+			}
+
+			if err != nil && *inventory != before {
+				return false // a failed change must leave the inventory as it was
+			}
+			if inventory.Sold < 0 || inventory.Reserved < 0 || inventory.Allotted < 0 ||
+				inventory.Sold+inventory.Reserved+inventory.Allotted > inventory.Capacity {
+				return false
+			}
+			if inventory.Sold < before.Sold {
+				return false // sold tickets never come back
+			}
+		}
+		return true
+	}
+
+	assert.NoError(t, quick.Check(property, &quick.Config{MaxCount: 500}))
+}
@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// QueueMode decides the order buyers are admitted in during an on-sale
+type QueueMode string
+
+const (
+	// QueueModeFIFO admits buyers in the order they joined
+	QueueModeFIFO QueueMode = "fifo"
+	// QueueModeLottery admits the buyers who joined before the sale opened in a random order,
+	// then the later ones in the order they joined
+	QueueModeLottery QueueMode = "lottery"
+)
+
+// IsValid checks if the queue mode is valid
+func (m QueueMode) IsValid() bool {
+	return m == QueueModeFIFO || m == QueueModeLottery
+}
+
+// QueueSettings configures the on-sale queue of an event
+type QueueSettings struct {
+	EventID int64
+	Enabled bool
+	Mode    QueueMode
+	// MaxConcurrentUsers is how many buyers may check out at the same time
+	MaxConcurrentUsers int
+	// ReservationTimeout is how long an admitted buyer has to check out
+	ReservationTimeout time.Duration
+	// EstimatedServiceTime is how long a checkout takes on average
+	EstimatedServiceTime time.Duration
+	MaxTicketsPerOrder   int
+	SaleStartDate        *time.Time
+	SalesPause           SalesPause
+	// CategorySalesPauses are the sales pauses of the categories paused or with a pause scheduled
+	CategorySalesPauses map[int64]SalesPause
+}
+
+// IsOpen tells whether the sale has started, buyers are only admitted from then
+func (s *QueueSettings) IsOpen(now time.Time) bool {
+	return s.SaleStartDate == nil || !now.Before(*s.SaleStartDate)
+}
+
+// SalesPaused tells whether the tickets of a category cannot be reserved at now
+func (s *QueueSettings) SalesPaused(ticketCategoryID int64, now time.Time) bool {
+	return s.SalesPause.IsPaused(now) || s.CategorySalesPauses[ticketCategoryID].IsPaused(now)
+}
+
+// EstimatedWait estimates how long the buyer at position waits to be admitted
+func (s *QueueSettings) EstimatedWait(position int64) time.Duration {
+	if position <= 0 || s.MaxConcurrentUsers <= 0 {
+		return 0
+	}
+	return time.Duration(position) * s.EstimatedServiceTime / time.Duration(s.MaxConcurrentUsers)
+}
+
+// QueueTicket is the place of a buyer in the on-sale queue
+type QueueTicket struct {
+	Token string
+	// Position is 1 for the next buyer to be admitted, 0 once admitted
+	Position int64
+	Admitted bool
+	// ExpiresAt is when an admitted buyer loses the checkout slot and the tickets reserved
+	ExpiresAt *time.Time
+}
+
+// QueueSettingsRepository loads the queue settings and inventory of on-sales
+type QueueSettingsRepository interface {
+	// GetQueueSettings retrieves the queue settings of an event
+	GetQueueSettings(ctx context.Context, eventID int64) (*QueueSettings, error)
+
+	// GetRemainingTickets retrieves how many tickets of a category of the event are left
+	GetRemainingTickets(ctx context.Context, eventID, ticketCategoryID int64) (int, error)
+}
+
+// OnSaleQueue admits buyers of an on-sale to checkout a bounded number at a time and holds
+// the inventory they reserve, so checkout traffic never reaches the database all at once
+type OnSaleQueue interface {
+	// Join puts the user in the queue, or returns the ticket the user already has
+	Join(ctx context.Context, settings *QueueSettings, userID int64) (*QueueTicket, error)
+
+	// Status admits the buyers the gate has room for and returns the ticket of token
+	Status(ctx context.Context, settings *QueueSettings, userID int64, token string) (*QueueTicket, error)
+
+	// Reserve holds quantity tickets of a category for an admitted buyer and returns how many are left.
+	// loadStock provides the inventory the first time the category is reserved from.
+	Reserve(ctx context.Context, settings *QueueSettings, userID int64, token string, ticketCategoryID int64, quantity int, loadStock func(ctx context.Context) (int, error)) (int, error)
+
+	// Leave gives up the place or checkout slot of token along with the tickets it reserved
+	Leave(ctx context.Context, eventID, userID int64, token string) error
+
+	// AdjustStock adds delta tickets to the stock of a category once per changeID, when the capacity of
+	// the category changed. A stock not loaded yet is left alone, it is loaded with the change.
+	AdjustStock(ctx context.Context, eventID, ticketCategoryID int64, delta int, changeID string) error
+}
@@ -0,0 +1,146 @@
+package domain
+
+import "time"
+
+// EventStatus represents the lifecycle of an event
+type EventStatus string
+
+const (
+	EventStatusDraft     EventStatus = "draft"
+	EventStatusPublished EventStatus = "published"
+	EventStatusCancelled EventStatus = "cancelled"
+	EventStatusPostponed EventStatus = "postponed"
+	EventStatusCompleted EventStatus = "completed"
+)
+
+// PublicEvent is the read model of a public event page: the event with everything the page shows,
+// loaded at once. Drafts have no public page.
+type PublicEvent struct {
+	ID          int64
+	Slug        string
+	Title       string
+	Description string
+	// MetaTitle and MetaDescription are the meta tags the organizer wrote, empty for the defaults
+	MetaTitle       string
+	MetaDescription string
+	EventType       string
+	Status          EventStatus
+	Timezone        string
+	// Currency is the one the ticket categories are priced in
+	Currency           string
+	ImageURL           string
+	AgeRestriction     *int
+	MaxTicketsPerOrder int
+	SaleStartDate      *time.Time
+	SaleEndDate        *time.Time
+	SalesPause         SalesPause
+	Sessions           []PublicSession
+	Venue              *PublicVenue
+	Organizer          PublicOrganizer
+	TicketCategories   []PublicTicketCategory
+	UpdatedAt          time.Time
+}
+
+// PublicSession is an occurrence of an event. An event has a single session until recurring
+// schedules are stored.
+type PublicSession struct {
+	StartDate time.Time
+	EndDate   *time.Time
+}
+
+// PublicVenue is where an event takes place
+type PublicVenue struct {
+	Name      string
+	Address   string
+	City      string
+	State     string
+	Country   string
+	VenueType string
+	Latitude  *float64
+	Longitude *float64
+}
+
+// PublicOrganizer is the organizer as shown to buyers, without contact details
+type PublicOrganizer struct {
+	ID   int64
+	Name string
+}
+
+// PublicTicketCategory is a ticket category with its availability
+type PublicTicketCategory struct {
+	ID           int64
+	Name         string
+	Description  string
+	CategoryType string
+	Price        string
+	Quantity     int
+	QuantitySold int
+	// QuantityReserved tickets are held by buyers checking out
+	QuantityReserved int
+	// QuantityAllotted tickets are set aside for press, sponsors or guests
+	QuantityAllotted int
+	MaxPerOrder      int
+	SaleStartDate    *time.Time
+	SaleEndDate      *time.Time
+	SalesPause       SalesPause
+}
+
+// Remaining returns how many tickets of the category are left
+func (c *PublicTicketCategory) Remaining() int {
+	if remaining := c.Quantity - c.QuantitySold - c.QuantityReserved - c.QuantityAllotted; remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// MaxSearchPeriod is the longest date range of a public search
+const MaxSearchPeriod = 366 * 24 * time.Hour
+
+// EventSearch are the filters of the public event search. Zero fields do not filter.
+type EventSearch struct {
+	// From and To bound the start of the events, From included and To excluded
+	From time.Time
+	To   time.Time
+	// EventType is the category of the events
+	EventType string
+	City      string
+	VenueID   int64
+	// MinPrice and MaxPrice keep the events with a ticket category priced between them, both included,
+	// in the currency of each event
+	MinPrice *float64
+	MaxPrice *float64
+	// Currency keeps the events priced in a currency
+	Currency string
+	// Text is searched for in the title and description of the events
+	Text string
+}
+
+// Validate checks the ranges of the search
+func (s EventSearch) Validate() error {
+	if !s.To.After(s.From) || s.To.Sub(s.From) > MaxSearchPeriod {
+		return ErrInvalidSearchPeriod
+	}
+	if s.MinPrice != nil && s.MaxPrice != nil && *s.MinPrice > *s.MaxPrice {
+		return ErrInvalidPriceRange
+	}
+	return nil
+}
+
+// PublicEventSummary is an event as listed by the public search, without its ticket categories
+type PublicEventSummary struct {
+	ID        int64
+	Slug      string
+	Title     string
+	EventType string
+	Status    EventStatus
+	Timezone  string
+	Currency  string
+	ImageURL  string
+	StartDate time.Time
+	EndDate   *time.Time
+	Venue     *PublicVenue
+	Organizer PublicOrganizer
+	// MinPrice and MaxPrice are the cheapest and dearest ticket categories, empty without any
+	MinPrice string
+	MaxPrice string
+}
@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventSearchValidate(t *testing.T) {
+	from := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	price := func(p float64) *float64 { return &p }
+
+	assert.NoError(t, EventSearch{From: from, To: from.Add(MaxSearchPeriod)}.Validate())
+	assert.NoError(t, EventSearch{From: from, To: from.AddDate(0, 0, 1), MinPrice: price(10), MaxPrice: price(10)}.Validate())
+
+	assert.Equal(t, ErrInvalidSearchPeriod, EventSearch{From: from, To: from}.Validate())
+	assert.Equal(t, ErrInvalidSearchPeriod, EventSearch{From: from, To: from.Add(MaxSearchPeriod + time.Hour)}.Validate())
+	assert.Equal(t, ErrInvalidPriceRange, EventSearch{From: from, To: from.AddDate(0, 0, 1), MinPrice: price(20), MaxPrice: price(10)}.Validate())
+}
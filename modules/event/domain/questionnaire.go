@@ -0,0 +1,254 @@
+package domain
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"tixgo/shared/listing"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// QuestionKind tells how a question of the attendee questionnaire is answered
+type QuestionKind string
+
+const (
+	QuestionKindText     QuestionKind = "text"
+	QuestionKindSelect   QuestionKind = "select"
+	QuestionKindCheckbox QuestionKind = "checkbox"
+)
+
+// IsValidQuestionKind checks if the question kind is valid
+func IsValidQuestionKind(kind string) bool {
+	switch QuestionKind(kind) {
+	case QuestionKindText, QuestionKindSelect, QuestionKindCheckbox:
+		return true
+	default:
+		return false
+	}
+}
+
+const (
+	// MaxEventQuestions bounds the questions of the questionnaire of an event
+	MaxEventQuestions = 20
+	// MaxQuestionOptions bounds the options of a select question
+	MaxQuestionOptions = 20
+	// MaxAnswerLength bounds the answer to a text question, in characters
+	MaxAnswerLength = 1000
+)
+
+// EventQuestion is a question of the questionnaire of an event, answered for every ticket. Select
+// questions are answered with one of their options, checkbox questions with "true" or "false"; a
+// required checkbox must be checked, e.g. to accept a waiver.
+type EventQuestion struct {
+	ID        int64
+	EventID   int64
+	Label     string
+	Kind      QuestionKind
+	Options   []string
+	Required  bool
+	Position  int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewEventQuestion creates a question of the event
+func NewEventQuestion(eventID int64, label string, kind QuestionKind, options []string, required bool, position int) (*EventQuestion, error) {
+	if !IsValidQuestionKind(string(kind)) {
+		return nil, ErrInvalidQuestionKind
+	}
+
+	question := &EventQuestion{EventID: eventID, Kind: kind}
+	if err := question.Update(label, options, required, position); err != nil {
+		return nil, err
+	}
+	return question, nil
+}
+
+// Update changes the question. Its kind is kept so the answers already given stay meaningful.
+func (q *EventQuestion) Update(label string, options []string, required bool, position int) error {
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return syserr.New(syserr.InvalidArgumentCode, "label is required")
+	}
+
+	options, err := normalizeOptions(q.Kind, options)
+	if err != nil {
+		return err
+	}
+
+	q.Label = label
+	q.Options = options
+	q.Required = required
+	q.Position = position
+	return nil
+}
+
+// normalizeOptions trims the options of a question, which only select questions have: at least two,
+// all different
+func normalizeOptions(kind QuestionKind, options []string) ([]string, error) {
+	if kind != QuestionKindSelect {
+		if len(options) > 0 {
+			return nil, ErrInvalidQuestionOptions
+		}
+		return []string{}, nil
+	}
+
+	if len(options) < 2 || len(options) > MaxQuestionOptions {
+		return nil, ErrInvalidQuestionOptions
+	}
+
+	normalized := make([]string, len(options))
+	seen := make(map[string]bool, len(options))
+	for i, option := range options {
+		option = strings.TrimSpace(option)
+		if option == "" || seen[option] {
+			return nil, ErrInvalidQuestionOptions
+		}
+		seen[option] = true
+		normalized[i] = option
+	}
+	return normalized, nil
+}
+
+// AttendeeAnswer is the answer given for a ticket to a question of its event
+type AttendeeAnswer struct {
+	QuestionID int64
+	Value      string
+}
+
+// ValidateAnswers checks the answers given for a ticket against the questionnaire of its event. It
+// returns them normalized, blank answers to optional questions left out.
+func ValidateAnswers(questions []*EventQuestion, answers []AttendeeAnswer) ([]AttendeeAnswer, error) {
+	values := make(map[int64]string, len(answers))
+	for _, answer := range answers {
+		if _, ok := values[answer.QuestionID]; ok {
+			return nil, ErrInvalidAnswer
+		}
+		values[answer.QuestionID] = strings.TrimSpace(answer.Value)
+	}
+
+	validated := make([]AttendeeAnswer, 0, len(answers))
+	for _, question := range questions {
+		value, err := question.normalizeAnswer(values[question.ID])
+		if err != nil {
+			return nil, err
+		}
+		delete(values, question.ID)
+
+		if value != "" {
+			validated = append(validated, AttendeeAnswer{QuestionID: question.ID, Value: value})
+		}
+	}
+
+	if len(values) > 0 {
+		return nil, ErrUnknownQuestion
+	}
+
+	return validated, nil
+}
+
+// normalizeAnswer checks an answer to the question, empty when not answered
+func (q *EventQuestion) normalizeAnswer(value string) (string, error) {
+	switch q.Kind {
+	case QuestionKindCheckbox:
+		checked := false
+		if value != "" {
+			var err error
+			if checked, err = strconv.ParseBool(value); err != nil {
+				return "", ErrInvalidAnswer
+			}
+		}
+		if q.Required && !checked {
+			return "", ErrAnswerRequired
+		}
+		return strconv.FormatBool(checked), nil
+
+	case QuestionKindSelect:
+		if value != "" && !slices.Contains(q.Options, value) {
+			return "", ErrInvalidAnswer
+		}
+
+	default:
+		if len([]rune(value)) > MaxAnswerLength {
+			return "", ErrInvalidAnswer
+		}
+	}
+
+	if q.Required && value == "" {
+		return "", ErrAnswerRequired
+	}
+	return value, nil
+}
+
+// Attendee is a ticket of a confirmed order of an event with its holder and the answers given for it,
+// a line of the attendee manifest
+type Attendee struct {
+	TicketID           int64
+	TicketNumber       string
+	TicketStatus       string
+	TicketCategoryName string
+	SeatSection        string
+	SeatRow            string
+	SeatNumber         string
+	OrderNumber        string
+	Email              string
+	// Name is the name of the account the order was delivered to, or given at the box office
+	Name string
+	// AttendeeName and AttendeeEmail are who the ticket is assigned to, empty when it is not
+	AttendeeName  string
+	AttendeeEmail string
+	// Answers are the answers given for the ticket by question label
+	Answers map[string]string
+	// AccessNeeds, DietaryNeeds and AccommodationNotes are the accommodations asked for with the order,
+	// shared by its tickets
+	AccessNeeds        []string
+	DietaryNeeds       []string
+	AccommodationNotes string
+}
+
+// EventQuestionRepository defines the interface for the persistence of event questionnaires. Questions
+// of events of other organizers are reported as not found.
+type EventQuestionRepository interface {
+	// List retrieves the questions of an event, by position, ErrEventNotFound if it does not exist
+	List(ctx context.Context, eventID int64) ([]*EventQuestion, error)
+
+	// Create adds a question to an event of the organizer, ErrTooManyQuestions if the event has
+	// MaxEventQuestions already and ErrQuestionLabelTaken if it has one with the same label
+	Create(ctx context.Context, question *EventQuestion, organizerID int64) error
+
+	// GetByID retrieves a question of an event of the organizer
+	GetByID(ctx context.Context, id, eventID, organizerID int64) (*EventQuestion, error)
+
+	// Update saves the changes to a question, ErrQuestionLabelTaken if its event has another with the
+	// same label
+	Update(ctx context.Context, question *EventQuestion) error
+
+	// Delete deletes a question with the answers given to it
+	Delete(ctx context.Context, question *EventQuestion) error
+}
+
+// AttendeeRepository stores the attendees of tickets and their answers, and lists them. The ticket must
+// be in a pending or confirmed order of the user, ErrTicketNotFound otherwise.
+type AttendeeRepository interface {
+	// Assign assigns a ticket of the event to the attendee, replacing the previous one. It fails with
+	// ErrAttendeeEditClosed past the edit cutoff of the event.
+	Assign(ctx context.Context, attendee *TicketAttendee) error
+
+	// Unassign removes the attendee of a ticket of the event, the buyer holding it again. It fails with
+	// ErrAttendeeRequired if the event requires attendees and ErrAttendeeEditClosed past its edit cutoff.
+	Unassign(ctx context.Context, eventID, ticketID, userID int64) error
+
+	// SaveAnswers replaces the answers given for a ticket of the event by the answers. Answers are closed
+	// once the event started, ErrAnswersClosed.
+	SaveAnswers(ctx context.Context, eventID, ticketID, userID int64, answers []AttendeeAnswer) error
+
+	// List retrieves the attendees of an event of the organizer with pagination, by ticket number
+	List(ctx context.Context, eventID, organizerID int64, paging *listing.Paging) ([]*Attendee, error)
+
+	// Stream calls fn for every attendee of an event of the organizer, by ticket number, as rows are read
+	Stream(ctx context.Context, eventID, organizerID int64, fn func(attendee *Attendee) error) error
+}
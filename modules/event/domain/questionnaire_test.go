@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEventQuestion(t *testing.T) {
+	_, err := NewEventQuestion(1, "T-shirt size", QuestionKind("radio"), nil, false, 0)
+	assert.ErrorIs(t, err, ErrInvalidQuestionKind)
+
+	_, err = NewEventQuestion(1, "  ", QuestionKindText, nil, false, 0)
+	assert.Error(t, err)
+
+	_, err = NewEventQuestion(1, "Company", QuestionKindText, []string{"A", "B"}, false, 0)
+	assert.ErrorIs(t, err, ErrInvalidQuestionOptions)
+
+	_, err = NewEventQuestion(1, "T-shirt size", QuestionKindSelect, []string{"M"}, false, 0)
+	assert.ErrorIs(t, err, ErrInvalidQuestionOptions)
+
+	_, err = NewEventQuestion(1, "T-shirt size", QuestionKindSelect, []string{"M", " M "}, false, 0)
+	assert.ErrorIs(t, err, ErrInvalidQuestionOptions)
+
+	question, err := NewEventQuestion(1, " T-shirt size ", QuestionKindSelect, []string{" S", "M", "L "}, true, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "T-shirt size", question.Label)
+	assert.Equal(t, []string{"S", "M", "L"}, question.Options)
+}
+
+func TestValidateAnswers(t *testing.T) {
+	questions := []*EventQuestion{
+		{ID: 1, Kind: QuestionKindText, Label: "Company"},
+		{ID: 2, Kind: QuestionKindSelect, Label: "T-shirt size", Options: []string{"S", "M", "L"}, Required: true},
+		{ID: 3, Kind: QuestionKindCheckbox, Label: "I accept the waiver", Required: true},
+		{ID: 4, Kind: QuestionKindCheckbox, Label: "Newsletter"},
+	}
+
+	answers, err := ValidateAnswers(questions, []AttendeeAnswer{
+		{QuestionID: 1, Value: "  "},
+		{QuestionID: 2, Value: " M "},
+		{QuestionID: 3, Value: "1"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []AttendeeAnswer{
+		{QuestionID: 2, Value: "M"},
+		{QuestionID: 3, Value: "true"},
+		{QuestionID: 4, Value: "false"},
+	}, answers)
+
+	tests := []struct {
+		name    string
+		answers []AttendeeAnswer
+		err     error
+	}{
+		{"required select missing", []AttendeeAnswer{{QuestionID: 3, Value: "true"}}, ErrAnswerRequired},
+		{"option not offered", []AttendeeAnswer{{QuestionID: 2, Value: "XL"}, {QuestionID: 3, Value: "true"}}, ErrInvalidAnswer},
+		{"required checkbox unchecked", []AttendeeAnswer{{QuestionID: 2, Value: "S"}, {QuestionID: 3, Value: "false"}}, ErrAnswerRequired},
+		{"checkbox not a boolean", []AttendeeAnswer{{QuestionID: 2, Value: "S"}, {QuestionID: 3, Value: "yes"}}, ErrInvalidAnswer},
+		{"question answered twice", []AttendeeAnswer{{QuestionID: 2, Value: "S"}, {QuestionID: 2, Value: "M"}, {QuestionID: 3, Value: "true"}}, ErrInvalidAnswer},
+		{"unknown question", []AttendeeAnswer{{QuestionID: 2, Value: "S"}, {QuestionID: 3, Value: "true"}, {QuestionID: 9, Value: "x"}}, ErrUnknownQuestion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ValidateAnswers(questions, tt.answers)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"context"
+
+	"tixgo/shared/listing"
+)
+
+// PublicEventRepository loads the read model of public event pages
+type PublicEventRepository interface {
+	// GetPublicBySlug retrieves the public event page of a non draft event by slug
+	GetPublicBySlug(ctx context.Context, slug string) (*PublicEvent, error)
+	// Search retrieves a page of the published and postponed events matching the search, soonest first.
+	// Drafts have no public page, and cancelled or completed events sell no tickets anymore.
+	Search(ctx context.Context, search EventSearch, paging *listing.Paging) ([]*PublicEventSummary, error)
+}
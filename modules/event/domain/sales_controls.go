@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// SalesPause tells whether the online sales of an event or ticket category are paused, now or from a
+// scheduled time. Box office sales and complimentary tickets are not affected.
+type SalesPause struct {
+	Paused bool
+	// PauseAt is when the sales pause on their own, nil when no pause is scheduled
+	PauseAt *time.Time
+}
+
+// IsPaused tells whether the sales are paused at now. A scheduled pause applies from its time on,
+// before the job recording it runs.
+func (p SalesPause) IsPaused(now time.Time) bool {
+	return p.Paused || (p.PauseAt != nil && !now.Before(*p.PauseAt))
+}
+
+// ValidatePauseAt checks that a scheduled pause is in the future
+func ValidatePauseAt(pauseAt time.Time, now time.Time) error {
+	if !pauseAt.After(now) {
+		return ErrInvalidPauseTime
+	}
+	return nil
+}
+
+// Resize changes the capacity of the category, returning by how much it changed. The capacity never
+// goes below the tickets sold, reserved or allotted.
+func (i *TicketInventory) Resize(capacity int) (int, error) {
+	if capacity < i.Sold+i.Reserved+i.Allotted {
+		return 0, ErrCapacityBelowSold
+	}
+
+	delta := capacity - i.Capacity
+	i.Capacity = capacity
+	return delta, nil
+}
+
+// SalesControls are the sales state of an event and its ticket categories, as organizers control them
+type SalesControls struct {
+	EventID          int64
+	SalesPause       SalesPause
+	TicketCategories []CategorySalesControls
+}
+
+// CategorySalesControls are the sales state of a ticket category
+type CategorySalesControls struct {
+	Name       string
+	SalesPause SalesPause
+	Inventory  TicketInventory
+}
+
+// AvailabilityChange tells what changed the availability of tickets
+type AvailabilityChange string
+
+const (
+	AvailabilityChangePaused         AvailabilityChange = "paused"
+	AvailabilityChangeResumed        AvailabilityChange = "resumed"
+	AvailabilityChangePauseScheduled AvailabilityChange = "pause_scheduled"
+	AvailabilityChangeCapacity       AvailabilityChange = "capacity"
+)
+
+// EventTicketAvailabilityChanged is published whenever organizers pause or resume sales, schedule a
+// pause or change the capacity of a category, and when a scheduled pause applies. TicketCategoryID is
+// 0 when the whole event changed. A capacity change carries its CapacityDelta; it may be delivered more
+// than once, consumers apply it once by ChangeID.
+type EventTicketAvailabilityChanged struct {
+	ChangeID         string
+	EventID          int64
+	TicketCategoryID int64
+	Change           AvailabilityChange
+	CapacityDelta    int
+	OccurredAt       time.Time
+}
+
+// NewEventTicketAvailabilityChanged creates the change of the availability of the target that occurred now
+func NewEventTicketAvailabilityChanged(target SalesTarget, change AvailabilityChange, capacityDelta int) *EventTicketAvailabilityChanged {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+
+	return &EventTicketAvailabilityChanged{
+		ChangeID:         hex.EncodeToString(id),
+		EventID:          target.EventID,
+		TicketCategoryID: target.TicketCategoryID,
+		Change:           change,
+		CapacityDelta:    capacityDelta,
+		OccurredAt:       time.Now(),
+	}
+}
+
+// SalesTarget is the event, or ticket category of the event, a sales control applies to. TicketCategoryID
+// is 0 for the whole event.
+type SalesTarget struct {
+	EventID          int64
+	TicketCategoryID int64
+}
+
+// SalesControlRepository stores the sales controls of events. Events of other organizers are reported
+// as not found, categories of other events too.
+type SalesControlRepository interface {
+	// Get retrieves the sales controls of an event of the organizer
+	Get(ctx context.Context, eventID, organizerID int64) (*SalesControls, error)
+
+	// SetPaused pauses or resumes the sales of the target, dropping the pause scheduled for it
+	SetPaused(ctx context.Context, target SalesTarget, organizerID int64, paused bool) error
+
+	// SchedulePause schedules the sales of the target to pause at pauseAt, nil dropping the schedule
+	SchedulePause(ctx context.Context, target SalesTarget, organizerID int64, pauseAt *time.Time) error
+
+	// Resize changes the capacity of a ticket category of an event of the organizer and returns its
+	// stock and by how much the capacity changed. It fails with ErrCapacityBelowSold below the tickets
+	// sold, reserved or allotted, and ErrCapacityExceedsSeats above the seats of a seated category.
+	Resize(ctx context.Context, target SalesTarget, organizerID int64, capacity int) (*TicketInventory, int, error)
+
+	// ApplyScheduledPauses pauses the sales whose scheduled pause is due and returns their targets
+	ApplyScheduledPauses(ctx context.Context) ([]SalesTarget, error)
+}
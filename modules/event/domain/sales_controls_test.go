@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSalesPause_IsPaused(t *testing.T) {
+	now := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+	later := now.Add(time.Hour)
+
+	assert.False(t, SalesPause{}.IsPaused(now))
+	assert.True(t, SalesPause{Paused: true}.IsPaused(now))
+	assert.False(t, SalesPause{PauseAt: &later}.IsPaused(now))
+	assert.True(t, SalesPause{PauseAt: &later}.IsPaused(later), "a scheduled pause applies from its time")
+
+	settings := &QueueSettings{CategorySalesPauses: map[int64]SalesPause{2: {Paused: true}}}
+	assert.False(t, settings.SalesPaused(1, now))
+	assert.True(t, settings.SalesPaused(2, now))
+
+	settings.SalesPause = SalesPause{PauseAt: &later}
+	assert.True(t, settings.SalesPaused(1, later), "pausing the event pauses every category")
+}
+
+func TestValidatePauseAt(t *testing.T) {
+	now := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+
+	assert.ErrorIs(t, ValidatePauseAt(now, now), ErrInvalidPauseTime)
+	assert.ErrorIs(t, ValidatePauseAt(now.Add(-time.Minute), now), ErrInvalidPauseTime)
+	assert.NoError(t, ValidatePauseAt(now.Add(time.Minute), now))
+}
+
+func TestTicketInventory_Resize(t *testing.T) {
+	inventory := &TicketInventory{Capacity: 100, Sold: 40, Reserved: 5, Allotted: 10}
+
+	_, err := inventory.Resize(54)
+	assert.ErrorIs(t, err, ErrCapacityBelowSold)
+	assert.Equal(t, 100, inventory.Capacity)
+
+	delta, err := inventory.Resize(55)
+	require.NoError(t, err)
+	assert.Equal(t, -45, delta)
+	assert.Equal(t, 0, inventory.Available())
+
+	delta, err = inventory.Resize(120)
+	require.NoError(t, err)
+	assert.Equal(t, 65, delta)
+	assert.Equal(t, 65, inventory.Available())
+}
+
+func TestNewEventTicketAvailabilityChanged(t *testing.T) {
+	target := SalesTarget{EventID: 1, TicketCategoryID: 2}
+
+	first := NewEventTicketAvailabilityChanged(target, AvailabilityChangeCapacity, 5)
+	second := NewEventTicketAvailabilityChanged(target, AvailabilityChangeCapacity, 5)
+	assert.Len(t, first.ChangeID, 32)
+	assert.NotEqual(t, first.ChangeID, second.ChangeID, "every change is applied once")
+	assert.Equal(t, 5, first.CapacityDelta)
+}
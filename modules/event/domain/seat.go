@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// SeatStatus is the status of a seat as shown on seat maps
+type SeatStatus string
+
+const (
+	SeatStatusAvailable SeatStatus = "available"
+	SeatStatusHeld      SeatStatus = "held"
+	SeatStatusSold      SeatStatus = "sold"
+)
+
+// IsValid checks if the seat status is valid
+func (s SeatStatus) IsValid() bool {
+	return s == SeatStatusAvailable || s == SeatStatusHeld || s == SeatStatusSold
+}
+
+// Seat is a reserved-seating ticket of an event
+type Seat struct {
+	TicketID         int64
+	TicketCategoryID int64
+	Section          string
+	Row              string
+	Number           string
+	Status           SeatStatus
+}
+
+// EventSeatStatusChanged is published whenever a seat is held, released or sold
+type EventSeatStatusChanged struct {
+	EventID    int64
+	TicketID   int64
+	Status     SeatStatus
+	OccurredAt time.Time
+}
+
+func NewEventSeatStatusChanged(eventID, ticketID int64, status SeatStatus) *EventSeatStatusChanged {
+	return &EventSeatStatusChanged{
+		EventID:    eventID,
+		TicketID:   ticketID,
+		Status:     status,
+		OccurredAt: time.Now(),
+	}
+}
+
+// SeatMapRepository loads the seats of events
+type SeatMapRepository interface {
+	// GetSeatMap retrieves the seats of a non draft event, ordered by section, row and number
+	GetSeatMap(ctx context.Context, eventID int64) ([]Seat, error)
+}
+
+// SeatUpdates fans seat status changes out to every instance serving seat map streams
+type SeatUpdates interface {
+	// Publish sends a change to the subscribers of its event on every instance
+	Publish(ctx context.Context, change *EventSeatStatusChanged) error
+
+	// Subscribe returns the changes of an event from now on, until ctx is done
+	Subscribe(ctx context.Context, eventID int64) (<-chan *EventSeatStatusChanged, error)
+}
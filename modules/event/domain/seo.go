@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// MaxMetaTitleLength and MaxMetaDescriptionLength are about what search engines show of a result
+	MaxMetaTitleLength       = 70
+	MaxMetaDescriptionLength = 160
+
+	minSlugLength = 3
+	maxSlugLength = 200
+)
+
+var (
+	slugRegex           = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	slugTrailingIDRegex = regexp.MustCompile(`-([0-9]+)$`)
+)
+
+// EventSEO is what search engines are told of the public page of an event: its slug and meta tags.
+// An empty meta tag falls back to the title or description of the event.
+type EventSEO struct {
+	EventID     int64
+	OrganizerID int64
+	Status      EventStatus
+	// Slug is empty until the event is published or its organizer sets one
+	Slug            string
+	MetaTitle       string
+	MetaDescription string
+	// FormerSlugs are the slugs the public page had before, newest first, which redirect to Slug
+	FormerSlugs []string
+}
+
+// Update sets the slug and meta tags of the page, keeping the slug when slug is empty. Renaming the
+// slug of a public page keeps the former one redirecting to the new one; drafts have no page yet.
+func (s *EventSEO) Update(slug, metaTitle, metaDescription string) error {
+	slug = strings.TrimSpace(slug)
+	metaTitle = strings.TrimSpace(metaTitle)
+	metaDescription = strings.TrimSpace(metaDescription)
+
+	if slug != "" && slug != s.Slug {
+		if err := ValidateSlug(slug, s.EventID); err != nil {
+			return err
+		}
+	}
+	if utf8.RuneCountInString(metaTitle) > MaxMetaTitleLength {
+		return ErrMetaTitleTooLong
+	}
+	if utf8.RuneCountInString(metaDescription) > MaxMetaDescriptionLength {
+		return ErrMetaDescriptionTooLong
+	}
+
+	if slug != "" && slug != s.Slug {
+		former := slices.DeleteFunc(s.FormerSlugs, func(former string) bool { return former == slug })
+		if s.Status != EventStatusDraft && s.Slug != "" {
+			former = append([]string{s.Slug}, former...)
+		}
+		s.FormerSlugs = former
+		s.Slug = slug
+	}
+	s.MetaTitle = metaTitle
+	s.MetaDescription = metaDescription
+	return nil
+}
+
+// ValidateSlug checks a slug an organizer chose for an event. A slug ending with a number must end
+// with the id of the event, as generated slugs do, so it never takes the slug another event gets
+// when published.
+func ValidateSlug(slug string, eventID int64) error {
+	if len(slug) < minSlugLength || len(slug) > maxSlugLength || !slugRegex.MatchString(slug) {
+		return ErrInvalidSlug
+	}
+	if match := slugTrailingIDRegex.FindStringSubmatch(slug); match != nil && match[1] != strconv.FormatInt(eventID, 10) {
+		return ErrInvalidSlug
+	}
+	return nil
+}
+
+// MetaDescription returns the meta description of a page, the start of the description of the event
+// when its organizer wrote none
+func MetaDescription(metaDescription, description string) string {
+	if metaDescription != "" {
+		return metaDescription
+	}
+
+	description = strings.Join(strings.Fields(description), " ")
+	if utf8.RuneCountInString(description) <= MaxMetaDescriptionLength {
+		return description
+	}
+	runes := []rune(description)[:MaxMetaDescriptionLength-1]
+	return strings.TrimRight(string(runes), " ") + "…"
+}
+
+// Site is the public site showing the event pages at /events/<slug>
+type Site struct {
+	// URL is the scheme and host of the site
+	URL string
+}
+
+// EventURL returns the URL of the public page of an event
+func (s Site) EventURL(slug string) string {
+	return strings.TrimRight(s.URL, "/") + "/events/" + slug
+}
+
+// SitemapEntry is a public event page listed in the sitemap
+type SitemapEntry struct {
+	Slug      string
+	UpdatedAt time.Time
+}
+
+// EventSEORepository defines the interface for the persistence of the slugs and meta tags of events.
+// Events of other organizers are reported as not found.
+type EventSEORepository interface {
+	// GetByEventID retrieves the slug, meta tags and former slugs of an event of the organizer
+	GetByEventID(ctx context.Context, eventID, organizerID int64) (*EventSEO, error)
+
+	// Save saves the slug, meta tags and former slugs of an event, ErrSlugTaken if the slug is or was
+	// the slug of another event
+	Save(ctx context.Context, seo *EventSEO) error
+
+	// GetCanonicalSlug returns the current slug of the public page a former slug redirects to,
+	// ErrEventNotFound if none does
+	GetCanonicalSlug(ctx context.Context, formerSlug string) (string, error)
+
+	// ListSitemapEntries retrieves up to limit published or postponed event pages, last updated first
+	ListSitemapEntries(ctx context.Context, limit int) ([]*SitemapEntry, error)
+}
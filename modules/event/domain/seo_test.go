@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventSEO_Update(t *testing.T) {
+	seo := &EventSEO{EventID: 42, Status: EventStatusPublished, Slug: "concert-42", FormerSlugs: []string{"summer-show"}}
+
+	require.NoError(t, seo.Update("summer-concert", " Summer Concert ", ""))
+	assert.Equal(t, "summer-concert", seo.Slug)
+	assert.Equal(t, "Summer Concert", seo.MetaTitle)
+	assert.Equal(t, []string{"concert-42", "summer-show"}, seo.FormerSlugs, "the former slug of a public page redirects")
+
+	require.NoError(t, seo.Update("", "", "Live by the sea"))
+	assert.Equal(t, "summer-concert", seo.Slug, "an empty slug keeps the current one")
+	assert.Equal(t, "Live by the sea", seo.MetaDescription)
+
+	require.NoError(t, seo.Update("summer-show", "", ""))
+	assert.Equal(t, []string{"summer-concert", "concert-42"}, seo.FormerSlugs, "taking a former slug back stops its redirect")
+
+	assert.ErrorIs(t, seo.Update("", strings.Repeat("t", MaxMetaTitleLength+1), ""), ErrMetaTitleTooLong)
+	assert.ErrorIs(t, seo.Update("", "", strings.Repeat("d", MaxMetaDescriptionLength+1)), ErrMetaDescriptionTooLong)
+	assert.ErrorIs(t, seo.Update("Summer Show", "", ""), ErrInvalidSlug)
+	assert.Equal(t, "summer-show", seo.Slug, "a failed update changes nothing")
+
+	draft := &EventSEO{EventID: 7, Status: EventStatusDraft, Slug: "draft-7"}
+	require.NoError(t, draft.Update("opening-night", "", ""))
+	assert.Empty(t, draft.FormerSlugs, "drafts have no page to redirect from")
+}
+
+func TestValidateSlug(t *testing.T) {
+	tests := []struct {
+		slug  string
+		valid bool
+	}{
+		{slug: "summer-concert", valid: true},
+		{slug: "summer-concert-42", valid: true},
+		{slug: "summer-2026-edition", valid: true},
+		{slug: "summer-concert-43", valid: false},
+		{slug: "2026", valid: true},
+		{slug: "ab", valid: false},
+		{slug: "Summer-Concert", valid: false},
+		{slug: "summer--concert", valid: false},
+		{slug: "-summer", valid: false},
+		{slug: strings.Repeat("a", maxSlugLength+1), valid: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.slug, func(t *testing.T) {
+			err := ValidateSlug(tt.slug, 42)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidSlug)
+			}
+		})
+	}
+}
+
+func TestMetaDescription(t *testing.T) {
+	assert.Equal(t, "Written", MetaDescription("Written", "Description"))
+	assert.Equal(t, "Doors open at 7pm.", MetaDescription("", "  Doors open\n\nat 7pm. "))
+
+	long := MetaDescription("", strings.Repeat("word ", 60))
+	assert.Equal(t, strings.TrimSpace(strings.Repeat("word ", 32))+"…", long)
+	assert.Equal(t, MaxMetaDescriptionLength, utf8.RuneCountInString(long))
+}
+
+func TestSite_EventURL(t *testing.T) {
+	assert.Equal(t, "https://tixgo.example/events/summer-concert", Site{URL: "https://tixgo.example/"}.EventURL("summer-concert"))
+}
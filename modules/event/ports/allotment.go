@@ -0,0 +1,153 @@
+package ports
+
+import (
+	"net/http"
+	"strconv"
+
+	"tixgo/components"
+	"tixgo/modules/event/adapters"
+	"tixgo/modules/event/app/command"
+	"tixgo/modules/event/app/query"
+	organizerAdapters "tixgo/modules/organizer/adapters"
+	organizerQuery "tixgo/modules/organizer/app/query"
+	templateAdapters "tixgo/modules/template/adapters"
+	"tixgo/shared/httpresponse"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ListAllotments(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventID, userID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+
+		handler := query.NewListAllotmentsHandler(adapters.NewAllotmentPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), query.ListAllotmentsQuery{EventID: eventID, OrganizerID: userID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func CreateAllotment(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.CreateAllotmentCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		eventID, userID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+		req.EventID = eventID
+		req.OrganizerID = userID
+
+		handler := command.NewCreateAllotmentHandler(adapters.NewAllotmentPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusCreated, result)
+	}
+}
+
+func ResizeAllotment(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.ResizeAllotmentCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		allotmentID, err := strconv.ParseInt(c.Param("allotment_id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		eventID, userID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+		req.ID = allotmentID
+		req.EventID = eventID
+		req.OrganizerID = userID
+
+		handler := command.NewResizeAllotmentHandler(adapters.NewAllotmentPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func DeleteAllotment(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		allotmentID, err := strconv.ParseInt(c.Param("allotment_id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		eventID, userID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+
+		handler := command.NewDeleteAllotmentHandler(adapters.NewAllotmentPostgresRepository(appCtx.GetDB()))
+
+		err = handler.Handle(c.Request.Context(), command.DeleteAllotmentCommand{ID: allotmentID, EventID: eventID, OrganizerID: userID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, true)
+	}
+}
+
+func IssueComplimentaryTickets(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.IssueComplimentaryTicketsCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		eventID, userID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+		req.EventID = eventID
+		req.OrganizerID = userID
+
+		complimentaryRepo := adapters.NewComplimentaryTicketPostgresRepository(appCtx.GetDB())
+		templateRepo := templateAdapters.NewCachedTemplateRepository(templateAdapters.NewTemplatePostgresRepository(appCtx.GetDB()), appCtx.GetCache())
+		themes := organizerQuery.NewResolveThemeHandler(organizerAdapters.NewThemePostgresRepository(appCtx.GetDB()))
+		templateRenderer := templateAdapters.NewHTMLTemplateRenderer(appCtx.GetAssetService(), themes, templateAdapters.NewVariableDefaultPostgresRepository(appCtx.GetDB()))
+		handler := command.NewIssueComplimentaryTicketsHandler(complimentaryRepo, templateRepo, templateRenderer, appCtx.GetReliableEventBus())
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusCreated, result)
+	}
+}
@@ -0,0 +1,126 @@
+package ports
+
+import (
+	"net/http"
+	"strconv"
+
+	"tixgo/components"
+	"tixgo/modules/event/adapters"
+	"tixgo/modules/event/app/command"
+	"tixgo/modules/event/app/query"
+	organizerAdapters "tixgo/modules/organizer/adapters"
+	organizerQuery "tixgo/modules/organizer/app/query"
+	templateAdapters "tixgo/modules/template/adapters"
+	"tixgo/shared/httpresponse"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetAttendeeSettings(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventID, _, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+
+		handler := query.NewGetAttendeeSettingsHandler(adapters.NewAttendeeSettingsPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), query.GetAttendeeSettingsQuery{EventID: eventID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func UpdateAttendeeSettings(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.UpdateAttendeeSettingsCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		eventID, userID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+		req.EventID = eventID
+		req.OrganizerID = userID
+
+		handler := command.NewUpdateAttendeeSettingsHandler(adapters.NewAttendeeSettingsPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func AssignTicketAttendee(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.AssignTicketAttendeeCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		ticketID, err := strconv.ParseInt(c.Param("ticket_id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		eventID, userID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+		req.EventID = eventID
+		req.TicketID = ticketID
+		req.UserID = userID
+
+		attendeeRepo := adapters.NewAttendeePostgresRepository(appCtx.GetDB())
+		templateRepo := templateAdapters.NewCachedTemplateRepository(templateAdapters.NewTemplatePostgresRepository(appCtx.GetDB()), appCtx.GetCache())
+		themes := organizerQuery.NewResolveThemeHandler(organizerAdapters.NewThemePostgresRepository(appCtx.GetDB()))
+		templateRenderer := templateAdapters.NewHTMLTemplateRenderer(appCtx.GetAssetService(), themes, templateAdapters.NewVariableDefaultPostgresRepository(appCtx.GetDB()))
+		handler := command.NewAssignTicketAttendeeHandler(attendeeRepo, templateRepo, templateRenderer, appCtx.GetReliableEventBus())
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func UnassignTicketAttendee(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ticketID, err := strconv.ParseInt(c.Param("ticket_id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		eventID, userID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+
+		handler := command.NewUnassignTicketAttendeeHandler(adapters.NewAttendeePostgresRepository(appCtx.GetDB()))
+
+		err = handler.Handle(c.Request.Context(), command.UnassignTicketAttendeeCommand{EventID: eventID, TicketID: ticketID, UserID: userID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, true)
+	}
+}
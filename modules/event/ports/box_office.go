@@ -0,0 +1,46 @@
+package ports
+
+import (
+	"net/http"
+
+	"tixgo/components"
+	"tixgo/modules/event/adapters"
+	"tixgo/modules/event/app/command"
+	organizerAdapters "tixgo/modules/organizer/adapters"
+	organizerQuery "tixgo/modules/organizer/app/query"
+	templateAdapters "tixgo/modules/template/adapters"
+	"tixgo/shared/httpresponse"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SellBoxOfficeTickets(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.SellBoxOfficeTicketsCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		eventID, userID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+		req.EventID = eventID
+		req.SellerID = userID
+
+		boxOfficeRepo := adapters.NewBoxOfficePostgresRepository(appCtx.GetDB())
+		templateRepo := templateAdapters.NewCachedTemplateRepository(templateAdapters.NewTemplatePostgresRepository(appCtx.GetDB()), appCtx.GetCache())
+		themes := organizerQuery.NewResolveThemeHandler(organizerAdapters.NewThemePostgresRepository(appCtx.GetDB()))
+		templateRenderer := templateAdapters.NewHTMLTemplateRenderer(appCtx.GetAssetService(), themes, templateAdapters.NewVariableDefaultPostgresRepository(appCtx.GetDB()))
+		handler := command.NewSellBoxOfficeTicketsHandler(boxOfficeRepo, templateRepo, templateRenderer, appCtx.GetReliableEventBus())
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusCreated, result)
+	}
+}
@@ -0,0 +1,56 @@
+package ports
+
+import (
+	"context"
+
+	"tixgo/components"
+	"tixgo/modules/event/adapters"
+	eventHandler "tixgo/modules/event/app/event"
+	"tixgo/modules/event/domain"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/duongptryu/gox/messaging"
+)
+
+const (
+	EventSeatStatusChanged         = "events.EventSeatStatusChanged"
+	EventTicketAvailabilityChanged = "events.EventTicketAvailabilityChanged"
+	EventConfigurationChanged      = "events.EventConfigurationChanged"
+)
+
+type EventMessagingHandlers struct {
+	dispatcher messaging.Dispatcher
+	appCtx     components.AppContext
+}
+
+func NewEventMessagingHandlers(dispatcher messaging.Dispatcher, appCtx components.AppContext) *EventMessagingHandlers {
+	return &EventMessagingHandlers{
+		dispatcher: dispatcher,
+		appCtx:     appCtx,
+	}
+}
+
+func (h *EventMessagingHandlers) RegisterEventMessagingHandlers() {
+	eventProcessor := h.dispatcher.GetEventProcessor()
+	eventProcessor.AddHandler(cqrs.NewEventHandler(EventSeatStatusChanged, h.HandleEventSeatStatusChanged))
+	eventProcessor.AddHandler(cqrs.NewEventHandler(EventTicketAvailabilityChanged, h.HandleEventTicketAvailabilityChanged))
+	eventProcessor.AddHandler(cqrs.NewEventHandler(EventConfigurationChanged, h.HandleEventConfigurationChanged))
+}
+
+func (h *EventMessagingHandlers) HandleEventSeatStatusChanged(ctx context.Context, event *domain.EventSeatStatusChanged) error {
+	biz := eventHandler.NewBroadcastSeatStatus(adapters.NewSeatUpdatesRedis(h.appCtx.GetRedis()))
+
+	return biz.Broadcast(ctx, event)
+}
+
+func (h *EventMessagingHandlers) HandleEventTicketAvailabilityChanged(ctx context.Context, event *domain.EventTicketAvailabilityChanged) error {
+	biz := eventHandler.NewRefreshTicketAvailability(newQueueSettingsRepository(h.appCtx), adapters.NewOnSaleQueueRedis(h.appCtx.GetRedis()))
+
+	return biz.Refresh(ctx, event)
+}
+
+func (h *EventMessagingHandlers) HandleEventConfigurationChanged(ctx context.Context, event *domain.EventConfigurationChanged) error {
+	biz := eventHandler.NewRecordChange(adapters.NewEventChangePostgresRepository(h.appCtx.GetDB()))
+
+	return biz.Record(ctx, event)
+}
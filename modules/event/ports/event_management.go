@@ -0,0 +1,182 @@
+package ports
+
+import (
+	"net/http"
+
+	"tixgo/components"
+	"tixgo/modules/event/adapters"
+	"tixgo/modules/event/app/command"
+	"tixgo/modules/event/app/query"
+	"tixgo/shared/httpresponse"
+	"tixgo/shared/listing"
+	"tixgo/shared/session"
+
+	"github.com/duongptryu/gox/context"
+
+	"github.com/gin-gonic/gin"
+)
+
+func CreateEvent(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.CreateEventCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		organizerID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.OrganizerID = organizerID
+		req.TestMode = session.IsTestMode(c.Request.Context())
+
+		handler := command.NewCreateEventHandler(adapters.NewEventPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusCreated, result)
+	}
+}
+
+func ListEvents(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var paging listing.Paging
+		if err := c.ShouldBind(&paging); err != nil {
+			c.Error(err)
+			return
+		}
+
+		// Apply pagination defaults in HTTP layer
+		paging.Fulfill()
+
+		var filters query.ListEventsQuery
+		if err := c.ShouldBindQuery(&filters); err != nil {
+			c.Error(err)
+			return
+		}
+
+		organizerID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		filters.OrganizerID = organizerID
+
+		handler := query.NewListEventsHandler(adapters.NewEventPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), filters, &paging)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.List(c, result, paging, filters)
+	}
+}
+
+func GetEvent(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventID, organizerID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+
+		handler := query.NewGetEventHandler(adapters.NewEventPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), query.GetEventQuery{ID: eventID, OrganizerID: organizerID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func UpdateEvent(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.UpdateEventCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		eventID, organizerID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+		req.ID = eventID
+		req.OrganizerID = organizerID
+
+		handler := command.NewUpdateEventHandler(adapters.NewEventPostgresRepository(appCtx.GetDB()), appCtx.GetReliableEventBus())
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func PublishEvent(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventID, organizerID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+
+		handler := command.NewPublishEventHandler(adapters.NewEventPostgresRepository(appCtx.GetDB()), appCtx.GetReliableEventBus())
+
+		result, err := handler.Handle(c.Request.Context(), command.PublishEventCommand{ID: eventID, OrganizerID: organizerID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+// ListEventChanges lists the change feed of the event: who changed its configuration, what and when
+func ListEventChanges(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventID, userID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+
+		var paging listing.Paging
+		if err := c.ShouldBind(&paging); err != nil {
+			c.Error(err)
+			return
+		}
+
+		// Apply pagination defaults in HTTP layer
+		paging.Fulfill()
+
+		var filters query.ListEventChangesQuery
+		if err := c.ShouldBindQuery(&filters); err != nil {
+			c.Error(err)
+			return
+		}
+		filters.EventID, filters.OrganizerID = eventID, userID
+
+		handler := query.NewListEventChangesHandler(adapters.NewEventChangePostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), filters, &paging)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.List(c, result, paging, filters)
+	}
+}
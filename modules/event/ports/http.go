@@ -0,0 +1,378 @@
+package ports
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"tixgo/components"
+	"tixgo/modules/event/adapters"
+	"tixgo/modules/event/app/command"
+	"tixgo/modules/event/app/query"
+	"tixgo/modules/event/domain"
+	userDomain "tixgo/modules/user/domain"
+	"tixgo/shared/apiversion"
+	"tixgo/shared/authz"
+	"tixgo/shared/httpresponse"
+	"tixgo/shared/listing"
+	"tixgo/shared/session"
+	"tixgo/shared/stream"
+
+	"github.com/duongptryu/gox/context"
+
+	"github.com/gin-gonic/gin"
+)
+
+// publicEventCachePolicy keeps ticket availability at most a few seconds old while letting the CDN
+// absorb the traffic of on-sale moments
+var publicEventCachePolicy = httpresponse.CachePolicy{
+	MaxAge:               5 * time.Second,
+	StaleWhileRevalidate: 30 * time.Second,
+}
+
+func RegisterEventRoutes(router *apiversion.Group, appCtx components.AppContext, site domain.Site) {
+	publicGroup := router.Group("/public/events")
+	{
+		publicGroup.GET("", SearchEvents(appCtx))
+		publicGroup.GET("/:slug", GetPublicEvent(appCtx, site))
+		publicGroup.GET("/:slug/structured-data", GetEventStructuredData(appCtx, site))
+	}
+
+	managementGroup := router.Group("/events")
+	{
+		managementGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		managementGroup.Use(authz.RequireUserType(string(userDomain.UserTypeOrganizer)))
+		managementGroup.POST("", CreateEvent(appCtx))
+		managementGroup.GET("", ListEvents(appCtx))
+		managementGroup.GET("/:id", GetEvent(appCtx))
+		managementGroup.PUT("/:id", UpdateEvent(appCtx))
+		managementGroup.POST("/:id/publish", PublishEvent(appCtx))
+		managementGroup.GET("/:id/seo", GetEventSEO(appCtx, site))
+		managementGroup.PUT("/:id/seo", UpdateEventSEO(appCtx, site))
+	}
+
+	seatGroup := router.Group("/events/:id/seats")
+	{
+		seatGroup.GET("", GetSeatMap(appCtx))
+		seatGroup.GET("/stream", StreamSeatMap(appCtx))
+	}
+
+	queueGroup := router.Group("/events/:id/queue")
+	{
+		queueGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		queueGroup.POST("", JoinQueue(appCtx))
+		queueGroup.GET("/:token", GetQueueStatus(appCtx))
+		queueGroup.POST("/:token/reservations", ReserveTickets(appCtx))
+		queueGroup.DELETE("/:token", LeaveQueue(appCtx))
+	}
+
+	cancellationGroup := router.Group("/events/:id/cancellation")
+	{
+		cancellationGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		cancellationGroup.POST("", CancelEvent(appCtx))
+		cancellationGroup.GET("", GetEventCancellation(appCtx))
+	}
+
+	eventGroup := router.Group("/events/:id")
+	{
+		eventGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		eventGroup.POST("/duplicate", DuplicateEvent(appCtx))
+		eventGroup.GET("/changes", ListEventChanges(appCtx))
+		eventGroup.GET("/allotments", ListAllotments(appCtx))
+		eventGroup.POST("/allotments", CreateAllotment(appCtx))
+		eventGroup.PATCH("/allotments/:allotment_id", ResizeAllotment(appCtx))
+		eventGroup.DELETE("/allotments/:allotment_id", DeleteAllotment(appCtx))
+		eventGroup.POST("/complimentary-tickets", IssueComplimentaryTickets(appCtx))
+		eventGroup.POST("/box-office/orders", SellBoxOfficeTickets(appCtx))
+		eventGroup.GET("/questions", ListEventQuestions(appCtx))
+		eventGroup.POST("/questions", CreateEventQuestion(appCtx))
+		eventGroup.PUT("/questions/:question_id", UpdateEventQuestion(appCtx))
+		eventGroup.DELETE("/questions/:question_id", DeleteEventQuestion(appCtx))
+		eventGroup.PUT("/tickets/:ticket_id/answers", SubmitAttendeeAnswers(appCtx))
+		eventGroup.GET("/attendees", ListAttendees(appCtx))
+		eventGroup.GET("/attendee-settings", GetAttendeeSettings(appCtx))
+		eventGroup.PUT("/attendee-settings", UpdateAttendeeSettings(appCtx))
+		eventGroup.PUT("/tickets/:ticket_id/attendee", AssignTicketAttendee(appCtx))
+		eventGroup.DELETE("/tickets/:ticket_id/attendee", UnassignTicketAttendee(appCtx))
+		eventGroup.GET("/sales", GetSalesControls(appCtx))
+		eventGroup.GET("/sales/forecast", GetSalesForecast(appCtx))
+		eventGroup.POST("/sales/pause", SetSalesPaused(appCtx, true))
+		eventGroup.POST("/sales/resume", SetSalesPaused(appCtx, false))
+		eventGroup.PUT("/sales/pause-schedule", ScheduleSalesPause(appCtx))
+		eventGroup.POST("/ticket-categories/:ticket_category_id/sales/pause", SetSalesPaused(appCtx, true))
+		eventGroup.POST("/ticket-categories/:ticket_category_id/sales/resume", SetSalesPaused(appCtx, false))
+		eventGroup.PUT("/ticket-categories/:ticket_category_id/sales/pause-schedule", ScheduleSalesPause(appCtx))
+		eventGroup.PUT("/ticket-categories/:ticket_category_id/capacity", ResizeTicketCategory(appCtx))
+	}
+
+	templateGroup := router.Group("/event-templates")
+	{
+		templateGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		templateGroup.POST("", SaveEventTemplate(appCtx))
+		templateGroup.GET("", ListEventTemplates(appCtx))
+		templateGroup.GET("/:id", GetEventTemplate(appCtx))
+		templateGroup.DELETE("/:id", DeleteEventTemplate(appCtx))
+		templateGroup.POST("/:id/events", CreateEventFromTemplate(appCtx))
+	}
+}
+
+// GetPublicEvent serves a public event page, redirecting former slugs to the current one
+func GetPublicEvent(appCtx components.AppContext, site domain.Site) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		publicEventRepo := adapters.NewPublicEventPostgresRepository(appCtx.GetDB())
+		handler := query.NewGetPublicEventHandler(publicEventRepo, adapters.NewEventSEOPostgresRepository(appCtx.GetDB()), site)
+
+		result, err := handler.Handle(c.Request.Context(), query.GetPublicEventQuery{Slug: c.Param("slug")})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		if result.Slug != c.Param("slug") {
+			redirectToSlug(c, result.Slug)
+			return
+		}
+
+		httpresponse.Cacheable(c, result, publicEventCachePolicy)
+	}
+}
+
+func SearchEvents(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var paging listing.Paging
+		if err := c.ShouldBind(&paging); err != nil {
+			c.Error(err)
+			return
+		}
+
+		// Apply pagination defaults in HTTP layer
+		paging.Fulfill()
+
+		var filters query.SearchEventsQuery
+		if err := c.ShouldBindQuery(&filters); err != nil {
+			c.Error(err)
+			return
+		}
+
+		handler := query.NewSearchEventsHandler(adapters.NewPublicEventPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), filters, &paging)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.List(c, result, paging, filters)
+	}
+}
+
+func GetSeatMap(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		handler := query.NewGetSeatMapHandler(adapters.NewSeatMapPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), query.GetSeatMapQuery{EventID: eventID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+// StreamSeatMap sends the seat map as a "snapshot" server-sent event, then a "seat" event for
+// every seat whose status changes. Clients reconnecting get a new snapshot.
+func StreamSeatMap(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		// subscribe before loading the snapshot so that no change falls in between
+		changes, err := adapters.NewSeatUpdatesRedis(appCtx.GetRedis()).Subscribe(c.Request.Context(), eventID)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		handler := query.NewGetSeatMapHandler(adapters.NewSeatMapPostgresRepository(appCtx.GetDB()))
+
+		seatMap, err := handler.Handle(c.Request.Context(), query.GetSeatMapQuery{EventID: eventID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		stream.SSE(c, []stream.Event{{Name: "snapshot", Data: seatMap}}, changes, func(change *domain.EventSeatStatusChanged) stream.Event {
+			return stream.Event{Name: "seat", Data: query.ToSeatChangeResult(change)}
+		})
+	}
+}
+
+func JoinQueue(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventID, userID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+
+		handler := command.NewJoinQueueHandler(newQueueSettingsRepository(appCtx), adapters.NewOnSaleQueueRedis(appCtx.GetRedis()))
+
+		result, err := handler.Handle(c.Request.Context(), command.JoinQueueCommand{EventID: eventID, UserID: userID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func GetQueueStatus(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventID, userID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+
+		handler := query.NewGetQueueStatusHandler(newQueueSettingsRepository(appCtx), adapters.NewOnSaleQueueRedis(appCtx.GetRedis()))
+
+		result, err := handler.Handle(c.Request.Context(), query.GetQueueStatusQuery{
+			EventID: eventID,
+			UserID:  userID,
+			Token:   c.Param("token"),
+		})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func ReserveTickets(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.ReserveTicketsCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		eventID, userID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+		req.EventID = eventID
+		req.UserID = userID
+		req.Token = c.Param("token")
+
+		handler := command.NewReserveTicketsHandler(newQueueSettingsRepository(appCtx), adapters.NewOnSaleQueueRedis(appCtx.GetRedis()))
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusCreated, result)
+	}
+}
+
+func LeaveQueue(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventID, userID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+
+		handler := command.NewLeaveQueueHandler(adapters.NewOnSaleQueueRedis(appCtx.GetRedis()))
+
+		err := handler.Handle(c.Request.Context(), command.LeaveQueueCommand{
+			EventID: eventID,
+			UserID:  userID,
+			Token:   c.Param("token"),
+		})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, true)
+	}
+}
+
+func CancelEvent(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.CancelEventCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		eventID, userID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+		req.EventID = eventID
+		req.OrganizerID = userID
+
+		handler := command.NewCancelEventHandler(adapters.NewEventCancellationPostgresRepository(appCtx.GetDB()), newQueueSettingsRepository(appCtx), appCtx.GetReliableEventBus())
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusAccepted, result)
+	}
+}
+
+func GetEventCancellation(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventID, userID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+
+		handler := query.NewGetEventCancellationHandler(adapters.NewEventCancellationPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), query.GetEventCancellationQuery{EventID: eventID, OrganizerID: userID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+// authenticatedEventParams reads the event ID, or template ID, of the URL and the authenticated user; on failure the error
+// is recorded and ok is false
+func authenticatedEventParams(c *gin.Context) (eventID, userID int64, ok bool) {
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(err)
+		return 0, 0, false
+	}
+
+	userID, err = context.GetUserIDFromContextAsInt64(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return 0, 0, false
+	}
+
+	return eventID, userID, true
+}
+
+func newQueueSettingsRepository(appCtx components.AppContext) *adapters.CachedQueueSettingsRepository {
+	return adapters.NewCachedQueueSettingsRepository(adapters.NewQueueSettingsPostgresRepository(appCtx.GetDB()), appCtx.GetCache())
+}
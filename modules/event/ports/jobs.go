@@ -0,0 +1,46 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"tixgo/components"
+	"tixgo/modules/event/adapters"
+	"tixgo/modules/event/app/command"
+	organizerAdapters "tixgo/modules/organizer/adapters"
+	organizerQuery "tixgo/modules/organizer/app/query"
+	templateAdapters "tixgo/modules/template/adapters"
+	"tixgo/shared/scheduler"
+)
+
+const (
+	JobProcessEventCancellations = "event.process_event_cancellations"
+	JobApplyScheduledSalesPauses = "event.apply_scheduled_sales_pauses"
+)
+
+// Jobs returns the jobs of the event module
+func Jobs(appCtx components.AppContext) []scheduler.Job {
+	return []scheduler.Job{
+		{
+			Name:     JobProcessEventCancellations,
+			Schedule: "@every 1m",
+			Timeout:  10 * time.Minute,
+			Run: func(ctx context.Context) error {
+				cancellationRepo := adapters.NewEventCancellationPostgresRepository(appCtx.GetDB())
+				templateRepo := templateAdapters.NewCachedTemplateRepository(templateAdapters.NewTemplatePostgresRepository(appCtx.GetDB()), appCtx.GetCache())
+				themes := organizerQuery.NewResolveThemeHandler(organizerAdapters.NewThemePostgresRepository(appCtx.GetDB()))
+				templateRenderer := templateAdapters.NewHTMLTemplateRenderer(appCtx.GetAssetService(), themes, templateAdapters.NewVariableDefaultPostgresRepository(appCtx.GetDB()))
+				return command.NewProcessEventCancellationsHandler(cancellationRepo, templateRepo, templateRenderer, appCtx.GetReliableEventBus()).Handle(ctx)
+			},
+		},
+		{
+			Name:     JobApplyScheduledSalesPauses,
+			Schedule: "@every 1m",
+			Timeout:  time.Minute,
+			Run: func(ctx context.Context) error {
+				salesRepo := adapters.NewSalesControlPostgresRepository(appCtx.GetDB())
+				return command.NewApplyScheduledSalesPausesHandler(salesRepo, appCtx.GetReliableEventBus()).Handle(ctx)
+			},
+		},
+	}
+}
@@ -0,0 +1,195 @@
+package ports
+
+import (
+	"net/http"
+	"strconv"
+
+	"tixgo/components"
+	"tixgo/modules/event/adapters"
+	"tixgo/modules/event/app/command"
+	"tixgo/modules/event/app/query"
+	"tixgo/shared/httpresponse"
+	"tixgo/shared/listing"
+	"tixgo/shared/stream"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ListEventQuestions(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventID, _, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+
+		handler := query.NewListEventQuestionsHandler(adapters.NewEventQuestionPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), query.ListEventQuestionsQuery{EventID: eventID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func CreateEventQuestion(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.CreateEventQuestionCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		eventID, userID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+		req.EventID = eventID
+		req.OrganizerID = userID
+
+		handler := command.NewCreateEventQuestionHandler(adapters.NewEventQuestionPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusCreated, result)
+	}
+}
+
+func UpdateEventQuestion(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.UpdateEventQuestionCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		questionID, err := strconv.ParseInt(c.Param("question_id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		eventID, userID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+		req.ID = questionID
+		req.EventID = eventID
+		req.OrganizerID = userID
+
+		handler := command.NewUpdateEventQuestionHandler(adapters.NewEventQuestionPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func DeleteEventQuestion(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		questionID, err := strconv.ParseInt(c.Param("question_id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		eventID, userID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+
+		handler := command.NewDeleteEventQuestionHandler(adapters.NewEventQuestionPostgresRepository(appCtx.GetDB()))
+
+		err = handler.Handle(c.Request.Context(), command.DeleteEventQuestionCommand{ID: questionID, EventID: eventID, OrganizerID: userID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, true)
+	}
+}
+
+func SubmitAttendeeAnswers(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.SubmitAttendeeAnswersCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		ticketID, err := strconv.ParseInt(c.Param("ticket_id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		eventID, userID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+		req.EventID = eventID
+		req.TicketID = ticketID
+		req.UserID = userID
+
+		questionRepo := adapters.NewEventQuestionPostgresRepository(appCtx.GetDB())
+		attendeeRepo := adapters.NewAttendeePostgresRepository(appCtx.GetDB())
+		handler := command.NewSubmitAttendeeAnswersHandler(questionRepo, attendeeRepo)
+
+		if err := handler.Handle(c.Request.Context(), req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, true)
+	}
+}
+
+func ListAttendees(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventID, userID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+		q := query.ListAttendeesQuery{EventID: eventID, OrganizerID: userID}
+
+		handler := query.NewListAttendeesHandler(adapters.NewAttendeePostgresRepository(appCtx.GetDB()))
+
+		// Stream the whole manifest as NDJSON or CSV when asked for, ignoring paging
+		if format, ok := stream.Negotiate(c); ok {
+			stream.Write(c, format, "attendees-"+c.Param("id"), func(encode stream.EncodeFunc) error {
+				return handler.Stream(c.Request.Context(), q, func(item *query.AttendeeItem) error {
+					return encode(item)
+				})
+			})
+			return
+		}
+
+		var paging listing.Paging
+		if err := c.ShouldBind(&paging); err != nil {
+			c.Error(err)
+			return
+		}
+
+		// Apply pagination defaults in HTTP layer
+		paging.Fulfill()
+
+		result, err := handler.Handle(c.Request.Context(), q, &paging)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.List(c, result, paging, nil)
+	}
+}
@@ -0,0 +1,167 @@
+package ports
+
+import (
+	"net/http"
+	"strconv"
+
+	"tixgo/components"
+	"tixgo/modules/event/adapters"
+	"tixgo/modules/event/app/command"
+	"tixgo/modules/event/app/query"
+	"tixgo/modules/event/domain"
+	"tixgo/shared/httpresponse"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetSalesControls(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventID, userID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+
+		handler := query.NewGetSalesControlsHandler(adapters.NewSalesControlPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), query.GetSalesControlsQuery{EventID: eventID, OrganizerID: userID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+// GetSalesForecast projects the sales of the event with the velocity heuristic
+func GetSalesForecast(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventID, userID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+
+		handler := query.NewGetSalesForecastHandler(
+			adapters.NewSalesHistoryPostgresRepository(appCtx.GetDB()),
+			domain.NewVelocityForecaster(domain.DefaultForecastWindow),
+		)
+
+		result, err := handler.Handle(c.Request.Context(), query.GetSalesForecastQuery{EventID: eventID, OrganizerID: userID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+// SetSalesPaused pauses or resumes the sales of the event, or of the ticket category in the path
+func SetSalesPaused(appCtx components.AppContext, paused bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventID, userID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+		ticketCategoryID, ok := optionalTicketCategoryParam(c)
+		if !ok {
+			return
+		}
+
+		handler := command.NewSetSalesPausedHandler(adapters.NewSalesControlPostgresRepository(appCtx.GetDB()), appCtx.GetReliableEventBus())
+
+		result, err := handler.Handle(c.Request.Context(), command.SetSalesPausedCommand{
+			EventID:          eventID,
+			TicketCategoryID: ticketCategoryID,
+			OrganizerID:      userID,
+			Paused:           paused,
+		})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func ScheduleSalesPause(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.ScheduleSalesPauseCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		eventID, userID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+		ticketCategoryID, ok := optionalTicketCategoryParam(c)
+		if !ok {
+			return
+		}
+		req.EventID = eventID
+		req.TicketCategoryID = ticketCategoryID
+		req.OrganizerID = userID
+
+		handler := command.NewScheduleSalesPauseHandler(adapters.NewSalesControlPostgresRepository(appCtx.GetDB()), appCtx.GetReliableEventBus())
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func ResizeTicketCategory(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.ResizeTicketCategoryCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		ticketCategoryID, err := strconv.ParseInt(c.Param("ticket_category_id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		eventID, userID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+		req.EventID = eventID
+		req.TicketCategoryID = ticketCategoryID
+		req.OrganizerID = userID
+
+		handler := command.NewResizeTicketCategoryHandler(adapters.NewSalesControlPostgresRepository(appCtx.GetDB()), appCtx.GetReliableEventBus())
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+// optionalTicketCategoryParam returns the ticket category in the path, 0 on the routes of the whole event
+func optionalTicketCategoryParam(c *gin.Context) (int64, bool) {
+	param := c.Param("ticket_category_id")
+	if param == "" {
+		return 0, true
+	}
+
+	ticketCategoryID, err := strconv.ParseInt(param, 10, 64)
+	if err != nil {
+		c.Error(err)
+		return 0, false
+	}
+	return ticketCategoryID, true
+}
@@ -0,0 +1,45 @@
+package ports
+
+import (
+	"tixgo/modules/event/app/command"
+	"tixgo/modules/event/app/query"
+	"tixgo/shared/jsonschema"
+	"tixgo/shared/listing"
+)
+
+// Schemas returns the request payloads of the event module
+func Schemas() []jsonschema.Payload {
+	return []jsonschema.Payload{
+		{Name: "events.create", In: jsonschema.Body, Example: command.CreateEventCommand{}},
+		{Name: "events.update", In: jsonschema.Body, Example: command.UpdateEventCommand{}},
+		{Name: "events.seo.update", In: jsonschema.Body, Example: command.UpdateEventSEOCommand{}},
+		{Name: "events.list", In: jsonschema.Query, Example: struct {
+			query.ListEventsQuery
+			listing.Paging
+		}{}},
+		{Name: "events.changes.list", In: jsonschema.Query, Example: struct {
+			query.ListEventChangesQuery
+			listing.Paging
+		}{}},
+		{Name: "public.events.search", In: jsonschema.Query, Example: struct {
+			query.SearchEventsQuery
+			listing.Paging
+		}{}},
+		{Name: "events.queue.reserve", In: jsonschema.Body, Example: command.ReserveTicketsCommand{}},
+		{Name: "events.cancellation.create", In: jsonschema.Body, Example: command.CancelEventCommand{}},
+		{Name: "events.duplicate", In: jsonschema.Body, Example: command.DuplicateEventCommand{}},
+		{Name: "events.allotments.create", In: jsonschema.Body, Example: command.CreateAllotmentCommand{}},
+		{Name: "events.allotments.resize", In: jsonschema.Body, Example: command.ResizeAllotmentCommand{}},
+		{Name: "events.complimentary-tickets.issue", In: jsonschema.Body, Example: command.IssueComplimentaryTicketsCommand{}},
+		{Name: "events.box-office.orders.create", In: jsonschema.Body, Example: command.SellBoxOfficeTicketsCommand{}},
+		{Name: "events.questions.create", In: jsonschema.Body, Example: command.CreateEventQuestionCommand{}},
+		{Name: "events.questions.update", In: jsonschema.Body, Example: command.UpdateEventQuestionCommand{}},
+		{Name: "events.tickets.answers.submit", In: jsonschema.Body, Example: command.SubmitAttendeeAnswersCommand{}},
+		{Name: "events.attendee-settings.update", In: jsonschema.Body, Example: command.UpdateAttendeeSettingsCommand{}},
+		{Name: "events.tickets.attendee.assign", In: jsonschema.Body, Example: command.AssignTicketAttendeeCommand{}},
+		{Name: "events.sales.pause-schedule", In: jsonschema.Body, Example: command.ScheduleSalesPauseCommand{}},
+		{Name: "events.ticket-categories.capacity", In: jsonschema.Body, Example: command.ResizeTicketCategoryCommand{}},
+		{Name: "event-templates.create", In: jsonschema.Body, Example: command.SaveEventTemplateCommand{}},
+		{Name: "event-templates.events.create", In: jsonschema.Body, Example: command.CreateEventFromTemplateCommand{}},
+	}
+}
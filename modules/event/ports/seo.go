@@ -0,0 +1,133 @@
+package ports
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"tixgo/components"
+	"tixgo/modules/event/adapters"
+	"tixgo/modules/event/app/command"
+	"tixgo/modules/event/app/query"
+	"tixgo/modules/event/domain"
+	"tixgo/shared/httpresponse"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sitemapCachePolicy lets crawlers and the CDN reuse the sitemap for a while; new pages wait for the
+// next crawl anyway
+var sitemapCachePolicy = httpresponse.CachePolicy{
+	MaxAge:               time.Hour,
+	StaleWhileRevalidate: time.Hour,
+}
+
+func GetEventSEO(appCtx components.AppContext, site domain.Site) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventID, userID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+
+		handler := query.NewGetEventSEOHandler(adapters.NewEventSEOPostgresRepository(appCtx.GetDB()), site)
+
+		result, err := handler.Handle(c.Request.Context(), query.GetEventSEOQuery{EventID: eventID, OrganizerID: userID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func UpdateEventSEO(appCtx components.AppContext, site domain.Site) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.UpdateEventSEOCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		eventID, userID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+		req.EventID = eventID
+		req.OrganizerID = userID
+
+		handler := command.NewUpdateEventSEOHandler(adapters.NewEventSEOPostgresRepository(appCtx.GetDB()), site, appCtx.GetReliableEventBus())
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+// GetEventStructuredData serves the JSON-LD of a public event page, redirecting former slugs
+func GetEventStructuredData(appCtx components.AppContext, site domain.Site) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		handler := query.NewGetEventStructuredDataHandler(
+			adapters.NewPublicEventPostgresRepository(appCtx.GetDB()),
+			adapters.NewEventSEOPostgresRepository(appCtx.GetDB()),
+			site,
+		)
+
+		result, err := handler.Handle(c.Request.Context(), query.GetEventStructuredDataQuery{Slug: c.Param("slug")})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		if result.Slug != c.Param("slug") {
+			redirectToSlug(c, result.Slug)
+			return
+		}
+
+		body, err := json.Marshal(result)
+		if err != nil {
+			c.Error(fmt.Errorf("failed to encode structured data: %w", err))
+			return
+		}
+
+		httpresponse.CacheableData(c, "application/ld+json", body, publicEventCachePolicy)
+	}
+}
+
+// Sitemap serves the sitemap of the public event pages of the site
+func Sitemap(appCtx components.AppContext, site domain.Site) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		handler := query.NewGetSitemapHandler(adapters.NewEventSEOPostgresRepository(appCtx.GetDB()), site)
+
+		result, err := handler.Handle(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		body, err := xml.Marshal(result)
+		if err != nil {
+			c.Error(fmt.Errorf("failed to encode sitemap: %w", err))
+			return
+		}
+
+		httpresponse.CacheableData(c, "application/xml; charset=utf-8", append([]byte(xml.Header), body...), sitemapCachePolicy)
+	}
+}
+
+// redirectToSlug permanently redirects a request for a former slug of a public page to its current
+// slug, keeping the rest of the path and the query
+func redirectToSlug(c *gin.Context, slug string) {
+	location := strings.Replace(c.FullPath(), ":slug", url.PathEscape(slug), 1)
+	if c.Request.URL.RawQuery != "" {
+		location += "?" + c.Request.URL.RawQuery
+	}
+	c.Redirect(http.StatusMovedPermanently, location)
+}
@@ -0,0 +1,181 @@
+package ports
+
+import (
+	"net/http"
+	"strconv"
+
+	"tixgo/components"
+	"tixgo/modules/event/adapters"
+	"tixgo/modules/event/app/command"
+	"tixgo/modules/event/app/query"
+	"tixgo/shared/httpresponse"
+	"tixgo/shared/listing"
+	"tixgo/shared/session"
+
+	"github.com/duongptryu/gox/context"
+
+	"github.com/gin-gonic/gin"
+)
+
+func DuplicateEvent(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.DuplicateEventCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		eventID, userID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+		req.EventID = eventID
+		req.OrganizerID = userID
+		req.TestMode = session.IsTestMode(c.Request.Context())
+
+		handler := command.NewDuplicateEventHandler(adapters.NewEventBlueprintPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusCreated, result)
+	}
+}
+
+func SaveEventTemplate(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.SaveEventTemplateCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		organizerID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.OrganizerID = organizerID
+
+		handler := command.NewSaveEventTemplateHandler(
+			adapters.NewEventBlueprintPostgresRepository(appCtx.GetDB()),
+			adapters.NewEventTemplatePostgresRepository(appCtx.GetDB()),
+		)
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusCreated, result)
+	}
+}
+
+func ListEventTemplates(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var paging listing.Paging
+		if err := c.ShouldBind(&paging); err != nil {
+			c.Error(err)
+			return
+		}
+
+		// Apply pagination defaults in HTTP layer
+		paging.Fulfill()
+
+		organizerID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		handler := query.NewListEventTemplatesHandler(adapters.NewEventTemplatePostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), query.ListEventTemplatesQuery{OrganizerID: organizerID}, &paging)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.List(c, result, paging, nil)
+	}
+}
+
+func GetEventTemplate(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		templateID, organizerID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+
+		handler := query.NewGetEventTemplateHandler(adapters.NewEventTemplatePostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), query.GetEventTemplateQuery{ID: templateID, OrganizerID: organizerID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func DeleteEventTemplate(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		templateID, organizerID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+
+		handler := command.NewDeleteEventTemplateHandler(adapters.NewEventTemplatePostgresRepository(appCtx.GetDB()))
+
+		err := handler.Handle(c.Request.Context(), command.DeleteEventTemplateCommand{ID: templateID, OrganizerID: organizerID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, true)
+	}
+}
+
+func CreateEventFromTemplate(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.CreateEventFromTemplateCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		templateID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.TemplateID = templateID
+
+		organizerID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.OrganizerID = organizerID
+		req.TestMode = session.IsTestMode(c.Request.Context())
+
+		handler := command.NewCreateEventFromTemplateHandler(
+			adapters.NewEventTemplatePostgresRepository(appCtx.GetDB()),
+			adapters.NewEventBlueprintPostgresRepository(appCtx.GetDB()),
+		)
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusCreated, result)
+	}
+}
@@ -0,0 +1,132 @@
+package adapters
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/notification/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/pgerr"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// DigestPostgresRepository implements the DigestRepository interface using PostgreSQL
+type DigestPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewDigestPostgresRepository creates a new PostgreSQL digest repository
+func NewDigestPostgresRepository(db *sqlx.DB) *DigestPostgresRepository {
+	return &DigestPostgresRepository{db: db}
+}
+
+// AddItem queues a notification for the digest of its user, ErrRecipientNotFound if the user is gone
+func (r *DigestPostgresRepository) AddItem(ctx context.Context, item *domain.DigestItem) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		INSERT INTO notification_digest_items (user_id, category, title, summary, url)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''))
+		RETURNING id, created_at`
+
+	err := r.db.QueryRowContext(ctx, query, item.UserID, item.Category, item.Title, item.Summary, item.URL).
+		Scan(&item.ID, &item.CreatedAt)
+	if err != nil {
+		if pgerr.IsForeignKeyViolation(err) {
+			return domain.ErrRecipientNotFound
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to add digest item")
+	}
+
+	return nil
+}
+
+// ListDue retrieves the digests of up to limit users whose oldest pending notification waited a full period
+func (r *DigestPostgresRepository) ListDue(ctx context.Context, now time.Time, limit, maxItems int) ([]*domain.Digest, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	// users who turned digests off since their notifications were queued get them at once
+	query := `
+		WITH due AS (
+			SELECT i.user_id, COALESCE(p.digest_frequency, $2) AS frequency
+			FROM notification_digest_items i
+			LEFT JOIN notification_preferences p ON p.user_id = i.user_id
+			WHERE i.digested_at IS NULL
+			GROUP BY i.user_id, p.digest_frequency
+			HAVING MIN(i.created_at) <= CASE COALESCE(p.digest_frequency, $2)
+			                                WHEN 'weekly' THEN $3::TIMESTAMPTZ
+			                                WHEN 'daily' THEN $4::TIMESTAMPTZ
+			                                ELSE $1::TIMESTAMPTZ
+			                            END
+			ORDER BY MIN(i.created_at)
+			LIMIT $5
+		)
+		SELECT d.user_id, u.email, d.frequency, i.id, i.category, i.title, i.summary, COALESCE(i.url, ''), i.created_at
+		FROM due d
+		JOIN users u ON u.id = d.user_id
+		JOIN LATERAL (
+			SELECT id, category, title, summary, url, created_at
+			FROM notification_digest_items
+			WHERE user_id = d.user_id AND digested_at IS NULL
+			ORDER BY created_at, id
+			LIMIT $6
+		) i ON TRUE
+		ORDER BY d.user_id, i.created_at, i.id`
+
+	rows, err := r.db.QueryContext(ctx, query,
+		domain.DigestFrequencyOff.DueBefore(now),
+		domain.DefaultDigestFrequency,
+		domain.DigestFrequencyWeekly.DueBefore(now),
+		domain.DigestFrequencyDaily.DueBefore(now),
+		limit,
+		maxItems,
+	)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list due digests")
+	}
+	defer rows.Close()
+
+	var digests []*domain.Digest
+	var digest *domain.Digest
+	for rows.Next() {
+		var userID int64
+		var email string
+		var frequency domain.DigestFrequency
+		item := &domain.DigestItem{}
+		err := rows.Scan(&userID, &email, &frequency, &item.ID, &item.Category, &item.Title, &item.Summary, &item.URL, &item.CreatedAt)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan digest item")
+		}
+
+		if digest == nil || digest.UserID != userID {
+			digest = &domain.Digest{UserID: userID, Email: email, Frequency: frequency}
+			digests = append(digests, digest)
+		}
+		item.UserID = userID
+		digest.Items = append(digest.Items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to iterate digest items")
+	}
+
+	return digests, nil
+}
+
+// MarkDigested records that notifications were mailed
+func (r *DigestPostgresRepository) MarkDigested(ctx context.Context, itemIDs []int64, at time.Time) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, `UPDATE notification_digest_items SET digested_at = $2 WHERE id = ANY($1)`, pq.Array(itemIDs), at)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to mark digest items")
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+
+	"tixgo/modules/notification/domain"
+	"tixgo/shared/dbtimeout"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+// PreferencePostgresRepository implements the PreferenceRepository interface using PostgreSQL
+type PreferencePostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewPreferencePostgresRepository creates a new PostgreSQL preference repository
+func NewPreferencePostgresRepository(db *sqlx.DB) *PreferencePostgresRepository {
+	return &PreferencePostgresRepository{db: db}
+}
+
+// GetByUserID retrieves the preference of a user, the default one if they never set it
+func (r *PreferencePostgresRepository) GetByUserID(ctx context.Context, userID int64) (*domain.Preference, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT user_id, digest_frequency, updated_at
+		FROM notification_preferences
+		WHERE user_id = $1`
+
+	preference := &domain.Preference{}
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&preference.UserID, &preference.DigestFrequency, &preference.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &domain.Preference{UserID: userID, DigestFrequency: domain.DefaultDigestFrequency}, nil
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get notification preference")
+	}
+
+	return preference, nil
+}
+
+// Save creates or replaces the preference of a user
+func (r *PreferencePostgresRepository) Save(ctx context.Context, preference *domain.Preference) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		INSERT INTO notification_preferences (user_id, digest_frequency)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET digest_frequency = EXCLUDED.digest_frequency, updated_at = NOW()
+		RETURNING updated_at`
+
+	err := r.db.QueryRowContext(ctx, query, preference.UserID, preference.DigestFrequency).Scan(&preference.UpdatedAt)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to save notification preference")
+	}
+
+	return nil
+}
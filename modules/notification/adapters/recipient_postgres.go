@@ -0,0 +1,39 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+
+	"tixgo/modules/notification/domain"
+	"tixgo/shared/dbtimeout"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+// RecipientPostgresRepository implements the RecipientRepository interface using PostgreSQL
+type RecipientPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewRecipientPostgresRepository creates a new PostgreSQL recipient repository
+func NewRecipientPostgresRepository(db *sqlx.DB) *RecipientPostgresRepository {
+	return &RecipientPostgresRepository{db: db}
+}
+
+// GetEmail retrieves the email address of a user
+func (r *RecipientPostgresRepository) GetEmail(ctx context.Context, userID int64) (string, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var email string
+	err := r.db.QueryRowContext(ctx, `SELECT email FROM users WHERE id = $1`, userID).Scan(&email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", domain.ErrRecipientNotFound
+		}
+		return "", syserr.Wrap(err, syserr.InternalCode, "failed to get recipient")
+	}
+
+	return email, nil
+}
@@ -0,0 +1,120 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/notification/domain"
+	templateDomain "tixgo/modules/template/domain"
+	sharedMail "tixgo/shared/events/mail"
+	sharedKafka "tixgo/shared/kafka"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/notification/mail"
+	"github.com/duongptryu/gox/syserr"
+)
+
+const (
+	SlugNotificationDigest = "notification-digest"
+
+	// digestBatchSize is how many users get their digest per batch
+	digestBatchSize = 100
+	// digestMaxItems bounds the notifications of one digest, the rest go in the next one
+	digestMaxItems = 50
+)
+
+// SendDigestsHandler mails the due notification digests
+type SendDigestsHandler struct {
+	digestRepo       domain.DigestRepository
+	templateRepo     templateDomain.TemplateRepository
+	templateRenderer templateDomain.TemplateRenderer
+	eventBus         messaging.EventBus
+}
+
+// NewSendDigestsHandler creates a new send digests handler
+func NewSendDigestsHandler(digestRepo domain.DigestRepository, templateRepo templateDomain.TemplateRepository, templateRenderer templateDomain.TemplateRenderer, eventBus messaging.EventBus) *SendDigestsHandler {
+	return &SendDigestsHandler{
+		digestRepo:       digestRepo,
+		templateRepo:     templateRepo,
+		templateRenderer: templateRenderer,
+		eventBus:         eventBus,
+	}
+}
+
+// Handle sends the due digests batch by batch. A digest is marked only once its mail is published, so a
+// crash in between sends it twice rather than never. Failed digests are logged and left for the next run.
+func (h *SendDigestsHandler) Handle(ctx context.Context) error {
+	template, err := h.templateRepo.GetBySlug(ctx, SlugNotificationDigest)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get template")
+	}
+
+	sent := 0
+	for ctx.Err() == nil {
+		now := time.Now()
+		digests, err := h.digestRepo.ListDue(ctx, now, digestBatchSize, digestMaxItems)
+		if err != nil {
+			return syserr.Wrap(err, syserr.InternalCode, "failed to list due digests")
+		}
+
+		failed := 0
+		for _, digest := range digests {
+			if err := h.send(ctx, template, digest, now); err != nil {
+				logger.Error(ctx, "Failed to send notification digest", logger.F("user_id", digest.UserID), logger.F("error", err))
+				failed++
+				continue
+			}
+			sent++
+		}
+
+		// failed digests would be listed again, leave them to the next run
+		if len(digests) < digestBatchSize || failed > 0 {
+			break
+		}
+	}
+
+	if sent > 0 {
+		logger.Info(ctx, "Sent notification digests", logger.F("count", sent))
+	}
+	return nil
+}
+
+func (h *SendDigestsHandler) send(ctx context.Context, template *templateDomain.Template, digest *domain.Digest, now time.Time) error {
+	items := make([]map[string]interface{}, len(digest.Items))
+	for i, item := range digest.Items {
+		items[i] = map[string]interface{}{
+			"category":   item.Category,
+			"title":      item.Title,
+			"summary":    item.Summary,
+			"url":        item.URL,
+			"created_at": item.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		}
+	}
+
+	rendered, err := h.templateRenderer.Render(ctx, template, map[string]interface{}{
+		"items":     items,
+		"count":     len(items),
+		"frequency": string(digest.Frequency),
+	}, templateDomain.RenderOptions{})
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to render template")
+	}
+
+	err = h.eventBus.PublishEvent(sharedKafka.WithPartitionKey(ctx, digest.Email), &sharedMail.EventSendMail{
+		ToMail: []mail.EmailAddress{
+			{
+				Email: digest.Email,
+				Name:  "",
+			},
+		},
+		Subject:  rendered.Subject,
+		HTMLBody: rendered.Content,
+		Priority: mail.PriorityLow,
+	})
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to publish send mail event")
+	}
+
+	return h.digestRepo.MarkDigested(ctx, digest.ItemIDs(), now)
+}
@@ -0,0 +1,173 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"tixgo/modules/notification/domain"
+	templateAdapters "tixgo/modules/template/adapters"
+	templateDomain "tixgo/modules/template/domain"
+	sharedMail "tixgo/shared/events/mail"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/notification/mail"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init(&logger.Config{Output: io.Discard})
+	os.Exit(m.Run())
+}
+
+// listedDigests hands out its batches of due digests in turn and keeps the items marked
+type listedDigests struct {
+	domain.DigestRepository
+	batches  [][]*domain.Digest
+	listedAt []time.Time
+	limits   [][2]int
+	marked   map[int64]time.Time
+}
+
+func (r *listedDigests) ListDue(_ context.Context, now time.Time, limit, maxItems int) ([]*domain.Digest, error) {
+	r.listedAt = append(r.listedAt, now)
+	r.limits = append(r.limits, [2]int{limit, maxItems})
+	if len(r.batches) == 0 {
+		return nil, nil
+	}
+	batch := r.batches[0]
+	r.batches = r.batches[1:]
+	return batch, nil
+}
+
+func (r *listedDigests) MarkDigested(_ context.Context, itemIDs []int64, at time.Time) error {
+	for _, id := range itemIDs {
+		r.marked[id] = at
+	}
+	return nil
+}
+
+// digestTemplateRepository serves the digest template
+type digestTemplateRepository struct {
+	templateDomain.TemplateRepository
+}
+
+func (digestTemplateRepository) GetBySlug(_ context.Context, slug string) (*templateDomain.Template, error) {
+	if slug != SlugNotificationDigest {
+		return nil, templateDomain.ErrTemplateNotFound
+	}
+	return &templateDomain.Template{
+		Slug:    slug,
+		Subject: "Your {{.frequency}} digest: {{.count}} updates",
+		Content: `{{range .items}}<li>{{.category}}: {{.title}} at {{.created_at}}</li>{{end}}`,
+	}, nil
+}
+
+// recordingBus keeps the mails it is asked to publish, failing the ones to failTo
+type recordingBus struct {
+	mails  []*sharedMail.EventSendMail
+	failTo string
+}
+
+func (b *recordingBus) PublishEvent(_ context.Context, event any) error {
+	sent := event.(*sharedMail.EventSendMail)
+	if sent.ToMail[0].Email == b.failTo {
+		return errors.New("broker unavailable")
+	}
+	b.mails = append(b.mails, sent)
+	return nil
+}
+
+func newDigest(userID int64, email string, itemIDs ...int64) *domain.Digest {
+	digest := &domain.Digest{UserID: userID, Email: email, Frequency: domain.DigestFrequencyDaily}
+	for i, id := range itemIDs {
+		digest.Items = append(digest.Items, &domain.DigestItem{
+			ID:        id,
+			UserID:    userID,
+			Category:  "price_drop",
+			Title:     "Jazz Night",
+			CreatedAt: time.Date(2026, 10, 15, 9, i, 0, 0, time.UTC),
+		})
+	}
+	return digest
+}
+
+func newDigestFixture(batches ...[]*domain.Digest) (*SendDigestsHandler, *listedDigests, *recordingBus) {
+	repo := &listedDigests{batches: batches, marked: map[int64]time.Time{}}
+	bus := &recordingBus{}
+	handler := NewSendDigestsHandler(repo, digestTemplateRepository{}, templateAdapters.NewHTMLTemplateRenderer(nil, nil, nil), bus)
+	return handler, repo, bus
+}
+
+func TestSendDigestsHandler(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("mails the due digests and marks their items at the time they were listed", func(t *testing.T) {
+		handler, repo, bus := newDigestFixture([]*domain.Digest{newDigest(1, "fan@example.com", 11, 12)})
+
+		require.NoError(t, handler.Handle(ctx))
+
+		require.Len(t, bus.mails, 1)
+		assert.Equal(t, "fan@example.com", bus.mails[0].ToMail[0].Email)
+		assert.Equal(t, "Your daily digest: 2 updates", bus.mails[0].Subject)
+		assert.Equal(t, "<li>price_drop: Jazz Night at 2026-10-15T09:00:00Z</li><li>price_drop: Jazz Night at 2026-10-15T09:01:00Z</li>", bus.mails[0].HTMLBody)
+		assert.Equal(t, mail.PriorityLow, bus.mails[0].Priority)
+
+		assert.Equal(t, [][2]int{{digestBatchSize, digestMaxItems}}, repo.limits)
+		require.Len(t, repo.listedAt, 1)
+		assert.Equal(t, map[int64]time.Time{11: repo.listedAt[0], 12: repo.listedAt[0]}, repo.marked,
+			"the items are marked at the time of the window they were listed in")
+	})
+
+	t.Run("leaves the items of a failed digest for the next run", func(t *testing.T) {
+		batch := make([]*domain.Digest, digestBatchSize)
+		for i := range batch {
+			batch[i] = newDigest(int64(i+1), "fan@example.com", int64(i+1)*10)
+		}
+		batch[0].Email = "bounced@example.com"
+		handler, repo, bus := newDigestFixture(batch, []*domain.Digest{newDigest(500, "late@example.com", 5000)})
+		bus.failTo = "bounced@example.com"
+
+		require.NoError(t, handler.Handle(ctx))
+
+		assert.Len(t, bus.mails, digestBatchSize-1)
+		assert.NotContains(t, repo.marked, int64(10))
+		assert.Len(t, repo.marked, digestBatchSize-1)
+		assert.Len(t, repo.listedAt, 1, "the failed digest would be listed again, the run stops")
+	})
+
+	t.Run("lists the next window while batches are full", func(t *testing.T) {
+		batch := make([]*domain.Digest, digestBatchSize)
+		for i := range batch {
+			batch[i] = newDigest(int64(i+1), "fan@example.com", int64(i+1)*10)
+		}
+		handler, repo, bus := newDigestFixture(batch, []*domain.Digest{newDigest(500, "late@example.com", 5000)})
+
+		require.NoError(t, handler.Handle(ctx))
+
+		assert.Len(t, bus.mails, digestBatchSize+1)
+		require.Len(t, repo.listedAt, 2)
+		assert.False(t, repo.listedAt[1].Before(repo.listedAt[0]))
+		assert.Equal(t, repo.listedAt[1], repo.marked[5000])
+	})
+
+	t.Run("fails without the template", func(t *testing.T) {
+		repo := &listedDigests{marked: map[int64]time.Time{}}
+		handler := NewSendDigestsHandler(repo, missingTemplates{}, nil, &recordingBus{})
+		assert.Error(t, handler.Handle(ctx))
+		assert.Empty(t, repo.listedAt)
+	})
+}
+
+// missingTemplates has no template
+type missingTemplates struct {
+	templateDomain.TemplateRepository
+}
+
+func (missingTemplates) GetBySlug(context.Context, string) (*templateDomain.Template, error) {
+	return nil, templateDomain.ErrTemplateNotFound
+}
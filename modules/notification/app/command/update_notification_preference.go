@@ -0,0 +1,62 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/notification/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// UpdateNotificationPreferenceCommand represents the command to change how a user gets notified
+type UpdateNotificationPreferenceCommand struct {
+	UserID          int64                  `json:"-"`
+	DigestFrequency domain.DigestFrequency `json:"digest_frequency" binding:"required"`
+}
+
+// NotificationPreferenceResult represents the notification preference of a user
+type NotificationPreferenceResult struct {
+	DigestFrequency string `json:"digest_frequency"`
+	UpdatedAt       string `json:"updated_at,omitempty"`
+}
+
+// ToNotificationPreferenceResult converts a preference to its result
+func ToNotificationPreferenceResult(preference *domain.Preference) *NotificationPreferenceResult {
+	result := &NotificationPreferenceResult{
+		DigestFrequency: string(preference.DigestFrequency),
+	}
+	if !preference.UpdatedAt.IsZero() {
+		result.UpdatedAt = preference.UpdatedAt.Format("2006-01-02T15:04:05Z")
+	}
+	return result
+}
+
+// UpdateNotificationPreferenceHandler handles notification preference updates
+type UpdateNotificationPreferenceHandler struct {
+	preferenceRepo domain.PreferenceRepository
+}
+
+// NewUpdateNotificationPreferenceHandler creates a new update notification preference handler
+func NewUpdateNotificationPreferenceHandler(preferenceRepo domain.PreferenceRepository) *UpdateNotificationPreferenceHandler {
+	return &UpdateNotificationPreferenceHandler{
+		preferenceRepo: preferenceRepo,
+	}
+}
+
+// Handle saves the preference. Notifications already queued are sent with the next digest of the new
+// frequency, or right away when digests are turned off.
+func (h *UpdateNotificationPreferenceHandler) Handle(ctx context.Context, cmd UpdateNotificationPreferenceCommand) (*NotificationPreferenceResult, error) {
+	if !cmd.DigestFrequency.IsValid() {
+		return nil, domain.ErrInvalidDigestFrequency
+	}
+
+	preference := &domain.Preference{
+		UserID:          cmd.UserID,
+		DigestFrequency: cmd.DigestFrequency,
+	}
+	if err := h.preferenceRepo.Save(ctx, preference); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to save notification preference")
+	}
+
+	return ToNotificationPreferenceResult(preference), nil
+}
@@ -0,0 +1,85 @@
+package event
+
+import (
+	"context"
+
+	"tixgo/modules/notification/domain"
+	sharedMail "tixgo/shared/events/mail"
+	sharedNotification "tixgo/shared/events/notification"
+	sharedKafka "tixgo/shared/kafka"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/notification/mail"
+	"github.com/duongptryu/gox/syserr"
+)
+
+type queueNotification struct {
+	preferenceRepo domain.PreferenceRepository
+	digestRepo     domain.DigestRepository
+	recipientRepo  domain.RecipientRepository
+	eventBus       messaging.EventBus
+}
+
+func NewQueueNotification(preferenceRepo domain.PreferenceRepository, digestRepo domain.DigestRepository, recipientRepo domain.RecipientRepository, eventBus messaging.EventBus) *queueNotification {
+	return &queueNotification{
+		preferenceRepo: preferenceRepo,
+		digestRepo:     digestRepo,
+		recipientRepo:  recipientRepo,
+		eventBus:       eventBus,
+	}
+}
+
+// Queue batches a low priority notification into the digest of its user, or mails it at once if it is
+// not low priority or the user turned digests off
+func (h *queueNotification) Queue(ctx context.Context, event *sharedNotification.EventNotificationRequested) error {
+	if event.Priority == mail.PriorityLow {
+		preference, err := h.preferenceRepo.GetByUserID(ctx, event.UserID)
+		if err != nil {
+			return syserr.Wrap(err, syserr.InternalCode, "failed to get notification preference")
+		}
+
+		if preference.DigestFrequency != domain.DigestFrequencyOff {
+			err := h.digestRepo.AddItem(ctx, &domain.DigestItem{
+				UserID:   event.UserID,
+				Category: event.Category,
+				Title:    event.Title,
+				Summary:  event.Summary,
+				URL:      event.URL,
+			})
+			if err == domain.ErrRecipientNotFound {
+				logger.Warning(ctx, "Dropping notification of unknown user", logger.F("user_id", event.UserID), logger.F("category", event.Category))
+				return nil
+			}
+			return err
+		}
+	}
+
+	email, err := h.recipientRepo.GetEmail(ctx, event.UserID)
+	if err == domain.ErrRecipientNotFound {
+		// the user was deleted since, there is nobody to tell
+		logger.Warning(ctx, "Dropping notification of unknown user", logger.F("user_id", event.UserID), logger.F("category", event.Category))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = h.eventBus.PublishEvent(sharedKafka.WithPartitionKey(ctx, email), &sharedMail.EventSendMail{
+		ToMail: []mail.EmailAddress{
+			{
+				Email: email,
+				Name:  "",
+			},
+		},
+		Subject:     event.Subject,
+		HTMLBody:    event.HTMLBody,
+		Priority:    event.Priority,
+		OrganizerID: event.OrganizerID,
+	})
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to publish send mail event")
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package event
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"tixgo/modules/notification/domain"
+	sharedMail "tixgo/shared/events/mail"
+	sharedNotification "tixgo/shared/events/notification"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/notification/mail"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init(&logger.Config{Output: io.Discard})
+	os.Exit(m.Run())
+}
+
+// fixedPreferences gives every user the same digest frequency
+type fixedPreferences struct {
+	domain.PreferenceRepository
+	frequency domain.DigestFrequency
+}
+
+func (r fixedPreferences) GetByUserID(_ context.Context, userID int64) (*domain.Preference, error) {
+	return &domain.Preference{UserID: userID, DigestFrequency: r.frequency}, nil
+}
+
+// memoryDigests keeps the items queued, failing for the users in gone
+type memoryDigests struct {
+	domain.DigestRepository
+	items []*domain.DigestItem
+	gone  map[int64]bool
+}
+
+func (r *memoryDigests) AddItem(_ context.Context, item *domain.DigestItem) error {
+	if r.gone[item.UserID] {
+		return domain.ErrRecipientNotFound
+	}
+	r.items = append(r.items, item)
+	return nil
+}
+
+// recipientsByID knows the emails of users
+type recipientsByID map[int64]string
+
+func (r recipientsByID) GetEmail(_ context.Context, userID int64) (string, error) {
+	email, ok := r[userID]
+	if !ok {
+		return "", domain.ErrRecipientNotFound
+	}
+	return email, nil
+}
+
+// recordingBus keeps the mails it is asked to publish
+type recordingBus struct {
+	mails []*sharedMail.EventSendMail
+}
+
+func (b *recordingBus) PublishEvent(_ context.Context, event any) error {
+	b.mails = append(b.mails, event.(*sharedMail.EventSendMail))
+	return nil
+}
+
+func notificationRequested(userID int64, priority mail.Priority) *sharedNotification.EventNotificationRequested {
+	return &sharedNotification.EventNotificationRequested{
+		UserID:   userID,
+		Category: "price_drop",
+		Title:    "Jazz Night",
+		Summary:  "Tickets from 20 USD",
+		URL:      "https://tixgo.example/events/jazz-night",
+		Subject:  "Jazz Night is cheaper",
+		HTMLBody: "<p>Tickets from 20 USD</p>",
+		Priority: priority,
+	}
+}
+
+func TestQueueNotification(t *testing.T) {
+	ctx := context.Background()
+	recipients := recipientsByID{1: "fan@example.com"}
+
+	t.Run("batches low priority notifications into the digest", func(t *testing.T) {
+		for _, frequency := range []domain.DigestFrequency{domain.DigestFrequencyDaily, domain.DigestFrequencyWeekly} {
+			digests, bus := &memoryDigests{}, &recordingBus{}
+			handler := NewQueueNotification(fixedPreferences{frequency: frequency}, digests, recipients, bus)
+
+			require.NoError(t, handler.Queue(ctx, notificationRequested(1, mail.PriorityLow)))
+
+			assert.Empty(t, bus.mails, "waits for the %s digest", frequency)
+			require.Len(t, digests.items, 1)
+			assert.Equal(t, &domain.DigestItem{
+				UserID:   1,
+				Category: "price_drop",
+				Title:    "Jazz Night",
+				Summary:  "Tickets from 20 USD",
+				URL:      "https://tixgo.example/events/jazz-night",
+			}, digests.items[0])
+		}
+	})
+
+	t.Run("mails at once when digests are off", func(t *testing.T) {
+		digests, bus := &memoryDigests{}, &recordingBus{}
+		handler := NewQueueNotification(fixedPreferences{frequency: domain.DigestFrequencyOff}, digests, recipients, bus)
+
+		require.NoError(t, handler.Queue(ctx, notificationRequested(1, mail.PriorityLow)))
+
+		assert.Empty(t, digests.items)
+		require.Len(t, bus.mails, 1)
+		assert.Equal(t, "Jazz Night is cheaper", bus.mails[0].Subject)
+		assert.Equal(t, mail.PriorityLow, bus.mails[0].Priority)
+	})
+
+	t.Run("never batches other priorities", func(t *testing.T) {
+		digests, bus := &memoryDigests{}, &recordingBus{}
+		handler := NewQueueNotification(fixedPreferences{frequency: domain.DigestFrequencyWeekly}, digests, recipients, bus)
+
+		require.NoError(t, handler.Queue(ctx, notificationRequested(1, mail.PriorityHigh)))
+
+		assert.Empty(t, digests.items)
+		require.Len(t, bus.mails, 1)
+		assert.Equal(t, "fan@example.com", bus.mails[0].ToMail[0].Email)
+	})
+
+	t.Run("drops the notifications of users gone", func(t *testing.T) {
+		digests, bus := &memoryDigests{gone: map[int64]bool{2: true}}, &recordingBus{}
+
+		handler := NewQueueNotification(fixedPreferences{frequency: domain.DigestFrequencyDaily}, digests, recipients, bus)
+		assert.NoError(t, handler.Queue(ctx, notificationRequested(2, mail.PriorityLow)))
+
+		handler = NewQueueNotification(fixedPreferences{frequency: domain.DigestFrequencyOff}, digests, recipients, bus)
+		assert.NoError(t, handler.Queue(ctx, notificationRequested(2, mail.PriorityLow)))
+
+		assert.Empty(t, digests.items)
+		assert.Empty(t, bus.mails)
+	})
+}
@@ -0,0 +1,37 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/notification/app/command"
+	"tixgo/modules/notification/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// GetNotificationPreferenceQuery represents the query to get the notification preference of a user
+type GetNotificationPreferenceQuery struct {
+	UserID int64
+}
+
+// GetNotificationPreferenceHandler handles notification preference queries
+type GetNotificationPreferenceHandler struct {
+	preferenceRepo domain.PreferenceRepository
+}
+
+// NewGetNotificationPreferenceHandler creates a new get notification preference handler
+func NewGetNotificationPreferenceHandler(preferenceRepo domain.PreferenceRepository) *GetNotificationPreferenceHandler {
+	return &GetNotificationPreferenceHandler{
+		preferenceRepo: preferenceRepo,
+	}
+}
+
+// Handle executes the get notification preference query
+func (h *GetNotificationPreferenceHandler) Handle(ctx context.Context, query GetNotificationPreferenceQuery) (*command.NotificationPreferenceResult, error) {
+	preference, err := h.preferenceRepo.GetByUserID(ctx, query.UserID)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get notification preference")
+	}
+
+	return command.ToNotificationPreferenceResult(preference), nil
+}
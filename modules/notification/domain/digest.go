@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// DigestFrequency is how often a user gets the digest of their low priority notifications
+type DigestFrequency string
+
+const (
+	// DigestFrequencyOff users get every notification mailed at once
+	DigestFrequencyOff    DigestFrequency = "off"
+	DigestFrequencyDaily  DigestFrequency = "daily"
+	DigestFrequencyWeekly DigestFrequency = "weekly"
+
+	// DefaultDigestFrequency applies to users who never set a preference
+	DefaultDigestFrequency = DigestFrequencyDaily
+)
+
+// IsValid checks if the digest frequency is valid
+func (f DigestFrequency) IsValid() bool {
+	return f == DigestFrequencyOff || f == DigestFrequencyDaily || f == DigestFrequencyWeekly
+}
+
+// Period is how long notifications wait for the digest at most
+func (f DigestFrequency) Period() time.Duration {
+	switch f {
+	case DigestFrequencyWeekly:
+		return 7 * 24 * time.Hour
+	case DigestFrequencyDaily:
+		return 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// DueBefore returns when the oldest pending notification of a user must have been queued for their
+// digest to be due at now. With digests off, pending notifications are due at once.
+func (f DigestFrequency) DueBefore(now time.Time) time.Time {
+	return now.Add(-f.Period())
+}
+
+// Preference is the notification preference of a user
+type Preference struct {
+	UserID          int64
+	DigestFrequency DigestFrequency
+	UpdatedAt       time.Time
+}
+
+// DigestItem is a notification waiting for the digest of its user
+type DigestItem struct {
+	ID        int64
+	UserID    int64
+	Category  string
+	Title     string
+	Summary   string
+	URL       string
+	CreatedAt time.Time
+}
+
+// Digest is the notifications of a user due to be mailed together, oldest first
+type Digest struct {
+	UserID    int64
+	Email     string
+	Frequency DigestFrequency
+	Items     []*DigestItem
+}
+
+// ItemIDs returns the notifications of the digest
+func (d *Digest) ItemIDs() []int64 {
+	ids := make([]int64, len(d.Items))
+	for i, item := range d.Items {
+		ids[i] = item.ID
+	}
+	return ids
+}
+
+// PreferenceRepository defines the interface for notification preference persistence
+type PreferenceRepository interface {
+	// GetByUserID retrieves the preference of a user, the default one if they never set it
+	GetByUserID(ctx context.Context, userID int64) (*Preference, error)
+
+	// Save creates or replaces the preference of a user
+	Save(ctx context.Context, preference *Preference) error
+}
+
+// DigestRepository defines the interface for the aggregation of digest notifications
+type DigestRepository interface {
+	// AddItem queues a notification for the digest of its user, ErrRecipientNotFound if the user is gone
+	AddItem(ctx context.Context, item *DigestItem) error
+
+	// ListDue retrieves the digests of up to limit users whose oldest pending notification waited a
+	// full period of their frequency at now, each with up to maxItems notifications
+	ListDue(ctx context.Context, now time.Time, limit, maxItems int) ([]*Digest, error)
+
+	// MarkDigested records that notifications were mailed
+	MarkDigested(ctx context.Context, itemIDs []int64, at time.Time) error
+}
+
+// RecipientRepository finds where users get their mails
+type RecipientRepository interface {
+	// GetEmail retrieves the email address of a user
+	GetEmail(ctx context.Context, userID int64) (string, error)
+}
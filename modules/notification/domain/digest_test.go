@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDigestFrequency(t *testing.T) {
+	assert.True(t, DigestFrequencyOff.IsValid())
+	assert.True(t, DigestFrequencyWeekly.IsValid())
+	assert.False(t, DigestFrequency("hourly").IsValid())
+	assert.Equal(t, DigestFrequencyDaily, DefaultDigestFrequency)
+}
+
+func TestDigestFrequency_DueBefore(t *testing.T) {
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		frequency DigestFrequency
+		queuedAt  time.Time
+		due       bool
+	}{
+		{DigestFrequencyDaily, now.Add(-24 * time.Hour), true},
+		{DigestFrequencyDaily, now.Add(-24*time.Hour + time.Second), false},
+		{DigestFrequencyDaily, now.AddDate(0, 0, -3), true},
+		{DigestFrequencyWeekly, now.AddDate(0, 0, -7), true},
+		{DigestFrequencyWeekly, now.AddDate(0, 0, -6), false},
+		{DigestFrequencyOff, now, true},
+		{DigestFrequencyOff, now.Add(time.Second), false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.frequency)+" queued "+now.Sub(tt.queuedAt).String()+" ago", func(t *testing.T) {
+			// a digest is due once its oldest notification was queued at or before the cutoff
+			due := !tt.queuedAt.After(tt.frequency.DueBefore(now))
+			assert.Equal(t, tt.due, due)
+		})
+	}
+}
+
+func TestDigest_ItemIDs(t *testing.T) {
+	digest := &Digest{Items: []*DigestItem{{ID: 4}, {ID: 9}, {ID: 2}}}
+	assert.Equal(t, []int64{4, 9, 2}, digest.ItemIDs(), "in the order they are mailed")
+	assert.Empty(t, (&Digest{}).ItemIDs())
+}
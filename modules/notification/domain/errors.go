@@ -0,0 +1,13 @@
+package domain
+
+import (
+	"tixgo/shared/errstack"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// Notification domain errors
+var (
+	ErrRecipientNotFound      = errstack.Sentinel(syserr.NotFoundCode, "notification recipient not found")
+	ErrInvalidDigestFrequency = errstack.Sentinel(syserr.InvalidArgumentCode, "invalid digest frequency, must be: off, daily or weekly")
+)
@@ -0,0 +1,45 @@
+package ports
+
+import (
+	"context"
+
+	"tixgo/components"
+	"tixgo/modules/notification/adapters"
+	notificationHandler "tixgo/modules/notification/app/event"
+	sharedNotification "tixgo/shared/events/notification"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/duongptryu/gox/messaging"
+)
+
+const (
+	EventNotificationRequested = "events.EventNotificationRequested"
+)
+
+type NotificationMessagingHandlers struct {
+	dispatcher messaging.Dispatcher
+	appCtx     components.AppContext
+}
+
+func NewNotificationMessagingHandlers(dispatcher messaging.Dispatcher, appCtx components.AppContext) *NotificationMessagingHandlers {
+	return &NotificationMessagingHandlers{
+		dispatcher: dispatcher,
+		appCtx:     appCtx,
+	}
+}
+
+func (h *NotificationMessagingHandlers) RegisterNotificationMessagingHandlers() {
+	eventProcessor := h.dispatcher.GetEventProcessor()
+	eventProcessor.AddHandler(cqrs.NewEventHandler(EventNotificationRequested, h.HandleEventNotificationRequested))
+}
+
+func (h *NotificationMessagingHandlers) HandleEventNotificationRequested(ctx context.Context, event *sharedNotification.EventNotificationRequested) error {
+	biz := notificationHandler.NewQueueNotification(
+		adapters.NewPreferencePostgresRepository(h.appCtx.GetDB()),
+		adapters.NewDigestPostgresRepository(h.appCtx.GetDB()),
+		adapters.NewRecipientPostgresRepository(h.appCtx.GetDB()),
+		h.appCtx.GetReliableEventBus(),
+	)
+
+	return biz.Queue(ctx, event)
+}
@@ -0,0 +1,73 @@
+package ports
+
+import (
+	"net/http"
+
+	"tixgo/components"
+	"tixgo/modules/notification/adapters"
+	"tixgo/modules/notification/app/command"
+	"tixgo/modules/notification/app/query"
+	"tixgo/shared/apiversion"
+	"tixgo/shared/httpresponse"
+	"tixgo/shared/session"
+
+	"github.com/duongptryu/gox/context"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterNotificationRoutes(router *apiversion.Group, appCtx components.AppContext) {
+	preferenceGroup := router.Group("/notification-preferences")
+	{
+		preferenceGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		preferenceGroup.GET("", GetNotificationPreference(appCtx))
+		preferenceGroup.PUT("", UpdateNotificationPreference(appCtx))
+	}
+}
+
+func GetNotificationPreference(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		handler := query.NewGetNotificationPreferenceHandler(adapters.NewPreferencePostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), query.GetNotificationPreferenceQuery{UserID: userID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func UpdateNotificationPreference(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.UpdateNotificationPreferenceCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		userID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.UserID = userID
+
+		handler := command.NewUpdateNotificationPreferenceHandler(adapters.NewPreferencePostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
@@ -0,0 +1,33 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"tixgo/components"
+	"tixgo/modules/notification/adapters"
+	"tixgo/modules/notification/app/command"
+	templateAdapters "tixgo/modules/template/adapters"
+	"tixgo/shared/scheduler"
+)
+
+const (
+	JobSendDigests = "notification.send_digests"
+)
+
+// Jobs returns the jobs of the notification module
+func Jobs(appCtx components.AppContext) []scheduler.Job {
+	return []scheduler.Job{
+		{
+			Name:     JobSendDigests,
+			Schedule: "@every 15m",
+			Timeout:  10 * time.Minute,
+			Run: func(ctx context.Context) error {
+				digestRepo := adapters.NewDigestPostgresRepository(appCtx.GetDB())
+				templateRepo := templateAdapters.NewCachedTemplateRepository(templateAdapters.NewTemplatePostgresRepository(appCtx.GetDB()), appCtx.GetCache())
+				templateRenderer := templateAdapters.NewHTMLTemplateRenderer(appCtx.GetAssetService(), nil, templateAdapters.NewVariableDefaultPostgresRepository(appCtx.GetDB()))
+				return command.NewSendDigestsHandler(digestRepo, templateRepo, templateRenderer, appCtx.GetReliableEventBus()).Handle(ctx)
+			},
+		},
+	}
+}
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"tixgo/modules/notification/app/command"
+	"tixgo/shared/jsonschema"
+)
+
+// Schemas returns the request payloads of the notification module
+func Schemas() []jsonschema.Payload {
+	return []jsonschema.Payload{
+		{Name: "notification-preferences.update", In: jsonschema.Body, Example: command.UpdateNotificationPreferenceCommand{}},
+	}
+}
@@ -0,0 +1,62 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+
+	"tixgo/modules/order/domain"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// createAccommodations stores the accommodation request of an order
+func createAccommodations(ctx context.Context, tx *sqlx.Tx, order *domain.Order) error {
+	accommodations := order.Accommodations
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO order_accommodations (order_id, access_needs, dietary_needs, notes)
+		VALUES ($1, $2, $3, $4)`,
+		order.ID,
+		pq.Array(needStrings(accommodations.AccessNeeds)),
+		pq.Array(needStrings(accommodations.DietaryNeeds)),
+		accommodations.Notes,
+	)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create accommodations")
+	}
+
+	return nil
+}
+
+// needStrings converts needs to an array, empty rather than NULL when there is none
+func needStrings[N ~string](needs []N) []string {
+	values := make([]string, len(needs))
+	for i, need := range needs {
+		values[i] = string(need)
+	}
+	return values
+}
+
+// accommodationsRow is the accommodation request an order is read with, NULL for the orders without one
+type accommodationsRow struct {
+	requested    bool
+	accessNeeds  []string
+	dietaryNeeds []string
+	notes        sql.NullString
+}
+
+func (r *accommodationsRow) toAccommodations() *domain.Accommodations {
+	if !r.requested {
+		return nil
+	}
+
+	accommodations := &domain.Accommodations{Notes: r.notes.String}
+	for _, need := range r.accessNeeds {
+		accommodations.AccessNeeds = append(accommodations.AccessNeeds, domain.AccessNeed(need))
+	}
+	for _, need := range r.dietaryNeeds {
+		accommodations.DietaryNeeds = append(accommodations.DietaryNeeds, domain.DietaryNeed(need))
+	}
+	return accommodations
+}
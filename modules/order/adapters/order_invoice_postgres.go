@@ -0,0 +1,140 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+
+	"tixgo/modules/order/domain"
+	"tixgo/shared/dbtimeout"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+// createInvoice stores the pro-forma invoice of an order paid by bank transfer, numbered after the
+// order, whose number is the payment reference of the transfer
+func createInvoice(ctx context.Context, tx *sqlx.Tx, order *domain.Order) error {
+	invoice := order.Invoice
+	invoice.OrderID = order.ID
+	invoice.Number = domain.InvoiceNumber(order.OrderNumber)
+	invoice.PaymentReference = order.OrderNumber
+
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO order_invoices (order_id, invoice_number, payment_reference, company_name, tax_id,
+		                            billing_address, billing_email, due_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING issued_at`,
+		invoice.OrderID,
+		invoice.Number,
+		invoice.PaymentReference,
+		invoice.Billing.CompanyName,
+		invoice.Billing.TaxID,
+		invoice.Billing.Address,
+		invoice.Billing.Email,
+		invoice.DueAt.UTC(),
+	).Scan(&invoice.IssuedAt)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create invoice")
+	}
+
+	return nil
+}
+
+// ReceiveTransfer records the bank transfer paying an order as a completed payment and sells its
+// tickets. Like Confirm, the status and expiry guard keeps the transfer from racing the expiry of the
+// order; the expiry is checked against when the transfer was received.
+func (r *OrderPostgresRepository) ReceiveTransfer(ctx context.Context, order *domain.Order, transfer *domain.Transfer) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE orders
+		SET status = $2, confirmed_at = $3, updated_at = NOW()
+		WHERE id = $1 AND status = 'awaiting_transfer' AND (expires_at IS NULL OR expires_at > $4)`,
+		order.ID, order.Status, order.ConfirmedAt.UTC(), transfer.ReceivedAt.UTC())
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to confirm order")
+	}
+	confirmed, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to confirm order")
+	}
+	if confirmed == 0 {
+		return domain.ErrOrderNotAwaitingTransfer
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO payments (order_id, amount, currency, status, transaction_id, gateway_response, processed_at)
+		VALUES ($1, $2, $3, 'completed', $4, 'bank transfer', $5)`,
+		order.ID, transfer.Amount, order.Currency, transfer.TransactionID, transfer.ReceivedAt.UTC())
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to record transfer")
+	}
+
+	if err := sell(ctx, tx, order.ID); err != nil {
+		return err
+	}
+	if err := issueQRCodes(ctx, tx, order); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to commit transfer")
+	}
+
+	return nil
+}
+
+// GetByPaymentReference retrieves the order paid by bank transfer with a payment reference
+func (r *OrderPostgresRepository) GetByPaymentReference(ctx context.Context, reference string) (*domain.Order, error) {
+	lookupCtx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var orderID int64
+	err := r.db.QueryRowContext(lookupCtx, `SELECT order_id FROM order_invoices WHERE payment_reference = $1`, reference).Scan(&orderID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrOrderNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to find order by payment reference")
+	}
+
+	return r.GetByID(ctx, orderID)
+}
+
+// invoiceRow is the invoice an order is read with, NULL for the orders not paid by bank transfer
+type invoiceRow struct {
+	number           sql.NullString
+	paymentReference sql.NullString
+	companyName      sql.NullString
+	taxID            sql.NullString
+	address          sql.NullString
+	email            sql.NullString
+	dueAt            sql.NullTime
+	issuedAt         sql.NullTime
+}
+
+func (r *invoiceRow) toInvoice(orderID int64) *domain.Invoice {
+	if !r.number.Valid {
+		return nil
+	}
+	return &domain.Invoice{
+		OrderID:          orderID,
+		Number:           r.number.String,
+		PaymentReference: r.paymentReference.String,
+		Billing: domain.BillingDetails{
+			CompanyName: r.companyName.String,
+			TaxID:       r.taxID.String,
+			Address:     r.address.String,
+			Email:       r.email.String,
+		},
+		DueAt:    r.dueAt.Time,
+		IssuedAt: r.issuedAt.Time,
+	}
+}
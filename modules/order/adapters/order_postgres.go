@@ -0,0 +1,734 @@
+package adapters
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	eventDomain "tixgo/modules/event/domain"
+	"tixgo/modules/order/domain"
+	promotionDomain "tixgo/modules/promotion/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/pgerr"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// OrderPostgresRepository implements the OrderRepository interface using PostgreSQL. The tickets a
+// checkout holds are counted in quantity_reserved of their categories, recorded in order_reservations,
+// and marked reserved until the order expires.
+type OrderPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewOrderPostgresRepository creates a new PostgreSQL order repository
+func NewOrderPostgresRepository(db *sqlx.DB) *OrderPostgresRepository {
+	return &OrderPostgresRepository{db: db}
+}
+
+// GetCheckoutEvent retrieves an event with the categories of categoryIDs that belong to it. The pauses
+// scheduled and not applied yet by the job count as applied.
+func (r *OrderPostgresRepository) GetCheckoutEvent(ctx context.Context, eventID int64, categoryIDs []int64) (*domain.CheckoutEvent, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	event := &domain.CheckoutEvent{Categories: make(map[int64]*domain.CheckoutCategory, len(categoryIDs))}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, organizer_id, title, status, COALESCE(max_tickets_per_order, 10), currency, test_mode
+		FROM events
+		WHERE id = $1`, eventID,
+	).Scan(&event.ID, &event.OrganizerID, &event.Title, &event.Status, &event.MaxTicketsPerOrder, &event.Currency, &event.TestMode)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, eventDomain.ErrEventNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get event")
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT c.id, c.name, COALESCE(c.max_per_order, 10),
+		       COALESCE(c.sale_start_date, e.sale_start_date), COALESCE(c.sale_end_date, e.sale_end_date),
+		       c.sales_paused OR e.sales_paused OR COALESCE(c.sales_pause_at <= NOW(), FALSE)
+		           OR COALESCE(e.sales_pause_at <= NOW(), FALSE)
+		FROM ticket_categories c
+		JOIN events e ON e.id = c.event_id
+		WHERE c.event_id = $1 AND c.id = ANY($2)`, eventID, pq.Array(categoryIDs))
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get ticket categories")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		category := &domain.CheckoutCategory{}
+		err := rows.Scan(
+			&category.ID,
+			&category.Name,
+			&category.MaxPerOrder,
+			&category.SaleStartDate,
+			&category.SaleEndDate,
+			&category.SalesPaused,
+		)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan ticket category")
+		}
+		event.Categories[category.ID] = category
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to iterate ticket categories")
+	}
+
+	return event, nil
+}
+
+// Create reserves the tickets of a checkout and stores its pending order, atomically. Concurrent
+// checkouts holding the same seats may deadlock, the losing transaction is run again.
+func (r *OrderPostgresRepository) Create(ctx context.Context, order *domain.Order, promo *promotionDomain.PromoCode, heldSeats []int64) error {
+	return pgerr.Retry(ctx, func(ctx context.Context) error {
+		return r.create(ctx, order, promo, heldSeats)
+	})
+}
+
+func (r *OrderPostgresRepository) create(ctx context.Context, order *domain.Order, promo *promotionDomain.PromoCode, heldSeats []int64) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	orderNumber, err := newOrderNumber()
+	if err != nil {
+		return err
+	}
+
+	// the amounts are set from the items below, in the currency of the event
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO orders (user_id, order_number, status, total_amount, final_amount, currency, email_received, expires_at,
+		                    test_mode)
+		SELECT id, $2, $3, 0, 0, $5, email, $4, $6
+		FROM users
+		WHERE id = $1
+		RETURNING id, email_received, created_at`,
+		order.UserID, orderNumber, order.Status, order.ExpiresAt.UTC(), order.Currency, order.TestMode,
+	).Scan(&order.ID, &order.Email, &order.CreatedAt)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create order")
+	}
+	order.OrderNumber = orderNumber
+
+	if order.Invoice != nil {
+		if err := createInvoice(ctx, tx, order); err != nil {
+			return err
+		}
+	}
+	if order.Accommodations != nil {
+		if err := createAccommodations(ctx, tx, order); err != nil {
+			return err
+		}
+	}
+
+	order.Tickets = nil
+	for _, line := range order.Lines {
+		if err := reserve(ctx, tx, order.ID, line); err != nil {
+			return err
+		}
+
+		tickets, err := holdTickets(ctx, tx, order, line, len(order.Tickets), heldSeats)
+		if err != nil {
+			return err
+		}
+		order.Tickets = append(order.Tickets, tickets...)
+	}
+
+	ticketIDs := make([]int64, len(order.Tickets))
+	for i, ticket := range order.Tickets {
+		ticketIDs[i] = ticket.ID
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO order_items (order_id, ticket_id, unit_price, quantity, subtotal)
+		SELECT $1, t.id, c.price, 1, c.price
+		FROM tickets t
+		JOIN ticket_categories c ON c.id = t.ticket_category_id
+		WHERE t.id = ANY($2)`, order.ID, pq.Array(ticketIDs))
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create order items")
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE orders
+		SET total_amount = items.total, final_amount = items.total, updated_at = NOW()
+		FROM (SELECT COALESCE(SUM(subtotal), 0) AS total FROM order_items WHERE order_id = $1) items
+		WHERE id = $1
+		RETURNING total_amount::TEXT, COALESCE(discount_amount, 0)::TEXT, final_amount::TEXT, COALESCE(currency, 'USD')`, order.ID,
+	).Scan(&order.TotalAmount, &order.DiscountAmount, &order.FinalAmount, &order.Currency)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to total order")
+	}
+
+	if promo != nil {
+		if err := redeem(ctx, tx, order, promo); err != nil {
+			return err
+		}
+	}
+
+	lines, err := getLines(ctx, tx, order.ID)
+	if err != nil {
+		return err
+	}
+	order.Lines = lines
+
+	if err := tx.Commit(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to commit order")
+	}
+
+	return nil
+}
+
+// reserve counts the tickets of a line in the stock of their category, ErrSoldOut if fewer are left
+func reserve(ctx context.Context, tx *sqlx.Tx, orderID int64, line *domain.OrderLine) error {
+	result, err := tx.ExecContext(ctx, `
+		UPDATE ticket_categories
+		SET quantity_reserved = quantity_reserved + $2, updated_at = NOW()
+		WHERE id = $1 AND quantity_sold + quantity_reserved + quantity_allotted + $2 <= quantity_available`,
+		line.TicketCategoryID, line.Quantity)
+	if err != nil {
+		if pgerr.Constraint(err) == pgerr.ConstraintTicketInventory {
+			return eventDomain.ErrSoldOut
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to reserve tickets")
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get rows affected")
+	}
+	if updated == 0 {
+		return eventDomain.ErrSoldOut
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO order_reservations (order_id, ticket_category_id, quantity)
+		VALUES ($1, $2, $3)`, orderID, line.TicketCategoryID, line.Quantity)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to record reservation")
+	}
+
+	return nil
+}
+
+// redeem discounts an order with a promo code and counts its redemption. The conditional increment
+// locks the row of the code, so the checkouts redeeming it are serialized from there on and its total
+// and per customer limits hold under concurrent checkouts.
+func redeem(ctx context.Context, tx *sqlx.Tx, order *domain.Order, promo *promotionDomain.PromoCode) error {
+	discount, err := promo.Discount(order.TotalAmount)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to compute discount")
+	}
+
+	var maxPerUser *int
+	err = tx.QueryRowContext(ctx, `
+		UPDATE promo_codes
+		SET redemption_count = redemption_count + 1, updated_at = NOW()
+		WHERE id = $1 AND active AND (max_redemptions IS NULL OR redemption_count < max_redemptions)
+		RETURNING max_per_user`, promo.ID,
+	).Scan(&maxPerUser)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return promotionDomain.ErrPromoCodeExhausted
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to redeem promo code")
+	}
+
+	if maxPerUser != nil {
+		var redeemed int
+		err := tx.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM promo_redemptions WHERE promo_code_id = $1 AND user_id = $2`,
+			promo.ID, order.UserID).Scan(&redeemed)
+		if err != nil {
+			return syserr.Wrap(err, syserr.InternalCode, "failed to count promo code redemptions")
+		}
+		if redeemed >= *maxPerUser {
+			return promotionDomain.ErrPromoCodeUserLimit
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO promo_redemptions (order_id, promo_code_id, user_id, discount_amount)
+		VALUES ($1, $2, $3, $4)`, order.ID, promo.ID, order.UserID, discount)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to record promo code redemption")
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE orders SET discount_amount = $2, final_amount = total_amount - $2, updated_at = NOW()
+		WHERE id = $1
+		RETURNING discount_amount::TEXT, final_amount::TEXT`, order.ID, discount,
+	).Scan(&order.DiscountAmount, &order.FinalAmount)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to discount order")
+	}
+	order.PromoCode = promo.Code
+
+	return nil
+}
+
+// holdTickets marks the tickets of a line reserved until the order expires. Seated categories hold the
+// seats selected, ErrSeatUnavailable if one is not available, or their best available seats but
+// heldSeats, ErrSoldOut if fewer are left; the tickets of general admission categories are created,
+// numbered after the order from offset on.
+func holdTickets(ctx context.Context, tx *sqlx.Tx, order *domain.Order, line *domain.OrderLine, offset int, heldSeats []int64) ([]*domain.OrderTicket, error) {
+	var seated bool
+	err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM tickets WHERE ticket_category_id = $1 AND seat_section IS NOT NULL)`,
+		line.TicketCategoryID).Scan(&seated)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get seat map")
+	}
+
+	if len(line.SeatIDs) > 0 && !seated {
+		return nil, domain.ErrSeatUnavailable
+	}
+
+	var rows *sql.Rows
+	switch {
+	case len(line.SeatIDs) > 0:
+		// reservations past their expiry are available again
+		rows, err = tx.QueryContext(ctx, `
+			UPDATE tickets
+			SET status = 'reserved', reserved_at = NOW(), reserved_expires_at = $3, updated_at = NOW()
+			WHERE ticket_category_id = $1 AND id = ANY($2) AND seat_section IS NOT NULL
+			  AND (status = 'available' OR (status = 'reserved' AND reserved_expires_at <= NOW()))
+			RETURNING id`,
+			line.TicketCategoryID, pq.Array(line.SeatIDs), order.ExpiresAt.UTC())
+	case seated:
+		rows, err = tx.QueryContext(ctx, `
+			UPDATE tickets
+			SET status = 'reserved', reserved_at = NOW(), reserved_expires_at = $3, updated_at = NOW()
+			WHERE id IN (
+				SELECT id FROM tickets
+				WHERE ticket_category_id = $1 AND id <> ALL(COALESCE($4::BIGINT[], '{}'))
+				  AND (status = 'available' OR (status = 'reserved' AND reserved_expires_at <= NOW()))
+				ORDER BY seat_section, seat_row, seat_number, id
+				LIMIT $2
+				FOR UPDATE SKIP LOCKED)
+			RETURNING id`,
+			line.TicketCategoryID, line.Quantity, order.ExpiresAt.UTC(), pq.Array(heldSeats))
+	default:
+		numbers := make([]string, line.Quantity)
+		for i := range numbers {
+			numbers[i] = fmt.Sprintf("%s-%d", order.OrderNumber, offset+i+1)
+		}
+
+		rows, err = tx.QueryContext(ctx, `
+			INSERT INTO tickets (ticket_category_id, ticket_number, status, reserved_at, reserved_expires_at)
+			SELECT $1, unnest($2::text[]), 'reserved', NOW(), $3
+			RETURNING id`,
+			line.TicketCategoryID, pq.Array(numbers), order.ExpiresAt.UTC())
+	}
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to hold tickets")
+	}
+	defer rows.Close()
+
+	var tickets []*domain.OrderTicket
+	for rows.Next() {
+		ticket := &domain.OrderTicket{TicketCategoryID: line.TicketCategoryID, Seated: seated}
+		if err := rows.Scan(&ticket.ID); err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan ticket")
+		}
+		tickets = append(tickets, ticket)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to iterate tickets")
+	}
+
+	if len(tickets) < line.Quantity {
+		if len(line.SeatIDs) > 0 {
+			return nil, domain.ErrSeatUnavailable
+		}
+		return nil, eventDomain.ErrSoldOut
+	}
+
+	return tickets, nil
+}
+
+// AvailableSeats returns the tickets of ticketIDs that are seats of the event on sale. Reservations
+// past their expiry are available again.
+func (r *OrderPostgresRepository) AvailableSeats(ctx context.Context, eventID int64, ticketIDs []int64) ([]int64, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT t.id
+		FROM tickets t
+		JOIN ticket_categories c ON c.id = t.ticket_category_id
+		WHERE c.event_id = $1 AND t.id = ANY($2) AND t.seat_section IS NOT NULL
+		  AND (t.status = 'available' OR (t.status = 'reserved' AND t.reserved_expires_at <= NOW()))
+		ORDER BY t.id`, eventID, pq.Array(ticketIDs))
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get available seats")
+	}
+	defer rows.Close()
+
+	var available []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan seat")
+		}
+		available = append(available, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to iterate seats")
+	}
+
+	return available, nil
+}
+
+// GetByID retrieves an order with its lines and tickets
+func (r *OrderPostgresRepository) GetByID(ctx context.Context, id int64) (*domain.Order, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	order := &domain.Order{}
+	invoice := &invoiceRow{}
+	accommodations := &accommodationsRow{}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT o.id, o.user_id, o.order_number, COALESCE(o.status::TEXT, 'pending'), o.email_received, o.test_mode,
+		       o.total_amount::TEXT, COALESCE(p.code, ''), COALESCE(o.discount_amount, 0)::TEXT, o.final_amount::TEXT,
+		       COALESCE(o.tax_amount, 0)::TEXT, COALESCE(o.service_fee, 0)::TEXT, COALESCE(o.currency, 'USD'), o.expires_at,
+		       o.confirmed_at, o.cancelled_at, COALESCE(o.created_at, NOW()), inv.invoice_number, inv.payment_reference, inv.company_name, inv.tax_id, inv.billing_address,
+		       inv.billing_email, inv.due_at, inv.issued_at, acc.order_id IS NOT NULL, acc.access_needs, acc.dietary_needs,
+		       acc.notes
+		FROM orders o
+		LEFT JOIN promo_redemptions pr ON pr.order_id = o.id
+		LEFT JOIN promo_codes p ON p.id = pr.promo_code_id
+		LEFT JOIN order_invoices inv ON inv.order_id = o.id
+		LEFT JOIN order_accommodations acc ON acc.order_id = o.id
+		WHERE o.id = $1`, id,
+	).Scan(
+		&order.ID,
+		&order.UserID,
+		&order.OrderNumber,
+		&order.Status,
+		&order.Email,
+		&order.TestMode,
+		&order.TotalAmount,
+		&order.PromoCode,
+		&order.DiscountAmount,
+		&order.FinalAmount,
+		&order.TaxAmount,
+		&order.ServiceFee,
+		&order.Currency,
+		&order.ExpiresAt,
+		&order.ConfirmedAt,
+		&order.CancelledAt,
+		&order.CreatedAt,
+		&invoice.number,
+		&invoice.paymentReference,
+		&invoice.companyName,
+		&invoice.taxID,
+		&invoice.address,
+		&invoice.email,
+		&invoice.dueAt,
+		&invoice.issuedAt,
+		&accommodations.requested,
+		pq.Array(&accommodations.accessNeeds),
+		pq.Array(&accommodations.dietaryNeeds),
+		&accommodations.notes,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrOrderNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get order")
+	}
+	order.Invoice = invoice.toInvoice(order.ID)
+	order.Accommodations = accommodations.toAccommodations()
+
+	if order.Lines, err = getLines(ctx, r.db, order.ID); err != nil {
+		return nil, err
+	}
+
+	// an order never spans events
+	err = r.db.QueryRowContext(ctx, `
+		SELECT c.event_id, e.organizer_id, e.title
+		FROM order_items i
+		JOIN tickets t ON t.id = i.ticket_id
+		JOIN ticket_categories c ON c.id = t.ticket_category_id
+		JOIN events e ON e.id = c.event_id
+		WHERE i.order_id = $1
+		LIMIT 1`, order.ID).Scan(&order.EventID, &order.OrganizerID, &order.EventTitle)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get order event")
+	}
+
+	// the QR code of a refunded seat sold again is the one of its new order
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT t.id, t.ticket_category_id, t.seat_section IS NOT NULL, t.status, rt.ticket_id IS NOT NULL,
+		       CASE WHEN rt.ticket_id IS NULL THEN COALESCE(t.qr_code, '') ELSE '' END
+		FROM order_items i
+		JOIN tickets t ON t.id = i.ticket_id
+		LEFT JOIN refund_tickets rt ON rt.order_id = i.order_id AND rt.ticket_id = t.id
+		WHERE i.order_id = $1
+		ORDER BY t.id`, order.ID)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get order tickets")
+	}
+	defer rows.Close()
+
+	order.Tickets, err = scanTickets(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// Confirm sells the tickets a pending order holds. The status and expiry guard keeps the confirmation
+// from racing the expiry of the order.
+func (r *OrderPostgresRepository) Confirm(ctx context.Context, order *domain.Order) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE orders
+		SET status = $2, confirmed_at = $3, updated_at = NOW()
+		WHERE id = $1 AND status = 'pending' AND (expires_at IS NULL OR expires_at > $3)`,
+		order.ID, order.Status, order.ConfirmedAt.UTC())
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to confirm order")
+	}
+	confirmed, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to confirm order")
+	}
+	if confirmed == 0 {
+		return domain.ErrOrderNotPending
+	}
+
+	if err := sell(ctx, tx, order.ID); err != nil {
+		return err
+	}
+	if err := issueQRCodes(ctx, tx, order); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to commit order confirmation")
+	}
+
+	return nil
+}
+
+// sell turns the tickets an order holds and their reservations into sold tickets
+func sell(ctx context.Context, tx *sqlx.Tx, orderID int64) error {
+	_, err := tx.ExecContext(ctx, `
+		WITH sold AS (
+			DELETE FROM order_reservations WHERE order_id = $1
+			RETURNING ticket_category_id, quantity
+		)
+		UPDATE ticket_categories c
+		SET quantity_reserved = c.quantity_reserved - sold.quantity, quantity_sold = c.quantity_sold + sold.quantity,
+		    updated_at = NOW()
+		FROM sold
+		WHERE c.id = sold.ticket_category_id`, orderID)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to sell reserved tickets")
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE tickets t
+		SET status = 'sold', reserved_at = NULL, reserved_expires_at = NULL, updated_at = NOW()
+		FROM order_items i
+		WHERE i.order_id = $1 AND t.id = i.ticket_id AND t.status = 'reserved'`, orderID)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to sell tickets")
+	}
+
+	return nil
+}
+
+// ExpirePending cancels up to limit checkout orders pending or awaiting transfer past their expiry at
+// before, releasing their tickets. Each expired order lists the tickets it gave back: seats taken by another buyer since
+// the order expired are left to them.
+func (r *OrderPostgresRepository) ExpirePending(ctx context.Context, before time.Time, limit int) ([]*domain.Order, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	// orders without reservations hold nothing to give back; the orders of group booking seats hold
+	// the reservation of their seat and expire with the booking hold like any checkout
+	rows, err := tx.QueryContext(ctx, `
+		SELECT o.id, o.user_id, o.order_number, o.email_received, o.expires_at
+		FROM orders o
+		WHERE o.status IN ('pending', 'awaiting_transfer') AND o.expires_at <= $1
+		  AND EXISTS (SELECT 1 FROM order_reservations r WHERE r.order_id = o.id)
+		ORDER BY o.expires_at, o.id
+		LIMIT $2
+		FOR UPDATE OF o SKIP LOCKED`, before.UTC(), limit)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get expired orders")
+	}
+
+	var expired []*domain.Order
+	for rows.Next() {
+		order := &domain.Order{Status: domain.OrderStatusCancelled}
+		if err := rows.Scan(&order.ID, &order.UserID, &order.OrderNumber, &order.Email, &order.ExpiresAt); err != nil {
+			rows.Close()
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan expired order")
+		}
+		expired = append(expired, order)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to iterate expired orders")
+	}
+
+	for _, order := range expired {
+		err := tx.QueryRowContext(ctx, `
+			UPDATE orders SET status = 'cancelled', cancelled_at = NOW(), updated_at = NOW()
+			WHERE id = $1
+			RETURNING cancelled_at`, order.ID).Scan(&order.CancelledAt)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to cancel order")
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			WITH released AS (
+				DELETE FROM order_reservations WHERE order_id = $1
+				RETURNING ticket_category_id, quantity
+			)
+			UPDATE ticket_categories c
+			SET quantity_reserved = c.quantity_reserved - released.quantity, updated_at = NOW()
+			FROM released
+			WHERE c.id = released.ticket_category_id`, order.ID)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to release reserved tickets")
+		}
+
+		// the promo code of an order never placed can be redeemed again
+		_, err = tx.ExecContext(ctx, `
+			WITH released AS (
+				DELETE FROM promo_redemptions WHERE order_id = $1
+				RETURNING promo_code_id
+			)
+			UPDATE promo_codes p
+			SET redemption_count = p.redemption_count - 1, updated_at = NOW()
+			FROM released
+			WHERE p.id = released.promo_code_id`, order.ID)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to release promo code redemption")
+		}
+
+		// only release the hold of this order, a seat may have been held again since it expired; the
+		// tickets created for general admission are void
+		rows, err := tx.QueryContext(ctx, `
+			UPDATE tickets t
+			SET status = CASE WHEN t.seat_section IS NULL THEN 'cancelled' ELSE 'available' END::ticket_status_enum,
+			    reserved_at = NULL, reserved_expires_at = NULL, updated_at = NOW()
+			FROM order_items i, ticket_categories c
+			WHERE i.order_id = $1 AND t.id = i.ticket_id AND c.id = t.ticket_category_id
+			  AND t.status = 'reserved' AND t.reserved_expires_at = $2
+			RETURNING t.id, t.ticket_category_id, t.seat_section IS NOT NULL, c.event_id`, order.ID, order.ExpiresAt)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to release tickets")
+		}
+		for rows.Next() {
+			ticket := &domain.OrderTicket{}
+			if err := rows.Scan(&ticket.ID, &ticket.TicketCategoryID, &ticket.Seated, &order.EventID); err != nil {
+				rows.Close()
+				return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan released ticket")
+			}
+			order.Tickets = append(order.Tickets, ticket)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to iterate released tickets")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to commit expired orders")
+	}
+
+	return expired, nil
+}
+
+// getLines retrieves the lines of an order, by category and price
+func getLines(ctx context.Context, q sqlx.QueryerContext, orderID int64) ([]*domain.OrderLine, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT c.id, c.name, COUNT(*)::INT, i.unit_price::TEXT, SUM(i.subtotal)::TEXT
+		FROM order_items i
+		JOIN tickets t ON t.id = i.ticket_id
+		JOIN ticket_categories c ON c.id = t.ticket_category_id
+		WHERE i.order_id = $1
+		GROUP BY c.id, c.name, i.unit_price
+		ORDER BY c.id, i.unit_price`, orderID)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get order lines")
+	}
+	defer rows.Close()
+
+	var lines []*domain.OrderLine
+	for rows.Next() {
+		line := &domain.OrderLine{}
+		if err := rows.Scan(&line.TicketCategoryID, &line.TicketCategoryName, &line.Quantity, &line.UnitPrice, &line.Subtotal); err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan order line")
+		}
+		lines = append(lines, line)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to iterate order lines")
+	}
+
+	return lines, nil
+}
+
+func scanTickets(rows *sql.Rows) ([]*domain.OrderTicket, error) {
+	var tickets []*domain.OrderTicket
+	for rows.Next() {
+		ticket := &domain.OrderTicket{}
+		if err := rows.Scan(&ticket.ID, &ticket.TicketCategoryID, &ticket.Seated, &ticket.Status, &ticket.Refunded, &ticket.QRCode); err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan order ticket")
+		}
+		tickets = append(tickets, ticket)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to iterate order tickets")
+	}
+
+	return tickets, nil
+}
+
+func newOrderNumber() (string, error) {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", syserr.Wrap(err, syserr.InternalCode, "failed to generate order number")
+	}
+	return "ORD-" + strings.ToUpper(hex.EncodeToString(b)), nil
+}
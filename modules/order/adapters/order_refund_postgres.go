@@ -0,0 +1,225 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+
+	"tixgo/modules/order/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/pgerr"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/lib/pq"
+)
+
+// Refund records a refund of the order against its latest completed payment and gives its tickets back,
+// atomically. The order is locked, so refunds of the same order apply one after the other; whether the
+// refund is full is decided here rather than from the order read before, which a concurrent refund may
+// have changed.
+func (r *OrderPostgresRepository) Refund(ctx context.Context, order *domain.Order, refund *domain.Refund) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	var status domain.OrderStatus
+	err = tx.QueryRowContext(ctx, `SELECT status FROM orders WHERE id = $1 FOR UPDATE`, order.ID).Scan(&status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrOrderNotFound
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to lock order")
+	}
+	if status != domain.OrderStatusConfirmed && status != domain.OrderStatusPartiallyRefunded {
+		return domain.ErrOrderNotRefundable
+	}
+
+	// what is left of the payment, the refunds that failed gave nothing back
+	var remaining string
+	err = tx.QueryRowContext(ctx, `
+		SELECT p.id, (p.amount - COALESCE(
+		           (SELECT SUM(amount) FROM refunds WHERE payment_id = p.id AND status <> 'failed'), 0))::TEXT,
+		       COALESCE(p.currency, 'USD')
+		FROM payments p
+		WHERE p.order_id = $1 AND p.status IN ('completed', 'partially_refunded')
+		ORDER BY p.id DESC
+		LIMIT 1`, order.ID,
+	).Scan(&refund.PaymentID, &remaining, &refund.Currency)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrNoPayment
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get payment")
+	}
+
+	ticketIDs := refund.TicketIDs()
+	result, err := tx.ExecContext(ctx, `
+		UPDATE tickets
+		SET status = CASE WHEN seat_section IS NULL THEN 'cancelled' ELSE 'available' END::ticket_status_enum,
+		    qr_code = NULL, updated_at = NOW()
+		WHERE id = ANY($1) AND status = 'sold'`, pq.Array(ticketIDs))
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to give tickets back")
+	}
+	released, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get rows affected")
+	}
+	if int(released) != len(ticketIDs) {
+		return domain.ErrTicketNotRefundable
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		SELECT NOT EXISTS (
+			SELECT 1 FROM order_items i
+			WHERE i.order_id = $1 AND i.ticket_id <> ALL($2)
+			  AND NOT EXISTS (SELECT 1 FROM refund_tickets rt WHERE rt.order_id = i.order_id AND rt.ticket_id = i.ticket_id))`,
+		order.ID, pq.Array(ticketIDs),
+	).Scan(&refund.Full)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to check refunded tickets")
+	}
+
+	// the tickets of a discounted order are refunded at their share of the final amount, the last refund
+	// of an order gives back the rest of the payment
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO refunds (payment_id, amount, reason, status)
+		SELECT $1,
+		       CASE WHEN $3 THEN $2::DECIMAL
+		            ELSE LEAST(COALESCE(ROUND(SUM(i.subtotal) *
+		                 (SELECT final_amount / NULLIF(total_amount, 0) FROM orders WHERE id = $5), 2), 0), $2::DECIMAL)
+		       END,
+		       $4, 'pending'
+		FROM order_items i
+		WHERE i.order_id = $5 AND i.ticket_id = ANY($6)
+		RETURNING id, amount::TEXT, created_at`,
+		refund.PaymentID, remaining, refund.Full, refund.Reason, order.ID, pq.Array(ticketIDs),
+	).Scan(&refund.ID, &refund.Amount, &refund.CreatedAt)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create refund")
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO refund_tickets (order_id, ticket_id, refund_id)
+		SELECT $1, unnest($2::BIGINT[]), $3`, order.ID, pq.Array(ticketIDs), refund.ID)
+	if err != nil {
+		if pgerr.IsUniqueViolation(err) {
+			return domain.ErrTicketNotRefundable
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to record refunded tickets")
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE ticket_categories c
+		SET quantity_sold = c.quantity_sold - refunded.quantity, updated_at = NOW()
+		FROM (
+			SELECT ticket_category_id, COUNT(*) AS quantity FROM tickets
+			WHERE id = ANY($1)
+			GROUP BY ticket_category_id
+		) refunded
+		WHERE c.id = refunded.ticket_category_id`, pq.Array(ticketIDs))
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to restore ticket inventory")
+	}
+
+	order.Status = domain.OrderStatusPartiallyRefunded
+	if refund.Full {
+		order.Status = domain.OrderStatusRefunded
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE orders SET status = $2, updated_at = NOW() WHERE id = $1`, order.ID, order.Status); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to update order status")
+	}
+
+	// the fake payment of a test order is refunded as soon as it is asked for
+	if order.TestMode {
+		err := tx.QueryRowContext(ctx, `
+			UPDATE refunds SET status = 'completed', gateway_response = 'test mode', processed_at = NOW()
+			WHERE id = $1
+			RETURNING status, processed_at`, refund.ID).Scan(&refund.Status, &refund.ProcessedAt)
+		if err != nil {
+			return syserr.Wrap(err, syserr.InternalCode, "failed to complete test refund")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to commit refund")
+	}
+
+	return nil
+}
+
+// ListRefunds retrieves the refunds of the payments of an order with the tickets they gave back, oldest
+// first. The refunds of a cancelled event refund the whole payment and list no ticket.
+func (r *OrderPostgresRepository) ListRefunds(ctx context.Context, orderID int64) ([]*domain.Refund, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT r.id, r.payment_id, r.amount::TEXT, COALESCE(p.currency, 'USD'), COALESCE(r.reason, ''),
+		       COALESCE(r.status::TEXT, 'pending'), r.processed_at, COALESCE(r.created_at, NOW())
+		FROM refunds r
+		JOIN payments p ON p.id = r.payment_id
+		WHERE p.order_id = $1
+		ORDER BY r.id`, orderID)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list refunds")
+	}
+	defer rows.Close()
+
+	var refunds []*domain.Refund
+	byID := make(map[int64]*domain.Refund)
+	for rows.Next() {
+		refund := &domain.Refund{OrderID: orderID}
+		err := rows.Scan(
+			&refund.ID,
+			&refund.PaymentID,
+			&refund.Amount,
+			&refund.Currency,
+			&refund.Reason,
+			&refund.Status,
+			&refund.ProcessedAt,
+			&refund.CreatedAt,
+		)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan refund")
+		}
+		refunds = append(refunds, refund)
+		byID[refund.ID] = refund
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to iterate refunds")
+	}
+
+	ticketRows, err := r.db.QueryContext(ctx, `
+		SELECT rt.refund_id, t.id, t.ticket_category_id, t.seat_section IS NOT NULL, t.status
+		FROM refund_tickets rt
+		JOIN tickets t ON t.id = rt.ticket_id
+		WHERE rt.order_id = $1
+		ORDER BY t.id`, orderID)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get refunded tickets")
+	}
+	defer ticketRows.Close()
+
+	for ticketRows.Next() {
+		var refundID int64
+		ticket := &domain.OrderTicket{Refunded: true}
+		if err := ticketRows.Scan(&refundID, &ticket.ID, &ticket.TicketCategoryID, &ticket.Seated, &ticket.Status); err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan refunded ticket")
+		}
+		if refund, ok := byID[refundID]; ok {
+			refund.Tickets = append(refund.Tickets, ticket)
+		}
+	}
+
+	if err := ticketRows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to iterate refunded tickets")
+	}
+
+	return refunds, nil
+}
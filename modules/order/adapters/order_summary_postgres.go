@@ -0,0 +1,213 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+
+	"tixgo/modules/order/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/listing"
+	"tixgo/shared/pgquery"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// projectSummaries upserts the summaries of the orders matching the condition it is formatted with.
+// The event of an order is the event of its tickets, an order never spans events.
+const projectSummaries = `
+	INSERT INTO order_summaries (
+		order_id, user_id, order_number, customer_name, customer_email, event_id, event_title,
+		event_start_date, ticket_count, total_amount, currency, status, created_at, projected_at
+	)
+	SELECT o.id, o.user_id, o.order_number, TRIM(u.first_name || ' ' || u.last_name), o.email_received,
+		e.id, e.title, e.start_date, COALESCE(items.ticket_count, 0), o.final_amount,
+		COALESCE(o.currency, 'USD'), COALESCE(o.status::TEXT, 'pending'), COALESCE(o.created_at, NOW()), NOW()
+	FROM orders o
+	JOIN users u ON u.id = o.user_id
+	LEFT JOIN LATERAL (
+		SELECT SUM(COALESCE(i.quantity, 1))::INT AS ticket_count, MIN(c.event_id) AS event_id
+		FROM order_items i
+		JOIN tickets t ON t.id = i.ticket_id
+		JOIN ticket_categories c ON c.id = t.ticket_category_id
+		WHERE i.order_id = o.id
+	) items ON TRUE
+	LEFT JOIN events e ON e.id = items.event_id
+	%s
+	ON CONFLICT (order_id) DO UPDATE SET
+		user_id = EXCLUDED.user_id,
+		order_number = EXCLUDED.order_number,
+		customer_name = EXCLUDED.customer_name,
+		customer_email = EXCLUDED.customer_email,
+		event_id = EXCLUDED.event_id,
+		event_title = EXCLUDED.event_title,
+		event_start_date = EXCLUDED.event_start_date,
+		ticket_count = EXCLUDED.ticket_count,
+		total_amount = EXCLUDED.total_amount,
+		currency = EXCLUDED.currency,
+		status = EXCLUDED.status,
+		created_at = EXCLUDED.created_at,
+		projected_at = EXCLUDED.projected_at`
+
+// OrderSummaryPostgresRepository implements the OrderSummaryRepository interface using PostgreSQL
+type OrderSummaryPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewOrderSummaryPostgresRepository creates a new PostgreSQL order summary repository
+func NewOrderSummaryPostgresRepository(db *sqlx.DB) *OrderSummaryPostgresRepository {
+	return &OrderSummaryPostgresRepository{db: db}
+}
+
+// Project projects the summaries of the given orders from their current state
+func (r *OrderSummaryPostgresRepository) Project(ctx context.Context, orderIDs []int64) error {
+	if len(orderIDs) == 0 {
+		return nil
+	}
+
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, fmt.Sprintf(projectSummaries, "WHERE o.id = ANY($1)"), pq.Array(orderIDs))
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to project order summaries")
+	}
+
+	return nil
+}
+
+// ProjectEvent projects the summaries of every order of an event
+func (r *OrderSummaryPostgresRepository) ProjectEvent(ctx context.Context, eventID int64) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	condition := `
+	WHERE o.id IN (
+		SELECT i.order_id
+		FROM order_items i
+		JOIN tickets t ON t.id = i.ticket_id
+		JOIN ticket_categories c ON c.id = t.ticket_category_id
+		WHERE c.event_id = $1
+	)`
+
+	_, err := r.db.ExecContext(ctx, fmt.Sprintf(projectSummaries, condition), eventID)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to project order summaries of event")
+	}
+
+	return nil
+}
+
+// Rebuild projects the summaries of every order again, batch by batch of batchSize orders,
+// deleting the summaries left without an order, and returns how many orders were projected
+func (r *OrderSummaryPostgresRepository) Rebuild(ctx context.Context, batchSize int) (int, error) {
+	query := fmt.Sprintf(projectSummaries, "WHERE o.id > $1 ORDER BY o.id LIMIT $2") + " RETURNING order_id"
+
+	var (
+		afterID   int64
+		projected int
+	)
+	for {
+		ids, err := r.projectBatch(ctx, query, afterID, batchSize)
+		if err != nil {
+			return projected, err
+		}
+
+		projected += len(ids)
+		if len(ids) < batchSize {
+			break
+		}
+		for _, id := range ids {
+			afterID = max(afterID, id)
+		}
+	}
+
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM order_summaries s
+		WHERE NOT EXISTS (SELECT 1 FROM orders o WHERE o.id = s.order_id)`)
+	if err != nil {
+		return projected, syserr.Wrap(err, syserr.InternalCode, "failed to delete orphaned order summaries")
+	}
+
+	return projected, nil
+}
+
+// projectBatch projects the summaries of the batchSize orders following afterID and returns their IDs
+func (r *OrderSummaryPostgresRepository) projectBatch(ctx context.Context, query string, afterID int64, batchSize int) ([]int64, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var ids []int64
+	if err := r.db.SelectContext(ctx, &ids, query, afterID, batchSize); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to rebuild order summaries")
+	}
+
+	return ids, nil
+}
+
+// ListByUserID retrieves a page of the summaries of the orders of a user, newest first
+func (r *OrderSummaryPostgresRepository) ListByUserID(ctx context.Context, userID int64, paging *listing.Paging) ([]*domain.OrderSummary, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	filter := &pgquery.Filter{}
+	filter.Where("user_id = ?", userID)
+
+	// Set total in paging
+	err := pgquery.Count(ctx, r.db, "order_summaries", filter, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to count order summaries")
+	}
+
+	pageClause, args := filter.Paged(paging)
+	query := fmt.Sprintf(`
+		SELECT order_id, user_id, order_number, customer_name, customer_email, COALESCE(event_id, 0),
+			COALESCE(event_title, ''), event_start_date, ticket_count, total_amount::TEXT, currency, status,
+			created_at, projected_at
+		FROM order_summaries
+		%s
+		ORDER BY created_at DESC, order_id DESC
+		%s`, filter.Clause(), pageClause)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list order summaries")
+	}
+	defer rows.Close()
+
+	var summaries []*domain.OrderSummary
+	for rows.Next() {
+		summary := &domain.OrderSummary{}
+		err := rows.Scan(
+			&summary.OrderID,
+			&summary.UserID,
+			&summary.OrderNumber,
+			&summary.CustomerName,
+			&summary.CustomerEmail,
+			&summary.EventID,
+			&summary.EventTitle,
+			&summary.EventStartDate,
+			&summary.TicketCount,
+			&summary.TotalAmount,
+			&summary.Currency,
+			&summary.Status,
+			&summary.CreatedAt,
+			&summary.ProjectedAt,
+		)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan order summary")
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "error iterating order summary rows")
+	}
+
+	return summaries[:paging.Fetched(len(summaries))], nil
+}
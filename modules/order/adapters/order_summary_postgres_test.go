@@ -0,0 +1,139 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statement is a statement run against a scripted database with its arguments
+type statement struct {
+	query string
+	args  []driver.Value
+}
+
+// scriptedDB answers the queries containing match with the order IDs of its batches in turn and
+// records the statements run. Statements without an answer affect no row.
+type scriptedDB struct {
+	match      string
+	batches    [][]int64
+	statements []statement
+}
+
+func (db *scriptedDB) open() *sqlx.DB {
+	return sqlx.NewDb(sql.OpenDB(scriptedConnector{db}), "postgres")
+}
+
+func (db *scriptedDB) record(query string, args []driver.NamedValue) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	db.statements = append(db.statements, statement{query: query, args: values})
+}
+
+// ran returns the statements run containing match
+func (db *scriptedDB) ran(match string) []statement {
+	var statements []statement
+	for _, s := range db.statements {
+		if strings.Contains(s.query, match) {
+			statements = append(statements, s)
+		}
+	}
+	return statements
+}
+
+type scriptedConnector struct{ db *scriptedDB }
+
+func (c scriptedConnector) Connect(context.Context) (driver.Conn, error) { return scriptedConn(c), nil }
+func (c scriptedConnector) Driver() driver.Driver                        { return nil }
+
+type scriptedConn struct{ db *scriptedDB }
+
+func (c scriptedConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.db.record(query, args)
+	rows := &scriptedRows{}
+	if c.db.match != "" && strings.Contains(query, c.db.match) && len(c.db.batches) > 0 {
+		for _, id := range c.db.batches[0] {
+			rows.rows = append(rows.rows, []driver.Value{id})
+		}
+		c.db.batches = c.db.batches[1:]
+	}
+	return rows, nil
+}
+
+func (c scriptedConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.record(query, args)
+	return driver.RowsAffected(0), nil
+}
+
+func (c scriptedConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+func (c scriptedConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c scriptedConn) Close() error                        { return nil }
+
+type scriptedRows struct {
+	rows [][]driver.Value
+}
+
+func (r *scriptedRows) Columns() []string { return []string{"order_id"} }
+func (r *scriptedRows) Close() error      { return nil }
+
+func (r *scriptedRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func TestOrderSummaryPostgresRepository_ProjectUpsertsByOrder(t *testing.T) {
+	db := &scriptedDB{}
+	repo := NewOrderSummaryPostgresRepository(db.open())
+
+	require.NoError(t, repo.Project(context.Background(), []int64{1, 2}))
+	require.NoError(t, repo.Project(context.Background(), []int64{1, 2}))
+
+	projections := db.ran("INSERT INTO order_summaries")
+	require.Len(t, projections, 2)
+	for _, projection := range projections {
+		assert.Contains(t, projection.query, "WHERE o.id = ANY($1)")
+		assert.Contains(t, projection.query, "ON CONFLICT (order_id) DO UPDATE SET",
+			"projecting an order again replaces its summary")
+		assert.Contains(t, projection.query, "status = EXCLUDED.status")
+	}
+}
+
+func TestOrderSummaryPostgresRepository_ProjectNothing(t *testing.T) {
+	db := &scriptedDB{}
+	repo := NewOrderSummaryPostgresRepository(db.open())
+
+	require.NoError(t, repo.Project(context.Background(), nil))
+	assert.Empty(t, db.statements)
+}
+
+func TestOrderSummaryPostgresRepository_RebuildInBatches(t *testing.T) {
+	db := &scriptedDB{match: "RETURNING order_id", batches: [][]int64{{3, 1, 2}, {7, 5, 6}, {9}}}
+	repo := NewOrderSummaryPostgresRepository(db.open())
+
+	projected, err := repo.Rebuild(context.Background(), 3)
+	require.NoError(t, err)
+	assert.Equal(t, 7, projected)
+
+	batches := db.ran("RETURNING order_id")
+	require.Len(t, batches, 3, "the rebuild stops at the first batch not full")
+	assert.Equal(t, []driver.Value{int64(0), int64(3)}, batches[0].args)
+	assert.Equal(t, []driver.Value{int64(3), int64(3)}, batches[1].args, "each batch follows the highest order of the last")
+	assert.Equal(t, []driver.Value{int64(7), int64(3)}, batches[2].args)
+	assert.Contains(t, batches[0].query, "ON CONFLICT (order_id) DO UPDATE SET", "rebuilding replaces the summaries")
+
+	assert.Len(t, db.ran("DELETE FROM order_summaries"), 1, "the summaries of deleted orders are dropped")
+}
@@ -0,0 +1,65 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+
+	"tixgo/modules/order/domain"
+	"tixgo/shared/dbtimeout"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// issueQRCodes stores the QR codes order.IssueQRCodes signed for the tickets the order sells
+func issueQRCodes(ctx context.Context, tx *sqlx.Tx, order *domain.Order) error {
+	var ids []int64
+	var codes []string
+	for _, ticket := range order.Tickets {
+		if ticket.QRCode != "" {
+			ids = append(ids, ticket.ID)
+			codes = append(codes, ticket.QRCode)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		UPDATE tickets t
+		SET qr_code = c.code, updated_at = NOW()
+		FROM unnest($1::BIGINT[], $2::TEXT[]) AS c(id, code)
+		WHERE t.id = c.id`, pq.Array(ids), pq.Array(codes))
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to issue ticket QR codes")
+	}
+
+	return nil
+}
+
+// GetTicketQR retrieves the QR code of a ticket sold by a confirmed order and not refunded since
+func (r *OrderPostgresRepository) GetTicketQR(ctx context.Context, ticketID int64) (*domain.TicketQR, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	qr := &domain.TicketQR{}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT t.id, o.user_id, e.organizer_id, t.qr_code
+		FROM tickets t
+		JOIN ticket_categories c ON c.id = t.ticket_category_id
+		JOIN events e ON e.id = c.event_id
+		JOIN order_items i ON i.ticket_id = t.id
+		JOIN orders o ON o.id = i.order_id
+		WHERE t.id = $1 AND t.qr_code IS NOT NULL AND o.status IN ('confirmed', 'partially_refunded')
+		  AND NOT EXISTS (SELECT 1 FROM refund_tickets rt WHERE rt.order_id = o.id AND rt.ticket_id = t.id)
+		LIMIT 1`, ticketID).Scan(&qr.TicketID, &qr.BuyerID, &qr.OrganizerID, &qr.Code)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrTicketQRNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get ticket QR code")
+	}
+
+	return qr, nil
+}
@@ -0,0 +1,298 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"tixgo/modules/order/domain"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/redis/go-redis/v9"
+)
+
+// seatHoldKeyTTL is how long the keys of the holds of an event outlive the last hold, for the lapsed
+// holds to be found
+const seatHoldKeyTTL = time.Hour
+
+// seatHoldEventsKey indexes the events with holds by the expiry of their latest hold
+const seatHoldEventsKey = "seathold:events"
+
+// pruneLua drops the holds past their expiry, recording their seats as lapsed. ARGV[1] is the key
+// prefix of the event.
+const pruneLua = `
+local function prune(prefix, now, ttl)
+	for _, seat in ipairs(redis.call("ZRANGEBYSCORE", prefix .. "expiry", "-inf", now)) do
+		local user = redis.call("HGET", prefix .. "owners", seat)
+		if user then
+			redis.call("SREM", prefix .. "user:" .. user, seat)
+		end
+		redis.call("HDEL", prefix .. "owners", seat)
+		redis.call("ZREM", prefix .. "expiry", seat)
+		redis.call("SADD", prefix .. "lapsed", seat)
+		redis.call("PEXPIRE", prefix .. "lapsed", ttl)
+	end
+end
+`
+
+// holdScript holds seats for a user along with the ones they hold already, all until the expiry, and
+// returns {0, seats...} or {status}.
+// ARGV: prefix, now ms, user id, expiry ms, limit, key ttl ms, seats...
+var holdScript = redis.NewScript(pruneLua + `
+local prefix, user = ARGV[1], ARGV[3]
+prune(prefix, ARGV[2], ARGV[6])
+
+local userKey = prefix .. "user:" .. user
+local count = redis.call("SCARD", userKey)
+for i = 7, #ARGV do
+	local owner = redis.call("HGET", prefix .. "owners", ARGV[i])
+	if not owner then
+		count = count + 1
+	elseif owner ~= user then
+		return {-1}
+	end
+end
+if count > tonumber(ARGV[5]) then
+	return {-2}
+end
+
+for i = 7, #ARGV do
+	redis.call("HSET", prefix .. "owners", ARGV[i], user)
+	redis.call("SADD", userKey, ARGV[i])
+	redis.call("SREM", prefix .. "lapsed", ARGV[i])
+end
+local seats = redis.call("SMEMBERS", userKey)
+for _, seat in ipairs(seats) do
+	redis.call("ZADD", prefix .. "expiry", ARGV[4], seat)
+end
+for _, key in ipairs({prefix .. "owners", prefix .. "expiry", userKey}) do
+	redis.call("PEXPIRE", key, ARGV[6])
+end
+return {0, unpack(seats)}`)
+
+const (
+	holdSeatHeld    = -1
+	holdTooManyHeld = -2
+)
+
+// getScript returns {expiry ms, seats...} of the seats a user holds, empty if none.
+// ARGV: prefix, now ms, user id, key ttl ms.
+var getScript = redis.NewScript(pruneLua + `
+prune(ARGV[1], ARGV[2], ARGV[4])
+local seats = redis.call("SMEMBERS", ARGV[1] .. "user:" .. ARGV[3])
+if #seats == 0 then
+	return {}
+end
+return {redis.call("ZSCORE", ARGV[1] .. "expiry", seats[1]), unpack(seats)}`)
+
+// releaseScript releases seats a user holds, every one when none is given, and returns the ones released.
+// ARGV: prefix, now ms, user id, key ttl ms, seats...
+var releaseScript = redis.NewScript(pruneLua + `
+local prefix, user = ARGV[1], ARGV[3]
+prune(prefix, ARGV[2], ARGV[4])
+
+local userKey = prefix .. "user:" .. user
+local seats = {}
+if #ARGV > 4 then
+	for i = 5, #ARGV do
+		table.insert(seats, ARGV[i])
+	end
+else
+	seats = redis.call("SMEMBERS", userKey)
+end
+
+local released = {}
+for _, seat in ipairs(seats) do
+	if redis.call("HGET", prefix .. "owners", seat) == user then
+		redis.call("HDEL", prefix .. "owners", seat)
+		redis.call("ZREM", prefix .. "expiry", seat)
+		redis.call("SREM", userKey, seat)
+		table.insert(released, seat)
+	end
+end
+return released`)
+
+// ownersScript returns the seats held with their holder, as HGETALL does. ARGV: prefix, now ms, key ttl ms.
+var ownersScript = redis.NewScript(pruneLua + `
+prune(ARGV[1], ARGV[2], ARGV[3])
+return redis.call("HGETALL", ARGV[1] .. "owners")`)
+
+// lapsedScript returns the seats whose hold lapsed and forgets them. ARGV: prefix, now ms, key ttl ms.
+var lapsedScript = redis.NewScript(pruneLua + `
+prune(ARGV[1], ARGV[2], ARGV[3])
+local lapsed = redis.call("SMEMBERS", ARGV[1] .. "lapsed")
+redis.call("DEL", ARGV[1] .. "lapsed")
+return lapsed`)
+
+// unindexScript drops an event from the index once its latest hold expired.
+// KEYS: index. ARGV: event id, now ms.
+var unindexScript = redis.NewScript(`
+local expiry = redis.call("ZSCORE", KEYS[1], ARGV[1])
+if expiry and tonumber(expiry) <= tonumber(ARGV[2]) then
+	redis.call("ZREM", KEYS[1], ARGV[1])
+end
+return 1`)
+
+// SeatHoldRedis implements the SeatHoldStore interface with redis. Every step runs in a lua script that
+// first drops the lapsed holds, so a seat is never held by two buyers; the keys of an event share a hash
+// tag to live on one cluster slot.
+type SeatHoldRedis struct {
+	client redis.UniversalClient
+	now    func() time.Time
+}
+
+// NewSeatHoldRedis creates a new redis seat hold store
+func NewSeatHoldRedis(client redis.UniversalClient) *SeatHoldRedis {
+	return &SeatHoldRedis{client: client, now: time.Now}
+}
+
+// Hold adds the seats of hold to the ones the buyer holds in the event and holds them all for ttl
+func (s *SeatHoldRedis) Hold(ctx context.Context, hold *domain.SeatHold, ttl time.Duration, limit int) error {
+	now := s.now()
+	expiresAt := now.Add(ttl)
+
+	args := []interface{}{seatHoldPrefix(hold.EventID), now.UnixMilli(), hold.UserID, expiresAt.UnixMilli(), limit,
+		(ttl + seatHoldKeyTTL).Milliseconds()}
+	for _, id := range hold.TicketIDs {
+		args = append(args, id)
+	}
+
+	result, err := holdScript.Run(ctx, s.client, nil, args...).Slice()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to hold seats")
+	}
+
+	switch result[0].(int64) {
+	case holdSeatHeld:
+		return domain.ErrSeatHeld
+	case holdTooManyHeld:
+		return domain.ErrTooManySeatsHeld
+	}
+
+	seats, err := parseSeats(result[1:])
+	if err != nil {
+		return err
+	}
+
+	// the index only lets the lapsed holds be found, a hold missing from it still lapses
+	err = s.client.ZAddGT(ctx, seatHoldEventsKey, redis.Z{Score: float64(expiresAt.UnixMilli()), Member: hold.EventID}).Err()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to index seat holds")
+	}
+
+	hold.TicketIDs = seats
+	hold.ExpiresAt = expiresAt.UTC()
+	return nil
+}
+
+// Get retrieves the seats the buyer holds in the event
+func (s *SeatHoldRedis) Get(ctx context.Context, eventID, userID int64) (*domain.SeatHold, error) {
+	result, err := getScript.Run(ctx, s.client, nil,
+		seatHoldPrefix(eventID), s.now().UnixMilli(), userID, seatHoldKeyTTL.Milliseconds()).StringSlice()
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get seat hold")
+	}
+	if len(result) == 0 {
+		return nil, domain.ErrSeatHoldNotFound
+	}
+
+	expiry, err := strconv.ParseFloat(result[0], 64)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to parse seat hold expiry")
+	}
+	seats, err := parseSeats(result[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.SeatHold{EventID: eventID, UserID: userID, TicketIDs: seats, ExpiresAt: time.UnixMilli(int64(expiry)).UTC()}, nil
+}
+
+// Release releases the seats of ticketIDs the buyer holds in the event, every seat they hold when empty
+func (s *SeatHoldRedis) Release(ctx context.Context, eventID, userID int64, ticketIDs []int64) ([]int64, error) {
+	args := []interface{}{seatHoldPrefix(eventID), s.now().UnixMilli(), userID, seatHoldKeyTTL.Milliseconds()}
+	for _, id := range ticketIDs {
+		args = append(args, id)
+	}
+
+	result, err := releaseScript.Run(ctx, s.client, nil, args...).StringSlice()
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to release seats")
+	}
+	return parseSeats(result)
+}
+
+// HeldByOthers returns the seats of the event held by buyers other than the user
+func (s *SeatHoldRedis) HeldByOthers(ctx context.Context, eventID, userID int64) ([]int64, error) {
+	result, err := ownersScript.Run(ctx, s.client, nil,
+		seatHoldPrefix(eventID), s.now().UnixMilli(), seatHoldKeyTTL.Milliseconds()).StringSlice()
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get held seats")
+	}
+
+	user := strconv.FormatInt(userID, 10)
+	var seats []string
+	for i := 0; i+1 < len(result); i += 2 {
+		if result[i+1] != user {
+			seats = append(seats, result[i])
+		}
+	}
+	return parseSeats(seats)
+}
+
+// Lapsed returns the seats whose hold lapsed since it was last called, by event. The events whose
+// latest hold expired leave the index.
+func (s *SeatHoldRedis) Lapsed(ctx context.Context) (map[int64][]int64, error) {
+	events, err := s.client.ZRange(ctx, seatHoldEventsKey, 0, -1).Result()
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list events with seat holds")
+	}
+
+	lapsed := make(map[int64][]int64)
+	for _, member := range events {
+		eventID, err := strconv.ParseInt(member, 10, 64)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to parse event id")
+		}
+
+		now := s.now().UnixMilli()
+		result, err := lapsedScript.Run(ctx, s.client, nil, seatHoldPrefix(eventID), now, seatHoldKeyTTL.Milliseconds()).StringSlice()
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get lapsed seat holds")
+		}
+		seats, err := parseSeats(result)
+		if err != nil {
+			return nil, err
+		}
+		if len(seats) > 0 {
+			lapsed[eventID] = seats
+		}
+
+		if err := unindexScript.Run(ctx, s.client, []string{seatHoldEventsKey}, eventID, now).Err(); err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to unindex seat holds")
+		}
+	}
+
+	return lapsed, nil
+}
+
+// seatHoldPrefix is the key prefix of the holds of an event
+func seatHoldPrefix(eventID int64) string {
+	return fmt.Sprintf("seathold:{%d}:", eventID)
+}
+
+// parseSeats parses the ticket ids of seats returned by a script, sorted
+func parseSeats[T any](values []T) ([]int64, error) {
+	seats := make([]int64, len(values))
+	for i, value := range values {
+		id, err := strconv.ParseInt(fmt.Sprint(value), 10, 64)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to parse seat")
+		}
+		seats[i] = id
+	}
+	slices.Sort(seats)
+	return seats, nil
+}
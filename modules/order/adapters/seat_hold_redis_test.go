@@ -0,0 +1,121 @@
+package adapters
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tixgo/modules/order/domain"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSeatHolds(t *testing.T) (*SeatHoldRedis, *time.Time) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	now := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+	store := NewSeatHoldRedis(client)
+	store.now = func() time.Time { return now }
+	return store, &now
+}
+
+func TestSeatHoldRedis_Hold(t *testing.T) {
+	ctx := context.Background()
+	store, now := newTestSeatHolds(t)
+
+	hold := &domain.SeatHold{EventID: 1, UserID: 10, TicketIDs: []int64{3, 1}}
+	require.NoError(t, store.Hold(ctx, hold, 10*time.Minute, 4))
+	assert.Equal(t, []int64{1, 3}, hold.TicketIDs)
+	assert.Equal(t, now.Add(10*time.Minute), hold.ExpiresAt)
+
+	err := store.Hold(ctx, &domain.SeatHold{EventID: 1, UserID: 20, TicketIDs: []int64{2, 3}}, 10*time.Minute, 4)
+	assert.Equal(t, domain.ErrSeatHeld, err, "a seat is held by one buyer at a time")
+	_, err = store.Get(ctx, 1, 20)
+	assert.Equal(t, domain.ErrSeatHoldNotFound, err, "a refused hold holds nothing")
+
+	require.NoError(t, store.Hold(ctx, &domain.SeatHold{EventID: 2, UserID: 20, TicketIDs: []int64{3}}, 10*time.Minute, 4),
+		"the seats of other events are apart")
+
+	*now = now.Add(5 * time.Minute)
+	more := &domain.SeatHold{EventID: 1, UserID: 10, TicketIDs: []int64{3, 5}}
+	require.NoError(t, store.Hold(ctx, more, 10*time.Minute, 4))
+	assert.Equal(t, []int64{1, 3, 5}, more.TicketIDs, "the seats add up to the ones held")
+	assert.Equal(t, now.Add(10*time.Minute), more.ExpiresAt, "the whole hold is extended")
+
+	err = store.Hold(ctx, &domain.SeatHold{EventID: 1, UserID: 10, TicketIDs: []int64{6, 7}}, 10*time.Minute, 4)
+	assert.Equal(t, domain.ErrTooManySeatsHeld, err)
+
+	others, err := store.HeldByOthers(ctx, 1, 20)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 3, 5}, others)
+	mine, err := store.HeldByOthers(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Empty(t, mine)
+}
+
+func TestSeatHoldRedis_Release(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestSeatHolds(t)
+
+	require.NoError(t, store.Hold(ctx, &domain.SeatHold{EventID: 1, UserID: 10, TicketIDs: []int64{1, 2, 3}}, 10*time.Minute, 4))
+
+	released, err := store.Release(ctx, 1, 20, []int64{1})
+	require.NoError(t, err)
+	assert.Empty(t, released, "the seats of another buyer are left alone")
+
+	released, err = store.Release(ctx, 1, 10, []int64{2, 9})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2}, released)
+
+	require.NoError(t, store.Hold(ctx, &domain.SeatHold{EventID: 1, UserID: 20, TicketIDs: []int64{2}}, 10*time.Minute, 4),
+		"a released seat can be held by another buyer")
+
+	released, err = store.Release(ctx, 1, 10, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 3}, released, "every seat is released when none is given")
+
+	_, err = store.Get(ctx, 1, 10)
+	assert.Equal(t, domain.ErrSeatHoldNotFound, err)
+}
+
+func TestSeatHoldRedis_Lapse(t *testing.T) {
+	ctx := context.Background()
+	store, now := newTestSeatHolds(t)
+
+	require.NoError(t, store.Hold(ctx, &domain.SeatHold{EventID: 1, UserID: 10, TicketIDs: []int64{1, 2}}, 10*time.Minute, 4))
+	require.NoError(t, store.Hold(ctx, &domain.SeatHold{EventID: 2, UserID: 10, TicketIDs: []int64{7}}, 20*time.Minute, 4))
+
+	lapsed, err := store.Lapsed(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, lapsed)
+
+	*now = now.Add(10 * time.Minute)
+	_, err = store.Get(ctx, 1, 10)
+	assert.Equal(t, domain.ErrSeatHoldNotFound, err, "a hold lapses at its expiry")
+
+	require.NoError(t, store.Hold(ctx, &domain.SeatHold{EventID: 1, UserID: 20, TicketIDs: []int64{2}}, 10*time.Minute, 4),
+		"a lapsed seat can be held by another buyer")
+
+	lapsed, err = store.Lapsed(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[int64][]int64{1: {1}}, lapsed, "the seats held again are not reported")
+
+	lapsed, err = store.Lapsed(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, lapsed, "lapsed seats are reported once")
+
+	*now = now.Add(10 * time.Minute)
+	lapsed, err = store.Lapsed(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[int64][]int64{1: {2}, 2: {7}}, lapsed)
+
+	events, err := store.client.ZCard(ctx, seatHoldEventsKey).Result()
+	require.NoError(t, err)
+	assert.Zero(t, events, "the events without holds leave the index")
+}
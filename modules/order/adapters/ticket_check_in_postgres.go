@@ -0,0 +1,165 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+
+	eventDomain "tixgo/modules/event/domain"
+	"tixgo/modules/order/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/pgerr"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+// TicketCheckInPostgresRepository implements the TicketCheckInRepository interface using PostgreSQL
+type TicketCheckInPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewTicketCheckInPostgresRepository creates a new PostgreSQL ticket check-in repository
+func NewTicketCheckInPostgresRepository(db *sqlx.DB) *TicketCheckInPostgresRepository {
+	return &TicketCheckInPostgresRepository{db: db}
+}
+
+// GetTicket retrieves the ticket of the claims of a QR code as sold by the order of the claims. The
+// ticket is revoked once the order no longer holds it.
+func (r *TicketCheckInPostgresRepository) GetTicket(ctx context.Context, claims domain.TicketClaims, organizerID int64) (*domain.TicketCheckIn, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	ticket := &domain.TicketCheckIn{}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT t.id, o.id, c.event_id, c.id, c.name, COALESCE(t.seat_section, ''), COALESCE(t.seat_row, ''),
+		       COALESCE(t.seat_number, ''), COALESCE(a.name, ''), COALESCE(t.status::TEXT, 'available'),
+		       o.status NOT IN ('confirmed', 'partially_refunded')
+		           OR EXISTS (SELECT 1 FROM refund_tickets rt WHERE rt.order_id = o.id AND rt.ticket_id = t.id),
+		       ci.checked_in_at, COALESCE(ci.checked_in_by, 0)
+		FROM order_items i
+		JOIN orders o ON o.id = i.order_id
+		JOIN tickets t ON t.id = i.ticket_id
+		JOIN ticket_categories c ON c.id = t.ticket_category_id
+		JOIN events e ON e.id = c.event_id
+		LEFT JOIN ticket_attendees a ON a.order_id = o.id AND a.ticket_id = t.id
+		LEFT JOIN ticket_check_ins ci ON ci.order_id = o.id AND ci.ticket_id = t.id
+		WHERE i.order_id = $1 AND i.ticket_id = $2 AND c.event_id = $3 AND e.organizer_id = $4`,
+		claims.OrderID, claims.TicketID, claims.EventID, organizerID).Scan(
+		&ticket.TicketID,
+		&ticket.OrderID,
+		&ticket.EventID,
+		&ticket.TicketCategoryID,
+		&ticket.TicketCategoryName,
+		&ticket.SeatSection,
+		&ticket.SeatRow,
+		&ticket.SeatNumber,
+		&ticket.AttendeeName,
+		&ticket.Status,
+		&ticket.Revoked,
+		&ticket.CheckedInAt,
+		&ticket.CheckedInBy,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrTicketNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get ticket")
+	}
+
+	return ticket, nil
+}
+
+// CheckIn records the check-in of a ticket and marks it used. Two scans of the same ticket race on the
+// primary key of the check-in; a refund racing the scan on the status of the ticket.
+func (r *TicketCheckInPostgresRepository) CheckIn(ctx context.Context, ticket *domain.TicketCheckIn) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO ticket_check_ins (order_id, ticket_id, event_id, checked_in_by, checked_in_at)
+		VALUES ($1, $2, $3, $4, $5)`,
+		ticket.OrderID, ticket.TicketID, ticket.EventID, ticket.CheckedInBy, ticket.CheckedInAt)
+	if err != nil {
+		if pgerr.IsUniqueViolation(err) {
+			return domain.ErrTicketAlreadyCheckedIn
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to record check-in")
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE tickets SET status = 'used', updated_at = NOW()
+		WHERE id = $1 AND status = 'sold'
+		  AND NOT EXISTS (SELECT 1 FROM refund_tickets rt WHERE rt.order_id = $2 AND rt.ticket_id = $1)`,
+		ticket.TicketID, ticket.OrderID)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to mark ticket used")
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get rows affected")
+	}
+	if updated == 0 {
+		return domain.ErrTicketRevoked
+	}
+
+	if err := tx.Commit(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to commit check-in")
+	}
+
+	return nil
+}
+
+// GetStats computes the check-in statistics of an event. The tickets expected are the ones sold or
+// given away and not refunded, whether checked in already or not.
+func (r *TicketCheckInPostgresRepository) GetStats(ctx context.Context, eventID, organizerID int64) (*domain.CheckInStats, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	stats := &domain.CheckInStats{EventID: eventID}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT (SELECT MAX(checked_in_at) FROM ticket_check_ins WHERE event_id = e.id)
+		FROM events e
+		WHERE e.id = $1 AND e.organizer_id = $2`, eventID, organizerID).Scan(&stats.LastCheckInAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, eventDomain.ErrEventNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get event")
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT c.id, c.name,
+		       COUNT(t.id) FILTER (WHERE t.status IN ('sold', 'used'))::INT,
+		       COUNT(t.id) FILTER (WHERE t.status = 'used')::INT
+		FROM ticket_categories c
+		LEFT JOIN tickets t ON t.ticket_category_id = c.id
+		WHERE c.event_id = $1
+		GROUP BY c.id, c.name
+		ORDER BY c.id`, eventID)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get check-in statistics")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		category := &domain.CategoryCheckInStats{}
+		if err := rows.Scan(&category.TicketCategoryID, &category.Name, &category.Expected, &category.CheckedIn); err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan check-in statistics")
+		}
+		stats.Expected += category.Expected
+		stats.CheckedIn += category.CheckedIn
+		stats.TicketCategories = append(stats.TicketCategories, category)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to iterate check-in statistics")
+	}
+
+	return stats, nil
+}
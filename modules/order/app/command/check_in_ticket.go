@@ -0,0 +1,98 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/order/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// CheckInTicketCommand represents the command of an organizer to admit the holder of a ticket at the
+// door of their event by scanning its QR code
+type CheckInTicketCommand struct {
+	EventID     int64 `json:"-"`
+	OrganizerID int64 `json:"-"`
+	// Code is the payload of the QR code scanned
+	Code string `json:"code" binding:"required,max=255"`
+}
+
+// SeatResult represents the seat of a reserved-seating ticket
+type SeatResult struct {
+	Section string `json:"section"`
+	Row     string `json:"row"`
+	Number  string `json:"number"`
+}
+
+// CheckInResult represents a ticket checked in, with what the door staff checks against its holder
+type CheckInResult struct {
+	TicketID           int64       `json:"ticket_id"`
+	OrderID            int64       `json:"order_id"`
+	EventID            int64       `json:"event_id"`
+	TicketCategoryID   int64       `json:"ticket_category_id"`
+	TicketCategoryName string      `json:"ticket_category_name"`
+	Seat               *SeatResult `json:"seat,omitempty"`
+	AttendeeName       string      `json:"attendee_name,omitempty"`
+	CheckedInAt        string      `json:"checked_in_at"`
+}
+
+// CheckInTicketHandler handles the check-ins of tickets
+type CheckInTicketHandler struct {
+	checkInRepo domain.TicketCheckInRepository
+	verifier    domain.TicketVerifier
+}
+
+// NewCheckInTicketHandler creates a new check-in ticket handler
+func NewCheckInTicketHandler(checkInRepo domain.TicketCheckInRepository, verifier domain.TicketVerifier) *CheckInTicketHandler {
+	return &CheckInTicketHandler{
+		checkInRepo: checkInRepo,
+		verifier:    verifier,
+	}
+}
+
+// Handle executes the check-in ticket command. The code must be signed by us for a ticket of the
+// event its order still holds; a ticket is checked in once.
+func (h *CheckInTicketHandler) Handle(ctx context.Context, cmd CheckInTicketCommand) (*CheckInResult, error) {
+	claims, err := domain.OpenTicketCode(h.verifier, cmd.Code, cmd.EventID)
+	if err != nil {
+		return nil, err
+	}
+
+	ticket, err := h.checkInRepo.GetTicket(ctx, claims, cmd.OrganizerID)
+	if err != nil {
+		if err == domain.ErrTicketNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get ticket")
+	}
+
+	if err := ticket.CheckIn(cmd.OrganizerID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := h.checkInRepo.CheckIn(ctx, ticket); err != nil {
+		if err == domain.ErrTicketAlreadyCheckedIn || err == domain.ErrTicketRevoked {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to check in ticket")
+	}
+
+	return toCheckInResult(ticket), nil
+}
+
+func toCheckInResult(ticket *domain.TicketCheckIn) *CheckInResult {
+	result := &CheckInResult{
+		TicketID:           ticket.TicketID,
+		OrderID:            ticket.OrderID,
+		EventID:            ticket.EventID,
+		TicketCategoryID:   ticket.TicketCategoryID,
+		TicketCategoryName: ticket.TicketCategoryName,
+		AttendeeName:       ticket.AttendeeName,
+		CheckedInAt:        ticket.CheckedInAt.UTC().Format("2006-01-02T15:04:05Z"),
+	}
+	if ticket.SeatSection != "" {
+		result.Seat = &SeatResult{Section: ticket.SeatSection, Row: ticket.SeatRow, Number: ticket.SeatNumber}
+	}
+	return result
+}
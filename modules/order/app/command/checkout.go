@@ -0,0 +1,235 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	eventDomain "tixgo/modules/event/domain"
+	"tixgo/modules/order/domain"
+	promotionDomain "tixgo/modules/promotion/domain"
+	templateDomain "tixgo/modules/template/domain"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// CheckoutLineInput is the quantity of tickets of a category asked for in a checkout, with the seats the
+// buyer selected and holds for a seated category
+type CheckoutLineInput struct {
+	TicketCategoryID int64   `json:"ticket_category_id" binding:"required"`
+	Quantity         int     `json:"quantity" binding:"required,min=1"`
+	SeatIDs          []int64 `json:"seat_ids" binding:"max=20"`
+}
+
+const (
+	// PaymentMethodCard pays the order at checkout, through the payment integration
+	PaymentMethodCard = "card"
+	// PaymentMethodBankTransfer pays the order by bank transfer, against a pro-forma invoice
+	PaymentMethodBankTransfer = "bank_transfer"
+)
+
+// BillingInput is the company a pro-forma invoice is made out to
+type BillingInput struct {
+	CompanyName string `json:"company_name" binding:"required,max=255"`
+	TaxID       string `json:"tax_id" binding:"max=50"`
+	Address     string `json:"address" binding:"required,max=500"`
+	Email       string `json:"email" binding:"required,email,max=255"`
+}
+
+// AccommodationsInput is the accessibility and dietary needs the buyer asks the organizer to accommodate
+type AccommodationsInput struct {
+	AccessNeeds  []string `json:"access_needs" binding:"max=9"`
+	DietaryNeeds []string `json:"dietary_needs" binding:"max=9"`
+	Notes        string   `json:"notes" binding:"max=500"`
+}
+
+// CheckoutCommand represents the command of a buyer to order tickets of an event, discounted by the
+// promo code they entered if any. Orders are paid by card unless PaymentMethod is bank_transfer, which
+// needs the company to invoice. Accommodations are passed on to the organizer of the event.
+type CheckoutCommand struct {
+	UserID         int64                `json:"-"`
+	EventID        int64                `json:"event_id" binding:"required"`
+	Lines          []CheckoutLineInput  `json:"lines" binding:"required,min=1,max=20,dive"`
+	PromoCode      string               `json:"promo_code" binding:"max=50"`
+	PaymentMethod  string               `json:"payment_method" binding:"omitempty,oneof=card bank_transfer"`
+	Billing        *BillingInput        `json:"billing" binding:"required_if=PaymentMethod bank_transfer"`
+	Accommodations *AccommodationsInput `json:"accommodations"`
+}
+
+// BankTransferOptions configures the checkouts paid by bank transfer, which are refused while the
+// account has no IBAN
+type BankTransferOptions struct {
+	// Hold is how long the order holds its tickets waiting for the transfer
+	Hold    time.Duration
+	Account domain.BankAccount
+}
+
+// CheckoutHandler handles checkouts
+type CheckoutHandler struct {
+	orderRepo domain.OrderRepository
+	promoRepo promotionDomain.PromoCodeRepository
+	hold      time.Duration
+	transfer  BankTransferOptions
+	seatHolds domain.SeatHoldStore
+	invoices  invoiceMailer
+	notifier  orderNotifier
+}
+
+// NewCheckoutHandler creates a new checkout handler, its orders holding their tickets for hold, or for
+// the hold of transfer when paid by bank transfer
+func NewCheckoutHandler(orderRepo domain.OrderRepository, promoRepo promotionDomain.PromoCodeRepository, seatHolds domain.SeatHoldStore, templateRepo templateDomain.TemplateRepository, templateRenderer templateDomain.TemplateRenderer, hold time.Duration, transfer BankTransferOptions, eventBus messaging.EventBus) *CheckoutHandler {
+	return &CheckoutHandler{
+		orderRepo: orderRepo,
+		promoRepo: promoRepo,
+		hold:      hold,
+		transfer:  transfer,
+		seatHolds: seatHolds,
+		invoices: invoiceMailer{
+			templateRepo:     templateRepo,
+			templateRenderer: templateRenderer,
+			eventBus:         eventBus,
+			account:          transfer.Account,
+		},
+		notifier: orderNotifier{eventBus: eventBus},
+	}
+}
+
+// Handle executes the checkout command. The tickets are reserved, the promo code redeemed and the
+// pending order created in one transaction, so a checkout either holds every ticket asked for or none.
+// An order paid by bank transfer awaits it instead, and its pro-forma invoice is mailed to the company;
+// a mail failing is logged and does not fail the checkout. The seats selected must be held by the buyer,
+// whose hold ends once the order holds them; the best available seats leave out the seats other buyers
+// hold.
+func (h *CheckoutHandler) Handle(ctx context.Context, cmd CheckoutCommand) (*OrderResult, error) {
+	byTransfer := cmd.PaymentMethod == PaymentMethodBankTransfer
+	if byTransfer {
+		if h.transfer.Account.IBAN == "" {
+			return nil, domain.ErrBankTransferDisabled
+		}
+		if cmd.Billing == nil {
+			return nil, syserr.New(syserr.InvalidArgumentCode, "billing is required to pay by bank transfer")
+		}
+	}
+
+	categoryIDs := make([]int64, len(cmd.Lines))
+	lines := make([]*domain.OrderLine, len(cmd.Lines))
+	var selected []int64
+	for i, line := range cmd.Lines {
+		categoryIDs[i] = line.TicketCategoryID
+		lines[i] = &domain.OrderLine{TicketCategoryID: line.TicketCategoryID, Quantity: line.Quantity, SeatIDs: line.SeatIDs}
+		selected = append(selected, line.SeatIDs...)
+	}
+
+	event, err := h.orderRepo.GetCheckoutEvent(ctx, cmd.EventID, categoryIDs)
+	if err != nil {
+		if err == eventDomain.ErrEventNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get event")
+	}
+
+	now := time.Now()
+	order, err := domain.NewOrder(cmd.UserID, event, lines, h.hold, now)
+	if err != nil {
+		return nil, err
+	}
+	if byTransfer {
+		billing := domain.BillingDetails{
+			CompanyName: cmd.Billing.CompanyName,
+			TaxID:       cmd.Billing.TaxID,
+			Address:     cmd.Billing.Address,
+			Email:       cmd.Billing.Email,
+		}
+		if err := order.PayByTransfer(billing, h.transfer.Hold, now); err != nil {
+			return nil, err
+		}
+	}
+	if cmd.Accommodations != nil {
+		order.Accommodations, err = toAccommodations(cmd.Accommodations)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var promo *promotionDomain.PromoCode
+	if cmd.PromoCode != "" {
+		promo, err = h.promoRepo.GetByCode(ctx, promotionDomain.NormalizeCode(cmd.PromoCode))
+		if err != nil {
+			if err == promotionDomain.ErrPromoCodeNotFound {
+				return nil, err
+			}
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get promo code")
+		}
+		if err := promo.CheckRedeemable(event.ID, now); err != nil {
+			return nil, err
+		}
+	}
+
+	heldSeats, err := h.checkSeatHolds(ctx, cmd.EventID, cmd.UserID, selected)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.orderRepo.Create(ctx, order, promo, heldSeats); err != nil {
+		switch err {
+		case eventDomain.ErrSoldOut, domain.ErrSeatUnavailable, promotionDomain.ErrPromoCodeExhausted, promotionDomain.ErrPromoCodeUserLimit:
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to create order")
+	}
+
+	// the order holds the seats now, a hold left behind only lapses
+	if len(selected) > 0 {
+		if _, err := h.seatHolds.Release(ctx, cmd.EventID, cmd.UserID, selected); err != nil {
+			logger.Warning(ctx, "Failed to release seat hold", logger.F("order_id", order.ID), logger.F("error", err))
+		}
+	}
+
+	h.notifier.created(ctx, order)
+
+	if order.Invoice != nil {
+		if err := h.invoices.send(ctx, order); err != nil {
+			logger.Error(ctx, "Failed to send pro-forma invoice", logger.F("order_id", order.ID), logger.F("error", err))
+		}
+	}
+
+	return ToOrderResult(order), nil
+}
+
+// checkSeatHolds checks the buyer holds the seats they selected and returns the seats other buyers hold.
+// The database decides which seats are sold, so the seats held by others are left out on a best effort
+// basis: without them, a checkout may take a seat another buyer is selecting.
+func (h *CheckoutHandler) checkSeatHolds(ctx context.Context, eventID, userID int64, selected []int64) ([]int64, error) {
+	if len(selected) > 0 {
+		hold, err := h.seatHolds.Get(ctx, eventID, userID)
+		if err != nil {
+			if err == domain.ErrSeatHoldNotFound {
+				return nil, domain.ErrSeatNotHeld
+			}
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get seat hold")
+		}
+		if !hold.Holds(selected) {
+			return nil, domain.ErrSeatNotHeld
+		}
+	}
+
+	heldSeats, err := h.seatHolds.HeldByOthers(ctx, eventID, userID)
+	if err != nil {
+		logger.Warning(ctx, "Failed to get held seats", logger.F("event_id", eventID), logger.F("error", err))
+		return nil, nil
+	}
+	return heldSeats, nil
+}
+
+func toAccommodations(input *AccommodationsInput) (*domain.Accommodations, error) {
+	accessNeeds := make([]domain.AccessNeed, len(input.AccessNeeds))
+	for i, need := range input.AccessNeeds {
+		accessNeeds[i] = domain.AccessNeed(need)
+	}
+	dietaryNeeds := make([]domain.DietaryNeed, len(input.DietaryNeeds))
+	for i, need := range input.DietaryNeeds {
+		dietaryNeeds[i] = domain.DietaryNeed(need)
+	}
+	return domain.NewAccommodations(accessNeeds, dietaryNeeds, input.Notes)
+}
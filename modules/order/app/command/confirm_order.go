@@ -0,0 +1,61 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/order/domain"
+
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ConfirmOrderCommand represents the command to confirm a pending order once paid
+type ConfirmOrderCommand struct {
+	ID int64
+}
+
+// ConfirmOrderHandler handles order confirmations
+type ConfirmOrderHandler struct {
+	orderRepo domain.OrderRepository
+	sealer    domain.TicketSealer
+	notifier  orderNotifier
+}
+
+// NewConfirmOrderHandler creates a new confirm order handler
+func NewConfirmOrderHandler(orderRepo domain.OrderRepository, sealer domain.TicketSealer, eventBus messaging.EventBus) *ConfirmOrderHandler {
+	return &ConfirmOrderHandler{
+		orderRepo: orderRepo,
+		sealer:    sealer,
+		notifier:  orderNotifier{eventBus: eventBus},
+	}
+}
+
+// Handle executes the confirm order command, selling the tickets the order holds with their QR codes
+func (h *ConfirmOrderHandler) Handle(ctx context.Context, cmd ConfirmOrderCommand) (*OrderResult, error) {
+	order, err := h.orderRepo.GetByID(ctx, cmd.ID)
+	if err != nil {
+		if err == domain.ErrOrderNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get order")
+	}
+
+	if err := order.Confirm(time.Now()); err != nil {
+		return nil, err
+	}
+	if err := order.IssueQRCodes(h.sealer); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to issue ticket QR codes")
+	}
+
+	if err := h.orderRepo.Confirm(ctx, order); err != nil {
+		if err == domain.ErrOrderNotPending {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to confirm order")
+	}
+
+	h.notifier.confirmed(ctx, order)
+
+	return ToOrderResult(order), nil
+}
@@ -0,0 +1,61 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	eventDomain "tixgo/modules/event/domain"
+	"tixgo/modules/order/domain"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// expireBatchSize bounds the orders expired per transaction
+const expireBatchSize = 100
+
+// ExpireOrdersHandler cancels the checkout orders left unpaid past their expiry, giving their tickets
+// back to sale
+type ExpireOrdersHandler struct {
+	orderRepo domain.OrderRepository
+	notifier  orderNotifier
+}
+
+// NewExpireOrdersHandler creates a new expire orders handler
+func NewExpireOrdersHandler(orderRepo domain.OrderRepository, eventBus messaging.EventBus) *ExpireOrdersHandler {
+	return &ExpireOrdersHandler{
+		orderRepo: orderRepo,
+		notifier:  orderNotifier{eventBus: eventBus},
+	}
+}
+
+// Handle expires the orders batch by batch
+func (h *ExpireOrdersHandler) Handle(ctx context.Context) error {
+	now := time.Now()
+	total := 0
+
+	for {
+		expired, err := h.orderRepo.ExpirePending(ctx, now, expireBatchSize)
+		if err != nil {
+			return syserr.Wrap(err, syserr.InternalCode, "failed to expire orders")
+		}
+
+		orderIDs := make([]int64, len(expired))
+		for i, order := range expired {
+			orderIDs[i] = order.ID
+			h.notifier.seatsChanged(ctx, order.EventID, order.SeatedTicketIDs(), eventDomain.SeatStatusAvailable)
+		}
+		h.notifier.ordersChanged(ctx, orderIDs...)
+
+		total += len(expired)
+		if len(expired) < expireBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		logger.Info(ctx, "Expired unpaid orders", logger.F("count", total))
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package command
+
+import (
+	"context"
+
+	eventDomain "tixgo/modules/event/domain"
+	"tixgo/modules/order/domain"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ExpireSeatHoldsHandler announces the seats whose hold lapsed to the seat maps. The seats are on sale
+// again as soon as their hold lapses, this only updates the seat maps.
+type ExpireSeatHoldsHandler struct {
+	orderRepo domain.OrderRepository
+	seatHolds domain.SeatHoldStore
+	notifier  orderNotifier
+}
+
+// NewExpireSeatHoldsHandler creates a new expire seat holds handler
+func NewExpireSeatHoldsHandler(orderRepo domain.OrderRepository, seatHolds domain.SeatHoldStore, eventBus messaging.EventBus) *ExpireSeatHoldsHandler {
+	return &ExpireSeatHoldsHandler{
+		orderRepo: orderRepo,
+		seatHolds: seatHolds,
+		notifier:  orderNotifier{eventBus: eventBus},
+	}
+}
+
+// Handle announces the lapsed seats still available, a lapsed seat may have been checked out since
+func (h *ExpireSeatHoldsHandler) Handle(ctx context.Context) error {
+	lapsed, err := h.seatHolds.Lapsed(ctx)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get lapsed seat holds")
+	}
+
+	total := 0
+	for eventID, seats := range lapsed {
+		available, err := h.orderRepo.AvailableSeats(ctx, eventID, seats)
+		if err != nil {
+			return syserr.Wrap(err, syserr.InternalCode, "failed to get available seats")
+		}
+		h.notifier.seatsChanged(ctx, eventID, available, eventDomain.SeatStatusAvailable)
+		total += len(seats)
+	}
+
+	if total > 0 {
+		logger.Info(ctx, "Expired seat holds", logger.F("count", total))
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	eventDomain "tixgo/modules/event/domain"
+	"tixgo/modules/order/domain"
+
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// HoldSeatsCommand represents the command of a buyer to hold the seats they select on the seat map of an
+// event while they check out
+type HoldSeatsCommand struct {
+	EventID int64   `json:"-"`
+	UserID  int64   `json:"-"`
+	SeatIDs []int64 `json:"seat_ids" binding:"required,min=1,max=20"`
+}
+
+// HoldSeatsHandler handles seat holds
+type HoldSeatsHandler struct {
+	orderRepo domain.OrderRepository
+	seatHolds domain.SeatHoldStore
+	ttl       time.Duration
+	notifier  orderNotifier
+}
+
+// NewHoldSeatsHandler creates a new hold seats handler, its holds lasting ttl
+func NewHoldSeatsHandler(orderRepo domain.OrderRepository, seatHolds domain.SeatHoldStore, ttl time.Duration, eventBus messaging.EventBus) *HoldSeatsHandler {
+	return &HoldSeatsHandler{
+		orderRepo: orderRepo,
+		seatHolds: seatHolds,
+		ttl:       ttl,
+		notifier:  orderNotifier{eventBus: eventBus},
+	}
+}
+
+// Handle executes the hold seats command. The seats are added to the ones the buyer already holds in the
+// event, at most the tickets of an order, and the whole hold lasts ttl from now; they show as held on the
+// seat maps.
+func (h *HoldSeatsHandler) Handle(ctx context.Context, cmd HoldSeatsCommand) (*SeatHoldResult, error) {
+	event, err := h.orderRepo.GetCheckoutEvent(ctx, cmd.EventID, nil)
+	if err != nil {
+		if err == eventDomain.ErrEventNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get event")
+	}
+
+	hold, err := domain.NewSeatHold(cmd.UserID, event, cmd.SeatIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	available, err := h.orderRepo.AvailableSeats(ctx, event.ID, hold.TicketIDs)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get available seats")
+	}
+	if len(available) != len(hold.TicketIDs) {
+		return nil, domain.ErrSeatUnavailable
+	}
+
+	if err := h.seatHolds.Hold(ctx, hold, h.ttl, event.MaxTicketsPerOrder); err != nil {
+		switch err {
+		case domain.ErrSeatHeld, domain.ErrTooManySeatsHeld:
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to hold seats")
+	}
+
+	h.notifier.seatsChanged(ctx, event.ID, cmd.SeatIDs, eventDomain.SeatStatusHeld)
+
+	return ToSeatHoldResult(hold), nil
+}
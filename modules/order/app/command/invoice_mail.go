@@ -0,0 +1,86 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/order/domain"
+	templateDomain "tixgo/modules/template/domain"
+	sharedMail "tixgo/shared/events/mail"
+	sharedKafka "tixgo/shared/kafka"
+
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/notification/mail"
+	"github.com/duongptryu/gox/syserr"
+)
+
+const SlugOrderProformaInvoice = "order-proforma-invoice"
+
+// invoiceMailer mails the pro-forma invoices of the orders paid by bank transfer
+type invoiceMailer struct {
+	templateRepo     templateDomain.TemplateRepository
+	templateRenderer templateDomain.TemplateRenderer
+	eventBus         messaging.EventBus
+	account          domain.BankAccount
+}
+
+// send mails the pro-forma invoice of an order to the company it is made out to and to the buyer
+func (m *invoiceMailer) send(ctx context.Context, order *domain.Order) error {
+	invoice := order.Invoice
+
+	template, err := m.templateRepo.GetBySlug(ctx, SlugOrderProformaInvoice)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get template")
+	}
+
+	lines := make([]map[string]interface{}, len(order.Lines))
+	for i, line := range order.Lines {
+		lines[i] = map[string]interface{}{
+			"name":       line.TicketCategoryName,
+			"quantity":   line.Quantity,
+			"unit_price": line.UnitPrice,
+			"subtotal":   line.Subtotal,
+		}
+	}
+
+	rendered, err := m.templateRenderer.Render(ctx, template, map[string]interface{}{
+		"event_title":       order.EventTitle,
+		"order_number":      order.OrderNumber,
+		"invoice_number":    invoice.Number,
+		"payment_reference": invoice.PaymentReference,
+		"company_name":      invoice.Billing.CompanyName,
+		"tax_id":            invoice.Billing.TaxID,
+		"billing_address":   invoice.Billing.Address,
+		"lines":             lines,
+		"total_amount":      order.TotalAmount,
+		"discount_amount":   order.DiscountAmount,
+		"final_amount":      order.FinalAmount,
+		"currency":          order.Currency,
+		"issued_at":         invoice.IssuedAt.Format("2006-01-02"),
+		"due_at":            invoice.DueAt.Format("2006-01-02"),
+		"beneficiary":       m.account.Beneficiary,
+		"bank_name":         m.account.BankName,
+		"iban":              m.account.IBAN,
+		"bic":               m.account.BIC,
+	}, templateDomain.RenderOptions{OrganizerID: order.OrganizerID})
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to render template")
+	}
+
+	to := []mail.EmailAddress{{Email: invoice.Billing.Email, Name: invoice.Billing.CompanyName}}
+	if order.Email != "" && order.Email != invoice.Billing.Email {
+		to = append(to, mail.EmailAddress{Email: order.Email, Name: ""})
+	}
+
+	err = m.eventBus.PublishEvent(sharedKafka.WithPartitionKey(ctx, invoice.Billing.Email), &sharedMail.EventSendMail{
+		ToMail:      to,
+		Subject:     rendered.Subject,
+		HTMLBody:    rendered.Content,
+		Priority:    mail.PriorityHigh,
+		OrganizerID: order.OrganizerID,
+	})
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to publish send mail event")
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package command
+
+import "tixgo/modules/order/domain"
+
+// BillingResult represents the company an invoice is made out to
+type BillingResult struct {
+	CompanyName string `json:"company_name"`
+	TaxID       string `json:"tax_id"`
+	Address     string `json:"address"`
+	Email       string `json:"email"`
+}
+
+// BankAccountResult represents the account a transfer is paid into
+type BankAccountResult struct {
+	Beneficiary string `json:"beneficiary"`
+	BankName    string `json:"bank_name"`
+	IBAN        string `json:"iban"`
+	BIC         string `json:"bic"`
+}
+
+// InvoiceResult represents the pro-forma invoice of an order paid by bank transfer
+type InvoiceResult struct {
+	InvoiceNumber    string             `json:"invoice_number"`
+	PaymentReference string             `json:"payment_reference"`
+	OrderID          int64              `json:"order_id"`
+	OrderNumber      string             `json:"order_number"`
+	OrderStatus      domain.OrderStatus `json:"order_status"`
+	EventTitle       string             `json:"event_title"`
+	Billing          BillingResult      `json:"billing"`
+	Lines            []*OrderLineResult `json:"lines"`
+	TotalAmount      string             `json:"total_amount"`
+	Discount         string             `json:"discount_amount"`
+	FinalAmount      string             `json:"final_amount"`
+	Currency         string             `json:"currency"`
+	PayTo            BankAccountResult  `json:"pay_to"`
+	IssuedAt         string             `json:"issued_at"`
+	DueAt            string             `json:"due_at"`
+}
+
+// ToInvoiceResult converts the invoice of an order to its result, payable into account
+func ToInvoiceResult(order *domain.Order, invoice *domain.Invoice, account domain.BankAccount) *InvoiceResult {
+	result := &InvoiceResult{
+		InvoiceNumber:    invoice.Number,
+		PaymentReference: invoice.PaymentReference,
+		OrderID:          order.ID,
+		OrderNumber:      order.OrderNumber,
+		OrderStatus:      order.Status,
+		EventTitle:       order.EventTitle,
+		Billing: BillingResult{
+			CompanyName: invoice.Billing.CompanyName,
+			TaxID:       invoice.Billing.TaxID,
+			Address:     invoice.Billing.Address,
+			Email:       invoice.Billing.Email,
+		},
+		Lines:       toOrderLineResults(order.Lines),
+		TotalAmount: order.TotalAmount,
+		Discount:    order.DiscountAmount,
+		FinalAmount: order.FinalAmount,
+		Currency:    order.Currency,
+		PayTo: BankAccountResult{
+			Beneficiary: account.Beneficiary,
+			BankName:    account.BankName,
+			IBAN:        account.IBAN,
+			BIC:         account.BIC,
+		},
+		IssuedAt: invoice.IssuedAt.Format("2006-01-02T15:04:05Z"),
+		DueAt:    invoice.DueAt.Format("2006-01-02T15:04:05Z"),
+	}
+	return result
+}
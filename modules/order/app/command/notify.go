@@ -0,0 +1,121 @@
+package command
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	eventDomain "tixgo/modules/event/domain"
+	"tixgo/modules/order/domain"
+	sharedOrder "tixgo/shared/events/order"
+	sharedKafka "tixgo/shared/kafka"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+)
+
+// orderNotifier announces the changes of orders once committed. The bus given is the reliable one, so a
+// publish failing still is logged rather than failing a change already made.
+type orderNotifier struct {
+	eventBus messaging.EventBus
+}
+
+// publish publishes an event about the order, keyed by the order so its events keep their order
+func (n *orderNotifier) publish(ctx context.Context, order *domain.Order, event interface{}) {
+	err := n.eventBus.PublishEvent(sharedKafka.WithPartitionKey(ctx, strconv.FormatInt(order.ID, 10)), event)
+	if err != nil {
+		logger.Warning(ctx, "Failed to publish order event", logger.F("order_id", order.ID), logger.F("error", err))
+	}
+}
+
+// created announces a checkout
+func (n *orderNotifier) created(ctx context.Context, order *domain.Order) {
+	n.publish(ctx, order, &sharedOrder.EventOrderCreated{
+		OrderID:     order.ID,
+		OrderNumber: order.OrderNumber,
+		UserID:      order.UserID,
+		Email:       order.Email,
+		EventID:     order.EventID,
+		OrganizerID: order.OrganizerID,
+		TicketCount: len(order.Tickets),
+		TotalAmount: order.TotalAmount,
+		Currency:    order.Currency,
+		ExpiresAt:   *order.ExpiresAt,
+		OccurredAt:  time.Now(),
+	})
+	n.seatsChanged(ctx, order.EventID, order.SeatedTicketIDs(), eventDomain.SeatStatusHeld)
+	n.ordersChanged(ctx, order.ID)
+}
+
+// confirmed announces the confirmation of an order
+func (n *orderNotifier) confirmed(ctx context.Context, order *domain.Order) {
+	n.publish(ctx, order, &sharedOrder.EventOrderConfirmed{
+		OrderID:     order.ID,
+		OrderNumber: order.OrderNumber,
+		UserID:      order.UserID,
+		Email:       order.Email,
+		EventID:     order.EventID,
+		OrganizerID: order.OrganizerID,
+		TicketCount: len(order.Tickets),
+		TotalAmount: order.TotalAmount,
+		Currency:    order.Currency,
+		OccurredAt:  time.Now(),
+	})
+	n.seatsChanged(ctx, order.EventID, order.SeatedTicketIDs(), eventDomain.SeatStatusSold)
+	n.ordersChanged(ctx, order.ID)
+}
+
+// refunded announces a refund of an order. A refund that is not settled yet is requested from the
+// payment integration.
+func (n *orderNotifier) refunded(ctx context.Context, order *domain.Order, refund *domain.Refund) {
+	if refund.Status == domain.RefundStatusPending {
+		n.publish(ctx, order, &eventDomain.EventRefundRequested{
+			RefundID:   refund.ID,
+			PaymentID:  refund.PaymentID,
+			OrderID:    order.ID,
+			Amount:     refund.Amount,
+			Currency:   refund.Currency,
+			Reason:     refund.Reason,
+			OccurredAt: time.Now(),
+		})
+	}
+	n.publish(ctx, order, &sharedOrder.EventOrderRefunded{
+		OrderID:     order.ID,
+		OrderNumber: order.OrderNumber,
+		RefundID:    refund.ID,
+		UserID:      order.UserID,
+		Email:       order.Email,
+		EventID:     order.EventID,
+		OrganizerID: order.OrganizerID,
+		TicketCount: len(refund.Tickets),
+		Amount:      refund.Amount,
+		Currency:    refund.Currency,
+		Reason:      refund.Reason,
+		Full:        refund.Full,
+		OccurredAt:  time.Now(),
+	})
+	n.seatsChanged(ctx, order.EventID, refund.SeatedTicketIDs(), eventDomain.SeatStatusAvailable)
+	n.ordersChanged(ctx, order.ID)
+}
+
+// seatsChanged announces the new status of seats of an event to the seat maps
+func (n *orderNotifier) seatsChanged(ctx context.Context, eventID int64, ticketIDs []int64, status eventDomain.SeatStatus) {
+	key := strconv.FormatInt(eventID, 10)
+	for _, ticketID := range ticketIDs {
+		err := n.eventBus.PublishEvent(sharedKafka.WithPartitionKey(ctx, key), eventDomain.NewEventSeatStatusChanged(eventID, ticketID, status))
+		if err != nil {
+			logger.Warning(ctx, "Failed to publish seat status change", logger.F("event_id", eventID), logger.F("ticket_id", ticketID), logger.F("error", err))
+		}
+	}
+}
+
+// ordersChanged announces changed orders to the order summaries
+func (n *orderNotifier) ordersChanged(ctx context.Context, orderIDs ...int64) {
+	if len(orderIDs) == 0 {
+		return
+	}
+
+	if err := n.eventBus.PublishEvent(ctx, sharedOrder.NewEventOrdersChanged(orderIDs...)); err != nil {
+		logger.Warning(ctx, "Failed to publish orders change", logger.F("order_ids", orderIDs), logger.F("error", err))
+	}
+}
@@ -0,0 +1,137 @@
+package command
+
+import (
+	"time"
+
+	"tixgo/modules/order/domain"
+)
+
+// OrderLineResult represents the tickets of a category an order holds
+type OrderLineResult struct {
+	TicketCategoryID   int64  `json:"ticket_category_id"`
+	TicketCategoryName string `json:"ticket_category_name"`
+	Quantity           int    `json:"quantity"`
+	UnitPrice          string `json:"unit_price"`
+	Subtotal           string `json:"subtotal"`
+}
+
+// OrderTicketResult represents a ticket an order holds
+type OrderTicketResult struct {
+	ID               int64               `json:"id"`
+	TicketCategoryID int64               `json:"ticket_category_id"`
+	Status           domain.TicketStatus `json:"status"`
+	Refunded         bool                `json:"refunded"`
+	// QRCode is the signed payload scanned at the entrance and QRURL the path of its image, set once
+	// the order is confirmed
+	QRCode string `json:"qr_code,omitempty"`
+	QRURL  string `json:"qr_url,omitempty"`
+}
+
+// AccommodationsResult represents the accessibility and dietary needs asked for with an order
+type AccommodationsResult struct {
+	AccessNeeds  []domain.AccessNeed  `json:"access_needs"`
+	DietaryNeeds []domain.DietaryNeed `json:"dietary_needs"`
+	Notes        string               `json:"notes"`
+}
+
+// OrderResult represents an order of a buyer
+type OrderResult struct {
+	ID          int64                `json:"id"`
+	OrderNumber string               `json:"order_number"`
+	EventID     int64                `json:"event_id"`
+	Status      domain.OrderStatus   `json:"status"`
+	Lines       []*OrderLineResult   `json:"lines"`
+	Tickets     []*OrderTicketResult `json:"tickets"`
+	TotalAmount string               `json:"total_amount"`
+	PromoCode   string               `json:"promo_code,omitempty"`
+	Discount    string               `json:"discount_amount"`
+	FinalAmount string               `json:"final_amount"`
+	Currency    string               `json:"currency"`
+	// InvoiceNumber and PaymentReference are set for orders paid by bank transfer, whose transfer
+	// carries the reference
+	InvoiceNumber    string                `json:"invoice_number,omitempty"`
+	PaymentReference string                `json:"payment_reference,omitempty"`
+	Accommodations   *AccommodationsResult `json:"accommodations,omitempty"`
+	ExpiresAt        *string               `json:"expires_at"`
+	ConfirmedAt      *string               `json:"confirmed_at"`
+	CancelledAt      *string               `json:"cancelled_at"`
+	CreatedAt        string                `json:"created_at"`
+}
+
+// ToOrderResult converts an order to its result
+func ToOrderResult(order *domain.Order) *OrderResult {
+	result := &OrderResult{
+		ID:          order.ID,
+		OrderNumber: order.OrderNumber,
+		EventID:     order.EventID,
+		Status:      order.Status,
+		Lines:       toOrderLineResults(order.Lines),
+		Tickets:     toOrderTicketResults(order.Tickets),
+		TotalAmount: order.TotalAmount,
+		PromoCode:   order.PromoCode,
+		Discount:    order.DiscountAmount,
+		FinalAmount: order.FinalAmount,
+		Currency:    order.Currency,
+		CreatedAt:   order.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+
+	// pending orders only expire, the others keep the expiry they had while pending
+	if order.Status == domain.OrderStatusPending || order.Status == domain.OrderStatusAwaitingTransfer {
+		result.ExpiresAt = formatTime(order.ExpiresAt)
+	}
+	result.ConfirmedAt = formatTime(order.ConfirmedAt)
+	result.CancelledAt = formatTime(order.CancelledAt)
+	if order.Invoice != nil {
+		result.InvoiceNumber = order.Invoice.Number
+		result.PaymentReference = order.Invoice.PaymentReference
+	}
+	if order.Accommodations != nil {
+		// the needs are listed empty rather than null
+		result.Accommodations = &AccommodationsResult{
+			AccessNeeds:  append([]domain.AccessNeed{}, order.Accommodations.AccessNeeds...),
+			DietaryNeeds: append([]domain.DietaryNeed{}, order.Accommodations.DietaryNeeds...),
+			Notes:        order.Accommodations.Notes,
+		}
+	}
+
+	return result
+}
+
+func toOrderLineResults(lines []*domain.OrderLine) []*OrderLineResult {
+	results := make([]*OrderLineResult, len(lines))
+	for i, line := range lines {
+		results[i] = &OrderLineResult{
+			TicketCategoryID:   line.TicketCategoryID,
+			TicketCategoryName: line.TicketCategoryName,
+			Quantity:           line.Quantity,
+			UnitPrice:          line.UnitPrice,
+			Subtotal:           line.Subtotal,
+		}
+	}
+	return results
+}
+
+func toOrderTicketResults(tickets []*domain.OrderTicket) []*OrderTicketResult {
+	results := make([]*OrderTicketResult, len(tickets))
+	for i, ticket := range tickets {
+		results[i] = &OrderTicketResult{
+			ID:               ticket.ID,
+			TicketCategoryID: ticket.TicketCategoryID,
+			Status:           ticket.Status,
+			Refunded:         ticket.Refunded,
+			QRCode:           ticket.QRCode,
+		}
+		if ticket.QRCode != "" {
+			results[i].QRURL = domain.TicketQRPath(ticket.ID, ticket.QRCode)
+		}
+	}
+	return results
+}
+
+func formatTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := t.Format("2006-01-02T15:04:05Z")
+	return &formatted
+}
@@ -0,0 +1,37 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/order/domain"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// rebuildBatchSize is how many orders are projected per batch of a rebuild
+const rebuildBatchSize = 500
+
+// RebuildOrderSummariesHandler projects the summaries of every order again, catching up on the
+// changes of orders the bus missed, e.g. orders written outside the application
+type RebuildOrderSummariesHandler struct {
+	summaryRepo domain.OrderSummaryRepository
+}
+
+// NewRebuildOrderSummariesHandler creates a new rebuild order summaries handler
+func NewRebuildOrderSummariesHandler(summaryRepo domain.OrderSummaryRepository) *RebuildOrderSummariesHandler {
+	return &RebuildOrderSummariesHandler{
+		summaryRepo: summaryRepo,
+	}
+}
+
+// Handle rebuilds the order summaries
+func (h *RebuildOrderSummariesHandler) Handle(ctx context.Context) error {
+	projected, err := h.summaryRepo.Rebuild(ctx, rebuildBatchSize)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to rebuild order summaries")
+	}
+
+	logger.Info(ctx, "Rebuilt order summaries", logger.F("orders", projected))
+	return nil
+}
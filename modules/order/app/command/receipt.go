@@ -0,0 +1,222 @@
+package command
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"tixgo/modules/order/domain"
+	"tixgo/shared/money"
+	"tixgo/shared/pdf"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ReceiptOptions configures the receipts and pro-forma invoices of orders
+type ReceiptOptions struct {
+	Issuer domain.ReceiptIssuer
+	// Account is where the transfers of the orders awaiting one are paid into
+	Account domain.BankAccount
+}
+
+// layout of the A4 pages of receipts, in points
+const (
+	receiptMargin   = 50.0
+	receiptRight    = pdf.PageWidth - receiptMargin
+	receiptBottom   = pdf.PageHeight - 70
+	receiptLeading  = 14.0
+	receiptTextSize = 10.0
+
+	// right edges of the columns of the lines
+	receiptQuantityColumn  = 360.0
+	receiptUnitPriceColumn = 455.0
+)
+
+// receiptWriter writes the rows of a receipt down its pages, starting a page when one is full
+type receiptWriter struct {
+	doc  *pdf.Document
+	page *pdf.Page
+	y    float64
+}
+
+// row returns the baseline of the next row, height points high, on a new page when it does not fit
+func (w *receiptWriter) row(height float64) float64 {
+	if w.page == nil || w.y+height > receiptBottom {
+		w.page = w.doc.AddPage()
+		w.y = 60
+	}
+	w.y += height
+	return w.y
+}
+
+// amount writes a row of the totals, the label right aligned left of the amount
+func (w *receiptWriter) amount(font pdf.Font, label, amount string) {
+	y := w.row(receiptLeading)
+	w.page.TextRight(receiptUnitPriceColumn, y, font, receiptTextSize, label)
+	w.page.TextRight(receiptRight, y, font, receiptTextSize, amount)
+}
+
+// RenderReceipt renders the PDF receipt of a paid order, listing its refunds, or the pro-forma invoice
+// of an order awaiting its bank transfer. Other orders have none, ErrReceiptUnavailable.
+func RenderReceipt(order *domain.Order, refunds []*domain.Refund, options ReceiptOptions) ([]byte, error) {
+	proforma := order.Status == domain.OrderStatusAwaitingTransfer && order.Invoice != nil
+	if !order.Paid() && !proforma {
+		return nil, domain.ErrReceiptUnavailable
+	}
+
+	title, number, issuedAt := "Receipt", domain.ReceiptNumber(order.OrderNumber), order.CreatedAt
+	if proforma {
+		title, number, issuedAt = "Pro-forma invoice", order.Invoice.Number, order.Invoice.IssuedAt
+	} else if order.ConfirmedAt != nil {
+		issuedAt = *order.ConfirmedAt
+	}
+
+	w := &receiptWriter{doc: pdf.New(title + " " + number)}
+	// amounts in the currency of the order, with its decimals
+	price := func(amount string) string {
+		if amount == "" {
+			amount = "0"
+		}
+		formatted, err := money.Format(amount, order.Currency)
+		if err != nil {
+			return amount + " " + order.Currency
+		}
+		return formatted
+	}
+
+	// the issuer on the right, the document on the left
+	y := w.row(20)
+	w.page.Text(receiptMargin, y, pdf.Bold, 20, title)
+	issuer := []string{}
+	for _, line := range strings.Split(options.Issuer.Address, "\n") {
+		if strings.TrimSpace(line) != "" {
+			issuer = append(issuer, pdf.Wrap(pdf.Regular, 9, line, 200)...)
+		}
+	}
+	if options.Issuer.TaxID != "" {
+		issuer = append(issuer, "Tax ID: "+options.Issuer.TaxID)
+	}
+	w.page.TextRight(receiptRight, y, pdf.Bold, 11, options.Issuer.Name)
+	for i, line := range issuer {
+		w.page.TextRight(receiptRight, y+float64(i+1)*12, pdf.Regular, 9, line)
+	}
+
+	details := [][2]string{
+		{"Number", number},
+		{"Order", order.OrderNumber},
+		{"Date", issuedAt.UTC().Format(time.DateOnly)},
+	}
+	if proforma {
+		details = append(details,
+			[2]string{"Due", order.Invoice.DueAt.UTC().Format(time.DateOnly)},
+			[2]string{"Payment reference", order.Invoice.PaymentReference},
+		)
+	}
+	w.y += 14
+	for _, detail := range details {
+		y := w.row(receiptLeading)
+		w.page.Text(receiptMargin, y, pdf.Bold, receiptTextSize, detail[0])
+		w.page.Text(receiptMargin+110, y, pdf.Regular, receiptTextSize, detail[1])
+	}
+	w.y = max(w.y, 60+20+float64(len(issuer)+1)*12)
+
+	// made out to the company of the invoice, the buyer otherwise
+	billTo := []string{order.Email}
+	if order.Invoice != nil {
+		billTo = []string{order.Invoice.Billing.CompanyName}
+		billTo = append(billTo, strings.Split(order.Invoice.Billing.Address, "\n")...)
+		if order.Invoice.Billing.TaxID != "" {
+			billTo = append(billTo, "Tax ID: "+order.Invoice.Billing.TaxID)
+		}
+	}
+	w.y += 14
+	y = w.row(receiptLeading)
+	w.page.Text(receiptMargin, y, pdf.Bold, receiptTextSize, "Bill to")
+	for _, line := range billTo {
+		for _, wrapped := range pdf.Wrap(pdf.Regular, receiptTextSize, line, 300) {
+			y := w.row(receiptLeading)
+			w.page.Text(receiptMargin, y, pdf.Regular, receiptTextSize, wrapped)
+		}
+	}
+
+	w.y += 14
+	y = w.row(receiptLeading)
+	w.page.Text(receiptMargin, y, pdf.Bold, receiptTextSize, "Event")
+	w.page.Text(receiptMargin+110, y, pdf.Regular, receiptTextSize, order.EventTitle)
+
+	// the lines, their names wrapped in the first column
+	w.y += 14
+	y = w.row(receiptLeading)
+	w.page.Text(receiptMargin, y, pdf.Bold, receiptTextSize, "Tickets")
+	w.page.TextRight(receiptQuantityColumn, y, pdf.Bold, receiptTextSize, "Qty")
+	w.page.TextRight(receiptUnitPriceColumn, y, pdf.Bold, receiptTextSize, "Unit price")
+	w.page.TextRight(receiptRight, y, pdf.Bold, receiptTextSize, "Amount")
+	w.page.Line(receiptMargin, y+5, receiptRight, y+5, 0.5)
+	w.y += 5
+	for _, line := range order.Lines {
+		names := pdf.Wrap(pdf.Regular, receiptTextSize, line.TicketCategoryName, 240)
+		y := w.row(receiptLeading)
+		w.page.Text(receiptMargin, y, pdf.Regular, receiptTextSize, names[0])
+		w.page.TextRight(receiptQuantityColumn, y, pdf.Regular, receiptTextSize, strconv.Itoa(line.Quantity))
+		w.page.TextRight(receiptUnitPriceColumn, y, pdf.Regular, receiptTextSize, price(line.UnitPrice))
+		w.page.TextRight(receiptRight, y, pdf.Regular, receiptTextSize, price(line.Subtotal))
+		for _, name := range names[1:] {
+			y := w.row(receiptLeading)
+			w.page.Text(receiptMargin, y, pdf.Regular, receiptTextSize, name)
+		}
+	}
+	w.page.Line(receiptMargin, w.y+5, receiptRight, w.y+5, 0.5)
+	w.y += 5
+
+	// the totals, the taxes and fees being included in the total
+	w.amount(pdf.Regular, "Subtotal", price(order.TotalAmount))
+	if order.PromoCode != "" {
+		w.amount(pdf.Regular, "Discount ("+order.PromoCode+")", "-"+price(order.DiscountAmount))
+	}
+	if strings.Trim(order.ServiceFee, "0.") != "" {
+		w.amount(pdf.Regular, "Service fee", price(order.ServiceFee))
+	}
+	w.amount(pdf.Regular, "Tax included", price(order.TaxAmount))
+	label := "Total paid"
+	if proforma {
+		label = "Total due"
+	}
+	w.amount(pdf.Bold, label, price(order.FinalAmount))
+
+	for _, refund := range refunds {
+		if refund.Status == domain.RefundStatusFailed {
+			continue
+		}
+		w.amount(pdf.Regular, "Refunded "+refund.CreatedAt.UTC().Format(time.DateOnly), "-"+price(refund.Amount))
+	}
+
+	// where the transfer is paid into
+	if proforma {
+		w.y += 14
+		y := w.row(receiptLeading)
+		w.page.Text(receiptMargin, y, pdf.Bold, receiptTextSize, "Pay by bank transfer to")
+		for _, detail := range [][2]string{
+			{"Beneficiary", options.Account.Beneficiary},
+			{"Bank", options.Account.BankName},
+			{"IBAN", options.Account.IBAN},
+			{"BIC", options.Account.BIC},
+			{"Reference", order.Invoice.PaymentReference},
+		} {
+			y := w.row(receiptLeading)
+			w.page.Text(receiptMargin, y, pdf.Regular, receiptTextSize, detail[0])
+			w.page.Text(receiptMargin+110, y, pdf.Regular, receiptTextSize, detail[1])
+		}
+	}
+
+	if order.TestMode {
+		w.y += 14
+		y := w.row(receiptLeading)
+		w.page.Text(receiptMargin, y, pdf.Bold, receiptTextSize, "Test mode order: no payment was made.")
+	}
+
+	receipt, err := w.doc.Render()
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to render receipt")
+	}
+	return receipt, nil
+}
@@ -0,0 +1,126 @@
+package command
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"regexp"
+	"testing"
+	"time"
+
+	"tixgo/modules/order/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// receiptText returns the content streams of a PDF rendered by RenderReceipt
+func receiptText(t *testing.T, receipt []byte) string {
+	var text bytes.Buffer
+	for _, stream := range regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`).FindAllSubmatch(receipt, -1) {
+		reader, err := zlib.NewReader(bytes.NewReader(stream[1]))
+		require.NoError(t, err)
+		content, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		text.Write(content)
+	}
+	return text.String()
+}
+
+func TestRenderReceipt(t *testing.T) {
+	confirmedAt := time.Date(2026, 10, 2, 9, 0, 0, 0, time.UTC)
+	options := ReceiptOptions{
+		Issuer:  domain.ReceiptIssuer{Name: "TixGo Ltd", Address: "1 Market Street\nSingapore", TaxID: "SG-123"},
+		Account: domain.BankAccount{Beneficiary: "TixGo Ltd", IBAN: "DE89370400440532013000", BIC: "COBADEFFXXX"},
+	}
+	order := func(status domain.OrderStatus) *domain.Order {
+		return &domain.Order{
+			ID:          42,
+			OrderNumber: "ORD-00000000002A",
+			Status:      status,
+			Email:       "fan@example.com",
+			EventTitle:  "Jazz Night",
+			Lines: []*domain.OrderLine{
+				{TicketCategoryName: "VIP", Quantity: 2, UnitPrice: "25.00", Subtotal: "50.00"},
+			},
+			TotalAmount:    "50.00",
+			PromoCode:      "EARLY",
+			DiscountAmount: "5.00",
+			FinalAmount:    "45.00",
+			TaxAmount:      "4.09",
+			ServiceFee:     "0.00",
+			Currency:       "USD",
+			ConfirmedAt:    &confirmedAt,
+		}
+	}
+
+	t.Run("paid orders get a receipt with their refunds", func(t *testing.T) {
+		refunds := []*domain.Refund{
+			{Amount: "22.50", Status: domain.RefundStatusCompleted, CreatedAt: confirmedAt.AddDate(0, 0, 3)},
+			{Amount: "22.50", Status: domain.RefundStatusFailed, CreatedAt: confirmedAt.AddDate(0, 0, 4)},
+		}
+		receipt, err := RenderReceipt(order(domain.OrderStatusPartiallyRefunded), refunds, options)
+		require.NoError(t, err)
+
+		assert.Contains(t, string(receipt), "/Title (Receipt RC-00000000002A)")
+		text := receiptText(t, receipt)
+		for _, want := range []string{"(TixGo Ltd)", "(Tax ID: SG-123)", "(RC-00000000002A)", "(2026-10-02)", "(fan@example.com)",
+			"(VIP)", "(25.00 USD)", "(Discount \\(EARLY\\))", "(-5.00 USD)", "(4.09 USD)", "(Total paid)", "(45.00 USD)",
+			"(Refunded 2026-10-05)", "(-22.50 USD)"} {
+			assert.Contains(t, text, want)
+		}
+		assert.NotContains(t, text, "(Service fee)", "a fee of zero is left out")
+		assert.NotContains(t, text, "2026-10-06", "failed refunds are left out")
+	})
+
+	t.Run("orders awaiting their transfer get a pro-forma invoice", func(t *testing.T) {
+		awaiting := order(domain.OrderStatusAwaitingTransfer)
+		awaiting.ConfirmedAt = nil
+		awaiting.Invoice = &domain.Invoice{
+			Number:           "PF-00000000002A",
+			PaymentReference: "ORD-00000000002A",
+			Billing:          domain.BillingDetails{CompanyName: "Acme Corp", Address: "2 Harbour Road"},
+			IssuedAt:         confirmedAt,
+			DueAt:            confirmedAt.AddDate(0, 0, 7),
+		}
+		receipt, err := RenderReceipt(awaiting, nil, options)
+		require.NoError(t, err)
+		assert.Equal(t, "PF-00000000002A.pdf", domain.ReceiptFilename(awaiting))
+
+		text := receiptText(t, receipt)
+		for _, want := range []string{"(Pro-forma invoice)", "(PF-00000000002A)", "(2026-10-09)", "(Acme Corp)", "(2 Harbour Road)",
+			"(Total due)", "(DE89370400440532013000)", "(COBADEFFXXX)"} {
+			assert.Contains(t, text, want)
+		}
+		assert.NotContains(t, text, "(fan@example.com)", "the invoice is made out to the company")
+	})
+
+	t.Run("amounts are written in the currency of the order", func(t *testing.T) {
+		dong := order(domain.OrderStatusConfirmed)
+		dong.Currency = "VND"
+		dong.Lines[0].UnitPrice, dong.Lines[0].Subtotal = "1250000.00", "2500000.00"
+		dong.FinalAmount = "2495000.00"
+		receipt, err := RenderReceipt(dong, nil, options)
+		require.NoError(t, err)
+
+		text := receiptText(t, receipt)
+		for _, want := range []string{"(1,250,000 VND)", "(2,500,000 VND)", "(2,495,000 VND)"} {
+			assert.Contains(t, text, want)
+		}
+	})
+
+	t.Run("long orders run over several pages", func(t *testing.T) {
+		long := order(domain.OrderStatusConfirmed)
+		for range 80 {
+			long.Lines = append(long.Lines, long.Lines[0])
+		}
+		receipt, err := RenderReceipt(long, nil, options)
+		require.NoError(t, err)
+		assert.Contains(t, string(receipt), "/Count 2")
+	})
+
+	for _, status := range []domain.OrderStatus{domain.OrderStatusPending, domain.OrderStatusCancelled} {
+		_, err := RenderReceipt(order(status), nil, options)
+		assert.ErrorIs(t, err, domain.ErrReceiptUnavailable, status)
+	}
+}
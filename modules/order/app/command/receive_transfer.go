@@ -0,0 +1,113 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/order/domain"
+
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ReceiveTransferCommand represents the command to record the bank transfer paying an order awaiting it
+type ReceiveTransferCommand struct {
+	OrderID int64 `json:"-"`
+	// TransactionID identifies the transfer at the bank
+	TransactionID string `json:"transaction_id" binding:"required,max=255"`
+	Amount        string `json:"amount" binding:"required,max=20"`
+	// Currency is the one of the order when empty
+	Currency string `json:"currency" binding:"omitempty,len=3"`
+	// ReceivedAt is when the bank booked the transfer, now when empty
+	ReceivedAt *time.Time `json:"received_at"`
+}
+
+// ReceiveTransferHandler handles the transfers received for orders paid by bank transfer
+type ReceiveTransferHandler struct {
+	orderRepo domain.OrderRepository
+	sealer    domain.TicketSealer
+	notifier  orderNotifier
+}
+
+// NewReceiveTransferHandler creates a new receive transfer handler
+func NewReceiveTransferHandler(orderRepo domain.OrderRepository, sealer domain.TicketSealer, eventBus messaging.EventBus) *ReceiveTransferHandler {
+	return &ReceiveTransferHandler{
+		orderRepo: orderRepo,
+		sealer:    sealer,
+		notifier:  orderNotifier{eventBus: eventBus},
+	}
+}
+
+// Handle executes the receive transfer command. The transfer is recorded as the completed payment of
+// the order, which is confirmed and its tickets issued, as if paid at checkout.
+func (h *ReceiveTransferHandler) Handle(ctx context.Context, cmd ReceiveTransferCommand) (*OrderResult, error) {
+	order, err := h.orderRepo.GetByID(ctx, cmd.OrderID)
+	if err != nil {
+		if err == domain.ErrOrderNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get order")
+	}
+
+	return h.receive(ctx, order, cmd)
+}
+
+func (h *ReceiveTransferHandler) receive(ctx context.Context, order *domain.Order, cmd ReceiveTransferCommand) (*OrderResult, error) {
+	now := time.Now()
+	transfer := &domain.Transfer{
+		TransactionID: cmd.TransactionID,
+		Amount:        cmd.Amount,
+		Currency:      cmd.Currency,
+		ReceivedAt:    now,
+	}
+	if transfer.Currency == "" {
+		transfer.Currency = order.Currency
+	}
+	if cmd.ReceivedAt != nil {
+		transfer.ReceivedAt = *cmd.ReceivedAt
+	}
+
+	if err := order.ReceiveTransfer(transfer, now); err != nil {
+		return nil, err
+	}
+	if err := order.IssueQRCodes(h.sealer); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to issue ticket QR codes")
+	}
+
+	if err := h.orderRepo.ReceiveTransfer(ctx, order, transfer); err != nil {
+		if err == domain.ErrOrderNotAwaitingTransfer {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to record transfer")
+	}
+
+	h.notifier.confirmed(ctx, order)
+
+	return ToOrderResult(order), nil
+}
+
+// ReconcileTransferCommand represents a transfer reported by the bank, matched with its order by the
+// payment reference found in its remittance information
+type ReconcileTransferCommand struct {
+	Remittance string
+	ReceiveTransferCommand
+}
+
+// Reconcile executes the reconcile transfer command, ErrOrderNotFound when the remittance carries no
+// known payment reference
+func (h *ReceiveTransferHandler) Reconcile(ctx context.Context, cmd ReconcileTransferCommand) (*OrderResult, error) {
+	reference, ok := domain.FindPaymentReference(cmd.Remittance)
+	if !ok {
+		return nil, domain.ErrOrderNotFound
+	}
+
+	order, err := h.orderRepo.GetByPaymentReference(ctx, reference)
+	if err != nil {
+		if err == domain.ErrOrderNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get order")
+	}
+
+	return h.receive(ctx, order, cmd.ReceiveTransferCommand)
+}
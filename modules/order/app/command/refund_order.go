@@ -0,0 +1,132 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/order/domain"
+	templateDomain "tixgo/modules/template/domain"
+	sharedMail "tixgo/shared/events/mail"
+	sharedKafka "tixgo/shared/kafka"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/notification/mail"
+	"github.com/duongptryu/gox/syserr"
+)
+
+const (
+	SlugOrderRefunded = "order-refunded"
+
+	// testModeSubjectPrefix watermarks the mails about test mode orders
+	testModeSubjectPrefix = "[TEST] "
+)
+
+// RefundOrderCommand represents the command to refund tickets of an order, all of them when TicketIDs
+// is empty
+type RefundOrderCommand struct {
+	OrderID   int64   `json:"-"`
+	TicketIDs []int64 `json:"ticket_ids" binding:"omitempty,max=100,dive,gt=0"`
+	Reason    string  `json:"reason" binding:"required,max=500"`
+}
+
+// RefundOrderHandler handles order refunds
+type RefundOrderHandler struct {
+	orderRepo        domain.OrderRepository
+	templateRepo     templateDomain.TemplateRepository
+	templateRenderer templateDomain.TemplateRenderer
+	eventBus         messaging.EventBus
+	notifier         orderNotifier
+}
+
+// NewRefundOrderHandler creates a new refund order handler
+func NewRefundOrderHandler(orderRepo domain.OrderRepository, templateRepo templateDomain.TemplateRepository, templateRenderer templateDomain.TemplateRenderer, eventBus messaging.EventBus) *RefundOrderHandler {
+	return &RefundOrderHandler{
+		orderRepo:        orderRepo,
+		templateRepo:     templateRepo,
+		templateRenderer: templateRenderer,
+		eventBus:         eventBus,
+		notifier:         orderNotifier{eventBus: eventBus},
+	}
+}
+
+// Handle executes the refund order command. The tickets are given back at once and the refund requested
+// from the payment integration; the customer is mailed a confirmation, which failing does not fail the
+// refund already recorded.
+func (h *RefundOrderHandler) Handle(ctx context.Context, cmd RefundOrderCommand) (*RefundResult, error) {
+	order, err := h.orderRepo.GetByID(ctx, cmd.OrderID)
+	if err != nil {
+		if err == domain.ErrOrderNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get order")
+	}
+
+	refund, err := order.Refund(cmd.TicketIDs, cmd.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.orderRepo.Refund(ctx, order, refund); err != nil {
+		switch err {
+		case domain.ErrOrderNotFound, domain.ErrOrderNotRefundable, domain.ErrTicketNotRefundable, domain.ErrNoPayment:
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to refund order")
+	}
+
+	h.notifier.refunded(ctx, order, refund)
+
+	if err := h.notify(ctx, order, refund); err != nil {
+		logger.Error(ctx, "Failed to send refund confirmation", logger.F("order_id", order.ID), logger.F("refund_id", refund.ID), logger.F("error", err))
+	}
+
+	return ToRefundResult(refund), nil
+}
+
+// notify mails the customer of an order the confirmation of its refund
+func (h *RefundOrderHandler) notify(ctx context.Context, order *domain.Order, refund *domain.Refund) error {
+	if order.Email == "" {
+		return nil
+	}
+
+	template, err := h.templateRepo.GetBySlug(ctx, SlugOrderRefunded)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get template")
+	}
+
+	rendered, err := h.templateRenderer.Render(ctx, template, map[string]interface{}{
+		"event_title":   order.EventTitle,
+		"order_number":  order.OrderNumber,
+		"ticket_count":  len(refund.Tickets),
+		"full_refund":   refund.Full,
+		"refund_amount": refund.Amount,
+		"currency":      refund.Currency,
+		"reason":        refund.Reason,
+	}, templateDomain.RenderOptions{OrganizerID: order.OrganizerID})
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to render template")
+	}
+
+	subject := rendered.Subject
+	if order.TestMode {
+		subject = testModeSubjectPrefix + subject
+	}
+
+	err = h.eventBus.PublishEvent(sharedKafka.WithPartitionKey(ctx, order.Email), &sharedMail.EventSendMail{
+		ToMail: []mail.EmailAddress{
+			{
+				Email: order.Email,
+				Name:  "",
+			},
+		},
+		Subject:     subject,
+		HTMLBody:    rendered.Content,
+		Priority:    mail.PriorityHigh,
+		OrganizerID: order.OrganizerID,
+	})
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to publish send mail event")
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package command
+
+import "tixgo/modules/order/domain"
+
+// RefundResult represents a refund of an order and the tickets it gave back
+type RefundResult struct {
+	ID          int64               `json:"id"`
+	OrderID     int64               `json:"order_id"`
+	TicketIDs   []int64             `json:"ticket_ids"`
+	Amount      string              `json:"amount"`
+	Currency    string              `json:"currency"`
+	Reason      string              `json:"reason"`
+	Status      domain.RefundStatus `json:"status"`
+	ProcessedAt *string             `json:"processed_at"`
+	CreatedAt   string              `json:"created_at"`
+}
+
+// ToRefundResult converts a refund to its result
+func ToRefundResult(refund *domain.Refund) *RefundResult {
+	return &RefundResult{
+		ID:          refund.ID,
+		OrderID:     refund.OrderID,
+		TicketIDs:   refund.TicketIDs(),
+		Amount:      refund.Amount,
+		Currency:    refund.Currency,
+		Reason:      refund.Reason,
+		Status:      refund.Status,
+		ProcessedAt: formatTime(refund.ProcessedAt),
+		CreatedAt:   refund.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
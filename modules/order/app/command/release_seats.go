@@ -0,0 +1,45 @@
+package command
+
+import (
+	"context"
+
+	eventDomain "tixgo/modules/event/domain"
+	"tixgo/modules/order/domain"
+
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ReleaseSeatsCommand represents the command of a buyer to release seats they hold in an event, every
+// seat they hold when SeatIDs is empty
+type ReleaseSeatsCommand struct {
+	EventID int64
+	UserID  int64
+	SeatIDs []int64
+}
+
+// ReleaseSeatsHandler handles releasing seat holds
+type ReleaseSeatsHandler struct {
+	seatHolds domain.SeatHoldStore
+	notifier  orderNotifier
+}
+
+// NewReleaseSeatsHandler creates a new release seats handler
+func NewReleaseSeatsHandler(seatHolds domain.SeatHoldStore, eventBus messaging.EventBus) *ReleaseSeatsHandler {
+	return &ReleaseSeatsHandler{
+		seatHolds: seatHolds,
+		notifier:  orderNotifier{eventBus: eventBus},
+	}
+}
+
+// Handle executes the release seats command. The seats released show as available on the seat maps;
+// seats the buyer does not hold are left alone.
+func (h *ReleaseSeatsHandler) Handle(ctx context.Context, cmd ReleaseSeatsCommand) error {
+	released, err := h.seatHolds.Release(ctx, cmd.EventID, cmd.UserID, cmd.SeatIDs)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to release seats")
+	}
+
+	h.notifier.seatsChanged(ctx, cmd.EventID, released, eventDomain.SeatStatusAvailable)
+	return nil
+}
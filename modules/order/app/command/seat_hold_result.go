@@ -0,0 +1,19 @@
+package command
+
+import "tixgo/modules/order/domain"
+
+// SeatHoldResult represents the seats a buyer holds in an event
+type SeatHoldResult struct {
+	EventID   int64   `json:"event_id"`
+	SeatIDs   []int64 `json:"seat_ids"`
+	ExpiresAt string  `json:"expires_at"`
+}
+
+// ToSeatHoldResult converts a seat hold to its result
+func ToSeatHoldResult(hold *domain.SeatHold) *SeatHoldResult {
+	return &SeatHoldResult{
+		EventID:   hold.EventID,
+		SeatIDs:   hold.TicketIDs,
+		ExpiresAt: hold.ExpiresAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
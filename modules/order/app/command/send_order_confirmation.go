@@ -0,0 +1,188 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"tixgo/modules/order/domain"
+	templateDomain "tixgo/modules/template/domain"
+	"tixgo/shared/dedup"
+	sharedMail "tixgo/shared/events/mail"
+	sharedKafka "tixgo/shared/kafka"
+	"tixgo/shared/pdf"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/notification/mail"
+	"github.com/duongptryu/gox/syserr"
+)
+
+const (
+	SlugOrderConfirmation = "order-confirmation"
+
+	dedupPurposeOrderConfirmation = "order_confirmation_mail"
+	// orderConfirmationWindow is how long a confirmation mail is remembered, longer than the redeliveries
+	// of its event
+	orderConfirmationWindow = 24 * time.Hour
+)
+
+// SendOrderConfirmationCommand represents the command to mail the buyer of a confirmed order its tickets
+type SendOrderConfirmationCommand struct {
+	OrderID int64
+}
+
+// SendOrderConfirmationHandler mails the confirmation of orders with the QR codes of their tickets
+type SendOrderConfirmationHandler struct {
+	orderRepo        domain.OrderRepository
+	templateRepo     templateDomain.TemplateRepository
+	templateRenderer templateDomain.TemplateRenderer
+	deduplicator     dedup.Deduplicator
+	eventBus         messaging.EventBus
+	// apiURL is the public scheme and host of the API serving the QR code images
+	apiURL   string
+	receipts ReceiptOptions
+}
+
+// NewSendOrderConfirmationHandler creates a new send order confirmation handler, linking the QR codes of
+// the tickets to the API at apiURL and attaching the receipt of the order
+func NewSendOrderConfirmationHandler(orderRepo domain.OrderRepository, templateRepo templateDomain.TemplateRepository, templateRenderer templateDomain.TemplateRenderer, deduplicator dedup.Deduplicator, eventBus messaging.EventBus, apiURL string, receipts ReceiptOptions) *SendOrderConfirmationHandler {
+	return &SendOrderConfirmationHandler{
+		orderRepo:        orderRepo,
+		templateRepo:     templateRepo,
+		templateRenderer: templateRenderer,
+		deduplicator:     deduplicator,
+		eventBus:         eventBus,
+		apiURL:           strings.TrimSuffix(apiURL, "/"),
+		receipts:         receipts,
+	}
+}
+
+// Handle sends the confirmation of an order once within orderConfirmationWindow; redeliveries are
+// acknowledged and dropped. When sending fails the claim is released so the redelivery sends it.
+func (h *SendOrderConfirmationHandler) Handle(ctx context.Context, cmd SendOrderConfirmationCommand) error {
+	key := dedup.Key(dedupPurposeOrderConfirmation, strconv.FormatInt(cmd.OrderID, 10))
+
+	err := h.deduplicator.Claim(ctx, key, orderConfirmationWindow)
+	if errors.Is(err, dedup.ErrDuplicate) {
+		logger.Info(ctx, "Skipping duplicate order confirmation mail", logger.F("order_id", cmd.OrderID))
+		return nil
+	}
+	if err != nil {
+		logger.Warning(ctx, "Failed to deduplicate order confirmation mail", logger.F("order_id", cmd.OrderID), logger.F("error", err))
+	}
+
+	if err := h.send(ctx, cmd); err != nil {
+		if forgetErr := h.deduplicator.Forget(ctx, key); forgetErr != nil {
+			logger.Warning(ctx, "Failed to release order confirmation mail claim", logger.F("order_id", cmd.OrderID), logger.F("error", forgetErr))
+		}
+		return err
+	}
+	return nil
+}
+
+func (h *SendOrderConfirmationHandler) send(ctx context.Context, cmd SendOrderConfirmationCommand) error {
+	order, err := h.orderRepo.GetByID(ctx, cmd.OrderID)
+	if err != nil {
+		if err == domain.ErrOrderNotFound {
+			logger.Warning(ctx, "Skipping confirmation mail of a missing order", logger.F("order_id", cmd.OrderID))
+			return nil
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get order")
+	}
+	if order.Email == "" {
+		return nil
+	}
+
+	template, err := h.templateRepo.GetBySlug(ctx, SlugOrderConfirmation)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get template")
+	}
+
+	rendered, err := h.templateRenderer.Render(ctx, template, h.variables(order), templateDomain.RenderOptions{OrganizerID: order.OrganizerID})
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to render template")
+	}
+
+	subject := rendered.Subject
+	if order.TestMode {
+		subject = testModeSubjectPrefix + subject
+	}
+
+	// the tickets matter more than the receipt, which the buyer can download again
+	var attachments []sharedMail.Attachment
+	receipt, err := RenderReceipt(order, nil, h.receipts)
+	if err != nil {
+		logger.Warning(ctx, "Failed to render order receipt, confirming without it", logger.F("order_id", order.ID), logger.F("error", err))
+	} else {
+		attachments = []sharedMail.Attachment{{Filename: domain.ReceiptFilename(order), ContentType: pdf.ContentType, Content: receipt}}
+	}
+
+	err = h.eventBus.PublishEvent(sharedKafka.WithPartitionKey(ctx, order.Email), &sharedMail.EventSendMail{
+		ToMail: []mail.EmailAddress{
+			{
+				Email: order.Email,
+				Name:  "",
+			},
+		},
+		Subject:     subject,
+		HTMLBody:    rendered.Content,
+		Priority:    mail.PriorityHigh,
+		OrganizerID: order.OrganizerID,
+		Attachments: attachments,
+	})
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to publish send mail event")
+	}
+
+	return nil
+}
+
+// variables are the order details the confirmation template renders. Tickets refunded since the
+// confirmation have no QR code and are left out.
+func (h *SendOrderConfirmationHandler) variables(order *domain.Order) map[string]interface{} {
+	categories := make(map[int64]string, len(order.Lines))
+	lines := make([]map[string]interface{}, len(order.Lines))
+	for i, line := range order.Lines {
+		categories[line.TicketCategoryID] = line.TicketCategoryName
+		lines[i] = map[string]interface{}{
+			"name":       line.TicketCategoryName,
+			"quantity":   line.Quantity,
+			"unit_price": line.UnitPrice,
+			"subtotal":   line.Subtotal,
+		}
+	}
+
+	tickets := make([]map[string]interface{}, 0, len(order.Tickets))
+	for _, ticket := range order.Tickets {
+		if ticket.QRCode == "" {
+			continue
+		}
+		tickets = append(tickets, map[string]interface{}{
+			"id":       ticket.ID,
+			"category": categories[ticket.TicketCategoryID],
+			"qr_url":   h.apiURL + domain.TicketQRPath(ticket.ID, ticket.QRCode),
+		})
+	}
+
+	confirmedAt := ""
+	if order.ConfirmedAt != nil {
+		confirmedAt = order.ConfirmedAt.Format("2006-01-02T15:04:05Z")
+	}
+
+	return map[string]interface{}{
+		"event_title":     order.EventTitle,
+		"order_number":    order.OrderNumber,
+		"lines":           lines,
+		"tickets":         tickets,
+		"ticket_count":    len(tickets),
+		"total_amount":    order.TotalAmount,
+		"promo_code":      order.PromoCode,
+		"discount_amount": order.DiscountAmount,
+		"final_amount":    order.FinalAmount,
+		"currency":        order.Currency,
+		"confirmed_at":    confirmedAt,
+	}
+}
@@ -0,0 +1,133 @@
+package command
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"tixgo/modules/order/domain"
+	templateAdapters "tixgo/modules/template/adapters"
+	templateDomain "tixgo/modules/template/domain"
+	"tixgo/shared/dedup"
+	sharedMail "tixgo/shared/events/mail"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init(&logger.Config{Output: io.Discard})
+	os.Exit(m.Run())
+}
+
+// memoryOrderRepository serves the orders it holds by ID
+type memoryOrderRepository struct {
+	domain.OrderRepository
+	orders map[int64]*domain.Order
+}
+
+func (r memoryOrderRepository) GetByID(_ context.Context, id int64) (*domain.Order, error) {
+	order, ok := r.orders[id]
+	if !ok {
+		return nil, domain.ErrOrderNotFound
+	}
+	return order, nil
+}
+
+// confirmationTemplateRepository serves a confirmation template listing the QR codes of the tickets
+type confirmationTemplateRepository struct {
+	templateDomain.TemplateRepository
+}
+
+func (confirmationTemplateRepository) GetBySlug(_ context.Context, slug string) (*templateDomain.Template, error) {
+	if slug != SlugOrderConfirmation {
+		return nil, templateDomain.ErrTemplateNotFound
+	}
+	return &templateDomain.Template{
+		Slug:    slug,
+		Subject: "Order {{.order_number}}",
+		Content: `{{.event_title}}: {{.final_amount}} {{.currency}}{{range .tickets}} <img alt="{{.category}}" src="{{.qr_url}}">{{end}}`,
+	}, nil
+}
+
+// onceDeduplicator lets a key be claimed once until it is forgotten
+type onceDeduplicator struct {
+	claimed map[string]bool
+}
+
+func (d *onceDeduplicator) Claim(_ context.Context, key string, _ time.Duration) error {
+	if d.claimed[key] {
+		return dedup.ErrDuplicate
+	}
+	d.claimed[key] = true
+	return nil
+}
+
+func (d *onceDeduplicator) Forget(_ context.Context, key string) error {
+	delete(d.claimed, key)
+	return nil
+}
+
+// recordingBus keeps the events it is asked to publish
+type recordingBus struct {
+	published []any
+}
+
+func (b *recordingBus) PublishEvent(_ context.Context, event any) error {
+	b.published = append(b.published, event)
+	return nil
+}
+
+func TestSendOrderConfirmationHandler(t *testing.T) {
+	order := &domain.Order{
+		ID:          42,
+		OrganizerID: 3,
+		EventTitle:  "Jazz Night",
+		OrderNumber: "ORD-42",
+		Status:      domain.OrderStatusConfirmed,
+		Email:       "fan@example.com",
+		Lines: []*domain.OrderLine{
+			{TicketCategoryID: 1, TicketCategoryName: "VIP", Quantity: 2, UnitPrice: "25.00", Subtotal: "50.00"},
+		},
+		Tickets: []*domain.OrderTicket{
+			{ID: 7, TicketCategoryID: 1, QRCode: "v1.a+b"},
+			{ID: 8, TicketCategoryID: 1, Refunded: true},
+		},
+		FinalAmount: "50.00",
+		Currency:    "USD",
+	}
+	orders := memoryOrderRepository{orders: map[int64]*domain.Order{42: order}}
+	bus := &recordingBus{}
+	handler := NewSendOrderConfirmationHandler(orders, confirmationTemplateRepository{}, templateAdapters.NewHTMLTemplateRenderer(nil, nil, nil),
+		&onceDeduplicator{claimed: map[string]bool{}}, bus, "https://api.tixgo.io/",
+		ReceiptOptions{Issuer: domain.ReceiptIssuer{Name: "TixGo"}})
+
+	require.NoError(t, handler.Handle(context.Background(), SendOrderConfirmationCommand{OrderID: 42}))
+	require.Len(t, bus.published, 1)
+	sent := bus.published[0].(*sharedMail.EventSendMail)
+	assert.Equal(t, "fan@example.com", sent.ToMail[0].Email)
+	assert.Equal(t, "Order ORD-42", sent.Subject)
+	assert.Equal(t, int64(3), sent.OrganizerID)
+	assert.Equal(t, `Jazz Night: 50.00 USD <img alt="VIP" src="https://api.tixgo.io/v1/tickets/7/qr?code=v1.a%2Bb">`, sent.HTMLBody,
+		"refunded tickets are left out")
+	require.Len(t, sent.Attachments, 1)
+	assert.Equal(t, "RC-42.pdf", sent.Attachments[0].Filename)
+	assert.Equal(t, "application/pdf", sent.Attachments[0].ContentType)
+	assert.True(t, strings.HasPrefix(string(sent.Attachments[0].Content), "%PDF-"))
+
+	require.NoError(t, handler.Handle(context.Background(), SendOrderConfirmationCommand{OrderID: 42}))
+	assert.Len(t, bus.published, 1, "a redelivered confirmation is not mailed again")
+
+	order.ID, order.TestMode = 43, true
+	orders.orders[43] = order
+	require.NoError(t, handler.Handle(context.Background(), SendOrderConfirmationCommand{OrderID: 43}))
+	require.Len(t, bus.published, 2)
+	assert.Equal(t, "[TEST] Order ORD-42", bus.published[1].(*sharedMail.EventSendMail).Subject)
+
+	require.NoError(t, handler.Handle(context.Background(), SendOrderConfirmationCommand{OrderID: 44}), "missing orders are skipped")
+	assert.Len(t, bus.published, 2)
+}
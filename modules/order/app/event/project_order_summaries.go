@@ -0,0 +1,30 @@
+package event
+
+import (
+	"context"
+
+	"tixgo/modules/order/domain"
+	sharedOrder "tixgo/shared/events/order"
+)
+
+type projectOrderSummaries struct {
+	summaryRepo domain.OrderSummaryRepository
+}
+
+func NewProjectOrderSummaries(summaryRepo domain.OrderSummaryRepository) *projectOrderSummaries {
+	return &projectOrderSummaries{
+		summaryRepo: summaryRepo,
+	}
+}
+
+// Project projects the summaries of the changed orders again. Projecting reads the orders as they are
+// now, so a redelivered or out of order event leaves the summaries as they should be.
+func (h *projectOrderSummaries) Project(ctx context.Context, event *sharedOrder.EventOrdersChanged) error {
+	if event.EventID != 0 {
+		if err := h.summaryRepo.ProjectEvent(ctx, event.EventID); err != nil {
+			return err
+		}
+	}
+
+	return h.summaryRepo.Project(ctx, event.OrderIDs)
+}
@@ -0,0 +1,120 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"tixgo/modules/order/domain"
+	sharedOrder "tixgo/shared/events/order"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// projectedSummaries projects the summaries from the orders as they are now, keyed by order like the
+// order_summaries table, and keeps the calls it is asked to make
+type projectedSummaries struct {
+	domain.OrderSummaryRepository
+	orders      map[int64]domain.OrderSummary
+	eventOrders map[int64][]int64
+	summaries   map[int64]domain.OrderSummary
+	calls       []string
+	failEvent   bool
+}
+
+func newProjectedSummaries(orders ...domain.OrderSummary) *projectedSummaries {
+	repo := &projectedSummaries{
+		orders:      map[int64]domain.OrderSummary{},
+		eventOrders: map[int64][]int64{},
+		summaries:   map[int64]domain.OrderSummary{},
+	}
+	for _, order := range orders {
+		repo.orders[order.OrderID] = order
+		repo.eventOrders[order.EventID] = append(repo.eventOrders[order.EventID], order.OrderID)
+	}
+	return repo
+}
+
+func (r *projectedSummaries) Project(_ context.Context, orderIDs []int64) error {
+	r.calls = append(r.calls, "orders")
+	for _, id := range orderIDs {
+		if order, ok := r.orders[id]; ok {
+			r.summaries[id] = order
+		}
+	}
+	return nil
+}
+
+func (r *projectedSummaries) ProjectEvent(ctx context.Context, eventID int64) error {
+	r.calls = append(r.calls, "event")
+	if r.failEvent {
+		return errors.New("connection reset")
+	}
+	for _, id := range r.eventOrders[eventID] {
+		r.summaries[id] = r.orders[id]
+	}
+	return nil
+}
+
+func TestProjectOrderSummaries(t *testing.T) {
+	ctx := context.Background()
+	paid := domain.OrderSummary{OrderID: 1, UserID: 5, EventID: 9, TicketCount: 2, TotalAmount: "40.00", Status: "paid"}
+	pending := domain.OrderSummary{OrderID: 2, UserID: 6, EventID: 9, TicketCount: 1, TotalAmount: "20.00", Status: "pending"}
+
+	t.Run("projects the changed orders", func(t *testing.T) {
+		repo := newProjectedSummaries(paid, pending)
+		handler := NewProjectOrderSummaries(repo)
+
+		require.NoError(t, handler.Project(ctx, sharedOrder.NewEventOrdersChanged(1)))
+
+		assert.Equal(t, map[int64]domain.OrderSummary{1: paid}, repo.summaries)
+		assert.Equal(t, []string{"orders"}, repo.calls, "the event is projected only when named")
+	})
+
+	t.Run("leaves the summaries as they are on redelivery", func(t *testing.T) {
+		repo := newProjectedSummaries(paid, pending)
+		handler := NewProjectOrderSummaries(repo)
+		event := sharedOrder.NewEventOrdersChanged(1, 2)
+
+		require.NoError(t, handler.Project(ctx, event))
+		projected := map[int64]domain.OrderSummary{1: paid, 2: pending}
+		assert.Equal(t, projected, repo.summaries)
+
+		require.NoError(t, handler.Project(ctx, event))
+		assert.Equal(t, projected, repo.summaries, "one summary per order however often it is projected")
+	})
+
+	t.Run("projects the current state of orders changed out of order", func(t *testing.T) {
+		repo := newProjectedSummaries(pending)
+		handler := NewProjectOrderSummaries(repo)
+		paying := sharedOrder.NewEventOrdersChanged(2)
+
+		confirmed := pending
+		confirmed.Status = "paid"
+		repo.orders[2] = confirmed
+		require.NoError(t, handler.Project(ctx, sharedOrder.NewEventOrdersChanged(2)))
+
+		require.NoError(t, handler.Project(ctx, paying))
+		assert.Equal(t, "paid", repo.summaries[2].Status, "a late event does not roll the summary back")
+	})
+
+	t.Run("projects every order of a changed event", func(t *testing.T) {
+		repo := newProjectedSummaries(paid, pending, domain.OrderSummary{OrderID: 3, EventID: 10})
+		handler := NewProjectOrderSummaries(repo)
+
+		require.NoError(t, handler.Project(ctx, sharedOrder.NewEventOrdersOfEventChanged(9)))
+
+		assert.Equal(t, map[int64]domain.OrderSummary{1: paid, 2: pending}, repo.summaries)
+		assert.Equal(t, []string{"event", "orders"}, repo.calls)
+	})
+
+	t.Run("fails for the event to be redelivered", func(t *testing.T) {
+		repo := newProjectedSummaries(paid)
+		repo.failEvent = true
+		handler := NewProjectOrderSummaries(repo)
+
+		assert.Error(t, handler.Project(ctx, &sharedOrder.EventOrdersChanged{OrderIDs: []int64{1}, EventID: 9}))
+		assert.Empty(t, repo.summaries)
+	})
+}
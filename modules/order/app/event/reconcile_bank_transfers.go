@@ -0,0 +1,84 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"tixgo/modules/order/app/command"
+	"tixgo/modules/order/domain"
+	"tixgo/shared/webhook"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// SourceBank is the webhook source of the bank reporting the transfers received
+const SourceBank = "bank"
+
+// bankTransferPayload is a transfer as the bank reports it
+type bankTransferPayload struct {
+	TransactionID string    `json:"transaction_id"`
+	Amount        string    `json:"amount"`
+	Currency      string    `json:"currency"`
+	Remittance    string    `json:"remittance"`
+	BookedAt      time.Time `json:"booked_at"`
+}
+
+type reconcileBankTransfers struct {
+	transfers *command.ReceiveTransferHandler
+}
+
+func NewReconcileBankTransfers(transfers *command.ReceiveTransferHandler) *reconcileBankTransfers {
+	return &reconcileBankTransfers{
+		transfers: transfers,
+	}
+}
+
+// Reconcile confirms the order a transfer reported by the bank pays. Transfers that match no order
+// awaiting them, or do not pay its amount, are logged for an admin to sort out rather than retried.
+func (h *reconcileBankTransfers) Reconcile(ctx context.Context, event *webhook.EventWebhookReceived) error {
+	if event.Source != SourceBank {
+		return nil
+	}
+
+	var payload bankTransferPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		logger.Warning(ctx, "Ignored malformed bank transfer", logger.F("delivery_id", event.DeliveryID), logger.F("error", err))
+		return nil
+	}
+	if payload.TransactionID == "" || payload.Amount == "" {
+		logger.Warning(ctx, "Ignored bank transfer without transaction or amount", logger.F("delivery_id", event.DeliveryID))
+		return nil
+	}
+
+	cmd := command.ReconcileTransferCommand{
+		Remittance: payload.Remittance,
+		ReceiveTransferCommand: command.ReceiveTransferCommand{
+			TransactionID: payload.TransactionID,
+			Amount:        payload.Amount,
+			Currency:      payload.Currency,
+		},
+	}
+	if !payload.BookedAt.IsZero() {
+		cmd.ReceivedAt = &payload.BookedAt
+	}
+
+	result, err := h.transfers.Reconcile(ctx, cmd)
+	if err != nil {
+		switch err {
+		case domain.ErrOrderNotFound, domain.ErrOrderNotAwaitingTransfer, domain.ErrOrderExpired,
+			domain.ErrTransferMismatch, domain.ErrTransferInFuture:
+			logger.Warning(ctx, "Unmatched bank transfer",
+				logger.F("transaction_id", payload.TransactionID),
+				logger.F("remittance", payload.Remittance),
+				logger.F("amount", payload.Amount),
+				logger.F("reason", err.Error()))
+			return nil
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to reconcile bank transfer")
+	}
+
+	logger.Info(ctx, "Reconciled bank transfer", logger.F("transaction_id", payload.TransactionID), logger.F("order_id", result.ID))
+	return nil
+}
@@ -0,0 +1,24 @@
+package event
+
+import (
+	"context"
+
+	"tixgo/modules/order/app/command"
+	sharedOrder "tixgo/shared/events/order"
+)
+
+type sendOrderConfirmation struct {
+	confirmations *command.SendOrderConfirmationHandler
+}
+
+func NewSendOrderConfirmation(confirmations *command.SendOrderConfirmationHandler) *sendOrderConfirmation {
+	return &sendOrderConfirmation{
+		confirmations: confirmations,
+	}
+}
+
+// Send mails the buyer of a confirmed order its tickets, read from the order rather than the event so
+// the mail carries their QR codes
+func (h *sendOrderConfirmation) Send(ctx context.Context, event *sharedOrder.EventOrderConfirmed) error {
+	return h.confirmations.Handle(ctx, command.SendOrderConfirmationCommand{OrderID: event.OrderID})
+}
@@ -0,0 +1,84 @@
+package query
+
+import (
+	"context"
+
+	eventDomain "tixgo/modules/event/domain"
+	"tixgo/modules/order/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// GetCheckInStatsQuery represents the query of an organizer for the check-in statistics of their event
+type GetCheckInStatsQuery struct {
+	EventID     int64
+	OrganizerID int64
+}
+
+// CategoryCheckInStatsResult represents the check-in of the tickets of a category
+type CategoryCheckInStatsResult struct {
+	TicketCategoryID int64  `json:"ticket_category_id"`
+	Name             string `json:"name"`
+	Expected         int    `json:"expected"`
+	CheckedIn        int    `json:"checked_in"`
+}
+
+// CheckInStatsResult represents how far the check-in of an event is
+type CheckInStatsResult struct {
+	EventID   int64 `json:"event_id"`
+	Expected  int   `json:"expected"`
+	CheckedIn int   `json:"checked_in"`
+	Remaining int   `json:"remaining"`
+	// CheckedInRate is the share of the expected tickets checked in, from 0 to 1
+	CheckedInRate    float64                       `json:"checked_in_rate"`
+	LastCheckInAt    *string                       `json:"last_check_in_at"`
+	TicketCategories []*CategoryCheckInStatsResult `json:"ticket_categories"`
+}
+
+// GetCheckInStatsHandler handles getting the check-in statistics of events
+type GetCheckInStatsHandler struct {
+	checkInRepo domain.TicketCheckInRepository
+}
+
+// NewGetCheckInStatsHandler creates a new get check-in stats handler
+func NewGetCheckInStatsHandler(checkInRepo domain.TicketCheckInRepository) *GetCheckInStatsHandler {
+	return &GetCheckInStatsHandler{
+		checkInRepo: checkInRepo,
+	}
+}
+
+// Handle executes the get check-in stats query
+func (h *GetCheckInStatsHandler) Handle(ctx context.Context, query GetCheckInStatsQuery) (*CheckInStatsResult, error) {
+	stats, err := h.checkInRepo.GetStats(ctx, query.EventID, query.OrganizerID)
+	if err != nil {
+		if err == eventDomain.ErrEventNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get check-in statistics")
+	}
+
+	result := &CheckInStatsResult{
+		EventID:          stats.EventID,
+		Expected:         stats.Expected,
+		CheckedIn:        stats.CheckedIn,
+		Remaining:        stats.Remaining(),
+		TicketCategories: make([]*CategoryCheckInStatsResult, len(stats.TicketCategories)),
+	}
+	if stats.Expected > 0 {
+		result.CheckedInRate = float64(stats.CheckedIn) / float64(stats.Expected)
+	}
+	if stats.LastCheckInAt != nil {
+		lastCheckInAt := stats.LastCheckInAt.UTC().Format("2006-01-02T15:04:05Z")
+		result.LastCheckInAt = &lastCheckInAt
+	}
+	for i, category := range stats.TicketCategories {
+		result.TicketCategories[i] = &CategoryCheckInStatsResult{
+			TicketCategoryID: category.TicketCategoryID,
+			Name:             category.Name,
+			Expected:         category.Expected,
+			CheckedIn:        category.CheckedIn,
+		}
+	}
+
+	return result, nil
+}
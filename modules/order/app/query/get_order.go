@@ -0,0 +1,45 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/order/app/command"
+	"tixgo/modules/order/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// GetOrderQuery represents the query of a buyer for one of their orders
+type GetOrderQuery struct {
+	ID     int64
+	UserID int64
+}
+
+// GetOrderHandler handles getting an order
+type GetOrderHandler struct {
+	orderRepo domain.OrderRepository
+}
+
+// NewGetOrderHandler creates a new get order handler
+func NewGetOrderHandler(orderRepo domain.OrderRepository) *GetOrderHandler {
+	return &GetOrderHandler{
+		orderRepo: orderRepo,
+	}
+}
+
+// Handle executes the get order query. The orders of other users are reported as not found.
+func (h *GetOrderHandler) Handle(ctx context.Context, query GetOrderQuery) (*command.OrderResult, error) {
+	order, err := h.orderRepo.GetByID(ctx, query.ID)
+	if err != nil {
+		if err == domain.ErrOrderNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get order")
+	}
+
+	if order.UserID != query.UserID {
+		return nil, domain.ErrOrderNotFound
+	}
+
+	return command.ToOrderResult(order), nil
+}
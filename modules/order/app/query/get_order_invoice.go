@@ -0,0 +1,51 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/order/app/command"
+	"tixgo/modules/order/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// GetOrderInvoiceQuery represents the query of a buyer for the pro-forma invoice of one of their orders
+type GetOrderInvoiceQuery struct {
+	OrderID int64
+	UserID  int64
+}
+
+// GetOrderInvoiceHandler handles getting the invoice of an order
+type GetOrderInvoiceHandler struct {
+	orderRepo domain.OrderRepository
+	account   domain.BankAccount
+}
+
+// NewGetOrderInvoiceHandler creates a new get order invoice handler, the transfers being paid into
+// account
+func NewGetOrderInvoiceHandler(orderRepo domain.OrderRepository, account domain.BankAccount) *GetOrderInvoiceHandler {
+	return &GetOrderInvoiceHandler{
+		orderRepo: orderRepo,
+		account:   account,
+	}
+}
+
+// Handle executes the get order invoice query. The orders of other users are reported as not found.
+func (h *GetOrderInvoiceHandler) Handle(ctx context.Context, query GetOrderInvoiceQuery) (*command.InvoiceResult, error) {
+	order, err := h.orderRepo.GetByID(ctx, query.OrderID)
+	if err != nil {
+		if err == domain.ErrOrderNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get order")
+	}
+
+	if order.UserID != query.UserID {
+		return nil, domain.ErrOrderNotFound
+	}
+	if order.Invoice == nil {
+		return nil, domain.ErrInvoiceNotFound
+	}
+
+	return command.ToInvoiceResult(order, order.Invoice, h.account), nil
+}
@@ -0,0 +1,67 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/order/app/command"
+	"tixgo/modules/order/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// GetOrderReceiptQuery represents the query of a buyer for the PDF receipt of one of their orders
+type GetOrderReceiptQuery struct {
+	OrderID int64
+	UserID  int64
+}
+
+// ReceiptResult is the PDF receipt of an order, or its pro-forma invoice while it awaits its transfer
+type ReceiptResult struct {
+	Filename string
+	Content  []byte
+}
+
+// GetOrderReceiptHandler handles getting the receipt of an order
+type GetOrderReceiptHandler struct {
+	orderRepo domain.OrderRepository
+	options   command.ReceiptOptions
+}
+
+// NewGetOrderReceiptHandler creates a new get order receipt handler
+func NewGetOrderReceiptHandler(orderRepo domain.OrderRepository, options command.ReceiptOptions) *GetOrderReceiptHandler {
+	return &GetOrderReceiptHandler{
+		orderRepo: orderRepo,
+		options:   options,
+	}
+}
+
+// Handle executes the get order receipt query. The orders of other users are reported as not found,
+// orders neither paid nor awaiting a transfer have no receipt.
+func (h *GetOrderReceiptHandler) Handle(ctx context.Context, query GetOrderReceiptQuery) (*ReceiptResult, error) {
+	order, err := h.orderRepo.GetByID(ctx, query.OrderID)
+	if err != nil {
+		if err == domain.ErrOrderNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get order")
+	}
+
+	if order.UserID != query.UserID {
+		return nil, domain.ErrOrderNotFound
+	}
+
+	var refunds []*domain.Refund
+	if order.Status == domain.OrderStatusPartiallyRefunded || order.Status == domain.OrderStatusRefunded {
+		refunds, err = h.orderRepo.ListRefunds(ctx, order.ID)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list order refunds")
+		}
+	}
+
+	content, err := command.RenderReceipt(order, refunds, h.options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReceiptResult{Filename: domain.ReceiptFilename(order), Content: content}, nil
+}
@@ -0,0 +1,41 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/order/app/command"
+	"tixgo/modules/order/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// GetSeatHoldQuery represents the query of a buyer for the seats they hold in an event
+type GetSeatHoldQuery struct {
+	EventID int64
+	UserID  int64
+}
+
+// GetSeatHoldHandler handles getting seat holds
+type GetSeatHoldHandler struct {
+	seatHolds domain.SeatHoldStore
+}
+
+// NewGetSeatHoldHandler creates a new get seat hold handler
+func NewGetSeatHoldHandler(seatHolds domain.SeatHoldStore) *GetSeatHoldHandler {
+	return &GetSeatHoldHandler{
+		seatHolds: seatHolds,
+	}
+}
+
+// Handle executes the get seat hold query
+func (h *GetSeatHoldHandler) Handle(ctx context.Context, query GetSeatHoldQuery) (*command.SeatHoldResult, error) {
+	hold, err := h.seatHolds.Get(ctx, query.EventID, query.UserID)
+	if err != nil {
+		if err == domain.ErrSeatHoldNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get seat hold")
+	}
+
+	return command.ToSeatHoldResult(hold), nil
+}
@@ -0,0 +1,72 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/order/domain"
+	"tixgo/shared/barcode"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+const (
+	// DefaultTicketQRScale is the pixels per module of the QR codes of tickets when the query sets none
+	DefaultTicketQRScale = 8
+	// MaxTicketQRScale bounds the size of the image
+	MaxTicketQRScale = 20
+)
+
+// GetTicketQRQuery represents the query of the QR code image of a ticket, by its buyer or the organizer
+// of its event, or by anyone presenting its code
+type GetTicketQRQuery struct {
+	TicketID int64 `json:"-"`
+	// UserID is the signed in user, zero when Code authorizes the query
+	UserID int64  `json:"-"`
+	Code   string `form:"code" binding:"max=255"`
+	// Scale is the pixels per module of the image
+	Scale int `form:"scale" binding:"omitempty,min=1,max=20"`
+}
+
+// GetTicketQRHandler handles getting the QR code image of a ticket
+type GetTicketQRHandler struct {
+	orderRepo domain.OrderRepository
+}
+
+// NewGetTicketQRHandler creates a new get ticket QR handler
+func NewGetTicketQRHandler(orderRepo domain.OrderRepository) *GetTicketQRHandler {
+	return &GetTicketQRHandler{
+		orderRepo: orderRepo,
+	}
+}
+
+// Handle executes the get ticket QR query, returning the PNG of the QR code. The QR codes the caller may
+// not see are reported as not found.
+func (h *GetTicketQRHandler) Handle(ctx context.Context, query GetTicketQRQuery) ([]byte, error) {
+	qr, err := h.orderRepo.GetTicketQR(ctx, query.TicketID)
+	if err != nil {
+		if err == domain.ErrTicketQRNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get ticket QR code")
+	}
+
+	if !qr.Authorizes(query.UserID, query.Code) {
+		return nil, domain.ErrTicketQRNotFound
+	}
+
+	scale := query.Scale
+	if scale == 0 {
+		scale = DefaultTicketQRScale
+	}
+
+	matrix, err := barcode.QR([]byte(qr.Code), barcode.ECLevelM)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to encode ticket QR code")
+	}
+	png, err := barcode.PNG(matrix.Image(min(scale, MaxTicketQRScale)))
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to draw ticket QR code")
+	}
+
+	return png, nil
+}
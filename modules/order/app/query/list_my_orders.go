@@ -0,0 +1,85 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/order/domain"
+	"tixgo/shared/listing"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// maxOrderPageSize bounds the orders returned per page
+const maxOrderPageSize = 100
+
+// ListMyOrdersQuery represents the query to list the order history of a user
+type ListMyOrdersQuery struct {
+	UserID int64 `json:"-" form:"-"`
+}
+
+// OrderItem represents an order in the order history
+type OrderItem struct {
+	OrderID        int64  `json:"order_id"`
+	OrderNumber    string `json:"order_number"`
+	CustomerName   string `json:"customer_name"`
+	CustomerEmail  string `json:"customer_email"`
+	EventID        int64  `json:"event_id,omitempty"`
+	EventTitle     string `json:"event_title,omitempty"`
+	EventStartDate string `json:"event_start_date,omitempty"`
+	TicketCount    int    `json:"ticket_count"`
+	TotalAmount    string `json:"total_amount"`
+	Currency       string `json:"currency"`
+	Status         string `json:"status"`
+	CreatedAt      string `json:"created_at"`
+}
+
+// ListMyOrdersHandler handles listing the order history of a user
+type ListMyOrdersHandler struct {
+	summaryRepo domain.OrderSummaryRepository
+}
+
+// NewListMyOrdersHandler creates a new list my orders handler
+func NewListMyOrdersHandler(summaryRepo domain.OrderSummaryRepository) *ListMyOrdersHandler {
+	return &ListMyOrdersHandler{
+		summaryRepo: summaryRepo,
+	}
+}
+
+// Handle executes the list my orders query
+func (h *ListMyOrdersHandler) Handle(ctx context.Context, query *ListMyOrdersQuery, paging *listing.Paging) ([]OrderItem, error) {
+	// Ensure paging is not nil (should already be handled in HTTP layer)
+	if paging == nil {
+		paging = &listing.Paging{}
+		paging.Fulfill()
+	}
+	if paging.Limit > maxOrderPageSize {
+		paging.Limit = maxOrderPageSize
+	}
+
+	summaries, err := h.summaryRepo.ListByUserID(ctx, query.UserID, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list orders")
+	}
+
+	items := make([]OrderItem, len(summaries))
+	for i, summary := range summaries {
+		items[i] = OrderItem{
+			OrderID:       summary.OrderID,
+			OrderNumber:   summary.OrderNumber,
+			CustomerName:  summary.CustomerName,
+			CustomerEmail: summary.CustomerEmail,
+			EventID:       summary.EventID,
+			EventTitle:    summary.EventTitle,
+			TicketCount:   summary.TicketCount,
+			TotalAmount:   summary.TotalAmount,
+			Currency:      summary.Currency,
+			Status:        summary.Status,
+			CreatedAt:     summary.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		}
+		if summary.EventStartDate != nil {
+			items[i].EventStartDate = summary.EventStartDate.Format("2006-01-02T15:04:05Z")
+		}
+	}
+
+	return items, nil
+}
@@ -0,0 +1,56 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/order/app/command"
+	"tixgo/modules/order/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ListOrderRefundsQuery represents the query for the refunds of an order. UserID restricts it to the
+// orders of a customer, zero for admins.
+type ListOrderRefundsQuery struct {
+	OrderID int64
+	UserID  int64
+}
+
+// ListOrderRefundsHandler handles listing the refunds of an order
+type ListOrderRefundsHandler struct {
+	orderRepo domain.OrderRepository
+}
+
+// NewListOrderRefundsHandler creates a new list order refunds handler
+func NewListOrderRefundsHandler(orderRepo domain.OrderRepository) *ListOrderRefundsHandler {
+	return &ListOrderRefundsHandler{
+		orderRepo: orderRepo,
+	}
+}
+
+// Handle executes the list order refunds query, tracking the status of each refund. The orders of other
+// customers are reported as not found.
+func (h *ListOrderRefundsHandler) Handle(ctx context.Context, query ListOrderRefundsQuery) ([]*command.RefundResult, error) {
+	order, err := h.orderRepo.GetByID(ctx, query.OrderID)
+	if err != nil {
+		if err == domain.ErrOrderNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get order")
+	}
+
+	if query.UserID != 0 && order.UserID != query.UserID {
+		return nil, domain.ErrOrderNotFound
+	}
+
+	refunds, err := h.orderRepo.ListRefunds(ctx, order.ID)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list refunds")
+	}
+
+	results := make([]*command.RefundResult, len(refunds))
+	for i, refund := range refunds {
+		results[i] = command.ToRefundResult(refund)
+	}
+	return results, nil
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// AccessNeed is an accessibility need of the attendees of an order
+type AccessNeed string
+
+const (
+	AccessWheelchair      AccessNeed = "wheelchair"
+	AccessStepFree        AccessNeed = "step_free"
+	AccessCompanionSeat   AccessNeed = "companion_seat"
+	AccessHearingLoop     AccessNeed = "hearing_loop"
+	AccessSignLanguage    AccessNeed = "sign_language"
+	AccessVisualAssist    AccessNeed = "visual_assistance"
+	AccessServiceAnimal   AccessNeed = "service_animal"
+	AccessQuietSpace      AccessNeed = "quiet_space"
+	AccessSeatingRequired AccessNeed = "seating_required"
+)
+
+// IsValid checks if the access need is valid
+func (n AccessNeed) IsValid() bool {
+	switch n {
+	case AccessWheelchair, AccessStepFree, AccessCompanionSeat, AccessHearingLoop, AccessSignLanguage,
+		AccessVisualAssist, AccessServiceAnimal, AccessQuietSpace, AccessSeatingRequired:
+		return true
+	default:
+		return false
+	}
+}
+
+// DietaryNeed is a dietary requirement of the attendees of an order
+type DietaryNeed string
+
+const (
+	DietVegetarian   DietaryNeed = "vegetarian"
+	DietVegan        DietaryNeed = "vegan"
+	DietHalal        DietaryNeed = "halal"
+	DietKosher       DietaryNeed = "kosher"
+	DietGlutenFree   DietaryNeed = "gluten_free"
+	DietDairyFree    DietaryNeed = "dairy_free"
+	DietNutAllergy   DietaryNeed = "nut_allergy"
+	DietShellfish    DietaryNeed = "shellfish_allergy"
+	DietOtherAllergy DietaryNeed = "other_allergy"
+)
+
+// IsValid checks if the dietary need is valid
+func (n DietaryNeed) IsValid() bool {
+	switch n {
+	case DietVegetarian, DietVegan, DietHalal, DietKosher, DietGlutenFree, DietDairyFree, DietNutAllergy,
+		DietShellfish, DietOtherAllergy:
+		return true
+	default:
+		return false
+	}
+}
+
+// maxAccommodationNotes bounds the free text of an accommodation request
+const maxAccommodationNotes = 500
+
+// Accommodations are the accessibility and dietary needs the buyer asks the organizer to accommodate for
+// the attendees of an order. They may reveal health conditions, so they are only shown to the buyer and
+// to the organizer on the attendee manifest, and never carried by the messages of the buses.
+type Accommodations struct {
+	AccessNeeds  []AccessNeed  `privacy:"sensitive"`
+	DietaryNeeds []DietaryNeed `privacy:"sensitive"`
+	// Notes details the needs in the words of the buyer
+	Notes string `privacy:"sensitive"`
+}
+
+// NewAccommodations checks an accommodation request, its needs sorted and each listed once. It returns
+// nil when nothing is asked for.
+func NewAccommodations(accessNeeds []AccessNeed, dietaryNeeds []DietaryNeed, notes string) (*Accommodations, error) {
+	for _, need := range accessNeeds {
+		if !need.IsValid() {
+			return nil, ErrInvalidAccessNeed
+		}
+	}
+	for _, need := range dietaryNeeds {
+		if !need.IsValid() {
+			return nil, ErrInvalidDietaryNeed
+		}
+	}
+	notes = strings.TrimSpace(notes)
+	if len([]rune(notes)) > maxAccommodationNotes {
+		return nil, syserr.New(syserr.InvalidArgumentCode, "accommodation notes must be at most 500 characters")
+	}
+
+	if len(accessNeeds) == 0 && len(dietaryNeeds) == 0 && notes == "" {
+		return nil, nil
+	}
+	return &Accommodations{
+		AccessNeeds:  slices.Compact(slices.Sorted(slices.Values(accessNeeds))),
+		DietaryNeeds: slices.Compact(slices.Sorted(slices.Values(dietaryNeeds))),
+		Notes:        notes,
+	}, nil
+}
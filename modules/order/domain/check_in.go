@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// TicketVerifier verifies the signed payloads of the QR codes of tickets, see barcode.Keyring
+type TicketVerifier interface {
+	Open(payload string, claims any) error
+}
+
+// TicketKeys sign the QR codes of the tickets orders sell and verify the ones scanned at the door
+type TicketKeys interface {
+	TicketSealer
+	TicketVerifier
+}
+
+// OpenTicketCode verifies the QR code payload scanned at the door of an event and returns its claims,
+// ErrInvalidTicketCode if we did not sign it and ErrTicketNotForEvent if it is a ticket of another event
+func OpenTicketCode(verifier TicketVerifier, code string, eventID int64) (TicketClaims, error) {
+	var claims TicketClaims
+	if err := verifier.Open(code, &claims); err != nil {
+		return TicketClaims{}, ErrInvalidTicketCode
+	}
+	if claims.TicketID == 0 || claims.OrderID == 0 {
+		return TicketClaims{}, ErrInvalidTicketCode
+	}
+	if claims.EventID != eventID {
+		return TicketClaims{}, ErrTicketNotForEvent
+	}
+	return claims, nil
+}
+
+// TicketCheckIn is a ticket presented at the door of its event, as its order sold it
+type TicketCheckIn struct {
+	TicketID           int64
+	OrderID            int64
+	EventID            int64
+	TicketCategoryID   int64
+	TicketCategoryName string
+	// SeatSection, SeatRow and SeatNumber are empty for general admission tickets
+	SeatSection string
+	SeatRow     string
+	SeatNumber  string
+	// AttendeeName is the attendee the ticket was assigned to, if any
+	AttendeeName string
+	Status       TicketStatus
+	// Revoked tells whether the order was refunded or cancelled since, with the ticket
+	Revoked     bool
+	CheckedInAt *time.Time
+	CheckedInBy int64
+}
+
+// CheckIn admits the holder of the ticket, which becomes used. A ticket is admitted once.
+func (t *TicketCheckIn) CheckIn(userID int64, now time.Time) error {
+	if t.CheckedInAt != nil || t.Status == TicketStatusUsed {
+		return ErrTicketAlreadyCheckedIn
+	}
+	if t.Revoked || t.Status != TicketStatusSold {
+		return ErrTicketRevoked
+	}
+
+	t.Status = TicketStatusUsed
+	t.CheckedInAt = &now
+	t.CheckedInBy = userID
+	return nil
+}
+
+// CheckInStats is how far the check-in of an event is: the tickets expected at the door, sold or
+// given away and not refunded, and the ones admitted already
+type CheckInStats struct {
+	EventID   int64
+	Expected  int
+	CheckedIn int
+	// LastCheckInAt is nil until the first ticket is checked in
+	LastCheckInAt    *time.Time
+	TicketCategories []*CategoryCheckInStats
+}
+
+// CategoryCheckInStats is the check-in of the tickets of a category
+type CategoryCheckInStats struct {
+	TicketCategoryID int64
+	Name             string
+	Expected         int
+	CheckedIn        int
+}
+
+// Remaining returns how many expected tickets were not checked in yet
+func (s *CheckInStats) Remaining() int {
+	return max(s.Expected-s.CheckedIn, 0)
+}
+
+// TicketCheckInRepository defines the interface for the persistence of the check-ins of tickets.
+// Events of other organizers are reported as not found.
+type TicketCheckInRepository interface {
+	// GetTicket retrieves the ticket of the claims of a QR code, as sold by the order of the claims,
+	// of an event of the organizer, ErrTicketNotFound otherwise
+	GetTicket(ctx context.Context, claims TicketClaims, organizerID int64) (*TicketCheckIn, error)
+
+	// CheckIn records the check-in of a ticket and marks it used, atomically,
+	// ErrTicketAlreadyCheckedIn if another scan admitted it meanwhile and ErrTicketRevoked if it was
+	// refunded meanwhile
+	CheckIn(ctx context.Context, ticket *TicketCheckIn) error
+
+	// GetStats computes the check-in statistics of an event of the organizer, by ticket category
+	GetStats(ctx context.Context, eventID, organizerID int64) (*CheckInStats, error)
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"tixgo/shared/barcode"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenTicketCode(t *testing.T) {
+	key, err := barcode.NewHMACKey("k1", []byte(strings.Repeat("s", 32)))
+	require.NoError(t, err)
+	keyring, err := barcode.NewKeyring(key)
+	require.NoError(t, err)
+	otherKey, err := barcode.NewHMACKey("k1", []byte(strings.Repeat("o", 32)))
+	require.NoError(t, err)
+	otherKeyring, err := barcode.NewKeyring(otherKey)
+	require.NoError(t, err)
+
+	code, err := keyring.Seal(TicketClaims{TicketID: 10, EventID: 3, OrderID: 7})
+	require.NoError(t, err)
+
+	claims, err := OpenTicketCode(keyring, code, 3)
+	require.NoError(t, err)
+	assert.Equal(t, TicketClaims{TicketID: 10, EventID: 3, OrderID: 7}, claims)
+
+	_, err = OpenTicketCode(keyring, code, 4)
+	assert.ErrorIs(t, err, ErrTicketNotForEvent)
+
+	_, err = OpenTicketCode(otherKeyring, code, 3)
+	assert.ErrorIs(t, err, ErrInvalidTicketCode, "signed with a key we do not hold")
+
+	_, err = OpenTicketCode(keyring, "TKT-0001", 3)
+	assert.ErrorIs(t, err, ErrInvalidTicketCode)
+
+	other, err := keyring.Seal(map[string]string{"uid": "5"})
+	require.NoError(t, err)
+	_, err = OpenTicketCode(keyring, other, 3)
+	assert.ErrorIs(t, err, ErrInvalidTicketCode, "a payload of ours that is no ticket")
+}
+
+func TestTicketCheckIn_CheckIn(t *testing.T) {
+	now := time.Date(2026, 11, 1, 19, 0, 0, 0, time.UTC)
+
+	ticket := &TicketCheckIn{TicketID: 10, Status: TicketStatusSold}
+	require.NoError(t, ticket.CheckIn(9, now))
+	assert.Equal(t, TicketStatusUsed, ticket.Status)
+	assert.Equal(t, &now, ticket.CheckedInAt)
+	assert.Equal(t, int64(9), ticket.CheckedInBy)
+
+	assert.ErrorIs(t, ticket.CheckIn(9, now.Add(time.Minute)), ErrTicketAlreadyCheckedIn)
+	assert.Equal(t, &now, ticket.CheckedInAt, "the first check-in is kept")
+
+	revoked := &TicketCheckIn{TicketID: 11, Status: TicketStatusAvailable, Revoked: true}
+	assert.ErrorIs(t, revoked.CheckIn(9, now), ErrTicketRevoked)
+
+	resold := &TicketCheckIn{TicketID: 12, Status: TicketStatusSold, Revoked: true}
+	assert.ErrorIs(t, resold.CheckIn(9, now), ErrTicketRevoked, "the seat was sold again to another order")
+}
+
+func TestCheckInStats_Remaining(t *testing.T) {
+	assert.Equal(t, 30, (&CheckInStats{Expected: 100, CheckedIn: 70}).Remaining())
+	assert.Equal(t, 0, (&CheckInStats{}).Remaining())
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"tixgo/shared/errstack"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// Order domain errors
+var (
+	ErrOrderNotFound   = errstack.Sentinel(syserr.NotFoundCode, "order not found")
+	ErrOrderNotPending = errstack.Sentinel(syserr.ConflictCode, "the order is no longer pending")
+	ErrOrderExpired    = errstack.Sentinel(syserr.ConflictCode, "the order expired, its tickets were released")
+	ErrEmptyOrder      = errstack.Sentinel(syserr.InvalidArgumentCode, "an order needs at least one ticket")
+	ErrSalesNotOpen    = errstack.Sentinel(syserr.ConflictCode, "these tickets are not on sale")
+
+	ErrOrderNotRefundable  = errstack.Sentinel(syserr.ConflictCode, "only confirmed orders can be refunded")
+	ErrNothingToRefund     = errstack.Sentinel(syserr.ConflictCode, "the order has no ticket left to refund")
+	ErrTicketNotInOrder    = errstack.Sentinel(syserr.InvalidArgumentCode, "the ticket is not part of the order")
+	ErrTicketNotRefundable = errstack.Sentinel(syserr.ConflictCode, "the ticket was already refunded or used")
+	ErrNoPayment           = errstack.Sentinel(syserr.ConflictCode, "the order has no completed payment to refund")
+
+	ErrBankTransferDisabled     = errstack.Sentinel(syserr.InvalidArgumentCode, "orders cannot be paid by bank transfer")
+	ErrOrderNotAwaitingTransfer = errstack.Sentinel(syserr.ConflictCode, "the order is not awaiting a bank transfer")
+	ErrTransferMismatch         = errstack.Sentinel(syserr.InvalidArgumentCode, "the transfer does not pay the amount due in the currency of the order")
+	ErrTransferInFuture         = errstack.Sentinel(syserr.InvalidArgumentCode, "received_at cannot be in the future")
+	ErrInvoiceNotFound          = errstack.Sentinel(syserr.NotFoundCode, "the order has no invoice, it is not paid by bank transfer")
+	ErrTicketQRNotFound         = errstack.Sentinel(syserr.NotFoundCode, "ticket QR code not found")
+	ErrReceiptUnavailable       = errstack.Sentinel(syserr.ConflictCode, "the order has no receipt until it is paid")
+
+	ErrInvalidTicketCode      = errstack.Sentinel(syserr.InvalidArgumentCode, "the code is not a ticket of ours, it may be forged or damaged")
+	ErrTicketNotForEvent      = errstack.Sentinel(syserr.InvalidArgumentCode, "the ticket is for another event")
+	ErrTicketNotFound         = errstack.Sentinel(syserr.NotFoundCode, "ticket not found")
+	ErrTicketRevoked          = errstack.Sentinel(syserr.ConflictCode, "the ticket was refunded or cancelled, it is no longer valid")
+	ErrTicketAlreadyCheckedIn = errstack.Sentinel(syserr.ConflictCode, "the ticket was already checked in")
+
+	ErrSeatHeld          = errstack.Sentinel(syserr.ConflictCode, "another buyer holds one of these seats")
+	ErrSeatUnavailable   = errstack.Sentinel(syserr.ConflictCode, "one of these seats is not for sale")
+	ErrSeatNotHeld       = errstack.Sentinel(syserr.ConflictCode, "hold the seats you select before checking out, your hold may have expired")
+	ErrSeatHoldNotFound  = errstack.Sentinel(syserr.NotFoundCode, "you hold no seat of this event")
+	ErrTooManySeatsHeld  = errstack.Sentinel(syserr.InvalidArgumentCode, "you cannot hold more seats than the tickets of an order")
+	ErrDuplicateSeat     = errstack.Sentinel(syserr.InvalidArgumentCode, "a seat is selected twice")
+	ErrSeatCountMismatch = errstack.Sentinel(syserr.InvalidArgumentCode, "select as many seat_ids as the quantity of the line")
+
+	ErrInvalidAccessNeed  = errstack.Sentinel(syserr.InvalidArgumentCode, "access_needs must be wheelchair, step_free, companion_seat, hearing_loop, sign_language, visual_assistance, service_animal, quiet_space or seating_required")
+	ErrInvalidDietaryNeed = errstack.Sentinel(syserr.InvalidArgumentCode, "dietary_needs must be vegetarian, vegan, halal, kosher, gluten_free, dairy_free, nut_allergy, shellfish_allergy or other_allergy")
+)
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// BillingDetails are the details of the company a pro-forma invoice is made out to
+type BillingDetails struct {
+	CompanyName string
+	// TaxID is the VAT or tax number of the company, empty when it has none
+	TaxID   string
+	Address string
+	// Email receives the invoice, besides the buyer
+	Email string
+}
+
+// Validate checks that the details identify the company and where to send its invoice
+func (b BillingDetails) Validate() error {
+	if strings.TrimSpace(b.CompanyName) == "" || strings.TrimSpace(b.Address) == "" {
+		return syserr.New(syserr.InvalidArgumentCode, "company_name and address are required to invoice a company")
+	}
+	if _, err := mail.ParseAddress(b.Email); err != nil {
+		return syserr.New(syserr.InvalidArgumentCode, "email must be a valid email address")
+	}
+	return nil
+}
+
+// BankAccount is the account bank transfers are paid into
+type BankAccount struct {
+	Beneficiary string
+	BankName    string
+	IBAN        string
+	BIC         string
+}
+
+// Invoice is the pro-forma invoice of an order paid by bank transfer: what the company owes, by when,
+// and the reference its transfer must carry to be matched with the order
+type Invoice struct {
+	OrderID          int64
+	Number           string
+	PaymentReference string
+	Billing          BillingDetails
+	DueAt            time.Time
+	IssuedAt         time.Time
+}
+
+// InvoiceNumber returns the number of the invoice of an order, which the order number identifies
+func InvoiceNumber(orderNumber string) string {
+	return "PF-" + strings.TrimPrefix(orderNumber, "ORD-")
+}
+
+// Transfer is a bank transfer received for an order
+type Transfer struct {
+	// TransactionID identifies the transfer at the bank
+	TransactionID string
+	Amount        string
+	Currency      string
+	ReceivedAt    time.Time
+}
+
+// paymentReferencePattern matches the order numbers transfers carry as their reference
+var paymentReferencePattern = regexp.MustCompile(`ORD-[0-9A-F]{12}`)
+
+// FindPaymentReference finds the payment reference in the remittance text of a transfer, which
+// customers fill in as they like; false if there is none
+func FindPaymentReference(remittance string) (string, bool) {
+	reference := paymentReferencePattern.FindString(strings.ToUpper(remittance))
+	return reference, reference != ""
+}
@@ -0,0 +1,392 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	eventDomain "tixgo/modules/event/domain"
+	promotionDomain "tixgo/modules/promotion/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// OrderStatus is the status of an order
+type OrderStatus string
+
+const (
+	OrderStatusPending           OrderStatus = "pending"
+	OrderStatusAwaitingTransfer  OrderStatus = "awaiting_transfer"
+	OrderStatusConfirmed         OrderStatus = "confirmed"
+	OrderStatusCancelled         OrderStatus = "cancelled"
+	OrderStatusRefunded          OrderStatus = "refunded"
+	OrderStatusPartiallyRefunded OrderStatus = "partially_refunded"
+)
+
+// TicketStatus is the status of a ticket
+type TicketStatus string
+
+const (
+	TicketStatusAvailable TicketStatus = "available"
+	TicketStatusReserved  TicketStatus = "reserved"
+	TicketStatusSold      TicketStatus = "sold"
+	TicketStatusCancelled TicketStatus = "cancelled"
+	TicketStatusUsed      TicketStatus = "used"
+)
+
+// CheckoutEvent is an event as checkouts see it, with the ticket categories asked for
+type CheckoutEvent struct {
+	ID                 int64
+	OrganizerID        int64
+	Title              string
+	Status             eventDomain.EventStatus
+	MaxTicketsPerOrder int
+	// Currency is the one the categories are priced in, and orders placed in
+	Currency string
+	// TestMode events place their orders in test mode
+	TestMode bool
+	// Categories are the categories of the event asked for, by ID
+	Categories map[int64]*CheckoutCategory
+}
+
+// CheckoutCategory is a ticket category as checkouts see it
+type CheckoutCategory struct {
+	ID          int64
+	Name        string
+	MaxPerOrder int
+	// SaleStartDate and SaleEndDate are the sale window of the category, the one of its event when it
+	// has none
+	SaleStartDate *time.Time
+	SaleEndDate   *time.Time
+	// SalesPaused tells whether the sales of the category or of its event are paused
+	SalesPaused bool
+}
+
+// OrderLine is the quantity of tickets of a category an order holds. UnitPrice and Subtotal are set
+// once the order is stored, at the price of the category then.
+type OrderLine struct {
+	TicketCategoryID   int64
+	TicketCategoryName string
+	Quantity           int
+	// SeatIDs are the seats of a seated category the buyer selected and holds, one per ticket; the
+	// best available seats are taken when empty
+	SeatIDs   []int64
+	UnitPrice string
+	Subtotal  string
+}
+
+// OrderTicket is a ticket an order holds
+type OrderTicket struct {
+	ID               int64
+	TicketCategoryID int64
+	// Seated tickets are seats of a seat map, the others are created for the order
+	Seated bool
+	Status TicketStatus
+	// Refunded tickets were given back by a refund of the order; a refunded seat may have been sold
+	// again since, so its status is the one of its new order
+	Refunded bool
+	// QRCode is the signed payload of the QR code scanned at the entrance, issued once the order is
+	// confirmed; empty before and once refunded
+	QRCode string
+}
+
+// Order is a purchase of tickets of an event. A checkout creates it pending, or awaiting transfer when
+// paid by bank transfer, holding its tickets until ExpiresAt; it is confirmed once paid or cancelled
+// when it expires, releasing them.
+type Order struct {
+	ID          int64
+	UserID      int64
+	EventID     int64
+	OrganizerID int64
+	EventTitle  string
+	OrderNumber string
+	Status      OrderStatus
+	Email       string
+	// TestMode orders were placed on test mode events and paid with fake payments
+	TestMode    bool
+	Lines       []*OrderLine
+	Tickets     []*OrderTicket
+	TotalAmount string
+	// PromoCode is the code the order was placed with, DiscountAmount what it took off the total and
+	// FinalAmount what the customer pays
+	PromoCode      string
+	DiscountAmount string
+	FinalAmount    string
+	// TaxAmount is the tax and ServiceFee the fee FinalAmount includes
+	TaxAmount  string
+	ServiceFee string
+	Currency   string
+	// Invoice is the pro-forma invoice of an order paid by bank transfer, nil for the other orders
+	Invoice *Invoice
+	// Accommodations are the needs the buyer asked the organizer to accommodate, nil when none
+	Accommodations *Accommodations
+	ExpiresAt      *time.Time
+	ConfirmedAt    *time.Time
+	CancelledAt    *time.Time
+	CreatedAt      time.Time
+}
+
+// NewOrder checks out the lines of tickets of the event for a buyer, the order holding them for hold.
+// The categories must be on sale and the quantities within the per-order limits of the categories and
+// of the event; the stock is checked when the tickets are reserved.
+func NewOrder(userID int64, event *CheckoutEvent, lines []*OrderLine, hold time.Duration, now time.Time) (*Order, error) {
+	switch event.Status {
+	case eventDomain.EventStatusDraft:
+		return nil, eventDomain.ErrEventNotPublished
+	case eventDomain.EventStatusCancelled, eventDomain.EventStatusCompleted:
+		return nil, eventDomain.ErrEventClosed
+	}
+	if len(lines) == 0 {
+		return nil, ErrEmptyOrder
+	}
+
+	total := 0
+	seen := make(map[int64]bool, len(lines))
+	for _, line := range lines {
+		if line.Quantity <= 0 {
+			return nil, syserr.New(syserr.InvalidArgumentCode, "quantity must be positive")
+		}
+		if len(line.SeatIDs) > 0 && len(line.SeatIDs) != line.Quantity {
+			return nil, ErrSeatCountMismatch
+		}
+		if seen[line.TicketCategoryID] {
+			return nil, eventDomain.ErrDuplicateTicketCategory
+		}
+		seen[line.TicketCategoryID] = true
+
+		category, ok := event.Categories[line.TicketCategoryID]
+		if !ok {
+			return nil, eventDomain.ErrTicketCategoryNotFound
+		}
+		if category.SaleStartDate != nil && now.Before(*category.SaleStartDate) ||
+			category.SaleEndDate != nil && !now.Before(*category.SaleEndDate) {
+			return nil, ErrSalesNotOpen
+		}
+		if category.SalesPaused {
+			return nil, eventDomain.ErrSalesPaused
+		}
+		if line.Quantity > category.MaxPerOrder {
+			return nil, eventDomain.ErrTicketLimitExceeded
+		}
+
+		line.TicketCategoryName = category.Name
+		total += line.Quantity
+	}
+	if total > event.MaxTicketsPerOrder {
+		return nil, eventDomain.ErrTicketLimitExceeded
+	}
+
+	expiresAt := now.Add(hold)
+	return &Order{
+		UserID:      userID,
+		EventID:     event.ID,
+		OrganizerID: event.OrganizerID,
+		EventTitle:  event.Title,
+		Status:      OrderStatusPending,
+		Currency:    event.Currency,
+		TestMode:    event.TestMode,
+		Lines:       lines,
+		ExpiresAt:   &expiresAt,
+	}, nil
+}
+
+// Confirm confirms a pending order once paid, before it expires
+func (o *Order) Confirm(now time.Time) error {
+	if o.Status != OrderStatusPending {
+		return ErrOrderNotPending
+	}
+	if o.ExpiresAt != nil && !now.Before(*o.ExpiresAt) {
+		return ErrOrderExpired
+	}
+
+	o.Status = OrderStatusConfirmed
+	o.ConfirmedAt = &now
+	o.sellTickets()
+	return nil
+}
+
+// sellTickets marks the tickets the order holds as sold, as confirming it does
+func (o *Order) sellTickets() {
+	for _, ticket := range o.Tickets {
+		if ticket.Status == TicketStatusReserved {
+			ticket.Status = TicketStatusSold
+		}
+	}
+}
+
+// PayByTransfer makes a new order wait for a bank transfer rather than a payment at checkout: it holds
+// its tickets for hold, and a pro-forma invoice made out to billing is due when the hold ends.
+func (o *Order) PayByTransfer(billing BillingDetails, hold time.Duration, now time.Time) error {
+	if o.Status != OrderStatusPending {
+		return ErrOrderNotPending
+	}
+	if err := billing.Validate(); err != nil {
+		return err
+	}
+
+	expiresAt := now.Add(hold)
+	o.Status = OrderStatusAwaitingTransfer
+	o.ExpiresAt = &expiresAt
+	o.Invoice = &Invoice{Billing: billing, DueAt: expiresAt}
+	return nil
+}
+
+// ReceiveTransfer confirms an order awaiting a bank transfer once the transfer is received. The
+// transfer must pay the final amount of the order, in its currency, and have been received before the
+// order expired, even when it is recorded later.
+func (o *Order) ReceiveTransfer(transfer *Transfer, now time.Time) error {
+	if o.Status != OrderStatusAwaitingTransfer {
+		return ErrOrderNotAwaitingTransfer
+	}
+	if transfer.ReceivedAt.After(now) {
+		return ErrTransferInFuture
+	}
+	if !strings.EqualFold(transfer.Currency, o.Currency) {
+		return ErrTransferMismatch
+	}
+	paid, err := promotionDomain.ParseCents(transfer.Amount)
+	if err != nil {
+		return ErrTransferMismatch
+	}
+	due, err := promotionDomain.ParseCents(o.FinalAmount)
+	if err != nil || paid != due {
+		return ErrTransferMismatch
+	}
+	if o.ExpiresAt != nil && !transfer.ReceivedAt.Before(*o.ExpiresAt) {
+		return ErrOrderExpired
+	}
+
+	o.Status = OrderStatusConfirmed
+	o.ConfirmedAt = &now
+	o.sellTickets()
+	return nil
+}
+
+// Refund gives back tickets of a confirmed or partially refunded order, every refundable one when
+// ticketIDs is empty. Only sold tickets are refundable, used ones were checked in. The order becomes
+// refunded once none of its tickets is left, partially refunded otherwise.
+func (o *Order) Refund(ticketIDs []int64, reason string) (*Refund, error) {
+	if o.Status != OrderStatusConfirmed && o.Status != OrderStatusPartiallyRefunded {
+		return nil, ErrOrderNotRefundable
+	}
+
+	tickets := make(map[int64]*OrderTicket, len(o.Tickets))
+	for _, ticket := range o.Tickets {
+		tickets[ticket.ID] = ticket
+	}
+
+	refund := &Refund{OrderID: o.ID, Reason: reason, Status: RefundStatusPending}
+	if len(ticketIDs) == 0 {
+		for _, ticket := range o.Tickets {
+			if ticket.refundable() {
+				refund.Tickets = append(refund.Tickets, ticket)
+			}
+		}
+		if len(refund.Tickets) == 0 {
+			return nil, ErrNothingToRefund
+		}
+	}
+
+	seen := make(map[int64]bool, len(ticketIDs))
+	for _, id := range ticketIDs {
+		ticket, ok := tickets[id]
+		if !ok {
+			return nil, ErrTicketNotInOrder
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if !ticket.refundable() {
+			return nil, ErrTicketNotRefundable
+		}
+		refund.Tickets = append(refund.Tickets, ticket)
+	}
+
+	refunding := make(map[int64]bool, len(refund.Tickets))
+	for _, ticket := range refund.Tickets {
+		refunding[ticket.ID] = true
+	}
+	refund.Full = true
+	for _, ticket := range o.Tickets {
+		if !ticket.Refunded && !refunding[ticket.ID] {
+			refund.Full = false
+			break
+		}
+	}
+
+	o.Status = OrderStatusPartiallyRefunded
+	if refund.Full {
+		o.Status = OrderStatusRefunded
+	}
+	return refund, nil
+}
+
+func (t *OrderTicket) refundable() bool {
+	return !t.Refunded && t.Status == TicketStatusSold
+}
+
+// SeatedTicketIDs returns the IDs of the seats the order holds
+func (o *Order) SeatedTicketIDs() []int64 {
+	var ids []int64
+	for _, ticket := range o.Tickets {
+		if ticket.Seated {
+			ids = append(ids, ticket.ID)
+		}
+	}
+	return ids
+}
+
+// OrderRepository defines the interface for order persistence
+type OrderRepository interface {
+	// GetCheckoutEvent retrieves an event with the categories of categoryIDs that belong to it
+	GetCheckoutEvent(ctx context.Context, eventID int64, categoryIDs []int64) (*CheckoutEvent, error)
+
+	// Create reserves the tickets of a checkout and stores its pending order, atomically, ErrSoldOut if
+	// fewer tickets of a category are left than asked for. The invoice of an order paid by bank transfer
+	// is numbered after the order and stored with it. With a promo code, the order is discounted
+	// and the code redeemed in the same transaction, failing with ErrPromoCodeExhausted or
+	// ErrPromoCodeUserLimit once its usage limits are reached. The best available seats leave out
+	// heldSeats, the seats other buyers hold; the seats selected for a line must be available,
+	// ErrSeatUnavailable otherwise.
+	Create(ctx context.Context, order *Order, promo *promotionDomain.PromoCode, heldSeats []int64) error
+
+	// AvailableSeats returns the tickets of ticketIDs that are seats of the event on sale, neither sold
+	// nor held by an order
+	AvailableSeats(ctx context.Context, eventID int64, ticketIDs []int64) ([]int64, error)
+
+	// GetByID retrieves an order with its lines, tickets and invoice
+	GetByID(ctx context.Context, id int64) (*Order, error)
+
+	// Confirm sells the tickets a pending order holds, ErrOrderNotPending if it was confirmed or expired
+	// meanwhile
+	Confirm(ctx context.Context, order *Order) error
+
+	// ReceiveTransfer records the bank transfer paying an order awaiting it as a completed payment and
+	// sells its tickets, atomically, ErrOrderNotAwaitingTransfer if it was confirmed or expired meanwhile
+	ReceiveTransfer(ctx context.Context, order *Order, transfer *Transfer) error
+
+	// GetByPaymentReference retrieves the order paid by bank transfer with a payment reference
+	GetByPaymentReference(ctx context.Context, reference string) (*Order, error)
+
+	// ExpirePending cancels up to limit checkout orders pending or awaiting transfer past their expiry at
+	// before, releasing their tickets and their promo code redemption. Orders being expired by another
+	// instance are skipped.
+	ExpirePending(ctx context.Context, before time.Time, limit int) ([]*Order, error)
+
+	// Refund records a refund of the order against its completed payment and gives its tickets back,
+	// atomically: seats become available again, general admission tickets are cancelled, the sold
+	// quantities of their categories are decreased and the order takes its new status. The amount of
+	// the refund is set from the prices of its tickets, capped at what is left of the payment. It fails
+	// with ErrNoPayment if the order has no completed payment, and with ErrOrderNotRefundable or
+	// ErrTicketNotRefundable if the order or its tickets changed meanwhile. Refunds of test mode
+	// orders are completed at once.
+	Refund(ctx context.Context, order *Order, refund *Refund) error
+
+	// ListRefunds retrieves the refunds of an order with their tickets, oldest first
+	ListRefunds(ctx context.Context, orderID int64) ([]*Refund, error)
+
+	// GetTicketQR retrieves the QR code of a ticket sold by a confirmed order and not refunded since,
+	// ErrTicketQRNotFound otherwise
+	GetTicketQR(ctx context.Context, ticketID int64) (*TicketQR, error)
+}
@@ -0,0 +1,276 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	eventDomain "tixgo/modules/event/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func checkoutEvent(now time.Time) *CheckoutEvent {
+	saleEnd := now.Add(time.Hour)
+	return &CheckoutEvent{
+		ID:                 3,
+		OrganizerID:        9,
+		Status:             eventDomain.EventStatusPublished,
+		MaxTicketsPerOrder: 6,
+		Currency:           "VND",
+		Categories: map[int64]*CheckoutCategory{
+			1: {ID: 1, Name: "General", MaxPerOrder: 4, SaleEndDate: &saleEnd},
+			2: {ID: 2, Name: "VIP", MaxPerOrder: 2},
+		},
+	}
+}
+
+func TestNewOrder(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	order, err := NewOrder(5, checkoutEvent(now), []*OrderLine{{TicketCategoryID: 1, Quantity: 4}, {TicketCategoryID: 2, Quantity: 2}}, 15*time.Minute, now)
+	require.NoError(t, err)
+	assert.Equal(t, OrderStatusPending, order.Status)
+	assert.Equal(t, int64(9), order.OrganizerID)
+	assert.Equal(t, now.Add(15*time.Minute), *order.ExpiresAt)
+	assert.Equal(t, "VIP", order.Lines[1].TicketCategoryName)
+	assert.Equal(t, "VND", order.Currency, "placed in the currency of the event")
+	assert.False(t, order.TestMode)
+
+	testEvent := checkoutEvent(now)
+	testEvent.TestMode = true
+	order, err = NewOrder(5, testEvent, []*OrderLine{{TicketCategoryID: 1, Quantity: 1}}, 15*time.Minute, now)
+	require.NoError(t, err)
+	assert.True(t, order.TestMode, "the orders of test mode events are in test mode")
+
+	tests := []struct {
+		name   string
+		modify func(e *CheckoutEvent)
+		lines  []*OrderLine
+		want   error
+	}{
+		{name: "draft event", modify: func(e *CheckoutEvent) { e.Status = eventDomain.EventStatusDraft }, lines: []*OrderLine{{TicketCategoryID: 1, Quantity: 1}}, want: eventDomain.ErrEventNotPublished},
+		{name: "cancelled event", modify: func(e *CheckoutEvent) { e.Status = eventDomain.EventStatusCancelled }, lines: []*OrderLine{{TicketCategoryID: 1, Quantity: 1}}, want: eventDomain.ErrEventClosed},
+		{name: "no lines", want: ErrEmptyOrder},
+		{name: "unknown category", lines: []*OrderLine{{TicketCategoryID: 7, Quantity: 1}}, want: eventDomain.ErrTicketCategoryNotFound},
+		{name: "category twice", lines: []*OrderLine{{TicketCategoryID: 2, Quantity: 1}, {TicketCategoryID: 2, Quantity: 1}}, want: eventDomain.ErrDuplicateTicketCategory},
+		{name: "above the category limit", lines: []*OrderLine{{TicketCategoryID: 2, Quantity: 3}}, want: eventDomain.ErrTicketLimitExceeded},
+		{name: "above the event limit", modify: func(e *CheckoutEvent) { e.MaxTicketsPerOrder = 5 }, lines: []*OrderLine{{TicketCategoryID: 1, Quantity: 4}, {TicketCategoryID: 2, Quantity: 2}}, want: eventDomain.ErrTicketLimitExceeded},
+		{name: "sales paused", modify: func(e *CheckoutEvent) { e.Categories[2].SalesPaused = true }, lines: []*OrderLine{{TicketCategoryID: 2, Quantity: 1}}, want: eventDomain.ErrSalesPaused},
+		{name: "sales ended", modify: func(e *CheckoutEvent) { *e.Categories[1].SaleEndDate = now }, lines: []*OrderLine{{TicketCategoryID: 1, Quantity: 1}}, want: ErrSalesNotOpen},
+		{name: "fewer seats than tickets", lines: []*OrderLine{{TicketCategoryID: 1, Quantity: 2, SeatIDs: []int64{8}}}, want: ErrSeatCountMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := checkoutEvent(now)
+			if tt.modify != nil {
+				tt.modify(event)
+			}
+			_, err := NewOrder(5, event, tt.lines, 15*time.Minute, now)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestOrder_Confirm(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	order, err := NewOrder(5, checkoutEvent(now), []*OrderLine{{TicketCategoryID: 1, Quantity: 1}}, 15*time.Minute, now)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, order.Confirm(now.Add(15*time.Minute)), ErrOrderExpired)
+	require.NoError(t, order.Confirm(now.Add(time.Minute)))
+	assert.Equal(t, OrderStatusConfirmed, order.Status)
+	assert.ErrorIs(t, order.Confirm(now.Add(time.Minute)), ErrOrderNotPending)
+}
+
+func TestOrder_Refund(t *testing.T) {
+	confirmedOrder := func() *Order {
+		return &Order{
+			ID:     4,
+			Status: OrderStatusConfirmed,
+			Tickets: []*OrderTicket{
+				{ID: 10, Status: TicketStatusSold, Seated: true},
+				{ID: 11, Status: TicketStatusSold},
+				{ID: 12, Status: TicketStatusUsed},
+			},
+		}
+	}
+
+	order := confirmedOrder()
+	refund, err := order.Refund([]int64{10, 10}, "moved abroad")
+	require.NoError(t, err)
+	assert.Equal(t, []int64{10}, refund.TicketIDs())
+	assert.Equal(t, []int64{10}, refund.SeatedTicketIDs())
+	assert.Equal(t, RefundStatusPending, refund.Status)
+	assert.False(t, refund.Full)
+	assert.Equal(t, OrderStatusPartiallyRefunded, order.Status)
+
+	// the used ticket stays, so refunding the rest is not a full refund
+	order = confirmedOrder()
+	order.Tickets[0].Refunded = true
+	order.Status = OrderStatusPartiallyRefunded
+	refund, err = order.Refund(nil, "event moved")
+	require.NoError(t, err)
+	assert.Equal(t, []int64{11}, refund.TicketIDs())
+	assert.False(t, refund.Full)
+
+	order = confirmedOrder()
+	order.Tickets = order.Tickets[:2]
+	refund, err = order.Refund(nil, "event moved")
+	require.NoError(t, err)
+	assert.True(t, refund.Full)
+	assert.Equal(t, OrderStatusRefunded, order.Status)
+
+	tests := []struct {
+		name      string
+		modify    func(o *Order)
+		ticketIDs []int64
+		want      error
+	}{
+		{name: "pending order", modify: func(o *Order) { o.Status = OrderStatusPending }, want: ErrOrderNotRefundable},
+		{name: "refunded order", modify: func(o *Order) { o.Status = OrderStatusRefunded }, want: ErrOrderNotRefundable},
+		{name: "ticket of another order", ticketIDs: []int64{99}, want: ErrTicketNotInOrder},
+		{name: "used ticket", ticketIDs: []int64{12}, want: ErrTicketNotRefundable},
+		{name: "refunded ticket", modify: func(o *Order) { o.Tickets[1].Refunded = true }, ticketIDs: []int64{11}, want: ErrTicketNotRefundable},
+		{name: "nothing left", modify: func(o *Order) { o.Tickets[0].Refunded, o.Tickets[1].Refunded = true, true }, want: ErrNothingToRefund},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := confirmedOrder()
+			if tt.modify != nil {
+				tt.modify(order)
+			}
+			_, err := order.Refund(tt.ticketIDs, "reason")
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestOrder_PayByTransfer(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	billing := BillingDetails{CompanyName: "Acme Ltd", Address: "1 Main St", Email: "billing@acme.test"}
+
+	order, err := NewOrder(5, checkoutEvent(now), []*OrderLine{{TicketCategoryID: 1, Quantity: 2}}, 15*time.Minute, now)
+	require.NoError(t, err)
+	require.NoError(t, order.PayByTransfer(billing, 7*24*time.Hour, now))
+	assert.Equal(t, OrderStatusAwaitingTransfer, order.Status)
+	assert.Equal(t, now.Add(7*24*time.Hour), *order.ExpiresAt)
+	assert.Equal(t, *order.ExpiresAt, order.Invoice.DueAt)
+	assert.Equal(t, billing, order.Invoice.Billing)
+
+	assert.ErrorIs(t, order.PayByTransfer(billing, time.Hour, now), ErrOrderNotPending)
+
+	order, err = NewOrder(5, checkoutEvent(now), []*OrderLine{{TicketCategoryID: 1, Quantity: 2}}, 15*time.Minute, now)
+	require.NoError(t, err)
+	assert.Error(t, order.PayByTransfer(BillingDetails{CompanyName: "Acme Ltd", Address: "1 Main St", Email: "acme"}, time.Hour, now))
+	assert.Error(t, order.PayByTransfer(BillingDetails{Address: "1 Main St", Email: "billing@acme.test"}, time.Hour, now))
+	assert.Equal(t, OrderStatusPending, order.Status)
+}
+
+func TestOrder_ReceiveTransfer(t *testing.T) {
+	now := time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC)
+	awaitingOrder := func() *Order {
+		expiresAt := now.Add(-time.Hour)
+		return &Order{Status: OrderStatusAwaitingTransfer, FinalAmount: "250.00", Currency: "USD", ExpiresAt: &expiresAt}
+	}
+
+	// received before the order expired, recorded after
+	order := awaitingOrder()
+	require.NoError(t, order.ReceiveTransfer(&Transfer{Amount: "250", Currency: "usd", ReceivedAt: now.Add(-2 * time.Hour)}, now))
+	assert.Equal(t, OrderStatusConfirmed, order.Status)
+	assert.Equal(t, now, *order.ConfirmedAt)
+
+	tests := []struct {
+		name     string
+		modify   func(o *Order)
+		transfer Transfer
+		want     error
+	}{
+		{name: "pending order", modify: func(o *Order) { o.Status = OrderStatusPending }, transfer: Transfer{Amount: "250.00", Currency: "USD", ReceivedAt: now.Add(-2 * time.Hour)}, want: ErrOrderNotAwaitingTransfer},
+		{name: "short payment", transfer: Transfer{Amount: "249.99", Currency: "USD", ReceivedAt: now.Add(-2 * time.Hour)}, want: ErrTransferMismatch},
+		{name: "other currency", transfer: Transfer{Amount: "250.00", Currency: "EUR", ReceivedAt: now.Add(-2 * time.Hour)}, want: ErrTransferMismatch},
+		{name: "invalid amount", transfer: Transfer{Amount: "250,00", Currency: "USD", ReceivedAt: now.Add(-2 * time.Hour)}, want: ErrTransferMismatch},
+		{name: "received after expiry", transfer: Transfer{Amount: "250.00", Currency: "USD", ReceivedAt: now.Add(-time.Hour)}, want: ErrOrderExpired},
+		{name: "received in the future", transfer: Transfer{Amount: "250.00", Currency: "USD", ReceivedAt: now.Add(time.Minute)}, want: ErrTransferInFuture},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := awaitingOrder()
+			if tt.modify != nil {
+				tt.modify(order)
+			}
+			assert.ErrorIs(t, order.ReceiveTransfer(&tt.transfer, now), tt.want)
+			assert.NotEqual(t, OrderStatusConfirmed, order.Status)
+		})
+	}
+}
+
+func TestFindPaymentReference(t *testing.T) {
+	reference, ok := FindPaymentReference("Invoice PF-0A1B2C3D4E5F ref ord-0a1b2c3d4e5f thanks")
+	assert.True(t, ok)
+	assert.Equal(t, "ORD-0A1B2C3D4E5F", reference)
+
+	_, ok = FindPaymentReference("tickets for the team")
+	assert.False(t, ok)
+}
+
+func TestNewAccommodations(t *testing.T) {
+	accommodations, err := NewAccommodations(
+		[]AccessNeed{AccessWheelchair, AccessCompanionSeat, AccessWheelchair},
+		[]DietaryNeed{DietVegan},
+		"  ramp at the north entrance  ",
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []AccessNeed{AccessCompanionSeat, AccessWheelchair}, accommodations.AccessNeeds)
+	assert.Equal(t, []DietaryNeed{DietVegan}, accommodations.DietaryNeeds)
+	assert.Equal(t, "ramp at the north entrance", accommodations.Notes)
+
+	accommodations, err = NewAccommodations(nil, nil, "   ")
+	require.NoError(t, err)
+	assert.Nil(t, accommodations)
+
+	_, err = NewAccommodations([]AccessNeed{"jetpack"}, nil, "")
+	assert.ErrorIs(t, err, ErrInvalidAccessNeed)
+
+	_, err = NewAccommodations(nil, []DietaryNeed{"carnivore"}, "")
+	assert.ErrorIs(t, err, ErrInvalidDietaryNeed)
+
+	_, err = NewAccommodations(nil, nil, strings.Repeat("é", 501))
+	assert.Error(t, err)
+}
+
+func TestNewSeatHold(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	hold, err := NewSeatHold(5, checkoutEvent(now), []int64{8, 4})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), hold.EventID)
+	assert.True(t, hold.Holds([]int64{4}))
+	assert.False(t, hold.Holds([]int64{4, 9}))
+
+	tests := []struct {
+		name   string
+		modify func(e *CheckoutEvent)
+		seats  []int64
+		want   error
+	}{
+		{name: "draft event", modify: func(e *CheckoutEvent) { e.Status = eventDomain.EventStatusDraft }, seats: []int64{1}, want: eventDomain.ErrEventNotPublished},
+		{name: "completed event", modify: func(e *CheckoutEvent) { e.Status = eventDomain.EventStatusCompleted }, seats: []int64{1}, want: eventDomain.ErrEventClosed},
+		{name: "above the event limit", modify: func(e *CheckoutEvent) { e.MaxTicketsPerOrder = 1 }, seats: []int64{1, 2}, want: ErrTooManySeatsHeld},
+		{name: "seat twice", seats: []int64{1, 1}, want: ErrDuplicateSeat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := checkoutEvent(now)
+			if tt.modify != nil {
+				tt.modify(event)
+			}
+			_, err := NewSeatHold(5, event, tt.seats)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+
+	_, err = NewSeatHold(5, checkoutEvent(now), nil)
+	assert.Error(t, err)
+}
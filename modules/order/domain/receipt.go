@@ -0,0 +1,34 @@
+package domain
+
+import "strings"
+
+// ReceiptIssuer is the company receipts and pro-forma invoices are issued by
+type ReceiptIssuer struct {
+	Name    string
+	Address string
+	// TaxID is the VAT or tax number of the company, empty when it has none
+	TaxID string
+}
+
+// Paid tells whether the order was paid, even if it was refunded since, so a receipt can be issued
+func (o *Order) Paid() bool {
+	switch o.Status {
+	case OrderStatusConfirmed, OrderStatusPartiallyRefunded, OrderStatusRefunded:
+		return true
+	}
+	return false
+}
+
+// ReceiptNumber returns the number of the receipt of an order, which the order number identifies
+func ReceiptNumber(orderNumber string) string {
+	return "RC-" + strings.TrimPrefix(orderNumber, "ORD-")
+}
+
+// ReceiptFilename is the name of the PDF of the receipt of an order, or of its pro-forma invoice while
+// the order awaits its transfer
+func ReceiptFilename(order *Order) string {
+	if !order.Paid() && order.Invoice != nil {
+		return order.Invoice.Number + ".pdf"
+	}
+	return ReceiptNumber(order.OrderNumber) + ".pdf"
+}
@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+// RefundStatus is the status of a refund, moved on by the payment integration once requested
+type RefundStatus string
+
+const (
+	RefundStatusPending    RefundStatus = "pending"
+	RefundStatusProcessing RefundStatus = "processing"
+	RefundStatusCompleted  RefundStatus = "completed"
+	RefundStatusFailed     RefundStatus = "failed"
+)
+
+// Refund gives money back for tickets of an order. It is recorded against the completed payment of the
+// order and issued by the payment integration; the tickets are given back as soon as it is recorded.
+type Refund struct {
+	ID        int64
+	OrderID   int64
+	PaymentID int64
+	Tickets   []*OrderTicket
+	Amount    string
+	Currency  string
+	Reason    string
+	Status    RefundStatus
+	// Full tells whether the refund gave back the last tickets of the order
+	Full        bool
+	ProcessedAt *time.Time
+	CreatedAt   time.Time
+}
+
+// TicketIDs returns the IDs of the tickets the refund gives back
+func (r *Refund) TicketIDs() []int64 {
+	ids := make([]int64, len(r.Tickets))
+	for i, ticket := range r.Tickets {
+		ids[i] = ticket.ID
+	}
+	return ids
+}
+
+// SeatedTicketIDs returns the IDs of the seats the refund gives back
+func (r *Refund) SeatedTicketIDs() []int64 {
+	var ids []int64
+	for _, ticket := range r.Tickets {
+		if ticket.Seated {
+			ids = append(ids, ticket.ID)
+		}
+	}
+	return ids
+}
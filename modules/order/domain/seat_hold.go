@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	eventDomain "tixgo/modules/event/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// SeatHold is the seats of an event a buyer selected on its seat map, held for them alone while they
+// check out. A hold lapses at ExpiresAt, returning its seats to sale.
+type SeatHold struct {
+	EventID int64
+	UserID  int64
+	// TicketIDs are the tickets of the seats held
+	TicketIDs []int64
+	ExpiresAt time.Time
+}
+
+// NewSeatHold checks the seats a buyer selects on a published event, at most the tickets of an order
+// and each selected once. Whether they are seats of the event still on sale is checked by the store.
+func NewSeatHold(userID int64, event *CheckoutEvent, ticketIDs []int64) (*SeatHold, error) {
+	switch event.Status {
+	case eventDomain.EventStatusDraft:
+		return nil, eventDomain.ErrEventNotPublished
+	case eventDomain.EventStatusCancelled, eventDomain.EventStatusCompleted:
+		return nil, eventDomain.ErrEventClosed
+	}
+	if len(ticketIDs) == 0 {
+		return nil, syserr.New(syserr.InvalidArgumentCode, "select at least one seat")
+	}
+	if len(ticketIDs) > event.MaxTicketsPerOrder {
+		return nil, ErrTooManySeatsHeld
+	}
+
+	seen := make(map[int64]bool, len(ticketIDs))
+	for _, id := range ticketIDs {
+		if seen[id] {
+			return nil, ErrDuplicateSeat
+		}
+		seen[id] = true
+	}
+
+	return &SeatHold{EventID: event.ID, UserID: userID, TicketIDs: ticketIDs}, nil
+}
+
+// Holds tells whether the hold has every seat of ticketIDs
+func (h *SeatHold) Holds(ticketIDs []int64) bool {
+	held := make(map[int64]bool, len(h.TicketIDs))
+	for _, id := range h.TicketIDs {
+		held[id] = true
+	}
+	for _, id := range ticketIDs {
+		if !held[id] {
+			return false
+		}
+	}
+	return true
+}
+
+// SeatHoldStore holds the seats buyers select, each for one buyer at a time. Holds lapse on their own
+// at their expiry.
+type SeatHoldStore interface {
+	// Hold adds the seats of hold to the ones the buyer holds in the event and holds them all for ttl
+	// from now, setting the seats and expiry of hold. It fails with ErrSeatHeld if another buyer holds
+	// one of them and with ErrTooManySeatsHeld past limit seats.
+	Hold(ctx context.Context, hold *SeatHold, ttl time.Duration, limit int) error
+
+	// Get retrieves the seats the buyer holds in the event, ErrSeatHoldNotFound if none
+	Get(ctx context.Context, eventID, userID int64) (*SeatHold, error)
+
+	// Release releases the seats of ticketIDs the buyer holds in the event, every seat they hold when
+	// empty, and returns the seats released
+	Release(ctx context.Context, eventID, userID int64, ticketIDs []int64) ([]int64, error)
+
+	// HeldByOthers returns the seats of the event held by buyers other than the user
+	HeldByOthers(ctx context.Context, eventID, userID int64) ([]int64, error)
+
+	// Lapsed returns the seats whose hold lapsed since it was last called, by event, unless held again
+	// meanwhile
+	Lapsed(ctx context.Context) (map[int64][]int64, error)
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"tixgo/shared/listing"
+)
+
+// OrderSummary is the denormalized read model of an order shown in the order history of its customer,
+// projected from the orders, their items, events and users so listing it needs no join
+type OrderSummary struct {
+	OrderID       int64
+	UserID        int64
+	OrderNumber   string
+	CustomerName  string
+	CustomerEmail string
+	// EventID and EventTitle are zero for an order without items
+	EventID        int64
+	EventTitle     string
+	EventStartDate *time.Time
+	TicketCount    int
+	TotalAmount    string
+	Currency       string
+	Status         string
+	CreatedAt      time.Time
+	// ProjectedAt is when the summary was last projected from the order
+	ProjectedAt time.Time
+}
+
+// OrderSummaryRepository maintains and reads the order summaries
+type OrderSummaryRepository interface {
+	// Project projects the summaries of the given orders from their current state
+	Project(ctx context.Context, orderIDs []int64) error
+
+	// ProjectEvent projects the summaries of every order of an event
+	ProjectEvent(ctx context.Context, eventID int64) error
+
+	// Rebuild projects the summaries of every order again, batch by batch of batchSize orders,
+	// deleting the summaries left without an order, and returns how many orders were projected
+	Rebuild(ctx context.Context, batchSize int) (int, error)
+
+	// ListByUserID retrieves a page of the summaries of the orders of a user, newest first
+	ListByUserID(ctx context.Context, userID int64, paging *listing.Paging) ([]*OrderSummary, error)
+}
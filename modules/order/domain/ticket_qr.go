@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/url"
+)
+
+// TicketClaims are the claims of the signed payload the QR code of a ticket carries. The order tells
+// apart the sales of a seat sold again after a refund, whose ticket ID stays the same.
+type TicketClaims struct {
+	TicketID int64 `json:"tid"`
+	EventID  int64 `json:"eid"`
+	OrderID  int64 `json:"oid"`
+}
+
+// TicketSealer signs the claims of the QR codes of tickets, see barcode.Keyring
+type TicketSealer interface {
+	Seal(claims any) (string, error)
+}
+
+// IssueQRCodes signs the QR code payload of each ticket the order sells. Refunded tickets get none.
+func (o *Order) IssueQRCodes(sealer TicketSealer) error {
+	for _, ticket := range o.Tickets {
+		if ticket.Refunded {
+			continue
+		}
+		code, err := sealer.Seal(TicketClaims{TicketID: ticket.ID, EventID: o.EventID, OrderID: o.ID})
+		if err != nil {
+			return err
+		}
+		ticket.QRCode = code
+	}
+	return nil
+}
+
+// TicketQRPath is the API path of the PNG of the QR code of a ticket. The code authorizes it, so the
+// image can be embedded in the mails of the buyer.
+func TicketQRPath(ticketID int64, code string) string {
+	return fmt.Sprintf("/v1/tickets/%d/qr?code=%s", ticketID, url.QueryEscape(code))
+}
+
+// TicketQR is the QR code of a ticket a confirmed order sold, with who may see it
+type TicketQR struct {
+	TicketID int64
+	// BuyerID is the user of the order that sold the ticket and OrganizerID the organizer of its event
+	BuyerID     int64
+	OrganizerID int64
+	Code        string
+}
+
+// Authorizes tells whether the QR code may be shown to the user, or to anyone presenting its code
+func (q *TicketQR) Authorizes(userID int64, code string) bool {
+	if code != "" {
+		return subtle.ConstantTimeCompare([]byte(code), []byte(q.Code)) == 1
+	}
+	return userID != 0 && (userID == q.BuyerID || userID == q.OrganizerID)
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"tixgo/shared/barcode"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrder_IssueQRCodes(t *testing.T) {
+	key, err := barcode.NewHMACKey("k1", []byte(strings.Repeat("s", 32)))
+	require.NoError(t, err)
+	keyring, err := barcode.NewKeyring(key)
+	require.NoError(t, err)
+
+	order := &Order{
+		ID:      7,
+		EventID: 3,
+		Status:  OrderStatusConfirmed,
+		Tickets: []*OrderTicket{
+			{ID: 10, Status: TicketStatusSold},
+			{ID: 11, Status: TicketStatusSold},
+			{ID: 12, Status: TicketStatusAvailable, Refunded: true},
+		},
+	}
+	require.NoError(t, order.IssueQRCodes(keyring))
+
+	var claims TicketClaims
+	require.NoError(t, keyring.Open(order.Tickets[0].QRCode, &claims))
+	assert.Equal(t, TicketClaims{TicketID: 10, EventID: 3, OrderID: 7}, claims)
+	assert.NotEqual(t, order.Tickets[0].QRCode, order.Tickets[1].QRCode)
+	assert.Empty(t, order.Tickets[2].QRCode)
+}
+
+func TestOrder_ConfirmSellsTickets(t *testing.T) {
+	order := &Order{
+		Status:  OrderStatusPending,
+		Tickets: []*OrderTicket{{ID: 10, Status: TicketStatusReserved}, {ID: 11, Status: TicketStatusReserved}},
+	}
+	require.NoError(t, order.Confirm(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)))
+
+	for _, ticket := range order.Tickets {
+		assert.Equal(t, TicketStatusSold, ticket.Status)
+	}
+}
+
+func TestTicketQR_Authorizes(t *testing.T) {
+	qr := &TicketQR{TicketID: 10, BuyerID: 5, OrganizerID: 9, Code: "t1.k1.claims.signature"}
+
+	tests := []struct {
+		name   string
+		userID int64
+		code   string
+		want   bool
+	}{
+		{name: "buyer", userID: 5, want: true},
+		{name: "organizer", userID: 9, want: true},
+		{name: "other user", userID: 6, want: false},
+		{name: "anonymous", want: false},
+		{name: "code", code: "t1.k1.claims.signature", want: true},
+		{name: "wrong code", code: "t1.k1.claims.forged", want: false},
+		{name: "wrong code of the buyer", userID: 5, code: "t1.k1.claims.forged", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, qr.Authorizes(tt.userID, tt.code))
+		})
+	}
+}
+
+func TestTicketQRPath(t *testing.T) {
+	path := TicketQRPath(10, "t1.k1.a+b/c.sig")
+
+	parsed, err := url.Parse(path)
+	require.NoError(t, err)
+	assert.Equal(t, "/v1/tickets/10/qr", parsed.Path)
+	assert.Equal(t, "t1.k1.a+b/c.sig", parsed.Query().Get("code"))
+}
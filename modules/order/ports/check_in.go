@@ -0,0 +1,80 @@
+package ports
+
+import (
+	"net/http"
+	"strconv"
+
+	"tixgo/components"
+	"tixgo/modules/order/adapters"
+	"tixgo/modules/order/app/command"
+	"tixgo/modules/order/app/query"
+	"tixgo/modules/order/domain"
+	"tixgo/shared/httpresponse"
+
+	"github.com/duongptryu/gox/context"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CheckInTicket admits the holder of the ticket whose QR code the door staff scanned
+func CheckInTicket(appCtx components.AppContext, verifier domain.TicketVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.CheckInTicketCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		eventID, userID, ok := organizerEventParams(c)
+		if !ok {
+			return
+		}
+		req.EventID = eventID
+		req.OrganizerID = userID
+
+		handler := command.NewCheckInTicketHandler(adapters.NewTicketCheckInPostgresRepository(appCtx.GetDB()), verifier)
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func GetCheckInStats(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventID, userID, ok := organizerEventParams(c)
+		if !ok {
+			return
+		}
+
+		handler := query.NewGetCheckInStatsHandler(adapters.NewTicketCheckInPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), query.GetCheckInStatsQuery{EventID: eventID, OrganizerID: userID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func organizerEventParams(c *gin.Context) (eventID, userID int64, ok bool) {
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(err)
+		return 0, 0, false
+	}
+
+	userID, err = context.GetUserIDFromContextAsInt64(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return 0, 0, false
+	}
+
+	return eventID, userID, true
+}
@@ -0,0 +1,81 @@
+package ports
+
+import (
+	"context"
+
+	"tixgo/components"
+	"tixgo/modules/order/adapters"
+	"tixgo/modules/order/app/command"
+	orderEvent "tixgo/modules/order/app/event"
+	"tixgo/modules/order/domain"
+	organizerAdapters "tixgo/modules/organizer/adapters"
+	organizerQuery "tixgo/modules/organizer/app/query"
+	templateAdapters "tixgo/modules/template/adapters"
+	"tixgo/shared/dedup"
+	sharedOrder "tixgo/shared/events/order"
+	"tixgo/shared/webhook"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/duongptryu/gox/messaging"
+)
+
+const (
+	EventOrdersChanged   = "events.EventOrdersChanged"
+	EventOrderConfirmed  = "events.EventOrderConfirmed"
+	EventWebhookReceived = "events.EventWebhookReceived"
+)
+
+type OrderMessagingHandlers struct {
+	dispatcher messaging.Dispatcher
+	appCtx     components.AppContext
+	sealer     domain.TicketSealer
+	// apiURL is the public scheme and host of the API the confirmation mails link the QR codes to
+	apiURL string
+	// receipts configures the receipts the confirmation mails attach
+	receipts command.ReceiptOptions
+}
+
+func NewOrderMessagingHandlers(dispatcher messaging.Dispatcher, appCtx components.AppContext, sealer domain.TicketSealer, apiURL string, receipts command.ReceiptOptions) *OrderMessagingHandlers {
+	return &OrderMessagingHandlers{
+		dispatcher: dispatcher,
+		appCtx:     appCtx,
+		sealer:     sealer,
+		apiURL:     apiURL,
+		receipts:   receipts,
+	}
+}
+
+func (h *OrderMessagingHandlers) RegisterOrderMessagingHandlers() {
+	eventProcessor := h.dispatcher.GetEventProcessor()
+	eventProcessor.AddHandler(cqrs.NewEventHandler(EventOrdersChanged, h.HandleEventOrdersChanged))
+	eventProcessor.AddHandler(cqrs.NewEventHandler(EventOrderConfirmed, h.HandleEventOrderConfirmed))
+	eventProcessor.AddHandler(cqrs.NewEventHandler(EventWebhookReceived, h.HandleEventWebhookReceived))
+}
+
+func (h *OrderMessagingHandlers) HandleEventOrdersChanged(ctx context.Context, event *sharedOrder.EventOrdersChanged) error {
+	biz := orderEvent.NewProjectOrderSummaries(adapters.NewOrderSummaryPostgresRepository(h.appCtx.GetDB()))
+
+	return biz.Project(ctx, event)
+}
+
+// HandleEventOrderConfirmed mails the buyer of a confirmed order the order-confirmation template, with its
+// receipt attached
+func (h *OrderMessagingHandlers) HandleEventOrderConfirmed(ctx context.Context, event *sharedOrder.EventOrderConfirmed) error {
+	orderRepo := adapters.NewOrderPostgresRepository(h.appCtx.GetDB())
+	templateRepo := templateAdapters.NewCachedTemplateRepository(templateAdapters.NewTemplatePostgresRepository(h.appCtx.GetDB()), h.appCtx.GetCache())
+	themes := organizerQuery.NewResolveThemeHandler(organizerAdapters.NewThemePostgresRepository(h.appCtx.GetDB()))
+	templateRenderer := templateAdapters.NewHTMLTemplateRenderer(h.appCtx.GetAssetService(), themes, templateAdapters.NewVariableDefaultPostgresRepository(h.appCtx.GetDB()))
+	deduplicator := dedup.NewRedisDeduplicator(h.appCtx.GetRedis())
+	confirmations := command.NewSendOrderConfirmationHandler(orderRepo, templateRepo, templateRenderer, deduplicator, h.appCtx.GetReliableEventBus(), h.apiURL, h.receipts)
+	biz := orderEvent.NewSendOrderConfirmation(confirmations)
+
+	return biz.Send(ctx, event)
+}
+
+// HandleEventWebhookReceived reconciles the transfers the bank reports with the orders awaiting them
+func (h *OrderMessagingHandlers) HandleEventWebhookReceived(ctx context.Context, event *webhook.EventWebhookReceived) error {
+	orderRepo := adapters.NewOrderPostgresRepository(h.appCtx.GetDB())
+	biz := orderEvent.NewReconcileBankTransfers(command.NewReceiveTransferHandler(orderRepo, h.sealer, h.appCtx.GetReliableEventBus()))
+
+	return biz.Reconcile(ctx, event)
+}
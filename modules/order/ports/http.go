@@ -0,0 +1,548 @@
+package ports
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"tixgo/components"
+	"tixgo/config"
+	"tixgo/modules/order/adapters"
+	"tixgo/modules/order/app/command"
+	"tixgo/modules/order/app/query"
+	"tixgo/modules/order/domain"
+	organizerAdapters "tixgo/modules/organizer/adapters"
+	organizerQuery "tixgo/modules/organizer/app/query"
+	promotionAdapters "tixgo/modules/promotion/adapters"
+	templateAdapters "tixgo/modules/template/adapters"
+	userDomain "tixgo/modules/user/domain"
+	"tixgo/shared/apiversion"
+	"tixgo/shared/authz"
+	"tixgo/shared/httpresponse"
+	"tixgo/shared/listing"
+	"tixgo/shared/pdf"
+	"tixgo/shared/session"
+
+	"github.com/duongptryu/gox/context"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// DefaultCheckoutHold is how long a checkout holds its tickets when the configuration sets no hold
+	DefaultCheckoutHold = 15 * time.Minute
+	// DefaultTransferHold is how long an order paid by bank transfer holds its tickets when the
+	// configuration sets no hold
+	DefaultTransferHold = 7 * 24 * time.Hour
+	// DefaultSeatHold is how long a buyer holds the seats they select when the configuration sets no hold
+	DefaultSeatHold = 10 * time.Minute
+	// DefaultReceiptIssuer is the company receipts are issued by when the configuration names none
+	DefaultReceiptIssuer = "TixGo"
+)
+
+// bankAccount is the account of cfg bank transfers are paid into
+func bankAccount(cfg config.BankTransfer) domain.BankAccount {
+	return domain.BankAccount{
+		Beneficiary: cfg.Beneficiary,
+		BankName:    cfg.BankName,
+		IBAN:        cfg.IBAN,
+		BIC:         cfg.BIC,
+	}
+}
+
+// NewReceiptOptions configures the receipts and pro-forma invoices of orders after cfg
+func NewReceiptOptions(cfg config.Orders) command.ReceiptOptions {
+	issuer := domain.ReceiptIssuer{
+		Name:    cfg.Receipt.Issuer,
+		Address: cfg.Receipt.Address,
+		TaxID:   cfg.Receipt.TaxID,
+	}
+	if issuer.Name == "" {
+		issuer.Name = DefaultReceiptIssuer
+	}
+	return command.ReceiptOptions{Issuer: issuer, Account: bankAccount(cfg.BankTransfer)}
+}
+
+func RegisterOrderRoutes(router *apiversion.Group, appCtx components.AppContext, cfg config.Orders, ticketKeys domain.TicketKeys) {
+	hold := cfg.CheckoutHold
+	if hold == 0 {
+		hold = DefaultCheckoutHold
+	}
+
+	transfer := command.BankTransferOptions{
+		Hold:    cfg.BankTransfer.Hold,
+		Account: bankAccount(cfg.BankTransfer),
+	}
+	if transfer.Hold == 0 {
+		transfer.Hold = DefaultTransferHold
+	}
+
+	seatHold := cfg.SeatHold
+	if seatHold == 0 {
+		seatHold = DefaultSeatHold
+	}
+
+	orderGroup := router.Group("/users/me/orders")
+	{
+		orderGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		orderGroup.GET("", ListMyOrders(appCtx))
+	}
+
+	checkoutGroup := router.Group("/orders")
+	{
+		checkoutGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		checkoutGroup.POST("", Checkout(appCtx, hold, transfer))
+		checkoutGroup.GET("/:id", GetOrder(appCtx))
+		checkoutGroup.GET("/:id/invoice", GetOrderInvoice(appCtx, NewReceiptOptions(cfg)))
+		checkoutGroup.GET("/:id/refunds", ListMyOrderRefunds(appCtx))
+	}
+
+	seatHoldGroup := router.Group("/orders/seat-holds/:event_id")
+	{
+		seatHoldGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		seatHoldGroup.GET("", GetSeatHold(appCtx))
+		seatHoldGroup.POST("", HoldSeats(appCtx, seatHold))
+		seatHoldGroup.DELETE("", ReleaseSeats(appCtx))
+		seatHoldGroup.DELETE("/seats/:ticket_id", ReleaseSeats(appCtx))
+	}
+
+	// the code of the QR authorizes it without a session, so mails can embed the image
+	ticketGroup := router.Group("/tickets")
+	{
+		ticketGroup.GET("/:id/qr", requireAuthWithoutCode(appCtx), GetTicketQR(appCtx))
+	}
+
+	checkInGroup := router.Group("/events/:id/check-in")
+	{
+		checkInGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		checkInGroup.Use(authz.RequireUserType(string(userDomain.UserTypeOrganizer)))
+		checkInGroup.POST("", CheckInTicket(appCtx, ticketKeys))
+		checkInGroup.GET("/stats", GetCheckInStats(appCtx))
+	}
+
+	adminGroup := router.Group("/admin/orders")
+	{
+		adminGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		adminGroup.Use(authz.RequireUserType(string(userDomain.UserTypeAdmin)))
+		adminGroup.POST("/:id/confirm", ConfirmOrder(appCtx, ticketKeys))
+		adminGroup.POST("/:id/transfers", ReceiveTransfer(appCtx, ticketKeys))
+		adminGroup.POST("/:id/refunds", RefundOrder(appCtx))
+		adminGroup.GET("/:id/refunds", ListOrderRefunds(appCtx))
+	}
+}
+
+func ListMyOrders(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		var paging listing.Paging
+		if err := c.ShouldBind(&paging); err != nil {
+			c.Error(err)
+			return
+		}
+
+		// Apply pagination defaults in HTTP layer
+		paging.Fulfill()
+
+		filters := query.ListMyOrdersQuery{UserID: userID}
+		handler := query.NewListMyOrdersHandler(adapters.NewOrderSummaryPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), &filters, &paging)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.List(c, result, paging, filters)
+	}
+}
+
+// Checkout reserves the tickets asked for and creates the pending order holding them for hold, or the
+// order awaiting a bank transfer holding them for the hold of transfer
+func Checkout(appCtx components.AppContext, hold time.Duration, transfer command.BankTransferOptions) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.CheckoutCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		userID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.UserID = userID
+
+		orderRepo := adapters.NewOrderPostgresRepository(appCtx.GetDB())
+		promoRepo := promotionAdapters.NewPromoCodePostgresRepository(appCtx.GetDB())
+		templateRepo := templateAdapters.NewCachedTemplateRepository(templateAdapters.NewTemplatePostgresRepository(appCtx.GetDB()), appCtx.GetCache())
+		themes := organizerQuery.NewResolveThemeHandler(organizerAdapters.NewThemePostgresRepository(appCtx.GetDB()))
+		templateRenderer := templateAdapters.NewHTMLTemplateRenderer(appCtx.GetAssetService(), themes, templateAdapters.NewVariableDefaultPostgresRepository(appCtx.GetDB()))
+		seatHolds := adapters.NewSeatHoldRedis(appCtx.GetRedis())
+		handler := command.NewCheckoutHandler(orderRepo, promoRepo, seatHolds, templateRepo, templateRenderer, hold, transfer, appCtx.GetReliableEventBus())
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusCreated, result)
+	}
+}
+
+func GetOrder(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		orderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		userID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		handler := query.NewGetOrderHandler(adapters.NewOrderPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), query.GetOrderQuery{ID: orderID, UserID: userID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+// GetOrderInvoice gets the pro-forma invoice of an order of the current user paid by bank transfer.
+// Asked for a PDF with the Accept header or ?format=pdf, it gets the receipt of any paid order instead,
+// or the pro-forma invoice while the order awaits its transfer.
+func GetOrderInvoice(appCtx components.AppContext, receipts command.ReceiptOptions) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		orderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		userID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		orderRepo := adapters.NewOrderPostgresRepository(appCtx.GetDB())
+
+		if c.Query("format") == "pdf" || c.NegotiateFormat(gin.MIMEJSON, pdf.ContentType) == pdf.ContentType {
+			handler := query.NewGetOrderReceiptHandler(orderRepo, receipts)
+
+			receipt, err := handler.Handle(c.Request.Context(), query.GetOrderReceiptQuery{OrderID: orderID, UserID: userID})
+			if err != nil {
+				c.Error(err)
+				return
+			}
+
+			// refunds change the receipt, it must not outlive them in a cache
+			c.Header("Cache-Control", "no-store")
+			c.Header("Content-Disposition", `inline; filename="`+receipt.Filename+`"`)
+			c.Data(http.StatusOK, pdf.ContentType, receipt.Content)
+			return
+		}
+
+		handler := query.NewGetOrderInvoiceHandler(orderRepo, receipts.Account)
+
+		result, err := handler.Handle(c.Request.Context(), query.GetOrderInvoiceQuery{OrderID: orderID, UserID: userID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+// ConfirmOrder confirms a pending order paid outside the payment integration, e.g. in cash. Orders
+// awaiting a bank transfer are confirmed by recording the transfer.
+func ConfirmOrder(appCtx components.AppContext, sealer domain.TicketSealer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		orderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		orderRepo := adapters.NewOrderPostgresRepository(appCtx.GetDB())
+		handler := command.NewConfirmOrderHandler(orderRepo, sealer, appCtx.GetReliableEventBus())
+
+		result, err := handler.Handle(c.Request.Context(), command.ConfirmOrderCommand{ID: orderID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+// ReceiveTransfer records the bank transfer paying an order awaiting it, confirming the order
+func ReceiveTransfer(appCtx components.AppContext, sealer domain.TicketSealer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		orderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		var req command.ReceiveTransferCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+		req.OrderID = orderID
+
+		orderRepo := adapters.NewOrderPostgresRepository(appCtx.GetDB())
+		handler := command.NewReceiveTransferHandler(orderRepo, sealer, appCtx.GetReliableEventBus())
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+// RefundOrder refunds tickets of a confirmed order, all of them when no ticket is listed
+func RefundOrder(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		orderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		var req command.RefundOrderCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+		req.OrderID = orderID
+
+		orderRepo := adapters.NewOrderPostgresRepository(appCtx.GetDB())
+		templateRepo := templateAdapters.NewCachedTemplateRepository(templateAdapters.NewTemplatePostgresRepository(appCtx.GetDB()), appCtx.GetCache())
+		themes := organizerQuery.NewResolveThemeHandler(organizerAdapters.NewThemePostgresRepository(appCtx.GetDB()))
+		templateRenderer := templateAdapters.NewHTMLTemplateRenderer(appCtx.GetAssetService(), themes, templateAdapters.NewVariableDefaultPostgresRepository(appCtx.GetDB()))
+		handler := command.NewRefundOrderHandler(orderRepo, templateRepo, templateRenderer, appCtx.GetReliableEventBus())
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusCreated, result)
+	}
+}
+
+// ListOrderRefunds lists the refunds of any order with their status
+func ListOrderRefunds(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		orderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		handler := query.NewListOrderRefundsHandler(adapters.NewOrderPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), query.ListOrderRefundsQuery{OrderID: orderID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+// ListMyOrderRefunds lists the refunds of an order of the current user with their status
+func ListMyOrderRefunds(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		orderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		userID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		handler := query.NewListOrderRefundsHandler(adapters.NewOrderPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), query.ListOrderRefundsQuery{OrderID: orderID, UserID: userID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+// HoldSeats holds the seats the current user selects on the seat map of an event for hold
+func HoldSeats(appCtx components.AppContext, hold time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.HoldSeatsCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		eventID, userID, ok := seatHoldParams(c)
+		if !ok {
+			return
+		}
+		req.EventID = eventID
+		req.UserID = userID
+
+		orderRepo := adapters.NewOrderPostgresRepository(appCtx.GetDB())
+		handler := command.NewHoldSeatsHandler(orderRepo, adapters.NewSeatHoldRedis(appCtx.GetRedis()), hold, appCtx.GetReliableEventBus())
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func GetSeatHold(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventID, userID, ok := seatHoldParams(c)
+		if !ok {
+			return
+		}
+
+		handler := query.NewGetSeatHoldHandler(adapters.NewSeatHoldRedis(appCtx.GetRedis()))
+
+		result, err := handler.Handle(c.Request.Context(), query.GetSeatHoldQuery{EventID: eventID, UserID: userID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+// ReleaseSeats releases a seat the current user holds in an event, every seat they hold without a
+// ticket_id
+func ReleaseSeats(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventID, userID, ok := seatHoldParams(c)
+		if !ok {
+			return
+		}
+		req := command.ReleaseSeatsCommand{EventID: eventID, UserID: userID}
+
+		if param := c.Param("ticket_id"); param != "" {
+			ticketID, err := strconv.ParseInt(param, 10, 64)
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			req.SeatIDs = []int64{ticketID}
+		}
+
+		handler := command.NewReleaseSeatsHandler(adapters.NewSeatHoldRedis(appCtx.GetRedis()), appCtx.GetReliableEventBus())
+
+		if err := handler.Handle(c.Request.Context(), req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, true)
+	}
+}
+
+// seatHoldParams parses the event of a seat hold route and gets the current user, reporting the error
+// when it fails
+func seatHoldParams(c *gin.Context) (eventID, userID int64, ok bool) {
+	eventID, err := strconv.ParseInt(c.Param("event_id"), 10, 64)
+	if err != nil {
+		c.Error(err)
+		return 0, 0, false
+	}
+
+	userID, err = context.GetUserIDFromContextAsInt64(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return 0, 0, false
+	}
+
+	return eventID, userID, true
+}
+
+// requireAuthWithoutCode requires a session of the requests that do not present the code of the QR
+func requireAuthWithoutCode(appCtx components.AppContext) gin.HandlerFunc {
+	requireAuth := session.RequireAuth(appCtx.GetSessionService())
+	return func(c *gin.Context) {
+		if c.Query("code") != "" {
+			c.Next()
+			return
+		}
+		requireAuth(c)
+	}
+}
+
+// GetTicketQR serves the PNG of the QR code of a ticket to its buyer, the organizer of its event, or
+// anyone presenting its code
+func GetTicketQR(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ticketID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		var req query.GetTicketQRQuery
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.Error(err)
+			return
+		}
+		req.TicketID = ticketID
+		if req.Code == "" {
+			req.UserID, err = context.GetUserIDFromContextAsInt64(c.Request.Context())
+			if err != nil {
+				c.Error(err)
+				return
+			}
+		}
+
+		handler := query.NewGetTicketQRHandler(adapters.NewOrderPostgresRepository(appCtx.GetDB()))
+
+		png, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		// a refund voids the QR code, it must not outlive it in a cache
+		c.Header("Cache-Control", "no-store")
+		c.Data(http.StatusOK, "image/png", png)
+	}
+}
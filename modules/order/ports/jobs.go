@@ -0,0 +1,54 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"tixgo/components"
+	"tixgo/modules/order/adapters"
+	"tixgo/modules/order/app/command"
+	"tixgo/shared/scheduler"
+)
+
+const (
+	// JobRebuildSummaries rebuilds the order summaries, it may also be triggered by hand after a backfill
+	JobRebuildSummaries = "order.rebuild_summaries"
+	// JobExpireOrders cancels the checkout orders left unpaid past their expiry, releasing their tickets
+	JobExpireOrders = "order.expire_orders"
+	// JobExpireSeatHolds announces the seats whose hold lapsed to the seat maps
+	JobExpireSeatHolds = "order.expire_seat_holds"
+)
+
+// Jobs returns the jobs of the order module
+func Jobs(appCtx components.AppContext) []scheduler.Job {
+	return []scheduler.Job{
+		{
+			Name:     JobRebuildSummaries,
+			Schedule: "@daily",
+			Timeout:  time.Hour,
+			Run: func(ctx context.Context) error {
+				summaryRepo := adapters.NewOrderSummaryPostgresRepository(appCtx.GetDB())
+				return command.NewRebuildOrderSummariesHandler(summaryRepo).Handle(ctx)
+			},
+		},
+		{
+			Name:     JobExpireOrders,
+			Schedule: "@every 1m",
+			Timeout:  5 * time.Minute,
+			Run: func(ctx context.Context) error {
+				orderRepo := adapters.NewOrderPostgresRepository(appCtx.GetDB())
+				return command.NewExpireOrdersHandler(orderRepo, appCtx.GetReliableEventBus()).Handle(ctx)
+			},
+		},
+		{
+			Name:     JobExpireSeatHolds,
+			Schedule: "@every 1m",
+			Timeout:  5 * time.Minute,
+			Run: func(ctx context.Context) error {
+				orderRepo := adapters.NewOrderPostgresRepository(appCtx.GetDB())
+				seatHolds := adapters.NewSeatHoldRedis(appCtx.GetRedis())
+				return command.NewExpireSeatHoldsHandler(orderRepo, seatHolds, appCtx.GetReliableEventBus()).Handle(ctx)
+			},
+		},
+	}
+}
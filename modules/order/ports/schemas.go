@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"tixgo/modules/order/app/command"
+	"tixgo/shared/jsonschema"
+	"tixgo/shared/listing"
+)
+
+// Schemas returns the request payloads of the order module
+func Schemas() []jsonschema.Payload {
+	return []jsonschema.Payload{
+		{Name: "users.me.orders", In: jsonschema.Query, Example: listing.Paging{}},
+		{Name: "orders.checkout", In: jsonschema.Body, Example: command.CheckoutCommand{}},
+		{Name: "orders.seat-holds", In: jsonschema.Body, Example: command.HoldSeatsCommand{}},
+		{Name: "events.check-in", In: jsonschema.Body, Example: command.CheckInTicketCommand{}},
+		{Name: "admin.orders.refund", In: jsonschema.Body, Example: command.RefundOrderCommand{}},
+		{Name: "admin.orders.transfer", In: jsonschema.Body, Example: command.ReceiveTransferCommand{}},
+	}
+}
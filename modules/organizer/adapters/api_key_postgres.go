@@ -0,0 +1,178 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+
+	"tixgo/modules/organizer/domain"
+	"tixgo/shared/dbtimeout"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+// APIKeyPostgresRepository implements the APIKeyRepository interface using PostgreSQL
+type APIKeyPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewAPIKeyPostgresRepository creates a new PostgreSQL API key repository
+func NewAPIKeyPostgresRepository(db *sqlx.DB) *APIKeyPostgresRepository {
+	return &APIKeyPostgresRepository{db: db}
+}
+
+const selectAPIKey = `
+	SELECT k.id, k.organizer_id, k.name, k.key_prefix, k.key_hash, k.plan, k.monthly_quota, k.test_mode,
+		k.created_at, k.last_used_at, k.revoked_at
+	FROM api_keys k`
+
+// Create stores a key unless the organizer has domain.MaxAPIKeys active keys already
+func (r *APIKeyPostgresRepository) Create(ctx context.Context, key *domain.APIKey) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		INSERT INTO api_keys (organizer_id, name, key_prefix, key_hash, plan, test_mode)
+		SELECT $1, $2, $3, $4, $5, $7
+		WHERE (SELECT COUNT(*) FROM api_keys WHERE organizer_id = $1 AND revoked_at IS NULL) < $6
+		RETURNING id, created_at`
+
+	err := r.db.QueryRowContext(ctx, query, key.OrganizerID, key.Name, key.Prefix, key.Hash, key.Plan, domain.MaxAPIKeys, key.TestMode).
+		Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrTooManyAPIKeys
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create API key")
+	}
+
+	return nil
+}
+
+// ListByOrganizerID retrieves the keys of an organizer, revoked ones included, oldest first
+func (r *APIKeyPostgresRepository) ListByOrganizerID(ctx context.Context, organizerID int64) ([]*domain.APIKey, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, selectAPIKey+`
+		WHERE k.organizer_id = $1
+		ORDER BY k.created_at, k.id`, organizerID)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list API keys")
+	}
+	defer rows.Close()
+
+	keys := []*domain.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan API key")
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "error iterating API key rows")
+	}
+
+	return keys, nil
+}
+
+// GetByID retrieves a key
+func (r *APIKeyPostgresRepository) GetByID(ctx context.Context, id int64) (*domain.APIKey, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, selectAPIKey+` WHERE k.id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrAPIKeyNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get API key")
+	}
+
+	return key, nil
+}
+
+// GetByHash retrieves an active key by the hash of the key
+func (r *APIKeyPostgresRepository) GetByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, selectAPIKey+`
+		WHERE k.key_hash = $1 AND k.revoked_at IS NULL`, hash))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrInvalidAPIKey
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get API key")
+	}
+
+	return key, nil
+}
+
+// Revoke revokes an active key of an organizer
+func (r *APIKeyPostgresRepository) Revoke(ctx context.Context, organizerID, id int64) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE api_keys SET revoked_at = NOW()
+		WHERE id = $1 AND organizer_id = $2 AND revoked_at IS NULL`, id, organizerID)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to revoke API key")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// SavePlan saves the plan and quota override of a key
+func (r *APIKeyPostgresRepository) SavePlan(ctx context.Context, key *domain.APIKey) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `UPDATE api_keys SET plan = $2, monthly_quota = $3 WHERE id = $1`,
+		key.ID, key.Plan, key.QuotaOverride)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to save API key plan")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+func scanAPIKey(row rowScanner) (*domain.APIKey, error) {
+	key := &domain.APIKey{}
+	err := row.Scan(
+		&key.ID,
+		&key.OrganizerID,
+		&key.Name,
+		&key.Prefix,
+		&key.Hash,
+		&key.Plan,
+		&key.QuotaOverride,
+		&key.TestMode,
+		&key.CreatedAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
@@ -0,0 +1,131 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+
+	"tixgo/modules/organizer/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/listing"
+	"tixgo/shared/pgquery"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+// APIUsagePostgresRepository implements the APIUsageRepository interface using PostgreSQL
+type APIUsagePostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewAPIUsagePostgresRepository creates a new PostgreSQL API usage repository
+func NewAPIUsagePostgresRepository(db *sqlx.DB) *APIUsagePostgresRepository {
+	return &APIUsagePostgresRepository{db: db}
+}
+
+// Save records the counts in one transaction. A count lower than the one saved is the counter having
+// lost keys, the saved one is kept.
+func (r *APIUsagePostgresRepository) Save(ctx context.Context, counts []domain.APIUsageCount) error {
+	if len(counts) == 0 {
+		return nil
+	}
+
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	query := `
+		WITH saved AS (
+			INSERT INTO api_key_usage (api_key_id, period, requests)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (api_key_id, period) DO UPDATE
+			SET requests = EXCLUDED.requests, updated_at = NOW()
+			WHERE api_key_usage.requests < EXCLUDED.requests
+			RETURNING api_key_id
+		)
+		UPDATE api_keys SET last_used_at = NOW()
+		WHERE id IN (SELECT api_key_id FROM saved)`
+
+	for _, count := range counts {
+		if _, err := tx.ExecContext(ctx, query, count.APIKeyID, count.Period, count.Requests); err != nil {
+			return syserr.Wrap(err, syserr.InternalCode, "failed to save API usage")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// List retrieves the usage in the period of the keys active during it, most used first
+func (r *APIUsagePostgresRepository) List(ctx context.Context, filters domain.ListAPIUsageFilters, paging *listing.Paging) ([]*domain.APIKeyUsage, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	// the period is the first argument, the join on the usage refers to it as $1
+	filter := &pgquery.Filter{}
+	filter.Where("(k.revoked_at IS NULL OR k.revoked_at >= ?)", filters.Period)
+	filter.Where("k.created_at < ?", filters.Period.AddDate(0, 1, 0))
+
+	if filters.OrganizerID != nil {
+		filter.Where("k.organizer_id = ?", *filters.OrganizerID)
+	}
+
+	// Set total in paging
+	err := pgquery.Count(ctx, r.db, "api_keys k", filter, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to count API keys")
+	}
+
+	pageClause, args := filter.Paged(paging)
+	query := fmt.Sprintf(`
+		SELECT k.id, k.organizer_id, k.name, k.key_prefix, k.key_hash, k.plan, k.monthly_quota, k.test_mode,
+			k.created_at, k.last_used_at, k.revoked_at, COALESCE(u.requests, 0)
+		FROM api_keys k
+		LEFT JOIN api_key_usage u ON u.api_key_id = k.id AND u.period = $1::date
+		%s
+		ORDER BY COALESCE(u.requests, 0) DESC, k.id
+		%s`, filter.Clause(), pageClause)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list API usage")
+	}
+	defer rows.Close()
+
+	var usages []*domain.APIKeyUsage
+	for rows.Next() {
+		usage := &domain.APIKeyUsage{Key: &domain.APIKey{}, Period: filters.Period}
+		err := rows.Scan(
+			&usage.Key.ID,
+			&usage.Key.OrganizerID,
+			&usage.Key.Name,
+			&usage.Key.Prefix,
+			&usage.Key.Hash,
+			&usage.Key.Plan,
+			&usage.Key.QuotaOverride,
+			&usage.Key.TestMode,
+			&usage.Key.CreatedAt,
+			&usage.Key.LastUsedAt,
+			&usage.Key.RevokedAt,
+			&usage.Requests,
+		)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan API usage")
+		}
+		usages = append(usages, usage)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "error iterating API usage rows")
+	}
+
+	return usages[:paging.Fetched(len(usages))], nil
+}
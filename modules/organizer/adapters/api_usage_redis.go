@@ -0,0 +1,101 @@
+package adapters
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"tixgo/modules/organizer/domain"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	apiUsageKeyPrefix = "apiusage:"
+
+	// apiUsageTTL keeps the counts of a period until the period after it was saved for good
+	apiUsageTTL = 62 * 24 * time.Hour
+)
+
+// RedisAPIUsageCounter implements the APIUsageCounter interface with a redis hash per period, holding
+// the count of every key that made requests in it
+type RedisAPIUsageCounter struct {
+	client redis.UniversalClient
+}
+
+// NewRedisAPIUsageCounter creates an API usage counter backed by redis
+func NewRedisAPIUsageCounter(client redis.UniversalClient) *RedisAPIUsageCounter {
+	return &RedisAPIUsageCounter{client: client}
+}
+
+func apiUsageKey(period time.Time) string {
+	return apiUsageKeyPrefix + period.Format(domain.APIUsagePeriodLayout)
+}
+
+// Increment counts a request of the key in the period and returns the requests made so far
+func (c *RedisAPIUsageCounter) Increment(ctx context.Context, keyID int64, period time.Time) (int64, error) {
+	key := apiUsageKey(period)
+
+	pipe := c.client.TxPipeline()
+	incr := pipe.HIncrBy(ctx, key, strconv.FormatInt(keyID, 10), 1)
+	pipe.Expire(ctx, key, apiUsageTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, syserr.Wrap(err, syserr.InternalCode, "failed to count API request")
+	}
+
+	return incr.Val(), nil
+}
+
+// Get returns the requests counted in the period for the keys
+func (c *RedisAPIUsageCounter) Get(ctx context.Context, keyIDs []int64, period time.Time) (map[int64]int64, error) {
+	counts := make(map[int64]int64, len(keyIDs))
+	if len(keyIDs) == 0 {
+		return counts, nil
+	}
+
+	fields := make([]string, len(keyIDs))
+	for i, id := range keyIDs {
+		fields[i] = strconv.FormatInt(id, 10)
+	}
+
+	values, err := c.client.HMGet(ctx, apiUsageKey(period), fields...).Result()
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get API usage")
+	}
+
+	for i, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue
+		}
+		if count, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			counts[keyIDs[i]] = count
+		}
+	}
+
+	return counts, nil
+}
+
+// List returns every count of the period
+func (c *RedisAPIUsageCounter) List(ctx context.Context, period time.Time) ([]domain.APIUsageCount, error) {
+	values, err := c.client.HGetAll(ctx, apiUsageKey(period)).Result()
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list API usage")
+	}
+
+	counts := make([]domain.APIUsageCount, 0, len(values))
+	for field, raw := range values {
+		keyID, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			continue
+		}
+		requests, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			continue
+		}
+		counts = append(counts, domain.APIUsageCount{APIKeyID: keyID, Period: period, Requests: requests})
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,51 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"tixgo/modules/organizer/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// NetDNSResolver implements the DNSResolver interface with the system resolver
+type NetDNSResolver struct {
+	resolver *net.Resolver
+}
+
+// NewNetDNSResolver creates a new DNS resolver
+func NewNetDNSResolver() *NetDNSResolver {
+	return &NetDNSResolver{resolver: net.DefaultResolver}
+}
+
+// Lookup returns the values of the records of recordType at host, none if the host does not exist
+func (r *NetDNSResolver) Lookup(ctx context.Context, recordType, host string) ([]string, error) {
+	var values []string
+	var err error
+
+	switch recordType {
+	case domain.DNSRecordTypeTXT:
+		values, err = r.resolver.LookupTXT(ctx, host)
+	case domain.DNSRecordTypeCNAME:
+		var target string
+		target, err = r.resolver.LookupCNAME(ctx, host)
+		// hosts without a CNAME resolve to themselves
+		if err == nil && domain.NormalizeDomain(target) != domain.NormalizeDomain(host) {
+			values = []string{target}
+		}
+	default:
+		return nil, syserr.New(syserr.InternalCode, "unsupported DNS record type "+recordType)
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to look up "+recordType+" records of "+host)
+	}
+
+	return values, nil
+}
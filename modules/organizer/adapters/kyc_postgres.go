@@ -0,0 +1,338 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"tixgo/modules/organizer/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/listing"
+	"tixgo/shared/pgerr"
+	"tixgo/shared/pgquery"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// KYCPostgresRepository implements the KYCRepository interface using PostgreSQL
+type KYCPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewKYCPostgresRepository creates a new PostgreSQL KYC repository
+func NewKYCPostgresRepository(db *sqlx.DB) *KYCPostgresRepository {
+	return &KYCPostgresRepository{db: db}
+}
+
+const (
+	selectKYCSubmission = `
+	SELECT s.id, s.organizer_id, s.legal_name, s.registration_number, COALESCE(s.tax_id, ''), s.address,
+	       s.country, s.status, s.reviewed_by, COALESCE(s.rejection_reason, ''), s.submitted_at, s.reviewed_at
+	FROM kyc_submissions s`
+
+	selectKYCDocument = `
+	SELECT d.id, d.organizer_id, d.submission_id, d.type, d.file_name, d.content_type, d.size, d.storage_key, d.created_at
+	FROM kyc_documents d`
+)
+
+// SaveDocument stores an uploaded document, not attached to a submission yet
+func (r *KYCPostgresRepository) SaveDocument(ctx context.Context, document *domain.KYCDocument) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		INSERT INTO kyc_documents (organizer_id, type, file_name, content_type, size, storage_key)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		document.OrganizerID,
+		document.Type,
+		document.FileName,
+		document.ContentType,
+		document.Size,
+		document.StorageKey,
+	).Scan(&document.ID, &document.CreatedAt)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to save KYC document")
+	}
+
+	return nil
+}
+
+// GetUnattachedDocuments retrieves the documents of an organizer not part of a submission yet
+func (r *KYCPostgresRepository) GetUnattachedDocuments(ctx context.Context, organizerID int64, ids []int64) ([]*domain.KYCDocument, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	documents, err := r.queryDocuments(ctx, selectKYCDocument+`
+		WHERE d.organizer_id = $1 AND d.id = ANY($2) AND d.submission_id IS NULL
+		ORDER BY d.id`, organizerID, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(documents) != len(uniqueIDs(ids)) {
+		return nil, domain.ErrKYCDocumentNotFound
+	}
+
+	return documents, nil
+}
+
+// Submit stores a submission pending review and attaches its documents
+func (r *KYCPostgresRepository) Submit(ctx context.Context, submission *domain.KYCSubmission) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO kyc_submissions (organizer_id, legal_name, registration_number, tax_id, address, country, status, submitted_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8)
+		RETURNING id`
+
+	err = tx.QueryRowContext(ctx, query,
+		submission.OrganizerID,
+		submission.LegalName,
+		submission.RegistrationNumber,
+		submission.TaxID,
+		submission.Address,
+		submission.Country,
+		submission.Status,
+		submission.SubmittedAt,
+	).Scan(&submission.ID)
+	if err != nil {
+		// an organizer has one open submission at most, enforced by a partial unique index
+		if pgerr.IsUniqueViolation(err) {
+			return domain.ErrKYCAlreadySubmitted
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create KYC submission")
+	}
+
+	ids := make([]int64, len(submission.Documents))
+	for i, document := range submission.Documents {
+		ids[i] = document.ID
+	}
+
+	// documents attached meanwhile to a concurrent submission are not taken over
+	result, err := tx.ExecContext(ctx, `
+		UPDATE kyc_documents
+		SET submission_id = $3
+		WHERE organizer_id = $1 AND id = ANY($2) AND submission_id IS NULL`, submission.OrganizerID, pq.Array(ids), submission.ID)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to attach KYC documents")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get rows affected")
+	}
+	if rowsAffected != int64(len(ids)) {
+		return domain.ErrKYCDocumentNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to commit transaction")
+	}
+
+	for _, document := range submission.Documents {
+		document.SubmissionID = &submission.ID
+	}
+
+	return nil
+}
+
+// GetByID retrieves a submission with its documents
+func (r *KYCPostgresRepository) GetByID(ctx context.Context, id int64) (*domain.KYCSubmission, error) {
+	return r.getSubmission(ctx, selectKYCSubmission+` WHERE s.id = $1`, id)
+}
+
+// GetLatestByOrganizerID retrieves the most recent submission of an organizer with its documents
+func (r *KYCPostgresRepository) GetLatestByOrganizerID(ctx context.Context, organizerID int64) (*domain.KYCSubmission, error) {
+	return r.getSubmission(ctx, selectKYCSubmission+`
+		WHERE s.organizer_id = $1
+		ORDER BY s.submitted_at DESC, s.id DESC
+		LIMIT 1`, organizerID)
+}
+
+// List retrieves submissions without their documents with pagination and filters, oldest first
+func (r *KYCPostgresRepository) List(ctx context.Context, filters domain.ListKYCFilters, paging *listing.Paging) ([]*domain.KYCSubmission, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	filter := &pgquery.Filter{}
+
+	if filters.OrganizerID != nil {
+		filter.Where("s.organizer_id = ?", *filters.OrganizerID)
+	}
+
+	if filters.Status != nil {
+		filter.Where("s.status = ?", *filters.Status)
+	}
+
+	// Set total in paging
+	err := pgquery.Count(ctx, r.db, "kyc_submissions s", filter, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to count KYC submissions")
+	}
+
+	pageClause, args := filter.Paged(paging)
+	query := fmt.Sprintf(`%s
+		%s
+		ORDER BY s.submitted_at, s.id
+		%s`, selectKYCSubmission, filter.Clause(), pageClause)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list KYC submissions")
+	}
+	defer rows.Close()
+
+	var submissions []*domain.KYCSubmission
+	for rows.Next() {
+		submission, err := scanKYCSubmission(rows)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan KYC submission")
+		}
+		submissions = append(submissions, submission)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "error iterating KYC submission rows")
+	}
+
+	return submissions[:paging.Fetched(len(submissions))], nil
+}
+
+// SaveReview records the review of a submission still pending, ErrKYCNotPending otherwise
+func (r *KYCPostgresRepository) SaveReview(ctx context.Context, submission *domain.KYCSubmission) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		UPDATE kyc_submissions
+		SET status = $2, reviewed_by = $3, rejection_reason = NULLIF($4, ''), reviewed_at = $5
+		WHERE id = $1 AND status = 'pending'`
+
+	result, err := r.db.ExecContext(ctx, query,
+		submission.ID,
+		submission.Status,
+		submission.ReviewedBy,
+		submission.RejectionReason,
+		submission.ReviewedAt,
+	)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to save KYC review")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return domain.ErrKYCNotPending
+	}
+
+	return nil
+}
+
+func (r *KYCPostgresRepository) getSubmission(ctx context.Context, query string, args ...interface{}) (*domain.KYCSubmission, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	submission, err := scanKYCSubmission(r.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrKYCNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get KYC submission")
+	}
+
+	submission.Documents, err = r.queryDocuments(ctx, selectKYCDocument+`
+		WHERE d.submission_id = $1
+		ORDER BY d.id`, submission.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return submission, nil
+}
+
+func (r *KYCPostgresRepository) queryDocuments(ctx context.Context, query string, args ...interface{}) ([]*domain.KYCDocument, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get KYC documents")
+	}
+	defer rows.Close()
+
+	var documents []*domain.KYCDocument
+	for rows.Next() {
+		document := &domain.KYCDocument{}
+		err := rows.Scan(
+			&document.ID,
+			&document.OrganizerID,
+			&document.SubmissionID,
+			&document.Type,
+			&document.FileName,
+			&document.ContentType,
+			&document.Size,
+			&document.StorageKey,
+			&document.CreatedAt,
+		)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan KYC document")
+		}
+		documents = append(documents, document)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "error iterating KYC document rows")
+	}
+
+	return documents, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanKYCSubmission(row rowScanner) (*domain.KYCSubmission, error) {
+	submission := &domain.KYCSubmission{}
+	err := row.Scan(
+		&submission.ID,
+		&submission.OrganizerID,
+		&submission.LegalName,
+		&submission.RegistrationNumber,
+		&submission.TaxID,
+		&submission.Address,
+		&submission.Country,
+		&submission.Status,
+		&submission.ReviewedBy,
+		&submission.RejectionReason,
+		&submission.SubmittedAt,
+		&submission.ReviewedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return submission, nil
+}
+
+// uniqueIDs drops the repeated IDs, which the database matches once
+func uniqueIDs(ids []int64) []int64 {
+	seen := make(map[int64]bool, len(ids))
+	unique := ids[:0:0]
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,144 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+
+	"tixgo/modules/organizer/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/pgerr"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+// SenderDomainPostgresRepository implements the SenderDomainRepository interface using PostgreSQL
+type SenderDomainPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewSenderDomainPostgresRepository creates a new PostgreSQL sender domain repository
+func NewSenderDomainPostgresRepository(db *sqlx.DB) *SenderDomainPostgresRepository {
+	return &SenderDomainPostgresRepository{db: db}
+}
+
+// Save creates or replaces the sender domain of its organizer
+func (r *SenderDomainPostgresRepository) Save(ctx context.Context, senderDomain *domain.SenderDomain) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		INSERT INTO sender_domains (organizer_id, domain, from_email, from_name, verification_token, status, verified_at, checked_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (organizer_id) DO UPDATE
+		SET domain = EXCLUDED.domain,
+		    from_email = EXCLUDED.from_email,
+		    from_name = EXCLUDED.from_name,
+		    verification_token = EXCLUDED.verification_token,
+		    status = EXCLUDED.status,
+		    verified_at = EXCLUDED.verified_at,
+		    checked_at = EXCLUDED.checked_at,
+		    updated_at = NOW()
+		RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		senderDomain.OrganizerID,
+		senderDomain.Domain,
+		senderDomain.FromEmail,
+		senderDomain.FromName,
+		senderDomain.VerificationToken,
+		senderDomain.Status,
+		senderDomain.VerifiedAt,
+		senderDomain.CheckedAt,
+	).Scan(&senderDomain.ID, &senderDomain.CreatedAt, &senderDomain.UpdatedAt)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to save sender domain")
+	}
+
+	return nil
+}
+
+// GetByOrganizerID retrieves the sender domain of an organizer
+func (r *SenderDomainPostgresRepository) GetByOrganizerID(ctx context.Context, organizerID int64) (*domain.SenderDomain, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT id, organizer_id, domain, from_email, from_name, verification_token, status, verified_at, checked_at, created_at, updated_at
+		FROM sender_domains
+		WHERE organizer_id = $1`
+
+	senderDomain := &domain.SenderDomain{}
+	err := r.db.QueryRowContext(ctx, query, organizerID).Scan(
+		&senderDomain.ID,
+		&senderDomain.OrganizerID,
+		&senderDomain.Domain,
+		&senderDomain.FromEmail,
+		&senderDomain.FromName,
+		&senderDomain.VerificationToken,
+		&senderDomain.Status,
+		&senderDomain.VerifiedAt,
+		&senderDomain.CheckedAt,
+		&senderDomain.CreatedAt,
+		&senderDomain.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrSenderDomainNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get sender domain")
+	}
+
+	return senderDomain, nil
+}
+
+// UpdateVerification saves the status of a sender domain after a check
+func (r *SenderDomainPostgresRepository) UpdateVerification(ctx context.Context, senderDomain *domain.SenderDomain) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		UPDATE sender_domains
+		SET status = $2, verified_at = $3, checked_at = $4, updated_at = NOW()
+		WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, senderDomain.ID, senderDomain.Status, senderDomain.VerifiedAt, senderDomain.CheckedAt)
+	if err != nil {
+		// a domain is verified for one organizer at most, enforced by a partial unique index
+		if pgerr.IsUniqueViolation(err) {
+			return domain.ErrSenderDomainTaken
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to update sender domain")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return domain.ErrSenderDomainNotFound
+	}
+
+	return nil
+}
+
+// Delete deletes the sender domain of an organizer
+func (r *SenderDomainPostgresRepository) Delete(ctx context.Context, organizerID int64) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM sender_domains WHERE organizer_id = $1`, organizerID)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to delete sender domain")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return domain.ErrSenderDomainNotFound
+	}
+
+	return nil
+}
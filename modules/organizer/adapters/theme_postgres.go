@@ -0,0 +1,102 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+
+	"tixgo/modules/organizer/domain"
+	"tixgo/shared/dbtimeout"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+// ThemePostgresRepository implements the ThemeRepository interface using PostgreSQL
+type ThemePostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewThemePostgresRepository creates a new PostgreSQL theme repository
+func NewThemePostgresRepository(db *sqlx.DB) *ThemePostgresRepository {
+	return &ThemePostgresRepository{db: db}
+}
+
+// Save creates or replaces the theme of its organizer
+func (r *ThemePostgresRepository) Save(ctx context.Context, theme *domain.Theme) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		INSERT INTO organizer_themes (organizer_id, primary_color, accent_color, logo_url, footer_text)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (organizer_id) DO UPDATE
+		SET primary_color = EXCLUDED.primary_color,
+		    accent_color = EXCLUDED.accent_color,
+		    logo_url = EXCLUDED.logo_url,
+		    footer_text = EXCLUDED.footer_text,
+		    updated_at = NOW()
+		RETURNING updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		theme.OrganizerID,
+		theme.PrimaryColor,
+		theme.AccentColor,
+		theme.LogoURL,
+		theme.FooterText,
+	).Scan(&theme.UpdatedAt)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to save theme")
+	}
+
+	return nil
+}
+
+// GetByOrganizerID retrieves the theme of an organizer
+func (r *ThemePostgresRepository) GetByOrganizerID(ctx context.Context, organizerID int64) (*domain.Theme, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT organizer_id, primary_color, accent_color, logo_url, footer_text, updated_at
+		FROM organizer_themes
+		WHERE organizer_id = $1`
+
+	theme := &domain.Theme{}
+	err := r.db.QueryRowContext(ctx, query, organizerID).Scan(
+		&theme.OrganizerID,
+		&theme.PrimaryColor,
+		&theme.AccentColor,
+		&theme.LogoURL,
+		&theme.FooterText,
+		&theme.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrThemeNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get theme")
+	}
+
+	return theme, nil
+}
+
+// Delete deletes the theme of an organizer
+func (r *ThemePostgresRepository) Delete(ctx context.Context, organizerID int64) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM organizer_themes WHERE organizer_id = $1`, organizerID)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to delete theme")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return domain.ErrThemeNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+
+	"tixgo/modules/organizer/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/pgerr"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+// WidgetOriginPostgresRepository implements the WidgetOriginRepository interface using PostgreSQL
+type WidgetOriginPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewWidgetOriginPostgresRepository creates a new PostgreSQL widget origin repository
+func NewWidgetOriginPostgresRepository(db *sqlx.DB) *WidgetOriginPostgresRepository {
+	return &WidgetOriginPostgresRepository{db: db}
+}
+
+// Add registers an origin unless the organizer has domain.MaxWidgetOrigins already
+func (r *WidgetOriginPostgresRepository) Add(ctx context.Context, origin *domain.WidgetOrigin) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		INSERT INTO widget_origins (organizer_id, origin)
+		SELECT $1, $2
+		WHERE (SELECT COUNT(*) FROM widget_origins WHERE organizer_id = $1) < $3
+		RETURNING id, created_at`
+
+	err := r.db.QueryRowContext(ctx, query, origin.OrganizerID, origin.Origin, domain.MaxWidgetOrigins).
+		Scan(&origin.ID, &origin.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrTooManyWidgetOrigins
+		}
+		if pgerr.IsUniqueViolation(err) {
+			return domain.ErrWidgetOriginExists
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to add widget origin")
+	}
+
+	return nil
+}
+
+// ListByOrganizerID retrieves the origins of an organizer, oldest first
+func (r *WidgetOriginPostgresRepository) ListByOrganizerID(ctx context.Context, organizerID int64) ([]*domain.WidgetOrigin, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, organizer_id, origin, created_at
+		FROM widget_origins
+		WHERE organizer_id = $1
+		ORDER BY created_at, id`, organizerID)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list widget origins")
+	}
+	defer rows.Close()
+
+	origins := []*domain.WidgetOrigin{}
+	for rows.Next() {
+		origin := &domain.WidgetOrigin{}
+		if err := rows.Scan(&origin.ID, &origin.OrganizerID, &origin.Origin, &origin.CreatedAt); err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan widget origin")
+		}
+		origins = append(origins, origin)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "error iterating widget origin rows")
+	}
+
+	return origins, nil
+}
+
+// Delete removes an origin of an organizer
+func (r *WidgetOriginPostgresRepository) Delete(ctx context.Context, organizerID, id int64) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM widget_origins WHERE id = $1 AND organizer_id = $2`, id, organizerID)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to delete widget origin")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return domain.ErrWidgetOriginNotFound
+	}
+
+	return nil
+}
+
+// IsRegistered tells whether the organizer registered the origin
+func (r *WidgetOriginPostgresRepository) IsRegistered(ctx context.Context, organizerID int64, origin string) (bool, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var registered bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM widget_origins WHERE organizer_id = $1 AND origin = $2)`,
+		organizerID, origin).Scan(&registered)
+	if err != nil {
+		return false, syserr.Wrap(err, syserr.InternalCode, "failed to check widget origin")
+	}
+
+	return registered, nil
+}
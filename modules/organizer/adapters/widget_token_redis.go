@@ -0,0 +1,62 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"tixgo/modules/organizer/domain"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/redis/go-redis/v9"
+)
+
+const widgetTokenKeyPrefix = "widget:token:"
+
+// RedisWidgetTokenStore implements the WidgetTokenStore interface with redis, the key of a token
+// expiring with it
+type RedisWidgetTokenStore struct {
+	client redis.UniversalClient
+}
+
+// NewRedisWidgetTokenStore creates a widget token store backed by redis
+func NewRedisWidgetTokenStore(client redis.UniversalClient) *RedisWidgetTokenStore {
+	return &RedisWidgetTokenStore{client: client}
+}
+
+// Save stores a token until its expiry
+func (s *RedisWidgetTokenStore) Save(ctx context.Context, token *domain.WidgetToken) error {
+	value, err := json.Marshal(token)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to encode widget token")
+	}
+
+	if err := s.client.Set(ctx, widgetTokenKeyPrefix+token.Token, value, time.Until(token.ExpiresAt)).Err(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to store widget token")
+	}
+
+	return nil
+}
+
+// Get retrieves a token, failing with domain.ErrInvalidWidgetToken if it is unknown or expired
+func (s *RedisWidgetTokenStore) Get(ctx context.Context, token string) (*domain.WidgetToken, error) {
+	value, err := s.client.Get(ctx, widgetTokenKeyPrefix+token).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, domain.ErrInvalidWidgetToken
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get widget token")
+	}
+
+	widgetToken := &domain.WidgetToken{}
+	if err := json.Unmarshal(value, widgetToken); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to decode widget token")
+	}
+	if !time.Now().Before(widgetToken.ExpiresAt) {
+		return nil, domain.ErrInvalidWidgetToken
+	}
+
+	widgetToken.Token = token
+	return widgetToken, nil
+}
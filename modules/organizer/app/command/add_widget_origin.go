@@ -0,0 +1,60 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/organizer/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// AddWidgetOriginCommand represents the command to allow a site to embed the checkout widget
+type AddWidgetOriginCommand struct {
+	OrganizerID int64  `json:"-"`
+	Origin      string `json:"origin" binding:"required,max=300"`
+}
+
+// AddWidgetOriginHandler handles widget origin registration
+type AddWidgetOriginHandler struct {
+	widgetOriginRepo domain.WidgetOriginRepository
+}
+
+// NewAddWidgetOriginHandler creates a new add widget origin handler
+func NewAddWidgetOriginHandler(widgetOriginRepo domain.WidgetOriginRepository) *AddWidgetOriginHandler {
+	return &AddWidgetOriginHandler{
+		widgetOriginRepo: widgetOriginRepo,
+	}
+}
+
+// Handle executes the add widget origin command
+func (h *AddWidgetOriginHandler) Handle(ctx context.Context, cmd AddWidgetOriginCommand) (*WidgetOriginResult, error) {
+	origin, err := domain.NewWidgetOrigin(cmd.OrganizerID, cmd.Origin)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.widgetOriginRepo.Add(ctx, origin); err != nil {
+		if err == domain.ErrWidgetOriginExists || err == domain.ErrTooManyWidgetOrigins {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to add widget origin")
+	}
+
+	return ToWidgetOriginResult(origin), nil
+}
+
+// WidgetOriginResult represents a site allowed to embed the checkout widget
+type WidgetOriginResult struct {
+	ID        int64  `json:"id"`
+	Origin    string `json:"origin"`
+	CreatedAt string `json:"created_at"`
+}
+
+// ToWidgetOriginResult converts a widget origin to its result
+func ToWidgetOriginResult(origin *domain.WidgetOrigin) *WidgetOriginResult {
+	return &WidgetOriginResult{
+		ID:        origin.ID,
+		Origin:    origin.Origin,
+		CreatedAt: origin.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
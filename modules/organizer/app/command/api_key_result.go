@@ -0,0 +1,41 @@
+package command
+
+import "tixgo/modules/organizer/domain"
+
+// APIKeyResult represents an API key, its key only comes with the result of its creation
+type APIKeyResult struct {
+	ID           int64          `json:"id"`
+	Name         string         `json:"name"`
+	Key          string         `json:"key,omitempty"`
+	Prefix       string         `json:"prefix"`
+	Plan         domain.APIPlan `json:"plan"`
+	MonthlyQuota int64          `json:"monthly_quota"`
+	TestMode     bool           `json:"test_mode"`
+	CreatedAt    string         `json:"created_at"`
+	LastUsedAt   *string        `json:"last_used_at"`
+	RevokedAt    *string        `json:"revoked_at"`
+}
+
+// ToAPIKeyResult converts an API key to its result
+func ToAPIKeyResult(key *domain.APIKey) *APIKeyResult {
+	result := &APIKeyResult{
+		ID:           key.ID,
+		Name:         key.Name,
+		Prefix:       key.Prefix,
+		Plan:         key.Plan,
+		MonthlyQuota: key.MonthlyQuota(),
+		TestMode:     key.TestMode,
+		CreatedAt:    key.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+
+	if key.LastUsedAt != nil {
+		lastUsedAt := key.LastUsedAt.Format("2006-01-02T15:04:05Z")
+		result.LastUsedAt = &lastUsedAt
+	}
+	if key.RevokedAt != nil {
+		revokedAt := key.RevokedAt.Format("2006-01-02T15:04:05Z")
+		result.RevokedAt = &revokedAt
+	}
+
+	return result
+}
@@ -0,0 +1,116 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/organizer/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ConfigureSenderDomainCommand represents the command to set the domain an organizer sends mails from
+type ConfigureSenderDomainCommand struct {
+	OrganizerID int64  `json:"-"`
+	Domain      string `json:"domain" binding:"required,fqdn"`
+	FromEmail   string `json:"from_email" binding:"required,email"`
+	FromName    string `json:"from_name" binding:"required,max=100"`
+}
+
+// ConfigureSenderDomainHandler handles sender domain configuration
+type ConfigureSenderDomainHandler struct {
+	senderDomainRepo domain.SenderDomainRepository
+	platform         domain.SenderPlatform
+}
+
+// NewConfigureSenderDomainHandler creates a new configure sender domain handler
+func NewConfigureSenderDomainHandler(senderDomainRepo domain.SenderDomainRepository, platform domain.SenderPlatform) *ConfigureSenderDomainHandler {
+	return &ConfigureSenderDomainHandler{
+		senderDomainRepo: senderDomainRepo,
+		platform:         platform,
+	}
+}
+
+// Handle executes the configure sender domain command. A new domain must be verified before mails are
+// sent from it; changing the from address on the same domain keeps its verification.
+func (h *ConfigureSenderDomainHandler) Handle(ctx context.Context, cmd ConfigureSenderDomainCommand) (*SenderDomainResult, error) {
+	senderDomain, err := domain.NewSenderDomain(cmd.OrganizerID, cmd.Domain, cmd.FromEmail, cmd.FromName)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := h.senderDomainRepo.GetByOrganizerID(ctx, cmd.OrganizerID)
+	if err != nil && err != domain.ErrSenderDomainNotFound {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get sender domain")
+	}
+	if existing != nil && existing.Domain == senderDomain.Domain {
+		senderDomain.VerificationToken = existing.VerificationToken
+		senderDomain.Status = existing.Status
+		senderDomain.VerifiedAt = existing.VerifiedAt
+		senderDomain.CheckedAt = existing.CheckedAt
+	}
+
+	if err := h.senderDomainRepo.Save(ctx, senderDomain); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to save sender domain")
+	}
+
+	return ToSenderDomainResult(senderDomain, h.platform, nil), nil
+}
+
+// SenderDomainResult represents a sender domain with the DNS records to publish
+type SenderDomainResult struct {
+	Domain     string             `json:"domain"`
+	FromEmail  string             `json:"from_email"`
+	FromName   string             `json:"from_name"`
+	Status     string             `json:"status"`
+	Records    []*DNSRecordResult `json:"records"`
+	VerifiedAt *string            `json:"verified_at,omitempty"`
+	CheckedAt  *string            `json:"checked_at,omitempty"`
+}
+
+// DNSRecordResult is a DNS record to publish. Found is set once the record was looked up.
+type DNSRecordResult struct {
+	Purpose string `json:"purpose"`
+	Type    string `json:"type"`
+	Host    string `json:"host"`
+	Value   string `json:"value"`
+	Found   *bool  `json:"found,omitempty"`
+}
+
+// ToSenderDomainResult converts a sender domain to its result, with the outcome of checks if given
+func ToSenderDomainResult(senderDomain *domain.SenderDomain, platform domain.SenderPlatform, checks []domain.DNSRecordCheck) *SenderDomainResult {
+	result := &SenderDomainResult{
+		Domain:    senderDomain.Domain,
+		FromEmail: senderDomain.FromEmail,
+		FromName:  senderDomain.FromName,
+		Status:    string(senderDomain.Status),
+	}
+
+	found := make(map[domain.DNSRecordPurpose]bool, len(checks))
+	for _, check := range checks {
+		found[check.Record.Purpose] = check.Found
+	}
+
+	for _, record := range senderDomain.Records(platform) {
+		recordResult := &DNSRecordResult{
+			Purpose: string(record.Purpose),
+			Type:    record.Type,
+			Host:    record.Host,
+			Value:   record.Value,
+		}
+		if checked, ok := found[record.Purpose]; ok {
+			recordResult.Found = &checked
+		}
+		result.Records = append(result.Records, recordResult)
+	}
+
+	if senderDomain.VerifiedAt != nil {
+		verifiedAt := senderDomain.VerifiedAt.Format("2006-01-02T15:04:05Z")
+		result.VerifiedAt = &verifiedAt
+	}
+	if senderDomain.CheckedAt != nil {
+		checkedAt := senderDomain.CheckedAt.Format("2006-01-02T15:04:05Z")
+		result.CheckedAt = &checkedAt
+	}
+
+	return result
+}
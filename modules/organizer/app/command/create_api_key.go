@@ -0,0 +1,49 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/organizer/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// CreateAPIKeyCommand represents the command of an organizer to create an API key
+type CreateAPIKeyCommand struct {
+	OrganizerID int64  `json:"-"`
+	Name        string `json:"name" binding:"required,max=100"`
+	// TestMode keys create test mode events, to try checkouts with fake payments
+	TestMode bool `json:"test_mode"`
+}
+
+// CreateAPIKeyHandler handles API key creation
+type CreateAPIKeyHandler struct {
+	apiKeyRepo domain.APIKeyRepository
+}
+
+// NewCreateAPIKeyHandler creates a new create API key handler
+func NewCreateAPIKeyHandler(apiKeyRepo domain.APIKeyRepository) *CreateAPIKeyHandler {
+	return &CreateAPIKeyHandler{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// Handle executes the create API key command. The key is on the free plan until an admin moves it,
+// and is only returned here: it cannot be read again.
+func (h *CreateAPIKeyHandler) Handle(ctx context.Context, cmd CreateAPIKeyCommand) (*APIKeyResult, error) {
+	key, secret, err := domain.NewAPIKey(cmd.OrganizerID, cmd.Name, cmd.TestMode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.apiKeyRepo.Create(ctx, key); err != nil {
+		if err == domain.ErrTooManyAPIKeys {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to create API key")
+	}
+
+	result := ToAPIKeyResult(key)
+	result.Key = secret
+	return result, nil
+}
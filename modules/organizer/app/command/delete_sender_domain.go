@@ -0,0 +1,39 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/organizer/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// DeleteSenderDomainCommand represents the command to go back to the platform sender identity
+type DeleteSenderDomainCommand struct {
+	OrganizerID int64
+}
+
+// DeleteSenderDomainHandler handles sender domain deletion
+type DeleteSenderDomainHandler struct {
+	senderDomainRepo domain.SenderDomainRepository
+}
+
+// NewDeleteSenderDomainHandler creates a new delete sender domain handler
+func NewDeleteSenderDomainHandler(senderDomainRepo domain.SenderDomainRepository) *DeleteSenderDomainHandler {
+	return &DeleteSenderDomainHandler{
+		senderDomainRepo: senderDomainRepo,
+	}
+}
+
+// Handle executes the delete sender domain command
+func (h *DeleteSenderDomainHandler) Handle(ctx context.Context, cmd DeleteSenderDomainCommand) error {
+	err := h.senderDomainRepo.Delete(ctx, cmd.OrganizerID)
+	if err != nil {
+		if err == domain.ErrSenderDomainNotFound {
+			return domain.ErrSenderDomainNotFound
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to delete sender domain")
+	}
+
+	return nil
+}
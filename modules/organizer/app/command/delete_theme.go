@@ -0,0 +1,39 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/organizer/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// DeleteThemeCommand represents the command to go back to the platform theme
+type DeleteThemeCommand struct {
+	OrganizerID int64
+}
+
+// DeleteThemeHandler handles theme deletion
+type DeleteThemeHandler struct {
+	themeRepo domain.ThemeRepository
+}
+
+// NewDeleteThemeHandler creates a new delete theme handler
+func NewDeleteThemeHandler(themeRepo domain.ThemeRepository) *DeleteThemeHandler {
+	return &DeleteThemeHandler{
+		themeRepo: themeRepo,
+	}
+}
+
+// Handle executes the delete theme command
+func (h *DeleteThemeHandler) Handle(ctx context.Context, cmd DeleteThemeCommand) error {
+	err := h.themeRepo.Delete(ctx, cmd.OrganizerID)
+	if err != nil {
+		if err == domain.ErrThemeNotFound {
+			return domain.ErrThemeNotFound
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to delete theme")
+	}
+
+	return nil
+}
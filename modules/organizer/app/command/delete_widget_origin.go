@@ -0,0 +1,41 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/organizer/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// DeleteWidgetOriginCommand represents the command to stop a site embedding the checkout widget.
+// The tokens it already got stay valid until they expire.
+type DeleteWidgetOriginCommand struct {
+	OrganizerID int64
+	ID          int64
+}
+
+// DeleteWidgetOriginHandler handles widget origin deletion
+type DeleteWidgetOriginHandler struct {
+	widgetOriginRepo domain.WidgetOriginRepository
+}
+
+// NewDeleteWidgetOriginHandler creates a new delete widget origin handler
+func NewDeleteWidgetOriginHandler(widgetOriginRepo domain.WidgetOriginRepository) *DeleteWidgetOriginHandler {
+	return &DeleteWidgetOriginHandler{
+		widgetOriginRepo: widgetOriginRepo,
+	}
+}
+
+// Handle executes the delete widget origin command
+func (h *DeleteWidgetOriginHandler) Handle(ctx context.Context, cmd DeleteWidgetOriginCommand) error {
+	err := h.widgetOriginRepo.Delete(ctx, cmd.OrganizerID, cmd.ID)
+	if err != nil {
+		if err == domain.ErrWidgetOriginNotFound {
+			return domain.ErrWidgetOriginNotFound
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to delete widget origin")
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/organizer/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// FlushAPIUsageHandler saves the requests counted for the API keys, the summaries of past periods
+// being read from what was saved
+type FlushAPIUsageHandler struct {
+	usageCounter domain.APIUsageCounter
+	usageRepo    domain.APIUsageRepository
+}
+
+// NewFlushAPIUsageHandler creates a new flush API usage handler
+func NewFlushAPIUsageHandler(usageCounter domain.APIUsageCounter, usageRepo domain.APIUsageRepository) *FlushAPIUsageHandler {
+	return &FlushAPIUsageHandler{
+		usageCounter: usageCounter,
+		usageRepo:    usageRepo,
+	}
+}
+
+// Handle saves the counts of the current period and of the previous one, which still gets the
+// requests made right before it ended
+func (h *FlushAPIUsageHandler) Handle(ctx context.Context, now time.Time) error {
+	current := domain.UsagePeriod(now)
+
+	for _, period := range []time.Time{current.AddDate(0, -1, 0), current} {
+		counts, err := h.usageCounter.List(ctx, period)
+		if err != nil {
+			return syserr.Wrap(err, syserr.InternalCode, "failed to list API usage")
+		}
+
+		if err := h.usageRepo.Save(ctx, counts); err != nil {
+			return syserr.Wrap(err, syserr.InternalCode, "failed to save API usage")
+		}
+	}
+
+	return nil
+}
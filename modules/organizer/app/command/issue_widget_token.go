@@ -0,0 +1,90 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	eventDomain "tixgo/modules/event/domain"
+	"tixgo/modules/organizer/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// IssueWidgetTokenCommand represents the request of an embedded checkout widget for a token.
+// Origin is the Origin header of the request, set by the browser of the buyer.
+type IssueWidgetTokenCommand struct {
+	EventSlug string `json:"event_slug" binding:"required,max=255"`
+	Origin    string `json:"-"`
+}
+
+// IssueWidgetTokenHandler hands widget tokens to the sites the organizer of an event allowed
+type IssueWidgetTokenHandler struct {
+	publicEventRepo  eventDomain.PublicEventRepository
+	widgetOriginRepo domain.WidgetOriginRepository
+	widgetTokenStore domain.WidgetTokenStore
+	site             eventDomain.Site
+}
+
+// NewIssueWidgetTokenHandler creates a new issue widget token handler, handing the buyers off to the
+// checkout of the event page on site
+func NewIssueWidgetTokenHandler(publicEventRepo eventDomain.PublicEventRepository, widgetOriginRepo domain.WidgetOriginRepository, widgetTokenStore domain.WidgetTokenStore, site eventDomain.Site) *IssueWidgetTokenHandler {
+	return &IssueWidgetTokenHandler{
+		publicEventRepo:  publicEventRepo,
+		widgetOriginRepo: widgetOriginRepo,
+		widgetTokenStore: widgetTokenStore,
+		site:             site,
+	}
+}
+
+// Handle executes the issue widget token command. Only published events are sold through the widget.
+func (h *IssueWidgetTokenHandler) Handle(ctx context.Context, cmd IssueWidgetTokenCommand) (*WidgetTokenResult, error) {
+	origin, err := domain.NormalizeOrigin(cmd.Origin)
+	if err != nil {
+		return nil, domain.ErrWidgetOriginNotAllowed
+	}
+
+	event, err := h.publicEventRepo.GetPublicBySlug(ctx, cmd.EventSlug)
+	if err != nil {
+		if err == eventDomain.ErrEventNotFound {
+			return nil, eventDomain.ErrEventNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get event")
+	}
+	if event.Status != eventDomain.EventStatusPublished {
+		return nil, eventDomain.ErrEventNotFound
+	}
+
+	registered, err := h.widgetOriginRepo.IsRegistered(ctx, event.Organizer.ID, origin)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to check widget origin")
+	}
+	if !registered {
+		return nil, domain.ErrWidgetOriginNotAllowed
+	}
+
+	token, err := domain.NewWidgetToken(event.Organizer.ID, event.ID, event.Slug, origin, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := h.widgetTokenStore.Save(ctx, token); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to save widget token")
+	}
+
+	return &WidgetTokenResult{
+		Token:       token.Token,
+		EventID:     token.EventID,
+		ExpiresAt:   token.ExpiresAt.Format("2006-01-02T15:04:05Z"),
+		CheckoutURL: h.site.EventURL(event.Slug),
+	}, nil
+}
+
+// WidgetTokenResult is the token the widget sends as a bearer token until it expires
+type WidgetTokenResult struct {
+	Token     string `json:"token"`
+	EventID   int64  `json:"event_id"`
+	ExpiresAt string `json:"expires_at"`
+	// CheckoutURL is the event page the widget opens for the buyer to order and pay: orders belong to
+	// signed in buyers and card payments go through the payment integration, neither is served to the
+	// sites of organizers
+	CheckoutURL string `json:"checkout_url"`
+}
@@ -0,0 +1,82 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	eventDomain "tixgo/modules/event/domain"
+	"tixgo/modules/organizer/adapters"
+	"tixgo/modules/organizer/domain"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// publicEventsBySlug holds one public event
+type publicEventsBySlug struct {
+	eventDomain.PublicEventRepository
+	event *eventDomain.PublicEvent
+}
+
+func (r *publicEventsBySlug) GetPublicBySlug(_ context.Context, slug string) (*eventDomain.PublicEvent, error) {
+	if r.event.Slug != slug {
+		return nil, eventDomain.ErrEventNotFound
+	}
+	return r.event, nil
+}
+
+// allowedOrigins registers the origins of one organizer
+type allowedOrigins struct {
+	domain.WidgetOriginRepository
+	organizerID int64
+	origins     []string
+}
+
+func (r *allowedOrigins) IsRegistered(_ context.Context, organizerID int64, origin string) (bool, error) {
+	for _, allowed := range r.origins {
+		if organizerID == r.organizerID && origin == allowed {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestIssueWidgetTokenHandler(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	event := &eventDomain.PublicEvent{
+		ID:        12,
+		Slug:      "summer-fest",
+		Status:    eventDomain.EventStatusPublished,
+		Organizer: eventDomain.PublicOrganizer{ID: 7},
+	}
+	tokens := adapters.NewRedisWidgetTokenStore(client)
+	handler := NewIssueWidgetTokenHandler(
+		&publicEventsBySlug{event: event},
+		&allowedOrigins{organizerID: 7, origins: []string{"https://tickets.example.com"}},
+		tokens,
+		eventDomain.Site{URL: "https://tixgo.example/"},
+	)
+
+	result, err := handler.Handle(ctx, IssueWidgetTokenCommand{EventSlug: "summer-fest", Origin: "https://Tickets.example.com:443"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), result.EventID)
+	assert.Equal(t, "https://tixgo.example/events/summer-fest", result.CheckoutURL, "buyers order and pay on the event page")
+
+	token, err := tokens.Get(ctx, result.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "https://tickets.example.com", token.Origin)
+	assert.Equal(t, int64(7), token.OrganizerID)
+
+	_, err = handler.Handle(ctx, IssueWidgetTokenCommand{EventSlug: "summer-fest", Origin: "https://evil.example.com"})
+	assert.Equal(t, domain.ErrWidgetOriginNotAllowed, err)
+
+	event.Status = eventDomain.EventStatusPostponed
+	_, err = handler.Handle(ctx, IssueWidgetTokenCommand{EventSlug: "summer-fest", Origin: "https://tickets.example.com"})
+	assert.Equal(t, eventDomain.ErrEventNotFound, err, "only published events are sold through the widget")
+}
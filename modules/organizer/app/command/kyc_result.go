@@ -0,0 +1,67 @@
+package command
+
+import "tixgo/modules/organizer/domain"
+
+// KYCDocumentResult represents an uploaded KYC document, its file is only served to admins
+type KYCDocumentResult struct {
+	ID          int64                  `json:"id"`
+	Type        domain.KYCDocumentType `json:"type"`
+	FileName    string                 `json:"file_name"`
+	ContentType string                 `json:"content_type"`
+	Size        int64                  `json:"size"`
+	CreatedAt   string                 `json:"created_at"`
+}
+
+// KYCSubmissionResult represents a KYC submission and its review
+type KYCSubmissionResult struct {
+	ID                 int64                `json:"id"`
+	OrganizerID        int64                `json:"organizer_id"`
+	LegalName          string               `json:"legal_name"`
+	RegistrationNumber string               `json:"registration_number"`
+	TaxID              string               `json:"tax_id,omitempty"`
+	Address            string               `json:"address"`
+	Country            string               `json:"country"`
+	Status             domain.KYCStatus     `json:"status"`
+	RejectionReason    string               `json:"rejection_reason,omitempty"`
+	Documents          []*KYCDocumentResult `json:"documents,omitempty"`
+	ReviewedBy         *int64               `json:"reviewed_by"`
+	SubmittedAt        string               `json:"submitted_at"`
+	ReviewedAt         *string              `json:"reviewed_at"`
+}
+
+// ToKYCDocumentResult converts a document to its result
+func ToKYCDocumentResult(document *domain.KYCDocument) *KYCDocumentResult {
+	return &KYCDocumentResult{
+		ID:          document.ID,
+		Type:        document.Type,
+		FileName:    document.FileName,
+		ContentType: document.ContentType,
+		Size:        document.Size,
+		CreatedAt:   document.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+// ToKYCSubmissionResult converts a submission to its result
+func ToKYCSubmissionResult(submission *domain.KYCSubmission) *KYCSubmissionResult {
+	result := &KYCSubmissionResult{
+		ID:                 submission.ID,
+		OrganizerID:        submission.OrganizerID,
+		LegalName:          submission.LegalName,
+		RegistrationNumber: submission.RegistrationNumber,
+		TaxID:              submission.TaxID,
+		Address:            submission.Address,
+		Country:            submission.Country,
+		Status:             submission.Status,
+		RejectionReason:    submission.RejectionReason,
+		ReviewedBy:         submission.ReviewedBy,
+		SubmittedAt:        submission.SubmittedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	for _, document := range submission.Documents {
+		result.Documents = append(result.Documents, ToKYCDocumentResult(document))
+	}
+	if submission.ReviewedAt != nil {
+		reviewedAt := submission.ReviewedAt.Format("2006-01-02T15:04:05Z")
+		result.ReviewedAt = &reviewedAt
+	}
+	return result
+}
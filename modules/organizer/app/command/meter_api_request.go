@@ -0,0 +1,111 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"tixgo/modules/organizer/domain"
+	"tixgo/shared/dedup"
+	sharedKafka "tixgo/shared/kafka"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/syserr"
+)
+
+const (
+	dedupPurposeAPIQuotaWarning = "api_quota_warning"
+)
+
+// MeterAPIRequestCommand represents a request authenticated with an API key, to count against its quota
+type MeterAPIRequestCommand struct {
+	Key string
+	Now time.Time
+}
+
+// MeteredAPIRequest is the key a request was made with and where it stands against its quota
+type MeteredAPIRequest struct {
+	Key   *domain.APIKey
+	Quota domain.APIQuota
+	// Metered is false when the request could not be counted, it then has no quota to report
+	Metered bool
+}
+
+// MeterAPIRequestHandler authenticates the requests made with API keys and counts them
+type MeterAPIRequestHandler struct {
+	apiKeyRepo   domain.APIKeyRepository
+	usageCounter domain.APIUsageCounter
+	deduplicator dedup.Deduplicator
+	eventBus     messaging.EventBus
+}
+
+// NewMeterAPIRequestHandler creates a new meter API request handler
+func NewMeterAPIRequestHandler(apiKeyRepo domain.APIKeyRepository, usageCounter domain.APIUsageCounter, deduplicator dedup.Deduplicator, eventBus messaging.EventBus) *MeterAPIRequestHandler {
+	return &MeterAPIRequestHandler{
+		apiKeyRepo:   apiKeyRepo,
+		usageCounter: usageCounter,
+		deduplicator: deduplicator,
+		eventBus:     eventBus,
+	}
+}
+
+// Handle executes the meter API request command. It fails with domain.ErrInvalidAPIKey for unknown
+// or revoked keys, and with domain.ErrAPIQuotaExceeded along with the request once the key made more
+// requests than its quota this month. Requests the counter is unavailable for are let through.
+func (h *MeterAPIRequestHandler) Handle(ctx context.Context, cmd MeterAPIRequestCommand) (*MeteredAPIRequest, error) {
+	key, err := h.apiKeyRepo.GetByHash(ctx, domain.HashAPIKey(cmd.Key))
+	if err != nil {
+		if err == domain.ErrInvalidAPIKey {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get API key")
+	}
+
+	request := &MeteredAPIRequest{Key: key}
+
+	period := domain.UsagePeriod(cmd.Now)
+	used, err := h.usageCounter.Increment(ctx, key.ID, period)
+	if err != nil {
+		logger.Warning(ctx, "Failed to count API request", logger.F("api_key_id", key.ID), logger.F("error", err))
+		return request, nil
+	}
+
+	request.Quota = domain.NewAPIQuota(key, period, used)
+	request.Metered = true
+
+	if request.Quota.ReachedWarning() {
+		h.warn(ctx, key, request.Quota, cmd.Now)
+	}
+
+	if request.Quota.Exceeded() {
+		return request, domain.ErrAPIQuotaExceeded
+	}
+
+	return request, nil
+}
+
+// warn publishes EventAPIQuotaWarning once per key and period. The warning is skipped when the
+// deduplicator is unavailable, every request past the threshold would warn otherwise.
+func (h *MeterAPIRequestHandler) warn(ctx context.Context, key *domain.APIKey, quota domain.APIQuota, now time.Time) {
+	dedupKey := dedup.Key(dedupPurposeAPIQuotaWarning, strconv.FormatInt(key.ID, 10)+":"+quota.PeriodAt.Format(domain.APIUsagePeriodLayout))
+
+	err := h.deduplicator.Claim(ctx, dedupKey, quota.ResetAt().Sub(now)+24*time.Hour)
+	if errors.Is(err, dedup.ErrDuplicate) {
+		return
+	}
+	if err != nil {
+		logger.Warning(ctx, "Failed to deduplicate API quota warning", logger.F("api_key_id", key.ID), logger.F("error", err))
+		return
+	}
+
+	organizerKey := strconv.FormatInt(key.OrganizerID, 10)
+	err = h.eventBus.PublishEvent(sharedKafka.WithPartitionKey(ctx, organizerKey), domain.NewEventAPIQuotaWarning(key, quota))
+	if err != nil {
+		logger.Error(ctx, "Failed to publish API quota warning", logger.F("api_key_id", key.ID), logger.F("error", err))
+		if forgetErr := h.deduplicator.Forget(ctx, dedupKey); forgetErr != nil {
+			logger.Warning(ctx, "Failed to release API quota warning claim", logger.F("api_key_id", key.ID), logger.F("error", forgetErr))
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tixgo/modules/organizer/adapters"
+	"tixgo/modules/organizer/domain"
+	"tixgo/shared/dedup"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryAPIKeyRepository holds one key
+type memoryAPIKeyRepository struct {
+	domain.APIKeyRepository
+	key *domain.APIKey
+}
+
+func (r *memoryAPIKeyRepository) GetByHash(_ context.Context, hash string) (*domain.APIKey, error) {
+	if r.key == nil || r.key.Hash != hash || r.key.RevokedAt != nil {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	return r.key, nil
+}
+
+// recordingBus keeps the events it is asked to publish
+type recordingBus struct {
+	published []any
+}
+
+func (b *recordingBus) PublishEvent(_ context.Context, event any) error {
+	b.published = append(b.published, event)
+	return nil
+}
+
+func TestMeterAPIRequestHandler(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	newHandler := func(t *testing.T, quota int64) (*MeterAPIRequestHandler, *memoryAPIKeyRepository, *recordingBus, string) {
+		server := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		t.Cleanup(func() { client.Close() })
+
+		key, secret, err := domain.NewAPIKey(7, "sync", false)
+		require.NoError(t, err)
+		key.ID = 3
+		key.QuotaOverride = &quota
+
+		repo := &memoryAPIKeyRepository{key: key}
+		bus := &recordingBus{}
+		handler := NewMeterAPIRequestHandler(repo, adapters.NewRedisAPIUsageCounter(client), dedup.NewRedisDeduplicator(client), bus)
+		return handler, repo, bus, secret
+	}
+
+	t.Run("counts requests and rejects them past the quota", func(t *testing.T) {
+		handler, _, _, secret := newHandler(t, 5)
+
+		for i := 1; i <= 5; i++ {
+			request, err := handler.Handle(ctx, MeterAPIRequestCommand{Key: secret, Now: now})
+			require.NoError(t, err)
+			assert.True(t, request.Metered)
+			assert.Equal(t, int64(7), request.Key.OrganizerID)
+			assert.Equal(t, int64(5-i), request.Quota.Remaining())
+		}
+
+		request, err := handler.Handle(ctx, MeterAPIRequestCommand{Key: secret, Now: now})
+		assert.Equal(t, domain.ErrAPIQuotaExceeded, err)
+		assert.Equal(t, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), request.Quota.ResetAt())
+
+		_, err = handler.Handle(ctx, MeterAPIRequestCommand{Key: secret, Now: now.AddDate(0, 1, 0)})
+		assert.NoError(t, err, "the quota resets with the month")
+	})
+
+	t.Run("warns once per period at 80 percent", func(t *testing.T) {
+		handler, _, bus, secret := newHandler(t, 10)
+
+		for i := 0; i < 9; i++ {
+			_, err := handler.Handle(ctx, MeterAPIRequestCommand{Key: secret, Now: now})
+			require.NoError(t, err)
+			if i < 7 {
+				assert.Empty(t, bus.published, "request %d", i+1)
+			}
+		}
+
+		require.Len(t, bus.published, 1)
+		warning := bus.published[0].(*domain.EventAPIQuotaWarning)
+		assert.Equal(t, int64(8), warning.Requests)
+		assert.Equal(t, int64(10), warning.Quota)
+		assert.Equal(t, "2026-10", warning.Period)
+		assert.Equal(t, int64(7), warning.OrganizerID)
+	})
+
+	t.Run("rejects unknown and revoked keys", func(t *testing.T) {
+		handler, repo, _, secret := newHandler(t, 10)
+
+		_, err := handler.Handle(ctx, MeterAPIRequestCommand{Key: "tixgo_unknown", Now: now})
+		assert.Equal(t, domain.ErrInvalidAPIKey, err)
+
+		revokedAt := now
+		repo.key.RevokedAt = &revokedAt
+		_, err = handler.Handle(ctx, MeterAPIRequestCommand{Key: secret, Now: now})
+		assert.Equal(t, domain.ErrInvalidAPIKey, err)
+	})
+}
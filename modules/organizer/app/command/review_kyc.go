@@ -0,0 +1,73 @@
+package command
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"tixgo/modules/organizer/domain"
+	sharedKafka "tixgo/shared/kafka"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ReviewKYCCommand represents the command of an admin approving or rejecting a KYC submission
+type ReviewKYCCommand struct {
+	SubmissionID int64  `json:"-"`
+	ReviewerID   int64  `json:"-"`
+	Approve      bool   `json:"-"`
+	Reason       string `json:"reason" binding:"max=1000"`
+}
+
+// ReviewKYCHandler handles KYC reviews
+type ReviewKYCHandler struct {
+	kycRepo  domain.KYCRepository
+	eventBus messaging.EventBus
+}
+
+// NewReviewKYCHandler creates a new review KYC handler
+func NewReviewKYCHandler(kycRepo domain.KYCRepository, eventBus messaging.EventBus) *ReviewKYCHandler {
+	return &ReviewKYCHandler{
+		kycRepo:  kycRepo,
+		eventBus: eventBus,
+	}
+}
+
+// Handle executes the review; the organizer is told about the decision by mail
+func (h *ReviewKYCHandler) Handle(ctx context.Context, cmd ReviewKYCCommand) (*KYCSubmissionResult, error) {
+	submission, err := h.kycRepo.GetByID(ctx, cmd.SubmissionID)
+	if err != nil {
+		if err == domain.ErrKYCNotFound {
+			return nil, domain.ErrKYCNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get KYC submission")
+	}
+
+	if cmd.Approve {
+		err = submission.Approve(cmd.ReviewerID)
+	} else {
+		err = submission.Reject(cmd.ReviewerID, strings.TrimSpace(cmd.Reason))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.kycRepo.SaveReview(ctx, submission)
+	if err != nil {
+		if err == domain.ErrKYCNotPending {
+			return nil, domain.ErrKYCNotPending
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to save KYC review")
+	}
+
+	// The review is saved, a failure only costs the organizer their mail so it is logged
+	key := strconv.FormatInt(submission.OrganizerID, 10)
+	err = h.eventBus.PublishEvent(sharedKafka.WithPartitionKey(ctx, key), domain.NewEventKYCReviewed(submission))
+	if err != nil {
+		logger.Error(ctx, "Failed to publish KYC review", logger.F("submission_id", submission.ID), logger.F("error", err))
+	}
+
+	return ToKYCSubmissionResult(submission), nil
+}
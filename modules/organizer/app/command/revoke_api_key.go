@@ -0,0 +1,39 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/organizer/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// RevokeAPIKeyCommand represents the command of an organizer to revoke one of their API keys
+type RevokeAPIKeyCommand struct {
+	OrganizerID int64
+	ID          int64
+}
+
+// RevokeAPIKeyHandler handles API key revocation
+type RevokeAPIKeyHandler struct {
+	apiKeyRepo domain.APIKeyRepository
+}
+
+// NewRevokeAPIKeyHandler creates a new revoke API key handler
+func NewRevokeAPIKeyHandler(apiKeyRepo domain.APIKeyRepository) *RevokeAPIKeyHandler {
+	return &RevokeAPIKeyHandler{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// Handle executes the revoke API key command, the usage of the key stays in the summaries
+func (h *RevokeAPIKeyHandler) Handle(ctx context.Context, cmd RevokeAPIKeyCommand) error {
+	if err := h.apiKeyRepo.Revoke(ctx, cmd.OrganizerID, cmd.ID); err != nil {
+		if err == domain.ErrAPIKeyNotFound {
+			return err
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to revoke API key")
+	}
+
+	return nil
+}
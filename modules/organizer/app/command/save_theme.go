@@ -0,0 +1,54 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/organizer/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ThemeInput is a theme as organizers write it; an empty field keeps the platform default
+type ThemeInput struct {
+	PrimaryColor string `json:"primary_color" binding:"omitempty,max=7"`
+	AccentColor  string `json:"accent_color" binding:"omitempty,max=7"`
+	LogoURL      string `json:"logo_url" binding:"omitempty,max=500"`
+	FooterText   string `json:"footer_text" binding:"max=300"`
+}
+
+// NewTheme validates the input as the theme of an organizer
+func (i ThemeInput) NewTheme(organizerID int64) (*domain.Theme, error) {
+	return domain.NewTheme(organizerID, i.PrimaryColor, i.AccentColor, i.LogoURL, i.FooterText)
+}
+
+// SaveThemeCommand represents the command to set the theme of an organizer
+type SaveThemeCommand struct {
+	OrganizerID int64 `json:"-"`
+	ThemeInput
+}
+
+// SaveThemeHandler handles theme changes
+type SaveThemeHandler struct {
+	themeRepo domain.ThemeRepository
+}
+
+// NewSaveThemeHandler creates a new save theme handler
+func NewSaveThemeHandler(themeRepo domain.ThemeRepository) *SaveThemeHandler {
+	return &SaveThemeHandler{
+		themeRepo: themeRepo,
+	}
+}
+
+// Handle executes the save theme command. The theme brands the tickets and mails rendered from then on.
+func (h *SaveThemeHandler) Handle(ctx context.Context, cmd SaveThemeCommand) (*ThemeResult, error) {
+	theme, err := cmd.NewTheme(cmd.OrganizerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.themeRepo.Save(ctx, theme); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to save theme")
+	}
+
+	return ToThemeResult(theme), nil
+}
@@ -0,0 +1,54 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/organizer/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// SetAPIKeyPlanCommand represents the command of an admin to move an API key to a plan
+type SetAPIKeyPlanCommand struct {
+	ID   int64  `json:"-"`
+	Plan string `json:"plan" binding:"required,oneof=free standard business"`
+	// MonthlyQuota overrides the quota of the plan, left out to use the plan's
+	MonthlyQuota *int64 `json:"monthly_quota" binding:"omitempty,min=1"`
+}
+
+// SetAPIKeyPlanHandler handles API key plan changes
+type SetAPIKeyPlanHandler struct {
+	apiKeyRepo domain.APIKeyRepository
+}
+
+// NewSetAPIKeyPlanHandler creates a new set API key plan handler
+func NewSetAPIKeyPlanHandler(apiKeyRepo domain.APIKeyRepository) *SetAPIKeyPlanHandler {
+	return &SetAPIKeyPlanHandler{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// Handle executes the set API key plan command. The new quota applies to the current period, the
+// requests already made in it counting against it.
+func (h *SetAPIKeyPlanHandler) Handle(ctx context.Context, cmd SetAPIKeyPlanCommand) (*APIKeyResult, error) {
+	key, err := h.apiKeyRepo.GetByID(ctx, cmd.ID)
+	if err != nil {
+		if err == domain.ErrAPIKeyNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get API key")
+	}
+
+	if err := key.SetPlan(domain.APIPlan(cmd.Plan), cmd.MonthlyQuota); err != nil {
+		return nil, err
+	}
+
+	if err := h.apiKeyRepo.SavePlan(ctx, key); err != nil {
+		if err == domain.ErrAPIKeyNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to save API key plan")
+	}
+
+	return ToAPIKeyResult(key), nil
+}
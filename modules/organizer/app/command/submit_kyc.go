@@ -0,0 +1,75 @@
+package command
+
+import (
+	"context"
+	"strings"
+
+	"tixgo/modules/organizer/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// SubmitKYCCommand represents the command of an organizer submitting their business for verification
+// with the documents they uploaded
+type SubmitKYCCommand struct {
+	OrganizerID        int64   `json:"-"`
+	LegalName          string  `json:"legal_name" binding:"required,max=255"`
+	RegistrationNumber string  `json:"registration_number" binding:"required,max=100"`
+	TaxID              string  `json:"tax_id" binding:"omitempty,max=100"`
+	Address            string  `json:"address" binding:"required,max=1000"`
+	Country            string  `json:"country" binding:"required,iso3166_1_alpha2"`
+	DocumentIDs        []int64 `json:"document_ids" binding:"required,min=1,max=10,dive,min=1"`
+}
+
+// SubmitKYCHandler handles KYC submissions
+type SubmitKYCHandler struct {
+	kycRepo domain.KYCRepository
+}
+
+// NewSubmitKYCHandler creates a new submit KYC handler
+func NewSubmitKYCHandler(kycRepo domain.KYCRepository) *SubmitKYCHandler {
+	return &SubmitKYCHandler{
+		kycRepo: kycRepo,
+	}
+}
+
+// Handle submits the business info for review. An organizer whose latest submission is pending or
+// approved cannot submit again; after a rejection they submit anew with fresh documents.
+func (h *SubmitKYCHandler) Handle(ctx context.Context, cmd SubmitKYCCommand) (*KYCSubmissionResult, error) {
+	latest, err := h.kycRepo.GetLatestByOrganizerID(ctx, cmd.OrganizerID)
+	if err != nil && err != domain.ErrKYCNotFound {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get KYC submission")
+	}
+	if latest != nil && latest.IsOpen() {
+		return nil, domain.ErrKYCAlreadySubmitted
+	}
+
+	documents, err := h.kycRepo.GetUnattachedDocuments(ctx, cmd.OrganizerID, cmd.DocumentIDs)
+	if err != nil {
+		if err == domain.ErrKYCDocumentNotFound {
+			return nil, domain.ErrKYCDocumentNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get KYC documents")
+	}
+
+	submission, err := domain.NewKYCSubmission(cmd.OrganizerID, domain.KYCBusinessInfo{
+		LegalName:          strings.TrimSpace(cmd.LegalName),
+		RegistrationNumber: strings.TrimSpace(cmd.RegistrationNumber),
+		TaxID:              strings.TrimSpace(cmd.TaxID),
+		Address:            strings.TrimSpace(cmd.Address),
+		Country:            strings.ToUpper(cmd.Country),
+	}, documents)
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.kycRepo.Submit(ctx, submission)
+	if err != nil {
+		if err == domain.ErrKYCAlreadySubmitted || err == domain.ErrKYCDocumentNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to submit KYC")
+	}
+
+	return ToKYCSubmissionResult(submission), nil
+}
@@ -0,0 +1,51 @@
+package command
+
+import (
+	"tixgo/modules/organizer/domain"
+	templateDomain "tixgo/modules/template/domain"
+)
+
+// ThemeResult represents the theme attendee-facing tickets and mails of an organizer are branded with,
+// its empty fields filled with the platform defaults
+type ThemeResult struct {
+	PrimaryColor string `json:"primary_color"`
+	AccentColor  string `json:"accent_color"`
+	LogoURL      string `json:"logo_url"`
+	FooterText   string `json:"footer_text"`
+	// Custom is false while the organizer has no theme and the platform theme applies
+	Custom    bool    `json:"custom"`
+	UpdatedAt *string `json:"updated_at,omitempty"`
+}
+
+// ToThemeResult converts the theme of an organizer to its result, nil for the platform theme
+func ToThemeResult(theme *domain.Theme) *ThemeResult {
+	effective := ToTemplateTheme(theme).WithDefaults()
+	result := &ThemeResult{
+		PrimaryColor: effective.PrimaryColor,
+		AccentColor:  effective.AccentColor,
+		LogoURL:      effective.LogoURL,
+		FooterText:   effective.FooterText,
+		Custom:       theme != nil,
+	}
+
+	if theme != nil {
+		updatedAt := theme.UpdatedAt.Format("2006-01-02T15:04:05Z")
+		result.UpdatedAt = &updatedAt
+	}
+
+	return result
+}
+
+// ToTemplateTheme converts the theme of an organizer to the one templates render with, nil for the
+// platform theme
+func ToTemplateTheme(theme *domain.Theme) *templateDomain.Theme {
+	if theme == nil {
+		return nil
+	}
+	return &templateDomain.Theme{
+		PrimaryColor: theme.PrimaryColor,
+		AccentColor:  theme.AccentColor,
+		LogoURL:      theme.LogoURL,
+		FooterText:   theme.FooterText,
+	}
+}
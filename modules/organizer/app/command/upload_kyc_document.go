@@ -0,0 +1,127 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"tixgo/modules/organizer/domain"
+	"tixgo/shared/storage"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// UploadKYCDocumentCommand represents the command of an organizer uploading a KYC document
+type UploadKYCDocumentCommand struct {
+	OrganizerID int64  `form:"-"`
+	Type        string `form:"type" binding:"required"`
+	FileName    string `form:"-"`
+	// Content is the file, read up to one byte past domain.MaxKYCDocumentSize
+	Content io.Reader `form:"-"`
+}
+
+// UploadKYCDocumentHandler keeps the documents organizers upload until they submit them
+type UploadKYCDocumentHandler struct {
+	kycRepo domain.KYCRepository
+	store   storage.Store
+}
+
+// NewUploadKYCDocumentHandler creates a new upload KYC document handler
+func NewUploadKYCDocumentHandler(kycRepo domain.KYCRepository, store storage.Store) *UploadKYCDocumentHandler {
+	return &UploadKYCDocumentHandler{
+		kycRepo: kycRepo,
+		store:   store,
+	}
+}
+
+// Handle stores the document in the storage then records it. The format is sniffed from the content
+// rather than trusted from the upload.
+func (h *UploadKYCDocumentHandler) Handle(ctx context.Context, cmd UploadKYCDocumentCommand) (*KYCDocumentResult, error) {
+	if !domain.IsValidKYCDocumentType(cmd.Type) {
+		return nil, domain.ErrInvalidKYCDocumentType
+	}
+
+	content := bufio.NewReaderSize(io.LimitReader(cmd.Content, domain.MaxKYCDocumentSize+1), 512)
+	head, err := content.Peek(512)
+	if err != nil && err != io.EOF {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to read KYC document")
+	}
+	contentType := http.DetectContentType(head)
+	if !isKYCDocumentContentType(contentType) {
+		return nil, domain.ErrInvalidKYCDocumentFormat
+	}
+
+	key, err := kycDocumentKey(cmd.OrganizerID)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to generate KYC document key")
+	}
+
+	size, err := h.store.Put(ctx, key, content)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to store KYC document")
+	}
+	if size > domain.MaxKYCDocumentSize {
+		h.discard(ctx, key)
+		return nil, domain.ErrKYCDocumentTooLarge
+	}
+
+	document := &domain.KYCDocument{
+		OrganizerID: cmd.OrganizerID,
+		Type:        domain.KYCDocumentType(cmd.Type),
+		FileName:    kycDocumentFileName(cmd.FileName),
+		ContentType: contentType,
+		Size:        size,
+		StorageKey:  key,
+	}
+	if err := h.kycRepo.SaveDocument(ctx, document); err != nil {
+		h.discard(ctx, key)
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to save KYC document")
+	}
+
+	return ToKYCDocumentResult(document), nil
+}
+
+// discard deletes a file that was not recorded, a failure only leaves an unreferenced file so it is logged
+func (h *UploadKYCDocumentHandler) discard(ctx context.Context, key string) {
+	if err := h.store.Delete(ctx, key); err != nil {
+		logger.Warning(ctx, "Failed to delete KYC document", logger.F("key", key), logger.F("error", err))
+	}
+}
+
+func isKYCDocumentContentType(contentType string) bool {
+	for _, accepted := range domain.KYCDocumentContentTypes {
+		if contentType == accepted {
+			return true
+		}
+	}
+	return false
+}
+
+// kycDocumentKey is a new random key under the directory of the organizer, so uploads never collide
+func kycDocumentKey(organizerID int64) (string, error) {
+	name := make([]byte, 16)
+	if _, err := rand.Read(name); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("kyc/%d/%s", organizerID, hex.EncodeToString(name)), nil
+}
+
+// kycDocumentFileName keeps the base name of the uploaded file for admins downloading it, bounded to
+// its column
+func kycDocumentFileName(name string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, `\`, "/")))
+	if name == "." || name == "/" || name == "" {
+		return "document"
+	}
+	if runes := []rune(name); len(runes) > 255 {
+		name = string(runes[len(runes)-255:])
+	}
+	return name
+}
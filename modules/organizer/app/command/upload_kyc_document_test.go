@@ -0,0 +1,102 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tixgo/modules/organizer/domain"
+	"tixgo/shared/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryKYCRepository records the saved documents
+type memoryKYCRepository struct {
+	domain.KYCRepository
+	documents []*domain.KYCDocument
+}
+
+func (r *memoryKYCRepository) SaveDocument(_ context.Context, document *domain.KYCDocument) error {
+	document.ID = int64(len(r.documents) + 1)
+	r.documents = append(r.documents, document)
+	return nil
+}
+
+func TestUploadKYCDocumentHandler(t *testing.T) {
+	ctx := context.Background()
+	pdf := "%PDF-1.7\n" + strings.Repeat("x", 600)
+
+	newHandler := func(t *testing.T) (*UploadKYCDocumentHandler, *memoryKYCRepository, string) {
+		root := t.TempDir()
+		repo := &memoryKYCRepository{}
+		return NewUploadKYCDocumentHandler(repo, storage.NewDiskStore(root)), repo, root
+	}
+
+	t.Run("stores the document under the organizer", func(t *testing.T) {
+		handler, repo, _ := newHandler(t)
+
+		result, err := handler.Handle(ctx, UploadKYCDocumentCommand{
+			OrganizerID: 7,
+			Type:        string(domain.KYCDocumentIdentity),
+			FileName:    `C:\scans\passport.pdf`,
+			Content:     strings.NewReader(pdf),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "passport.pdf", result.FileName)
+		assert.Equal(t, "application/pdf", result.ContentType)
+		assert.Equal(t, int64(len(pdf)), result.Size)
+
+		require.Len(t, repo.documents, 1)
+		assert.True(t, strings.HasPrefix(repo.documents[0].StorageKey, "kyc/7/"))
+
+		file, err := handler.store.Open(ctx, repo.documents[0].StorageKey)
+		require.NoError(t, err)
+		defer file.Close()
+		content, err := io.ReadAll(file)
+		require.NoError(t, err)
+		assert.Equal(t, pdf, string(content))
+	})
+
+	t.Run("the format is sniffed from the content", func(t *testing.T) {
+		handler, repo, _ := newHandler(t)
+
+		_, err := handler.Handle(ctx, UploadKYCDocumentCommand{
+			OrganizerID: 7,
+			Type:        string(domain.KYCDocumentIdentity),
+			FileName:    "passport.pdf",
+			Content:     strings.NewReader("<html><script>alert(1)</script></html>"),
+		})
+		assert.Equal(t, domain.ErrInvalidKYCDocumentFormat, err)
+		assert.Empty(t, repo.documents)
+	})
+
+	t.Run("oversized documents are discarded", func(t *testing.T) {
+		handler, repo, root := newHandler(t)
+
+		_, err := handler.Handle(ctx, UploadKYCDocumentCommand{
+			OrganizerID: 7,
+			Type:        string(domain.KYCDocumentBusinessRegistration),
+			FileName:    "registration.pdf",
+			Content:     io.MultiReader(strings.NewReader("%PDF-1.7\n"), bytes.NewReader(make([]byte, domain.MaxKYCDocumentSize))),
+		})
+		assert.Equal(t, domain.ErrKYCDocumentTooLarge, err)
+		assert.Empty(t, repo.documents)
+
+		entries, err := os.ReadDir(filepath.Join(root, "kyc", "7"))
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("unknown document types are rejected", func(t *testing.T) {
+		handler, _, _ := newHandler(t)
+
+		_, err := handler.Handle(ctx, UploadKYCDocumentCommand{OrganizerID: 7, Type: "selfie", Content: strings.NewReader(pdf)})
+		assert.Equal(t, domain.ErrInvalidKYCDocumentType, err)
+	})
+}
@@ -0,0 +1,65 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/organizer/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// VerifySenderDomainCommand represents the command to check the DNS records of the sender domain of an organizer
+type VerifySenderDomainCommand struct {
+	OrganizerID int64
+}
+
+// VerifySenderDomainHandler handles sender domain verification
+type VerifySenderDomainHandler struct {
+	senderDomainRepo domain.SenderDomainRepository
+	resolver         domain.DNSResolver
+	platform         domain.SenderPlatform
+}
+
+// NewVerifySenderDomainHandler creates a new verify sender domain handler
+func NewVerifySenderDomainHandler(senderDomainRepo domain.SenderDomainRepository, resolver domain.DNSResolver, platform domain.SenderPlatform) *VerifySenderDomainHandler {
+	return &VerifySenderDomainHandler{
+		senderDomainRepo: senderDomainRepo,
+		resolver:         resolver,
+		platform:         platform,
+	}
+}
+
+// Handle executes the verify sender domain command: every record is looked up and the domain is verified
+// when all of them are published. A verified domain whose records went missing stops being used.
+func (h *VerifySenderDomainHandler) Handle(ctx context.Context, cmd VerifySenderDomainCommand) (*SenderDomainResult, error) {
+	senderDomain, err := h.senderDomainRepo.GetByOrganizerID(ctx, cmd.OrganizerID)
+	if err != nil {
+		if err == domain.ErrSenderDomainNotFound {
+			return nil, domain.ErrSenderDomainNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get sender domain")
+	}
+
+	records := senderDomain.Records(h.platform)
+	checks := make([]domain.DNSRecordCheck, len(records))
+	for i, record := range records {
+		values, err := h.resolver.Lookup(ctx, record.Type, record.Host)
+		if err != nil {
+			return nil, err
+		}
+		checks[i] = domain.DNSRecordCheck{Record: record, Found: record.Matches(values)}
+	}
+
+	senderDomain.ApplyChecks(checks, time.Now())
+
+	err = h.senderDomainRepo.UpdateVerification(ctx, senderDomain)
+	if err != nil {
+		if err == domain.ErrSenderDomainTaken {
+			return nil, domain.ErrSenderDomainTaken
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to update sender domain")
+	}
+
+	return ToSenderDomainResult(senderDomain, h.platform, checks), nil
+}
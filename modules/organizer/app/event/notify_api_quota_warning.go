@@ -0,0 +1,72 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"tixgo/modules/organizer/domain"
+	templateDomain "tixgo/modules/template/domain"
+	sharedNotification "tixgo/shared/events/notification"
+	sharedKafka "tixgo/shared/kafka"
+
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/notification/mail"
+	"github.com/duongptryu/gox/syserr"
+)
+
+const (
+	SlugAPIQuotaWarning = "organizer-api-quota-warning"
+)
+
+type notifyAPIQuotaWarning struct {
+	templateRepo     templateDomain.TemplateRepository
+	templateRenderer templateDomain.TemplateRenderer
+	eventBus         messaging.EventBus
+}
+
+func NewNotifyAPIQuotaWarning(templateRepo templateDomain.TemplateRepository, templateRenderer templateDomain.TemplateRenderer, eventBus messaging.EventBus) *notifyAPIQuotaWarning {
+	return &notifyAPIQuotaWarning{
+		templateRepo:     templateRepo,
+		templateRenderer: templateRenderer,
+		eventBus:         eventBus,
+	}
+}
+
+// Notify mails the organizer that an API key used most of its quota, and when the quota resets
+func (h *notifyAPIQuotaWarning) Notify(ctx context.Context, event *domain.EventAPIQuotaWarning) error {
+	template, err := h.templateRepo.GetBySlug(ctx, SlugAPIQuotaWarning)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get template")
+	}
+
+	rendered, err := h.templateRenderer.Render(ctx, template, map[string]interface{}{
+		"key_name":     event.Name,
+		"key_prefix":   event.Prefix,
+		"plan":         string(event.Plan),
+		"period":       event.Period,
+		"requests":     event.Requests,
+		"quota":        event.Quota,
+		"used_percent": event.Requests * 100 / event.Quota,
+		"reset_at":     event.ResetAt.Format("2006-01-02"),
+	}, templateDomain.RenderOptions{})
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to render template")
+	}
+
+	key := strconv.FormatInt(event.OrganizerID, 10)
+	err = h.eventBus.PublishEvent(sharedKafka.WithPartitionKey(ctx, key), &sharedNotification.EventNotificationRequested{
+		UserID:   event.OrganizerID,
+		Category: "api_quota",
+		Title:    "API key " + event.Name + " is running out of requests",
+		Summary:  fmt.Sprintf("%d of %d requests used in %s", event.Requests, event.Quota, event.Period),
+		Subject:  rendered.Subject,
+		HTMLBody: rendered.Content,
+		Priority: mail.PriorityHigh,
+	})
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to publish notification event")
+	}
+
+	return nil
+}
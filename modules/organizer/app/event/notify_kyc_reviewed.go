@@ -0,0 +1,68 @@
+package event
+
+import (
+	"context"
+	"strconv"
+
+	"tixgo/modules/organizer/domain"
+	templateDomain "tixgo/modules/template/domain"
+	sharedNotification "tixgo/shared/events/notification"
+	sharedKafka "tixgo/shared/kafka"
+
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/notification/mail"
+	"github.com/duongptryu/gox/syserr"
+)
+
+const (
+	SlugKYCReviewed = "organizer-kyc-reviewed"
+)
+
+type notifyKYCReviewed struct {
+	templateRepo     templateDomain.TemplateRepository
+	templateRenderer templateDomain.TemplateRenderer
+	eventBus         messaging.EventBus
+}
+
+func NewNotifyKYCReviewed(templateRepo templateDomain.TemplateRepository, templateRenderer templateDomain.TemplateRenderer, eventBus messaging.EventBus) *notifyKYCReviewed {
+	return &notifyKYCReviewed{
+		templateRepo:     templateRepo,
+		templateRenderer: templateRenderer,
+		eventBus:         eventBus,
+	}
+}
+
+// Notify mails the organizer the decision on their KYC submission and, when rejected, the reason
+func (h *notifyKYCReviewed) Notify(ctx context.Context, event *domain.EventKYCReviewed) error {
+	template, err := h.templateRepo.GetBySlug(ctx, SlugKYCReviewed)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get template")
+	}
+
+	rendered, err := h.templateRenderer.Render(ctx, template, map[string]interface{}{
+		"submission_id":    event.SubmissionID,
+		"legal_name":       event.LegalName,
+		"status":           string(event.Status),
+		"approved":         event.Status == domain.KYCStatusApproved,
+		"rejection_reason": event.RejectionReason,
+	}, templateDomain.RenderOptions{})
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to render template")
+	}
+
+	key := strconv.FormatInt(event.OrganizerID, 10)
+	err = h.eventBus.PublishEvent(sharedKafka.WithPartitionKey(ctx, key), &sharedNotification.EventNotificationRequested{
+		UserID:   event.OrganizerID,
+		Category: "kyc_review",
+		Title:    "Business verification " + string(event.Status),
+		Summary:  event.RejectionReason,
+		Subject:  rendered.Subject,
+		HTMLBody: rendered.Content,
+		Priority: mail.PriorityHigh,
+	})
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to publish notification event")
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/organizer/app/command"
+	"tixgo/modules/organizer/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// GetKYCQuery represents the query of an organizer for their latest KYC submission
+type GetKYCQuery struct {
+	OrganizerID int64
+}
+
+// GetKYCHandler handles getting the KYC status of organizers
+type GetKYCHandler struct {
+	kycRepo domain.KYCRepository
+}
+
+// NewGetKYCHandler creates a new get KYC handler
+func NewGetKYCHandler(kycRepo domain.KYCRepository) *GetKYCHandler {
+	return &GetKYCHandler{
+		kycRepo: kycRepo,
+	}
+}
+
+// Handle returns the latest submission of the organizer, ErrKYCNotFound if they never submitted
+func (h *GetKYCHandler) Handle(ctx context.Context, query GetKYCQuery) (*command.KYCSubmissionResult, error) {
+	submission, err := h.kycRepo.GetLatestByOrganizerID(ctx, query.OrganizerID)
+	if err != nil {
+		if err == domain.ErrKYCNotFound {
+			return nil, domain.ErrKYCNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get KYC submission")
+	}
+
+	return command.ToKYCSubmissionResult(submission), nil
+}
@@ -0,0 +1,40 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/organizer/app/command"
+	"tixgo/modules/organizer/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// GetKYCSubmissionQuery represents the query of an admin for a KYC submission
+type GetKYCSubmissionQuery struct {
+	ID int64
+}
+
+// GetKYCSubmissionHandler handles getting KYC submissions
+type GetKYCSubmissionHandler struct {
+	kycRepo domain.KYCRepository
+}
+
+// NewGetKYCSubmissionHandler creates a new get KYC submission handler
+func NewGetKYCSubmissionHandler(kycRepo domain.KYCRepository) *GetKYCSubmissionHandler {
+	return &GetKYCSubmissionHandler{
+		kycRepo: kycRepo,
+	}
+}
+
+// Handle executes the get KYC submission query
+func (h *GetKYCSubmissionHandler) Handle(ctx context.Context, query GetKYCSubmissionQuery) (*command.KYCSubmissionResult, error) {
+	submission, err := h.kycRepo.GetByID(ctx, query.ID)
+	if err != nil {
+		if err == domain.ErrKYCNotFound {
+			return nil, domain.ErrKYCNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get KYC submission")
+	}
+
+	return command.ToKYCSubmissionResult(submission), nil
+}
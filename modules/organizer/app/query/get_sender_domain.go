@@ -0,0 +1,42 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/organizer/app/command"
+	"tixgo/modules/organizer/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// GetSenderDomainQuery represents the query to get the sender domain of an organizer
+type GetSenderDomainQuery struct {
+	OrganizerID int64
+}
+
+// GetSenderDomainHandler handles sender domain queries
+type GetSenderDomainHandler struct {
+	senderDomainRepo domain.SenderDomainRepository
+	platform         domain.SenderPlatform
+}
+
+// NewGetSenderDomainHandler creates a new get sender domain handler
+func NewGetSenderDomainHandler(senderDomainRepo domain.SenderDomainRepository, platform domain.SenderPlatform) *GetSenderDomainHandler {
+	return &GetSenderDomainHandler{
+		senderDomainRepo: senderDomainRepo,
+		platform:         platform,
+	}
+}
+
+// Handle executes the get sender domain query
+func (h *GetSenderDomainHandler) Handle(ctx context.Context, query GetSenderDomainQuery) (*command.SenderDomainResult, error) {
+	senderDomain, err := h.senderDomainRepo.GetByOrganizerID(ctx, query.OrganizerID)
+	if err != nil {
+		if err == domain.ErrSenderDomainNotFound {
+			return nil, domain.ErrSenderDomainNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get sender domain")
+	}
+
+	return command.ToSenderDomainResult(senderDomain, h.platform, nil), nil
+}
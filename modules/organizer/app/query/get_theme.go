@@ -0,0 +1,37 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/organizer/app/command"
+	"tixgo/modules/organizer/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// GetThemeQuery represents the query to get the theme of an organizer
+type GetThemeQuery struct {
+	OrganizerID int64
+}
+
+// GetThemeHandler handles theme queries
+type GetThemeHandler struct {
+	themeRepo domain.ThemeRepository
+}
+
+// NewGetThemeHandler creates a new get theme handler
+func NewGetThemeHandler(themeRepo domain.ThemeRepository) *GetThemeHandler {
+	return &GetThemeHandler{
+		themeRepo: themeRepo,
+	}
+}
+
+// Handle executes the get theme query, answering the platform theme for organizers without one
+func (h *GetThemeHandler) Handle(ctx context.Context, query GetThemeQuery) (*command.ThemeResult, error) {
+	theme, err := h.themeRepo.GetByOrganizerID(ctx, query.OrganizerID)
+	if err != nil && err != domain.ErrThemeNotFound {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get theme")
+	}
+
+	return command.ToThemeResult(theme), nil
+}
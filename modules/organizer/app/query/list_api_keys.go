@@ -0,0 +1,42 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/organizer/app/command"
+	"tixgo/modules/organizer/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ListAPIKeysQuery represents the query to list the API keys of an organizer
+type ListAPIKeysQuery struct {
+	OrganizerID int64
+}
+
+// ListAPIKeysHandler handles API key queries
+type ListAPIKeysHandler struct {
+	apiKeyRepo domain.APIKeyRepository
+}
+
+// NewListAPIKeysHandler creates a new list API keys handler
+func NewListAPIKeysHandler(apiKeyRepo domain.APIKeyRepository) *ListAPIKeysHandler {
+	return &ListAPIKeysHandler{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// Handle executes the list API keys query, revoked keys included
+func (h *ListAPIKeysHandler) Handle(ctx context.Context, query ListAPIKeysQuery) ([]*command.APIKeyResult, error) {
+	keys, err := h.apiKeyRepo.ListByOrganizerID(ctx, query.OrganizerID)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list API keys")
+	}
+
+	results := make([]*command.APIKeyResult, len(keys))
+	for i, key := range keys {
+		results[i] = command.ToAPIKeyResult(key)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,112 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/organizer/domain"
+	"tixgo/shared/listing"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// FilterAPIUsageQuery represents the filters for listing the usage of API keys
+type FilterAPIUsageQuery struct {
+	OrganizerID *int64 `json:"organizer_id" form:"organizer_id"`
+	// Period is the month of the usage, e.g. 2026-10, the current one when left out
+	Period string `json:"period" form:"period" binding:"omitempty,datetime=2006-01"`
+}
+
+// APIKeyUsageResult represents the usage of an API key in a period against its quota
+type APIKeyUsageResult struct {
+	APIKeyID     int64          `json:"api_key_id"`
+	OrganizerID  int64          `json:"organizer_id"`
+	Name         string         `json:"name"`
+	Prefix       string         `json:"prefix"`
+	Plan         domain.APIPlan `json:"plan"`
+	Period       string         `json:"period"`
+	Requests     int64          `json:"requests"`
+	MonthlyQuota int64          `json:"monthly_quota"`
+	Remaining    int64          `json:"remaining"`
+	UsedPercent  int64          `json:"used_percent"`
+	Revoked      bool           `json:"revoked"`
+}
+
+// ListAPIUsageHandler handles the usage summaries of API keys, for organizers and admins
+type ListAPIUsageHandler struct {
+	usageRepo    domain.APIUsageRepository
+	usageCounter domain.APIUsageCounter
+}
+
+// NewListAPIUsageHandler creates a new list API usage handler
+func NewListAPIUsageHandler(usageRepo domain.APIUsageRepository, usageCounter domain.APIUsageCounter) *ListAPIUsageHandler {
+	return &ListAPIUsageHandler{
+		usageRepo:    usageRepo,
+		usageCounter: usageCounter,
+	}
+}
+
+// Handle executes the list API usage query. The usage of the current period is saved every minute,
+// the live counts are used instead when the counter has them.
+func (h *ListAPIUsageHandler) Handle(ctx context.Context, filters *FilterAPIUsageQuery, paging *listing.Paging) ([]*APIKeyUsageResult, error) {
+	// Ensure paging is not nil (should already be handled in HTTP layer)
+	if paging == nil {
+		paging = &listing.Paging{}
+		paging.Fulfill()
+	}
+
+	current := domain.UsagePeriod(time.Now())
+	period := current
+	if filters.Period != "" {
+		parsed, err := time.Parse(domain.APIUsagePeriodLayout, filters.Period)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InvalidArgumentCode, "the period must be a month like 2026-10")
+		}
+		period = parsed
+	}
+	filters.Period = period.Format(domain.APIUsagePeriodLayout)
+
+	usages, err := h.usageRepo.List(ctx, domain.ListAPIUsageFilters{OrganizerID: filters.OrganizerID, Period: period}, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list API usage")
+	}
+
+	if period.Equal(current) && len(usages) > 0 {
+		keyIDs := make([]int64, len(usages))
+		for i, usage := range usages {
+			keyIDs[i] = usage.Key.ID
+		}
+
+		live, err := h.usageCounter.Get(ctx, keyIDs, period)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get API usage")
+		}
+		for _, usage := range usages {
+			usage.Requests = max(usage.Requests, live[usage.Key.ID])
+		}
+	}
+
+	results := make([]*APIKeyUsageResult, len(usages))
+	for i, usage := range usages {
+		results[i] = toAPIKeyUsageResult(usage)
+	}
+
+	return results, nil
+}
+
+func toAPIKeyUsageResult(usage *domain.APIKeyUsage) *APIKeyUsageResult {
+	quota := domain.NewAPIQuota(usage.Key, usage.Period, usage.Requests)
+	return &APIKeyUsageResult{
+		APIKeyID:     usage.Key.ID,
+		OrganizerID:  usage.Key.OrganizerID,
+		Name:         usage.Key.Name,
+		Prefix:       usage.Key.Prefix,
+		Plan:         usage.Key.Plan,
+		Period:       usage.Period.Format(domain.APIUsagePeriodLayout),
+		Requests:     usage.Requests,
+		MonthlyQuota: quota.Limit,
+		Remaining:    quota.Remaining(),
+		UsedPercent:  usage.Requests * 100 / quota.Limit,
+		Revoked:      usage.Key.RevokedAt != nil,
+	}
+}
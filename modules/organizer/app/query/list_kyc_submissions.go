@@ -0,0 +1,63 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/organizer/app/command"
+	"tixgo/modules/organizer/domain"
+	"tixgo/shared/listing"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// FilterKYCSubmissionsQuery represents the filters for listing KYC submissions
+type FilterKYCSubmissionsQuery struct {
+	OrganizerID *int64  `json:"organizer_id" form:"organizer_id"`
+	Status      *string `json:"status" form:"status"`
+}
+
+// ListKYCSubmissionsHandler handles listing KYC submissions, the review queue of admins
+type ListKYCSubmissionsHandler struct {
+	kycRepo domain.KYCRepository
+}
+
+// NewListKYCSubmissionsHandler creates a new list KYC submissions handler
+func NewListKYCSubmissionsHandler(kycRepo domain.KYCRepository) *ListKYCSubmissionsHandler {
+	return &ListKYCSubmissionsHandler{
+		kycRepo: kycRepo,
+	}
+}
+
+// Handle executes the list KYC submissions query, the submissions come without their documents
+func (h *ListKYCSubmissionsHandler) Handle(ctx context.Context, filters *FilterKYCSubmissionsQuery, paging *listing.Paging) ([]*command.KYCSubmissionResult, error) {
+	// Ensure paging is not nil (should already be handled in HTTP layer)
+	if paging == nil {
+		paging = &listing.Paging{}
+		paging.Fulfill()
+	}
+
+	domainFilters := domain.ListKYCFilters{
+		OrganizerID: filters.OrganizerID,
+	}
+
+	// Set status filter
+	if filters.Status != nil && *filters.Status != "" {
+		if !domain.IsValidKYCStatus(*filters.Status) {
+			return nil, domain.ErrInvalidKYCStatus
+		}
+		status := domain.KYCStatus(*filters.Status)
+		domainFilters.Status = &status
+	}
+
+	submissions, err := h.kycRepo.List(ctx, domainFilters, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list KYC submissions")
+	}
+
+	items := make([]*command.KYCSubmissionResult, len(submissions))
+	for i, submission := range submissions {
+		items[i] = command.ToKYCSubmissionResult(submission)
+	}
+
+	return items, nil
+}
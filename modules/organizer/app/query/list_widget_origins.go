@@ -0,0 +1,42 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/organizer/app/command"
+	"tixgo/modules/organizer/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ListWidgetOriginsQuery represents the query to list the sites an organizer embeds the checkout widget on
+type ListWidgetOriginsQuery struct {
+	OrganizerID int64
+}
+
+// ListWidgetOriginsHandler handles widget origin queries
+type ListWidgetOriginsHandler struct {
+	widgetOriginRepo domain.WidgetOriginRepository
+}
+
+// NewListWidgetOriginsHandler creates a new list widget origins handler
+func NewListWidgetOriginsHandler(widgetOriginRepo domain.WidgetOriginRepository) *ListWidgetOriginsHandler {
+	return &ListWidgetOriginsHandler{
+		widgetOriginRepo: widgetOriginRepo,
+	}
+}
+
+// Handle executes the list widget origins query
+func (h *ListWidgetOriginsHandler) Handle(ctx context.Context, query ListWidgetOriginsQuery) ([]*command.WidgetOriginResult, error) {
+	origins, err := h.widgetOriginRepo.ListByOrganizerID(ctx, query.OrganizerID)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list widget origins")
+	}
+
+	results := make([]*command.WidgetOriginResult, len(origins))
+	for i, origin := range origins {
+		results[i] = command.ToWidgetOriginResult(origin)
+	}
+
+	return results, nil
+}
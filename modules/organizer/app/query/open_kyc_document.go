@@ -0,0 +1,63 @@
+package query
+
+import (
+	"context"
+	"io"
+
+	"tixgo/modules/organizer/domain"
+	"tixgo/shared/storage"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// OpenKYCDocumentQuery represents the query of an admin for the file of a submitted document
+type OpenKYCDocumentQuery struct {
+	SubmissionID int64
+	DocumentID   int64
+}
+
+// KYCDocumentFile is a document with its content, which the caller must close
+type KYCDocumentFile struct {
+	Document *domain.KYCDocument
+	Content  io.ReadSeekCloser
+}
+
+// OpenKYCDocumentHandler handles reading the files of KYC documents
+type OpenKYCDocumentHandler struct {
+	kycRepo domain.KYCRepository
+	store   storage.Store
+}
+
+// NewOpenKYCDocumentHandler creates a new open KYC document handler
+func NewOpenKYCDocumentHandler(kycRepo domain.KYCRepository, store storage.Store) *OpenKYCDocumentHandler {
+	return &OpenKYCDocumentHandler{
+		kycRepo: kycRepo,
+		store:   store,
+	}
+}
+
+// Handle opens the file of a document of the submission
+func (h *OpenKYCDocumentHandler) Handle(ctx context.Context, query OpenKYCDocumentQuery) (*KYCDocumentFile, error) {
+	submission, err := h.kycRepo.GetByID(ctx, query.SubmissionID)
+	if err != nil {
+		if err == domain.ErrKYCNotFound {
+			return nil, domain.ErrKYCNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get KYC submission")
+	}
+
+	document, ok := submission.Document(query.DocumentID)
+	if !ok {
+		return nil, domain.ErrKYCDocumentNotFound
+	}
+
+	content, err := h.store.Open(ctx, document.StorageKey)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			return nil, domain.ErrKYCDocumentNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to open KYC document")
+	}
+
+	return &KYCDocumentFile{Document: document, Content: content}, nil
+}
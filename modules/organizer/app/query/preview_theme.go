@@ -0,0 +1,102 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/organizer/app/command"
+	"tixgo/modules/organizer/domain"
+	templateDomain "tixgo/modules/template/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// PreviewThemeQuery represents the query to render an attendee-facing mail with the theme of an organizer
+type PreviewThemeQuery struct {
+	OrganizerID  int64                  `json:"-"`
+	TemplateSlug string                 `json:"template_slug" binding:"required,max=100"`
+	Variables    map[string]interface{} `json:"variables"`
+	// Theme is previewed in place of the saved theme of the organizer, to try it before saving it
+	Theme *command.ThemeInput `json:"theme"`
+	// Locale and TimeZone format the dates and amounts of the render, see templateDomain.RenderOptions
+	Locale   string `json:"locale"`
+	TimeZone string `json:"time_zone"`
+}
+
+// PreviewThemeResult represents a mail rendered with a theme
+type PreviewThemeResult struct {
+	Subject     string `json:"subject"`
+	Content     string `json:"content"`
+	ContentType string `json:"content_type"`
+}
+
+// PreviewThemeHandler handles theme previews
+type PreviewThemeHandler struct {
+	themeRepo        domain.ThemeRepository
+	templateRepo     templateDomain.TemplateRepository
+	templateRenderer templateDomain.TemplateRenderer
+}
+
+// NewPreviewThemeHandler creates a new preview theme handler
+func NewPreviewThemeHandler(themeRepo domain.ThemeRepository, templateRepo templateDomain.TemplateRepository, templateRenderer templateDomain.TemplateRenderer) *PreviewThemeHandler {
+	return &PreviewThemeHandler{
+		themeRepo:        themeRepo,
+		templateRepo:     templateRepo,
+		templateRenderer: templateRenderer,
+	}
+}
+
+// Handle executes the preview theme query. Only active email templates can be previewed.
+func (h *PreviewThemeHandler) Handle(ctx context.Context, query PreviewThemeQuery) (*PreviewThemeResult, error) {
+	theme, err := h.theme(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := h.templateRepo.GetBySlug(ctx, query.TemplateSlug)
+	if err != nil {
+		if err == templateDomain.ErrTemplateNotFound {
+			return nil, templateDomain.ErrTemplateNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get template")
+	}
+	if template.Type != templateDomain.TemplateTypeEmail {
+		return nil, templateDomain.ErrTemplateNotRenderable
+	}
+	if !template.IsActive() {
+		return nil, templateDomain.ErrTemplateInactive
+	}
+
+	rendered, err := h.templateRenderer.Render(ctx, template, query.Variables, templateDomain.RenderOptions{
+		Locale:   query.Locale,
+		TimeZone: query.TimeZone,
+		Theme:    command.ToTemplateTheme(theme),
+	})
+	if err != nil {
+		if err == templateDomain.ErrInvalidTimeZone {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to render template")
+	}
+
+	return &PreviewThemeResult{
+		Subject:     rendered.Subject,
+		Content:     rendered.Content,
+		ContentType: rendered.ContentType,
+	}, nil
+}
+
+// theme returns the theme to preview: the one of the query, else the saved one, nil for the platform theme
+func (h *PreviewThemeHandler) theme(ctx context.Context, query PreviewThemeQuery) (*domain.Theme, error) {
+	if query.Theme != nil {
+		return query.Theme.NewTheme(query.OrganizerID)
+	}
+
+	theme, err := h.themeRepo.GetByOrganizerID(ctx, query.OrganizerID)
+	if err == domain.ErrThemeNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get theme")
+	}
+	return theme, nil
+}
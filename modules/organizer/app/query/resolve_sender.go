@@ -0,0 +1,38 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/organizer/domain"
+
+	"github.com/duongptryu/gox/notification/mail"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ResolveSenderHandler finds the identity the attendee-facing mails of an organizer are sent from
+type ResolveSenderHandler struct {
+	senderDomainRepo domain.SenderDomainRepository
+}
+
+// NewResolveSenderHandler creates a new resolve sender handler
+func NewResolveSenderHandler(senderDomainRepo domain.SenderDomainRepository) *ResolveSenderHandler {
+	return &ResolveSenderHandler{
+		senderDomainRepo: senderDomainRepo,
+	}
+}
+
+// ResolveSender returns the verified sender of an organizer, or nil for the platform identity
+func (h *ResolveSenderHandler) ResolveSender(ctx context.Context, organizerID int64) (*mail.EmailAddress, error) {
+	senderDomain, err := h.senderDomainRepo.GetByOrganizerID(ctx, organizerID)
+	if err == domain.ErrSenderDomainNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get sender domain")
+	}
+
+	if !senderDomain.IsVerified() {
+		return nil, nil
+	}
+	return &mail.EmailAddress{Email: senderDomain.FromEmail, Name: senderDomain.FromName}, nil
+}
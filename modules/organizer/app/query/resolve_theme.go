@@ -0,0 +1,36 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/organizer/app/command"
+	"tixgo/modules/organizer/domain"
+	templateDomain "tixgo/modules/template/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ResolveThemeHandler finds the theme attendee-facing templates of an organizer render with
+type ResolveThemeHandler struct {
+	themeRepo domain.ThemeRepository
+}
+
+// NewResolveThemeHandler creates a new resolve theme handler
+func NewResolveThemeHandler(themeRepo domain.ThemeRepository) *ResolveThemeHandler {
+	return &ResolveThemeHandler{
+		themeRepo: themeRepo,
+	}
+}
+
+// ResolveTheme returns the theme of an organizer, or nil for the platform theme
+func (h *ResolveThemeHandler) ResolveTheme(ctx context.Context, organizerID int64) (*templateDomain.Theme, error) {
+	theme, err := h.themeRepo.GetByOrganizerID(ctx, organizerID)
+	if err == domain.ErrThemeNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get theme")
+	}
+
+	return command.ToTemplateTheme(theme), nil
+}
@@ -0,0 +1,271 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"tixgo/shared/listing"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// APIPlan is the plan of an API key, it sets how many requests the key makes per month
+type APIPlan string
+
+const (
+	APIPlanFree     APIPlan = "free"
+	APIPlanStandard APIPlan = "standard"
+	APIPlanBusiness APIPlan = "business"
+)
+
+// apiPlanQuotas are the requests per month of each plan
+var apiPlanQuotas = map[APIPlan]int64{
+	APIPlanFree:     10_000,
+	APIPlanStandard: 100_000,
+	APIPlanBusiness: 1_000_000,
+}
+
+// IsValidAPIPlan checks if the plan is valid
+func IsValidAPIPlan(plan string) bool {
+	_, ok := apiPlanQuotas[APIPlan(plan)]
+	return ok
+}
+
+// Quota returns the requests per month of the plan
+func (p APIPlan) Quota() int64 {
+	return apiPlanQuotas[p]
+}
+
+const (
+	// MaxAPIKeys bounds the active API keys of an organizer
+	MaxAPIKeys = 10
+
+	// APIKeyHeader is the header requests authenticate with an API key in
+	APIKeyHeader = "X-API-Key"
+
+	// apiKeySecretPrefix starts every API key so leaked keys are easy to spot and scan for
+	apiKeySecretPrefix = "tixgo_"
+	// apiKeyTestSecretPrefix starts test mode keys instead, so they are told apart at a glance
+	apiKeyTestSecretPrefix = "tixgo_test_"
+	// apiKeyDisplayLength is how much of a key is kept in clear to tell keys apart, after its prefix
+	apiKeyDisplayLength = 6
+
+	// APIQuotaWarningPercent is the share of its quota a key uses before its organizer is warned
+	APIQuotaWarningPercent = 80
+)
+
+// APIKey lets an organizer call the API from their own systems instead of with a session. Only the
+// hash of the key is stored, the key itself is shown once when created.
+type APIKey struct {
+	ID          int64
+	OrganizerID int64
+	Name        string
+	Prefix      string
+	Hash        string
+	Plan        APIPlan
+	// QuotaOverride replaces the quota of the plan when an admin set one
+	QuotaOverride *int64
+	// TestMode keys create test mode events, whose orders are paid with fake payments
+	TestMode   bool
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// NewAPIKey creates an API key of an organizer on the free plan, along with the key to hand them
+func NewAPIKey(organizerID int64, name string, testMode bool) (*APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", syserr.New(syserr.InvalidArgumentCode, "name is required")
+	}
+
+	random := make([]byte, 24)
+	if _, err := rand.Read(random); err != nil {
+		return nil, "", syserr.Wrap(err, syserr.InternalCode, "failed to generate API key")
+	}
+	prefix := apiKeySecretPrefix
+	if testMode {
+		prefix = apiKeyTestSecretPrefix
+	}
+	secret := prefix + hex.EncodeToString(random)
+
+	return &APIKey{
+		OrganizerID: organizerID,
+		Name:        name,
+		Prefix:      secret[:len(prefix)+apiKeyDisplayLength],
+		Hash:        HashAPIKey(secret),
+		Plan:        APIPlanFree,
+		TestMode:    testMode,
+	}, secret, nil
+}
+
+// HashAPIKey returns the hash an API key is stored and looked up by
+func HashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// MonthlyQuota returns the requests the key makes per month
+func (k *APIKey) MonthlyQuota() int64 {
+	if k.QuotaOverride != nil {
+		return *k.QuotaOverride
+	}
+	return k.Plan.Quota()
+}
+
+// SetPlan moves the key to a plan, quota overriding the quota of the plan when not nil
+func (k *APIKey) SetPlan(plan APIPlan, quota *int64) error {
+	if !IsValidAPIPlan(string(plan)) {
+		return ErrInvalidAPIPlan
+	}
+	if quota != nil && *quota <= 0 {
+		return syserr.New(syserr.InvalidArgumentCode, "the quota must be positive")
+	}
+
+	k.Plan = plan
+	k.QuotaOverride = quota
+	return nil
+}
+
+// UsagePeriod returns the period the usage at t counts in, the first day of its month in UTC
+func UsagePeriod(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// APIQuota is where a key stands against its quota in a period
+type APIQuota struct {
+	Limit    int64
+	Used     int64
+	PeriodAt time.Time
+}
+
+// NewAPIQuota returns the quota of the key in the period with used requests made
+func NewAPIQuota(key *APIKey, period time.Time, used int64) APIQuota {
+	return APIQuota{Limit: key.MonthlyQuota(), Used: used, PeriodAt: period}
+}
+
+// Remaining returns the requests left in the period
+func (q APIQuota) Remaining() int64 {
+	return max(q.Limit-q.Used, 0)
+}
+
+// Exceeded tells whether the key made more requests than its quota
+func (q APIQuota) Exceeded() bool {
+	return q.Used > q.Limit
+}
+
+// ResetAt returns when the next period starts and the quota is whole again
+func (q APIQuota) ResetAt() time.Time {
+	return q.PeriodAt.AddDate(0, 1, 0)
+}
+
+// WarningThreshold returns the requests from which the organizer is warned the quota runs out
+func (q APIQuota) WarningThreshold() int64 {
+	return (q.Limit*APIQuotaWarningPercent + 99) / 100
+}
+
+// ReachedWarning tells whether the key used APIQuotaWarningPercent of its quota
+func (q APIQuota) ReachedWarning() bool {
+	return q.Used >= q.WarningThreshold()
+}
+
+// EventAPIQuotaWarning is published once per period when a key used APIQuotaWarningPercent of its quota
+type EventAPIQuotaWarning struct {
+	APIKeyID    int64     `json:"api_key_id"`
+	OrganizerID int64     `json:"organizer_id"`
+	Name        string    `json:"name"`
+	Prefix      string    `json:"prefix"`
+	Plan        APIPlan   `json:"plan"`
+	Period      string    `json:"period"`
+	Requests    int64     `json:"requests"`
+	Quota       int64     `json:"quota"`
+	ResetAt     time.Time `json:"reset_at"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
+
+// NewEventAPIQuotaWarning creates the warning event of a key
+func NewEventAPIQuotaWarning(key *APIKey, quota APIQuota) *EventAPIQuotaWarning {
+	return &EventAPIQuotaWarning{
+		APIKeyID:    key.ID,
+		OrganizerID: key.OrganizerID,
+		Name:        key.Name,
+		Prefix:      key.Prefix,
+		Plan:        key.Plan,
+		Period:      quota.PeriodAt.Format(APIUsagePeriodLayout),
+		Requests:    quota.Used,
+		Quota:       quota.Limit,
+		ResetAt:     quota.ResetAt(),
+		OccurredAt:  time.Now(),
+	}
+}
+
+// APIUsagePeriodLayout is how periods are written, e.g. 2026-10
+const APIUsagePeriodLayout = "2006-01"
+
+// APIUsageCount is the requests a key made in a period
+type APIUsageCount struct {
+	APIKeyID int64
+	Period   time.Time
+	Requests int64
+}
+
+// APIKeyUsage is the usage of a key in a period, a line of the usage summaries
+type APIKeyUsage struct {
+	Key      *APIKey
+	Period   time.Time
+	Requests int64
+}
+
+// ListAPIUsageFilters represents filters for listing the usage of API keys in a period
+type ListAPIUsageFilters struct {
+	OrganizerID *int64
+	Period      time.Time
+}
+
+// APIKeyRepository defines the interface for API key persistence
+type APIKeyRepository interface {
+	// Create stores a key, failing with ErrTooManyAPIKeys if the organizer has MaxAPIKeys active keys
+	Create(ctx context.Context, key *APIKey) error
+
+	// ListByOrganizerID retrieves the keys of an organizer, revoked ones included, oldest first
+	ListByOrganizerID(ctx context.Context, organizerID int64) ([]*APIKey, error)
+
+	// GetByID retrieves a key
+	GetByID(ctx context.Context, id int64) (*APIKey, error)
+
+	// GetByHash retrieves an active key, failing with ErrInvalidAPIKey if it is unknown or revoked
+	GetByHash(ctx context.Context, hash string) (*APIKey, error)
+
+	// Revoke revokes an active key of an organizer, it stops authenticating right away
+	Revoke(ctx context.Context, organizerID, id int64) error
+
+	// SavePlan saves the plan and quota override of a key
+	SavePlan(ctx context.Context, key *APIKey) error
+}
+
+// APIUsageRepository keeps the usage of the keys per period once saved from the counter
+type APIUsageRepository interface {
+	// Save records the counts, never lowering a count already saved, and marks the keys with new
+	// requests as used
+	Save(ctx context.Context, counts []APIUsageCount) error
+
+	// List retrieves the usage in the period of the keys active during it with pagination, most used first
+	List(ctx context.Context, filters ListAPIUsageFilters, paging *listing.Paging) ([]*APIKeyUsage, error)
+}
+
+// APIUsageCounter counts the requests of the keys as they are made, shared by every instance of the API
+type APIUsageCounter interface {
+	// Increment counts a request of the key in the period and returns the requests made so far
+	Increment(ctx context.Context, keyID int64, period time.Time) (int64, error)
+
+	// Get returns the requests counted in the period for the keys, keys without any being left out
+	Get(ctx context.Context, keyIDs []int64, period time.Time) (map[int64]int64, error)
+
+	// List returns every count of the period
+	List(ctx context.Context, period time.Time) ([]APIUsageCount, error)
+}
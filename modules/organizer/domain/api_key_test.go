@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAPIKey(t *testing.T) {
+	key, secret, err := NewAPIKey(7, "  Box office sync ", false)
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(secret, "tixgo_"))
+	assert.Equal(t, "Box office sync", key.Name)
+	assert.Equal(t, secret[:len(key.Prefix)], key.Prefix)
+	assert.Equal(t, HashAPIKey(secret), key.Hash)
+	assert.NotContains(t, key.Hash, secret)
+	assert.Equal(t, APIPlanFree, key.Plan)
+	assert.Equal(t, int64(10_000), key.MonthlyQuota())
+
+	_, other, err := NewAPIKey(7, "other", false)
+	require.NoError(t, err)
+	assert.NotEqual(t, secret, other)
+
+	_, _, err = NewAPIKey(7, " ", false)
+	assert.Error(t, err)
+}
+
+func TestNewAPIKey_TestMode(t *testing.T) {
+	live, _, err := NewAPIKey(7, "live", false)
+	require.NoError(t, err)
+	assert.False(t, live.TestMode)
+	assert.False(t, strings.HasPrefix(live.Prefix, "tixgo_test_"))
+
+	key, secret, err := NewAPIKey(7, "staging", true)
+	require.NoError(t, err)
+	assert.True(t, key.TestMode)
+	assert.True(t, strings.HasPrefix(secret, "tixgo_test_"), "test keys are told apart by their prefix")
+	assert.Len(t, key.Prefix, len(live.Prefix)+len("test_"), "test keys keep as much of their key in clear")
+	assert.Equal(t, secret[:len(key.Prefix)], key.Prefix)
+}
+
+func TestAPIKey_SetPlan(t *testing.T) {
+	key := &APIKey{Plan: APIPlanFree}
+
+	require.NoError(t, key.SetPlan(APIPlanBusiness, nil))
+	assert.Equal(t, int64(1_000_000), key.MonthlyQuota())
+
+	quota := int64(250_000)
+	require.NoError(t, key.SetPlan(APIPlanStandard, &quota))
+	assert.Equal(t, quota, key.MonthlyQuota(), "the override wins over the plan")
+
+	assert.Equal(t, ErrInvalidAPIPlan, key.SetPlan("enterprise", nil))
+	zero := int64(0)
+	assert.Error(t, key.SetPlan(APIPlanStandard, &zero))
+}
+
+func TestAPIQuota(t *testing.T) {
+	period := UsagePeriod(time.Date(2026, 12, 31, 23, 30, 0, 0, time.FixedZone("UTC-2", -2*3600)))
+	assert.Equal(t, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), period, "periods are months in UTC")
+
+	key := &APIKey{Plan: APIPlanFree}
+
+	quota := NewAPIQuota(key, period, 7_999)
+	assert.False(t, quota.ReachedWarning())
+	assert.Equal(t, int64(2_001), quota.Remaining())
+	assert.Equal(t, time.Date(2027, 2, 1, 0, 0, 0, 0, time.UTC), quota.ResetAt())
+
+	quota = NewAPIQuota(key, period, 8_000)
+	assert.True(t, quota.ReachedWarning())
+	assert.False(t, quota.Exceeded())
+
+	quota = NewAPIQuota(key, period, 10_000)
+	assert.False(t, quota.Exceeded(), "the last request of the quota goes through")
+	assert.Zero(t, quota.Remaining())
+
+	quota = NewAPIQuota(key, period, 10_001)
+	assert.True(t, quota.Exceeded())
+	assert.Zero(t, quota.Remaining())
+
+	odd := int64(7)
+	key.QuotaOverride = &odd
+	assert.Equal(t, int64(6), NewAPIQuota(key, period, 0).WarningThreshold(), "the threshold rounds up")
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"tixgo/shared/errstack"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// QuotaExceededCode is the error code of requests of an API key past its monthly quota
+const QuotaExceededCode syserr.Code = "quota_exceeded"
+
+// Organizer domain errors
+var (
+	ErrSenderDomainNotFound = errstack.Sentinel(syserr.NotFoundCode, "no sender domain configured")
+	ErrInvalidSenderDomain  = errstack.Sentinel(syserr.InvalidArgumentCode, "invalid sender domain")
+	ErrFromEmailNotOnDomain = errstack.Sentinel(syserr.InvalidArgumentCode, "the from address must belong to the sender domain")
+	ErrSenderDomainTaken    = errstack.Sentinel(syserr.ConflictCode, "the domain is already verified by another organizer")
+
+	ErrInvalidWidgetOrigin    = errstack.Sentinel(syserr.InvalidArgumentCode, "the origin must be an https scheme, host and optional port")
+	ErrWidgetOriginNotFound   = errstack.Sentinel(syserr.NotFoundCode, "widget origin not found")
+	ErrWidgetOriginExists     = errstack.Sentinel(syserr.ConflictCode, "the origin is already registered")
+	ErrTooManyWidgetOrigins   = errstack.Sentinel(syserr.InvalidArgumentCode, "too many widget origins, remove one first")
+	ErrWidgetOriginNotAllowed = errstack.Sentinel(syserr.ForbiddenCode, "the origin is not allowed to embed the checkout of this event")
+	ErrInvalidWidgetToken     = errstack.Sentinel(syserr.UnauthorizedCode, "invalid or expired widget token")
+
+	ErrInvalidAPIKey    = errstack.Sentinel(syserr.UnauthorizedCode, "invalid or revoked API key")
+	ErrAPIKeyNotFound   = errstack.Sentinel(syserr.NotFoundCode, "API key not found")
+	ErrTooManyAPIKeys   = errstack.Sentinel(syserr.InvalidArgumentCode, "too many API keys, revoke one first")
+	ErrInvalidAPIPlan   = errstack.Sentinel(syserr.InvalidArgumentCode, "invalid API plan")
+	ErrAPIKeyNotAllowed = errstack.Sentinel(syserr.ForbiddenCode, "API keys cannot manage API keys, sign in instead")
+	ErrAPIQuotaExceeded = errstack.Sentinel(QuotaExceededCode, "the monthly request quota of the API key is used up")
+
+	ErrKYCNotFound                = errstack.Sentinel(syserr.NotFoundCode, "KYC submission not found")
+	ErrKYCDocumentNotFound        = errstack.Sentinel(syserr.NotFoundCode, "KYC document not found")
+	ErrInvalidKYCStatus           = errstack.Sentinel(syserr.InvalidArgumentCode, "invalid KYC status")
+	ErrInvalidKYCDocumentType     = errstack.Sentinel(syserr.InvalidArgumentCode, "invalid KYC document type")
+	ErrInvalidKYCDocumentFormat   = errstack.Sentinel(syserr.InvalidArgumentCode, "KYC documents must be PDF, JPEG or PNG files")
+	ErrKYCDocumentTooLarge        = errstack.Sentinel(syserr.InvalidArgumentCode, "KYC documents must not exceed 10 MB")
+	ErrKYCDocumentMissing         = errstack.Sentinel(syserr.InvalidArgumentCode, "an identity and a business registration document are required")
+	ErrKYCAlreadySubmitted        = errstack.Sentinel(syserr.ConflictCode, "a KYC submission is already pending or approved")
+	ErrKYCNotPending              = errstack.Sentinel(syserr.ConflictCode, "the KYC submission was already reviewed")
+	ErrKYCRejectionReasonRequired = errstack.Sentinel(syserr.InvalidArgumentCode, "a reason is required to reject a KYC submission")
+	ErrKYCNotApproved             = errstack.Sentinel(syserr.ForbiddenCode, "the organizer must pass KYC verification first")
+
+	ErrThemeNotFound      = errstack.Sentinel(syserr.NotFoundCode, "no theme configured")
+	ErrInvalidThemeColor  = errstack.Sentinel(syserr.InvalidArgumentCode, "theme colors must be hex colors like #1f2937")
+	ErrInvalidThemeLogo   = errstack.Sentinel(syserr.InvalidArgumentCode, "the theme logo must be an https URL of at most 500 characters")
+	ErrThemeFooterTooLong = errstack.Sentinel(syserr.InvalidArgumentCode, "the theme footer must not exceed 300 characters")
+)
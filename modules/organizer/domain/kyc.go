@@ -0,0 +1,226 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"tixgo/shared/listing"
+)
+
+// KYCStatus represents the review status of a KYC submission
+type KYCStatus string
+
+const (
+	KYCStatusPending  KYCStatus = "pending"
+	KYCStatusApproved KYCStatus = "approved"
+	// KYCStatusRejected submissions can be followed by a new one fixing what the admin pointed out
+	KYCStatusRejected KYCStatus = "rejected"
+)
+
+// IsValidKYCStatus checks if the KYC status is valid
+func IsValidKYCStatus(status string) bool {
+	switch KYCStatus(status) {
+	case KYCStatusPending, KYCStatusApproved, KYCStatusRejected:
+		return true
+	default:
+		return false
+	}
+}
+
+// KYCDocumentType tells what a KYC document proves
+type KYCDocumentType string
+
+const (
+	// KYCDocumentIdentity is the ID card or passport of the legal representative
+	KYCDocumentIdentity KYCDocumentType = "identity"
+	// KYCDocumentBusinessRegistration is the certificate of registration of the business
+	KYCDocumentBusinessRegistration KYCDocumentType = "business_registration"
+	// KYCDocumentProofOfAddress is a recent bill or statement at the address of the business
+	KYCDocumentProofOfAddress KYCDocumentType = "proof_of_address"
+)
+
+// RequiredKYCDocuments are the document types every submission must include
+var RequiredKYCDocuments = []KYCDocumentType{KYCDocumentIdentity, KYCDocumentBusinessRegistration}
+
+// IsValidKYCDocumentType checks if the KYC document type is valid
+func IsValidKYCDocumentType(documentType string) bool {
+	switch KYCDocumentType(documentType) {
+	case KYCDocumentIdentity, KYCDocumentBusinessRegistration, KYCDocumentProofOfAddress:
+		return true
+	default:
+		return false
+	}
+}
+
+const (
+	// MaxKYCDocumentSize is the largest document accepted, scans of a few pages
+	MaxKYCDocumentSize = 10 << 20
+)
+
+// KYCDocumentContentTypes are the formats accepted for documents, sniffed from their content
+var KYCDocumentContentTypes = []string{"application/pdf", "image/jpeg", "image/png"}
+
+// KYCDocument is a file uploaded by an organizer, attached to the submission it is part of once submitted
+type KYCDocument struct {
+	ID           int64
+	OrganizerID  int64
+	SubmissionID *int64
+	Type         KYCDocumentType
+	FileName     string
+	ContentType  string
+	Size         int64
+	// StorageKey is where the file is kept in the storage
+	StorageKey string
+	CreatedAt  time.Time
+}
+
+// KYCBusinessInfo are the business details an organizer declares
+type KYCBusinessInfo struct {
+	LegalName          string
+	RegistrationNumber string
+	TaxID              string
+	Address            string
+	// Country is the ISO 3166-1 alpha-2 code of the country the business is registered in
+	Country string
+}
+
+// KYCSubmission is the business details and documents of an organizer, reviewed by an admin before the
+// organizer can receive payouts
+type KYCSubmission struct {
+	ID          int64
+	OrganizerID int64
+	KYCBusinessInfo
+	Documents       []*KYCDocument
+	Status          KYCStatus
+	ReviewedBy      *int64
+	RejectionReason string
+	SubmittedAt     time.Time
+	ReviewedAt      *time.Time
+}
+
+// NewKYCSubmission submits the business info of an organizer for review with its documents, which must
+// include every required type
+func NewKYCSubmission(organizerID int64, info KYCBusinessInfo, documents []*KYCDocument) (*KYCSubmission, error) {
+	for _, required := range RequiredKYCDocuments {
+		found := false
+		for _, document := range documents {
+			found = found || document.Type == required
+		}
+		if !found {
+			return nil, ErrKYCDocumentMissing
+		}
+	}
+
+	return &KYCSubmission{
+		OrganizerID:     organizerID,
+		KYCBusinessInfo: info,
+		Documents:       documents,
+		Status:          KYCStatusPending,
+		SubmittedAt:     time.Now(),
+	}, nil
+}
+
+// IsOpen tells whether the submission blocks a new one, being pending review or approved
+func (s *KYCSubmission) IsOpen() bool {
+	return s.Status == KYCStatusPending || s.Status == KYCStatusApproved
+}
+
+// Document returns the document of the submission with the given ID
+func (s *KYCSubmission) Document(id int64) (*KYCDocument, bool) {
+	for _, document := range s.Documents {
+		if document.ID == id {
+			return document, true
+		}
+	}
+	return nil, false
+}
+
+// Approve records the approval of the submission by an admin
+func (s *KYCSubmission) Approve(reviewerID int64) error {
+	return s.review(KYCStatusApproved, reviewerID, "")
+}
+
+// Reject records the rejection of the submission by an admin, who must tell the organizer why
+func (s *KYCSubmission) Reject(reviewerID int64, reason string) error {
+	if reason == "" {
+		return ErrKYCRejectionReasonRequired
+	}
+	return s.review(KYCStatusRejected, reviewerID, reason)
+}
+
+func (s *KYCSubmission) review(status KYCStatus, reviewerID int64, reason string) error {
+	if s.Status != KYCStatusPending {
+		return ErrKYCNotPending
+	}
+
+	now := time.Now()
+	s.Status = status
+	s.ReviewedBy = &reviewerID
+	s.RejectionReason = reason
+	s.ReviewedAt = &now
+	return nil
+}
+
+// CanReceivePayouts tells whether the organizer of the latest submission, nil if they have none, can be
+// paid out
+func CanReceivePayouts(latest *KYCSubmission) error {
+	if latest == nil || latest.Status != KYCStatusApproved {
+		return ErrKYCNotApproved
+	}
+	return nil
+}
+
+// EventKYCReviewed is published when an admin approves or rejects a KYC submission
+type EventKYCReviewed struct {
+	SubmissionID    int64     `json:"submission_id"`
+	OrganizerID     int64     `json:"organizer_id"`
+	LegalName       string    `json:"legal_name"`
+	Status          KYCStatus `json:"status"`
+	RejectionReason string    `json:"rejection_reason"`
+	OccurredAt      time.Time `json:"occurred_at"`
+}
+
+// NewEventKYCReviewed creates the event of a reviewed submission
+func NewEventKYCReviewed(submission *KYCSubmission) *EventKYCReviewed {
+	return &EventKYCReviewed{
+		SubmissionID:    submission.ID,
+		OrganizerID:     submission.OrganizerID,
+		LegalName:       submission.LegalName,
+		Status:          submission.Status,
+		RejectionReason: submission.RejectionReason,
+		OccurredAt:      time.Now(),
+	}
+}
+
+// ListKYCFilters represents filters for listing KYC submissions
+type ListKYCFilters struct {
+	OrganizerID *int64
+	Status      *KYCStatus
+}
+
+// KYCRepository defines the interface for KYC submission and document persistence
+type KYCRepository interface {
+	// SaveDocument stores an uploaded document, not attached to a submission yet
+	SaveDocument(ctx context.Context, document *KYCDocument) error
+
+	// GetUnattachedDocuments retrieves the documents of an organizer by ID, ErrKYCDocumentNotFound if one
+	// of them does not exist, belongs to someone else or is part of a submission already
+	GetUnattachedDocuments(ctx context.Context, organizerID int64, ids []int64) ([]*KYCDocument, error)
+
+	// Submit stores a submission pending review and attaches its documents, ErrKYCAlreadySubmitted if the
+	// organizer has an open submission
+	Submit(ctx context.Context, submission *KYCSubmission) error
+
+	// GetByID retrieves a submission with its documents
+	GetByID(ctx context.Context, id int64) (*KYCSubmission, error)
+
+	// GetLatestByOrganizerID retrieves the most recent submission of an organizer with its documents
+	GetLatestByOrganizerID(ctx context.Context, organizerID int64) (*KYCSubmission, error)
+
+	// List retrieves submissions without their documents with pagination and filters, oldest first so
+	// the review queue is worked in order
+	List(ctx context.Context, filters ListKYCFilters, paging *listing.Paging) ([]*KYCSubmission, error)
+
+	// SaveReview records the review of a submission still pending, ErrKYCNotPending otherwise
+	SaveReview(ctx context.Context, submission *KYCSubmission) error
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewKYCSubmission(t *testing.T) {
+	identity := &KYCDocument{ID: 1, Type: KYCDocumentIdentity}
+	registration := &KYCDocument{ID: 2, Type: KYCDocumentBusinessRegistration}
+	address := &KYCDocument{ID: 3, Type: KYCDocumentProofOfAddress}
+
+	_, err := NewKYCSubmission(7, KYCBusinessInfo{LegalName: "Acme"}, []*KYCDocument{identity, address})
+	assert.Equal(t, ErrKYCDocumentMissing, err)
+
+	submission, err := NewKYCSubmission(7, KYCBusinessInfo{LegalName: "Acme"}, []*KYCDocument{identity, registration})
+	require.NoError(t, err)
+	assert.Equal(t, KYCStatusPending, submission.Status)
+	assert.True(t, submission.IsOpen())
+	assert.Equal(t, ErrKYCNotApproved, CanReceivePayouts(submission))
+}
+
+func TestKYCSubmission_Review(t *testing.T) {
+	newSubmission := func() *KYCSubmission {
+		return &KYCSubmission{ID: 1, OrganizerID: 7, Status: KYCStatusPending}
+	}
+
+	t.Run("approved organizers can be paid out", func(t *testing.T) {
+		submission := newSubmission()
+		require.NoError(t, submission.Approve(99))
+		assert.Equal(t, KYCStatusApproved, submission.Status)
+		assert.Equal(t, int64(99), *submission.ReviewedBy)
+		assert.NotNil(t, submission.ReviewedAt)
+		assert.NoError(t, CanReceivePayouts(submission))
+		assert.True(t, submission.IsOpen())
+
+		assert.Equal(t, ErrKYCNotPending, submission.Reject(99, "too late"))
+	})
+
+	t.Run("a rejection needs a reason and lets the organizer submit again", func(t *testing.T) {
+		submission := newSubmission()
+		assert.Equal(t, ErrKYCRejectionReasonRequired, submission.Reject(99, ""))
+		assert.Equal(t, KYCStatusPending, submission.Status)
+
+		require.NoError(t, submission.Reject(99, "the registration certificate is unreadable"))
+		assert.Equal(t, KYCStatusRejected, submission.Status)
+		assert.False(t, submission.IsOpen())
+		assert.Equal(t, ErrKYCNotApproved, CanReceivePayouts(submission))
+
+		event := NewEventKYCReviewed(submission)
+		assert.Equal(t, KYCStatusRejected, event.Status)
+		assert.Equal(t, "the registration certificate is unreadable", event.RejectionReason)
+	})
+
+	t.Run("organizers without a submission cannot be paid out", func(t *testing.T) {
+		assert.Equal(t, ErrKYCNotApproved, CanReceivePayouts(nil))
+	})
+}
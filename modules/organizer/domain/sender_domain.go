@@ -0,0 +1,214 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// SenderDomainStatus represents the verification state of a sender domain
+type SenderDomainStatus string
+
+const (
+	SenderDomainStatusPending  SenderDomainStatus = "pending"
+	SenderDomainStatusVerified SenderDomainStatus = "verified"
+	// SenderDomainStatusFailed domains were checked and miss some of their DNS records
+	SenderDomainStatusFailed SenderDomainStatus = "failed"
+)
+
+// DNSRecordPurpose tells what a DNS record of a sender domain proves
+type DNSRecordPurpose string
+
+const (
+	DNSRecordPurposeOwnership DNSRecordPurpose = "ownership"
+	DNSRecordPurposeSPF       DNSRecordPurpose = "spf"
+	DNSRecordPurposeDKIM      DNSRecordPurpose = "dkim"
+)
+
+const (
+	DNSRecordTypeTXT   = "TXT"
+	DNSRecordTypeCNAME = "CNAME"
+
+	// verificationHostPrefix and dkimSelector name the records organizers publish under their domain
+	verificationHostPrefix = "_tixgo."
+	dkimSelector           = "tixgo._domainkey."
+	verificationValue      = "tixgo-verification="
+)
+
+var domainNameRegex = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+// SenderPlatform is what the mail platform asks sender domains to delegate to it. Empty values are not checked.
+type SenderPlatform struct {
+	// SPFInclude is the domain SPF records must include
+	SPFInclude string
+	// DKIMHost is the target of the DKIM CNAME record
+	DKIMHost string
+}
+
+// SenderDomain is the identity attendee-facing mails of an organizer are sent from once verified
+type SenderDomain struct {
+	ID                int64
+	OrganizerID       int64
+	Domain            string
+	FromEmail         string
+	FromName          string
+	VerificationToken string
+	Status            SenderDomainStatus
+	VerifiedAt        *time.Time
+	CheckedAt         *time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// NewSenderDomain creates a pending sender domain with a new verification token. The from address
+// must belong to the domain.
+func NewSenderDomain(organizerID int64, domainName, fromEmail, fromName string) (*SenderDomain, error) {
+	domainName = NormalizeDomain(domainName)
+	if len(domainName) > 253 || !domainNameRegex.MatchString(domainName) {
+		return nil, ErrInvalidSenderDomain
+	}
+
+	fromEmail = strings.TrimSpace(fromEmail)
+	at := strings.LastIndex(fromEmail, "@")
+	if at < 1 || NormalizeDomain(fromEmail[at+1:]) != domainName {
+		return nil, ErrFromEmailNotOnDomain
+	}
+
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to generate verification token")
+	}
+
+	return &SenderDomain{
+		OrganizerID:       organizerID,
+		Domain:            domainName,
+		FromEmail:         fromEmail,
+		FromName:          strings.TrimSpace(fromName),
+		VerificationToken: hex.EncodeToString(token),
+		Status:            SenderDomainStatusPending,
+	}, nil
+}
+
+// NormalizeDomain lowercases a domain name and drops its trailing dot
+func NormalizeDomain(name string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+}
+
+// IsVerified tells whether mails can be sent from the domain
+func (d *SenderDomain) IsVerified() bool {
+	return d.Status == SenderDomainStatusVerified
+}
+
+// Records returns the DNS records the organizer must publish for the domain to be verified
+func (d *SenderDomain) Records(platform SenderPlatform) []DNSRecord {
+	records := []DNSRecord{
+		{
+			Purpose: DNSRecordPurposeOwnership,
+			Type:    DNSRecordTypeTXT,
+			Host:    verificationHostPrefix + d.Domain,
+			Value:   verificationValue + d.VerificationToken,
+		},
+	}
+	if platform.SPFInclude != "" {
+		records = append(records, DNSRecord{
+			Purpose: DNSRecordPurposeSPF,
+			Type:    DNSRecordTypeTXT,
+			Host:    d.Domain,
+			Value:   "v=spf1 include:" + platform.SPFInclude + " ~all",
+		})
+	}
+	if platform.DKIMHost != "" {
+		records = append(records, DNSRecord{
+			Purpose: DNSRecordPurposeDKIM,
+			Type:    DNSRecordTypeCNAME,
+			Host:    dkimSelector + d.Domain,
+			Value:   platform.DKIMHost,
+		})
+	}
+	return records
+}
+
+// ApplyChecks records the outcome of a DNS check: the domain is verified when every record was found
+func (d *SenderDomain) ApplyChecks(checks []DNSRecordCheck, now time.Time) {
+	d.CheckedAt = &now
+	for _, check := range checks {
+		if !check.Found {
+			d.Status = SenderDomainStatusFailed
+			d.VerifiedAt = nil
+			return
+		}
+	}
+	if d.Status != SenderDomainStatusVerified {
+		d.VerifiedAt = &now
+	}
+	d.Status = SenderDomainStatusVerified
+}
+
+// DNSRecord is a record of a sender domain
+type DNSRecord struct {
+	Purpose DNSRecordPurpose
+	Type    string
+	Host    string
+	Value   string
+}
+
+// Matches tells whether one of the values published at the host of the record satisfies it. SPF
+// records only need to include the platform among their other mechanisms.
+func (r DNSRecord) Matches(values []string) bool {
+	for _, value := range values {
+		switch r.Purpose {
+		case DNSRecordPurposeSPF:
+			if !strings.HasPrefix(value, "v=spf1 ") {
+				continue
+			}
+			include := strings.Fields(r.Value)[1]
+			for _, mechanism := range strings.Fields(value) {
+				if strings.EqualFold(strings.TrimLeft(mechanism, "+"), include) {
+					return true
+				}
+			}
+		case DNSRecordPurposeDKIM:
+			if NormalizeDomain(value) == NormalizeDomain(r.Value) {
+				return true
+			}
+		default:
+			if strings.TrimSpace(value) == r.Value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DNSRecordCheck is the outcome of looking a record up
+type DNSRecordCheck struct {
+	Record DNSRecord
+	Found  bool
+}
+
+// DNSResolver looks the records of sender domains up
+type DNSResolver interface {
+	// Lookup returns the values of the records of recordType at host, none if the host does not exist
+	Lookup(ctx context.Context, recordType, host string) ([]string, error)
+}
+
+// SenderDomainRepository defines the interface for sender domain persistence
+type SenderDomainRepository interface {
+	// Save creates or replaces the sender domain of its organizer
+	Save(ctx context.Context, senderDomain *SenderDomain) error
+
+	// GetByOrganizerID retrieves the sender domain of an organizer
+	GetByOrganizerID(ctx context.Context, organizerID int64) (*SenderDomain, error)
+
+	// UpdateVerification saves the status of a sender domain after a check. It fails with
+	// ErrSenderDomainTaken if another organizer verified the domain first.
+	UpdateVerification(ctx context.Context, senderDomain *SenderDomain) error
+
+	// Delete deletes the sender domain of an organizer
+	Delete(ctx context.Context, organizerID int64) error
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// MaxThemeLogoURLLength and MaxThemeFooterLength bound what a theme adds to every ticket and mail
+	MaxThemeLogoURLLength = 500
+	MaxThemeFooterLength  = 300
+)
+
+var themeColorRegex = regexp.MustCompile(`^#([0-9a-f]{3}|[0-9a-f]{6})$`)
+
+// Theme is how an organizer brands the tickets and mails of their attendees: colors, a logo and the
+// text of the footer. An empty field keeps the platform default.
+type Theme struct {
+	OrganizerID  int64
+	PrimaryColor string
+	AccentColor  string
+	LogoURL      string
+	FooterText   string
+	UpdatedAt    time.Time
+}
+
+// NewTheme creates the theme of an organizer. Colors are hex like #1f2937, the logo is an https URL.
+func NewTheme(organizerID int64, primaryColor, accentColor, logoURL, footerText string) (*Theme, error) {
+	theme := &Theme{
+		OrganizerID:  organizerID,
+		PrimaryColor: strings.ToLower(strings.TrimSpace(primaryColor)),
+		AccentColor:  strings.ToLower(strings.TrimSpace(accentColor)),
+		LogoURL:      strings.TrimSpace(logoURL),
+		FooterText:   strings.TrimSpace(footerText),
+	}
+
+	for _, color := range []string{theme.PrimaryColor, theme.AccentColor} {
+		if color != "" && !themeColorRegex.MatchString(color) {
+			return nil, ErrInvalidThemeColor
+		}
+	}
+
+	if theme.LogoURL != "" {
+		if len(theme.LogoURL) > MaxThemeLogoURLLength {
+			return nil, ErrInvalidThemeLogo
+		}
+		// the logo is loaded by the mail clients of attendees, which block mixed content
+		logo, err := url.Parse(theme.LogoURL)
+		if err != nil || logo.Scheme != "https" || logo.Host == "" || logo.User != nil {
+			return nil, ErrInvalidThemeLogo
+		}
+	}
+
+	if utf8.RuneCountInString(theme.FooterText) > MaxThemeFooterLength {
+		return nil, ErrThemeFooterTooLong
+	}
+
+	return theme, nil
+}
+
+// ThemeRepository defines the interface for theme persistence
+type ThemeRepository interface {
+	// Save creates or replaces the theme of its organizer
+	Save(ctx context.Context, theme *Theme) error
+
+	// GetByOrganizerID retrieves the theme of an organizer
+	GetByOrganizerID(ctx context.Context, organizerID int64) (*Theme, error)
+
+	// Delete deletes the theme of an organizer
+	Delete(ctx context.Context, organizerID int64) error
+}
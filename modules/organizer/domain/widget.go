@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+const (
+	// MaxWidgetOrigins bounds the sites an organizer embeds the checkout widget on
+	MaxWidgetOrigins = 10
+
+	// WidgetTokenTTL is how long a widget token is valid, the widget asks for another one once expired
+	WidgetTokenTTL = 15 * time.Minute
+)
+
+// WidgetOrigin is a site an organizer embeds the checkout widget on, e.g. https://tickets.example.com
+type WidgetOrigin struct {
+	ID          int64
+	OrganizerID int64
+	Origin      string
+	CreatedAt   time.Time
+}
+
+// NewWidgetOrigin creates a widget origin of an organizer from the origin they typed
+func NewWidgetOrigin(organizerID int64, origin string) (*WidgetOrigin, error) {
+	origin, err := NormalizeOrigin(origin)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WidgetOrigin{
+		OrganizerID: organizerID,
+		Origin:      origin,
+	}, nil
+}
+
+// NormalizeOrigin returns an origin the way browsers send it in the Origin header: a lowercase scheme
+// and host, with the port only when it is not the default one. Origins must be https, http is only
+// accepted on loopback hosts for local development.
+func NormalizeOrigin(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Opaque != "" || u.User != nil || u.RawQuery != "" || u.Fragment != "" || (u.Path != "" && u.Path != "/") {
+		return "", ErrInvalidWidgetOrigin
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == "" || len(host) > 253 {
+		return "", ErrInvalidWidgetOrigin
+	}
+
+	port := u.Port()
+	switch u.Scheme {
+	case "https":
+		if port == "443" {
+			port = ""
+		}
+	case "http":
+		if !isLoopback(host) {
+			return "", ErrInvalidWidgetOrigin
+		}
+		if port == "80" {
+			port = ""
+		}
+	default:
+		return "", ErrInvalidWidgetOrigin
+	}
+
+	if port != "" {
+		return u.Scheme + "://" + net.JoinHostPort(host, port), nil
+	}
+	if strings.Contains(host, ":") {
+		return u.Scheme + "://[" + host + "]", nil
+	}
+	return u.Scheme + "://" + host, nil
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// WidgetToken lets a checkout widget embedded on Origin act on one event of an organizer, without
+// the buyer signing in. It is only honored on requests sent from that origin.
+type WidgetToken struct {
+	Token       string    `json:"-"`
+	OrganizerID int64     `json:"organizer_id"`
+	EventID     int64     `json:"event_id"`
+	EventSlug   string    `json:"event_slug"`
+	Origin      string    `json:"origin"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// NewWidgetToken creates a widget token for an event valid for WidgetTokenTTL
+func NewWidgetToken(organizerID, eventID int64, eventSlug, origin string, now time.Time) (*WidgetToken, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to generate widget token")
+	}
+
+	return &WidgetToken{
+		Token:       hex.EncodeToString(token),
+		OrganizerID: organizerID,
+		EventID:     eventID,
+		EventSlug:   eventSlug,
+		Origin:      origin,
+		ExpiresAt:   now.Add(WidgetTokenTTL),
+	}, nil
+}
+
+// WidgetOriginRepository defines the interface for widget origin persistence
+type WidgetOriginRepository interface {
+	// Add registers an origin, failing with ErrWidgetOriginExists if the organizer registered it already
+	// and ErrTooManyWidgetOrigins if they have MaxWidgetOrigins
+	Add(ctx context.Context, origin *WidgetOrigin) error
+
+	// ListByOrganizerID retrieves the origins of an organizer, oldest first
+	ListByOrganizerID(ctx context.Context, organizerID int64) ([]*WidgetOrigin, error)
+
+	// Delete removes an origin of an organizer
+	Delete(ctx context.Context, organizerID, id int64) error
+
+	// IsRegistered tells whether the organizer registered the origin
+	IsRegistered(ctx context.Context, organizerID int64, origin string) (bool, error)
+}
+
+// WidgetTokenStore keeps the widget tokens until they expire
+type WidgetTokenStore interface {
+	// Save stores a token until its expiry
+	Save(ctx context.Context, token *WidgetToken) error
+
+	// Get retrieves a token, failing with ErrInvalidWidgetToken if it is unknown or expired
+	Get(ctx context.Context, token string) (*WidgetToken, error)
+}
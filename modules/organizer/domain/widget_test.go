@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeOrigin(t *testing.T) {
+	valid := map[string]string{
+		"https://tickets.example.com":      "https://tickets.example.com",
+		"HTTPS://Tickets.Example.com/":     "https://tickets.example.com",
+		"https://example.com:443":          "https://example.com",
+		"https://example.com:8443":         "https://example.com:8443",
+		"http://localhost:3000":            "http://localhost:3000",
+		"http://127.0.0.1":                 "http://127.0.0.1",
+		"https://[2001:db8::1]":            "https://[2001:db8::1]",
+		"  https://example.com  ":          "https://example.com",
+		"https://xn--bcher-kva.example.ch": "https://xn--bcher-kva.example.ch",
+	}
+	for raw, want := range valid {
+		got, err := NormalizeOrigin(raw)
+		if assert.NoError(t, err, raw) {
+			assert.Equal(t, want, got, raw)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"null",
+		"example.com",
+		"http://example.com",
+		"ftp://example.com",
+		"https://example.com/checkout",
+		"https://example.com?embed=1",
+		"https://example.com#widget",
+		"https://user@example.com",
+		"https://",
+	}
+	for _, raw := range invalid {
+		_, err := NormalizeOrigin(raw)
+		assert.ErrorIs(t, err, ErrInvalidWidgetOrigin, raw)
+	}
+}
@@ -0,0 +1,229 @@
+package ports
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"tixgo/components"
+	"tixgo/modules/organizer/adapters"
+	"tixgo/modules/organizer/app/command"
+	"tixgo/modules/organizer/app/query"
+	"tixgo/modules/organizer/domain"
+	userDomain "tixgo/modules/user/domain"
+	"tixgo/shared/dedup"
+	"tixgo/shared/httpresponse"
+	"tixgo/shared/listing"
+	"tixgo/shared/session"
+
+	"github.com/duongptryu/gox/context"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthenticateAPIKey authenticates the requests bearing an API key in the X-API-Key header as the
+// organizer owning it, and counts them against the monthly quota of the key. Every metered response
+// tells the quota in the X-RateLimit headers; requests past it are answered with 429 until the quota
+// resets. Requests without the header are left to session.RequireAuth.
+func AuthenticateAPIKey(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(domain.APIKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		handler := command.NewMeterAPIRequestHandler(
+			adapters.NewAPIKeyPostgresRepository(appCtx.GetDB()),
+			adapters.NewRedisAPIUsageCounter(appCtx.GetRedis()),
+			dedup.NewRedisDeduplicator(appCtx.GetRedis()),
+			appCtx.GetReliableEventBus(),
+		)
+
+		request, err := handler.Handle(c.Request.Context(), command.MeterAPIRequestCommand{Key: key, Now: time.Now()})
+		if request != nil && request.Metered {
+			c.Header("X-RateLimit-Limit", strconv.FormatInt(request.Quota.Limit, 10))
+			c.Header("X-RateLimit-Remaining", strconv.FormatInt(request.Quota.Remaining(), 10))
+			c.Header("X-RateLimit-Reset", strconv.FormatInt(request.Quota.ResetAt().Unix(), 10))
+		}
+		if errors.Is(err, domain.ErrAPIQuotaExceeded) {
+			retryAfter := int64(time.Until(request.Quota.ResetAt()).Seconds()) + 1
+			c.Header("Retry-After", strconv.FormatInt(retryAfter, 10))
+			httpresponse.Error(c, http.StatusTooManyRequests, string(domain.QuotaExceededCode), err.Error(), nil)
+			c.Abort()
+			return
+		}
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		ctx := c.Request.Context()
+		ctx = context.WithUserID(ctx, strconv.FormatInt(request.Key.OrganizerID, 10))
+		ctx = context.WithUserType(ctx, string(userDomain.UserTypeOrganizer))
+		ctx = session.WithAPIKey(ctx, request.Key.ID)
+		if request.Key.TestMode {
+			ctx = session.WithTestMode(ctx)
+		}
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// RejectAPIKey keeps the routes managing API keys to signed in organizers, a leaked key must not
+// be able to mint others nor revoke the ones replacing it
+func RejectAPIKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := session.APIKeyID(c.Request.Context()); ok {
+			c.Error(domain.ErrAPIKeyNotAllowed)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func ListAPIKeys(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		organizerID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		handler := query.NewListAPIKeysHandler(adapters.NewAPIKeyPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), query.ListAPIKeysQuery{OrganizerID: organizerID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func CreateAPIKey(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.CreateAPIKeyCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		organizerID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.OrganizerID = organizerID
+
+		handler := command.NewCreateAPIKeyHandler(adapters.NewAPIKeyPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.Header("Cache-Control", "no-store")
+		httpresponse.Success(c, http.StatusCreated, result)
+	}
+}
+
+func RevokeAPIKey(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		organizerID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		handler := command.NewRevokeAPIKeyHandler(adapters.NewAPIKeyPostgresRepository(appCtx.GetDB()))
+
+		err = handler.Handle(c.Request.Context(), command.RevokeAPIKeyCommand{OrganizerID: organizerID, ID: id})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, true)
+	}
+}
+
+// ListAPIUsage lists the usage of API keys in a period. Organizers only get their own keys, admins
+// get every key or those of the organizer_id they filter on.
+func ListAPIUsage(appCtx components.AppContext, ownKeys bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var filters query.FilterAPIUsageQuery
+		if err := c.ShouldBind(&filters); err != nil {
+			c.Error(err)
+			return
+		}
+
+		var paging listing.Paging
+		if err := c.ShouldBind(&paging); err != nil {
+			c.Error(err)
+			return
+		}
+
+		// Apply pagination defaults in HTTP layer
+		paging.Fulfill()
+
+		if ownKeys {
+			organizerID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			filters.OrganizerID = &organizerID
+		}
+
+		handler := query.NewListAPIUsageHandler(adapters.NewAPIUsagePostgresRepository(appCtx.GetDB()), adapters.NewRedisAPIUsageCounter(appCtx.GetRedis()))
+
+		result, err := handler.Handle(c.Request.Context(), &filters, &paging)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.List(c, result, paging, filters)
+	}
+}
+
+func SetAPIKeyPlan(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.SetAPIKeyPlanCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.ID = id
+
+		handler := command.NewSetAPIKeyPlanHandler(adapters.NewAPIKeyPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
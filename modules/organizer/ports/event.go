@@ -0,0 +1,52 @@
+package ports
+
+import (
+	"context"
+
+	"tixgo/components"
+	organizerEvent "tixgo/modules/organizer/app/event"
+	"tixgo/modules/organizer/domain"
+	templateAdapters "tixgo/modules/template/adapters"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/duongptryu/gox/messaging"
+)
+
+const (
+	EventKYCReviewed     = "events.EventKYCReviewed"
+	EventAPIQuotaWarning = "events.EventAPIQuotaWarning"
+)
+
+type OrganizerMessagingHandlers struct {
+	dispatcher messaging.Dispatcher
+	appCtx     components.AppContext
+}
+
+func NewOrganizerMessagingHandlers(dispatcher messaging.Dispatcher, appCtx components.AppContext) *OrganizerMessagingHandlers {
+	return &OrganizerMessagingHandlers{
+		dispatcher: dispatcher,
+		appCtx:     appCtx,
+	}
+}
+
+func (h *OrganizerMessagingHandlers) RegisterOrganizerMessagingHandlers() {
+	eventProcessor := h.dispatcher.GetEventProcessor()
+	eventProcessor.AddHandler(cqrs.NewEventHandler(EventKYCReviewed, h.HandleEventKYCReviewed))
+	eventProcessor.AddHandler(cqrs.NewEventHandler(EventAPIQuotaWarning, h.HandleEventAPIQuotaWarning))
+}
+
+func (h *OrganizerMessagingHandlers) HandleEventKYCReviewed(ctx context.Context, event *domain.EventKYCReviewed) error {
+	templateRepo := templateAdapters.NewCachedTemplateRepository(templateAdapters.NewTemplatePostgresRepository(h.appCtx.GetDB()), h.appCtx.GetCache())
+	templateRenderer := templateAdapters.NewHTMLTemplateRenderer(h.appCtx.GetAssetService(), nil, templateAdapters.NewVariableDefaultPostgresRepository(h.appCtx.GetDB()))
+	biz := organizerEvent.NewNotifyKYCReviewed(templateRepo, templateRenderer, h.appCtx.GetReliableEventBus())
+
+	return biz.Notify(ctx, event)
+}
+
+func (h *OrganizerMessagingHandlers) HandleEventAPIQuotaWarning(ctx context.Context, event *domain.EventAPIQuotaWarning) error {
+	templateRepo := templateAdapters.NewCachedTemplateRepository(templateAdapters.NewTemplatePostgresRepository(h.appCtx.GetDB()), h.appCtx.GetCache())
+	templateRenderer := templateAdapters.NewHTMLTemplateRenderer(h.appCtx.GetAssetService(), nil, templateAdapters.NewVariableDefaultPostgresRepository(h.appCtx.GetDB()))
+	biz := organizerEvent.NewNotifyAPIQuotaWarning(templateRepo, templateRenderer, h.appCtx.GetReliableEventBus())
+
+	return biz.Notify(ctx, event)
+}
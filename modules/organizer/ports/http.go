@@ -0,0 +1,189 @@
+package ports
+
+import (
+	"net/http"
+
+	"tixgo/components"
+	eventDomain "tixgo/modules/event/domain"
+	"tixgo/modules/organizer/adapters"
+	"tixgo/modules/organizer/app/command"
+	"tixgo/modules/organizer/app/query"
+	"tixgo/modules/organizer/domain"
+	userDomain "tixgo/modules/user/domain"
+	"tixgo/shared/apiversion"
+	"tixgo/shared/authz"
+	"tixgo/shared/httpresponse"
+	"tixgo/shared/session"
+
+	"github.com/duongptryu/gox/context"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterOrganizerRoutes(router *apiversion.Group, appCtx components.AppContext, platform domain.SenderPlatform, site eventDomain.Site) {
+	senderDomainGroup := router.Group("/organizer/sender-domain")
+	{
+		senderDomainGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		senderDomainGroup.Use(authz.RequireUserType(string(userDomain.UserTypeOrganizer)))
+		senderDomainGroup.GET("", GetSenderDomain(appCtx, platform))
+		senderDomainGroup.PUT("", ConfigureSenderDomain(appCtx, platform))
+		senderDomainGroup.POST("/verify", VerifySenderDomain(appCtx, platform))
+		senderDomainGroup.DELETE("", DeleteSenderDomain(appCtx))
+	}
+
+	widgetOriginGroup := router.Group("/organizer/widget/origins")
+	{
+		widgetOriginGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		widgetOriginGroup.Use(authz.RequireUserType(string(userDomain.UserTypeOrganizer)))
+		widgetOriginGroup.GET("", ListWidgetOrigins(appCtx))
+		widgetOriginGroup.POST("", AddWidgetOrigin(appCtx))
+		widgetOriginGroup.DELETE("/:id", DeleteWidgetOrigin(appCtx))
+	}
+
+	// Keys are managed from a session only, the usage is also readable with the keys themselves
+	apiKeyGroup := router.Group("/organizer/api-keys")
+	{
+		apiKeyGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		apiKeyGroup.Use(authz.RequireUserType(string(userDomain.UserTypeOrganizer)))
+		apiKeyGroup.GET("/usage", ListAPIUsage(appCtx, true))
+		apiKeyGroup.GET("", RejectAPIKey(), ListAPIKeys(appCtx))
+		apiKeyGroup.POST("", RejectAPIKey(), CreateAPIKey(appCtx))
+		apiKeyGroup.DELETE("/:id", RejectAPIKey(), RevokeAPIKey(appCtx))
+	}
+
+	themeGroup := router.Group("/organizer/theme")
+	{
+		themeGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		themeGroup.Use(authz.RequireUserType(string(userDomain.UserTypeOrganizer)))
+		themeGroup.GET("", GetTheme(appCtx))
+		themeGroup.PUT("", SaveTheme(appCtx))
+		themeGroup.DELETE("", DeleteTheme(appCtx))
+		themeGroup.POST("/preview", PreviewTheme(appCtx))
+	}
+
+	kycGroup := router.Group("/organizer/kyc")
+	{
+		kycGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		kycGroup.Use(authz.RequireUserType(string(userDomain.UserTypeOrganizer)))
+		kycGroup.GET("", GetKYC(appCtx))
+		kycGroup.POST("", SubmitKYC(appCtx))
+		kycGroup.POST("/documents", UploadKYCDocument(appCtx))
+	}
+
+	// Review of the KYC submissions of organizers
+	kycReviewGroup := router.Group("/admin/kyc")
+	{
+		kycReviewGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		kycReviewGroup.Use(authz.RequireUserType(string(userDomain.UserTypeAdmin)))
+		kycReviewGroup.GET("", ListKYCSubmissions(appCtx))
+		kycReviewGroup.GET("/:id", GetKYCSubmission(appCtx))
+		kycReviewGroup.GET("/:id/documents/:document_id", DownloadKYCDocument(appCtx))
+		kycReviewGroup.POST("/:id/approve", ReviewKYC(appCtx, true))
+		kycReviewGroup.POST("/:id/reject", ReviewKYC(appCtx, false))
+	}
+
+	// Usage and plans of the API keys of every organizer
+	apiUsageGroup := router.Group("/admin")
+	{
+		apiUsageGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		apiUsageGroup.Use(authz.RequireUserType(string(userDomain.UserTypeAdmin)))
+		apiUsageGroup.GET("/api-usage", ListAPIUsage(appCtx, false))
+		apiUsageGroup.PUT("/api-keys/:id/plan", SetAPIKeyPlan(appCtx))
+	}
+
+	// the checkout widget embedded on the sites of organizers, public but scoped to their origins. It
+	// shows the availability of the event; buyers order and pay on the event page of the site.
+	widgetGroup := router.Group("/widget")
+	{
+		widgetGroup.Use(widgetCORS())
+		widgetGroup.POST("/tokens", IssueWidgetToken(appCtx, site))
+		widgetGroup.GET("/availability", RequireWidgetToken(appCtx), GetWidgetAvailability(appCtx))
+	}
+}
+
+func GetSenderDomain(appCtx components.AppContext, platform domain.SenderPlatform) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		organizerID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		handler := query.NewGetSenderDomainHandler(adapters.NewSenderDomainPostgresRepository(appCtx.GetDB()), platform)
+
+		result, err := handler.Handle(c.Request.Context(), query.GetSenderDomainQuery{OrganizerID: organizerID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func ConfigureSenderDomain(appCtx components.AppContext, platform domain.SenderPlatform) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.ConfigureSenderDomainCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		organizerID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.OrganizerID = organizerID
+
+		handler := command.NewConfigureSenderDomainHandler(adapters.NewSenderDomainPostgresRepository(appCtx.GetDB()), platform)
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func VerifySenderDomain(appCtx components.AppContext, platform domain.SenderPlatform) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		organizerID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		handler := command.NewVerifySenderDomainHandler(adapters.NewSenderDomainPostgresRepository(appCtx.GetDB()), adapters.NewNetDNSResolver(), platform)
+
+		result, err := handler.Handle(c.Request.Context(), command.VerifySenderDomainCommand{OrganizerID: organizerID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func DeleteSenderDomain(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		organizerID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		handler := command.NewDeleteSenderDomainHandler(adapters.NewSenderDomainPostgresRepository(appCtx.GetDB()))
+
+		err = handler.Handle(c.Request.Context(), command.DeleteSenderDomainCommand{OrganizerID: organizerID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, true)
+	}
+}
@@ -0,0 +1,31 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"tixgo/components"
+	"tixgo/modules/organizer/adapters"
+	"tixgo/modules/organizer/app/command"
+	"tixgo/shared/scheduler"
+)
+
+const (
+	JobFlushAPIUsage = "organizer.flush_api_usage"
+)
+
+// Jobs returns the jobs of the organizer module
+func Jobs(appCtx components.AppContext) []scheduler.Job {
+	return []scheduler.Job{
+		{
+			Name:     JobFlushAPIUsage,
+			Schedule: "@every 1m",
+			Timeout:  30 * time.Second,
+			Run: func(ctx context.Context) error {
+				usageCounter := adapters.NewRedisAPIUsageCounter(appCtx.GetRedis())
+				usageRepo := adapters.NewAPIUsagePostgresRepository(appCtx.GetDB())
+				return command.NewFlushAPIUsageHandler(usageCounter, usageRepo).Handle(ctx, time.Now())
+			},
+		},
+	}
+}
@@ -0,0 +1,263 @@
+package ports
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+
+	"tixgo/components"
+	"tixgo/modules/organizer/adapters"
+	"tixgo/modules/organizer/app/command"
+	"tixgo/modules/organizer/app/query"
+	"tixgo/modules/organizer/domain"
+	"tixgo/shared/httpresponse"
+	"tixgo/shared/listing"
+
+	"github.com/duongptryu/gox/context"
+	"github.com/duongptryu/gox/syserr"
+
+	"github.com/gin-gonic/gin"
+)
+
+// kycUploadOverhead is what a multipart request adds around the file of a document
+const kycUploadOverhead = 1 << 20
+
+// RequireApprovedKYC guards the routes paying organizers out, like payouts, behind an approved KYC
+// submission. It must come after session.RequireAuth.
+func RequireApprovedKYC(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		organizerID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		latest, err := adapters.NewKYCPostgresRepository(appCtx.GetDB()).GetLatestByOrganizerID(c.Request.Context(), organizerID)
+		if err == domain.ErrKYCNotFound {
+			latest, err = nil, nil
+		}
+		if err == nil {
+			err = domain.CanReceivePayouts(latest)
+		}
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func GetKYC(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		organizerID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		handler := query.NewGetKYCHandler(adapters.NewKYCPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), query.GetKYCQuery{OrganizerID: organizerID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func UploadKYCDocument(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, domain.MaxKYCDocumentSize+kycUploadOverhead)
+
+		var req command.UploadKYCDocumentCommand
+		if err := c.ShouldBind(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			c.Error(syserr.Wrap(err, syserr.InvalidArgumentCode, "a document file is required"))
+			return
+		}
+		if fileHeader.Size > domain.MaxKYCDocumentSize {
+			c.Error(domain.ErrKYCDocumentTooLarge)
+			return
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.Error(syserr.Wrap(err, syserr.InternalCode, "failed to read the document file"))
+			return
+		}
+		defer file.Close()
+
+		organizerID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.OrganizerID = organizerID
+		req.FileName = fileHeader.Filename
+		req.Content = file
+
+		handler := command.NewUploadKYCDocumentHandler(adapters.NewKYCPostgresRepository(appCtx.GetDB()), appCtx.GetStorage())
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusCreated, result)
+	}
+}
+
+func SubmitKYC(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.SubmitKYCCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		organizerID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.OrganizerID = organizerID
+
+		handler := command.NewSubmitKYCHandler(adapters.NewKYCPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusCreated, result)
+	}
+}
+
+func ListKYCSubmissions(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var filters query.FilterKYCSubmissionsQuery
+		if err := c.ShouldBind(&filters); err != nil {
+			c.Error(err)
+			return
+		}
+
+		var paging listing.Paging
+		if err := c.ShouldBind(&paging); err != nil {
+			c.Error(err)
+			return
+		}
+
+		// Apply pagination defaults in HTTP layer
+		paging.Fulfill()
+
+		handler := query.NewListKYCSubmissionsHandler(adapters.NewKYCPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), &filters, &paging)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.List(c, result, paging, filters)
+	}
+}
+
+func GetKYCSubmission(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		handler := query.NewGetKYCSubmissionHandler(adapters.NewKYCPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), query.GetKYCSubmissionQuery{ID: id})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+// DownloadKYCDocument serves admins the file of a submitted document as an attachment, never rendered
+// inline nor cached
+func DownloadKYCDocument(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		submissionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		documentID, err := strconv.ParseInt(c.Param("document_id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		handler := query.NewOpenKYCDocumentHandler(adapters.NewKYCPostgresRepository(appCtx.GetDB()), appCtx.GetStorage())
+
+		file, err := handler.Handle(c.Request.Context(), query.OpenKYCDocumentQuery{SubmissionID: submissionID, DocumentID: documentID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		defer file.Content.Close()
+
+		c.Header("Content-Type", file.Document.ContentType)
+		c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": file.Document.FileName}))
+		c.Header("Cache-Control", "no-store")
+		c.Header("X-Content-Type-Options", "nosniff")
+		http.ServeContent(c.Writer, c.Request, file.Document.FileName, file.Document.CreatedAt, file.Content)
+	}
+}
+
+func ReviewKYC(appCtx components.AppContext, approve bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.ReviewKYCCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.SubmissionID = id
+		req.Approve = approve
+
+		reviewerID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.ReviewerID = reviewerID
+
+		handler := command.NewReviewKYCHandler(adapters.NewKYCPostgresRepository(appCtx.GetDB()), appCtx.GetReliableEventBus())
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
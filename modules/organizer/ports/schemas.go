@@ -0,0 +1,35 @@
+package ports
+
+import (
+	"tixgo/modules/organizer/app/command"
+	"tixgo/modules/organizer/app/query"
+	"tixgo/shared/jsonschema"
+	"tixgo/shared/listing"
+)
+
+// Schemas returns the request payloads of the organizer module
+func Schemas() []jsonschema.Payload {
+	return []jsonschema.Payload{
+		{Name: "organizer.sender-domain.configure", In: jsonschema.Body, Example: command.ConfigureSenderDomainCommand{}},
+		{Name: "organizer.theme.save", In: jsonschema.Body, Example: command.SaveThemeCommand{}},
+		{Name: "organizer.theme.preview", In: jsonschema.Body, Example: query.PreviewThemeQuery{}},
+		{Name: "organizer.widget.origins.add", In: jsonschema.Body, Example: command.AddWidgetOriginCommand{}},
+		{Name: "organizer.api-keys.create", In: jsonschema.Body, Example: command.CreateAPIKeyCommand{}},
+		{Name: "organizer.api-keys.usage", In: jsonschema.Query, Example: struct {
+			query.FilterAPIUsageQuery
+			listing.Paging
+		}{}},
+		{Name: "organizer.kyc.submit", In: jsonschema.Body, Example: command.SubmitKYCCommand{}},
+		{Name: "admin.kyc.list", In: jsonschema.Query, Example: struct {
+			query.FilterKYCSubmissionsQuery
+			listing.Paging
+		}{}},
+		{Name: "admin.kyc.review", In: jsonschema.Body, Example: command.ReviewKYCCommand{}},
+		{Name: "admin.api-usage.list", In: jsonschema.Query, Example: struct {
+			query.FilterAPIUsageQuery
+			listing.Paging
+		}{}},
+		{Name: "admin.api-keys.plan", In: jsonschema.Body, Example: command.SetAPIKeyPlanCommand{}},
+		{Name: "widget.tokens.issue", In: jsonschema.Body, Example: command.IssueWidgetTokenCommand{}},
+	}
+}
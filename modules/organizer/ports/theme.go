@@ -0,0 +1,112 @@
+package ports
+
+import (
+	"net/http"
+
+	"tixgo/components"
+	"tixgo/modules/organizer/adapters"
+	"tixgo/modules/organizer/app/command"
+	"tixgo/modules/organizer/app/query"
+	templateAdapters "tixgo/modules/template/adapters"
+	"tixgo/shared/httpresponse"
+
+	"github.com/duongptryu/gox/context"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetTheme(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		organizerID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		handler := query.NewGetThemeHandler(adapters.NewThemePostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), query.GetThemeQuery{OrganizerID: organizerID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func SaveTheme(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.SaveThemeCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		organizerID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.OrganizerID = organizerID
+
+		handler := command.NewSaveThemeHandler(adapters.NewThemePostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func DeleteTheme(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		organizerID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		handler := command.NewDeleteThemeHandler(adapters.NewThemePostgresRepository(appCtx.GetDB()))
+
+		err = handler.Handle(c.Request.Context(), command.DeleteThemeCommand{OrganizerID: organizerID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, true)
+	}
+}
+
+func PreviewTheme(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req query.PreviewThemeQuery
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		organizerID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.OrganizerID = organizerID
+
+		templateRepo := templateAdapters.NewCachedTemplateRepository(templateAdapters.NewTemplatePostgresRepository(appCtx.GetDB()), appCtx.GetCache())
+		templateRenderer := templateAdapters.NewHTMLTemplateRenderer(appCtx.GetAssetService(), nil, templateAdapters.NewVariableDefaultPostgresRepository(appCtx.GetDB()))
+		handler := query.NewPreviewThemeHandler(adapters.NewThemePostgresRepository(appCtx.GetDB()), templateRepo, templateRenderer)
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
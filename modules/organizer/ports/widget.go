@@ -0,0 +1,194 @@
+package ports
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tixgo/components"
+	eventAdapters "tixgo/modules/event/adapters"
+	eventQuery "tixgo/modules/event/app/query"
+	eventDomain "tixgo/modules/event/domain"
+	"tixgo/modules/organizer/adapters"
+	"tixgo/modules/organizer/app/command"
+	"tixgo/modules/organizer/app/query"
+	"tixgo/modules/organizer/domain"
+	"tixgo/shared/httpresponse"
+
+	"github.com/duongptryu/gox/context"
+
+	"github.com/gin-gonic/gin"
+)
+
+// widgetTokenKey is the gin context key of the widget token of a request
+const widgetTokenKey = "widget_token"
+
+// widgetCORS scopes the CORS headers of the widget endpoints: the router answers every origin with a
+// wildcard, here only the origin the request was checked against may read the response
+func widgetCORS() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Del("Access-Control-Allow-Origin")
+		c.Writer.Header().Add("Vary", "Origin")
+		c.Next()
+	}
+}
+
+// allowWidgetOrigin lets origin read the response, once the request was checked against it
+func allowWidgetOrigin(c *gin.Context, origin string) {
+	c.Header("Access-Control-Allow-Origin", origin)
+}
+
+// RequireWidgetToken authenticates widget requests by their bearer widget token, only honored when
+// the request comes from the origin the token was issued to
+func RequireWidgetToken(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			c.Error(domain.ErrInvalidWidgetToken)
+			c.Abort()
+			return
+		}
+
+		widgetToken, err := adapters.NewRedisWidgetTokenStore(appCtx.GetRedis()).Get(c.Request.Context(), token)
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		origin, err := domain.NormalizeOrigin(c.GetHeader("Origin"))
+		if err != nil || origin != widgetToken.Origin {
+			c.Error(domain.ErrWidgetOriginNotAllowed)
+			c.Abort()
+			return
+		}
+
+		allowWidgetOrigin(c, origin)
+		c.Set(widgetTokenKey, widgetToken)
+		c.Next()
+	}
+}
+
+func IssueWidgetToken(appCtx components.AppContext, site eventDomain.Site) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.IssueWidgetTokenCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+		req.Origin = c.GetHeader("Origin")
+
+		handler := command.NewIssueWidgetTokenHandler(
+			eventAdapters.NewPublicEventPostgresRepository(appCtx.GetDB()),
+			adapters.NewWidgetOriginPostgresRepository(appCtx.GetDB()),
+			adapters.NewRedisWidgetTokenStore(appCtx.GetRedis()),
+			site,
+		)
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		// the origin was checked against the ones of the organizer
+		allowWidgetOrigin(c, c.GetHeader("Origin"))
+		httpresponse.Success(c, http.StatusCreated, result)
+	}
+}
+
+// GetWidgetAvailability returns the event of the widget token with the availability of its tickets. The
+// widget follows the event when its slug is renamed after the token was issued.
+func GetWidgetAvailability(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		widgetToken := c.MustGet(widgetTokenKey).(*domain.WidgetToken)
+
+		// the widget is embedded in the site of the organizer, so the page has no canonical URL of ours
+		handler := eventQuery.NewGetPublicEventHandler(
+			eventAdapters.NewPublicEventPostgresRepository(appCtx.GetDB()),
+			eventAdapters.NewEventSEOPostgresRepository(appCtx.GetDB()),
+			eventDomain.Site{},
+		)
+
+		result, err := handler.Handle(c.Request.Context(), eventQuery.GetPublicEventQuery{Slug: widgetToken.EventSlug})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func ListWidgetOrigins(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		organizerID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		handler := query.NewListWidgetOriginsHandler(adapters.NewWidgetOriginPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), query.ListWidgetOriginsQuery{OrganizerID: organizerID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func AddWidgetOrigin(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.AddWidgetOriginCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		organizerID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.OrganizerID = organizerID
+
+		handler := command.NewAddWidgetOriginHandler(adapters.NewWidgetOriginPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusCreated, result)
+	}
+}
+
+func DeleteWidgetOrigin(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		organizerID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		handler := command.NewDeleteWidgetOriginHandler(adapters.NewWidgetOriginPostgresRepository(appCtx.GetDB()))
+
+		err = handler.Handle(c.Request.Context(), command.DeleteWidgetOriginCommand{OrganizerID: organizerID, ID: id})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, true)
+	}
+}
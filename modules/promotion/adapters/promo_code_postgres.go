@@ -0,0 +1,223 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	eventDomain "tixgo/modules/event/domain"
+	"tixgo/modules/promotion/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/listing"
+	"tixgo/shared/pgerr"
+	"tixgo/shared/pgquery"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+// codeConstraint is the unique constraint of the codes
+const codeConstraint = "promo_codes_code_key"
+
+const promoCodeColumns = `id, code, description, discount_type, discount_value::TEXT, event_id, max_redemptions,
+	max_per_user, redemption_count, starts_at, expires_at, active, COALESCE(created_by, 0), created_at, updated_at`
+
+// PromoCodePostgresRepository implements the PromoCodeRepository interface using PostgreSQL
+type PromoCodePostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewPromoCodePostgresRepository creates a new PostgreSQL promo code repository
+func NewPromoCodePostgresRepository(db *sqlx.DB) *PromoCodePostgresRepository {
+	return &PromoCodePostgresRepository{db: db}
+}
+
+// Create stores a new promo code, ErrEventNotFound if the event it is restricted to does not exist
+func (r *PromoCodePostgresRepository) Create(ctx context.Context, promo *domain.PromoCode) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO promo_codes (code, description, discount_type, discount_value, event_id, max_redemptions,
+		                         max_per_user, starts_at, expires_at, active, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, 0))
+		RETURNING id, created_at, updated_at`,
+		promo.Code,
+		promo.Description,
+		promo.DiscountType,
+		promo.DiscountValue,
+		promo.EventID,
+		promo.MaxRedemptions,
+		promo.MaxPerUser,
+		promo.StartsAt,
+		promo.ExpiresAt,
+		promo.Active,
+		promo.CreatedBy,
+	).Scan(&promo.ID, &promo.CreatedAt, &promo.UpdatedAt)
+	if err != nil {
+		if pgerr.Constraint(err) == codeConstraint {
+			return domain.ErrCodeTaken
+		}
+		if pgerr.IsForeignKeyViolation(err) {
+			return eventDomain.ErrEventNotFound
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create promo code")
+	}
+
+	return nil
+}
+
+// GetByID retrieves a promo code
+func (r *PromoCodePostgresRepository) GetByID(ctx context.Context, id int64) (*domain.PromoCode, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return scanPromoCode(r.db.QueryRowContext(ctx, `SELECT `+promoCodeColumns+` FROM promo_codes WHERE id = $1`, id))
+}
+
+// GetByCode retrieves the promo code of a normalized code
+func (r *PromoCodePostgresRepository) GetByCode(ctx context.Context, code string) (*domain.PromoCode, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return scanPromoCode(r.db.QueryRowContext(ctx, `SELECT `+promoCodeColumns+` FROM promo_codes WHERE code = $1`, code))
+}
+
+// List retrieves a page of the promo codes, newest first
+func (r *PromoCodePostgresRepository) List(ctx context.Context, eventID *int64, paging *listing.Paging) ([]*domain.PromoCode, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	filter := &pgquery.Filter{}
+	if eventID != nil {
+		filter.Where("event_id = ?", *eventID)
+	}
+
+	if err := pgquery.Count(ctx, r.db, "promo_codes", filter, paging); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to count promo codes")
+	}
+
+	pageClause, args := filter.Paged(paging)
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT %s
+		FROM promo_codes
+		%s
+		ORDER BY created_at DESC, id DESC
+		%s`, promoCodeColumns, filter.Clause(), pageClause), args...)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list promo codes")
+	}
+	defer rows.Close()
+
+	var promos []*domain.PromoCode
+	for rows.Next() {
+		promo, err := scanPromoCode(rows)
+		if err != nil {
+			return nil, err
+		}
+		promos = append(promos, promo)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to iterate promo codes")
+	}
+
+	return promos[:paging.Fetched(len(promos))], nil
+}
+
+// Update saves the details of a promo code. The redemption count is left to the checkouts.
+func (r *PromoCodePostgresRepository) Update(ctx context.Context, promo *domain.PromoCode) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE promo_codes
+		SET code = $2, description = $3, discount_type = $4, discount_value = $5, event_id = $6,
+		    max_redemptions = $7, max_per_user = $8, starts_at = $9, expires_at = $10, active = $11,
+		    updated_at = NOW()
+		WHERE id = $1
+		RETURNING redemption_count, updated_at`,
+		promo.ID,
+		promo.Code,
+		promo.Description,
+		promo.DiscountType,
+		promo.DiscountValue,
+		promo.EventID,
+		promo.MaxRedemptions,
+		promo.MaxPerUser,
+		promo.StartsAt,
+		promo.ExpiresAt,
+		promo.Active,
+	).Scan(&promo.Redemptions, &promo.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrPromoCodeNotFound
+		}
+		if pgerr.Constraint(err) == codeConstraint {
+			return domain.ErrCodeTaken
+		}
+		if pgerr.IsForeignKeyViolation(err) {
+			return eventDomain.ErrEventNotFound
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to update promo code")
+	}
+
+	return nil
+}
+
+// Delete deletes a promo code. Its redemptions reference it, so a redeemed code fails to be deleted.
+func (r *PromoCodePostgresRepository) Delete(ctx context.Context, id int64) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM promo_codes WHERE id = $1`, id)
+	if err != nil {
+		if pgerr.IsForeignKeyViolation(err) {
+			return domain.ErrPromoCodeInUse
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to delete promo code")
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get rows affected")
+	}
+	if deleted == 0 {
+		return domain.ErrPromoCodeNotFound
+	}
+
+	return nil
+}
+
+// rowScanner is a single row or the current row of rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPromoCode(row rowScanner) (*domain.PromoCode, error) {
+	promo := &domain.PromoCode{}
+	err := row.Scan(
+		&promo.ID,
+		&promo.Code,
+		&promo.Description,
+		&promo.DiscountType,
+		&promo.DiscountValue,
+		&promo.EventID,
+		&promo.MaxRedemptions,
+		&promo.MaxPerUser,
+		&promo.Redemptions,
+		&promo.StartsAt,
+		&promo.ExpiresAt,
+		&promo.Active,
+		&promo.CreatedBy,
+		&promo.CreatedAt,
+		&promo.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrPromoCodeNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan promo code")
+	}
+	return promo, nil
+}
@@ -0,0 +1,80 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	eventDomain "tixgo/modules/event/domain"
+	"tixgo/modules/promotion/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// PromoCodeDetailsInput are the details of a promo code sent to create and update it
+type PromoCodeDetailsInput struct {
+	Code           string     `json:"code" binding:"required,max=50"`
+	Description    string     `json:"description" binding:"max=500"`
+	DiscountType   string     `json:"discount_type" binding:"required"`
+	DiscountValue  string     `json:"discount_value" binding:"required"`
+	EventID        *int64     `json:"event_id" binding:"omitempty,gt=0"`
+	MaxRedemptions *int       `json:"max_redemptions" binding:"omitempty,min=1"`
+	MaxPerUser     *int       `json:"max_per_user" binding:"omitempty,min=1"`
+	StartsAt       *time.Time `json:"starts_at"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	Active         *bool      `json:"active"`
+}
+
+// Details converts the input to the details of a domain promo code, active unless told otherwise
+func (in PromoCodeDetailsInput) Details() domain.PromoCodeDetails {
+	active := true
+	if in.Active != nil {
+		active = *in.Active
+	}
+	return domain.PromoCodeDetails{
+		Code:           in.Code,
+		Description:    in.Description,
+		DiscountType:   domain.DiscountType(in.DiscountType),
+		DiscountValue:  in.DiscountValue,
+		EventID:        in.EventID,
+		MaxRedemptions: in.MaxRedemptions,
+		MaxPerUser:     in.MaxPerUser,
+		StartsAt:       in.StartsAt,
+		ExpiresAt:      in.ExpiresAt,
+		Active:         active,
+	}
+}
+
+// CreatePromoCodeCommand represents the command of an admin to create a promo code
+type CreatePromoCodeCommand struct {
+	AdminID int64 `json:"-"`
+	PromoCodeDetailsInput
+}
+
+// CreatePromoCodeHandler handles promo code creation
+type CreatePromoCodeHandler struct {
+	promoRepo domain.PromoCodeRepository
+}
+
+// NewCreatePromoCodeHandler creates a new create promo code handler
+func NewCreatePromoCodeHandler(promoRepo domain.PromoCodeRepository) *CreatePromoCodeHandler {
+	return &CreatePromoCodeHandler{
+		promoRepo: promoRepo,
+	}
+}
+
+// Handle executes the create promo code command
+func (h *CreatePromoCodeHandler) Handle(ctx context.Context, cmd CreatePromoCodeCommand) (*PromoCodeResult, error) {
+	promo, err := domain.NewPromoCode(cmd.Details(), cmd.AdminID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.promoRepo.Create(ctx, promo); err != nil {
+		if err == domain.ErrCodeTaken || err == eventDomain.ErrEventNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to create promo code")
+	}
+
+	return ToPromoCodeResult(promo), nil
+}
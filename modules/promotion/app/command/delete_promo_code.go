@@ -0,0 +1,39 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/promotion/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// DeletePromoCodeCommand represents the command of an admin to delete a promo code
+type DeletePromoCodeCommand struct {
+	ID int64
+}
+
+// DeletePromoCodeHandler handles promo code deletion
+type DeletePromoCodeHandler struct {
+	promoRepo domain.PromoCodeRepository
+}
+
+// NewDeletePromoCodeHandler creates a new delete promo code handler
+func NewDeletePromoCodeHandler(promoRepo domain.PromoCodeRepository) *DeletePromoCodeHandler {
+	return &DeletePromoCodeHandler{
+		promoRepo: promoRepo,
+	}
+}
+
+// Handle executes the delete promo code command. Redeemed codes stay for the orders placed with them,
+// they are deactivated instead.
+func (h *DeletePromoCodeHandler) Handle(ctx context.Context, cmd DeletePromoCodeCommand) error {
+	if err := h.promoRepo.Delete(ctx, cmd.ID); err != nil {
+		if err == domain.ErrPromoCodeNotFound || err == domain.ErrPromoCodeInUse {
+			return err
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to delete promo code")
+	}
+
+	return nil
+}
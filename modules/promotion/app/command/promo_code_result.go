@@ -0,0 +1,53 @@
+package command
+
+import (
+	"time"
+
+	"tixgo/modules/promotion/domain"
+)
+
+// PromoCodeResult represents a promo code with its redemptions
+type PromoCodeResult struct {
+	ID             int64               `json:"id"`
+	Code           string              `json:"code"`
+	Description    string              `json:"description"`
+	DiscountType   domain.DiscountType `json:"discount_type"`
+	DiscountValue  string              `json:"discount_value"`
+	EventID        *int64              `json:"event_id"`
+	MaxRedemptions *int                `json:"max_redemptions"`
+	MaxPerUser     *int                `json:"max_per_user"`
+	Redemptions    int                 `json:"redemptions"`
+	StartsAt       *string             `json:"starts_at"`
+	ExpiresAt      *string             `json:"expires_at"`
+	Active         bool                `json:"active"`
+	CreatedAt      string              `json:"created_at"`
+	UpdatedAt      string              `json:"updated_at"`
+}
+
+// ToPromoCodeResult converts a promo code to its result
+func ToPromoCodeResult(promo *domain.PromoCode) *PromoCodeResult {
+	return &PromoCodeResult{
+		ID:             promo.ID,
+		Code:           promo.Code,
+		Description:    promo.Description,
+		DiscountType:   promo.DiscountType,
+		DiscountValue:  promo.DiscountValue,
+		EventID:        promo.EventID,
+		MaxRedemptions: promo.MaxRedemptions,
+		MaxPerUser:     promo.MaxPerUser,
+		Redemptions:    promo.Redemptions,
+		StartsAt:       formatTime(promo.StartsAt),
+		ExpiresAt:      formatTime(promo.ExpiresAt),
+		Active:         promo.Active,
+		CreatedAt:      promo.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:      promo.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+func formatTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := t.Format("2006-01-02T15:04:05Z")
+	return &formatted
+}
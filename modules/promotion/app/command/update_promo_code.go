@@ -0,0 +1,52 @@
+package command
+
+import (
+	"context"
+
+	eventDomain "tixgo/modules/event/domain"
+	"tixgo/modules/promotion/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// UpdatePromoCodeCommand represents the command of an admin to change a promo code
+type UpdatePromoCodeCommand struct {
+	ID int64 `json:"-"`
+	PromoCodeDetailsInput
+}
+
+// UpdatePromoCodeHandler handles promo code updates
+type UpdatePromoCodeHandler struct {
+	promoRepo domain.PromoCodeRepository
+}
+
+// NewUpdatePromoCodeHandler creates a new update promo code handler
+func NewUpdatePromoCodeHandler(promoRepo domain.PromoCodeRepository) *UpdatePromoCodeHandler {
+	return &UpdatePromoCodeHandler{
+		promoRepo: promoRepo,
+	}
+}
+
+// Handle executes the update promo code command. The orders already placed keep their discount.
+func (h *UpdatePromoCodeHandler) Handle(ctx context.Context, cmd UpdatePromoCodeCommand) (*PromoCodeResult, error) {
+	promo, err := h.promoRepo.GetByID(ctx, cmd.ID)
+	if err != nil {
+		if err == domain.ErrPromoCodeNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get promo code")
+	}
+
+	if err := promo.Update(cmd.Details()); err != nil {
+		return nil, err
+	}
+
+	if err := h.promoRepo.Update(ctx, promo); err != nil {
+		if err == domain.ErrPromoCodeNotFound || err == domain.ErrCodeTaken || err == eventDomain.ErrEventNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to update promo code")
+	}
+
+	return ToPromoCodeResult(promo), nil
+}
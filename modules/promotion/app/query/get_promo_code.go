@@ -0,0 +1,40 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/promotion/app/command"
+	"tixgo/modules/promotion/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// GetPromoCodeQuery represents the query for a promo code
+type GetPromoCodeQuery struct {
+	ID int64
+}
+
+// GetPromoCodeHandler handles getting a promo code
+type GetPromoCodeHandler struct {
+	promoRepo domain.PromoCodeRepository
+}
+
+// NewGetPromoCodeHandler creates a new get promo code handler
+func NewGetPromoCodeHandler(promoRepo domain.PromoCodeRepository) *GetPromoCodeHandler {
+	return &GetPromoCodeHandler{
+		promoRepo: promoRepo,
+	}
+}
+
+// Handle executes the get promo code query
+func (h *GetPromoCodeHandler) Handle(ctx context.Context, query GetPromoCodeQuery) (*command.PromoCodeResult, error) {
+	promo, err := h.promoRepo.GetByID(ctx, query.ID)
+	if err != nil {
+		if err == domain.ErrPromoCodeNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get promo code")
+	}
+
+	return command.ToPromoCodeResult(promo), nil
+}
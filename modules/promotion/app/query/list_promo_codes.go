@@ -0,0 +1,54 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/promotion/app/command"
+	"tixgo/modules/promotion/domain"
+	"tixgo/shared/listing"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// maxPromoCodePageSize bounds the promo codes returned per page
+const maxPromoCodePageSize = 100
+
+// ListPromoCodesQuery represents the query to list the promo codes, the ones of an event when EventID is set
+type ListPromoCodesQuery struct {
+	EventID *int64 `json:"event_id" form:"event_id" binding:"omitempty,gt=0"`
+}
+
+// ListPromoCodesHandler handles listing the promo codes
+type ListPromoCodesHandler struct {
+	promoRepo domain.PromoCodeRepository
+}
+
+// NewListPromoCodesHandler creates a new list promo codes handler
+func NewListPromoCodesHandler(promoRepo domain.PromoCodeRepository) *ListPromoCodesHandler {
+	return &ListPromoCodesHandler{
+		promoRepo: promoRepo,
+	}
+}
+
+// Handle executes the list promo codes query
+func (h *ListPromoCodesHandler) Handle(ctx context.Context, query *ListPromoCodesQuery, paging *listing.Paging) ([]*command.PromoCodeResult, error) {
+	// Ensure paging is not nil (should already be handled in HTTP layer)
+	if paging == nil {
+		paging = &listing.Paging{}
+		paging.Fulfill()
+	}
+	if paging.Limit > maxPromoCodePageSize {
+		paging.Limit = maxPromoCodePageSize
+	}
+
+	promos, err := h.promoRepo.List(ctx, query.EventID, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list promo codes")
+	}
+
+	results := make([]*command.PromoCodeResult, len(promos))
+	for i, promo := range promos {
+		results[i] = command.ToPromoCodeResult(promo)
+	}
+	return results, nil
+}
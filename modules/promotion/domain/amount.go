@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// amountPattern matches the amounts DECIMAL(10, 2) columns hold
+var amountPattern = regexp.MustCompile(`^\d{1,8}(\.\d{1,2})?$`)
+
+// ParseCents parses a decimal amount with at most 2 decimals into cents, so discounts are computed
+// without floats
+func ParseCents(amount string) (int64, error) {
+	if !amountPattern.MatchString(amount) {
+		return 0, fmt.Errorf("invalid amount %q", amount)
+	}
+
+	units, fraction, _ := strings.Cut(amount, ".")
+	fraction = (fraction + "00")[:2]
+	return strconv.ParseInt(units+fraction, 10, 64)
+}
+
+// FormatCents formats cents as a decimal amount with 2 decimals
+func FormatCents(cents int64) string {
+	return fmt.Sprintf("%d.%02d", cents/100, cents%100)
+}
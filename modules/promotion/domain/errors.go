@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"tixgo/shared/errstack"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// Promotion domain errors
+var (
+	ErrPromoCodeNotFound      = errstack.Sentinel(syserr.NotFoundCode, "promo code not found")
+	ErrPromoCodeNotApplicable = errstack.Sentinel(syserr.InvalidArgumentCode, "the promo code is not valid for this event")
+	ErrPromoCodeExpired       = errstack.Sentinel(syserr.InvalidArgumentCode, "the promo code is not valid at this time")
+	ErrPromoCodeExhausted     = errstack.Sentinel(syserr.ConflictCode, "the promo code was redeemed as many times as allowed")
+	ErrPromoCodeUserLimit     = errstack.Sentinel(syserr.ConflictCode, "you redeemed this promo code as many times as allowed")
+	ErrPromoCodeInUse         = errstack.Sentinel(syserr.ConflictCode, "a redeemed promo code cannot be deleted, deactivate it instead")
+	ErrCodeTaken              = errstack.Sentinel(syserr.ConflictCode, "another promo code uses this code")
+	ErrInvalidCode            = errstack.Sentinel(syserr.InvalidArgumentCode, "code must be 3 to 50 letters, digits, dashes or underscores")
+	ErrInvalidDiscountType    = errstack.Sentinel(syserr.InvalidArgumentCode, "discount_type must be percentage or fixed_amount")
+	ErrInvalidDiscountValue   = errstack.Sentinel(syserr.InvalidArgumentCode, "discount_value must be a positive amount with at most 2 decimals, a percentage at most 100")
+	ErrInvalidValidity        = errstack.Sentinel(syserr.InvalidArgumentCode, "the promo code must expire after it starts")
+)
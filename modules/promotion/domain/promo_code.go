@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"tixgo/shared/listing"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// DiscountType is how a promo code discounts an order
+type DiscountType string
+
+const (
+	// DiscountPercentage takes a percentage off the total of the order
+	DiscountPercentage DiscountType = "percentage"
+	// DiscountFixedAmount takes an amount off the total of the order, in its currency, at most the total
+	DiscountFixedAmount DiscountType = "fixed_amount"
+)
+
+// IsValid checks if the discount type is valid
+func (t DiscountType) IsValid() bool {
+	return t == DiscountPercentage || t == DiscountFixedAmount
+}
+
+// codePattern matches the codes customers can type: letters, digits, dashes and underscores
+var codePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,50}$`)
+
+// NormalizeCode returns the form codes are stored and looked up in, so they match whatever their case
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// PromoCodeDetails are the details of a promo code an admin sets when creating and updating it
+type PromoCodeDetails struct {
+	Code          string
+	Description   string
+	DiscountType  DiscountType
+	DiscountValue string
+	// EventID restricts the code to the orders of an event, nil for a code valid for every event
+	EventID *int64
+	// MaxRedemptions bounds the orders the code is redeemed by, MaxPerUser the ones of each customer;
+	// nil for no limit
+	MaxRedemptions *int
+	MaxPerUser     *int
+	StartsAt       *time.Time
+	ExpiresAt      *time.Time
+	Active         bool
+}
+
+// PromoCode is a code customers enter at checkout for a discount on their order
+type PromoCode struct {
+	ID int64
+	PromoCodeDetails
+	// Redemptions counts the orders holding the code: pending checkouts and placed orders, the expired
+	// checkouts giving theirs back
+	Redemptions int
+	CreatedBy   int64
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewPromoCode creates a promo code of an admin
+func NewPromoCode(details PromoCodeDetails, createdBy int64) (*PromoCode, error) {
+	promo := &PromoCode{CreatedBy: createdBy}
+	if err := promo.Update(details); err != nil {
+		return nil, err
+	}
+	return promo, nil
+}
+
+// Update changes the details of the promo code. Lowering the limits below the redemptions made stops
+// further redemptions and keeps the ones made.
+func (p *PromoCode) Update(details PromoCodeDetails) error {
+	details.Code = NormalizeCode(details.Code)
+	if !codePattern.MatchString(details.Code) {
+		return ErrInvalidCode
+	}
+	if !details.DiscountType.IsValid() {
+		return ErrInvalidDiscountType
+	}
+
+	value, err := ParseCents(details.DiscountValue)
+	if err != nil || value <= 0 {
+		return ErrInvalidDiscountValue
+	}
+	if details.DiscountType == DiscountPercentage && value > 100*100 {
+		return ErrInvalidDiscountValue
+	}
+	details.DiscountValue = FormatCents(value)
+
+	if details.MaxRedemptions != nil && *details.MaxRedemptions < 1 || details.MaxPerUser != nil && *details.MaxPerUser < 1 {
+		return syserr.New(syserr.InvalidArgumentCode, "usage limits must be at least 1")
+	}
+	if details.StartsAt != nil && details.ExpiresAt != nil && !details.ExpiresAt.After(*details.StartsAt) {
+		return ErrInvalidValidity
+	}
+
+	p.PromoCodeDetails = details
+	return nil
+}
+
+// CheckRedeemable tells whether the code can be redeemed by an order of the event at now. The usage
+// limits are checked again when the redemption is recorded, which concurrent checkouts race for.
+func (p *PromoCode) CheckRedeemable(eventID int64, now time.Time) error {
+	if !p.Active {
+		return ErrPromoCodeNotFound
+	}
+	if p.EventID != nil && *p.EventID != eventID {
+		return ErrPromoCodeNotApplicable
+	}
+	if p.StartsAt != nil && now.Before(*p.StartsAt) || p.ExpiresAt != nil && !now.Before(*p.ExpiresAt) {
+		return ErrPromoCodeExpired
+	}
+	if p.MaxRedemptions != nil && p.Redemptions >= *p.MaxRedemptions {
+		return ErrPromoCodeExhausted
+	}
+	return nil
+}
+
+// Discount returns the discount of the code on an order totaling total, both decimal amounts. A
+// percentage is rounded half up to the cent; a fixed amount never takes more than the total.
+func (p *PromoCode) Discount(total string) (string, error) {
+	totalCents, err := ParseCents(total)
+	if err != nil {
+		return "", err
+	}
+	value, err := ParseCents(p.DiscountValue)
+	if err != nil {
+		return "", err
+	}
+
+	var discount int64
+	switch p.DiscountType {
+	case DiscountPercentage:
+		// value is in hundredths of a percent
+		discount = (totalCents*value + 5000) / 10000
+	default:
+		discount = min(value, totalCents)
+	}
+	return FormatCents(discount), nil
+}
+
+// PromoCodeRepository defines the interface for promo code persistence
+type PromoCodeRepository interface {
+	// Create stores a new promo code, ErrCodeTaken if its code is used by another one
+	Create(ctx context.Context, promo *PromoCode) error
+
+	// GetByID retrieves a promo code
+	GetByID(ctx context.Context, id int64) (*PromoCode, error)
+
+	// GetByCode retrieves the promo code of a normalized code
+	GetByCode(ctx context.Context, code string) (*PromoCode, error)
+
+	// List retrieves a page of the promo codes, newest first, the ones of an event only when eventID is set
+	List(ctx context.Context, eventID *int64, paging *listing.Paging) ([]*PromoCode, error)
+
+	// Update saves the details of a promo code, ErrCodeTaken if its code is used by another one
+	Update(ctx context.Context, promo *PromoCode) error
+
+	// Delete deletes a promo code, ErrPromoCodeInUse once it was redeemed
+	Delete(ctx context.Context, id int64) error
+}
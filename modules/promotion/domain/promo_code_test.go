@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestNewPromoCode(t *testing.T) {
+	promo, err := NewPromoCode(PromoCodeDetails{Code: " summer-25 ", DiscountType: DiscountPercentage, DiscountValue: "12.5", Active: true}, 7)
+	require.NoError(t, err)
+	assert.Equal(t, "SUMMER-25", promo.Code)
+	assert.Equal(t, "12.50", promo.DiscountValue)
+	assert.Equal(t, int64(7), promo.CreatedBy)
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		details PromoCodeDetails
+		want    error
+	}{
+		{name: "short code", details: PromoCodeDetails{Code: "AB", DiscountType: DiscountPercentage, DiscountValue: "10"}, want: ErrInvalidCode},
+		{name: "code with spaces", details: PromoCodeDetails{Code: "SUMMER SALE", DiscountType: DiscountPercentage, DiscountValue: "10"}, want: ErrInvalidCode},
+		{name: "unknown type", details: PromoCodeDetails{Code: "SUMMER", DiscountType: "bogo", DiscountValue: "10"}, want: ErrInvalidDiscountType},
+		{name: "zero value", details: PromoCodeDetails{Code: "SUMMER", DiscountType: DiscountFixedAmount, DiscountValue: "0"}, want: ErrInvalidDiscountValue},
+		{name: "three decimals", details: PromoCodeDetails{Code: "SUMMER", DiscountType: DiscountFixedAmount, DiscountValue: "1.005"}, want: ErrInvalidDiscountValue},
+		{name: "above 100 percent", details: PromoCodeDetails{Code: "SUMMER", DiscountType: DiscountPercentage, DiscountValue: "100.01"}, want: ErrInvalidDiscountValue},
+		{name: "expires before it starts", details: PromoCodeDetails{Code: "SUMMER", DiscountType: DiscountPercentage, DiscountValue: "10", StartsAt: &now, ExpiresAt: &now}, want: ErrInvalidValidity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPromoCode(tt.details, 7)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+
+	_, err = NewPromoCode(PromoCodeDetails{Code: "SUMMER", DiscountType: DiscountPercentage, DiscountValue: "10", MaxPerUser: ptr(0)}, 7)
+	assert.Error(t, err)
+}
+
+func TestPromoCode_CheckRedeemable(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	later := now.Add(time.Hour)
+
+	promo := func() *PromoCode {
+		return &PromoCode{PromoCodeDetails: PromoCodeDetails{Active: true, EventID: ptr(int64(3)), MaxRedemptions: ptr(2), ExpiresAt: &later}, Redemptions: 1}
+	}
+	require.NoError(t, promo().CheckRedeemable(3, now))
+
+	tests := []struct {
+		name    string
+		modify  func(p *PromoCode)
+		eventID int64
+		want    error
+	}{
+		{name: "inactive", modify: func(p *PromoCode) { p.Active = false }, eventID: 3, want: ErrPromoCodeNotFound},
+		{name: "other event", eventID: 4, want: ErrPromoCodeNotApplicable},
+		{name: "not started", modify: func(p *PromoCode) { p.StartsAt = &later; p.ExpiresAt = nil }, eventID: 3, want: ErrPromoCodeExpired},
+		{name: "expired", modify: func(p *PromoCode) { p.ExpiresAt = &now }, eventID: 3, want: ErrPromoCodeExpired},
+		{name: "exhausted", modify: func(p *PromoCode) { p.Redemptions = 2 }, eventID: 3, want: ErrPromoCodeExhausted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := promo()
+			if tt.modify != nil {
+				tt.modify(p)
+			}
+			assert.ErrorIs(t, p.CheckRedeemable(tt.eventID, now), tt.want)
+		})
+	}
+
+	global := promo()
+	global.EventID = nil
+	assert.NoError(t, global.CheckRedeemable(9, now))
+}
+
+func TestPromoCode_Discount(t *testing.T) {
+	tests := []struct {
+		discountType DiscountType
+		value        string
+		total        string
+		want         string
+	}{
+		{DiscountPercentage, "10.00", "59.90", "5.99"},
+		{DiscountPercentage, "12.50", "0.99", "0.12"},
+		{DiscountPercentage, "15.00", "0.10", "0.02"},
+		{DiscountPercentage, "100.00", "42.00", "42.00"},
+		{DiscountFixedAmount, "5.00", "42.00", "5.00"},
+		{DiscountFixedAmount, "50.00", "42.00", "42.00"},
+		{DiscountFixedAmount, "5.00", "0.00", "0.00"},
+	}
+	for _, tt := range tests {
+		promo := &PromoCode{PromoCodeDetails: PromoCodeDetails{DiscountType: tt.discountType, DiscountValue: tt.value}}
+		discount, err := promo.Discount(tt.total)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, discount, "%s %s of %s", tt.discountType, tt.value, tt.total)
+	}
+}
+
+func TestParseCents(t *testing.T) {
+	tests := []struct {
+		amount    string
+		cents     int64
+		formatted string
+	}{
+		{"0", 0, "0.00"},
+		{"7", 700, "7.00"},
+		{"7.5", 750, "7.50"},
+		{"7.05", 705, "7.05"},
+		{"12345678.99", 1234567899, "12345678.99"},
+	}
+	for _, tt := range tests {
+		cents, err := ParseCents(tt.amount)
+		require.NoError(t, err, tt.amount)
+		assert.Equal(t, tt.cents, cents, tt.amount)
+		assert.Equal(t, tt.formatted, FormatCents(cents), tt.amount)
+	}
+
+	for _, amount := range []string{"", "-1", "1.", ".5", "1.234", "1e3", "123456789"} {
+		_, err := ParseCents(amount)
+		assert.Error(t, err, amount)
+	}
+}
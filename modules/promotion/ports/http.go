@@ -0,0 +1,156 @@
+package ports
+
+import (
+	"net/http"
+	"strconv"
+
+	"tixgo/components"
+	"tixgo/modules/promotion/adapters"
+	"tixgo/modules/promotion/app/command"
+	"tixgo/modules/promotion/app/query"
+	userDomain "tixgo/modules/user/domain"
+	"tixgo/shared/apiversion"
+	"tixgo/shared/authz"
+	"tixgo/shared/httpresponse"
+	"tixgo/shared/listing"
+	"tixgo/shared/session"
+
+	"github.com/duongptryu/gox/context"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterPromotionRoutes(router *apiversion.Group, appCtx components.AppContext) {
+	adminGroup := router.Group("/admin/promo-codes")
+	{
+		adminGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		adminGroup.Use(authz.RequireUserType(string(userDomain.UserTypeAdmin)))
+		adminGroup.POST("", CreatePromoCode(appCtx))
+		adminGroup.GET("", ListPromoCodes(appCtx))
+		adminGroup.GET("/:id", GetPromoCode(appCtx))
+		adminGroup.PUT("/:id", UpdatePromoCode(appCtx))
+		adminGroup.DELETE("/:id", DeletePromoCode(appCtx))
+	}
+}
+
+func CreatePromoCode(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.CreatePromoCodeCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		adminID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.AdminID = adminID
+
+		handler := command.NewCreatePromoCodeHandler(adapters.NewPromoCodePostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusCreated, result)
+	}
+}
+
+func ListPromoCodes(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var filters query.ListPromoCodesQuery
+		if err := c.ShouldBind(&filters); err != nil {
+			c.Error(err)
+			return
+		}
+
+		var paging listing.Paging
+		if err := c.ShouldBind(&paging); err != nil {
+			c.Error(err)
+			return
+		}
+
+		// Apply pagination defaults in HTTP layer
+		paging.Fulfill()
+
+		handler := query.NewListPromoCodesHandler(adapters.NewPromoCodePostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), &filters, &paging)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.List(c, result, paging, filters)
+	}
+}
+
+func GetPromoCode(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		handler := query.NewGetPromoCodeHandler(adapters.NewPromoCodePostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), query.GetPromoCodeQuery{ID: id})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func UpdatePromoCode(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		var req command.UpdatePromoCodeCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+		req.ID = id
+
+		handler := command.NewUpdatePromoCodeHandler(adapters.NewPromoCodePostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func DeletePromoCode(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		handler := command.NewDeletePromoCodeHandler(adapters.NewPromoCodePostgresRepository(appCtx.GetDB()))
+
+		if err := handler.Handle(c.Request.Context(), command.DeletePromoCodeCommand{ID: id}); err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, true)
+	}
+}
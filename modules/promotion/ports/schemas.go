@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"tixgo/modules/promotion/app/command"
+	"tixgo/modules/promotion/app/query"
+	"tixgo/shared/jsonschema"
+	"tixgo/shared/listing"
+)
+
+// Schemas returns the request payloads of the promotion module
+func Schemas() []jsonschema.Payload {
+	return []jsonschema.Payload{
+		{Name: "admin.promo_codes.create", In: jsonschema.Body, Example: command.CreatePromoCodeCommand{}},
+		{Name: "admin.promo_codes.update", In: jsonschema.Body, Example: command.UpdatePromoCodeCommand{}},
+		{Name: "admin.promo_codes", In: jsonschema.Query, Example: struct {
+			query.ListPromoCodesQuery
+			listing.Paging
+		}{}},
+	}
+}
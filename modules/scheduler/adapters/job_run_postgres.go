@@ -0,0 +1,188 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"tixgo/modules/scheduler/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/listing"
+	"tixgo/shared/pgquery"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// JobRunPostgresRepository implements the JobRunRepository interface using PostgreSQL
+type JobRunPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewJobRunPostgresRepository creates a new PostgreSQL job run repository
+func NewJobRunPostgresRepository(db *sqlx.DB) *JobRunPostgresRepository {
+	return &JobRunPostgresRepository{db: db}
+}
+
+// Create creates a new job run in the database
+func (r *JobRunPostgresRepository) Create(ctx context.Context, run *domain.JobRun) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		INSERT INTO job_runs (job_name, trigger, status, started_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`
+
+	err := r.db.QueryRowContext(ctx, query, run.JobName, run.Trigger, run.Status, run.StartedAt).Scan(&run.ID)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create job run")
+	}
+
+	return nil
+}
+
+// Update updates the status of an existing job run
+func (r *JobRunPostgresRepository) Update(ctx context.Context, run *domain.JobRun) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		UPDATE job_runs
+		SET status = $2, error = $3, finished_at = $4
+		WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, run.ID, run.Status, run.Error, run.FinishedAt)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to update job run")
+	}
+
+	return nil
+}
+
+// List retrieves job runs with pagination and filters, newest first
+func (r *JobRunPostgresRepository) List(ctx context.Context, filters domain.ListJobRunFilters, paging *listing.Paging) ([]*domain.JobRun, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	filter := &pgquery.Filter{}
+
+	if filters.JobName != "" {
+		filter.Where("job_name = ?", filters.JobName)
+	}
+
+	if filters.Status != nil {
+		filter.Where("status = ?", *filters.Status)
+	}
+
+	// Set total in paging
+	err := pgquery.Count(ctx, r.db, "job_runs", filter, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to count job runs")
+	}
+
+	// Main query
+	pageClause, args := filter.Paged(paging)
+	query := fmt.Sprintf(`
+		SELECT id, job_name, trigger, status, error, started_at, finished_at
+		FROM job_runs
+		%s
+		ORDER BY started_at DESC
+		%s`, filter.Clause(), pageClause)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list job runs")
+	}
+	defer rows.Close()
+
+	var runs []*domain.JobRun
+	for rows.Next() {
+		run, err := scanJobRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "error iterating job run rows")
+	}
+
+	return runs[:paging.Fetched(len(runs))], nil
+}
+
+// GetLatestByJobNames retrieves the latest run of each given job
+func (r *JobRunPostgresRepository) GetLatestByJobNames(ctx context.Context, jobNames []string) (map[string]*domain.JobRun, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT DISTINCT ON (job_name) id, job_name, trigger, status, error, started_at, finished_at
+		FROM job_runs
+		WHERE job_name = ANY($1)
+		ORDER BY job_name, started_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(jobNames))
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get latest job runs")
+	}
+	defer rows.Close()
+
+	runs := make(map[string]*domain.JobRun, len(jobNames))
+	for rows.Next() {
+		run, err := scanJobRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs[run.JobName] = run
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "error iterating job run rows")
+	}
+
+	return runs, nil
+}
+
+// DeleteFinishedBefore deletes runs finished before the given time
+func (r *JobRunPostgresRepository) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `DELETE FROM job_runs WHERE finished_at < $1`
+
+	result, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, syserr.Wrap(err, syserr.InternalCode, "failed to delete job runs")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, syserr.Wrap(err, syserr.InternalCode, "failed to get rows affected")
+	}
+
+	return rowsAffected, nil
+}
+
+func scanJobRun(rows *sql.Rows) (*domain.JobRun, error) {
+	run := &domain.JobRun{}
+	var runError sql.NullString
+	err := rows.Scan(
+		&run.ID,
+		&run.JobName,
+		&run.Trigger,
+		&run.Status,
+		&runError,
+		&run.StartedAt,
+		&run.FinishedAt,
+	)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan job run")
+	}
+	run.Error = runError.String
+
+	return run, nil
+}
@@ -0,0 +1,43 @@
+package adapters
+
+import (
+	"context"
+
+	"tixgo/modules/scheduler/domain"
+	"tixgo/shared/scheduler"
+)
+
+// JobRunRecorder records the scheduler run history into the job run repository
+type JobRunRecorder struct {
+	jobRunRepo domain.JobRunRepository
+}
+
+// NewJobRunRecorder creates a new job run recorder
+func NewJobRunRecorder(jobRunRepo domain.JobRunRepository) *JobRunRecorder {
+	return &JobRunRecorder{jobRunRepo: jobRunRepo}
+}
+
+// RecordStart stores the run as running and keeps its ID on the scheduler run
+func (r *JobRunRecorder) RecordStart(ctx context.Context, run *scheduler.Run) error {
+	jobRun := domain.NewJobRun(run.JobName, string(run.Trigger), run.StartedAt)
+	if err := r.jobRunRepo.Create(ctx, jobRun); err != nil {
+		return err
+	}
+
+	run.ID = jobRun.ID
+	return nil
+}
+
+// RecordFinish stores the outcome of the run
+func (r *JobRunRecorder) RecordFinish(ctx context.Context, run *scheduler.Run) error {
+	// the start could not be recorded, there is no row to update
+	if run.ID == 0 {
+		return nil
+	}
+
+	jobRun := domain.NewJobRun(run.JobName, string(run.Trigger), run.StartedAt)
+	jobRun.ID = run.ID
+	jobRun.Finish(run.FinishedAt, run.Err)
+
+	return r.jobRunRepo.Update(ctx, jobRun)
+}
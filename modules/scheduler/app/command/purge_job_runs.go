@@ -0,0 +1,36 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/scheduler/domain"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// PurgeJobRunsHandler deletes the job run history older than the retention
+type PurgeJobRunsHandler struct {
+	jobRunRepo domain.JobRunRepository
+	retention  time.Duration
+}
+
+// NewPurgeJobRunsHandler creates a new purge job runs handler
+func NewPurgeJobRunsHandler(jobRunRepo domain.JobRunRepository, retention time.Duration) *PurgeJobRunsHandler {
+	return &PurgeJobRunsHandler{
+		jobRunRepo: jobRunRepo,
+		retention:  retention,
+	}
+}
+
+// Handle executes the purge
+func (h *PurgeJobRunsHandler) Handle(ctx context.Context) error {
+	deleted, err := h.jobRunRepo.DeleteFinishedBefore(ctx, time.Now().Add(-h.retention))
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to purge job runs")
+	}
+
+	logger.Info(ctx, "Purged job runs", logger.F("deleted", deleted), logger.F("retention", h.retention))
+	return nil
+}
@@ -0,0 +1,50 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/scheduler/domain"
+	"tixgo/shared/scheduler"
+
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// TriggerJobCommand asks the worker to run a scheduled job right away
+type TriggerJobCommand struct {
+	JobName string `json:"job_name"`
+}
+
+// TriggerJobHandler handles triggering scheduled jobs from the API
+type TriggerJobHandler struct {
+	jobs       []scheduler.Job
+	commandBus messaging.CommandBus
+}
+
+// NewTriggerJobHandler creates a new trigger job handler
+func NewTriggerJobHandler(jobs []scheduler.Job, commandBus messaging.CommandBus) *TriggerJobHandler {
+	return &TriggerJobHandler{
+		jobs:       jobs,
+		commandBus: commandBus,
+	}
+}
+
+// Handle validates the job and forwards the trigger to the worker hosting the scheduler
+func (h *TriggerJobHandler) Handle(ctx context.Context, cmd *TriggerJobCommand) error {
+	found := false
+	for _, job := range h.jobs {
+		if job.Name == cmd.JobName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return domain.ErrJobNotFound
+	}
+
+	if err := h.commandBus.PublishCommand(ctx, cmd); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to trigger job")
+	}
+
+	return nil
+}
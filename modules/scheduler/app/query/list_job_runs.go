@@ -0,0 +1,89 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/scheduler/domain"
+	"tixgo/shared/listing"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// FilterJobRunsQuery represents the filters for listing job runs
+type FilterJobRunsQuery struct {
+	JobName string  `json:"-" form:"-"`
+	Status  *string `json:"status" form:"status"`
+}
+
+// JobRunItem represents a job run item in the list
+type JobRunItem struct {
+	ID         int64               `json:"id"`
+	JobName    string              `json:"job_name"`
+	Trigger    string              `json:"trigger"`
+	Status     domain.JobRunStatus `json:"status"`
+	Error      string              `json:"error,omitempty"`
+	StartedAt  string              `json:"started_at"`
+	FinishedAt *string             `json:"finished_at"`
+}
+
+// ListJobRunsHandler handles listing the run history of a job
+type ListJobRunsHandler struct {
+	jobRunRepo domain.JobRunRepository
+}
+
+// NewListJobRunsHandler creates a new list job runs handler
+func NewListJobRunsHandler(jobRunRepo domain.JobRunRepository) *ListJobRunsHandler {
+	return &ListJobRunsHandler{
+		jobRunRepo: jobRunRepo,
+	}
+}
+
+// Handle executes the list job runs query
+func (h *ListJobRunsHandler) Handle(ctx context.Context, filters *FilterJobRunsQuery, paging *listing.Paging) ([]JobRunItem, error) {
+	// Ensure paging is not nil (should already be handled in HTTP layer)
+	if paging == nil {
+		paging = &listing.Paging{}
+		paging.Fulfill()
+	}
+
+	domainFilters := domain.ListJobRunFilters{
+		JobName: filters.JobName,
+	}
+
+	// Set status filter
+	if filters.Status != nil && *filters.Status != "" {
+		if !domain.IsValidJobRunStatus(*filters.Status) {
+			return nil, domain.ErrInvalidJobRunStatus
+		}
+		status := domain.JobRunStatus(*filters.Status)
+		domainFilters.Status = &status
+	}
+
+	runs, err := h.jobRunRepo.List(ctx, domainFilters, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list job runs")
+	}
+
+	items := make([]JobRunItem, len(runs))
+	for i, run := range runs {
+		items[i] = toJobRunItem(run)
+	}
+
+	return items, nil
+}
+
+func toJobRunItem(run *domain.JobRun) JobRunItem {
+	item := JobRunItem{
+		ID:        run.ID,
+		JobName:   run.JobName,
+		Trigger:   run.Trigger,
+		Status:    run.Status,
+		Error:     run.Error,
+		StartedAt: run.StartedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if run.FinishedAt != nil {
+		finishedAt := run.FinishedAt.Format("2006-01-02T15:04:05Z")
+		item.FinishedAt = &finishedAt
+	}
+	return item
+}
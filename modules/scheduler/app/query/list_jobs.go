@@ -0,0 +1,67 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/scheduler/domain"
+	"tixgo/shared/scheduler"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// JobItem represents a scheduled job with its next and latest run
+type JobItem struct {
+	Name      string      `json:"name"`
+	Schedule  string      `json:"schedule"`
+	NextRunAt string      `json:"next_run_at"`
+	LastRun   *JobRunItem `json:"last_run"`
+}
+
+// ListJobsHandler handles listing the scheduled jobs
+type ListJobsHandler struct {
+	jobs       []scheduler.Job
+	jobRunRepo domain.JobRunRepository
+}
+
+// NewListJobsHandler creates a new list jobs handler
+func NewListJobsHandler(jobs []scheduler.Job, jobRunRepo domain.JobRunRepository) *ListJobsHandler {
+	return &ListJobsHandler{
+		jobs:       jobs,
+		jobRunRepo: jobRunRepo,
+	}
+}
+
+// Handle executes the list jobs query
+func (h *ListJobsHandler) Handle(ctx context.Context) ([]JobItem, error) {
+	names := make([]string, len(h.jobs))
+	for i, job := range h.jobs {
+		names[i] = job.Name
+	}
+
+	lastRuns, err := h.jobRunRepo.GetLatestByJobNames(ctx, names)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get latest job runs")
+	}
+
+	now := time.Now()
+	items := make([]JobItem, len(h.jobs))
+	for i, job := range h.jobs {
+		nextRun, err := job.NextRun(now)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to compute next job run")
+		}
+
+		items[i] = JobItem{
+			Name:      job.Name,
+			Schedule:  job.Schedule,
+			NextRunAt: nextRun.Format("2006-01-02T15:04:05Z"),
+		}
+		if run, ok := lastRuns[job.Name]; ok {
+			item := toJobRunItem(run)
+			items[i].LastRun = &item
+		}
+	}
+
+	return items, nil
+}
@@ -0,0 +1,13 @@
+package domain
+
+import (
+	"tixgo/shared/errstack"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// Scheduler domain errors
+var (
+	ErrJobNotFound         = errstack.Sentinel(syserr.NotFoundCode, "scheduled job not found")
+	ErrInvalidJobRunStatus = errstack.Sentinel(syserr.InvalidArgumentCode, "invalid job run status")
+)
@@ -0,0 +1,53 @@
+package domain
+
+import "time"
+
+// JobRunStatus represents the status of a job run
+type JobRunStatus string
+
+const (
+	JobRunStatusRunning   JobRunStatus = "running"
+	JobRunStatusSucceeded JobRunStatus = "succeeded"
+	JobRunStatusFailed    JobRunStatus = "failed"
+)
+
+// JobRun represents a single execution of a scheduled job
+type JobRun struct {
+	ID         int64
+	JobName    string
+	Trigger    string
+	Status     JobRunStatus
+	Error      string
+	StartedAt  time.Time
+	FinishedAt *time.Time
+}
+
+// NewJobRun creates a running job run
+func NewJobRun(jobName, trigger string, startedAt time.Time) *JobRun {
+	return &JobRun{
+		JobName:   jobName,
+		Trigger:   trigger,
+		Status:    JobRunStatusRunning,
+		StartedAt: startedAt,
+	}
+}
+
+// Finish marks the run as succeeded or failed depending on err
+func (r *JobRun) Finish(finishedAt time.Time, err error) {
+	r.FinishedAt = &finishedAt
+	r.Status = JobRunStatusSucceeded
+	if err != nil {
+		r.Status = JobRunStatusFailed
+		r.Error = err.Error()
+	}
+}
+
+// IsValidJobRunStatus checks if the job run status is valid
+func IsValidJobRunStatus(status string) bool {
+	switch JobRunStatus(status) {
+	case JobRunStatusRunning, JobRunStatusSucceeded, JobRunStatusFailed:
+		return true
+	default:
+		return false
+	}
+}
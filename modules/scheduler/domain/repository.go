@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"tixgo/shared/listing"
+)
+
+// JobRunRepository defines the interface for job run persistence
+type JobRunRepository interface {
+	// Create creates a new job run
+	Create(ctx context.Context, run *JobRun) error
+
+	// Update updates the status of an existing job run
+	Update(ctx context.Context, run *JobRun) error
+
+	// List retrieves job runs with pagination and filters, newest first
+	List(ctx context.Context, filters ListJobRunFilters, paging *listing.Paging) ([]*JobRun, error)
+
+	// GetLatestByJobNames retrieves the latest run of each given job
+	GetLatestByJobNames(ctx context.Context, jobNames []string) (map[string]*JobRun, error)
+
+	// DeleteFinishedBefore deletes runs finished before the given time and returns how many were removed
+	DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// ListJobRunFilters represents filters for listing job runs
+type ListJobRunFilters struct {
+	JobName string
+	Status  *JobRunStatus
+}
@@ -0,0 +1,45 @@
+package ports
+
+import (
+	"context"
+	"errors"
+
+	"tixgo/modules/scheduler/app/command"
+	"tixgo/shared/scheduler"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+)
+
+const (
+	CommandTriggerJob = "commands.TriggerJob"
+)
+
+type SchedulerMessagingHandlers struct {
+	dispatcher messaging.Dispatcher
+	scheduler  *scheduler.Scheduler
+}
+
+func NewSchedulerMessagingHandlers(dispatcher messaging.Dispatcher, scheduler *scheduler.Scheduler) *SchedulerMessagingHandlers {
+	return &SchedulerMessagingHandlers{
+		dispatcher: dispatcher,
+		scheduler:  scheduler,
+	}
+}
+
+func (h *SchedulerMessagingHandlers) RegisterSchedulerMessagingHandlers() {
+	commandProcessor := h.dispatcher.GetCommandProcessor()
+	commandProcessor.AddHandler(cqrs.NewCommandHandler(CommandTriggerJob, h.HandleCommandTriggerJob))
+}
+
+func (h *SchedulerMessagingHandlers) HandleCommandTriggerJob(ctx context.Context, cmd *command.TriggerJobCommand) error {
+	err := h.scheduler.Trigger(cmd.JobName)
+	if errors.Is(err, scheduler.ErrJobNotFound) {
+		// retrying cannot help, the worker does not host this job
+		logger.Warning(ctx, "Ignoring trigger of unknown job", logger.F("job", cmd.JobName))
+		return nil
+	}
+
+	return err
+}
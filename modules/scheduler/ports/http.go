@@ -0,0 +1,92 @@
+package ports
+
+import (
+	"net/http"
+
+	"tixgo/components"
+	"tixgo/modules/scheduler/adapters"
+	"tixgo/modules/scheduler/app/command"
+	"tixgo/modules/scheduler/app/query"
+	userDomain "tixgo/modules/user/domain"
+	"tixgo/shared/apiversion"
+	"tixgo/shared/authz"
+	"tixgo/shared/httpresponse"
+	"tixgo/shared/listing"
+	"tixgo/shared/scheduler"
+	"tixgo/shared/session"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterSchedulerRoutes(router *apiversion.Group, appCtx components.AppContext, jobs []scheduler.Job) {
+	jobGroup := router.Group("/admin/jobs")
+	{
+		jobGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		jobGroup.Use(authz.RequireUserType(string(userDomain.UserTypeAdmin)))
+		jobGroup.GET("", ListJobs(appCtx, jobs))
+		jobGroup.GET("/:name/runs", ListJobRuns(appCtx))
+		jobGroup.POST("/:name/trigger", TriggerJob(appCtx, jobs))
+	}
+}
+
+func ListJobs(appCtx components.AppContext, jobs []scheduler.Job) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		jobRunRepo := adapters.NewJobRunPostgresRepository(appCtx.GetDB())
+		handler := query.NewListJobsHandler(jobs, jobRunRepo)
+
+		result, err := handler.Handle(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func ListJobRuns(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var filters query.FilterJobRunsQuery
+		if err := c.ShouldBind(&filters); err != nil {
+			c.Error(err)
+			return
+		}
+		filters.JobName = c.Param("name")
+
+		var paging listing.Paging
+		if err := c.ShouldBind(&paging); err != nil {
+			c.Error(err)
+			return
+		}
+
+		// Apply pagination defaults in HTTP layer
+		paging.Fulfill()
+
+		jobRunRepo := adapters.NewJobRunPostgresRepository(appCtx.GetDB())
+		handler := query.NewListJobRunsHandler(jobRunRepo)
+
+		result, err := handler.Handle(c.Request.Context(), &filters, &paging)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.List(c, result, paging, filters)
+	}
+}
+
+func TriggerJob(appCtx components.AppContext, jobs []scheduler.Job) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		handler := command.NewTriggerJobHandler(jobs, appCtx.GetCommandBus())
+
+		err := handler.Handle(c.Request.Context(), &command.TriggerJobCommand{
+			JobName: c.Param("name"),
+		})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusAccepted, true)
+	}
+}
@@ -0,0 +1,36 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"tixgo/components"
+	"tixgo/modules/scheduler/adapters"
+	"tixgo/modules/scheduler/app/command"
+	"tixgo/shared/scheduler"
+)
+
+const (
+	JobPurgeJobRuns = "scheduler.purge_job_runs"
+
+	defaultJobRunRetention = 30 * 24 * time.Hour
+)
+
+// Jobs returns the jobs of the scheduler module
+func Jobs(appCtx components.AppContext, jobRunRetention time.Duration) []scheduler.Job {
+	if jobRunRetention <= 0 {
+		jobRunRetention = defaultJobRunRetention
+	}
+
+	return []scheduler.Job{
+		{
+			Name:     JobPurgeJobRuns,
+			Schedule: "@daily",
+			Timeout:  10 * time.Minute,
+			Run: func(ctx context.Context) error {
+				jobRunRepo := adapters.NewJobRunPostgresRepository(appCtx.GetDB())
+				return command.NewPurgeJobRunsHandler(jobRunRepo, jobRunRetention).Handle(ctx)
+			},
+		},
+	}
+}
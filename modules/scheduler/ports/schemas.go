@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"tixgo/modules/scheduler/app/query"
+	"tixgo/shared/jsonschema"
+	"tixgo/shared/listing"
+)
+
+// Schemas returns the request payloads of the scheduler module
+func Schemas() []jsonschema.Payload {
+	return []jsonschema.Payload{
+		{Name: "admin.jobs.runs", In: jsonschema.Query, Example: struct {
+			query.FilterJobRunsQuery
+			listing.Paging
+		}{}},
+	}
+}
@@ -0,0 +1,75 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"tixgo/modules/support/domain"
+	"tixgo/shared/dbtimeout"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+// ContextPostgresReader implements the ContextReader interface using PostgreSQL. Orders are read
+// from the order_summaries projection of the order module, which has their event already.
+type ContextPostgresReader struct {
+	db *sqlx.DB
+}
+
+// NewContextPostgresReader creates a new PostgreSQL reader of the context of support tickets
+func NewContextPostgresReader(db *sqlx.DB) *ContextPostgresReader {
+	return &ContextPostgresReader{db: db}
+}
+
+// GetUserContext reads the account of a user
+func (r *ContextPostgresReader) GetUserContext(ctx context.Context, userID int64) (*domain.UserContext, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	user := &domain.UserContext{}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, email, first_name || ' ' || last_name, COALESCE(user_type::TEXT, 'customer')
+		FROM users
+		WHERE id = $1`, userID).Scan(&user.ID, &user.Email, &user.Name, &user.UserType)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get user")
+	}
+
+	return user, nil
+}
+
+// GetOrderContext reads an order of the user, ErrOrderNotFound if the user has none of the ID
+func (r *ContextPostgresReader) GetOrderContext(ctx context.Context, orderID, userID int64) (*domain.OrderContext, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	order := &domain.OrderContext{}
+	var eventTitle sql.NullString
+	var createdAt time.Time
+	err := r.db.QueryRowContext(ctx, `
+		SELECT order_id, order_number, status, total_amount, currency, ticket_count, event_id, event_title, created_at
+		FROM order_summaries
+		WHERE order_id = $1 AND user_id = $2`, orderID, userID).Scan(
+		&order.ID,
+		&order.OrderNumber,
+		&order.Status,
+		&order.TotalAmount,
+		&order.Currency,
+		&order.TicketCount,
+		&order.EventID,
+		&eventTitle,
+		&createdAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrOrderNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get order")
+	}
+	order.EventTitle = eventTitle.String
+	order.CreatedAt = createdAt.UTC().Format("2006-01-02T15:04:05Z")
+
+	return order, nil
+}
@@ -0,0 +1,148 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"tixgo/modules/support/domain"
+	"tixgo/shared/httpclient"
+)
+
+// FreshdeskProvider names Freshdesk in the provider of the tickets pushed to it
+const FreshdeskProvider = "freshdesk"
+
+// The statuses of Freshdesk tickets, and the priority of the ones we create
+const (
+	freshdeskStatusOpen     = 2
+	freshdeskStatusPending  = 3
+	freshdeskStatusResolved = 4
+	freshdeskStatusClosed   = 5
+	freshdeskPriorityLow    = 1
+)
+
+// FreshdeskHelpDesk implements the HelpDesk interface with the Tickets API of Freshdesk
+type FreshdeskHelpDesk struct {
+	client  *httpclient.Client
+	baseURL string
+	apiKey  string
+}
+
+// NewFreshdeskHelpDesk creates a help desk of the Freshdesk account at baseURL, e.g.
+// https://acme.freshdesk.com, authenticated with the API key of an agent
+func NewFreshdeskHelpDesk(baseURL, apiKey string) *FreshdeskHelpDesk {
+	return &FreshdeskHelpDesk{
+		client:  httpclient.New(httpclient.DefaultConfig(FreshdeskProvider)),
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+	}
+}
+
+func (f *FreshdeskHelpDesk) Name() string {
+	return FreshdeskProvider
+}
+
+type freshdeskTicket struct {
+	ID          int64    `json:"id,omitempty"`
+	Status      int      `json:"status,omitempty"`
+	Priority    int      `json:"priority,omitempty"`
+	Subject     string   `json:"subject,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	Email       string   `json:"email,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+// CreateTicket creates the ticket on behalf of its user, who gets the replies of agents by mail.
+// Freshdesk takes HTML descriptions: the text of the user is escaped.
+func (f *FreshdeskHelpDesk) CreateTicket(ctx context.Context, ticket *domain.Ticket) (string, error) {
+	description := html.EscapeString(ticket.Context.Description(ticket.Description))
+	payload := freshdeskTicket{
+		Status:      freshdeskStatusOpen,
+		Priority:    freshdeskPriorityLow,
+		Subject:     ticket.Subject,
+		Description: strings.ReplaceAll(description, "\n", "<br>"),
+		Name:        ticket.Context.User.Name,
+		Email:       ticket.Context.User.Email,
+		Tags:        append(ticketTags(ticket), externalRef(ticket)),
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode freshdesk ticket: %w", err)
+	}
+
+	var result freshdeskTicket
+	if _, err := f.do(ctx, "create_ticket", http.MethodPost, "/api/v2/tickets", body, &result); err != nil {
+		return "", err
+	}
+	if result.ID == 0 {
+		return "", fmt.Errorf("freshdesk answered without a ticket id")
+	}
+	return strconv.FormatInt(result.ID, 10), nil
+}
+
+// GetStatuses reads the tickets of the IDs one by one, Freshdesk having no way to read them at once
+func (f *FreshdeskHelpDesk) GetStatuses(ctx context.Context, externalIDs []string) (map[string]domain.TicketStatus, error) {
+	statuses := make(map[string]domain.TicketStatus, len(externalIDs))
+	for _, id := range externalIDs {
+		var result freshdeskTicket
+		found, err := f.do(ctx, "view_ticket", http.MethodGet, "/api/v2/tickets/"+url.PathEscape(id), nil, &result)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			statuses[id] = freshdeskStatus(result.Status)
+		}
+	}
+	return statuses, nil
+}
+
+// do sends a request to Freshdesk and decodes its answer in result, telling whether the resource exists
+func (f *FreshdeskHelpDesk) do(ctx context.Context, endpoint, method, path string, body []byte, result any) (bool, error) {
+	req, err := http.NewRequestWithContext(httpclient.WithEndpoint(ctx, endpoint), method, f.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create freshdesk request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(f.apiKey, "X")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound && method == http.MethodGet {
+		return false, nil
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return false, fmt.Errorf("freshdesk answered %s %s with status %d: %s", method, path, resp.StatusCode, message)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return false, fmt.Errorf("failed to decode freshdesk response: %w", err)
+	}
+	return true, nil
+}
+
+// freshdeskStatus maps the statuses of Freshdesk to ours; custom statuses count as open
+func freshdeskStatus(status int) domain.TicketStatus {
+	switch status {
+	case freshdeskStatusPending:
+		return domain.TicketStatusPending
+	case freshdeskStatusResolved:
+		return domain.TicketStatusSolved
+	case freshdeskStatusClosed:
+		return domain.TicketStatusClosed
+	default:
+		return domain.TicketStatusOpen
+	}
+}
@@ -0,0 +1,123 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"tixgo/modules/support/domain"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init(&logger.Config{Output: io.Discard})
+	os.Exit(m.Run())
+}
+
+func testTicket() *domain.Ticket {
+	return &domain.Ticket{
+		ID:          42,
+		Category:    domain.CategoryOrder,
+		Subject:     "Missing tickets",
+		Description: "I paid <but> got no tickets",
+		Context: domain.TicketContext{
+			User: domain.UserContext{ID: 3, Email: "jane@example.com", Name: "Jane Doe", UserType: "customer"},
+		},
+	}
+}
+
+func TestZendeskHelpDesk(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "agent@example.com/token" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v2/tickets.json":
+			var body struct {
+				Ticket zendeskTicket `json:"ticket"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "tixgo-42", body.Ticket.ExternalID)
+			assert.Equal(t, "jane@example.com", body.Ticket.Requester.Email)
+			assert.Equal(t, []string{"tixgo", "tixgo_order"}, body.Ticket.Tags)
+			assert.Contains(t, body.Ticket.Comment.Body, "I paid <but> got no tickets\n\n---\nUser: #3")
+			_, _ = w.Write([]byte(`{"ticket":{"id":1001,"status":"new"}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2/tickets/show_many.json":
+			assert.Equal(t, "1001,1002,1003", r.URL.Query().Get("ids"))
+			_, _ = w.Write([]byte(`{"tickets":[{"id":1001,"status":"hold"},{"id":1002,"status":"solved"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	helpDesk := NewZendeskHelpDesk(server.URL+"/", "agent@example.com", "secret")
+
+	externalID, err := helpDesk.CreateTicket(context.Background(), testTicket())
+	require.NoError(t, err)
+	assert.Equal(t, "1001", externalID)
+
+	statuses, err := helpDesk.GetStatuses(context.Background(), []string{"1001", "1002", "1003"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]domain.TicketStatus{
+		"1001": domain.TicketStatusOpen,
+		"1002": domain.TicketStatusSolved,
+	}, statuses, "deleted tickets are left out")
+
+	_, err = NewZendeskHelpDesk(server.URL, "agent@example.com", "wrong").CreateTicket(context.Background(), testTicket())
+	assert.ErrorContains(t, err, "status 401")
+}
+
+func TestFreshdeskHelpDesk(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _, ok := r.BasicAuth()
+		if !ok || user != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v2/tickets":
+			var body freshdeskTicket
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "jane@example.com", body.Email)
+			assert.Equal(t, []string{"tixgo", "tixgo_order", "tixgo-42"}, body.Tags)
+			assert.Contains(t, body.Description, "I paid &lt;but&gt; got no tickets<br><br>---<br>User: #3")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":77,"status":2}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2/tickets/77":
+			_, _ = w.Write([]byte(`{"id":77,"status":4}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2/tickets/78":
+			_, _ = w.Write([]byte(`{"id":78,"status":3}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	helpDesk := NewFreshdeskHelpDesk(server.URL, "secret")
+
+	externalID, err := helpDesk.CreateTicket(context.Background(), testTicket())
+	require.NoError(t, err)
+	assert.Equal(t, "77", externalID)
+
+	statuses, err := helpDesk.GetStatuses(context.Background(), []string{"77", "78", "79"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]domain.TicketStatus{
+		"77": domain.TicketStatusSolved,
+		"78": domain.TicketStatusPending,
+	}, statuses, "deleted tickets are left out")
+
+	_, err = NewFreshdeskHelpDesk(server.URL, "wrong").GetStatuses(context.Background(), []string{"77"})
+	assert.ErrorContains(t, err, "status 401")
+}
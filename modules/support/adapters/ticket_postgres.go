@@ -0,0 +1,226 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"tixgo/modules/support/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/listing"
+	"tixgo/shared/pgquery"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+// TicketPostgresRepository implements the TicketRepository interface using PostgreSQL
+type TicketPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewTicketPostgresRepository creates a new PostgreSQL support ticket repository
+func NewTicketPostgresRepository(db *sqlx.DB) *TicketPostgresRepository {
+	return &TicketPostgresRepository{db: db}
+}
+
+// ticketColumns are the columns scanned by scanTicket
+const ticketColumns = `id, user_id, order_id, category, subject, description, context, status, COALESCE(provider, ''),
+	COALESCE(external_id, ''), push_attempts, COALESCE(push_error, ''), synced_at, created_at, updated_at`
+
+// Create stores a ticket, setting its ID
+func (r *TicketPostgresRepository) Create(ctx context.Context, ticket *domain.Ticket) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	ticketContext, err := json.Marshal(ticket.Context)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to encode support ticket context")
+	}
+
+	err = r.db.QueryRowContext(ctx, `
+		INSERT INTO support_tickets (user_id, order_id, category, subject, description, context, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id`,
+		ticket.UserID,
+		ticket.OrderID,
+		ticket.Category,
+		ticket.Subject,
+		ticket.Description,
+		ticketContext,
+		ticket.Status,
+		ticket.CreatedAt,
+		ticket.UpdatedAt,
+	).Scan(&ticket.ID)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create support ticket")
+	}
+
+	return nil
+}
+
+// GetByID retrieves a ticket of the user, ErrTicketNotFound if the user has none of the ID
+func (r *TicketPostgresRepository) GetByID(ctx context.Context, id, userID int64) (*domain.Ticket, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	row := r.db.QueryRowContext(ctx, `SELECT `+ticketColumns+` FROM support_tickets WHERE id = $1 AND user_id = $2`, id, userID)
+	ticket, err := scanTicket(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrTicketNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get support ticket")
+	}
+
+	return ticket, nil
+}
+
+// ListByUser retrieves a page of the tickets of a user, newest first
+func (r *TicketPostgresRepository) ListByUser(ctx context.Context, userID int64, paging *listing.Paging) ([]*domain.Ticket, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	filter := &pgquery.Filter{}
+	filter.Where("user_id = ?", userID)
+
+	// Set total in paging
+	err := pgquery.Count(ctx, r.db, "support_tickets", filter, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to count support tickets")
+	}
+
+	pageClause, args := filter.Paged(paging)
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM support_tickets
+		%s
+		ORDER BY created_at DESC, id DESC
+		%s`, ticketColumns, filter.Clause(), pageClause)
+
+	tickets, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return tickets[:paging.Fetched(len(tickets))], nil
+}
+
+// ListUnpushed retrieves the oldest tickets not in the help desk yet, below MaxPushAttempts
+func (r *TicketPostgresRepository) ListUnpushed(ctx context.Context, limit int) ([]*domain.Ticket, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return r.query(ctx, `
+		SELECT `+ticketColumns+`
+		FROM support_tickets
+		WHERE external_id IS NULL AND push_attempts < $1
+		ORDER BY id
+		LIMIT $2`, domain.MaxPushAttempts, limit)
+}
+
+// ListUnclosed retrieves the tickets of the help desk not closed yet, the least recently synced first
+func (r *TicketPostgresRepository) ListUnclosed(ctx context.Context, provider string, limit int) ([]*domain.Ticket, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return r.query(ctx, `
+		SELECT `+ticketColumns+`
+		FROM support_tickets
+		WHERE external_id IS NOT NULL AND status <> 'closed' AND provider = $1
+		ORDER BY synced_at NULLS FIRST, id
+		LIMIT $2`, provider, limit)
+}
+
+// SavePush records the outcome of an attempt to create a ticket in the help desk
+func (r *TicketPostgresRepository) SavePush(ctx context.Context, ticket *domain.Ticket) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE support_tickets
+		SET provider = NULLIF($2, ''), external_id = NULLIF($3, ''), status = $4, push_attempts = $5,
+		    push_error = NULLIF($6, ''), synced_at = $7, updated_at = $8
+		WHERE id = $1`,
+		ticket.ID, ticket.Provider, ticket.ExternalID, ticket.Status, ticket.PushAttempts,
+		ticket.PushError, ticket.SyncedAt, ticket.UpdatedAt)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to save support ticket push")
+	}
+
+	return nil
+}
+
+// SaveSync records the status read from the help desk
+func (r *TicketPostgresRepository) SaveSync(ctx context.Context, ticket *domain.Ticket) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE support_tickets SET status = $2, synced_at = $3, updated_at = $4
+		WHERE id = $1`,
+		ticket.ID, ticket.Status, ticket.SyncedAt, ticket.UpdatedAt)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to save support ticket sync")
+	}
+
+	return nil
+}
+
+func (r *TicketPostgresRepository) query(ctx context.Context, query string, args ...any) ([]*domain.Ticket, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list support tickets")
+	}
+	defer rows.Close()
+
+	var tickets []*domain.Ticket
+	for rows.Next() {
+		ticket, err := scanTicket(rows)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan support ticket")
+		}
+		tickets = append(tickets, ticket)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "error iterating support ticket rows")
+	}
+
+	return tickets, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTicket(row rowScanner) (*domain.Ticket, error) {
+	ticket := &domain.Ticket{}
+	var ticketContext []byte
+	err := row.Scan(
+		&ticket.ID,
+		&ticket.UserID,
+		&ticket.OrderID,
+		&ticket.Category,
+		&ticket.Subject,
+		&ticket.Description,
+		&ticketContext,
+		&ticket.Status,
+		&ticket.Provider,
+		&ticket.ExternalID,
+		&ticket.PushAttempts,
+		&ticket.PushError,
+		&ticket.SyncedAt,
+		&ticket.CreatedAt,
+		&ticket.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(ticketContext, &ticket.Context); err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
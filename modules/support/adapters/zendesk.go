@@ -0,0 +1,156 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"tixgo/modules/support/domain"
+	"tixgo/shared/httpclient"
+)
+
+// ZendeskProvider names Zendesk in the provider of the tickets pushed to it
+const ZendeskProvider = "zendesk"
+
+// ZendeskHelpDesk implements the HelpDesk interface with the Tickets API of Zendesk Support
+type ZendeskHelpDesk struct {
+	client   *httpclient.Client
+	baseURL  string
+	email    string
+	apiToken string
+}
+
+// NewZendeskHelpDesk creates a help desk of the Zendesk account at baseURL, e.g.
+// https://acme.zendesk.com, authenticated with the API token of the agent of email
+func NewZendeskHelpDesk(baseURL, email, apiToken string) *ZendeskHelpDesk {
+	return &ZendeskHelpDesk{
+		client:   httpclient.New(httpclient.DefaultConfig(ZendeskProvider)),
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		email:    email,
+		apiToken: apiToken,
+	}
+}
+
+func (z *ZendeskHelpDesk) Name() string {
+	return ZendeskProvider
+}
+
+type zendeskTicket struct {
+	ID         int64             `json:"id,omitempty"`
+	Status     string            `json:"status,omitempty"`
+	Subject    string            `json:"subject,omitempty"`
+	ExternalID string            `json:"external_id,omitempty"`
+	Comment    *zendeskComment   `json:"comment,omitempty"`
+	Requester  *zendeskRequester `json:"requester,omitempty"`
+	Tags       []string          `json:"tags,omitempty"`
+}
+
+type zendeskComment struct {
+	Body string `json:"body"`
+}
+
+type zendeskRequester struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// CreateTicket creates the ticket on behalf of its user, who gets the replies of agents by mail. The
+// ID of the ticket in the app is its external_id, to find it back from Zendesk.
+func (z *ZendeskHelpDesk) CreateTicket(ctx context.Context, ticket *domain.Ticket) (string, error) {
+	payload := zendeskTicket{
+		Subject:    ticket.Subject,
+		ExternalID: externalRef(ticket),
+		Comment:    &zendeskComment{Body: ticket.Context.Description(ticket.Description)},
+		Requester:  &zendeskRequester{Name: ticket.Context.User.Name, Email: ticket.Context.User.Email},
+		Tags:       ticketTags(ticket),
+	}
+
+	body, err := json.Marshal(map[string]zendeskTicket{"ticket": payload})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode zendesk ticket: %w", err)
+	}
+
+	var result struct {
+		Ticket zendeskTicket `json:"ticket"`
+	}
+	if err := z.do(ctx, "create_ticket", http.MethodPost, "/api/v2/tickets.json", body, &result); err != nil {
+		return "", err
+	}
+	if result.Ticket.ID == 0 {
+		return "", fmt.Errorf("zendesk answered without a ticket id")
+	}
+	return strconv.FormatInt(result.Ticket.ID, 10), nil
+}
+
+// GetStatuses reads the tickets of the IDs at once, 100 at most
+func (z *ZendeskHelpDesk) GetStatuses(ctx context.Context, externalIDs []string) (map[string]domain.TicketStatus, error) {
+	path := "/api/v2/tickets/show_many.json?" + url.Values{"ids": {strings.Join(externalIDs, ",")}}.Encode()
+
+	var result struct {
+		Tickets []zendeskTicket `json:"tickets"`
+	}
+	if err := z.do(ctx, "show_tickets", http.MethodGet, path, nil, &result); err != nil {
+		return nil, err
+	}
+
+	statuses := make(map[string]domain.TicketStatus, len(result.Tickets))
+	for _, ticket := range result.Tickets {
+		statuses[strconv.FormatInt(ticket.ID, 10)] = zendeskStatus(ticket.Status)
+	}
+	return statuses, nil
+}
+
+func (z *ZendeskHelpDesk) do(ctx context.Context, endpoint, method, path string, body []byte, result any) error {
+	req, err := http.NewRequestWithContext(httpclient.WithEndpoint(ctx, endpoint), method, z.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create zendesk request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(z.email+"/token", z.apiToken)
+
+	resp, err := z.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return fmt.Errorf("zendesk answered %s %s with status %d: %s", method, path, resp.StatusCode, message)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode zendesk response: %w", err)
+	}
+	return nil
+}
+
+// zendeskStatus maps the statuses of Zendesk to ours: a new ticket or one on hold, waiting for a third
+// party, is open as far as the user is concerned
+func zendeskStatus(status string) domain.TicketStatus {
+	switch status {
+	case "pending":
+		return domain.TicketStatusPending
+	case "solved":
+		return domain.TicketStatusSolved
+	case "closed":
+		return domain.TicketStatusClosed
+	default:
+		return domain.TicketStatusOpen
+	}
+}
+
+// externalRef identifies a ticket of the app in the help desk
+func externalRef(ticket *domain.Ticket) string {
+	return "tixgo-" + strconv.FormatInt(ticket.ID, 10)
+}
+
+// ticketTags are the tags agents route and filter the tickets of the app by
+func ticketTags(ticket *domain.Ticket) []string {
+	return []string{"tixgo", "tixgo_" + string(ticket.Category)}
+}
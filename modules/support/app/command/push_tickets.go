@@ -0,0 +1,63 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/support/domain"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// pushBatchSize is how many tickets a run of the push job creates in the help desk at most
+const pushBatchSize = 50
+
+// PushTicketsHandler creates the tickets users reported in the help desk
+type PushTicketsHandler struct {
+	ticketRepo domain.TicketRepository
+	helpDesk   domain.HelpDesk
+}
+
+// NewPushTicketsHandler creates a new push tickets handler
+func NewPushTicketsHandler(ticketRepo domain.TicketRepository, helpDesk domain.HelpDesk) *PushTicketsHandler {
+	return &PushTicketsHandler{
+		ticketRepo: ticketRepo,
+		helpDesk:   helpDesk,
+	}
+}
+
+// Handle creates the tickets not in the help desk yet, oldest first. A ticket the help desk refuses
+// is retried by the next runs, up to domain.MaxPushAttempts; the others still go.
+func (h *PushTicketsHandler) Handle(ctx context.Context) error {
+	tickets, err := h.ticketRepo.ListUnpushed(ctx, pushBatchSize)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to list unpushed support tickets")
+	}
+
+	failed := 0
+	for _, ticket := range tickets {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		externalID, err := h.helpDesk.CreateTicket(ctx, ticket)
+		if err != nil {
+			logger.Warning(ctx, "Failed to create support ticket in help desk",
+				logger.F("ticket_id", ticket.ID), logger.F("provider", h.helpDesk.Name()), logger.F("error", err))
+			ticket.PushFailed(err, time.Now())
+			failed++
+		} else {
+			ticket.Pushed(h.helpDesk.Name(), externalID, time.Now())
+		}
+
+		if err := h.ticketRepo.SavePush(ctx, ticket); err != nil {
+			return syserr.Wrap(err, syserr.InternalCode, "failed to save support ticket push")
+		}
+	}
+
+	if failed > 0 {
+		logger.Warning(ctx, "Some support tickets were not created in help desk", logger.F("failed", failed), logger.F("total", len(tickets)))
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"testing"
+
+	"tixgo/modules/support/domain"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init(&logger.Config{Output: io.Discard})
+	os.Exit(m.Run())
+}
+
+// memoryTicketRepository keeps the tickets in memory, recording the saved ones
+type memoryTicketRepository struct {
+	domain.TicketRepository
+	tickets []*domain.Ticket
+	saved   []int64
+}
+
+func (r *memoryTicketRepository) ListUnpushed(_ context.Context, limit int) ([]*domain.Ticket, error) {
+	var tickets []*domain.Ticket
+	for _, ticket := range r.tickets {
+		if !ticket.IsPushed() && ticket.PushAttempts < domain.MaxPushAttempts && len(tickets) < limit {
+			tickets = append(tickets, ticket)
+		}
+	}
+	return tickets, nil
+}
+
+func (r *memoryTicketRepository) ListUnclosed(_ context.Context, provider string, limit int) ([]*domain.Ticket, error) {
+	var tickets []*domain.Ticket
+	for _, ticket := range r.tickets {
+		if ticket.IsPushed() && ticket.Provider == provider && ticket.Status != domain.TicketStatusClosed && len(tickets) < limit {
+			tickets = append(tickets, ticket)
+		}
+	}
+	return tickets, nil
+}
+
+func (r *memoryTicketRepository) SavePush(_ context.Context, ticket *domain.Ticket) error {
+	r.saved = append(r.saved, ticket.ID)
+	return nil
+}
+
+func (r *memoryTicketRepository) SaveSync(_ context.Context, ticket *domain.Ticket) error {
+	r.saved = append(r.saved, ticket.ID)
+	return nil
+}
+
+// fakeHelpDesk creates tickets numbered from 1000, refusing the ones of refused, and reports statuses
+type fakeHelpDesk struct {
+	refused  map[int64]bool
+	created  []int64
+	statuses map[string]domain.TicketStatus
+	requests [][]string
+}
+
+func (h *fakeHelpDesk) Name() string {
+	return "fake"
+}
+
+func (h *fakeHelpDesk) CreateTicket(_ context.Context, ticket *domain.Ticket) (string, error) {
+	if h.refused[ticket.ID] {
+		return "", errors.New("help desk refused the ticket")
+	}
+	h.created = append(h.created, ticket.ID)
+	return strconv.FormatInt(1000+ticket.ID, 10), nil
+}
+
+func (h *fakeHelpDesk) GetStatuses(_ context.Context, externalIDs []string) (map[string]domain.TicketStatus, error) {
+	h.requests = append(h.requests, externalIDs)
+	statuses := make(map[string]domain.TicketStatus)
+	for _, id := range externalIDs {
+		if status, ok := h.statuses[id]; ok {
+			statuses[id] = status
+		}
+	}
+	return statuses, nil
+}
+
+func TestPushTickets(t *testing.T) {
+	repo := &memoryTicketRepository{tickets: []*domain.Ticket{
+		{ID: 1, Status: domain.TicketStatusSubmitted},
+		{ID: 2, Status: domain.TicketStatusSubmitted},
+		{ID: 3, Status: domain.TicketStatusSubmitted, PushAttempts: domain.MaxPushAttempts},
+		{ID: 4, Status: domain.TicketStatusOpen, Provider: "fake", ExternalID: "1004"},
+	}}
+	helpDesk := &fakeHelpDesk{refused: map[int64]bool{1: true}}
+
+	require.NoError(t, NewPushTicketsHandler(repo, helpDesk).Handle(context.Background()))
+	assert.Equal(t, []int64{2}, helpDesk.created)
+	assert.Equal(t, []int64{1, 2}, repo.saved, "failed attempts are saved too")
+
+	refused, pushed := repo.tickets[0], repo.tickets[1]
+	assert.False(t, refused.IsPushed())
+	assert.Equal(t, 1, refused.PushAttempts)
+	assert.Equal(t, "help desk refused the ticket", refused.PushError)
+	assert.Equal(t, "fake", pushed.Provider)
+	assert.Equal(t, "1002", pushed.ExternalID)
+	assert.Equal(t, domain.TicketStatusOpen, pushed.Status)
+
+	helpDesk.refused = nil
+	repo.saved = nil
+	require.NoError(t, NewPushTicketsHandler(repo, helpDesk).Handle(context.Background()))
+	assert.Equal(t, []int64{1}, repo.saved, "the refused ticket is retried, the given up one is not")
+	assert.Equal(t, "1001", refused.ExternalID)
+}
+
+func TestSyncTickets(t *testing.T) {
+	repo := &memoryTicketRepository{}
+	for id := int64(1); id <= syncChunkSize+1; id++ {
+		repo.tickets = append(repo.tickets, &domain.Ticket{ID: id, Status: domain.TicketStatusOpen, Provider: "fake", ExternalID: strconv.FormatInt(1000+id, 10)})
+	}
+	repo.tickets = append(repo.tickets, &domain.Ticket{ID: 999, Status: domain.TicketStatusOpen, Provider: "other", ExternalID: "x"})
+
+	solved, deleted := repo.tickets[0], repo.tickets[1]
+	statuses := make(map[string]domain.TicketStatus)
+	for _, ticket := range repo.tickets {
+		statuses[ticket.ExternalID] = domain.TicketStatusOpen
+	}
+	statuses[solved.ExternalID] = domain.TicketStatusSolved
+	delete(statuses, deleted.ExternalID)
+	helpDesk := &fakeHelpDesk{statuses: statuses}
+
+	require.NoError(t, NewSyncTicketsHandler(repo, helpDesk).Handle(context.Background()))
+	require.Len(t, helpDesk.requests, 2, "the tickets are read by chunks")
+	assert.Len(t, helpDesk.requests[0], syncChunkSize)
+	assert.Len(t, helpDesk.requests[1], 1)
+	assert.Len(t, repo.saved, syncChunkSize+1, "only the tickets of the help desk are synced")
+
+	assert.Equal(t, domain.TicketStatusSolved, solved.Status)
+	assert.Equal(t, domain.TicketStatusClosed, deleted.Status, "a ticket missing from the help desk is closed")
+	assert.Equal(t, domain.TicketStatusOpen, repo.tickets[2].Status)
+	assert.NotNil(t, repo.tickets[2].SyncedAt)
+}
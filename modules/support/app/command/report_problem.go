@@ -0,0 +1,69 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/support/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ReportProblemCommand represents the command of a user reporting a problem from the app
+type ReportProblemCommand struct {
+	UserID    int64  `json:"-"`
+	UserAgent string `json:"-"`
+	Category  string `json:"category" binding:"required,oneof=order payment account event other"`
+	Subject   string `json:"subject" binding:"required,max=200"`
+	// Description is in the words of the user, the context of their account and order is attached
+	Description string `json:"description" binding:"required,max=5000"`
+	// OrderID is the order of the user the problem is about, if any
+	OrderID *int64 `json:"order_id" binding:"omitempty,min=1"`
+	// Page is where in the app the problem was reported
+	Page string `json:"page" binding:"max=500"`
+}
+
+// ReportProblemHandler handles the problems users report
+type ReportProblemHandler struct {
+	ticketRepo    domain.TicketRepository
+	contextReader domain.ContextReader
+}
+
+// NewReportProblemHandler creates a new report problem handler
+func NewReportProblemHandler(ticketRepo domain.TicketRepository, contextReader domain.ContextReader) *ReportProblemHandler {
+	return &ReportProblemHandler{
+		ticketRepo:    ticketRepo,
+		contextReader: contextReader,
+	}
+}
+
+// Handle executes the report problem command. The ticket is stored with its context and created in
+// the help desk by the support.push_tickets job, so reporting works while the help desk is down.
+func (h *ReportProblemHandler) Handle(ctx context.Context, cmd ReportProblemCommand) (*TicketResult, error) {
+	user, err := h.contextReader.GetUserContext(ctx, cmd.UserID)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get user")
+	}
+	ticketContext := domain.TicketContext{User: *user, Page: cmd.Page, UserAgent: cmd.UserAgent}
+
+	if cmd.OrderID != nil {
+		order, err := h.contextReader.GetOrderContext(ctx, *cmd.OrderID, cmd.UserID)
+		if err != nil {
+			if err == domain.ErrOrderNotFound {
+				return nil, err
+			}
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get order")
+		}
+		ticketContext.Order = order
+	}
+
+	ticket, err := domain.NewTicket(cmd.Category, cmd.Subject, cmd.Description, ticketContext)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.ticketRepo.Create(ctx, ticket); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to create support ticket")
+	}
+
+	return ToTicketResult(ticket), nil
+}
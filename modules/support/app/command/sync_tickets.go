@@ -0,0 +1,68 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/support/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+const (
+	// syncBatchSize is how many tickets a run of the sync job reads from the help desk at most
+	syncBatchSize = 500
+	// syncChunkSize is how many tickets are read from the help desk per request
+	syncChunkSize = 100
+)
+
+// SyncTicketsHandler copies the statuses agents give the tickets in the help desk back to the app
+type SyncTicketsHandler struct {
+	ticketRepo domain.TicketRepository
+	helpDesk   domain.HelpDesk
+}
+
+// NewSyncTicketsHandler creates a new sync tickets handler
+func NewSyncTicketsHandler(ticketRepo domain.TicketRepository, helpDesk domain.HelpDesk) *SyncTicketsHandler {
+	return &SyncTicketsHandler{
+		ticketRepo: ticketRepo,
+		helpDesk:   helpDesk,
+	}
+}
+
+// Handle reads the status of the tickets not closed yet, the least recently synced first, so every
+// ticket is synced in turn however many are open. A ticket the help desk no longer has, e.g. deleted
+// as spam, is closed.
+func (h *SyncTicketsHandler) Handle(ctx context.Context) error {
+	tickets, err := h.ticketRepo.ListUnclosed(ctx, h.helpDesk.Name(), syncBatchSize)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to list unclosed support tickets")
+	}
+
+	for start := 0; start < len(tickets); start += syncChunkSize {
+		chunk := tickets[start:min(start+syncChunkSize, len(tickets))]
+
+		ids := make([]string, len(chunk))
+		for i, ticket := range chunk {
+			ids[i] = ticket.ExternalID
+		}
+		statuses, err := h.helpDesk.GetStatuses(ctx, ids)
+		if err != nil {
+			return syserr.Wrap(err, syserr.InternalCode, "failed to get support ticket statuses")
+		}
+
+		now := time.Now()
+		for _, ticket := range chunk {
+			status, ok := statuses[ticket.ExternalID]
+			if !ok {
+				status = domain.TicketStatusClosed
+			}
+			ticket.Synced(status, now)
+			if err := h.ticketRepo.SaveSync(ctx, ticket); err != nil {
+				return syserr.Wrap(err, syserr.InternalCode, "failed to save support ticket sync")
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package command
+
+import (
+	"tixgo/modules/support/domain"
+)
+
+// TicketResult represents a support ticket as its user follows it
+type TicketResult struct {
+	ID          int64  `json:"id"`
+	Category    string `json:"category"`
+	Subject     string `json:"subject"`
+	Description string `json:"description"`
+	OrderID     *int64 `json:"order_id,omitempty"`
+	// Status is submitted until the ticket is in the help desk, then open, pending, solved or closed
+	Status string `json:"status"`
+	// Reference is the number of the ticket in the help desk, to quote when writing to support
+	Reference string `json:"reference,omitempty"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// ToTicketResult converts a support ticket to its result
+func ToTicketResult(ticket *domain.Ticket) *TicketResult {
+	return &TicketResult{
+		ID:          ticket.ID,
+		Category:    string(ticket.Category),
+		Subject:     ticket.Subject,
+		Description: ticket.Description,
+		OrderID:     ticket.OrderID,
+		Status:      string(ticket.Status),
+		Reference:   ticket.ExternalID,
+		CreatedAt:   ticket.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:   ticket.UpdatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+	}
+}
@@ -0,0 +1,41 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/support/app/command"
+	"tixgo/modules/support/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// GetTicketQuery represents the query of a user for one of their support tickets
+type GetTicketQuery struct {
+	ID     int64
+	UserID int64
+}
+
+// GetTicketHandler handles getting support tickets
+type GetTicketHandler struct {
+	ticketRepo domain.TicketRepository
+}
+
+// NewGetTicketHandler creates a new get ticket handler
+func NewGetTicketHandler(ticketRepo domain.TicketRepository) *GetTicketHandler {
+	return &GetTicketHandler{
+		ticketRepo: ticketRepo,
+	}
+}
+
+// Handle executes the get ticket query
+func (h *GetTicketHandler) Handle(ctx context.Context, q GetTicketQuery) (*command.TicketResult, error) {
+	ticket, err := h.ticketRepo.GetByID(ctx, q.ID, q.UserID)
+	if err != nil {
+		if err == domain.ErrTicketNotFound {
+			return nil, domain.ErrTicketNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get support ticket")
+	}
+
+	return command.ToTicketResult(ticket), nil
+}
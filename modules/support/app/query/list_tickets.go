@@ -0,0 +1,49 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/support/app/command"
+	"tixgo/modules/support/domain"
+	"tixgo/shared/listing"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ListTicketsQuery represents the query of a user for their support tickets
+type ListTicketsQuery struct {
+	UserID int64 `json:"-" form:"-"`
+}
+
+// ListTicketsHandler handles listing the support tickets of users
+type ListTicketsHandler struct {
+	ticketRepo domain.TicketRepository
+}
+
+// NewListTicketsHandler creates a new list tickets handler
+func NewListTicketsHandler(ticketRepo domain.TicketRepository) *ListTicketsHandler {
+	return &ListTicketsHandler{
+		ticketRepo: ticketRepo,
+	}
+}
+
+// Handle executes the list tickets query, newest first
+func (h *ListTicketsHandler) Handle(ctx context.Context, filters *ListTicketsQuery, paging *listing.Paging) ([]*command.TicketResult, error) {
+	// Ensure paging is not nil (should already be handled in HTTP layer)
+	if paging == nil {
+		paging = &listing.Paging{}
+		paging.Fulfill()
+	}
+
+	tickets, err := h.ticketRepo.ListByUser(ctx, filters.UserID, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list support tickets")
+	}
+
+	items := make([]*command.TicketResult, len(tickets))
+	for i, ticket := range tickets {
+		items[i] = command.ToTicketResult(ticket)
+	}
+
+	return items, nil
+}
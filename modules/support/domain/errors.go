@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"tixgo/shared/errstack"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// Support domain errors
+var (
+	ErrInvalidCategory    = errstack.Sentinel(syserr.InvalidArgumentCode, "invalid category, expected order, payment, account, event or other")
+	ErrInvalidSubject     = errstack.Sentinel(syserr.InvalidArgumentCode, "the subject must be between 1 and 200 characters")
+	ErrInvalidDescription = errstack.Sentinel(syserr.InvalidArgumentCode, "the description must be between 1 and 5000 characters")
+	ErrTicketNotFound     = errstack.Sentinel(syserr.NotFoundCode, "support ticket not found")
+	ErrOrderNotFound      = errstack.Sentinel(syserr.NotFoundCode, "order not found")
+)
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"context"
+
+	"tixgo/shared/listing"
+)
+
+// HelpDesk is the help desk agents answer the tickets in, e.g. Zendesk or Freshdesk
+type HelpDesk interface {
+	// Name identifies the help desk the tickets are pushed to
+	Name() string
+
+	// CreateTicket creates the ticket in the help desk, requested by the user of its context, and
+	// returns its ID there
+	CreateTicket(ctx context.Context, ticket *Ticket) (string, error)
+
+	// GetStatuses returns the status of the tickets of the IDs, leaving out the ones the help desk no
+	// longer has
+	GetStatuses(ctx context.Context, externalIDs []string) (map[string]TicketStatus, error)
+}
+
+// TicketRepository defines the interface for the persistence of support tickets
+type TicketRepository interface {
+	// Create stores a ticket, setting its ID
+	Create(ctx context.Context, ticket *Ticket) error
+
+	// GetByID retrieves a ticket of the user, ErrTicketNotFound if the user has none of the ID
+	GetByID(ctx context.Context, id, userID int64) (*Ticket, error)
+
+	// ListByUser retrieves a page of the tickets of a user, newest first
+	ListByUser(ctx context.Context, userID int64, paging *listing.Paging) ([]*Ticket, error)
+
+	// ListUnpushed retrieves the oldest tickets not in the help desk yet, below MaxPushAttempts
+	ListUnpushed(ctx context.Context, limit int) ([]*Ticket, error)
+
+	// ListUnclosed retrieves the tickets of the help desk not closed yet, the least recently synced first
+	ListUnclosed(ctx context.Context, provider string, limit int) ([]*Ticket, error)
+
+	// SavePush records the outcome of an attempt to create a ticket in the help desk
+	SavePush(ctx context.Context, ticket *Ticket) error
+
+	// SaveSync records the status read from the help desk
+	SaveSync(ctx context.Context, ticket *Ticket) error
+}
+
+// ContextReader reads what a ticket is reported with
+type ContextReader interface {
+	// GetUserContext reads the account of a user
+	GetUserContext(ctx context.Context, userID int64) (*UserContext, error)
+
+	// GetOrderContext reads an order of the user, ErrOrderNotFound if the user has none of the ID
+	GetOrderContext(ctx context.Context, orderID, userID int64) (*OrderContext, error)
+}
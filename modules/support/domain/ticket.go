@@ -0,0 +1,190 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Category is what a reported problem is about
+type Category string
+
+const (
+	CategoryOrder   Category = "order"
+	CategoryPayment Category = "payment"
+	CategoryAccount Category = "account"
+	CategoryEvent   Category = "event"
+	CategoryOther   Category = "other"
+)
+
+// IsValidCategory checks if a category is known
+func IsValidCategory(category string) bool {
+	switch Category(category) {
+	case CategoryOrder, CategoryPayment, CategoryAccount, CategoryEvent, CategoryOther:
+		return true
+	}
+	return false
+}
+
+// TicketStatus is where a support ticket stands, as the help desk reports it
+type TicketStatus string
+
+const (
+	// TicketStatusSubmitted means the ticket is not in the help desk yet
+	TicketStatusSubmitted TicketStatus = "submitted"
+	// TicketStatusOpen means agents are working on the ticket
+	TicketStatusOpen TicketStatus = "open"
+	// TicketStatusPending means agents wait for an answer of the user
+	TicketStatusPending TicketStatus = "pending"
+	// TicketStatusSolved means agents solved the ticket, which the user may still reopen by mail
+	TicketStatusSolved TicketStatus = "solved"
+	// TicketStatusClosed means the ticket is over
+	TicketStatusClosed TicketStatus = "closed"
+)
+
+const (
+	MaxSubjectLength     = 200
+	MaxDescriptionLength = 5000
+	// MaxPushAttempts is how many times creating a ticket in the help desk is tried before giving up
+	MaxPushAttempts = 10
+)
+
+// UserContext is the account of the user reporting a problem, as it was then
+type UserContext struct {
+	ID       int64  `json:"id"`
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	UserType string `json:"user_type"`
+}
+
+// OrderContext is the order a problem is about, as it was then
+type OrderContext struct {
+	ID          int64   `json:"id"`
+	OrderNumber string  `json:"order_number"`
+	Status      string  `json:"status"`
+	TotalAmount float64 `json:"total_amount"`
+	Currency    string  `json:"currency"`
+	TicketCount int     `json:"ticket_count"`
+	EventID     *int64  `json:"event_id,omitempty"`
+	EventTitle  string  `json:"event_title,omitempty"`
+	CreatedAt   string  `json:"created_at"`
+}
+
+// TicketContext is attached to a ticket so agents need not ask the user for it
+type TicketContext struct {
+	User  UserContext   `json:"user"`
+	Order *OrderContext `json:"order,omitempty"`
+	// Page is where in the app the problem was reported
+	Page      string `json:"page,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+}
+
+// Ticket is a problem a user reported from the app, and its ticket in the help desk
+type Ticket struct {
+	ID          int64
+	UserID      int64
+	OrderID     *int64
+	Category    Category
+	Subject     string
+	Description string
+	Context     TicketContext
+	Status      TicketStatus
+	// Provider and ExternalID are the help desk and the ticket there, empty until pushed
+	Provider     string
+	ExternalID   string
+	PushAttempts int
+	PushError    string
+	// SyncedAt is when the status was last read from the help desk
+	SyncedAt  *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewTicket creates the ticket of a problem reported by the user of the context
+func NewTicket(category, subject, description string, ticketContext TicketContext) (*Ticket, error) {
+	if !IsValidCategory(category) {
+		return nil, ErrInvalidCategory
+	}
+
+	subject = strings.TrimSpace(subject)
+	description = strings.TrimSpace(description)
+	if subject == "" || utf8.RuneCountInString(subject) > MaxSubjectLength {
+		return nil, ErrInvalidSubject
+	}
+	if description == "" || utf8.RuneCountInString(description) > MaxDescriptionLength {
+		return nil, ErrInvalidDescription
+	}
+
+	now := time.Now()
+	ticket := &Ticket{
+		UserID:      ticketContext.User.ID,
+		Category:    Category(category),
+		Subject:     subject,
+		Description: description,
+		Context:     ticketContext,
+		Status:      TicketStatusSubmitted,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if ticketContext.Order != nil {
+		ticket.OrderID = &ticketContext.Order.ID
+	}
+	return ticket, nil
+}
+
+// IsPushed tells whether the ticket is in the help desk
+func (t *Ticket) IsPushed() bool {
+	return t.ExternalID != ""
+}
+
+// Pushed records the ticket created in the help desk
+func (t *Ticket) Pushed(provider, externalID string, now time.Time) {
+	t.Provider = provider
+	t.ExternalID = externalID
+	t.Status = TicketStatusOpen
+	t.PushAttempts++
+	t.PushError = ""
+	t.SyncedAt = &now
+	t.UpdatedAt = now
+}
+
+// PushFailed records a failed attempt to create the ticket in the help desk, retried until MaxPushAttempts
+func (t *Ticket) PushFailed(err error, now time.Time) {
+	t.PushAttempts++
+	t.PushError = err.Error()
+	t.UpdatedAt = now
+}
+
+// Synced records the status the help desk reports and tells whether it changed
+func (t *Ticket) Synced(status TicketStatus, now time.Time) bool {
+	t.SyncedAt = &now
+	if status == t.Status {
+		return false
+	}
+	t.Status = status
+	t.UpdatedAt = now
+	return true
+}
+
+// Description renders the context of the ticket for the agents, after the words of the user
+func (c TicketContext) Description(description string) string {
+	var b strings.Builder
+	b.WriteString(description)
+	b.WriteString("\n\n---\n")
+	fmt.Fprintf(&b, "User: #%d %s <%s> (%s)\n", c.User.ID, c.User.Name, c.User.Email, c.User.UserType)
+	if order := c.Order; order != nil {
+		fmt.Fprintf(&b, "Order: %s (#%d), %s, %d tickets, %.2f %s, placed %s\n",
+			order.OrderNumber, order.ID, order.Status, order.TicketCount, order.TotalAmount, order.Currency, order.CreatedAt)
+		if order.EventID != nil {
+			fmt.Fprintf(&b, "Event: %s (#%d)\n", order.EventTitle, *order.EventID)
+		}
+	}
+	if c.Page != "" {
+		fmt.Fprintf(&b, "Page: %s\n", c.Page)
+	}
+	if c.UserAgent != "" {
+		fmt.Fprintf(&b, "User agent: %s\n", c.UserAgent)
+	}
+	return b.String()
+}
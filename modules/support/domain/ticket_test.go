@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTicket(t *testing.T) {
+	eventID := int64(7)
+	ticketContext := TicketContext{
+		User:  UserContext{ID: 3, Email: "jane@example.com", Name: "Jane Doe", UserType: "customer"},
+		Order: &OrderContext{ID: 42, OrderNumber: "ORD-42", EventID: &eventID},
+	}
+
+	ticket, err := NewTicket("order", "  Missing tickets ", " I paid but got no tickets\n", ticketContext)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), ticket.UserID)
+	require.NotNil(t, ticket.OrderID)
+	assert.Equal(t, int64(42), *ticket.OrderID)
+	assert.Equal(t, "Missing tickets", ticket.Subject)
+	assert.Equal(t, "I paid but got no tickets", ticket.Description)
+	assert.Equal(t, TicketStatusSubmitted, ticket.Status)
+	assert.False(t, ticket.IsPushed())
+
+	_, err = NewTicket("refund", "Subject", "Description", ticketContext)
+	assert.Equal(t, ErrInvalidCategory, err)
+
+	_, err = NewTicket("other", "   ", "Description", ticketContext)
+	assert.Equal(t, ErrInvalidSubject, err)
+
+	_, err = NewTicket("other", strings.Repeat("é", MaxSubjectLength+1), "Description", ticketContext)
+	assert.Equal(t, ErrInvalidSubject, err)
+
+	_, err = NewTicket("other", "Subject", strings.Repeat("a", MaxDescriptionLength+1), ticketContext)
+	assert.Equal(t, ErrInvalidDescription, err)
+
+	ticket, err = NewTicket("other", "Subject", strings.Repeat("é", MaxDescriptionLength), TicketContext{})
+	require.NoError(t, err, "lengths are counted in characters")
+	assert.Nil(t, ticket.OrderID)
+}
+
+func TestTicketPush(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	ticket := &Ticket{Status: TicketStatusSubmitted}
+
+	ticket.PushFailed(errors.New("zendesk is down"), now)
+	assert.Equal(t, 1, ticket.PushAttempts)
+	assert.Equal(t, "zendesk is down", ticket.PushError)
+	assert.False(t, ticket.IsPushed())
+
+	ticket.Pushed("zendesk", "1001", now)
+	assert.True(t, ticket.IsPushed())
+	assert.Equal(t, 2, ticket.PushAttempts)
+	assert.Empty(t, ticket.PushError)
+	assert.Equal(t, TicketStatusOpen, ticket.Status)
+	assert.Equal(t, &now, ticket.SyncedAt)
+}
+
+func TestTicketSynced(t *testing.T) {
+	created := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	now := created.Add(time.Hour)
+	ticket := &Ticket{Status: TicketStatusOpen, UpdatedAt: created}
+
+	assert.False(t, ticket.Synced(TicketStatusOpen, now))
+	assert.Equal(t, &now, ticket.SyncedAt)
+	assert.Equal(t, created, ticket.UpdatedAt, "an unchanged status does not update the ticket")
+
+	assert.True(t, ticket.Synced(TicketStatusSolved, now))
+	assert.Equal(t, TicketStatusSolved, ticket.Status)
+	assert.Equal(t, now, ticket.UpdatedAt)
+}
+
+func TestTicketContextDescription(t *testing.T) {
+	eventID := int64(7)
+	ticketContext := TicketContext{
+		User: UserContext{ID: 3, Email: "jane@example.com", Name: "Jane Doe", UserType: "customer"},
+		Order: &OrderContext{
+			ID: 42, OrderNumber: "ORD-42", Status: "confirmed", TotalAmount: 50, Currency: "USD", TicketCount: 2,
+			EventID: &eventID, EventTitle: "Jazz Night", CreatedAt: "2026-10-15T20:00:00Z",
+		},
+		Page:      "/orders/42",
+		UserAgent: "Mozilla/5.0",
+	}
+
+	assert.Equal(t, "I paid but got no tickets\n\n---\n"+
+		"User: #3 Jane Doe <jane@example.com> (customer)\n"+
+		"Order: ORD-42 (#42), confirmed, 2 tickets, 50.00 USD, placed 2026-10-15T20:00:00Z\n"+
+		"Event: Jazz Night (#7)\n"+
+		"Page: /orders/42\n"+
+		"User agent: Mozilla/5.0\n",
+		ticketContext.Description("I paid but got no tickets"))
+
+	assert.Equal(t, "Help\n\n---\nUser: #3 Jane Doe <jane@example.com> (customer)\n",
+		TicketContext{User: ticketContext.User}.Description("Help"))
+}
@@ -0,0 +1,131 @@
+package ports
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"tixgo/components"
+	"tixgo/modules/support/adapters"
+	"tixgo/modules/support/app/command"
+	"tixgo/modules/support/app/query"
+	"tixgo/shared/apiversion"
+	"tixgo/shared/feature"
+	"tixgo/shared/httpresponse"
+	"tixgo/shared/listing"
+	"tixgo/shared/ratelimit"
+	"tixgo/shared/session"
+	"tixgo/shared/strictjson"
+
+	"github.com/duongptryu/gox/context"
+
+	"github.com/gin-gonic/gin"
+)
+
+// reportLimit bounds the problems a user reports, a burst of 5 then one every 10 minutes
+var reportLimit = ratelimit.Limit{Rate: 6, Per: time.Hour, Burst: 5}
+
+// FeatureName is the feature the support routes are gated by
+const FeatureName = "support"
+
+func RegisterSupportRoutes(router *apiversion.Group, appCtx components.AppContext, features *feature.Flags) {
+	ticketGroup := router.Group("/support/tickets")
+	{
+		ticketGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		// Soft-launched under the support feature while the help desk integration is rolled out
+		ticketGroup.Use(features.Gate(FeatureName))
+		limit := ratelimit.PerCaller(ratelimit.NewRedisLimiter(appCtx.GetRedis()), "support.report", reportLimit)
+		ticketGroup.POST("", strictjson.Require(), limit, ReportProblem(appCtx))
+		ticketGroup.GET("", ListTickets(appCtx))
+		ticketGroup.GET("/:id", GetTicket(appCtx))
+	}
+}
+
+// ReportProblem opens a support ticket for the problem the user reports, with the context of their
+// account and of the order it is about
+func ReportProblem(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.ReportProblemCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		userID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.UserID = userID
+		req.UserAgent = c.Request.UserAgent()
+
+		handler := command.NewReportProblemHandler(
+			adapters.NewTicketPostgresRepository(appCtx.GetDB()),
+			adapters.NewContextPostgresReader(appCtx.GetDB()),
+		)
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusCreated, result)
+	}
+}
+
+func ListTickets(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		var paging listing.Paging
+		if err := c.ShouldBind(&paging); err != nil {
+			c.Error(err)
+			return
+		}
+
+		// Apply pagination defaults in HTTP layer
+		paging.Fulfill()
+
+		filters := query.ListTicketsQuery{UserID: userID}
+		handler := query.NewListTicketsHandler(adapters.NewTicketPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), &filters, &paging)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.List(c, result, paging, filters)
+	}
+}
+
+func GetTicket(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		userID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		handler := query.NewGetTicketHandler(adapters.NewTicketPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), query.GetTicketQuery{ID: id, UserID: userID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
@@ -0,0 +1,59 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"tixgo/components"
+	"tixgo/config"
+	"tixgo/modules/support/adapters"
+	"tixgo/modules/support/app/command"
+	"tixgo/modules/support/domain"
+	"tixgo/shared/scheduler"
+)
+
+const (
+	JobPushTickets = "support.push_tickets"
+	JobSyncTickets = "support.sync_tickets"
+)
+
+// Jobs returns the jobs of the support module, none without a help desk to push the tickets to
+func Jobs(appCtx components.AppContext, cfg config.Support) []scheduler.Job {
+	helpDesk := newHelpDesk(cfg)
+	if helpDesk == nil {
+		return nil
+	}
+
+	return []scheduler.Job{
+		{
+			Name:     JobPushTickets,
+			Schedule: "@every 1m",
+			Timeout:  5 * time.Minute,
+			Run: func(ctx context.Context) error {
+				ticketRepo := adapters.NewTicketPostgresRepository(appCtx.GetDB())
+				return command.NewPushTicketsHandler(ticketRepo, helpDesk).Handle(ctx)
+			},
+		},
+		{
+			Name:     JobSyncTickets,
+			Schedule: "@every 10m",
+			Timeout:  5 * time.Minute,
+			Run: func(ctx context.Context) error {
+				ticketRepo := adapters.NewTicketPostgresRepository(appCtx.GetDB())
+				return command.NewSyncTicketsHandler(ticketRepo, helpDesk).Handle(ctx)
+			},
+		},
+	}
+}
+
+// newHelpDesk returns the help desk of the configuration, nil without one
+func newHelpDesk(cfg config.Support) domain.HelpDesk {
+	switch cfg.Provider {
+	case adapters.ZendeskProvider:
+		return adapters.NewZendeskHelpDesk(cfg.BaseURL, cfg.Email, cfg.APIToken)
+	case adapters.FreshdeskProvider:
+		return adapters.NewFreshdeskHelpDesk(cfg.BaseURL, cfg.APIToken)
+	default:
+		return nil
+	}
+}
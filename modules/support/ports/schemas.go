@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"tixgo/modules/support/app/command"
+	"tixgo/shared/jsonschema"
+	"tixgo/shared/listing"
+)
+
+// Schemas returns the request payloads of the support module
+func Schemas() []jsonschema.Payload {
+	return []jsonschema.Payload{
+		{Name: "support.tickets.create", In: jsonschema.Body, Example: command.ReportProblemCommand{}},
+		{Name: "support.tickets", In: jsonschema.Query, Example: listing.Paging{}},
+	}
+}
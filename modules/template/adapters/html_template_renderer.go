@@ -3,159 +3,308 @@ package adapters
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"html/template"
+	"sort"
 	"strings"
+	templateParse "text/template/parse"
 
 	"tixgo/modules/template/domain"
+	"tixgo/shared/htmlsanitize"
 
+	"github.com/duongptryu/gox/logger"
 	"github.com/duongptryu/gox/syserr"
 )
 
+// templateFuncs are the helper functions available to every template
+var templateFuncs = template.FuncMap{
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+	"title":    strings.Title,
+	"trim":     strings.TrimSpace,
+	"contains": strings.Contains,
+	"replace":  strings.ReplaceAll,
+	"default": func(defaultValue interface{}, value interface{}) interface{} {
+		if value == nil || value == "" {
+			return defaultValue
+		}
+		return value
+	},
+	// safeHTML inserts a variable as markup rather than text, cleaned by the sanitization policy since
+	// variables come from users
+	"safeHTML": func(s string) template.HTML {
+		return template.HTML(htmlsanitize.Sanitize(s))
+	},
+	"safeURL": func(s string) template.URL {
+		return template.URL(s)
+	},
+	// asset is the URL of a hosted image, bound at every render to the assets resolved at compilation
+	"asset": func(name string) (string, error) {
+		return "", assetNotHosted(name)
+	},
+}
+
 // HTMLTemplateRenderer implements domain.TemplateRenderer using Go's html/template
-type HTMLTemplateRenderer struct{}
+type HTMLTemplateRenderer struct {
+	assets   domain.AssetResolver
+	themes   domain.ThemeResolver
+	defaults domain.DefaultsResolver
+}
 
-// NewHTMLTemplateRenderer creates a new HTML template renderer
-func NewHTMLTemplateRenderer() *HTMLTemplateRenderer {
-	return &HTMLTemplateRenderer{}
+// NewHTMLTemplateRenderer creates a new HTML template renderer resolving the asset function with assets,
+// the themes of organizers with themes and the overrides of the platform defaults with defaults. Without
+// assets, templates referencing one fail to render; without themes, every template renders with the
+// platform theme; without defaults, with the platform defaults as configured.
+func NewHTMLTemplateRenderer(assets domain.AssetResolver, themes domain.ThemeResolver, defaults domain.DefaultsResolver) *HTMLTemplateRenderer {
+	return &HTMLTemplateRenderer{assets: assets, themes: themes, defaults: defaults}
 }
 
-// Render renders a template with given variables
-func (r *HTMLTemplateRenderer) Render(ctx context.Context, tmpl *domain.Template, variables map[string]interface{}) (*domain.RenderedTemplate, error) {
-	// Ensure variables is not nil
-	if variables == nil {
-		variables = make(map[string]interface{})
+// Render renders a template with given variables, localized and branded by options
+func (r *HTMLTemplateRenderer) Render(ctx context.Context, tmpl *domain.Template, variables map[string]interface{}, options domain.RenderOptions) (*domain.RenderedTemplate, error) {
+	compiled, err := r.Compile(ctx, tmpl)
+	if err != nil {
+		return nil, err
 	}
 
-	// Render subject
-	renderedSubject, err := r.renderText(tmpl.Subject, variables)
+	if options.Theme == nil && options.OrganizerID != 0 && r.themes != nil {
+		// a mail is better sent with the platform theme than not at all
+		theme, err := r.themes.ResolveTheme(ctx, options.OrganizerID)
+		if err != nil {
+			logger.Warning(ctx, "Failed to resolve theme, rendering with the platform theme",
+				logger.F("organizer_id", options.OrganizerID), logger.F("error", err))
+		} else {
+			options.Theme = theme
+		}
+	}
+
+	if options.Defaults == nil && r.defaults != nil {
+		// as for themes, the configured defaults are better than no mail
+		defaults, err := r.defaults.ResolveDefaults(ctx, options.OrganizerID)
+		if err != nil {
+			logger.Warning(ctx, "Failed to resolve default variables, rendering with the configured ones",
+				logger.F("organizer_id", options.OrganizerID), logger.F("error", err))
+		} else {
+			options.Defaults = defaults
+		}
+	}
+
+	return compiled.Execute(variables, options)
+}
+
+// Compile parses the subject and content of a template once, to render it many times. The assets they
+// reference are resolved here, so renders need no lookup.
+func (r *HTMLTemplateRenderer) Compile(ctx context.Context, tmpl *domain.Template) (domain.CompiledTemplate, error) {
+	subject, err := parse("subject", tmpl.Subject)
 	if err != nil {
 		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to render subject")
 	}
 
-	// Render content
-	renderedContent, err := r.renderHTML(tmpl.Content, variables)
+	content, err := parse("content", tmpl.Content)
 	if err != nil {
 		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to render content")
 	}
 
-	return &domain.RenderedTemplate{
-		Subject:     renderedSubject,
-		Content:     renderedContent,
-		ContentType: "text/html",
-	}, nil
+	assets, err := r.resolveAssets(ctx, subject, content)
+	if err != nil {
+		return nil, err
+	}
+
+	return &compiledHTMLTemplate{subject: subject, content: content, assets: assets}, nil
 }
 
-// ValidateTemplate validates template syntax
+// ValidateTemplate validates template syntax and that the assets it references are hosted
 func (r *HTMLTemplateRenderer) ValidateTemplate(ctx context.Context, content string) error {
 	// Try to parse the template to check for syntax errors with helper functions
-	tmpl := template.New("validation").Funcs(template.FuncMap{
-		"upper":    strings.ToUpper,
-		"lower":    strings.ToLower,
-		"title":    strings.Title,
-		"trim":     strings.TrimSpace,
-		"contains": strings.Contains,
-		"replace":  strings.ReplaceAll,
-		"default": func(defaultValue interface{}, value interface{}) interface{} {
-			if value == nil || value == "" {
-				return defaultValue
-			}
-			return value
-		},
-		"safeHTML": func(s string) template.HTML {
-			return template.HTML(s)
-		},
-		"safeURL": func(s string) template.URL {
-			return template.URL(s)
-		},
-	})
-
-	_, err := tmpl.Parse(content)
+	parsed, err := parse("validation", content)
 	if err != nil {
 		return syserr.Wrap(err, syserr.InvalidArgumentCode, "template syntax error")
 	}
-	return nil
+
+	_, err = r.resolveAssets(ctx, parsed)
+	return err
 }
 
-// renderText renders plain text template (for subjects)
-func (r *HTMLTemplateRenderer) renderText(templateStr string, variables map[string]interface{}) (string, error) {
-	if templateStr == "" {
-		return "", nil
+// resolveAssets returns the URLs of the assets the parsed templates reference, failing with
+// domain.ErrAssetNotHosted for a name without one
+func (r *HTMLTemplateRenderer) resolveAssets(ctx context.Context, templates ...*template.Template) (map[string]string, error) {
+	names := make(map[string]bool)
+	for _, tmpl := range templates {
+		if tmpl == nil {
+			continue
+		}
+		for _, t := range tmpl.Templates() {
+			if t.Tree != nil {
+				collectAssetNames(t.Tree.Root, names)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil
 	}
 
-	// Create template with helper functions (same as HTML template)
-	tmpl := template.New("subject").Funcs(template.FuncMap{
-		"upper":    strings.ToUpper,
-		"lower":    strings.ToLower,
-		"title":    strings.Title,
-		"trim":     strings.TrimSpace,
-		"contains": strings.Contains,
-		"replace":  strings.ReplaceAll,
-		"default": func(defaultValue interface{}, value interface{}) interface{} {
-			if value == nil || value == "" {
-				return defaultValue
+	urls := map[string]string{}
+	if r.assets != nil {
+		list := make([]string, 0, len(names))
+		for name := range names {
+			list = append(list, name)
+		}
+		sort.Strings(list)
+
+		var err error
+		urls, err = r.assets.URLs(ctx, list)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to resolve assets")
+		}
+	}
+
+	for name := range names {
+		if _, ok := urls[name]; !ok {
+			return nil, assetNotHosted(name)
+		}
+	}
+	return urls, nil
+}
+
+// collectAssetNames adds the names the asset function is called with to names. Only names written as
+// string literals are found, which is why templates must write them so.
+func collectAssetNames(node templateParse.Node, names map[string]bool) {
+	switch n := node.(type) {
+	case *templateParse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			collectAssetNames(child, names)
+		}
+	case *templateParse.ActionNode:
+		collectAssetNames(n.Pipe, names)
+	case *templateParse.PipeNode:
+		if n == nil {
+			return
+		}
+		for i, cmd := range n.Cmds {
+			// "logo.png" | asset
+			if i > 0 && len(cmd.Args) == 1 && len(n.Cmds[i-1].Args) == 1 {
+				fn, isFunc := cmd.Args[0].(*templateParse.IdentifierNode)
+				name, isString := n.Cmds[i-1].Args[0].(*templateParse.StringNode)
+				if isFunc && isString && fn.Ident == "asset" {
+					names[name.Text] = true
+				}
 			}
-			return value
-		},
-		"safeHTML": func(s string) template.HTML {
-			return template.HTML(s)
-		},
-		"safeURL": func(s string) template.URL {
-			return template.URL(s)
-		},
-	})
-
-	tmpl, err := tmpl.Parse(templateStr)
+			collectAssetNames(cmd, names)
+		}
+	case *templateParse.CommandNode:
+		if len(n.Args) == 2 {
+			if fn, ok := n.Args[0].(*templateParse.IdentifierNode); ok && fn.Ident == "asset" {
+				if name, ok := n.Args[1].(*templateParse.StringNode); ok {
+					names[name.Text] = true
+				}
+			}
+		}
+		for _, arg := range n.Args {
+			collectAssetNames(arg, names)
+		}
+	case *templateParse.IfNode:
+		collectAssetNames(&n.BranchNode, names)
+	case *templateParse.RangeNode:
+		collectAssetNames(&n.BranchNode, names)
+	case *templateParse.WithNode:
+		collectAssetNames(&n.BranchNode, names)
+	case *templateParse.BranchNode:
+		collectAssetNames(n.Pipe, names)
+		collectAssetNames(n.List, names)
+		collectAssetNames(n.ElseList, names)
+	case *templateParse.TemplateNode:
+		collectAssetNames(n.Pipe, names)
+	}
+}
+
+func assetNotHosted(name string) error {
+	return syserr.Wrap(domain.ErrAssetNotHosted, syserr.InvalidArgumentCode, fmt.Sprintf("asset %q is not hosted, upload it first", name))
+}
+
+// compiledHTMLTemplate is a parsed template; an empty part is nil. The parsed parts are never executed
+// themselves, each render executes a clone bound to the locale functions of its options.
+type compiledHTMLTemplate struct {
+	subject *template.Template
+	content *template.Template
+	// assets are the URLs of the assets the template references, by name
+	assets map[string]string
+}
+
+// Execute renders the template with given variables, localized by options and branded by their theme,
+// the platform theme when none. The variables the caller leaves out take their default values, those of
+// the options over the platform ones. It is safe for concurrent use.
+func (t *compiledHTMLTemplate) Execute(variables map[string]interface{}, options domain.RenderOptions) (*domain.RenderedTemplate, error) {
+	// the defaults and theme are added to a copy, the variables of the caller are left as they are
+	defaults := domain.MergeDefaults(domain.PlatformDefaults(), options.Defaults)
+	branded := make(map[string]interface{}, len(defaults)+len(variables)+1)
+	for name, value := range defaults {
+		branded[name] = value
+	}
+	for name, value := range variables {
+		branded[name] = value
+	}
+	branded[domain.ThemeVariable] = options.Theme.WithDefaults().Variables()
+	variables = branded
+
+	locale, err := newLocale(options)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	funcs := locale.funcs()
+	funcs["asset"] = t.asset
 
-	var buf bytes.Buffer
-	err = tmpl.Execute(&buf, variables)
+	// Render subject
+	renderedSubject, err := execute(t.subject, variables, funcs)
 	if err != nil {
-		return "", err
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to render subject")
+	}
+
+	// Render content
+	renderedContent, err := execute(t.content, variables, funcs)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to render content")
 	}
 
-	return strings.TrimSpace(buf.String()), nil
+	return &domain.RenderedTemplate{
+		Subject:     strings.TrimSpace(renderedSubject),
+		Content:     renderedContent,
+		ContentType: "text/html",
+	}, nil
+}
+
+// asset returns the URL of the asset of a name resolved at compilation
+func (t *compiledHTMLTemplate) asset(name string) (string, error) {
+	url, ok := t.assets[name]
+	if !ok {
+		return "", assetNotHosted(name)
+	}
+	return url, nil
 }
 
-// renderHTML renders HTML template (for content)
-func (r *HTMLTemplateRenderer) renderHTML(templateStr string, variables map[string]interface{}) (string, error) {
+// parse parses a template with the helper functions, returning nil for an empty one
+func parse(name, templateStr string) (*template.Template, error) {
 	if templateStr == "" {
+		return nil, nil
+	}
+	return template.New(name).Funcs(templateFuncs).Funcs(defaultLocale.funcs()).Parse(templateStr)
+}
+
+func execute(tmpl *template.Template, variables map[string]interface{}, funcs template.FuncMap) (string, error) {
+	if tmpl == nil {
 		return "", nil
 	}
 
-	// Create template with helper functions
-	tmpl := template.New("content").Funcs(template.FuncMap{
-		"upper":    strings.ToUpper,
-		"lower":    strings.ToLower,
-		"title":    strings.Title,
-		"trim":     strings.TrimSpace,
-		"contains": strings.Contains,
-		"replace":  strings.ReplaceAll,
-		"default": func(defaultValue interface{}, value interface{}) interface{} {
-			if value == nil || value == "" {
-				return defaultValue
-			}
-			return value
-		},
-		"safeHTML": func(s string) template.HTML {
-			return template.HTML(s)
-		},
-		"safeURL": func(s string) template.URL {
-			return template.URL(s)
-		},
-	})
-
-	tmpl, err := tmpl.Parse(templateStr)
+	// an executed html/template can no longer be cloned, so the parsed one is left unexecuted
+	clone, err := tmpl.Clone()
 	if err != nil {
 		return "", err
 	}
 
 	var buf bytes.Buffer
-	err = tmpl.Execute(&buf, variables)
-	if err != nil {
+	if err := clone.Funcs(funcs).Execute(&buf, variables); err != nil {
 		return "", err
 	}
-
 	return buf.String(), nil
 }
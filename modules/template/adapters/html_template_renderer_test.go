@@ -2,16 +2,25 @@ package adapters
 
 import (
 	"context"
+	"io"
+	"os"
+	"strings"
 	"testing"
 
 	"tixgo/modules/template/domain"
 
+	"github.com/duongptryu/gox/logger"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+func TestMain(m *testing.M) {
+	logger.Init(&logger.Config{Output: io.Discard})
+	os.Exit(m.Run())
+}
+
 func TestHTMLTemplateRenderer_Render(t *testing.T) {
-	renderer := NewHTMLTemplateRenderer()
+	renderer := NewHTMLTemplateRenderer(nil, nil, nil)
 	ctx := context.Background()
 
 	tests := []struct {
@@ -102,7 +111,7 @@ func TestHTMLTemplateRenderer_Render(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := renderer.Render(ctx, tt.template, tt.variables)
+			result, err := renderer.Render(ctx, tt.template, tt.variables, domain.RenderOptions{})
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -118,7 +127,7 @@ func TestHTMLTemplateRenderer_Render(t *testing.T) {
 }
 
 func TestHTMLTemplateRenderer_ValidateTemplate(t *testing.T) {
-	renderer := NewHTMLTemplateRenderer()
+	renderer := NewHTMLTemplateRenderer(nil, nil, nil)
 	ctx := context.Background()
 
 	tests := []struct {
@@ -167,7 +176,7 @@ func TestHTMLTemplateRenderer_ValidateTemplate(t *testing.T) {
 }
 
 func TestHTMLTemplateRenderer_RenderComplexTemplate(t *testing.T) {
-	renderer := NewHTMLTemplateRenderer()
+	renderer := NewHTMLTemplateRenderer(nil, nil, nil)
 	ctx := context.Background()
 
 	template := &domain.Template{
@@ -201,7 +210,7 @@ func TestHTMLTemplateRenderer_RenderComplexTemplate(t *testing.T) {
 		"LoginLink":     "https://app.tixgo.com/login",
 	}
 
-	result, err := renderer.Render(ctx, template, variables)
+	result, err := renderer.Render(ctx, template, variables, domain.RenderOptions{})
 
 	require.NoError(t, err)
 	assert.Equal(t, "OTP Verification - tixgo", result.Subject)
@@ -212,3 +221,260 @@ func TestHTMLTemplateRenderer_RenderComplexTemplate(t *testing.T) {
 	assert.Contains(t, result.Content, `<a href="https://app.tixgo.com/login">Click here to login</a>`)
 	assert.Equal(t, "text/html", result.ContentType)
 }
+
+func TestHTMLTemplateRenderer_Compile(t *testing.T) {
+	renderer := NewHTMLTemplateRenderer(nil, nil, nil)
+	ctx := context.Background()
+
+	compiled, err := renderer.Compile(ctx, &domain.Template{
+		Subject: "Hi {{.Name}}",
+		Content: "<p>{{upper .Name}}</p>",
+	})
+	require.NoError(t, err)
+
+	for _, name := range []string{"ann", "bob"} {
+		result, err := compiled.Execute(map[string]interface{}{"Name": name}, domain.RenderOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "Hi "+name, result.Subject)
+		assert.Equal(t, "<p>"+strings.ToUpper(name)+"</p>", result.Content)
+	}
+
+	_, err = renderer.Compile(ctx, &domain.Template{Content: "{{.Name"})
+	assert.Error(t, err)
+}
+
+func TestHTMLTemplateRenderer_Localized(t *testing.T) {
+	renderer := NewHTMLTemplateRenderer(nil, nil, nil)
+	ctx := context.Background()
+
+	compiled, err := renderer.Compile(ctx, &domain.Template{
+		Subject: "{{.Event}} on {{formatDate .StartsAt}}",
+		Content: `{{formatDate .StartsAt}} {{formatTime .StartsAt}}|{{formatDate .Day}}|{{formatMoney .Total .Currency}}`,
+	})
+	require.NoError(t, err)
+
+	variables := map[string]interface{}{
+		"Event":    "Concert",
+		"StartsAt": "2026-03-01T23:30:00Z",
+		"Day":      "2026-03-01",
+		"Total":    "1234567.5",
+		"Currency": "USD",
+	}
+
+	tests := []struct {
+		name     string
+		options  domain.RenderOptions
+		currency string
+		subject  string
+		content  string
+	}{
+		{
+			name:     "defaults to American English in UTC",
+			currency: "USD",
+			subject:  "Concert on Mar 1, 2026",
+			content:  "Mar 1, 2026 11:30 PM|Mar 1, 2026|$1,234,567.50",
+		},
+		{
+			name:     "local time of the recipient, dates stay dates",
+			options:  domain.RenderOptions{Locale: "vi-VN", TimeZone: "Asia/Ho_Chi_Minh"},
+			currency: "VND",
+			subject:  "Concert on 02/03/2026",
+			content:  "02/03/2026 06:30|01/03/2026|1.234.568\u00a0₫",
+		},
+		{
+			name:     "Accept-Language header",
+			options:  domain.RenderOptions{Locale: "fr-CH, de;q=0.9, en;q=0.5", TimeZone: "Europe/Zurich"},
+			currency: "CHF",
+			subject:  "Concert on 02/03/2026",
+			content:  "02/03/2026 00:30|01/03/2026|1\u00a0234\u00a0567,50\u00a0CHF",
+		},
+		{
+			name:     "unsupported locale falls back",
+			options:  domain.RenderOptions{Locale: "not a locale"},
+			currency: "EUR",
+			subject:  "Concert on Mar 1, 2026",
+			content:  "Mar 1, 2026 11:30 PM|Mar 1, 2026|€1,234,567.50",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			variables["Currency"] = tt.currency
+
+			result, err := compiled.Execute(variables, tt.options)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.subject, result.Subject)
+			assert.Equal(t, tt.content, result.Content)
+		})
+	}
+
+	_, err = compiled.Execute(variables, domain.RenderOptions{TimeZone: "Mars/Olympus"})
+	assert.ErrorIs(t, err, domain.ErrInvalidTimeZone)
+}
+
+func TestHTMLTemplateRenderer_SafeHTMLIsSanitized(t *testing.T) {
+	renderer := NewHTMLTemplateRenderer(nil, nil, nil)
+
+	rendered, err := renderer.Render(context.Background(), &domain.Template{
+		Content: `<div>{{safeHTML .Bio}}</div>`,
+	}, map[string]interface{}{
+		"Bio": `<p onclick="steal()">Hi <b>there</b></p><script>steal()</script><a href="javascript:steal()">me</a>`,
+	}, domain.RenderOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, `<div><p>Hi <b>there</b></p><a>me</a></div>`, rendered.Content)
+}
+
+// hostedAssets resolves the assets of a map, counting the lookups
+type hostedAssets struct {
+	urls    map[string]string
+	lookups int
+}
+
+func (a *hostedAssets) URLs(_ context.Context, names []string) (map[string]string, error) {
+	a.lookups++
+	urls := map[string]string{}
+	for _, name := range names {
+		if url, ok := a.urls[name]; ok {
+			urls[name] = url
+		}
+	}
+	return urls, nil
+}
+
+func TestHTMLTemplateRenderer_Assets(t *testing.T) {
+	assets := &hostedAssets{urls: map[string]string{
+		"logo.png":   "https://cdn.tixgo.test/assets/0a1b.png",
+		"banner.jpg": "https://cdn.tixgo.test/assets/2c3d.jpg",
+	}}
+	renderer := NewHTMLTemplateRenderer(assets, nil, nil)
+	ctx := context.Background()
+
+	compiled, err := renderer.Compile(ctx, &domain.Template{
+		Content: `<img src="{{asset "logo.png"}}">{{if .Promo}}<img src="{{"banner.jpg" | asset}}">{{end}}`,
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		result, err := compiled.Execute(map[string]interface{}{"Promo": true}, domain.RenderOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, `<img src="https://cdn.tixgo.test/assets/0a1b.png"><img src="https://cdn.tixgo.test/assets/2c3d.jpg">`, result.Content)
+	}
+	assert.Equal(t, 1, assets.lookups, "assets are resolved once at compilation")
+
+	_, err = renderer.Compile(ctx, &domain.Template{Content: `<img src="{{asset "missing.png"}}">`})
+	assert.ErrorIs(t, err, domain.ErrAssetNotHosted)
+	assert.ErrorIs(t, renderer.ValidateTemplate(ctx, `{{define "footer"}}<img src="{{asset "missing.png"}}">{{end}}`), domain.ErrAssetNotHosted)
+	assert.NoError(t, renderer.ValidateTemplate(ctx, `<img src="{{asset "logo.png"}}">`))
+
+	// names only known at render time cannot be resolved
+	compiled, err = renderer.Compile(ctx, &domain.Template{Content: `<img src="{{asset .Logo}}">`})
+	require.NoError(t, err)
+	_, err = compiled.Execute(map[string]interface{}{"Logo": "logo.png"}, domain.RenderOptions{})
+	assert.ErrorIs(t, err, domain.ErrAssetNotHosted)
+
+	_, err = NewHTMLTemplateRenderer(nil, nil, nil).Compile(ctx, &domain.Template{Content: `<img src="{{asset "logo.png"}}">`})
+	assert.ErrorIs(t, err, domain.ErrAssetNotHosted)
+}
+
+type organizerThemes struct {
+	themes map[int64]*domain.Theme
+	err    error
+}
+
+func (o *organizerThemes) ResolveTheme(ctx context.Context, organizerID int64) (*domain.Theme, error) {
+	return o.themes[organizerID], o.err
+}
+
+func TestHTMLTemplateRenderer_Themes(t *testing.T) {
+	themes := &organizerThemes{themes: map[int64]*domain.Theme{
+		7: {PrimaryColor: "#0f766e", LogoURL: "https://cdn.example.com/logo.png"},
+	}}
+	renderer := NewHTMLTemplateRenderer(nil, themes, nil)
+	ctx := context.Background()
+	tmpl := &domain.Template{
+		Subject: "Tickets for {{.event_title}}",
+		Content: `<h1 style="color: {{.theme.primary_color}}">{{with .theme.logo_url}}<img src="{{.}}">{{end}}</h1>` +
+			`<a style="color: {{.theme.accent_color}}">View</a><p>{{.theme.footer_text}}</p>`,
+	}
+	variables := map[string]interface{}{"event_title": "Jazz Night"}
+
+	result, err := renderer.Render(ctx, tmpl, variables, domain.RenderOptions{OrganizerID: 7})
+	require.NoError(t, err)
+	assert.Equal(t, `<h1 style="color: #0f766e"><img src="https://cdn.example.com/logo.png"></h1>`+
+		`<a style="color: #4f46e5">View</a><p>`+domain.PlatformTheme.FooterText+`</p>`, result.Content,
+		"the fields the organizer left empty are the platform defaults")
+	assert.NotContains(t, variables, domain.ThemeVariable, "the variables of the caller are left as they are")
+
+	platform := `<h1 style="color: #1f2937"></h1><a style="color: #4f46e5">View</a><p>` + domain.PlatformTheme.FooterText + `</p>`
+	for name, options := range map[string]domain.RenderOptions{
+		"no organizer":          {},
+		"organizer of no theme": {OrganizerID: 8},
+	} {
+		result, err := renderer.Render(ctx, tmpl, variables, options)
+		require.NoError(t, err, name)
+		assert.Equal(t, platform, result.Content, name)
+	}
+
+	result, err = renderer.Render(ctx, tmpl, variables, domain.RenderOptions{OrganizerID: 7, Theme: &domain.Theme{FooterText: "Draft"}})
+	require.NoError(t, err)
+	assert.Equal(t, `<h1 style="color: #1f2937"></h1><a style="color: #4f46e5">View</a><p>Draft</p>`, result.Content,
+		"a given theme is rendered in place of the one of the organizer")
+
+	themes.err = assert.AnError
+	result, err = renderer.Render(ctx, tmpl, variables, domain.RenderOptions{OrganizerID: 7})
+	require.NoError(t, err, "a theme that cannot be resolved does not keep the mail from being sent")
+	assert.Equal(t, platform, result.Content)
+}
+
+type variableDefaults struct {
+	platform   map[string]string
+	organizers map[int64]map[string]string
+	err        error
+}
+
+func (v *variableDefaults) ResolveDefaults(ctx context.Context, organizerID int64) (map[string]string, error) {
+	return domain.MergeDefaults(v.platform, v.organizers[organizerID]), v.err
+}
+
+func TestHTMLTemplateRenderer_Defaults(t *testing.T) {
+	require.NoError(t, domain.SetPlatformDefaults(map[string]string{"AppName": "TixGo", "SupportEmail": "support@tixgo.io", "HelpURL": "https://tixgo.io/help"}))
+	t.Cleanup(func() { require.NoError(t, domain.SetPlatformDefaults(nil)) })
+
+	defaults := &variableDefaults{
+		platform:   map[string]string{"SupportEmail": "help@tixgo.io"},
+		organizers: map[int64]map[string]string{7: {"AppName": "Jazz Club Tickets"}},
+	}
+	renderer := NewHTMLTemplateRenderer(nil, nil, defaults)
+	ctx := context.Background()
+	tmpl := &domain.Template{
+		Subject: "Welcome to {{.AppName}}",
+		Content: `<p>Hi {{.Name}}, write to {{.SupportEmail}} or see <a href="{{.HelpURL}}">help</a>.</p>`,
+	}
+	variables := map[string]interface{}{"Name": "An"}
+
+	result, err := renderer.Render(ctx, tmpl, variables, domain.RenderOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "Welcome to TixGo", result.Subject)
+	assert.Equal(t, `<p>Hi An, write to help@tixgo.io or see <a href="https://tixgo.io/help">help</a>.</p>`, result.Content,
+		"the platform overrides win over the configuration")
+	assert.Len(t, variables, 1, "the variables of the caller are left as they are")
+
+	result, err = renderer.Render(ctx, tmpl, variables, domain.RenderOptions{OrganizerID: 7})
+	require.NoError(t, err)
+	assert.Equal(t, "Welcome to Jazz Club Tickets", result.Subject, "the organizer overrides win over the platform ones")
+
+	result, err = renderer.Render(ctx, tmpl, map[string]interface{}{"Name": "An", "AppName": "TixGo Box Office"}, domain.RenderOptions{OrganizerID: 7})
+	require.NoError(t, err)
+	assert.Equal(t, "Welcome to TixGo Box Office", result.Subject, "the variables of the caller win over every default")
+
+	defaults.err = assert.AnError
+	result, err = renderer.Render(ctx, tmpl, variables, domain.RenderOptions{OrganizerID: 7})
+	require.NoError(t, err, "defaults that cannot be resolved do not keep the mail from being sent")
+	assert.Equal(t, "Welcome to TixGo", result.Subject)
+	assert.Contains(t, result.Content, "support@tixgo.io", "the configured defaults are rendered")
+
+	assert.ErrorIs(t, domain.SetPlatformDefaults(map[string]string{"app name": "TixGo"}), domain.ErrInvalidVariableName)
+	assert.ErrorIs(t, domain.SetPlatformDefaults(map[string]string{domain.ThemeVariable: "dark"}), domain.ErrInvalidVariableName)
+}
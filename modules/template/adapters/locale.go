@@ -0,0 +1,201 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"tixgo/modules/template/domain"
+	"tixgo/shared/money"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// localeFormat is how a locale writes dates, times and amounts
+type localeFormat struct {
+	tag        language.Tag
+	dateLayout string
+	timeLayout string
+	// symbolAfter places the currency symbol after the amount
+	symbolAfter bool
+}
+
+// localeFormats are the locales templates are formatted in, the first one being the fallback
+var localeFormats = []localeFormat{
+	{tag: language.AmericanEnglish, dateLayout: "Jan 2, 2006", timeLayout: "3:04 PM"},
+	{tag: language.BritishEnglish, dateLayout: "2 Jan 2006", timeLayout: "15:04"},
+	{tag: language.Vietnamese, dateLayout: "02/01/2006", timeLayout: "15:04", symbolAfter: true},
+	{tag: language.German, dateLayout: "02.01.2006", timeLayout: "15:04", symbolAfter: true},
+	{tag: language.French, dateLayout: "02/01/2006", timeLayout: "15:04", symbolAfter: true},
+	{tag: language.Spanish, dateLayout: "02/01/2006", timeLayout: "15:04", symbolAfter: true},
+	{tag: language.Italian, dateLayout: "02/01/2006", timeLayout: "15:04", symbolAfter: true},
+	{tag: language.BrazilianPortuguese, dateLayout: "02/01/2006", timeLayout: "15:04"},
+	{tag: language.EuropeanPortuguese, dateLayout: "02/01/2006", timeLayout: "15:04", symbolAfter: true},
+	{tag: language.Dutch, dateLayout: "02-01-2006", timeLayout: "15:04"},
+	{tag: language.Japanese, dateLayout: "2006/01/02", timeLayout: "15:04"},
+	{tag: language.Chinese, dateLayout: "2006/01/02", timeLayout: "15:04"},
+}
+
+var localeMatcher = func() language.Matcher {
+	tags := make([]language.Tag, len(localeFormats))
+	for i, format := range localeFormats {
+		tags[i] = format.tag
+	}
+	return language.NewMatcher(tags)
+}()
+
+// locale formats the values of one render for its recipient
+type locale struct {
+	format   localeFormat
+	printer  *message.Printer
+	location *time.Location
+}
+
+// defaultLocale formats in American English and UTC
+var defaultLocale = &locale{
+	format:   localeFormats[0],
+	printer:  message.NewPrinter(localeFormats[0].tag),
+	location: time.UTC,
+}
+
+// newLocale resolves render options. A locale that can't be parsed falls back to the default one,
+// as it usually comes from a header; an unknown time zone is an error.
+func newLocale(options domain.RenderOptions) (*locale, error) {
+	if options.Locale == "" && options.TimeZone == "" {
+		return defaultLocale, nil
+	}
+
+	l := *defaultLocale
+	if options.TimeZone != "" {
+		location, err := time.LoadLocation(options.TimeZone)
+		if err != nil {
+			return nil, domain.ErrInvalidTimeZone
+		}
+		l.location = location
+	}
+
+	if tags, _, err := language.ParseAcceptLanguage(options.Locale); err == nil && len(tags) > 0 {
+		tag, index, _ := localeMatcher.Match(tags...)
+		l.format = localeFormats[index]
+		l.printer = message.NewPrinter(tag)
+	}
+
+	return &l, nil
+}
+
+// funcs are the template functions formatting with the locale
+func (l *locale) funcs() template.FuncMap {
+	return template.FuncMap{
+		"formatDate":  l.formatDate,
+		"formatTime":  l.formatTime,
+		"formatMoney": l.formatMoney,
+	}
+}
+
+// formatDate writes the date of a time in the time zone, or a date as is
+func (l *locale) formatDate(value interface{}) (string, error) {
+	t, dateOnly, err := toTime(value)
+	if err != nil || t.IsZero() {
+		return "", err
+	}
+	if !dateOnly {
+		t = t.In(l.location)
+	}
+	return t.Format(l.format.dateLayout), nil
+}
+
+// formatTime writes the time of day of a time in the time zone
+func (l *locale) formatTime(value interface{}) (string, error) {
+	t, _, err := toTime(value)
+	if err != nil || t.IsZero() {
+		return "", err
+	}
+	return t.In(l.location).Format(l.format.timeLayout), nil
+}
+
+// formatMoney writes an amount with the separators of the locale, the digits of the currency and
+// its symbol
+func (l *locale) formatMoney(amount interface{}, code string) (string, error) {
+	value, err := toFloat(amount)
+	if err != nil {
+		return "", err
+	}
+
+	normalized, err := money.Normalize(code)
+	if err != nil {
+		return "", fmt.Errorf("formatMoney: unknown currency %q", code)
+	}
+	unit := currency.MustParseISO(normalized)
+
+	formatted := l.printer.Sprint(number.Decimal(value, number.Scale(money.Digits(normalized))))
+	symbol := l.printer.Sprint(currency.Symbol(unit))
+
+	// a no-break space keeps the symbol on the line of its amount
+	if l.format.symbolAfter {
+		return formatted + "\u00a0" + symbol, nil
+	}
+	if last := []rune(symbol); unicode.IsLetter(last[len(last)-1]) {
+		return symbol + "\u00a0" + formatted, nil
+	}
+	return symbol + formatted, nil
+}
+
+// toTime reads the times templates are given: time values and RFC 3339 or plain date strings.
+// Nothing or an empty string is the zero time.
+func toTime(value interface{}) (t time.Time, dateOnly bool, err error) {
+	switch v := value.(type) {
+	case nil:
+		return time.Time{}, false, nil
+	case time.Time:
+		return v, false, nil
+	case *time.Time:
+		if v == nil {
+			return time.Time{}, false, nil
+		}
+		return *v, false, nil
+	case string:
+		if v == "" {
+			return time.Time{}, false, nil
+		}
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t, false, nil
+		}
+		if t, err := time.Parse(time.DateOnly, v); err == nil {
+			return t, true, nil
+		}
+		return time.Time{}, false, fmt.Errorf("cannot read %q as a date", v)
+	default:
+		return time.Time{}, false, fmt.Errorf("cannot read a date from %T", value)
+	}
+}
+
+// toFloat reads the amounts templates are given: numbers, JSON numbers and decimal strings
+func toFloat(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case json.Number:
+		return v.Float64()
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("cannot read %q as an amount", v)
+		}
+		return f, nil
+	default:
+		return 0, fmt.Errorf("cannot read an amount from %T", value)
+	}
+}
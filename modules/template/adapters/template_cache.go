@@ -0,0 +1,106 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"tixgo/modules/template/domain"
+	"tixgo/shared/cache"
+
+	"github.com/duongptryu/gox/logger"
+)
+
+// CachedTemplateRepository serves GetByID and GetBySlug from the cache and invalidates it on writes.
+// Templates are cached by ID only; a slug maps to the ID, so renaming or deleting a template
+// needs a single invalidation and a stale slug mapping is detected on read.
+type CachedTemplateRepository struct {
+	domain.TemplateRepository
+	cache *cache.Cache
+}
+
+// NewCachedTemplateRepository wraps repo with a read-through cache
+func NewCachedTemplateRepository(repo domain.TemplateRepository, c *cache.Cache) *CachedTemplateRepository {
+	return &CachedTemplateRepository{TemplateRepository: repo, cache: c}
+}
+
+// GetByID retrieves a template by ID
+func (r *CachedTemplateRepository) GetByID(ctx context.Context, id int64) (*domain.Template, error) {
+	return cache.GetOrLoad(ctx, r.cache, templateCacheKey(id), func(ctx context.Context) (*domain.Template, error) {
+		return r.TemplateRepository.GetByID(ctx, id)
+	})
+}
+
+// GetBySlug retrieves a template by slug
+func (r *CachedTemplateRepository) GetBySlug(ctx context.Context, slug string) (*domain.Template, error) {
+	var id int64
+	err := r.cache.Get(ctx, templateSlugCacheKey(slug), &id)
+	if err == nil {
+		template, err := r.GetByID(ctx, id)
+		if err == nil && template.Slug == slug {
+			return template, nil
+		}
+		if err != nil && !errors.Is(err, domain.ErrTemplateNotFound) {
+			return nil, err
+		}
+		// the template was renamed or deleted since the slug was cached
+	} else if !errors.Is(err, cache.ErrMiss) {
+		logger.Warning(ctx, "Cache lookup failed", logger.F("slug", slug), logger.F("error", err))
+	}
+
+	template, err := r.TemplateRepository.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.cache.Set(ctx, templateSlugCacheKey(slug), template.ID); err != nil {
+		logger.Warning(ctx, "Cache fill failed", logger.F("slug", slug), logger.F("error", err))
+	}
+	if err := r.cache.Set(ctx, templateCacheKey(template.ID), template); err != nil {
+		logger.Warning(ctx, "Cache fill failed", logger.F("template_id", template.ID), logger.F("error", err))
+	}
+	return template, nil
+}
+
+// Update updates an existing template
+func (r *CachedTemplateRepository) Update(ctx context.Context, template *domain.Template) error {
+	if err := r.TemplateRepository.Update(ctx, template); err != nil {
+		return err
+	}
+	r.invalidate(ctx, template.ID)
+	return nil
+}
+
+// ApplyRevision saves template with an approved revision applied, along with the approval
+func (r *CachedTemplateRepository) ApplyRevision(ctx context.Context, template *domain.Template, revision *domain.TemplateRevision) error {
+	if err := r.TemplateRepository.ApplyRevision(ctx, template, revision); err != nil {
+		return err
+	}
+	r.invalidate(ctx, template.ID)
+	return nil
+}
+
+// Delete deletes a template by ID
+func (r *CachedTemplateRepository) Delete(ctx context.Context, id int64) error {
+	if err := r.TemplateRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.invalidate(ctx, id)
+	return nil
+}
+
+// invalidate drops the cached template. The write already succeeded, so a failure is only logged:
+// the entry then expires with the cache TTL.
+func (r *CachedTemplateRepository) invalidate(ctx context.Context, id int64) {
+	if err := r.cache.Delete(ctx, templateCacheKey(id)); err != nil {
+		logger.Error(ctx, "Failed to invalidate cached template", logger.F("template_id", id), logger.F("error", err))
+	}
+}
+
+func templateCacheKey(id int64) string {
+	return "template:" + strconv.FormatInt(id, 10)
+}
+
+func templateSlugCacheKey(slug string) string {
+	return "template:slug:" + slug
+}
@@ -4,17 +4,114 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"strings"
 	"time"
 
 	"tixgo/modules/template/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/listing"
+	"tixgo/shared/pgerr"
+	"tixgo/shared/pgquery"
 
-	"github.com/duongptryu/gox/pagination"
 	"github.com/duongptryu/gox/syserr"
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 )
 
+// templateColumns are the columns of templateRow, in the order they are selected
+const templateColumns = `id, name, slug, subject, content, type, status, variables, description,
+	approved, content_version, created_by, created_at, updated_at`
+
+// templateRow is a row of the templates table
+type templateRow struct {
+	ID          int64                 `db:"id"`
+	Name        string                `db:"name"`
+	Slug        string                `db:"slug"`
+	Subject     string                `db:"subject"`
+	Content     string                `db:"content"`
+	Type        domain.TemplateType   `db:"type"`
+	Status      domain.TemplateStatus `db:"status"`
+	Variables   pq.StringArray        `db:"variables"`
+	Description string                `db:"description"`
+	Approved    bool                  `db:"approved"`
+	Version     int                   `db:"content_version"`
+	CreatedBy   int64                 `db:"created_by"`
+	CreatedAt   time.Time             `db:"created_at"`
+	UpdatedAt   time.Time             `db:"updated_at"`
+}
+
+func newTemplateRow(template *domain.Template) *templateRow {
+	return &templateRow{
+		ID:          template.ID,
+		Name:        template.Name,
+		Slug:        template.Slug,
+		Subject:     template.Subject,
+		Content:     template.Content,
+		Type:        template.Type,
+		Status:      template.Status,
+		Variables:   pq.StringArray(template.Variables),
+		Description: template.Description,
+		Approved:    template.Approved,
+		Version:     template.ContentVersion,
+		CreatedBy:   template.CreatedBy,
+		CreatedAt:   template.CreatedAt,
+		UpdatedAt:   template.UpdatedAt,
+	}
+}
+
+func (row *templateRow) toDomain() *domain.Template {
+	return &domain.Template{
+		ID:             row.ID,
+		Name:           row.Name,
+		Slug:           row.Slug,
+		Subject:        row.Subject,
+		Content:        row.Content,
+		Type:           row.Type,
+		Status:         row.Status,
+		Variables:      []string(row.Variables),
+		Description:    row.Description,
+		Approved:       row.Approved,
+		ContentVersion: row.Version,
+		CreatedBy:      row.CreatedBy,
+		CreatedAt:      row.CreatedAt,
+		UpdatedAt:      row.UpdatedAt,
+	}
+}
+
+// templateSummaryColumns are the columns of templateSummaryRow, in the order they are selected
+const templateSummaryColumns = `id, name, slug, subject, type, status, description,
+	approved, created_by, created_at, updated_at`
+
+// templateSummaryRow is a row of the templates table without its content
+type templateSummaryRow struct {
+	ID          int64                 `db:"id"`
+	Name        string                `db:"name"`
+	Slug        string                `db:"slug"`
+	Subject     string                `db:"subject"`
+	Type        domain.TemplateType   `db:"type"`
+	Status      domain.TemplateStatus `db:"status"`
+	Description string                `db:"description"`
+	Approved    bool                  `db:"approved"`
+	CreatedBy   int64                 `db:"created_by"`
+	CreatedAt   time.Time             `db:"created_at"`
+	UpdatedAt   time.Time             `db:"updated_at"`
+}
+
+func (row *templateSummaryRow) toDomain() *domain.TemplateSummary {
+	return &domain.TemplateSummary{
+		ID:          row.ID,
+		Name:        row.Name,
+		Slug:        row.Slug,
+		Subject:     row.Subject,
+		Type:        row.Type,
+		Status:      row.Status,
+		Description: row.Description,
+		Approved:    row.Approved,
+		CreatedBy:   row.CreatedBy,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+}
+
 // TemplatePostgresRepository implements the TemplateRepository interface using PostgreSQL
 type TemplatePostgresRepository struct {
 	db *sqlx.DB
@@ -27,29 +124,20 @@ func NewTemplatePostgresRepository(db *sqlx.DB) *TemplatePostgresRepository {
 
 // Create creates a new template in the database
 func (r *TemplatePostgresRepository) Create(ctx context.Context, template *domain.Template) error {
-	query := `
-		INSERT INTO templates (name, slug, subject, content, type, status, variables, description, created_by, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
-		RETURNING id`
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
 
-	err := r.db.QueryRowContext(
-		ctx,
-		query,
-		template.Name,
-		template.Slug,
-		template.Subject,
-		template.Content,
-		template.Type,
-		template.Status,
-		pq.Array(template.Variables),
-		template.Description,
-		template.CreatedBy,
-		template.CreatedAt,
-		template.UpdatedAt,
-	).Scan(&template.ID)
+	query, args, err := r.db.BindNamed(`
+		INSERT INTO templates (name, slug, subject, content, type, status, variables, description, approved, created_by, created_at, updated_at)
+		VALUES (:name, :slug, :subject, :content, :type, :status, :variables, :description, :approved, :created_by, :created_at, :updated_at)
+		RETURNING id`, newTemplateRow(template))
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to bind template")
+	}
 
+	err = r.db.GetContext(ctx, &template.ID, query, args...)
 	if err != nil {
-		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint") {
+		if pgerr.IsUniqueViolation(err) {
 			return domain.ErrTemplateAlreadyExists
 		}
 		return syserr.Wrap(err, syserr.InternalCode, "failed to create template")
@@ -60,28 +148,11 @@ func (r *TemplatePostgresRepository) Create(ctx context.Context, template *domai
 
 // GetByID retrieves a template by ID
 func (r *TemplatePostgresRepository) GetByID(ctx context.Context, id int64) (*domain.Template, error) {
-	query := `
-		SELECT id, name, slug, subject, content, type, status, variables, description, 
-		       created_by, created_at, updated_at
-		FROM templates 
-		WHERE id = $1`
-
-	template := &domain.Template{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&template.ID,
-		&template.Name,
-		&template.Slug,
-		&template.Subject,
-		&template.Content,
-		&template.Type,
-		&template.Status,
-		pq.Array(&template.Variables),
-		&template.Description,
-		&template.CreatedBy,
-		&template.CreatedAt,
-		&template.UpdatedAt,
-	)
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
 
+	row := &templateRow{}
+	err := r.db.GetContext(ctx, row, `SELECT `+templateColumns+` FROM templates WHERE id = $1`, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, domain.ErrTemplateNotFound
@@ -89,33 +160,16 @@ func (r *TemplatePostgresRepository) GetByID(ctx context.Context, id int64) (*do
 		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get template by ID")
 	}
 
-	return template, nil
+	return row.toDomain(), nil
 }
 
 // GetBySlug retrieves a template by slug
 func (r *TemplatePostgresRepository) GetBySlug(ctx context.Context, slug string) (*domain.Template, error) {
-	query := `
-		SELECT id, name, slug, subject, content, type, status, variables, description, 
-		       created_by, created_at, updated_at
-		FROM templates 
-		WHERE slug = $1`
-
-	template := &domain.Template{}
-	err := r.db.QueryRowContext(ctx, query, slug).Scan(
-		&template.ID,
-		&template.Name,
-		&template.Slug,
-		&template.Subject,
-		&template.Content,
-		&template.Type,
-		&template.Status,
-		pq.Array(&template.Variables),
-		&template.Description,
-		&template.CreatedBy,
-		&template.CreatedAt,
-		&template.UpdatedAt,
-	)
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
 
+	row := &templateRow{}
+	err := r.db.GetContext(ctx, row, `SELECT `+templateColumns+` FROM templates WHERE slug = $1`, slug)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, domain.ErrTemplateNotFound
@@ -123,133 +177,211 @@ func (r *TemplatePostgresRepository) GetBySlug(ctx context.Context, slug string)
 		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get template by slug")
 	}
 
-	return template, nil
+	return row.toDomain(), nil
 }
 
 // List retrieves templates with pagination and filters
-func (r *TemplatePostgresRepository) List(ctx context.Context, filters domain.ListTemplateFilters, paging *pagination.Paging) ([]*domain.Template, error) {
-	// Build WHERE clause
-	var conditions []string
-	var args []interface{}
-	argCount := 0
+func (r *TemplatePostgresRepository) List(ctx context.Context, filters domain.ListTemplateFilters, paging *listing.Paging) ([]*domain.Template, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
 
-	if filters.Type != nil {
-		argCount++
-		conditions = append(conditions, fmt.Sprintf("type = $%d", argCount))
-		args = append(args, *filters.Type)
+	query, args, err := r.listQuery(ctx, templateColumns, filters, paging)
+	if err != nil {
+		return nil, err
 	}
 
-	if filters.Status != nil {
-		argCount++
-		conditions = append(conditions, fmt.Sprintf("status = $%d", argCount))
-		args = append(args, *filters.Status)
+	var templates []*domain.Template
+	err = queryRows(ctx, r.db, query, args, func(row *templateRow) error {
+		templates = append(templates, row.toDomain())
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if filters.CreatedBy != nil {
-		argCount++
-		conditions = append(conditions, fmt.Sprintf("created_by = $%d", argCount))
-		args = append(args, *filters.CreatedBy)
-	}
+	return templates[:paging.Fetched(len(templates))], nil
+}
 
-	if filters.Search != "" {
-		argCount++
-		conditions = append(conditions, fmt.Sprintf("(name ILIKE $%d OR description ILIKE $%d OR slug ILIKE $%d)", argCount, argCount, argCount))
-		args = append(args, "%"+filters.Search+"%")
-	}
+// ListSummaries retrieves the summaries of templates with pagination and filters, without reading their content
+func (r *TemplatePostgresRepository) ListSummaries(ctx context.Context, filters domain.ListTemplateFilters, paging *listing.Paging) ([]*domain.TemplateSummary, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
 
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	query, args, err := r.listQuery(ctx, templateSummaryColumns, filters, paging)
+	if err != nil {
+		return nil, err
 	}
 
-	// Count query
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM templates %s", whereClause)
-	var total int64
-	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
+	var summaries []*domain.TemplateSummary
+	err = queryRows(ctx, r.db, query, args, func(row *templateSummaryRow) error {
+		summaries = append(summaries, row.toDomain())
+		return nil
+	})
 	if err != nil {
-		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to count templates")
+		return nil, err
 	}
 
+	return summaries[:paging.Fetched(len(summaries))], nil
+}
+
+// listQuery counts the templates matching filters into paging and builds the query of the page, selecting columns
+func (r *TemplatePostgresRepository) listQuery(ctx context.Context, columns string, filters domain.ListTemplateFilters, paging *listing.Paging) (string, []interface{}, error) {
+	filter := listTemplateFilter(filters)
+
 	// Set total in paging
-	paging.Total = total
+	err := pgquery.Count(ctx, r.db, "templates", filter, paging)
+	if err != nil {
+		return "", nil, syserr.Wrap(err, syserr.InternalCode, "failed to count templates")
+	}
 
 	// Main query
-	argCount++
-	limitArg := argCount
-	argCount++
-	offsetArg := argCount
-
+	pageClause, args := filter.Paged(paging)
 	query := fmt.Sprintf(`
-		SELECT id, name, slug, subject, content, type, status, variables, description, 
-		       created_by, created_at, updated_at
+		SELECT %s
 		FROM templates 
 		%s
 		ORDER BY created_at DESC
-		LIMIT $%d OFFSET $%d`, whereClause, limitArg, offsetArg)
+		%s`, columns, filter.Clause(), pageClause)
 
-	args = append(args, paging.Limit, paging.GetOffset())
+	return query, args, nil
+}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+// Stream calls fn for the summary of every template matching filters as rows are read from the cursor,
+// without counting or paging. It is bounded by the context of the caller rather than the query
+// timeout, since the cursor stays open for as long as fn takes to write the rows out.
+func (r *TemplatePostgresRepository) Stream(ctx context.Context, filters domain.ListTemplateFilters, fn func(summary *domain.TemplateSummary) error) error {
+	filter := listTemplateFilter(filters)
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM templates 
+		%s
+		ORDER BY created_at DESC`, templateSummaryColumns, filter.Clause())
+
+	return queryRows(ctx, r.db, query, filter.Args(), func(row *templateSummaryRow) error {
+		return fn(row.toDomain())
+	})
+}
+
+// queryRows runs query and calls fn for each row scanned into a new R, stopping at the first error
+func queryRows[R any](ctx context.Context, db *sqlx.DB, query string, args []interface{}, fn func(row *R) error) error {
+	rows, err := db.QueryxContext(ctx, query, args...)
 	if err != nil {
-		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list templates")
+		return syserr.Wrap(err, syserr.InternalCode, "failed to list templates")
 	}
 	defer rows.Close()
 
-	var templates []*domain.Template
 	for rows.Next() {
-		template := &domain.Template{}
-		err := rows.Scan(
-			&template.ID,
-			&template.Name,
-			&template.Slug,
-			&template.Subject,
-			&template.Content,
-			&template.Type,
-			&template.Status,
-			pq.Array(&template.Variables),
-			&template.Description,
-			&template.CreatedBy,
-			&template.CreatedAt,
-			&template.UpdatedAt,
-		)
-		if err != nil {
-			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan template")
+		row := new(R)
+		if err := rows.StructScan(row); err != nil {
+			return syserr.Wrap(err, syserr.InternalCode, "failed to scan template")
+		}
+		if err := fn(row); err != nil {
+			return err
 		}
-		templates = append(templates, template)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, syserr.Wrap(err, syserr.InternalCode, "error iterating template rows")
+		return syserr.Wrap(err, syserr.InternalCode, "error iterating template rows")
+	}
+
+	return nil
+}
+
+// listTemplateFilter builds the conditions of a template listing
+func listTemplateFilter(filters domain.ListTemplateFilters) *pgquery.Filter {
+	filter := &pgquery.Filter{}
+
+	if filters.Type != nil {
+		filter.Where("type = ?", *filters.Type)
+	}
+
+	if filters.Status != nil {
+		filter.Where("status = ?", *filters.Status)
 	}
 
-	return templates, nil
+	if filters.CreatedBy != nil {
+		filter.Where("created_by = ?", *filters.CreatedBy)
+	}
+
+	if filters.Search != "" {
+		search := "%" + filters.Search + "%"
+		filter.Where("(name ILIKE ? OR description ILIKE ? OR slug ILIKE ?)", search, search, search)
+	}
+
+	return filter
 }
 
-// Update updates an existing template
+// Update updates an existing template, counting a new content version when its content changes
 func (r *TemplatePostgresRepository) Update(ctx context.Context, template *domain.Template) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
 		UPDATE templates 
-		SET name = $2, subject = $3, content = $4, status = $5, variables = $6, 
-		    description = $7, updated_at = $8
-		WHERE id = $1`
+		SET name = :name, subject = :subject, content = :content, status = :status, variables = :variables, 
+		    description = :description, approved = :approved, updated_at = :updated_at,
+		    content_version = content_version + CASE
+		        WHEN (name, subject, content, variables, description) IS DISTINCT FROM
+		             (CAST(:name AS TEXT), CAST(:subject AS TEXT), CAST(:content AS TEXT), CAST(:variables AS TEXT[]), CAST(:description AS TEXT))
+		        THEN 1 ELSE 0 END
+		WHERE id = :id`
 
 	template.UpdatedAt = time.Now()
 
-	result, err := r.db.ExecContext(
-		ctx,
-		query,
+	result, err := r.db.NamedExecContext(ctx, query, newTemplateRow(template))
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to update template")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get rows affected")
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrTemplateNotFound
+	}
+
+	return nil
+}
+
+// ApplyRevision saves template, with revision applied, and the approval of revision in one transaction.
+// The approval is recorded first: ErrRevisionNotPending when the revision was reviewed meanwhile, then
+// ErrRevisionStale when the content of the template changed since the revision was written.
+func (r *TemplatePostgresRepository) ApplyRevision(ctx context.Context, template *domain.Template, revision *domain.TemplateRevision) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	if err := saveReview(ctx, tx, revision); err != nil {
+		return err
+	}
+
+	template.UpdatedAt = time.Now()
+	query := `
+		UPDATE templates
+		SET name = $2, subject = $3, content = $4, variables = $5, description = $6, approved = TRUE,
+		    content_version = content_version + 1, updated_at = $7
+		WHERE id = $1 AND content_version = $8`
+
+	result, err := tx.ExecContext(ctx, query,
 		template.ID,
 		template.Name,
 		template.Subject,
 		template.Content,
-		template.Status,
 		pq.Array(template.Variables),
 		template.Description,
 		template.UpdatedAt,
+		revision.BaseVersion,
 	)
-
 	if err != nil {
-		return syserr.Wrap(err, syserr.InternalCode, "failed to update template")
+		return syserr.Wrap(err, syserr.InternalCode, "failed to apply template revision")
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -257,8 +389,13 @@ func (r *TemplatePostgresRepository) Update(ctx context.Context, template *domai
 		return syserr.Wrap(err, syserr.InternalCode, "failed to get rows affected")
 	}
 
+	// the revision cascades with its template, so the template exists but was changed
 	if rowsAffected == 0 {
-		return domain.ErrTemplateNotFound
+		return domain.ErrRevisionStale
+	}
+
+	if err := tx.Commit(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to commit transaction")
 	}
 
 	return nil
@@ -266,6 +403,9 @@ func (r *TemplatePostgresRepository) Update(ctx context.Context, template *domai
 
 // Delete deletes a template by ID
 func (r *TemplatePostgresRepository) Delete(ctx context.Context, id int64) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
 	query := `DELETE FROM templates WHERE id = $1`
 
 	result, err := r.db.ExecContext(ctx, query, id)
@@ -0,0 +1,85 @@
+package adapters
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"tixgo/modules/template/domain"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/reflectx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateRow_RoundTrip(t *testing.T) {
+	template := &domain.Template{
+		ID:             7,
+		Name:           "Welcome",
+		Slug:           "welcome",
+		Subject:        "Welcome {{.name}}",
+		Content:        "<p>Hello {{.name}}</p>",
+		Type:           domain.TemplateTypeEmail,
+		Status:         domain.TemplateStatusActive,
+		Variables:      []string{"name", "event"},
+		Description:    "Sent after registration",
+		Approved:       false,
+		CreatedBy:      3,
+		ContentVersion: 4,
+		CreatedAt:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt:      time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	assert.Equal(t, template, newTemplateRow(template).toDomain())
+}
+
+func TestTemplateRow_Variables(t *testing.T) {
+	row := newTemplateRow(&domain.Template{Variables: []string{"name", "has,comma"}})
+
+	value, err := row.Variables.Value()
+	assert.NoError(t, err)
+
+	scanned := &templateRow{}
+	assert.NoError(t, scanned.Variables.Scan([]byte(value.(string))))
+	assert.Equal(t, []string{"name", "has,comma"}, scanned.toDomain().Variables)
+}
+
+func TestTemplateRow_Columns(t *testing.T) {
+	assertColumns(t, templateColumns, templateRow{})
+}
+
+func TestTemplateSummaryRow_Columns(t *testing.T) {
+	assertColumns(t, templateSummaryColumns, templateSummaryRow{})
+	assert.NotContains(t, templateSummaryColumns, "content")
+}
+
+// assertColumns checks that every selected column lands in a field of row and every field is selected,
+// a new column otherwise fails to scan or silently stays zero
+func assertColumns(t *testing.T, selected string, row interface{}) {
+	t.Helper()
+
+	fields := reflectx.NewMapperFunc("db", sqlx.NameMapper).TypeMap(reflect.TypeOf(row)).Names
+
+	var columns []string
+	for _, column := range strings.Split(selected, ",") {
+		column = strings.TrimSpace(column)
+		columns = append(columns, column)
+		assert.Contains(t, fields, column)
+	}
+	assert.Len(t, fields, len(columns))
+}
+
+func TestListTemplateFilter(t *testing.T) {
+	templateType := domain.TemplateTypeEmail
+	createdBy := int64(3)
+
+	filter := listTemplateFilter(domain.ListTemplateFilters{
+		Type:      &templateType,
+		CreatedBy: &createdBy,
+		Search:    "welcome",
+	})
+
+	assert.Equal(t, "WHERE type = $1 AND created_by = $2 AND (name ILIKE $3 OR description ILIKE $4 OR slug ILIKE $5)", filter.Clause())
+	assert.Equal(t, []interface{}{templateType, createdBy, "%welcome%", "%welcome%", "%welcome%"}, filter.Args())
+}
@@ -0,0 +1,213 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"tixgo/modules/template/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/listing"
+	"tixgo/shared/pgquery"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// TemplateRevisionPostgresRepository implements the TemplateRevisionRepository interface using PostgreSQL
+type TemplateRevisionPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewTemplateRevisionPostgresRepository creates a new PostgreSQL template revision repository
+func NewTemplateRevisionPostgresRepository(db *sqlx.DB) *TemplateRevisionPostgresRepository {
+	return &TemplateRevisionPostgresRepository{db: db}
+}
+
+const selectTemplateRevision = `
+	SELECT r.id, r.template_id, t.slug, r.name, COALESCE(r.subject, ''), r.content, r.variables,
+	       COALESCE(r.description, ''), r.base_version, r.status, r.submitted_by, r.reviewed_by,
+	       COALESCE(r.review_comment, ''), r.created_at, r.reviewed_at
+	FROM template_revisions r
+	JOIN templates t ON t.id = r.template_id`
+
+// Submit stores a revision pending review, superseding the pending revision of its template if any
+func (r *TemplateRevisionPostgresRepository) Submit(ctx context.Context, revision *domain.TemplateRevision) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE template_revisions
+		SET status = 'superseded'
+		WHERE template_id = $1 AND status = 'pending_review'`, revision.TemplateID)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to supersede pending revision")
+	}
+
+	query := `
+		INSERT INTO template_revisions (template_id, name, subject, content, variables, description, base_version, status, submitted_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id`
+
+	err = tx.QueryRowContext(ctx, query,
+		revision.TemplateID,
+		revision.Name,
+		revision.Subject,
+		revision.Content,
+		pq.Array(revision.Variables),
+		revision.Description,
+		revision.BaseVersion,
+		revision.Status,
+		revision.SubmittedBy,
+		revision.CreatedAt,
+	).Scan(&revision.ID)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create template revision")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// GetByID retrieves a revision by ID
+func (r *TemplateRevisionPostgresRepository) GetByID(ctx context.Context, id int64) (*domain.TemplateRevision, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	revision, err := scanTemplateRevision(r.db.QueryRowContext(ctx, selectTemplateRevision+` WHERE r.id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrRevisionNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get template revision")
+	}
+
+	return revision, nil
+}
+
+// List retrieves revisions with pagination and filters, most recent first
+func (r *TemplateRevisionPostgresRepository) List(ctx context.Context, filters domain.ListRevisionFilters, paging *listing.Paging) ([]*domain.TemplateRevision, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	filter := &pgquery.Filter{}
+
+	if filters.TemplateID != nil {
+		filter.Where("r.template_id = ?", *filters.TemplateID)
+	}
+
+	if filters.Status != nil {
+		filter.Where("r.status = ?", *filters.Status)
+	}
+
+	// Set total in paging
+	err := pgquery.Count(ctx, r.db, "template_revisions r", filter, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to count template revisions")
+	}
+
+	pageClause, args := filter.Paged(paging)
+	query := fmt.Sprintf(`%s
+		%s
+		ORDER BY r.created_at DESC, r.id DESC
+		%s`, selectTemplateRevision, filter.Clause(), pageClause)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list template revisions")
+	}
+	defer rows.Close()
+
+	var revisions []*domain.TemplateRevision
+	for rows.Next() {
+		revision, err := scanTemplateRevision(rows)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan template revision")
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "error iterating template revision rows")
+	}
+
+	return revisions[:paging.Fetched(len(revisions))], nil
+}
+
+// SaveReview records the review of a revision still pending, ErrRevisionNotPending otherwise
+func (r *TemplateRevisionPostgresRepository) SaveReview(ctx context.Context, revision *domain.TemplateRevision) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return saveReview(ctx, r.db, revision)
+}
+
+// saveReview records the review of a revision still pending with db, also run in the transaction
+// applying an approved revision to its template
+func saveReview(ctx context.Context, db sqlx.ExecerContext, revision *domain.TemplateRevision) error {
+	query := `
+		UPDATE template_revisions
+		SET status = $2, reviewed_by = $3, review_comment = NULLIF($4, ''), reviewed_at = $5
+		WHERE id = $1 AND status = 'pending_review'`
+
+	result, err := db.ExecContext(ctx, query,
+		revision.ID,
+		revision.Status,
+		revision.ReviewedBy,
+		revision.ReviewComment,
+		revision.ReviewedAt,
+	)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to save template revision review")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get rows affected")
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrRevisionNotPending
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTemplateRevision(row rowScanner) (*domain.TemplateRevision, error) {
+	revision := &domain.TemplateRevision{}
+	err := row.Scan(
+		&revision.ID,
+		&revision.TemplateID,
+		&revision.TemplateSlug,
+		&revision.Name,
+		&revision.Subject,
+		&revision.Content,
+		pq.Array(&revision.Variables),
+		&revision.Description,
+		&revision.BaseVersion,
+		&revision.Status,
+		&revision.SubmittedBy,
+		&revision.ReviewedBy,
+		&revision.ReviewComment,
+		&revision.CreatedAt,
+		&revision.ReviewedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
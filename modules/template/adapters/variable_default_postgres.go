@@ -0,0 +1,147 @@
+package adapters
+
+import (
+	"context"
+
+	"tixgo/modules/template/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/pgerr"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+// VariableDefaultPostgresRepository implements the VariableDefaultRepository interface using PostgreSQL
+type VariableDefaultPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewVariableDefaultPostgresRepository creates a new PostgreSQL default variable repository
+func NewVariableDefaultPostgresRepository(db *sqlx.DB) *VariableDefaultPostgresRepository {
+	return &VariableDefaultPostgresRepository{db: db}
+}
+
+// Save creates or replaces the override of a variable for its organizer or the platform
+func (r *VariableDefaultPostgresRepository) Save(ctx context.Context, variableDefault *domain.VariableDefault) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		INSERT INTO template_variable_defaults (organizer_id, name, value, updated_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (COALESCE(organizer_id, 0), name) DO UPDATE
+		SET value = EXCLUDED.value,
+		    updated_by = EXCLUDED.updated_by,
+		    updated_at = NOW()
+		RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		variableDefault.OrganizerID,
+		variableDefault.Name,
+		variableDefault.Value,
+		variableDefault.UpdatedBy,
+	).Scan(&variableDefault.ID, &variableDefault.CreatedAt, &variableDefault.UpdatedAt)
+	if err != nil {
+		if pgerr.IsForeignKeyViolation(err) {
+			return domain.ErrOrganizerNotFound
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to save default variable")
+	}
+
+	return nil
+}
+
+// List retrieves the overrides of the organizer, of the platform when nil, by name
+func (r *VariableDefaultPostgresRepository) List(ctx context.Context, organizerID *int64) ([]*domain.VariableDefault, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, organizer_id, name, value, COALESCE(updated_by, 0), created_at, updated_at
+		FROM template_variable_defaults
+		WHERE organizer_id IS NOT DISTINCT FROM $1
+		ORDER BY name`, organizerID)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list default variables")
+	}
+	defer rows.Close()
+
+	variableDefaults := []*domain.VariableDefault{}
+	for rows.Next() {
+		variableDefault := &domain.VariableDefault{}
+		err := rows.Scan(
+			&variableDefault.ID,
+			&variableDefault.OrganizerID,
+			&variableDefault.Name,
+			&variableDefault.Value,
+			&variableDefault.UpdatedBy,
+			&variableDefault.CreatedAt,
+			&variableDefault.UpdatedAt,
+		)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan default variable")
+		}
+		variableDefaults = append(variableDefaults, variableDefault)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "error iterating default variable rows")
+	}
+
+	return variableDefaults, nil
+}
+
+// Delete deletes the override of a variable
+func (r *VariableDefaultPostgresRepository) Delete(ctx context.Context, organizerID *int64, name string) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM template_variable_defaults
+		WHERE organizer_id IS NOT DISTINCT FROM $1 AND name = $2`, organizerID, name)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to delete default variable")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return domain.ErrVariableDefaultNotFound
+	}
+
+	return nil
+}
+
+// ResolveDefaults returns the overrides of the platform merged with those of the organizer, the
+// organizer ones coming last to win
+func (r *VariableDefaultPostgresRepository) ResolveDefaults(ctx context.Context, organizerID int64) (map[string]string, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT name, value
+		FROM template_variable_defaults
+		WHERE organizer_id IS NULL OR organizer_id = $1
+		ORDER BY organizer_id NULLS FIRST`, organizerID)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to resolve default variables")
+	}
+	defer rows.Close()
+
+	defaults := make(map[string]string)
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan default variable")
+		}
+		defaults[name] = value
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "error iterating default variable rows")
+	}
+
+	return defaults, nil
+}
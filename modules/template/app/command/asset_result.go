@@ -0,0 +1,27 @@
+package command
+
+import "tixgo/shared/assets"
+
+// AssetResult represents a hosted image and the URL templates get for it
+type AssetResult struct {
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	UploadedBy  int64  `json:"uploaded_by"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+// ToAssetResult converts an asset to its result, url being the URL of its current file
+func ToAssetResult(asset *assets.Asset, url string) *AssetResult {
+	return &AssetResult{
+		Name:        asset.Name,
+		URL:         url,
+		ContentType: asset.ContentType,
+		Size:        asset.Size,
+		UploadedBy:  asset.UploadedBy,
+		CreatedAt:   asset.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:   asset.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"tixgo/modules/template/domain"
+	"tixgo/shared/dryrun"
 
 	"github.com/duongptryu/gox/syserr"
 )
@@ -18,6 +19,8 @@ type CreateTemplateCommand struct {
 	Variables   []string `json:"variables"`
 	Description string   `json:"description"`
 	CreatedBy   int64    `json:"-"`
+	// IsAdmin creators skip the review, the templates of others need an approved revision to be activated
+	IsAdmin bool `json:"-"`
 }
 
 // CreateTemplateResult represents the result of template creation
@@ -30,43 +33,55 @@ type CreateTemplateResult struct {
 	Status      domain.TemplateStatus `json:"status"`
 	Variables   []string              `json:"variables"`
 	Description string                `json:"description"`
+	Approved    bool                  `json:"approved"`
 	CreatedAt   string                `json:"created_at"`
+	// PendingRevisionID is the revision submitted for review when the creator is not an admin
+	PendingRevisionID *int64 `json:"pending_revision_id,omitempty"`
 }
 
 // CreateTemplateHandler handles template creation
 type CreateTemplateHandler struct {
 	templateRepo     domain.TemplateRepository
+	revisionRepo     domain.TemplateRevisionRepository
 	templateRenderer domain.TemplateRenderer
 }
 
 // NewCreateTemplateHandler creates a new create template handler
-func NewCreateTemplateHandler(templateRepo domain.TemplateRepository, templateRenderer domain.TemplateRenderer) *CreateTemplateHandler {
+func NewCreateTemplateHandler(templateRepo domain.TemplateRepository, revisionRepo domain.TemplateRevisionRepository, templateRenderer domain.TemplateRenderer) *CreateTemplateHandler {
 	return &CreateTemplateHandler{
 		templateRepo:     templateRepo,
+		revisionRepo:     revisionRepo,
 		templateRenderer: templateRenderer,
 	}
 }
 
-// Handle executes the create template command
-func (h *CreateTemplateHandler) Handle(ctx context.Context, cmd CreateTemplateCommand) error {
+// Handle executes the create template command. A dry run validates the template without saving it.
+func (h *CreateTemplateHandler) Handle(ctx context.Context, cmd CreateTemplateCommand) (*CreateTemplateResult, error) {
 	// Validate template type
 	if !domain.IsValidTemplateType(cmd.Type) {
-		return domain.ErrInvalidTemplateType
+		return nil, domain.ErrInvalidTemplateType
 	}
 
 	// Check if template with slug already exists
 	existingTemplate, err := h.templateRepo.GetBySlug(ctx, cmd.Slug)
 	if err != nil && err != domain.ErrTemplateNotFound {
-		return syserr.Wrap(err, syserr.InternalCode, "failed to check existing template")
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to check existing template")
 	}
 	if existingTemplate != nil {
-		return domain.ErrTemplateAlreadyExists
+		return nil, domain.ErrTemplateAlreadyExists
 	}
 
 	// Validate template syntax
 	err = h.templateRenderer.ValidateTemplate(ctx, cmd.Content)
 	if err != nil {
-		return syserr.Wrap(err, syserr.InvalidArgumentCode, "template syntax validation failed")
+		return nil, syserr.Wrap(err, syserr.InvalidArgumentCode, "template syntax validation failed")
+	}
+
+	// Admins are trusted with any markup, the content of others must pass the sanitization policy
+	if !cmd.IsAdmin {
+		if err := domain.CheckContent(domain.TemplateType(cmd.Type), cmd.Content); err != nil {
+			return nil, err
+		}
 	}
 
 	// Create new template
@@ -81,14 +96,46 @@ func (h *CreateTemplateHandler) Handle(ctx context.Context, cmd CreateTemplateCo
 		cmd.CreatedBy,
 	)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	template.Approved = cmd.IsAdmin
+
+	if dryrun.IsDryRun(ctx) {
+		return toCreateTemplateResult(template, nil), nil
 	}
 
 	// Save template
 	err = h.templateRepo.Create(ctx, template)
 	if err != nil {
-		return syserr.Wrap(err, syserr.InternalCode, "failed to create template")
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to create template")
+	}
+
+	if template.Approved {
+		return toCreateTemplateResult(template, nil), nil
+	}
+
+	// Submit the content for review, the template stays a draft until it is approved
+	revision := domain.NewTemplateRevision(template, cmd.CreatedBy)
+	err = h.revisionRepo.Submit(ctx, revision)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to submit template revision")
 	}
 
-	return nil
+	return toCreateTemplateResult(template, &revision.ID), nil
+}
+
+func toCreateTemplateResult(template *domain.Template, pendingRevisionID *int64) *CreateTemplateResult {
+	return &CreateTemplateResult{
+		ID:                template.ID,
+		Name:              template.Name,
+		Slug:              template.Slug,
+		Subject:           template.Subject,
+		Type:              template.Type,
+		Status:            template.Status,
+		Variables:         template.Variables,
+		Description:       template.Description,
+		Approved:          template.Approved,
+		CreatedAt:         template.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		PendingRevisionID: pendingRevisionID,
+	}
 }
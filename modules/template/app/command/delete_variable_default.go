@@ -0,0 +1,42 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/template/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// DeleteVariableDefaultCommand represents the command of an admin removing the override of a default
+// variable, of an organizer or of the platform when OrganizerID is nil
+type DeleteVariableDefaultCommand struct {
+	Name        string `json:"-"`
+	OrganizerID *int64 `form:"organizer_id" binding:"omitempty,min=1"`
+}
+
+// DeleteVariableDefaultHandler handles deleting the overrides of default variables
+type DeleteVariableDefaultHandler struct {
+	variableDefaultRepo domain.VariableDefaultRepository
+}
+
+// NewDeleteVariableDefaultHandler creates a new delete variable default handler
+func NewDeleteVariableDefaultHandler(variableDefaultRepo domain.VariableDefaultRepository) *DeleteVariableDefaultHandler {
+	return &DeleteVariableDefaultHandler{
+		variableDefaultRepo: variableDefaultRepo,
+	}
+}
+
+// Handle executes the delete variable default command. The variable goes back to the value of the
+// platform, or of the configuration.
+func (h *DeleteVariableDefaultHandler) Handle(ctx context.Context, cmd DeleteVariableDefaultCommand) error {
+	err := h.variableDefaultRepo.Delete(ctx, cmd.OrganizerID, cmd.Name)
+	if err != nil {
+		if err == domain.ErrVariableDefaultNotFound {
+			return domain.ErrVariableDefaultNotFound
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to delete default variable")
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package command
+
+import (
+	"context"
+	"strconv"
+
+	"tixgo/modules/template/domain"
+	sharedKafka "tixgo/shared/kafka"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ReviewTemplateRevisionCommand represents the command of an admin approving or rejecting a revision
+type ReviewTemplateRevisionCommand struct {
+	RevisionID int64  `json:"-"`
+	ReviewerID int64  `json:"-"`
+	Approve    bool   `json:"-"`
+	Comment    string `json:"comment"`
+}
+
+// ReviewTemplateRevisionHandler handles template revision reviews
+type ReviewTemplateRevisionHandler struct {
+	templateRepo domain.TemplateRepository
+	revisionRepo domain.TemplateRevisionRepository
+	eventBus     messaging.EventBus
+}
+
+// NewReviewTemplateRevisionHandler creates a new review template revision handler
+func NewReviewTemplateRevisionHandler(templateRepo domain.TemplateRepository, revisionRepo domain.TemplateRevisionRepository, eventBus messaging.EventBus) *ReviewTemplateRevisionHandler {
+	return &ReviewTemplateRevisionHandler{
+		templateRepo: templateRepo,
+		revisionRepo: revisionRepo,
+		eventBus:     eventBus,
+	}
+}
+
+// Handle executes the review. An approved revision replaces the content of its template, which keeps
+// its status, unless the content changed since the revision was written; the author is told about the
+// decision by mail.
+func (h *ReviewTemplateRevisionHandler) Handle(ctx context.Context, cmd ReviewTemplateRevisionCommand) (*TemplateRevisionResult, error) {
+	revision, err := h.revisionRepo.GetByID(ctx, cmd.RevisionID)
+	if err != nil {
+		if err == domain.ErrRevisionNotFound {
+			return nil, domain.ErrRevisionNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get template revision")
+	}
+
+	if cmd.Approve {
+		err = revision.Approve(cmd.ReviewerID, cmd.Comment)
+	} else {
+		err = revision.Reject(cmd.ReviewerID, cmd.Comment)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if revision.Status == domain.RevisionStatusApproved {
+		err = h.approve(ctx, revision)
+	} else {
+		err = h.revisionRepo.SaveReview(ctx, revision)
+	}
+	if err != nil {
+		if err == domain.ErrRevisionNotPending || err == domain.ErrRevisionStale || err == domain.ErrTemplateNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to save template revision review")
+	}
+
+	// The review is saved, a failure only costs the author their mail so it is logged
+	key := strconv.FormatInt(revision.SubmittedBy, 10)
+	err = h.eventBus.PublishEvent(sharedKafka.WithPartitionKey(ctx, key), domain.NewEventTemplateReviewed(revision))
+	if err != nil {
+		logger.Error(ctx, "Failed to publish template review", logger.F("revision_id", revision.ID), logger.F("error", err))
+	}
+
+	return ToTemplateRevisionResult(revision), nil
+}
+
+// approve applies the approved revision to its template, saved together with the approval
+func (h *ReviewTemplateRevisionHandler) approve(ctx context.Context, revision *domain.TemplateRevision) error {
+	template, err := h.templateRepo.GetByID(ctx, revision.TemplateID)
+	if err != nil {
+		if err == domain.ErrTemplateNotFound {
+			return domain.ErrTemplateNotFound
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get template")
+	}
+
+	if err := template.ApplyRevision(revision); err != nil {
+		return err
+	}
+	return h.templateRepo.ApplyRevision(ctx, template, revision)
+}
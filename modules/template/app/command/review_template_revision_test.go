@@ -0,0 +1,161 @@
+package command
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"tixgo/modules/template/domain"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init(&logger.Config{Output: io.Discard})
+	os.Exit(m.Run())
+}
+
+// memoryTemplateRepository holds one template and records how it is saved
+type memoryTemplateRepository struct {
+	domain.TemplateRepository
+	template *domain.Template
+	// applyErr is returned by ApplyRevision, as when the revision was reviewed meanwhile
+	applyErr error
+	updated  *domain.Template
+	applied  *domain.TemplateRevision
+}
+
+func (r *memoryTemplateRepository) GetByID(_ context.Context, id int64) (*domain.Template, error) {
+	if r.template == nil || r.template.ID != id {
+		return nil, domain.ErrTemplateNotFound
+	}
+	copied := *r.template
+	return &copied, nil
+}
+
+func (r *memoryTemplateRepository) Update(_ context.Context, template *domain.Template) error {
+	r.updated = template
+	return nil
+}
+
+func (r *memoryTemplateRepository) ApplyRevision(_ context.Context, template *domain.Template, revision *domain.TemplateRevision) error {
+	if r.applyErr != nil {
+		return r.applyErr
+	}
+	r.updated, r.applied = template, revision
+	return nil
+}
+
+// memoryRevisionRepository holds one revision and records the reviews and submissions saved
+type memoryRevisionRepository struct {
+	domain.TemplateRevisionRepository
+	revision  *domain.TemplateRevision
+	reviewed  *domain.TemplateRevision
+	submitted *domain.TemplateRevision
+}
+
+func (r *memoryRevisionRepository) GetByID(_ context.Context, id int64) (*domain.TemplateRevision, error) {
+	if r.revision == nil || r.revision.ID != id {
+		return nil, domain.ErrRevisionNotFound
+	}
+	copied := *r.revision
+	return &copied, nil
+}
+
+func (r *memoryRevisionRepository) SaveReview(_ context.Context, revision *domain.TemplateRevision) error {
+	r.reviewed = revision
+	return nil
+}
+
+func (r *memoryRevisionRepository) Submit(_ context.Context, revision *domain.TemplateRevision) error {
+	revision.ID = 11
+	r.submitted = revision
+	return nil
+}
+
+// recordingBus keeps the events it is asked to publish
+type recordingBus struct {
+	published []any
+}
+
+func (b *recordingBus) PublishEvent(_ context.Context, event any) error {
+	b.published = append(b.published, event)
+	return nil
+}
+
+// newReviewFixture returns a template at content version 2 and a revision of it pending review,
+// written against baseVersion
+func newReviewFixture(baseVersion int) (*memoryTemplateRepository, *memoryRevisionRepository) {
+	template := &domain.Template{ID: 1, Slug: "welcome", Name: "Welcome", Content: "<p>Hi</p>", Status: domain.TemplateStatusActive, ContentVersion: 2}
+	proposed := *template
+	proposed.Update("", "", "<p>Hello</p>", "", nil)
+	revision := domain.NewTemplateRevision(&proposed, 9)
+	revision.ID = 5
+	revision.BaseVersion = baseVersion
+
+	return &memoryTemplateRepository{template: template}, &memoryRevisionRepository{revision: revision}
+}
+
+func TestReviewTemplateRevisionHandler_Approve(t *testing.T) {
+	templates, revisions := newReviewFixture(2)
+	bus := &recordingBus{}
+	handler := NewReviewTemplateRevisionHandler(templates, revisions, bus)
+
+	result, err := handler.Handle(context.Background(), ReviewTemplateRevisionCommand{RevisionID: 5, ReviewerID: 1, Approve: true})
+	require.NoError(t, err)
+	assert.Equal(t, domain.RevisionStatusApproved, result.Status)
+
+	require.NotNil(t, templates.applied, "the approval is saved with the content")
+	assert.Equal(t, domain.RevisionStatusApproved, templates.applied.Status)
+	assert.Equal(t, "<p>Hello</p>", templates.updated.Content)
+	assert.Equal(t, domain.TemplateStatusActive, templates.updated.Status)
+	assert.Equal(t, 3, templates.updated.ContentVersion)
+	assert.Nil(t, revisions.reviewed, "the approval is not saved apart from the content")
+	assert.Len(t, bus.published, 1)
+}
+
+func TestReviewTemplateRevisionHandler_ApproveStale(t *testing.T) {
+	templates, revisions := newReviewFixture(1)
+	bus := &recordingBus{}
+	handler := NewReviewTemplateRevisionHandler(templates, revisions, bus)
+
+	_, err := handler.Handle(context.Background(), ReviewTemplateRevisionCommand{RevisionID: 5, ReviewerID: 1, Approve: true})
+	assert.ErrorIs(t, err, domain.ErrRevisionStale)
+	assert.Nil(t, templates.updated)
+	assert.Nil(t, templates.applied)
+	assert.Nil(t, revisions.reviewed)
+	assert.Empty(t, bus.published)
+
+	// a stale revision can still be rejected
+	result, err := handler.Handle(context.Background(), ReviewTemplateRevisionCommand{RevisionID: 5, ReviewerID: 1, Comment: "rebase on the current content"})
+	require.NoError(t, err)
+	assert.Equal(t, domain.RevisionStatusRejected, result.Status)
+	assert.NotNil(t, revisions.reviewed)
+}
+
+func TestReviewTemplateRevisionHandler_ApproveReviewedMeanwhile(t *testing.T) {
+	templates, revisions := newReviewFixture(2)
+	templates.applyErr = domain.ErrRevisionNotPending
+	bus := &recordingBus{}
+	handler := NewReviewTemplateRevisionHandler(templates, revisions, bus)
+
+	_, err := handler.Handle(context.Background(), ReviewTemplateRevisionCommand{RevisionID: 5, ReviewerID: 1, Approve: true})
+	assert.ErrorIs(t, err, domain.ErrRevisionNotPending)
+	assert.Empty(t, bus.published)
+}
+
+func TestReviewTemplateRevisionHandler_Reject(t *testing.T) {
+	templates, revisions := newReviewFixture(2)
+	handler := NewReviewTemplateRevisionHandler(templates, revisions, &recordingBus{})
+
+	_, err := handler.Handle(context.Background(), ReviewTemplateRevisionCommand{RevisionID: 5, ReviewerID: 1})
+	assert.ErrorIs(t, err, domain.ErrReviewCommentRequired)
+
+	_, err = handler.Handle(context.Background(), ReviewTemplateRevisionCommand{RevisionID: 5, ReviewerID: 1, Comment: "typo"})
+	require.NoError(t, err)
+	assert.Equal(t, domain.RevisionStatusRejected, revisions.reviewed.Status)
+	assert.Nil(t, templates.updated, "a rejection leaves the template as is")
+}
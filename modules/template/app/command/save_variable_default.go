@@ -0,0 +1,47 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/template/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// SaveVariableDefaultCommand represents the command of an admin overriding the default value of a
+// template variable, for the renders of an organizer or for every render when OrganizerID is nil
+type SaveVariableDefaultCommand struct {
+	Name        string `json:"-"`
+	Value       string `json:"value" binding:"max=2000"`
+	OrganizerID *int64 `json:"organizer_id" binding:"omitempty,min=1"`
+	AdminID     int64  `json:"-"`
+}
+
+// SaveVariableDefaultHandler handles saving the overrides of default variables
+type SaveVariableDefaultHandler struct {
+	variableDefaultRepo domain.VariableDefaultRepository
+}
+
+// NewSaveVariableDefaultHandler creates a new save variable default handler
+func NewSaveVariableDefaultHandler(variableDefaultRepo domain.VariableDefaultRepository) *SaveVariableDefaultHandler {
+	return &SaveVariableDefaultHandler{
+		variableDefaultRepo: variableDefaultRepo,
+	}
+}
+
+// Handle executes the save variable default command. The override takes effect with the next render.
+func (h *SaveVariableDefaultHandler) Handle(ctx context.Context, cmd SaveVariableDefaultCommand) (*VariableDefaultResult, error) {
+	variableDefault, err := domain.NewVariableDefault(cmd.OrganizerID, cmd.Name, cmd.Value, cmd.AdminID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.variableDefaultRepo.Save(ctx, variableDefault); err != nil {
+		if err == domain.ErrOrganizerNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to save default variable")
+	}
+
+	return ToVariableDefaultResult(variableDefault), nil
+}
@@ -0,0 +1,45 @@
+package command
+
+import "tixgo/modules/template/domain"
+
+// TemplateRevisionResult represents a template revision and its review
+type TemplateRevisionResult struct {
+	ID            int64                 `json:"id"`
+	TemplateID    int64                 `json:"template_id"`
+	TemplateSlug  string                `json:"template_slug"`
+	Name          string                `json:"name"`
+	Subject       string                `json:"subject"`
+	Content       string                `json:"content"`
+	Variables     []string              `json:"variables"`
+	Description   string                `json:"description"`
+	Status        domain.RevisionStatus `json:"status"`
+	SubmittedBy   int64                 `json:"submitted_by"`
+	ReviewedBy    *int64                `json:"reviewed_by"`
+	ReviewComment string                `json:"review_comment,omitempty"`
+	CreatedAt     string                `json:"created_at"`
+	ReviewedAt    *string               `json:"reviewed_at"`
+}
+
+// ToTemplateRevisionResult converts a revision to its result
+func ToTemplateRevisionResult(revision *domain.TemplateRevision) *TemplateRevisionResult {
+	result := &TemplateRevisionResult{
+		ID:            revision.ID,
+		TemplateID:    revision.TemplateID,
+		TemplateSlug:  revision.TemplateSlug,
+		Name:          revision.Name,
+		Subject:       revision.Subject,
+		Content:       revision.Content,
+		Variables:     revision.Variables,
+		Description:   revision.Description,
+		Status:        revision.Status,
+		SubmittedBy:   revision.SubmittedBy,
+		ReviewedBy:    revision.ReviewedBy,
+		ReviewComment: revision.ReviewComment,
+		CreatedAt:     revision.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if revision.ReviewedAt != nil {
+		reviewedAt := revision.ReviewedAt.Format("2006-01-02T15:04:05Z")
+		result.ReviewedAt = &reviewedAt
+	}
+	return result
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"tixgo/modules/template/domain"
+	"tixgo/shared/dryrun"
 
 	"github.com/duongptryu/gox/syserr"
 )
@@ -17,6 +18,9 @@ type UpdateTemplateCommand struct {
 	Variables   []string `json:"variables"`
 	Description string   `json:"description"`
 	Status      string   `json:"status"`
+	UpdatedBy   int64    `json:"-"`
+	// IsAdmin editors change the template directly, the content edits of others are submitted for review
+	IsAdmin bool `json:"-"`
 }
 
 // UpdateTemplateResult represents the result of template update
@@ -29,64 +33,127 @@ type UpdateTemplateResult struct {
 	Status      domain.TemplateStatus `json:"status"`
 	Variables   []string              `json:"variables"`
 	Description string                `json:"description"`
+	Approved    bool                  `json:"approved"`
 	UpdatedAt   string                `json:"updated_at"`
+	// PendingRevisionID is the revision submitted for review when the editor is not an admin
+	PendingRevisionID *int64 `json:"pending_revision_id,omitempty"`
 }
 
 // UpdateTemplateHandler handles template updates
 type UpdateTemplateHandler struct {
 	templateRepo     domain.TemplateRepository
+	revisionRepo     domain.TemplateRevisionRepository
 	templateRenderer domain.TemplateRenderer
 }
 
 // NewUpdateTemplateHandler creates a new update template handler
-func NewUpdateTemplateHandler(templateRepo domain.TemplateRepository, templateRenderer domain.TemplateRenderer) *UpdateTemplateHandler {
+func NewUpdateTemplateHandler(templateRepo domain.TemplateRepository, revisionRepo domain.TemplateRevisionRepository, templateRenderer domain.TemplateRenderer) *UpdateTemplateHandler {
 	return &UpdateTemplateHandler{
 		templateRepo:     templateRepo,
+		revisionRepo:     revisionRepo,
 		templateRenderer: templateRenderer,
 	}
 }
 
-// Handle executes the update template command
-func (h *UpdateTemplateHandler) Handle(ctx context.Context, cmd UpdateTemplateCommand) error {
+// Handle executes the update template command. Content edits by non-admins leave the template as is and
+// are submitted as a revision pending review instead, without a status change. A dry run validates the change without saving it.
+func (h *UpdateTemplateHandler) Handle(ctx context.Context, cmd UpdateTemplateCommand) (*UpdateTemplateResult, error) {
 	// Get existing template
 	template, err := h.templateRepo.GetByID(ctx, cmd.ID)
 	if err != nil {
 		if err == domain.ErrTemplateNotFound {
-			return domain.ErrTemplateNotFound
+			return nil, domain.ErrTemplateNotFound
 		}
-		return syserr.Wrap(err, syserr.InternalCode, "failed to get template")
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get template")
 	}
 
 	// Validate template content if provided
 	if cmd.Content != "" {
 		err = h.templateRenderer.ValidateTemplate(ctx, cmd.Content)
 		if err != nil {
-			return syserr.Wrap(err, syserr.InvalidArgumentCode, "template syntax validation failed")
+			return nil, syserr.Wrap(err, syserr.InvalidArgumentCode, "template syntax validation failed")
+		}
+
+		// Admins are trusted with any markup, the content of others must pass the sanitization policy
+		if !cmd.IsAdmin {
+			if err := domain.CheckContent(template.Type, cmd.Content); err != nil {
+				return nil, err
+			}
 		}
 	}
 
-	// Update template
-	template.Update(cmd.Name, cmd.Subject, cmd.Content, cmd.Description, cmd.Variables)
+	// Update template, or the revision submitted in its place
+	var revision *domain.TemplateRevision
+	if cmd.hasContentChanges() {
+		// The status of the template would change at once while its content waits for the review
+		if !cmd.IsAdmin && cmd.Status != "" {
+			return nil, domain.ErrStatusChangeInRevision
+		}
+
+		if cmd.IsAdmin {
+			template.Update(cmd.Name, cmd.Subject, cmd.Content, cmd.Description, cmd.Variables)
+			template.Approved = true
+		} else {
+			proposed := *template
+			proposed.Update(cmd.Name, cmd.Subject, cmd.Content, cmd.Description, cmd.Variables)
+			revision = domain.NewTemplateRevision(&proposed, cmd.UpdatedBy)
+		}
+	}
 
 	// Update status if provided
 	if cmd.Status != "" {
 		switch domain.TemplateStatus(cmd.Status) {
 		case domain.TemplateStatusActive:
-			template.Activate()
+			if err := template.Activate(); err != nil {
+				return nil, err
+			}
 		case domain.TemplateStatusInactive:
 			template.Deactivate()
 		case domain.TemplateStatusDraft:
 			template.Status = domain.TemplateStatusDraft
 		default:
-			return domain.ErrInvalidTemplateStatus
+			return nil, domain.ErrInvalidTemplateStatus
 		}
 	}
 
+	if dryrun.IsDryRun(ctx) {
+		return toUpdateTemplateResult(template, nil), nil
+	}
+
 	// Save updated template
-	err = h.templateRepo.Update(ctx, template)
+	if revision == nil {
+		err = h.templateRepo.Update(ctx, template)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to update template")
+		}
+		return toUpdateTemplateResult(template, nil), nil
+	}
+
+	err = h.revisionRepo.Submit(ctx, revision)
 	if err != nil {
-		return syserr.Wrap(err, syserr.InternalCode, "failed to update template")
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to submit template revision")
 	}
 
-	return nil
+	return toUpdateTemplateResult(template, &revision.ID), nil
+}
+
+// hasContentChanges checks if the command edits the content of the template rather than its status only
+func (cmd UpdateTemplateCommand) hasContentChanges() bool {
+	return cmd.Name != "" || cmd.Subject != "" || cmd.Content != "" || cmd.Description != "" || cmd.Variables != nil
+}
+
+func toUpdateTemplateResult(template *domain.Template, pendingRevisionID *int64) *UpdateTemplateResult {
+	return &UpdateTemplateResult{
+		ID:                template.ID,
+		Name:              template.Name,
+		Slug:              template.Slug,
+		Subject:           template.Subject,
+		Type:              template.Type,
+		Status:            template.Status,
+		Variables:         template.Variables,
+		Description:       template.Description,
+		Approved:          template.Approved,
+		UpdatedAt:         template.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+		PendingRevisionID: pendingRevisionID,
+	}
 }
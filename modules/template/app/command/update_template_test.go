@@ -0,0 +1,59 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"tixgo/modules/template/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// acceptingRenderer finds every template valid
+type acceptingRenderer struct {
+	domain.TemplateRenderer
+}
+
+func (acceptingRenderer) ValidateTemplate(context.Context, string) error {
+	return nil
+}
+
+func TestUpdateTemplateHandler_NonAdminSubmitsRevision(t *testing.T) {
+	templates, revisions := newReviewFixture(2)
+	handler := NewUpdateTemplateHandler(templates, revisions, acceptingRenderer{})
+
+	result, err := handler.Handle(context.Background(), UpdateTemplateCommand{ID: 1, Content: "<p>Hey</p>", UpdatedBy: 9})
+	require.NoError(t, err)
+	require.NotNil(t, result.PendingRevisionID)
+	assert.Nil(t, templates.updated, "the template waits for the review")
+	assert.Equal(t, "<p>Hey</p>", revisions.submitted.Content)
+	assert.Equal(t, 2, revisions.submitted.BaseVersion)
+}
+
+func TestUpdateTemplateHandler_NonAdminCannotChangeStatusWithContent(t *testing.T) {
+	templates, revisions := newReviewFixture(2)
+	handler := NewUpdateTemplateHandler(templates, revisions, acceptingRenderer{})
+
+	_, err := handler.Handle(context.Background(), UpdateTemplateCommand{ID: 1, Content: "<p>Hey</p>", Status: string(domain.TemplateStatusInactive), UpdatedBy: 9})
+	assert.ErrorIs(t, err, domain.ErrStatusChangeInRevision)
+	assert.Nil(t, templates.updated)
+	assert.Nil(t, revisions.submitted)
+
+	// the status alone is changed at once
+	result, err := handler.Handle(context.Background(), UpdateTemplateCommand{ID: 1, Status: string(domain.TemplateStatusInactive), UpdatedBy: 9})
+	require.NoError(t, err)
+	assert.Equal(t, domain.TemplateStatusInactive, templates.updated.Status)
+	assert.Nil(t, result.PendingRevisionID)
+}
+
+func TestUpdateTemplateHandler_AdminChangesStatusWithContent(t *testing.T) {
+	templates, revisions := newReviewFixture(2)
+	handler := NewUpdateTemplateHandler(templates, revisions, acceptingRenderer{})
+
+	_, err := handler.Handle(context.Background(), UpdateTemplateCommand{ID: 1, Content: "<p>Hey</p>", Status: string(domain.TemplateStatusInactive), UpdatedBy: 1, IsAdmin: true})
+	require.NoError(t, err)
+	assert.Equal(t, "<p>Hey</p>", templates.updated.Content)
+	assert.Equal(t, domain.TemplateStatusInactive, templates.updated.Status)
+	assert.Nil(t, revisions.submitted)
+}
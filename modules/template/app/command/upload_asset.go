@@ -0,0 +1,45 @@
+package command
+
+import (
+	"context"
+	"io"
+
+	"tixgo/shared/assets"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// UploadAssetCommand represents the command of an admin uploading an image templates reference
+type UploadAssetCommand struct {
+	// Name is what templates write in {{asset "logo.png"}}
+	Name       string `form:"name" binding:"required"`
+	UploadedBy int64  `form:"-"`
+	// Content is the image, read up to one byte past assets.MaxSize
+	Content io.Reader `form:"-"`
+}
+
+// UploadAssetHandler hosts the images of templates
+type UploadAssetHandler struct {
+	assets *assets.Service
+}
+
+// NewUploadAssetHandler creates a new upload asset handler
+func NewUploadAssetHandler(assetService *assets.Service) *UploadAssetHandler {
+	return &UploadAssetHandler{
+		assets: assetService,
+	}
+}
+
+// Handle hosts the image under its name. Uploading another image under a name replaces it in the
+// templates rendered from then on.
+func (h *UploadAssetHandler) Handle(ctx context.Context, cmd UploadAssetCommand) (*AssetResult, error) {
+	asset, err := h.assets.Upload(ctx, cmd.Name, cmd.Content, cmd.UploadedBy)
+	if err != nil {
+		if err == assets.ErrInvalidName || err == assets.ErrUnsupportedType || err == assets.ErrTooLarge {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to upload asset")
+	}
+
+	return ToAssetResult(asset, h.assets.URL(asset)), nil
+}
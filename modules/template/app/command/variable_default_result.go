@@ -0,0 +1,36 @@
+package command
+
+import "tixgo/modules/template/domain"
+
+// VariableDefaultResult represents the default value of a template variable and where it comes from.
+// Values of the configuration have no organizer nor update.
+type VariableDefaultResult struct {
+	Name        string               `json:"name"`
+	Value       string               `json:"value"`
+	Source      domain.DefaultSource `json:"source"`
+	OrganizerID *int64               `json:"organizer_id"`
+	UpdatedBy   *int64               `json:"updated_by"`
+	UpdatedAt   *string              `json:"updated_at"`
+}
+
+// ToVariableDefaultResult converts an override to its result
+func ToVariableDefaultResult(variableDefault *domain.VariableDefault) *VariableDefaultResult {
+	source := domain.DefaultSourcePlatform
+	if variableDefault.OrganizerID != nil {
+		source = domain.DefaultSourceOrganizer
+	}
+
+	result := &VariableDefaultResult{
+		Name:        variableDefault.Name,
+		Value:       variableDefault.Value,
+		Source:      source,
+		OrganizerID: variableDefault.OrganizerID,
+	}
+	if variableDefault.UpdatedBy != 0 {
+		updatedBy := variableDefault.UpdatedBy
+		result.UpdatedBy = &updatedBy
+	}
+	updatedAt := variableDefault.UpdatedAt.Format("2006-01-02T15:04:05Z")
+	result.UpdatedAt = &updatedAt
+	return result
+}
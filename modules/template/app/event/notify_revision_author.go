@@ -0,0 +1,67 @@
+package event
+
+import (
+	"context"
+	"strconv"
+
+	"tixgo/modules/template/domain"
+	sharedNotification "tixgo/shared/events/notification"
+	sharedKafka "tixgo/shared/kafka"
+
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/notification/mail"
+	"github.com/duongptryu/gox/syserr"
+)
+
+const (
+	SlugTemplateReviewed = "template-reviewed"
+)
+
+type notifyRevisionAuthor struct {
+	templateRepo     domain.TemplateRepository
+	templateRenderer domain.TemplateRenderer
+	eventBus         messaging.EventBus
+}
+
+func NewNotifyRevisionAuthor(templateRepo domain.TemplateRepository, templateRenderer domain.TemplateRenderer, eventBus messaging.EventBus) *notifyRevisionAuthor {
+	return &notifyRevisionAuthor{
+		templateRepo:     templateRepo,
+		templateRenderer: templateRenderer,
+		eventBus:         eventBus,
+	}
+}
+
+// Notify mails the author of a revision the decision of the admin and their comment
+func (h *notifyRevisionAuthor) Notify(ctx context.Context, event *domain.EventTemplateReviewed) error {
+	template, err := h.templateRepo.GetBySlug(ctx, SlugTemplateReviewed)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get template")
+	}
+
+	rendered, err := h.templateRenderer.Render(ctx, template, map[string]interface{}{
+		"revision_id":   event.RevisionID,
+		"template_id":   event.TemplateID,
+		"template_slug": event.TemplateSlug,
+		"status":        string(event.Status),
+		"comment":       event.Comment,
+	}, domain.RenderOptions{})
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to render template")
+	}
+
+	key := strconv.FormatInt(event.AuthorID, 10)
+	err = h.eventBus.PublishEvent(sharedKafka.WithPartitionKey(ctx, key), &sharedNotification.EventNotificationRequested{
+		UserID:   event.AuthorID,
+		Category: "template_review",
+		Title:    "Template " + event.TemplateSlug + " " + string(event.Status),
+		Summary:  event.Comment,
+		Subject:  rendered.Subject,
+		HTMLBody: rendered.Content,
+		Priority: mail.PriorityNormal,
+	})
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to publish notification event")
+	}
+
+	return nil
+}
@@ -25,6 +25,7 @@ type TemplateResult struct {
 	Status      domain.TemplateStatus `json:"status"`
 	Variables   []string              `json:"variables"`
 	Description string                `json:"description"`
+	Approved    bool                  `json:"approved"`
 	CreatedBy   int64                 `json:"created_by"`
 	CreatedAt   string                `json:"created_at"`
 	UpdatedAt   string                `json:"updated_at"`
@@ -72,6 +73,7 @@ func (h *GetTemplateHandler) Handle(ctx context.Context, query GetTemplateQuery)
 		Status:      template.Status,
 		Variables:   template.Variables,
 		Description: template.Description,
+		Approved:    template.Approved,
 		CreatedBy:   template.CreatedBy,
 		CreatedAt:   template.CreatedAt.Format("2006-01-02T15:04:05Z"),
 		UpdatedAt:   template.UpdatedAt.Format("2006-01-02T15:04:05Z"),
@@ -0,0 +1,40 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/template/app/command"
+	"tixgo/modules/template/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// GetTemplateRevisionQuery represents the query to get a template revision
+type GetTemplateRevisionQuery struct {
+	ID int64
+}
+
+// GetTemplateRevisionHandler handles getting template revisions
+type GetTemplateRevisionHandler struct {
+	revisionRepo domain.TemplateRevisionRepository
+}
+
+// NewGetTemplateRevisionHandler creates a new get template revision handler
+func NewGetTemplateRevisionHandler(revisionRepo domain.TemplateRevisionRepository) *GetTemplateRevisionHandler {
+	return &GetTemplateRevisionHandler{
+		revisionRepo: revisionRepo,
+	}
+}
+
+// Handle executes the get template revision query
+func (h *GetTemplateRevisionHandler) Handle(ctx context.Context, query GetTemplateRevisionQuery) (*command.TemplateRevisionResult, error) {
+	revision, err := h.revisionRepo.GetByID(ctx, query.ID)
+	if err != nil {
+		if err == domain.ErrRevisionNotFound {
+			return nil, domain.ErrRevisionNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get template revision")
+	}
+
+	return command.ToTemplateRevisionResult(revision), nil
+}
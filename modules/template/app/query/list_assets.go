@@ -0,0 +1,36 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/template/app/command"
+	"tixgo/shared/assets"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ListAssetsHandler handles listing the hosted images templates may reference
+type ListAssetsHandler struct {
+	assets *assets.Service
+}
+
+// NewListAssetsHandler creates a new list assets handler
+func NewListAssetsHandler(assetService *assets.Service) *ListAssetsHandler {
+	return &ListAssetsHandler{
+		assets: assetService,
+	}
+}
+
+// Handle lists every hosted image by name. They are a handful of logos and banners, so they are not paged.
+func (h *ListAssetsHandler) Handle(ctx context.Context) ([]*command.AssetResult, error) {
+	list, err := h.assets.List(ctx)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list assets")
+	}
+
+	results := make([]*command.AssetResult, len(list))
+	for i, asset := range list {
+		results[i] = command.ToAssetResult(asset, h.assets.URL(asset))
+	}
+	return results, nil
+}
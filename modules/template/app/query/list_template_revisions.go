@@ -0,0 +1,63 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/template/app/command"
+	"tixgo/modules/template/domain"
+	"tixgo/shared/listing"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// FilterTemplateRevisionsQuery represents the filters for listing template revisions
+type FilterTemplateRevisionsQuery struct {
+	TemplateID *int64  `json:"template_id" form:"template_id"`
+	Status     *string `json:"status" form:"status"`
+}
+
+// ListTemplateRevisionsHandler handles listing template revisions, the review queue of admins
+type ListTemplateRevisionsHandler struct {
+	revisionRepo domain.TemplateRevisionRepository
+}
+
+// NewListTemplateRevisionsHandler creates a new list template revisions handler
+func NewListTemplateRevisionsHandler(revisionRepo domain.TemplateRevisionRepository) *ListTemplateRevisionsHandler {
+	return &ListTemplateRevisionsHandler{
+		revisionRepo: revisionRepo,
+	}
+}
+
+// Handle executes the list template revisions query
+func (h *ListTemplateRevisionsHandler) Handle(ctx context.Context, filters *FilterTemplateRevisionsQuery, paging *listing.Paging) ([]*command.TemplateRevisionResult, error) {
+	// Ensure paging is not nil (should already be handled in HTTP layer)
+	if paging == nil {
+		paging = &listing.Paging{}
+		paging.Fulfill()
+	}
+
+	domainFilters := domain.ListRevisionFilters{
+		TemplateID: filters.TemplateID,
+	}
+
+	// Set status filter
+	if filters.Status != nil && *filters.Status != "" {
+		if !domain.IsValidRevisionStatus(*filters.Status) {
+			return nil, domain.ErrInvalidRevisionStatus
+		}
+		status := domain.RevisionStatus(*filters.Status)
+		domainFilters.Status = &status
+	}
+
+	revisions, err := h.revisionRepo.List(ctx, domainFilters, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list template revisions")
+	}
+
+	items := make([]*command.TemplateRevisionResult, len(revisions))
+	for i, revision := range revisions {
+		items[i] = command.ToTemplateRevisionResult(revision)
+	}
+
+	return items, nil
+}
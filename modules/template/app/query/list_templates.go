@@ -2,10 +2,12 @@ package query
 
 import (
 	"context"
+	"strconv"
+	"strings"
 
 	"tixgo/modules/template/domain"
+	"tixgo/shared/listing"
 
-	"github.com/duongptryu/gox/pagination"
 	"github.com/duongptryu/gox/syserr"
 )
 
@@ -15,6 +17,8 @@ type FilterTemplatesQuery struct {
 	Status    *string `json:"status" form:"status"`
 	CreatedBy *int64  `json:"created_by" form:"created_by"`
 	Search    string  `json:"search" form:"search"`
+	// Fields narrows paged list items to a comma separated selection of their fields, every field by default
+	Fields string `json:"fields,omitempty" form:"fields"`
 }
 
 // ListTemplatesResult represents the result of template listing
@@ -31,6 +35,7 @@ type TemplateListItem struct {
 	Type        domain.TemplateType   `json:"type"`
 	Status      domain.TemplateStatus `json:"status"`
 	Description string                `json:"description"`
+	Approved    bool                  `json:"approved"`
 	CreatedBy   int64                 `json:"created_by"`
 	CreatedAt   string                `json:"created_at"`
 	UpdatedAt   string                `json:"updated_at"`
@@ -49,14 +54,47 @@ func NewListTemplatesHandler(templateRepo domain.TemplateRepository) *ListTempla
 }
 
 // Handle executes the list templates query
-func (h *ListTemplatesHandler) Handle(ctx context.Context, filters *FilterTemplatesQuery, paging *pagination.Paging) ([]TemplateListItem, error) {
+func (h *ListTemplatesHandler) Handle(ctx context.Context, filters *FilterTemplatesQuery, paging *listing.Paging) ([]TemplateListItem, error) {
 	// Ensure paging is not nil (should already be handled in HTTP layer)
 	if paging == nil {
-		paging = &pagination.Paging{}
+		paging = &listing.Paging{}
 		paging.Fulfill()
 	}
 
-	// Build domain filters from query filters
+	domainFilters, err := toListTemplateFilters(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get templates, their content is never listed
+	summaries, err := h.templateRepo.ListSummaries(ctx, domainFilters, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list templates")
+	}
+
+	// Convert to list items
+	items := make([]TemplateListItem, len(summaries))
+	for i, summary := range summaries {
+		items[i] = toTemplateListItem(summary)
+	}
+
+	return items, nil
+}
+
+// Stream calls fn with every template matching filters, reading them from the database as fn consumes them
+func (h *ListTemplatesHandler) Stream(ctx context.Context, filters *FilterTemplatesQuery, fn func(item TemplateListItem) error) error {
+	domainFilters, err := toListTemplateFilters(filters)
+	if err != nil {
+		return err
+	}
+
+	return h.templateRepo.Stream(ctx, domainFilters, func(summary *domain.TemplateSummary) error {
+		return fn(toTemplateListItem(summary))
+	})
+}
+
+// toListTemplateFilters builds domain filters from query filters
+func toListTemplateFilters(filters *FilterTemplatesQuery) (domain.ListTemplateFilters, error) {
 	domainFilters := domain.ListTemplateFilters{
 		Search: filters.Search,
 	}
@@ -64,7 +102,7 @@ func (h *ListTemplatesHandler) Handle(ctx context.Context, filters *FilterTempla
 	// Set type filter
 	if filters.Type != nil && *filters.Type != "" {
 		if !domain.IsValidTemplateType(*filters.Type) {
-			return nil, domain.ErrInvalidTemplateType
+			return domain.ListTemplateFilters{}, domain.ErrInvalidTemplateType
 		}
 		templateType := domain.TemplateType(*filters.Type)
 		domainFilters.Type = &templateType
@@ -77,7 +115,7 @@ func (h *ListTemplatesHandler) Handle(ctx context.Context, filters *FilterTempla
 		case domain.TemplateStatusActive, domain.TemplateStatusInactive, domain.TemplateStatusDraft:
 			domainFilters.Status = &templateStatus
 		default:
-			return nil, domain.ErrInvalidTemplateStatus
+			return domain.ListTemplateFilters{}, domain.ErrInvalidTemplateStatus
 		}
 	}
 
@@ -86,28 +124,71 @@ func (h *ListTemplatesHandler) Handle(ctx context.Context, filters *FilterTempla
 		domainFilters.CreatedBy = filters.CreatedBy
 	}
 
-	// Get templates
-	templates, err := h.templateRepo.List(ctx, domainFilters, paging)
-	if err != nil {
-		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list templates")
+	return domainFilters, nil
+}
+
+func toTemplateListItem(summary *domain.TemplateSummary) TemplateListItem {
+	return TemplateListItem{
+		ID:          summary.ID,
+		Name:        summary.Name,
+		Slug:        summary.Slug,
+		Subject:     summary.Subject,
+		Type:        summary.Type,
+		Status:      summary.Status,
+		Description: summary.Description,
+		Approved:    summary.Approved,
+		CreatedBy:   summary.CreatedBy,
+		CreatedAt:   summary.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:   summary.UpdatedAt.Format("2006-01-02T15:04:05Z"),
 	}
+}
 
-	// Convert to list items
-	items := make([]TemplateListItem, len(templates))
-	for i, template := range templates {
-		items[i] = TemplateListItem{
-			ID:          template.ID,
-			Name:        template.Name,
-			Slug:        template.Slug,
-			Subject:     template.Subject,
-			Type:        template.Type,
-			Status:      template.Status,
-			Description: template.Description,
-			CreatedBy:   template.CreatedBy,
-			CreatedAt:   template.CreatedAt.Format("2006-01-02T15:04:05Z"),
-			UpdatedAt:   template.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+// fields returns the list item keyed by its JSON field names
+func (item TemplateListItem) fields() map[string]interface{} {
+	return map[string]interface{}{
+		"id":          item.ID,
+		"name":        item.Name,
+		"slug":        item.Slug,
+		"subject":     item.Subject,
+		"type":        item.Type,
+		"status":      item.Status,
+		"description": item.Description,
+		"approved":    item.Approved,
+		"created_by":  item.CreatedBy,
+		"created_at":  item.CreatedAt,
+		"updated_at":  item.UpdatedAt,
+	}
+}
+
+// ParseTemplateFields validates a comma separated selection of list item fields, e.g. "id,name,slug"
+func ParseTemplateFields(fields string) ([]string, error) {
+	known := TemplateListItem{}.fields()
+
+	var selected []string
+	for _, field := range strings.Split(fields, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if _, ok := known[field]; !ok {
+			return nil, syserr.New(syserr.InvalidArgumentCode, "unknown field "+strconv.Quote(field))
 		}
+		selected = append(selected, field)
 	}
 
-	return items, nil
+	return selected, nil
+}
+
+// SelectTemplateFields narrows list items to the selected fields
+func SelectTemplateFields(items []TemplateListItem, selected []string) []map[string]interface{} {
+	narrowed := make([]map[string]interface{}, len(items))
+	for i, item := range items {
+		all := item.fields()
+		narrowed[i] = make(map[string]interface{}, len(selected))
+		for _, field := range selected {
+			narrowed[i][field] = all[field]
+		}
+	}
+
+	return narrowed
 }
@@ -0,0 +1,60 @@
+package query
+
+import (
+	"context"
+	"sort"
+
+	"tixgo/modules/template/app/command"
+	"tixgo/modules/template/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ListVariableDefaultsQuery represents the query of an admin for the default variables renders have,
+// those of the renders of an organizer when OrganizerID is set
+type ListVariableDefaultsQuery struct {
+	OrganizerID *int64 `form:"organizer_id" binding:"omitempty,min=1"`
+}
+
+// ListVariableDefaultsHandler handles listing the default variables
+type ListVariableDefaultsHandler struct {
+	variableDefaultRepo domain.VariableDefaultRepository
+}
+
+// NewListVariableDefaultsHandler creates a new list variable defaults handler
+func NewListVariableDefaultsHandler(variableDefaultRepo domain.VariableDefaultRepository) *ListVariableDefaultsHandler {
+	return &ListVariableDefaultsHandler{
+		variableDefaultRepo: variableDefaultRepo,
+	}
+}
+
+// Handle lists the default variables by name with the value renders get and its source: the
+// configuration, overridden by the platform, overridden by the organizer. They are a handful, so they
+// are not paged.
+func (h *ListVariableDefaultsHandler) Handle(ctx context.Context, query ListVariableDefaultsQuery) ([]*command.VariableDefaultResult, error) {
+	effective := make(map[string]*command.VariableDefaultResult)
+	for name, value := range domain.PlatformDefaults() {
+		effective[name] = &command.VariableDefaultResult{Name: name, Value: value, Source: domain.DefaultSourceConfig}
+	}
+
+	scopes := []*int64{nil}
+	if query.OrganizerID != nil {
+		scopes = append(scopes, query.OrganizerID)
+	}
+	for _, organizerID := range scopes {
+		overrides, err := h.variableDefaultRepo.List(ctx, organizerID)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list default variables")
+		}
+		for _, override := range overrides {
+			effective[override.Name] = command.ToVariableDefaultResult(override)
+		}
+	}
+
+	results := make([]*command.VariableDefaultResult, 0, len(effective))
+	for _, result := range effective {
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+	return results, nil
+}
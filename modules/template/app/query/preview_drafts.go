@@ -0,0 +1,87 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/template/domain"
+	"tixgo/shared/listing"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// PreviewDraftsQuery represents the query to render the draft templates, for the debug previews
+type PreviewDraftsQuery struct {
+	// Locale and TimeZone format the dates and amounts of the previews, see domain.RenderOptions
+	Locale   string `json:"locale" form:"locale"`
+	TimeZone string `json:"time_zone" form:"time_zone"`
+}
+
+// DraftPreview is a draft template rendered with placeholders for its variables
+type DraftPreview struct {
+	TemplateID  int64               `json:"template_id"`
+	Name        string              `json:"name"`
+	Slug        string              `json:"slug"`
+	Type        domain.TemplateType `json:"type"`
+	Subject     string              `json:"subject"`
+	Content     string              `json:"content"`
+	ContentType string              `json:"content_type"`
+	// Error is why the draft failed to render, the other drafts are previewed all the same
+	Error string `json:"error,omitempty"`
+}
+
+// PreviewDraftsHandler handles rendering the draft templates
+type PreviewDraftsHandler struct {
+	templateRepo     domain.TemplateRepository
+	templateRenderer domain.TemplateRenderer
+}
+
+// NewPreviewDraftsHandler creates a new preview drafts handler
+func NewPreviewDraftsHandler(templateRepo domain.TemplateRepository, templateRenderer domain.TemplateRenderer) *PreviewDraftsHandler {
+	return &PreviewDraftsHandler{
+		templateRepo:     templateRepo,
+		templateRenderer: templateRenderer,
+	}
+}
+
+// Handle executes the preview drafts query. Each variable of a draft renders as its name in brackets.
+func (h *PreviewDraftsHandler) Handle(ctx context.Context, query *PreviewDraftsQuery, paging *listing.Paging) ([]DraftPreview, error) {
+	status := domain.TemplateStatusDraft
+	drafts, err := h.templateRepo.List(ctx, domain.ListTemplateFilters{Status: &status}, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list draft templates")
+	}
+
+	options := domain.RenderOptions{Locale: query.Locale, TimeZone: query.TimeZone}
+	previews := make([]DraftPreview, len(drafts))
+	for i, draft := range drafts {
+		previews[i] = DraftPreview{
+			TemplateID: draft.ID,
+			Name:       draft.Name,
+			Slug:       draft.Slug,
+			Type:       draft.Type,
+		}
+
+		rendered, err := h.templateRenderer.Render(ctx, draft, placeholders(draft.Variables), options)
+		if err != nil {
+			if err == domain.ErrInvalidTimeZone {
+				return nil, err
+			}
+			previews[i].Error = err.Error()
+			continue
+		}
+		previews[i].Subject = rendered.Subject
+		previews[i].Content = rendered.Content
+		previews[i].ContentType = rendered.ContentType
+	}
+
+	return previews, nil
+}
+
+// placeholders stands in for the variables of a template with their names in brackets
+func placeholders(variables []string) map[string]interface{} {
+	values := make(map[string]interface{}, len(variables))
+	for _, name := range variables {
+		values[name] = "[" + name + "]"
+	}
+	return values
+}
@@ -0,0 +1,167 @@
+package query
+
+import (
+	"context"
+	"fmt"
+
+	"tixgo/modules/template/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// MaxRenderBatchSize is the maximum number of items of a batch render
+const MaxRenderBatchSize = 500
+
+// RenderBatchQuery represents the query to render many templates at once
+type RenderBatchQuery struct {
+	Items []RenderBatchItem `json:"items" binding:"required"`
+	// Types are the template types the caller may render, every type when nil
+	Types []domain.TemplateType `json:"-"`
+}
+
+// RenderBatchItem is a template to render with its variables, in the locale and time zone of its recipient
+type RenderBatchItem struct {
+	TemplateSlug string                 `json:"template_slug"`
+	Variables    map[string]interface{} `json:"variables"`
+	Locale       string                 `json:"locale"`
+	TimeZone     string                 `json:"time_zone"`
+}
+
+// RenderBatchResult holds the results in the order of the query items
+type RenderBatchResult struct {
+	Items []RenderBatchItemResult `json:"items"`
+}
+
+// RenderBatchItemResult is either a rendered template or the error of its item
+type RenderBatchItemResult struct {
+	TemplateSlug string            `json:"template_slug"`
+	TemplateID   int64             `json:"template_id,omitempty"`
+	Subject      string            `json:"subject,omitempty"`
+	Content      string            `json:"content,omitempty"`
+	ContentType  string            `json:"content_type,omitempty"`
+	Error        *RenderBatchError `json:"error,omitempty"`
+}
+
+// RenderBatchError describes why an item failed
+type RenderBatchError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// RenderBatchHandler handles batch template rendering
+type RenderBatchHandler struct {
+	templateRepo     domain.TemplateRepository
+	templateRenderer domain.TemplateRenderer
+	defaults         domain.DefaultsResolver
+}
+
+// NewRenderBatchHandler creates a new render batch handler
+func NewRenderBatchHandler(templateRepo domain.TemplateRepository, templateRenderer domain.TemplateRenderer, defaults domain.DefaultsResolver) *RenderBatchHandler {
+	return &RenderBatchHandler{
+		templateRepo:     templateRepo,
+		templateRenderer: templateRenderer,
+		defaults:         defaults,
+	}
+}
+
+// compiledEntry is a template loaded and parsed once for the whole batch, or why it can't be used
+type compiledEntry struct {
+	template *domain.Template
+	compiled domain.CompiledTemplate
+	err      error
+}
+
+// Handle executes the render batch query. Each distinct template is fetched and parsed once, and the
+// overrides of the platform defaults resolved once for every item; a failing item gets its own error
+// and does not fail the batch.
+func (h *RenderBatchHandler) Handle(ctx context.Context, query RenderBatchQuery) (*RenderBatchResult, error) {
+	if len(query.Items) == 0 {
+		return nil, syserr.New(syserr.InvalidArgumentCode, "items must not be empty")
+	}
+	if len(query.Items) > MaxRenderBatchSize {
+		return nil, syserr.New(syserr.InvalidArgumentCode, fmt.Sprintf("at most %d items can be rendered at once", MaxRenderBatchSize))
+	}
+
+	defaults, err := h.defaults.ResolveDefaults(ctx, 0)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to resolve default variables")
+	}
+
+	compiled := make(map[string]*compiledEntry)
+	result := &RenderBatchResult{Items: make([]RenderBatchItemResult, len(query.Items))}
+
+	for i, item := range query.Items {
+		itemResult := RenderBatchItemResult{TemplateSlug: item.TemplateSlug}
+
+		entry, ok := compiled[item.TemplateSlug]
+		if !ok {
+			entry = h.compile(ctx, item.TemplateSlug, query.Types)
+			compiled[item.TemplateSlug] = entry
+		}
+		if entry.err != nil {
+			// infrastructure failures fail the whole batch, item level problems are reported per item
+			if syserr.GetCodeFromGenericError(entry.err) == syserr.InternalCode {
+				return nil, entry.err
+			}
+			itemResult.Error = toRenderBatchError(entry.err)
+			result.Items[i] = itemResult
+			continue
+		}
+
+		rendered, err := entry.compiled.Execute(item.Variables, domain.RenderOptions{
+			Locale:   item.Locale,
+			TimeZone: item.TimeZone,
+			Defaults: defaults,
+		})
+		if err != nil {
+			itemResult.Error = toRenderBatchError(syserr.Wrap(err, syserr.InvalidArgumentCode, "failed to render template"))
+			result.Items[i] = itemResult
+			continue
+		}
+
+		itemResult.TemplateID = entry.template.ID
+		itemResult.Subject = rendered.Subject
+		itemResult.Content = rendered.Content
+		itemResult.ContentType = rendered.ContentType
+		result.Items[i] = itemResult
+	}
+
+	return result, nil
+}
+
+func (h *RenderBatchHandler) compile(ctx context.Context, slug string, types []domain.TemplateType) *compiledEntry {
+	if slug == "" {
+		return &compiledEntry{err: syserr.New(syserr.InvalidArgumentCode, "template_slug is required")}
+	}
+
+	template, err := h.templateRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		if err == domain.ErrTemplateNotFound {
+			return &compiledEntry{err: domain.ErrTemplateNotFound}
+		}
+		return &compiledEntry{err: syserr.Wrap(err, syserr.InternalCode, "failed to get template")}
+	}
+
+	if !renderable(types, template) {
+		return &compiledEntry{err: domain.ErrTemplateNotRenderable}
+	}
+
+	// Check if template is active
+	if !template.IsActive() {
+		return &compiledEntry{err: domain.ErrTemplateInactive}
+	}
+
+	compiled, err := h.templateRenderer.Compile(ctx, template)
+	if err != nil {
+		return &compiledEntry{err: syserr.Wrap(err, syserr.InvalidArgumentCode, "failed to parse template")}
+	}
+
+	return &compiledEntry{template: template, compiled: compiled}
+}
+
+func toRenderBatchError(err error) *RenderBatchError {
+	return &RenderBatchError{
+		Code:    string(syserr.GetCodeFromGenericError(err)),
+		Message: err.Error(),
+	}
+}
@@ -2,6 +2,7 @@ package query
 
 import (
 	"context"
+	"slices"
 
 	"tixgo/modules/template/domain"
 
@@ -13,6 +14,11 @@ type RenderTemplateQuery struct {
 	TemplateID   *int64                 `json:"template_id"`
 	TemplateSlug *string                `json:"template_slug"`
 	Variables    map[string]interface{} `json:"variables"`
+	// Locale and TimeZone format the dates and amounts of the render, see domain.RenderOptions
+	Locale   string `json:"locale"`
+	TimeZone string `json:"time_zone"`
+	// Types are the template types the caller may render, every type when nil
+	Types []domain.TemplateType `json:"-"`
 }
 
 // RenderTemplateResult represents the result of template rendering
@@ -58,14 +64,24 @@ func (h *RenderTemplateHandler) Handle(ctx context.Context, query RenderTemplate
 		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get template")
 	}
 
+	if !renderable(query.Types, template) {
+		return nil, domain.ErrTemplateNotRenderable
+	}
+
 	// Check if template is active
 	if !template.IsActive() {
 		return nil, domain.ErrTemplateInactive
 	}
 
 	// Render template
-	rendered, err := h.templateRenderer.Render(ctx, template, query.Variables)
+	rendered, err := h.templateRenderer.Render(ctx, template, query.Variables, domain.RenderOptions{
+		Locale:   query.Locale,
+		TimeZone: query.TimeZone,
+	})
 	if err != nil {
+		if err == domain.ErrInvalidTimeZone {
+			return nil, err
+		}
 		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to render template")
 	}
 
@@ -76,3 +92,8 @@ func (h *RenderTemplateHandler) Handle(ctx context.Context, query RenderTemplate
 		TemplateID:  template.ID,
 	}, nil
 }
+
+// renderable tells whether a caller limited to types may render the template
+func renderable(types []domain.TemplateType, template *domain.Template) bool {
+	return types == nil || slices.Contains(types, template.Type)
+}
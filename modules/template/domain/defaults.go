@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"context"
+	"regexp"
+	"sync/atomic"
+	"time"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// variableNamePattern matches the names templates can write as {{.Name}}
+var variableNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]{0,99}$`)
+
+// VariableDefault overrides the value a template variable renders with when its caller leaves it out,
+// for the whole platform or for the mails of an organizer
+type VariableDefault struct {
+	ID int64
+	// OrganizerID is the organizer whose renders it applies to, nil for every render
+	OrganizerID *int64
+	Name        string
+	Value       string
+	UpdatedBy   int64
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// DefaultSource tells where the value of a default variable comes from, a later source overriding an
+// earlier one
+type DefaultSource string
+
+const (
+	DefaultSourceConfig    DefaultSource = "config"
+	DefaultSourcePlatform  DefaultSource = "platform"
+	DefaultSourceOrganizer DefaultSource = "organizer"
+)
+
+// NewVariableDefault creates the override of a variable, for the organizer or the platform when nil
+func NewVariableDefault(organizerID *int64, name, value string, updatedBy int64) (*VariableDefault, error) {
+	if err := ValidateVariableName(name); err != nil {
+		return nil, err
+	}
+	if len(value) > 2000 {
+		return nil, syserr.New(syserr.InvalidArgumentCode, "value must be at most 2000 bytes")
+	}
+
+	return &VariableDefault{OrganizerID: organizerID, Name: name, Value: value, UpdatedBy: updatedBy}, nil
+}
+
+// ValidateVariableName checks the name of a default variable: a letter followed by letters, digits and
+// underscores, and not the reserved ThemeVariable
+func ValidateVariableName(name string) error {
+	if !variableNamePattern.MatchString(name) || name == ThemeVariable {
+		return ErrInvalidVariableName
+	}
+	return nil
+}
+
+// platformDefaults are the defaults of the environment, set from the configuration at startup
+var platformDefaults atomic.Pointer[map[string]string]
+
+// SetPlatformDefaults sets the defaults of the environment every render has, before their overrides
+func SetPlatformDefaults(defaults map[string]string) error {
+	copied := make(map[string]string, len(defaults))
+	for name, value := range defaults {
+		if err := ValidateVariableName(name); err != nil {
+			return syserr.Wrap(err, syserr.InvalidArgumentCode, "invalid default variable "+name)
+		}
+		copied[name] = value
+	}
+	platformDefaults.Store(&copied)
+	return nil
+}
+
+// PlatformDefaults returns the defaults of the environment, not to be modified
+func PlatformDefaults() map[string]string {
+	if defaults := platformDefaults.Load(); defaults != nil {
+		return *defaults
+	}
+	return nil
+}
+
+// MergeDefaults returns the defaults of the layers, a later layer overriding an earlier one
+func MergeDefaults(layers ...map[string]string) map[string]string {
+	merged := make(map[string]string)
+	for _, layer := range layers {
+		for name, value := range layer {
+			merged[name] = value
+		}
+	}
+	return merged
+}
+
+// DefaultsResolver finds the overrides of the platform defaults a render has
+type DefaultsResolver interface {
+	// ResolveDefaults returns the overrides of the platform merged with those of the organizer, the
+	// platform ones alone for a zero organizerID
+	ResolveDefaults(ctx context.Context, organizerID int64) (map[string]string, error)
+}
+
+// VariableDefaultRepository defines the interface for the persistence of the overrides
+type VariableDefaultRepository interface {
+	DefaultsResolver
+
+	// Save creates or replaces the override of a variable for its organizer or the platform
+	Save(ctx context.Context, variableDefault *VariableDefault) error
+
+	// List retrieves the overrides of the organizer, of the platform when nil, by name
+	List(ctx context.Context, organizerID *int64) ([]*VariableDefault, error)
+
+	// Delete deletes the override of a variable, ErrVariableDefaultNotFound when there is none
+	Delete(ctx context.Context, organizerID *int64, name string) error
+}
@@ -1,15 +1,32 @@
 package domain
 
-import "github.com/duongptryu/gox/syserr"
+import (
+	"tixgo/shared/errstack"
+
+	"github.com/duongptryu/gox/syserr"
+)
 
 // Template domain errors
 var (
-	ErrTemplateNotFound      = syserr.New(syserr.NotFoundCode, "template not found")
-	ErrTemplateAlreadyExists = syserr.New(syserr.ConflictCode, "template already exists")
-	ErrInvalidTemplateType   = syserr.New(syserr.InvalidArgumentCode, "invalid template type")
-	ErrInvalidTemplateStatus = syserr.New(syserr.InvalidArgumentCode, "invalid template status")
-	ErrTemplateInactive      = syserr.New(syserr.ForbiddenCode, "template is inactive")
-	ErrTemplateRenderFailed  = syserr.New(syserr.InternalCode, "template rendering failed")
-	ErrInvalidTemplateSlug   = syserr.New(syserr.InvalidArgumentCode, "invalid template slug")
-	ErrTemplateSyntaxError   = syserr.New(syserr.InvalidArgumentCode, "template syntax error")
+	ErrTemplateNotFound        = errstack.Sentinel(syserr.NotFoundCode, "template not found")
+	ErrTemplateAlreadyExists   = errstack.Sentinel(syserr.ConflictCode, "template already exists")
+	ErrInvalidTemplateType     = errstack.Sentinel(syserr.InvalidArgumentCode, "invalid template type")
+	ErrInvalidTemplateStatus   = errstack.Sentinel(syserr.InvalidArgumentCode, "invalid template status")
+	ErrTemplateInactive        = errstack.Sentinel(syserr.ForbiddenCode, "template is inactive")
+	ErrTemplateRenderFailed    = errstack.Sentinel(syserr.InternalCode, "template rendering failed")
+	ErrInvalidTemplateSlug     = errstack.Sentinel(syserr.InvalidArgumentCode, "invalid template slug")
+	ErrTemplateSyntaxError     = errstack.Sentinel(syserr.InvalidArgumentCode, "template syntax error")
+	ErrTemplateNotApproved     = errstack.Sentinel(syserr.ConflictCode, "template has no approved version yet")
+	ErrRevisionNotFound        = errstack.Sentinel(syserr.NotFoundCode, "template revision not found")
+	ErrRevisionNotPending      = errstack.Sentinel(syserr.ConflictCode, "template revision is not pending review")
+	ErrRevisionStale           = errstack.Sentinel(syserr.ConflictCode, "the template changed since the revision was submitted, it must be rejected and submitted again")
+	ErrStatusChangeInRevision  = errstack.Sentinel(syserr.InvalidArgumentCode, "the status cannot change in an edit submitted for review, change it separately")
+	ErrReviewCommentRequired   = errstack.Sentinel(syserr.InvalidArgumentCode, "a comment is required to reject a revision")
+	ErrInvalidRevisionStatus   = errstack.Sentinel(syserr.InvalidArgumentCode, "invalid template revision status")
+	ErrInvalidTimeZone         = errstack.Sentinel(syserr.InvalidArgumentCode, "invalid time zone")
+	ErrTemplateNotRenderable   = errstack.Sentinel(syserr.ForbiddenCode, "templates of this type cannot be rendered through the API")
+	ErrAssetNotHosted          = errstack.Sentinel(syserr.InvalidArgumentCode, "template references an asset that is not hosted")
+	ErrInvalidVariableName     = errstack.Sentinel(syserr.InvalidArgumentCode, "variable names are a letter followed by letters, digits and underscores, 100 at most, and not theme")
+	ErrOrganizerNotFound       = errstack.Sentinel(syserr.NotFoundCode, "organizer not found")
+	ErrVariableDefaultNotFound = errstack.Sentinel(syserr.NotFoundCode, "default variable not found")
 )
@@ -3,7 +3,7 @@ package domain
 import (
 	"context"
 
-	"github.com/duongptryu/gox/pagination"
+	"tixgo/shared/listing"
 )
 
 // TemplateRepository defines the interface for template persistence
@@ -18,24 +18,50 @@ type TemplateRepository interface {
 	GetBySlug(ctx context.Context, slug string) (*Template, error)
 
 	// List retrieves templates with pagination and filters
-	List(ctx context.Context, filters ListTemplateFilters, paging *pagination.Paging) ([]*Template, error)
+	List(ctx context.Context, filters ListTemplateFilters, paging *listing.Paging) ([]*Template, error)
+
+	// ListSummaries retrieves the summaries of templates with pagination and filters, in List order
+	ListSummaries(ctx context.Context, filters ListTemplateFilters, paging *listing.Paging) ([]*TemplateSummary, error)
+
+	// Stream calls fn for the summary of every template matching filters, in list order, as rows are read
+	Stream(ctx context.Context, filters ListTemplateFilters, fn func(summary *TemplateSummary) error) error
 
 	// Update updates an existing template
 	Update(ctx context.Context, template *Template) error
 
+	// ApplyRevision saves template, with the approved revision applied, and the review of revision at once:
+	// ErrRevisionNotPending when the revision was reviewed meanwhile, ErrRevisionStale when the content
+	// of the template changed since the revision was written, and nothing is saved then
+	ApplyRevision(ctx context.Context, template *Template, revision *TemplateRevision) error
+
 	// Delete deletes a template by ID
 	Delete(ctx context.Context, id int64) error
 }
 
 // TemplateRenderer defines the interface for template rendering
 type TemplateRenderer interface {
-	// Render renders a template with given variables
-	Render(ctx context.Context, template *Template, variables map[string]interface{}) (*RenderedTemplate, error)
+	// Render renders a template with given variables, localized by options
+	Render(ctx context.Context, template *Template, variables map[string]interface{}, options RenderOptions) (*RenderedTemplate, error)
+
+	// Compile parses a template once so it can be rendered many times
+	Compile(ctx context.Context, template *Template) (CompiledTemplate, error)
 
-	// ValidateTemplate validates template syntax
+	// ValidateTemplate validates template syntax and that the assets it references are hosted
 	ValidateTemplate(ctx context.Context, content string) error
 }
 
+// AssetResolver resolves the names of the hosted images templates reference with the asset function
+type AssetResolver interface {
+	// URLs returns the URLs of the assets of the names, leaving out the unknown ones
+	URLs(ctx context.Context, names []string) (map[string]string, error)
+}
+
+// CompiledTemplate is a parsed template, safe to render concurrently
+type CompiledTemplate interface {
+	// Execute renders the template with given variables, localized by options
+	Execute(variables map[string]interface{}, options RenderOptions) (*RenderedTemplate, error)
+}
+
 // ListTemplateFilters represents filters for listing templates
 type ListTemplateFilters struct {
 	Type      *TemplateType
@@ -44,6 +70,27 @@ type ListTemplateFilters struct {
 	Search    string
 }
 
+// RenderOptions localize a render for its recipient: the formatDate, formatTime and formatMoney
+// template functions follow them. The zero value renders in American English and UTC.
+type RenderOptions struct {
+	// Locale is a BCP 47 tag like "vi-VN", or an Accept-Language header listing several
+	Locale string
+
+	// TimeZone is an IANA time zone name like "Asia/Ho_Chi_Minh"
+	TimeZone string
+
+	// OrganizerID brands an attendee-facing render with the theme of the organizer, resolved by Render.
+	// Zero renders with the platform theme.
+	OrganizerID int64
+
+	// Theme brands the render in place of the theme of OrganizerID, e.g. to preview a theme not saved yet
+	Theme *Theme
+
+	// Defaults override the platform defaults for the render, those of the platform and of OrganizerID
+	// resolved by Render when nil. The variables of the caller override both.
+	Defaults map[string]string
+}
+
 // RenderedTemplate represents a rendered template result
 type RenderedTemplate struct {
 	Subject     string
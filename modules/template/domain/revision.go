@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"tixgo/shared/listing"
+)
+
+// RevisionStatus represents the review status of a template revision
+type RevisionStatus string
+
+const (
+	RevisionStatusPendingReview RevisionStatus = "pending_review"
+	RevisionStatusApproved      RevisionStatus = "approved"
+	RevisionStatusRejected      RevisionStatus = "rejected"
+	// RevisionStatusSuperseded revisions were replaced by a newer submission before their review
+	RevisionStatusSuperseded RevisionStatus = "superseded"
+)
+
+// IsValidRevisionStatus checks if the revision status is valid
+func IsValidRevisionStatus(status string) bool {
+	switch RevisionStatus(status) {
+	case RevisionStatusPendingReview, RevisionStatusApproved, RevisionStatusRejected, RevisionStatusSuperseded:
+		return true
+	default:
+		return false
+	}
+}
+
+// TemplateRevision is a version of a template submitted by a non-admin, waiting for an admin review
+type TemplateRevision struct {
+	ID           int64
+	TemplateID   int64
+	TemplateSlug string
+	Name         string
+	Subject      string
+	Content      string
+	Variables    []string
+	Description  string
+	// BaseVersion is the content version of the template the revision was written against
+	BaseVersion   int
+	Status        RevisionStatus
+	SubmittedBy   int64
+	ReviewedBy    *int64
+	ReviewComment string
+	CreatedAt     time.Time
+	ReviewedAt    *time.Time
+}
+
+// NewTemplateRevision submits the content of template for review
+func NewTemplateRevision(template *Template, submittedBy int64) *TemplateRevision {
+	return &TemplateRevision{
+		TemplateID:   template.ID,
+		TemplateSlug: template.Slug,
+		Name:         template.Name,
+		Subject:      template.Subject,
+		Content:      template.Content,
+		Variables:    template.Variables,
+		Description:  template.Description,
+		BaseVersion:  template.ContentVersion,
+		Status:       RevisionStatusPendingReview,
+		SubmittedBy:  submittedBy,
+		CreatedAt:    time.Now(),
+	}
+}
+
+// Approve records the approval of the revision by an admin
+func (r *TemplateRevision) Approve(reviewerID int64, comment string) error {
+	return r.review(RevisionStatusApproved, reviewerID, comment)
+}
+
+// Reject records the rejection of the revision by an admin, who must tell the author why
+func (r *TemplateRevision) Reject(reviewerID int64, comment string) error {
+	if comment == "" {
+		return ErrReviewCommentRequired
+	}
+	return r.review(RevisionStatusRejected, reviewerID, comment)
+}
+
+func (r *TemplateRevision) review(status RevisionStatus, reviewerID int64, comment string) error {
+	if r.Status != RevisionStatusPendingReview {
+		return ErrRevisionNotPending
+	}
+
+	now := time.Now()
+	r.Status = status
+	r.ReviewedBy = &reviewerID
+	r.ReviewComment = comment
+	r.ReviewedAt = &now
+	return nil
+}
+
+// EventTemplateReviewed is published when an admin approves or rejects a revision
+type EventTemplateReviewed struct {
+	RevisionID   int64          `json:"revision_id"`
+	TemplateID   int64          `json:"template_id"`
+	TemplateSlug string         `json:"template_slug"`
+	AuthorID     int64          `json:"author_id"`
+	Status       RevisionStatus `json:"status"`
+	Comment      string         `json:"comment"`
+	OccurredAt   time.Time      `json:"occurred_at"`
+}
+
+// NewEventTemplateReviewed creates the event of a reviewed revision
+func NewEventTemplateReviewed(revision *TemplateRevision) *EventTemplateReviewed {
+	return &EventTemplateReviewed{
+		RevisionID:   revision.ID,
+		TemplateID:   revision.TemplateID,
+		TemplateSlug: revision.TemplateSlug,
+		AuthorID:     revision.SubmittedBy,
+		Status:       revision.Status,
+		Comment:      revision.ReviewComment,
+		OccurredAt:   time.Now(),
+	}
+}
+
+// ListRevisionFilters represents filters for listing template revisions
+type ListRevisionFilters struct {
+	TemplateID *int64
+	Status     *RevisionStatus
+}
+
+// TemplateRevisionRepository defines the interface for template revision persistence
+type TemplateRevisionRepository interface {
+	// Submit stores a revision pending review, superseding the pending revision of its template if any
+	Submit(ctx context.Context, revision *TemplateRevision) error
+
+	// GetByID retrieves a revision by ID
+	GetByID(ctx context.Context, id int64) (*TemplateRevision, error)
+
+	// List retrieves revisions with pagination and filters, most recent first
+	List(ctx context.Context, filters ListRevisionFilters, paging *listing.Paging) ([]*TemplateRevision, error)
+
+	// SaveReview records the review of a revision still pending, ErrRevisionNotPending otherwise
+	SaveReview(ctx context.Context, revision *TemplateRevision) error
+}
@@ -1,8 +1,11 @@
 package domain
 
 import (
+	"strings"
 	"time"
 
+	"tixgo/shared/htmlsanitize"
+
 	"github.com/duongptryu/gox/syserr"
 )
 
@@ -35,6 +38,27 @@ type Template struct {
 	Status      TemplateStatus
 	Variables   []string
 	Description string
+	// Approved is false until an admin approves the first revision of a template created by a non-admin
+	Approved bool
+	// ContentVersion counts the content changes of the template, the revisions written against an
+	// older one are stale
+	ContentVersion int
+	CreatedBy      int64
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// TemplateSummary is the projection of a template listings work with, leaving out its content
+// and variables so listing large HTML templates reads no more than the metadata
+type TemplateSummary struct {
+	ID          int64
+	Name        string
+	Slug        string
+	Subject     string
+	Type        TemplateType
+	Status      TemplateStatus
+	Description string
+	Approved    bool
 	CreatedBy   int64
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
@@ -65,16 +89,21 @@ func NewTemplate(name, slug, subject, content string, templateType TemplateType,
 		Status:      TemplateStatusDraft,
 		Variables:   variables,
 		Description: description,
+		Approved:    true,
 		CreatedBy:   createdBy,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}, nil
 }
 
-// Activate sets the template status to active
-func (t *Template) Activate() {
+// Activate sets the template status to active. Only approved content can go live.
+func (t *Template) Activate() error {
+	if !t.Approved {
+		return ErrTemplateNotApproved
+	}
 	t.Status = TemplateStatusActive
 	t.UpdatedAt = time.Now()
+	return nil
 }
 
 // Deactivate sets the template status to inactive
@@ -103,6 +132,24 @@ func (t *Template) Update(name, subject, content, description string, variables
 	t.UpdatedAt = time.Now()
 }
 
+// ApplyRevision replaces the content of the template with an approved revision. A revision written
+// against an older content version is stale: applying it would undo the changes made since.
+func (t *Template) ApplyRevision(revision *TemplateRevision) error {
+	if revision.BaseVersion != t.ContentVersion {
+		return ErrRevisionStale
+	}
+
+	t.Name = revision.Name
+	t.Subject = revision.Subject
+	t.Content = revision.Content
+	t.Variables = revision.Variables
+	t.Description = revision.Description
+	t.Approved = true
+	t.ContentVersion++
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
 // IsActive checks if the template is active
 func (t *Template) IsActive() bool {
 	return t.Status == TemplateStatusActive
@@ -117,3 +164,17 @@ func IsValidTemplateType(templateType string) bool {
 		return false
 	}
 }
+
+// CheckContent checks the content of an email template against the sanitization policy, so its author
+// cannot get markup mail clients would run into the mails of the platform. Other types are plain text.
+func CheckContent(templateType TemplateType, content string) error {
+	if templateType != TemplateTypeEmail {
+		return nil
+	}
+
+	violations := htmlsanitize.Check(content)
+	if len(violations) == 0 {
+		return nil
+	}
+	return syserr.New(syserr.InvalidArgumentCode, "the template uses HTML that is not allowed: "+strings.Join(violations, ", "))
+}
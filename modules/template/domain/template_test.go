@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplate_ApplyRevision(t *testing.T) {
+	template := &Template{ID: 1, Name: "Welcome", Content: "<p>Hi</p>", ContentVersion: 3}
+
+	proposed := *template
+	proposed.Update("", "", "<p>Hello</p>", "", nil)
+	revision := NewTemplateRevision(&proposed, 9)
+	assert.Equal(t, 3, revision.BaseVersion)
+
+	require.NoError(t, template.ApplyRevision(revision))
+	assert.Equal(t, "<p>Hello</p>", template.Content)
+	assert.True(t, template.Approved)
+	assert.Equal(t, 4, template.ContentVersion)
+}
+
+func TestTemplate_ApplyRevision_Stale(t *testing.T) {
+	template := &Template{ID: 1, Content: "<p>Hi</p>", ContentVersion: 3}
+	older := NewTemplateRevision(&Template{ID: 1, Content: "<p>Old</p>", ContentVersion: 2}, 9)
+
+	assert.ErrorIs(t, template.ApplyRevision(older), ErrRevisionStale)
+	assert.Equal(t, "<p>Hi</p>", template.Content, "a stale revision would undo the newer content")
+	assert.Equal(t, 3, template.ContentVersion)
+}
@@ -0,0 +1,62 @@
+package domain
+
+import "context"
+
+// ThemeVariable is the variable every render brands its template with, e.g. {{.theme.primary_color}}
+const ThemeVariable = "theme"
+
+// Theme is the branding of the tickets and mails an organizer sends their attendees. An empty field
+// falls back to the platform theme.
+type Theme struct {
+	PrimaryColor string
+	AccentColor  string
+	// LogoURL is an https URL, empty for no logo
+	LogoURL    string
+	FooterText string
+}
+
+// PlatformTheme brands the mails of the platform itself and those of organizers without a theme
+var PlatformTheme = Theme{
+	PrimaryColor: "#1f2937",
+	AccentColor:  "#4f46e5",
+	FooterText:   "Sent by TixGo. You receive this email because you have tickets for this event.",
+}
+
+// WithDefaults returns the theme with its empty fields taken from the platform theme. A nil theme is the
+// platform theme.
+func (t *Theme) WithDefaults() Theme {
+	if t == nil {
+		return PlatformTheme
+	}
+
+	theme := *t
+	if theme.PrimaryColor == "" {
+		theme.PrimaryColor = PlatformTheme.PrimaryColor
+	}
+	if theme.AccentColor == "" {
+		theme.AccentColor = PlatformTheme.AccentColor
+	}
+	if theme.LogoURL == "" {
+		theme.LogoURL = PlatformTheme.LogoURL
+	}
+	if theme.FooterText == "" {
+		theme.FooterText = PlatformTheme.FooterText
+	}
+	return theme
+}
+
+// Variables returns the theme as the value of ThemeVariable
+func (t Theme) Variables() map[string]interface{} {
+	return map[string]interface{}{
+		"primary_color": t.PrimaryColor,
+		"accent_color":  t.AccentColor,
+		"logo_url":      t.LogoURL,
+		"footer_text":   t.FooterText,
+	}
+}
+
+// ThemeResolver finds the theme attendee-facing templates of an organizer render with
+type ThemeResolver interface {
+	// ResolveTheme returns the theme of the organizer, or nil for the platform theme
+	ResolveTheme(ctx context.Context, organizerID int64) (*Theme, error)
+}
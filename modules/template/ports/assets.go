@@ -0,0 +1,80 @@
+package ports
+
+import (
+	"net/http"
+
+	"tixgo/components"
+	"tixgo/modules/template/app/command"
+	"tixgo/modules/template/app/query"
+	"tixgo/shared/assets"
+	"tixgo/shared/httpresponse"
+
+	"github.com/duongptryu/gox/context"
+	"github.com/duongptryu/gox/syserr"
+
+	"github.com/gin-gonic/gin"
+)
+
+// assetUploadOverhead is what a multipart request adds around the file of an asset
+const assetUploadOverhead = 1 << 20
+
+func UploadAsset(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, assets.MaxSize+assetUploadOverhead)
+
+		var req command.UploadAssetCommand
+		if err := c.ShouldBind(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			c.Error(syserr.Wrap(err, syserr.InvalidArgumentCode, "an image file is required"))
+			return
+		}
+		if fileHeader.Size > assets.MaxSize {
+			c.Error(assets.ErrTooLarge)
+			return
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.Error(syserr.Wrap(err, syserr.InternalCode, "failed to read the image file"))
+			return
+		}
+		defer file.Close()
+
+		userID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.UploadedBy = userID
+		req.Content = file
+
+		handler := command.NewUploadAssetHandler(appCtx.GetAssetService())
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusCreated, result)
+	}
+}
+
+func ListAssets(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		handler := query.NewListAssetsHandler(appCtx.GetAssetService())
+
+		result, err := handler.Handle(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
@@ -0,0 +1,42 @@
+package ports
+
+import (
+	"context"
+
+	"tixgo/components"
+	"tixgo/modules/template/adapters"
+	templateEvent "tixgo/modules/template/app/event"
+	"tixgo/modules/template/domain"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/duongptryu/gox/messaging"
+)
+
+const (
+	EventTemplateReviewed = "events.EventTemplateReviewed"
+)
+
+type TemplateMessagingHandlers struct {
+	dispatcher messaging.Dispatcher
+	appCtx     components.AppContext
+}
+
+func NewTemplateMessagingHandlers(dispatcher messaging.Dispatcher, appCtx components.AppContext) *TemplateMessagingHandlers {
+	return &TemplateMessagingHandlers{
+		dispatcher: dispatcher,
+		appCtx:     appCtx,
+	}
+}
+
+func (h *TemplateMessagingHandlers) RegisterTemplateMessagingHandlers() {
+	eventProcessor := h.dispatcher.GetEventProcessor()
+	eventProcessor.AddHandler(cqrs.NewEventHandler(EventTemplateReviewed, h.HandleEventTemplateReviewed))
+}
+
+func (h *TemplateMessagingHandlers) HandleEventTemplateReviewed(ctx context.Context, event *domain.EventTemplateReviewed) error {
+	templateRepo := adapters.NewCachedTemplateRepository(adapters.NewTemplatePostgresRepository(h.appCtx.GetDB()), h.appCtx.GetCache())
+	templateRenderer := adapters.NewHTMLTemplateRenderer(h.appCtx.GetAssetService(), nil, adapters.NewVariableDefaultPostgresRepository(h.appCtx.GetDB()))
+	biz := templateEvent.NewNotifyRevisionAuthor(templateRepo, templateRenderer, h.appCtx.GetReliableEventBus())
+
+	return biz.Notify(ctx, event)
+}
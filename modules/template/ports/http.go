@@ -3,33 +3,127 @@ package ports
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"tixgo/components"
+	"tixgo/config"
 	"tixgo/modules/template/adapters"
 	"tixgo/modules/template/app/command"
 	"tixgo/modules/template/app/query"
-
-	"github.com/duongptryu/gox/pagination"
-	"github.com/duongptryu/gox/response"
+	"tixgo/modules/template/domain"
+	userDomain "tixgo/modules/user/domain"
+	"tixgo/shared/apiversion"
+	"tixgo/shared/authz"
+	"tixgo/shared/dryrun"
+	"tixgo/shared/httpresponse"
+	"tixgo/shared/listing"
+	"tixgo/shared/ratelimit"
+	"tixgo/shared/session"
+	"tixgo/shared/stream"
+	"tixgo/shared/strictjson"
+
+	"github.com/duongptryu/gox/context"
 
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterTemplateRoutes(router *gin.RouterGroup, appCtx components.AppContext) {
+// v1 answered template writes with true, the written template is returned from v2 on
+var (
+	createTemplateShims = apiversion.Shims[*command.CreateTemplateResult]{
+		apiversion.V1: func(*command.CreateTemplateResult) any { return true },
+	}
+	updateTemplateShims = apiversion.Shims[*command.UpdateTemplateResult]{
+		apiversion.V1: func(*command.UpdateTemplateResult) any { return true },
+	}
+)
+
+// DefaultRenderLimit is the quota of every caller across the render endpoints when none is configured
+var DefaultRenderLimit = ratelimit.Limit{Rate: 60, Per: time.Minute, Burst: 10}
+
+// RenderPolicy restricts what non-admins render and how often
+type RenderPolicy struct {
+	// PublicTypes are the template types non-admins may render
+	PublicTypes []domain.TemplateType
+	// Limit is the quota of every caller across the render endpoints
+	Limit ratelimit.Limit
+}
+
+// ConfigureDefaults sets the default variables of the environment every render has, failing on a name
+// templates cannot write
+func ConfigureDefaults(cfg config.Templates) error {
+	defaults := make(map[string]string, len(cfg.Defaults))
+	for _, variable := range cfg.Defaults {
+		defaults[variable.Name] = variable.Value
+	}
+	return domain.SetPlatformDefaults(defaults)
+}
+
+// types returns the template types the caller of the request may render, every type for admins
+func (p RenderPolicy) types(c *gin.Context) []domain.TemplateType {
+	if context.GetUserTypeFromContext(c.Request.Context()) == string(userDomain.UserTypeAdmin) {
+		return nil
+	}
+	return append([]domain.TemplateType{}, p.PublicTypes...)
+}
+
+func RegisterTemplateRoutes(router *apiversion.Group, appCtx components.AppContext, render RenderPolicy) {
 	templateGroup := router.Group("/templates")
 	{
-		// Public endpoints for rendering templates
-		templateGroup.POST("/render", RenderTemplate(appCtx))
+		// Public endpoints
 		templateGroup.GET("/by-slug/:slug", GetTemplateBySlug(appCtx))
 
 		// Protected endpoints requiring authentication
-		// templateGroup.Use(middleware.RequireAuth(appCtx.GetJWTService()))
-		templateGroup.POST("", CreateTemplate(appCtx))
+		templateGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		renderLimit := ratelimit.PerCaller(ratelimit.NewRedisLimiter(appCtx.GetRedis()), "templates.render", render.Limit)
+		templateGroup.POST("/render", renderLimit, RenderTemplate(appCtx, render))
+		templateGroup.POST("/render-batch", renderLimit, RenderBatch(appCtx, render))
+		templateGroup.POST("", strictjson.Require(), CreateTemplate(appCtx))
 		templateGroup.GET("", ListTemplates(appCtx))
 		templateGroup.GET("/:id", GetTemplate(appCtx))
-		templateGroup.PUT("/:id", UpdateTemplate(appCtx))
+		templateGroup.PUT("/:id", strictjson.Require(), UpdateTemplate(appCtx))
 		templateGroup.DELETE("/:id", DeleteTemplate(appCtx))
 	}
+
+	// Review of the template edits of non-admins
+	revisionGroup := router.Group("/template-revisions")
+	{
+		revisionGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		revisionGroup.Use(authz.RequireUserType(string(userDomain.UserTypeAdmin)))
+		revisionGroup.GET("", ListTemplateRevisions(appCtx))
+		revisionGroup.GET("/:id", GetTemplateRevision(appCtx))
+		revisionGroup.POST("/:id/approve", ReviewTemplateRevision(appCtx, true))
+		revisionGroup.POST("/:id/reject", ReviewTemplateRevision(appCtx, false))
+	}
+
+	// Images templates reference with the asset function, uploaded by admins since their names are
+	// shared by every template
+	assetGroup := router.Group("/template-assets")
+	{
+		assetGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		assetGroup.GET("", ListAssets(appCtx))
+		assetGroup.POST("", authz.RequireUserType(string(userDomain.UserTypeAdmin)), UploadAsset(appCtx))
+	}
+
+	// Overrides of the default variables of the configuration, for the platform or an organizer
+	defaultGroup := router.Group("/template-defaults")
+	{
+		defaultGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		defaultGroup.Use(authz.RequireUserType(string(userDomain.UserTypeAdmin)))
+		defaultGroup.GET("", ListVariableDefaults(appCtx))
+		defaultGroup.PUT("/:name", SaveVariableDefault(appCtx))
+		defaultGroup.DELETE("/:name", DeleteVariableDefault(appCtx))
+	}
+}
+
+// RegisterTemplateDebugRoutes serves admins the draft previews, only registered when
+// config.Debug.TemplatePreviews is on
+func RegisterTemplateDebugRoutes(router *apiversion.Group, appCtx components.AppContext) {
+	debugGroup := router.Group("/debug/templates")
+	{
+		debugGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		debugGroup.Use(authz.RequireUserType(string(userDomain.UserTypeAdmin)))
+		debugGroup.GET("/drafts", PreviewDrafts(appCtx))
+	}
 }
 
 func CreateTemplate(appCtx components.AppContext) gin.HandlerFunc {
@@ -41,25 +135,32 @@ func CreateTemplate(appCtx components.AppContext) gin.HandlerFunc {
 		}
 
 		// Get user ID from context
-		// userID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
-		// if err != nil {
-		// 	c.Error(err)
-		// 	return
-		// }
-		req.CreatedBy = -1
+		userID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.CreatedBy = userID
+		req.IsAdmin = context.GetUserTypeFromContext(c.Request.Context()) == string(userDomain.UserTypeAdmin)
 
-		templateRepo := adapters.NewTemplatePostgresRepository(appCtx.GetDB())
-		templateRenderer := adapters.NewHTMLTemplateRenderer()
+		templateRepo := adapters.NewCachedTemplateRepository(adapters.NewTemplatePostgresRepository(appCtx.GetDB()), appCtx.GetCache())
+		revisionRepo := adapters.NewTemplateRevisionPostgresRepository(appCtx.GetDB())
+		templateRenderer := adapters.NewHTMLTemplateRenderer(appCtx.GetAssetService(), nil, adapters.NewVariableDefaultPostgresRepository(appCtx.GetDB()))
 
-		handler := command.NewCreateTemplateHandler(templateRepo, templateRenderer)
+		handler := command.NewCreateTemplateHandler(templateRepo, revisionRepo, templateRenderer)
 
-		err := handler.Handle(c.Request.Context(), req)
+		result, err := handler.Handle(c.Request.Context(), req)
 		if err != nil {
 			c.Error(err)
 			return
 		}
 
-		c.JSON(http.StatusCreated, response.NewSimpleSuccessResponse(true))
+		if dryrun.IsDryRun(c.Request.Context()) {
+			httpresponse.Success(c, http.StatusOK, result)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusCreated, apiversion.Render(c.Request.Context(), result, createTemplateShims))
 	}
 }
 
@@ -80,18 +181,32 @@ func UpdateTemplate(appCtx components.AppContext) gin.HandlerFunc {
 		}
 		req.ID = id
 
-		templateRepo := adapters.NewTemplatePostgresRepository(appCtx.GetDB())
-		templateRenderer := adapters.NewHTMLTemplateRenderer()
+		userID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.UpdatedBy = userID
+		req.IsAdmin = context.GetUserTypeFromContext(c.Request.Context()) == string(userDomain.UserTypeAdmin)
 
-		handler := command.NewUpdateTemplateHandler(templateRepo, templateRenderer)
+		templateRepo := adapters.NewCachedTemplateRepository(adapters.NewTemplatePostgresRepository(appCtx.GetDB()), appCtx.GetCache())
+		revisionRepo := adapters.NewTemplateRevisionPostgresRepository(appCtx.GetDB())
+		templateRenderer := adapters.NewHTMLTemplateRenderer(appCtx.GetAssetService(), nil, adapters.NewVariableDefaultPostgresRepository(appCtx.GetDB()))
 
-		err = handler.Handle(c.Request.Context(), req)
+		handler := command.NewUpdateTemplateHandler(templateRepo, revisionRepo, templateRenderer)
+
+		result, err := handler.Handle(c.Request.Context(), req)
 		if err != nil {
 			c.Error(err)
 			return
 		}
 
-		c.JSON(http.StatusOK, response.NewSimpleSuccessResponse(true))
+		if dryrun.IsDryRun(c.Request.Context()) {
+			httpresponse.Success(c, http.StatusOK, result)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, apiversion.Render(c.Request.Context(), result, updateTemplateShims))
 	}
 }
 
@@ -105,7 +220,7 @@ func GetTemplate(appCtx components.AppContext) gin.HandlerFunc {
 			return
 		}
 
-		templateRepo := adapters.NewTemplatePostgresRepository(appCtx.GetDB())
+		templateRepo := adapters.NewCachedTemplateRepository(adapters.NewTemplatePostgresRepository(appCtx.GetDB()), appCtx.GetCache())
 		handler := query.NewGetTemplateHandler(templateRepo)
 
 		result, err := handler.Handle(c.Request.Context(), query.GetTemplateQuery{
@@ -116,7 +231,7 @@ func GetTemplate(appCtx components.AppContext) gin.HandlerFunc {
 			return
 		}
 
-		c.JSON(http.StatusOK, response.NewSimpleSuccessResponse(result))
+		httpresponse.Success(c, http.StatusOK, result)
 	}
 }
 
@@ -124,7 +239,7 @@ func GetTemplateBySlug(appCtx components.AppContext) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		slug := c.Param("slug")
 
-		templateRepo := adapters.NewTemplatePostgresRepository(appCtx.GetDB())
+		templateRepo := adapters.NewCachedTemplateRepository(adapters.NewTemplatePostgresRepository(appCtx.GetDB()), appCtx.GetCache())
 		handler := query.NewGetTemplateHandler(templateRepo)
 
 		result, err := handler.Handle(c.Request.Context(), query.GetTemplateQuery{
@@ -135,7 +250,7 @@ func GetTemplateBySlug(appCtx components.AppContext) gin.HandlerFunc {
 			return
 		}
 
-		c.JSON(http.StatusOK, response.NewSimpleSuccessResponse(result))
+		httpresponse.Success(c, http.StatusOK, result)
 	}
 }
 
@@ -148,8 +263,21 @@ func ListTemplates(appCtx components.AppContext) gin.HandlerFunc {
 			return
 		}
 
+		templateRepo := adapters.NewCachedTemplateRepository(adapters.NewTemplatePostgresRepository(appCtx.GetDB()), appCtx.GetCache())
+		handler := query.NewListTemplatesHandler(templateRepo)
+
+		// Stream every matching template as NDJSON or CSV when asked for, ignoring paging
+		if format, ok := stream.Negotiate(c); ok {
+			stream.Write(c, format, "templates", func(encode stream.EncodeFunc) error {
+				return handler.Stream(c.Request.Context(), &filters, func(item query.TemplateListItem) error {
+					return encode(item)
+				})
+			})
+			return
+		}
+
 		// Bind paging separately
-		var paging pagination.Paging
+		var paging listing.Paging
 		if err := c.ShouldBind(&paging); err != nil {
 			c.Error(err)
 			return
@@ -158,8 +286,11 @@ func ListTemplates(appCtx components.AppContext) gin.HandlerFunc {
 		// Apply pagination defaults in HTTP layer
 		paging.Fulfill()
 
-		templateRepo := adapters.NewTemplatePostgresRepository(appCtx.GetDB())
-		handler := query.NewListTemplatesHandler(templateRepo)
+		fields, err := query.ParseTemplateFields(filters.Fields)
+		if err != nil {
+			c.Error(err)
+			return
+		}
 
 		result, err := handler.Handle(c.Request.Context(), &filters, &paging)
 		if err != nil {
@@ -167,20 +298,29 @@ func ListTemplates(appCtx components.AppContext) gin.HandlerFunc {
 			return
 		}
 
-		c.JSON(http.StatusOK, response.NewSuccessResponse(result, paging, filters))
+		if len(fields) > 0 {
+			httpresponse.List(c, query.SelectTemplateFields(result, fields), paging, filters)
+			return
+		}
+
+		httpresponse.List(c, result, paging, filters)
 	}
 }
 
-func RenderTemplate(appCtx components.AppContext) gin.HandlerFunc {
+func RenderTemplate(appCtx components.AppContext, render RenderPolicy) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req query.RenderTemplateQuery
 		if err := c.ShouldBindJSON(&req); err != nil {
 			c.Error(err)
 			return
 		}
+		if req.Locale == "" {
+			req.Locale = c.GetHeader("Accept-Language")
+		}
+		req.Types = render.types(c)
 
-		templateRepo := adapters.NewTemplatePostgresRepository(appCtx.GetDB())
-		templateRenderer := adapters.NewHTMLTemplateRenderer()
+		templateRepo := adapters.NewCachedTemplateRepository(adapters.NewTemplatePostgresRepository(appCtx.GetDB()), appCtx.GetCache())
+		templateRenderer := adapters.NewHTMLTemplateRenderer(appCtx.GetAssetService(), nil, adapters.NewVariableDefaultPostgresRepository(appCtx.GetDB()))
 
 		handler := query.NewRenderTemplateHandler(templateRepo, templateRenderer)
 
@@ -190,7 +330,32 @@ func RenderTemplate(appCtx components.AppContext) gin.HandlerFunc {
 			return
 		}
 
-		c.JSON(http.StatusOK, response.NewSimpleSuccessResponse(result))
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func RenderBatch(appCtx components.AppContext, render RenderPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req query.RenderBatchQuery
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+		req.Types = render.types(c)
+
+		templateRepo := adapters.NewCachedTemplateRepository(adapters.NewTemplatePostgresRepository(appCtx.GetDB()), appCtx.GetCache())
+		defaults := adapters.NewVariableDefaultPostgresRepository(appCtx.GetDB())
+		templateRenderer := adapters.NewHTMLTemplateRenderer(appCtx.GetAssetService(), nil, defaults)
+
+		handler := query.NewRenderBatchHandler(templateRepo, templateRenderer, defaults)
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
 	}
 }
 
@@ -204,7 +369,7 @@ func DeleteTemplate(appCtx components.AppContext) gin.HandlerFunc {
 			return
 		}
 
-		templateRepo := adapters.NewTemplatePostgresRepository(appCtx.GetDB())
+		templateRepo := adapters.NewCachedTemplateRepository(adapters.NewTemplatePostgresRepository(appCtx.GetDB()), appCtx.GetCache())
 
 		err = templateRepo.Delete(c.Request.Context(), id)
 		if err != nil {
@@ -212,6 +377,192 @@ func DeleteTemplate(appCtx components.AppContext) gin.HandlerFunc {
 			return
 		}
 
-		c.JSON(http.StatusOK, response.NewSimpleSuccessResponse(true))
+		httpresponse.Success(c, http.StatusOK, true)
+	}
+}
+
+func ListTemplateRevisions(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var filters query.FilterTemplateRevisionsQuery
+		if err := c.ShouldBind(&filters); err != nil {
+			c.Error(err)
+			return
+		}
+
+		var paging listing.Paging
+		if err := c.ShouldBind(&paging); err != nil {
+			c.Error(err)
+			return
+		}
+
+		// Apply pagination defaults in HTTP layer
+		paging.Fulfill()
+
+		handler := query.NewListTemplateRevisionsHandler(adapters.NewTemplateRevisionPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), &filters, &paging)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.List(c, result, paging, filters)
+	}
+}
+
+func GetTemplateRevision(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		handler := query.NewGetTemplateRevisionHandler(adapters.NewTemplateRevisionPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), query.GetTemplateRevisionQuery{ID: id})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func ReviewTemplateRevision(appCtx components.AppContext, approve bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.ReviewTemplateRevisionCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.RevisionID = id
+		req.Approve = approve
+
+		reviewerID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.ReviewerID = reviewerID
+
+		templateRepo := adapters.NewCachedTemplateRepository(adapters.NewTemplatePostgresRepository(appCtx.GetDB()), appCtx.GetCache())
+		revisionRepo := adapters.NewTemplateRevisionPostgresRepository(appCtx.GetDB())
+
+		handler := command.NewReviewTemplateRevisionHandler(templateRepo, revisionRepo, appCtx.GetReliableEventBus())
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func PreviewDrafts(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req query.PreviewDraftsQuery
+		if err := c.ShouldBind(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		var paging listing.Paging
+		if err := c.ShouldBind(&paging); err != nil {
+			c.Error(err)
+			return
+		}
+		paging.Fulfill()
+
+		templateRepo := adapters.NewTemplatePostgresRepository(appCtx.GetDB())
+		templateRenderer := adapters.NewHTMLTemplateRenderer(appCtx.GetAssetService(), nil, adapters.NewVariableDefaultPostgresRepository(appCtx.GetDB()))
+
+		handler := query.NewPreviewDraftsHandler(templateRepo, templateRenderer)
+
+		result, err := handler.Handle(c.Request.Context(), &req, &paging)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.List(c, result, paging, req)
+	}
+}
+
+func ListVariableDefaults(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req query.ListVariableDefaultsQuery
+		if err := c.ShouldBind(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		handler := query.NewListVariableDefaultsHandler(adapters.NewVariableDefaultPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func SaveVariableDefault(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.SaveVariableDefaultCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+		req.Name = c.Param("name")
+
+		adminID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.AdminID = adminID
+
+		handler := command.NewSaveVariableDefaultHandler(adapters.NewVariableDefaultPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func DeleteVariableDefault(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.DeleteVariableDefaultCommand
+		if err := c.ShouldBind(&req); err != nil {
+			c.Error(err)
+			return
+		}
+		req.Name = c.Param("name")
+
+		handler := command.NewDeleteVariableDefaultHandler(adapters.NewVariableDefaultPostgresRepository(appCtx.GetDB()))
+
+		err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, true)
 	}
 }
@@ -0,0 +1,30 @@
+package ports
+
+import (
+	"tixgo/modules/template/app/command"
+	"tixgo/modules/template/app/query"
+	"tixgo/shared/jsonschema"
+	"tixgo/shared/listing"
+)
+
+// Schemas returns the request payloads of the template module
+func Schemas() []jsonschema.Payload {
+	return []jsonschema.Payload{
+		{Name: "templates.render", In: jsonschema.Body, Example: query.RenderTemplateQuery{}},
+		{Name: "templates.render-batch", In: jsonschema.Body, Example: query.RenderBatchQuery{}},
+		{Name: "templates.create", In: jsonschema.Body, Example: command.CreateTemplateCommand{}},
+		{Name: "templates.update", In: jsonschema.Body, Example: command.UpdateTemplateCommand{}},
+		{Name: "templates.list", In: jsonschema.Query, Example: struct {
+			query.FilterTemplatesQuery
+			listing.Paging
+		}{}},
+		{Name: "template-revisions.list", In: jsonschema.Query, Example: struct {
+			query.FilterTemplateRevisionsQuery
+			listing.Paging
+		}{}},
+		{Name: "template-revisions.review", In: jsonschema.Body, Example: command.ReviewTemplateRevisionCommand{}},
+		{Name: "template-defaults.list", In: jsonschema.Query, Example: query.ListVariableDefaultsQuery{}},
+		{Name: "template-defaults.save", In: jsonschema.Body, Example: command.SaveVariableDefaultCommand{}},
+		{Name: "template-defaults.delete", In: jsonschema.Query, Example: command.DeleteVariableDefaultCommand{}},
+	}
+}
@@ -0,0 +1,159 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+
+	"tixgo/modules/ticket/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/pgerr"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/lib/pq"
+)
+
+// BindSeats makes the venue seats the seats of a ticket type. The event is locked, so bindings of its
+// ticket types apply one after the other and do not race a cancellation, and the venue seats are locked
+// against a seat map replacement removing them. The seats of the ticket type are tickets: those of the
+// seats bound already are kept, seats copied with their labels from another event are bound to the venue
+// seat they match, and cancelled seats bound again are made available.
+func (r *TicketTypePostgresRepository) BindSeats(ctx context.Context, ticketType *domain.TicketType, venueSeatIDs []int64) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	var venueID sql.NullInt64
+	event := &domain.Event{ID: ticketType.EventID}
+	err = tx.QueryRowContext(ctx, `SELECT venue_id, status FROM events WHERE id = $1 FOR UPDATE`, event.ID).Scan(&venueID, &event.Status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrEventNotFound
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to lock event")
+	}
+	if event.Closed() {
+		return domain.ErrEventClosed
+	}
+	if !venueID.Valid {
+		return domain.ErrEventHasNoVenue
+	}
+
+	seatIDs := pq.Array(venueSeatIDs)
+	var found int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM (SELECT id FROM venue_seats WHERE venue_id = $1 AND id = ANY($2) FOR SHARE) s`,
+		venueID.Int64, seatIDs).Scan(&found)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get venue seats")
+	}
+	if found != len(venueSeatIDs) {
+		return domain.ErrSeatNotInVenue
+	}
+
+	var taken bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM tickets t
+			JOIN ticket_categories c ON c.id = t.ticket_category_id
+			WHERE c.event_id = $1 AND c.id <> $2 AND t.venue_seat_id = ANY($3) AND t.status <> 'cancelled')`,
+		event.ID, ticketType.ID, seatIDs).Scan(&taken)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to check bound seats")
+	}
+	if taken {
+		return domain.ErrSeatTaken
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE tickets t
+		SET venue_seat_id = s.id, updated_at = NOW()
+		FROM venue_seats s
+		WHERE t.ticket_category_id = $1 AND t.venue_seat_id IS NULL AND t.status <> 'cancelled'
+		  AND s.id = ANY($2) AND t.seat_section = s.section AND t.seat_row = s.row_label AND t.seat_number = s.seat_number`,
+		ticketType.ID, seatIDs)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to bind copied seats")
+	}
+
+	// the seats unbound are locked, so they are not held meanwhile
+	rows, err := tx.QueryContext(ctx, `
+		SELECT status
+		FROM tickets
+		WHERE ticket_category_id = $1 AND seat_section IS NOT NULL AND status <> 'cancelled'
+		  AND (venue_seat_id IS NULL OR venue_seat_id <> ALL($2))
+		FOR UPDATE`, ticketType.ID, seatIDs)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to lock unbound seats")
+	}
+	inUse := false
+	for rows.Next() {
+		var status string
+		if err := rows.Scan(&status); err != nil {
+			rows.Close()
+			return syserr.Wrap(err, syserr.InternalCode, "failed to scan seat")
+		}
+		inUse = inUse || status != "available"
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to iterate unbound seats")
+	}
+	if inUse {
+		return domain.ErrSeatInUse
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE tickets
+		SET status = 'cancelled', updated_at = NOW()
+		WHERE ticket_category_id = $1 AND seat_section IS NOT NULL AND status = 'available'
+		  AND (venue_seat_id IS NULL OR venue_seat_id <> ALL($2))`, ticketType.ID, seatIDs)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to unbind seats")
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO tickets (ticket_category_id, ticket_number, seat_section, seat_row, seat_number, venue_seat_id, status)
+		SELECT $1, format('C%s-S%s', $1::BIGINT, s.id), s.section, s.row_label, s.seat_number, s.id, 'available'
+		FROM venue_seats s
+		WHERE s.id = ANY($2)
+		  AND NOT EXISTS (
+		      SELECT 1 FROM tickets t WHERE t.ticket_category_id = $1 AND t.venue_seat_id = s.id AND t.status <> 'cancelled')
+		ON CONFLICT (ticket_category_id, seat_section, seat_row, seat_number) DO UPDATE
+		SET venue_seat_id = EXCLUDED.venue_seat_id, status = 'available', updated_at = NOW()
+		WHERE tickets.status = 'cancelled'`, ticketType.ID, seatIDs)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create seats")
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE ticket_categories
+		SET quantity_available = (
+		        SELECT COUNT(*) FROM tickets
+		        WHERE ticket_category_id = $1 AND seat_section IS NOT NULL AND status <> 'cancelled'),
+		    updated_at = NOW()
+		WHERE id = $1
+		RETURNING quantity_available, quantity_sold, quantity_reserved, quantity_allotted, updated_at`, ticketType.ID,
+	).Scan(&ticketType.Quantity, &ticketType.Sold, &ticketType.Reserved, &ticketType.Allotted, &ticketType.UpdatedAt)
+	if err != nil {
+		if pgerr.Constraint(err) == pgerr.ConstraintTicketInventory {
+			return domain.ErrSeatInUse
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to resize ticket type")
+	}
+	if ticketType.Quantity != len(venueSeatIDs) {
+		// a seat of the ticket type had the labels of a bound one
+		return domain.ErrSeatTaken
+	}
+
+	if err := tx.Commit(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to commit seats")
+	}
+
+	return nil
+}
@@ -0,0 +1,281 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+
+	"tixgo/modules/ticket/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/pgerr"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+// TicketTypePostgresRepository implements the TicketTypeRepository interface using PostgreSQL. Ticket
+// types are the rows of ticket_categories.
+type TicketTypePostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewTicketTypePostgresRepository creates a new PostgreSQL ticket type repository
+func NewTicketTypePostgresRepository(db *sqlx.DB) *TicketTypePostgresRepository {
+	return &TicketTypePostgresRepository{db: db}
+}
+
+const selectTicketType = `
+	SELECT tc.id, tc.event_id, tc.name, COALESCE(tc.description, ''), COALESCE(tc.category_type::TEXT, 'general'),
+	       tc.price::TEXT, COALESCE(tc.max_per_order, 10), tc.sale_start_date, tc.sale_end_date,
+	       COALESCE(tc.is_transferable, TRUE), COALESCE(tc.is_refundable, TRUE), tc.quantity_available,
+	       tc.quantity_sold, tc.quantity_reserved, tc.quantity_allotted, tc.sales_paused, e.currency,
+	       COALESCE(tc.created_at, NOW()), COALESCE(tc.updated_at, tc.created_at, NOW())
+	FROM ticket_categories tc
+	JOIN events e ON e.id = tc.event_id`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// GetEvent retrieves an event of the organizer
+func (r *TicketTypePostgresRepository) GetEvent(ctx context.Context, eventID, organizerID int64) (*domain.Event, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	event := &domain.Event{}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, organizer_id, status, currency
+		FROM events
+		WHERE id = $1 AND organizer_id = $2`, eventID, organizerID).Scan(&event.ID, &event.OrganizerID, &event.Status, &event.Currency)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrEventNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get event")
+	}
+
+	return event, nil
+}
+
+// Create stores a new ticket type. The status guard keeps the creation from racing a cancellation.
+func (r *TicketTypePostgresRepository) Create(ctx context.Context, ticketType *domain.TicketType) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		INSERT INTO ticket_categories (event_id, name, description, category_type, price, quantity_available,
+		                               max_per_order, sale_start_date, sale_end_date, is_transferable, is_refundable)
+		SELECT id, $2, NULLIF($3, ''), $4, $5, $6, $7, $8, $9, $10, $11
+		FROM events
+		WHERE id = $1 AND status NOT IN ('cancelled', 'completed')
+		RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		ticketType.EventID,
+		ticketType.Name,
+		ticketType.Description,
+		ticketType.Kind,
+		ticketType.Price,
+		ticketType.Quantity,
+		ticketType.MaxPerOrder,
+		ticketType.SaleStartDate,
+		ticketType.SaleEndDate,
+		ticketType.IsTransferable,
+		ticketType.IsRefundable,
+	).Scan(&ticketType.ID, &ticketType.CreatedAt, &ticketType.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrEventClosed
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create ticket type")
+	}
+
+	return nil
+}
+
+// GetByID retrieves a ticket type of an event of the organizer
+func (r *TicketTypePostgresRepository) GetByID(ctx context.Context, id, eventID, organizerID int64) (*domain.TicketType, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	ticketType, err := scanTicketType(r.db.QueryRowContext(ctx, selectTicketType+`
+		WHERE tc.id = $1 AND tc.event_id = $2 AND e.organizer_id = $3`, id, eventID, organizerID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrTicketTypeNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get ticket type")
+	}
+
+	return ticketType, nil
+}
+
+// List retrieves the ticket types of an event, cheapest first
+func (r *TicketTypePostgresRepository) List(ctx context.Context, eventID int64) ([]*domain.TicketType, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, selectTicketType+`
+		WHERE tc.event_id = $1
+		ORDER BY tc.price, tc.id`, eventID)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list ticket types")
+	}
+	defer rows.Close()
+
+	return collectTicketTypes(rows)
+}
+
+// ListPublic retrieves the event with the public page at slug and its ticket types, cheapest first
+func (r *TicketTypePostgresRepository) ListPublic(ctx context.Context, slug string) (*domain.Event, []*domain.TicketType, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	event := &domain.Event{}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, organizer_id, status, currency
+		FROM events
+		WHERE slug = $1 AND status <> 'draft'`, slug).Scan(&event.ID, &event.OrganizerID, &event.Status, &event.Currency)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, domain.ErrEventNotFound
+		}
+		return nil, nil, syserr.Wrap(err, syserr.InternalCode, "failed to get event")
+	}
+
+	// The pauses scheduled and not applied yet by the job count as applied
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT tc.id, tc.event_id, tc.name, COALESCE(tc.description, ''), COALESCE(tc.category_type::TEXT, 'general'),
+		       tc.price::TEXT, COALESCE(tc.max_per_order, 10),
+		       COALESCE(tc.sale_start_date, e.sale_start_date), COALESCE(tc.sale_end_date, e.sale_end_date),
+		       COALESCE(tc.is_transferable, TRUE), COALESCE(tc.is_refundable, TRUE), tc.quantity_available,
+		       tc.quantity_sold, tc.quantity_reserved, tc.quantity_allotted,
+		       tc.sales_paused OR e.sales_paused OR COALESCE(tc.sales_pause_at <= NOW(), FALSE)
+		           OR COALESCE(e.sales_pause_at <= NOW(), FALSE), e.currency,
+		       COALESCE(tc.created_at, NOW()), COALESCE(tc.updated_at, tc.created_at, NOW())
+		FROM ticket_categories tc
+		JOIN events e ON e.id = tc.event_id
+		WHERE tc.event_id = $1
+		ORDER BY tc.price, tc.id`, event.ID)
+	if err != nil {
+		return nil, nil, syserr.Wrap(err, syserr.InternalCode, "failed to list ticket types")
+	}
+	defer rows.Close()
+
+	ticketTypes, err := collectTicketTypes(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return event, ticketTypes, nil
+}
+
+// Update saves the details of a ticket type. The status guard keeps an update from racing a cancellation.
+func (r *TicketTypePostgresRepository) Update(ctx context.Context, ticketType *domain.TicketType) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		UPDATE ticket_categories tc
+		SET name = $3, description = NULLIF($4, ''), category_type = $5, price = $6, max_per_order = $7,
+		    sale_start_date = $8, sale_end_date = $9, is_transferable = $10, is_refundable = $11
+		FROM events e
+		WHERE tc.id = $1 AND tc.event_id = $2 AND e.id = tc.event_id AND e.status NOT IN ('cancelled', 'completed')
+		RETURNING tc.updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		ticketType.ID,
+		ticketType.EventID,
+		ticketType.Name,
+		ticketType.Description,
+		ticketType.Kind,
+		ticketType.Price,
+		ticketType.MaxPerOrder,
+		ticketType.SaleStartDate,
+		ticketType.SaleEndDate,
+		ticketType.IsTransferable,
+		ticketType.IsRefundable,
+	).Scan(&ticketType.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrEventClosed
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to update ticket type")
+	}
+
+	return nil
+}
+
+// Delete deletes a ticket type with its seats. The stock guard keeps the deletion from racing a
+// reservation, and the tickets of past orders keep it from deleting what they reference.
+func (r *TicketTypePostgresRepository) Delete(ctx context.Context, ticketType *domain.TicketType) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM ticket_categories
+		WHERE id = $1 AND event_id = $2 AND quantity_sold = 0 AND quantity_reserved = 0 AND quantity_allotted = 0`,
+		ticketType.ID, ticketType.EventID)
+	if err != nil {
+		if pgerr.IsForeignKeyViolation(err) {
+			return domain.ErrTicketTypeInUse
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to delete ticket type")
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to delete ticket type")
+	}
+	if deleted == 0 {
+		return domain.ErrTicketTypeInUse
+	}
+
+	return nil
+}
+
+func collectTicketTypes(rows *sql.Rows) ([]*domain.TicketType, error) {
+	ticketTypes := []*domain.TicketType{}
+	for rows.Next() {
+		ticketType, err := scanTicketType(rows)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan ticket type")
+		}
+		ticketTypes = append(ticketTypes, ticketType)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "error iterating ticket type rows")
+	}
+
+	return ticketTypes, nil
+}
+
+func scanTicketType(row rowScanner) (*domain.TicketType, error) {
+	ticketType := &domain.TicketType{}
+	err := row.Scan(
+		&ticketType.ID,
+		&ticketType.EventID,
+		&ticketType.Name,
+		&ticketType.Description,
+		&ticketType.Kind,
+		&ticketType.Price,
+		&ticketType.MaxPerOrder,
+		&ticketType.SaleStartDate,
+		&ticketType.SaleEndDate,
+		&ticketType.IsTransferable,
+		&ticketType.IsRefundable,
+		&ticketType.Quantity,
+		&ticketType.Sold,
+		&ticketType.Reserved,
+		&ticketType.Allotted,
+		&ticketType.SalesPaused,
+		&ticketType.Currency,
+		&ticketType.CreatedAt,
+		&ticketType.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ticketType, nil
+}
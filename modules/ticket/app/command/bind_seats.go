@@ -0,0 +1,57 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/ticket/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// BindSeatsCommand represents the command of an organizer to sell a ticket type of their event as seats
+// of the seat map of its venue
+type BindSeatsCommand struct {
+	ID           int64   `json:"-"`
+	EventID      int64   `json:"-"`
+	OrganizerID  int64   `json:"-"`
+	VenueSeatIDs []int64 `json:"venue_seat_ids" binding:"required,min=1,max=10000,dive,gt=0"`
+}
+
+// BindSeatsHandler handles binding the seats of ticket types
+type BindSeatsHandler struct {
+	ticketTypeRepo domain.TicketTypeRepository
+}
+
+// NewBindSeatsHandler creates a new bind seats handler
+func NewBindSeatsHandler(ticketTypeRepo domain.TicketTypeRepository) *BindSeatsHandler {
+	return &BindSeatsHandler{
+		ticketTypeRepo: ticketTypeRepo,
+	}
+}
+
+// Handle executes the bind seats command. The seats replace the ones of the ticket type, its quantity
+// becomes their number; the seats held, sold or allotted stay bound.
+func (h *BindSeatsHandler) Handle(ctx context.Context, cmd BindSeatsCommand) (*TicketTypeResult, error) {
+	ticketType, err := h.ticketTypeRepo.GetByID(ctx, cmd.ID, cmd.EventID, cmd.OrganizerID)
+	if err != nil {
+		if err == domain.ErrTicketTypeNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get ticket type")
+	}
+
+	if err := ticketType.CheckSeats(cmd.VenueSeatIDs); err != nil {
+		return nil, err
+	}
+
+	if err := h.ticketTypeRepo.BindSeats(ctx, ticketType, cmd.VenueSeatIDs); err != nil {
+		switch err {
+		case domain.ErrEventNotFound, domain.ErrEventClosed, domain.ErrEventHasNoVenue, domain.ErrSeatNotInVenue,
+			domain.ErrSeatTaken, domain.ErrSeatInUse:
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to bind seats")
+	}
+
+	return ToTicketTypeResult(ticketType), nil
+}
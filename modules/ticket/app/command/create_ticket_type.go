@@ -0,0 +1,86 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/ticket/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// TicketTypeDetailsInput are the details of a ticket type sent to create and update it
+type TicketTypeDetailsInput struct {
+	Name           string     `json:"name" binding:"required,max=100"`
+	Description    string     `json:"description" binding:"max=2000"`
+	Kind           string     `json:"kind" binding:"required"`
+	Price          string     `json:"price" binding:"required"`
+	MaxPerOrder    int        `json:"max_per_order" binding:"required,min=1,max=100"`
+	SaleStartDate  *time.Time `json:"sale_start_date"`
+	SaleEndDate    *time.Time `json:"sale_end_date"`
+	IsTransferable bool       `json:"is_transferable"`
+	IsRefundable   bool       `json:"is_refundable"`
+}
+
+// Details converts the input to the details of a domain ticket type
+func (in TicketTypeDetailsInput) Details() domain.TicketTypeDetails {
+	return domain.TicketTypeDetails{
+		Name:           in.Name,
+		Description:    in.Description,
+		Kind:           domain.Kind(in.Kind),
+		Price:          in.Price,
+		MaxPerOrder:    in.MaxPerOrder,
+		SaleStartDate:  in.SaleStartDate,
+		SaleEndDate:    in.SaleEndDate,
+		IsTransferable: in.IsTransferable,
+		IsRefundable:   in.IsRefundable,
+	}
+}
+
+// CreateTicketTypeCommand represents the command of an organizer to add a ticket type to their event
+type CreateTicketTypeCommand struct {
+	EventID     int64 `json:"-"`
+	OrganizerID int64 `json:"-"`
+	TicketTypeDetailsInput
+	Quantity int `json:"quantity" binding:"required,min=1,max=1000000"`
+}
+
+// CreateTicketTypeHandler handles ticket type creation
+type CreateTicketTypeHandler struct {
+	ticketTypeRepo domain.TicketTypeRepository
+}
+
+// NewCreateTicketTypeHandler creates a new create ticket type handler
+func NewCreateTicketTypeHandler(ticketTypeRepo domain.TicketTypeRepository) *CreateTicketTypeHandler {
+	return &CreateTicketTypeHandler{
+		ticketTypeRepo: ticketTypeRepo,
+	}
+}
+
+// Handle executes the create ticket type command
+func (h *CreateTicketTypeHandler) Handle(ctx context.Context, cmd CreateTicketTypeCommand) (*TicketTypeResult, error) {
+	event, err := h.ticketTypeRepo.GetEvent(ctx, cmd.EventID, cmd.OrganizerID)
+	if err != nil {
+		if err == domain.ErrEventNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get event")
+	}
+	if event.Closed() {
+		return nil, domain.ErrEventClosed
+	}
+
+	ticketType, err := domain.NewTicketType(event, cmd.Details(), cmd.Quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.ticketTypeRepo.Create(ctx, ticketType); err != nil {
+		if err == domain.ErrEventClosed {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to create ticket type")
+	}
+
+	return ToTicketTypeResult(ticketType), nil
+}
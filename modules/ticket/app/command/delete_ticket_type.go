@@ -0,0 +1,53 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/ticket/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// DeleteTicketTypeCommand represents the command of an organizer to delete a ticket type of their event
+type DeleteTicketTypeCommand struct {
+	ID          int64
+	EventID     int64
+	OrganizerID int64
+}
+
+// DeleteTicketTypeHandler handles ticket type deletion
+type DeleteTicketTypeHandler struct {
+	ticketTypeRepo domain.TicketTypeRepository
+}
+
+// NewDeleteTicketTypeHandler creates a new delete ticket type handler
+func NewDeleteTicketTypeHandler(ticketTypeRepo domain.TicketTypeRepository) *DeleteTicketTypeHandler {
+	return &DeleteTicketTypeHandler{
+		ticketTypeRepo: ticketTypeRepo,
+	}
+}
+
+// Handle executes the delete ticket type command. Ticket types with tickets sold, reserved or allotted
+// stay, their sales can be paused instead.
+func (h *DeleteTicketTypeHandler) Handle(ctx context.Context, cmd DeleteTicketTypeCommand) error {
+	ticketType, err := h.ticketTypeRepo.GetByID(ctx, cmd.ID, cmd.EventID, cmd.OrganizerID)
+	if err != nil {
+		if err == domain.ErrTicketTypeNotFound {
+			return err
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get ticket type")
+	}
+
+	if err := ticketType.CheckDeletable(); err != nil {
+		return err
+	}
+
+	if err := h.ticketTypeRepo.Delete(ctx, ticketType); err != nil {
+		if err == domain.ErrTicketTypeInUse {
+			return err
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to delete ticket type")
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package command
+
+import (
+	"time"
+
+	"tixgo/modules/ticket/domain"
+)
+
+// TicketTypeResult represents a ticket type of an event of an organizer
+type TicketTypeResult struct {
+	ID               int64       `json:"id"`
+	EventID          int64       `json:"event_id"`
+	Name             string      `json:"name"`
+	Description      string      `json:"description"`
+	Kind             domain.Kind `json:"kind"`
+	Price            string      `json:"price"`
+	Currency         string      `json:"currency"`
+	Quantity         int         `json:"quantity"`
+	QuantitySold     int         `json:"quantity_sold"`
+	QuantityReserved int         `json:"quantity_reserved"`
+	QuantityAllotted int         `json:"quantity_allotted"`
+	Remaining        int         `json:"remaining"`
+	MaxPerOrder      int         `json:"max_per_order"`
+	SaleStartDate    *string     `json:"sale_start_date"`
+	SaleEndDate      *string     `json:"sale_end_date"`
+	IsTransferable   bool        `json:"is_transferable"`
+	IsRefundable     bool        `json:"is_refundable"`
+	SalesPaused      bool        `json:"sales_paused"`
+	CreatedAt        string      `json:"created_at"`
+	UpdatedAt        string      `json:"updated_at"`
+}
+
+// ToTicketTypeResult converts a ticket type to its result
+func ToTicketTypeResult(ticketType *domain.TicketType) *TicketTypeResult {
+	return &TicketTypeResult{
+		ID:               ticketType.ID,
+		EventID:          ticketType.EventID,
+		Name:             ticketType.Name,
+		Description:      ticketType.Description,
+		Kind:             ticketType.Kind,
+		Price:            ticketType.Price,
+		Currency:         ticketType.Currency,
+		Quantity:         ticketType.Quantity,
+		QuantitySold:     ticketType.Sold,
+		QuantityReserved: ticketType.Reserved,
+		QuantityAllotted: ticketType.Allotted,
+		Remaining:        ticketType.Remaining(),
+		MaxPerOrder:      ticketType.MaxPerOrder,
+		SaleStartDate:    FormatTime(ticketType.SaleStartDate),
+		SaleEndDate:      FormatTime(ticketType.SaleEndDate),
+		IsTransferable:   ticketType.IsTransferable,
+		IsRefundable:     ticketType.IsRefundable,
+		SalesPaused:      ticketType.SalesPaused,
+		CreatedAt:        ticketType.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:        ticketType.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+// FormatTime formats an optional time of a result
+func FormatTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := t.Format("2006-01-02T15:04:05Z")
+	return &formatted
+}
@@ -0,0 +1,54 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/ticket/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// UpdateTicketTypeCommand represents the command of an organizer to change the details of a ticket type
+// of their event. Its quantity is resized on its own, see the ticket category capacity of the event module.
+type UpdateTicketTypeCommand struct {
+	ID          int64 `json:"-"`
+	EventID     int64 `json:"-"`
+	OrganizerID int64 `json:"-"`
+	TicketTypeDetailsInput
+}
+
+// UpdateTicketTypeHandler handles ticket type updates
+type UpdateTicketTypeHandler struct {
+	ticketTypeRepo domain.TicketTypeRepository
+}
+
+// NewUpdateTicketTypeHandler creates a new update ticket type handler
+func NewUpdateTicketTypeHandler(ticketTypeRepo domain.TicketTypeRepository) *UpdateTicketTypeHandler {
+	return &UpdateTicketTypeHandler{
+		ticketTypeRepo: ticketTypeRepo,
+	}
+}
+
+// Handle executes the update ticket type command. The orders already placed keep their price.
+func (h *UpdateTicketTypeHandler) Handle(ctx context.Context, cmd UpdateTicketTypeCommand) (*TicketTypeResult, error) {
+	ticketType, err := h.ticketTypeRepo.GetByID(ctx, cmd.ID, cmd.EventID, cmd.OrganizerID)
+	if err != nil {
+		if err == domain.ErrTicketTypeNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get ticket type")
+	}
+
+	if err := ticketType.Update(cmd.Details()); err != nil {
+		return nil, err
+	}
+
+	if err := h.ticketTypeRepo.Update(ctx, ticketType); err != nil {
+		if err == domain.ErrEventClosed {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to update ticket type")
+	}
+
+	return ToTicketTypeResult(ticketType), nil
+}
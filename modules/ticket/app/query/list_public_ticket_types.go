@@ -0,0 +1,84 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/ticket/app/command"
+	"tixgo/modules/ticket/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ListPublicTicketTypesQuery represents the query of a buyer for the ticket types of a public event
+type ListPublicTicketTypesQuery struct {
+	Slug string
+}
+
+// PublicTicketTypeResult represents a ticket type with its availability, as buyers see it
+type PublicTicketTypeResult struct {
+	ID             int64               `json:"id"`
+	Name           string              `json:"name"`
+	Description    string              `json:"description"`
+	Kind           domain.Kind         `json:"kind"`
+	Price          string              `json:"price"`
+	Currency       string              `json:"currency"`
+	Remaining      int                 `json:"remaining"`
+	MaxPerOrder    int                 `json:"max_per_order"`
+	SaleStartDate  *string             `json:"sale_start_date"`
+	SaleEndDate    *string             `json:"sale_end_date"`
+	IsTransferable bool                `json:"is_transferable"`
+	IsRefundable   bool                `json:"is_refundable"`
+	Availability   domain.Availability `json:"availability"`
+}
+
+// ListPublicTicketTypesHandler handles listing the ticket types of a public event
+type ListPublicTicketTypesHandler struct {
+	ticketTypeRepo domain.TicketTypeRepository
+}
+
+// NewListPublicTicketTypesHandler creates a new list public ticket types handler
+func NewListPublicTicketTypesHandler(ticketTypeRepo domain.TicketTypeRepository) *ListPublicTicketTypesHandler {
+	return &ListPublicTicketTypesHandler{
+		ticketTypeRepo: ticketTypeRepo,
+	}
+}
+
+// Handle executes the list public ticket types query. The sales of cancelled and completed events are
+// over whatever the sale windows of their ticket types.
+func (h *ListPublicTicketTypesHandler) Handle(ctx context.Context, query ListPublicTicketTypesQuery) ([]*PublicTicketTypeResult, error) {
+	event, ticketTypes, err := h.ticketTypeRepo.ListPublic(ctx, query.Slug)
+	if err != nil {
+		if err == domain.ErrEventNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list ticket types")
+	}
+
+	now := time.Now()
+	items := make([]*PublicTicketTypeResult, len(ticketTypes))
+	for i, ticketType := range ticketTypes {
+		availability := ticketType.Availability(now)
+		if event.Closed() {
+			availability = domain.AvailabilityEnded
+		}
+
+		items[i] = &PublicTicketTypeResult{
+			ID:             ticketType.ID,
+			Name:           ticketType.Name,
+			Description:    ticketType.Description,
+			Kind:           ticketType.Kind,
+			Price:          ticketType.Price,
+			Currency:       ticketType.Currency,
+			Remaining:      ticketType.Remaining(),
+			MaxPerOrder:    ticketType.MaxPerOrder,
+			SaleStartDate:  command.FormatTime(ticketType.SaleStartDate),
+			SaleEndDate:    command.FormatTime(ticketType.SaleEndDate),
+			IsTransferable: ticketType.IsTransferable,
+			IsRefundable:   ticketType.IsRefundable,
+			Availability:   availability,
+		}
+	}
+
+	return items, nil
+}
@@ -0,0 +1,51 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/ticket/app/command"
+	"tixgo/modules/ticket/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ListTicketTypesQuery represents the query of an organizer for the ticket types of their event
+type ListTicketTypesQuery struct {
+	EventID     int64
+	OrganizerID int64
+}
+
+// ListTicketTypesHandler handles listing the ticket types of an event for its organizer
+type ListTicketTypesHandler struct {
+	ticketTypeRepo domain.TicketTypeRepository
+}
+
+// NewListTicketTypesHandler creates a new list ticket types handler
+func NewListTicketTypesHandler(ticketTypeRepo domain.TicketTypeRepository) *ListTicketTypesHandler {
+	return &ListTicketTypesHandler{
+		ticketTypeRepo: ticketTypeRepo,
+	}
+}
+
+// Handle executes the list ticket types query
+func (h *ListTicketTypesHandler) Handle(ctx context.Context, query ListTicketTypesQuery) ([]*command.TicketTypeResult, error) {
+	event, err := h.ticketTypeRepo.GetEvent(ctx, query.EventID, query.OrganizerID)
+	if err != nil {
+		if err == domain.ErrEventNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get event")
+	}
+
+	ticketTypes, err := h.ticketTypeRepo.List(ctx, event.ID)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list ticket types")
+	}
+
+	items := make([]*command.TicketTypeResult, len(ticketTypes))
+	for i, ticketType := range ticketTypes {
+		items[i] = command.ToTicketTypeResult(ticketType)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"tixgo/shared/errstack"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// Ticket domain errors
+var (
+	ErrEventNotFound      = errstack.Sentinel(syserr.NotFoundCode, "event not found")
+	ErrEventClosed        = errstack.Sentinel(syserr.ConflictCode, "the event is cancelled or over")
+	ErrTicketTypeNotFound = errstack.Sentinel(syserr.NotFoundCode, "ticket type not found")
+	ErrInvalidTicketKind  = errstack.Sentinel(syserr.InvalidArgumentCode, "kind must be general, vip, early_bird, group or season")
+	ErrInvalidPrice       = errstack.Sentinel(syserr.InvalidArgumentCode, "price must be a non negative amount with at most the decimals of the currency, 2 at most, below 100000000")
+	ErrInvalidSaleWindow  = errstack.Sentinel(syserr.InvalidArgumentCode, "the sales must end after they start")
+	ErrTicketTypeInUse    = errstack.Sentinel(syserr.ConflictCode, "a ticket type cannot be deleted once tickets of it are sold, reserved or allotted")
+	ErrEventHasNoVenue    = errstack.Sentinel(syserr.ConflictCode, "the event has no venue to bind seats of")
+	ErrSeatNotInVenue     = errstack.Sentinel(syserr.InvalidArgumentCode, "seats must be seats of the seat map of the venue of the event")
+	ErrDuplicateSeat      = errstack.Sentinel(syserr.InvalidArgumentCode, "a seat is bound twice")
+	ErrSeatTaken          = errstack.Sentinel(syserr.ConflictCode, "a seat is bound to another ticket type of the event")
+	ErrSeatInUse          = errstack.Sentinel(syserr.ConflictCode, "seats held, sold or allotted cannot be unbound")
+)
@@ -0,0 +1,216 @@
+package domain
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"tixgo/shared/money"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// Kind is the pricing tier of a ticket type
+type Kind string
+
+const (
+	KindGeneral   Kind = "general"
+	KindVIP       Kind = "vip"
+	KindEarlyBird Kind = "early_bird"
+	KindGroup     Kind = "group"
+	KindSeason    Kind = "season"
+)
+
+// IsValidKind checks if the kind is valid
+func IsValidKind(kind string) bool {
+	switch Kind(kind) {
+	case KindGeneral, KindVIP, KindEarlyBird, KindGroup, KindSeason:
+		return true
+	default:
+		return false
+	}
+}
+
+// Availability tells whether the tickets of a ticket type can be bought
+type Availability string
+
+const (
+	AvailabilityUpcoming Availability = "upcoming"
+	AvailabilityOnSale   Availability = "on_sale"
+	AvailabilityPaused   Availability = "paused"
+	AvailabilitySoldOut  Availability = "sold_out"
+	AvailabilityEnded    Availability = "ended"
+)
+
+// pricePattern matches the amounts the price column, DECIMAL(10, 2), holds
+var pricePattern = regexp.MustCompile(`^\d{1,8}(\.\d{1,2})?$`)
+
+// Event is the event ticket types are sold for, as far as ticket types are concerned
+type Event struct {
+	ID          int64
+	OrganizerID int64
+	Status      string
+	// Currency is the one its ticket types are priced in
+	Currency string
+}
+
+// Closed tells whether the event is cancelled or over, its ticket types then cannot change
+func (e *Event) Closed() bool {
+	return e.Status == "cancelled" || e.Status == "completed"
+}
+
+// TicketTypeDetails are the details of a ticket type its organizer sets when creating and updating it.
+// The quantity is set at creation only, it is resized with the stock already taken in mind afterwards.
+type TicketTypeDetails struct {
+	Name           string
+	Description    string
+	Kind           Kind
+	Price          string
+	MaxPerOrder    int
+	SaleStartDate  *time.Time
+	SaleEndDate    *time.Time
+	IsTransferable bool
+	IsRefundable   bool
+}
+
+// TicketType is a pricing tier of an event, e.g. Early Bird, VIP or General, with its own price,
+// stock, sale window and per-order limit. Ticket types are stored as ticket categories.
+type TicketType struct {
+	ID      int64
+	EventID int64
+	TicketTypeDetails
+	Quantity int
+	// Sold, Reserved and Allotted count the stock taken: tickets sold, held by buyers checking out and
+	// set aside for press, sponsors or guests
+	Sold     int
+	Reserved int
+	Allotted int
+	// SalesPaused tells whether the organizer paused the sales of the ticket type or of its event
+	SalesPaused bool
+	// Currency is the one of the event, ticket types have none of their own
+	Currency  string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewTicketType creates a ticket type of the event with quantity tickets, priced in the currency of the
+// event
+func NewTicketType(event *Event, details TicketTypeDetails, quantity int) (*TicketType, error) {
+	if quantity < 1 {
+		return nil, syserr.New(syserr.InvalidArgumentCode, "quantity must be at least 1")
+	}
+
+	ticketType := &TicketType{EventID: event.ID, Currency: event.Currency, Quantity: quantity}
+	if err := ticketType.Update(details); err != nil {
+		return nil, err
+	}
+	return ticketType, nil
+}
+
+// Update changes the details of the ticket type. The orders already placed keep the price they were
+// placed at. The price cannot be finer than the minor unit of the currency: dong have no decimals.
+func (t *TicketType) Update(details TicketTypeDetails) error {
+	details.Name = strings.TrimSpace(details.Name)
+	if details.Name == "" {
+		return syserr.New(syserr.InvalidArgumentCode, "name is required")
+	}
+	if !IsValidKind(string(details.Kind)) {
+		return ErrInvalidTicketKind
+	}
+	if !pricePattern.MatchString(details.Price) {
+		return ErrInvalidPrice
+	}
+	if _, err := money.ToMinor(details.Price, t.Currency); err != nil {
+		return ErrInvalidPrice
+	}
+	if details.MaxPerOrder < 1 {
+		return syserr.New(syserr.InvalidArgumentCode, "max_per_order must be at least 1")
+	}
+	if details.SaleStartDate != nil && details.SaleEndDate != nil && !details.SaleEndDate.After(*details.SaleStartDate) {
+		return ErrInvalidSaleWindow
+	}
+
+	t.TicketTypeDetails = details
+	return nil
+}
+
+// Remaining returns how many tickets are left to sell
+func (t *TicketType) Remaining() int {
+	return max(t.Quantity-t.Sold-t.Reserved-t.Allotted, 0)
+}
+
+// Availability tells whether the tickets can be bought at now
+func (t *TicketType) Availability(now time.Time) Availability {
+	switch {
+	case t.SaleEndDate != nil && !now.Before(*t.SaleEndDate):
+		return AvailabilityEnded
+	case t.SaleStartDate != nil && now.Before(*t.SaleStartDate):
+		return AvailabilityUpcoming
+	case t.SalesPaused:
+		return AvailabilityPaused
+	case t.Remaining() == 0:
+		return AvailabilitySoldOut
+	default:
+		return AvailabilityOnSale
+	}
+}
+
+// CheckDeletable fails with ErrTicketTypeInUse once tickets are sold, reserved or allotted
+func (t *TicketType) CheckDeletable() error {
+	if t.Sold+t.Reserved+t.Allotted > 0 {
+		return ErrTicketTypeInUse
+	}
+	return nil
+}
+
+// CheckSeats checks the venue seats the ticket type is to be sold as: at least one, each bound once, and
+// enough of them for the tickets already taken
+func (t *TicketType) CheckSeats(venueSeatIDs []int64) error {
+	if len(venueSeatIDs) == 0 {
+		return syserr.New(syserr.InvalidArgumentCode, "at least one seat must be bound")
+	}
+	bound := make(map[int64]bool, len(venueSeatIDs))
+	for _, id := range venueSeatIDs {
+		if bound[id] {
+			return ErrDuplicateSeat
+		}
+		bound[id] = true
+	}
+	if len(venueSeatIDs) < t.Sold+t.Reserved+t.Allotted {
+		return ErrSeatInUse
+	}
+	return nil
+}
+
+// TicketTypeRepository defines the interface for ticket type persistence. Events and ticket types of
+// other organizers are reported as not found.
+type TicketTypeRepository interface {
+	// GetEvent retrieves an event of the organizer
+	GetEvent(ctx context.Context, eventID, organizerID int64) (*Event, error)
+
+	// Create stores a new ticket type
+	Create(ctx context.Context, ticketType *TicketType) error
+
+	// GetByID retrieves a ticket type of an event of the organizer
+	GetByID(ctx context.Context, id, eventID, organizerID int64) (*TicketType, error)
+
+	// List retrieves the ticket types of an event, cheapest first
+	List(ctx context.Context, eventID int64) ([]*TicketType, error)
+
+	// ListPublic retrieves the event with the public page at slug and its ticket types, cheapest first,
+	// ErrEventNotFound for drafts. Ticket types without a sale window of their own get the one of the event.
+	ListPublic(ctx context.Context, slug string) (*Event, []*TicketType, error)
+
+	// Update saves the details of a ticket type
+	Update(ctx context.Context, ticketType *TicketType) error
+
+	// Delete deletes a ticket type with its seats, ErrTicketTypeInUse if tickets of it were taken
+	// meanwhile
+	Delete(ctx context.Context, ticketType *TicketType) error
+
+	// BindSeats makes the venue seats the seats of a ticket type, its quantity their number. The seats
+	// unbound are cancelled, ErrSeatInUse unless they are available; ErrSeatNotInVenue unless the seats
+	// are seats of the venue of the event and ErrSeatTaken if another ticket type of the event has one.
+	BindSeats(ctx context.Context, ticketType *TicketType, venueSeatIDs []int64) error
+}
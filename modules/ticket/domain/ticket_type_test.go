@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ticketEvent() *Event {
+	return &Event{ID: 7, Status: "published", Currency: "USD"}
+}
+
+func ticketTypeDetails() TicketTypeDetails {
+	return TicketTypeDetails{
+		Name:        " VIP ",
+		Kind:        KindVIP,
+		Price:       "150.50",
+		MaxPerOrder: 4,
+	}
+}
+
+func TestNewTicketType(t *testing.T) {
+	ticketType, err := NewTicketType(ticketEvent(), ticketTypeDetails(), 100)
+	require.NoError(t, err)
+	assert.Equal(t, "VIP", ticketType.Name)
+	assert.Equal(t, 100, ticketType.Remaining())
+
+	_, err = NewTicketType(ticketEvent(), ticketTypeDetails(), 0)
+	assert.Error(t, err)
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		modify func(d *TicketTypeDetails)
+		want   error
+	}{
+		{name: "unknown kind", modify: func(d *TicketTypeDetails) { d.Kind = "platinum" }, want: ErrInvalidTicketKind},
+		{name: "negative price", modify: func(d *TicketTypeDetails) { d.Price = "-1" }, want: ErrInvalidPrice},
+		{name: "too many decimals", modify: func(d *TicketTypeDetails) { d.Price = "1.005" }, want: ErrInvalidPrice},
+		{name: "price too large", modify: func(d *TicketTypeDetails) { d.Price = "100000000" }, want: ErrInvalidPrice},
+		{name: "sales ending as they start", modify: func(d *TicketTypeDetails) { d.SaleStartDate, d.SaleEndDate = &now, &now }, want: ErrInvalidSaleWindow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			details := ticketTypeDetails()
+			tt.modify(&details)
+			_, err := NewTicketType(ticketEvent(), details, 100)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+
+	details := ticketTypeDetails()
+	details.Price = "0"
+	_, err = NewTicketType(ticketEvent(), details, 100)
+	assert.NoError(t, err, "free tickets are allowed")
+
+	// priced in the currency of the event
+	event := ticketEvent()
+	event.Currency = "VND"
+	details.Price = "150000"
+	ticketType, err = NewTicketType(event, details, 100)
+	require.NoError(t, err)
+	assert.Equal(t, "VND", ticketType.Currency)
+	details.Price = "150000.50"
+	_, err = NewTicketType(event, details, 100)
+	assert.ErrorIs(t, err, ErrInvalidPrice, "dong have no decimals")
+}
+
+func TestTicketType_Availability(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	start, end := now.Add(time.Hour), now.Add(2*time.Hour)
+
+	details := ticketTypeDetails()
+	details.SaleStartDate, details.SaleEndDate = &start, &end
+	ticketType, err := NewTicketType(ticketEvent(), details, 10)
+	require.NoError(t, err)
+
+	assert.Equal(t, AvailabilityUpcoming, ticketType.Availability(now))
+	assert.Equal(t, AvailabilityOnSale, ticketType.Availability(start))
+	assert.Equal(t, AvailabilityEnded, ticketType.Availability(end))
+
+	ticketType.SalesPaused = true
+	assert.Equal(t, AvailabilityPaused, ticketType.Availability(start))
+	ticketType.SalesPaused = false
+
+	ticketType.Sold, ticketType.Reserved, ticketType.Allotted = 5, 3, 2
+	assert.Equal(t, 0, ticketType.Remaining())
+	assert.Equal(t, AvailabilitySoldOut, ticketType.Availability(start))
+}
+
+func TestTicketType_CheckDeletable(t *testing.T) {
+	ticketType, err := NewTicketType(ticketEvent(), ticketTypeDetails(), 10)
+	require.NoError(t, err)
+	assert.NoError(t, ticketType.CheckDeletable())
+
+	ticketType.Reserved = 1
+	assert.ErrorIs(t, ticketType.CheckDeletable(), ErrTicketTypeInUse)
+}
+
+func TestTicketType_CheckSeats(t *testing.T) {
+	ticketType, err := NewTicketType(ticketEvent(), ticketTypeDetails(), 10)
+	require.NoError(t, err)
+	assert.NoError(t, ticketType.CheckSeats([]int64{1, 2, 3}))
+	assert.Error(t, ticketType.CheckSeats(nil))
+	assert.ErrorIs(t, ticketType.CheckSeats([]int64{1, 2, 1}), ErrDuplicateSeat)
+
+	ticketType.Sold, ticketType.Allotted = 2, 2
+	assert.ErrorIs(t, ticketType.CheckSeats([]int64{1, 2, 3}), ErrSeatInUse)
+	assert.NoError(t, ticketType.CheckSeats([]int64{1, 2, 3, 4}))
+}
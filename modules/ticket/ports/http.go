@@ -0,0 +1,215 @@
+package ports
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"tixgo/components"
+	"tixgo/modules/ticket/adapters"
+	"tixgo/modules/ticket/app/command"
+	"tixgo/modules/ticket/app/query"
+	userDomain "tixgo/modules/user/domain"
+	"tixgo/shared/apiversion"
+	"tixgo/shared/authz"
+	"tixgo/shared/httpresponse"
+	"tixgo/shared/session"
+
+	"github.com/duongptryu/gox/context"
+
+	"github.com/gin-gonic/gin"
+)
+
+// publicTicketTypesCachePolicy matches the one of the public event page, the two show the same
+// availability
+var publicTicketTypesCachePolicy = httpresponse.CachePolicy{
+	MaxAge:               5 * time.Second,
+	StaleWhileRevalidate: 30 * time.Second,
+}
+
+func RegisterTicketRoutes(router *apiversion.Group, appCtx components.AppContext) {
+	publicGroup := router.Group("/public/events/:slug/ticket-types")
+	{
+		publicGroup.GET("", ListPublicTicketTypes(appCtx))
+	}
+
+	managementGroup := router.Group("/events/:id/ticket-types")
+	{
+		managementGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		managementGroup.Use(authz.RequireUserType(string(userDomain.UserTypeOrganizer)))
+		managementGroup.POST("", CreateTicketType(appCtx))
+		managementGroup.GET("", ListTicketTypes(appCtx))
+		managementGroup.PUT("/:ticket_type_id", UpdateTicketType(appCtx))
+		managementGroup.DELETE("/:ticket_type_id", DeleteTicketType(appCtx))
+		managementGroup.PUT("/:ticket_type_id/seats", BindSeats(appCtx))
+	}
+}
+
+func CreateTicketType(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.CreateTicketTypeCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		eventID, organizerID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+		req.EventID = eventID
+		req.OrganizerID = organizerID
+
+		handler := command.NewCreateTicketTypeHandler(adapters.NewTicketTypePostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusCreated, result)
+	}
+}
+
+func ListTicketTypes(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventID, organizerID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+
+		handler := query.NewListTicketTypesHandler(adapters.NewTicketTypePostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), query.ListTicketTypesQuery{EventID: eventID, OrganizerID: organizerID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func UpdateTicketType(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ticketTypeID, err := strconv.ParseInt(c.Param("ticket_type_id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		var req command.UpdateTicketTypeCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		eventID, organizerID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+		req.ID = ticketTypeID
+		req.EventID = eventID
+		req.OrganizerID = organizerID
+
+		handler := command.NewUpdateTicketTypeHandler(adapters.NewTicketTypePostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func DeleteTicketType(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ticketTypeID, err := strconv.ParseInt(c.Param("ticket_type_id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		eventID, organizerID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+
+		handler := command.NewDeleteTicketTypeHandler(adapters.NewTicketTypePostgresRepository(appCtx.GetDB()))
+
+		err = handler.Handle(c.Request.Context(), command.DeleteTicketTypeCommand{ID: ticketTypeID, EventID: eventID, OrganizerID: organizerID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, true)
+	}
+}
+
+func BindSeats(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ticketTypeID, err := strconv.ParseInt(c.Param("ticket_type_id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		var req command.BindSeatsCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		eventID, organizerID, ok := authenticatedEventParams(c)
+		if !ok {
+			return
+		}
+		req.ID = ticketTypeID
+		req.EventID = eventID
+		req.OrganizerID = organizerID
+
+		handler := command.NewBindSeatsHandler(adapters.NewTicketTypePostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func ListPublicTicketTypes(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		handler := query.NewListPublicTicketTypesHandler(adapters.NewTicketTypePostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), query.ListPublicTicketTypesQuery{Slug: c.Param("slug")})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Cacheable(c, result, publicTicketTypesCachePolicy)
+	}
+}
+
+func authenticatedEventParams(c *gin.Context) (eventID, userID int64, ok bool) {
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(err)
+		return 0, 0, false
+	}
+
+	userID, err = context.GetUserIDFromContextAsInt64(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return 0, 0, false
+	}
+
+	return eventID, userID, true
+}
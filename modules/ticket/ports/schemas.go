@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"tixgo/modules/ticket/app/command"
+	"tixgo/shared/jsonschema"
+)
+
+// Schemas returns the request payloads of the ticket module
+func Schemas() []jsonschema.Payload {
+	return []jsonschema.Payload{
+		{Name: "events.ticket-types.create", In: jsonschema.Body, Example: command.CreateTicketTypeCommand{}},
+		{Name: "events.ticket-types.update", In: jsonschema.Body, Example: command.UpdateTicketTypeCommand{}},
+		{Name: "events.ticket-types.seats", In: jsonschema.Body, Example: command.BindSeatsCommand{}},
+	}
+}
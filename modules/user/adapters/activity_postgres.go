@@ -0,0 +1,121 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"tixgo/modules/user/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/listing"
+	"tixgo/shared/pgquery"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+// ActivityPostgresRepository implements the ActivityRepository interface using PostgreSQL
+type ActivityPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewActivityPostgresRepository creates a new PostgreSQL activity repository
+func NewActivityPostgresRepository(db *sqlx.DB) *ActivityPostgresRepository {
+	return &ActivityPostgresRepository{db: db}
+}
+
+// Record stores an activity, doing nothing if its EventID was already recorded or its user is gone
+func (r *ActivityPostgresRepository) Record(ctx context.Context, activity *domain.Activity) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	metadata, err := json.Marshal(activity.Metadata)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to encode activity metadata")
+	}
+	if activity.Metadata == nil {
+		metadata = []byte("{}")
+	}
+
+	query := `
+		INSERT INTO user_activities (event_id, user_id, type, metadata, ip_address, user_agent, occurred_at)
+		SELECT $1, u.id, $3, $4, NULLIF($5, ''), NULLIF($6, ''), $7
+		FROM users u
+		WHERE u.id = $2
+		ON CONFLICT (event_id) DO NOTHING`
+
+	_, err = r.db.ExecContext(ctx, query,
+		activity.EventID,
+		activity.UserID,
+		activity.Type,
+		metadata,
+		activity.IPAddress,
+		activity.UserAgent,
+		activity.OccurredAt,
+	)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to record activity")
+	}
+
+	return nil
+}
+
+// ListByUserID retrieves the activities of a user, most recent first
+func (r *ActivityPostgresRepository) ListByUserID(ctx context.Context, userID int64, paging *listing.Paging) ([]*domain.Activity, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	filter := &pgquery.Filter{}
+	filter.Where("user_id = ?", userID)
+
+	// Set total in paging
+	err := pgquery.Count(ctx, r.db, "user_activities", filter, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to count activities")
+	}
+
+	pageClause, args := filter.Paged(paging)
+	query := fmt.Sprintf(`
+		SELECT id, event_id, user_id, type, metadata, COALESCE(ip_address, ''), COALESCE(user_agent, ''), occurred_at
+		FROM user_activities
+		%s
+		ORDER BY occurred_at DESC, id DESC
+		%s`, filter.Clause(), pageClause)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list activities")
+	}
+	defer rows.Close()
+
+	var activities []*domain.Activity
+	for rows.Next() {
+		activity := &domain.Activity{}
+		var metadata []byte
+		err := rows.Scan(
+			&activity.ID,
+			&activity.EventID,
+			&activity.UserID,
+			&activity.Type,
+			&metadata,
+			&activity.IPAddress,
+			&activity.UserAgent,
+			&activity.OccurredAt,
+		)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan activity")
+		}
+
+		if err := json.Unmarshal(metadata, &activity.Metadata); err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to decode activity metadata")
+		}
+
+		activities = append(activities, activity)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "error iterating activity rows")
+	}
+
+	return activities[:paging.Fetched(len(activities))], nil
+}
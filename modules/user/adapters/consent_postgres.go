@@ -0,0 +1,145 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+
+	"tixgo/modules/user/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/listing"
+	"tixgo/shared/pgquery"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+// ConsentPostgresRepository implements the ConsentRepository interface using PostgreSQL
+type ConsentPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewConsentPostgresRepository creates a new PostgreSQL consent repository
+func NewConsentPostgresRepository(db *sqlx.DB) *ConsentPostgresRepository {
+	return &ConsentPostgresRepository{db: db}
+}
+
+// consentColumns are the columns scanned by scanConsent
+const consentColumns = `id, user_id, kind, version, granted, source, COALESCE(ip_address, ''), COALESCE(user_agent, ''), created_at`
+
+// Record stores consents at once, setting their IDs
+func (r *ConsentPostgresRepository) Record(ctx context.Context, consents ...*domain.Consent) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	for _, consent := range consents {
+		err := tx.GetContext(ctx, &consent.ID, `
+			INSERT INTO user_consents (user_id, kind, version, granted, source, ip_address, user_agent, created_at)
+			VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF(LEFT($7, 500), ''), $8)
+			RETURNING id`,
+			consent.UserID, consent.Kind, consent.Version, consent.Granted, consent.Source,
+			consent.IPAddress, consent.UserAgent, consent.CreatedAt)
+		if err != nil {
+			return syserr.Wrap(err, syserr.InternalCode, "failed to record consent")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to commit consents")
+	}
+
+	return nil
+}
+
+// Latest retrieves the latest consent of each kind of a user
+func (r *ConsentPostgresRepository) Latest(ctx context.Context, userID int64) (map[domain.ConsentKind]*domain.Consent, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	consents, err := r.query(ctx, `
+		SELECT DISTINCT ON (kind) `+consentColumns+`
+		FROM user_consents
+		WHERE user_id = $1
+		ORDER BY kind, created_at DESC, id DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make(map[domain.ConsentKind]*domain.Consent, len(consents))
+	for _, consent := range consents {
+		latest[consent.Kind] = consent
+	}
+	return latest, nil
+}
+
+// List retrieves a page of the consent history of a user, newest first
+func (r *ConsentPostgresRepository) List(ctx context.Context, filters domain.ConsentFilters, paging *listing.Paging) ([]*domain.Consent, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	filter := &pgquery.Filter{}
+	filter.Where("user_id = ?", filters.UserID)
+	if filters.Kind != "" {
+		filter.Where("kind = ?", filters.Kind)
+	}
+
+	// Set total in paging
+	err := pgquery.Count(ctx, r.db, "user_consents", filter, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to count consents")
+	}
+
+	pageClause, args := filter.Paged(paging)
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM user_consents
+		%s
+		ORDER BY created_at DESC, id DESC
+		%s`, consentColumns, filter.Clause(), pageClause)
+
+	consents, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return consents[:paging.Fetched(len(consents))], nil
+}
+
+func (r *ConsentPostgresRepository) query(ctx context.Context, query string, args ...any) ([]*domain.Consent, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list consents")
+	}
+	defer rows.Close()
+
+	consents := []*domain.Consent{}
+	for rows.Next() {
+		consent := &domain.Consent{}
+		err := rows.Scan(
+			&consent.ID,
+			&consent.UserID,
+			&consent.Kind,
+			&consent.Version,
+			&consent.Granted,
+			&consent.Source,
+			&consent.IPAddress,
+			&consent.UserAgent,
+			&consent.CreatedAt,
+		)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan consent")
+		}
+		consents = append(consents, consent)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "error iterating consent rows")
+	}
+
+	return consents, nil
+}
@@ -0,0 +1,58 @@
+package adapters
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// storeCleanups counts the running cleanup goroutines of the in-memory stores, by store. A count
+// growing with the traffic means stores are created per request and never closed.
+var storeCleanups = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "tixgo_store_cleanup_goroutines",
+	Help: "Cleanup goroutines of the in-memory stores currently running, by store.",
+}, []string{"store"})
+
+// janitor runs the cleanup of an in-memory store in a goroutine of its own until it is closed
+type janitor struct {
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// startJanitor runs cleanup every interval, counting the goroutine in storeCleanups under store
+func startJanitor(store string, interval time.Duration, cleanup func()) *janitor {
+	j := &janitor{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	gauge := storeCleanups.WithLabelValues(store)
+	gauge.Inc()
+	go func() {
+		defer close(j.done)
+		defer gauge.Dec()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				cleanup()
+			case <-j.stop:
+				return
+			}
+		}
+	}()
+
+	return j
+}
+
+// Close stops the goroutine and waits for it to return. It can be called more than once.
+func (j *janitor) Close() {
+	j.once.Do(func() { close(j.stop) })
+	<-j.done
+}
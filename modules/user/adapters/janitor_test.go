@@ -0,0 +1,46 @@
+package adapters
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+// TestMain fails the package when a test leaves a goroutine running, such as the cleanup of a store
+// never closed
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestJanitor(t *testing.T) {
+	gauge := storeCleanups.WithLabelValues("test")
+
+	var cleanups atomic.Int32
+	j := startJanitor("test", time.Millisecond, func() { cleanups.Add(1) })
+	assert.Equal(t, float64(1), testutil.ToFloat64(gauge))
+	assert.Eventually(t, func() bool { return cleanups.Load() >= 2 }, time.Second, time.Millisecond)
+
+	j.Close()
+	j.Close()
+	assert.Equal(t, float64(0), testutil.ToFloat64(gauge))
+
+	stopped := cleanups.Load()
+	time.Sleep(5 * time.Millisecond)
+	assert.Equal(t, stopped, cleanups.Load())
+}
+
+func TestInMemoryStores_CloseStopsTheirCleanup(t *testing.T) {
+	otpStore, tempUserStore := NewInMemoryOTPStore(), NewInMemoryTempUserStore()
+	assert.Equal(t, float64(1), testutil.ToFloat64(storeCleanups.WithLabelValues("otp")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(storeCleanups.WithLabelValues("temp_user")))
+
+	otpStore.Close()
+	tempUserStore.Close()
+	goleak.VerifyNone(t)
+	assert.Equal(t, float64(0), testutil.ToFloat64(storeCleanups.WithLabelValues("otp")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(storeCleanups.WithLabelValues("temp_user")))
+}
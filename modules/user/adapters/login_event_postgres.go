@@ -0,0 +1,54 @@
+package adapters
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/user/domain"
+	"tixgo/shared/dbtimeout"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+)
+
+// LoginEventPostgresRepository implements the LoginEventRepository interface using PostgreSQL
+type LoginEventPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewLoginEventPostgresRepository creates a new PostgreSQL login event repository
+func NewLoginEventPostgresRepository(db *sqlx.DB) *LoginEventPostgresRepository {
+	return &LoginEventPostgresRepository{db: db}
+}
+
+// Record stores a login event, a single insert that never locks the user row
+func (r *LoginEventPostgresRepository) Record(ctx context.Context, event *domain.LoginEvent) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		INSERT INTO login_events (user_id, ip_address, user_agent, created_at)
+		VALUES ($1, NULLIF($2, ''), NULLIF(LEFT($3, 500), ''), $4)
+		RETURNING id`
+
+	err := r.db.GetContext(ctx, &event.ID, query, event.UserID, event.IPAddress, event.UserAgent, event.CreatedAt)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to record login event")
+	}
+
+	return nil
+}
+
+// LastLogin retrieves the time of the latest login of a user, nil if they never logged in
+func (r *LoginEventPostgresRepository) LastLogin(ctx context.Context, userID int64) (*time.Time, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var lastLogin *time.Time
+	err := r.db.GetContext(ctx, &lastLogin, `SELECT MAX(created_at) FROM login_events WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get last login")
+	}
+
+	return lastLogin, nil
+}
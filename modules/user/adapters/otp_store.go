@@ -18,35 +18,45 @@ type OTPEntry struct {
 type InMemoryOTPStore struct {
 	store   map[string]*OTPEntry
 	mutex   sync.RWMutex
-	cleanup chan struct{}
+	janitor *janitor
 }
 
-// NewInMemoryOTPStore creates a new in-memory OTP store
+// NewInMemoryOTPStore creates a new in-memory OTP store with its cleanup goroutine. Create it once:
+// its owner calls Close on shutdown.
 func NewInMemoryOTPStore() *InMemoryOTPStore {
 	store := &InMemoryOTPStore{
-		store:   make(map[string]*OTPEntry),
-		cleanup: make(chan struct{}),
+		store: make(map[string]*OTPEntry),
 	}
-
-	// Start cleanup goroutine
-	go store.startCleanup()
+	store.janitor = startJanitor("otp", time.Minute, store.cleanupExpired)
 
 	return store
 }
 
-// Store stores an OTP for a user email with 5-minute expiration
+// Store stores an OTP for a user email, valid for domain.OTPTTL
 func (s *InMemoryOTPStore) Store(ctx context.Context, email, otp string) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
 	s.store[email] = &OTPEntry{
 		OTP:       otp,
-		ExpiresAt: time.Now().Add(5 * time.Minute),
+		ExpiresAt: time.Now().Add(domain.OTPTTL),
 	}
 
 	return nil
 }
 
+// Get returns the pending OTP of a user email without consuming it
+func (s *InMemoryOTPStore) Get(ctx context.Context, email string) (string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	entry, exists := s.store[email]
+	if !exists || time.Now().After(entry.ExpiresAt) {
+		return "", domain.ErrOTPNotFound
+	}
+	return entry.OTP, nil
+}
+
 // Verify verifies an OTP for a user email and removes it if valid
 func (s *InMemoryOTPStore) Verify(ctx context.Context, email, otp string) error {
 	s.mutex.Lock()
@@ -83,21 +93,6 @@ func (s *InMemoryOTPStore) Delete(ctx context.Context, email string) error {
 	return nil
 }
 
-// startCleanup starts a goroutine to clean up expired OTPs
-func (s *InMemoryOTPStore) startCleanup() {
-	ticker := time.NewTicker(1 * time.Minute)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ticker.C:
-			s.cleanupExpired()
-		case <-s.cleanup:
-			return
-		}
-	}
-}
-
 // cleanupExpired removes expired OTPs from the store
 func (s *InMemoryOTPStore) cleanupExpired() {
 	s.mutex.Lock()
@@ -111,7 +106,7 @@ func (s *InMemoryOTPStore) cleanupExpired() {
 	}
 }
 
-// Close stops the cleanup goroutine
+// Close stops the cleanup goroutine, waiting for it to return
 func (s *InMemoryOTPStore) Close() {
-	close(s.cleanup)
+	s.janitor.Close()
 }
@@ -0,0 +1,79 @@
+package adapters
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+
+	"tixgo/modules/user/domain"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	otpKeyPrefix = "otp:"
+	// phoneOTPKeyPrefix namespaces the phone verification codes apart from the email ones
+	phoneOTPKeyPrefix = "otp:phone:"
+)
+
+// RedisOTPStore implements the OTPStore interface with redis, the key expiring with the code
+type RedisOTPStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisOTPStore creates an OTP store of the email verification codes backed by redis
+func NewRedisOTPStore(client redis.UniversalClient) *RedisOTPStore {
+	return &RedisOTPStore{client: client, prefix: otpKeyPrefix}
+}
+
+// NewRedisPhoneOTPStore creates an OTP store of the phone verification codes backed by redis
+func NewRedisPhoneOTPStore(client redis.UniversalClient) *RedisOTPStore {
+	return &RedisOTPStore{client: client, prefix: phoneOTPKeyPrefix}
+}
+
+// Store stores an OTP for a user email, valid for domain.OTPTTL
+func (s *RedisOTPStore) Store(ctx context.Context, email, otp string) error {
+	if err := s.client.Set(ctx, s.prefix+email, otp, domain.OTPTTL).Err(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to store OTP")
+	}
+	return nil
+}
+
+// Get returns the pending OTP of a user email without consuming it
+func (s *RedisOTPStore) Get(ctx context.Context, email string) (string, error) {
+	otp, err := s.client.Get(ctx, s.prefix+email).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", domain.ErrOTPNotFound
+		}
+		return "", syserr.Wrap(err, syserr.InternalCode, "failed to get OTP")
+	}
+	return otp, nil
+}
+
+// Verify verifies an OTP for a user email and removes it if valid. An expired code is gone with its key.
+func (s *RedisOTPStore) Verify(ctx context.Context, email, otp string) error {
+	stored, err := s.client.Get(ctx, s.prefix+email).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return domain.ErrInvalidOTP
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get OTP")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(stored), []byte(otp)) != 1 {
+		return domain.ErrInvalidOTP
+	}
+
+	return s.Delete(ctx, email)
+}
+
+// Delete removes an OTP for a user email
+func (s *RedisOTPStore) Delete(ctx context.Context, email string) error {
+	if err := s.client.Del(ctx, s.prefix+email).Err(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to delete OTP")
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package adapters
+
+import (
+	"context"
+	"testing"
+
+	"tixgo/modules/user/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisOTPStore(t *testing.T) {
+	ctx := context.Background()
+	email := "test@example.com"
+
+	t.Run("verifies a code once", func(t *testing.T) {
+		client, _ := newTestRedisClient(t)
+		store := NewRedisOTPStore(client)
+
+		require.NoError(t, store.Store(ctx, email, "123456"))
+		assert.Equal(t, domain.ErrInvalidOTP, store.Verify(ctx, email, "654321"))
+		assert.NoError(t, store.Verify(ctx, email, "123456"))
+		assert.Equal(t, domain.ErrInvalidOTP, store.Verify(ctx, email, "123456"), "a verified code is consumed")
+	})
+
+	t.Run("reads a code back without consuming it", func(t *testing.T) {
+		client, _ := newTestRedisClient(t)
+		store := NewRedisOTPStore(client)
+
+		_, err := store.Get(ctx, email)
+		assert.Equal(t, domain.ErrOTPNotFound, err)
+
+		require.NoError(t, store.Store(ctx, email, "123456"))
+		otp, err := store.Get(ctx, email)
+		require.NoError(t, err)
+		assert.Equal(t, "123456", otp)
+		assert.NoError(t, store.Verify(ctx, email, "123456"))
+	})
+
+	t.Run("expires a code", func(t *testing.T) {
+		client, server := newTestRedisClient(t)
+		store := NewRedisOTPStore(client)
+
+		require.NoError(t, store.Store(ctx, email, "123456"))
+		server.FastForward(domain.OTPTTL)
+		assert.Equal(t, domain.ErrInvalidOTP, store.Verify(ctx, email, "123456"))
+	})
+
+	t.Run("keeps phone codes apart from email codes", func(t *testing.T) {
+		client, server := newTestRedisClient(t)
+		emails, phones := NewRedisOTPStore(client), NewRedisPhoneOTPStore(client)
+
+		require.NoError(t, phones.Store(ctx, "42:+84901234567", "123456"))
+		assert.True(t, server.Exists("otp:phone:42:+84901234567"))
+		_, err := emails.Get(ctx, "42:+84901234567")
+		assert.Equal(t, domain.ErrOTPNotFound, err)
+		assert.NoError(t, phones.Verify(ctx, "42:+84901234567", "123456"))
+	})
+}
@@ -0,0 +1,220 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"tixgo/modules/user/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/listing"
+	"tixgo/shared/pgquery"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// RegistrationRiskPostgresRepository implements the RegistrationRiskRepository interface using PostgreSQL
+type RegistrationRiskPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewRegistrationRiskPostgresRepository creates a new PostgreSQL registration risk repository
+func NewRegistrationRiskPostgresRepository(db *sqlx.DB) *RegistrationRiskPostgresRepository {
+	return &RegistrationRiskPostgresRepository{db: db}
+}
+
+// Record stores the assessment of a registration attempt
+func (r *RegistrationRiskPostgresRepository) Record(ctx context.Context, risk *domain.RegistrationRisk) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	signals, err := json.Marshal(risk.Signals)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to encode registration signals")
+	}
+
+	query := `
+		INSERT INTO registration_risks (email, ip_address, device_id, user_agent, signals, score, decision, reasons, review_status, created_at)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), $5, $6, $7, $8, NULLIF($9, ''), $10)
+		RETURNING id`
+
+	err = r.db.QueryRowContext(ctx, query,
+		risk.Email,
+		risk.IPAddress,
+		risk.DeviceID,
+		risk.UserAgent,
+		signals,
+		risk.Score,
+		risk.Decision,
+		pq.Array(risk.Reasons),
+		risk.ReviewStatus,
+		risk.CreatedAt,
+	).Scan(&risk.ID)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to record registration risk")
+	}
+
+	return nil
+}
+
+// CountFlagged returns the registrations from an IP blocked, or rejected by a review, since a time
+func (r *RegistrationRiskPostgresRepository) CountFlagged(ctx context.Context, ipAddress string, since time.Time) (int, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM registration_risks
+		WHERE ip_address = $1 AND created_at >= $2
+		  AND (decision = 'block' OR review_status = 'rejected')`, ipAddress, since).Scan(&count)
+	if err != nil {
+		return 0, syserr.Wrap(err, syserr.InternalCode, "failed to count flagged registrations")
+	}
+
+	return count, nil
+}
+
+// registrationRiskColumns are the columns scanned by scanRegistrationRisk, of registration_risks r
+// joined with the users u of their email
+const registrationRiskColumns = `r.id, r.email, r.ip_address, COALESCE(r.device_id, ''), COALESCE(r.user_agent, ''),
+	r.signals, r.score, r.decision, r.reasons, COALESCE(r.review_status, ''), r.reviewed_by,
+	COALESCE(r.review_reason, ''), r.reviewed_at, u.id, r.created_at`
+
+// GetByID retrieves an assessment, ErrRegistrationRiskNotFound if there is none
+func (r *RegistrationRiskPostgresRepository) GetByID(ctx context.Context, id int64) (*domain.RegistrationRisk, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+registrationRiskColumns+`
+		FROM registration_risks r
+		LEFT JOIN users u ON u.email = r.email
+		WHERE r.id = $1`, id)
+
+	risk, err := scanRegistrationRisk(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrRegistrationRiskNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get registration risk")
+	}
+
+	return risk, nil
+}
+
+// List retrieves a page of assessments, newest first
+func (r *RegistrationRiskPostgresRepository) List(ctx context.Context, filters domain.ListRegistrationRiskFilters, paging *listing.Paging) ([]*domain.RegistrationRisk, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	filter := &pgquery.Filter{}
+	if filters.Decision != nil {
+		filter.Where("r.decision = ?", *filters.Decision)
+	}
+	if filters.ReviewStatus != nil {
+		filter.Where("r.review_status = ?", *filters.ReviewStatus)
+	}
+	if filters.IPAddress != nil {
+		filter.Where("r.ip_address = ?", *filters.IPAddress)
+	}
+
+	// Set total in paging
+	err := pgquery.Count(ctx, r.db, "registration_risks r", filter, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to count registration risks")
+	}
+
+	pageClause, args := filter.Paged(paging)
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM registration_risks r
+		LEFT JOIN users u ON u.email = r.email
+		%s
+		ORDER BY r.created_at DESC, r.id DESC
+		%s`, registrationRiskColumns, filter.Clause(), pageClause)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list registration risks")
+	}
+	defer rows.Close()
+
+	var risks []*domain.RegistrationRisk
+	for rows.Next() {
+		risk, err := scanRegistrationRisk(rows)
+		if err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan registration risk")
+		}
+		risks = append(risks, risk)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "error iterating registration risk rows")
+	}
+
+	return risks[:paging.Fetched(len(risks))], nil
+}
+
+// SaveReview records the review of an assessment still pending, so that two admins reviewing it at
+// once cannot both decide
+func (r *RegistrationRiskPostgresRepository) SaveReview(ctx context.Context, risk *domain.RegistrationRisk) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE registration_risks
+		SET review_status = $2, reviewed_by = $3, review_reason = NULLIF($4, ''), reviewed_at = $5
+		WHERE id = $1 AND review_status = 'pending'`,
+		risk.ID, risk.ReviewStatus, risk.ReviewedBy, risk.ReviewReason, risk.ReviewedAt)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to save registration review")
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get rows affected")
+	}
+	if updated == 0 {
+		return domain.ErrRiskReviewNotPending
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRegistrationRisk(row rowScanner) (*domain.RegistrationRisk, error) {
+	risk := &domain.RegistrationRisk{}
+	var signals []byte
+	err := row.Scan(
+		&risk.ID,
+		&risk.Email,
+		&risk.IPAddress,
+		&risk.DeviceID,
+		&risk.UserAgent,
+		&signals,
+		&risk.Score,
+		&risk.Decision,
+		pq.Array(&risk.Reasons),
+		&risk.ReviewStatus,
+		&risk.ReviewedBy,
+		&risk.ReviewReason,
+		&risk.ReviewedAt,
+		&risk.UserID,
+		&risk.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(signals, &risk.Signals); err != nil {
+		return nil, err
+	}
+	return risk, nil
+}
@@ -0,0 +1,69 @@
+package adapters
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	registrationIPKeyPrefix     = "registrations:ip:"
+	registrationDeviceKeyPrefix = "registrations:device:"
+)
+
+// RedisRegistrationVelocity implements the RegistrationVelocity interface with redis counters. A counter
+// expires a window after the first registration it counts, so the window is fixed rather than sliding:
+// a burst across two windows is split between them, which the limits allow for.
+type RedisRegistrationVelocity struct {
+	client redis.UniversalClient
+}
+
+// NewRedisRegistrationVelocity creates a registration velocity backed by redis
+func NewRedisRegistrationVelocity(client redis.UniversalClient) *RedisRegistrationVelocity {
+	return &RedisRegistrationVelocity{client: client}
+}
+
+// Count returns the registrations of the window from the IP and from the device, zero for an empty device
+func (v *RedisRegistrationVelocity) Count(ctx context.Context, ipAddress, deviceID string, _ time.Duration) (int, int, error) {
+	keys := []string{registrationIPKeyPrefix + ipAddress}
+	if deviceID != "" {
+		keys = append(keys, registrationDeviceKeyPrefix+deviceID)
+	}
+
+	values, err := v.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return 0, 0, syserr.Wrap(err, syserr.InternalCode, "failed to count registrations")
+	}
+
+	counts := make([]int, 2)
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			counts[i], _ = strconv.Atoi(s)
+		}
+	}
+	return counts[0], counts[1], nil
+}
+
+// Record counts a registration from the IP and the device
+func (v *RedisRegistrationVelocity) Record(ctx context.Context, ipAddress, deviceID string, window time.Duration) error {
+	keys := []string{registrationIPKeyPrefix + ipAddress}
+	if deviceID != "" {
+		keys = append(keys, registrationDeviceKeyPrefix+deviceID)
+	}
+
+	// the counter starts with its window, INCR keeping the expiry it has
+	_, err := v.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.SetNX(ctx, key, 0, window)
+			pipe.Incr(ctx, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to record registration")
+	}
+	return nil
+}
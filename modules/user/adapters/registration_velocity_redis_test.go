@@ -0,0 +1,41 @@
+package adapters
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisRegistrationVelocity(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("counts the registrations of the window per IP and per device", func(t *testing.T) {
+		client, server := newTestRedisClient(t)
+		velocity := NewRedisRegistrationVelocity(client)
+
+		require.NoError(t, velocity.Record(ctx, "198.51.100.7", "device-1", time.Hour))
+		require.NoError(t, velocity.Record(ctx, "198.51.100.7", "", time.Hour))
+
+		fromIP, fromDevice, err := velocity.Count(ctx, "198.51.100.7", "device-1", time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, 2, fromIP)
+		assert.Equal(t, 1, fromDevice)
+
+		fromIP, fromDevice, err = velocity.Count(ctx, "198.51.100.8", "", time.Hour)
+		require.NoError(t, err)
+		assert.Zero(t, fromIP)
+		assert.Zero(t, fromDevice)
+
+		server.FastForward(30 * time.Minute)
+		require.NoError(t, velocity.Record(ctx, "198.51.100.7", "device-1", time.Hour))
+		server.FastForward(31 * time.Minute)
+
+		fromIP, fromDevice, err = velocity.Count(ctx, "198.51.100.7", "device-1", time.Hour)
+		require.NoError(t, err)
+		assert.Zero(t, fromIP, "the window starts with its first registration")
+		assert.Zero(t, fromDevice)
+	})
+}
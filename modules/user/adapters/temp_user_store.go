@@ -18,30 +18,28 @@ type TempUserEntry struct {
 type InMemoryTempUserStore struct {
 	store   map[string]*TempUserEntry
 	mutex   sync.RWMutex
-	cleanup chan struct{}
+	janitor *janitor
 }
 
-// NewInMemoryTempUserStore creates a new in-memory temporary user store
+// NewInMemoryTempUserStore creates a new in-memory temporary user store with its cleanup goroutine.
+// Create it once: its owner calls Close on shutdown.
 func NewInMemoryTempUserStore() *InMemoryTempUserStore {
 	store := &InMemoryTempUserStore{
-		store:   make(map[string]*TempUserEntry),
-		cleanup: make(chan struct{}),
+		store: make(map[string]*TempUserEntry),
 	}
-
-	// Start cleanup goroutine
-	go store.startCleanup()
+	store.janitor = startJanitor("temp_user", 2*time.Minute, store.cleanupExpired)
 
 	return store
 }
 
-// Store stores a user temporarily with 10-minute expiration
+// Store stores a user temporarily, expiring after domain.PendingRegistrationTTL
 func (s *InMemoryTempUserStore) Store(ctx context.Context, email string, user *domain.User) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
 	s.store[email] = &TempUserEntry{
 		User:      user,
-		ExpiresAt: time.Now().Add(10 * time.Minute),
+		ExpiresAt: time.Now().Add(domain.PendingRegistrationTTL),
 	}
 
 	return nil
@@ -65,6 +63,19 @@ func (s *InMemoryTempUserStore) Get(ctx context.Context, email string) (*domain.
 	return entry.User, nil
 }
 
+// GetPending retrieves the pending registration of an email, expired ones included until cleaned up
+func (s *InMemoryTempUserStore) GetPending(ctx context.Context, email string) (*domain.PendingRegistration, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	entry, exists := s.store[email]
+	if !exists {
+		return nil, domain.ErrUserNotFound
+	}
+
+	return &domain.PendingRegistration{User: entry.User, ExpiresAt: entry.ExpiresAt}, nil
+}
+
 // Delete removes a temporary user by email
 func (s *InMemoryTempUserStore) Delete(ctx context.Context, email string) error {
 	s.mutex.Lock()
@@ -74,35 +85,20 @@ func (s *InMemoryTempUserStore) Delete(ctx context.Context, email string) error
 	return nil
 }
 
-// startCleanup starts a goroutine to clean up expired temporary users
-func (s *InMemoryTempUserStore) startCleanup() {
-	ticker := time.NewTicker(2 * time.Minute)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ticker.C:
-			s.cleanupExpired()
-		case <-s.cleanup:
-			return
-		}
-	}
-}
-
-// cleanupExpired removes expired temporary users from the store
+// cleanupExpired removes the temporary users expired for longer than domain.ExpiredRegistrationRetention
 func (s *InMemoryTempUserStore) cleanupExpired() {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
 	now := time.Now()
 	for email, entry := range s.store {
-		if now.After(entry.ExpiresAt) {
+		if now.After(entry.ExpiresAt.Add(domain.ExpiredRegistrationRetention)) {
 			delete(s.store, email)
 		}
 	}
 }
 
-// Close stops the cleanup goroutine
+// Close stops the cleanup goroutine, waiting for it to return
 func (s *InMemoryTempUserStore) Close() {
-	close(s.cleanup)
+	s.janitor.Close()
 }
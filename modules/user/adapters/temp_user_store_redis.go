@@ -0,0 +1,90 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"tixgo/modules/user/domain"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/redis/go-redis/v9"
+)
+
+const tempUserKeyPrefix = "registration:"
+
+// pendingRegistrationRecord is the redis value of a pending registration
+type pendingRegistrationRecord struct {
+	User      *domain.User `json:"user"`
+	ExpiresAt time.Time    `json:"expires_at"`
+}
+
+// RedisTempUserStore implements the TempUserStore interface with redis, so a registration started on
+// one instance can be verified on another. The key outlives the registration by
+// domain.ExpiredRegistrationRetention to tell an expired registration from an unknown one.
+type RedisTempUserStore struct {
+	client redis.UniversalClient
+}
+
+// NewRedisTempUserStore creates a temporary user store backed by redis
+func NewRedisTempUserStore(client redis.UniversalClient) *RedisTempUserStore {
+	return &RedisTempUserStore{client: client}
+}
+
+// Store stores a user temporarily, expiring after domain.PendingRegistrationTTL
+func (s *RedisTempUserStore) Store(ctx context.Context, email string, user *domain.User) error {
+	value, err := json.Marshal(&pendingRegistrationRecord{
+		User:      user,
+		ExpiresAt: time.Now().Add(domain.PendingRegistrationTTL),
+	})
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to encode pending registration")
+	}
+
+	ttl := domain.PendingRegistrationTTL + domain.ExpiredRegistrationRetention
+	if err := s.client.Set(ctx, tempUserKeyPrefix+email, value, ttl).Err(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to store pending registration")
+	}
+
+	return nil
+}
+
+// Get retrieves a temporary user by email
+func (s *RedisTempUserStore) Get(ctx context.Context, email string) (*domain.User, error) {
+	pending, err := s.GetPending(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if pending.IsExpired(time.Now()) {
+		return nil, domain.ErrUserNotFound
+	}
+
+	return pending.User, nil
+}
+
+// GetPending retrieves the pending registration of an email, expired ones included
+func (s *RedisTempUserStore) GetPending(ctx context.Context, email string) (*domain.PendingRegistration, error) {
+	value, err := s.client.Get(ctx, tempUserKeyPrefix+email).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get pending registration")
+	}
+
+	var record pendingRegistrationRecord
+	if err := json.Unmarshal(value, &record); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to decode pending registration")
+	}
+
+	return &domain.PendingRegistration{User: record.User, ExpiresAt: record.ExpiresAt}, nil
+}
+
+// Delete removes a temporary user by email
+func (s *RedisTempUserStore) Delete(ctx context.Context, email string) error {
+	if err := s.client.Del(ctx, tempUserKeyPrefix+email).Err(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to delete pending registration")
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package adapters
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tixgo/modules/user/domain"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedisClient(t *testing.T) (redis.UniversalClient, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return client, server
+}
+
+func TestRedisTempUserStore(t *testing.T) {
+	ctx := context.Background()
+	email := "test@example.com"
+
+	user, err := domain.NewUser(email, "password123", "John", "Doe", domain.UserTypeOrganizer)
+	require.NoError(t, err)
+
+	t.Run("keeps a pending registration until it expires", func(t *testing.T) {
+		client, _ := newTestRedisClient(t)
+		store := NewRedisTempUserStore(client)
+
+		require.NoError(t, store.Store(ctx, email, user))
+
+		stored, err := store.Get(ctx, email)
+		require.NoError(t, err)
+		assert.Equal(t, user.Email, stored.Email)
+		assert.Equal(t, user.PasswordHash, stored.PasswordHash)
+		assert.Equal(t, domain.UserTypeOrganizer, stored.UserType)
+
+		pending, err := store.GetPending(ctx, email)
+		require.NoError(t, err)
+		assert.False(t, pending.IsExpired(time.Now()))
+		assert.True(t, pending.IsExpired(time.Now().Add(domain.PendingRegistrationTTL)))
+	})
+
+	t.Run("remembers an expired registration for the retention", func(t *testing.T) {
+		client, server := newTestRedisClient(t)
+		store := NewRedisTempUserStore(client)
+
+		require.NoError(t, store.Store(ctx, email, user))
+		assert.Equal(t, domain.PendingRegistrationTTL+domain.ExpiredRegistrationRetention, server.TTL(tempUserKeyPrefix+email))
+
+		server.FastForward(domain.PendingRegistrationTTL + domain.ExpiredRegistrationRetention)
+		_, err := store.GetPending(ctx, email)
+		assert.Equal(t, domain.ErrUserNotFound, err)
+	})
+
+	t.Run("deletes a registration", func(t *testing.T) {
+		client, _ := newTestRedisClient(t)
+		store := NewRedisTempUserStore(client)
+
+		require.NoError(t, store.Store(ctx, email, user))
+		require.NoError(t, store.Delete(ctx, email))
+
+		_, err := store.Get(ctx, email)
+		assert.Equal(t, domain.ErrUserNotFound, err)
+	})
+}
@@ -0,0 +1,120 @@
+package adapters
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"tixgo/modules/user/domain"
+	"tixgo/shared/cache"
+
+	"github.com/duongptryu/gox/logger"
+)
+
+// CachedUserRepository serves GetByID from the cache and invalidates it on writes. The cache is shared
+// by every instance, so only a cachedUser is kept there: users it serves have no PasswordHash nor
+// DateOfBirth, which logins read by email from the database.
+type CachedUserRepository struct {
+	domain.UserRepository
+	cache *cache.Cache
+}
+
+// NewCachedUserRepository wraps repo with a read-through cache
+func NewCachedUserRepository(repo domain.UserRepository, c *cache.Cache) *CachedUserRepository {
+	return &CachedUserRepository{UserRepository: repo, cache: c}
+}
+
+// GetByID retrieves a user by ID
+func (r *CachedUserRepository) GetByID(ctx context.Context, id int64) (*domain.User, error) {
+	cached, err := cache.GetOrLoad(ctx, r.cache, userCacheKey(id), func(ctx context.Context) (*cachedUser, error) {
+		user, err := r.UserRepository.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return newCachedUser(user), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cached.toDomain(), nil
+}
+
+// Update updates an existing user
+func (r *CachedUserRepository) Update(ctx context.Context, user *domain.User) error {
+	if err := r.UserRepository.Update(ctx, user); err != nil {
+		return err
+	}
+	r.invalidate(ctx, user.ID)
+	return nil
+}
+
+// Delete deletes a user by ID
+func (r *CachedUserRepository) Delete(ctx context.Context, id int64) error {
+	if err := r.UserRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.invalidate(ctx, id)
+	return nil
+}
+
+// invalidate drops the cached user. The write already succeeded, so a failure is only logged:
+// the entry then expires with the cache TTL.
+func (r *CachedUserRepository) invalidate(ctx context.Context, id int64) {
+	if err := r.cache.Delete(ctx, userCacheKey(id)); err != nil {
+		logger.Error(ctx, "Failed to invalidate cached user", logger.F("user_id", id), logger.F("error", err))
+	}
+}
+
+func userCacheKey(id int64) string {
+	return "user:" + strconv.FormatInt(id, 10)
+}
+
+// cachedUser is what the cache keeps of a user: no credentials nor personal data its readers do not need
+type cachedUser struct {
+	ID            int64
+	Email         string
+	FirstName     string
+	LastName      string
+	Phone         *string
+	PhoneCountry  *string
+	UserType      domain.UserType
+	Status        domain.UserStatus
+	EmailVerified bool
+	PhoneVerified bool
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func newCachedUser(user *domain.User) *cachedUser {
+	return &cachedUser{
+		ID:            user.ID,
+		Email:         user.Email,
+		FirstName:     user.FirstName,
+		LastName:      user.LastName,
+		Phone:         user.Phone,
+		PhoneCountry:  user.PhoneCountry,
+		UserType:      user.UserType,
+		Status:        user.Status,
+		EmailVerified: user.EmailVerified,
+		PhoneVerified: user.PhoneVerified,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+	}
+}
+
+func (u *cachedUser) toDomain() *domain.User {
+	return &domain.User{
+		ID:            u.ID,
+		Email:         u.Email,
+		FirstName:     u.FirstName,
+		LastName:      u.LastName,
+		Phone:         u.Phone,
+		PhoneCountry:  u.PhoneCountry,
+		UserType:      u.UserType,
+		Status:        u.Status,
+		EmailVerified: u.EmailVerified,
+		PhoneVerified: u.PhoneVerified,
+		CreatedAt:     u.CreatedAt,
+		UpdatedAt:     u.UpdatedAt,
+	}
+}
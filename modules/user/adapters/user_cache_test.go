@@ -0,0 +1,66 @@
+package adapters
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tixgo/modules/user/domain"
+	"tixgo/shared/cache"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type usersByID struct {
+	domain.UserRepository
+	user  *domain.User
+	calls int
+}
+
+func (r *usersByID) GetByID(ctx context.Context, id int64) (*domain.User, error) {
+	r.calls++
+	if r.user.ID != id {
+		return nil, domain.ErrUserNotFound
+	}
+	copied := *r.user
+	return &copied, nil
+}
+
+func TestCachedUserRepository_GetByID(t *testing.T) {
+	ctx := context.Background()
+	client, server := newTestRedisClient(t)
+
+	user, err := domain.NewUser("john@example.com", "password123", "John", "Doe", domain.UserTypeCustomer)
+	require.NoError(t, err)
+	user.ID = 42
+	user.CreatedAt = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	user.UpdatedAt = user.CreatedAt
+	phone, dateOfBirth := "+84901234567", time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)
+	user.Phone, user.DateOfBirth = &phone, &dateOfBirth
+
+	users := &usersByID{user: user}
+	repo := NewCachedUserRepository(users, cache.New(client, cache.DefaultConfig()))
+
+	loaded, err := repo.GetByID(ctx, 42)
+	require.NoError(t, err)
+	cached, err := repo.GetByID(ctx, 42)
+	require.NoError(t, err)
+	assert.Equal(t, 1, users.calls, "the second lookup is cached")
+	assert.Equal(t, loaded, cached)
+
+	assert.Equal(t, "john@example.com", cached.Email)
+	assert.Equal(t, &phone, cached.Phone)
+	assert.Empty(t, cached.PasswordHash)
+	assert.Nil(t, cached.DateOfBirth)
+
+	payload, err := server.Get("cache:" + userCacheKey(42))
+	require.NoError(t, err)
+	assert.Contains(t, payload, "john@example.com")
+	assert.NotContains(t, payload, user.PasswordHash)
+	assert.NotContains(t, payload, "PasswordHash")
+	assert.NotContains(t, payload, "1990-05-17")
+
+	_, err = repo.GetByID(ctx, 7)
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+}
@@ -6,12 +6,74 @@ import (
 	"time"
 
 	"tixgo/modules/user/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/pgerr"
 
 	"github.com/duongptryu/gox/syserr"
 
 	"github.com/jmoiron/sqlx"
 )
 
+// userColumns are the columns of userRow, in the order they are selected
+const userColumns = `id, email, password_hash, first_name, last_name, phone, phone_country, date_of_birth,
+	user_type, status, email_verified, phone_verified, created_at, updated_at`
+
+// userRow is a row of the users table
+type userRow struct {
+	ID            int64             `db:"id"`
+	Email         string            `db:"email"`
+	PasswordHash  string            `db:"password_hash"`
+	FirstName     string            `db:"first_name"`
+	LastName      string            `db:"last_name"`
+	Phone         *string           `db:"phone"`
+	PhoneCountry  *string           `db:"phone_country"`
+	DateOfBirth   *time.Time        `db:"date_of_birth"`
+	UserType      domain.UserType   `db:"user_type"`
+	Status        domain.UserStatus `db:"status"`
+	EmailVerified bool              `db:"email_verified"`
+	PhoneVerified bool              `db:"phone_verified"`
+	CreatedAt     time.Time         `db:"created_at"`
+	UpdatedAt     time.Time         `db:"updated_at"`
+}
+
+func newUserRow(user *domain.User) *userRow {
+	return &userRow{
+		ID:            user.ID,
+		Email:         user.Email,
+		PasswordHash:  user.PasswordHash,
+		FirstName:     user.FirstName,
+		LastName:      user.LastName,
+		Phone:         user.Phone,
+		PhoneCountry:  user.PhoneCountry,
+		DateOfBirth:   user.DateOfBirth,
+		UserType:      user.UserType,
+		Status:        user.Status,
+		EmailVerified: user.EmailVerified,
+		PhoneVerified: user.PhoneVerified,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+	}
+}
+
+func (row *userRow) toDomain() *domain.User {
+	return &domain.User{
+		ID:            row.ID,
+		Email:         row.Email,
+		PasswordHash:  row.PasswordHash,
+		FirstName:     row.FirstName,
+		LastName:      row.LastName,
+		Phone:         row.Phone,
+		PhoneCountry:  row.PhoneCountry,
+		DateOfBirth:   row.DateOfBirth,
+		UserType:      row.UserType,
+		Status:        row.Status,
+		EmailVerified: row.EmailVerified,
+		PhoneVerified: row.PhoneVerified,
+		CreatedAt:     row.CreatedAt,
+		UpdatedAt:     row.UpdatedAt,
+	}
+}
+
 // UserPostgresRepository implements the UserRepository interface using PostgreSQL
 type UserPostgresRepository struct {
 	db *sqlx.DB
@@ -24,28 +86,23 @@ func NewUserPostgresRepository(db *sqlx.DB) *UserPostgresRepository {
 
 // Create creates a new user in the database
 func (r *UserPostgresRepository) Create(ctx context.Context, user *domain.User) error {
-	query := `
-		INSERT INTO users (email, password_hash, first_name, last_name, phone, date_of_birth, user_type, status, email_verified, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
-		RETURNING id`
-
-	err := r.db.QueryRowContext(
-		ctx,
-		query,
-		user.Email,
-		user.PasswordHash,
-		user.FirstName,
-		user.LastName,
-		user.Phone,
-		user.DateOfBirth,
-		user.UserType,
-		user.Status,
-		user.EmailVerified,
-		user.CreatedAt,
-		user.UpdatedAt,
-	).Scan(&user.ID)
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
 
+	query, args, err := r.db.BindNamed(`
+		INSERT INTO users (email, password_hash, first_name, last_name, phone, phone_country, date_of_birth, user_type, status, email_verified, phone_verified, created_at, updated_at)
+		VALUES (:email, :password_hash, :first_name, :last_name, :phone, :phone_country, :date_of_birth, :user_type, :status, :email_verified, :phone_verified, :created_at, :updated_at)
+		RETURNING id`, newUserRow(user))
 	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to bind user")
+	}
+
+	err = r.db.GetContext(ctx, &user.ID, query, args...)
+	if err != nil {
+		// emails are unique, concurrent registrations of one email are told apart here
+		if pgerr.IsUniqueViolation(err) {
+			return domain.ErrUserAlreadyExists
+		}
 		return syserr.Wrap(err, syserr.InternalCode, "failed to create user")
 	}
 
@@ -54,29 +111,11 @@ func (r *UserPostgresRepository) Create(ctx context.Context, user *domain.User)
 
 // GetByID retrieves a user by ID
 func (r *UserPostgresRepository) GetByID(ctx context.Context, id int64) (*domain.User, error) {
-	query := `
-		SELECT id, email, password_hash, first_name, last_name, phone, date_of_birth, 
-		       user_type, status, email_verified, created_at, updated_at, last_login
-		FROM users 
-		WHERE id = $1`
-
-	user := &domain.User{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&user.ID,
-		&user.Email,
-		&user.PasswordHash,
-		&user.FirstName,
-		&user.LastName,
-		&user.Phone,
-		&user.DateOfBirth,
-		&user.UserType,
-		&user.Status,
-		&user.EmailVerified,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-		&user.LastLogin,
-	)
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
 
+	row := &userRow{}
+	err := r.db.GetContext(ctx, row, `SELECT `+userColumns+` FROM users WHERE id = $1`, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, domain.ErrUserNotFound
@@ -84,34 +123,16 @@ func (r *UserPostgresRepository) GetByID(ctx context.Context, id int64) (*domain
 		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get user by ID")
 	}
 
-	return user, nil
+	return row.toDomain(), nil
 }
 
 // GetByEmail retrieves a user by email
 func (r *UserPostgresRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
-	query := `
-		SELECT id, email, password_hash, first_name, last_name, phone, date_of_birth, 
-		       user_type, status, email_verified, created_at, updated_at, last_login
-		FROM users 
-		WHERE email = $1`
-
-	user := &domain.User{}
-	err := r.db.QueryRowContext(ctx, query, email).Scan(
-		&user.ID,
-		&user.Email,
-		&user.PasswordHash,
-		&user.FirstName,
-		&user.LastName,
-		&user.Phone,
-		&user.DateOfBirth,
-		&user.UserType,
-		&user.Status,
-		&user.EmailVerified,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-		&user.LastLogin,
-	)
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
 
+	row := &userRow{}
+	err := r.db.GetContext(ctx, row, `SELECT `+userColumns+` FROM users WHERE email = $1`, email)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, domain.ErrUserNotFound
@@ -119,37 +140,24 @@ func (r *UserPostgresRepository) GetByEmail(ctx context.Context, email string) (
 		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get user by email")
 	}
 
-	return user, nil
+	return row.toDomain(), nil
 }
 
-// Update updates an existing user
+// Update updates an existing user, keeping the stored password hash and date of birth when it has none
 func (r *UserPostgresRepository) Update(ctx context.Context, user *domain.User) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
 		UPDATE users 
-		SET email = $2, password_hash = $3, first_name = $4, last_name = $5, 
-		    phone = $6, date_of_birth = $7, user_type = $8, status = $9, 
-		    email_verified = $10, updated_at = $11, last_login = $12
-		WHERE id = $1`
+		SET email = :email, password_hash = COALESCE(NULLIF(:password_hash, ''), password_hash), first_name = :first_name, last_name = :last_name, 
+		    phone = :phone, phone_country = :phone_country, date_of_birth = COALESCE(:date_of_birth, date_of_birth), user_type = :user_type, status = :status, 
+		    email_verified = :email_verified, phone_verified = :phone_verified, updated_at = :updated_at
+		WHERE id = :id`
 
 	user.UpdatedAt = time.Now()
 
-	result, err := r.db.ExecContext(
-		ctx,
-		query,
-		user.ID,
-		user.Email,
-		user.PasswordHash,
-		user.FirstName,
-		user.LastName,
-		user.Phone,
-		user.DateOfBirth,
-		user.UserType,
-		user.Status,
-		user.EmailVerified,
-		user.UpdatedAt,
-		user.LastLogin,
-	)
-
+	result, err := r.db.NamedExecContext(ctx, query, newUserRow(user))
 	if err != nil {
 		return syserr.Wrap(err, syserr.InternalCode, "failed to update user")
 	}
@@ -168,6 +176,9 @@ func (r *UserPostgresRepository) Update(ctx context.Context, user *domain.User)
 
 // Delete deletes a user by ID
 func (r *UserPostgresRepository) Delete(ctx context.Context, id int64) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
 	query := `DELETE FROM users WHERE id = $1`
 
 	result, err := r.db.ExecContext(ctx, query, id)
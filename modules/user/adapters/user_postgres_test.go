@@ -0,0 +1,91 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"tixgo/modules/user/domain"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/reflectx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRow_RoundTrip(t *testing.T) {
+	phone, phoneCountry := "+84901234567", "VN"
+	dateOfBirth := time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)
+
+	user := &domain.User{
+		ID:            42,
+		Email:         "john@example.com",
+		PasswordHash:  "hash",
+		FirstName:     "John",
+		LastName:      "Doe",
+		Phone:         &phone,
+		PhoneCountry:  &phoneCountry,
+		DateOfBirth:   &dateOfBirth,
+		UserType:      domain.UserTypeOrganizer,
+		Status:        domain.UserStatusSuspended,
+		EmailVerified: true,
+		PhoneVerified: true,
+		CreatedAt:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt:     time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	assert.Equal(t, user, newUserRow(user).toDomain())
+}
+
+func TestUserRow_Columns(t *testing.T) {
+	// every selected column must land in a field and every field must be selected,
+	// a new column otherwise fails to scan or silently stays zero
+	fields := reflectx.NewMapperFunc("db", sqlx.NameMapper).TypeMap(reflect.TypeOf(userRow{})).Names
+
+	var columns []string
+	for _, column := range strings.Split(userColumns, ",") {
+		column = strings.TrimSpace(column)
+		columns = append(columns, column)
+		assert.Contains(t, fields, column)
+	}
+	assert.Len(t, fields, len(columns))
+}
+
+// failingConnector opens connections whose queries all fail with err, as Postgres answers them
+type failingConnector struct{ err error }
+
+func (c failingConnector) Connect(context.Context) (driver.Conn, error) { return failingConn(c), nil }
+func (c failingConnector) Driver() driver.Driver                        { return nil }
+
+type failingConn struct{ err error }
+
+func (c failingConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return nil, c.err
+}
+func (c failingConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c failingConn) Close() error                        { return nil }
+func (c failingConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func TestUserPostgresRepository_Create(t *testing.T) {
+	user, err := domain.NewUserCustomer("john@example.com", "password123", "John", "Doe")
+	require.NoError(t, err)
+
+	create := func(queryErr error) error {
+		db := sqlx.NewDb(sql.OpenDB(failingConnector{err: queryErr}), "postgres")
+		defer db.Close()
+		return NewUserPostgresRepository(db).Create(context.Background(), user)
+	}
+
+	err = create(&pq.Error{Code: "23505", Constraint: "users_email_key"})
+	assert.ErrorIs(t, err, domain.ErrUserAlreadyExists, "a concurrent registration of the email won")
+
+	err = create(&pq.Error{Code: "23503"})
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, domain.ErrUserAlreadyExists)
+}
@@ -0,0 +1,76 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/user/domain"
+	"tixgo/shared/phone"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ConfirmPhoneVerificationCommand confirms a phone number with the code texted to it
+type ConfirmPhoneVerificationCommand struct {
+	UserID int64 `json:"-"`
+	// Phone and Country are given as when the code was requested
+	Phone   string `json:"phone" binding:"required,max=32"`
+	Country string `json:"country" binding:"omitempty,iso3166_1_alpha2"`
+	OTP     string `json:"otp" binding:"required,len=6"`
+}
+
+// ConfirmPhoneVerificationResult is the confirmed phone number of the user
+type ConfirmPhoneVerificationResult struct {
+	Phone         string `json:"phone"`
+	PhoneCountry  string `json:"phone_country"`
+	PhoneVerified bool   `json:"phone_verified"`
+}
+
+// ConfirmPhoneVerificationHandler sets the phone number of a user once confirmed
+type ConfirmPhoneVerificationHandler struct {
+	userRepo domain.UserRepository
+	otpStore domain.OTPStore
+}
+
+// NewConfirmPhoneVerificationHandler creates a new confirm phone verification handler. otpStore must be
+// the phone namespace of the codes.
+func NewConfirmPhoneVerificationHandler(userRepo domain.UserRepository, otpStore domain.OTPStore) *ConfirmPhoneVerificationHandler {
+	return &ConfirmPhoneVerificationHandler{
+		userRepo: userRepo,
+		otpStore: otpStore,
+	}
+}
+
+// Handle executes the confirm phone verification command, the number being stored in E.164 format
+// with its country
+func (h *ConfirmPhoneVerificationHandler) Handle(ctx context.Context, cmd *ConfirmPhoneVerificationCommand) (*ConfirmPhoneVerificationResult, error) {
+	number, err := phone.Parse(cmd.Phone, cmd.Country)
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.otpStore.Verify(ctx, PhoneOTPKey(cmd.UserID, number.E164), cmd.OTP)
+	if err != nil {
+		return nil, domain.ErrInvalidOTP
+	}
+
+	user, err := h.userRepo.GetByID(ctx, cmd.UserID)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get user")
+	}
+
+	user.VerifyPhone(number)
+
+	err = h.userRepo.Update(ctx, user)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to update user")
+	}
+
+	return &ConfirmPhoneVerificationResult{
+		Phone:         *user.Phone,
+		PhoneCountry:  *user.PhoneCountry,
+		PhoneVerified: user.PhoneVerified,
+	}, nil
+}
@@ -0,0 +1,136 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"tixgo/modules/user/domain"
+	"tixgo/shared/session"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryConsentRepository keeps the consent history in memory, failing to record when err is set
+type memoryConsentRepository struct {
+	domain.ConsentRepository
+	consents []*domain.Consent
+	err      error
+}
+
+func (r *memoryConsentRepository) Record(_ context.Context, consents ...*domain.Consent) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.consents = append(r.consents, consents...)
+	return nil
+}
+
+func (r *memoryConsentRepository) Latest(_ context.Context, userID int64) (map[domain.ConsentKind]*domain.Consent, error) {
+	latest := map[domain.ConsentKind]*domain.Consent{}
+	for _, consent := range r.consents {
+		if consent.UserID == userID {
+			latest[consent.Kind] = consent
+		}
+	}
+	return latest, nil
+}
+
+var testConsentPolicy = domain.ConsentPolicy{TermsVersion: "2026-10", PrivacyVersion: "2026-09"}
+
+func TestRegisterUserHandler_Consents(t *testing.T) {
+	tempUserStore := &memoryTempUserStore{}
+	handler := NewRegisterUserHandler(noUsers{}, tempUserStore, &memoryOTPStore{}, allowDeduplicator{}, &recordingBus{}, domain.EmailPolicy{}, testConsentPolicy, nil)
+
+	cmd := &RegisterUserCommand{
+		Email:              "user@example.com",
+		Password:           "password123",
+		FirstName:          "Jane",
+		LastName:           "Doe",
+		AcceptTermsVersion: "2026-10",
+		IPAddress:          "203.0.113.7",
+	}
+	_, err := handler.Handle(context.Background(), cmd)
+	assert.Equal(t, domain.ErrConsentRequired, err, "the privacy policy is not accepted")
+	assert.Empty(t, tempUserStore.users)
+
+	cmd.AcceptPrivacyVersion = "2026-09"
+	cmd.MarketingConsent = true
+	_, err = handler.Handle(context.Background(), cmd)
+	require.NoError(t, err)
+
+	consents := tempUserStore.users["user@example.com"].Consents
+	require.Len(t, consents, 3)
+	assert.Equal(t, domain.ConsentKindTerms, consents[0].Kind)
+	assert.Equal(t, domain.ConsentKindPrivacy, consents[1].Kind)
+	assert.Equal(t, domain.ConsentKindMarketing, consents[2].Kind)
+	assert.True(t, consents[2].Granted)
+	assert.Equal(t, domain.ConsentSourceRegistration, consents[0].Source)
+	assert.Equal(t, "203.0.113.7", consents[0].IPAddress)
+}
+
+func TestVerifyOTPHandler_RecordsConsents(t *testing.T) {
+	user, err := domain.NewUserCustomer("user@example.com", "password123", "Jane", "Doe")
+	require.NoError(t, err)
+	user.Consents, err = testConsentPolicy.Accept(0, testConsentPolicy.Outdated(nil), "2026-10", "2026-09", domain.ConsentSourceRegistration, "", "")
+	require.NoError(t, err)
+
+	tempUserStore := &memoryTempUserStore{users: map[string]*domain.User{"user@example.com": user}}
+	otpStore := &memoryOTPStore{otps: map[string]string{"user@example.com": "123456"}}
+	consentRepo := &memoryConsentRepository{}
+
+	result, err := NewVerifyOTPHandler(&memoryUserRepository{users: map[int64]*domain.User{}}, tempUserStore, otpStore, consentRepo, domain.EmailPolicy{}).
+		Handle(context.Background(), &VerifyOTPCommand{Email: "user@example.com", OTP: "123456"})
+	require.NoError(t, err)
+
+	require.Len(t, consentRepo.consents, 2)
+	for _, consent := range consentRepo.consents {
+		assert.Equal(t, result.UserID, consent.UserID, "the consents get the ID of the user created")
+	}
+}
+
+func TestLoginUserHandler_Consents(t *testing.T) {
+	user, err := domain.NewUserCustomer("user@example.com", "password123", "Jane", "Doe")
+	require.NoError(t, err)
+	user.ID = 7
+	user.VerifyEmail()
+
+	sessions := session.NewService("secret", "tixgo-test", "tixgo-api", session.Policy{Default: session.Lifetime{Access: time.Minute, Refresh: time.Hour}})
+	loginEvents := loginEventRepositoryFunc(func(context.Context, *domain.LoginEvent) error { return nil })
+	login := func(consentRepo *memoryConsentRepository, cmd LoginUserCommand) (*LoginUserResult, error) {
+		cmd.Email, cmd.Password = "user@example.com", "password123"
+		return NewLoginUserHandler(singleUserRepository{user: user}, loginEvents, consentRepo, sessions, &recordingBus{}, domain.EmailPolicy{}, testConsentPolicy).
+			Handle(context.Background(), &cmd)
+	}
+
+	consentRepo := &memoryConsentRepository{consents: []*domain.Consent{
+		domain.NewConsent(7, domain.ConsentKindTerms, "2026-01", true, domain.ConsentSourceRegistration, "", ""),
+		domain.NewConsent(7, domain.ConsentKindPrivacy, "2026-09", true, domain.ConsentSourceRegistration, "", ""),
+	}}
+
+	_, err = login(consentRepo, LoginUserCommand{})
+	assert.Equal(t, domain.ErrConsentRequired, err, "the terms were updated since the registration")
+
+	_, err = login(consentRepo, LoginUserCommand{AcceptTermsVersion: "2026-01"})
+	assert.Equal(t, domain.ErrConsentRequired, err)
+
+	result, err := login(consentRepo, LoginUserCommand{AcceptTermsVersion: "2026-10", IPAddress: "203.0.113.7"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, result.AccessToken)
+	require.Len(t, consentRepo.consents, 3, "only the updated terms are accepted again")
+	accepted := consentRepo.consents[2]
+	assert.Equal(t, domain.ConsentKindTerms, accepted.Kind)
+	assert.Equal(t, "2026-10", accepted.Version)
+	assert.Equal(t, domain.ConsentSourceLogin, accepted.Source)
+	assert.Equal(t, "203.0.113.7", accepted.IPAddress)
+
+	_, err = login(consentRepo, LoginUserCommand{})
+	require.NoError(t, err, "the current versions are accepted")
+	assert.Len(t, consentRepo.consents, 3)
+
+	consentRepo = &memoryConsentRepository{err: errors.New("database unavailable")}
+	_, err = login(consentRepo, LoginUserCommand{AcceptTermsVersion: "2026-10", AcceptPrivacyVersion: "2026-09"})
+	assert.Error(t, err, "no login without the consents recorded")
+}
@@ -5,7 +5,13 @@ import (
 	"strconv"
 
 	"tixgo/modules/user/domain"
-	"github.com/duongptryu/gox/auth"
+	"tixgo/shared/errstack"
+	sharedActivity "tixgo/shared/events/activity"
+	sharedKafka "tixgo/shared/kafka"
+	"tixgo/shared/session"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
 	"github.com/duongptryu/gox/syserr"
 )
 
@@ -13,6 +19,17 @@ import (
 type LoginUserCommand struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	// Client is the kind of client logging in, which picks the lifetime of the tokens; web by default
+	Client string `json:"client" binding:"omitempty,oneof=web mobile"`
+	// RememberMe extends the session up to the configured maximum
+	RememberMe bool `json:"remember_me"`
+	// AcceptTermsVersion and AcceptPrivacyVersion are the versions of the terms of service and privacy
+	// policy the user accepts, asked for with consent_required once either was updated
+	AcceptTermsVersion   string `json:"accept_terms_version" binding:"max=50"`
+	AcceptPrivacyVersion string `json:"accept_privacy_version" binding:"max=50"`
+	// IPAddress and UserAgent describe the client in the activity feed of the user
+	IPAddress string `json:"-"`
+	UserAgent string `json:"-"`
 }
 
 // LoginUserResult represents the result of user login
@@ -21,26 +38,53 @@ type LoginUserResult struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
 	ExpiresIn    int64  `json:"expires_in"`
+	// RefreshExpiresIn is how long the refresh token is valid, in seconds
+	RefreshExpiresIn int64 `json:"refresh_expires_in"`
 }
 
 // LoginUserHandler handles user login
 type LoginUserHandler struct {
-	userRepo   domain.UserRepository
-	jwtService *auth.JWTService
+	userRepo       domain.UserRepository
+	loginEventRepo domain.LoginEventRepository
+	consentRepo    domain.ConsentRepository
+	sessions       *session.Service
+	eventBus       messaging.EventBus
+	emailPolicy    domain.EmailPolicy
+	consentPolicy  domain.ConsentPolicy
 }
 
 // NewLoginUserHandler creates a new login user handler
-func NewLoginUserHandler(userRepo domain.UserRepository, jwtService *auth.JWTService) *LoginUserHandler {
+func NewLoginUserHandler(userRepo domain.UserRepository, loginEventRepo domain.LoginEventRepository, consentRepo domain.ConsentRepository, sessions *session.Service, eventBus messaging.EventBus, emailPolicy domain.EmailPolicy, consentPolicy domain.ConsentPolicy) *LoginUserHandler {
 	return &LoginUserHandler{
-		userRepo:   userRepo,
-		jwtService: jwtService,
+		userRepo:       userRepo,
+		loginEventRepo: loginEventRepo,
+		consentRepo:    consentRepo,
+		sessions:       sessions,
+		eventBus:       eventBus,
+		emailPolicy:    emailPolicy,
+		consentPolicy:  consentPolicy,
 	}
 }
 
 // Handle executes the login user command
 func (h *LoginUserHandler) Handle(ctx context.Context, cmd *LoginUserCommand) (*LoginUserResult, error) {
-	// Get user by email
-	user, err := h.userRepo.GetByEmail(ctx, cmd.Email)
+	// Resolve the client first, a request that cannot get tokens is not a login
+	client := session.ClientWeb
+	if cmd.Client != "" {
+		client = session.Client(cmd.Client)
+	}
+	if !client.IsValid() {
+		return nil, errstack.New(syserr.InvalidArgumentCode, "client must be web or mobile")
+	}
+
+	// Get user by email, under any of the spellings it may be stored with
+	var user *domain.User
+	var err error
+	for _, email := range h.emailPolicy.LookupEmails(cmd.Email) {
+		if user, err = h.userRepo.GetByEmail(ctx, email); err != domain.ErrUserNotFound {
+			break
+		}
+	}
 	if err != nil {
 		if err == domain.ErrUserNotFound {
 			return nil, domain.ErrInvalidCredentials
@@ -60,23 +104,65 @@ func (h *LoginUserHandler) Handle(ctx context.Context, cmd *LoginUserCommand) (*
 		return nil, err
 	}
 
-	// Update last login
-	user.UpdateLastLogin()
-	err = h.userRepo.Update(ctx, user)
+	err = h.acceptConsents(ctx, user, cmd)
 	if err != nil {
-		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to update last login")
+		return nil, err
+	}
+
+	// Record the login rather than updating the user row, a failure must not fail the login
+	err = h.loginEventRepo.Record(ctx, domain.NewLoginEvent(user.ID, cmd.IPAddress, cmd.UserAgent))
+	if err != nil {
+		logger.Warning(ctx, "Failed to record login event", logger.F("user_id", user.ID), logger.F("error", err))
 	}
 
 	// Generate JWT tokens
-	accessToken, refreshToken, expiresIn, err := h.jwtService.GenerateTokenPair(ctx, strconv.FormatInt(user.ID, 10), string(user.UserType))
+	tokens, err := h.sessions.GenerateTokenPair(ctx, strconv.FormatInt(user.ID, 10), string(user.UserType), client, cmd.RememberMe)
 	if err != nil {
 		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to generate tokens")
 	}
 
+	// Record the login in the activity feed, a failure must not fail the login
+	activity := sharedActivity.NewEventAccountActivity(user.ID, sharedActivity.TypeLogin, nil)
+	activity.IPAddress = cmd.IPAddress
+	activity.UserAgent = cmd.UserAgent
+	err = h.eventBus.PublishEvent(sharedKafka.WithPartitionKey(ctx, strconv.FormatInt(user.ID, 10)), activity)
+	if err != nil {
+		logger.Warning(ctx, "Failed to publish login activity", logger.F("user_id", user.ID), logger.F("error", err))
+	}
+
 	return &LoginUserResult{
-		UserID:       user.ID,
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresIn:    expiresIn,
+		UserID:           user.ID,
+		AccessToken:      tokens.AccessToken,
+		RefreshToken:     tokens.RefreshToken,
+		ExpiresIn:        tokens.ExpiresIn,
+		RefreshExpiresIn: tokens.RefreshExpiresIn,
 	}, nil
 }
+
+// acceptConsents lets the user in once they accepted the current terms of service and privacy policy.
+// The documents updated since they last did must be accepted with the login, or it is refused with
+// ErrConsentRequired.
+func (h *LoginUserHandler) acceptConsents(ctx context.Context, user *domain.User, cmd *LoginUserCommand) error {
+	if len(h.consentPolicy.Outdated(nil)) == 0 {
+		return nil
+	}
+
+	latest, err := h.consentRepo.Latest(ctx, user.ID)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get consents")
+	}
+	outdated := h.consentPolicy.Outdated(latest)
+	if len(outdated) == 0 {
+		return nil
+	}
+
+	consents, err := h.consentPolicy.Accept(user.ID, outdated, cmd.AcceptTermsVersion, cmd.AcceptPrivacyVersion,
+		domain.ConsentSourceLogin, cmd.IPAddress, cmd.UserAgent)
+	if err != nil {
+		return err
+	}
+	if err := h.consentRepo.Record(ctx, consents...); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to record consents")
+	}
+	return nil
+}
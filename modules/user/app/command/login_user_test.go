@@ -0,0 +1,130 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"tixgo/modules/user/domain"
+	sharedActivity "tixgo/shared/events/activity"
+	"tixgo/shared/session"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// singleUserRepository serves one user and fails any write
+type singleUserRepository struct {
+	domain.UserRepository
+	user *domain.User
+}
+
+func (r singleUserRepository) GetByEmail(_ context.Context, email string) (*domain.User, error) {
+	if email != r.user.Email {
+		return nil, domain.ErrUserNotFound
+	}
+	return r.user, nil
+}
+
+func (r singleUserRepository) Update(context.Context, *domain.User) error {
+	return errors.New("the user row must not be updated on login")
+}
+
+type loginEventRepositoryFunc func(ctx context.Context, event *domain.LoginEvent) error
+
+func (f loginEventRepositoryFunc) Record(ctx context.Context, event *domain.LoginEvent) error {
+	return f(ctx, event)
+}
+
+func (f loginEventRepositoryFunc) LastLogin(context.Context, int64) (*time.Time, error) {
+	return nil, nil
+}
+
+func TestLoginUserHandler_RecordsLoginBestEffort(t *testing.T) {
+	user, err := domain.NewUserCustomer("user@example.com", "password123", "Jane", "Doe")
+	require.NoError(t, err)
+	user.ID = 7
+	user.VerifyEmail()
+
+	sessions := session.NewService("secret", "tixgo-test", "tixgo-api", session.Policy{Default: session.Lifetime{Access: time.Minute, Refresh: time.Hour}})
+	cmd := &LoginUserCommand{Email: "user@example.com", Password: "password123", IPAddress: "203.0.113.7", UserAgent: "test"}
+
+	t.Run("records the login", func(t *testing.T) {
+		var recorded *domain.LoginEvent
+		loginEvents := loginEventRepositoryFunc(func(_ context.Context, event *domain.LoginEvent) error {
+			recorded = event
+			return nil
+		})
+
+		result, err := NewLoginUserHandler(singleUserRepository{user: user}, loginEvents, nil, sessions, &recordingBus{}, domain.EmailPolicy{}, domain.ConsentPolicy{}).Handle(context.Background(), cmd)
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), result.UserID)
+
+		require.NotNil(t, recorded)
+		assert.Equal(t, int64(7), recorded.UserID)
+		assert.Equal(t, "203.0.113.7", recorded.IPAddress)
+		assert.Equal(t, "test", recorded.UserAgent)
+	})
+
+	t.Run("logs in when recording fails", func(t *testing.T) {
+		loginEvents := loginEventRepositoryFunc(func(context.Context, *domain.LoginEvent) error {
+			return errors.New("database unavailable")
+		})
+
+		result, err := NewLoginUserHandler(singleUserRepository{user: user}, loginEvents, nil, sessions, &recordingBus{}, domain.EmailPolicy{}, domain.ConsentPolicy{}).Handle(context.Background(), cmd)
+		require.NoError(t, err)
+		assert.NotEmpty(t, result.AccessToken)
+	})
+
+	t.Run("publishes the login to the activity feed", func(t *testing.T) {
+		bus := &recordingBus{}
+		loginEvents := loginEventRepositoryFunc(func(context.Context, *domain.LoginEvent) error { return nil })
+
+		_, err := NewLoginUserHandler(singleUserRepository{user: user}, loginEvents, nil, sessions, bus, domain.EmailPolicy{}, domain.ConsentPolicy{}).Handle(context.Background(), cmd)
+		require.NoError(t, err)
+
+		require.Len(t, bus.published, 1)
+		activity, ok := bus.published[0].(*sharedActivity.EventAccountActivity)
+		require.True(t, ok)
+		assert.Equal(t, int64(7), activity.UserID)
+		assert.Equal(t, sharedActivity.TypeLogin, activity.Type)
+		assert.Equal(t, "203.0.113.7", activity.IPAddress)
+		assert.Equal(t, "test", activity.UserAgent)
+		assert.NotEmpty(t, activity.ID, "redeliveries are recorded once by the ID")
+	})
+
+	t.Run("logs in when the activity cannot be published", func(t *testing.T) {
+		loginEvents := loginEventRepositoryFunc(func(context.Context, *domain.LoginEvent) error { return nil })
+
+		result, err := NewLoginUserHandler(singleUserRepository{user: user}, loginEvents, nil, sessions, failingBus{}, domain.EmailPolicy{}, domain.ConsentPolicy{}).Handle(context.Background(), cmd)
+		require.NoError(t, err)
+		assert.NotEmpty(t, result.AccessToken)
+	})
+}
+
+// failingBus fails every publish
+type failingBus struct{}
+
+func (failingBus) PublishEvent(context.Context, any) error {
+	return errors.New("broker unavailable")
+}
+
+func TestLoginUserHandler_RejectsUnknownClientBeforeRecording(t *testing.T) {
+	user, err := domain.NewUserCustomer("user@example.com", "password123", "Jane", "Doe")
+	require.NoError(t, err)
+	user.ID = 7
+	user.VerifyEmail()
+
+	sessions := session.NewService("secret", "tixgo-test", "tixgo-api", session.Policy{Default: session.Lifetime{Access: time.Minute, Refresh: time.Hour}})
+	loginEvents := loginEventRepositoryFunc(func(context.Context, *domain.LoginEvent) error {
+		t.Error("a login with an unknown client must not be recorded")
+		return nil
+	})
+	bus := &recordingBus{}
+
+	cmd := &LoginUserCommand{Email: "user@example.com", Password: "password123", Client: "desktop"}
+	_, err = NewLoginUserHandler(singleUserRepository{user: user}, loginEvents, nil, sessions, bus, domain.EmailPolicy{}, domain.ConsentPolicy{}).Handle(context.Background(), cmd)
+	assert.Error(t, err)
+	assert.Empty(t, bus.published)
+}
@@ -2,26 +2,66 @@ package command
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"tixgo/modules/user/domain"
+	"tixgo/shared/dedup"
+	"tixgo/shared/dryrun"
+	sharedKafka "tixgo/shared/kafka"
 
+	"github.com/duongptryu/gox/logger"
 	"github.com/duongptryu/gox/messaging"
 	"github.com/duongptryu/gox/syserr"
 )
 
+const (
+	dedupPurposeUserRegistered = "user_registered"
+	// userRegisteredWindow is how long a repeated registration of the same email publishes nothing
+	userRegisteredWindow = time.Minute
+)
+
 // RegisterUserCommand represents the command to register a new user
 type RegisterUserCommand struct {
 	Email     string `json:"email" binding:"required,email"`
 	Password  string `json:"password" binding:"required,min=8"`
 	FirstName string `json:"first_name" binding:"required"`
 	LastName  string `json:"last_name" binding:"required"`
-	UserType  string `json:"-"`
+	// UserType is the kind of account to open, a customer unless asked for an organizer
+	UserType string `json:"user_type" binding:"omitempty,oneof=customer organizer"`
+	// CaptchaToken is the CAPTCHA the user solved, asked for once the registration is challenged
+	CaptchaToken string `json:"captcha_token" binding:"max=4096"`
+	// AcceptTermsVersion and AcceptPrivacyVersion are the versions of the terms of service and privacy
+	// policy the user accepts, which must be the current ones
+	AcceptTermsVersion   string `json:"accept_terms_version" binding:"max=50"`
+	AcceptPrivacyVersion string `json:"accept_privacy_version" binding:"max=50"`
+	// MarketingConsent tells whether the user agrees to receive marketing
+	MarketingConsent bool `json:"marketing_consent"`
+
+	// IPAddress, DeviceID and UserAgent are of the request, which the registration is scored on
+	IPAddress string `json:"-"`
+	DeviceID  string `json:"-"`
+	UserAgent string `json:"-"`
 }
 
-// RegisterUserResult represents the result of user registration
+// RegisterUserResult represents the result of user registration. The verification code is only ever
+// sent by mail: it is generated after the registration is accepted and never part of the response.
 type RegisterUserResult struct {
 	Email string `json:"email"`
-	OTP   string `json:"otp"`
+	// ExpiresAt is when the verification code mailed to Email stops being valid
+	ExpiresAt time.Time `json:"expires_at"`
+	// ResendAfter is the earliest time another code can be mailed to Email
+	ResendAfter time.Time `json:"resend_after"`
+}
+
+// newRegisterUserResult describes the verification code about to be mailed to email
+func newRegisterUserResult(email string) *RegisterUserResult {
+	now := time.Now()
+	return &RegisterUserResult{
+		Email:       email,
+		ExpiresAt:   now.Add(domain.OTPTTL),
+		ResendAfter: now.Add(otpMailWindow),
+	}
 }
 
 // RegisterUserHandler handles user registration
@@ -29,55 +69,142 @@ type RegisterUserHandler struct {
 	userRepo      domain.UserRepository
 	tempUserStore domain.TempUserStore
 	otpStore      domain.OTPStore
+	deduplicator  dedup.Deduplicator
 	eventBus      messaging.EventBus
+	emailPolicy   domain.EmailPolicy
+	consentPolicy domain.ConsentPolicy
+	// screen is nil when registrations are not scored for spam
+	screen *RegistrationScreen
 }
 
 // NewRegisterUserHandler creates a new register user handler
-func NewRegisterUserHandler(userRepo domain.UserRepository, tempUserStore domain.TempUserStore, otpStore domain.OTPStore, eventBus messaging.EventBus) *RegisterUserHandler {
+func NewRegisterUserHandler(userRepo domain.UserRepository, tempUserStore domain.TempUserStore, otpStore domain.OTPStore, deduplicator dedup.Deduplicator, eventBus messaging.EventBus, emailPolicy domain.EmailPolicy, consentPolicy domain.ConsentPolicy, screen *RegistrationScreen) *RegisterUserHandler {
 	return &RegisterUserHandler{
 		userRepo:      userRepo,
 		tempUserStore: tempUserStore,
 		otpStore:      otpStore,
+		deduplicator:  deduplicator,
 		eventBus:      eventBus,
+		emailPolicy:   emailPolicy,
+		consentPolicy: consentPolicy,
+		screen:        screen,
 	}
 }
 
-// Handle executes the register user command
+// Handle executes the register user command. A taken email is rejected up front, before any code is
+// mailed to it; the user is only inserted once the email is verified, where the unique email constraint
+// decides between concurrent registrations, which the lookup here cannot. Registering an email whose registration is pending or
+// expired restarts it: the previous details are replaced and a new code is mailed, unless one just was.
+// The email is normalized by the email policy, which also turns down disposable mailboxes. Registrations
+// are then scored for spam: risky ones must solve a CAPTCHA, riskier ones get an account inactive until
+// an admin approves it and the riskiest are refused. The user must accept the current terms of service
+// and privacy policy, recorded along with their marketing consent once the account is created.
 func (h *RegisterUserHandler) Handle(ctx context.Context, cmd *RegisterUserCommand) (*RegisterUserResult, error) {
-	// Check if user already exists in database
-	existingUser, err := h.userRepo.GetByEmail(ctx, cmd.Email)
-	if err != nil && err != domain.ErrUserNotFound {
-		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to check existing user")
+	userType := domain.UserTypeCustomer
+	if cmd.UserType != "" {
+		if !domain.IsValidUserType(cmd.UserType) {
+			return nil, domain.ErrInvalidUserType
+		}
+		userType = domain.UserType(cmd.UserType)
 	}
-	if existingUser != nil {
-		return nil, domain.ErrUserAlreadyExists
+	if !domain.IsRegistrableUserType(userType) {
+		return nil, domain.ErrUserTypeNotRegistrable
 	}
 
-	// Check if user already exists in temp store (pending verification)
-	tempUser, err := h.tempUserStore.Get(ctx, cmd.Email)
-	if err == nil && tempUser != nil {
-		return nil, domain.ErrUserAlreadyExists
+	email := h.emailPolicy.Normalize(cmd.Email)
+	if err := h.emailPolicy.CheckRegistrable(email); err != nil {
+		return nil, err
+	}
+	if err := h.checkEmailAvailable(ctx, cmd.Email); err != nil {
+		return nil, err
 	}
 
 	// Create new user
-	user, err := domain.NewUserCustomer(cmd.Email, cmd.Password, cmd.FirstName, cmd.LastName)
+	user, err := domain.NewUser(email, cmd.Password, cmd.FirstName, cmd.LastName, userType)
 	if err != nil {
 		return nil, err
 	}
 
-	// Store user temporarily (not in database yet)
-	err = h.tempUserStore.Store(ctx, cmd.Email, user)
+	// The user is not created yet: the consents get its ID when it is
+	user.Consents, err = h.consentPolicy.Accept(0, h.consentPolicy.Outdated(nil), cmd.AcceptTermsVersion, cmd.AcceptPrivacyVersion,
+		domain.ConsentSourceRegistration, cmd.IPAddress, cmd.UserAgent)
+	if err != nil {
+		return nil, err
+	}
+	user.Consents = append(user.Consents, domain.NewConsent(0, domain.ConsentKindMarketing, "", cmd.MarketingConsent,
+		domain.ConsentSourceRegistration, cmd.IPAddress, cmd.UserAgent))
+
+	if h.screen != nil {
+		risk, err := h.screen.Assess(ctx, user.Email, cmd)
+		// a dry run is not an attempt to record
+		if !dryrun.IsDryRun(ctx) {
+			h.screen.Record(ctx, risk)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if risk.Decision == domain.RiskDecisionReview {
+			user.Status = domain.UserStatusInactive
+		}
+	}
+
+	// A dry run only checks that the registration would be accepted
+	if dryrun.IsDryRun(ctx) {
+		return newRegisterUserResult(user.Email), nil
+	}
+
+	// Store user temporarily (not in database yet), replacing a previous registration of the email
+	err = h.tempUserStore.Store(ctx, user.Email, user)
 	if err != nil {
 		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to store user temporarily")
 	}
 
-	// Publish event to send OTP to user
-	err = h.eventBus.PublishEvent(ctx, domain.NewEventUserRegistered(user.Email))
+	// Publish event to send OTP to user once per email, so a double-submit sends a single mail
+	err = h.publishUserRegistered(ctx, user)
 	if err != nil {
 		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to publish event user registered")
 	}
 
-	return &RegisterUserResult{
-		Email: user.Email,
-	}, nil
+	return newRegisterUserResult(user.Email), nil
+}
+
+// checkEmailAvailable returns ErrUserAlreadyExists when a user has email, under any of the spellings its
+// account may be stored with
+func (h *RegisterUserHandler) checkEmailAvailable(ctx context.Context, email string) error {
+	for _, lookup := range h.emailPolicy.LookupEmails(email) {
+		_, err := h.userRepo.GetByEmail(ctx, lookup)
+		if err == nil {
+			return domain.ErrUserAlreadyExists
+		}
+		if !errors.Is(err, domain.ErrUserNotFound) {
+			return syserr.Wrap(err, syserr.InternalCode, "failed to check existing user")
+		}
+	}
+	return nil
+}
+
+// publishUserRegistered publishes EventUserRegistered unless it was already published for the email
+// of user within userRegisteredWindow. When the deduplicator is unavailable the event is published anyway.
+func (h *RegisterUserHandler) publishUserRegistered(ctx context.Context, user *domain.User) error {
+	email := user.Email
+	key := dedup.Key(dedupPurposeUserRegistered, email)
+
+	err := h.deduplicator.Claim(ctx, key, userRegisteredWindow)
+	if errors.Is(err, dedup.ErrDuplicate) {
+		logger.Info(ctx, "Skipping duplicate user registered event", logger.F("email", email))
+		return nil
+	}
+	if err != nil {
+		logger.Warning(ctx, "Failed to deduplicate user registered event", logger.F("email", email), logger.F("error", err))
+	}
+
+	// keyed by email so a user's messages stay in order
+	err = h.eventBus.PublishEvent(sharedKafka.WithPartitionKey(ctx, email), domain.NewEventUserRegistered(email, user.UserType))
+	if err != nil {
+		if forgetErr := h.deduplicator.Forget(ctx, key); forgetErr != nil {
+			logger.Warning(ctx, "Failed to release user registered claim", logger.F("email", email), logger.F("error", forgetErr))
+		}
+		return err
+	}
+	return nil
 }
@@ -0,0 +1,267 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"tixgo/modules/user/domain"
+	"tixgo/shared/dedup"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryOTPStore keeps the codes it is given so tests know which code to look for
+type memoryOTPStore struct {
+	mu   sync.Mutex
+	otps map[string]string
+}
+
+func (s *memoryOTPStore) Store(_ context.Context, email, otp string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.otps == nil {
+		s.otps = map[string]string{}
+	}
+	s.otps[email] = otp
+	return nil
+}
+
+func (s *memoryOTPStore) Get(_ context.Context, email string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	otp, ok := s.otps[email]
+	if !ok {
+		return "", domain.ErrOTPNotFound
+	}
+	return otp, nil
+}
+
+func (s *memoryOTPStore) Verify(_ context.Context, email, otp string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.otps[email] != otp {
+		return domain.ErrInvalidOTP
+	}
+	return nil
+}
+
+func (s *memoryOTPStore) Delete(_ context.Context, email string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.otps, email)
+	return nil
+}
+
+type memoryTempUserStore struct {
+	users map[string]*domain.User
+}
+
+func (s *memoryTempUserStore) Store(_ context.Context, email string, user *domain.User) error {
+	if s.users == nil {
+		s.users = map[string]*domain.User{}
+	}
+	s.users[email] = user
+	return nil
+}
+
+func (s *memoryTempUserStore) Get(_ context.Context, email string) (*domain.User, error) {
+	user, ok := s.users[email]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (s *memoryTempUserStore) GetPending(ctx context.Context, email string) (*domain.PendingRegistration, error) {
+	user, err := s.Get(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.PendingRegistration{User: user, ExpiresAt: time.Now().Add(domain.PendingRegistrationTTL)}, nil
+}
+
+func (s *memoryTempUserStore) Delete(_ context.Context, email string) error {
+	delete(s.users, email)
+	return nil
+}
+
+// allowDeduplicator never reports a duplicate
+type allowDeduplicator struct{}
+
+func (allowDeduplicator) Claim(context.Context, string, time.Duration) error { return nil }
+func (allowDeduplicator) Forget(context.Context, string) error               { return nil }
+
+var _ dedup.Deduplicator = allowDeduplicator{}
+
+// noUsers has no user
+type noUsers struct {
+	domain.UserRepository
+}
+
+func (noUsers) GetByEmail(context.Context, string) (*domain.User, error) {
+	return nil, domain.ErrUserNotFound
+}
+
+// recordingBus keeps the events it is asked to publish
+type recordingBus struct {
+	published []any
+}
+
+func (b *recordingBus) PublishEvent(_ context.Context, event any) error {
+	b.published = append(b.published, event)
+	return nil
+}
+
+func TestRegisterUserHandler_ResultHasNoOTP(t *testing.T) {
+	otpStore := &memoryOTPStore{}
+	handler := NewRegisterUserHandler(noUsers{}, &memoryTempUserStore{}, otpStore, allowDeduplicator{}, &recordingBus{}, domain.EmailPolicy{}, domain.ConsentPolicy{}, nil)
+
+	before := time.Now()
+	result, err := handler.Handle(context.Background(), &RegisterUserCommand{
+		Email:     "user@example.com",
+		Password:  "password123",
+		FirstName: "Jane",
+		LastName:  "Doe",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "user@example.com", result.Email)
+	assert.WithinDuration(t, before.Add(domain.OTPTTL), result.ExpiresAt, time.Second)
+	assert.WithinDuration(t, before.Add(otpMailWindow), result.ResendAfter, time.Second)
+
+	body, err := json.Marshal(result)
+	require.NoError(t, err)
+
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(body, &fields))
+	assert.ElementsMatch(t, []string{"email", "expires_at", "resend_after"}, keys(fields))
+	assert.Empty(t, otpStore.otps, "the code is generated when the mail is sent, not at registration")
+}
+
+func TestRegisterUserHandler_UserType(t *testing.T) {
+	tests := []struct {
+		name     string
+		userType string
+		want     domain.UserType
+		wantErr  error
+	}{
+		{name: "defaults to customer", userType: "", want: domain.UserTypeCustomer},
+		{name: "customer", userType: "customer", want: domain.UserTypeCustomer},
+		{name: "organizer", userType: "organizer", want: domain.UserTypeOrganizer},
+		{name: "admin cannot register", userType: "admin", wantErr: domain.ErrUserTypeNotRegistrable},
+		{name: "unknown type", userType: "staff", wantErr: domain.ErrInvalidUserType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempUserStore, bus := &memoryTempUserStore{}, &recordingBus{}
+			handler := NewRegisterUserHandler(noUsers{}, tempUserStore, &memoryOTPStore{}, allowDeduplicator{}, bus, domain.EmailPolicy{}, domain.ConsentPolicy{}, nil)
+
+			_, err := handler.Handle(context.Background(), &RegisterUserCommand{
+				Email:     "user@example.com",
+				Password:  "password123",
+				FirstName: "Jane",
+				LastName:  "Doe",
+				UserType:  tt.userType,
+			})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, bus.published)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, tempUserStore.users["user@example.com"].UserType)
+			require.Len(t, bus.published, 1)
+			assert.Equal(t, tt.want, bus.published[0].(*domain.EventUserRegistered).UserType)
+		})
+	}
+}
+
+func TestRegisterUserHandler_RestartsPendingRegistration(t *testing.T) {
+	tempUserStore := &memoryTempUserStore{}
+	handler := NewRegisterUserHandler(noUsers{}, tempUserStore, &memoryOTPStore{}, allowDeduplicator{}, &recordingBus{}, domain.EmailPolicy{}, domain.ConsentPolicy{}, nil)
+
+	register := func(firstName string) error {
+		_, err := handler.Handle(context.Background(), &RegisterUserCommand{
+			Email:     "user@example.com",
+			Password:  "password123",
+			FirstName: firstName,
+			LastName:  "Doe",
+		})
+		return err
+	}
+
+	require.NoError(t, register("Jane"))
+	require.NoError(t, register("Janet"), "a pending registration is not an existing user")
+	assert.Equal(t, "Janet", tempUserStore.users["user@example.com"].FirstName)
+}
+
+func TestRegisterUserHandler_RejectsTakenEmail(t *testing.T) {
+	existing, err := domain.NewUserCustomer("jane.doe@gmail.com", "password123", "Jane", "Doe")
+	require.NoError(t, err)
+
+	tempUserStore, bus := &memoryTempUserStore{}, &recordingBus{}
+	// the account predates Gmail folding, it is found under its original spelling
+	policy := domain.NewEmailPolicy(true, nil)
+	handler := NewRegisterUserHandler(singleUserRepository{user: existing}, tempUserStore, &memoryOTPStore{}, allowDeduplicator{}, bus, policy, domain.ConsentPolicy{}, nil)
+
+	_, err = handler.Handle(context.Background(), &RegisterUserCommand{
+		Email:     "Jane.Doe@gmail.com",
+		Password:  "password123",
+		FirstName: "Jane",
+		LastName:  "Doe",
+	})
+	assert.ErrorIs(t, err, domain.ErrUserAlreadyExists)
+	assert.Empty(t, tempUserStore.users)
+	assert.Empty(t, bus.published, "no code is mailed to the existing account")
+}
+
+func TestRegisterUserHandler_EmailPolicy(t *testing.T) {
+	policy := domain.NewEmailPolicy(true, []string{"mailinator.com"})
+
+	t.Run("registers the normalized email", func(t *testing.T) {
+		tempUserStore, bus := &memoryTempUserStore{}, &recordingBus{}
+		handler := NewRegisterUserHandler(noUsers{}, tempUserStore, &memoryOTPStore{}, allowDeduplicator{}, bus, policy, domain.ConsentPolicy{}, nil)
+
+		result, err := handler.Handle(context.Background(), &RegisterUserCommand{
+			Email:     " Jane.Doe+tickets@GoogleMail.com ",
+			Password:  "password123",
+			FirstName: "Jane",
+			LastName:  "Doe",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "janedoe@gmail.com", result.Email)
+		assert.Contains(t, tempUserStore.users, "janedoe@gmail.com")
+		require.Len(t, bus.published, 1)
+		assert.Equal(t, "janedoe@gmail.com", bus.published[0].(*domain.EventUserRegistered).Email)
+	})
+
+	t.Run("rejects disposable domains", func(t *testing.T) {
+		tempUserStore, bus := &memoryTempUserStore{}, &recordingBus{}
+		handler := NewRegisterUserHandler(noUsers{}, tempUserStore, &memoryOTPStore{}, allowDeduplicator{}, bus, policy, domain.ConsentPolicy{}, nil)
+
+		_, err := handler.Handle(context.Background(), &RegisterUserCommand{
+			Email:     "jane@eu.Mailinator.com",
+			Password:  "password123",
+			FirstName: "Jane",
+			LastName:  "Doe",
+		})
+		assert.ErrorIs(t, err, domain.ErrDisposableEmail)
+		assert.Empty(t, tempUserStore.users)
+		assert.Empty(t, bus.published)
+	})
+}
+
+func keys(m map[string]any) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
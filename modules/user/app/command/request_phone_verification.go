@@ -0,0 +1,144 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"tixgo/modules/user/domain"
+	"tixgo/shared/dedup"
+	sharedSMS "tixgo/shared/events/sms"
+	sharedKafka "tixgo/shared/kafka"
+	"tixgo/shared/phone"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+	"github.com/duongptryu/gox/syserr"
+)
+
+const (
+	dedupPurposeOTPSMS = "otp_sms"
+	// otpSMSWindow is the minimum time between two verification codes texted to the same number
+	otpSMSWindow = time.Minute
+)
+
+// RequestPhoneVerificationCommand asks for a verification code texted to a phone number
+type RequestPhoneVerificationCommand struct {
+	UserID int64 `json:"-"`
+	// Phone is in international format, or in the national format of Country when it is given
+	Phone   string `json:"phone" binding:"required,max=32"`
+	Country string `json:"country" binding:"omitempty,iso3166_1_alpha2"`
+}
+
+// RequestPhoneVerificationResult tells when the texted code expires. Like the email code, it is never
+// part of the response.
+type RequestPhoneVerificationResult struct {
+	// Phone is the number the code was texted to, in E.164 format
+	Phone   string `json:"phone"`
+	Country string `json:"country"`
+	// ExpiresAt is when the code texted to Phone stops being valid
+	ExpiresAt time.Time `json:"expires_at"`
+	// ResendAfter is the earliest time another code can be texted to Phone
+	ResendAfter time.Time `json:"resend_after"`
+}
+
+// RequestPhoneVerificationHandler texts a verification code to the phone number a user claims
+type RequestPhoneVerificationHandler struct {
+	userRepo     domain.UserRepository
+	otpStore     domain.OTPStore
+	deduplicator dedup.Deduplicator
+	eventBus     messaging.EventBus
+	// exposeOTP logs the generated codes, for dev setups without an SMS provider
+	exposeOTP bool
+}
+
+// NewRequestPhoneVerificationHandler creates a new request phone verification handler. otpStore must be
+// the phone namespace of the codes.
+func NewRequestPhoneVerificationHandler(userRepo domain.UserRepository, otpStore domain.OTPStore, deduplicator dedup.Deduplicator, eventBus messaging.EventBus, exposeOTP bool) *RequestPhoneVerificationHandler {
+	return &RequestPhoneVerificationHandler{
+		userRepo:     userRepo,
+		otpStore:     otpStore,
+		deduplicator: deduplicator,
+		eventBus:     eventBus,
+		exposeOTP:    exposeOTP,
+	}
+}
+
+// Handle texts at most one code per number within otpSMSWindow, a repeat is refused with
+// ErrOTPResendTooSoon. When sending fails the claim is released so the user can ask again at once.
+// Numbers that are not valid or cannot receive text messages are refused before anything is sent.
+func (h *RequestPhoneVerificationHandler) Handle(ctx context.Context, cmd *RequestPhoneVerificationCommand) (*RequestPhoneVerificationResult, error) {
+	number, err := phone.Parse(cmd.Phone, cmd.Country)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := h.userRepo.GetByID(ctx, cmd.UserID)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get user")
+	}
+	if user.HasVerifiedPhone(number) {
+		return nil, domain.ErrPhoneAlreadyVerified
+	}
+
+	key := dedup.Key(dedupPurposeOTPSMS, number.E164)
+	err = h.deduplicator.Claim(ctx, key, otpSMSWindow)
+	if errors.Is(err, dedup.ErrDuplicate) {
+		return nil, domain.ErrOTPResendTooSoon
+	}
+	if err != nil {
+		logger.Warning(ctx, "Failed to deduplicate OTP SMS", logger.F("user_id", cmd.UserID), logger.F("error", err))
+	}
+
+	if err := h.send(ctx, cmd.UserID, number); err != nil {
+		if forgetErr := h.deduplicator.Forget(ctx, key); forgetErr != nil {
+			logger.Warning(ctx, "Failed to release OTP SMS claim", logger.F("user_id", cmd.UserID), logger.F("error", forgetErr))
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	return &RequestPhoneVerificationResult{
+		Phone:       number.E164,
+		Country:     number.Country,
+		ExpiresAt:   now.Add(domain.OTPTTL),
+		ResendAfter: now.Add(otpSMSWindow),
+	}, nil
+}
+
+func (h *RequestPhoneVerificationHandler) send(ctx context.Context, userID int64, number phone.Number) error {
+	otp, err := generateOTP()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to generate OTP")
+	}
+
+	// the code is bound to the number it was texted to, so it cannot confirm another one
+	err = h.otpStore.Store(ctx, PhoneOTPKey(userID, number.E164), otp)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to store OTP")
+	}
+
+	// Codes are secrets and only leave through the SMS, unless the dev setup asked to see them
+	if h.exposeOTP {
+		logger.Info(ctx, "Generated phone OTP", logger.F("user_id", userID), logger.F("otp", otp))
+	}
+
+	err = h.eventBus.PublishEvent(sharedKafka.WithPartitionKey(ctx, number.E164), &sharedSMS.EventSendSMS{
+		To:   number.E164,
+		Body: fmt.Sprintf("Your TixGo verification code is %s. It expires in %d minutes.", otp, int(domain.OTPTTL.Minutes())),
+	})
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to publish send SMS event")
+	}
+
+	return nil
+}
+
+// PhoneOTPKey is the key of the code confirming phone for a user in the phone OTP store
+func PhoneOTPKey(userID int64, phone string) string {
+	return fmt.Sprintf("%d:%s", userID, phone)
+}
@@ -0,0 +1,132 @@
+package command
+
+import (
+	"context"
+	"strings"
+
+	"tixgo/modules/user/domain"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ReviewRegistrationCommand represents the command of an admin approving or rejecting a registration
+// queued for review
+type ReviewRegistrationCommand struct {
+	ID         int64  `json:"-"`
+	ReviewerID int64  `json:"-"`
+	Approve    bool   `json:"-"`
+	Reason     string `json:"reason" binding:"max=1000"`
+}
+
+// RegistrationRiskResult represents the risk assessment of a registration attempt
+type RegistrationRiskResult struct {
+	ID        int64                      `json:"id"`
+	Email     string                     `json:"email"`
+	IPAddress string                     `json:"ip_address"`
+	DeviceID  string                     `json:"device_id,omitempty"`
+	UserAgent string                     `json:"user_agent,omitempty"`
+	Signals   domain.RegistrationSignals `json:"signals"`
+	Score     int                        `json:"score"`
+	Decision  string                     `json:"decision"`
+	Reasons   []string                   `json:"reasons"`
+	// ReviewStatus is empty unless the registration was queued for review
+	ReviewStatus string  `json:"review_status,omitempty"`
+	ReviewedBy   *int64  `json:"reviewed_by,omitempty"`
+	ReviewReason string  `json:"review_reason,omitempty"`
+	ReviewedAt   *string `json:"reviewed_at,omitempty"`
+	// UserID is the account of the email, once verified
+	UserID    *int64 `json:"user_id,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+// ToRegistrationRiskResult converts a risk assessment to its result
+func ToRegistrationRiskResult(risk *domain.RegistrationRisk) *RegistrationRiskResult {
+	result := &RegistrationRiskResult{
+		ID:           risk.ID,
+		Email:        risk.Email,
+		IPAddress:    risk.IPAddress,
+		DeviceID:     risk.DeviceID,
+		UserAgent:    risk.UserAgent,
+		Signals:      risk.Signals,
+		Score:        risk.Score,
+		Decision:     string(risk.Decision),
+		Reasons:      risk.Reasons,
+		ReviewStatus: string(risk.ReviewStatus),
+		ReviewedBy:   risk.ReviewedBy,
+		ReviewReason: risk.ReviewReason,
+		UserID:       risk.UserID,
+		CreatedAt:    risk.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+	}
+	if risk.ReviewedAt != nil {
+		reviewedAt := risk.ReviewedAt.UTC().Format("2006-01-02T15:04:05Z")
+		result.ReviewedAt = &reviewedAt
+	}
+	return result
+}
+
+// ReviewRegistrationHandler handles the reviews of the registrations the risk policy queued
+type ReviewRegistrationHandler struct {
+	riskRepo domain.RegistrationRiskRepository
+	userRepo domain.UserRepository
+}
+
+// NewReviewRegistrationHandler creates a new review registration handler
+func NewReviewRegistrationHandler(riskRepo domain.RegistrationRiskRepository, userRepo domain.UserRepository) *ReviewRegistrationHandler {
+	return &ReviewRegistrationHandler{
+		riskRepo: riskRepo,
+		userRepo: userRepo,
+	}
+}
+
+// Handle executes the review. The account of a registration queued for review is inactive: approving
+// activates it, so it must be verified already, rejecting suspends it if it is.
+func (h *ReviewRegistrationHandler) Handle(ctx context.Context, cmd ReviewRegistrationCommand) (*RegistrationRiskResult, error) {
+	risk, err := h.riskRepo.GetByID(ctx, cmd.ID)
+	if err != nil {
+		if err == domain.ErrRegistrationRiskNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get registration risk")
+	}
+
+	if cmd.Approve {
+		err = risk.Approve(cmd.ReviewerID)
+	} else {
+		err = risk.Reject(cmd.ReviewerID, strings.TrimSpace(cmd.Reason))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := h.userRepo.GetByEmail(ctx, risk.Email)
+	if err != nil && err != domain.ErrUserNotFound {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get user")
+	}
+	if user == nil && cmd.Approve {
+		return nil, domain.ErrUserNotFound
+	}
+
+	err = h.riskRepo.SaveReview(ctx, risk)
+	if err != nil {
+		if err == domain.ErrRiskReviewNotPending {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to save registration review")
+	}
+
+	if user != nil {
+		if cmd.Approve {
+			user.Activate()
+		} else {
+			user.Suspend()
+		}
+		if err := h.userRepo.Update(ctx, user); err != nil {
+			// the review is saved but the account kept its status, which must then be fixed by hand
+			logger.Error(ctx, "Failed to apply registration review", logger.F("risk_id", risk.ID), logger.F("error", err))
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to update user status")
+		}
+	}
+
+	return ToRegistrationRiskResult(risk), nil
+}
@@ -0,0 +1,89 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/user/domain"
+
+	"github.com/duongptryu/gox/logger"
+)
+
+// RegistrationScreen scores registrations for spam with the risk policy. The signals it cannot collect,
+// e.g. while redis is down, count as clean: screening never fails a registration.
+type RegistrationScreen struct {
+	policy   domain.RiskPolicy
+	velocity domain.RegistrationVelocity
+	riskRepo domain.RegistrationRiskRepository
+	// captcha is nil when no CAPTCHA provider is configured
+	captcha domain.CaptchaVerifier
+}
+
+// NewRegistrationScreen creates a new registration screen; captcha may be nil
+func NewRegistrationScreen(policy domain.RiskPolicy, velocity domain.RegistrationVelocity, riskRepo domain.RegistrationRiskRepository, captcha domain.CaptchaVerifier) *RegistrationScreen {
+	policy.CaptchaEnabled = captcha != nil
+	return &RegistrationScreen{
+		policy:   policy,
+		velocity: velocity,
+		riskRepo: riskRepo,
+		captcha:  captcha,
+	}
+}
+
+// Assess collects the signals of a registration and scores them. A challenged registration whose
+// CAPTCHA token was refused is told so rather than asked for one.
+func (s *RegistrationScreen) Assess(ctx context.Context, email string, cmd *RegisterUserCommand) (*domain.RegistrationRisk, error) {
+	policy := s.policy
+	signals := domain.RegistrationSignals{
+		IPListed:    policy.IsRisky(cmd.IPAddress),
+		HasDeviceID: cmd.DeviceID != "",
+	}
+	signals.EmailEntropy, signals.EmailLocalLength, signals.EmailDigits = domain.EmailSignals(email)
+
+	fromIP, fromDevice, err := s.velocity.Count(ctx, cmd.IPAddress, cmd.DeviceID, policy.VelocityWindow)
+	if err != nil {
+		logger.Warning(ctx, "Failed to count registrations", logger.F("ip", cmd.IPAddress), logger.F("error", err))
+	}
+	signals.RegistrationsFromIP, signals.RegistrationsFromDevice = fromIP, fromDevice
+
+	flagged, err := s.riskRepo.CountFlagged(ctx, cmd.IPAddress, time.Now().Add(-domain.RiskFlaggedLookback))
+	if err != nil {
+		logger.Warning(ctx, "Failed to count flagged registrations", logger.F("ip", cmd.IPAddress), logger.F("error", err))
+	}
+	signals.IPFlagged = flagged
+
+	captchaRefused := false
+	if cmd.CaptchaToken != "" && s.captcha != nil {
+		passed, err := s.captcha.Verify(ctx, cmd.CaptchaToken, cmd.IPAddress)
+		if err != nil {
+			// users cannot be challenged while the provider is down: they go to review instead
+			logger.Warning(ctx, "Failed to verify CAPTCHA", logger.F("error", err))
+			policy.CaptchaEnabled = false
+		}
+		signals.CaptchaPassed = passed
+		captchaRefused = err == nil && !passed
+	}
+
+	risk := domain.NewRegistrationRisk(policy, email, cmd.IPAddress, cmd.DeviceID, cmd.UserAgent, signals)
+	if err := risk.Err(); err != nil {
+		if err == domain.ErrCaptchaRequired && captchaRefused {
+			return risk, domain.ErrCaptchaInvalid
+		}
+		return risk, err
+	}
+	return risk, nil
+}
+
+// Record stores the assessment of a registration and counts the registrations accepted against the
+// velocity of their IP and device
+func (s *RegistrationScreen) Record(ctx context.Context, risk *domain.RegistrationRisk) {
+	if err := s.riskRepo.Record(ctx, risk); err != nil {
+		logger.Warning(ctx, "Failed to record registration risk", logger.F("email", risk.Email), logger.F("error", err))
+	}
+	if risk.Err() != nil {
+		return
+	}
+	if err := s.velocity.Record(ctx, risk.IPAddress, risk.DeviceID, s.policy.VelocityWindow); err != nil {
+		logger.Warning(ctx, "Failed to record registration velocity", logger.F("ip", risk.IPAddress), logger.F("error", err))
+	}
+}
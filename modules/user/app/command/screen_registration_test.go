@@ -0,0 +1,132 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"tixgo/modules/user/domain"
+	"tixgo/shared/listing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryVelocity counts the registrations per device, the window aside
+type memoryVelocity struct {
+	fromDevice map[string]int
+	err        error
+}
+
+func (v *memoryVelocity) Count(_ context.Context, _, deviceID string, _ time.Duration) (int, int, error) {
+	return 0, v.fromDevice[deviceID], v.err
+}
+
+func (v *memoryVelocity) Record(_ context.Context, _, deviceID string, _ time.Duration) error {
+	if v.fromDevice == nil {
+		v.fromDevice = map[string]int{}
+	}
+	v.fromDevice[deviceID]++
+	return v.err
+}
+
+// memoryRiskRepo keeps the assessments it records
+type memoryRiskRepo struct {
+	risks   []*domain.RegistrationRisk
+	flagged int
+}
+
+func (r *memoryRiskRepo) Record(_ context.Context, risk *domain.RegistrationRisk) error {
+	r.risks = append(r.risks, risk)
+	return nil
+}
+
+func (r *memoryRiskRepo) CountFlagged(context.Context, string, time.Time) (int, error) {
+	return r.flagged, nil
+}
+
+func (r *memoryRiskRepo) GetByID(context.Context, int64) (*domain.RegistrationRisk, error) {
+	return nil, domain.ErrRegistrationRiskNotFound
+}
+
+func (r *memoryRiskRepo) List(context.Context, domain.ListRegistrationRiskFilters, *listing.Paging) ([]*domain.RegistrationRisk, error) {
+	return r.risks, nil
+}
+
+func (r *memoryRiskRepo) SaveReview(context.Context, *domain.RegistrationRisk) error {
+	return nil
+}
+
+// stubCaptcha passes the token "solved"
+type stubCaptcha struct {
+	err error
+}
+
+func (c stubCaptcha) Verify(_ context.Context, token, _ string) (bool, error) {
+	return token == "solved", c.err
+}
+
+func TestRegisterUserHandler_Screening(t *testing.T) {
+	policy, err := domain.NewRiskPolicy(domain.RiskPolicy{DeviceLimit: 1}, []string{"203.0.113.0/24"})
+	require.NoError(t, err)
+
+	register := func(t *testing.T, screen *RegistrationScreen, cmd RegisterUserCommand) (*memoryTempUserStore, error) {
+		t.Helper()
+		tempUserStore := &memoryTempUserStore{}
+		handler := NewRegisterUserHandler(noUsers{}, tempUserStore, &memoryOTPStore{}, allowDeduplicator{}, &recordingBus{}, domain.EmailPolicy{}, domain.ConsentPolicy{}, screen)
+		cmd.Email, cmd.Password, cmd.FirstName, cmd.LastName = "jane@example.com", "password123", "Jane", "Doe"
+		_, err := handler.Handle(context.Background(), &cmd)
+		return tempUserStore, err
+	}
+
+	t.Run("a device registering again is challenged until the CAPTCHA is solved", func(t *testing.T) {
+		velocity := &memoryVelocity{fromDevice: map[string]int{"device-1": 2}}
+		riskRepo := &memoryRiskRepo{}
+		screen := NewRegistrationScreen(policy, velocity, riskRepo, stubCaptcha{})
+
+		store, err := register(t, screen, RegisterUserCommand{IPAddress: "198.51.100.7", DeviceID: "device-1"})
+		assert.Equal(t, domain.ErrCaptchaRequired, err)
+		assert.Empty(t, store.users)
+
+		_, err = register(t, screen, RegisterUserCommand{IPAddress: "198.51.100.7", DeviceID: "device-1", CaptchaToken: "wrong"})
+		assert.Equal(t, domain.ErrCaptchaInvalid, err)
+
+		store, err = register(t, screen, RegisterUserCommand{IPAddress: "198.51.100.7", DeviceID: "device-1", CaptchaToken: "solved"})
+		require.NoError(t, err)
+		assert.Equal(t, domain.UserStatusActive, store.users["jane@example.com"].Status)
+
+		require.Len(t, riskRepo.risks, 3)
+		assert.Equal(t, domain.RiskDecisionChallenge, riskRepo.risks[2].Decision)
+		assert.True(t, riskRepo.risks[2].Signals.CaptchaPassed)
+		assert.Equal(t, 3, velocity.fromDevice["device-1"], "only the registration accepted counts")
+	})
+
+	t.Run("a challenge goes to review while the CAPTCHA provider is down", func(t *testing.T) {
+		velocity := &memoryVelocity{fromDevice: map[string]int{"device-1": 2}}
+		screen := NewRegistrationScreen(policy, velocity, &memoryRiskRepo{}, stubCaptcha{err: errors.New("timeout")})
+
+		store, err := register(t, screen, RegisterUserCommand{IPAddress: "198.51.100.7", DeviceID: "device-1", CaptchaToken: "solved"})
+		require.NoError(t, err)
+		assert.Equal(t, domain.UserStatusInactive, store.users["jane@example.com"].Status)
+	})
+
+	t.Run("a listed IP with a history of spam is blocked", func(t *testing.T) {
+		riskRepo := &memoryRiskRepo{flagged: 2}
+		screen := NewRegistrationScreen(policy, &memoryVelocity{}, riskRepo, nil)
+
+		store, err := register(t, screen, RegisterUserCommand{IPAddress: "203.0.113.9", DeviceID: "device-2"})
+		assert.Equal(t, domain.ErrRegistrationBlocked, err)
+		assert.Empty(t, store.users)
+		require.Len(t, riskRepo.risks, 1)
+		assert.Equal(t, []string{domain.RiskReasonIPReputation, domain.RiskReasonIPHistory}, riskRepo.risks[0].Reasons)
+	})
+
+	t.Run("velocity failing open lets the registration through", func(t *testing.T) {
+		screen := NewRegistrationScreen(policy, &memoryVelocity{err: errors.New("redis down")}, &memoryRiskRepo{}, nil)
+
+		store, err := register(t, screen, RegisterUserCommand{IPAddress: "198.51.100.7", DeviceID: "device-3"})
+		require.NoError(t, err)
+		assert.Equal(t, domain.UserStatusActive, store.users["jane@example.com"].Status)
+	})
+}
@@ -3,12 +3,17 @@ package command
 import (
 	"context"
 	"crypto/rand"
+	"errors"
 	"fmt"
 	"math/big"
+	"time"
 	templateDomain "tixgo/modules/template/domain"
 	"tixgo/modules/user/domain"
+	"tixgo/shared/dedup"
 	sharedMail "tixgo/shared/events/mail"
+	sharedKafka "tixgo/shared/kafka"
 
+	"github.com/duongptryu/gox/logger"
 	"github.com/duongptryu/gox/messaging"
 	"github.com/duongptryu/gox/notification/mail"
 	"github.com/duongptryu/gox/syserr"
@@ -16,29 +21,66 @@ import (
 
 const (
 	SlugMailOTP = "mail-verify-mail"
+	// SlugMailOTPOrganizer is the verification mail of organizers, which also walks them through
+	// setting up their organizer account. SlugMailOTP is used while it does not exist.
+	SlugMailOTPOrganizer = "mail-verify-mail-organizer"
+
+	dedupPurposeOTPMail = "otp_mail"
+	// otpMailWindow is the minimum time between two OTP mails to the same address
+	otpMailWindow = time.Minute
 )
 
 type sendOTPVerifyMailHandler struct {
 	otpStore         domain.OTPStore
 	templateRepo     templateDomain.TemplateRepository
 	templateRenderer templateDomain.TemplateRenderer
+	deduplicator     dedup.Deduplicator
 	eventBus         messaging.EventBus
+	// exposeOTP logs the generated codes, for dev setups without a mail server
+	exposeOTP bool
 }
 
 type SendOTPVerifyMailCommand struct {
 	Mail string
+	// UserType is the type of the registered account, empty for a customer
+	UserType domain.UserType
 }
 
-func NewSendOTPVerifyMailHandler(otpStore domain.OTPStore, templateRepo templateDomain.TemplateRepository, templateRenderer templateDomain.TemplateRenderer, eventBus messaging.EventBus) *sendOTPVerifyMailHandler {
+func NewSendOTPVerifyMailHandler(otpStore domain.OTPStore, templateRepo templateDomain.TemplateRepository, templateRenderer templateDomain.TemplateRenderer, deduplicator dedup.Deduplicator, eventBus messaging.EventBus, exposeOTP bool) *sendOTPVerifyMailHandler {
 	return &sendOTPVerifyMailHandler{
 		otpStore:         otpStore,
 		templateRepo:     templateRepo,
 		templateRenderer: templateRenderer,
+		deduplicator:     deduplicator,
 		eventBus:         eventBus,
+		exposeOTP:        exposeOTP,
 	}
 }
 
+// Handle sends at most one OTP mail per address within otpMailWindow; repeats are acknowledged and dropped.
+// When sending fails the claim is released so the redelivered command is not mistaken for a repeat.
 func (h *sendOTPVerifyMailHandler) Handle(ctx context.Context, cmd *SendOTPVerifyMailCommand) error {
+	key := dedup.Key(dedupPurposeOTPMail, cmd.Mail)
+
+	err := h.deduplicator.Claim(ctx, key, otpMailWindow)
+	if errors.Is(err, dedup.ErrDuplicate) {
+		logger.Info(ctx, "Skipping duplicate OTP mail", logger.F("email", cmd.Mail))
+		return nil
+	}
+	if err != nil {
+		logger.Warning(ctx, "Failed to deduplicate OTP mail", logger.F("email", cmd.Mail), logger.F("error", err))
+	}
+
+	if err := h.send(ctx, cmd); err != nil {
+		if forgetErr := h.deduplicator.Forget(ctx, key); forgetErr != nil {
+			logger.Warning(ctx, "Failed to release OTP mail claim", logger.F("email", cmd.Mail), logger.F("error", forgetErr))
+		}
+		return err
+	}
+	return nil
+}
+
+func (h *sendOTPVerifyMailHandler) send(ctx context.Context, cmd *SendOTPVerifyMailCommand) error {
 	otp, err := generateOTP()
 	if err != nil {
 		return syserr.Wrap(err, syserr.InternalCode, "failed to generate OTP")
@@ -50,7 +92,12 @@ func (h *sendOTPVerifyMailHandler) Handle(ctx context.Context, cmd *SendOTPVerif
 		return syserr.Wrap(err, syserr.InternalCode, "failed to store OTP")
 	}
 
-	template, err := h.templateRepo.GetBySlug(ctx, SlugMailOTP)
+	// Codes are secrets and only leave through the mail, unless the dev setup asked to see them
+	if h.exposeOTP {
+		logger.Info(ctx, "Generated OTP", logger.F("email", cmd.Mail), logger.F("otp", otp))
+	}
+
+	template, err := h.template(ctx, cmd.UserType)
 	if err != nil {
 		return syserr.Wrap(err, syserr.InternalCode, "failed to get template")
 	}
@@ -58,13 +105,13 @@ func (h *sendOTPVerifyMailHandler) Handle(ctx context.Context, cmd *SendOTPVerif
 	// render to html
 	rendered, err := h.templateRenderer.Render(ctx, template, map[string]interface{}{
 		"otp": otp,
-	})
+	}, templateDomain.RenderOptions{})
 	if err != nil {
 		return syserr.Wrap(err, syserr.InternalCode, "failed to render template")
 	}
 
 	// send mail
-	h.eventBus.PublishEvent(ctx, &sharedMail.EventSendMail{
+	err = h.eventBus.PublishEvent(sharedKafka.WithPartitionKey(ctx, cmd.Mail), &sharedMail.EventSendMail{
 		ToMail: []mail.EmailAddress{
 			{
 				Email: cmd.Mail,
@@ -75,10 +122,24 @@ func (h *sendOTPVerifyMailHandler) Handle(ctx context.Context, cmd *SendOTPVerif
 		HTMLBody: rendered.Content,
 		Priority: mail.PriorityHigh,
 	})
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to publish send mail event")
+	}
 
 	return nil
 }
 
+// template returns the verification mail template of the user type
+func (h *sendOTPVerifyMailHandler) template(ctx context.Context, userType domain.UserType) (*templateDomain.Template, error) {
+	if userType == domain.UserTypeOrganizer {
+		template, err := h.templateRepo.GetBySlug(ctx, SlugMailOTPOrganizer)
+		if !errors.Is(err, templateDomain.ErrTemplateNotFound) {
+			return template, err
+		}
+	}
+	return h.templateRepo.GetBySlug(ctx, SlugMailOTP)
+}
+
 // generateOTP generates a 6-digit OTP
 func generateOTP() (string, error) {
 	max := big.NewInt(999999)
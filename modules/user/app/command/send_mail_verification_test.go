@@ -0,0 +1,85 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	templateAdapters "tixgo/modules/template/adapters"
+	templateDomain "tixgo/modules/template/domain"
+	"tixgo/modules/user/domain"
+	sharedMail "tixgo/shared/events/mail"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// logs captures the logs of the package tests, the logger can only be initialized once
+var logs bytes.Buffer
+
+func TestMain(m *testing.M) {
+	logger.Init(&logger.Config{Output: &logs})
+	os.Exit(m.Run())
+}
+
+// otpTemplateRepository serves the OTP mail templates, the organizer one only when withOrganizer is set
+type otpTemplateRepository struct {
+	templateDomain.TemplateRepository
+	withOrganizer bool
+}
+
+func (r otpTemplateRepository) GetBySlug(_ context.Context, slug string) (*templateDomain.Template, error) {
+	switch {
+	case slug == SlugMailOTP:
+		return &templateDomain.Template{Slug: slug, Subject: "Verify your email", Content: "Your code is {{.otp}}"}, nil
+	case slug == SlugMailOTPOrganizer && r.withOrganizer:
+		return &templateDomain.Template{Slug: slug, Subject: "Verify your organizer account", Content: "Your code is {{.otp}}"}, nil
+	default:
+		return nil, templateDomain.ErrTemplateNotFound
+	}
+}
+
+// sendOTPMail runs the OTP mail handler with the logs captured and returns the code it generated
+func sendOTPMail(t *testing.T, exposeOTP bool) (otp string, logged string, bus *recordingBus) {
+	t.Helper()
+	logs.Reset()
+
+	otpStore, bus := &memoryOTPStore{}, &recordingBus{}
+	handler := NewSendOTPVerifyMailHandler(otpStore, otpTemplateRepository{}, templateAdapters.NewHTMLTemplateRenderer(nil, nil, nil), allowDeduplicator{}, bus, exposeOTP)
+
+	require.NoError(t, handler.Handle(context.Background(), &SendOTPVerifyMailCommand{Mail: "user@example.com"}))
+	otp = otpStore.otps["user@example.com"]
+	require.Len(t, otp, 6)
+
+	return otp, logs.String(), bus
+}
+
+func TestSendOTPVerifyMailHandler_OTPOnlyInMail(t *testing.T) {
+	otp, logged, bus := sendOTPMail(t, false)
+
+	assert.NotContains(t, logged, otp)
+	require.Len(t, bus.published, 1)
+	assert.Contains(t, bus.published[0].(*sharedMail.EventSendMail).HTMLBody, otp)
+}
+
+func TestSendOTPVerifyMailHandler_ExposeOTP(t *testing.T) {
+	otp, logged, _ := sendOTPMail(t, true)
+
+	assert.Contains(t, logged, otp)
+}
+
+func TestSendOTPVerifyMailHandler_OrganizerTemplate(t *testing.T) {
+	subject := func(repo otpTemplateRepository, userType domain.UserType) string {
+		bus := &recordingBus{}
+		handler := NewSendOTPVerifyMailHandler(&memoryOTPStore{}, repo, templateAdapters.NewHTMLTemplateRenderer(nil, nil, nil), allowDeduplicator{}, bus, false)
+		require.NoError(t, handler.Handle(context.Background(), &SendOTPVerifyMailCommand{Mail: "user@example.com", UserType: userType}))
+		require.Len(t, bus.published, 1)
+		return bus.published[0].(*sharedMail.EventSendMail).Subject
+	}
+
+	assert.Equal(t, "Verify your organizer account", subject(otpTemplateRepository{withOrganizer: true}, domain.UserTypeOrganizer))
+	assert.Equal(t, "Verify your email", subject(otpTemplateRepository{withOrganizer: true}, domain.UserTypeCustomer))
+	assert.Equal(t, "Verify your email", subject(otpTemplateRepository{}, domain.UserTypeOrganizer), "falls back while the organizer template does not exist")
+}
@@ -0,0 +1,69 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/user/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// UpdateMarketingConsentCommand represents the command of a user granting or withdrawing their
+// marketing consent
+type UpdateMarketingConsentCommand struct {
+	UserID  int64 `json:"-"`
+	Granted *bool `json:"granted" binding:"required"`
+	// IPAddress and UserAgent describe the client in the consent history
+	IPAddress string `json:"-"`
+	UserAgent string `json:"-"`
+}
+
+// ConsentResult is a consent of the history of a user
+type ConsentResult struct {
+	ID        int64              `json:"id"`
+	Kind      domain.ConsentKind `json:"kind"`
+	Version   string             `json:"version,omitempty"`
+	Granted   bool               `json:"granted"`
+	Source    string             `json:"source"`
+	IPAddress string             `json:"ip_address,omitempty"`
+	UserAgent string             `json:"user_agent,omitempty"`
+	CreatedAt string             `json:"created_at"`
+}
+
+// ToConsentResult converts a consent to its result
+func ToConsentResult(consent *domain.Consent) *ConsentResult {
+	return &ConsentResult{
+		ID:        consent.ID,
+		Kind:      consent.Kind,
+		Version:   consent.Version,
+		Granted:   consent.Granted,
+		Source:    string(consent.Source),
+		IPAddress: consent.IPAddress,
+		UserAgent: consent.UserAgent,
+		CreatedAt: consent.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+// UpdateMarketingConsentHandler handles the changes of marketing consent
+type UpdateMarketingConsentHandler struct {
+	consentRepo domain.ConsentRepository
+}
+
+// NewUpdateMarketingConsentHandler creates a new update marketing consent handler
+func NewUpdateMarketingConsentHandler(consentRepo domain.ConsentRepository) *UpdateMarketingConsentHandler {
+	return &UpdateMarketingConsentHandler{
+		consentRepo: consentRepo,
+	}
+}
+
+// Handle executes the update marketing consent command, adding the consent to the history of the user
+func (h *UpdateMarketingConsentHandler) Handle(ctx context.Context, cmd *UpdateMarketingConsentCommand) (*ConsentResult, error) {
+	consent := domain.NewConsent(cmd.UserID, domain.ConsentKindMarketing, "", *cmd.Granted,
+		domain.ConsentSourceSettings, cmd.IPAddress, cmd.UserAgent)
+
+	if err := h.consentRepo.Record(ctx, consent); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to record marketing consent")
+	}
+
+	return ToConsentResult(consent), nil
+}
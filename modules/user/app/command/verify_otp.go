@@ -5,6 +5,7 @@ import (
 
 	"tixgo/modules/user/domain"
 
+	"github.com/duongptryu/gox/logger"
 	"github.com/duongptryu/gox/syserr"
 )
 
@@ -18,6 +19,8 @@ type VerifyOTPCommand struct {
 type VerifyOTPResult struct {
 	UserID int64  `json:"user_id"`
 	Email  string `json:"email"`
+	// UserType tells clients where the account continues, organizers go on with their organizer setup
+	UserType string `json:"user_type"`
 }
 
 // VerifyOTPHandler handles OTP verification
@@ -25,27 +28,33 @@ type VerifyOTPHandler struct {
 	userRepo      domain.UserRepository
 	tempUserStore domain.TempUserStore
 	otpStore      domain.OTPStore
+	consentRepo   domain.ConsentRepository
+	emailPolicy   domain.EmailPolicy
 }
 
 // NewVerifyOTPHandler creates a new verify OTP handler
-func NewVerifyOTPHandler(userRepo domain.UserRepository, tempUserStore domain.TempUserStore, otpStore domain.OTPStore) *VerifyOTPHandler {
+func NewVerifyOTPHandler(userRepo domain.UserRepository, tempUserStore domain.TempUserStore, otpStore domain.OTPStore, consentRepo domain.ConsentRepository, emailPolicy domain.EmailPolicy) *VerifyOTPHandler {
 	return &VerifyOTPHandler{
 		userRepo:      userRepo,
 		tempUserStore: tempUserStore,
 		otpStore:      otpStore,
+		consentRepo:   consentRepo,
+		emailPolicy:   emailPolicy,
 	}
 }
 
 // Handle executes the verify OTP command
 func (h *VerifyOTPHandler) Handle(ctx context.Context, cmd *VerifyOTPCommand) (*VerifyOTPResult, error) {
+	email := h.emailPolicy.Normalize(cmd.Email)
+
 	// Verify OTP
-	err := h.otpStore.Verify(ctx, cmd.Email, cmd.OTP)
+	err := h.otpStore.Verify(ctx, email, cmd.OTP)
 	if err != nil {
 		return nil, domain.ErrInvalidOTP
 	}
 
 	// Get user from temp store
-	user, err := h.tempUserStore.Get(ctx, cmd.Email)
+	user, err := h.tempUserStore.Get(ctx, email)
 	if err != nil {
 		if err == domain.ErrUserNotFound {
 			return nil, domain.ErrUserNotFound
@@ -59,18 +68,35 @@ func (h *VerifyOTPHandler) Handle(ctx context.Context, cmd *VerifyOTPCommand) (*
 	// Save user to database (move from temp to permanent storage)
 	err = h.userRepo.Create(ctx, user)
 	if err != nil {
+		if err == domain.ErrUserAlreadyExists {
+			// the email was registered meanwhile, the pending registration is void
+			_ = h.tempUserStore.Delete(ctx, email)
+			return nil, domain.ErrUserAlreadyExists
+		}
 		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to create user")
 	}
 
+	// Record the consents given with the registration. A failure must not fail the verification: the
+	// terms and privacy policy not recorded are asked for again at login.
+	for _, consent := range user.Consents {
+		consent.UserID = user.ID
+	}
+	if len(user.Consents) > 0 {
+		if err := h.consentRepo.Record(ctx, user.Consents...); err != nil {
+			logger.Warning(ctx, "Failed to record registration consents", logger.F("user_id", user.ID), logger.F("error", err))
+		}
+	}
+
 	// Clean up temp store
-	err = h.tempUserStore.Delete(ctx, cmd.Email)
+	err = h.tempUserStore.Delete(ctx, email)
 	if err != nil {
 		// Log error but don't fail the operation since user is already created
 		// This is just cleanup
 	}
 
 	return &VerifyOTPResult{
-		UserID: user.ID,
-		Email:  user.Email,
+		UserID:   user.ID,
+		Email:    user.Email,
+		UserType: string(user.UserType),
 	}, nil
 }
@@ -0,0 +1,145 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tixgo/modules/user/domain"
+	"tixgo/shared/dedup"
+	sharedSMS "tixgo/shared/events/sms"
+	"tixgo/shared/phone"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryUserRepository keeps users by ID
+type memoryUserRepository struct {
+	domain.UserRepository
+	users map[int64]*domain.User
+}
+
+func (r *memoryUserRepository) Create(_ context.Context, user *domain.User) error {
+	user.ID = int64(len(r.users) + 1)
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *memoryUserRepository) GetByID(_ context.Context, id int64) (*domain.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (r *memoryUserRepository) Update(_ context.Context, user *domain.User) error {
+	r.users[user.ID] = user
+	return nil
+}
+
+// onceDeduplicator lets a key be claimed once until it is forgotten
+type onceDeduplicator struct {
+	claimed map[string]bool
+}
+
+func (d *onceDeduplicator) Claim(_ context.Context, key string, _ time.Duration) error {
+	if d.claimed[key] {
+		return dedup.ErrDuplicate
+	}
+	d.claimed[key] = true
+	return nil
+}
+
+func (d *onceDeduplicator) Forget(_ context.Context, key string) error {
+	delete(d.claimed, key)
+	return nil
+}
+
+func TestPhoneVerification(t *testing.T) {
+	ctx := context.Background()
+	number := phone.Number{E164: "+84901234567", Country: "VN"}
+
+	newUsers := func() *memoryUserRepository {
+		return &memoryUserRepository{users: map[int64]*domain.User{42: {ID: 42, Email: "fan@example.com"}}}
+	}
+
+	t.Run("a texted code confirms the number", func(t *testing.T) {
+		users, otpStore, bus := newUsers(), &memoryOTPStore{}, &recordingBus{}
+		request := NewRequestPhoneVerificationHandler(users, otpStore, &onceDeduplicator{claimed: map[string]bool{}}, bus, false)
+
+		result, err := request.Handle(ctx, &RequestPhoneVerificationCommand{UserID: 42, Phone: number.E164})
+		require.NoError(t, err)
+		assert.Equal(t, number.E164, result.Phone)
+		assert.Equal(t, number.Country, result.Country)
+		assert.True(t, result.ResendAfter.Before(result.ExpiresAt))
+
+		otp := otpStore.otps[PhoneOTPKey(42, number.E164)]
+		require.Len(t, otp, 6)
+		require.Len(t, bus.published, 1)
+		sms := bus.published[0].(*sharedSMS.EventSendSMS)
+		assert.Equal(t, number.E164, sms.To)
+		assert.Contains(t, sms.Body, otp)
+
+		confirm := NewConfirmPhoneVerificationHandler(users, otpStore)
+		confirmed, err := confirm.Handle(ctx, &ConfirmPhoneVerificationCommand{UserID: 42, Phone: number.E164, OTP: otp})
+		require.NoError(t, err)
+		assert.Equal(t, &ConfirmPhoneVerificationResult{Phone: number.E164, PhoneCountry: "VN", PhoneVerified: true}, confirmed)
+		assert.True(t, users.users[42].HasVerifiedPhone(number))
+		assert.NoError(t, users.users[42].CanTakeHighRiskAction())
+
+		_, err = request.Handle(ctx, &RequestPhoneVerificationCommand{UserID: 42, Phone: number.E164})
+		assert.Equal(t, domain.ErrPhoneAlreadyVerified, err)
+	})
+
+	t.Run("a code only confirms the number it was texted to", func(t *testing.T) {
+		users, otpStore := newUsers(), &memoryOTPStore{}
+		request := NewRequestPhoneVerificationHandler(users, otpStore, allowDeduplicator{}, &recordingBus{}, false)
+
+		_, err := request.Handle(ctx, &RequestPhoneVerificationCommand{UserID: 42, Phone: number.E164})
+		require.NoError(t, err)
+		otp := otpStore.otps[PhoneOTPKey(42, number.E164)]
+
+		confirm := NewConfirmPhoneVerificationHandler(users, otpStore)
+		_, err = confirm.Handle(ctx, &ConfirmPhoneVerificationCommand{UserID: 42, Phone: "+84909999999", OTP: otp})
+		assert.Equal(t, domain.ErrInvalidOTP, err)
+		assert.Equal(t, domain.ErrPhoneNotVerified, users.users[42].CanTakeHighRiskAction())
+	})
+
+	t.Run("a national number is normalized to E.164", func(t *testing.T) {
+		users, otpStore, bus := newUsers(), &memoryOTPStore{}, &recordingBus{}
+		request := NewRequestPhoneVerificationHandler(users, otpStore, allowDeduplicator{}, bus, false)
+
+		result, err := request.Handle(ctx, &RequestPhoneVerificationCommand{UserID: 42, Phone: "090 123 4567", Country: "VN"})
+		require.NoError(t, err)
+		assert.Equal(t, number.E164, result.Phone)
+		assert.Equal(t, number.E164, bus.published[0].(*sharedSMS.EventSendSMS).To)
+
+		confirm := NewConfirmPhoneVerificationHandler(users, otpStore)
+		_, err = confirm.Handle(ctx, &ConfirmPhoneVerificationCommand{UserID: 42, Phone: "+84 90 123 4567", OTP: otpStore.otps[PhoneOTPKey(42, number.E164)]})
+		require.NoError(t, err)
+		assert.Equal(t, "VN", *users.users[42].PhoneCountry)
+	})
+
+	t.Run("a number that cannot receive texts is refused", func(t *testing.T) {
+		bus := &recordingBus{}
+		request := NewRequestPhoneVerificationHandler(newUsers(), &memoryOTPStore{}, allowDeduplicator{}, bus, false)
+
+		_, err := request.Handle(ctx, &RequestPhoneVerificationCommand{UserID: 42, Phone: "+44 20 7946 0000"})
+		assert.ErrorIs(t, err, phone.ErrUndeliverable)
+		assert.Empty(t, bus.published)
+	})
+
+	t.Run("a second code within the window is refused", func(t *testing.T) {
+		bus := &recordingBus{}
+		request := NewRequestPhoneVerificationHandler(newUsers(), &memoryOTPStore{}, &onceDeduplicator{claimed: map[string]bool{}}, bus, false)
+
+		_, err := request.Handle(ctx, &RequestPhoneVerificationCommand{UserID: 42, Phone: number.E164})
+		require.NoError(t, err)
+		_, err = request.Handle(ctx, &RequestPhoneVerificationCommand{UserID: 42, Phone: number.E164})
+		assert.Equal(t, domain.ErrOTPResendTooSoon, err)
+		assert.Len(t, bus.published, 1)
+	})
+}
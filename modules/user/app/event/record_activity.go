@@ -0,0 +1,30 @@
+package event
+
+import (
+	"context"
+
+	"tixgo/modules/user/domain"
+	sharedActivity "tixgo/shared/events/activity"
+)
+
+type recordActivity struct {
+	activityRepo domain.ActivityRepository
+}
+
+func NewRecordActivity(activityRepo domain.ActivityRepository) *recordActivity {
+	return &recordActivity{
+		activityRepo: activityRepo,
+	}
+}
+
+func (h *recordActivity) Record(ctx context.Context, event *sharedActivity.EventAccountActivity) error {
+	return h.activityRepo.Record(ctx, &domain.Activity{
+		EventID:    event.ID,
+		UserID:     event.UserID,
+		Type:       string(event.Type),
+		Metadata:   event.Metadata,
+		IPAddress:  event.IPAddress,
+		UserAgent:  event.UserAgent,
+		OccurredAt: event.OccurredAt,
+	})
+}
@@ -0,0 +1,57 @@
+package event
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tixgo/modules/user/domain"
+	sharedActivity "tixgo/shared/events/activity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryActivities records each activity once by its event ID, like the activity table
+type memoryActivities struct {
+	domain.ActivityRepository
+	recorded []*domain.Activity
+}
+
+func (r *memoryActivities) Record(_ context.Context, activity *domain.Activity) error {
+	for _, recorded := range r.recorded {
+		if recorded.EventID == activity.EventID {
+			return nil
+		}
+	}
+	r.recorded = append(r.recorded, activity)
+	return nil
+}
+
+func TestRecordActivity(t *testing.T) {
+	activities := &memoryActivities{}
+	handler := NewRecordActivity(activities)
+
+	event := sharedActivity.NewEventAccountActivity(7, sharedActivity.TypePurchase, map[string]string{"order_number": "ORD-42"})
+	event.IPAddress, event.UserAgent = "203.0.113.7", "test"
+
+	require.NoError(t, handler.Record(context.Background(), event))
+	require.NoError(t, handler.Record(context.Background(), event), "redeliveries are accepted")
+
+	require.Len(t, activities.recorded, 1, "and recorded once")
+	assert.Equal(t, &domain.Activity{
+		EventID:    event.ID,
+		UserID:     7,
+		Type:       "purchase",
+		Metadata:   map[string]string{"order_number": "ORD-42"},
+		IPAddress:  "203.0.113.7",
+		UserAgent:  "test",
+		OccurredAt: event.OccurredAt,
+	}, activities.recorded[0])
+
+	other := sharedActivity.NewEventAccountActivity(7, sharedActivity.TypeLogin, nil)
+	assert.NotEqual(t, event.ID, other.ID)
+	assert.WithinDuration(t, time.Now(), other.OccurredAt, time.Minute)
+	require.NoError(t, handler.Record(context.Background(), other))
+	assert.Len(t, activities.recorded, 2)
+}
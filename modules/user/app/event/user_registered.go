@@ -4,6 +4,7 @@ import (
 	"context"
 	"tixgo/modules/user/app/command"
 	"tixgo/modules/user/domain"
+	sharedKafka "tixgo/shared/kafka"
 
 	"github.com/duongptryu/gox/messaging"
 )
@@ -20,8 +21,9 @@ func NewSendMailOnUserRegistered(commandBus messaging.CommandBus) *sendMailOnUse
 
 func (h *sendMailOnUserRegistered) SendMailVerification(ctx context.Context, event *domain.EventUserRegistered) error {
 	sendMailVerificationCmd := &command.SendOTPVerifyMailCommand{
-		Mail: event.Email,
+		Mail:     event.Email,
+		UserType: event.UserType,
 	}
 
-	return h.commandBus.PublishCommand(ctx, sendMailVerificationCmd)
+	return h.commandBus.PublishCommand(sharedKafka.WithPartitionKey(ctx, event.Email), sendMailVerificationCmd)
 }
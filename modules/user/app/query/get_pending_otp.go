@@ -0,0 +1,49 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/user/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// GetPendingOTPQuery represents the query to read back the verification code mailed to an email
+type GetPendingOTPQuery struct {
+	Email string `form:"email" binding:"required,email"`
+}
+
+// PendingOTPResult represents the pending verification code of an email
+type PendingOTPResult struct {
+	Email string `json:"email"`
+	OTP   string `json:"otp"`
+}
+
+// GetPendingOTPHandler handles reading back pending verification codes, for the debug OTP echo
+type GetPendingOTPHandler struct {
+	otpStore    domain.OTPStore
+	emailPolicy domain.EmailPolicy
+}
+
+// NewGetPendingOTPHandler creates a new get pending OTP handler
+func NewGetPendingOTPHandler(otpStore domain.OTPStore, emailPolicy domain.EmailPolicy) *GetPendingOTPHandler {
+	return &GetPendingOTPHandler{
+		otpStore:    otpStore,
+		emailPolicy: emailPolicy,
+	}
+}
+
+// Handle executes the get pending OTP query, the code stays valid for verification
+func (h *GetPendingOTPHandler) Handle(ctx context.Context, query *GetPendingOTPQuery) (*PendingOTPResult, error) {
+	email := h.emailPolicy.Normalize(query.Email)
+
+	otp, err := h.otpStore.Get(ctx, email)
+	if err != nil {
+		if err == domain.ErrOTPNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get OTP")
+	}
+
+	return &PendingOTPResult{Email: email, OTP: otp}, nil
+}
@@ -0,0 +1,82 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"tixgo/modules/user/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// GetRegistrationStatusQuery represents the query to get where the registration of an email stands
+type GetRegistrationStatusQuery struct {
+	Email string `form:"email" binding:"required,email"`
+}
+
+// RegistrationStatusResult represents the registration status of an email
+type RegistrationStatusResult struct {
+	Email  string `json:"email"`
+	Status string `json:"status"`
+	// ExpiresAt is when a pending registration stops being verifiable, or when an expired one did
+	ExpiresAt string `json:"expires_at,omitempty"`
+	// CanRegister tells whether registering the email now starts a registration from scratch
+	CanRegister bool `json:"can_register"`
+}
+
+// GetRegistrationStatusHandler handles getting the registration status of an email
+type GetRegistrationStatusHandler struct {
+	userRepo      domain.UserRepository
+	tempUserStore domain.TempUserStore
+	emailPolicy   domain.EmailPolicy
+}
+
+// NewGetRegistrationStatusHandler creates a new get registration status handler
+func NewGetRegistrationStatusHandler(userRepo domain.UserRepository, tempUserStore domain.TempUserStore, emailPolicy domain.EmailPolicy) *GetRegistrationStatusHandler {
+	return &GetRegistrationStatusHandler{
+		userRepo:      userRepo,
+		tempUserStore: tempUserStore,
+		emailPolicy:   emailPolicy,
+	}
+}
+
+// Handle executes the get registration status query. An existing account wins over a pending
+// registration, which is void once its email is taken.
+func (h *GetRegistrationStatusHandler) Handle(ctx context.Context, query *GetRegistrationStatusQuery) (*RegistrationStatusResult, error) {
+	email := h.emailPolicy.Normalize(query.Email)
+	result := &RegistrationStatusResult{Email: email}
+
+	for _, lookup := range h.emailPolicy.LookupEmails(query.Email) {
+		_, err := h.userRepo.GetByEmail(ctx, lookup)
+		if err == nil {
+			result.Status = string(domain.RegistrationStatusRegistered)
+			return result, nil
+		}
+		if err != domain.ErrUserNotFound {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get user")
+		}
+	}
+
+	// registering again starts from scratch or restarts a pending registration, unless its domain is blocked
+	registrable := h.emailPolicy.CheckRegistrable(email) == nil
+
+	pending, err := h.tempUserStore.GetPending(ctx, email)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			result.Status = string(domain.RegistrationStatusNone)
+			result.CanRegister = registrable
+			return result, nil
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get pending registration")
+	}
+
+	result.ExpiresAt = pending.ExpiresAt.UTC().Format("2006-01-02T15:04:05Z")
+	if pending.IsExpired(time.Now()) {
+		result.Status = string(domain.RegistrationStatusExpired)
+	} else {
+		result.Status = string(domain.RegistrationStatusPending)
+	}
+	result.CanRegister = registrable
+
+	return result, nil
+}
@@ -20,22 +20,26 @@ type UserProfileResult struct {
 	FirstName     string `json:"first_name"`
 	LastName      string `json:"last_name"`
 	Phone         string `json:"phone,omitempty"`
+	PhoneCountry  string `json:"phone_country,omitempty"`
 	UserType      string `json:"user_type"`
 	Status        string `json:"status"`
 	EmailVerified bool   `json:"email_verified"`
+	PhoneVerified bool   `json:"phone_verified"`
 	CreatedAt     string `json:"created_at"`
 	LastLogin     string `json:"last_login,omitempty"`
 }
 
 // GetUserProfileHandler handles getting user profile
 type GetUserProfileHandler struct {
-	userRepo domain.UserRepository
+	userRepo       domain.UserRepository
+	loginEventRepo domain.LoginEventRepository
 }
 
 // NewGetUserProfileHandler creates a new get user profile handler
-func NewGetUserProfileHandler(userRepo domain.UserRepository) *GetUserProfileHandler {
+func NewGetUserProfileHandler(userRepo domain.UserRepository, loginEventRepo domain.LoginEventRepository) *GetUserProfileHandler {
 	return &GetUserProfileHandler{
-		userRepo: userRepo,
+		userRepo:       userRepo,
+		loginEventRepo: loginEventRepo,
 	}
 }
 
@@ -59,15 +63,23 @@ func (h *GetUserProfileHandler) Handle(ctx context.Context, query *GetUserProfil
 		UserType:      string(user.UserType),
 		Status:        string(user.Status),
 		EmailVerified: user.EmailVerified,
+		PhoneVerified: user.PhoneVerified,
 		CreatedAt:     user.CreatedAt.Format("2006-01-02T15:04:05Z"),
 	}
 
 	if user.Phone != nil {
 		result.Phone = *user.Phone
 	}
+	if user.PhoneCountry != nil {
+		result.PhoneCountry = *user.PhoneCountry
+	}
 
-	if user.LastLogin != nil {
-		result.LastLogin = user.LastLogin.Format("2006-01-02T15:04:05Z")
+	lastLogin, err := h.loginEventRepo.LastLogin(ctx, user.ID)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get last login")
+	}
+	if lastLogin != nil {
+		result.LastLogin = lastLogin.Format("2006-01-02T15:04:05Z")
 	}
 
 	return result, nil
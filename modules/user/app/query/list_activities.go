@@ -0,0 +1,71 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/user/domain"
+	"tixgo/shared/listing"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// maxActivityPageSize bounds the activities returned per page
+const maxActivityPageSize = 100
+
+// ListActivitiesQuery represents the query to list the activity feed of a user
+type ListActivitiesQuery struct {
+	UserID int64 `json:"-" form:"-"`
+}
+
+// ActivityItem represents an activity in the feed
+type ActivityItem struct {
+	ID         int64             `json:"id"`
+	Type       string            `json:"type"`
+	Metadata   map[string]string `json:"metadata"`
+	IPAddress  string            `json:"ip_address,omitempty"`
+	UserAgent  string            `json:"user_agent,omitempty"`
+	OccurredAt string            `json:"occurred_at"`
+}
+
+// ListActivitiesHandler handles listing the activity feed of a user
+type ListActivitiesHandler struct {
+	activityRepo domain.ActivityRepository
+}
+
+// NewListActivitiesHandler creates a new list activities handler
+func NewListActivitiesHandler(activityRepo domain.ActivityRepository) *ListActivitiesHandler {
+	return &ListActivitiesHandler{
+		activityRepo: activityRepo,
+	}
+}
+
+// Handle executes the list activities query
+func (h *ListActivitiesHandler) Handle(ctx context.Context, query *ListActivitiesQuery, paging *listing.Paging) ([]ActivityItem, error) {
+	// Ensure paging is not nil (should already be handled in HTTP layer)
+	if paging == nil {
+		paging = &listing.Paging{}
+		paging.Fulfill()
+	}
+	if paging.Limit > maxActivityPageSize {
+		paging.Limit = maxActivityPageSize
+	}
+
+	activities, err := h.activityRepo.ListByUserID(ctx, query.UserID, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list activities")
+	}
+
+	items := make([]ActivityItem, len(activities))
+	for i, activity := range activities {
+		items[i] = ActivityItem{
+			ID:         activity.ID,
+			Type:       activity.Type,
+			Metadata:   activity.Metadata,
+			IPAddress:  activity.IPAddress,
+			UserAgent:  activity.UserAgent,
+			OccurredAt: activity.OccurredAt.Format("2006-01-02T15:04:05Z"),
+		}
+	}
+
+	return items, nil
+}
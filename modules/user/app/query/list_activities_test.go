@@ -0,0 +1,65 @@
+package query
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tixgo/modules/user/domain"
+	"tixgo/shared/listing"
+
+	"github.com/duongptryu/gox/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// activitiesByUser serves the activities of users, recording the paging asked for
+type activitiesByUser struct {
+	domain.ActivityRepository
+	activities map[int64][]*domain.Activity
+	paging     *listing.Paging
+}
+
+func (r *activitiesByUser) ListByUserID(_ context.Context, userID int64, paging *listing.Paging) ([]*domain.Activity, error) {
+	r.paging = paging
+	return r.activities[userID], nil
+}
+
+func TestListActivitiesHandler(t *testing.T) {
+	occurredAt := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+	repo := &activitiesByUser{activities: map[int64][]*domain.Activity{
+		7: {
+			{ID: 2, UserID: 7, Type: "purchase", Metadata: map[string]string{"order_number": "ORD-42"}, OccurredAt: occurredAt},
+			{ID: 1, UserID: 7, Type: "login", IPAddress: "203.0.113.7", UserAgent: "test", OccurredAt: occurredAt.Add(-time.Hour)},
+		},
+		8: {{ID: 3, UserID: 8, Type: "login", OccurredAt: occurredAt}},
+	}}
+	handler := NewListActivitiesHandler(repo)
+
+	items, err := handler.Handle(context.Background(), &ListActivitiesQuery{UserID: 7}, &listing.Paging{Paging: pagination.Paging{Page: 1, Limit: 20}})
+	require.NoError(t, err)
+	assert.Equal(t, []ActivityItem{
+		{ID: 2, Type: "purchase", Metadata: map[string]string{"order_number": "ORD-42"}, OccurredAt: "2026-10-16T09:30:00Z"},
+		{ID: 1, Type: "login", IPAddress: "203.0.113.7", UserAgent: "test", OccurredAt: "2026-10-16T08:30:00Z"},
+	}, items, "only the activities of the user, as listed")
+
+	t.Run("caps the page size", func(t *testing.T) {
+		_, err := handler.Handle(context.Background(), &ListActivitiesQuery{UserID: 7}, &listing.Paging{Paging: pagination.Paging{Page: 1, Limit: 1000}})
+		require.NoError(t, err)
+		assert.Equal(t, maxActivityPageSize, repo.paging.Limit)
+	})
+
+	t.Run("pages by default", func(t *testing.T) {
+		_, err := handler.Handle(context.Background(), &ListActivitiesQuery{UserID: 8}, nil)
+		require.NoError(t, err)
+		require.NotNil(t, repo.paging)
+		assert.Positive(t, repo.paging.Limit)
+		assert.LessOrEqual(t, repo.paging.Limit, maxActivityPageSize)
+	})
+
+	t.Run("lists nothing for a user without activity", func(t *testing.T) {
+		items, err := handler.Handle(context.Background(), &ListActivitiesQuery{UserID: 9}, nil)
+		require.NoError(t, err)
+		assert.Empty(t, items)
+	})
+}
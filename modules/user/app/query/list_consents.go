@@ -0,0 +1,69 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/user/app/command"
+	"tixgo/modules/user/domain"
+	"tixgo/shared/listing"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ListConsentsQuery represents the query of the consent history of a user
+type ListConsentsQuery struct {
+	UserID int64  `json:"-" form:"-"`
+	Kind   string `json:"kind" form:"kind" binding:"omitempty,oneof=terms privacy marketing"`
+}
+
+// ListConsentsHandler handles listing the consent history of a user
+type ListConsentsHandler struct {
+	consentRepo domain.ConsentRepository
+}
+
+// NewListConsentsHandler creates a new list consents handler
+func NewListConsentsHandler(consentRepo domain.ConsentRepository) *ListConsentsHandler {
+	return &ListConsentsHandler{
+		consentRepo: consentRepo,
+	}
+}
+
+// Handle executes the list consents query
+func (h *ListConsentsHandler) Handle(ctx context.Context, query *ListConsentsQuery, paging *listing.Paging) ([]*command.ConsentResult, error) {
+	// Ensure paging is not nil (should already be handled in HTTP layer)
+	if paging == nil {
+		paging = &listing.Paging{}
+		paging.Fulfill()
+	}
+
+	filters := domain.ConsentFilters{UserID: query.UserID, Kind: domain.ConsentKind(query.Kind)}
+	consents, err := h.consentRepo.List(ctx, filters, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list consents")
+	}
+
+	items := make([]*command.ConsentResult, len(consents))
+	for i, consent := range consents {
+		items[i] = command.ToConsentResult(consent)
+	}
+
+	return items, nil
+}
+
+// LegalDocumentsResult are the current versions of the documents users accept
+type LegalDocumentsResult struct {
+	TermsVersion   string `json:"terms_version,omitempty"`
+	TermsURL       string `json:"terms_url,omitempty"`
+	PrivacyVersion string `json:"privacy_version,omitempty"`
+	PrivacyURL     string `json:"privacy_url,omitempty"`
+}
+
+// ToLegalDocumentsResult describes the documents of the consent policy
+func ToLegalDocumentsResult(policy domain.ConsentPolicy) *LegalDocumentsResult {
+	return &LegalDocumentsResult{
+		TermsVersion:   policy.TermsVersion,
+		TermsURL:       policy.TermsURL,
+		PrivacyVersion: policy.PrivacyVersion,
+		PrivacyURL:     policy.PrivacyURL,
+	}
+}
@@ -0,0 +1,64 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/user/app/command"
+	"tixgo/modules/user/domain"
+	"tixgo/shared/listing"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// FilterRegistrationRisksQuery represents the filters for listing the risk assessments of registrations
+type FilterRegistrationRisksQuery struct {
+	Decision     string  `json:"decision" form:"decision" binding:"omitempty,oneof=allow challenge review block"`
+	ReviewStatus string  `json:"review_status" form:"review_status" binding:"omitempty,oneof=pending approved rejected"`
+	IPAddress    *string `json:"ip_address" form:"ip_address" binding:"omitempty,ip"`
+}
+
+// ListRegistrationRisksHandler handles listing the risk assessments of registrations, the review
+// queue of admins when filtered on the pending ones
+type ListRegistrationRisksHandler struct {
+	riskRepo domain.RegistrationRiskRepository
+}
+
+// NewListRegistrationRisksHandler creates a new list registration risks handler
+func NewListRegistrationRisksHandler(riskRepo domain.RegistrationRiskRepository) *ListRegistrationRisksHandler {
+	return &ListRegistrationRisksHandler{
+		riskRepo: riskRepo,
+	}
+}
+
+// Handle executes the list registration risks query
+func (h *ListRegistrationRisksHandler) Handle(ctx context.Context, filters *FilterRegistrationRisksQuery, paging *listing.Paging) ([]*command.RegistrationRiskResult, error) {
+	// Ensure paging is not nil (should already be handled in HTTP layer)
+	if paging == nil {
+		paging = &listing.Paging{}
+		paging.Fulfill()
+	}
+
+	domainFilters := domain.ListRegistrationRiskFilters{
+		IPAddress: filters.IPAddress,
+	}
+	if filters.Decision != "" {
+		decision := domain.RiskDecision(filters.Decision)
+		domainFilters.Decision = &decision
+	}
+	if filters.ReviewStatus != "" {
+		status := domain.RiskReviewStatus(filters.ReviewStatus)
+		domainFilters.ReviewStatus = &status
+	}
+
+	risks, err := h.riskRepo.List(ctx, domainFilters, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list registration risks")
+	}
+
+	items := make([]*command.RegistrationRiskResult, len(risks))
+	for i, risk := range risks {
+		items[i] = command.ToRegistrationRiskResult(risk)
+	}
+
+	return items, nil
+}
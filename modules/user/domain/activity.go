@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"tixgo/shared/listing"
+)
+
+// Activity is an entry of the activity feed of a user
+type Activity struct {
+	ID         int64
+	EventID    string
+	UserID     int64
+	Type       string
+	Metadata   map[string]string
+	IPAddress  string
+	UserAgent  string
+	OccurredAt time.Time
+}
+
+// ActivityRepository defines the interface for the activity feed persistence
+type ActivityRepository interface {
+	// Record stores an activity, doing nothing if its EventID was already recorded
+	Record(ctx context.Context, activity *Activity) error
+
+	// ListByUserID retrieves the activities of a user, most recent first
+	ListByUserID(ctx context.Context, userID int64, paging *listing.Paging) ([]*Activity, error)
+}
@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"tixgo/shared/listing"
+)
+
+// ConsentKind is what a user consents to
+type ConsentKind string
+
+const (
+	ConsentKindTerms     ConsentKind = "terms"
+	ConsentKindPrivacy   ConsentKind = "privacy"
+	ConsentKindMarketing ConsentKind = "marketing"
+)
+
+// IsValidConsentKind checks if a consent kind is known
+func IsValidConsentKind(kind string) bool {
+	switch ConsentKind(kind) {
+	case ConsentKindTerms, ConsentKindPrivacy, ConsentKindMarketing:
+		return true
+	}
+	return false
+}
+
+// ConsentSource is where a consent was given
+type ConsentSource string
+
+const (
+	ConsentSourceRegistration ConsentSource = "registration"
+	ConsentSourceLogin        ConsentSource = "login"
+	ConsentSourceSettings     ConsentSource = "settings"
+)
+
+// Consent records a user accepting a version of a legal document, or granting or withdrawing their
+// marketing consent. Consents are never updated: the history of a user is kept for audits, the latest
+// consent of each kind being the one in force.
+type Consent struct {
+	ID     int64
+	UserID int64
+	Kind   ConsentKind
+	// Version is the version of the document accepted, empty for marketing
+	Version string
+	// Granted is false when a marketing consent is withdrawn, documents are only ever accepted
+	Granted   bool
+	Source    ConsentSource
+	IPAddress string
+	UserAgent string
+	CreatedAt time.Time
+}
+
+// NewConsent creates a consent of the user given now
+func NewConsent(userID int64, kind ConsentKind, version string, granted bool, source ConsentSource, ipAddress, userAgent string) *Consent {
+	return &Consent{
+		UserID:    userID,
+		Kind:      kind,
+		Version:   version,
+		Granted:   granted,
+		Source:    source,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		CreatedAt: time.Now(),
+	}
+}
+
+// ConsentPolicy is the current version of the terms of service and of the privacy policy users must
+// accept to use their account. A document without a version is not asked for.
+type ConsentPolicy struct {
+	TermsVersion   string
+	TermsURL       string
+	PrivacyVersion string
+	PrivacyURL     string
+}
+
+// Version returns the current version of the document of kind, empty when it is not asked for
+func (p ConsentPolicy) Version(kind ConsentKind) string {
+	switch kind {
+	case ConsentKindTerms:
+		return p.TermsVersion
+	case ConsentKindPrivacy:
+		return p.PrivacyVersion
+	}
+	return ""
+}
+
+// Outdated returns the documents whose current version the user has not accepted, given the latest
+// consent of each kind of the user
+func (p ConsentPolicy) Outdated(latest map[ConsentKind]*Consent) []ConsentKind {
+	var outdated []ConsentKind
+	for _, kind := range []ConsentKind{ConsentKindTerms, ConsentKindPrivacy} {
+		version := p.Version(kind)
+		if version == "" {
+			continue
+		}
+		if consent := latest[kind]; consent == nil || !consent.Granted || consent.Version != version {
+			outdated = append(outdated, kind)
+		}
+	}
+	return outdated
+}
+
+// Accept checks that the versions a user accepts are the current ones of the documents of kinds,
+// ErrConsentRequired otherwise, and returns their consents
+func (p ConsentPolicy) Accept(userID int64, kinds []ConsentKind, termsVersion, privacyVersion string, source ConsentSource, ipAddress, userAgent string) ([]*Consent, error) {
+	accepted := map[ConsentKind]string{ConsentKindTerms: termsVersion, ConsentKindPrivacy: privacyVersion}
+
+	consents := make([]*Consent, 0, len(kinds))
+	for _, kind := range kinds {
+		if accepted[kind] != p.Version(kind) {
+			return nil, ErrConsentRequired
+		}
+		consents = append(consents, NewConsent(userID, kind, accepted[kind], true, source, ipAddress, userAgent))
+	}
+	return consents, nil
+}
+
+// ConsentFilters are the filters of the consent history of a user
+type ConsentFilters struct {
+	UserID int64
+	// Kind keeps the consents of this kind, all of them when empty
+	Kind ConsentKind
+}
+
+// ConsentRepository defines the interface for the consent history persistence
+type ConsentRepository interface {
+	// Record stores consents
+	Record(ctx context.Context, consents ...*Consent) error
+
+	// Latest retrieves the latest consent of each kind of a user
+	Latest(ctx context.Context, userID int64) (map[ConsentKind]*Consent, error)
+
+	// List retrieves a page of the consent history of a user, newest first
+	List(ctx context.Context, filters ConsentFilters, paging *listing.Paging) ([]*Consent, error)
+}
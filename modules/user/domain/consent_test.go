@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsentPolicyOutdated(t *testing.T) {
+	policy := ConsentPolicy{TermsVersion: "2026-10", PrivacyVersion: "2026-09"}
+
+	assert.Equal(t, []ConsentKind{ConsentKindTerms, ConsentKindPrivacy}, policy.Outdated(nil))
+	assert.Empty(t, ConsentPolicy{}.Outdated(nil), "documents without a version are not asked for")
+	assert.Equal(t, []ConsentKind{ConsentKindPrivacy}, ConsentPolicy{PrivacyVersion: "2026-09"}.Outdated(nil))
+
+	latest := map[ConsentKind]*Consent{
+		ConsentKindTerms:     {Kind: ConsentKindTerms, Version: "2026-01", Granted: true},
+		ConsentKindPrivacy:   {Kind: ConsentKindPrivacy, Version: "2026-09", Granted: true},
+		ConsentKindMarketing: {Kind: ConsentKindMarketing, Granted: false},
+	}
+	assert.Equal(t, []ConsentKind{ConsentKindTerms}, policy.Outdated(latest), "the terms were updated since")
+
+	latest[ConsentKindTerms] = &Consent{Kind: ConsentKindTerms, Version: "2026-10", Granted: true}
+	assert.Empty(t, policy.Outdated(latest), "marketing is never required")
+}
+
+func TestConsentPolicyAccept(t *testing.T) {
+	policy := ConsentPolicy{TermsVersion: "2026-10", PrivacyVersion: "2026-09"}
+	kinds := []ConsentKind{ConsentKindTerms, ConsentKindPrivacy}
+
+	consents, err := policy.Accept(7, kinds, "2026-10", "2026-09", ConsentSourceLogin, "203.0.113.7", "test")
+	require.NoError(t, err)
+	require.Len(t, consents, 2)
+	assert.Equal(t, ConsentKindTerms, consents[0].Kind)
+	assert.Equal(t, "2026-10", consents[0].Version)
+	assert.Equal(t, ConsentKindPrivacy, consents[1].Kind)
+	assert.Equal(t, "2026-09", consents[1].Version)
+	for _, consent := range consents {
+		assert.Equal(t, int64(7), consent.UserID)
+		assert.True(t, consent.Granted)
+		assert.Equal(t, ConsentSourceLogin, consent.Source)
+		assert.Equal(t, "203.0.113.7", consent.IPAddress)
+	}
+
+	_, err = policy.Accept(7, kinds, "2026-01", "2026-09", ConsentSourceLogin, "", "")
+	assert.Equal(t, ErrConsentRequired, err, "an outdated version is not accepted")
+
+	_, err = policy.Accept(7, kinds, "", "", ConsentSourceLogin, "", "")
+	assert.Equal(t, ErrConsentRequired, err)
+
+	consents, err = policy.Accept(7, []ConsentKind{ConsentKindPrivacy}, "", "2026-09", ConsentSourceLogin, "", "")
+	require.NoError(t, err, "only the outdated documents are accepted again")
+	assert.Len(t, consents, 1)
+}
@@ -0,0 +1,69 @@
+package domain
+
+import "strings"
+
+// gmailDomains are the domains of Gmail mailboxes, googlemail.com delivering to the same ones
+var gmailDomains = map[string]bool{"gmail.com": true, "googlemail.com": true}
+
+// EmailPolicy decides how the emails of accounts are compared and which can register one. Emails are
+// trimmed and lowercased; with FoldGmail the dots and +tag of Gmail addresses are dropped as well, since
+// Gmail delivers j.doe+tix@googlemail.com to jdoe@gmail.com, so they cannot open several accounts.
+type EmailPolicy struct {
+	FoldGmail bool
+	// disposable are the domains of throwaway mailboxes, lowercased
+	disposable map[string]bool
+}
+
+// NewEmailPolicy creates the email policy blocking the registration of emails of the disposable domains
+// and their subdomains
+func NewEmailPolicy(foldGmail bool, disposableDomains []string) EmailPolicy {
+	disposable := make(map[string]bool, len(disposableDomains))
+	for _, domain := range disposableDomains {
+		disposable[strings.ToLower(strings.TrimSpace(domain))] = true
+	}
+	return EmailPolicy{FoldGmail: foldGmail, disposable: disposable}
+}
+
+// Normalize returns the email accounts are stored and looked up by. Every email goes through it before
+// reaching a store, so two spellings of a mailbox never make two accounts.
+func (p EmailPolicy) Normalize(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if !p.FoldGmail {
+		return email
+	}
+
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || !gmailDomains[domain] {
+		return email
+	}
+	local, _, _ = strings.Cut(local, "+")
+	return strings.ReplaceAll(local, ".", "") + "@gmail.com"
+}
+
+// CheckRegistrable returns ErrDisposableEmail if email is a mailbox of a disposable domain
+func (p EmailPolicy) CheckRegistrable(email string) error {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return nil
+	}
+
+	domain := strings.ToLower(strings.TrimSpace(email[at+1:]))
+	for domain != "" {
+		if p.disposable[domain] {
+			return ErrDisposableEmail
+		}
+		_, domain, _ = strings.Cut(domain, ".")
+	}
+	return nil
+}
+
+// LookupEmails returns the emails an account of email may be stored under, normalized first. Accounts
+// registered before FoldGmail was turned on keep their dots and +tag, so they are tried second.
+func (p EmailPolicy) LookupEmails(email string) []string {
+	normalized := p.Normalize(email)
+	lowered := strings.ToLower(strings.TrimSpace(email))
+	if lowered == normalized {
+		return []string{normalized}
+	}
+	return []string{normalized, lowered}
+}
@@ -1,6 +1,10 @@
 package domain
 
-import "github.com/duongptryu/gox/syserr"
+import (
+	"tixgo/shared/errstack"
+
+	"github.com/duongptryu/gox/syserr"
+)
 
 // Domain-specific error codes for client handling
 const (	
@@ -8,8 +12,10 @@ const (
 	UserNotFoundCode syserr.Code = "user_not_found"
 
 	// User registration errors
-	UserAlreadyExistsCode syserr.Code = "user_already_exists"
-	InvalidUserTypeCode   syserr.Code = "invalid_user_type"
+	UserAlreadyExistsCode      syserr.Code = "user_already_exists"
+	InvalidUserTypeCode        syserr.Code = "invalid_user_type"
+	UserTypeNotRegistrableCode syserr.Code = "user_type_not_registrable"
+	DisposableEmailCode        syserr.Code = "disposable_email"
 
 	// Authentication errors
 	InvalidCredentialsCode syserr.Code = "invalid_credentials"
@@ -23,27 +29,59 @@ const (
 	InvalidOTPCode  syserr.Code = "invalid_otp"
 	OTPExpiredCode  syserr.Code = "otp_expired"
 	OTPNotFoundCode syserr.Code = "otp_not_found"
+
+	// Phone verification errors
+	PhoneNotVerifiedCode     syserr.Code = "phone_not_verified"
+	PhoneAlreadyVerifiedCode syserr.Code = "phone_already_verified"
+	OTPResendTooSoonCode     syserr.Code = "otp_resend_too_soon"
+
+	// Registration risk errors
+	CaptchaRequiredCode          syserr.Code = "captcha_required"
+	CaptchaInvalidCode           syserr.Code = "captcha_invalid"
+	RegistrationBlockedCode      syserr.Code = "registration_blocked"
+	RegistrationRiskNotFoundCode syserr.Code = "registration_risk_not_found"
+	RiskReviewNotPendingCode     syserr.Code = "risk_review_not_pending"
+
+	// Consent errors
+	ConsentRequiredCode syserr.Code = "consent_required"
 )
 
 // Domain-specific errors with specific codes
 var (
 	// User not found errors
-	ErrUserNotFound = syserr.New(UserNotFoundCode, "user not found")
+	ErrUserNotFound = errstack.Sentinel(UserNotFoundCode, "user not found")
 
 	// User registration errors
-	ErrUserAlreadyExists = syserr.New(UserAlreadyExistsCode, "user with this email already exists")
-	ErrInvalidUserType   = syserr.New(InvalidUserTypeCode, "invalid user type, must be: customer, organizer, or admin")
+	ErrUserAlreadyExists      = errstack.Sentinel(UserAlreadyExistsCode, "user with this email already exists")
+	ErrInvalidUserType        = errstack.Sentinel(InvalidUserTypeCode, "invalid user type, must be: customer, organizer, or admin")
+	ErrUserTypeNotRegistrable = errstack.Sentinel(UserTypeNotRegistrableCode, "only customer and organizer accounts can be registered")
+	ErrDisposableEmail        = errstack.Sentinel(DisposableEmailCode, "disposable email addresses cannot be used, please register with a permanent one")
 
 	// Authentication errors
-	ErrInvalidCredentials = syserr.New(InvalidCredentialsCode, "invalid email or password")
+	ErrInvalidCredentials = errstack.Sentinel(InvalidCredentialsCode, "invalid email or password")
 
 	// Authorization/Access errors
-	ErrEmailNotVerified = syserr.New(EmailNotVerifiedCode, "email address not verified, please check your email for verification code")
-	ErrUserInactive     = syserr.New(UserInactiveCode, "user account is inactive, please contact support")
-	ErrUserSuspended    = syserr.New(UserSuspendedCode, "user account is suspended, please contact support")
+	ErrEmailNotVerified = errstack.Sentinel(EmailNotVerifiedCode, "email address not verified, please check your email for verification code")
+	ErrUserInactive     = errstack.Sentinel(UserInactiveCode, "user account is inactive, please contact support")
+	ErrUserSuspended    = errstack.Sentinel(UserSuspendedCode, "user account is suspended, please contact support")
 
 	// OTP errors
-	ErrInvalidOTP  = syserr.New(InvalidOTPCode, "invalid verification code")
-	ErrOTPExpired  = syserr.New(OTPExpiredCode, "verification code has expired, please request a new one")
-	ErrOTPNotFound = syserr.New(OTPNotFoundCode, "no verification code found for this email")
+	ErrInvalidOTP  = errstack.Sentinel(InvalidOTPCode, "invalid verification code")
+	ErrOTPExpired  = errstack.Sentinel(OTPExpiredCode, "verification code has expired, please request a new one")
+	ErrOTPNotFound = errstack.Sentinel(OTPNotFoundCode, "no verification code found for this email")
+
+	// Phone verification errors
+	ErrPhoneNotVerified     = errstack.Sentinel(PhoneNotVerifiedCode, "phone number not verified, please verify it first")
+	ErrPhoneAlreadyVerified = errstack.Sentinel(PhoneAlreadyVerifiedCode, "phone number already verified")
+	ErrOTPResendTooSoon     = errstack.Sentinel(OTPResendTooSoonCode, "a verification code was just sent, please wait before asking for another one")
+
+	// Registration risk errors
+	ErrCaptchaRequired          = errstack.Sentinel(CaptchaRequiredCode, "please solve the CAPTCHA to register")
+	ErrCaptchaInvalid           = errstack.Sentinel(CaptchaInvalidCode, "the CAPTCHA could not be verified, please solve it again")
+	ErrRegistrationBlocked      = errstack.Sentinel(RegistrationBlockedCode, "registration refused, please contact support")
+	ErrRegistrationRiskNotFound = errstack.Sentinel(RegistrationRiskNotFoundCode, "registration risk assessment not found")
+	ErrRiskReviewNotPending     = errstack.Sentinel(RiskReviewNotPendingCode, "registration is not pending review")
+
+	// Consent errors
+	ErrConsentRequired = errstack.Sentinel(ConsentRequiredCode, "please accept the current terms of service and privacy policy")
 )
@@ -3,13 +3,16 @@ package domain
 import "time"
 
 type EventUserRegistered struct {
-	Email      string
+	Email string
+	// UserType picks the verification flow, events published before it existed are customers
+	UserType   UserType
 	OccurredAt time.Time
 }
 
-func NewEventUserRegistered(email string) *EventUserRegistered {
+func NewEventUserRegistered(email string, userType UserType) *EventUserRegistered {
 	return &EventUserRegistered{
 		Email:      email,
+		UserType:   userType,
 		OccurredAt: time.Now(),
 	}
 }
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// LoginEvent records a successful login of a user
+type LoginEvent struct {
+	ID        int64
+	UserID    int64
+	IPAddress string
+	UserAgent string
+	CreatedAt time.Time
+}
+
+// NewLoginEvent creates the login event of a user logging in now
+func NewLoginEvent(userID int64, ipAddress, userAgent string) *LoginEvent {
+	return &LoginEvent{
+		UserID:    userID,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		CreatedAt: time.Now(),
+	}
+}
+
+// LoginEventRepository defines the interface for the login history persistence
+type LoginEventRepository interface {
+	// Record stores a login event
+	Record(ctx context.Context, event *LoginEvent) error
+
+	// LastLogin retrieves the time of the latest login of a user, nil if they never logged in
+	LastLogin(ctx context.Context, userID int64) (*time.Time, error)
+}
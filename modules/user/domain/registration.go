@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+const (
+	// PendingRegistrationTTL is how long a registration waits for the verification of its email
+	PendingRegistrationTTL = 10 * time.Minute
+	// ExpiredRegistrationRetention is how long an expired registration is remembered,
+	// so its status tells the user to register again rather than that nothing is known
+	ExpiredRegistrationRetention = 24 * time.Hour
+)
+
+// RegistrationStatus is where the registration of an email stands
+type RegistrationStatus string
+
+const (
+	// RegistrationStatusNone means no registration is known for the email
+	RegistrationStatusNone RegistrationStatus = "none"
+	// RegistrationStatusPending means the registration waits for the verification code
+	RegistrationStatusPending RegistrationStatus = "pending"
+	// RegistrationStatusExpired means the registration was not verified in time and must be restarted
+	RegistrationStatusExpired RegistrationStatus = "expired"
+	// RegistrationStatusRegistered means the email is verified and the account exists
+	RegistrationStatusRegistered RegistrationStatus = "registered"
+)
+
+// PendingRegistration is a registered user waiting for the verification of their email
+type PendingRegistration struct {
+	User      *User
+	ExpiresAt time.Time
+}
+
+// IsExpired checks if the registration can no longer be verified
+func (p *PendingRegistration) IsExpired(now time.Time) bool {
+	return !now.Before(p.ExpiresAt)
+}
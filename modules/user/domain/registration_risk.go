@@ -0,0 +1,358 @@
+package domain
+
+import (
+	"context"
+	"math"
+	"net"
+	"strings"
+	"time"
+	"unicode"
+
+	"tixgo/shared/listing"
+)
+
+// RiskDecision is what becomes of a registration given its risk score
+type RiskDecision string
+
+const (
+	// RiskDecisionAllow lets the registration through
+	RiskDecisionAllow RiskDecision = "allow"
+	// RiskDecisionChallenge lets the registration through once the user solves a CAPTCHA
+	RiskDecisionChallenge RiskDecision = "challenge"
+	// RiskDecisionReview lets the registration through, its account inactive until an admin approves it
+	RiskDecisionReview RiskDecision = "review"
+	// RiskDecisionBlock refuses the registration
+	RiskDecisionBlock RiskDecision = "block"
+)
+
+// IsValidRiskDecision checks if a risk decision is known
+func IsValidRiskDecision(decision string) bool {
+	switch RiskDecision(decision) {
+	case RiskDecisionAllow, RiskDecisionChallenge, RiskDecisionReview, RiskDecisionBlock:
+		return true
+	}
+	return false
+}
+
+// RiskReviewStatus is where the review of a registration queued for review stands
+type RiskReviewStatus string
+
+const (
+	RiskReviewPending  RiskReviewStatus = "pending"
+	RiskReviewApproved RiskReviewStatus = "approved"
+	RiskReviewRejected RiskReviewStatus = "rejected"
+)
+
+// IsValidRiskReviewStatus checks if a review status is known
+func IsValidRiskReviewStatus(status string) bool {
+	switch RiskReviewStatus(status) {
+	case RiskReviewPending, RiskReviewApproved, RiskReviewRejected:
+		return true
+	}
+	return false
+}
+
+// Reasons a registration scores, recorded with its assessment
+const (
+	RiskReasonIPReputation   = "ip_reputation"
+	RiskReasonIPHistory      = "ip_history"
+	RiskReasonIPVelocity     = "ip_velocity"
+	RiskReasonDeviceVelocity = "device_velocity"
+	RiskReasonNoDevice       = "no_device"
+	RiskReasonEmailEntropy   = "email_entropy"
+)
+
+const (
+	// DefaultChallengeScore, DefaultReviewScore and DefaultBlockScore are the scores from which a
+	// registration is challenged, queued for review or blocked when the policy sets none
+	DefaultChallengeScore = 30
+	DefaultReviewScore    = 60
+	DefaultBlockScore     = 90
+	// DefaultVelocityWindow is the window registrations are counted in per IP and per device
+	DefaultVelocityWindow = time.Hour
+	// DefaultIPLimit and DefaultDeviceLimit are the registrations of the window that score nothing
+	DefaultIPLimit     = 5
+	DefaultDeviceLimit = 2
+	// DefaultEmailEntropyThreshold is the entropy, in bits per character of the local part, from which
+	// an email looks generated rather than chosen
+	DefaultEmailEntropyThreshold = 3.5
+
+	// minEntropyLength is the shortest local part whose entropy means anything
+	minEntropyLength = 10
+	// RiskFlaggedLookback is how far back the registrations blocked or rejected from an IP count
+	RiskFlaggedLookback = 30 * 24 * time.Hour
+)
+
+// RegistrationSignals are what a registration is scored on, recorded with its assessment to train
+// models on later
+type RegistrationSignals struct {
+	// IPListed tells whether the IP is in one of the risky networks of the policy
+	IPListed bool `json:"ip_listed"`
+	// IPFlagged is the registrations from the IP blocked or rejected within RiskFlaggedLookback
+	IPFlagged int `json:"ip_flagged"`
+	// RegistrationsFromIP and RegistrationsFromDevice are the registrations of the velocity window
+	// before this one
+	RegistrationsFromIP     int     `json:"registrations_from_ip"`
+	RegistrationsFromDevice int     `json:"registrations_from_device"`
+	HasDeviceID             bool    `json:"has_device_id"`
+	EmailEntropy            float64 `json:"email_entropy"`
+	EmailLocalLength        int     `json:"email_local_length"`
+	EmailDigits             int     `json:"email_digits"`
+	// CaptchaPassed tells whether the user solved a CAPTCHA with the registration
+	CaptchaPassed bool `json:"captcha_passed"`
+}
+
+// RiskPolicy scores registrations and decides what becomes of them
+type RiskPolicy struct {
+	ChallengeScore int
+	ReviewScore    int
+	BlockScore     int
+	VelocityWindow time.Duration
+	IPLimit        int
+	DeviceLimit    int
+	// EmailEntropyThreshold is the entropy of the local part of an email from which it scores
+	EmailEntropyThreshold float64
+	// CaptchaEnabled tells whether users can be challenged; without a CAPTCHA, registrations to
+	// challenge are queued for review
+	CaptchaEnabled bool
+	// riskyNetworks are the networks of bad reputation, e.g. of abusive hosting providers
+	riskyNetworks []*net.IPNet
+}
+
+// NewRiskPolicy creates a risk policy with the defaults for the zero values, scoring the IPs of the
+// risky networks, given in CIDR notation
+func NewRiskPolicy(policy RiskPolicy, riskyNetworks []string) (RiskPolicy, error) {
+	if policy.ChallengeScore == 0 {
+		policy.ChallengeScore = DefaultChallengeScore
+	}
+	if policy.ReviewScore == 0 {
+		policy.ReviewScore = DefaultReviewScore
+	}
+	if policy.BlockScore == 0 {
+		policy.BlockScore = DefaultBlockScore
+	}
+	if policy.VelocityWindow == 0 {
+		policy.VelocityWindow = DefaultVelocityWindow
+	}
+	if policy.IPLimit == 0 {
+		policy.IPLimit = DefaultIPLimit
+	}
+	if policy.DeviceLimit == 0 {
+		policy.DeviceLimit = DefaultDeviceLimit
+	}
+	if policy.EmailEntropyThreshold == 0 {
+		policy.EmailEntropyThreshold = DefaultEmailEntropyThreshold
+	}
+
+	for _, cidr := range riskyNetworks {
+		_, network, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return RiskPolicy{}, err
+		}
+		policy.riskyNetworks = append(policy.riskyNetworks, network)
+	}
+	return policy, nil
+}
+
+// IsRisky tells whether ip belongs to one of the risky networks
+func (p RiskPolicy) IsRisky(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range p.riskyNetworks {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// Assess scores the signals of a registration from 0 to 100 and decides what becomes of it. Solving a
+// CAPTCHA lets a challenged registration through, see RegistrationRisk.Err; it does not lower the score.
+func (p RiskPolicy) Assess(signals RegistrationSignals) (int, RiskDecision, []string) {
+	score := 0
+	reasons := []string{}
+	add := func(points int, reason string) {
+		if points > 0 {
+			score += points
+			reasons = append(reasons, reason)
+		}
+	}
+
+	if signals.IPListed {
+		add(50, RiskReasonIPReputation)
+	}
+	add(min(20*signals.IPFlagged, 40), RiskReasonIPHistory)
+	add(min(10*(signals.RegistrationsFromIP+1-p.IPLimit), 40), RiskReasonIPVelocity)
+	if signals.HasDeviceID {
+		add(min(20*(signals.RegistrationsFromDevice+1-p.DeviceLimit), 60), RiskReasonDeviceVelocity)
+	} else {
+		add(5, RiskReasonNoDevice)
+	}
+	if signals.EmailLocalLength >= minEntropyLength && signals.EmailEntropy >= p.EmailEntropyThreshold {
+		add(25, RiskReasonEmailEntropy)
+	}
+	score = min(score, 100)
+
+	switch {
+	case score >= p.BlockScore:
+		return score, RiskDecisionBlock, reasons
+	case score >= p.ReviewScore:
+		return score, RiskDecisionReview, reasons
+	case score >= p.ChallengeScore && !p.CaptchaEnabled:
+		return score, RiskDecisionReview, reasons
+	case score >= p.ChallengeScore:
+		return score, RiskDecisionChallenge, reasons
+	default:
+		return score, RiskDecisionAllow, reasons
+	}
+}
+
+// EmailSignals returns the Shannon entropy, in bits per character, the length and the digits of the
+// local part of an email, without its +tag and separators. Generated mailboxes, e.g. x7k2q9zv1pw3,
+// use many characters once each where chosen ones repeat the letters of a name.
+func EmailSignals(email string) (entropy float64, length, digits int) {
+	local := email
+	if at := strings.LastIndex(email, "@"); at >= 0 {
+		local = email[:at]
+	}
+	local, _, _ = strings.Cut(local, "+")
+
+	counts := map[rune]int{}
+	for _, r := range strings.ToLower(local) {
+		if r == '.' || r == '_' || r == '-' {
+			continue
+		}
+		counts[r]++
+		length++
+		if unicode.IsDigit(r) {
+			digits++
+		}
+	}
+
+	for _, count := range counts {
+		p := float64(count) / float64(length)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy, length, digits
+}
+
+// RegistrationRisk is the risk assessment of a registration attempt, recorded whatever its decision
+type RegistrationRisk struct {
+	ID        int64
+	Email     string
+	IPAddress string
+	DeviceID  string
+	UserAgent string
+	Signals   RegistrationSignals
+	Score     int
+	Decision  RiskDecision
+	Reasons   []string
+	// ReviewStatus is set for registrations queued for review
+	ReviewStatus RiskReviewStatus
+	ReviewedBy   *int64
+	ReviewReason string
+	ReviewedAt   *time.Time
+	// UserID is the account of the email once verified, listed with the assessments only
+	UserID    *int64
+	CreatedAt time.Time
+}
+
+// NewRegistrationRisk assesses a registration attempt with the policy
+func NewRegistrationRisk(policy RiskPolicy, email, ipAddress, deviceID, userAgent string, signals RegistrationSignals) *RegistrationRisk {
+	score, decision, reasons := policy.Assess(signals)
+	risk := &RegistrationRisk{
+		Email:     email,
+		IPAddress: ipAddress,
+		DeviceID:  deviceID,
+		UserAgent: userAgent,
+		Signals:   signals,
+		Score:     score,
+		Decision:  decision,
+		Reasons:   reasons,
+		CreatedAt: time.Now(),
+	}
+	if decision == RiskDecisionReview {
+		risk.ReviewStatus = RiskReviewPending
+	}
+	return risk
+}
+
+// Err returns the error refusing the registration, nil when it goes through
+func (r *RegistrationRisk) Err() error {
+	switch r.Decision {
+	case RiskDecisionBlock:
+		return ErrRegistrationBlocked
+	case RiskDecisionChallenge:
+		if r.Signals.CaptchaPassed {
+			return nil
+		}
+		return ErrCaptchaRequired
+	default:
+		return nil
+	}
+}
+
+// Approve records an admin approving a registration queued for review
+func (r *RegistrationRisk) Approve(reviewerID int64) error {
+	return r.review(RiskReviewApproved, reviewerID, "")
+}
+
+// Reject records an admin rejecting a registration queued for review
+func (r *RegistrationRisk) Reject(reviewerID int64, reason string) error {
+	return r.review(RiskReviewRejected, reviewerID, reason)
+}
+
+func (r *RegistrationRisk) review(status RiskReviewStatus, reviewerID int64, reason string) error {
+	if r.ReviewStatus != RiskReviewPending {
+		return ErrRiskReviewNotPending
+	}
+	now := time.Now()
+	r.ReviewStatus = status
+	r.ReviewedBy = &reviewerID
+	r.ReviewReason = reason
+	r.ReviewedAt = &now
+	return nil
+}
+
+// RegistrationVelocity counts the registrations per IP and per device in a window
+type RegistrationVelocity interface {
+	// Count returns the registrations of the window from the IP and from the device, zero for an
+	// empty device
+	Count(ctx context.Context, ipAddress, deviceID string, window time.Duration) (fromIP, fromDevice int, err error)
+
+	// Record counts a registration from the IP and the device
+	Record(ctx context.Context, ipAddress, deviceID string, window time.Duration) error
+}
+
+// CaptchaVerifier verifies the CAPTCHA tokens clients get when their user solves one
+type CaptchaVerifier interface {
+	// Verify tells whether token is a solved CAPTCHA, of the user at ipAddress
+	Verify(ctx context.Context, token, ipAddress string) (bool, error)
+}
+
+// ListRegistrationRiskFilters are the filters of the registration risk assessments
+type ListRegistrationRiskFilters struct {
+	Decision     *RiskDecision
+	ReviewStatus *RiskReviewStatus
+	IPAddress    *string
+}
+
+// RegistrationRiskRepository defines the interface for the persistence of the risk assessments
+type RegistrationRiskRepository interface {
+	// Record stores the assessment of a registration attempt
+	Record(ctx context.Context, risk *RegistrationRisk) error
+
+	// CountFlagged returns the registrations from an IP blocked, or rejected by a review, since a time
+	CountFlagged(ctx context.Context, ipAddress string, since time.Time) (int, error)
+
+	// GetByID retrieves an assessment, ErrRegistrationRiskNotFound if there is none
+	GetByID(ctx context.Context, id int64) (*RegistrationRisk, error)
+
+	// List retrieves a page of assessments, newest first
+	List(ctx context.Context, filters ListRegistrationRiskFilters, paging *listing.Paging) ([]*RegistrationRisk, error)
+
+	// SaveReview records the review of an assessment still pending, ErrRiskReviewNotPending otherwise
+	SaveReview(ctx context.Context, risk *RegistrationRisk) error
+}
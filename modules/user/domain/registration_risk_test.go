@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailSignals(t *testing.T) {
+	entropy, length, digits := EmailSignals("jane.doe+tickets@example.com")
+	assert.Equal(t, 7, length, "separators and the +tag do not count")
+	assert.Zero(t, digits)
+	assert.Less(t, entropy, DefaultEmailEntropyThreshold)
+
+	entropy, length, digits = EmailSignals("x7k2q9zv1pw3@example.com")
+	assert.Equal(t, 12, length)
+	assert.Equal(t, 5, digits)
+	assert.Greater(t, entropy, DefaultEmailEntropyThreshold)
+}
+
+func TestRiskPolicy_Assess(t *testing.T) {
+	policy, err := NewRiskPolicy(RiskPolicy{CaptchaEnabled: true}, []string{"203.0.113.0/24"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		signals  RegistrationSignals
+		score    int
+		decision RiskDecision
+	}{
+		{name: "a clean registration is allowed", signals: RegistrationSignals{HasDeviceID: true}, decision: RiskDecisionAllow},
+		{name: "a missing device alone is allowed", signals: RegistrationSignals{}, score: 5, decision: RiskDecisionAllow},
+		{
+			name:     "a generated email from a busy IP is challenged",
+			signals:  RegistrationSignals{HasDeviceID: true, RegistrationsFromIP: 5, EmailEntropy: 3.6, EmailLocalLength: 12},
+			score:    35,
+			decision: RiskDecisionChallenge,
+		},
+		{
+			name:     "a listed IP is reviewed",
+			signals:  RegistrationSignals{IPListed: true, RegistrationsFromDevice: 2, HasDeviceID: true},
+			score:    70,
+			decision: RiskDecisionReview,
+		},
+		{
+			name:     "the score is capped",
+			signals:  RegistrationSignals{IPListed: true, IPFlagged: 5, RegistrationsFromIP: 20},
+			score:    100,
+			decision: RiskDecisionBlock,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, decision, _ := policy.Assess(tt.signals)
+			assert.Equal(t, tt.score, score)
+			assert.Equal(t, tt.decision, decision)
+		})
+	}
+
+	t.Run("without a CAPTCHA a challenge is reviewed", func(t *testing.T) {
+		noCaptcha := policy
+		noCaptcha.CaptchaEnabled = false
+		_, decision, _ := noCaptcha.Assess(RegistrationSignals{IPListed: true, HasDeviceID: true})
+		assert.Equal(t, RiskDecisionReview, decision)
+	})
+
+	t.Run("a listed IP is one of the risky networks", func(t *testing.T) {
+		assert.True(t, policy.IsRisky("203.0.113.42"))
+		assert.False(t, policy.IsRisky("198.51.100.1"))
+		assert.False(t, policy.IsRisky("not an ip"))
+	})
+
+	t.Run("an invalid network is refused", func(t *testing.T) {
+		_, err := NewRiskPolicy(RiskPolicy{}, []string{"203.0.113.0/33"})
+		assert.Error(t, err)
+	})
+}
+
+func TestRegistrationRisk_Review(t *testing.T) {
+	policy, err := NewRiskPolicy(RiskPolicy{}, []string{"203.0.113.0/24"})
+	require.NoError(t, err)
+
+	risk := NewRegistrationRisk(policy, "jane@example.com", "203.0.113.9", "", "", RegistrationSignals{IPListed: true, HasDeviceID: true})
+	require.Equal(t, RiskDecisionReview, risk.Decision)
+	assert.Equal(t, RiskReviewPending, risk.ReviewStatus)
+	assert.NoError(t, risk.Err(), "a registration to review goes through, its account inactive")
+
+	require.NoError(t, risk.Reject(7, "spam wave"))
+	assert.Equal(t, RiskReviewRejected, risk.ReviewStatus)
+	assert.Equal(t, int64(7), *risk.ReviewedBy)
+	assert.Equal(t, ErrRiskReviewNotPending, risk.Approve(7))
+
+	allowed := NewRegistrationRisk(policy, "jane@example.com", "198.51.100.1", "device-1", "", RegistrationSignals{HasDeviceID: true})
+	assert.Equal(t, ErrRiskReviewNotPending, allowed.Approve(7), "only registrations queued for review are reviewed")
+}
@@ -1,6 +1,12 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
+
+// OTPTTL is how long a verification code stays valid once sent
+const OTPTTL = 5 * time.Minute
 
 // UserRepository defines the interface for user persistence
 type UserRepository interface {
@@ -13,7 +19,8 @@ type UserRepository interface {
 	// GetByEmail retrieves a user by email
 	GetByEmail(ctx context.Context, email string) (*User, error)
 
-	// Update updates an existing user
+	// Update updates an existing user. An empty PasswordHash and a nil DateOfBirth keep the stored ones,
+	// users read from the cache having neither
 	Update(ctx context.Context, user *User) error
 
 	// Delete deletes a user by ID
@@ -25,6 +32,10 @@ type OTPStore interface {
 	// Store stores an OTP for a user email with expiration
 	Store(ctx context.Context, email, otp string) error
 
+	// Get returns the pending OTP of a user email without consuming it, ErrOTPNotFound if there is none.
+	// Only the debug OTP echo reads codes back.
+	Get(ctx context.Context, email string) (string, error)
+
 	// Verify verifies an OTP for a user email and removes it if valid
 	Verify(ctx context.Context, email, otp string) error
 
@@ -40,6 +51,10 @@ type TempUserStore interface {
 	// Get retrieves a temporary user by email
 	Get(ctx context.Context, email string) (*User, error)
 
+	// GetPending retrieves the pending registration of an email, expired ones included for
+	// ExpiredRegistrationRetention. It returns ErrUserNotFound when none is known.
+	GetPending(ctx context.Context, email string) (*PendingRegistration, error)
+
 	// Delete removes a temporary user by email
 	Delete(ctx context.Context, email string) error
 }
@@ -3,6 +3,8 @@ package domain
 import (
 	"time"
 
+	"tixgo/shared/phone"
+
 	"github.com/duongptryu/gox/syserr"
 
 	"golang.org/x/crypto/bcrypt"
@@ -33,18 +35,30 @@ type User struct {
 	PasswordHash  string
 	FirstName     string
 	LastName      string
-	Phone         *string
+	Phone         *string // in E.164 format
+	PhoneCountry  *string // ISO 3166-1 alpha-2 region of Phone
 	DateOfBirth   *time.Time
 	UserType      UserType
 	Status        UserStatus
 	EmailVerified bool
+	// PhoneVerified tells that the user confirmed Phone with a code sent to it
+	PhoneVerified bool
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
-	LastLogin     *time.Time
+	// Consents are the ones given with a pending registration, recorded once the user is created
+	Consents []*Consent
 }
 
-// NewUser creates a new user with hashed password
+// NewUserCustomer creates a new customer with hashed password
 func NewUserCustomer(email, password, firstName, lastName string) (*User, error) {
+	return NewUser(email, password, firstName, lastName, UserTypeCustomer)
+}
+
+// NewUser creates a new user of the given type with hashed password
+func NewUser(email, password, firstName, lastName string, userType UserType) (*User, error) {
+	if !IsValidUserType(string(userType)) {
+		return nil, ErrInvalidUserType
+	}
 	if email == "" {
 		return nil, syserr.New(syserr.InvalidArgumentCode, "email is required")
 	}
@@ -69,7 +83,7 @@ func NewUserCustomer(email, password, firstName, lastName string) (*User, error)
 		PasswordHash:  hashedPassword,
 		FirstName:     firstName,
 		LastName:      lastName,
-		UserType:      UserTypeCustomer,
+		UserType:      userType,
 		Status:        UserStatusActive,
 		EmailVerified: false,
 		CreatedAt:     now,
@@ -92,11 +106,38 @@ func (u *User) VerifyEmail() {
 	u.UpdatedAt = time.Now()
 }
 
-// UpdateLastLogin updates the user's last login timestamp
-func (u *User) UpdateLastLogin() {
-	now := time.Now()
-	u.LastLogin = &now
-	u.UpdatedAt = now
+// Activate lets the user in again, e.g. once an admin approved their registration
+func (u *User) Activate() {
+	u.Status = UserStatusActive
+	u.UpdatedAt = time.Now()
+}
+
+// Suspend keeps the user out until an admin activates them again
+func (u *User) Suspend() {
+	u.Status = UserStatusSuspended
+	u.UpdatedAt = time.Now()
+}
+
+// VerifyPhone sets the phone number of the user, confirmed with a code sent to it
+func (u *User) VerifyPhone(number phone.Number) {
+	u.Phone = &number.E164
+	u.PhoneCountry = &number.Country
+	u.PhoneVerified = true
+	u.UpdatedAt = time.Now()
+}
+
+// HasVerifiedPhone tells whether number is the confirmed number of the user
+func (u *User) HasVerifiedPhone(number phone.Number) bool {
+	return u.PhoneVerified && u.Phone != nil && *u.Phone == number.E164
+}
+
+// CanTakeHighRiskAction checks that the user confirmed a phone number, which high-risk actions
+// like payouts require
+func (u *User) CanTakeHighRiskAction() error {
+	if !u.PhoneVerified {
+		return ErrPhoneNotVerified
+	}
+	return nil
 }
 
 // CanLogin checks if the user can login
@@ -124,6 +165,12 @@ func hashPassword(password string) (string, error) {
 	return string(hash), nil
 }
 
+// IsRegistrableUserType checks if accounts of the user type can sign up by themselves,
+// admins are never registered through the public API
+func IsRegistrableUserType(userType UserType) bool {
+	return userType == UserTypeCustomer || userType == UserTypeOrganizer
+}
+
 // IsValidUserType checks if the user type is valid
 func IsValidUserType(userType string) bool {
 	switch UserType(userType) {
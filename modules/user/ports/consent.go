@@ -0,0 +1,106 @@
+package ports
+
+import (
+	"net/http"
+	"strconv"
+
+	"tixgo/components"
+	"tixgo/modules/user/adapters"
+	"tixgo/modules/user/app/command"
+	"tixgo/modules/user/app/query"
+	"tixgo/modules/user/domain"
+	"tixgo/shared/httpresponse"
+	"tixgo/shared/listing"
+
+	"github.com/duongptryu/gox/context"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetLegalDocuments(consentPolicy domain.ConsentPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		httpresponse.Success(c, http.StatusOK, query.ToLegalDocumentsResult(consentPolicy))
+	}
+}
+
+func ListMyConsents(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		listConsents(c, appCtx, userID)
+	}
+}
+
+func ListUserConsents(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		listConsents(c, appCtx, userID)
+	}
+}
+
+// listConsents answers a page of the consent history of a user
+func listConsents(c *gin.Context, appCtx components.AppContext, userID int64) {
+	var paging listing.Paging
+	if err := c.ShouldBind(&paging); err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Apply pagination defaults in HTTP layer
+	paging.Fulfill()
+
+	var filters query.ListConsentsQuery
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.Error(err)
+		return
+	}
+	filters.UserID = userID
+
+	handler := query.NewListConsentsHandler(adapters.NewConsentPostgresRepository(appCtx.GetDB()))
+
+	result, err := handler.Handle(c.Request.Context(), &filters, &paging)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	httpresponse.List(c, result, paging, filters)
+}
+
+func UpdateMarketingConsent(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.UpdateMarketingConsentCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		userID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.UserID = userID
+		req.IPAddress = c.ClientIP()
+		req.UserAgent = c.Request.UserAgent()
+
+		handler := command.NewUpdateMarketingConsentHandler(adapters.NewConsentPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), &req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"tixgo/modules/user/app/command"
 	userEvent "tixgo/modules/user/app/event"
 	"tixgo/modules/user/domain"
+	"tixgo/shared/dedup"
+	sharedActivity "tixgo/shared/events/activity"
 
 	"github.com/ThreeDotsLabs/watermill/components/cqrs"
 	"github.com/duongptryu/gox/messaging"
@@ -16,24 +18,29 @@ import (
 
 const (
 	EventUserRegistered      = "events.EventUserRegistered"
+	EventAccountActivity     = "events.EventAccountActivity"
 	CommandSendOTPVerifyMail = "commands.SendOTPVerifyMail"
 )
 
 type UserMessagingHandlers struct {
 	dispatcher messaging.Dispatcher
 	appCtx     components.AppContext
+	// exposeOTP logs the verification codes, see config.App.ExposeOTP
+	exposeOTP bool
 }
 
-func NewUserMessagingHandlers(dispatcher messaging.Dispatcher, appCtx components.AppContext) *UserMessagingHandlers {
+func NewUserMessagingHandlers(dispatcher messaging.Dispatcher, appCtx components.AppContext, exposeOTP bool) *UserMessagingHandlers {
 	return &UserMessagingHandlers{
 		dispatcher: dispatcher,
 		appCtx:     appCtx,
+		exposeOTP:  exposeOTP,
 	}
 }
 
 func (h *UserMessagingHandlers) RegisterUserMessagingHandlers() {
 	eventProcessor := h.dispatcher.GetEventProcessor()
 	eventProcessor.AddHandler(cqrs.NewEventHandler(EventUserRegistered, h.HandleEventUserRegistered))
+	eventProcessor.AddHandler(cqrs.NewEventHandler(EventAccountActivity, h.HandleEventAccountActivity))
 
 	commandProcessor := h.dispatcher.GetCommandProcessor()
 	commandProcessor.AddHandler(cqrs.NewCommandHandler(CommandSendOTPVerifyMail, h.HandleCommandSendOTPVerifyMail))
@@ -50,11 +57,18 @@ func (h *UserMessagingHandlers) HandleEventUserRegistered(ctx context.Context, e
 	return nil
 }
 
+func (h *UserMessagingHandlers) HandleEventAccountActivity(ctx context.Context, event *sharedActivity.EventAccountActivity) error {
+	biz := userEvent.NewRecordActivity(adapters.NewActivityPostgresRepository(h.appCtx.GetDB()))
+
+	return biz.Record(ctx, event)
+}
+
 func (h *UserMessagingHandlers) HandleCommandSendOTPVerifyMail(ctx context.Context, cmd *command.SendOTPVerifyMailCommand) error {
-	otpStore := adapters.NewInMemoryOTPStore()
-	templateRepo := templateAdapters.NewTemplatePostgresRepository(h.appCtx.GetDB())
-	templateRenderer := templateAdapters.NewHTMLTemplateRenderer()
-	biz := command.NewSendOTPVerifyMailHandler(otpStore, templateRepo, templateRenderer, h.appCtx.GetEventBus())
+	otpStore := adapters.NewRedisOTPStore(h.appCtx.GetRedis())
+	templateRepo := templateAdapters.NewCachedTemplateRepository(templateAdapters.NewTemplatePostgresRepository(h.appCtx.GetDB()), h.appCtx.GetCache())
+	templateRenderer := templateAdapters.NewHTMLTemplateRenderer(h.appCtx.GetAssetService(), nil, templateAdapters.NewVariableDefaultPostgresRepository(h.appCtx.GetDB()))
+	deduplicator := dedup.NewRedisDeduplicator(h.appCtx.GetRedis())
+	biz := command.NewSendOTPVerifyMailHandler(otpStore, templateRepo, templateRenderer, deduplicator, h.appCtx.GetReliableEventBus(), h.exposeOTP)
 
 	err := biz.Handle(ctx, cmd)
 	if err != nil {
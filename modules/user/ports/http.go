@@ -7,27 +7,68 @@ import (
 	"tixgo/modules/user/adapters"
 	"tixgo/modules/user/app/command"
 	"tixgo/modules/user/app/query"
+	"tixgo/modules/user/domain"
+	"tixgo/shared/apiversion"
+	"tixgo/shared/authz"
+	"tixgo/shared/dedup"
+	"tixgo/shared/dryrun"
+	"tixgo/shared/httpresponse"
+	"tixgo/shared/listing"
+	"tixgo/shared/session"
 
 	"github.com/duongptryu/gox/context"
-	"github.com/duongptryu/gox/response"
-	"github.com/duongptryu/gox/server/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterUserRoutes(router *gin.RouterGroup, appCtx components.AppContext) {
+// RegisterUserRoutes serves the user routes. exposeOTP logs the phone verification codes, like
+// config.App.ExposeOTP does for the email ones; emailPolicy normalizes the emails of accounts and
+// screening scores their registrations for spam.
+func RegisterUserRoutes(router *apiversion.Group, appCtx components.AppContext, exposeOTP bool, emailPolicy domain.EmailPolicy, consentPolicy domain.ConsentPolicy, screening RegistrationScreening) {
 	userGroup := router.Group("/users")
 	{
-		userGroup.POST("/register", RegisterUser(appCtx))
-		userGroup.POST("/verify-otp", VerifyOTP(appCtx))
-		userGroup.POST("/login", LoginUser(appCtx))
+		userGroup.GET("/legal", GetLegalDocuments(consentPolicy))
+		userGroup.POST("/register", RegisterUser(appCtx, emailPolicy, consentPolicy, screening))
+		userGroup.GET("/registration-status", GetRegistrationStatus(appCtx, emailPolicy))
+		userGroup.POST("/verify-otp", VerifyOTP(appCtx, emailPolicy))
+		userGroup.POST("/login", LoginUser(appCtx, emailPolicy, consentPolicy))
 
-		userGroup.Use(middleware.RequireAuth(appCtx.GetJWTService()))
+		userGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
 		userGroup.GET("/profile", GetUserProfile(appCtx))
+		userGroup.GET("/me/activity", ListMyActivity(appCtx))
+		userGroup.GET("/me/consents", ListMyConsents(appCtx))
+		userGroup.PUT("/me/consents/marketing", UpdateMarketingConsent(appCtx))
+		userGroup.POST("/verify-phone/request", RequestPhoneVerification(appCtx, exposeOTP))
+		userGroup.POST("/verify-phone/confirm", ConfirmPhoneVerification(appCtx))
+	}
+
+	// The review queue of the registrations the risk policy held back
+	riskGroup := router.Group("/admin/registration-risks")
+	{
+		riskGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		riskGroup.Use(authz.RequireUserType(string(domain.UserTypeAdmin)))
+		riskGroup.GET("", ListRegistrationRisks(appCtx))
+		riskGroup.POST("/:id/approve", ReviewRegistration(appCtx, true))
+		riskGroup.POST("/:id/reject", ReviewRegistration(appCtx, false))
+	}
+
+	adminUserGroup := router.Group("/admin/users/:id")
+	{
+		adminUserGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		adminUserGroup.Use(authz.RequireUserType(string(domain.UserTypeAdmin)))
+		adminUserGroup.GET("/consents", ListUserConsents(appCtx))
 	}
 }
 
-func RegisterUser(appCtx components.AppContext) gin.HandlerFunc {
+// RegisterUserDebugRoutes serves the OTP echo, only registered when config.Debug.EchoOTP is on
+func RegisterUserDebugRoutes(router *apiversion.Group, appCtx components.AppContext, emailPolicy domain.EmailPolicy) {
+	debugGroup := router.Group("/debug")
+	{
+		debugGroup.GET("/otp", GetPendingOTP(appCtx, emailPolicy))
+	}
+}
+
+func RegisterUser(appCtx components.AppContext, emailPolicy domain.EmailPolicy, consentPolicy domain.ConsentPolicy, screening RegistrationScreening) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req command.RegisterUserCommand
 		if err := c.ShouldBindJSON(&req); err != nil {
@@ -35,11 +76,16 @@ func RegisterUser(appCtx components.AppContext) gin.HandlerFunc {
 			return
 		}
 
+		req.IPAddress = c.ClientIP()
+		req.DeviceID = deviceID(c)
+		req.UserAgent = c.Request.UserAgent()
+
 		userRepo := adapters.NewUserPostgresRepository(appCtx.GetDB())
-		tempUserStore := adapters.NewInMemoryTempUserStore()
-		otpStore := adapters.NewInMemoryOTPStore()
+		tempUserStore := adapters.NewRedisTempUserStore(appCtx.GetRedis())
+		otpStore := adapters.NewRedisOTPStore(appCtx.GetRedis())
+		deduplicator := dedup.NewRedisDeduplicator(appCtx.GetRedis())
 
-		biz := command.NewRegisterUserHandler(userRepo, tempUserStore, otpStore, appCtx.GetEventBus())
+		biz := command.NewRegisterUserHandler(userRepo, tempUserStore, otpStore, deduplicator, appCtx.GetReliableEventBus(), emailPolicy, consentPolicy, screening.screen(appCtx))
 
 		result, err := biz.Handle(c.Request.Context(), &req)
 		if err != nil {
@@ -47,11 +93,39 @@ func RegisterUser(appCtx components.AppContext) gin.HandlerFunc {
 			return
 		}
 
-		c.JSON(http.StatusCreated, response.NewSimpleSuccessResponse(result))
+		if dryrun.IsDryRun(c.Request.Context()) {
+			httpresponse.Success(c, http.StatusOK, result)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusCreated, result)
 	}
 }
 
-func VerifyOTP(appCtx components.AppContext) gin.HandlerFunc {
+func GetRegistrationStatus(appCtx components.AppContext, emailPolicy domain.EmailPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req query.GetRegistrationStatusQuery
+		if err := c.ShouldBind(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		userRepo := adapters.NewCachedUserRepository(adapters.NewUserPostgresRepository(appCtx.GetDB()), appCtx.GetCache())
+		tempUserStore := adapters.NewRedisTempUserStore(appCtx.GetRedis())
+
+		biz := query.NewGetRegistrationStatusHandler(userRepo, tempUserStore, emailPolicy)
+
+		result, err := biz.Handle(c.Request.Context(), &req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func VerifyOTP(appCtx components.AppContext, emailPolicy domain.EmailPolicy) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req command.VerifyOTPCommand
 		if err := c.ShouldBindJSON(&req); err != nil {
@@ -59,11 +133,13 @@ func VerifyOTP(appCtx components.AppContext) gin.HandlerFunc {
 			return
 		}
 
-		userRepo := adapters.NewUserPostgresRepository(appCtx.GetDB())
-		tempUserStore := adapters.NewInMemoryTempUserStore()
-		otpStore := adapters.NewInMemoryOTPStore()
+		userRepo := adapters.NewCachedUserRepository(adapters.NewUserPostgresRepository(appCtx.GetDB()), appCtx.GetCache())
+		tempUserStore := adapters.NewRedisTempUserStore(appCtx.GetRedis())
+		otpStore := adapters.NewRedisOTPStore(appCtx.GetRedis())
 
-		biz := command.NewVerifyOTPHandler(userRepo, tempUserStore, otpStore)
+		consentRepo := adapters.NewConsentPostgresRepository(appCtx.GetDB())
+
+		biz := command.NewVerifyOTPHandler(userRepo, tempUserStore, otpStore, consentRepo, emailPolicy)
 
 		result, err := biz.Handle(c.Request.Context(), &req)
 		if err != nil {
@@ -71,11 +147,11 @@ func VerifyOTP(appCtx components.AppContext) gin.HandlerFunc {
 			return
 		}
 
-		c.JSON(http.StatusOK, response.NewSimpleSuccessResponse(result))
+		httpresponse.Success(c, http.StatusOK, result)
 	}
 }
 
-func LoginUser(appCtx components.AppContext) gin.HandlerFunc {
+func LoginUser(appCtx components.AppContext, emailPolicy domain.EmailPolicy, consentPolicy domain.ConsentPolicy) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req command.LoginUserCommand
 		if err := c.ShouldBindJSON(&req); err != nil {
@@ -83,9 +159,15 @@ func LoginUser(appCtx components.AppContext) gin.HandlerFunc {
 			return
 		}
 
-		userRepo := adapters.NewUserPostgresRepository(appCtx.GetDB())
+		userRepo := adapters.NewCachedUserRepository(adapters.NewUserPostgresRepository(appCtx.GetDB()), appCtx.GetCache())
 
-		biz := command.NewLoginUserHandler(userRepo, appCtx.GetJWTService())
+		req.IPAddress = c.ClientIP()
+		req.UserAgent = c.Request.UserAgent()
+
+		loginEventRepo := adapters.NewLoginEventPostgresRepository(appCtx.GetDB())
+		consentRepo := adapters.NewConsentPostgresRepository(appCtx.GetDB())
+
+		biz := command.NewLoginUserHandler(userRepo, loginEventRepo, consentRepo, appCtx.GetSessionService(), appCtx.GetReliableEventBus(), emailPolicy, consentPolicy)
 
 		result, err := biz.Handle(c.Request.Context(), &req)
 		if err != nil {
@@ -93,7 +175,117 @@ func LoginUser(appCtx components.AppContext) gin.HandlerFunc {
 			return
 		}
 
-		c.JSON(http.StatusOK, response.NewSimpleSuccessResponse(result))
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func GetPendingOTP(appCtx components.AppContext, emailPolicy domain.EmailPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req query.GetPendingOTPQuery
+		if err := c.ShouldBind(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		otpStore := adapters.NewRedisOTPStore(appCtx.GetRedis())
+
+		biz := query.NewGetPendingOTPHandler(otpStore, emailPolicy)
+
+		result, err := biz.Handle(c.Request.Context(), &req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.Header("Cache-Control", "no-store")
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func RequestPhoneVerification(appCtx components.AppContext, exposeOTP bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		var req command.RequestPhoneVerificationCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+		req.UserID = userID
+
+		userRepo := adapters.NewCachedUserRepository(adapters.NewUserPostgresRepository(appCtx.GetDB()), appCtx.GetCache())
+		otpStore := adapters.NewRedisPhoneOTPStore(appCtx.GetRedis())
+		deduplicator := dedup.NewRedisDeduplicator(appCtx.GetRedis())
+
+		biz := command.NewRequestPhoneVerificationHandler(userRepo, otpStore, deduplicator, appCtx.GetReliableEventBus(), exposeOTP)
+
+		result, err := biz.Handle(c.Request.Context(), &req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func ConfirmPhoneVerification(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		var req command.ConfirmPhoneVerificationCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+		req.UserID = userID
+
+		userRepo := adapters.NewCachedUserRepository(adapters.NewUserPostgresRepository(appCtx.GetDB()), appCtx.GetCache())
+		otpStore := adapters.NewRedisPhoneOTPStore(appCtx.GetRedis())
+
+		biz := command.NewConfirmPhoneVerificationHandler(userRepo, otpStore)
+
+		result, err := biz.Handle(c.Request.Context(), &req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+// RequireVerifiedPhone guards high-risk routes, like payouts, behind a confirmed phone number. It must
+// come after session.RequireAuth.
+func RequireVerifiedPhone(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		userRepo := adapters.NewCachedUserRepository(adapters.NewUserPostgresRepository(appCtx.GetDB()), appCtx.GetCache())
+		user, err := userRepo.GetByID(c.Request.Context(), userID)
+		if err == nil {
+			err = user.CanTakeHighRiskAction()
+		}
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		c.Next()
 	}
 }
 
@@ -105,8 +297,8 @@ func GetUserProfile(appCtx components.AppContext) gin.HandlerFunc {
 			return
 		}
 
-		userRepo := adapters.NewUserPostgresRepository(appCtx.GetDB())
-		biz := query.NewGetUserProfileHandler(userRepo)
+		userRepo := adapters.NewCachedUserRepository(adapters.NewUserPostgresRepository(appCtx.GetDB()), appCtx.GetCache())
+		biz := query.NewGetUserProfileHandler(userRepo, adapters.NewLoginEventPostgresRepository(appCtx.GetDB()))
 
 		result, err := biz.Handle(c.Request.Context(), &query.GetUserProfileQuery{
 			UserID: userIDInt64,
@@ -116,6 +308,36 @@ func GetUserProfile(appCtx components.AppContext) gin.HandlerFunc {
 			return
 		}
 
-		c.JSON(http.StatusOK, response.NewSimpleSuccessResponse(result))
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func ListMyActivity(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		var paging listing.Paging
+		if err := c.ShouldBind(&paging); err != nil {
+			c.Error(err)
+			return
+		}
+
+		// Apply pagination defaults in HTTP layer
+		paging.Fulfill()
+
+		filters := query.ListActivitiesQuery{UserID: userID}
+		handler := query.NewListActivitiesHandler(adapters.NewActivityPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), &filters, &paging)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.List(c, result, paging, filters)
 	}
 }
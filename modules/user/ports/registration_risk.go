@@ -0,0 +1,114 @@
+package ports
+
+import (
+	"net/http"
+	"strconv"
+
+	"tixgo/components"
+	"tixgo/modules/user/adapters"
+	"tixgo/modules/user/app/command"
+	"tixgo/modules/user/app/query"
+	"tixgo/modules/user/domain"
+	"tixgo/shared/httpresponse"
+	"tixgo/shared/listing"
+
+	"github.com/duongptryu/gox/context"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeviceIDHeader carries the identifier clients keep for the device, which registrations are scored on
+const DeviceIDHeader = "X-Device-ID"
+
+// maxDeviceIDLength bounds the device identifiers recorded, longer ones are ignored
+const maxDeviceIDLength = 255
+
+// RegistrationScreening configures the spam scoring of registrations
+type RegistrationScreening struct {
+	Policy domain.RiskPolicy
+	// Captcha is nil when no CAPTCHA provider is configured
+	Captcha domain.CaptchaVerifier
+}
+
+func (s RegistrationScreening) screen(appCtx components.AppContext) *command.RegistrationScreen {
+	return command.NewRegistrationScreen(
+		s.Policy,
+		adapters.NewRedisRegistrationVelocity(appCtx.GetRedis()),
+		adapters.NewRegistrationRiskPostgresRepository(appCtx.GetDB()),
+		s.Captcha,
+	)
+}
+
+// deviceID returns the device identifier of the request, empty when it has none or a bogus one
+func deviceID(c *gin.Context) string {
+	id := c.GetHeader(DeviceIDHeader)
+	if len(id) > maxDeviceIDLength {
+		return ""
+	}
+	return id
+}
+
+func ListRegistrationRisks(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var filters query.FilterRegistrationRisksQuery
+		if err := c.ShouldBind(&filters); err != nil {
+			c.Error(err)
+			return
+		}
+
+		var paging listing.Paging
+		if err := c.ShouldBind(&paging); err != nil {
+			c.Error(err)
+			return
+		}
+
+		// Apply pagination defaults in HTTP layer
+		paging.Fulfill()
+
+		handler := query.NewListRegistrationRisksHandler(adapters.NewRegistrationRiskPostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), &filters, &paging)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.List(c, result, paging, filters)
+	}
+}
+
+func ReviewRegistration(appCtx components.AppContext, approve bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.ReviewRegistrationCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.ID = id
+		req.Approve = approve
+
+		reviewerID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.ReviewerID = reviewerID
+
+		userRepo := adapters.NewCachedUserRepository(adapters.NewUserPostgresRepository(appCtx.GetDB()), appCtx.GetCache())
+		handler := command.NewReviewRegistrationHandler(adapters.NewRegistrationRiskPostgresRepository(appCtx.GetDB()), userRepo)
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
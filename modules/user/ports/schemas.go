@@ -0,0 +1,35 @@
+package ports
+
+import (
+	"tixgo/modules/user/app/command"
+	"tixgo/modules/user/app/query"
+	"tixgo/shared/jsonschema"
+	"tixgo/shared/listing"
+)
+
+// Schemas returns the request payloads of the user module
+func Schemas() []jsonschema.Payload {
+	return []jsonschema.Payload{
+		{Name: "users.register", In: jsonschema.Body, Example: command.RegisterUserCommand{}},
+		{Name: "users.registration-status", In: jsonschema.Query, Example: query.GetRegistrationStatusQuery{}},
+		{Name: "users.verify-otp", In: jsonschema.Body, Example: command.VerifyOTPCommand{}},
+		{Name: "users.login", In: jsonschema.Body, Example: command.LoginUserCommand{}},
+		{Name: "users.me.activity", In: jsonschema.Query, Example: listing.Paging{}},
+		{Name: "users.me.consents", In: jsonschema.Query, Example: struct {
+			query.ListConsentsQuery
+			listing.Paging
+		}{}},
+		{Name: "users.me.consents.marketing", In: jsonschema.Body, Example: command.UpdateMarketingConsentCommand{}},
+		{Name: "users.verify-phone.request", In: jsonschema.Body, Example: command.RequestPhoneVerificationCommand{}},
+		{Name: "users.verify-phone.confirm", In: jsonschema.Body, Example: command.ConfirmPhoneVerificationCommand{}},
+		{Name: "admin.registration-risks.list", In: jsonschema.Query, Example: struct {
+			query.FilterRegistrationRisksQuery
+			listing.Paging
+		}{}},
+		{Name: "admin.registration-risks.review", In: jsonschema.Body, Example: command.ReviewRegistrationCommand{}},
+		{Name: "admin.users.consents", In: jsonschema.Query, Example: struct {
+			query.ListConsentsQuery
+			listing.Paging
+		}{}},
+	}
+}
@@ -0,0 +1,340 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"tixgo/modules/venue/domain"
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/listing"
+	"tixgo/shared/pgerr"
+	"tixgo/shared/pgquery"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const venueColumns = `id, organizer_id, name, COALESCE(description, ''), address, city, COALESCE(state, ''), country,
+	capacity, venue_type, latitude::FLOAT8, longitude::FLOAT8, COALESCE(contact_email, ''), COALESCE(contact_phone, ''),
+	(SELECT COUNT(*) FROM venue_seats s WHERE s.venue_id = venues.id),
+	COALESCE(created_at, NOW()), COALESCE(updated_at, created_at, NOW())`
+
+// VenuePostgresRepository implements the VenueRepository interface using PostgreSQL
+type VenuePostgresRepository struct {
+	db *sqlx.DB
+}
+
+// NewVenuePostgresRepository creates a new PostgreSQL venue repository
+func NewVenuePostgresRepository(db *sqlx.DB) *VenuePostgresRepository {
+	return &VenuePostgresRepository{db: db}
+}
+
+// Create stores a new venue
+func (r *VenuePostgresRepository) Create(ctx context.Context, venue *domain.Venue) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO venues (organizer_id, name, description, address, city, state, country, capacity, venue_type,
+		                    latitude, longitude, contact_email, contact_phone)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, NULLIF($6, ''), $7, $8, $9, $10, $11, NULLIF($12, ''), NULLIF($13, ''))
+		RETURNING id, created_at, updated_at`,
+		venue.OrganizerID,
+		venue.Name,
+		venue.Description,
+		venue.Address,
+		venue.City,
+		venue.State,
+		venue.Country,
+		venue.Capacity,
+		venue.VenueType,
+		venue.Latitude,
+		venue.Longitude,
+		venue.ContactEmail,
+		venue.ContactPhone,
+	).Scan(&venue.ID, &venue.CreatedAt, &venue.UpdatedAt)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to create venue")
+	}
+
+	return nil
+}
+
+// GetByID retrieves a venue of the organizer or a shared one
+func (r *VenuePostgresRepository) GetByID(ctx context.Context, id, organizerID int64) (*domain.Venue, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return scanVenue(r.db.QueryRowContext(ctx, `
+		SELECT `+venueColumns+`
+		FROM venues
+		WHERE id = $1 AND (organizer_id = $2 OR organizer_id IS NULL)`, id, organizerID))
+}
+
+// List retrieves a page of the venues of the organizer and the shared ones, by name
+func (r *VenuePostgresRepository) List(ctx context.Context, organizerID int64, paging *listing.Paging) ([]*domain.Venue, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	filter := &pgquery.Filter{}
+	filter.Where("(organizer_id = ? OR organizer_id IS NULL)", organizerID)
+
+	if err := pgquery.Count(ctx, r.db, "venues", filter, paging); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to count venues")
+	}
+
+	pageClause, args := filter.Paged(paging)
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT %s
+		FROM venues
+		%s
+		ORDER BY name, id
+		%s`, venueColumns, filter.Clause(), pageClause), args...)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list venues")
+	}
+	defer rows.Close()
+
+	var venues []*domain.Venue
+	for rows.Next() {
+		venue, err := scanVenue(rows)
+		if err != nil {
+			return nil, err
+		}
+		venues = append(venues, venue)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to iterate venues")
+	}
+
+	return venues[:paging.Fetched(len(venues))], nil
+}
+
+// Update saves the details of a venue of its organizer. The capacity guard keeps the update from racing
+// a seat map replacement.
+func (r *VenuePostgresRepository) Update(ctx context.Context, venue *domain.Venue) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE venues
+		SET name = $3, description = NULLIF($4, ''), address = $5, city = $6, state = NULLIF($7, ''), country = $8,
+		    capacity = $9, venue_type = $10, latitude = $11, longitude = $12, contact_email = NULLIF($13, ''),
+		    contact_phone = NULLIF($14, ''), updated_at = NOW()
+		WHERE id = $1 AND organizer_id = $2 AND $9 >= (SELECT COUNT(*) FROM venue_seats WHERE venue_id = $1)
+		RETURNING updated_at`,
+		venue.ID,
+		venue.OrganizerID,
+		venue.Name,
+		venue.Description,
+		venue.Address,
+		venue.City,
+		venue.State,
+		venue.Country,
+		venue.Capacity,
+		venue.VenueType,
+		venue.Latitude,
+		venue.Longitude,
+		venue.ContactEmail,
+		venue.ContactPhone,
+	).Scan(&venue.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrSeatMapExceedsCapacity
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to update venue")
+	}
+
+	return nil
+}
+
+// Delete deletes a venue of its organizer with its seat map. The events at the venue reference it, so a
+// venue in use fails to be deleted.
+func (r *VenuePostgresRepository) Delete(ctx context.Context, venue *domain.Venue) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM venues WHERE id = $1 AND organizer_id = $2`, venue.ID, venue.OrganizerID)
+	if err != nil {
+		if pgerr.IsForeignKeyViolation(err) {
+			return domain.ErrVenueInUse
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to delete venue")
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to get rows affected")
+	}
+	if deleted == 0 {
+		return domain.ErrVenueNotFound
+	}
+
+	return nil
+}
+
+// GetSeatMap retrieves the seat map of a venue, in the order it is laid out
+func (r *VenuePostgresRepository) GetSeatMap(ctx context.Context, venueID int64) (domain.SeatMap, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return getSeatMap(ctx, r.db, venueID, "")
+}
+
+// ReplaceSeatMap replaces the seat map of a venue. The venue and its seats are locked, so a replacement
+// does not race another one, a capacity change or the binding of its seats to tickets. The cancelled
+// tickets a removed seat was bound to let go of it.
+func (r *VenuePostgresRepository) ReplaceSeatMap(ctx context.Context, venue *domain.Venue, seatMap domain.SeatMap) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	var capacity int
+	err = tx.QueryRowContext(ctx, `SELECT capacity FROM venues WHERE id = $1 AND organizer_id = $2 FOR UPDATE`,
+		venue.ID, venue.OrganizerID).Scan(&capacity)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrVenueNotFound
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to lock venue")
+	}
+	if len(seatMap) > capacity {
+		return domain.ErrSeatMapExceedsCapacity
+	}
+
+	current, err := getSeatMap(ctx, tx, venue.ID, "FOR UPDATE")
+	if err != nil {
+		return err
+	}
+
+	removed := seatMap.Replace(current)
+	if len(removed) > 0 {
+		var bound bool
+		err = tx.QueryRowContext(ctx, `
+			SELECT EXISTS (SELECT 1 FROM tickets WHERE venue_seat_id = ANY($1) AND status <> 'cancelled')`,
+			pq.Array(removed)).Scan(&bound)
+		if err != nil {
+			return syserr.Wrap(err, syserr.InternalCode, "failed to check bound seats")
+		}
+		if bound {
+			return domain.ErrSeatBound
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM venue_seats WHERE id = ANY($1)`, pq.Array(removed)); err != nil {
+			return syserr.Wrap(err, syserr.InternalCode, "failed to remove seats")
+		}
+	}
+
+	sections := make([]string, len(seatMap))
+	seatRows := make([]string, len(seatMap))
+	numbers := make([]string, len(seatMap))
+	for i, seat := range seatMap {
+		sections[i] = seat.Section
+		seatRows[i] = seat.Row
+		numbers[i] = seat.Number
+	}
+
+	// the seats kept only move, the new ones get their IDs
+	rows, err := tx.QueryContext(ctx, `
+		INSERT INTO venue_seats (venue_id, section, row_label, seat_number, position)
+		SELECT $1, s.section, s.row_label, s.seat_number, s.position
+		FROM unnest($2::text[], $3::text[], $4::text[]) WITH ORDINALITY AS s(section, row_label, seat_number, position)
+		ON CONFLICT ON CONSTRAINT venue_seats_seat_key DO UPDATE SET position = EXCLUDED.position
+		RETURNING id, section, row_label, seat_number`,
+		venue.ID, pq.Array(sections), pq.Array(seatRows), pq.Array(numbers))
+	if err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to save seats")
+	}
+	saved, err := collectSeats(rows)
+	if err != nil {
+		return err
+	}
+	seatMap.Replace(saved)
+
+	if _, err := tx.ExecContext(ctx, `UPDATE venues SET updated_at = NOW() WHERE id = $1`, venue.ID); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to update venue")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return syserr.Wrap(err, syserr.InternalCode, "failed to commit seat map")
+	}
+
+	venue.Seats = len(seatMap)
+	return nil
+}
+
+// getSeatMap retrieves the seat map of a venue, locking its seats when lock is FOR UPDATE
+func getSeatMap(ctx context.Context, db sqlx.QueryerContext, venueID int64, lock string) (domain.SeatMap, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, section, row_label, seat_number
+		FROM venue_seats
+		WHERE venue_id = $1
+		ORDER BY position, id `+lock, venueID)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get seat map")
+	}
+
+	return collectSeats(rows)
+}
+
+func collectSeats(rows *sql.Rows) (domain.SeatMap, error) {
+	defer rows.Close()
+
+	seatMap := domain.SeatMap{}
+	for rows.Next() {
+		var seat domain.Seat
+		if err := rows.Scan(&seat.ID, &seat.Section, &seat.Row, &seat.Number); err != nil {
+			return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan seat")
+		}
+		seatMap = append(seatMap, seat)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to iterate seats")
+	}
+
+	return seatMap, nil
+}
+
+// rowScanner is a single row or the current row of rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanVenue(row rowScanner) (*domain.Venue, error) {
+	venue := &domain.Venue{}
+	err := row.Scan(
+		&venue.ID,
+		&venue.OrganizerID,
+		&venue.Name,
+		&venue.Description,
+		&venue.Address,
+		&venue.City,
+		&venue.State,
+		&venue.Country,
+		&venue.Capacity,
+		&venue.VenueType,
+		&venue.Latitude,
+		&venue.Longitude,
+		&venue.ContactEmail,
+		&venue.ContactPhone,
+		&venue.Seats,
+		&venue.CreatedAt,
+		&venue.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrVenueNotFound
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to scan venue")
+	}
+	return venue, nil
+}
@@ -0,0 +1,75 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/venue/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// VenueDetailsInput are the details of a venue sent to create and update it
+type VenueDetailsInput struct {
+	Name         string   `json:"name" binding:"required,max=255"`
+	Description  string   `json:"description" binding:"max=2000"`
+	Address      string   `json:"address" binding:"required,max=255"`
+	City         string   `json:"city" binding:"required,max=100"`
+	State        string   `json:"state" binding:"max=100"`
+	Country      string   `json:"country" binding:"required,max=100"`
+	Capacity     int      `json:"capacity" binding:"required,min=1"`
+	VenueType    string   `json:"venue_type" binding:"required"`
+	Latitude     *float64 `json:"latitude"`
+	Longitude    *float64 `json:"longitude"`
+	ContactEmail string   `json:"contact_email" binding:"omitempty,email,max=255"`
+	ContactPhone string   `json:"contact_phone" binding:"max=20"`
+}
+
+// Details converts the input to the details of a domain venue
+func (in VenueDetailsInput) Details() domain.VenueDetails {
+	return domain.VenueDetails{
+		Name:         in.Name,
+		Description:  in.Description,
+		Address:      in.Address,
+		City:         in.City,
+		State:        in.State,
+		Country:      in.Country,
+		Capacity:     in.Capacity,
+		VenueType:    domain.VenueType(in.VenueType),
+		Latitude:     in.Latitude,
+		Longitude:    in.Longitude,
+		ContactEmail: in.ContactEmail,
+		ContactPhone: in.ContactPhone,
+	}
+}
+
+// CreateVenueCommand represents the command of an organizer to create a venue
+type CreateVenueCommand struct {
+	OrganizerID int64 `json:"-"`
+	VenueDetailsInput
+}
+
+// CreateVenueHandler handles venue creation
+type CreateVenueHandler struct {
+	venueRepo domain.VenueRepository
+}
+
+// NewCreateVenueHandler creates a new create venue handler
+func NewCreateVenueHandler(venueRepo domain.VenueRepository) *CreateVenueHandler {
+	return &CreateVenueHandler{
+		venueRepo: venueRepo,
+	}
+}
+
+// Handle executes the create venue command. The venue starts without a seat map.
+func (h *CreateVenueHandler) Handle(ctx context.Context, cmd CreateVenueCommand) (*VenueResult, error) {
+	venue, err := domain.NewVenue(cmd.OrganizerID, cmd.Details())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.venueRepo.Create(ctx, venue); err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to create venue")
+	}
+
+	return ToVenueResult(venue), nil
+}
@@ -0,0 +1,44 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/venue/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// DeleteVenueCommand represents the command of an organizer to delete their venue
+type DeleteVenueCommand struct {
+	ID          int64
+	OrganizerID int64
+}
+
+// DeleteVenueHandler handles venue deletion
+type DeleteVenueHandler struct {
+	venueRepo domain.VenueRepository
+}
+
+// NewDeleteVenueHandler creates a new delete venue handler
+func NewDeleteVenueHandler(venueRepo domain.VenueRepository) *DeleteVenueHandler {
+	return &DeleteVenueHandler{
+		venueRepo: venueRepo,
+	}
+}
+
+// Handle executes the delete venue command. Venues events take place at stay for them.
+func (h *DeleteVenueHandler) Handle(ctx context.Context, cmd DeleteVenueCommand) error {
+	venue, err := getEditableVenue(ctx, h.venueRepo, cmd.ID, cmd.OrganizerID)
+	if err != nil {
+		return err
+	}
+
+	if err := h.venueRepo.Delete(ctx, venue); err != nil {
+		if err == domain.ErrVenueNotFound || err == domain.ErrVenueInUse {
+			return err
+		}
+		return syserr.Wrap(err, syserr.InternalCode, "failed to delete venue")
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/venue/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// SeatMapInput is the seat map of a venue sent to replace it, its seats by section and row
+type SeatMapInput struct {
+	Sections []SectionInput `json:"sections" binding:"dive"`
+}
+
+// SectionInput is a section of a seat map with its rows
+type SectionInput struct {
+	Name string     `json:"name" binding:"required,max=50"`
+	Rows []RowInput `json:"rows" binding:"required,dive"`
+}
+
+// RowInput is a row of a section with the numbers of its seats, in the order they are laid out
+type RowInput struct {
+	Label string   `json:"label" binding:"required,max=10"`
+	Seats []string `json:"seats" binding:"required,dive,required,max=10"`
+}
+
+// Seats flattens the seat map into its seats, section by section and row by row
+func (in SeatMapInput) Seats() []domain.Seat {
+	var seats []domain.Seat
+	for _, section := range in.Sections {
+		for _, row := range section.Rows {
+			for _, number := range row.Seats {
+				seats = append(seats, domain.Seat{Section: section.Name, Row: row.Label, Number: number})
+			}
+		}
+	}
+	return seats
+}
+
+// ReplaceSeatMapCommand represents the command of an organizer to replace the seat map of their venue
+type ReplaceSeatMapCommand struct {
+	VenueID     int64 `json:"-"`
+	OrganizerID int64 `json:"-"`
+	SeatMapInput
+}
+
+// ReplaceSeatMapHandler handles seat map replacements
+type ReplaceSeatMapHandler struct {
+	venueRepo domain.VenueRepository
+}
+
+// NewReplaceSeatMapHandler creates a new replace seat map handler
+func NewReplaceSeatMapHandler(venueRepo domain.VenueRepository) *ReplaceSeatMapHandler {
+	return &ReplaceSeatMapHandler{
+		venueRepo: venueRepo,
+	}
+}
+
+// Handle executes the replace seat map command. The seats kept keep their IDs and the tickets bound to
+// them; seats bound to tickets cannot be removed.
+func (h *ReplaceSeatMapHandler) Handle(ctx context.Context, cmd ReplaceSeatMapCommand) (*SeatMapResult, error) {
+	venue, err := getEditableVenue(ctx, h.venueRepo, cmd.VenueID, cmd.OrganizerID)
+	if err != nil {
+		return nil, err
+	}
+
+	seatMap, err := domain.NewSeatMap(cmd.Seats(), venue.Capacity)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.venueRepo.ReplaceSeatMap(ctx, venue, seatMap); err != nil {
+		switch err {
+		case domain.ErrVenueNotFound, domain.ErrSeatMapExceedsCapacity, domain.ErrSeatBound:
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to replace seat map")
+	}
+
+	return ToSeatMapResult(venue.ID, seatMap), nil
+}
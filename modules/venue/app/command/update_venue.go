@@ -0,0 +1,66 @@
+package command
+
+import (
+	"context"
+
+	"tixgo/modules/venue/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// UpdateVenueCommand represents the command of an organizer to change the details of their venue
+type UpdateVenueCommand struct {
+	ID          int64 `json:"-"`
+	OrganizerID int64 `json:"-"`
+	VenueDetailsInput
+}
+
+// UpdateVenueHandler handles venue updates
+type UpdateVenueHandler struct {
+	venueRepo domain.VenueRepository
+}
+
+// NewUpdateVenueHandler creates a new update venue handler
+func NewUpdateVenueHandler(venueRepo domain.VenueRepository) *UpdateVenueHandler {
+	return &UpdateVenueHandler{
+		venueRepo: venueRepo,
+	}
+}
+
+// Handle executes the update venue command. The capacity cannot go below the seats of the seat map.
+func (h *UpdateVenueHandler) Handle(ctx context.Context, cmd UpdateVenueCommand) (*VenueResult, error) {
+	venue, err := getEditableVenue(ctx, h.venueRepo, cmd.ID, cmd.OrganizerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := venue.Update(cmd.Details()); err != nil {
+		return nil, err
+	}
+
+	if err := h.venueRepo.Update(ctx, venue); err != nil {
+		if err == domain.ErrSeatMapExceedsCapacity {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to update venue")
+	}
+
+	return ToVenueResult(venue), nil
+}
+
+// getEditableVenue retrieves a venue the organizer owns
+func getEditableVenue(ctx context.Context, venueRepo domain.VenueRepository, id, organizerID int64) (*domain.Venue, error) {
+	venue, err := venueRepo.GetByID(ctx, id, organizerID)
+	if err != nil {
+		if err == domain.ErrVenueNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get venue")
+	}
+
+	if err := venue.CheckEditable(organizerID); err != nil {
+		return nil, err
+	}
+
+	return venue, nil
+}
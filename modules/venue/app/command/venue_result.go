@@ -0,0 +1,103 @@
+package command
+
+import (
+	"tixgo/modules/venue/domain"
+)
+
+// VenueResult represents a venue with the number of seats of its seat map
+type VenueResult struct {
+	ID           int64            `json:"id"`
+	OrganizerID  *int64           `json:"organizer_id"`
+	Shared       bool             `json:"shared"`
+	Name         string           `json:"name"`
+	Description  string           `json:"description"`
+	Address      string           `json:"address"`
+	City         string           `json:"city"`
+	State        string           `json:"state"`
+	Country      string           `json:"country"`
+	Capacity     int              `json:"capacity"`
+	VenueType    domain.VenueType `json:"venue_type"`
+	Latitude     *float64         `json:"latitude"`
+	Longitude    *float64         `json:"longitude"`
+	ContactEmail string           `json:"contact_email"`
+	ContactPhone string           `json:"contact_phone"`
+	Seats        int              `json:"seats"`
+	CreatedAt    string           `json:"created_at"`
+	UpdatedAt    string           `json:"updated_at"`
+}
+
+// ToVenueResult converts a venue to its result
+func ToVenueResult(venue *domain.Venue) *VenueResult {
+	return &VenueResult{
+		ID:           venue.ID,
+		OrganizerID:  venue.OrganizerID,
+		Shared:       venue.OrganizerID == nil,
+		Name:         venue.Name,
+		Description:  venue.Description,
+		Address:      venue.Address,
+		City:         venue.City,
+		State:        venue.State,
+		Country:      venue.Country,
+		Capacity:     venue.Capacity,
+		VenueType:    venue.VenueType,
+		Latitude:     venue.Latitude,
+		Longitude:    venue.Longitude,
+		ContactEmail: venue.ContactEmail,
+		ContactPhone: venue.ContactPhone,
+		Seats:        venue.Seats,
+		CreatedAt:    venue.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:    venue.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+// SeatMapResult represents the seat map of a venue, its seats by section and row
+type SeatMapResult struct {
+	VenueID  int64           `json:"venue_id"`
+	Seats    int             `json:"seats"`
+	Sections []SectionResult `json:"sections"`
+}
+
+// SectionResult represents a section of a seat map with its rows
+type SectionResult struct {
+	Name string      `json:"name"`
+	Rows []RowResult `json:"rows"`
+}
+
+// RowResult represents a row of a section with its seats, in the order they are laid out
+type RowResult struct {
+	Label string       `json:"label"`
+	Seats []SeatResult `json:"seats"`
+}
+
+// SeatResult represents a seat of a row, its ID is what ticket types bind seats with
+type SeatResult struct {
+	ID     int64  `json:"id"`
+	Number string `json:"number"`
+}
+
+// ToSeatMapResult groups the seats of a seat map by section and row, in the order they first appear
+func ToSeatMapResult(venueID int64, seatMap domain.SeatMap) *SeatMapResult {
+	result := &SeatMapResult{VenueID: venueID, Seats: len(seatMap), Sections: []SectionResult{}}
+
+	sections := make(map[string]int)
+	rows := make(map[[2]string]int)
+	for _, seat := range seatMap {
+		s, ok := sections[seat.Section]
+		if !ok {
+			s = len(result.Sections)
+			sections[seat.Section] = s
+			result.Sections = append(result.Sections, SectionResult{Name: seat.Section})
+		}
+		section := &result.Sections[s]
+
+		r, ok := rows[[2]string{seat.Section, seat.Row}]
+		if !ok {
+			r = len(section.Rows)
+			rows[[2]string{seat.Section, seat.Row}] = r
+			section.Rows = append(section.Rows, RowResult{Label: seat.Row})
+		}
+		section.Rows[r].Seats = append(section.Rows[r].Seats, SeatResult{ID: seat.ID, Number: seat.Number})
+	}
+
+	return result
+}
@@ -0,0 +1,46 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/venue/app/command"
+	"tixgo/modules/venue/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// GetSeatMapQuery represents the query of an organizer for the seat map of a venue they can use
+type GetSeatMapQuery struct {
+	VenueID     int64
+	OrganizerID int64
+}
+
+// GetSeatMapHandler handles getting the seat map of a venue
+type GetSeatMapHandler struct {
+	venueRepo domain.VenueRepository
+}
+
+// NewGetSeatMapHandler creates a new get seat map handler
+func NewGetSeatMapHandler(venueRepo domain.VenueRepository) *GetSeatMapHandler {
+	return &GetSeatMapHandler{
+		venueRepo: venueRepo,
+	}
+}
+
+// Handle executes the get seat map query
+func (h *GetSeatMapHandler) Handle(ctx context.Context, query GetSeatMapQuery) (*command.SeatMapResult, error) {
+	venue, err := h.venueRepo.GetByID(ctx, query.VenueID, query.OrganizerID)
+	if err != nil {
+		if err == domain.ErrVenueNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get venue")
+	}
+
+	seatMap, err := h.venueRepo.GetSeatMap(ctx, venue.ID)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get seat map")
+	}
+
+	return command.ToSeatMapResult(venue.ID, seatMap), nil
+}
@@ -0,0 +1,41 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/venue/app/command"
+	"tixgo/modules/venue/domain"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// GetVenueQuery represents the query of an organizer for one of their venues or a shared one
+type GetVenueQuery struct {
+	ID          int64
+	OrganizerID int64
+}
+
+// GetVenueHandler handles getting a venue
+type GetVenueHandler struct {
+	venueRepo domain.VenueRepository
+}
+
+// NewGetVenueHandler creates a new get venue handler
+func NewGetVenueHandler(venueRepo domain.VenueRepository) *GetVenueHandler {
+	return &GetVenueHandler{
+		venueRepo: venueRepo,
+	}
+}
+
+// Handle executes the get venue query
+func (h *GetVenueHandler) Handle(ctx context.Context, query GetVenueQuery) (*command.VenueResult, error) {
+	venue, err := h.venueRepo.GetByID(ctx, query.ID, query.OrganizerID)
+	if err != nil {
+		if err == domain.ErrVenueNotFound {
+			return nil, err
+		}
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to get venue")
+	}
+
+	return command.ToVenueResult(venue), nil
+}
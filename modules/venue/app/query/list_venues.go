@@ -0,0 +1,54 @@
+package query
+
+import (
+	"context"
+
+	"tixgo/modules/venue/app/command"
+	"tixgo/modules/venue/domain"
+	"tixgo/shared/listing"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// maxVenuePageSize bounds the venues returned per page
+const maxVenuePageSize = 100
+
+// ListVenuesQuery represents the query of an organizer for their venues and the shared ones
+type ListVenuesQuery struct {
+	OrganizerID int64 `json:"-"`
+}
+
+// ListVenuesHandler handles listing the venues
+type ListVenuesHandler struct {
+	venueRepo domain.VenueRepository
+}
+
+// NewListVenuesHandler creates a new list venues handler
+func NewListVenuesHandler(venueRepo domain.VenueRepository) *ListVenuesHandler {
+	return &ListVenuesHandler{
+		venueRepo: venueRepo,
+	}
+}
+
+// Handle executes the list venues query
+func (h *ListVenuesHandler) Handle(ctx context.Context, query ListVenuesQuery, paging *listing.Paging) ([]*command.VenueResult, error) {
+	// Ensure paging is not nil (should already be handled in HTTP layer)
+	if paging == nil {
+		paging = &listing.Paging{}
+		paging.Fulfill()
+	}
+	if paging.Limit > maxVenuePageSize {
+		paging.Limit = maxVenuePageSize
+	}
+
+	venues, err := h.venueRepo.List(ctx, query.OrganizerID, paging)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to list venues")
+	}
+
+	results := make([]*command.VenueResult, len(venues))
+	for i, venue := range venues {
+		results[i] = command.ToVenueResult(venue)
+	}
+	return results, nil
+}
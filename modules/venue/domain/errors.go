@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"tixgo/shared/errstack"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// Venue domain errors
+var (
+	ErrVenueNotFound          = errstack.Sentinel(syserr.NotFoundCode, "venue not found")
+	ErrVenueNotEditable       = errstack.Sentinel(syserr.ForbiddenCode, "shared venues cannot be changed by organizers")
+	ErrVenueInUse             = errstack.Sentinel(syserr.ConflictCode, "a venue events take place at cannot be deleted")
+	ErrInvalidVenueType       = errstack.Sentinel(syserr.InvalidArgumentCode, "venue_type must be indoor, outdoor, virtual or hybrid")
+	ErrInvalidCoordinates     = errstack.Sentinel(syserr.InvalidArgumentCode, "latitude must be within -90 and 90 and longitude within -180 and 180, both set or neither")
+	ErrInvalidSeat            = errstack.Sentinel(syserr.InvalidArgumentCode, "every seat needs a section of at most 50 characters, a row and a number of at most 10")
+	ErrDuplicateSeat          = errstack.Sentinel(syserr.InvalidArgumentCode, "a seat appears twice in the seat map")
+	ErrSeatMapExceedsCapacity = errstack.Sentinel(syserr.InvalidArgumentCode, "the seat map has more seats than the capacity of the venue")
+	ErrSeatBound              = errstack.Sentinel(syserr.ConflictCode, "seats bound to tickets cannot be removed from the seat map")
+)
@@ -0,0 +1,73 @@
+package domain
+
+import "strings"
+
+// Seat is a seat of the seat map of a venue, referenced by section, row and number
+type Seat struct {
+	ID      int64
+	Section string
+	Row     string
+	Number  string
+}
+
+type seatKey struct {
+	section, row, number string
+}
+
+func (s Seat) key() seatKey {
+	return seatKey{s.Section, s.Row, s.Number}
+}
+
+// SeatMap is the seats of a venue in the order they are laid out, section by section and row by row
+type SeatMap []Seat
+
+// NewSeatMap checks the seats of a seat map of a venue of capacity, trimming their labels
+func NewSeatMap(seats []Seat, capacity int) (SeatMap, error) {
+	if len(seats) > capacity {
+		return nil, ErrSeatMapExceedsCapacity
+	}
+
+	seatMap := make(SeatMap, len(seats))
+	taken := make(map[seatKey]bool, len(seats))
+	for i, seat := range seats {
+		seat = Seat{
+			Section: strings.TrimSpace(seat.Section),
+			Row:     strings.TrimSpace(seat.Row),
+			Number:  strings.TrimSpace(seat.Number),
+		}
+		if seat.Section == "" || seat.Row == "" || seat.Number == "" ||
+			len(seat.Section) > 50 || len(seat.Row) > 10 || len(seat.Number) > 10 {
+			return nil, ErrInvalidSeat
+		}
+		if taken[seat.key()] {
+			return nil, ErrDuplicateSeat
+		}
+		taken[seat.key()] = true
+		seatMap[i] = seat
+	}
+	return seatMap, nil
+}
+
+// Replace matches the seats of the seat map with the ones of the current seat map of the venue: the seats
+// in both take their current IDs, and the IDs of the current seats left out are returned
+func (m SeatMap) Replace(current SeatMap) (removed []int64) {
+	ids := make(map[seatKey]int64, len(current))
+	for _, seat := range current {
+		ids[seat.key()] = seat.ID
+	}
+
+	for i := range m {
+		key := m[i].key()
+		if id, ok := ids[key]; ok {
+			m[i].ID = id
+			delete(ids, key)
+		}
+	}
+
+	for _, seat := range current {
+		if _, ok := ids[seat.key()]; ok {
+			removed = append(removed, seat.ID)
+		}
+	}
+	return removed
+}
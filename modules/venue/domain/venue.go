@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"tixgo/shared/listing"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// VenueType is the kind of place a venue is
+type VenueType string
+
+const (
+	VenueTypeIndoor  VenueType = "indoor"
+	VenueTypeOutdoor VenueType = "outdoor"
+	VenueTypeVirtual VenueType = "virtual"
+	VenueTypeHybrid  VenueType = "hybrid"
+)
+
+// IsValid checks if the venue type is valid
+func (t VenueType) IsValid() bool {
+	switch t {
+	case VenueTypeIndoor, VenueTypeOutdoor, VenueTypeVirtual, VenueTypeHybrid:
+		return true
+	default:
+		return false
+	}
+}
+
+// VenueDetails are the details of a venue its organizer sets when creating and updating it
+type VenueDetails struct {
+	Name        string
+	Description string
+	Address     string
+	City        string
+	State       string
+	Country     string
+	// Capacity bounds the seats of the seat map
+	Capacity     int
+	VenueType    VenueType
+	Latitude     *float64
+	Longitude    *float64
+	ContactEmail string
+	ContactPhone string
+}
+
+// Venue is a place events take place at, with its seat map when it has reserved seating
+type Venue struct {
+	ID int64
+	// OrganizerID is the organizer owning the venue, nil for a venue shared by every organizer
+	OrganizerID *int64
+	VenueDetails
+	// Seats counts the seats of the seat map
+	Seats     int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewVenue creates a venue of the organizer
+func NewVenue(organizerID int64, details VenueDetails) (*Venue, error) {
+	venue := &Venue{OrganizerID: &organizerID}
+	if err := venue.Update(details); err != nil {
+		return nil, err
+	}
+	return venue, nil
+}
+
+// Update changes the details of the venue. The events at the venue are left as they are.
+func (v *Venue) Update(details VenueDetails) error {
+	details.Name = strings.TrimSpace(details.Name)
+	details.Address = strings.TrimSpace(details.Address)
+	details.City = strings.TrimSpace(details.City)
+	details.Country = strings.TrimSpace(details.Country)
+	if details.Name == "" || details.Address == "" || details.City == "" || details.Country == "" {
+		return syserr.New(syserr.InvalidArgumentCode, "name, address, city and country are required")
+	}
+	if details.Capacity < 1 {
+		return syserr.New(syserr.InvalidArgumentCode, "capacity must be at least 1")
+	}
+	if !details.VenueType.IsValid() {
+		return ErrInvalidVenueType
+	}
+	if (details.Latitude == nil) != (details.Longitude == nil) {
+		return ErrInvalidCoordinates
+	}
+	if details.Latitude != nil && (*details.Latitude < -90 || *details.Latitude > 90 || *details.Longitude < -180 || *details.Longitude > 180) {
+		return ErrInvalidCoordinates
+	}
+	if details.Capacity < v.Seats {
+		return ErrSeatMapExceedsCapacity
+	}
+
+	v.VenueDetails = details
+	return nil
+}
+
+// CheckEditable fails with ErrVenueNotEditable unless the venue is owned by the organizer; shared venues
+// are used by every organizer and changed by none
+func (v *Venue) CheckEditable(organizerID int64) error {
+	if v.OrganizerID == nil || *v.OrganizerID != organizerID {
+		return ErrVenueNotEditable
+	}
+	return nil
+}
+
+// VenueRepository defines the interface for venue persistence. Venues of other organizers are reported as
+// not found; shared venues are found by every organizer.
+type VenueRepository interface {
+	// Create stores a new venue
+	Create(ctx context.Context, venue *Venue) error
+
+	// GetByID retrieves a venue of the organizer or a shared one, with the number of its seats
+	GetByID(ctx context.Context, id, organizerID int64) (*Venue, error)
+
+	// List retrieves a page of the venues of the organizer and the shared ones, by name
+	List(ctx context.Context, organizerID int64, paging *listing.Paging) ([]*Venue, error)
+
+	// Update saves the details of a venue, ErrSeatMapExceedsCapacity if its capacity is below the seats
+	// of its seat map
+	Update(ctx context.Context, venue *Venue) error
+
+	// Delete deletes a venue with its seat map, ErrVenueInUse if events take place at it
+	Delete(ctx context.Context, venue *Venue) error
+
+	// GetSeatMap retrieves the seat map of a venue
+	GetSeatMap(ctx context.Context, venueID int64) (SeatMap, error)
+
+	// ReplaceSeatMap replaces the seat map of a venue, setting the IDs of its seats. The seats kept keep
+	// their IDs; ErrSeatBound if a seat removed is bound to tickets.
+	ReplaceSeatMap(ctx context.Context, venue *Venue, seatMap SeatMap) error
+}
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestNewVenue(t *testing.T) {
+	details := VenueDetails{Name: " Arena ", Address: "1 Main St", City: "Hanoi", Country: "VN", Capacity: 100, VenueType: VenueTypeIndoor}
+	venue, err := NewVenue(7, details)
+	require.NoError(t, err)
+	assert.Equal(t, "Arena", venue.Name)
+	assert.Equal(t, int64(7), *venue.OrganizerID)
+
+	tests := []struct {
+		name   string
+		modify func(d *VenueDetails)
+		want   error
+	}{
+		{name: "unknown type", modify: func(d *VenueDetails) { d.VenueType = "stadium" }, want: ErrInvalidVenueType},
+		{name: "latitude without longitude", modify: func(d *VenueDetails) { d.Latitude = ptr(21.0) }, want: ErrInvalidCoordinates},
+		{name: "latitude out of range", modify: func(d *VenueDetails) { d.Latitude, d.Longitude = ptr(91.0), ptr(105.0) }, want: ErrInvalidCoordinates},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := details
+			tt.modify(&d)
+			_, err := NewVenue(7, d)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+
+	d := details
+	d.Capacity = 0
+	_, err = NewVenue(7, d)
+	assert.Error(t, err)
+}
+
+func TestVenue_Update(t *testing.T) {
+	venue := &Venue{OrganizerID: ptr(int64(7)), Seats: 50}
+	details := VenueDetails{Name: "Arena", Address: "1 Main St", City: "Hanoi", Country: "VN", Capacity: 49, VenueType: VenueTypeOutdoor}
+	assert.ErrorIs(t, venue.Update(details), ErrSeatMapExceedsCapacity)
+
+	details.Capacity = 50
+	assert.NoError(t, venue.Update(details))
+}
+
+func TestVenue_CheckEditable(t *testing.T) {
+	assert.NoError(t, (&Venue{OrganizerID: ptr(int64(7))}).CheckEditable(7))
+	assert.ErrorIs(t, (&Venue{OrganizerID: ptr(int64(8))}).CheckEditable(7), ErrVenueNotEditable)
+	assert.ErrorIs(t, (&Venue{}).CheckEditable(7), ErrVenueNotEditable)
+}
+
+func TestNewSeatMap(t *testing.T) {
+	seatMap, err := NewSeatMap([]Seat{{Section: " A ", Row: "1", Number: "1"}, {Section: "A", Row: "1", Number: "2"}}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "A", seatMap[0].Section)
+
+	_, err = NewSeatMap([]Seat{{Section: "A", Row: "1", Number: "1"}, {Section: "A", Row: "1", Number: "2"}}, 1)
+	assert.ErrorIs(t, err, ErrSeatMapExceedsCapacity)
+
+	_, err = NewSeatMap([]Seat{{Section: "A", Row: "1", Number: "1"}, {Section: "A ", Row: "1", Number: "1"}}, 2)
+	assert.ErrorIs(t, err, ErrDuplicateSeat)
+
+	_, err = NewSeatMap([]Seat{{Section: "A", Row: "", Number: "1"}}, 2)
+	assert.ErrorIs(t, err, ErrInvalidSeat)
+
+	_, err = NewSeatMap([]Seat{{Section: "A", Row: "12345678901", Number: "1"}}, 2)
+	assert.ErrorIs(t, err, ErrInvalidSeat)
+}
+
+func TestSeatMap_Replace(t *testing.T) {
+	current := SeatMap{
+		{ID: 1, Section: "A", Row: "1", Number: "1"},
+		{ID: 2, Section: "A", Row: "1", Number: "2"},
+		{ID: 3, Section: "A", Row: "1", Number: "3"},
+	}
+	seatMap := SeatMap{
+		{Section: "A", Row: "1", Number: "2"},
+		{Section: "A", Row: "1", Number: "4"},
+		{Section: "A", Row: "1", Number: "1"},
+	}
+
+	removed := seatMap.Replace(current)
+	assert.Equal(t, []int64{3}, removed)
+	assert.Equal(t, []int64{2, 0, 1}, []int64{seatMap[0].ID, seatMap[1].ID, seatMap[2].ID})
+}
@@ -0,0 +1,219 @@
+package ports
+
+import (
+	"net/http"
+	"strconv"
+
+	"tixgo/components"
+	userDomain "tixgo/modules/user/domain"
+	"tixgo/modules/venue/adapters"
+	"tixgo/modules/venue/app/command"
+	"tixgo/modules/venue/app/query"
+	"tixgo/shared/apiversion"
+	"tixgo/shared/authz"
+	"tixgo/shared/httpresponse"
+	"tixgo/shared/listing"
+	"tixgo/shared/session"
+
+	"github.com/duongptryu/gox/context"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterVenueRoutes(router *apiversion.Group, appCtx components.AppContext) {
+	venueGroup := router.Group("/venues")
+	{
+		venueGroup.Use(session.RequireAuth(appCtx.GetSessionService()))
+		venueGroup.Use(authz.RequireUserType(string(userDomain.UserTypeOrganizer)))
+		venueGroup.POST("", CreateVenue(appCtx))
+		venueGroup.GET("", ListVenues(appCtx))
+		venueGroup.GET("/:id", GetVenue(appCtx))
+		venueGroup.PUT("/:id", UpdateVenue(appCtx))
+		venueGroup.DELETE("/:id", DeleteVenue(appCtx))
+		venueGroup.GET("/:id/seat-map", GetSeatMap(appCtx))
+		venueGroup.PUT("/:id/seat-map", ReplaceSeatMap(appCtx))
+	}
+}
+
+func CreateVenue(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.CreateVenueCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		organizerID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		req.OrganizerID = organizerID
+
+		handler := command.NewCreateVenueHandler(adapters.NewVenuePostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusCreated, result)
+	}
+}
+
+func ListVenues(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		organizerID, err := context.GetUserIDFromContextAsInt64(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		var paging listing.Paging
+		if err := c.ShouldBind(&paging); err != nil {
+			c.Error(err)
+			return
+		}
+
+		// Apply pagination defaults in HTTP layer
+		paging.Fulfill()
+
+		filters := query.ListVenuesQuery{OrganizerID: organizerID}
+		handler := query.NewListVenuesHandler(adapters.NewVenuePostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), filters, &paging)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.List(c, result, paging, filters)
+	}
+}
+
+func GetVenue(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		venueID, organizerID, ok := authenticatedVenueParams(c)
+		if !ok {
+			return
+		}
+
+		handler := query.NewGetVenueHandler(adapters.NewVenuePostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), query.GetVenueQuery{ID: venueID, OrganizerID: organizerID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func UpdateVenue(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.UpdateVenueCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		venueID, organizerID, ok := authenticatedVenueParams(c)
+		if !ok {
+			return
+		}
+		req.ID = venueID
+		req.OrganizerID = organizerID
+
+		handler := command.NewUpdateVenueHandler(adapters.NewVenuePostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func DeleteVenue(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		venueID, organizerID, ok := authenticatedVenueParams(c)
+		if !ok {
+			return
+		}
+
+		handler := command.NewDeleteVenueHandler(adapters.NewVenuePostgresRepository(appCtx.GetDB()))
+
+		if err := handler.Handle(c.Request.Context(), command.DeleteVenueCommand{ID: venueID, OrganizerID: organizerID}); err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, true)
+	}
+}
+
+func GetSeatMap(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		venueID, organizerID, ok := authenticatedVenueParams(c)
+		if !ok {
+			return
+		}
+
+		handler := query.NewGetSeatMapHandler(adapters.NewVenuePostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), query.GetSeatMapQuery{VenueID: venueID, OrganizerID: organizerID})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func ReplaceSeatMap(appCtx components.AppContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req command.ReplaceSeatMapCommand
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		venueID, organizerID, ok := authenticatedVenueParams(c)
+		if !ok {
+			return
+		}
+		req.VenueID = venueID
+		req.OrganizerID = organizerID
+
+		handler := command.NewReplaceSeatMapHandler(adapters.NewVenuePostgresRepository(appCtx.GetDB()))
+
+		result, err := handler.Handle(c.Request.Context(), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, result)
+	}
+}
+
+func authenticatedVenueParams(c *gin.Context) (venueID, userID int64, ok bool) {
+	venueID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(err)
+		return 0, 0, false
+	}
+
+	userID, err = context.GetUserIDFromContextAsInt64(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return 0, 0, false
+	}
+
+	return venueID, userID, true
+}
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"tixgo/modules/venue/app/command"
+	"tixgo/shared/jsonschema"
+	"tixgo/shared/listing"
+)
+
+// Schemas returns the request payloads of the venue module
+func Schemas() []jsonschema.Payload {
+	return []jsonschema.Payload{
+		{Name: "venues.create", In: jsonschema.Body, Example: command.CreateVenueCommand{}},
+		{Name: "venues.update", In: jsonschema.Body, Example: command.UpdateVenueCommand{}},
+		{Name: "venues", In: jsonschema.Query, Example: listing.Paging{}},
+		{Name: "venues.seat-map", In: jsonschema.Body, Example: command.ReplaceSeatMapCommand{}},
+	}
+}
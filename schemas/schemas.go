@@ -0,0 +1,46 @@
+// Package schemas lists the request payloads of every module, published as JSON Schemas by the API
+package schemas
+
+import (
+	bookingPort "tixgo/modules/booking/ports"
+	compliancePort "tixgo/modules/compliance/ports"
+	eventPort "tixgo/modules/event/ports"
+	notificationPort "tixgo/modules/notification/ports"
+	orderPort "tixgo/modules/order/ports"
+	organizerPort "tixgo/modules/organizer/ports"
+	promotionPort "tixgo/modules/promotion/ports"
+	schedulerPort "tixgo/modules/scheduler/ports"
+	supportPort "tixgo/modules/support/ports"
+	templatePort "tixgo/modules/template/ports"
+	ticketPort "tixgo/modules/ticket/ports"
+	userPort "tixgo/modules/user/ports"
+	venuePort "tixgo/modules/venue/ports"
+	"tixgo/shared/jsonschema"
+	"tixgo/shared/readonly"
+)
+
+// All returns the payloads of every module. A payload changed in a way that breaks its clients fails
+// the tests of this package, see testdata/schemas.json.
+func All() []jsonschema.Payload {
+	var payloads []jsonschema.Payload
+
+	// Add any additional module payloads here
+	payloads = append(payloads, userPort.Schemas()...)
+	payloads = append(payloads, templatePort.Schemas()...)
+	payloads = append(payloads, schedulerPort.Schemas()...)
+	payloads = append(payloads, eventPort.Schemas()...)
+	payloads = append(payloads, bookingPort.Schemas()...)
+	payloads = append(payloads, organizerPort.Schemas()...)
+	payloads = append(payloads, notificationPort.Schemas()...)
+	payloads = append(payloads, orderPort.Schemas()...)
+	payloads = append(payloads, compliancePort.Schemas()...)
+	payloads = append(payloads, ticketPort.Schemas()...)
+	payloads = append(payloads, promotionPort.Schemas()...)
+	payloads = append(payloads, venuePort.Schemas()...)
+	payloads = append(payloads, supportPort.Schemas()...)
+
+	// Payloads of the routes of the API server itself
+	payloads = append(payloads, jsonschema.Payload{Name: "admin.read_only", In: jsonschema.Body, Example: readonly.Request{}})
+
+	return payloads
+}
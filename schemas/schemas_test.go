@@ -0,0 +1,75 @@
+package schemas
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"tixgo/shared/jsonschema"
+)
+
+var update = flag.Bool("update", false, "record the current payload schemas in testdata/schemas.json")
+
+const snapshotPath = "testdata/schemas.json"
+
+// TestBackwardCompatible compares the payload schemas with the snapshot of the last recorded ones.
+// Breaking changes fail; compatible ones fail until they are recorded with
+// go test ./schemas -run TestBackwardCompatible -update, so the snapshot follows every change.
+func TestBackwardCompatible(t *testing.T) {
+	current, err := json.MarshalIndent(jsonschema.NewRegistry(All()...).Schemas(), "", "  ")
+	if err != nil {
+		t.Fatalf("marshal schemas: %v", err)
+	}
+	current = append(current, '\n')
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(snapshotPath), 0o755); err != nil {
+			t.Fatalf("create testdata: %v", err)
+		}
+		if err := os.WriteFile(snapshotPath, current, 0o644); err != nil {
+			t.Fatalf("write snapshot: %v", err)
+		}
+		return
+	}
+
+	recorded, err := os.ReadFile(snapshotPath)
+	if err != nil {
+		t.Fatalf("read snapshot, record it with -update: %v", err)
+	}
+
+	var previous, next map[string]*jsonschema.Schema
+	if err := json.Unmarshal(recorded, &previous); err != nil {
+		t.Fatalf("unmarshal snapshot: %v", err)
+	}
+	if err := json.Unmarshal(current, &next); err != nil {
+		t.Fatalf("unmarshal schemas: %v", err)
+	}
+
+	names := make([]string, 0, len(previous))
+	for name := range previous {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		schema, ok := next[name]
+		if !ok {
+			t.Errorf("%s: payload removed", name)
+			continue
+		}
+		for _, problem := range jsonschema.Breaking(previous[name], schema) {
+			t.Errorf("%s: %s", name, problem)
+		}
+	}
+	if t.Failed() {
+		t.Log("these changes break the clients of the API, keep the old fields or add a new API version")
+		return
+	}
+
+	if string(recorded) != string(current) {
+		t.Errorf("payload schemas changed compatibly, record them with go test ./schemas -run TestBackwardCompatible -update")
+	}
+}
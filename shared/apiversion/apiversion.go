@@ -0,0 +1,79 @@
+package apiversion
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	V1 = "v1"
+	V2 = "v2"
+)
+
+// Versions lists the served API versions, oldest first
+var Versions = []string{V1, V2}
+
+// Latest is the newest API version
+var Latest = Versions[len(Versions)-1]
+
+// Deprecation announces that a version is going away
+type Deprecation struct {
+	// DeprecatedAt is when the version is (or will be) deprecated
+	DeprecatedAt time.Time
+	// SunsetAt is when the version stops being served, zero if not decided yet
+	SunsetAt time.Time
+}
+
+type versionKey struct{}
+
+// Group is the route group of an API version, mounted at "/<version>"
+type Group struct {
+	*gin.RouterGroup
+	Version string
+}
+
+// NewGroup mounts the routes of version on router. Every request it serves knows its version (see
+// FromContext), and a deprecated version answers with Deprecation and Sunset headers
+// (RFC 9745, RFC 8594) pointing clients at the latest version.
+func NewGroup(router gin.IRouter, version string, deprecation *Deprecation) *Group {
+	group := router.Group("/" + version)
+	group.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), versionKey{}, version))
+		if deprecation != nil {
+			setDeprecationHeaders(c, deprecation)
+		}
+		c.Next()
+	})
+	return &Group{RouterGroup: group, Version: version}
+}
+
+// AtLeast reports whether the group serves version or a newer one, to register routes from a version on
+func (g *Group) AtLeast(version string) bool {
+	return AtLeast(g.Version, version)
+}
+
+// FromContext returns the API version of the request, the latest one outside of a version group
+func FromContext(ctx context.Context) string {
+	if version, ok := ctx.Value(versionKey{}).(string); ok {
+		return version
+	}
+	return Latest
+}
+
+// AtLeast reports whether version is the same as or newer than other
+func AtLeast(version, other string) bool {
+	return slices.Index(Versions, version) >= slices.Index(Versions, other)
+}
+
+func setDeprecationHeaders(c *gin.Context, deprecation *Deprecation) {
+	c.Header("Deprecation", "@"+strconv.FormatInt(deprecation.DeprecatedAt.Unix(), 10))
+	if !deprecation.SunsetAt.IsZero() {
+		c.Header("Sunset", deprecation.SunsetAt.UTC().Format(http.TimeFormat))
+	}
+	c.Header("Link", `</`+Latest+`>; rel="successor-version"`)
+}
@@ -0,0 +1,58 @@
+package apiversion
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewGroup(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	served := map[string]string{}
+	deprecation := &Deprecation{
+		DeprecatedAt: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+		SunsetAt:     time.Date(2027, 5, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for _, version := range Versions {
+		var group *Group
+		if version == V1 {
+			group = NewGroup(router, version, deprecation)
+		} else {
+			group = NewGroup(router, version, nil)
+		}
+		group.GET("/ping", func(c *gin.Context) {
+			served[group.Version] = FromContext(c.Request.Context())
+			c.JSON(http.StatusOK, Render(c.Request.Context(), "latest", Shims[string]{
+				V1: func(latest string) any { return "legacy " + latest },
+			}))
+		})
+	}
+
+	v1 := httptest.NewRecorder()
+	router.ServeHTTP(v1, httptest.NewRequest(http.MethodGet, "/v1/ping", nil))
+	assert.Equal(t, "@1793491200", v1.Header().Get("Deprecation"))
+	assert.Equal(t, "Sat, 01 May 2027 00:00:00 GMT", v1.Header().Get("Sunset"))
+	assert.Equal(t, `</v2>; rel="successor-version"`, v1.Header().Get("Link"))
+	assert.Equal(t, `"legacy latest"`, v1.Body.String())
+
+	v2 := httptest.NewRecorder()
+	router.ServeHTTP(v2, httptest.NewRequest(http.MethodGet, "/v2/ping", nil))
+	assert.Empty(t, v2.Header().Get("Deprecation"))
+	assert.Empty(t, v2.Header().Get("Sunset"))
+	assert.Equal(t, `"latest"`, v2.Body.String())
+
+	assert.Equal(t, map[string]string{V1: V1, V2: V2}, served)
+}
+
+func TestAtLeast(t *testing.T) {
+	assert.True(t, AtLeast(V2, V1))
+	assert.True(t, AtLeast(V2, V2))
+	assert.False(t, AtLeast(V1, V2))
+	assert.True(t, (&Group{Version: V2}).AtLeast(V2))
+}
@@ -0,0 +1,16 @@
+package apiversion
+
+import "context"
+
+// Shims convert a response of the latest version into the shape clients of an older version
+// expect, keyed by that older version. Handlers build the latest shape only, and breaking changes
+// ship with a shim for each version they would break.
+type Shims[T any] map[string]func(latest T) any
+
+// Render returns latest as the API version of ctx expects it
+func Render[T any](ctx context.Context, latest T, shims Shims[T]) any {
+	if shim, ok := shims[FromContext(ctx)]; ok {
+		return shim(latest)
+	}
+	return latest
+}
@@ -0,0 +1,202 @@
+// Package assets hosts the public images mails reference, e.g. the logos and banners of templates.
+// Each image is stored under the hash of its content, so a URL always serves the same bytes and is
+// cached for good by browsers and CDNs; uploading another image under a name gives the name a new URL
+// while the mails already sent keep showing the image they were sent with.
+package assets
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"tixgo/shared/errstack"
+	"tixgo/shared/storage"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+const (
+	// PathPrefix is where the files are served, asset URLs are the base URL, the prefix and the file
+	PathPrefix = "/assets/"
+	// MaxSize is the largest image accepted, mail clients are slow to load larger ones
+	MaxSize = 2 << 20
+	// CacheControl lets browsers and CDNs keep a file for a year without revalidating it, its content
+	// never changes
+	CacheControl = "public, max-age=31536000, immutable"
+
+	// storagePrefix is the directory of the files in the storage
+	storagePrefix = "assets/"
+)
+
+var (
+	// ErrNotFound is returned for unknown names and files
+	ErrNotFound = errstack.Sentinel(syserr.NotFoundCode, "asset not found")
+	// ErrInvalidName is returned for names that cannot be written in templates as they are
+	ErrInvalidName = errstack.Sentinel(syserr.InvalidArgumentCode, "asset names are 1 to 100 letters, digits, dots, dashes and underscores, starting with a letter or digit")
+	// ErrUnsupportedType is returned for files that are not an image mail clients show
+	ErrUnsupportedType = errstack.Sentinel(syserr.InvalidArgumentCode, "assets must be PNG, JPEG, GIF or WebP images")
+	// ErrTooLarge is returned for files larger than MaxSize
+	ErrTooLarge = errstack.Sentinel(syserr.InvalidArgumentCode, fmt.Sprintf("assets must be at most %d MB", MaxSize>>20))
+)
+
+var (
+	namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,99}$`)
+	filePattern = regexp.MustCompile(`^[0-9a-f]{64}\.[a-z]+$`)
+
+	// extensions are the file extensions of the accepted formats, sniffed from the content
+	extensions = map[string]string{
+		"image/png":  ".png",
+		"image/jpeg": ".jpg",
+		"image/gif":  ".gif",
+		"image/webp": ".webp",
+	}
+)
+
+// Asset is a hosted image, addressed in templates by its name
+type Asset struct {
+	Name string
+	// Hash is the SHA-256 of the content in hex
+	Hash        string
+	ContentType string
+	Size        int64
+	UploadedBy  int64
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// File is the name of the file of the asset: its hash and the extension of its format
+func (a *Asset) File() string {
+	return a.Hash + extensions[a.ContentType]
+}
+
+// Store keeps the names of the assets and the file each currently points to
+type Store interface {
+	// Save creates the asset or points its name to the new file
+	Save(ctx context.Context, asset *Asset) error
+
+	// GetByNames retrieves the assets of the names, leaving out the unknown ones
+	GetByNames(ctx context.Context, names []string) ([]*Asset, error)
+
+	// List retrieves every asset by name
+	List(ctx context.Context) ([]*Asset, error)
+}
+
+// Service uploads the assets and resolves their URLs
+type Service struct {
+	store   Store
+	files   storage.Store
+	baseURL string
+}
+
+// NewService creates a service keeping the files in files, whose URLs start with baseURL, the public
+// scheme and host of the API or of the CDN in front of it
+func NewService(store Store, files storage.Store, baseURL string) *Service {
+	return &Service{
+		store:   store,
+		files:   files,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// Upload stores the image of r under name, replacing the image the name pointed to. The format is
+// sniffed from the content rather than trusted from the upload. Files are never deleted, mails already
+// sent still show them.
+func (s *Service) Upload(ctx context.Context, name string, r io.Reader, uploadedBy int64) (*Asset, error) {
+	if !namePattern.MatchString(name) {
+		return nil, ErrInvalidName
+	}
+
+	content, err := io.ReadAll(io.LimitReader(r, MaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read asset: %w", err)
+	}
+	if len(content) > MaxSize {
+		return nil, ErrTooLarge
+	}
+
+	contentType := http.DetectContentType(content)
+	if _, ok := extensions[contentType]; !ok {
+		return nil, ErrUnsupportedType
+	}
+
+	hash := sha256.Sum256(content)
+	asset := &Asset{
+		Name:        name,
+		Hash:        hex.EncodeToString(hash[:]),
+		ContentType: contentType,
+		Size:        int64(len(content)),
+		UploadedBy:  uploadedBy,
+	}
+
+	// the same content always lands on the same file, so writing it again is harmless
+	if _, err := s.files.Put(ctx, storagePrefix+asset.File(), bytes.NewReader(content)); err != nil {
+		return nil, fmt.Errorf("failed to store asset: %w", err)
+	}
+	if err := s.store.Save(ctx, asset); err != nil {
+		return nil, err
+	}
+
+	return asset, nil
+}
+
+// URL returns the URL of the current file of an asset
+func (s *Service) URL(asset *Asset) string {
+	return s.baseURL + PathPrefix + asset.File()
+}
+
+// URLs returns the URLs of the assets of the names, leaving out the unknown ones
+func (s *Service) URLs(ctx context.Context, names []string) (map[string]string, error) {
+	urls := make(map[string]string, len(names))
+	if len(names) == 0 {
+		return urls, nil
+	}
+
+	assets, err := s.store.GetByNames(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	for _, asset := range assets {
+		urls[asset.Name] = s.URL(asset)
+	}
+	return urls, nil
+}
+
+// List retrieves every asset by name
+func (s *Service) List(ctx context.Context) ([]*Asset, error) {
+	return s.store.List(ctx)
+}
+
+// Open reads a file, as named in asset URLs, and returns its content type. It fails with ErrNotFound
+// for names that are not a hosted file.
+func (s *Service) Open(ctx context.Context, file string) (io.ReadSeekCloser, string, error) {
+	if !filePattern.MatchString(file) {
+		return nil, "", ErrNotFound
+	}
+
+	contentType := ""
+	for accepted, extension := range extensions {
+		if strings.HasSuffix(file, extension) {
+			contentType = accepted
+		}
+	}
+	if contentType == "" {
+		return nil, "", ErrNotFound
+	}
+
+	content, err := s.files.Open(ctx, storagePrefix+file)
+	if err == storage.ErrNotFound {
+		return nil, "", ErrNotFound
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open asset %s: %w", file, err)
+	}
+
+	return content, contentType, nil
+}
@@ -0,0 +1,156 @@
+package assets
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"tixgo/shared/storage"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init(&logger.Config{Output: io.Discard})
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+// memoryStore is a Store keeping the assets in memory
+type memoryStore struct {
+	mu     sync.Mutex
+	assets map[string]*Asset
+}
+
+func (s *memoryStore) Save(_ context.Context, asset *Asset) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	asset.UpdatedAt = time.Now()
+	asset.CreatedAt = asset.UpdatedAt
+	if existing, ok := s.assets[asset.Name]; ok {
+		asset.CreatedAt = existing.CreatedAt
+	}
+	stored := *asset
+	s.assets[asset.Name] = &stored
+	return nil
+}
+
+func (s *memoryStore) GetByNames(_ context.Context, names []string) ([]*Asset, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var assets []*Asset
+	for _, name := range names {
+		if asset, ok := s.assets[name]; ok {
+			assets = append(assets, asset)
+		}
+	}
+	return assets, nil
+}
+
+func (s *memoryStore) List(_ context.Context) ([]*Asset, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var assets []*Asset
+	for _, asset := range s.assets {
+		assets = append(assets, asset)
+	}
+	sort.Slice(assets, func(i, j int) bool { return assets[i].Name < assets[j].Name })
+	return assets, nil
+}
+
+// a 1x1 transparent PNG and GIF
+var (
+	png, _ = base64.StdEncoding.DecodeString("iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAQAAAC1HAwCAAAAC0lEQVR42mNkYAAAAAYAAjCB0C8AAAAASUVORK5CYII=")
+	gif, _ = base64.StdEncoding.DecodeString("R0lGODlhAQABAAAAACw=")
+)
+
+func newTestService(t *testing.T) *Service {
+	return NewService(&memoryStore{assets: map[string]*Asset{}}, storage.NewDiskStore(t.TempDir()), "https://cdn.tixgo.test/")
+}
+
+func TestService_Upload(t *testing.T) {
+	ctx := context.Background()
+	service := newTestService(t)
+
+	first, err := service.Upload(ctx, "logo.png", bytes.NewReader(png), 7)
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", first.ContentType)
+	assert.Equal(t, int64(len(png)), first.Size)
+	assert.Regexp(t, `^https://cdn\.tixgo\.test/assets/[0-9a-f]{64}\.png$`, service.URL(first))
+
+	// the name moves to the new file, the old one is still served
+	second, err := service.Upload(ctx, "logo.png", bytes.NewReader(gif), 7)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.Hash, second.Hash)
+
+	urls, err := service.URLs(ctx, []string{"logo.png", "banner.png"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"logo.png": service.URL(second)}, urls)
+
+	content, contentType, err := service.Open(ctx, first.File())
+	require.NoError(t, err)
+	defer content.Close()
+	assert.Equal(t, "image/png", contentType)
+	stored, err := io.ReadAll(content)
+	require.NoError(t, err)
+	assert.Equal(t, png, stored)
+}
+
+func TestService_UploadRejects(t *testing.T) {
+	ctx := context.Background()
+	service := newTestService(t)
+
+	for _, name := range []string{"", ".hidden", "../logo.png", "logo png", strings.Repeat("a", 101)} {
+		_, err := service.Upload(ctx, name, bytes.NewReader(png), 7)
+		assert.ErrorIs(t, err, ErrInvalidName, name)
+	}
+
+	_, err := service.Upload(ctx, "logo.svg", strings.NewReader(`<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`), 7)
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+
+	_, err = service.Upload(ctx, "huge.png", io.MultiReader(bytes.NewReader(png), bytes.NewReader(make([]byte, MaxSize))), 7)
+	assert.ErrorIs(t, err, ErrTooLarge)
+}
+
+func TestHandler(t *testing.T) {
+	service := newTestService(t)
+	asset, err := service.Upload(context.Background(), "logo.png", bytes.NewReader(png), 7)
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.GET(PathPrefix+":file", Handler(service))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, PathPrefix+asset.File(), nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	assert.Equal(t, CacheControl, w.Header().Get("Cache-Control"))
+	assert.Equal(t, png, w.Body.Bytes())
+
+	req := httptest.NewRequest(http.MethodGet, PathPrefix+asset.File(), nil)
+	req.Header.Set("If-None-Match", w.Header().Get("ETag"))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	for _, file := range []string{asset.Hash + ".gif", asset.Hash + ".svg", "logo.png"} {
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, PathPrefix+file, nil))
+		assert.Equal(t, http.StatusNotFound, w.Code, file)
+	}
+}
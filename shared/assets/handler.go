@@ -0,0 +1,36 @@
+package assets
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// Handler serves the file of the :file parameter to mail clients and the CDN in front of the API.
+// Files never change, so they are cached for good and their hash is their ETag.
+func Handler(service *Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		file := c.Param("file")
+		content, contentType, err := service.Open(c.Request.Context(), file)
+		switch {
+		case errors.Is(err, ErrNotFound):
+			c.Status(http.StatusNotFound)
+			return
+		case err != nil:
+			logger.Error(c.Request.Context(), "Failed to open asset", logger.F("file", file), logger.F("error", err))
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		defer content.Close()
+
+		c.Header("Content-Type", contentType)
+		c.Header("Cache-Control", CacheControl)
+		c.Header("ETag", `"`+strings.TrimSuffix(file, path.Ext(file))+`"`)
+		http.ServeContent(c.Writer, c.Request, "", time.Time{}, content)
+	}
+}
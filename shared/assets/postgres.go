@@ -0,0 +1,84 @@
+package assets
+
+import (
+	"context"
+	"fmt"
+
+	"tixgo/shared/dbtimeout"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// PostgresStore implements Store with the assets table
+type PostgresStore struct {
+	db *sqlx.DB
+}
+
+// NewPostgresStore creates an asset store backed by postgres
+func NewPostgresStore(db *sqlx.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+const selectAsset = `
+	SELECT name, hash, content_type, size, uploaded_by, created_at, updated_at
+	FROM assets`
+
+func (s *PostgresStore) Save(ctx context.Context, asset *Asset) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		INSERT INTO assets (name, hash, content_type, size, uploaded_by)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (name) DO UPDATE
+		SET hash = EXCLUDED.hash, content_type = EXCLUDED.content_type, size = EXCLUDED.size,
+		    uploaded_by = EXCLUDED.uploaded_by, updated_at = NOW()
+		RETURNING created_at, updated_at`
+
+	err := s.db.QueryRowContext(ctx, query, asset.Name, asset.Hash, asset.ContentType, asset.Size, asset.UploadedBy).
+		Scan(&asset.CreatedAt, &asset.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save asset %s: %w", asset.Name, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresStore) GetByNames(ctx context.Context, names []string) ([]*Asset, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return s.query(ctx, selectAsset+` WHERE name = ANY($1)`, pq.Array(names))
+}
+
+func (s *PostgresStore) List(ctx context.Context) ([]*Asset, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return s.query(ctx, selectAsset+` ORDER BY name`)
+}
+
+func (s *PostgresStore) query(ctx context.Context, query string, args ...interface{}) ([]*Asset, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assets: %w", err)
+	}
+	defer rows.Close()
+
+	var assets []*Asset
+	for rows.Next() {
+		asset := &Asset{}
+		err := rows.Scan(&asset.Name, &asset.Hash, &asset.ContentType, &asset.Size, &asset.UploadedBy, &asset.CreatedAt, &asset.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan asset: %w", err)
+		}
+		assets = append(assets, asset)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating asset rows: %w", err)
+	}
+
+	return assets, nil
+}
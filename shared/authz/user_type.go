@@ -0,0 +1,27 @@
+package authz
+
+import (
+	"slices"
+
+	"tixgo/shared/errstack"
+
+	"github.com/duongptryu/gox/context"
+	"github.com/duongptryu/gox/syserr"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireUserType only lets through requests authenticated as one of the given user types.
+// It must run after session.RequireAuth, which puts the user type into the request context.
+func RequireUserType(userTypes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userType := context.GetUserTypeFromContext(c.Request.Context())
+		if !slices.Contains(userTypes, userType) {
+			c.Error(errstack.New(syserr.ForbiddenCode, "insufficient permissions"))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,101 @@
+package barcode
+
+import "errors"
+
+const (
+	// Code128QuietZone is the light margin on both sides of a Code 128 barcode, in modules
+	Code128QuietZone = 10
+
+	code128StartB = 104
+	code128StartC = 105
+	code128Stop   = 106
+)
+
+// ErrInvalidCode128 is returned for text Code 128 cannot carry, or empty text
+var ErrInvalidCode128 = errors.New("barcode: text must be non-empty printable ASCII")
+
+// code128Patterns are the bar and space widths of each symbol, starting with a bar; the stop symbol
+// ends with its termination bar
+var code128Patterns = [107]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+// Bars is a Code 128 barcode, a row of modules
+type Bars struct {
+	modules []bool
+}
+
+// Width is the number of modules, without the quiet zones
+func (b *Bars) Width() int {
+	return len(b.modules)
+}
+
+// Dark tells whether the module at x is part of a bar
+func (b *Bars) Dark(x int) bool {
+	return b.modules[x]
+}
+
+// Code128 encodes printable ASCII text as a Code 128 barcode. Text made of an even number of digits
+// uses code set C, two digits per symbol, the rest code set B.
+func Code128(text string) (*Bars, error) {
+	if text == "" {
+		return nil, ErrInvalidCode128
+	}
+	for i := 0; i < len(text); i++ {
+		if text[i] < 32 || text[i] > 126 {
+			return nil, ErrInvalidCode128
+		}
+	}
+
+	var symbols []int
+	if isEvenDigits(text) {
+		symbols = append(symbols, code128StartC)
+		for i := 0; i < len(text); i += 2 {
+			symbols = append(symbols, int(text[i]-'0')*10+int(text[i+1]-'0'))
+		}
+	} else {
+		symbols = append(symbols, code128StartB)
+		for i := 0; i < len(text); i++ {
+			symbols = append(symbols, int(text[i])-32)
+		}
+	}
+
+	checksum := symbols[0]
+	for i, symbol := range symbols[1:] {
+		checksum += (i + 1) * symbol
+	}
+	symbols = append(symbols, checksum%103, code128Stop)
+
+	var modules []bool
+	for _, symbol := range symbols {
+		for i, width := range code128Patterns[symbol] {
+			for w := 0; w < int(width-'0'); w++ {
+				modules = append(modules, i%2 == 0)
+			}
+		}
+	}
+
+	return &Bars{modules: modules}, nil
+}
+
+func isEvenDigits(text string) bool {
+	if len(text)%2 != 0 {
+		return false
+	}
+	for i := 0; i < len(text); i++ {
+		if text[i] < '0' || text[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+package barcode
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCode128Patterns(t *testing.T) {
+	seen := map[string]bool{}
+	for symbol, pattern := range code128Patterns {
+		width := 0
+		for _, w := range pattern {
+			width += int(w - '0')
+		}
+
+		if symbol == code128Stop {
+			assert.Equal(t, 13, width, "stop")
+			continue
+		}
+		assert.Equal(t, 11, width, "symbol %d", symbol)
+		assert.False(t, seen[pattern], "symbol %d repeats a pattern", symbol)
+		seen[pattern] = true
+	}
+}
+
+func TestCode128(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		start   int
+		symbols []int
+	}{
+		{name: "code set B", text: "TIX-42", start: code128StartB, symbols: []int{52, 41, 56, 13, 20, 18}},
+		{name: "even digits use code set C", text: "0042", start: code128StartC, symbols: []int{0, 42}},
+		{name: "odd digits stay in code set B", text: "123", start: code128StartB, symbols: []int{17, 18, 19}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bars, err := Code128(tt.text)
+			require.NoError(t, err)
+
+			checksum := tt.start
+			for i, symbol := range tt.symbols {
+				checksum += (i + 1) * symbol
+			}
+			expected := append(append([]int{tt.start}, tt.symbols...), checksum%103, code128Stop)
+
+			assert.Equal(t, expected, readCode128(t, bars))
+		})
+	}
+}
+
+func TestCode128_Invalid(t *testing.T) {
+	for _, text := range []string{"", "tab\there", "café"} {
+		_, err := Code128(text)
+		assert.ErrorIs(t, err, ErrInvalidCode128, text)
+	}
+}
+
+func TestBars_Image(t *testing.T) {
+	bars, err := Code128("TIX-42")
+	require.NoError(t, err)
+
+	encoded, err := PNG(bars.Image(2, 50))
+	require.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(encoded))
+	require.NoError(t, err)
+	assert.Equal(t, (bars.Width()+2*Code128QuietZone)*2, img.Bounds().Dx())
+	assert.Equal(t, 50, img.Bounds().Dy())
+
+	// the quiet zone is light and the start symbol begins with a bar
+	r, _, _, _ := img.At(Code128QuietZone*2-1, 10).RGBA()
+	assert.Equal(t, uint32(0xFFFF), r)
+	r, _, _, _ = img.At(Code128QuietZone*2, 10).RGBA()
+	assert.Equal(t, uint32(0), r)
+}
+
+// readCode128 splits the modules of a barcode back into symbols
+func readCode128(t *testing.T, bars *Bars) []int {
+	t.Helper()
+
+	bySymbol := map[string]int{}
+	for symbol, pattern := range code128Patterns {
+		bySymbol[pattern] = symbol
+	}
+
+	var widths []byte
+	for x := 0; x < bars.Width(); {
+		run := 1
+		for x+run < bars.Width() && bars.Dark(x+run) == bars.Dark(x) {
+			run++
+		}
+		widths = append(widths, byte('0'+run))
+		x += run
+	}
+
+	var symbols []int
+	for len(widths) > 7 {
+		symbol, ok := bySymbol[string(widths[:6])]
+		require.True(t, ok, "unknown pattern %s", widths[:6])
+		symbols = append(symbols, symbol)
+		widths = widths[6:]
+	}
+	symbol, ok := bySymbol[string(widths)]
+	require.True(t, ok, "unknown stop pattern %s", widths)
+	return append(symbols, symbol)
+}
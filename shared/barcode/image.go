@@ -0,0 +1,64 @@
+package barcode
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// Image renders the QR code with scale pixels per module, surrounded by its quiet zone
+func (m *Matrix) Image(scale int) image.Image {
+	scale = max(scale, 1)
+	side := (m.size + 2*QRQuietZone) * scale
+
+	img := newLightImage(side, side)
+	for y := 0; y < m.size; y++ {
+		for x := 0; x < m.size; x++ {
+			if m.Dark(x, y) {
+				fillDark(img, (x+QRQuietZone)*scale, (y+QRQuietZone)*scale, scale, scale)
+			}
+		}
+	}
+	return img
+}
+
+// Image renders the barcode with scale pixels per module and bars height pixels tall, between its
+// quiet zones
+func (b *Bars) Image(scale, height int) image.Image {
+	scale = max(scale, 1)
+	height = max(height, 1)
+
+	img := newLightImage((len(b.modules)+2*Code128QuietZone)*scale, height)
+	for x, dark := range b.modules {
+		if dark {
+			fillDark(img, (x+Code128QuietZone)*scale, 0, scale, height)
+		}
+	}
+	return img
+}
+
+// PNG encodes a rendered QR code or barcode as PNG
+func PNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func newLightImage(width, height int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for i := range img.Pix {
+		img.Pix[i] = 0xFF
+	}
+	return img
+}
+
+func fillDark(img *image.Gray, x, y, width, height int) {
+	for yy := y; yy < y+height; yy++ {
+		for xx := x; xx < x+width; xx++ {
+			img.SetGray(xx, yy, color.Gray{})
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package barcode
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// PayloadVersion is the version of the payload format written by Seal, the first segment of every
+// payload: "<version>.<key ID>.<claims>.<signature>", claims being base64url JSON. A scanner rejects
+// versions it does not know rather than guessing at their layout.
+const PayloadVersion = "t1"
+
+var (
+	// ErrMalformedPayload is returned for payloads that are not made of the expected segments
+	ErrMalformedPayload = errors.New("barcode: malformed payload")
+	// ErrUnsupportedVersion is returned for payloads of a format version the keyring does not read
+	ErrUnsupportedVersion = errors.New("barcode: unsupported payload version")
+	// ErrUnknownKey is returned for payloads signed with a key the keyring does not hold
+	ErrUnknownKey = errors.New("barcode: unknown signing key")
+	// ErrInvalidSignature is returned for payloads whose signature does not match
+	ErrInvalidSignature = errors.New("barcode: invalid signature")
+)
+
+// Keyring signs payloads with its current key and verifies them with any key it holds. Rotating keys
+// is adding the new key as the current one and keeping the previous ones as verifiers until the
+// payloads they signed are no longer in circulation.
+type Keyring struct {
+	current   Signer
+	verifiers map[string]Verifier
+}
+
+// NewKeyring creates a keyring signing with current and also accepting the payloads of previous
+func NewKeyring(current Signer, previous ...Verifier) (*Keyring, error) {
+	if current == nil {
+		return nil, errors.New("barcode: a current signing key is required")
+	}
+
+	verifiers := map[string]Verifier{current.KeyID(): current}
+	for _, verifier := range previous {
+		if _, ok := verifiers[verifier.KeyID()]; ok {
+			return nil, fmt.Errorf("barcode: duplicate key ID %s", verifier.KeyID())
+		}
+		verifiers[verifier.KeyID()] = verifier
+	}
+
+	return &Keyring{current: current, verifiers: verifiers}, nil
+}
+
+// Seal encodes claims as JSON and signs them with the current key
+func (k *Keyring) Seal(claims any) (string, error) {
+	encoded, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode barcode claims: %w", err)
+	}
+
+	signed := strings.Join([]string{PayloadVersion, k.current.KeyID(), base64.RawURLEncoding.EncodeToString(encoded)}, ".")
+	signature, err := k.current.Sign([]byte(signed))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign barcode payload with key %s: %w", k.current.KeyID(), err)
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Open verifies the signature of a payload and decodes its claims into claims
+func (k *Keyring) Open(payload string, claims any) error {
+	parts := strings.Split(payload, ".")
+	if len(parts) != 4 {
+		return ErrMalformedPayload
+	}
+	version, keyID, encoded, encodedSignature := parts[0], parts[1], parts[2], parts[3]
+
+	if version != PayloadVersion {
+		return ErrUnsupportedVersion
+	}
+
+	verifier, ok := k.verifiers[keyID]
+	if !ok {
+		return ErrUnknownKey
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return ErrMalformedPayload
+	}
+	signed := payload[:len(payload)-len(encodedSignature)-1]
+	if !verifier.Verify([]byte(signed), signature) {
+		return ErrInvalidSignature
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ErrMalformedPayload
+	}
+	if err := json.Unmarshal(decoded, claims); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedPayload, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package barcode
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ticketClaims struct {
+	TicketID int64  `json:"tid"`
+	EventID  int64  `json:"eid"`
+	Holder   string `json:"h"`
+}
+
+func newHMACKey(t *testing.T, id string) *HMACKey {
+	t.Helper()
+
+	secret := make([]byte, 32)
+	_, err := rand.Read(secret)
+	require.NoError(t, err)
+
+	key, err := NewHMACKey(id, secret)
+	require.NoError(t, err)
+	return key
+}
+
+func newEd25519Signer(t *testing.T, id string) *Ed25519Signer {
+	t.Helper()
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	signer, err := NewEd25519Signer(id, privateKey)
+	require.NoError(t, err)
+	return signer
+}
+
+func TestKeyring_SealOpen(t *testing.T) {
+	signers := map[string]Signer{
+		"hmac":    newHMACKey(t, "h1"),
+		"ed25519": newEd25519Signer(t, "e1"),
+	}
+
+	for name, signer := range signers {
+		t.Run(name, func(t *testing.T) {
+			keyring, err := NewKeyring(signer)
+			require.NoError(t, err)
+
+			payload, err := keyring.Seal(ticketClaims{TicketID: 42, EventID: 7, Holder: "Jane"})
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(payload, PayloadVersion+"."+signer.KeyID()+"."))
+
+			var claims ticketClaims
+			require.NoError(t, keyring.Open(payload, &claims))
+			assert.Equal(t, ticketClaims{TicketID: 42, EventID: 7, Holder: "Jane"}, claims)
+		})
+	}
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	oldKey := newEd25519Signer(t, "2025")
+	newKey := newEd25519Signer(t, "2026")
+
+	oldKeyring, err := NewKeyring(oldKey)
+	require.NoError(t, err)
+	oldPayload, err := oldKeyring.Seal(ticketClaims{TicketID: 1})
+	require.NoError(t, err)
+
+	// scanners only hold the public half of the retired key
+	retired, err := NewEd25519Verifier("2025", oldKey.publicKey)
+	require.NoError(t, err)
+	keyring, err := NewKeyring(newKey, retired)
+	require.NoError(t, err)
+
+	var claims ticketClaims
+	require.NoError(t, keyring.Open(oldPayload, &claims))
+	assert.Equal(t, int64(1), claims.TicketID)
+
+	newPayload, err := keyring.Seal(ticketClaims{TicketID: 2})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(newPayload, PayloadVersion+".2026."))
+
+	// once the retired key is dropped, its payloads are refused
+	keyring, err = NewKeyring(newKey)
+	require.NoError(t, err)
+	assert.ErrorIs(t, keyring.Open(oldPayload, &claims), ErrUnknownKey)
+}
+
+func TestKeyring_Open_Rejects(t *testing.T) {
+	key := newHMACKey(t, "k1")
+	keyring, err := NewKeyring(key)
+	require.NoError(t, err)
+
+	payload, err := keyring.Seal(ticketClaims{TicketID: 42})
+	require.NoError(t, err)
+	parts := strings.Split(payload, ".")
+
+	forged, err := keyring.Seal(ticketClaims{TicketID: 43})
+	require.NoError(t, err)
+	forgedParts := strings.Split(forged, ".")
+
+	otherKeyring, err := NewKeyring(newHMACKey(t, "k1"))
+	require.NoError(t, err)
+	otherPayload, err := otherKeyring.Seal(ticketClaims{TicketID: 42})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		payload string
+		err     error
+	}{
+		{name: "missing segment", payload: strings.Join(parts[:3], "."), err: ErrMalformedPayload},
+		{name: "future version", payload: "t2." + strings.Join(parts[1:], "."), err: ErrUnsupportedVersion},
+		{name: "unknown key", payload: strings.Join([]string{parts[0], "k9", parts[2], parts[3]}, "."), err: ErrUnknownKey},
+		{name: "swapped claims", payload: strings.Join([]string{parts[0], parts[1], forgedParts[2], parts[3]}, "."), err: ErrInvalidSignature},
+		{name: "other secret", payload: otherPayload, err: ErrInvalidSignature},
+		{name: "undecodable signature", payload: strings.Join(append(parts[:3:3], "!"), "."), err: ErrMalformedPayload},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var claims ticketClaims
+			assert.ErrorIs(t, keyring.Open(tt.payload, &claims), tt.err)
+		})
+	}
+}
+
+func TestKeyring_PayloadFitsQR(t *testing.T) {
+	keyring, err := NewKeyring(newEd25519Signer(t, "2026"))
+	require.NoError(t, err)
+
+	payload, err := keyring.Seal(ticketClaims{TicketID: 123456789, EventID: 98765, Holder: "Jane Doe"})
+	require.NoError(t, err)
+
+	matrix, err := QR([]byte(payload), ECLevelM)
+	require.NoError(t, err)
+
+	_, data := readQR(t, matrix)
+	var claims ticketClaims
+	require.NoError(t, keyring.Open(string(data), &claims))
+	assert.Equal(t, int64(123456789), claims.TicketID)
+}
+
+func TestNewKeyring_Errors(t *testing.T) {
+	_, err := NewKeyring(nil)
+	assert.Error(t, err)
+
+	key := newHMACKey(t, "k1")
+	_, err = NewKeyring(key, newHMACKey(t, "k1"))
+	assert.Error(t, err)
+
+	_, err = NewHMACKey("k1", []byte("short"))
+	assert.Error(t, err)
+
+	_, err = NewHMACKey("k.1", make([]byte, 32))
+	assert.Error(t, err)
+}
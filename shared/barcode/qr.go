@@ -0,0 +1,567 @@
+// Package barcode draws the QR codes and Code 128 barcodes printed on tickets and scanned at check-in,
+// and signs the payloads they carry so scanners can tell a genuine ticket from a forged one.
+package barcode
+
+import "errors"
+
+// ECLevel is the error correction level of a QR code, how much of the symbol may be damaged and still scan
+type ECLevel int
+
+const (
+	// ECLevelL recovers about 7% of the symbol
+	ECLevelL ECLevel = iota
+	// ECLevelM recovers about 15% of the symbol, the usual choice for printed and screen tickets
+	ECLevelM
+	// ECLevelQ recovers about 25% of the symbol
+	ECLevelQ
+	// ECLevelH recovers about 30% of the symbol
+	ECLevelH
+)
+
+// formatBits are the bits of the level in the format information
+func (l ECLevel) formatBits() int {
+	return [...]int{1, 0, 3, 2}[l]
+}
+
+const (
+	minVersion = 1
+	maxVersion = 40
+
+	// QRQuietZone is the light margin around a QR code, in modules
+	QRQuietZone = 4
+)
+
+var (
+	// ErrDataTooLong is returned when the data does not fit the largest symbol
+	ErrDataTooLong = errors.New("barcode: data too long")
+	// ErrInvalidLevel is returned for unknown error correction levels
+	ErrInvalidLevel = errors.New("barcode: invalid error correction level")
+)
+
+// error correction codewords per block and number of blocks, by level then version (index 0 unused)
+var (
+	eccCodewordsPerBlock = [4][41]int{
+		{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+		{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+		{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+		{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	}
+	numErrorCorrectionBlocks = [4][41]int{
+		{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+		{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+		{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+		{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+	}
+)
+
+// Matrix is a QR code, a square of modules
+type Matrix struct {
+	size    int
+	modules []bool
+}
+
+// Size is the number of modules per side, without the quiet zone
+func (m *Matrix) Size() int {
+	return m.size
+}
+
+// Dark tells whether the module at column x and row y is dark
+func (m *Matrix) Dark(x, y int) bool {
+	return m.modules[y*m.size+x]
+}
+
+// QR encodes data in byte mode in the smallest QR code of the level that holds it
+func QR(data []byte, level ECLevel) (*Matrix, error) {
+	if level < ECLevelL || level > ECLevelH {
+		return nil, ErrInvalidLevel
+	}
+
+	version := 0
+	for v := minVersion; v <= maxVersion; v++ {
+		if 4+charCountBits(v)+len(data)*8 <= numDataCodewords(v, level)*8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrDataTooLong
+	}
+
+	// byte mode segment, terminator and padding up to the data capacity
+	var bits bitBuffer
+	bits.append(0b0100, 4)
+	bits.append(len(data), charCountBits(version))
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+	capacity := numDataCodewords(version, level) * 8
+	bits.append(0, min(4, capacity-bits.len()))
+	bits.append(0, (8-bits.len()%8)%8)
+	for pad := 0xEC; bits.len() < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+
+	b := newQRBuilder(version, level)
+	b.drawFunctionPatterns()
+	b.drawCodewords(addECAndInterleave(bits.bytes(), version, level))
+
+	bestMask, minPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		b.applyMask(mask)
+		b.drawFormatBits(mask)
+		if penalty := b.penalty(); minPenalty < 0 || penalty < minPenalty {
+			bestMask, minPenalty = mask, penalty
+		}
+		// masking twice undoes it
+		b.applyMask(mask)
+	}
+	b.applyMask(bestMask)
+	b.drawFormatBits(bestMask)
+
+	return &Matrix{size: b.size, modules: b.modules}, nil
+}
+
+// charCountBits is the length of the character count of byte mode segments
+func charCountBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+// numRawDataModules is the number of modules left for data and error correction once the function
+// patterns are drawn
+func numRawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		numAlign := version/7 + 2
+		result -= (25*numAlign-10)*numAlign - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+// numDataCodewords is the number of data codewords of a symbol, error correction excluded
+func numDataCodewords(version int, level ECLevel) int {
+	return numRawDataModules(version)/8 - eccCodewordsPerBlock[level][version]*numErrorCorrectionBlocks[level][version]
+}
+
+// addECAndInterleave splits data in blocks, appends the error correction of each and interleaves them
+func addECAndInterleave(data []byte, version int, level ECLevel) []byte {
+	numBlocks := numErrorCorrectionBlocks[level][version]
+	blockECLen := eccCodewordsPerBlock[level][version]
+	rawCodewords := numRawDataModules(version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortBlockLen := rawCodewords / numBlocks
+
+	divisor := reedSolomonDivisor(blockECLen)
+	blocks := make([][]byte, numBlocks)
+	for i, k := 0, 0; i < numBlocks; i++ {
+		dataLen := shortBlockLen - blockECLen
+		if i >= numShortBlocks {
+			dataLen++
+		}
+		block := append([]byte(nil), data[k:k+dataLen]...)
+		k += dataLen
+		ec := reedSolomonRemainder(block, divisor)
+		if i < numShortBlocks {
+			// placeholder keeping the error correction of short and long blocks aligned, skipped below
+			block = append(block, 0)
+		}
+		blocks[i] = append(block, ec...)
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := range blocks[0] {
+		for j, block := range blocks {
+			if i != shortBlockLen-blockECLen || j >= numShortBlocks {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+// reedSolomonDivisor returns the generator polynomial of the given degree, highest coefficient first
+// and the leading 1 omitted
+func reedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+// reedSolomonRemainder returns the error correction codewords of data
+func reedSolomonRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, coef := range divisor {
+			result[i] ^= gfMultiply(coef, factor)
+		}
+	}
+	return result
+}
+
+// gfMultiply multiplies in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1
+func gfMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>i)&1) * int(x)
+	}
+	return byte(z)
+}
+
+// qrBuilder draws a symbol, tracking which modules belong to function patterns
+type qrBuilder struct {
+	version    int
+	level      ECLevel
+	size       int
+	modules    []bool
+	isFunction []bool
+}
+
+func newQRBuilder(version int, level ECLevel) *qrBuilder {
+	size := version*4 + 17
+	return &qrBuilder{
+		version:    version,
+		level:      level,
+		size:       size,
+		modules:    make([]bool, size*size),
+		isFunction: make([]bool, size*size),
+	}
+}
+
+func (b *qrBuilder) setFunction(x, y int, dark bool) {
+	b.modules[y*b.size+x] = dark
+	b.isFunction[y*b.size+x] = true
+}
+
+// drawFunctionPatterns draws the finder, timing and alignment patterns and reserves the format and
+// version information
+func (b *qrBuilder) drawFunctionPatterns() {
+	for i := 0; i < b.size; i++ {
+		b.setFunction(6, i, i%2 == 0)
+		b.setFunction(i, 6, i%2 == 0)
+	}
+
+	b.drawFinderPattern(3, 3)
+	b.drawFinderPattern(b.size-4, 3)
+	b.drawFinderPattern(3, b.size-4)
+
+	positions := b.alignmentPositions()
+	n := len(positions)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			// the corners taken by finder patterns
+			if (i == 0 && j == 0) || (i == 0 && j == n-1) || (i == n-1 && j == 0) {
+				continue
+			}
+			b.drawAlignmentPattern(positions[i], positions[j])
+		}
+	}
+
+	b.drawFormatBits(0)
+	b.drawVersion()
+}
+
+// drawFinderPattern draws a finder pattern and its separator centered on x, y
+func (b *qrBuilder) drawFinderPattern(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= b.size || yy < 0 || yy >= b.size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			b.setFunction(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+// drawAlignmentPattern draws an alignment pattern centered on x, y
+func (b *qrBuilder) drawAlignmentPattern(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			b.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// alignmentPositions returns the rows and columns of the centers of the alignment patterns
+func (b *qrBuilder) alignmentPositions() []int {
+	if b.version == 1 {
+		return nil
+	}
+
+	numAlign := b.version/7 + 2
+	step := 26
+	if b.version != 32 {
+		step = (b.version*4 + numAlign*2 + 1) / (numAlign*2 - 2) * 2
+	}
+
+	result := make([]int, numAlign)
+	result[0] = 6
+	for i, pos := numAlign-1, b.size-7; i >= 1; i, pos = i-1, pos-step {
+		result[i] = pos
+	}
+	return result
+}
+
+// drawFormatBits draws both copies of the format information of the level and mask, and the dark module
+func (b *qrBuilder) drawFormatBits(mask int) {
+	bits := formatInfo(b.level, mask)
+
+	for i := 0; i <= 5; i++ {
+		b.setFunction(8, i, bit(bits, i))
+	}
+	b.setFunction(8, 7, bit(bits, 6))
+	b.setFunction(8, 8, bit(bits, 7))
+	b.setFunction(7, 8, bit(bits, 8))
+	for i := 9; i < 15; i++ {
+		b.setFunction(14-i, 8, bit(bits, i))
+	}
+
+	for i := 0; i < 8; i++ {
+		b.setFunction(b.size-1-i, 8, bit(bits, i))
+	}
+	for i := 8; i < 15; i++ {
+		b.setFunction(8, b.size-15+i, bit(bits, i))
+	}
+	b.setFunction(8, b.size-8, true)
+}
+
+// drawVersion draws both copies of the version information of symbols from version 7
+func (b *qrBuilder) drawVersion() {
+	if b.version < 7 {
+		return
+	}
+
+	bits := versionInfo(b.version)
+	for i := 0; i < 18; i++ {
+		dark := bit(bits, i)
+		x, y := b.size-11+i%3, i/3
+		b.setFunction(x, y, dark)
+		b.setFunction(y, x, dark)
+	}
+}
+
+// formatInfo is the 15 bit format information of a level and mask, BCH protected and masked
+func formatInfo(level ECLevel, mask int) int {
+	data := level.formatBits()<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+// versionInfo is the 18 bit version information, BCH protected
+func versionInfo(version int) int {
+	rem := version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	return version<<12 | rem
+}
+
+// drawCodewords places the codewords in the zigzag of two module wide columns, from the bottom right
+func (b *qrBuilder) drawCodewords(data []byte) {
+	i := 0
+	for right := b.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			// the vertical timing pattern
+			right = 5
+		}
+		for vert := 0; vert < b.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = b.size - 1 - vert
+				}
+				if !b.isFunction[y*b.size+x] && i < len(data)*8 {
+					b.modules[y*b.size+x] = bit(int(data[i>>3]), 7-i&7)
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask flips the data modules selected by a mask pattern
+func (b *qrBuilder) applyMask(mask int) {
+	for y := 0; y < b.size; y++ {
+		for x := 0; x < b.size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !b.isFunction[y*b.size+x] {
+				b.modules[y*b.size+x] = !b.modules[y*b.size+x]
+			}
+		}
+	}
+}
+
+// penalty scores how hard the symbol is to scan, the mask with the lowest score is kept
+func (b *qrBuilder) penalty() int {
+	const (
+		penaltyRun       = 3
+		penaltyBlock     = 3
+		penaltyFinder    = 40
+		penaltyImbalance = 10
+	)
+
+	result := 0
+	line := make([]bool, b.size)
+	for _, horizontal := range []bool{true, false} {
+		for i := 0; i < b.size; i++ {
+			for j := 0; j < b.size; j++ {
+				if horizontal {
+					line[j] = b.modules[i*b.size+j]
+				} else {
+					line[j] = b.modules[j*b.size+i]
+				}
+			}
+
+			// runs of five or more modules of the same color
+			run := 1
+			for j := 1; j <= b.size; j++ {
+				if j < b.size && line[j] == line[j-1] {
+					run++
+					continue
+				}
+				if run >= 5 {
+					result += penaltyRun + run - 5
+				}
+				run = 1
+			}
+
+			result += penaltyFinder * countFinderLike(line)
+		}
+	}
+
+	// two by two blocks of the same color
+	for y := 0; y < b.size-1; y++ {
+		for x := 0; x < b.size-1; x++ {
+			dark := b.modules[y*b.size+x]
+			if dark == b.modules[y*b.size+x+1] && dark == b.modules[(y+1)*b.size+x] && dark == b.modules[(y+1)*b.size+x+1] {
+				result += penaltyBlock
+			}
+		}
+	}
+
+	// share of dark modules away from half
+	dark := 0
+	for _, module := range b.modules {
+		if module {
+			dark++
+		}
+	}
+	total := len(b.modules)
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	result += k * penaltyImbalance
+
+	return result
+}
+
+// finderLike is the dark:light:dark:dark:dark:light:dark pattern of a finder, scanned with four light
+// modules on one of its sides
+var finderLike = []bool{true, false, true, true, true, false, true}
+
+// countFinderLike counts the finder-like patterns of a line, outside the line counting as light
+func countFinderLike(line []bool) int {
+	at := func(i int) bool {
+		return i >= 0 && i < len(line) && line[i]
+	}
+	lightRun := func(from int) bool {
+		for i := from; i < from+4; i++ {
+			if at(i) {
+				return false
+			}
+		}
+		return true
+	}
+
+	count := 0
+	for start := 0; start+len(finderLike) <= len(line); start++ {
+		matches := true
+		for i, dark := range finderLike {
+			if line[start+i] != dark {
+				matches = false
+				break
+			}
+		}
+		if matches && (lightRun(start-4) || lightRun(start+len(finderLike))) {
+			count++
+		}
+	}
+	return count
+}
+
+// bitBuffer accumulates bits, most significant first
+type bitBuffer struct {
+	bits []bool
+}
+
+func (b *bitBuffer) append(value, length int) {
+	for i := length - 1; i >= 0; i-- {
+		b.bits = append(b.bits, bit(value, i))
+	}
+}
+
+func (b *bitBuffer) len() int {
+	return len(b.bits)
+}
+
+func (b *bitBuffer) bytes() []byte {
+	result := make([]byte, len(b.bits)/8)
+	for i, set := range b.bits {
+		if set {
+			result[i/8] |= 1 << (7 - i%8)
+		}
+	}
+	return result
+}
+
+func bit(value, i int) bool {
+	return (value>>i)&1 != 0
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
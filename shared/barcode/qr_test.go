@@ -0,0 +1,223 @@
+package barcode
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReedSolomonRemainder(t *testing.T) {
+	// version 1-M codewords of "HELLO WORLD" and their error correction
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+
+	ec := reedSolomonRemainder(data, reedSolomonDivisor(10))
+
+	assert.Equal(t, []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}, ec)
+}
+
+func TestFormatInfo(t *testing.T) {
+	assert.Equal(t, 0b111011111000100, formatInfo(ECLevelL, 0))
+	assert.Equal(t, 0b101010000010010, formatInfo(ECLevelM, 0))
+	assert.Equal(t, 0b011010101011111, formatInfo(ECLevelQ, 0))
+	assert.Equal(t, 0b001011010001001, formatInfo(ECLevelH, 0))
+	assert.Equal(t, 0b110011000101111, formatInfo(ECLevelL, 4))
+}
+
+func TestVersionInfo(t *testing.T) {
+	assert.Equal(t, 0b000111110010010100, versionInfo(7))
+	assert.Equal(t, 0b101000110001101001, versionInfo(40))
+}
+
+func TestByteCapacity(t *testing.T) {
+	capacity := func(version int, level ECLevel) int {
+		return (numDataCodewords(version, level)*8 - 4 - charCountBits(version)) / 8
+	}
+
+	assert.Equal(t, 17, capacity(1, ECLevelL))
+	assert.Equal(t, 14, capacity(1, ECLevelM))
+	assert.Equal(t, 11, capacity(1, ECLevelQ))
+	assert.Equal(t, 7, capacity(1, ECLevelH))
+	assert.Equal(t, 122, capacity(7, ECLevelM))
+	assert.Equal(t, 213, capacity(10, ECLevelM))
+	assert.Equal(t, 2953, capacity(40, ECLevelL))
+	assert.Equal(t, 1273, capacity(40, ECLevelH))
+}
+
+func TestQR_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		level ECLevel
+	}{
+		{name: "single block", data: "hello", level: ECLevelM},
+		{name: "fills version 1", data: strings.Repeat("x", 14), level: ECLevelM},
+		{name: "short and long blocks", data: strings.Repeat("tixgo ", 20), level: ECLevelQ},
+		{name: "version information", data: strings.Repeat("t1.key.payload", 12), level: ECLevelH},
+		{name: "sixteen bit length", data: strings.Repeat("0123456789", 40), level: ECLevelL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matrix, err := QR([]byte(tt.data), tt.level)
+			require.NoError(t, err)
+
+			level, data := readQR(t, matrix)
+			assert.Equal(t, tt.level, level)
+			assert.Equal(t, tt.data, string(data))
+		})
+	}
+}
+
+func TestQR_SmallestVersion(t *testing.T) {
+	matrix, err := QR(bytes.Repeat([]byte{'a'}, 14), ECLevelM)
+	require.NoError(t, err)
+	assert.Equal(t, 21, matrix.Size())
+
+	matrix, err = QR(bytes.Repeat([]byte{'a'}, 15), ECLevelM)
+	require.NoError(t, err)
+	assert.Equal(t, 25, matrix.Size())
+}
+
+func TestQR_Errors(t *testing.T) {
+	_, err := QR(make([]byte, 2954), ECLevelL)
+	assert.ErrorIs(t, err, ErrDataTooLong)
+
+	_, err = QR([]byte("x"), ECLevel(7))
+	assert.ErrorIs(t, err, ErrInvalidLevel)
+}
+
+func TestMatrix_Image(t *testing.T) {
+	matrix, err := QR([]byte("hello"), ECLevelM)
+	require.NoError(t, err)
+
+	encoded, err := PNG(matrix.Image(4))
+	require.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(encoded))
+	require.NoError(t, err)
+	assert.Equal(t, (21+2*QRQuietZone)*4, img.Bounds().Dx())
+
+	// the quiet zone is light and the finder pattern corner dark
+	r, _, _, _ := img.At(0, 0).RGBA()
+	assert.Equal(t, uint32(0xFFFF), r)
+	r, _, _, _ = img.At(QRQuietZone*4, QRQuietZone*4).RGBA()
+	assert.Equal(t, uint32(0), r)
+}
+
+// readQR decodes a symbol written by QR: format information, unmasking, codeword order,
+// de-interleaving with the error correction checked, then the byte mode segment
+func readQR(t *testing.T, matrix *Matrix) (ECLevel, []byte) {
+	t.Helper()
+
+	size := matrix.Size()
+	version := (size - 17) / 4
+
+	format := 0
+	for i := 0; i < 8; i++ {
+		if matrix.Dark(size-1-i, 8) {
+			format |= 1 << i
+		}
+	}
+	for i := 8; i < 15; i++ {
+		if matrix.Dark(8, size-15+i) {
+			format |= 1 << i
+		}
+	}
+
+	var (
+		level ECLevel
+		mask  = -1
+	)
+	for l := ECLevelL; l <= ECLevelH; l++ {
+		for m := 0; m < 8; m++ {
+			if formatInfo(l, m) == format {
+				level, mask = l, m
+			}
+		}
+	}
+	require.NotEqual(t, -1, mask, "format information")
+
+	b := newQRBuilder(version, level)
+	b.drawFunctionPatterns()
+	b.drawFormatBits(mask)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if b.isFunction[y*size+x] {
+				require.Equal(t, b.modules[y*size+x], matrix.Dark(x, y), "function module %d,%d", x, y)
+			}
+		}
+	}
+	copy(b.modules, matrix.modules)
+	b.applyMask(mask)
+
+	var bits bitBuffer
+	for right := size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = size - 1 - vert
+				}
+				if !b.isFunction[y*size+x] {
+					value := 0
+					if b.modules[y*size+x] {
+						value = 1
+					}
+					bits.append(value, 1)
+				}
+			}
+		}
+	}
+	codewords := bits.bytes()[:numRawDataModules(version)/8]
+
+	numBlocks := numErrorCorrectionBlocks[level][version]
+	blockECLen := eccCodewordsPerBlock[level][version]
+	numShortBlocks := numBlocks - len(codewords)%numBlocks
+	shortDataLen := len(codewords)/numBlocks - blockECLen
+
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := 0; i <= shortDataLen; i++ {
+		for j := range blocks {
+			if i < shortDataLen || j >= numShortBlocks {
+				blocks[j] = append(blocks[j], codewords[k])
+				k++
+			}
+		}
+	}
+	var data []byte
+	for i := 0; i < blockECLen; i++ {
+		for j := range blocks {
+			blocks[j] = append(blocks[j], codewords[k])
+			k++
+		}
+	}
+	for _, block := range blocks {
+		dataLen := len(block) - blockECLen
+		require.Equal(t, block[dataLen:], reedSolomonRemainder(block[:dataLen], reedSolomonDivisor(blockECLen)))
+		data = append(data, block[:dataLen]...)
+	}
+
+	readBits := func(offset, length int) int {
+		value := 0
+		for i := offset; i < offset+length; i++ {
+			value = value<<1 | int(data[i/8]>>(7-i%8)&1)
+		}
+		return value
+	}
+	require.Equal(t, 0b0100, readBits(0, 4), "byte mode")
+	length := readBits(4, charCountBits(version))
+
+	result := make([]byte, length)
+	for i := range result {
+		result[i] = byte(readBits(4+charCountBits(version)+i*8, 8))
+	}
+	return level, result
+}
@@ -0,0 +1,127 @@
+package barcode
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+)
+
+// minHMACSecretLength is the shortest HMAC secret accepted, the size of the SHA-256 output
+const minHMACSecretLength = 32
+
+// Verifier checks the signatures made with one key. The key ID travels in every payload so the
+// verifier can be found again after the signing key is rotated.
+type Verifier interface {
+	// KeyID identifies the key
+	KeyID() string
+
+	// Verify tells whether signature is a signature of message by the key
+	Verify(message, signature []byte) bool
+}
+
+// Signer signs payloads with one key and verifies its own signatures
+type Signer interface {
+	Verifier
+
+	// Sign signs message
+	Sign(message []byte) ([]byte, error)
+}
+
+// HMACKey signs with HMAC-SHA256. Anyone able to verify its signatures can also make them, so it
+// suits scanners run by the platform itself.
+type HMACKey struct {
+	id     string
+	secret []byte
+}
+
+// NewHMACKey creates an HMAC key from a secret of at least 32 bytes
+func NewHMACKey(id string, secret []byte) (*HMACKey, error) {
+	if err := validateKeyID(id); err != nil {
+		return nil, err
+	}
+	if len(secret) < minHMACSecretLength {
+		return nil, fmt.Errorf("barcode: HMAC secret of key %s must be at least %d bytes", id, minHMACSecretLength)
+	}
+	return &HMACKey{id: id, secret: append([]byte(nil), secret...)}, nil
+}
+
+// KeyID identifies the key
+func (k *HMACKey) KeyID() string {
+	return k.id
+}
+
+// Sign signs message
+func (k *HMACKey) Sign(message []byte) ([]byte, error) {
+	mac := hmac.New(sha256.New, k.secret)
+	mac.Write(message)
+	return mac.Sum(nil), nil
+}
+
+// Verify tells whether signature is the HMAC of message
+func (k *HMACKey) Verify(message, signature []byte) bool {
+	expected, _ := k.Sign(message)
+	return hmac.Equal(expected, signature)
+}
+
+// Ed25519Signer signs with an Ed25519 private key, whose public half can be handed to scanners that
+// must not be able to make tickets
+type Ed25519Signer struct {
+	Ed25519Verifier
+	privateKey ed25519.PrivateKey
+}
+
+// NewEd25519Signer creates a signer from an Ed25519 private key
+func NewEd25519Signer(id string, privateKey ed25519.PrivateKey) (*Ed25519Signer, error) {
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("barcode: Ed25519 private key of key %s must be %d bytes", id, ed25519.PrivateKeySize)
+	}
+
+	verifier, err := NewEd25519Verifier(id, privateKey.Public().(ed25519.PublicKey))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Ed25519Signer{Ed25519Verifier: *verifier, privateKey: privateKey}, nil
+}
+
+// Sign signs message
+func (s *Ed25519Signer) Sign(message []byte) ([]byte, error) {
+	return ed25519.Sign(s.privateKey, message), nil
+}
+
+// Ed25519Verifier verifies signatures with an Ed25519 public key
+type Ed25519Verifier struct {
+	id        string
+	publicKey ed25519.PublicKey
+}
+
+// NewEd25519Verifier creates a verifier from an Ed25519 public key
+func NewEd25519Verifier(id string, publicKey ed25519.PublicKey) (*Ed25519Verifier, error) {
+	if err := validateKeyID(id); err != nil {
+		return nil, err
+	}
+	if len(publicKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("barcode: Ed25519 public key of key %s must be %d bytes", id, ed25519.PublicKeySize)
+	}
+	return &Ed25519Verifier{id: id, publicKey: publicKey}, nil
+}
+
+// KeyID identifies the key
+func (v *Ed25519Verifier) KeyID() string {
+	return v.id
+}
+
+// Verify tells whether signature is an Ed25519 signature of message
+func (v *Ed25519Verifier) Verify(message, signature []byte) bool {
+	return len(signature) == ed25519.SignatureSize && ed25519.Verify(v.publicKey, message, signature)
+}
+
+// validateKeyID rejects key IDs that would break the segments of a payload
+func validateKeyID(id string) error {
+	if id == "" || strings.Contains(id, ".") {
+		return fmt.Errorf("barcode: key ID %q must be non-empty and without dots", id)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrMiss is returned by Get when the key is not cached
+var ErrMiss = errors.New("cache miss")
+
+// Config configures the two cache tiers
+type Config struct {
+	// RedisTTL is how long an entry lives in the shared redis tier
+	RedisTTL time.Duration
+	// LocalTTL is how long an entry lives in the in-process tier. Invalidations only reach the
+	// local tier of the instance that made them, so keep it short: it bounds how stale other
+	// instances can be after an update.
+	LocalTTL time.Duration
+	// LocalSize is the maximum number of entries of the in-process tier
+	LocalSize int
+}
+
+// DefaultConfig returns the cache settings used for entity lookups
+func DefaultConfig() Config {
+	return Config{
+		RedisTTL:  5 * time.Minute,
+		LocalTTL:  10 * time.Second,
+		LocalSize: 10000,
+	}
+}
+
+// Cache is a read-through cache with an in-process LRU in front of redis.
+// Values are stored JSON encoded, so callers always get their own copy.
+type Cache struct {
+	redis redis.UniversalClient
+	local *lru
+	cfg   Config
+}
+
+// New creates a cache
+func New(redisClient redis.UniversalClient, cfg Config) *Cache {
+	return &Cache{
+		redis: redisClient,
+		local: newLRU(cfg.LocalSize, cfg.LocalTTL),
+		cfg:   cfg,
+	}
+}
+
+// Get decodes the cached value of key into dest, or returns ErrMiss
+func (c *Cache) Get(ctx context.Context, key string, dest any) error {
+	data, ok := c.local.get(key)
+	if !ok {
+		var err error
+		data, err = c.redis.Get(ctx, redisKey(key)).Bytes()
+		if errors.Is(err, redis.Nil) {
+			return ErrMiss
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get %s from redis: %w", key, err)
+		}
+		c.local.set(key, data)
+	}
+
+	if err := json.Unmarshal(data, dest); err != nil {
+		c.local.delete(key)
+		return fmt.Errorf("failed to decode cached %s: %w", key, err)
+	}
+	return nil
+}
+
+// Set caches value under key in both tiers
+func (c *Cache) Set(ctx context.Context, key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", key, err)
+	}
+
+	c.local.set(key, data)
+	if err := c.redis.Set(ctx, redisKey(key), data, c.cfg.RedisTTL).Err(); err != nil {
+		return fmt.Errorf("failed to set %s in redis: %w", key, err)
+	}
+	return nil
+}
+
+// Delete invalidates keys in both tiers
+func (c *Cache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	redisKeys := make([]string, len(keys))
+	for i, key := range keys {
+		c.local.delete(key)
+		redisKeys[i] = redisKey(key)
+	}
+
+	if err := c.redis.Del(ctx, redisKeys...).Err(); err != nil {
+		return fmt.Errorf("failed to delete %v from redis: %w", keys, err)
+	}
+	return nil
+}
+
+// GetOrLoad returns the cached value of key, calling load and caching its result on a miss.
+// Cache failures are logged and fall back to load, so an unavailable redis only costs latency.
+// Errors of load (including not found) are returned as is and never cached.
+func GetOrLoad[T any](ctx context.Context, c *Cache, key string, load func(ctx context.Context) (*T, error)) (*T, error) {
+	value := new(T)
+	err := c.Get(ctx, key, value)
+	if err == nil {
+		return value, nil
+	}
+	if !errors.Is(err, ErrMiss) {
+		logger.Warning(ctx, "Cache lookup failed", logger.F("key", key), logger.F("error", err))
+	}
+
+	value, err = load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.Set(ctx, key, value); err != nil {
+		logger.Warning(ctx, "Cache fill failed", logger.F("key", key), logger.F("error", err))
+	}
+	return value, nil
+}
+
+func redisKey(key string) string {
+	return "cache:" + key
+}
@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/duongptryu/gox/logger"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init(&logger.Config{Output: io.Discard})
+	os.Exit(m.Run())
+}
+
+type item struct {
+	ID   int64
+	Name string
+}
+
+func newTestCache(t *testing.T) (*Cache, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return New(client, DefaultConfig()), server
+}
+
+func countingLoader(calls *int, value *item, err error) func(context.Context) (*item, error) {
+	return func(context.Context) (*item, error) {
+		*calls++
+		return value, err
+	}
+}
+
+func TestGetOrLoad(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("loads once and serves hits from the cache", func(t *testing.T) {
+		c, server := newTestCache(t)
+		calls := 0
+		load := countingLoader(&calls, &item{ID: 1, Name: "one"}, nil)
+
+		for i := 0; i < 3; i++ {
+			got, err := GetOrLoad(ctx, c, "item:1", load)
+			require.NoError(t, err)
+			assert.Equal(t, &item{ID: 1, Name: "one"}, got)
+		}
+		assert.Equal(t, 1, calls)
+		assert.True(t, server.Exists("cache:item:1"))
+	})
+
+	t.Run("falls back to redis when the local tier expired", func(t *testing.T) {
+		c, _ := newTestCache(t)
+		calls := 0
+		load := countingLoader(&calls, &item{ID: 1}, nil)
+
+		_, err := GetOrLoad(ctx, c, "item:1", load)
+		require.NoError(t, err)
+		c.local.now = func() time.Time { return time.Now().Add(time.Minute) }
+
+		_, err = GetOrLoad(ctx, c, "item:1", load)
+		require.NoError(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("does not cache load errors", func(t *testing.T) {
+		c, _ := newTestCache(t)
+		calls := 0
+		notFound := errors.New("not found")
+		load := countingLoader(&calls, nil, notFound)
+
+		_, err := GetOrLoad(ctx, c, "item:1", load)
+		assert.ErrorIs(t, err, notFound)
+		_, err = GetOrLoad(ctx, c, "item:1", load)
+		assert.ErrorIs(t, err, notFound)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("loads from the source when redis is down", func(t *testing.T) {
+		c, server := newTestCache(t)
+		server.Close()
+		calls := 0
+
+		got, err := GetOrLoad(ctx, c, "item:1", countingLoader(&calls, &item{ID: 1}, nil))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), got.ID)
+	})
+}
+
+func TestCache_Delete(t *testing.T) {
+	ctx := context.Background()
+	c, server := newTestCache(t)
+	require.NoError(t, c.Set(ctx, "item:1", &item{ID: 1}))
+
+	require.NoError(t, c.Delete(ctx, "item:1"))
+
+	assert.ErrorIs(t, c.Get(ctx, "item:1", &item{}), ErrMiss)
+	assert.False(t, server.Exists("cache:item:1"))
+}
+
+func TestLRU_Evicts(t *testing.T) {
+	l := newLRU(2, time.Minute)
+	l.set("a", []byte("a"))
+	l.set("b", []byte("b"))
+	l.get("a")
+	l.set("c", []byte("c"))
+
+	_, ok := l.get("b")
+	assert.False(t, ok, "least recently used entry is evicted")
+	_, ok = l.get("a")
+	assert.True(t, ok)
+}
@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// lru is a size bounded in-process cache whose entries also expire after a ttl
+type lru struct {
+	mu       sync.Mutex
+	size     int
+	ttl      time.Duration
+	order    *list.List
+	elements map[string]*list.Element
+	now      func() time.Time
+}
+
+func newLRU(size int, ttl time.Duration) *lru {
+	return &lru{
+		size:     size,
+		ttl:      ttl,
+		order:    list.New(),
+		elements: make(map[string]*list.Element),
+		now:      time.Now,
+	}
+}
+
+func (l *lru) get(key string) ([]byte, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	element, ok := l.elements[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*lruEntry)
+	if l.now().After(entry.expiresAt) {
+		l.removeElement(element)
+		return nil, false
+	}
+
+	l.order.MoveToFront(element)
+	return entry.value, true
+}
+
+func (l *lru) set(key string, value []byte) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	expiresAt := l.now().Add(l.ttl)
+	if element, ok := l.elements[key]; ok {
+		entry := element.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		l.order.MoveToFront(element)
+		return
+	}
+
+	l.elements[key] = l.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	for l.order.Len() > l.size {
+		l.removeElement(l.order.Back())
+	}
+}
+
+func (l *lru) delete(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if element, ok := l.elements[key]; ok {
+		l.removeElement(element)
+	}
+}
+
+func (l *lru) removeElement(element *list.Element) {
+	l.order.Remove(element)
+	delete(l.elements, element.Value.(*lruEntry).key)
+}
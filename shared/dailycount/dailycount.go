@@ -0,0 +1,75 @@
+// Package dailycount counts occurrences by UTC day in redis, so every instance adds to the same count
+// and it outlives restarts, for dashboards reading what happened today. Counts are kept a week.
+package dailycount
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	redisKeyPrefix = "dailycount:"
+
+	// retention is how long the count of a day is kept after its first occurrence
+	retention = 8 * 24 * time.Hour
+)
+
+// Counter counts the occurrences of one thing by day
+type Counter struct {
+	client redis.UniversalClient
+	name   string
+	now    func() time.Time
+}
+
+// New creates the counter of name
+func New(client redis.UniversalClient, name string) *Counter {
+	return &Counter{
+		client: client,
+		name:   name,
+		now:    time.Now,
+	}
+}
+
+func (c *Counter) key(day time.Time) string {
+	return redisKeyPrefix + c.name + ":" + day.UTC().Format(time.DateOnly)
+}
+
+// Incr counts an occurrence today
+func (c *Counter) Incr(ctx context.Context) error {
+	key := c.key(c.now())
+
+	pipe := c.client.TxPipeline()
+	pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, retention)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Get returns the occurrences of the UTC day of day, 0 for days without any or past the retention
+func (c *Counter) Get(ctx context.Context, day time.Time) (int64, error) {
+	count, err := c.client.Get(ctx, c.key(day)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return count, err
+}
+
+// CountFailures wraps a message handler to count the messages it fails to handle, every attempt of a
+// message retried by the bus included. Failing to count is only logged.
+func CountFailures[T any](counter *Counter, handle func(ctx context.Context, message T) error) func(ctx context.Context, message T) error {
+	return func(ctx context.Context, message T) error {
+		err := handle(ctx, message)
+		if err == nil {
+			return nil
+		}
+
+		if countErr := counter.Incr(ctx); countErr != nil {
+			logger.Warning(ctx, "Failed to count a failure", logger.F("counter", counter.name), logger.F("error", countErr))
+		}
+		return err
+	}
+}
@@ -0,0 +1,59 @@
+package dailycount
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/duongptryu/gox/logger"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init(&logger.Config{Output: io.Discard})
+	os.Exit(m.Run())
+}
+
+func TestCounter(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	now := time.Date(2026, 11, 1, 23, 30, 0, 0, time.UTC)
+	counter := New(client, "sends_failed")
+	counter.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	handle := CountFailures(counter, func(_ context.Context, fail bool) error {
+		if fail {
+			return errors.New("provider unreachable")
+		}
+		return nil
+	})
+	require.NoError(t, handle(ctx, false))
+	require.Error(t, handle(ctx, true))
+	require.Error(t, handle(ctx, true))
+
+	now = now.Add(time.Hour)
+	require.Error(t, handle(ctx, true))
+
+	count, err := counter.Get(ctx, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count, "only failures are counted")
+
+	count, err = counter.Get(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count, "a new count starts at midnight UTC")
+
+	assert.Equal(t, retention, server.TTL("dailycount:sends_failed:2026-11-02"))
+
+	count, err = counter.Get(ctx, now.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
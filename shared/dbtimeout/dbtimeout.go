@@ -0,0 +1,36 @@
+package dbtimeout
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultQueryTimeout bounds a repository call when database.query_timeout is not configured
+const DefaultQueryTimeout = 5 * time.Second
+
+var queryTimeout atomic.Int64
+
+func init() {
+	queryTimeout.Store(int64(DefaultQueryTimeout))
+}
+
+// SetQueryTimeout changes the bound of every repository call, a non-positive d restores the default
+func SetQueryTimeout(d time.Duration) {
+	if d <= 0 {
+		d = DefaultQueryTimeout
+	}
+	queryTimeout.Store(int64(d))
+}
+
+// QueryTimeout returns the bound of a repository call
+func QueryTimeout() time.Duration {
+	return time.Duration(queryTimeout.Load())
+}
+
+// WithQueryTimeout bounds ctx by the query timeout so a stuck Postgres call gives its connection and its caller back.
+// An earlier deadline of the caller, e.g. a bus handler deadline, still wins.
+// Repositories call it first and defer cancel, which covers the pool wait, every statement and the transaction.
+func WithQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, QueryTimeout())
+}
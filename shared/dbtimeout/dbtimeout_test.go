@@ -0,0 +1,59 @@
+package dbtimeout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithQueryTimeout(t *testing.T) {
+	t.Cleanup(func() { SetQueryTimeout(0) })
+
+	t.Run("bounds the context by the query timeout", func(t *testing.T) {
+		SetQueryTimeout(time.Second)
+
+		ctx, cancel := WithQueryTimeout(context.Background())
+		defer cancel()
+
+		deadline, ok := ctx.Deadline()
+		require.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(time.Second), deadline, 100*time.Millisecond)
+	})
+
+	t.Run("keeps an earlier deadline of the caller", func(t *testing.T) {
+		SetQueryTimeout(time.Minute)
+
+		parent, cancelParent := context.WithTimeout(context.Background(), time.Second)
+		defer cancelParent()
+
+		ctx, cancel := WithQueryTimeout(parent)
+		defer cancel()
+
+		parentDeadline, _ := parent.Deadline()
+		deadline, _ := ctx.Deadline()
+		assert.Equal(t, parentDeadline, deadline)
+	})
+
+	t.Run("expires a stuck call", func(t *testing.T) {
+		SetQueryTimeout(10 * time.Millisecond)
+
+		ctx, cancel := WithQueryTimeout(context.Background())
+		defer cancel()
+
+		select {
+		case <-ctx.Done():
+			assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+		case <-time.After(time.Second):
+			t.Fatal("query context did not expire")
+		}
+	})
+
+	t.Run("non-positive timeout restores the default", func(t *testing.T) {
+		SetQueryTimeout(-time.Second)
+
+		assert.Equal(t, DefaultQueryTimeout, QueryTimeout())
+	})
+}
@@ -0,0 +1,26 @@
+package dedup
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrDuplicate is returned when the key was already claimed within its window
+var ErrDuplicate = errors.New("duplicate request")
+
+// Deduplicator suppresses repeats of an operation across every instance of the platform
+type Deduplicator interface {
+	// Claim marks key as done for window, returning ErrDuplicate if it already was
+	Claim(ctx context.Context, key string, window time.Duration) error
+
+	// Forget drops the claim on key so a failed operation can be retried right away
+	Forget(ctx context.Context, key string) error
+}
+
+// Key builds the key of an operation done for a purpose on a subject, e.g. Key("otp_mail", email).
+// Subjects are compared case-insensitively.
+func Key(purpose, subject string) string {
+	return purpose + ":" + strings.ToLower(strings.TrimSpace(subject))
+}
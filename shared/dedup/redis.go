@@ -0,0 +1,43 @@
+package dedup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const redisKeyPrefix = "dedup:"
+
+// RedisDeduplicator implements Deduplicator with SET NX, the window being the key ttl
+type RedisDeduplicator struct {
+	client redis.UniversalClient
+}
+
+// NewRedisDeduplicator creates a deduplicator backed by redis
+func NewRedisDeduplicator(client redis.UniversalClient) *RedisDeduplicator {
+	return &RedisDeduplicator{client: client}
+}
+
+func (d *RedisDeduplicator) Claim(ctx context.Context, key string, window time.Duration) error {
+	if window <= 0 {
+		return fmt.Errorf("invalid window %s for %s", window, key)
+	}
+
+	ok, err := d.client.SetNX(ctx, redisKeyPrefix+key, time.Now().Unix(), window).Result()
+	if err != nil {
+		return fmt.Errorf("failed to claim %s: %w", key, err)
+	}
+	if !ok {
+		return ErrDuplicate
+	}
+	return nil
+}
+
+func (d *RedisDeduplicator) Forget(ctx context.Context, key string) error {
+	if err := d.client.Del(ctx, redisKeyPrefix+key).Err(); err != nil {
+		return fmt.Errorf("failed to forget %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package dedup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedisDeduplicator(t *testing.T) (*RedisDeduplicator, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewRedisDeduplicator(client), server
+}
+
+func TestRedisDeduplicator_Claim(t *testing.T) {
+	ctx := context.Background()
+	key := Key("otp_mail", "User@Example.com ")
+
+	t.Run("rejects repeats within the window", func(t *testing.T) {
+		d, server := newTestRedisDeduplicator(t)
+
+		require.NoError(t, d.Claim(ctx, key, time.Minute))
+		assert.ErrorIs(t, d.Claim(ctx, Key("otp_mail", "user@example.com"), time.Minute), ErrDuplicate)
+
+		server.FastForward(time.Minute)
+		assert.NoError(t, d.Claim(ctx, key, time.Minute), "window elapsed")
+	})
+
+	t.Run("allows a retry once forgotten", func(t *testing.T) {
+		d, _ := newTestRedisDeduplicator(t)
+
+		require.NoError(t, d.Claim(ctx, key, time.Minute))
+		require.NoError(t, d.Forget(ctx, key))
+		assert.NoError(t, d.Claim(ctx, key, time.Minute))
+	})
+
+	t.Run("keys are scoped by purpose", func(t *testing.T) {
+		d, _ := newTestRedisDeduplicator(t)
+
+		require.NoError(t, d.Claim(ctx, Key("otp_mail", "user@example.com"), time.Minute))
+		assert.NoError(t, d.Claim(ctx, Key("user_registered", "user@example.com"), time.Minute))
+	})
+
+	t.Run("rejects an empty window", func(t *testing.T) {
+		d, _ := newTestRedisDeduplicator(t)
+		assert.Error(t, d.Claim(ctx, key, 0))
+	})
+}
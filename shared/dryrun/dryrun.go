@@ -0,0 +1,38 @@
+package dryrun
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Header asks for a dry run of a command when set to a true value, e.g. "X-Dry-Run: true".
+// Responses of dry runs echo it back.
+const Header = "X-Dry-Run"
+
+type dryRunKey struct{}
+
+// WithDryRun marks ctx as a dry run: command handlers that support it validate and compute
+// their result but neither persist nor publish anything
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// IsDryRun reports whether ctx is a dry run
+func IsDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey{}).(bool)
+	return dryRun
+}
+
+// Middleware turns requests carrying the dry run header into dry runs.
+// Only handlers that check IsDryRun honour it; others run as usual.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if dryRun, err := strconv.ParseBool(c.GetHeader(Header)); err == nil && dryRun {
+			c.Request = c.Request.WithContext(WithDryRun(c.Request.Context()))
+			c.Header(Header, "true")
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,46 @@
+package dryrun
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{header: "", want: false},
+		{header: "true", want: true},
+		{header: "1", want: true},
+		{header: "false", want: false},
+		{header: "yes please", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			var got bool
+			router := gin.New()
+			router.Use(Middleware())
+			router.POST("/", func(c *gin.Context) {
+				got = IsDryRun(c.Request.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(Header, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want, rec.Header().Get(Header) == "true")
+		})
+	}
+}
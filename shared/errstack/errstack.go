@@ -0,0 +1,36 @@
+// Package errstack builds system errors (syserr.Error) without paying for stacks nobody reads.
+// syserr.New captures the stack of every error, even for the errors of bad input or of a wrong
+// password answered many times a second, and for the package-level domain errors, whose stack is the
+// one of the package init. Only unexpected errors, answered as internal, are investigated with their
+// stack, so only they capture one here; syserr resolves it to frames when it is read.
+package errstack
+
+import "github.com/duongptryu/gox/syserr"
+
+// Sentinel creates an error without a stack, for the package-level errors of domains compared with
+// errors.Is, whatever their code
+func Sentinel(code syserr.Code, message string) *syserr.Error {
+	return syserr.NewWithoutStack(code, message)
+}
+
+// New creates an error, with its stack when its code is syserr.InternalCode. Expected errors, like
+// invalid arguments or a missing authorization, have none.
+func New(code syserr.Code, message string) *syserr.Error {
+	if code == syserr.InternalCode {
+		// the stack starts at the caller of New
+		return syserr.NewSkip(1, code, message)
+	}
+	return syserr.NewWithoutStack(code, message)
+}
+
+// Wrap creates an error caused by err, with its stack when its code is syserr.InternalCode
+func Wrap(err error, code syserr.Code, message string) *syserr.Error {
+	var wrapped *syserr.Error
+	if code == syserr.InternalCode {
+		wrapped = syserr.NewSkip(1, code, message)
+	} else {
+		wrapped = syserr.NewWithoutStack(code, message)
+	}
+	wrapped.WrappedError = err
+	return wrapped
+}
@@ -0,0 +1,81 @@
+package errstack
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errSentinel = Sentinel(syserr.NotFoundCode, "order not found")
+
+func TestSentinel(t *testing.T) {
+	assert.Equal(t, syserr.NotFoundCode, errSentinel.Code())
+	assert.Equal(t, "order not found", errSentinel.Error())
+	assert.Empty(t, errSentinel.StackTrace(), "the stack of the package init tells nothing")
+	assert.Empty(t, Sentinel(syserr.InternalCode, "boom").StackTrace())
+
+	wrapped := syserr.Wrap(errSentinel, syserr.InternalCode, "failed to get order")
+	assert.ErrorIs(t, wrapped, errSentinel)
+}
+
+func TestNew(t *testing.T) {
+	expected := New(syserr.UnauthorizedCode, "authorization token required")
+	assert.Equal(t, syserr.UnauthorizedCode, expected.Code())
+	assert.Equal(t, syserr.UnauthorizedCode, syserr.GetCodeFromGenericError(expected))
+	assert.Empty(t, expected.StackTrace())
+
+	internal := New(syserr.InternalCode, "invariant broken")
+	stack := internal.StackTrace()
+	require.NotEmpty(t, stack)
+	assert.LessOrEqual(t, len(stack), syserr.MaxStackDepth)
+	assert.Equal(t, "tixgo/shared/errstack.TestNew", stack[0].Function, "the stack starts at the caller")
+	assert.True(t, strings.HasSuffix(stack[0].File, "errstack_test.go"))
+	assert.Empty(t, internal.Stack, "the stack is resolved when read")
+	formatted := internal.StackFormatted()
+	require.Len(t, formatted, len(stack))
+	assert.True(t, strings.HasSuffix(formatted[0], " tixgo/shared/errstack.TestNew"))
+	assert.Equal(t, formatted, syserr.GetStackFormattedFromGenericError(internal))
+
+	// errors of a code do not share anything
+	other := New(syserr.UnauthorizedCode, "token is not an access token")
+	assert.Equal(t, "authorization token required", expected.Error())
+	assert.NotSame(t, expected, other)
+}
+
+func TestWrap(t *testing.T) {
+	wrapped := Wrap(io.ErrUnexpectedEOF, syserr.InternalCode, "failed to read body")
+	assert.Equal(t, "failed to read body: unexpected EOF", wrapped.Error())
+	assert.True(t, errors.Is(wrapped, io.ErrUnexpectedEOF))
+	assert.Equal(t, "tixgo/shared/errstack.TestWrap", wrapped.StackTrace()[0].Function)
+
+	assert.Empty(t, Wrap(io.ErrUnexpectedEOF, syserr.ValidationCode, "the JSON body ends unexpectedly").StackTrace())
+}
+
+var sink *syserr.Error
+
+// BenchmarkNew compares the errors of bad input with syserr.New, which captures a stack for each of them
+func BenchmarkNew(b *testing.B) {
+	b.Run("syserr", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			sink = syserr.New(syserr.UnauthorizedCode, "authorization token required")
+		}
+	})
+	b.Run("expected", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			sink = New(syserr.UnauthorizedCode, "authorization token required")
+		}
+	})
+	b.Run("internal", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			sink = New(syserr.InternalCode, "invariant broken")
+		}
+	})
+}
@@ -0,0 +1,103 @@
+// Package eventbus catalogs the messages of the event and command buses: their topic, the schema of
+// their payload and the modules producing them, so consumers discover topics without reading the code.
+package eventbus
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"sync"
+
+	"tixgo/shared/httpresponse"
+	"tixgo/shared/jsonschema"
+	sharedKafka "tixgo/shared/kafka"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/gin-gonic/gin"
+)
+
+// Kind tells events from commands
+type Kind string
+
+const (
+	// KindEvent messages tell that something happened, any number of modules consume them
+	KindEvent Kind = "event"
+	// KindCommand messages ask one module to do something
+	KindCommand Kind = "command"
+)
+
+// Message is a message type of the buses
+type Message struct {
+	// Name is the name the bus gives the message, its struct name
+	Name string `json:"name"`
+	Kind Kind   `json:"kind"`
+	// Topic is the logical topic of the message, KafkaTopic the topic it is published to
+	Topic      string `json:"topic"`
+	KafkaTopic string `json:"kafka_topic,omitempty"`
+	// Producers are the modules publishing the message
+	Producers   []string           `json:"producers"`
+	Description string             `json:"description"`
+	Schema      *jsonschema.Schema `json:"schema"`
+}
+
+var (
+	catalogMu sync.RWMutex
+	catalog   = map[string]Message{}
+)
+
+// RegisterEvent adds an event to the catalog, with the modules publishing it
+func RegisterEvent(event any, description string, producers ...string) {
+	register(KindEvent, "events.", event, description, producers)
+}
+
+// RegisterCommand adds a command to the catalog, with the modules sending it
+func RegisterCommand(command any, description string, producers ...string) {
+	register(KindCommand, "commands.", command, description, producers)
+}
+
+// register names the message and its topic like the bus does. The catalog is a static list, a message
+// registered twice or carrying a sensitive field panics.
+func register(kind Kind, topicPrefix string, message any, description string, producers []string) {
+	name := cqrs.StructName(message)
+
+	if field := sensitiveField(reflect.TypeOf(message), map[reflect.Type]bool{}); field != "" {
+		panic(fmt.Sprintf("eventbus: message %s carries the sensitive field %s", name, field))
+	}
+
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+
+	if _, ok := catalog[name]; ok {
+		panic(fmt.Sprintf("eventbus: message %s registered twice", name))
+	}
+	catalog[name] = Message{
+		Name:        name,
+		Kind:        kind,
+		Topic:       topicPrefix + name,
+		Producers:   producers,
+		Description: description,
+		Schema:      jsonschema.Generate(message, jsonschema.Body),
+	}
+}
+
+// Catalog returns the registered messages sorted by topic, with the Kafka topic of naming
+func Catalog(naming sharedKafka.TopicNaming) []Message {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+
+	messages := make([]Message, 0, len(catalog))
+	for _, message := range catalog {
+		message.KafkaTopic = naming.Physical(message.Topic)
+		messages = append(messages, message)
+	}
+	sort.Slice(messages, func(i, j int) bool { return messages[i].Topic < messages[j].Topic })
+	return messages
+}
+
+// Handler answers the catalog
+func Handler(naming sharedKafka.TopicNaming) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		httpresponse.Success(c, http.StatusOK, Catalog(naming))
+	}
+}
@@ -0,0 +1,70 @@
+package eventbus
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	sharedKafka "tixgo/shared/kafka"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type EventThingCreated struct {
+	ThingID    int64     `json:"thing_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+type CreateThingCommand struct {
+	Name string
+}
+
+type thingOwner struct {
+	Name   string
+	Health string `privacy:"sensitive"`
+}
+
+type EventThingShared struct {
+	ThingID int64         `json:"thing_id"`
+	Owners  []*thingOwner `json:"owners"`
+}
+
+func TestCatalog(t *testing.T) {
+	RegisterEvent(&EventThingCreated{}, "A thing was created.", "things", "imports")
+	RegisterCommand(CreateThingCommand{}, "Creates a thing.", "things")
+
+	assert.Panics(t, func() {
+		RegisterEvent(EventThingCreated{}, "A thing was created again.", "things")
+	})
+
+	messages := Catalog(sharedKafka.NewTopicNaming("stg"))
+	require.Len(t, messages, 2)
+
+	command, event := messages[0], messages[1]
+	assert.Equal(t, "CreateThingCommand", command.Name)
+	assert.Equal(t, KindCommand, command.Kind)
+	assert.Equal(t, "commands.CreateThingCommand", command.Topic)
+	assert.Equal(t, "stg.commands.CreateThingCommand", command.KafkaTopic)
+	assert.Contains(t, command.Schema.Properties, "Name")
+
+	assert.Equal(t, "EventThingCreated", event.Name)
+	assert.Equal(t, KindEvent, event.Kind)
+	assert.Equal(t, "events.EventThingCreated", event.Topic)
+	assert.Equal(t, []string{"things", "imports"}, event.Producers)
+	assert.Equal(t, "date-time", event.Schema.Properties["occurred_at"].Format)
+
+	var docs strings.Builder
+	require.NoError(t, WriteMarkdown(&docs, messages))
+	assert.Contains(t, docs.String(), "| [`events.EventThingCreated`](#eventseventthingcreated) | event | things, imports |")
+	assert.Contains(t, docs.String(), "## commands.CreateThingCommand\n\nCreates a thing.")
+	assert.Contains(t, docs.String(), `"thing_id": {`)
+}
+
+func TestRegister_SensitiveField(t *testing.T) {
+	assert.PanicsWithValue(t, "eventbus: message EventThingShared carries the sensitive field Owners.Health", func() {
+		RegisterEvent(EventThingShared{}, "A thing was shared.", "things")
+	})
+	assert.Equal(t, "", sensitiveField(reflect.TypeOf(EventThingCreated{}), map[reflect.Type]bool{}))
+}
@@ -0,0 +1,43 @@
+package eventbus
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteMarkdown documents messages in markdown: an index of the topics, then the description, the
+// producers and the payload schema of every message
+func WriteMarkdown(w io.Writer, messages []Message) error {
+	var b strings.Builder
+
+	b.WriteString("# Message Catalog\n\n")
+	b.WriteString("The events and commands published on the buses. Kafka topics carry the environment prefix of `kafka.topic_prefix`, if any.\n\n")
+	b.WriteString("| Topic | Kind | Producers |\n")
+	b.WriteString("|-------|------|-----------|\n")
+	for _, message := range messages {
+		fmt.Fprintf(&b, "| [`%s`](#%s) | %s | %s |\n", message.Topic, anchor(message.Topic), message.Kind, strings.Join(message.Producers, ", "))
+	}
+
+	for _, message := range messages {
+		schema, err := json.MarshalIndent(message.Schema, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal the schema of %s: %w", message.Name, err)
+		}
+
+		fmt.Fprintf(&b, "\n## %s\n\n", message.Topic)
+		fmt.Fprintf(&b, "%s\n\n", message.Description)
+		fmt.Fprintf(&b, "- Kind: %s\n", message.Kind)
+		fmt.Fprintf(&b, "- Producers: %s\n\n", strings.Join(message.Producers, ", "))
+		fmt.Fprintf(&b, "```json\n%s\n```\n", schema)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// anchor is the heading anchor markdown renderers give a topic
+func anchor(topic string) string {
+	return strings.ToLower(strings.ReplaceAll(topic, ".", ""))
+}
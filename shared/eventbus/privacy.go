@@ -0,0 +1,34 @@
+package eventbus
+
+import "reflect"
+
+// The struct fields holding data that must never leave the module keeping it, e.g. the health details
+// of an accommodation request, are tagged `privacy:"sensitive"`. The messages of the buses feed
+// analytics and every consumer alike, so a message carrying such a field, however deep, is refused when
+// it is registered.
+const (
+	privacyTag       = "privacy"
+	privacySensitive = "sensitive"
+)
+
+// sensitiveField returns the path of the first field of t tagged sensitive, empty when it has none
+func sensitiveField(t reflect.Type, seen map[reflect.Type]bool) string {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || seen[t] {
+		return ""
+	}
+	seen[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Tag.Get(privacyTag) == privacySensitive {
+			return field.Name
+		}
+		if path := sensitiveField(field.Type, seen); path != "" {
+			return field.Name + "." + path
+		}
+	}
+	return ""
+}
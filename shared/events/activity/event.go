@@ -0,0 +1,44 @@
+package activity
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// Type is the kind of account activity
+type Type string
+
+const (
+	TypeLogin          Type = "login"
+	TypePurchase       Type = "purchase"
+	TypeProfileChange  Type = "profile_change"
+	TypeTicketTransfer Type = "ticket_transfer"
+)
+
+// EventAccountActivity records something that happened on the account of a user, for their activity feed.
+// ID identifies the activity so redeliveries are recorded once.
+type EventAccountActivity struct {
+	ID     string `json:"id"`
+	UserID int64  `json:"user_id"`
+	Type   Type   `json:"type"`
+	// Metadata describes the activity to the user, e.g. the order of a purchase
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	IPAddress  string            `json:"ip_address,omitempty"`
+	UserAgent  string            `json:"user_agent,omitempty"`
+	OccurredAt time.Time         `json:"occurred_at"`
+}
+
+// NewEventAccountActivity creates an activity of the user that occurred now
+func NewEventAccountActivity(userID int64, activityType Type, metadata map[string]string) *EventAccountActivity {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+
+	return &EventAccountActivity{
+		ID:         hex.EncodeToString(id),
+		UserID:     userID,
+		Type:       activityType,
+		Metadata:   metadata,
+		OccurredAt: time.Now(),
+	}
+}
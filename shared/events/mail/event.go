@@ -10,4 +10,15 @@ type EventSendMail struct {
 	TextBody string              `json:"text_body"`
 	HTMLBody string              `json:"html_body"`
 	Priority mail.Priority       `json:"priority"`
+	// OrganizerID marks attendee-facing mails, sent from the verified domain of the organizer if any
+	OrganizerID int64 `json:"organizer_id,omitempty"`
+	// Attachments travel inside the event, so they are kept to documents of a few kilobytes like receipts
+	Attachments []Attachment `json:"attachments,omitempty"`
+}
+
+// Attachment is a file attached to a mail
+type Attachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Content     []byte `json:"content"`
 }
@@ -1,6 +1,7 @@
 package mail
 
 import (
+	"bytes"
 	"context"
 
 	"github.com/duongptryu/gox/notification/mail"
@@ -11,15 +12,33 @@ type ConfigMail struct {
 	OurName string
 }
 
+// SenderResolver finds the identity the attendee-facing mails of an organizer are sent from
+type SenderResolver interface {
+	// ResolveSender returns the verified sender of the organizer, or nil for the platform identity
+	ResolveSender(ctx context.Context, organizerID int64) (*mail.EmailAddress, error)
+}
+
+// Throttle holds the mails to the quota of the provider
+type Throttle interface {
+	// Wait blocks until the mail may be sent
+	Wait(ctx context.Context) error
+}
+
 type EventSendMailHandler struct {
 	mailCfg      ConfigMail
 	mailProvider mail.MailProvider
+	senders      SenderResolver
+	throttle     Throttle
 }
 
-func NewEventSendMailHandler(mailProvider mail.MailProvider, cfgMail ConfigMail) *EventSendMailHandler {
+// NewEventSendMailHandler creates the mail dispatcher. senders may be nil, every mail then goes out
+// from the platform identity. throttle may be nil when the provider has no quota.
+func NewEventSendMailHandler(mailProvider mail.MailProvider, cfgMail ConfigMail, senders SenderResolver, throttle Throttle) *EventSendMailHandler {
 	return &EventSendMailHandler{
 		mailProvider: mailProvider,
 		mailCfg:      cfgMail,
+		senders:      senders,
+		throttle:     throttle,
 	}
 }
 
@@ -29,8 +48,20 @@ func (h *EventSendMailHandler) Handle(ctx context.Context, event *EventSendMail)
 		priority = event.Priority
 	}
 
-	_, err := h.mailProvider.SendEmail(ctx, &mail.EmailMessage{
-		From:     mail.EmailAddress{Email: h.mailCfg.OurMail, Name: h.mailCfg.OurName},
+	from, err := h.sender(ctx, event)
+	if err != nil {
+		return err
+	}
+
+	// Mails over the quota wait for their slot, holding back the partition instead of failing
+	if h.throttle != nil {
+		if err := h.throttle.Wait(ctx); err != nil {
+			return err
+		}
+	}
+
+	_, err = h.mailProvider.SendEmail(ctx, &mail.EmailMessage{
+		From:     from,
 		To:       event.ToMail,
 		CC:       event.CC,
 		BCC:      event.BCC,
@@ -38,6 +69,8 @@ func (h *EventSendMailHandler) Handle(ctx context.Context, event *EventSendMail)
 		TextBody: event.TextBody,
 		HTMLBody: event.HTMLBody,
 		Priority: priority,
+		// the reader of an attachment is drained by the send, redeliveries convert them again
+		Attachments: attachments(event.Attachments),
 	})
 
 	if err != nil {
@@ -46,3 +79,38 @@ func (h *EventSendMailHandler) Handle(ctx context.Context, event *EventSendMail)
 
 	return nil
 }
+
+// sender picks the identity of a mail: the organizer's for their attendees once verified, the platform's otherwise
+func (h *EventSendMailHandler) sender(ctx context.Context, event *EventSendMail) (mail.EmailAddress, error) {
+	platform := mail.EmailAddress{Email: h.mailCfg.OurMail, Name: h.mailCfg.OurName}
+	if event.OrganizerID == 0 || h.senders == nil {
+		return platform, nil
+	}
+
+	from, err := h.senders.ResolveSender(ctx, event.OrganizerID)
+	if err != nil {
+		return mail.EmailAddress{}, err
+	}
+	if from == nil {
+		return platform, nil
+	}
+	return *from, nil
+}
+
+// attachments converts the attachments of an event to those of the provider, nil when there are none
+func attachments(files []Attachment) []mail.Attachment {
+	if len(files) == 0 {
+		return nil
+	}
+
+	converted := make([]mail.Attachment, len(files))
+	for i, file := range files {
+		converted[i] = mail.Attachment{
+			Filename:    file.Filename,
+			Content:     bytes.NewReader(file.Content),
+			ContentType: file.ContentType,
+			Size:        int64(len(file.Content)),
+		}
+	}
+	return converted
+}
@@ -0,0 +1,147 @@
+package mail
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/duongptryu/gox/notification/mail"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingProvider keeps the messages it is asked to send
+type recordingProvider struct {
+	mail.MailProvider
+	sent []*mail.EmailMessage
+}
+
+func (p *recordingProvider) SendEmail(_ context.Context, message *mail.EmailMessage) (*mail.SendEmailResponse, error) {
+	p.sent = append(p.sent, message)
+	return &mail.SendEmailResponse{}, nil
+}
+
+type senderResolverFunc func(ctx context.Context, organizerID int64) (*mail.EmailAddress, error)
+
+func (f senderResolverFunc) ResolveSender(ctx context.Context, organizerID int64) (*mail.EmailAddress, error) {
+	return f(ctx, organizerID)
+}
+
+func TestEventSendMailHandler_Sender(t *testing.T) {
+	ctx := context.Background()
+	platform := ConfigMail{OurMail: "no-reply@tixgo.io", OurName: "TixGo"}
+	organizer := mail.EmailAddress{Email: "tickets@band.example", Name: "The Band"}
+
+	senders := senderResolverFunc(func(_ context.Context, organizerID int64) (*mail.EmailAddress, error) {
+		switch organizerID {
+		case 1:
+			return &organizer, nil
+		case 2:
+			return nil, nil
+		default:
+			return nil, errors.New("database unavailable")
+		}
+	})
+
+	tests := []struct {
+		name        string
+		senders     SenderResolver
+		organizerID int64
+		want        mail.EmailAddress
+	}{
+		{name: "platform mails use the platform identity", senders: senders, organizerID: 0, want: mail.EmailAddress{Email: platform.OurMail, Name: platform.OurName}},
+		{name: "verified organizers send as themselves", senders: senders, organizerID: 1, want: organizer},
+		{name: "other organizers use the platform identity", senders: senders, organizerID: 2, want: mail.EmailAddress{Email: platform.OurMail, Name: platform.OurName}},
+		{name: "without resolver every mail uses the platform identity", senders: nil, organizerID: 1, want: mail.EmailAddress{Email: platform.OurMail, Name: platform.OurName}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &recordingProvider{}
+			handler := NewEventSendMailHandler(provider, platform, tt.senders, nil)
+
+			err := handler.Handle(ctx, &EventSendMail{ToMail: []mail.EmailAddress{{Email: "fan@example.com"}}, OrganizerID: tt.organizerID})
+			require.NoError(t, err)
+			require.Len(t, provider.sent, 1)
+			assert.Equal(t, tt.want, provider.sent[0].From)
+		})
+	}
+
+	t.Run("lookup failures are retried rather than sent from another identity", func(t *testing.T) {
+		provider := &recordingProvider{}
+		handler := NewEventSendMailHandler(provider, platform, senders, nil)
+
+		err := handler.Handle(ctx, &EventSendMail{ToMail: []mail.EmailAddress{{Email: "fan@example.com"}}, OrganizerID: 3})
+		assert.Error(t, err)
+		assert.Empty(t, provider.sent)
+	})
+}
+
+type throttleFunc func(ctx context.Context) error
+
+func (f throttleFunc) Wait(ctx context.Context) error {
+	return f(ctx)
+}
+
+func TestEventSendMailHandler_Throttle(t *testing.T) {
+	ctx := context.Background()
+	platform := ConfigMail{OurMail: "no-reply@tixgo.io", OurName: "TixGo"}
+	event := &EventSendMail{ToMail: []mail.EmailAddress{{Email: "fan@example.com"}}}
+
+	t.Run("mails wait for the throttle", func(t *testing.T) {
+		provider := &recordingProvider{}
+		waited := 0
+		handler := NewEventSendMailHandler(provider, platform, nil, throttleFunc(func(context.Context) error {
+			waited++
+			assert.Empty(t, provider.sent)
+			return nil
+		}))
+
+		require.NoError(t, handler.Handle(ctx, event))
+		assert.Equal(t, 1, waited)
+		assert.Len(t, provider.sent, 1)
+	})
+
+	t.Run("a mail running out of time waiting is not sent", func(t *testing.T) {
+		provider := &recordingProvider{}
+		handler := NewEventSendMailHandler(provider, platform, nil, throttleFunc(func(context.Context) error {
+			return context.DeadlineExceeded
+		}))
+
+		assert.ErrorIs(t, handler.Handle(ctx, event), context.DeadlineExceeded)
+		assert.Empty(t, provider.sent)
+	})
+}
+
+func TestEventSendMailHandler_Attachments(t *testing.T) {
+	ctx := context.Background()
+	platform := ConfigMail{OurMail: "no-reply@tixgo.io", OurName: "TixGo"}
+
+	// attachments are published inside the event, they must survive its encoding
+	encoded, err := json.Marshal(&EventSendMail{
+		ToMail:      []mail.EmailAddress{{Email: "fan@example.com"}},
+		Attachments: []Attachment{{Filename: "receipt.pdf", ContentType: "application/pdf", Content: []byte("%PDF-1.4\n\xe2")}},
+	})
+	require.NoError(t, err)
+	var event EventSendMail
+	require.NoError(t, json.Unmarshal(encoded, &event))
+
+	provider := &recordingProvider{}
+	handler := NewEventSendMailHandler(provider, platform, nil, nil)
+	require.NoError(t, handler.Handle(ctx, &event))
+	require.Len(t, provider.sent, 1)
+	require.Len(t, provider.sent[0].Attachments, 1)
+
+	attachment := provider.sent[0].Attachments[0]
+	assert.Equal(t, "receipt.pdf", attachment.Filename)
+	assert.Equal(t, "application/pdf", attachment.ContentType)
+	assert.Equal(t, int64(10), attachment.Size)
+	content, err := io.ReadAll(attachment.Content)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("%PDF-1.4\n\xe2"), content)
+
+	require.NoError(t, handler.Handle(ctx, &EventSendMail{ToMail: []mail.EmailAddress{{Email: "fan@example.com"}}}))
+	assert.Nil(t, provider.sent[1].Attachments)
+}
@@ -0,0 +1,20 @@
+package notification
+
+import "github.com/duongptryu/gox/notification/mail"
+
+// EventNotificationRequested asks for a notification to a user. Low priority notifications are batched
+// into the digest of the user, unless they turned digests off; the others are mailed at once.
+type EventNotificationRequested struct {
+	UserID   int64  `json:"user_id"`
+	Category string `json:"category"`
+	// Title, Summary and URL make the entry of the notification in a digest
+	Title   string `json:"title"`
+	Summary string `json:"summary"`
+	URL     string `json:"url"`
+	// Subject and HTMLBody make the mail sent when the notification is not batched
+	Subject  string        `json:"subject"`
+	HTMLBody string        `json:"html_body"`
+	Priority mail.Priority `json:"priority"`
+	// OrganizerID marks attendee-facing notifications, see EventSendMail
+	OrganizerID int64 `json:"organizer_id,omitempty"`
+}
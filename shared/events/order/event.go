@@ -0,0 +1,69 @@
+package order
+
+import "time"
+
+// EventOrdersChanged tells the read models built from orders that some orders changed. Either OrderIDs
+// lists them, or EventID names an event every order of which may have changed, e.g. when it is cancelled.
+type EventOrdersChanged struct {
+	OrderIDs   []int64   `json:"order_ids,omitempty"`
+	EventID    int64     `json:"event_id,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// NewEventOrdersChanged creates the event of changed orders
+func NewEventOrdersChanged(orderIDs ...int64) *EventOrdersChanged {
+	return &EventOrdersChanged{OrderIDs: orderIDs, OccurredAt: time.Now()}
+}
+
+// NewEventOrdersOfEventChanged creates the event of the orders of an event changing together
+func NewEventOrdersOfEventChanged(eventID int64) *EventOrdersChanged {
+	return &EventOrdersChanged{EventID: eventID, OccurredAt: time.Now()}
+}
+
+// EventOrderCreated tells that a buyer checked out: the order is pending, holding its tickets until
+// ExpiresAt for the buyer to pay
+type EventOrderCreated struct {
+	OrderID     int64     `json:"order_id"`
+	OrderNumber string    `json:"order_number"`
+	UserID      int64     `json:"user_id"`
+	Email       string    `json:"email"`
+	EventID     int64     `json:"event_id"`
+	OrganizerID int64     `json:"organizer_id"`
+	TicketCount int       `json:"ticket_count"`
+	TotalAmount string    `json:"total_amount"`
+	Currency    string    `json:"currency"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
+
+// EventOrderConfirmed tells that an order was paid and its tickets sold to the buyer
+type EventOrderConfirmed struct {
+	OrderID     int64     `json:"order_id"`
+	OrderNumber string    `json:"order_number"`
+	UserID      int64     `json:"user_id"`
+	Email       string    `json:"email"`
+	EventID     int64     `json:"event_id"`
+	OrganizerID int64     `json:"organizer_id"`
+	TicketCount int       `json:"ticket_count"`
+	TotalAmount string    `json:"total_amount"`
+	Currency    string    `json:"currency"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
+
+// EventOrderRefunded tells that tickets of an order were refunded and given back. Full is set when no
+// ticket of the order is left; the payment integration issues the refund itself from RefundID.
+type EventOrderRefunded struct {
+	OrderID     int64     `json:"order_id"`
+	OrderNumber string    `json:"order_number"`
+	RefundID    int64     `json:"refund_id"`
+	UserID      int64     `json:"user_id"`
+	Email       string    `json:"email"`
+	EventID     int64     `json:"event_id"`
+	OrganizerID int64     `json:"organizer_id"`
+	TicketCount int       `json:"ticket_count"`
+	Amount      string    `json:"amount"`
+	Currency    string    `json:"currency"`
+	Reason      string    `json:"reason"`
+	Full        bool      `json:"full"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
@@ -0,0 +1,9 @@
+package sms
+
+// EventSendSMS asks for a text message to a phone number
+type EventSendSMS struct {
+	// To is the phone number in E.164 format, like +84901234567. Messages to numbers that cannot
+	// receive text messages are dropped.
+	To   string `json:"to"`
+	Body string `json:"body"`
+}
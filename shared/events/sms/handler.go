@@ -0,0 +1,52 @@
+package sms
+
+import (
+	"context"
+
+	"tixgo/shared/phone"
+
+	"github.com/duongptryu/gox/logger"
+)
+
+// Sender sends text messages through a provider
+type Sender interface {
+	SendSMS(ctx context.Context, to, body string) error
+}
+
+// Throttle holds the messages to the quota of the provider
+type Throttle interface {
+	// Wait blocks until the message may be sent
+	Wait(ctx context.Context) error
+}
+
+type EventSendSMSHandler struct {
+	sender   Sender
+	throttle Throttle
+}
+
+// NewEventSendSMSHandler creates the text message dispatcher. throttle may be nil when the provider has no quota.
+func NewEventSendSMSHandler(sender Sender, throttle Throttle) *EventSendSMSHandler {
+	return &EventSendSMSHandler{
+		sender:   sender,
+		throttle: throttle,
+	}
+}
+
+// Handle sends the message unless its number cannot receive it. Such a message is dropped before
+// reaching the provider, which would refuse it on every retry and still count it in the quota.
+func (h *EventSendSMSHandler) Handle(ctx context.Context, event *EventSendSMS) error {
+	number, err := phone.Parse(event.To, "")
+	if err != nil {
+		logger.Warning(ctx, "Dropping text message to an undeliverable number", logger.F("error", err))
+		return nil
+	}
+
+	// Messages over the quota wait for their slot, holding back the partition instead of failing
+	if h.throttle != nil {
+		if err := h.throttle.Wait(ctx); err != nil {
+			return err
+		}
+	}
+
+	return h.sender.SendSMS(ctx, number.E164, event.Body)
+}
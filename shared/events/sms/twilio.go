@@ -0,0 +1,61 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"tixgo/shared/httpclient"
+)
+
+// TwilioProvider is the name of the Twilio quota in send_limits
+const TwilioProvider = "twilio"
+
+const twilioBaseURL = "https://api.twilio.com/2010-04-01"
+
+// TwilioSender sends text messages with the Messages API of Twilio
+type TwilioSender struct {
+	client     *httpclient.Client
+	baseURL    string
+	accountSID string
+	authToken  string
+	from       string
+}
+
+// NewTwilioSender creates a sender of the Twilio account, messages going out from the number from
+func NewTwilioSender(accountSID, authToken, from string) *TwilioSender {
+	return &TwilioSender{
+		client:     httpclient.New(httpclient.DefaultConfig(TwilioProvider)),
+		baseURL:    twilioBaseURL,
+		accountSID: accountSID,
+		authToken:  authToken,
+		from:       from,
+	}
+}
+
+func (s *TwilioSender) SendSMS(ctx context.Context, to, body string) error {
+	form := url.Values{"To": {to}, "From": {s.from}, "Body": {body}}
+	endpoint := fmt.Sprintf("%s/Accounts/%s/Messages.json", s.baseURL, s.accountSID)
+
+	req, err := http.NewRequestWithContext(httpclient.WithEndpoint(ctx, "send_sms"), http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create twilio request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(s.accountSID, s.authToken)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return fmt.Errorf("twilio refused the message with status %d: %s", resp.StatusCode, message)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package sms
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init(&logger.Config{Output: io.Discard})
+	os.Exit(m.Run())
+}
+
+func newTestTwilioSender(t *testing.T, handler http.HandlerFunc) *TwilioSender {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	sender := NewTwilioSender("AC123", "secret", "+15005550006")
+	sender.baseURL = server.URL
+	return sender
+}
+
+func TestTwilioSender_SendSMS(t *testing.T) {
+	t.Run("posts the message to the account", func(t *testing.T) {
+		sender := newTestTwilioSender(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/Accounts/AC123/Messages.json", r.URL.Path)
+			user, password, ok := r.BasicAuth()
+			assert.True(t, ok)
+			assert.Equal(t, "AC123", user)
+			assert.Equal(t, "secret", password)
+
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "+84901234567", r.PostForm.Get("To"))
+			assert.Equal(t, "+15005550006", r.PostForm.Get("From"))
+			assert.Equal(t, "Your code is 123456", r.PostForm.Get("Body"))
+			w.WriteHeader(http.StatusCreated)
+		})
+
+		assert.NoError(t, sender.SendSMS(context.Background(), "+84901234567", "Your code is 123456"))
+	})
+
+	t.Run("a refused message fails", func(t *testing.T) {
+		sender := newTestTwilioSender(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":21211,"message":"invalid To number"}`))
+		})
+
+		err := sender.SendSMS(context.Background(), "+1", "Your code is 123456")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid To number")
+	})
+}
+
+type senderFunc func(ctx context.Context, to, body string) error
+
+func (f senderFunc) SendSMS(ctx context.Context, to, body string) error {
+	return f(ctx, to, body)
+}
+
+type throttleFunc func(ctx context.Context) error
+
+func (f throttleFunc) Wait(ctx context.Context) error {
+	return f(ctx)
+}
+
+func TestEventSendSMSHandler_Throttle(t *testing.T) {
+	var sent []string
+	sender := senderFunc(func(_ context.Context, to, _ string) error {
+		sent = append(sent, to)
+		return nil
+	})
+
+	handler := NewEventSendSMSHandler(sender, throttleFunc(func(context.Context) error { return context.DeadlineExceeded }))
+	assert.ErrorIs(t, handler.Handle(context.Background(), &EventSendSMS{To: "+84901234567"}), context.DeadlineExceeded)
+	assert.Empty(t, sent)
+
+	handler = NewEventSendSMSHandler(sender, nil)
+	require.NoError(t, handler.Handle(context.Background(), &EventSendSMS{To: "+84901234567"}))
+	assert.Equal(t, []string{"+84901234567"}, sent)
+}
+
+func TestEventSendSMSHandler_UndeliverableNumber(t *testing.T) {
+	var sent []string
+	sender := senderFunc(func(_ context.Context, to, _ string) error {
+		sent = append(sent, to)
+		return nil
+	})
+	handler := NewEventSendSMSHandler(sender, nil)
+
+	for _, to := range []string{"", "+1", "+44 20 7946 0000"} {
+		assert.NoError(t, handler.Handle(context.Background(), &EventSendSMS{To: to}), "retrying would not deliver it")
+	}
+	assert.Empty(t, sent)
+
+	require.NoError(t, handler.Handle(context.Background(), &EventSendSMS{To: "+84 90 123 4567"}))
+	assert.Equal(t, []string{"+84901234567"}, sent)
+}
@@ -0,0 +1,103 @@
+// Package feature soft-launches new modules: the routes of a gated feature are only served to the users
+// on its allowlist, by ID, email domain or a share of them, and answer 404 to everyone else as if they
+// did not exist.
+package feature
+
+import (
+	"context"
+	"hash/fnv"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/duongptryu/gox/logger"
+)
+
+// Rule decides who is let into a feature. A user is let in when any part of it matches.
+type Rule struct {
+	// Enabled opens the feature to everyone, once it is launched
+	Enabled bool
+	UserIDs []int64
+	// EmailDomains let in the users whose email is of one of these domains or their subdomains
+	EmailDomains []string
+	// Percentage lets in this share of the users, from 0 to 100. Users are bucketed by a hash of their ID
+	// and the feature, so raising it keeps the users already in and features get different users.
+	Percentage int
+}
+
+// EmailLookup returns the email of a user, for the rules allowing email domains
+type EmailLookup func(ctx context.Context, userID int64) (string, error)
+
+// Flags holds the rules of the gated features, by feature name. A feature without a rule is not gated:
+// modules gate their new route groups from the start and are soft-launched by adding their rule.
+type Flags struct {
+	rules  map[string]Rule
+	emails EmailLookup
+}
+
+// NewFlags creates the flags of rules, looking up the emails of users with emails
+func NewFlags(rules map[string]Rule, emails EmailLookup) *Flags {
+	normalized := make(map[string]Rule, len(rules))
+	for name, rule := range rules {
+		domains := make([]string, 0, len(rule.EmailDomains))
+		for _, domain := range rule.EmailDomains {
+			domains = append(domains, strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "@")))
+		}
+		rule.EmailDomains = domains
+		normalized[strings.ToLower(name)] = rule
+	}
+	return &Flags{rules: normalized, emails: emails}
+}
+
+// Allows checks if the user of userID, 0 for anonymous requests, is let into the feature of name
+func (f *Flags) Allows(ctx context.Context, name string, userID int64) bool {
+	name = strings.ToLower(name)
+	rule, gated := f.rules[name]
+	if !gated || rule.Enabled {
+		return true
+	}
+	if userID == 0 {
+		return false
+	}
+
+	if slices.Contains(rule.UserIDs, userID) || bucket(name, userID) < rule.Percentage {
+		return true
+	}
+
+	if len(rule.EmailDomains) == 0 || f.emails == nil {
+		return false
+	}
+	email, err := f.emails(ctx, userID)
+	if err != nil {
+		logger.Warning(ctx, "Failed to look up the email of a user for a feature",
+			logger.F("feature", name), logger.F("user_id", userID), logger.F("error", err))
+		return false
+	}
+	_, domain, _ := strings.Cut(strings.ToLower(email), "@")
+	for _, allowed := range rule.EmailDomains {
+		if domain == allowed || strings.HasSuffix(domain, "."+allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// Enabled returns the names of the gated features the user of userID is let into, sorted
+func (f *Flags) Enabled(ctx context.Context, userID int64) []string {
+	names := make([]string, 0, len(f.rules))
+	for name := range f.rules {
+		if f.Allows(ctx, name, userID) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// bucket places a user of a feature in one of 100 buckets
+func bucket(name string, userID int64) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name + ":" + strconv.FormatInt(userID, 10)))
+	return int(h.Sum32() % 100)
+}
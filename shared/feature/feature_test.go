@@ -0,0 +1,118 @@
+package feature
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+
+	pkgContext "github.com/duongptryu/gox/context"
+	"github.com/duongptryu/gox/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init(&logger.Config{Output: io.Discard})
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+func testEmails(_ context.Context, userID int64) (string, error) {
+	switch userID {
+	case 1:
+		return "jane@tixgo.io", nil
+	case 2:
+		return "john@staff.tixgo.io", nil
+	case 3:
+		return "fan@nottixgo.io", nil
+	}
+	return "", errors.New("user not found")
+}
+
+func TestFlagsAllows(t *testing.T) {
+	ctx := context.Background()
+	flags := NewFlags(map[string]Rule{
+		"Resale":   {UserIDs: []int64{42}, EmailDomains: []string{"@TixGo.io"}},
+		"waitlist": {Enabled: true},
+		"closed":   {},
+	}, testEmails)
+
+	assert.True(t, flags.Allows(ctx, "resale", 42))
+	assert.True(t, flags.Allows(ctx, "resale", 1), "email domains are compared case-insensitively")
+	assert.True(t, flags.Allows(ctx, "resale", 2), "subdomains are allowed too")
+	assert.False(t, flags.Allows(ctx, "resale", 3))
+	assert.False(t, flags.Allows(ctx, "resale", 4), "users whose email cannot be looked up are not let in")
+	assert.False(t, flags.Allows(ctx, "resale", 0))
+
+	assert.True(t, flags.Allows(ctx, "waitlist", 0), "launched features are open to everyone")
+	assert.True(t, flags.Allows(ctx, "transfers", 0), "features without a rule are not gated")
+	assert.False(t, flags.Allows(ctx, "closed", 42))
+
+	assert.Equal(t, []string{"resale", "waitlist"}, flags.Enabled(ctx, 42))
+	assert.Equal(t, []string{"waitlist"}, flags.Enabled(ctx, 3))
+}
+
+func TestFlagsPercentage(t *testing.T) {
+	ctx := context.Background()
+	allowed := func(flags *Flags, name string) map[int64]bool {
+		users := map[int64]bool{}
+		for id := int64(1); id <= 1000; id++ {
+			if flags.Allows(ctx, name, id) {
+				users[id] = true
+			}
+		}
+		return users
+	}
+
+	tenth := allowed(NewFlags(map[string]Rule{"resale": {Percentage: 10}}, nil), "resale")
+	assert.InDelta(t, 100, len(tenth), 30)
+
+	half := allowed(NewFlags(map[string]Rule{"resale": {Percentage: 50}}, nil), "resale")
+	for id := range tenth {
+		assert.True(t, half[id], "raising the percentage keeps user %d in", id)
+	}
+
+	other := allowed(NewFlags(map[string]Rule{"waitlist": {Percentage: 10}}, nil), "waitlist")
+	assert.NotEqual(t, tenth, other, "features get different users")
+
+	assert.Len(t, allowed(NewFlags(map[string]Rule{"resale": {Percentage: 100}}, nil), "resale"), 1000)
+}
+
+func TestGate(t *testing.T) {
+	flags := NewFlags(map[string]Rule{"resale": {UserIDs: []int64{42}}}, nil)
+
+	router := gin.New()
+	api := router.Group("/api/v1")
+	api.Use(func(c *gin.Context) {
+		if id := c.GetHeader("X-User-ID"); id != "" {
+			c.Request = c.Request.WithContext(pkgContext.WithUserID(c.Request.Context(), id))
+		}
+	})
+	resale := api.Group("/resale", flags.Gate("resale"))
+	resale.GET("/listings", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	request := func(userID int64) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/resale/listings", nil)
+		if userID != 0 {
+			req.Header.Set("X-User-ID", strconv.FormatInt(userID, 10))
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusNoContent, request(42).Code)
+
+	unknown := httptest.NewRecorder()
+	router.ServeHTTP(unknown, httptest.NewRequest(http.MethodGet, "/api/v1/unknown", nil))
+	for _, userID := range []int64{7, 0} {
+		rec := request(userID)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, unknown.Body.String(), rec.Body.String(), "gated routes answer like unknown ones")
+	}
+}
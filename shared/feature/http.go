@@ -0,0 +1,43 @@
+package feature
+
+import (
+	"net/http"
+
+	"tixgo/shared/httpresponse"
+
+	pkgContext "github.com/duongptryu/gox/context"
+	"github.com/gin-gonic/gin"
+)
+
+// notFoundBody is the body gin answers unknown routes with, so gated routes cannot be told apart
+const notFoundBody = "404 page not found"
+
+// Gate only serves the routes of the group to the users let into the feature of name, answering 404
+// like an unknown route to everyone else. It must run after session.RequireAuth, which puts the user
+// into the request context; anonymous requests are only served once the feature is launched.
+func (f *Flags) Gate(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := pkgContext.GetUserIDFromContextAsInt64(c.Request.Context())
+		if !f.Allows(c.Request.Context(), name, userID) {
+			c.Data(http.StatusNotFound, "text/plain", []byte(notFoundBody))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// EnabledResult lists the gated features a user is let into, for clients to show their entry points
+type EnabledResult struct {
+	Features []string `json:"features"`
+}
+
+// EnabledHandler answers the gated features the user of the request is let into
+func EnabledHandler(f *Flags) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := pkgContext.GetUserIDFromContextAsInt64(c.Request.Context())
+		c.Header("Cache-Control", "no-store")
+		httpresponse.Success(c, http.StatusOK, EnabledResult{Features: f.Enabled(c.Request.Context(), userID)})
+	}
+}
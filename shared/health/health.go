@@ -0,0 +1,122 @@
+// Package health answers the deep health check: the dependencies a server needs to do its work are
+// each checked, and the server reports unavailable when one of them fails, or degraded when it still
+// serves most requests without it.
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultTimeout bounds every check when the checker has none
+const DefaultTimeout = 2 * time.Second
+
+// Check tells whether a dependency is usable, an error telling why not
+type Check func(ctx context.Context) error
+
+// Result is the outcome of a check
+type Result struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Response is the answer of the deep health check
+type Response struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+const (
+	statusOK          = "ok"
+	statusFailing     = "failing"
+	statusReady       = "ready"
+	statusDegraded    = "degraded"
+	statusUnavailable = "unavailable"
+)
+
+type namedCheck struct {
+	name  string
+	check Check
+	// degrading checks failing leave the server degraded rather than unavailable
+	degrading bool
+}
+
+// Checker runs named checks concurrently, each under the timeout
+type Checker struct {
+	timeout time.Duration
+	checks  []namedCheck
+}
+
+// NewChecker creates a checker bounding every check by timeout, DefaultTimeout when it is not positive
+func NewChecker(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Checker{timeout: timeout}
+}
+
+// Add registers a check under name
+func (h *Checker) Add(name string, check Check) *Checker {
+	h.checks = append(h.checks, namedCheck{name: name, check: check})
+	return h
+}
+
+// AddDegrading registers a check of a dependency the server runs degraded without: its failure makes
+// the server degraded, which keeps it routed to, rather than unavailable
+func (h *Checker) AddDegrading(name string, check Check) *Checker {
+	h.checks = append(h.checks, namedCheck{name: name, check: check, degrading: true})
+	return h
+}
+
+// Run runs every check and tells whether they all passed
+func (h *Checker) Run(ctx context.Context) Response {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	results := make([]Result, len(h.checks))
+	var wg sync.WaitGroup
+	for i, named := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = Result{Status: statusOK}
+			if err := named.check(ctx); err != nil {
+				results[i] = Result{Status: statusFailing, Error: err.Error()}
+			}
+		}()
+	}
+	wg.Wait()
+
+	response := Response{Status: statusReady, Checks: make(map[string]Result, len(h.checks))}
+	for i, named := range h.checks {
+		response.Checks[named.name] = results[i]
+		switch {
+		case results[i].Status == statusOK:
+		case named.degrading && response.Status == statusReady:
+			response.Status = statusDegraded
+		case !named.degrading:
+			response.Status = statusUnavailable
+		}
+	}
+	return response
+}
+
+// Handler answers the checks, with 503 when the server is unavailable so load balancers and orchestrators
+// stop routing to it; a degraded server answers 200. The answer is bare JSON, like the health endpoints
+// of the router.
+func (h *Checker) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		response := h.Run(c.Request.Context())
+
+		status := http.StatusOK
+		if response.Status == statusUnavailable {
+			status = http.StatusServiceUnavailable
+		}
+		c.Header("Cache-Control", "no-store")
+		c.JSON(status, response)
+	}
+}
@@ -0,0 +1,80 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serve(t *testing.T, checker *Checker) (*httptest.ResponseRecorder, Response) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/health/deep", checker.Handler())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/deep", nil))
+
+	var response Response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return w, response
+}
+
+func passing(context.Context) error { return nil }
+
+func TestChecker(t *testing.T) {
+	t.Run("every check passes", func(t *testing.T) {
+		w, response := serve(t, NewChecker(time.Second).Add("database", passing).Add("redis", passing))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+		assert.Equal(t, Response{Status: "ready", Checks: map[string]Result{
+			"database": {Status: "ok"},
+			"redis":    {Status: "ok"},
+		}}, response)
+	})
+
+	t.Run("a failing check makes the server unavailable", func(t *testing.T) {
+		checker := NewChecker(time.Second).
+			Add("database", passing).
+			Add("kafka_lag", func(context.Context) error { return errors.New("behind on events.X") })
+		w, response := serve(t, checker)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "unavailable", response.Status)
+		assert.Equal(t, Result{Status: "failing", Error: "behind on events.X"}, response.Checks["kafka_lag"])
+		assert.Equal(t, Result{Status: "ok"}, response.Checks["database"])
+	})
+
+	t.Run("a failing degrading check makes the server degraded", func(t *testing.T) {
+		unreachable := func(context.Context) error { return errors.New("brokers unreachable") }
+
+		w, response := serve(t, NewChecker(time.Second).Add("database", passing).AddDegrading("kafka", unreachable))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "degraded", response.Status)
+		assert.Equal(t, Result{Status: "failing", Error: "brokers unreachable"}, response.Checks["kafka"])
+
+		w, response = serve(t, NewChecker(time.Second).AddDegrading("kafka", unreachable).Add("database", unreachable))
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "unavailable", response.Status)
+	})
+
+	t.Run("a check is bounded by the timeout", func(t *testing.T) {
+		checker := NewChecker(10*time.Millisecond).Add("redis", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		w, response := serve(t, checker)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, Result{Status: "failing", Error: context.DeadlineExceeded.Error()}, response.Checks["redis"])
+	})
+}
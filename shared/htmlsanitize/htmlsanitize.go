@@ -0,0 +1,243 @@
+// Package htmlsanitize keeps the HTML of mails to an allow-list of elements, attributes and URL schemes,
+// so content written by less trusted authors cannot run scripts or load what mail clients would execute.
+package htmlsanitize
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"golang.org/x/net/html"
+)
+
+// DefaultTags are the elements allowed when the policy lists none, what mail layouts are built with
+var DefaultTags = []string{
+	"a", "abbr", "b", "blockquote", "body", "br", "caption", "center", "code", "col", "colgroup", "div",
+	"em", "font", "h1", "h2", "h3", "h4", "h5", "h6", "head", "hr", "html", "i", "img", "li", "meta",
+	"ol", "p", "pre", "s", "small", "span", "strong", "style", "sub", "sup", "table", "tbody", "td",
+	"tfoot", "th", "thead", "title", "tr", "u", "ul",
+}
+
+// DefaultAttributes are the attributes allowed on every allowed element when the policy lists none
+var DefaultAttributes = []string{
+	"align", "alt", "bgcolor", "border", "cellpadding", "cellspacing", "charset", "class", "color",
+	"colspan", "content", "dir", "face", "height", "href", "id", "lang", "name", "rowspan", "size",
+	"src", "style", "target", "title", "valign", "width",
+}
+
+// DefaultURLSchemes are the schemes links and images may use when the policy lists none. URLs
+// without a scheme are relative and always allowed.
+var DefaultURLSchemes = []string{"http", "https", "mailto", "tel"}
+
+// droppedWithContent are the elements whose content goes with them when they are not allowed, it is
+// code or markup rather than text
+var droppedWithContent = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true, "noscript": true,
+	"template": true, "svg": true, "math": true, "frameset": true, "applet": true, "textarea": true,
+}
+
+// urlAttributes are the attributes holding a URL, checked against the allowed schemes
+var urlAttributes = map[string]bool{
+	"href": true, "src": true, "action": true, "formaction": true, "background": true, "poster": true,
+	"cite": true, "xlink:href": true,
+}
+
+// Policy is an allow-list of elements, attributes and URL schemes
+type Policy struct {
+	tags       map[string]bool
+	attributes map[string]bool
+	schemes    map[string]bool
+}
+
+// NewPolicy creates a policy allowing the tags, attributes and URL schemes, an empty list allowing
+// the defaults. Event handler attributes (on*) are never allowed.
+func NewPolicy(tags, attributes, schemes []string) *Policy {
+	return &Policy{
+		tags:       toSet(tags, DefaultTags),
+		attributes: toSet(attributes, DefaultAttributes),
+		schemes:    toSet(schemes, DefaultURLSchemes),
+	}
+}
+
+func toSet(values, defaults []string) map[string]bool {
+	if len(values) == 0 {
+		values = defaults
+	}
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[strings.ToLower(strings.TrimSpace(value))] = true
+	}
+	return set
+}
+
+var policy atomic.Pointer[Policy]
+
+func init() {
+	policy.Store(NewPolicy(nil, nil, nil))
+}
+
+// SetPolicy changes the policy of Sanitize and Check, a nil policy restores the default
+func SetPolicy(p *Policy) {
+	if p == nil {
+		p = NewPolicy(nil, nil, nil)
+	}
+	policy.Store(p)
+}
+
+// Sanitize cleans HTML with the current policy
+func Sanitize(content string) string {
+	return policy.Load().Sanitize(content)
+}
+
+// Check lists what the current policy does not allow in HTML
+func Check(content string) []string {
+	return policy.Load().Check(content)
+}
+
+// Sanitize cleans HTML: elements that are not allowed are removed, their text kept unless they hold
+// code, and so are the attributes that are not allowed, comments and doctypes
+func (p *Policy) Sanitize(content string) string {
+	var b strings.Builder
+	// the text of a style element is CSS, written as it was read
+	inStyle := false
+	p.walk(content, func(token html.Token, allowed bool) {
+		if !allowed {
+			return
+		}
+		switch token.Type {
+		case html.TextToken:
+			if inStyle {
+				b.WriteString(token.Data)
+			} else {
+				b.WriteString(html.EscapeString(token.Data))
+			}
+		case html.StartTagToken, html.SelfClosingTagToken, html.EndTagToken:
+			inStyle = token.Type == html.StartTagToken && token.Data == "style"
+			b.WriteString(token.String())
+		}
+	}, nil)
+	return b.String()
+}
+
+// Check lists what the policy does not allow in HTML, e.g. "<script>" or "onclick attribute", each
+// once in the order found. Nothing is listed for HTML the policy keeps as it is.
+func (p *Policy) Check(content string) []string {
+	var violations []string
+	p.walk(content, nil, func(violation string) {
+		if !slices.Contains(violations, violation) {
+			violations = append(violations, violation)
+		}
+	})
+	return violations
+}
+
+// walk tokenizes HTML and calls emit with every token, cleaned of the attributes the policy does not
+// allow, telling whether it is kept; violate is called with what the policy does not allow
+func (p *Policy) walk(content string, emit func(token html.Token, allowed bool), violate func(violation string)) {
+	if emit == nil {
+		emit = func(html.Token, bool) {}
+	}
+	if violate == nil {
+		violate = func(string) {}
+	}
+
+	tokenizer := html.NewTokenizer(strings.NewReader(content))
+	// skipping is the element whose content is dropped, nested in it depth times
+	var skipping string
+	depth := 0
+
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			if tokenizer.Err() != io.EOF {
+				violate("malformed HTML")
+			}
+			return
+		}
+		token := tokenizer.Token()
+
+		if skipping != "" {
+			switch {
+			case tokenType == html.StartTagToken && token.Data == skipping:
+				depth++
+			case tokenType == html.EndTagToken && token.Data == skipping:
+				depth--
+				if depth == 0 {
+					skipping = ""
+				}
+			}
+			continue
+		}
+
+		switch tokenType {
+		case html.CommentToken, html.DoctypeToken:
+			// html/template strips the comments of templates, Sanitize drops them along with doctypes
+			emit(token, false)
+
+		case html.StartTagToken, html.SelfClosingTagToken, html.EndTagToken:
+			if !p.tags[token.Data] {
+				if tokenType != html.EndTagToken {
+					violate("<" + token.Data + ">")
+				}
+				if tokenType == html.StartTagToken && droppedWithContent[token.Data] {
+					skipping, depth = token.Data, 1
+				}
+				emit(token, false)
+				continue
+			}
+			token.Attr = p.cleanAttributes(token.Attr, violate)
+			emit(token, true)
+
+		default:
+			emit(token, true)
+		}
+	}
+}
+
+// cleanAttributes keeps the allowed attributes, dropping URLs with a scheme that is not allowed
+func (p *Policy) cleanAttributes(attributes []html.Attribute, violate func(violation string)) []html.Attribute {
+	kept := attributes[:0]
+	for _, attribute := range attributes {
+		name := strings.ToLower(attribute.Key)
+		if attribute.Namespace != "" {
+			name = attribute.Namespace + ":" + name
+		}
+
+		if strings.HasPrefix(name, "on") || !p.attributes[name] {
+			violate(name + " attribute")
+			continue
+		}
+		if urlAttributes[name] && !p.allowsURL(attribute.Val) {
+			violate(fmt.Sprintf("%s URL in %s", urlScheme(attribute.Val), name))
+			continue
+		}
+		kept = append(kept, attribute)
+	}
+	return kept
+}
+
+// allowsURL tells whether a URL is relative or has an allowed scheme
+func (p *Policy) allowsURL(url string) bool {
+	scheme := urlScheme(url)
+	return scheme == "" || p.schemes[scheme]
+}
+
+// urlScheme returns the lowercase scheme of a URL, empty for a relative one. Browsers ignore control
+// characters and whitespace in schemes, so "java\tscript:" is javascript.
+func urlScheme(url string) string {
+	var b strings.Builder
+	for _, r := range url {
+		switch {
+		case r == ':':
+			return strings.ToLower(b.String())
+		case r == '/' || r == '?' || r == '#':
+			return ""
+		case r <= ' ':
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return ""
+}
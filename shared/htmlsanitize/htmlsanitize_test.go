@@ -0,0 +1,47 @@
+package htmlsanitize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy_Sanitize(t *testing.T) {
+	policy := NewPolicy(nil, nil, nil)
+
+	tests := map[string]string{
+		`<p>Hello <b>world</b></p>`:                                 `<p>Hello <b>world</b></p>`,
+		`<p onclick="x()" class="lead">Hi</p>`:                      `<p class="lead">Hi</p>`,
+		`before<script>alert(1)</script>after`:                      `beforeafter`,
+		`<iframe src="https://evil.example"><p>x</p></iframe>ok`:    `ok`,
+		`<a href="javascript:alert(1)">link</a>`:                    `<a>link</a>`,
+		`<a href=" Java&#09;Script:alert(1)">link</a>`:              `<a>link</a>`,
+		`<a href="https://example.com/?a=1&b=2">link</a>`:           `<a href="https://example.com/?a=1&amp;b=2">link</a>`,
+		`<a href="/orders/1">link</a>`:                              `<a href="/orders/1">link</a>`,
+		`<blink>kept text</blink>`:                                  `kept text`,
+		`<!-- note --><img src="https://cdn.example/a.png" alt="">`: `<img src="https://cdn.example/a.png" alt="">`,
+		`<style>p > b { color: red }</style>`:                       `<style>p > b { color: red }</style>`,
+		`5 < 6 & 7`:                                                 `5 &lt; 6 &amp; 7`,
+	}
+	for input, want := range tests {
+		assert.Equal(t, want, policy.Sanitize(input), input)
+	}
+}
+
+func TestPolicy_Check(t *testing.T) {
+	policy := NewPolicy(nil, nil, nil)
+
+	assert.Empty(t, policy.Check(`<p style="color: red">Hello {{.Name}}, <a href="{{.Link}}">open</a></p>`),
+		"template actions are left to html/template")
+
+	assert.Equal(t, []string{"<script>", "onclick attribute", "javascript URL in href"}, policy.Check(
+		`<script>x()</script><p onclick="x()">a</p><p onclick="y()">b</p><a href="javascript:x()">c</a>`))
+}
+
+func TestNewPolicy_AllowList(t *testing.T) {
+	policy := NewPolicy([]string{"P", "a"}, []string{"href"}, []string{"https"})
+
+	assert.Equal(t, `<p>a <a>b</a> <a href="https://x.example">c</a></p>`,
+		policy.Sanitize(`<p class="x">a <a href="mailto:a@b.example">b</a> <a href="https://x.example">c</a></p>`))
+	assert.Equal(t, []string{"<div>", "class attribute"}, policy.Check(`<div>a <a class="x">b</a></div>`))
+}
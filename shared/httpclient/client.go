@@ -0,0 +1,231 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// ErrCircuitOpen is returned without calling the provider while its circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// Config configures a resilient client for a single outbound provider
+type Config struct {
+	// Name identifies the provider, e.g. "sendgrid"
+	Name string
+	// Timeout bounds a single attempt, including reading the response body
+	Timeout time.Duration
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries   int
+	RetryWaitMin time.Duration
+	RetryWaitMax time.Duration
+	// MaxConnsPerHost and MaxIdleConnsPerHost size the connection pool kept for each provider host
+	MaxConnsPerHost     int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+	// BreakerFailures is the number of consecutive failures that opens the circuit of a host
+	BreakerFailures uint32
+	// BreakerOpenTimeout is how long the circuit stays open before letting a probe request through
+	BreakerOpenTimeout time.Duration
+}
+
+// DefaultConfig returns sane defaults for a provider API
+func DefaultConfig(name string) Config {
+	return Config{
+		Name:                name,
+		Timeout:             10 * time.Second,
+		MaxRetries:          2,
+		RetryWaitMin:        100 * time.Millisecond,
+		RetryWaitMax:        2 * time.Second,
+		MaxConnsPerHost:     50,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+		BreakerFailures:     5,
+		BreakerOpenTimeout:  30 * time.Second,
+	}
+}
+
+// Client wraps http.Client with per-attempt timeouts, retries with jittered backoff
+// and a circuit breaker per host. Provider adapters must use it instead of a bare http.Client.
+type Client struct {
+	cfg        Config
+	httpClient *http.Client
+
+	mu       sync.Mutex
+	breakers map[string]*gobreaker.TwoStepCircuitBreaker
+
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// New creates a resilient client
+func New(cfg Config) *Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxConnsPerHost = cfg.MaxConnsPerHost
+	transport.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	transport.IdleConnTimeout = cfg.IdleConnTimeout
+
+	return &Client{
+		cfg:        cfg,
+		httpClient: &http.Client{Transport: transport, Timeout: cfg.Timeout},
+		breakers:   make(map[string]*gobreaker.TwoStepCircuitBreaker),
+		sleep:      sleepContext,
+	}
+}
+
+type retryKey struct{}
+
+// WithRetry lets the calls made with ctx be retried whatever their method, for provider endpoints that
+// are safe to call twice although they are not idempotent by HTTP semantics
+func WithRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryKey{}, true)
+}
+
+// Do sends the request, retrying transport errors, 429 and 5xx responses.
+// Only idempotent methods are retried: a timeout or a 5xx may come after the provider acted, and
+// sending a POST again would send a second SMS or open a second ticket. Other methods are retried
+// when the request has an Idempotency-Key header or its context comes from WithRetry.
+// Requests with a body are only retried when the body can be replayed (req.GetBody is set,
+// which http.NewRequest does for in-memory bodies).
+func (c *Client) Do(req *http.Request) (resp *http.Response, err error) {
+	breaker := c.breaker(req.URL.Host)
+
+	start := time.Now()
+	attempt := 0
+	defer func() {
+		c.observe(req, resp, err, attempt, time.Since(start))
+	}()
+
+	for ; ; attempt++ {
+		if attempt > 0 && req.Body != nil {
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return nil, fmt.Errorf("failed to rewind %s request body: %w", c.cfg.Name, bodyErr)
+			}
+			req.Body = body
+		}
+
+		resp, err = c.attempt(req, breaker)
+		if errors.Is(err, ErrCircuitOpen) || !c.canRetry(req, attempt, resp, err) {
+			return resp, err
+		}
+
+		wait := c.backoff(attempt, resp)
+		if resp != nil {
+			// drain so the connection goes back to the pool
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if sleepErr := c.sleep(req.Context(), wait); sleepErr != nil {
+			return nil, sleepErr
+		}
+	}
+}
+
+func (c *Client) attempt(req *http.Request, breaker *gobreaker.TwoStepCircuitBreaker) (*http.Response, error) {
+	done, err := breaker.Allow()
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", c.cfg.Name, req.URL.Host, ErrCircuitOpen)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	done(err == nil && !isRetryableStatus(resp.StatusCode))
+
+	if err != nil {
+		return nil, fmt.Errorf("%s request failed: %w", c.cfg.Name, err)
+	}
+	return resp, nil
+}
+
+func (c *Client) canRetry(req *http.Request, attempt int, resp *http.Response, err error) bool {
+	if attempt >= c.cfg.MaxRetries || req.Context().Err() != nil {
+		return false
+	}
+	if !isReplayable(req) {
+		return false
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return false
+	}
+	if err != nil {
+		var netErr net.Error
+		return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)
+	}
+	return isRetryableStatus(resp.StatusCode)
+}
+
+// backoff returns the wait before the next attempt: the Retry-After header when the provider
+// sends one, otherwise exponential backoff with full jitter
+func (c *Client) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return min(time.Duration(seconds)*time.Second, c.cfg.RetryWaitMax)
+		}
+	}
+
+	ceiling := min(c.cfg.RetryWaitMin<<attempt, c.cfg.RetryWaitMax)
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int64N(int64(ceiling)))
+}
+
+func (c *Client) breaker(host string) *gobreaker.TwoStepCircuitBreaker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	breaker, ok := c.breakers[host]
+	if !ok {
+		failures := c.cfg.BreakerFailures
+		breaker = gobreaker.NewTwoStepCircuitBreaker(gobreaker.Settings{
+			Name:    c.cfg.Name + ":" + host,
+			Timeout: c.cfg.BreakerOpenTimeout,
+			ReadyToTrip: func(counts gobreaker.Counts) bool {
+				return failures > 0 && counts.ConsecutiveFailures >= failures
+			},
+			OnStateChange: func(_ string, from, to gobreaker.State) {
+				c.observeBreaker(host, from, to)
+			},
+		})
+		c.breakers[host] = breaker
+	}
+	return breaker
+}
+
+// isReplayable tells whether sending req twice has the effect of sending it once
+func isReplayable(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	if req.Header.Get("Idempotency-Key") != "" {
+		return true
+	}
+	retry, _ := req.Context().Value(retryKey{}).(bool)
+	return retry
+}
+
+func isRetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,192 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init(&logger.Config{Output: io.Discard})
+	os.Exit(m.Run())
+}
+
+func newTestClient(cfg Config) (*Client, *[]time.Duration) {
+	client := New(cfg)
+	var waits []time.Duration
+	client.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return ctx.Err()
+	}
+	return client, &waits
+}
+
+func statusSequence(t *testing.T, statuses ...int) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "payload", string(body))
+
+		status := statuses[len(statuses)-1]
+		if int(n) <= len(statuses) {
+			status = statuses[n-1]
+		}
+		if status == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "1")
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func send(t *testing.T, client *Client, method, url string) (*http.Response, error) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader("payload"))
+	require.NoError(t, err)
+	return client.Do(req)
+}
+
+func TestClient_Do(t *testing.T) {
+	t.Run("retries 5xx and 429 until success", func(t *testing.T) {
+		server, calls := statusSequence(t, http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK)
+		client, waits := newTestClient(DefaultConfig("test"))
+
+		resp, err := send(t, client, http.MethodPut, server.URL)
+
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.EqualValues(t, 3, *calls)
+		require.Len(t, *waits, 2)
+		assert.Less(t, (*waits)[0], DefaultConfig("test").RetryWaitMin)
+		assert.Equal(t, time.Second, (*waits)[1], "Retry-After is honoured")
+	})
+
+	t.Run("returns the last response once retries are exhausted", func(t *testing.T) {
+		server, calls := statusSequence(t, http.StatusBadGateway)
+		client, _ := newTestClient(DefaultConfig("test"))
+
+		resp, err := send(t, client, http.MethodPut, server.URL)
+
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+		assert.EqualValues(t, 3, *calls)
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		server, calls := statusSequence(t, http.StatusBadRequest)
+		client, _ := newTestClient(DefaultConfig("test"))
+
+		resp, err := send(t, client, http.MethodPut, server.URL)
+
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.EqualValues(t, 1, *calls)
+	})
+
+	t.Run("does not retry bodies that cannot be replayed", func(t *testing.T) {
+		server, calls := statusSequence(t, http.StatusServiceUnavailable)
+		client, _ := newTestClient(DefaultConfig("test"))
+
+		req, err := http.NewRequest(http.MethodPut, server.URL, io.NopCloser(strings.NewReader("payload")))
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.EqualValues(t, 1, *calls)
+	})
+
+	t.Run("does not retry non-idempotent methods", func(t *testing.T) {
+		server, calls := statusSequence(t, http.StatusServiceUnavailable, http.StatusOK)
+		client, _ := newTestClient(DefaultConfig("test"))
+
+		resp, err := send(t, client, http.MethodPost, server.URL)
+
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.EqualValues(t, 1, *calls, "the provider may have acted already")
+	})
+
+	t.Run("retries non-idempotent methods with an idempotency key", func(t *testing.T) {
+		server, calls := statusSequence(t, http.StatusServiceUnavailable, http.StatusOK)
+		client, _ := newTestClient(DefaultConfig("test"))
+
+		req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("payload"))
+		require.NoError(t, err)
+		req.Header.Set("Idempotency-Key", "order-42")
+		resp, err := client.Do(req)
+
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.EqualValues(t, 2, *calls)
+	})
+
+	t.Run("opens the circuit after consecutive failures", func(t *testing.T) {
+		server, calls := statusSequence(t, http.StatusInternalServerError)
+		cfg := DefaultConfig("test")
+		cfg.MaxRetries = 0
+		cfg.BreakerFailures = 2
+		cfg.BreakerOpenTimeout = time.Hour
+		client, _ := newTestClient(cfg)
+
+		for i := 0; i < 2; i++ {
+			resp, err := send(t, client, http.MethodPut, server.URL)
+			require.NoError(t, err)
+			resp.Body.Close()
+		}
+
+		_, err := send(t, client, http.MethodPut, server.URL)
+		assert.True(t, errors.Is(err, ErrCircuitOpen))
+		assert.EqualValues(t, 2, *calls)
+	})
+
+	t.Run("times out a hanging attempt", func(t *testing.T) {
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer server.Close()
+		defer close(release)
+		cfg := DefaultConfig("test")
+		cfg.Timeout = 20 * time.Millisecond
+		cfg.MaxRetries = 0
+		client, _ := newTestClient(cfg)
+
+		_, err := send(t, client, http.MethodPut, server.URL)
+		assert.Error(t, err)
+	})
+}
+
+func TestClient_Metrics(t *testing.T) {
+	server, _ := statusSequence(t, http.StatusServiceUnavailable, http.StatusOK)
+	client, _ := newTestClient(DefaultConfig("metrics-test"))
+
+	req, err := http.NewRequestWithContext(WithRetry(WithEndpoint(context.Background(), "send_mail")), http.MethodPost, server.URL, strings.NewReader("payload"))
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(requestsTotal.WithLabelValues("metrics-test", "send_mail", "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(requestRetries.WithLabelValues("metrics-test", "send_mail")))
+}
@@ -0,0 +1,95 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sony/gobreaker"
+)
+
+var (
+	requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "tixgo_outbound_requests_total",
+		Help: "Outbound provider calls by provider, endpoint and final status.",
+	}, []string{"provider", "endpoint", "status"})
+
+	requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "tixgo_outbound_request_duration_seconds",
+		Help:    "Duration of outbound provider calls, retries and backoff included.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"provider", "endpoint"})
+
+	requestRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "tixgo_outbound_request_retries_total",
+		Help: "Retries of outbound provider calls.",
+	}, []string{"provider", "endpoint"})
+
+	breakerOpen = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "tixgo_outbound_circuit_open",
+		Help: "1 while the circuit breaker of a provider host is open or half-open.",
+	}, []string{"provider", "host"})
+)
+
+type endpointKey struct{}
+
+// WithEndpoint names the provider endpoint of the calls made with ctx, e.g. "send_mail".
+// The name is used in logs and metrics instead of the raw path so ids in URLs do not explode label cardinality.
+func WithEndpoint(ctx context.Context, endpoint string) context.Context {
+	return context.WithValue(ctx, endpointKey{}, endpoint)
+}
+
+func endpointName(req *http.Request) string {
+	if endpoint, ok := req.Context().Value(endpointKey{}).(string); ok && endpoint != "" {
+		return endpoint
+	}
+	return req.URL.Host
+}
+
+// observe logs the outcome of a call and records its metrics
+func (c *Client) observe(req *http.Request, resp *http.Response, err error, retries int, duration time.Duration) {
+	endpoint := endpointName(req)
+
+	status := "error"
+	if resp != nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+
+	requestsTotal.WithLabelValues(c.cfg.Name, endpoint, status).Inc()
+	requestDuration.WithLabelValues(c.cfg.Name, endpoint).Observe(duration.Seconds())
+	if retries > 0 {
+		requestRetries.WithLabelValues(c.cfg.Name, endpoint).Add(float64(retries))
+	}
+
+	fields := []*logger.Field{
+		logger.F("provider", c.cfg.Name),
+		logger.F("endpoint", endpoint),
+		logger.F("method", req.Method),
+		logger.F("status", status),
+		logger.F("duration", duration),
+		logger.F("retries", retries),
+	}
+	if err != nil || resp.StatusCode >= http.StatusInternalServerError {
+		logger.Warning(req.Context(), "Outbound request failed", append(fields, logger.F("error", err))...)
+		return
+	}
+	logger.Info(req.Context(), "Outbound request", fields...)
+}
+
+func (c *Client) observeBreaker(host string, from, to gobreaker.State) {
+	open := 0.0
+	if to != gobreaker.StateClosed {
+		open = 1
+	}
+	breakerOpen.WithLabelValues(c.cfg.Name, host).Set(open)
+
+	logger.Warning(context.Background(), "Outbound circuit breaker state changed",
+		logger.F("provider", c.cfg.Name),
+		logger.F("host", host),
+		logger.F("from", from.String()),
+		logger.F("to", to.String()))
+}
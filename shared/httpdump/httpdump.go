@@ -0,0 +1,99 @@
+// Package httpdump logs whole requests and responses, to debug clients against a local or staging API.
+// Bodies are logged as sent, credentials included, so it must never be switched on in production.
+package httpdump
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// MaxBody is the number of bytes of a body that are logged, the rest is cut
+const MaxBody = 4 << 10
+
+// maskedHeaders are the headers whose values are left out of the dumps
+var maskedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// Middleware logs the method, path, headers and body of every request, then the status, headers and
+// body of its response. Credentials in headers are masked, bodies are cut at MaxBody.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var requestBody []byte
+		if c.Request.Body != nil {
+			body, err := io.ReadAll(c.Request.Body)
+			if err != nil {
+				c.Error(err)
+				c.Abort()
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+			requestBody = body
+		}
+
+		logger.Info(c.Request.Context(), "Request dump",
+			logger.F("method", c.Request.Method),
+			logger.F("path", c.Request.URL.RequestURI()),
+			logger.F("headers", dumpHeaders(c.Request.Header)),
+			logger.F("body", dumpBody(requestBody)))
+
+		writer := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		logger.Info(c.Request.Context(), "Response dump",
+			logger.F("method", c.Request.Method),
+			logger.F("path", c.Request.URL.RequestURI()),
+			logger.F("status", writer.Status()),
+			logger.F("headers", dumpHeaders(writer.Header())),
+			logger.F("body", dumpBody(writer.body.Bytes())))
+	}
+}
+
+// recordingWriter keeps the first MaxBody bytes of a response, streams go through all the same
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.record(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.record([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *recordingWriter) record(data []byte) {
+	if room := MaxBody + 1 - w.body.Len(); room > 0 {
+		w.body.Write(data[:min(len(data), room)])
+	}
+}
+
+// dumpHeaders flattens headers for the log, masking credentials
+func dumpHeaders(header http.Header) map[string]string {
+	dumped := make(map[string]string, len(header))
+	for name, values := range header {
+		dumped[name] = strings.Join(values, ", ")
+	}
+	for _, name := range maskedHeaders {
+		if _, ok := dumped[name]; ok {
+			dumped[name] = "********"
+		}
+	}
+	return dumped
+}
+
+// dumpBody returns a body for the log, cut at MaxBody
+func dumpBody(body []byte) string {
+	if len(body) > MaxBody {
+		return string(body[:MaxBody]) + "...(truncated)"
+	}
+	return string(body)
+}
@@ -0,0 +1,95 @@
+package httpdump
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var logs bytes.Buffer
+
+func TestMain(m *testing.M) {
+	logger.Init(&logger.Config{Output: &logs})
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+// dumps returns the dumps logged since the last call, by message
+func dumps(t *testing.T) map[string]map[string]interface{} {
+	t.Helper()
+	defer logs.Reset()
+
+	byMessage := map[string]map[string]interface{}{}
+	scanner := bufio.NewScanner(&logs)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		byMessage[line["msg"].(string)] = line
+	}
+	return byMessage
+}
+
+func TestMiddleware(t *testing.T) {
+	router := gin.New()
+	router.Use(Middleware())
+	router.POST("/echo", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.Header("Set-Cookie", "session=secret")
+		c.Data(http.StatusCreated, "application/json", body)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/echo?dry_run=true", strings.NewReader(`{"name":"tixgo"}`))
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `{"name":"tixgo"}`, w.Body.String(), "the handler reads the dumped body")
+
+	logged := dumps(t)
+	request, response := logged["Request dump"], logged["Response dump"]
+	require.NotNil(t, request)
+	require.NotNil(t, response)
+
+	assert.Equal(t, "/echo?dry_run=true", request["path"])
+	assert.Equal(t, `{"name":"tixgo"}`, request["body"])
+	assert.Equal(t, "********", request["headers"].(map[string]interface{})["Authorization"])
+	assert.Equal(t, "application/json", request["headers"].(map[string]interface{})["Content-Type"])
+
+	assert.EqualValues(t, http.StatusCreated, response["status"])
+	assert.Equal(t, `{"name":"tixgo"}`, response["body"])
+	assert.Equal(t, "********", response["headers"].(map[string]interface{})["Set-Cookie"])
+}
+
+func TestMiddleware_Truncates(t *testing.T) {
+	router := gin.New()
+	router.Use(Middleware())
+	router.POST("/large", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	})
+
+	large := strings.Repeat("x", MaxBody*2)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/large", strings.NewReader(large)))
+
+	assert.Equal(t, large, w.Body.String(), "the whole response goes out")
+
+	logged := dumps(t)
+	truncated := strings.Repeat("x", MaxBody) + "...(truncated)"
+	assert.Equal(t, truncated, logged["Request dump"]["body"])
+	assert.Equal(t, truncated, logged["Response dump"]["body"])
+}
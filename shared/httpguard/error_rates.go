@@ -0,0 +1,127 @@
+package httpguard
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// DefaultErrorRateWindow is the window error rates are computed over when none is given
+const DefaultErrorRateWindow = 15 * time.Minute
+
+var responsesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "tixgo_http_responses_total",
+	Help: "Responses by status class: 2xx, 3xx, 4xx or 5xx.",
+}, []string{"class"})
+
+// responseBucket counts the responses of a minute
+type responseBucket struct {
+	minute       int64
+	requests     int64
+	clientErrors int64
+	serverErrors int64
+}
+
+// ErrorRateReport is the share of the responses of the window that were errors
+type ErrorRateReport struct {
+	Window       time.Duration
+	Requests     int64
+	ClientErrors int64
+	ServerErrors int64
+	// ClientErrorRate and ServerErrorRate are the shares of 4xx and 5xx responses, 0 without requests
+	ClientErrorRate float64
+	ServerErrorRate float64
+}
+
+// ErrorRates counts the responses of this instance by the minute over a sliding window, for dashboards
+// wanting the error rates without querying prometheus. Every response is counted in
+// tixgo_http_responses_total as well, which aggregates the instances.
+type ErrorRates struct {
+	mu      sync.Mutex
+	buckets []responseBucket
+	now     func() time.Time
+}
+
+// NewErrorRates creates error rates over window, rounded up to the minute, DefaultErrorRateWindow when
+// it is not positive
+func NewErrorRates(window time.Duration) *ErrorRates {
+	if window <= 0 {
+		window = DefaultErrorRateWindow
+	}
+	minutes := int((window + time.Minute - 1) / time.Minute)
+	return &ErrorRates{
+		buckets: make([]responseBucket, minutes),
+		now:     time.Now,
+	}
+}
+
+// Middleware counts the status of every response once the handlers and error middleware after it ran,
+// except those of the routes skipped, like the metrics scraped and the health probes
+func (r *ErrorRates) Middleware(skip ...string) gin.HandlerFunc {
+	skipped := make(map[string]bool, len(skip))
+	for _, route := range skip {
+		skipped[route] = true
+	}
+
+	return func(c *gin.Context) {
+		c.Next()
+
+		if skipped[c.FullPath()] {
+			return
+		}
+		r.Record(c.Writer.Status())
+	}
+}
+
+// Record counts a response of status
+func (r *ErrorRates) Record(status int) {
+	responsesTotal.WithLabelValues(strconv.Itoa(status/100) + "xx").Inc()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bucket := r.bucket(r.now().Unix() / 60)
+	bucket.requests++
+	switch {
+	case status >= 500:
+		bucket.serverErrors++
+	case status >= 400:
+		bucket.clientErrors++
+	}
+}
+
+// bucket returns the bucket of minute, emptied when it last counted an older minute
+func (r *ErrorRates) bucket(minute int64) *responseBucket {
+	bucket := &r.buckets[minute%int64(len(r.buckets))]
+	if bucket.minute != minute {
+		*bucket = responseBucket{minute: minute}
+	}
+	return bucket
+}
+
+// Report sums the responses of the window up to now
+func (r *ErrorRates) Report() ErrorRateReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := ErrorRateReport{Window: time.Duration(len(r.buckets)) * time.Minute}
+	oldest := r.now().Unix()/60 - int64(len(r.buckets)) + 1
+	for _, bucket := range r.buckets {
+		if bucket.minute < oldest {
+			continue
+		}
+		report.Requests += bucket.requests
+		report.ClientErrors += bucket.clientErrors
+		report.ServerErrors += bucket.serverErrors
+	}
+
+	if report.Requests > 0 {
+		report.ClientErrorRate = float64(report.ClientErrors) / float64(report.Requests)
+		report.ServerErrorRate = float64(report.ServerErrors) / float64(report.Requests)
+	}
+	return report
+}
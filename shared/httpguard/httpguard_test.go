@@ -0,0 +1,168 @@
+package httpguard
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"tixgo/shared/httpresponse"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/syserr"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init(&logger.Config{Output: io.Discard})
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+type envelope struct {
+	IsError bool   `json:"is_error"`
+	Code    string `json:"code"`
+}
+
+func serve(t *testing.T, router *gin.Engine, path string) (*httptest.ResponseRecorder, envelope) {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var body envelope
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	return w, body
+}
+
+func TestTimeouts_For(t *testing.T) {
+	timeouts := Timeouts{
+		Default: 5 * time.Second,
+		Routes: map[string]time.Duration{
+			"/templates":               10 * time.Second,
+			"/templates/render-batch/": 30 * time.Second,
+			"/events/:id/seats/stream": 0,
+		},
+	}
+
+	assert.Equal(t, 5*time.Second, timeouts.For("/users/me"))
+	assert.Equal(t, 10*time.Second, timeouts.For("/templates"))
+	assert.Equal(t, 10*time.Second, timeouts.For("/templates/:id"))
+	assert.Equal(t, 30*time.Second, timeouts.For("/templates/render-batch"))
+	assert.Equal(t, 5*time.Second, timeouts.For("/templatesx"))
+	assert.Equal(t, time.Duration(0), timeouts.For("/events/:id/seats/stream"))
+	assert.Equal(t, 5*time.Second, timeouts.For("/events/:id/seats"))
+}
+
+func TestTimeout(t *testing.T) {
+	router := gin.New()
+	router.Use(httpresponse.Middleware(), httpresponse.ErrorHandler(nil))
+
+	api := router.Group("/v1")
+	api.Use(Timeout(api.BasePath(), Timeouts{
+		Default: 20 * time.Millisecond,
+		Routes:  map[string]time.Duration{"/stream": 0},
+	}))
+
+	// a handler failing on the cancelled context, as on a cancelled query
+	api.GET("/slow", func(c *gin.Context) {
+		<-c.Request.Context().Done()
+		c.Error(syserr.Wrap(c.Request.Context().Err(), syserr.InternalCode, "failed to query"))
+	})
+	api.GET("/fast", func(c *gin.Context) {
+		httpresponse.Success(c, http.StatusOK, "done")
+	})
+	api.GET("/stream", func(c *gin.Context) {
+		_, hasDeadline := c.Request.Context().Deadline()
+		httpresponse.Success(c, http.StatusOK, hasDeadline)
+	})
+
+	before := testutil.ToFloat64(timeoutsTotal.WithLabelValues("/v1/slow"))
+	w, body := serve(t, router, "/v1/slow")
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.True(t, body.IsError)
+	assert.Equal(t, string(TimeoutCode), body.Code)
+	assert.Equal(t, before+1, testutil.ToFloat64(timeoutsTotal.WithLabelValues("/v1/slow")))
+
+	w, body = serve(t, router, "/v1/fast")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, body.IsError)
+
+	w, _ = serve(t, router, "/v1/stream")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `false`, string(mustData(t, w)))
+}
+
+func TestRecovery(t *testing.T) {
+	router := gin.New()
+	router.Use(httpresponse.Middleware(), httpresponse.ErrorHandler(nil), Recovery())
+	router.GET("/panic/:id", func(c *gin.Context) {
+		var m map[string]int
+		m["boom"]++
+	})
+	router.GET("/abort", func(c *gin.Context) {
+		panic(http.ErrAbortHandler)
+	})
+
+	before := testutil.ToFloat64(panicsTotal.WithLabelValues("/panic/:id"))
+	w, body := serve(t, router, "/panic/1")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.True(t, body.IsError)
+	assert.Equal(t, string(syserr.InternalCode), body.Code)
+	assert.Equal(t, before+1, testutil.ToFloat64(panicsTotal.WithLabelValues("/panic/:id")))
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/abort", nil))
+	})
+}
+
+func mustData(t *testing.T, w *httptest.ResponseRecorder) json.RawMessage {
+	t.Helper()
+
+	var body struct {
+		Data json.RawMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	return body.Data
+}
+
+func TestErrorRates(t *testing.T) {
+	now := time.Date(2026, 11, 1, 10, 0, 30, 0, time.UTC)
+	rates := NewErrorRates(90 * time.Second)
+	rates.now = func() time.Time { return now }
+
+	router := gin.New()
+	router.Use(rates.Middleware("/metrics"))
+	router.GET("/ok", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/missing", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+	router.GET("/broken", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+	router.GET("/metrics", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+
+	for _, path := range []string{"/ok", "/ok", "/missing", "/broken", "/metrics"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	report := rates.Report()
+	assert.Equal(t, 2*time.Minute, report.Window, "the window is rounded up to the minute")
+	assert.Equal(t, int64(4), report.Requests, "skipped routes are not counted")
+	assert.Equal(t, 0.25, report.ClientErrorRate)
+	assert.Equal(t, 0.25, report.ServerErrorRate)
+
+	now = now.Add(time.Minute)
+	rates.Record(http.StatusOK)
+	assert.Equal(t, int64(5), rates.Report().Requests)
+
+	now = now.Add(time.Minute)
+	report = rates.Report()
+	assert.Equal(t, int64(1), report.Requests, "minutes past the window are dropped")
+	assert.Zero(t, report.ServerErrorRate)
+
+	now = now.Add(time.Hour)
+	assert.Equal(t, ErrorRateReport{Window: 2 * time.Minute}, rates.Report())
+}
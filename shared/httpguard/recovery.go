@@ -0,0 +1,57 @@
+package httpguard
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"tixgo/shared/httpresponse"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/syserr"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var panicsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "tixgo_http_panics_total",
+	Help: "Panics recovered from HTTP handlers, by route.",
+}, []string{"route"})
+
+// Recovery answers a handler panic with an internal error in the response envelope and records it,
+// with the panic value and the full stack, in the logs and the panic metrics. It goes after the
+// response middleware; the router's own recovery stays the last resort for the middleware before it.
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// handlers abort streams they can't finish this way, the server closes the connection
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			route := c.FullPath()
+			if route == "" {
+				route = "unmatched"
+			}
+			panicsTotal.WithLabelValues(route).Inc()
+			logger.Error(c.Request.Context(), "Recovered from handler panic",
+				logger.F("route", route),
+				logger.F("method", c.Request.Method),
+				logger.F("panic", fmt.Sprint(recovered)),
+				logger.F("stack", string(debug.Stack())))
+
+			c.Abort()
+			c.Errors = c.Errors[:0]
+			if !c.Writer.Written() {
+				httpresponse.Error(c, http.StatusInternalServerError, string(syserr.InternalCode), "internal server error", nil)
+			}
+		}()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,85 @@
+// Package httpguard keeps one slow or broken handler from taking the API down with it: requests get a
+// time limit, and handler panics are answered and recorded instead of dropping the connection. The
+// error rates of the responses are counted to tell when they do.
+package httpguard
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"tixgo/shared/httpresponse"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/syserr"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// TimeoutCode is the error code of requests that ran out of time
+const TimeoutCode syserr.Code = "timeout"
+
+var timeoutsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "tixgo_http_timeouts_total",
+	Help: "Requests answered with 504 after running out of time, by route.",
+}, []string{"route"})
+
+// Timeouts are the time limits of the routes of a group: Default, unless Routes holds the route or one
+// of its parents, the longest match winning. Routes are keyed by their pattern relative to the group,
+// like "/templates" or "/events/:id/seats/stream"; a zero limit lifts it, for streams.
+type Timeouts struct {
+	Default time.Duration
+	Routes  map[string]time.Duration
+}
+
+// For returns the limit of a route relative to the group
+func (t Timeouts) For(route string) time.Duration {
+	limit, matched := t.Default, -1
+	for prefix, timeout := range t.Routes {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if route != prefix && !strings.HasPrefix(route, prefix+"/") {
+			continue
+		}
+		if len(prefix) > matched {
+			limit, matched = timeout, len(prefix)
+		}
+	}
+	return limit
+}
+
+// Timeout cancels the context of the requests of the group at basePath once their limit is over.
+// Handlers stop at their next context check, a database query for most; if one returns without having
+// responded, the request is answered with 504 and TimeoutCode whatever error the cancellation caused.
+func Timeout(basePath string, timeouts Timeouts) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := strings.TrimPrefix(c.FullPath(), strings.TrimSuffix(basePath, "/"))
+		limit := timeouts.For(route)
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), limit)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) || c.Writer.Written() {
+			return
+		}
+
+		timeoutsTotal.WithLabelValues(c.FullPath()).Inc()
+		logger.Warning(ctx, "Request timed out",
+			logger.F("route", c.FullPath()),
+			logger.F("method", c.Request.Method),
+			logger.F("timeout", limit))
+
+		// the errors are the cancellation surfacing in the handler, the timeout is the answer
+		c.Errors = c.Errors[:0]
+		httpresponse.Error(c, http.StatusGatewayTimeout, string(TimeoutCode), "the request took too long", nil)
+	}
+}
@@ -0,0 +1,84 @@
+package httpresponse
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CachePolicy tells shared caches and CDNs how long a response may be served
+type CachePolicy struct {
+	// MaxAge is how long the response is fresh
+	MaxAge time.Duration
+	// StaleWhileRevalidate is how long a stale response may still be served while it is refetched
+	StaleWhileRevalidate time.Duration
+}
+
+// CacheControl returns the Cache-Control header value of the policy
+func (p CachePolicy) CacheControl() string {
+	value := fmt.Sprintf("public, max-age=%d", int(p.MaxAge.Seconds()))
+	if p.StaleWhileRevalidate > 0 {
+		value += fmt.Sprintf(", stale-while-revalidate=%d", int(p.StaleWhileRevalidate.Seconds()))
+	}
+	return value
+}
+
+// Cacheable writes data like Success, with the Cache-Control header of policy and an ETag of data.
+// A client or CDN that already has data gets a bodiless 304 Not Modified. The ETag is weak since it
+// covers data and not the response metadata.
+func Cacheable(c *gin.Context, data interface{}, policy CachePolicy) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to encode response: %w", err))
+		return
+	}
+	if notModified(c, body, policy) {
+		return
+	}
+
+	Success(c, http.StatusOK, json.RawMessage(body))
+}
+
+// CacheableData writes body as is like Cacheable, for the documents crawlers read that are not wrapped
+// in the response envelope
+func CacheableData(c *gin.Context, contentType string, body []byte, policy CachePolicy) {
+	if notModified(c, body, policy) {
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, body)
+}
+
+// notModified sets the caching headers of body and writes a 304 Not Modified when the client already
+// has it
+func notModified(c *gin.Context, body []byte, policy CachePolicy) bool {
+	sum := sha256.Sum256(body)
+	etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", policy.CacheControl())
+
+	if !etagMatches(c.GetHeader("If-None-Match"), etag) {
+		return false
+	}
+	c.Status(http.StatusNotModified)
+	c.Writer.WriteHeaderNow()
+	return true
+}
+
+// etagMatches reports whether the If-None-Match header lists etag, comparing weakly
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
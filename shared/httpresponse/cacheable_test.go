@@ -0,0 +1,74 @@
+package httpresponse
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheable(t *testing.T) {
+	policy := CachePolicy{MaxAge: 5 * time.Second, StaleWhileRevalidate: 30 * time.Second}
+	router := gin.New()
+	router.Use(Middleware())
+	router.GET("/event", func(c *gin.Context) {
+		Cacheable(c, gin.H{"title": "Concert"}, policy)
+	})
+
+	request := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/event", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := request("")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "public, max-age=5, stale-while-revalidate=30", rec.Header().Get("Cache-Control"))
+	etag := rec.Header().Get("ETag")
+	assert.Regexp(t, `^W/"[0-9a-f]{32}"$`, etag)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, map[string]interface{}{"title": "Concert"}, body["data"])
+
+	rec = request(`"other", ` + etag)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+	assert.Equal(t, etag, rec.Header().Get("ETag"))
+
+	assert.Equal(t, http.StatusNotModified, request(etag[2:]).Code, "compared weakly")
+	assert.Equal(t, http.StatusOK, request(`W/"other"`).Code)
+}
+
+func TestCacheableData(t *testing.T) {
+	policy := CachePolicy{MaxAge: time.Hour}
+	router := gin.New()
+	router.Use(Middleware())
+	router.GET("/sitemap.xml", func(c *gin.Context) {
+		CacheableData(c, "application/xml; charset=utf-8", []byte("<urlset/>"), policy)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "<urlset/>", rec.Body.String())
+	assert.Equal(t, "application/xml; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "public, max-age=3600", rec.Header().Get("Cache-Control"))
+
+	req = httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil)
+	req.Header.Set("If-None-Match", rec.Header().Get("ETag"))
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+}
@@ -0,0 +1,81 @@
+package httpresponse
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/syserr"
+	"github.com/gin-gonic/gin"
+)
+
+// DebugHeader is how a client asks for the debug details of errors where a DebugPolicy requires it
+const DebugHeader = "X-Debug-Errors"
+
+// DebugPolicy decides whether the error response of a request carries the debug details of the error
+type DebugPolicy func(c *gin.Context) bool
+
+// ErrorDebug is the cause chain of an error with the stack where it originated
+type ErrorDebug struct {
+	Chain []ErrorCause `json:"chain"`
+	Stack []string     `json:"stack,omitempty"`
+}
+
+// ErrorCause is one error of a cause chain, outermost first
+type ErrorCause struct {
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+// ErrorHandler writes the last error of the request in the response envelope. It takes over from
+// the router's default error handler, which sits before it in the chain: the handled errors are
+// cleared so that one does not write a second body. Responses get the debug details of the error
+// when debug says so; a nil debug never exposes them.
+func ErrorHandler(debug DebugPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		c.Errors = c.Errors[:0]
+
+		var errorDebug *ErrorDebug
+		if debug != nil && debug(c) {
+			errorDebug = NewErrorDebug(err)
+		}
+
+		var sysErr *syserr.Error
+		if errors.As(err, &sysErr) {
+			writeError(c, http.StatusOK, string(sysErr.Code()), sysErr.Error(), nil, errorDebug)
+			return
+		}
+
+		// log error
+		logger.LogError(c.Request.Context(), err)
+
+		// Default error
+		writeError(c, http.StatusOK, "internal_error", "An error occurred", nil, errorDebug)
+	}
+}
+
+// NewErrorDebug unwraps err into its cause chain. The stack is the one of the innermost system error,
+// captured where the failure was first reported.
+func NewErrorDebug(err error) *ErrorDebug {
+	debug := &ErrorDebug{}
+	for ; err != nil; err = errors.Unwrap(err) {
+		sysErr, ok := err.(*syserr.Error)
+		if !ok {
+			debug.Chain = append(debug.Chain, ErrorCause{Message: err.Error()})
+			continue
+		}
+
+		debug.Chain = append(debug.Chain, ErrorCause{Code: string(sysErr.Code()), Message: sysErr.Message})
+		if stack := sysErr.StackFormatted(); len(stack) > 0 {
+			debug.Stack = stack
+		}
+	}
+	return debug
+}
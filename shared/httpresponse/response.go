@@ -0,0 +1,80 @@
+package httpresponse
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	pkgContext "github.com/duongptryu/gox/context"
+	"github.com/gin-gonic/gin"
+)
+
+const startedAtKey = "httpresponse.started_at"
+
+// Meta is attached to every response so support can correlate it with logs
+type Meta struct {
+	RequestID string `json:"request_id,omitempty"`
+	// DurationMs is the server time spent on the request until the response was written
+	DurationMs float64 `json:"duration_ms"`
+}
+
+// successEnvelope is the body of successful JSON responses
+type successEnvelope struct {
+	IsError bool        `json:"is_error"`
+	Data    interface{} `json:"data"`
+	Paging  interface{} `json:"paging,omitempty"`
+	Filter  interface{} `json:"filter,omitempty"`
+	Meta    Meta        `json:"meta"`
+}
+
+// errorEnvelope is the body of error JSON responses
+type errorEnvelope struct {
+	IsError bool        `json:"is_error"`
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
+	Debug   *ErrorDebug `json:"debug,omitempty"`
+	Meta    Meta        `json:"meta"`
+}
+
+// Middleware records when the request started and echoes its request ID in the X-Request-ID header.
+// It must run after the request context middleware that assigns the request ID.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(startedAtKey, time.Now())
+		if requestID := pkgContext.GetRequestID(c.Request.Context()); requestID != "" {
+			c.Header("X-Request-ID", requestID)
+		}
+		c.Next()
+	}
+}
+
+// Success writes data in the response envelope
+func Success(c *gin.Context, status int, data interface{}) {
+	c.JSON(status, &successEnvelope{Data: data, Meta: newMeta(c)})
+}
+
+// List writes a page of data with its paging and the filters it was listed with
+func List(c *gin.Context, data, paging, filter interface{}) {
+	c.JSON(http.StatusOK, &successEnvelope{Data: data, Paging: paging, Filter: filter, Meta: newMeta(c)})
+}
+
+// Error writes an error in the response envelope
+func Error(c *gin.Context, status int, code, message string, details interface{}) {
+	writeError(c, status, code, message, details, nil)
+}
+
+// writeError writes an error in the response envelope with its debug details, if any
+func writeError(c *gin.Context, status int, code, message string, details interface{}, debug *ErrorDebug) {
+	c.JSON(status, &errorEnvelope{IsError: true, Code: code, Message: message, Details: details, Debug: debug, Meta: newMeta(c)})
+}
+
+// newMeta builds the response metadata and sets the matching Server-Timing header
+func newMeta(c *gin.Context) Meta {
+	meta := Meta{RequestID: pkgContext.GetRequestID(c.Request.Context())}
+	if startedAt, ok := c.Get(startedAtKey); ok {
+		meta.DurationMs = float64(time.Since(startedAt.(time.Time)).Microseconds()) / 1000
+		c.Header("Server-Timing", fmt.Sprintf("app;dur=%.2f", meta.DurationMs))
+	}
+	return meta
+}
@@ -0,0 +1,141 @@
+package httpresponse
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/server/middleware"
+	"github.com/duongptryu/gox/syserr"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init(&logger.Config{Output: io.Discard})
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+func newTestRouter(debug DebugPolicy) *gin.Engine {
+	router := gin.New()
+	router.Use(middleware.RequestContext(), middleware.ErrorHandler())
+	router.Use(Middleware(), ErrorHandler(debug))
+
+	router.GET("/item", func(c *gin.Context) {
+		Success(c, http.StatusOK, gin.H{"id": 1})
+	})
+	router.GET("/items", func(c *gin.Context) {
+		List(c, []int{1, 2}, gin.H{"page": 1}, gin.H{"q": "x"})
+	})
+	router.GET("/not-found", func(c *gin.Context) {
+		c.Error(syserr.New(syserr.NotFoundCode, "item not found"))
+	})
+	router.GET("/boom", func(c *gin.Context) {
+		c.Error(errors.New("boom"))
+	})
+	router.GET("/wrapped", func(c *gin.Context) {
+		cause := syserr.Wrap(errors.New("connection refused"), syserr.InternalCode, "failed to get item")
+		c.Error(syserr.WrapAsIs(cause, "failed to render item"))
+	})
+	return router
+}
+
+func get(t *testing.T, path string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	rec := httptest.NewRecorder()
+	newTestRouter(nil).ServeHTTP(rec, req)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body), "a single JSON body is written")
+	return rec, body
+}
+
+func TestEnvelope(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		rec, body := get(t, "/item")
+
+		assert.Equal(t, "req-1", rec.Header().Get("X-Request-ID"))
+		assert.Contains(t, rec.Header().Get("Server-Timing"), "app;dur=")
+		assert.Equal(t, false, body["is_error"])
+		assert.Equal(t, map[string]interface{}{"id": float64(1)}, body["data"])
+		meta := body["meta"].(map[string]interface{})
+		assert.Equal(t, "req-1", meta["request_id"])
+		assert.Contains(t, meta, "duration_ms")
+	})
+
+	t.Run("list", func(t *testing.T) {
+		_, body := get(t, "/items")
+
+		assert.Equal(t, []interface{}{float64(1), float64(2)}, body["data"])
+		assert.Equal(t, map[string]interface{}{"page": float64(1)}, body["paging"])
+		assert.Equal(t, map[string]interface{}{"q": "x"}, body["filter"])
+	})
+
+	t.Run("system error", func(t *testing.T) {
+		_, body := get(t, "/not-found")
+
+		assert.Equal(t, true, body["is_error"])
+		assert.Equal(t, "not_found", body["code"])
+		assert.Equal(t, "item not found", body["message"])
+		assert.Equal(t, "req-1", body["meta"].(map[string]interface{})["request_id"])
+	})
+
+	t.Run("unexpected error", func(t *testing.T) {
+		_, body := get(t, "/boom")
+
+		assert.Equal(t, "internal_error", body["code"])
+		assert.Equal(t, "An error occurred", body["message"])
+	})
+}
+
+func TestErrorDebug(t *testing.T) {
+	debugOnHeader := func(c *gin.Context) bool { return c.GetHeader(DebugHeader) == "true" }
+
+	request := func(path string, header bool) map[string]interface{} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if header {
+			req.Header.Set(DebugHeader, "true")
+		}
+		rec := httptest.NewRecorder()
+		newTestRouter(debugOnHeader).ServeHTTP(rec, req)
+
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		return body
+	}
+
+	t.Run("cause chain", func(t *testing.T) {
+		body := request("/wrapped", true)
+
+		assert.Equal(t, "internal", body["code"])
+		debug := body["debug"].(map[string]interface{})
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"code": "internal", "message": "failed to render item"},
+			map[string]interface{}{"code": "internal", "message": "failed to get item"},
+			map[string]interface{}{"message": "connection refused"},
+		}, debug["chain"])
+		assert.NotEmpty(t, debug["stack"])
+	})
+
+	t.Run("unexpected error", func(t *testing.T) {
+		body := request("/boom", true)
+
+		assert.Equal(t, "An error occurred", body["message"])
+		assert.Equal(t, []interface{}{map[string]interface{}{"message": "boom"}}, body["debug"].(map[string]interface{})["chain"])
+	})
+
+	t.Run("not asked for", func(t *testing.T) {
+		body := request("/wrapped", false)
+
+		assert.NotContains(t, body, "debug")
+	})
+}
@@ -0,0 +1,131 @@
+package jsonschema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Breaking lists the changes from previous to current that fail payloads valid against previous:
+// removed properties, changed types and formats, newly required properties, dropped enum values and
+// tighter bounds. Adding optional properties and loosening rules is compatible.
+func Breaking(previous, current *Schema) []string {
+	var c checker
+	c.compare("", previous, current)
+	return c.problems
+}
+
+type checker struct {
+	problems []string
+}
+
+func (c *checker) report(path, format string, args ...interface{}) {
+	if path == "" {
+		path = "(root)"
+	}
+	c.problems = append(c.problems, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (c *checker) compare(path string, previous, current *Schema) {
+	if !c.compatibleTypes(path, previous.Type, current.Type) {
+		return
+	}
+
+	if current.Format != "" && current.Format != previous.Format {
+		c.report(path, "format changed from %q to %q", previous.Format, current.Format)
+	}
+	c.compareEnum(path, previous.Enum, current.Enum)
+
+	c.compareMin(path, "minLength", previous.MinLength, current.MinLength)
+	c.compareMax(path, "maxLength", previous.MaxLength, current.MaxLength)
+	c.compareMin(path, "minItems", previous.MinItems, current.MinItems)
+	c.compareMax(path, "maxItems", previous.MaxItems, current.MaxItems)
+	if current.Minimum != nil && (previous.Minimum == nil || *current.Minimum > *previous.Minimum) {
+		c.report(path, "minimum raised to %v", *current.Minimum)
+	}
+	if current.Maximum != nil && (previous.Maximum == nil || *current.Maximum < *previous.Maximum) {
+		c.report(path, "maximum lowered to %v", *current.Maximum)
+	}
+
+	for name, property := range previous.Properties {
+		next, ok := current.Properties[name]
+		if !ok {
+			c.report(join(path, name), "property removed")
+			continue
+		}
+		c.compare(join(path, name), property, next)
+	}
+
+	for _, name := range current.Required {
+		if !contains(previous.Required, name) {
+			c.report(join(path, name), "property now required")
+		}
+	}
+
+	if previous.Items != nil && current.Items != nil {
+		c.compare(path+"[]", previous.Items, current.Items)
+	}
+	if previous.AdditionalProperties != nil && current.AdditionalProperties != nil {
+		c.compare(path+"{}", previous.AdditionalProperties, current.AdditionalProperties)
+	}
+}
+
+// compatibleTypes tells whether current allows every type previous did, reporting it otherwise
+func (c *checker) compatibleTypes(path string, previous, current Types) bool {
+	if len(current) == 0 {
+		return true
+	}
+	if len(previous) == 0 {
+		c.report(path, "type narrowed from any value to %s", strings.Join(current, ", "))
+		return false
+	}
+	for _, typ := range previous {
+		if !current.Has(typ) {
+			c.report(path, "type changed from %s to %s", strings.Join(previous, ", "), strings.Join(current, ", "))
+			return false
+		}
+	}
+	return true
+}
+
+func (c *checker) compareEnum(path string, previous, current []string) {
+	if len(current) == 0 {
+		return
+	}
+	if len(previous) == 0 {
+		c.report(path, "values restricted to %s", strings.Join(current, ", "))
+		return
+	}
+	for _, value := range previous {
+		if !contains(current, value) {
+			c.report(path, "value %q removed", value)
+		}
+	}
+}
+
+func (c *checker) compareMin(path, keyword string, previous, current *int) {
+	if current != nil && *current > 0 && (previous == nil || *current > *previous) {
+		c.report(path, "%s raised to %d", keyword, *current)
+	}
+}
+
+func (c *checker) compareMax(path, keyword string, previous, current *int) {
+	if current != nil && (previous == nil || *current < *previous) {
+		c.report(path, "%s lowered to %d", keyword, *current)
+	}
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
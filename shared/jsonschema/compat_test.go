@@ -0,0 +1,85 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type previousCommand struct {
+	Name     string   `json:"name" binding:"required,max=50"`
+	Kind     string   `json:"kind" binding:"omitempty,oneof=public private"`
+	Quantity int      `json:"quantity"`
+	Tags     []string `json:"tags"`
+	Note     string   `json:"note"`
+}
+
+func TestBreaking(t *testing.T) {
+	previous := Generate(previousCommand{}, Body)
+
+	tests := []struct {
+		name     string
+		current  interface{}
+		problems []string
+	}{
+		{
+			name:    "unchanged",
+			current: previousCommand{},
+		},
+		{
+			name: "optional property added and bounds loosened",
+			current: struct {
+				Name     string   `json:"name" binding:"required,max=100"`
+				Kind     string   `json:"kind" binding:"omitempty,oneof=public private unlisted"`
+				Quantity *int     `json:"quantity"`
+				Tags     []string `json:"tags"`
+				Note     string   `json:"note"`
+				Venue    string   `json:"venue"`
+			}{},
+		},
+		{
+			name: "property removed",
+			current: struct {
+				Name     string   `json:"name" binding:"required,max=50"`
+				Kind     string   `json:"kind" binding:"omitempty,oneof=public private"`
+				Quantity int      `json:"quantity"`
+				Tags     []string `json:"tags"`
+			}{},
+			problems: []string{"note: property removed"},
+		},
+		{
+			name: "type changed",
+			current: struct {
+				Name     string `json:"name" binding:"required,max=50"`
+				Kind     string `json:"kind" binding:"omitempty,oneof=public private"`
+				Quantity string `json:"quantity"`
+				Tags     []int  `json:"tags"`
+				Note     string `json:"note"`
+			}{},
+			problems: []string{"quantity: type changed from integer to string", "tags[]: type changed from string to integer"},
+		},
+		{
+			name: "rules tightened",
+			current: struct {
+				Name     string   `json:"name" binding:"required,max=20"`
+				Kind     string   `json:"kind" binding:"omitempty,oneof=public"`
+				Quantity int      `json:"quantity" binding:"required,min=1"`
+				Tags     []string `json:"tags"`
+				Note     string   `json:"note" binding:"email"`
+			}{},
+			problems: []string{
+				"name: maxLength lowered to 20",
+				"kind: value \"private\" removed",
+				"quantity: minimum raised to 1",
+				"note: format changed from \"\" to \"email\"",
+				"quantity: property now required",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ElementsMatch(t, tt.problems, Breaking(previous, Generate(tt.current, Body)))
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package jsonschema
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"tixgo/shared/errstack"
+	"tixgo/shared/httpresponse"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/gin-gonic/gin"
+)
+
+// ErrSchemaNotFound is returned for a payload name nothing is registered under
+var ErrSchemaNotFound = errstack.Sentinel(syserr.NotFoundCode, "payload schema not found")
+
+// Payload is a request payload published with its schema
+type Payload struct {
+	// Name identifies the payload, like "users.register"
+	Name string `json:"name"`
+	// In tells whether the payload is the request body or its query string
+	In Location `json:"in"`
+	// Example is a value of the struct the payload is bound to
+	Example interface{} `json:"-"`
+}
+
+// Registry holds the schemas of the request payloads, generated once
+type Registry struct {
+	payloads []Payload
+	schemas  map[string]*Schema
+}
+
+// NewRegistry generates the schemas of payloads. The payloads are a static list, a name registered
+// twice panics.
+func NewRegistry(payloads ...Payload) *Registry {
+	r := &Registry{schemas: make(map[string]*Schema, len(payloads))}
+	for _, payload := range payloads {
+		if _, ok := r.schemas[payload.Name]; ok {
+			panic(fmt.Sprintf("jsonschema: payload %q registered twice", payload.Name))
+		}
+
+		schema := Generate(payload.Example, payload.In)
+		schema.Schema = Draft
+		schema.Title = payload.Name
+		r.schemas[payload.Name] = schema
+		r.payloads = append(r.payloads, payload)
+	}
+
+	sort.Slice(r.payloads, func(i, j int) bool { return r.payloads[i].Name < r.payloads[j].Name })
+	return r
+}
+
+// Schema returns the schema of the payload registered under name
+func (r *Registry) Schema(name string) (*Schema, bool) {
+	schema, ok := r.schemas[name]
+	return schema, ok
+}
+
+// Schemas returns the schemas by payload name
+func (r *Registry) Schemas() map[string]*Schema {
+	return r.schemas
+}
+
+// ListHandler answers the names of the payloads and where they are read from, sorted by name
+func (r *Registry) ListHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		httpresponse.Success(c, http.StatusOK, r.payloads)
+	}
+}
+
+// GetHandler answers the schema of the payload of the :name parameter as a bare JSON Schema
+// document, for validators and code generators to fetch
+func (r *Registry) GetHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		schema, ok := r.schemas[c.Param("name")]
+		if !ok {
+			c.Error(ErrSchemaNotFound)
+			return
+		}
+
+		c.Header("Content-Type", "application/schema+json")
+		c.JSON(http.StatusOK, schema)
+	}
+}
@@ -0,0 +1,260 @@
+// Package jsonschema describes request payloads with JSON Schemas generated from the structs they are
+// bound to, and tells which changes of a schema break the clients of its previous version.
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Draft is the JSON Schema dialect of the generated schemas
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Location is where a payload is read from, which tag names its properties
+type Location string
+
+const (
+	// Body payloads are JSON request bodies, their properties named by the json tags
+	Body Location = "body"
+	// Query payloads are query strings, their properties are the fields with a form tag
+	Query Location = "query"
+)
+
+// Schema is the part of JSON Schema payload structs are described with
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 Types              `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+}
+
+// Types are the JSON types a value may have, written as a single type when there is only one.
+// No types means any value.
+type Types []string
+
+// MarshalJSON writes a single type as a string
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// UnmarshalJSON reads a type or a list of types
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = Types{single}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+// Has tells whether a value of the type typ is allowed, integers being numbers
+func (t Types) Has(typ string) bool {
+	for _, allowed := range t {
+		if allowed == typ || (allowed == "number" && typ == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// Generate returns the schema of the payload example is a value of. Properties follow the binding of
+// gin: the json tags of a body, the form tags of a query string, embedded structs flattened. The
+// binding and validate rules required, oneof, min, max, email, fqdn, url and uuid are translated.
+func Generate(example interface{}, in Location) *Schema {
+	g := &generator{tag: "json", seen: make(map[reflect.Type]bool)}
+	if in == Query {
+		g.tag = "form"
+	}
+	return g.schemaOf(reflect.TypeOf(example))
+}
+
+type generator struct {
+	tag string
+	// seen holds the structs being generated, a recursive one is described as any object
+	seen map[reflect.Type]bool
+}
+
+// schemaOf describes a type, pointers allowing null in bodies; query parameters are only ever missing
+func (g *generator) schemaOf(t reflect.Type) *Schema {
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		nullable = true
+	}
+
+	schema := g.bare(t)
+	if nullable && g.tag == "json" && len(schema.Type) > 0 {
+		schema.Type = append(schema.Type, "null")
+	}
+	return schema
+}
+
+func (g *generator) bare(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: Types{"string"}, Format: "date-time"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: Types{"string"}}
+	case reflect.Bool:
+		return &Schema{Type: Types{"boolean"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: Types{"integer"}}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: Types{"number"}}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// encoding/json writes bytes in base64
+			return &Schema{Type: Types{"string"}}
+		}
+		return &Schema{Type: Types{"array"}, Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: Types{"object"}, AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		return g.object(t)
+	default:
+		return &Schema{}
+	}
+}
+
+func (g *generator) object(t reflect.Type) *Schema {
+	schema := &Schema{Type: Types{"object"}}
+	if g.seen[t] {
+		return schema
+	}
+	g.seen[t] = true
+	defer delete(g.seen, t)
+
+	schema.Properties = make(map[string]*Schema)
+	g.fields(t, schema)
+	return schema
+}
+
+// fields adds the properties of the fields of t to schema
+func (g *generator) fields(t reflect.Type, schema *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, tagged := field.Tag.Lookup(g.tag)
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+
+		embedded := field.Type
+		if embedded.Kind() == reflect.Pointer {
+			embedded = embedded.Elem()
+		}
+		if field.Anonymous && name == "" && embedded.Kind() == reflect.Struct {
+			g.fields(embedded, schema)
+			continue
+		}
+		if !field.IsExported() || (g.tag == "form" && !tagged) {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := g.schemaOf(field.Type)
+		rules := field.Tag.Get("binding")
+		if rules == "" {
+			rules = field.Tag.Get("validate")
+		}
+		if applyRules(property, rules) {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = property
+	}
+}
+
+// applyRules translates the validation rules of a field to its schema and tells whether it is required.
+// The rules after dive apply to the items.
+func applyRules(schema *Schema, rules string) (required bool) {
+	target := schema
+	for _, rule := range strings.Split(rules, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "dive":
+			if target.Items == nil {
+				return required
+			}
+			target = target.Items
+		case "required":
+			required = required || target == schema
+		case "oneof":
+			target.Enum = strings.Fields(param)
+		case "min", "max", "len":
+			bound(target, name, param)
+		case "email":
+			target.Format = "email"
+		case "fqdn":
+			target.Format = "hostname"
+		case "url":
+			target.Format = "uri"
+		case "uuid":
+			target.Format = "uuid"
+		}
+	}
+	return required
+}
+
+// bound sets the min, max or len rule as the length, range or item count bound of the type
+func bound(schema *Schema, rule, param string) {
+	value, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+	count := int(value)
+
+	setMin, setMax := rule != "max", rule != "min"
+	switch {
+	case schema.Type.Has("string"):
+		if setMin {
+			schema.MinLength = &count
+		}
+		if setMax {
+			schema.MaxLength = &count
+		}
+	case schema.Type.Has("array"):
+		if setMin {
+			schema.MinItems = &count
+		}
+		if setMax {
+			schema.MaxItems = &count
+		}
+	case schema.Type.Has("number"), schema.Type.Has("integer"):
+		if setMin {
+			schema.Minimum = &value
+		}
+		if setMax {
+			schema.Maximum = &value
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"tixgo/shared/httpresponse"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init(&logger.Config{Output: io.Discard})
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+type invite struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type paging struct {
+	Page  int `json:"page" form:"page"`
+	Limit int `json:"limit" form:"limit" binding:"omitempty,max=100"`
+}
+
+type createCommand struct {
+	Name       string                 `json:"name" binding:"required,min=3,max=50"`
+	Kind       string                 `json:"kind" binding:"omitempty,oneof=public private"`
+	Parent     *int64                 `json:"parent_id"`
+	Tags       []string               `json:"tags" binding:"max=5,dive,max=20"`
+	Invites    []invite               `json:"invites" binding:"required,dive"`
+	Variables  map[string]interface{} `json:"variables"`
+	StartsAt   time.Time              `json:"starts_at"`
+	Internal   int64                  `json:"-"`
+	Untagged   bool
+	unexported bool
+}
+
+type listQuery struct {
+	Status  *string `form:"status"`
+	OwnerID int64
+	paging
+}
+
+func TestGenerate_Body(t *testing.T) {
+	schema, err := json.Marshal(Generate(createCommand{}, Body))
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "minLength": 3, "maxLength": 50},
+			"kind": {"type": "string", "enum": ["public", "private"]},
+			"parent_id": {"type": ["integer", "null"]},
+			"tags": {"type": "array", "maxItems": 5, "items": {"type": "string", "maxLength": 20}},
+			"invites": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"properties": {"email": {"type": "string", "format": "email"}},
+					"required": ["email"]
+				}
+			},
+			"variables": {"type": "object", "additionalProperties": {}},
+			"starts_at": {"type": "string", "format": "date-time"},
+			"Untagged": {"type": "boolean"}
+		},
+		"required": ["name", "invites"]
+	}`, string(schema))
+}
+
+func TestGenerate_Query(t *testing.T) {
+	schema, err := json.Marshal(Generate(listQuery{}, Query))
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"status": {"type": "string"},
+			"page": {"type": "integer"},
+			"limit": {"type": "integer", "maximum": 100}
+		}
+	}`, string(schema))
+}
+
+func TestTypes_JSON(t *testing.T) {
+	var types Types
+	require.NoError(t, json.Unmarshal([]byte(`"string"`), &types))
+	assert.Equal(t, Types{"string"}, types)
+	require.NoError(t, json.Unmarshal([]byte(`["integer", "null"]`), &types))
+	assert.Equal(t, Types{"integer", "null"}, types)
+
+	assert.True(t, Types{"number"}.Has("integer"))
+	assert.False(t, Types{"integer"}.Has("number"))
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry(
+		Payload{Name: "things.list", In: Query, Example: listQuery{}},
+		Payload{Name: "things.create", In: Body, Example: createCommand{}},
+	)
+	assert.Panics(t, func() {
+		NewRegistry(Payload{Name: "things.list", In: Query, Example: listQuery{}}, Payload{Name: "things.list", In: Query, Example: paging{}})
+	})
+
+	router := gin.New()
+	router.Use(httpresponse.Middleware(), httpresponse.ErrorHandler(nil))
+	router.GET("/schemas", registry.ListHandler())
+	router.GET("/schemas/:name", registry.GetHandler())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/schemas", nil))
+	var list struct {
+		Data []Payload `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Equal(t, []Payload{{Name: "things.create", In: Body}, {Name: "things.list", In: Query}}, list.Data)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/schemas/things.create", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/schema+json", w.Header().Get("Content-Type"))
+	var schema Schema
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &schema))
+	assert.Equal(t, Draft, schema.Schema)
+	assert.Equal(t, "things.create", schema.Title)
+	assert.Equal(t, []string{"name", "invites"}, schema.Required)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/schemas/things.delete", nil))
+	var failure struct {
+		IsError bool   `json:"is_error"`
+		Code    string `json:"code"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &failure))
+	assert.True(t, failure.IsError)
+	assert.Equal(t, "not_found", failure.Code)
+}
@@ -0,0 +1,133 @@
+package kafka
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+// ConsumerConfig holds the consumer settings of a single topic
+type ConsumerConfig struct {
+	Topic       string
+	Concurrency int
+	Ordered     bool
+	// Timeout overrides the default handler deadline of the topic when positive
+	Timeout time.Duration
+	// MaxLag is the consumer group lag past which the consumers of the topic are behind, zero to only report it
+	MaxLag int64
+}
+
+// ConsumerSettings resolves per-topic consumer settings
+type ConsumerSettings struct {
+	topics map[string]ConsumerConfig
+}
+
+// NewConsumerSettings creates consumer settings indexed by topic
+func NewConsumerSettings(consumers []ConsumerConfig) *ConsumerSettings {
+	topics := make(map[string]ConsumerConfig, len(consumers))
+	for _, consumer := range consumers {
+		topics[consumer.Topic] = consumer
+	}
+	return &ConsumerSettings{topics: topics}
+}
+
+// Concurrency returns the number of consumer group members to run for a topic (at least 1)
+func (s *ConsumerSettings) Concurrency(topic string) int {
+	if consumer, ok := s.topics[topic]; ok && consumer.Concurrency > 1 {
+		return consumer.Concurrency
+	}
+	return 1
+}
+
+// IsOrdered reports whether messages of a topic must keep their per-key order
+func (s *ConsumerSettings) IsOrdered(topic string) bool {
+	consumer, ok := s.topics[topic]
+	return ok && consumer.Ordered
+}
+
+// Timeout returns the handler deadline configured for a topic, fallback when it has none
+func (s *ConsumerSettings) Timeout(topic string, fallback time.Duration) time.Duration {
+	if consumer, ok := s.topics[topic]; ok && consumer.Timeout > 0 {
+		return consumer.Timeout
+	}
+	return fallback
+}
+
+// MaxLag returns the lag past which the consumers of a topic are behind, zero when none is configured
+func (s *ConsumerSettings) MaxLag(topic string) int64 {
+	return s.topics[topic].MaxLag
+}
+
+// Topics returns the configured topics, sorted
+func (s *ConsumerSettings) Topics() []string {
+	topics := make([]string, 0, len(s.topics))
+	for topic := range s.topics {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+// ConcurrentSubscriber fans in several subscriptions of the same topic so that one process runs
+// multiple consumer group members. Kafka assigns each member its own partitions, so messages sharing
+// a partition key are still handled one at a time and in order.
+type ConcurrentSubscriber struct {
+	subscriber message.Subscriber
+	settings   *ConsumerSettings
+}
+
+// NewConcurrentSubscriber wraps a subscriber with per-topic concurrency
+func NewConcurrentSubscriber(subscriber message.Subscriber, settings *ConsumerSettings) *ConcurrentSubscriber {
+	return &ConcurrentSubscriber{
+		subscriber: subscriber,
+		settings:   settings,
+	}
+}
+
+// Subscribe subscribes to a topic once per configured consumer and merges the messages into one channel
+func (s *ConcurrentSubscriber) Subscribe(ctx context.Context, topic string) (<-chan *message.Message, error) {
+	concurrency := s.settings.Concurrency(topic)
+	if concurrency == 1 {
+		return s.subscriber.Subscribe(ctx, topic)
+	}
+
+	channels := make([]<-chan *message.Message, 0, concurrency)
+	for range concurrency {
+		messages, err := s.subscriber.Subscribe(ctx, topic)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, messages)
+	}
+
+	out := make(chan *message.Message)
+	var wg sync.WaitGroup
+	wg.Add(len(channels))
+	for _, messages := range channels {
+		go func(messages <-chan *message.Message) {
+			defer wg.Done()
+			for msg := range messages {
+				select {
+				case out <- msg:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(messages)
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out, nil
+}
+
+// Close closes the underlying subscriber
+func (s *ConcurrentSubscriber) Close() error {
+	return s.subscriber.Close()
+}
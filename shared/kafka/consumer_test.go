@@ -0,0 +1,185 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
+	pkgContext "github.com/duongptryu/gox/context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsumerSettings_Concurrency(t *testing.T) {
+	settings := NewConsumerSettings([]ConsumerConfig{
+		{Topic: "commands.SendOTPVerifyMail", Concurrency: 4, Ordered: true},
+		{Topic: "events.Zero", Concurrency: 0},
+	})
+
+	assert.Equal(t, 4, settings.Concurrency("commands.SendOTPVerifyMail"))
+	assert.Equal(t, 1, settings.Concurrency("events.Zero"))
+	assert.Equal(t, 1, settings.Concurrency("events.Unknown"))
+	assert.True(t, settings.IsOrdered("commands.SendOTPVerifyMail"))
+	assert.False(t, settings.IsOrdered("events.Unknown"))
+}
+
+func TestPartitionKey(t *testing.T) {
+	settings := NewConsumerSettings([]ConsumerConfig{
+		{Topic: "ordered", Ordered: true},
+	})
+
+	t.Run("ordered topic uses context key", func(t *testing.T) {
+		msg := message.NewMessage(watermill.NewUUID(), nil)
+		msg.SetContext(WithPartitionKey(context.Background(), "john@example.com"))
+
+		assert.Equal(t, "john@example.com", partitionKey(settings, "ordered", msg))
+		assert.Equal(t, "john@example.com", msg.Metadata.Get(PartitionKeyMetadata))
+	})
+
+	t.Run("ordered topic prefers metadata key", func(t *testing.T) {
+		msg := message.NewMessage(watermill.NewUUID(), nil)
+		msg.Metadata.Set(PartitionKeyMetadata, "from-metadata")
+		msg.SetContext(WithPartitionKey(context.Background(), "from-context"))
+
+		assert.Equal(t, "from-metadata", partitionKey(settings, "ordered", msg))
+	})
+
+	t.Run("ordered topic without key falls back to UUID", func(t *testing.T) {
+		msg := message.NewMessage(watermill.NewUUID(), nil)
+
+		assert.Equal(t, msg.UUID, partitionKey(settings, "ordered", msg))
+	})
+
+	t.Run("unordered topic ignores key", func(t *testing.T) {
+		msg := message.NewMessage(watermill.NewUUID(), nil)
+		msg.SetContext(WithPartitionKey(context.Background(), "john@example.com"))
+
+		assert.Equal(t, msg.UUID, partitionKey(settings, "unordered", msg))
+	})
+}
+
+func TestConcurrentSubscriber_Subscribe(t *testing.T) {
+	pubSub := gochannel.NewGoChannel(gochannel.Config{}, watermill.NopLogger{})
+	defer pubSub.Close()
+
+	subscriber := NewConcurrentSubscriber(pubSub, NewConsumerSettings([]ConsumerConfig{
+		{Topic: "topic", Concurrency: 3},
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	messages, err := subscriber.Subscribe(ctx, "topic")
+	require.NoError(t, err)
+
+	// The go channel pub/sub fans out to every subscription, so each of the 3 members receives the message
+	require.NoError(t, pubSub.Publish("topic", message.NewMessage(watermill.NewUUID(), []byte("payload"))))
+
+	for range 3 {
+		select {
+		case msg := <-messages:
+			assert.Equal(t, "payload", string(msg.Payload))
+			msg.Ack()
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for message")
+		}
+	}
+}
+
+func TestConsumerSettings_Timeout(t *testing.T) {
+	settings := NewConsumerSettings([]ConsumerConfig{
+		{Topic: "events.Slow", Timeout: time.Minute},
+	})
+
+	assert.Equal(t, time.Minute, settings.Timeout("events.Slow", time.Second))
+	assert.Equal(t, time.Second, settings.Timeout("events.Unknown", time.Second))
+}
+
+func TestDeadlineSubscriber_Subscribe(t *testing.T) {
+	pubSub := gochannel.NewGoChannel(gochannel.Config{}, watermill.NopLogger{})
+	defer pubSub.Close()
+
+	subscriber := NewDeadlineSubscriber(pubSub, NewConsumerSettings([]ConsumerConfig{
+		{Topic: "slow", Timeout: time.Hour},
+	}), 20*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	receive := func(t *testing.T, topic string) *message.Message {
+		messages, err := subscriber.Subscribe(ctx, topic)
+		require.NoError(t, err)
+		require.NoError(t, pubSub.Publish(topic, message.NewMessage(watermill.NewUUID(), nil)))
+
+		select {
+		case msg := <-messages:
+			return msg
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for message")
+			return nil
+		}
+	}
+
+	t.Run("a stuck handler runs out of time", func(t *testing.T) {
+		msg := receive(t, "topic")
+		defer msg.Ack()
+
+		select {
+		case <-msg.Context().Done():
+			assert.ErrorIs(t, msg.Context().Err(), context.DeadlineExceeded)
+		case <-time.After(time.Second):
+			t.Fatal("message context did not expire")
+		}
+	})
+
+	t.Run("topic timeout overrides the default", func(t *testing.T) {
+		msg := receive(t, "slow")
+		defer msg.Ack()
+
+		deadline, ok := msg.Context().Deadline()
+		require.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), deadline, time.Second)
+	})
+
+	t.Run("acking releases the deadline", func(t *testing.T) {
+		msg := receive(t, "slow")
+		msg.Ack()
+
+		select {
+		case <-msg.Context().Done():
+			assert.ErrorIs(t, msg.Context().Err(), context.Canceled)
+		case <-time.After(time.Second):
+			t.Fatal("message context was not released")
+		}
+	})
+}
+
+func TestRequestIDPropagation(t *testing.T) {
+	pubSub := gochannel.NewGoChannel(gochannel.Config{}, watermill.NopLogger{})
+	defer pubSub.Close()
+
+	publisher := NewRequestIDPublisher(pubSub)
+	subscriber := NewRequestIDSubscriber(pubSub)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	messages, err := subscriber.Subscribe(ctx, "topic")
+	require.NoError(t, err)
+
+	msg := message.NewMessage(watermill.NewUUID(), nil)
+	msg.SetContext(pkgContext.WithRequestID(context.Background(), "req-1"))
+	require.NoError(t, publisher.Publish("topic", msg))
+
+	select {
+	case received := <-messages:
+		defer received.Ack()
+		assert.Equal(t, "req-1", received.Metadata.Get(RequestIDMetadata))
+		assert.Equal(t, "req-1", pkgContext.GetRequestID(received.Context()))
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for message")
+	}
+}
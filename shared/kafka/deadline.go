@@ -0,0 +1,77 @@
+package kafka
+
+import (
+	"context"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+// DefaultHandlerTimeout bounds the handling of a message when kafka.handler_timeout is not configured
+const DefaultHandlerTimeout = 30 * time.Second
+
+// DeadlineSubscriber gives every message it delivers a context deadline, so a handler stuck on Postgres
+// or Kafka gives its consumer back instead of holding the partition forever. The deadline spans the
+// retries of the bus, a message past it is nacked and ends up in the poison queue.
+type DeadlineSubscriber struct {
+	subscriber message.Subscriber
+	settings   *ConsumerSettings
+	timeout    time.Duration
+}
+
+// NewDeadlineSubscriber wraps a subscriber with per-topic handler deadlines, timeout being the default
+func NewDeadlineSubscriber(subscriber message.Subscriber, settings *ConsumerSettings, timeout time.Duration) *DeadlineSubscriber {
+	if timeout <= 0 {
+		timeout = DefaultHandlerTimeout
+	}
+	return &DeadlineSubscriber{
+		subscriber: subscriber,
+		settings:   settings,
+		timeout:    timeout,
+	}
+}
+
+// Subscribe subscribes to a topic and bounds the context of each of its messages by the topic deadline
+func (s *DeadlineSubscriber) Subscribe(ctx context.Context, topic string) (<-chan *message.Message, error) {
+	messages, err := s.subscriber.Subscribe(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := s.settings.Timeout(topic, s.timeout)
+	out := make(chan *message.Message)
+	go func() {
+		defer close(out)
+		for msg := range messages {
+			withDeadline(msg, timeout)
+
+			select {
+			case out <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// Close closes the underlying subscriber
+func (s *DeadlineSubscriber) Close() error {
+	return s.subscriber.Close()
+}
+
+// withDeadline bounds the context of msg by timeout until the message is acked or nacked
+func withDeadline(msg *message.Message, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(msg.Context(), timeout)
+	msg.SetContext(ctx)
+
+	go func() {
+		defer cancel()
+		select {
+		case <-msg.Acked():
+		case <-msg.Nacked():
+		case <-ctx.Done():
+		}
+	}()
+}
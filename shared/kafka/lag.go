@@ -0,0 +1,332 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/duongptryu/gox/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	// DefaultLagCheckInterval is how often the lag is collected when kafka.lag_check_interval is not configured
+	DefaultLagCheckInterval = 30 * time.Second
+
+	// PoisonQueueTopic is the logical topic the messages failing every retry of the bus end up in
+	PoisonQueueTopic = "poison_queue"
+)
+
+var (
+	consumerLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "tixgo_kafka_consumer_lag",
+		Help: "Messages of a topic the consumer group has yet to commit, summed over the partitions.",
+	}, []string{"group", "topic"})
+	poisonQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "tixgo_kafka_poison_queue_depth",
+		Help: "Messages retained in the poison queue, summed over the partitions.",
+	})
+)
+
+// offsetSource is the subset of sarama.Client and sarama.ClusterAdmin used by the lag monitor
+type offsetSource interface {
+	Partitions(topic string) ([]int32, error)
+	GetOffset(topic string, partition int32, time int64) (int64, error)
+	ListConsumerGroupOffsets(group string, topicPartitions map[string][]int32) (*sarama.OffsetFetchResponse, error)
+	Close() error
+}
+
+// saramaOffsetSource reads the log end offsets with a client and the committed ones with an admin
+type saramaOffsetSource struct {
+	sarama.Client
+	admin sarama.ClusterAdmin
+}
+
+func (s *saramaOffsetSource) ListConsumerGroupOffsets(group string, topicPartitions map[string][]int32) (*sarama.OffsetFetchResponse, error) {
+	return s.admin.ListConsumerGroupOffsets(group, topicPartitions)
+}
+
+// Close closes the admin, which closes the client it was created from
+func (s *saramaOffsetSource) Close() error {
+	return s.admin.Close()
+}
+
+// TopicLag is the lag of the consumer group on a topic
+type TopicLag struct {
+	// Topic is the logical topic
+	Topic string `json:"topic"`
+	Lag   int64  `json:"lag"`
+	// MaxLag is the lag past which the consumers are behind, zero when the lag is only reported
+	MaxLag int64 `json:"max_lag,omitempty"`
+	Behind bool  `json:"behind"`
+}
+
+// LagReport is the outcome of the last lag collection
+type LagReport struct {
+	Group  string     `json:"group"`
+	Topics []TopicLag `json:"topics"`
+	// PoisonQueue is the number of messages retained in the poison queue
+	PoisonQueue int64     `json:"poison_queue"`
+	CheckedAt   time.Time `json:"checked_at"`
+	// Error is why the last collection failed, Topics then being those of the last one that succeeded
+	Error string `json:"error,omitempty"`
+}
+
+// LagMonitor periodically collects the lag of the consumer group on the consumed topics, exports it
+// in tixgo_kafka_consumer_lag and tells when a topic is past its max lag. It collects the depth of the
+// poison queue along, exported in tixgo_kafka_poison_queue_depth.
+type LagMonitor struct {
+	// connect opens the source on the first collection, so the monitor is created without the brokers
+	connect  func() (offsetSource, error)
+	sourceMu sync.Mutex
+	source   offsetSource
+
+	group     string
+	naming    TopicNaming
+	consumers *ConsumerSettings
+
+	mu     sync.RWMutex
+	report LagReport
+}
+
+// NewLagMonitor creates a lag monitor of the consumer group on the topics of the consumer settings. Its
+// client and cluster admin connect on the first collection, retried by the next ones while the
+// brokers are unreachable.
+func NewLagMonitor(brokers []string, group string, topology Topology) *LagMonitor {
+	monitor := newLagMonitor(nil, group, topology)
+	monitor.connect = func() (offsetSource, error) {
+		client, err := sarama.NewClient(brokers, sarama.NewConfig())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create kafka client: %w", err)
+		}
+		admin, err := sarama.NewClusterAdminFromClient(client)
+		if err != nil {
+			_ = client.Close()
+			return nil, fmt.Errorf("failed to create kafka cluster admin: %w", err)
+		}
+		return &saramaOffsetSource{Client: client, admin: admin}, nil
+	}
+	return monitor
+}
+
+func newLagMonitor(source offsetSource, group string, topology Topology) *LagMonitor {
+	return &LagMonitor{
+		source:    source,
+		group:     group,
+		naming:    topology.Naming,
+		consumers: topology.Consumers,
+		report:    LagReport{Group: group},
+	}
+}
+
+// Run collects the lag every interval, DefaultLagCheckInterval when it is not positive, until ctx is done
+func (m *LagMonitor) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultLagCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.Collect(ctx); err != nil {
+			logger.Warning(ctx, "Failed to collect kafka consumer lag", logger.F("group", m.group), logger.F("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Collect reads the lag of every consumed topic. On failure the report keeps the lags of the last
+// collection that succeeded.
+func (m *LagMonitor) Collect(ctx context.Context) error {
+	topics, poisonQueue, err := m.collect()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.report.CheckedAt = time.Now()
+	if err != nil {
+		m.report.Error = err.Error()
+		return err
+	}
+	m.report.Topics = topics
+	m.report.PoisonQueue = poisonQueue
+	m.report.Error = ""
+	poisonQueueDepth.Set(float64(poisonQueue))
+
+	for _, topic := range topics {
+		consumerLag.WithLabelValues(m.group, topic.Topic).Set(float64(topic.Lag))
+		if topic.Behind {
+			logger.Warning(ctx, "Kafka consumers are behind",
+				logger.F("group", m.group), logger.F("topic", topic.Topic),
+				logger.F("lag", topic.Lag), logger.F("max_lag", topic.MaxLag))
+		}
+	}
+	return nil
+}
+
+// offsetSource returns the source, connecting it on first use
+func (m *LagMonitor) offsetSource() (offsetSource, error) {
+	m.sourceMu.Lock()
+	defer m.sourceMu.Unlock()
+
+	if m.source == nil {
+		source, err := m.connect()
+		if err != nil {
+			return nil, err
+		}
+		m.source = source
+	}
+	return m.source, nil
+}
+
+func (m *LagMonitor) collect() ([]TopicLag, int64, error) {
+	source, err := m.offsetSource()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	logical := m.consumers.Topics()
+
+	partitions := make(map[string][]int32, len(logical))
+	for _, topic := range logical {
+		name := m.naming.Physical(topic)
+		ids, err := source.Partitions(name)
+		if errors.Is(err, sarama.ErrUnknownTopicOrPartition) {
+			// nothing was published to the topic yet
+			continue
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list the partitions of %s: %w", name, err)
+		}
+		partitions[name] = ids
+	}
+
+	committed, err := source.ListConsumerGroupOffsets(m.group, partitions)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list the offsets of consumer group %s: %w", m.group, err)
+	}
+
+	lags := make([]TopicLag, 0, len(logical))
+	for _, topic := range logical {
+		name := m.naming.Physical(topic)
+		var lag int64
+		for _, partition := range partitions[name] {
+			partitionLag, err := m.partitionLag(source, committed, name, partition)
+			if err != nil {
+				return nil, 0, err
+			}
+			lag += partitionLag
+		}
+
+		maxLag := m.consumers.MaxLag(topic)
+		lags = append(lags, TopicLag{Topic: topic, Lag: lag, MaxLag: maxLag, Behind: maxLag > 0 && lag > maxLag})
+	}
+
+	poisonQueue, err := m.poisonQueueDepth(source)
+	if err != nil {
+		return nil, 0, err
+	}
+	return lags, poisonQueue, nil
+}
+
+// poisonQueueDepth is the number of messages retained in the poison queue. Nothing consumes it, so its
+// messages stay until the retention of the topic drops them.
+func (m *LagMonitor) poisonQueueDepth(source offsetSource) (int64, error) {
+	name := m.naming.Physical(PoisonQueueTopic)
+	partitions, err := source.Partitions(name)
+	if errors.Is(err, sarama.ErrUnknownTopicOrPartition) {
+		// no message failed yet
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to list the partitions of %s: %w", name, err)
+	}
+
+	var depth int64
+	for _, partition := range partitions {
+		oldest, err := source.GetOffset(name, partition, sarama.OffsetOldest)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get the oldest offset of %s/%d: %w", name, partition, err)
+		}
+		newest, err := source.GetOffset(name, partition, sarama.OffsetNewest)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get the newest offset of %s/%d: %w", name, partition, err)
+		}
+		depth += max(newest-oldest, 0)
+	}
+	return depth, nil
+}
+
+// partitionLag is the distance from the committed offset to the end of the partition. A group that
+// committed nothing yet starts from the oldest message, so all the retained messages are its lag.
+func (m *LagMonitor) partitionLag(source offsetSource, committed *sarama.OffsetFetchResponse, topic string, partition int32) (int64, error) {
+	newest, err := source.GetOffset(topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get the newest offset of %s/%d: %w", topic, partition, err)
+	}
+
+	offset := int64(-1)
+	if block := committed.GetBlock(topic, partition); block != nil {
+		if block.Err != sarama.ErrNoError {
+			return 0, fmt.Errorf("failed to get the committed offset of %s/%d: %w", topic, partition, block.Err)
+		}
+		offset = block.Offset
+	}
+	if offset < 0 {
+		offset, err = source.GetOffset(topic, partition, sarama.OffsetOldest)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get the oldest offset of %s/%d: %w", topic, partition, err)
+		}
+	}
+
+	return max(newest-offset, 0), nil
+}
+
+// Report returns the outcome of the last collection
+func (m *LagMonitor) Report() LagReport {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	report := m.report
+	report.Topics = append([]TopicLag(nil), m.report.Topics...)
+	return report
+}
+
+// Check fails when a topic was past its max lag at the last collection that succeeded. A failed
+// collection alone does not: the broker being unreachable is not the consumers falling behind.
+func (m *LagMonitor) Check(ctx context.Context) error {
+	report := m.Report()
+
+	var behind []string
+	for _, topic := range report.Topics {
+		if topic.Behind {
+			behind = append(behind, fmt.Sprintf("%s (%d > %d)", topic.Topic, topic.Lag, topic.MaxLag))
+		}
+	}
+	if len(behind) > 0 {
+		sort.Strings(behind)
+		return fmt.Errorf("consumer group %s is behind on %v", m.group, behind)
+	}
+	return nil
+}
+
+// Close closes the connections of the monitor, if it connected
+func (m *LagMonitor) Close() error {
+	m.sourceMu.Lock()
+	defer m.sourceMu.Unlock()
+
+	if m.source == nil {
+		return nil
+	}
+	return m.source.Close()
+}
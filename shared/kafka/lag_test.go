@@ -0,0 +1,131 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type partitionOffsets struct {
+	oldest, newest, committed int64
+}
+
+type fakeOffsetSource struct {
+	// offsets are by topic, then partition
+	offsets map[string]map[int32]partitionOffsets
+	err     error
+}
+
+func (s *fakeOffsetSource) Partitions(topic string) ([]int32, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	partitions, ok := s.offsets[topic]
+	if !ok {
+		return nil, sarama.ErrUnknownTopicOrPartition
+	}
+	ids := make([]int32, 0, len(partitions))
+	for id := range partitions {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (s *fakeOffsetSource) GetOffset(topic string, partition int32, time int64) (int64, error) {
+	offsets := s.offsets[topic][partition]
+	if time == sarama.OffsetOldest {
+		return offsets.oldest, nil
+	}
+	return offsets.newest, nil
+}
+
+func (s *fakeOffsetSource) ListConsumerGroupOffsets(group string, topicPartitions map[string][]int32) (*sarama.OffsetFetchResponse, error) {
+	response := &sarama.OffsetFetchResponse{}
+	for topic, partitions := range topicPartitions {
+		for _, partition := range partitions {
+			response.AddBlock(topic, partition, &sarama.OffsetFetchResponseBlock{
+				Offset: s.offsets[topic][partition].committed,
+			})
+		}
+	}
+	return response, nil
+}
+
+func (s *fakeOffsetSource) Close() error {
+	return nil
+}
+
+func newTestLagMonitor(source offsetSource) *LagMonitor {
+	return newLagMonitor(source, "tixgo", Topology{
+		Naming: NewTopicNaming("dev"),
+		Consumers: NewConsumerSettings([]ConsumerConfig{
+			{Topic: "commands.SendOTPVerifyMailCommand", MaxLag: 100},
+			{Topic: "events.EventNotificationRequested", MaxLag: 10},
+			{Topic: "events.EventAccountActivity"},
+			{Topic: "events.NeverPublished", MaxLag: 1},
+		}),
+	})
+}
+
+func TestLagMonitor_Collect(t *testing.T) {
+	source := &fakeOffsetSource{offsets: map[string]map[int32]partitionOffsets{
+		"dev.commands.SendOTPVerifyMailCommand": {
+			0: {oldest: 0, newest: 50, committed: 40},
+			1: {oldest: 0, newest: 80, committed: 60},
+		},
+		// nothing committed yet: the retained messages are the lag
+		"dev.events.EventNotificationRequested": {
+			0: {oldest: 20, newest: 35, committed: -1},
+		},
+		"dev.events.EventAccountActivity": {
+			0: {oldest: 0, newest: 1000, committed: 10},
+		},
+		// retention dropped the first messages of the poison queue
+		"dev.poison_queue": {
+			0: {oldest: 5, newest: 12},
+			1: {oldest: 0, newest: 3},
+		},
+	}}
+	monitor := newTestLagMonitor(source)
+
+	require.NoError(t, monitor.Collect(context.Background()))
+
+	report := monitor.Report()
+	assert.Equal(t, "tixgo", report.Group)
+	assert.Empty(t, report.Error)
+	assert.Equal(t, []TopicLag{
+		{Topic: "commands.SendOTPVerifyMailCommand", Lag: 30, MaxLag: 100},
+		{Topic: "events.EventAccountActivity", Lag: 990},
+		{Topic: "events.EventNotificationRequested", Lag: 15, MaxLag: 10, Behind: true},
+		{Topic: "events.NeverPublished", MaxLag: 1},
+	}, report.Topics)
+	assert.Equal(t, int64(10), report.PoisonQueue)
+
+	err := monitor.Check(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "events.EventNotificationRequested (15 > 10)")
+}
+
+func TestLagMonitor_CheckKeepsLastCollection(t *testing.T) {
+	source := &fakeOffsetSource{offsets: map[string]map[int32]partitionOffsets{
+		"dev.commands.SendOTPVerifyMailCommand": {0: {newest: 10, committed: 10}},
+	}}
+	monitor := newTestLagMonitor(source)
+
+	require.NoError(t, monitor.Collect(context.Background()))
+	assert.NoError(t, monitor.Check(context.Background()))
+	assert.Zero(t, monitor.Report().PoisonQueue, "nothing failed yet without a poison queue")
+
+	// an unreachable broker is reported but does not flip readiness
+	source.err = errors.New("broker unreachable")
+	require.Error(t, monitor.Collect(context.Background()))
+
+	report := monitor.Report()
+	assert.Equal(t, "failed to list the partitions of dev.commands.SendOTPVerifyMailCommand: broker unreachable", report.Error)
+	assert.Len(t, report.Topics, 4)
+	assert.NoError(t, monitor.Check(context.Background()))
+}
@@ -0,0 +1,148 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/duongptryu/gox/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// DefaultReconnectInterval is how often a lazy publisher retries to reach the brokers
+const DefaultReconnectInterval = 5 * time.Second
+
+// ErrUnavailable is returned by the publishes made before the brokers could be reached
+var ErrUnavailable = errors.New("kafka brokers are unavailable")
+
+// errPublisherClosed stops the connection of a publisher closed before it was connected
+var errPublisherClosed = errors.New("kafka publisher closed")
+
+var kafkaConnected = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "tixgo_kafka_connected",
+	Help: "1 once the publisher reached the kafka brokers, 0 while the instance runs degraded without them.",
+})
+
+// LazyPublisher connects its publisher in the background so the server starts, and serves what does
+// not need kafka, while the brokers are unreachable. Publishes fail with ErrUnavailable until the
+// publisher is connected.
+type LazyPublisher struct {
+	connect func() (message.Publisher, error)
+
+	mu        sync.RWMutex
+	publisher message.Publisher
+	lastErr   error
+	closed    bool
+	connected chan struct{}
+}
+
+// NewLazyPublisher creates a publisher connected by connect once Run reaches the brokers
+func NewLazyPublisher(connect func() (message.Publisher, error)) *LazyPublisher {
+	return &LazyPublisher{
+		connect:   connect,
+		lastErr:   ErrUnavailable,
+		connected: make(chan struct{}),
+	}
+}
+
+// Run connects the publisher, retrying every interval, DefaultReconnectInterval when it is not
+// positive, until it succeeds or ctx is done
+func (p *LazyPublisher) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultReconnectInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for attempt := 1; ; attempt++ {
+		err := p.tryConnect()
+		if err == nil {
+			logger.Info(ctx, "Kafka publisher connected", logger.F("attempts", attempt))
+			return
+		}
+		if errors.Is(err, errPublisherClosed) {
+			return
+		}
+		if attempt > 1 {
+			logger.Warning(ctx, "Kafka brokers are still unreachable", logger.F("attempts", attempt), logger.F("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *LazyPublisher) tryConnect() error {
+	publisher, err := p.connect()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err != nil {
+		p.lastErr = fmt.Errorf("%w: %v", ErrUnavailable, err)
+		return err
+	}
+	if p.closed {
+		_ = publisher.Close()
+		return errPublisherClosed
+	}
+
+	p.publisher = publisher
+	p.lastErr = nil
+	kafkaConnected.Set(1)
+	close(p.connected)
+	return nil
+}
+
+// Connected is closed once the publisher is connected
+func (p *LazyPublisher) Connected() <-chan struct{} {
+	return p.connected
+}
+
+// Wait blocks until the publisher is connected or ctx is done
+func (p *LazyPublisher) Wait(ctx context.Context) error {
+	select {
+	case <-p.connected:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Check fails while the publisher is not connected, telling why the brokers were not reached
+func (p *LazyPublisher) Check(ctx context.Context) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.lastErr
+}
+
+// Publish publishes the messages, failing with ErrUnavailable until the publisher is connected
+func (p *LazyPublisher) Publish(topic string, messages ...*message.Message) error {
+	p.mu.RLock()
+	publisher := p.publisher
+	p.mu.RUnlock()
+
+	if publisher == nil {
+		return ErrUnavailable
+	}
+	return publisher.Publish(topic, messages...)
+}
+
+// Close closes the publisher, and stops a pending connection from keeping its own
+func (p *LazyPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	if p.publisher == nil {
+		return nil
+	}
+	return p.publisher.Close()
+}
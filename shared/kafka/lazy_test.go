@@ -0,0 +1,58 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazyPublisher(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pubSub := gochannel.NewGoChannel(gochannel.Config{Persistent: true}, watermill.NopLogger{})
+	attempts := 0
+	publisher := NewLazyPublisher(func() (message.Publisher, error) {
+		attempts++
+		if attempts < 3 {
+			return nil, errors.New("connection refused")
+		}
+		return pubSub, nil
+	})
+
+	assert.ErrorIs(t, publisher.Publish("events.X", message.NewMessage(watermill.NewUUID(), nil)), ErrUnavailable)
+
+	go publisher.Run(ctx, time.Millisecond)
+	require.NoError(t, publisher.Wait(ctx))
+	assert.Equal(t, 3, attempts)
+	assert.NoError(t, publisher.Check(ctx))
+
+	messages, err := pubSub.Subscribe(ctx, "events.X")
+	require.NoError(t, err)
+	require.NoError(t, publisher.Publish("events.X", message.NewMessage("1", nil)))
+	assert.Equal(t, "1", (<-messages).UUID)
+
+	require.NoError(t, publisher.Close())
+}
+
+func TestLazyPublisher_Unreachable(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	publisher := NewLazyPublisher(func() (message.Publisher, error) {
+		return nil, errors.New("connection refused")
+	})
+	publisher.Run(ctx, time.Millisecond)
+
+	assert.ErrorIs(t, publisher.Wait(ctx), context.DeadlineExceeded)
+	err := publisher.Check(ctx)
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.ErrorContains(t, err, "connection refused")
+}
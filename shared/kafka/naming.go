@@ -0,0 +1,76 @@
+package kafka
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+// TopicNaming maps logical topic names (as generated by the bus) to physical, environment-prefixed Kafka topics
+type TopicNaming struct {
+	prefix string
+}
+
+// NewTopicNaming creates a topic naming strategy; an empty prefix keeps topic names unchanged
+func NewTopicNaming(prefix string) TopicNaming {
+	return TopicNaming{prefix: prefix}
+}
+
+// Physical returns the Kafka topic name of a logical topic
+func (n TopicNaming) Physical(topic string) string {
+	if n.prefix == "" {
+		return topic
+	}
+	return n.prefix + "." + topic
+}
+
+// Logical returns the logical topic name of a Kafka topic
+func (n TopicNaming) Logical(topic string) string {
+	if n.prefix == "" {
+		return topic
+	}
+	return strings.TrimPrefix(topic, n.prefix+".")
+}
+
+// NamingPublisher publishes to the physical topic of every logical topic
+type NamingPublisher struct {
+	publisher message.Publisher
+	naming    TopicNaming
+}
+
+// NewNamingPublisher wraps a publisher with a topic naming strategy
+func NewNamingPublisher(publisher message.Publisher, naming TopicNaming) *NamingPublisher {
+	return &NamingPublisher{publisher: publisher, naming: naming}
+}
+
+// Publish publishes messages to the physical topic
+func (p *NamingPublisher) Publish(topic string, messages ...*message.Message) error {
+	return p.publisher.Publish(p.naming.Physical(topic), messages...)
+}
+
+// Close closes the underlying publisher
+func (p *NamingPublisher) Close() error {
+	return p.publisher.Close()
+}
+
+// NamingSubscriber subscribes to the physical topic of every logical topic
+type NamingSubscriber struct {
+	subscriber message.Subscriber
+	naming     TopicNaming
+}
+
+// NewNamingSubscriber wraps a subscriber with a topic naming strategy
+func NewNamingSubscriber(subscriber message.Subscriber, naming TopicNaming) *NamingSubscriber {
+	return &NamingSubscriber{subscriber: subscriber, naming: naming}
+}
+
+// Subscribe subscribes to the physical topic
+func (s *NamingSubscriber) Subscribe(ctx context.Context, topic string) (<-chan *message.Message, error) {
+	return s.subscriber.Subscribe(ctx, s.naming.Physical(topic))
+}
+
+// Close closes the underlying subscriber
+func (s *NamingSubscriber) Close() error {
+	return s.subscriber.Close()
+}
@@ -0,0 +1,51 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/watermill-kafka/v3/pkg/kafka"
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+// PartitionKeyMetadata is the message metadata key carrying the partition key
+const PartitionKeyMetadata = "partition_key"
+
+type partitionKeyCtxKey struct{}
+
+// WithPartitionKey returns a context that makes the next publish use key as the Kafka partition key,
+// so every message sharing the key (e.g. a user email) lands on the same partition and keeps its order
+func WithPartitionKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, partitionKeyCtxKey{}, key)
+}
+
+// PartitionKeyFromContext returns the partition key stored in the context, if any
+func PartitionKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(partitionKeyCtxKey{}).(string)
+	return key
+}
+
+// NewPartitioningMarshaler creates a Kafka marshaler that partitions messages of ordered topics by key
+func NewPartitioningMarshaler(consumers *ConsumerSettings, naming TopicNaming) kafka.MarshalerUnmarshaler {
+	return kafka.NewWithPartitioningMarshaler(func(topic string, msg *message.Message) (string, error) {
+		return partitionKey(consumers, naming.Logical(topic), msg), nil
+	})
+}
+
+// partitionKey resolves the partition key of a message: explicit metadata first, then the publish context.
+// Unordered topics, and ordered messages published without a key, fall back to the message UUID to spread load.
+func partitionKey(consumers *ConsumerSettings, topic string, msg *message.Message) string {
+	if !consumers.IsOrdered(topic) {
+		return msg.UUID
+	}
+
+	key := msg.Metadata.Get(PartitionKeyMetadata)
+	if key == "" {
+		key = PartitionKeyFromContext(msg.Context())
+	}
+	if key == "" {
+		return msg.UUID
+	}
+
+	msg.Metadata.Set(PartitionKeyMetadata, key)
+	return key
+}
@@ -0,0 +1,82 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	pkgContext "github.com/duongptryu/gox/context"
+)
+
+// RequestIDMetadata is the message metadata key carrying the ID of the request a message was published for
+const RequestIDMetadata = "request_id"
+
+// RequestIDPublisher records the request ID of the publish context in the metadata of every message,
+// so the logs of its handlers can be joined with the logs of the HTTP request that caused it
+type RequestIDPublisher struct {
+	publisher message.Publisher
+}
+
+// NewRequestIDPublisher wraps a publisher with request ID propagation
+func NewRequestIDPublisher(publisher message.Publisher) *RequestIDPublisher {
+	return &RequestIDPublisher{publisher: publisher}
+}
+
+// Publish publishes messages along with their request ID
+func (p *RequestIDPublisher) Publish(topic string, messages ...*message.Message) error {
+	for _, msg := range messages {
+		if msg.Metadata.Get(RequestIDMetadata) != "" {
+			continue
+		}
+		if requestID := pkgContext.GetRequestID(msg.Context()); requestID != "" {
+			msg.Metadata.Set(RequestIDMetadata, requestID)
+		}
+	}
+	return p.publisher.Publish(topic, messages...)
+}
+
+// Close closes the underlying publisher
+func (p *RequestIDPublisher) Close() error {
+	return p.publisher.Close()
+}
+
+// RequestIDSubscriber restores the request ID of every message it delivers into the message context,
+// where the logger and any further publish pick it up
+type RequestIDSubscriber struct {
+	subscriber message.Subscriber
+}
+
+// NewRequestIDSubscriber wraps a subscriber with request ID propagation
+func NewRequestIDSubscriber(subscriber message.Subscriber) *RequestIDSubscriber {
+	return &RequestIDSubscriber{subscriber: subscriber}
+}
+
+// Subscribe subscribes to a topic and puts the request ID of each of its messages into their context
+func (s *RequestIDSubscriber) Subscribe(ctx context.Context, topic string) (<-chan *message.Message, error) {
+	messages, err := s.subscriber.Subscribe(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan *message.Message)
+	go func() {
+		defer close(out)
+		for msg := range messages {
+			if requestID := msg.Metadata.Get(RequestIDMetadata); requestID != "" {
+				msg.SetContext(pkgContext.WithRequestID(msg.Context(), requestID))
+			}
+
+			select {
+			case out <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// Close closes the underlying subscriber
+func (s *RequestIDSubscriber) Close() error {
+	return s.subscriber.Close()
+}
@@ -0,0 +1,153 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/duongptryu/gox/logger"
+)
+
+// TopicConfig declares a topic the platform relies on
+type TopicConfig struct {
+	Name              string
+	Partitions        int32
+	ReplicationFactor int16
+	Retention         time.Duration
+	CleanupPolicy     string
+}
+
+// Topology groups the naming, consumer settings and declared topics of the platform
+type Topology struct {
+	Naming    TopicNaming
+	Consumers *ConsumerSettings
+	Topics    []TopicConfig
+}
+
+// clusterAdmin is the subset of sarama.ClusterAdmin used by the topic manager
+type clusterAdmin interface {
+	ListTopics() (map[string]sarama.TopicDetail, error)
+	CreateTopic(topic string, detail *sarama.TopicDetail, validateOnly bool) error
+	CreatePartitions(topic string, count int32, assignment [][]int32, validateOnly bool) error
+	AlterConfig(resourceType sarama.ConfigResourceType, name string, entries map[string]*string, validateOnly bool) error
+	Close() error
+}
+
+// TopicManager declares the required topics on the cluster instead of relying on broker auto-creation
+type TopicManager struct {
+	admin     clusterAdmin
+	naming    TopicNaming
+	consumers *ConsumerSettings
+	topics    []TopicConfig
+}
+
+// NewTopicManager connects a cluster admin and creates a topic manager
+func NewTopicManager(brokers []string, topology Topology) (*TopicManager, error) {
+	admin, err := sarama.NewClusterAdmin(brokers, sarama.NewConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka cluster admin: %w", err)
+	}
+
+	return newTopicManager(admin, topology), nil
+}
+
+func newTopicManager(admin clusterAdmin, topology Topology) *TopicManager {
+	return &TopicManager{
+		admin:     admin,
+		naming:    topology.Naming,
+		consumers: topology.Consumers,
+		topics:    topology.Topics,
+	}
+}
+
+// EnsureTopics creates missing topics, grows partitions and applies topic configs.
+// With dryRun the broker only validates the requests.
+func (m *TopicManager) EnsureTopics(ctx context.Context, dryRun bool) error {
+	existing, err := m.admin.ListTopics()
+	if err != nil {
+		return fmt.Errorf("failed to list kafka topics: %w", err)
+	}
+
+	for _, topic := range m.topics {
+		name := m.naming.Physical(topic.Name)
+		partitions := m.partitions(topic)
+		entries := topicConfigEntries(topic)
+
+		detail, exists := existing[name]
+		if !exists {
+			logger.Info(ctx, "Creating kafka topic",
+				logger.F("topic", name),
+				logger.F("partitions", partitions),
+				logger.F("dry_run", dryRun))
+
+			err = m.admin.CreateTopic(name, &sarama.TopicDetail{
+				NumPartitions:     partitions,
+				ReplicationFactor: replicationFactor(topic),
+				ConfigEntries:     entries,
+			}, dryRun)
+			if err != nil {
+				return fmt.Errorf("failed to create kafka topic %s: %w", name, err)
+			}
+			continue
+		}
+
+		// Partitions can only grow, shrinking would break key ordering
+		if detail.NumPartitions < partitions {
+			logger.Info(ctx, "Increasing kafka topic partitions",
+				logger.F("topic", name),
+				logger.F("from", detail.NumPartitions),
+				logger.F("to", partitions),
+				logger.F("dry_run", dryRun))
+
+			err = m.admin.CreatePartitions(name, partitions, nil, dryRun)
+			if err != nil {
+				return fmt.Errorf("failed to increase partitions of kafka topic %s: %w", name, err)
+			}
+		}
+
+		if len(entries) > 0 {
+			err = m.admin.AlterConfig(sarama.TopicResource, name, entries, dryRun)
+			if err != nil {
+				return fmt.Errorf("failed to update config of kafka topic %s: %w", name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Close closes the cluster admin
+func (m *TopicManager) Close() error {
+	return m.admin.Close()
+}
+
+// partitions returns the declared partitions, never fewer than the topic consumer concurrency
+func (m *TopicManager) partitions(topic TopicConfig) int32 {
+	partitions := max(topic.Partitions, 1)
+	if concurrency := int32(m.consumers.Concurrency(topic.Name)); concurrency > partitions {
+		partitions = concurrency
+	}
+	return partitions
+}
+
+func replicationFactor(topic TopicConfig) int16 {
+	if topic.ReplicationFactor < 1 {
+		return 1
+	}
+	return topic.ReplicationFactor
+}
+
+func topicConfigEntries(topic TopicConfig) map[string]*string {
+	entries := make(map[string]*string)
+	if topic.Retention > 0 {
+		retention := strconv.FormatInt(topic.Retention.Milliseconds(), 10)
+		entries["retention.ms"] = &retention
+	}
+	if topic.CleanupPolicy != "" {
+		cleanupPolicy := topic.CleanupPolicy
+		entries["cleanup.policy"] = &cleanupPolicy
+	}
+	return entries
+}
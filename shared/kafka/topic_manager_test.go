@@ -0,0 +1,100 @@
+package kafka
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/duongptryu/gox/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init(&logger.Config{Output: io.Discard})
+	os.Exit(m.Run())
+}
+
+type fakeClusterAdmin struct {
+	topics     map[string]sarama.TopicDetail
+	created    map[string]*sarama.TopicDetail
+	partitions map[string]int32
+	configs    map[string]map[string]*string
+}
+
+func newFakeClusterAdmin(topics map[string]sarama.TopicDetail) *fakeClusterAdmin {
+	return &fakeClusterAdmin{
+		topics:     topics,
+		created:    make(map[string]*sarama.TopicDetail),
+		partitions: make(map[string]int32),
+		configs:    make(map[string]map[string]*string),
+	}
+}
+
+func (a *fakeClusterAdmin) ListTopics() (map[string]sarama.TopicDetail, error) {
+	return a.topics, nil
+}
+
+func (a *fakeClusterAdmin) CreateTopic(topic string, detail *sarama.TopicDetail, validateOnly bool) error {
+	a.created[topic] = detail
+	return nil
+}
+
+func (a *fakeClusterAdmin) CreatePartitions(topic string, count int32, assignment [][]int32, validateOnly bool) error {
+	a.partitions[topic] = count
+	return nil
+}
+
+func (a *fakeClusterAdmin) AlterConfig(resourceType sarama.ConfigResourceType, name string, entries map[string]*string, validateOnly bool) error {
+	a.configs[name] = entries
+	return nil
+}
+
+func (a *fakeClusterAdmin) Close() error {
+	return nil
+}
+
+func TestTopicNaming(t *testing.T) {
+	naming := NewTopicNaming("stg")
+	assert.Equal(t, "stg.events.EventUserRegistered", naming.Physical("events.EventUserRegistered"))
+	assert.Equal(t, "events.EventUserRegistered", naming.Logical("stg.events.EventUserRegistered"))
+
+	noPrefix := NewTopicNaming("")
+	assert.Equal(t, "events.EventUserRegistered", noPrefix.Physical("events.EventUserRegistered"))
+	assert.Equal(t, "events.EventUserRegistered", noPrefix.Logical("events.EventUserRegistered"))
+}
+
+func TestTopicManager_EnsureTopics(t *testing.T) {
+	admin := newFakeClusterAdmin(map[string]sarama.TopicDetail{
+		"dev.events.Existing": {NumPartitions: 2},
+	})
+
+	manager := newTopicManager(admin, Topology{
+		Naming: NewTopicNaming("dev"),
+		Consumers: NewConsumerSettings([]ConsumerConfig{
+			{Topic: "commands.SendOTPVerifyMail", Concurrency: 8},
+		}),
+		Topics: []TopicConfig{
+			{Name: "commands.SendOTPVerifyMail", Partitions: 3, Retention: 24 * time.Hour, CleanupPolicy: "delete"},
+			{Name: "events.Existing", Partitions: 6},
+		},
+	})
+
+	err := manager.EnsureTopics(context.Background(), false)
+	require.NoError(t, err)
+
+	// Missing topic is created with partitions raised to the consumer concurrency
+	created, ok := admin.created["dev.commands.SendOTPVerifyMail"]
+	require.True(t, ok)
+	assert.Equal(t, int32(8), created.NumPartitions)
+	assert.Equal(t, int16(1), created.ReplicationFactor)
+	assert.Equal(t, "86400000", *created.ConfigEntries["retention.ms"])
+	assert.Equal(t, "delete", *created.ConfigEntries["cleanup.policy"])
+
+	// Existing topic only grows its partitions
+	assert.Equal(t, int32(6), admin.partitions["dev.events.Existing"])
+	assert.NotContains(t, admin.created, "dev.events.Existing")
+}
@@ -0,0 +1,79 @@
+package listing
+
+import (
+	"encoding/json"
+
+	"github.com/duongptryu/gox/pagination"
+)
+
+// Count is how a listing computes the total of its rows, chosen per request with ?count=
+type Count string
+
+const (
+	// CountExact runs a COUNT(*) of every matching row
+	CountExact Count = "exact"
+	// CountEstimate uses the row estimate of the query planner, cheap but approximate
+	CountEstimate Count = "estimate"
+	// CountNone skips the total, has_more still tells whether a next page exists
+	CountNone Count = "none"
+)
+
+// Paging is the offset pagination of a listing along with how its total is counted.
+// Listings fetch one row more than the limit so HasMore is exact whatever the count.
+type Paging struct {
+	pagination.Paging
+	Count Count `json:"count" form:"count" binding:"omitempty,oneof=exact estimate none"`
+	// Estimated is set when Total comes from the planner rather than a count
+	Estimated bool `json:"estimated,omitempty"`
+	HasMore   bool `json:"has_more"`
+}
+
+// Fulfill applies the default page, limit and count
+func (p *Paging) Fulfill() {
+	p.Paging.Fulfill()
+	if p.Count == "" {
+		p.Count = CountExact
+	}
+}
+
+// FetchLimit is the number of rows to fetch for the page, one more than the limit to tell HasMore
+func (p *Paging) FetchLimit() int {
+	return p.Limit + 1
+}
+
+// Fetched records that n rows were fetched with FetchLimit and returns how many of them are on the page
+func (p *Paging) Fetched(n int) int {
+	p.HasMore = n > p.Limit
+	if p.HasMore {
+		return p.Limit
+	}
+	return n
+}
+
+// pagingJSON is the response form of Paging, leaving the total out when it was not counted
+type pagingJSON struct {
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	Total      *int64 `json:"total,omitempty"`
+	NextCursor int    `json:"next_cursor"`
+	Count      Count  `json:"count"`
+	Estimated  bool   `json:"estimated,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// MarshalJSON writes the paging of a response
+func (p Paging) MarshalJSON() ([]byte, error) {
+	out := pagingJSON{
+		Page:       p.Page,
+		Limit:      p.Limit,
+		NextCursor: p.NextCursor,
+		Count:      p.Count,
+		Estimated:  p.Estimated,
+		HasMore:    p.HasMore,
+	}
+	if p.Count != CountNone {
+		total := p.Total
+		out.Total = &total
+	}
+	return json.Marshal(out)
+}
@@ -0,0 +1,65 @@
+package listing
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/duongptryu/gox/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaging_Fulfill(t *testing.T) {
+	paging := &Paging{}
+	paging.Fulfill()
+
+	assert.Equal(t, 1, paging.Page)
+	assert.Equal(t, 10, paging.Limit)
+	assert.Equal(t, CountExact, paging.Count)
+}
+
+func TestPaging_Fetched(t *testing.T) {
+	paging := &Paging{Paging: pagination.Paging{Page: 1, Limit: 10}}
+	assert.Equal(t, 11, paging.FetchLimit())
+
+	t.Run("one row past the limit means a next page", func(t *testing.T) {
+		assert.Equal(t, 10, paging.Fetched(11))
+		assert.True(t, paging.HasMore)
+	})
+
+	t.Run("a full last page", func(t *testing.T) {
+		assert.Equal(t, 10, paging.Fetched(10))
+		assert.False(t, paging.HasMore)
+	})
+
+	t.Run("a partial last page", func(t *testing.T) {
+		assert.Equal(t, 3, paging.Fetched(3))
+		assert.False(t, paging.HasMore)
+	})
+}
+
+func TestPaging_MarshalJSON(t *testing.T) {
+	t.Run("counted total", func(t *testing.T) {
+		paging := Paging{Paging: pagination.Paging{Page: 2, Limit: 10, Total: 35}, Count: CountExact, HasMore: true}
+
+		body, err := json.Marshal(paging)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"page":2,"limit":10,"total":35,"next_cursor":0,"count":"exact","has_more":true}`, string(body))
+	})
+
+	t.Run("estimated total", func(t *testing.T) {
+		paging := Paging{Paging: pagination.Paging{Page: 1, Limit: 10, Total: 1520}, Count: CountEstimate, Estimated: true, HasMore: true}
+
+		body, err := json.Marshal(paging)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"page":1,"limit":10,"total":1520,"next_cursor":0,"count":"estimate","estimated":true,"has_more":true}`, string(body))
+	})
+
+	t.Run("uncounted total is left out", func(t *testing.T) {
+		paging := Paging{Paging: pagination.Paging{Page: 1, Limit: 10}, Count: CountNone}
+
+		body, err := json.Marshal(paging)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"page":1,"limit":10,"next_cursor":0,"count":"none","has_more":false}`, string(body))
+	})
+}
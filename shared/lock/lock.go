@@ -0,0 +1,30 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotAcquired is returned when the lock is currently held by another owner
+var ErrNotAcquired = errors.New("lock is held by another owner")
+
+// Locker provides mutual exclusion across every instance of the platform
+type Locker interface {
+	// WithLock runs fn while holding the lock on key, returning ErrNotAcquired without running fn
+	// when the lock is already held. The ttl bounds how long a crashed owner keeps the lock.
+	WithLock(ctx context.Context, key string, ttl time.Duration, fn func(ctx context.Context) error) error
+}
+
+// release is called once fn returns to give the lock back
+type release func(ctx context.Context) error
+
+// run executes fn and always releases the lock, even when ctx was cancelled meanwhile
+func run(ctx context.Context, unlock release, fn func(ctx context.Context) error) error {
+	err := fn(ctx)
+
+	if releaseErr := unlock(context.WithoutCancel(ctx)); releaseErr != nil && err == nil {
+		return releaseErr
+	}
+	return err
+}
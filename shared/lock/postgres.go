@@ -0,0 +1,66 @@
+package lock
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"tixgo/shared/dbtimeout"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgresLocker implements Locker with session-level advisory locks.
+// The lock lives as long as the dedicated connection, so ttl is not used: a crashed owner
+// releases it when its session is closed by the server.
+type PostgresLocker struct {
+	db *sqlx.DB
+}
+
+// NewPostgresLocker creates a locker backed by postgres advisory locks
+func NewPostgresLocker(db *sqlx.DB) *PostgresLocker {
+	return &PostgresLocker{db: db}
+}
+
+func (l *PostgresLocker) WithLock(ctx context.Context, key string, ttl time.Duration, fn func(ctx context.Context) error) error {
+	// only acquiring is bounded by the query timeout, the session then lives as long as fn runs
+	acquireCtx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	conn, err := l.db.Connx(acquireCtx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire lock %s: %w", key, err)
+	}
+
+	lockID := advisoryLockID(key)
+
+	var acquired bool
+	if err := conn.GetContext(acquireCtx, &acquired, `SELECT pg_try_advisory_lock($1)`, lockID); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to acquire lock %s: %w", key, err)
+	}
+	if !acquired {
+		conn.Close()
+		return ErrNotAcquired
+	}
+
+	return run(ctx, func(releaseCtx context.Context) error {
+		defer conn.Close()
+
+		if _, err := conn.ExecContext(releaseCtx, `SELECT pg_advisory_unlock($1)`, lockID); err != nil {
+			// drop the session instead of returning it to the pool still holding the lock
+			conn.Raw(func(any) error { return driver.ErrBadConn })
+			return fmt.Errorf("failed to release lock %s: %w", key, err)
+		}
+		return nil
+	}, fn)
+}
+
+// advisoryLockID maps a lock key onto the bigint keyspace of advisory locks
+func advisoryLockID(key string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return int64(h.Sum64())
+}
@@ -0,0 +1,107 @@
+package lock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+const redisKeyPrefix = "lock:"
+
+// releaseScript deletes the key only if it still holds our token
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// refreshScript extends the ttl only if the key still holds our token
+var refreshScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+// RedisLocker implements Locker with SET NX and a per-owner token.
+// The lock is refreshed while fn runs; if it is lost, the context passed to fn is cancelled.
+type RedisLocker struct {
+	client redis.UniversalClient
+}
+
+// NewRedisLocker creates a locker backed by redis
+func NewRedisLocker(client redis.UniversalClient) *RedisLocker {
+	return &RedisLocker{client: client}
+}
+
+func (l *RedisLocker) WithLock(ctx context.Context, key string, ttl time.Duration, fn func(ctx context.Context) error) error {
+	if ttl <= 0 {
+		return fmt.Errorf("invalid ttl %s for lock %s", ttl, key)
+	}
+	redisKey := redisKeyPrefix + key
+
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+
+	ok, err := l.client.SetNX(ctx, redisKey, token, ttl).Result()
+	if err != nil {
+		return fmt.Errorf("failed to acquire lock %s: %w", key, err)
+	}
+	if !ok {
+		return ErrNotAcquired
+	}
+
+	lockCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go l.keepAlive(lockCtx, cancel, redisKey, token, ttl, done)
+
+	return run(lockCtx, func(releaseCtx context.Context) error {
+		cancel()
+		<-done
+
+		if err := releaseScript.Run(releaseCtx, l.client, []string{redisKey}, token).Err(); err != nil {
+			return fmt.Errorf("failed to release lock %s: %w", key, err)
+		}
+		return nil
+	}, fn)
+}
+
+// keepAlive extends the lock every third of its ttl and cancels the holder once the lock is lost
+func (l *RedisLocker) keepAlive(ctx context.Context, cancel context.CancelFunc, redisKey, token string, ttl time.Duration, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(max(ttl/3, time.Millisecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			refreshed, err := refreshScript.Run(ctx, l.client, []string{redisKey}, token, ttl.Milliseconds()).Int()
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			if err != nil || refreshed == 0 {
+				logger.Warning(ctx, "Lost distributed lock", logger.F("key", redisKey), logger.F("error", err))
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate lock token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,113 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/duongptryu/gox/logger"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init(&logger.Config{Output: io.Discard})
+	os.Exit(m.Run())
+}
+
+func newTestRedisLocker(t *testing.T) (*RedisLocker, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewRedisLocker(client), server
+}
+
+func TestRedisLocker_WithLock(t *testing.T) {
+	t.Run("runs fn and releases the lock", func(t *testing.T) {
+		locker, server := newTestRedisLocker(t)
+
+		ran := false
+		err := locker.WithLock(context.Background(), "job", time.Minute, func(ctx context.Context) error {
+			ran = true
+			assert.True(t, server.Exists("lock:job"))
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.True(t, ran)
+		assert.False(t, server.Exists("lock:job"))
+	})
+
+	t.Run("returns ErrNotAcquired while held", func(t *testing.T) {
+		locker, _ := newTestRedisLocker(t)
+
+		err := locker.WithLock(context.Background(), "job", time.Minute, func(ctx context.Context) error {
+			return locker.WithLock(ctx, "job", time.Minute, func(context.Context) error {
+				t.Fatal("fn must not run while the lock is held")
+				return nil
+			})
+		})
+
+		assert.ErrorIs(t, err, ErrNotAcquired)
+	})
+
+	t.Run("returns fn error and still releases", func(t *testing.T) {
+		locker, server := newTestRedisLocker(t)
+		fnErr := errors.New("boom")
+
+		err := locker.WithLock(context.Background(), "job", time.Minute, func(context.Context) error {
+			return fnErr
+		})
+
+		assert.ErrorIs(t, err, fnErr)
+		assert.False(t, server.Exists("lock:job"))
+	})
+
+	t.Run("does not release a lock taken over by another owner", func(t *testing.T) {
+		locker, server := newTestRedisLocker(t)
+
+		err := locker.WithLock(context.Background(), "job", time.Minute, func(context.Context) error {
+			return server.Set("lock:job", "other-owner")
+		})
+
+		require.NoError(t, err)
+		value, _ := server.Get("lock:job")
+		assert.Equal(t, "other-owner", value)
+	})
+
+	t.Run("cancels fn once the lock is lost", func(t *testing.T) {
+		locker, server := newTestRedisLocker(t)
+
+		err := locker.WithLock(context.Background(), "job", 30*time.Millisecond, func(ctx context.Context) error {
+			server.Del("lock:job")
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Second):
+				return nil
+			}
+		})
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("keeps the lock alive past its ttl", func(t *testing.T) {
+		locker, server := newTestRedisLocker(t)
+
+		err := locker.WithLock(context.Background(), "job", 30*time.Millisecond, func(ctx context.Context) error {
+			server.FastForward(20 * time.Millisecond)
+			time.Sleep(50 * time.Millisecond)
+			server.FastForward(20 * time.Millisecond)
+			assert.True(t, server.Exists("lock:job"))
+			return ctx.Err()
+		})
+
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,169 @@
+package migrationlint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Rule identifies an unsafe operation
+type Rule string
+
+const (
+	// RuleIndexNotConcurrent: CREATE INDEX without CONCURRENTLY blocks writes to the table while it builds
+	RuleIndexNotConcurrent Rule = "index-not-concurrent"
+	// RuleDropColumn: dropping a column breaks instances still running the previous release
+	RuleDropColumn Rule = "drop-column"
+	// RuleDropTable: dropping a table loses data and breaks instances still running the previous release
+	RuleDropTable Rule = "drop-table"
+	// RuleAlterColumnType: changing a column type rewrites the table under an exclusive lock
+	RuleAlterColumnType Rule = "alter-column-type"
+	// RuleSetNotNull: SET NOT NULL scans the whole table under an exclusive lock
+	RuleSetNotNull Rule = "set-not-null"
+	// RuleConstraintNotValid: adding a CHECK or FOREIGN KEY without NOT VALID scans the whole table under lock
+	RuleConstraintNotValid Rule = "constraint-not-valid"
+	// RuleVolatileDefault: adding a column with a volatile default rewrites the table
+	RuleVolatileDefault Rule = "volatile-default"
+	// RuleValidateSameMigration: validating a constraint added NOT VALID in the same migration scans the
+	// table in the transaction still holding the lock of the ADD CONSTRAINT
+	RuleValidateSameMigration Rule = "validate-same-migration"
+)
+
+// Finding is an unsafe statement of a migration
+type Finding struct {
+	File    string
+	Line    int
+	Rule    Rule
+	Message string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s:%d: %s: %s", f.File, f.Line, f.Rule, f.Message)
+}
+
+var (
+	createTableRe  = regexp.MustCompile(`^CREATE\s+(?:UNLOGGED\s+)?TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?([\w."]+)`)
+	createIndexRe  = regexp.MustCompile(`^CREATE\s+(?:UNIQUE\s+)?INDEX\s+(CONCURRENTLY\s+)?.*?\bON\s+(?:ONLY\s+)?([\w."]+)`)
+	alterTableRe   = regexp.MustCompile(`^ALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(?:ONLY\s+)?([\w."]+)`)
+	dropTableRe    = regexp.MustCompile(`^DROP\s+TABLE\b`)
+	dropColumnRe   = regexp.MustCompile(`\bDROP\s+(?:COLUMN\s+)?(?:IF\s+EXISTS\s+)?[\w"]+\s*(?:CASCADE|RESTRICT)?\s*(?:,|$)`)
+	dropOtherRe    = regexp.MustCompile(`\bDROP\s+(?:CONSTRAINT|DEFAULT|NOT\s+NULL|IDENTITY|EXPRESSION)\b`)
+	columnTypeRe   = regexp.MustCompile(`\bALTER\s+(?:COLUMN\s+)?[\w"]+\s+(?:SET\s+DATA\s+)?TYPE\b`)
+	setNotNullRe   = regexp.MustCompile(`\bSET\s+NOT\s+NULL\b`)
+	addConstraint  = regexp.MustCompile(`\bADD\s+(?:CONSTRAINT\s+[\w"]+\s+)?(?:CHECK|FOREIGN\s+KEY)\b`)
+	notValidRe     = regexp.MustCompile(`\bNOT\s+VALID\b`)
+	constraintName = regexp.MustCompile(`\bADD\s+CONSTRAINT\s+([\w"]+)\s+(?:CHECK|FOREIGN\s+KEY)\b`)
+	validateRe     = regexp.MustCompile(`\bVALIDATE\s+CONSTRAINT\s+([\w"]+)`)
+	addColumnRe    = regexp.MustCompile(`\bADD\s+(?:COLUMN\s+)?(?:IF\s+NOT\s+EXISTS\s+)?[\w"]+\s+[^,]*?\bDEFAULT\s+([^,]+)`)
+	stableDefaults = regexp.MustCompile(`^(?:NOW\(\)|CURRENT_TIMESTAMP|CURRENT_DATE|LOCALTIMESTAMP|TRANSACTION_TIMESTAMP\(\))`)
+	versionRe      = regexp.MustCompile(`^(\d+)_.*\.up\.sql$`)
+)
+
+// Lint returns the unsafe statements of the migration named file.
+// Statements on tables created earlier in the same migration are safe: nothing reads them yet.
+func Lint(file, sql string) []Finding {
+	var findings []Finding
+	created := map[string]bool{}
+	// notValid are the constraints added NOT VALID so far, by table and name
+	notValid := map[string]bool{}
+
+	for _, stmt := range splitStatements(sql) {
+		text := strings.ToUpper(strings.Join(strings.Fields(stmt.text), " "))
+		report := func(rule Rule, message string) {
+			findings = append(findings, Finding{File: file, Line: stmt.line, Rule: rule, Message: message})
+		}
+
+		if m := createTableRe.FindStringSubmatch(text); m != nil {
+			created[tableName(m[1])] = true
+			continue
+		}
+
+		if m := createIndexRe.FindStringSubmatch(text); m != nil {
+			if m[1] == "" && !created[tableName(m[2])] {
+				report(RuleIndexNotConcurrent, "create the index CONCURRENTLY in a migration of its own")
+			}
+			continue
+		}
+
+		if dropTableRe.MatchString(text) {
+			report(RuleDropTable, "drop the table only once no released version uses it")
+			continue
+		}
+
+		m := alterTableRe.FindStringSubmatch(text)
+		if m == nil || created[tableName(m[1])] {
+			continue
+		}
+		actions := text[len(m[0]):]
+
+		if dropColumnRe.MatchString(dropOtherRe.ReplaceAllString(actions, "")) {
+			report(RuleDropColumn, "stop using the column in a release first, then drop it")
+		}
+		if columnTypeRe.MatchString(actions) {
+			report(RuleAlterColumnType, "add a new column, backfill it in batches and switch over instead")
+		}
+		if setNotNullRe.MatchString(actions) {
+			report(RuleSetNotNull, "add a CHECK (col IS NOT NULL) NOT VALID constraint and validate it separately")
+		}
+		if addConstraint.MatchString(actions) && !notValidRe.MatchString(actions) {
+			report(RuleConstraintNotValid, "add the constraint NOT VALID, then VALIDATE CONSTRAINT in a later migration")
+		}
+		if notValidRe.MatchString(actions) {
+			for _, c := range constraintName.FindAllStringSubmatch(actions, -1) {
+				notValid[tableName(m[1])+"."+tableName(c[1])] = true
+			}
+		}
+		for _, v := range validateRe.FindAllStringSubmatch(actions, -1) {
+			if notValid[tableName(m[1])+"."+tableName(v[1])] {
+				report(RuleValidateSameMigration, "validate the constraint in a later migration, a migration runs in one transaction")
+			}
+		}
+		if d := addColumnRe.FindStringSubmatch(actions); d != nil && strings.Contains(d[1], "(") && !stableDefaults.MatchString(strings.TrimSpace(d[1])) {
+			report(RuleVolatileDefault, "add the column without a default, then backfill it in batches")
+		}
+	}
+
+	return findings
+}
+
+// LintDir lints the up migrations of dir whose version is greater than after, i.e. the ones
+// a database at version after has yet to apply
+func LintDir(dir string, after uint) ([]Finding, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory %s: %w", dir, err)
+	}
+
+	var findings []Finding
+	for _, entry := range entries {
+		m := versionRe.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || m == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(m[1], 10, 64)
+		if err != nil || uint(version) <= after {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+		findings = append(findings, Lint(entry.Name(), string(content))...)
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].File < findings[j].File })
+	return findings, nil
+}
+
+func tableName(name string) string {
+	name = strings.ReplaceAll(name, `"`, "")
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
@@ -0,0 +1,125 @@
+package migrationlint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rules(findings []Finding) []Rule {
+	var out []Rule
+	for _, f := range findings {
+		out = append(out, f.Rule)
+	}
+	return out
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want []Rule
+	}{
+		{
+			name: "indexes on a new table are safe",
+			sql: `CREATE TABLE IF NOT EXISTS job_runs (id BIGSERIAL PRIMARY KEY, name TEXT NOT NULL);
+			      CREATE INDEX idx_job_runs_name ON job_runs(name);
+			      ALTER TABLE job_runs ADD CONSTRAINT job_runs_name_check CHECK (name <> '');`,
+		},
+		{
+			name: "non-concurrent index on an existing table",
+			sql:  `CREATE INDEX idx_users_phone ON users(phone);`,
+			want: []Rule{RuleIndexNotConcurrent},
+		},
+		{
+			name: "concurrent index",
+			sql:  `CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS idx_users_phone ON public.users (phone);`,
+		},
+		{
+			name: "column and table drops",
+			sql: `ALTER TABLE users DROP COLUMN phone;
+			      ALTER TABLE users DROP legacy_id CASCADE;
+			      DROP TABLE IF EXISTS old_users;`,
+			want: []Rule{RuleDropColumn, RuleDropColumn, RuleDropTable},
+		},
+		{
+			name: "dropping constraints and defaults is safe",
+			sql: `ALTER TABLE users DROP CONSTRAINT users_phone_check;
+			      ALTER TABLE users ALTER COLUMN phone DROP DEFAULT, ALTER COLUMN phone DROP NOT NULL;`,
+		},
+		{
+			name: "table rewrites and full scans",
+			sql: `ALTER TABLE users ALTER COLUMN phone TYPE VARCHAR(32);
+			      ALTER TABLE users ALTER COLUMN phone SET NOT NULL;
+			      ALTER TABLE orders ADD CONSTRAINT fk_orders_user FOREIGN KEY (user_id) REFERENCES users(id);
+			      ALTER TABLE orders ADD COLUMN token UUID NOT NULL DEFAULT gen_random_uuid();`,
+			want: []Rule{RuleAlterColumnType, RuleSetNotNull, RuleConstraintNotValid, RuleVolatileDefault},
+		},
+		{
+			name: "online alternatives",
+			sql: `ALTER TABLE orders ADD CONSTRAINT fk_orders_user FOREIGN KEY (user_id) REFERENCES users(id) NOT VALID;
+			      ALTER TABLE orders ADD COLUMN note TEXT DEFAULT 'none', ADD COLUMN seen_at TIMESTAMP DEFAULT NOW();`,
+		},
+		{
+			name: "validating a constraint of an earlier migration",
+			sql:  `ALTER TABLE orders VALIDATE CONSTRAINT fk_orders_user;`,
+		},
+		{
+			name: "validating in the migration adding the constraint",
+			sql: `ALTER TABLE events ADD CONSTRAINT events_mode_check CHECK (mode IN ('a', 'b')) NOT VALID;
+			      ALTER TABLE public.events VALIDATE CONSTRAINT "events_mode_check";
+			      ALTER TABLE orders VALIDATE CONSTRAINT events_mode_check;`,
+			want: []Rule{RuleValidateSameMigration},
+		},
+		{
+			name: "comments, literals and function bodies are ignored",
+			sql: `-- DROP TABLE users;
+			      /* ALTER TABLE users DROP COLUMN phone; */
+			      CREATE OR REPLACE FUNCTION f() RETURNS TRIGGER AS $$
+			      BEGIN
+			          EXECUTE 'DROP TABLE users; ALTER TABLE users DROP COLUMN phone';
+			      END;
+			      $$ LANGUAGE plpgsql;
+			      COMMENT ON TABLE users IS 'DROP TABLE; it''s fine';`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, rules(Lint("test.up.sql", tt.sql)))
+		})
+	}
+}
+
+func TestLint_Lines(t *testing.T) {
+	findings := Lint("000004_x.up.sql", "-- header\n\nCREATE INDEX idx ON users(email);\n\nALTER TABLE users\n  DROP COLUMN phone;\n")
+
+	require.Len(t, findings, 2)
+	assert.Equal(t, 3, findings[0].Line)
+	assert.Equal(t, 5, findings[1].Line)
+	assert.Equal(t, "000004_x.up.sql:5: drop-column: stop using the column in a release first, then drop it", findings[1].String())
+}
+
+func TestLintDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"000001_init.up.sql":    "CREATE INDEX idx ON users(email);",
+		"000001_init.down.sql":  "DROP TABLE users;",
+		"000002_phone.up.sql":   "ALTER TABLE users DROP COLUMN phone;",
+		"000002_phone.down.sql": "ALTER TABLE users ADD COLUMN phone TEXT;",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	findings, err := LintDir(dir, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []Rule{RuleIndexNotConcurrent, RuleDropColumn}, rules(findings))
+
+	findings, err = LintDir(dir, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []Rule{RuleDropColumn}, rules(findings), "applied migrations are skipped")
+}
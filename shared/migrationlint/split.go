@@ -0,0 +1,103 @@
+package migrationlint
+
+import (
+	"regexp"
+	"strings"
+)
+
+type statement struct {
+	text string
+	// line is the 1-based line the statement starts on
+	line int
+}
+
+var dollarTagRe = regexp.MustCompile(`^\$[A-Za-z_]*\$`)
+
+// splitStatements splits sql on semicolons, skipping comments, string literals and
+// dollar-quoted bodies (function definitions), which are dropped from the statement text
+func splitStatements(sql string) []statement {
+	var (
+		statements []statement
+		current    strings.Builder
+		line       = 1
+		start      = 0
+	)
+
+	flush := func() {
+		if text := strings.TrimSpace(current.String()); text != "" {
+			statements = append(statements, statement{text: text, line: start})
+		}
+		current.Reset()
+		start = 0
+	}
+
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		rest := sql[i:]
+
+		switch {
+		case strings.HasPrefix(rest, "--"):
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			i += end - 1
+			continue
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest, "*/")
+			if end < 0 {
+				end = len(rest) - 2
+			}
+			line += strings.Count(rest[:end+2], "\n")
+			i += end + 1
+			continue
+		case c == '\'':
+			end := closingQuote(rest)
+			line += strings.Count(rest[:end], "\n")
+			current.WriteString(" '' ")
+			i += end - 1
+			continue
+		case c == '$':
+			if tag := dollarTagRe.FindString(rest); tag != "" {
+				end := strings.Index(rest[len(tag):], tag)
+				if end < 0 {
+					end = len(rest) - 2*len(tag)
+				}
+				body := rest[:len(tag)+end+len(tag)]
+				line += strings.Count(body, "\n")
+				current.WriteString(" $$ ")
+				i += len(body) - 1
+				continue
+			}
+		case c == ';':
+			flush()
+			continue
+		}
+
+		if c == '\n' {
+			line++
+		}
+		if start == 0 && c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+			start = line
+		}
+		current.WriteByte(c)
+	}
+	flush()
+
+	return statements
+}
+
+// closingQuote returns the length of the string literal starting at s[0]; a doubled quote is an escaped quote
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		if s[i] != '\'' {
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == '\'' {
+			i++
+			continue
+		}
+		return i + 1
+	}
+	return len(s)
+}
@@ -0,0 +1,156 @@
+// Package money handles the currencies of prices and the amounts in them. Amounts are decimal strings,
+// as the DECIMAL(10, 2) columns hold them, so currencies with more than 2 decimals are not supported.
+// Payment providers take amounts in the minor unit of their currency, which ToMinor converts to: cents
+// of a dollar, but whole yen or dong.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang.org/x/text/currency"
+)
+
+// DefaultCurrency is the currency of the events created before events had one
+const DefaultCurrency = "USD"
+
+var (
+	// ErrUnknownCurrency is returned for codes that are not ISO 4217 currencies, or have more decimals
+	// than amounts are stored with
+	ErrUnknownCurrency = errors.New("unknown currency")
+	// ErrInvalidAmount is returned for amounts that are not decimals, or have more decimals than their
+	// currency
+	ErrInvalidAmount = errors.New("invalid amount")
+)
+
+// maxDigits is the most decimals amounts are stored with
+const maxDigits = 2
+
+// Normalize returns the upper-case ISO 4217 code of a currency, ErrUnknownCurrency if it is not one
+// amounts can be stored in
+func Normalize(code string) (string, error) {
+	unit, err := currency.ParseISO(strings.TrimSpace(code))
+	if err != nil {
+		return "", ErrUnknownCurrency
+	}
+	if scale, _ := currency.Standard.Rounding(unit); scale > maxDigits {
+		return "", ErrUnknownCurrency
+	}
+	return unit.String(), nil
+}
+
+// Digits returns the decimals of the minor unit of a currency: 2 for the dollar, 0 for the yen. Unknown
+// currencies have 2.
+func Digits(code string) int {
+	unit, err := currency.ParseISO(code)
+	if err != nil {
+		return maxDigits
+	}
+	scale, _ := currency.Standard.Rounding(unit)
+	return min(scale, maxDigits)
+}
+
+// parse reads a decimal amount, ErrInvalidAmount if it is not one
+func parse(amount string) (*big.Rat, error) {
+	value, ok := new(big.Rat).SetString(strings.TrimSpace(amount))
+	if !ok || strings.ContainsAny(amount, "eE/") {
+		return nil, ErrInvalidAmount
+	}
+	return value, nil
+}
+
+// ToMinor converts an amount to the minor unit of its currency, what payment providers charge: "12.50"
+// dollars are 1250 cents, "120000.00" dong are 120000 dong. Amounts finer than the minor unit are
+// refused with ErrInvalidAmount rather than rounded.
+func ToMinor(amount, code string) (int64, error) {
+	value, err := parse(amount)
+	if err != nil {
+		return 0, err
+	}
+
+	value.Mul(value, new(big.Rat).SetInt(pow10(Digits(code))))
+	if !value.IsInt() || !value.Num().IsInt64() {
+		return 0, ErrInvalidAmount
+	}
+	return value.Num().Int64(), nil
+}
+
+// FromMinor converts an amount in the minor unit of its currency back to a decimal amount, with the
+// decimals of the currency
+func FromMinor(minor int64, code string) string {
+	return new(big.Rat).SetFrac(big.NewInt(minor), pow10(Digits(code))).FloatString(Digits(code))
+}
+
+// Convert converts an amount to another currency at rate, the units of to a unit of from buys, rounded
+// half away from zero to the decimals of to
+func Convert(amount, rate, to string) (string, error) {
+	value, err := parse(amount)
+	if err != nil {
+		return "", err
+	}
+	factor, err := parse(rate)
+	if err != nil || factor.Sign() <= 0 {
+		return "", fmt.Errorf("invalid rate %q", rate)
+	}
+	return round(value.Mul(value, factor), Digits(to)), nil
+}
+
+// Round writes an amount with the decimals of its currency, rounded half away from zero
+func Round(amount, code string) (string, error) {
+	value, err := parse(amount)
+	if err != nil {
+		return "", err
+	}
+	return round(value, Digits(code)), nil
+}
+
+// Format writes an amount for people, whatever their locale: its digits grouped by thousands with
+// commas and the decimals of its currency, followed by the code, e.g. "1,250.00 USD" or "120,000 VND".
+// Templates format amounts in the locale of their reader instead.
+func Format(amount, code string) (string, error) {
+	rounded, err := Round(amount, code)
+	if err != nil {
+		return "", err
+	}
+
+	sign, digits := "", rounded
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+	whole, decimals, _ := strings.Cut(digits, ".")
+
+	var grouped strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+	if decimals != "" {
+		grouped.WriteString("." + decimals)
+	}
+	return sign + grouped.String() + " " + code, nil
+}
+
+// round writes value with digits decimals, rounded half away from zero
+func round(value *big.Rat, digits int) string {
+	scale := pow10(digits)
+	scaled := new(big.Rat).Mul(value, new(big.Rat).SetInt(scale))
+
+	// add a half away from zero, then truncate toward zero
+	half := big.NewRat(1, 2)
+	if scaled.Sign() < 0 {
+		half.Neg(half)
+	}
+	scaled.Add(scaled, half)
+	truncated := new(big.Int).Quo(scaled.Num(), scaled.Denom())
+
+	return new(big.Rat).SetFrac(truncated, scale).FloatString(digits)
+}
+
+// pow10 returns 10 to the power of n
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
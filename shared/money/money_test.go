@@ -0,0 +1,86 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	for code, want := range map[string]string{"usd": "USD", " EUR ": "EUR", "vnd": "VND", "JPY": "JPY"} {
+		got, err := Normalize(code)
+		require.NoError(t, err, code)
+		assert.Equal(t, want, got)
+	}
+
+	for _, code := range []string{"", "US", "XYZ", "dollars", "KWD"} {
+		_, err := Normalize(code)
+		assert.ErrorIs(t, err, ErrUnknownCurrency, code)
+	}
+}
+
+func TestMinorUnits(t *testing.T) {
+	tests := []struct {
+		amount string
+		code   string
+		minor  int64
+		back   string
+	}{
+		{amount: "12.50", code: "USD", minor: 1250, back: "12.50"},
+		{amount: "12.5", code: "EUR", minor: 1250, back: "12.50"},
+		{amount: "0", code: "USD", minor: 0, back: "0.00"},
+		{amount: "120000.00", code: "VND", minor: 120000, back: "120000"},
+		{amount: "3000", code: "JPY", minor: 3000, back: "3000"},
+	}
+	for _, tt := range tests {
+		minor, err := ToMinor(tt.amount, tt.code)
+		require.NoError(t, err, tt.amount)
+		assert.Equal(t, tt.minor, minor, tt.amount)
+		assert.Equal(t, tt.back, FromMinor(minor, tt.code))
+	}
+
+	for _, amount := range []string{"12.505", "abc", "1e3", "1/2", ""} {
+		_, err := ToMinor(amount, "USD")
+		assert.ErrorIs(t, err, ErrInvalidAmount, amount)
+	}
+	_, err := ToMinor("100.50", "VND")
+	assert.ErrorIs(t, err, ErrInvalidAmount, "dong have no decimals")
+}
+
+func TestConvert(t *testing.T) {
+	converted, err := Convert("10.00", "25400.5", "VND")
+	require.NoError(t, err)
+	assert.Equal(t, "254005", converted)
+
+	converted, err = Convert("100000", "0.0000394", "USD")
+	require.NoError(t, err)
+	assert.Equal(t, "3.94", converted)
+
+	converted, err = Convert("0.01", "0.5", "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, "0.01", converted, "halves round away from zero")
+
+	_, err = Convert("10.00", "0", "EUR")
+	assert.Error(t, err)
+}
+
+func TestFormat(t *testing.T) {
+	tests := map[string][3]string{
+		"grouped":          {"1250", "USD", "1,250.00 USD"},
+		"no decimals":      {"120000.00", "VND", "120,000 VND"},
+		"rounded":          {"2.345", "EUR", "2.35 EUR"},
+		"negative":         {"-1234567.8", "USD", "-1,234,567.80 USD"},
+		"below a thousand": {"999.99", "GBP", "999.99 GBP"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			formatted, err := Format(tt[0], tt[1])
+			require.NoError(t, err)
+			assert.Equal(t, tt[2], formatted)
+		})
+	}
+
+	_, err := Format("ten", "USD")
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+}
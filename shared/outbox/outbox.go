@@ -0,0 +1,80 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+)
+
+// Event is an event parked in the outbox after its publish failed
+type Event struct {
+	ID           int64
+	Name         string
+	PartitionKey string
+	Payload      []byte
+	Attempts     int
+	LastError    string
+	CreatedAt    time.Time
+}
+
+// Store keeps the parked events until they are published
+type Store interface {
+	// Add parks an event
+	Add(ctx context.Context, event *Event) error
+
+	// ListPending retrieves up to limit parked events, oldest first
+	ListPending(ctx context.Context, limit int) ([]*Event, error)
+
+	// Delete removes an event once it is published
+	Delete(ctx context.Context, id int64) error
+
+	// RecordFailure counts a failed attempt to publish an event
+	RecordFailure(ctx context.Context, id int64, reason string) error
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]reflect.Type{}
+)
+
+// Register declares the events that may be parked, so the relay can decode them back to their type.
+// Events are named like the bus names them, by their struct name.
+func Register(events ...any) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for _, event := range events {
+		registry[cqrs.StructName(event)] = reflect.Indirect(reflect.ValueOf(event)).Type()
+	}
+}
+
+// isRegistered reports whether events named name may be parked
+func isRegistered(name string) bool {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	_, ok := registry[name]
+	return ok
+}
+
+// decode rebuilds the event of a parked event
+func decode(event *Event) (any, error) {
+	registryMu.RLock()
+	eventType, ok := registry[event.Name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("outbox event %s is not registered", event.Name)
+	}
+
+	value := reflect.New(eventType).Interface()
+	if err := json.Unmarshal(event.Payload, value); err != nil {
+		return nil, fmt.Errorf("failed to decode outbox event %s: %w", event.Name, err)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,171 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"testing"
+
+	sharedKafka "tixgo/shared/kafka"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testParkedEvent struct {
+	Email string `json:"email"`
+}
+
+type testUnregisteredEvent struct{}
+
+func TestMain(m *testing.M) {
+	logger.Init(&logger.Config{Output: io.Discard})
+	Register(testParkedEvent{})
+	os.Exit(m.Run())
+}
+
+// fakeBus fails the first failures publishes, with err when set, and records the published events with
+// their partition key
+type fakeBus struct {
+	failures  int
+	err       error
+	calls     int
+	published []any
+	keys      []string
+}
+
+func (b *fakeBus) PublishEvent(ctx context.Context, event any) error {
+	b.calls++
+	if b.calls <= b.failures {
+		if b.err != nil {
+			return b.err
+		}
+		return errors.New("broker unavailable")
+	}
+	b.published = append(b.published, event)
+	b.keys = append(b.keys, sharedKafka.PartitionKeyFromContext(ctx))
+	return nil
+}
+
+type memoryStore struct {
+	mu     sync.Mutex
+	nextID int64
+	events []*Event
+}
+
+func (s *memoryStore) Add(_ context.Context, event *Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	event.ID = s.nextID
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *memoryStore) ListPending(_ context.Context, limit int) ([]*Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.events) < limit {
+		limit = len(s.events)
+	}
+	return append([]*Event(nil), s.events[:limit]...), nil
+}
+
+func (s *memoryStore) Delete(_ context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, event := range s.events {
+		if event.ID == id {
+			s.events = append(s.events[:i], s.events[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (s *memoryStore) RecordFailure(_ context.Context, id int64, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, event := range s.events {
+		if event.ID == id {
+			event.Attempts++
+			event.LastError = reason
+		}
+	}
+	return nil
+}
+
+func TestPublisher_PublishEvent(t *testing.T) {
+	ctx := sharedKafka.WithPartitionKey(context.Background(), "user@example.com")
+
+	t.Run("retries a failed publish", func(t *testing.T) {
+		bus, store := &fakeBus{failures: DefaultPublishAttempts - 1}, &memoryStore{}
+
+		require.NoError(t, NewPublisher(bus, store).PublishEvent(ctx, &testParkedEvent{Email: "user@example.com"}))
+		assert.Len(t, bus.published, 1)
+		assert.Empty(t, store.events)
+	})
+
+	t.Run("parks the event once the retries are exhausted", func(t *testing.T) {
+		bus, store := &fakeBus{failures: DefaultPublishAttempts}, &memoryStore{}
+
+		require.NoError(t, NewPublisher(bus, store).PublishEvent(ctx, &testParkedEvent{Email: "user@example.com"}))
+		assert.Equal(t, DefaultPublishAttempts, bus.calls)
+		require.Len(t, store.events, 1)
+		assert.Equal(t, "testParkedEvent", store.events[0].Name)
+		assert.Equal(t, "user@example.com", store.events[0].PartitionKey)
+		assert.JSONEq(t, `{"email":"user@example.com"}`, string(store.events[0].Payload))
+		assert.Equal(t, "broker unavailable", store.events[0].LastError)
+	})
+
+	t.Run("parks the event right away while the brokers were never reached", func(t *testing.T) {
+		bus, store := &fakeBus{failures: DefaultPublishAttempts, err: sharedKafka.ErrUnavailable}, &memoryStore{}
+
+		require.NoError(t, NewPublisher(bus, store).PublishEvent(ctx, &testParkedEvent{Email: "user@example.com"}))
+		assert.Equal(t, 1, bus.calls)
+		require.Len(t, store.events, 1)
+		assert.Equal(t, sharedKafka.ErrUnavailable.Error(), store.events[0].LastError)
+	})
+
+	t.Run("fails for an unregistered event", func(t *testing.T) {
+		bus, store := &fakeBus{failures: DefaultPublishAttempts}, &memoryStore{}
+
+		assert.Error(t, NewPublisher(bus, store).PublishEvent(ctx, &testUnregisteredEvent{}))
+		assert.Empty(t, store.events)
+	})
+}
+
+func TestRelay_Run(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("publishes and deletes the parked events", func(t *testing.T) {
+		store := &memoryStore{}
+		park := NewPublisher(&fakeBus{failures: 100}, store)
+		require.NoError(t, park.PublishEvent(sharedKafka.WithPartitionKey(ctx, "a@example.com"), &testParkedEvent{Email: "a@example.com"}))
+		require.NoError(t, park.PublishEvent(ctx, &testParkedEvent{Email: "b@example.com"}))
+
+		bus := &fakeBus{}
+		require.NoError(t, NewRelay(bus, store).Run(ctx))
+
+		assert.Equal(t, []any{&testParkedEvent{Email: "a@example.com"}, &testParkedEvent{Email: "b@example.com"}}, bus.published)
+		assert.Equal(t, []string{"a@example.com", ""}, bus.keys)
+		assert.Empty(t, store.events)
+	})
+
+	t.Run("stops at the first failure and records it", func(t *testing.T) {
+		store := &memoryStore{}
+		park := NewPublisher(&fakeBus{failures: 100}, store)
+		require.NoError(t, park.PublishEvent(ctx, &testParkedEvent{Email: "a@example.com"}))
+		require.NoError(t, park.PublishEvent(ctx, &testParkedEvent{Email: "b@example.com"}))
+
+		bus := &fakeBus{failures: 1}
+		assert.Error(t, NewRelay(bus, store).Run(ctx))
+
+		assert.Equal(t, 1, bus.calls)
+		require.Len(t, store.events, 2)
+		assert.Equal(t, DefaultPublishAttempts+1, store.events[0].Attempts)
+	})
+}
@@ -0,0 +1,108 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"tixgo/shared/dbtimeout"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// outboxRow is the outbox_events row of an Event
+type outboxRow struct {
+	ID           int64     `db:"id"`
+	Name         string    `db:"name"`
+	PartitionKey string    `db:"partition_key"`
+	Payload      []byte    `db:"payload"`
+	Attempts     int       `db:"attempts"`
+	LastError    string    `db:"last_error"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+func (r *outboxRow) toEvent() *Event {
+	return &Event{
+		ID:           r.ID,
+		Name:         r.Name,
+		PartitionKey: r.PartitionKey,
+		Payload:      r.Payload,
+		Attempts:     r.Attempts,
+		LastError:    r.LastError,
+		CreatedAt:    r.CreatedAt,
+	}
+}
+
+// PostgresStore implements Store with the outbox_events table
+type PostgresStore struct {
+	db *sqlx.DB
+}
+
+// NewPostgresStore creates an outbox store backed by postgres
+func NewPostgresStore(db *sqlx.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) Add(ctx context.Context, event *Event) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		INSERT INTO outbox_events (name, partition_key, payload, attempts, last_error)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+
+	err := s.db.QueryRowContext(ctx, query, event.Name, event.PartitionKey, event.Payload, event.Attempts, event.LastError).
+		Scan(&event.ID, &event.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add outbox event %s: %w", event.Name, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresStore) ListPending(ctx context.Context, limit int) ([]*Event, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT id, name, partition_key, payload, attempts, last_error, created_at
+		FROM outbox_events
+		ORDER BY id
+		LIMIT $1`
+
+	var rows []outboxRow
+	if err := s.db.SelectContext(ctx, &rows, query, limit); err != nil {
+		return nil, fmt.Errorf("failed to list outbox events: %w", err)
+	}
+
+	events := make([]*Event, 0, len(rows))
+	for i := range rows {
+		events = append(events, rows[i].toEvent())
+	}
+
+	return events, nil
+}
+
+func (s *PostgresStore) Delete(ctx context.Context, id int64) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM outbox_events WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete outbox event %d: %w", id, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresStore) RecordFailure(ctx context.Context, id int64, reason string) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `UPDATE outbox_events SET attempts = attempts + 1, last_error = $2 WHERE id = $1`
+	if _, err := s.db.ExecContext(ctx, query, id, reason); err != nil {
+		return fmt.Errorf("failed to record failure of outbox event %d: %w", id, err)
+	}
+
+	return nil
+}
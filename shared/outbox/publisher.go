@@ -0,0 +1,92 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	sharedKafka "tixgo/shared/kafka"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+)
+
+const (
+	// DefaultPublishAttempts is how many times an event is published before it is parked
+	DefaultPublishAttempts = 3
+
+	publishBackoff = 50 * time.Millisecond
+)
+
+// Publisher is an event bus that does not lose events to a broker outage: a failed publish is
+// retried, and an event that still cannot be published is parked in the outbox for the relay job.
+// It only fails when the event could neither be published nor parked.
+type Publisher struct {
+	eventBus messaging.EventBus
+	store    Store
+}
+
+// NewPublisher wraps eventBus with retries and the outbox
+func NewPublisher(eventBus messaging.EventBus, store Store) *Publisher {
+	return &Publisher{
+		eventBus: eventBus,
+		store:    store,
+	}
+}
+
+// PublishEvent publishes event, parking it in the outbox when the bus keeps failing
+func (p *Publisher) PublishEvent(ctx context.Context, event any) error {
+	err := p.publish(ctx, event)
+	if err == nil {
+		return nil
+	}
+
+	name := cqrs.StructName(event)
+	if !isRegistered(name) {
+		return fmt.Errorf("failed to publish %s, which cannot be parked as it is not registered: %w", name, err)
+	}
+
+	payload, marshalErr := json.Marshal(event)
+	if marshalErr != nil {
+		return fmt.Errorf("failed to encode %s for the outbox: %w", name, marshalErr)
+	}
+
+	// the request may be cancelled already, parking must still happen
+	parkErr := p.store.Add(context.WithoutCancel(ctx), &Event{
+		Name:         name,
+		PartitionKey: sharedKafka.PartitionKeyFromContext(ctx),
+		Payload:      payload,
+		Attempts:     DefaultPublishAttempts,
+		LastError:    err.Error(),
+	})
+	if parkErr != nil {
+		return fmt.Errorf("failed to park %s in the outbox after publishing failed with %v: %w", name, err, parkErr)
+	}
+
+	logger.Warning(ctx, "Parked event in the outbox", logger.F("event_name", name), logger.F("error", err))
+	return nil
+}
+
+// publish tries to publish event up to DefaultPublishAttempts times. Retrying is pointless while the
+// brokers were never reached, the event is parked right away.
+func (p *Publisher) publish(ctx context.Context, event any) error {
+	backoff := publishBackoff
+	for attempt := 1; ; attempt++ {
+		err := p.eventBus.PublishEvent(ctx, event)
+		if err == nil || attempt == DefaultPublishAttempts || errors.Is(err, sharedKafka.ErrUnavailable) {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
@@ -0,0 +1,87 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sharedKafka "tixgo/shared/kafka"
+	"tixgo/shared/scheduler"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+)
+
+const (
+	// JobRelay is the name of the job publishing the parked events
+	JobRelay = "outbox.relay"
+
+	relayBatchSize = 100
+)
+
+// Relay publishes the events parked in the outbox, oldest first
+type Relay struct {
+	eventBus messaging.EventBus
+	store    Store
+}
+
+// NewRelay creates a relay publishing the events of store on eventBus, which must be the raw bus
+// rather than a Publisher so a failed relay does not park the event a second time
+func NewRelay(eventBus messaging.EventBus, store Store) *Relay {
+	return &Relay{
+		eventBus: eventBus,
+		store:    store,
+	}
+}
+
+// Run publishes a batch of parked events and deletes the published ones. It stops at the first
+// failed publish, the broker is likely still down and the remaining events keep their order.
+func (r *Relay) Run(ctx context.Context) error {
+	events, err := r.store.ListPending(ctx, relayBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		decoded, err := decode(event)
+		if err != nil {
+			// an event that cannot be decoded never will, keep it for inspection and move on
+			logger.Error(ctx, "Skipped undecodable outbox event", logger.F("outbox_id", event.ID), logger.F("error", err))
+			continue
+		}
+
+		publishCtx := ctx
+		if event.PartitionKey != "" {
+			publishCtx = sharedKafka.WithPartitionKey(ctx, event.PartitionKey)
+		}
+
+		if err := r.eventBus.PublishEvent(publishCtx, decoded); err != nil {
+			if recordErr := r.store.RecordFailure(ctx, event.ID, err.Error()); recordErr != nil {
+				logger.Error(ctx, "Failed to record outbox failure", logger.F("outbox_id", event.ID), logger.F("error", recordErr))
+			}
+			return fmt.Errorf("failed to relay outbox event %d (%s): %w", event.ID, event.Name, err)
+		}
+
+		if err := r.store.Delete(ctx, event.ID); err != nil {
+			// the event is published again on the next run, delivery is at least once like the bus itself
+			return err
+		}
+	}
+
+	if len(events) > 0 {
+		logger.Info(ctx, "Relayed outbox events", logger.F("count", len(events)))
+	}
+
+	return nil
+}
+
+// NewRelayJob returns the job relaying the parked events every minute
+func NewRelayJob(eventBus messaging.EventBus, store Store) scheduler.Job {
+	relay := NewRelay(eventBus, store)
+	return scheduler.Job{
+		Name:     JobRelay,
+		Schedule: "@every 1m",
+		Timeout:  50 * time.Second,
+		Run:      relay.Run,
+	}
+}
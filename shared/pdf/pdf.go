@@ -0,0 +1,230 @@
+// Package pdf writes simple PDF documents, receipts and other printouts: A4 pages of text in the
+// standard Helvetica fonts and lines. The standard fonts are not embedded, so documents stay small
+// enough to be mailed, but they only cover Windows-1252: other letters lose their accents, and
+// characters without a plain form are written as "?".
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/unicode/norm"
+)
+
+// ContentType is the media type of PDF documents
+const ContentType = "application/pdf"
+
+// A4 page size, in points
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Font is a standard font of the documents
+type Font string
+
+const (
+	Regular Font = "F1"
+	Bold    Font = "F2"
+)
+
+// baseFonts are the standard fonts the fonts of the documents name
+var baseFonts = map[Font]string{
+	Regular: "Helvetica",
+	Bold:    "Helvetica-Bold",
+}
+
+// Document is a PDF document being written, page by page
+type Document struct {
+	title string
+	pages []*Page
+}
+
+// New creates an empty document titled title
+func New(title string) *Document {
+	return &Document{title: title}
+}
+
+// AddPage adds a blank page at the end of the document
+func (d *Document) AddPage() *Page {
+	page := &Page{}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+// Page is a page of a document. Positions are in points from the top left corner of the page; the y
+// of a text is its baseline.
+type Page struct {
+	content bytes.Buffer
+}
+
+// Text writes text starting at x
+func (p *Page) Text(x, y float64, font Font, size float64, text string) {
+	fmt.Fprintf(&p.content, "BT /%s %s Tf %s %s Td (%s) Tj ET\n",
+		font, number(size), number(x), number(PageHeight-y), escape(encode(text)))
+}
+
+// TextRight writes text ending at x, for columns of amounts
+func (p *Page) TextRight(x, y float64, font Font, size float64, text string) {
+	p.Text(x-TextWidth(font, size, text), y, font, size, text)
+}
+
+// Line draws a line width points thick
+func (p *Page) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s m %s %s l S\n",
+		number(width), number(x1), number(PageHeight-y1), number(x2), number(PageHeight-y2))
+}
+
+// TextWidth is the width of text in font at size, in points
+func TextWidth(font Font, size float64, text string) float64 {
+	widths := helveticaWidths
+	if font == Bold {
+		widths = helveticaBoldWidths
+	}
+
+	total := 0
+	for _, b := range encode(text) {
+		if b >= 32 && int(b-32) < len(widths) {
+			total += widths[b-32]
+		} else {
+			total += defaultWidth
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// Wrap breaks text into lines no wider than width, between words. A word wider than width gets a line
+// of its own.
+func Wrap(font Font, size float64, text string, width float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if line != "" && TextWidth(font, size, candidate) > width {
+				lines = append(lines, line)
+				candidate = word
+			}
+			line = candidate
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Render returns the document, with one blank page if none was added
+func (d *Document) Render() ([]byte, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// 1 catalog, 2 page tree, 3 and 4 fonts, 5 info, then a page and its content for every page
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	for _, font := range []Font{Regular, Bold} {
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", baseFonts[font]))
+	}
+	object(fmt.Sprintf("<< /Title (%s) /Producer (tixgo) >>", escape(encode(d.title))))
+
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			number(PageWidth), number(PageHeight), 7+2*i))
+
+		var content bytes.Buffer
+		writer := zlib.NewWriter(&content)
+		if _, err := writer.Write(page.content.Bytes()); err != nil {
+			return nil, fmt.Errorf("failed to compress page %d: %w", i+1, err)
+		}
+		if err := writer.Close(); err != nil {
+			return nil, fmt.Errorf("failed to compress page %d: %w", i+1, err)
+		}
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", content.Len(), content.Bytes()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.Bytes(), nil
+}
+
+// number formats a coordinate or size with two decimals at most
+func number(n float64) string {
+	return strconv.FormatFloat(math.Round(n*100)/100, 'f', -1, 64)
+}
+
+// encode converts text to Windows-1252, which the fonts are encoded in. Letters it lacks lose their
+// accents, the other characters become "?".
+func encode(text string) []byte {
+	encoded := make([]byte, 0, len(text))
+	for _, r := range text {
+		if b, ok := charmap.Windows1252.EncodeRune(r); ok {
+			encoded = append(encoded, b)
+			continue
+		}
+		encoded = append(encoded, plain(r))
+	}
+	return encoded
+}
+
+// plain returns the letter r is an accented form of, like the "e" of "ễ"
+func plain(r rune) byte {
+	switch r {
+	case 'đ':
+		return 'd'
+	case 'Đ':
+		return 'D'
+	}
+
+	for _, base := range norm.NFD.String(string(r)) {
+		if unicode.Is(unicode.Mn, base) {
+			continue
+		}
+		if b, ok := charmap.Windows1252.EncodeRune(base); ok {
+			return b
+		}
+	}
+	return '?'
+}
+
+// escape writes encoded text as a PDF string literal, in ASCII
+func escape(encoded []byte) string {
+	var b strings.Builder
+	for _, c := range encoded {
+		switch {
+		case c == '\\' || c == '(' || c == ')':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 32 || c > 126:
+			fmt.Fprintf(&b, "\\%03o", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,72 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	doc := New("Receipt ORD-1")
+	page := doc.AddPage()
+	page.Text(40, 60, Bold, 18, "Receipt (paid)")
+	page.TextRight(555, 60, Regular, 10, "Hồ Chí Minh đ 100,000 €")
+	page.Line(40, 70, 555, 70, 0.5)
+
+	out, err := doc.Render()
+	require.NoError(t, err)
+
+	assert.True(t, bytes.HasPrefix(out, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(out, []byte("%%EOF\n")))
+	assert.Contains(t, string(out), "/Title (Receipt ORD-1)")
+	assert.Contains(t, string(out), "/BaseFont /Helvetica-Bold")
+
+	// Every object starts at the offset of the cross-reference table
+	xref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	require.NotNil(t, xref)
+	start, err := strconv.Atoi(string(xref[1]))
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(out[start:], []byte("xref\n0 8\n")))
+	offsets := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out[start:], -1)
+	require.Len(t, offsets, 7)
+	for i, offset := range offsets {
+		at, err := strconv.Atoi(string(offset[1]))
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(out[at:], []byte(strconv.Itoa(i+1)+" 0 obj\n")), "object %d", i+1)
+	}
+
+	stream := regexp.MustCompile(`(?s)stream\n(.*)\nendstream`).FindSubmatch(out)
+	require.NotNil(t, stream)
+	reader, err := zlib.NewReader(bytes.NewReader(stream[1]))
+	require.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(content), "BT /F2 18 Tf 40 781.89 Td (Receipt \\(paid\\)) Tj ET")
+	assert.Contains(t, string(content), "(Ho Ch\\355 Minh d 100,000 \\200) Tj")
+	assert.Contains(t, string(content), "0.5 w 40 771.89 m 555 771.89 l S")
+
+	again, err := doc.Render()
+	require.NoError(t, err)
+	assert.Equal(t, out, again)
+}
+
+func TestTextWidth(t *testing.T) {
+	assert.InDelta(t, 5.56, TextWidth(Regular, 10, "a"), 0.001)
+	assert.InDelta(t, 6.11, TextWidth(Bold, 10, "b"), 0.001)
+	assert.InDelta(t, TextWidth(Regular, 10, "e"), TextWidth(Regular, 10, "ễ"), 0.001)
+}
+
+func TestWrap(t *testing.T) {
+	width := TextWidth(Regular, 10, "Floor tickets")
+	assert.Equal(t, []string{"Floor tickets", "for the", "evening", "show"},
+		Wrap(Regular, 10, "Floor tickets for the\nevening show", width))
+	assert.Equal(t, []string{"Unbreakablefloortickets"}, Wrap(Regular, 10, "Unbreakablefloortickets", width))
+	assert.Equal(t, []string{""}, Wrap(Regular, 10, "", width))
+}
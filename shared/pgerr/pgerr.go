@@ -0,0 +1,107 @@
+package pgerr
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Postgres error codes the repositories handle, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	CodeUniqueViolation      pq.ErrorCode = "23505"
+	CodeForeignKeyViolation  pq.ErrorCode = "23503"
+	CodeSerializationFailure pq.ErrorCode = "40001"
+	CodeDeadlockDetected     pq.ErrorCode = "40P01"
+)
+
+// ConstraintTicketInventory is the CHECK constraint of ticket_categories keeping the sold, reserved
+// and allotted tickets of a category within its capacity. The repositories changing the stock with
+// conditional updates map its violation, raised by a concurrent writer, to their domain error.
+const ConstraintTicketInventory = "ticket_categories_inventory_check"
+
+// Class is what a repository can do about a Postgres error
+type Class int
+
+const (
+	// ClassOther errors are unexpected, repositories wrap them as internal errors
+	ClassOther Class = iota
+	// ClassUniqueViolation errors mean the row already exists, a conflict for the caller
+	ClassUniqueViolation
+	// ClassForeignKeyViolation errors mean a referenced row does not exist (anymore)
+	ClassForeignKeyViolation
+	// ClassRetryable errors are transient: the transaction lost a race and may be run again as is
+	ClassRetryable
+)
+
+// Classify tells what kind of Postgres error err wraps, ClassOther if it wraps none
+func Classify(err error) Class {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return ClassOther
+	}
+
+	switch pqErr.Code {
+	case CodeUniqueViolation:
+		return ClassUniqueViolation
+	case CodeForeignKeyViolation:
+		return ClassForeignKeyViolation
+	case CodeSerializationFailure, CodeDeadlockDetected:
+		return ClassRetryable
+	default:
+		return ClassOther
+	}
+}
+
+// IsUniqueViolation checks if err is a unique constraint violation
+func IsUniqueViolation(err error) bool {
+	return Classify(err) == ClassUniqueViolation
+}
+
+// IsForeignKeyViolation checks if err is a foreign key violation
+func IsForeignKeyViolation(err error) bool {
+	return Classify(err) == ClassForeignKeyViolation
+}
+
+// IsRetryable checks if err is a serialization failure or a deadlock
+func IsRetryable(err error) bool {
+	return Classify(err) == ClassRetryable
+}
+
+// Constraint returns the name of the constraint err violates, empty if it is not a Postgres error
+func Constraint(err error) string {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return ""
+	}
+	return pqErr.Constraint
+}
+
+const (
+	// DefaultRetryAttempts is how many times Retry runs fn at most
+	DefaultRetryAttempts = 3
+	// retryBackoff is the wait before the second attempt, doubled for each further one
+	retryBackoff = 20 * time.Millisecond
+)
+
+// Retry runs fn, typically a whole transaction, again while it fails with a retryable error, up to
+// DefaultRetryAttempts times. It returns the last error of fn, or the error of ctx if it is done while waiting.
+func Retry(ctx context.Context, fn func(ctx context.Context) error) error {
+	backoff := retryBackoff
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil || attempt == DefaultRetryAttempts || !IsRetryable(err) {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
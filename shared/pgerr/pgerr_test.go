@@ -0,0 +1,88 @@
+package pgerr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Class
+	}{
+		{"unique violation", &pq.Error{Code: CodeUniqueViolation}, ClassUniqueViolation},
+		{"foreign key violation", &pq.Error{Code: CodeForeignKeyViolation}, ClassForeignKeyViolation},
+		{"serialization failure", &pq.Error{Code: CodeSerializationFailure}, ClassRetryable},
+		{"deadlock", &pq.Error{Code: CodeDeadlockDetected}, ClassRetryable},
+		{"other postgres error", &pq.Error{Code: "42P01"}, ClassOther},
+		{"not a postgres error", errors.New("duplicate key"), ClassOther},
+		{"nil", nil, ClassOther},
+		{"wrapped", syserr.Wrap(fmt.Errorf("insert: %w", &pq.Error{Code: CodeUniqueViolation}), syserr.InternalCode, "failed"), ClassUniqueViolation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Classify(tt.err))
+		})
+	}
+}
+
+func TestConstraint(t *testing.T) {
+	assert.Equal(t, "users_email_key", Constraint(fmt.Errorf("insert: %w", &pq.Error{Code: CodeUniqueViolation, Constraint: "users_email_key"})))
+	assert.Empty(t, Constraint(errors.New("boom")))
+}
+
+func TestRetry(t *testing.T) {
+	t.Run("retries retryable errors", func(t *testing.T) {
+		calls := 0
+		err := Retry(context.Background(), func(context.Context) error {
+			calls++
+			if calls < 3 {
+				return &pq.Error{Code: CodeSerializationFailure}
+			}
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		calls := 0
+		err := Retry(context.Background(), func(context.Context) error {
+			calls++
+			return &pq.Error{Code: CodeDeadlockDetected}
+		})
+
+		assert.True(t, IsRetryable(err))
+		assert.Equal(t, DefaultRetryAttempts, calls)
+	})
+
+	t.Run("returns other errors at once", func(t *testing.T) {
+		calls := 0
+		err := Retry(context.Background(), func(context.Context) error {
+			calls++
+			return &pq.Error{Code: CodeUniqueViolation}
+		})
+
+		assert.True(t, IsUniqueViolation(err))
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		err := Retry(ctx, func(context.Context) error {
+			cancel()
+			return &pq.Error{Code: CodeSerializationFailure}
+		})
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
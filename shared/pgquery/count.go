@@ -0,0 +1,63 @@
+package pgquery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"tixgo/shared/listing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Count sets the total of paging the way it asks for. from is the FROM part of the listing without
+// its conditions, e.g. "templates" or "template_revisions r".
+func Count(ctx context.Context, db sqlx.QueryerContext, from string, filter *Filter, paging *listing.Paging) error {
+	switch paging.Count {
+	case listing.CountNone:
+		return nil
+	case listing.CountEstimate:
+		total, err := estimate(ctx, db, fmt.Sprintf("SELECT 1 FROM %s %s", from, filter.Clause()), filter.Args())
+		if err != nil {
+			return err
+		}
+		paging.Total = total
+		paging.Estimated = true
+		return nil
+	default:
+		var total int64
+		err := sqlx.GetContext(ctx, db, &total, fmt.Sprintf("SELECT COUNT(*) FROM %s %s", from, filter.Clause()), filter.Args()...)
+		if err != nil {
+			return err
+		}
+		paging.Total = total
+		return nil
+	}
+}
+
+// estimate returns the number of rows the planner expects query to return, without running it
+func estimate(ctx context.Context, db sqlx.QueryerContext, query string, args []interface{}) (int64, error) {
+	var plan []byte
+	if err := sqlx.GetContext(ctx, db, &plan, "EXPLAIN (FORMAT JSON) "+query, args...); err != nil {
+		return 0, err
+	}
+
+	return planRows(plan)
+}
+
+// planRows reads the row estimate of the top node of a JSON query plan
+func planRows(plan []byte) (int64, error) {
+	var explained []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal(plan, &explained); err != nil {
+		return 0, fmt.Errorf("failed to decode query plan: %w", err)
+	}
+	if len(explained) == 0 {
+		return 0, fmt.Errorf("empty query plan")
+	}
+
+	return int64(explained[0].Plan.Rows), nil
+}
@@ -0,0 +1,34 @@
+package pgquery
+
+import (
+	"context"
+	"testing"
+
+	"tixgo/shared/listing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanRows(t *testing.T) {
+	plan := []byte(`[{"Plan": {"Node Type": "Seq Scan", "Relation Name": "templates", "Plan Rows": 1520.0, "Plan Width": 4}}]`)
+
+	rows, err := planRows(plan)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1520), rows)
+
+	_, err = planRows([]byte(`[]`))
+	assert.Error(t, err)
+
+	_, err = planRows([]byte(`not json`))
+	assert.Error(t, err)
+}
+
+func TestCount_None(t *testing.T) {
+	paging := &listing.Paging{Count: listing.CountNone}
+
+	// no query is run, so no database is needed
+	require.NoError(t, Count(context.Background(), nil, "templates", &Filter{}, paging))
+	assert.Zero(t, paging.Total)
+	assert.False(t, paging.Estimated)
+}
@@ -0,0 +1,60 @@
+package pgquery
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"tixgo/shared/listing"
+)
+
+// Filter builds the WHERE clause of a dynamic query, numbering the Postgres placeholders of its
+// conditions so repositories never count arguments by hand. The zero value is an empty filter.
+type Filter struct {
+	conditions []string
+	args       []interface{}
+}
+
+// Where adds a condition ANDed with the others. Each ? of the condition is a placeholder for the
+// next of args, so conditions cannot use the ? operators of jsonb (use jsonb_exists instead).
+// It panics when the placeholders and args do not match, a bug of the query rather than of the input.
+func (f *Filter) Where(condition string, args ...interface{}) *Filter {
+	if n := strings.Count(condition, "?"); n != len(args) {
+		panic(fmt.Sprintf("pgquery: condition %q has %d placeholders for %d args", condition, n, len(args)))
+	}
+
+	var b strings.Builder
+	for _, r := range condition {
+		if r != '?' {
+			b.WriteRune(r)
+			continue
+		}
+		f.args = append(f.args, args[0])
+		args = args[1:]
+		b.WriteString("$" + strconv.Itoa(len(f.args)))
+	}
+
+	f.conditions = append(f.conditions, b.String())
+	return f
+}
+
+// Clause returns the WHERE clause of the conditions, empty when there are none
+func (f *Filter) Clause() string {
+	if len(f.conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(f.conditions, " AND ")
+}
+
+// Args returns the arguments of Clause
+func (f *Filter) Args() []interface{} {
+	return append([]interface{}(nil), f.args...)
+}
+
+// Paged returns the LIMIT and OFFSET clause of paging, numbered after the conditions,
+// along with the arguments of the whole query. The limit is the FetchLimit of paging.
+func (f *Filter) Paged(paging *listing.Paging) (string, []interface{}) {
+	n := len(f.args)
+	clause := fmt.Sprintf("LIMIT $%d OFFSET $%d", n+1, n+2)
+	return clause, append(f.Args(), paging.FetchLimit(), paging.GetOffset())
+}
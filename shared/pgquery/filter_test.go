@@ -0,0 +1,53 @@
+package pgquery
+
+import (
+	"testing"
+
+	"tixgo/shared/listing"
+
+	"github.com/duongptryu/gox/pagination"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter_Empty(t *testing.T) {
+	var filter Filter
+
+	assert.Equal(t, "", filter.Clause())
+	assert.Empty(t, filter.Args())
+
+	clause, args := filter.Paged(&listing.Paging{Paging: pagination.Paging{Page: 3, Limit: 20}})
+	assert.Equal(t, "LIMIT $1 OFFSET $2", clause)
+	assert.Equal(t, []interface{}{21, 40}, args)
+}
+
+func TestFilter_Where(t *testing.T) {
+	var filter Filter
+	filter.
+		Where("type = ?", "email").
+		Where("(name ILIKE ? OR slug ILIKE ?)", "%wel%", "%wel%").
+		Where("created_by = ?", int64(7))
+
+	assert.Equal(t, "WHERE type = $1 AND (name ILIKE $2 OR slug ILIKE $3) AND created_by = $4", filter.Clause())
+	assert.Equal(t, []interface{}{"email", "%wel%", "%wel%", int64(7)}, filter.Args())
+
+	clause, args := filter.Paged(&listing.Paging{Paging: pagination.Paging{Page: 1, Limit: 10}})
+	assert.Equal(t, "LIMIT $5 OFFSET $6", clause)
+	assert.Equal(t, []interface{}{"email", "%wel%", "%wel%", int64(7), 11, 0}, args)
+}
+
+func TestFilter_PagedKeepsFilterArgs(t *testing.T) {
+	var filter Filter
+	filter.Where("status = ?", "active")
+
+	_, _ = filter.Paged(&listing.Paging{Paging: pagination.Paging{Page: 1, Limit: 10}})
+
+	assert.Equal(t, []interface{}{"active"}, filter.Args())
+	assert.Equal(t, "WHERE status = $1", filter.Clause())
+}
+
+func TestFilter_WhereMismatchedArgs(t *testing.T) {
+	var filter Filter
+
+	assert.Panics(t, func() { filter.Where("type = ? AND status = ?", "email") })
+	assert.Panics(t, func() { filter.Where("type = 'email'", "email") })
+}
@@ -0,0 +1,53 @@
+// Package phone validates phone numbers with the libphonenumber metadata and normalizes them to E.164
+package phone
+
+import (
+	"strings"
+
+	"tixgo/shared/errstack"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/ttacon/libphonenumber"
+)
+
+const (
+	InvalidPhoneCode       syserr.Code = "invalid_phone"
+	UndeliverablePhoneCode syserr.Code = "undeliverable_phone"
+)
+
+var (
+	// ErrInvalid is a number that is not assigned in its region, or not a phone number at all
+	ErrInvalid = errstack.Sentinel(InvalidPhoneCode, "phone number is not valid, please enter it with its country code")
+	// ErrUndeliverable is a valid number that cannot receive text messages, like a landline
+	ErrUndeliverable = errstack.Sentinel(UndeliverablePhoneCode, "phone number cannot receive text messages, please enter a mobile number")
+)
+
+// Number is a phone number text messages can be delivered to
+type Number struct {
+	// E164 is the number in E.164 format, like +84901234567
+	E164 string
+	// Country is the ISO 3166-1 alpha-2 code of the region of the number, like VN
+	Country string
+}
+
+// Parse validates a number written in international format, or in the national format of
+// defaultRegion when it is set, e.g. "0901 234 567" in VN. Only numbers that may receive text
+// messages are accepted: mobile numbers, VoIP and personal numbers, and the ones of the regions
+// where mobile and fixed lines cannot be told apart.
+func Parse(raw, defaultRegion string) (Number, error) {
+	number, err := libphonenumber.Parse(strings.TrimSpace(raw), strings.ToUpper(defaultRegion))
+	if err != nil || !libphonenumber.IsValidNumber(number) {
+		return Number{}, ErrInvalid
+	}
+
+	switch libphonenumber.GetNumberType(number) {
+	case libphonenumber.MOBILE, libphonenumber.FIXED_LINE_OR_MOBILE, libphonenumber.VOIP, libphonenumber.PERSONAL_NUMBER:
+	default:
+		return Number{}, ErrUndeliverable
+	}
+
+	return Number{
+		E164:    libphonenumber.Format(number, libphonenumber.E164),
+		Country: libphonenumber.GetRegionCodeForNumber(number),
+	}, nil
+}
@@ -0,0 +1,40 @@
+package phone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name          string
+		raw           string
+		defaultRegion string
+		want          Number
+		wantErr       error
+	}{
+		{name: "e164 mobile", raw: "+84901234567", want: Number{E164: "+84901234567", Country: "VN"}},
+		{name: "formatted international", raw: " +44 7400 123456 ", want: Number{E164: "+447400123456", Country: "GB"}},
+		{name: "national with region", raw: "090 123 4567", defaultRegion: "vn", want: Number{E164: "+84901234567", Country: "VN"}},
+		{name: "fixed line or mobile", raw: "+1 650 253 0000", want: Number{E164: "+16502530000", Country: "US"}},
+		{name: "national without region", raw: "0901234567", wantErr: ErrInvalid},
+		{name: "too short", raw: "+1", wantErr: ErrInvalid},
+		{name: "not a number", raw: "call me", wantErr: ErrInvalid},
+		{name: "landline", raw: "+44 20 7946 0000", wantErr: ErrUndeliverable},
+		{name: "toll free", raw: "+1 800 253 0000", wantErr: ErrUndeliverable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.raw, tt.defaultRegion)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
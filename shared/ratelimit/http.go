@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"tixgo/shared/httpresponse"
+
+	pkgContext "github.com/duongptryu/gox/context"
+	"github.com/duongptryu/gox/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Code is the error code of the requests refused over the quota of their caller
+const Code = "rate_limited"
+
+var refusedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "tixgo_rate_limited_requests_total",
+	Help: "Requests refused over the quota of their caller, by quota.",
+}, []string{"quota"})
+
+// PerCaller refuses the requests of a caller over limit with 429, Code and a Retry-After. Callers are
+// told apart by user ID once authenticated and by IP otherwise; quota names the bucket, routes sharing
+// it share their quota. The requests are let through when redis fails, the limit is not worth an outage.
+func PerCaller(limiter *RedisLimiter, quota string, limit Limit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		caller := "ip:" + c.ClientIP()
+		if userID := pkgContext.GetUserIDFromContext(ctx); userID != "" {
+			caller = "user:" + userID
+		}
+
+		wait, err := limiter.Allow(ctx, "http:"+quota+":"+caller, limit)
+		if err != nil {
+			logger.Warning(ctx, "Rate limit unavailable, letting the request through", logger.F("quota", quota), logger.F("error", err))
+			c.Next()
+			return
+		}
+		if wait > 0 {
+			refusedRequests.WithLabelValues(quota).Inc()
+			retryAfter := strconv.Itoa(int(math.Ceil(wait.Seconds())))
+			c.Header("Retry-After", retryAfter)
+			httpresponse.Error(c, http.StatusTooManyRequests, Code, "too many requests, retry in "+retryAfter+"s", nil)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
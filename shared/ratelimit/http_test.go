@@ -0,0 +1,47 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	pkgContext "github.com/duongptryu/gox/context"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPerCaller(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter, _ := newTestRedisLimiter(t)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if userID := c.GetHeader("X-Test-User"); userID != "" {
+			c.Request = c.Request.WithContext(pkgContext.WithUserID(c.Request.Context(), userID))
+		}
+	})
+	router.POST("/render", PerCaller(limiter, "render", Limit{Rate: 1, Per: 10 * time.Second}), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	send := func(userID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/render", nil)
+		req.Header.Set("X-Test-User", userID)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusNoContent, send("7").Code)
+
+	refused := send("7")
+	assert.Equal(t, http.StatusTooManyRequests, refused.Code)
+	assert.Equal(t, "10", refused.Header().Get("Retry-After"))
+	assert.Contains(t, refused.Body.String(), `"code":"rate_limited"`)
+
+	// other callers, signed in or not, have their own quota
+	assert.Equal(t, http.StatusNoContent, send("8").Code)
+	assert.Equal(t, http.StatusNoContent, send("").Code)
+	assert.Equal(t, http.StatusTooManyRequests, send("").Code)
+}
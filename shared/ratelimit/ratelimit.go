@@ -0,0 +1,164 @@
+// Package ratelimit keeps the sends of every instance under the quota of a provider with a token
+// bucket kept in redis. Sends over the quota are not refused: each reserves the next free slot and
+// waits for it, so a burst is spread out instead of failing. Requests of API callers, which should
+// not wait, are refused over their quota instead, see PerCaller.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+)
+
+const redisKeyPrefix = "ratelimit:"
+
+var (
+	queueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "tixgo_send_queue_depth",
+		Help: "Sends of this instance waiting for a slot in the quota of their provider.",
+	}, []string{"provider"})
+	sendDelay = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "tixgo_send_delay_seconds",
+		Help:    "How long sends waited for a slot in the quota of their provider.",
+		Buckets: []float64{0, .01, .05, .1, .5, 1, 5, 10, 30, 60},
+	}, []string{"provider"})
+)
+
+// Limit is a quota of Rate sends every Per, Burst of which may go out at once
+type Limit struct {
+	Rate  int
+	Per   time.Duration
+	Burst int
+}
+
+// interval is the time a token takes to come back
+func (l Limit) interval() time.Duration {
+	return l.Per / time.Duration(l.Rate)
+}
+
+// burst is the size of the bucket, one token at least
+func (l Limit) burst() int {
+	return max(l.Burst, 1)
+}
+
+// Limiter reserves slots in a quota
+type Limiter interface {
+	// Reserve takes the next free slot of the quota of key and returns how long to wait for it
+	Reserve(ctx context.Context, key string, limit Limit) (time.Duration, error)
+}
+
+// reserveScript is the generic cell rate algorithm: the key holds the time the bucket is full again,
+// in microseconds of the redis clock. Every reservation pushes it one interval further and waits for
+// what exceeds the burst. Using the redis clock keeps instances with skewed clocks on one schedule.
+var reserveScript = redis.NewScript(`
+local now = redis.call("TIME")
+now = tonumber(now[1]) * 1000000 + tonumber(now[2])
+local interval = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+
+local full = tonumber(redis.call("GET", KEYS[1]) or now)
+full = math.max(full, now) + interval
+
+redis.call("SET", KEYS[1], full, "PX", math.ceil((full - now) / 1000))
+return math.max(full - now - burst * interval, 0)`)
+
+// allowScript is reserveScript taking the slot only when it is free at once, so refused requests do
+// not push the bucket further. It returns the wait before a slot frees up, 0 once it is taken.
+var allowScript = redis.NewScript(`
+local now = redis.call("TIME")
+now = tonumber(now[1]) * 1000000 + tonumber(now[2])
+local interval = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+
+local full = tonumber(redis.call("GET", KEYS[1]) or now)
+full = math.max(full, now) + interval
+
+local wait = full - now - burst * interval
+if wait > 0 then
+	return wait
+end
+redis.call("SET", KEYS[1], full, "PX", math.ceil((full - now) / 1000))
+return 0`)
+
+// RedisLimiter implements Limiter with a bucket per key shared by every instance
+type RedisLimiter struct {
+	client redis.UniversalClient
+}
+
+// NewRedisLimiter creates a limiter backed by redis
+func NewRedisLimiter(client redis.UniversalClient) *RedisLimiter {
+	return &RedisLimiter{client: client}
+}
+
+func (l *RedisLimiter) Reserve(ctx context.Context, key string, limit Limit) (time.Duration, error) {
+	if limit.Rate <= 0 || limit.Per <= 0 {
+		return 0, fmt.Errorf("invalid rate limit %d per %s of %s", limit.Rate, limit.Per, key)
+	}
+
+	wait, err := reserveScript.Run(ctx, l.client, []string{redisKeyPrefix + key},
+		limit.interval().Microseconds(), limit.burst()).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("failed to reserve a slot of %s: %w", key, err)
+	}
+	return time.Duration(wait) * time.Microsecond, nil
+}
+
+// Allow takes a slot of the quota of key if one is free, otherwise it returns how long until one is
+func (l *RedisLimiter) Allow(ctx context.Context, key string, limit Limit) (time.Duration, error) {
+	if limit.Rate <= 0 || limit.Per <= 0 {
+		return 0, fmt.Errorf("invalid rate limit %d per %s of %s", limit.Rate, limit.Per, key)
+	}
+
+	wait, err := allowScript.Run(ctx, l.client, []string{redisKeyPrefix + key},
+		limit.interval().Microseconds(), limit.burst()).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("failed to take a slot of %s: %w", key, err)
+	}
+	return time.Duration(wait) * time.Microsecond, nil
+}
+
+// Throttle holds the sends to a provider to its quota
+type Throttle struct {
+	limiter  Limiter
+	provider string
+	limit    Limit
+}
+
+// NewThrottle creates the throttle of the sends to provider
+func NewThrottle(limiter Limiter, provider string, limit Limit) *Throttle {
+	return &Throttle{limiter: limiter, provider: provider, limit: limit}
+}
+
+// Wait blocks until the send has a slot in the quota. The slot is taken even when ctx ends first: it
+// is not given back, erring on the side of the quota. A nil throttle lets every send through.
+func (t *Throttle) Wait(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	wait, err := t.limiter.Reserve(ctx, "send:"+t.provider, t.limit)
+	if err != nil {
+		return err
+	}
+	sendDelay.WithLabelValues(t.provider).Observe(wait.Seconds())
+	if wait <= 0 {
+		return nil
+	}
+
+	depth := queueDepth.WithLabelValues(t.provider)
+	depth.Inc()
+	defer depth.Dec()
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
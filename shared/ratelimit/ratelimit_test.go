@@ -0,0 +1,126 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedisLimiter(t *testing.T) (*RedisLimiter, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	server.SetTime(time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC))
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewRedisLimiter(client), server
+}
+
+func TestRedisLimiter_Reserve(t *testing.T) {
+	ctx := context.Background()
+	limit := Limit{Rate: 10, Per: time.Second, Burst: 2}
+
+	t.Run("sends over the burst wait for their slot", func(t *testing.T) {
+		limiter, _ := newTestRedisLimiter(t)
+
+		var waits []time.Duration
+		for i := 0; i < 4; i++ {
+			wait, err := limiter.Reserve(ctx, "send:sendgrid", limit)
+			require.NoError(t, err)
+			waits = append(waits, wait)
+		}
+
+		assert.Equal(t, []time.Duration{0, 0, 100 * time.Millisecond, 200 * time.Millisecond}, waits)
+	})
+
+	t.Run("the bucket fills up again", func(t *testing.T) {
+		limiter, server := newTestRedisLimiter(t)
+
+		for i := 0; i < 3; i++ {
+			_, err := limiter.Reserve(ctx, "send:sendgrid", limit)
+			require.NoError(t, err)
+		}
+
+		server.SetTime(time.Date(2026, 10, 1, 12, 0, 0, int(250*time.Millisecond), time.UTC))
+		wait, err := limiter.Reserve(ctx, "send:sendgrid", limit)
+		require.NoError(t, err)
+		assert.Zero(t, wait)
+	})
+
+	t.Run("keys have their own bucket", func(t *testing.T) {
+		limiter, server := newTestRedisLimiter(t)
+
+		for i := 0; i < 2; i++ {
+			_, err := limiter.Reserve(ctx, "send:sendgrid", limit)
+			require.NoError(t, err)
+		}
+		wait, err := limiter.Reserve(ctx, "send:twilio", limit)
+		require.NoError(t, err)
+		assert.Zero(t, wait)
+		assert.True(t, server.Exists("ratelimit:send:twilio"))
+	})
+
+	t.Run("rejects an empty limit", func(t *testing.T) {
+		limiter, _ := newTestRedisLimiter(t)
+
+		_, err := limiter.Reserve(ctx, "send:sendgrid", Limit{})
+		assert.Error(t, err)
+	})
+}
+
+func TestRedisLimiter_Allow(t *testing.T) {
+	ctx := context.Background()
+	limit := Limit{Rate: 10, Per: time.Second, Burst: 2}
+	limiter, server := newTestRedisLimiter(t)
+
+	var waits []time.Duration
+	for i := 0; i < 4; i++ {
+		wait, err := limiter.Allow(ctx, "http:render:user:7", limit)
+		require.NoError(t, err)
+		waits = append(waits, wait)
+	}
+
+	// refused requests take no slot, the next one frees up after a single interval
+	assert.Equal(t, []time.Duration{0, 0, 100 * time.Millisecond, 100 * time.Millisecond}, waits)
+
+	server.SetTime(time.Date(2026, 10, 1, 12, 0, 0, int(100*time.Millisecond), time.UTC))
+	wait, err := limiter.Allow(ctx, "http:render:user:7", limit)
+	require.NoError(t, err)
+	assert.Zero(t, wait)
+}
+
+type fixedLimiter time.Duration
+
+func (l fixedLimiter) Reserve(context.Context, string, Limit) (time.Duration, error) {
+	return time.Duration(l), nil
+}
+
+func TestThrottle_Wait(t *testing.T) {
+	t.Run("waits for the slot", func(t *testing.T) {
+		throttle := NewThrottle(fixedLimiter(30*time.Millisecond), "throttle-wait", Limit{Rate: 1, Per: time.Second})
+
+		done := make(chan error)
+		go func() { done <- throttle.Wait(context.Background()) }()
+
+		assert.Eventually(t, func() bool {
+			return testutil.ToFloat64(queueDepth.WithLabelValues("throttle-wait")) == 1
+		}, time.Second, time.Millisecond)
+		require.NoError(t, <-done)
+		assert.Zero(t, testutil.ToFloat64(queueDepth.WithLabelValues("throttle-wait")))
+	})
+
+	t.Run("gives up when the context ends", func(t *testing.T) {
+		throttle := NewThrottle(fixedLimiter(time.Hour), "throttle-cancel", Limit{Rate: 1, Per: time.Second})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, throttle.Wait(ctx), context.DeadlineExceeded)
+		assert.Zero(t, testutil.ToFloat64(queueDepth.WithLabelValues("throttle-cancel")))
+	})
+}
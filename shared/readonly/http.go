@@ -0,0 +1,96 @@
+package readonly
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tixgo/shared/httpresponse"
+
+	pkgContext "github.com/duongptryu/gox/context"
+	"github.com/gin-gonic/gin"
+)
+
+// Code is the error code of the writes refused in read-only mode
+const Code = "read_only"
+
+// RetryAfter is the Retry-After of the refused writes, in seconds: failovers take a minute or so
+const RetryAfter = 30
+
+// Request turns the read-only mode on or off
+type Request struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+	// Reason is shown to the clients whose writes are refused
+	Reason string `json:"reason" binding:"max=200"`
+}
+
+// Middleware refuses the writes to the routes of the group at basePath while s is enabled, answering
+// 503 with Code and a Retry-After. Reads, GET, HEAD and OPTIONS, are served. Exempt holds the routes,
+// relative to the group, that keep accepting writes, like the one turning the mode off.
+func Middleware(s *Switch, basePath string, exempt ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		state := s.State()
+		if !state.Enabled {
+			c.Next()
+			return
+		}
+
+		route := strings.TrimPrefix(c.FullPath(), strings.TrimSuffix(basePath, "/"))
+		for _, path := range exempt {
+			if route == path {
+				c.Next()
+				return
+			}
+		}
+
+		message := "the service is read-only for maintenance, try again later"
+		if state.Reason != "" {
+			message += ": " + state.Reason
+		}
+		c.Header("Retry-After", strconv.Itoa(RetryAfter))
+		httpresponse.Error(c, http.StatusServiceUnavailable, Code, message, nil)
+		c.Abort()
+	}
+}
+
+// GetHandler answers the read-only state
+func GetHandler(s *Switch) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "no-store")
+		httpresponse.Success(c, http.StatusOK, s.State())
+	}
+}
+
+// SetHandler turns the read-only mode on or off for every instance, on behalf of the user of the request
+func SetHandler(s *Switch) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req Request
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err)
+			return
+		}
+
+		var (
+			state State
+			err   error
+		)
+		if *req.Enabled {
+			by, _ := pkgContext.GetUserIDFromContextAsInt64(c.Request.Context())
+			state, err = s.Enable(c.Request.Context(), req.Reason, by)
+		} else {
+			state, err = s.Disable(c.Request.Context())
+		}
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusOK, state)
+	}
+}
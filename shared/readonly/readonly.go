@@ -0,0 +1,178 @@
+// Package readonly turns the platform read-only while the database cannot take writes, during a
+// failover of the primary or a migration locking tables: reads are served, writes are refused with 503.
+package readonly
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"tixgo/shared/errstack"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/syserr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+)
+
+// redisKey holds the state set by admins, shared by every instance
+const redisKey = "readonly:state"
+
+// DefaultRefreshInterval is how often instances pick up the state set on another instance
+const DefaultRefreshInterval = 2 * time.Second
+
+// ErrForced is returned when lifting a read-only mode set by the configuration
+var ErrForced = errstack.Sentinel(syserr.ConflictCode, "read-only mode is set by the configuration")
+
+var readOnlyGauge = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "tixgo_read_only",
+	Help: "1 while the instance refuses writes, 0 otherwise.",
+})
+
+// Source tells who turned the read-only mode on
+type Source string
+
+const (
+	SourceConfig Source = "config"
+	SourceAdmin  Source = "admin"
+)
+
+// State is whether writes are refused, and why
+type State struct {
+	Enabled bool       `json:"enabled"`
+	Source  Source     `json:"source,omitempty"`
+	Reason  string     `json:"reason,omitempty"`
+	Since   *time.Time `json:"since,omitempty"`
+	// By is the admin who turned it on
+	By int64 `json:"by,omitempty"`
+}
+
+// Switch holds the read-only state of the platform. Admins set it in redis so it applies to every
+// instance; the configuration can force it on, for when redis is unavailable too.
+type Switch struct {
+	client redis.UniversalClient
+	forced bool
+
+	mu    sync.RWMutex
+	state State
+}
+
+// NewSwitch creates a switch on the state kept in redis, forced on when forced is set
+func NewSwitch(client redis.UniversalClient, forced bool) *Switch {
+	s := &Switch{client: client, forced: forced}
+	if forced {
+		s.set(State{Enabled: true, Source: SourceConfig, Reason: "server.read_only is set"})
+	}
+	return s
+}
+
+// State returns the state as of the last refresh
+func (s *Switch) State() State {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state
+}
+
+// Enabled reports whether writes are refused
+func (s *Switch) Enabled() bool {
+	return s.State().Enabled
+}
+
+// Enable refuses writes on every instance, within a refresh interval for the other ones
+func (s *Switch) Enable(ctx context.Context, reason string, by int64) (State, error) {
+	now := time.Now().UTC()
+	state := State{Enabled: true, Source: SourceAdmin, Reason: reason, Since: &now, By: by}
+
+	value, err := json.Marshal(state)
+	if err != nil {
+		return State{}, err
+	}
+	if err := s.client.Set(ctx, redisKey, value, 0).Err(); err != nil {
+		return State{}, fmt.Errorf("failed to enable read-only mode: %w", err)
+	}
+
+	if !s.forced {
+		s.set(state)
+	}
+	return s.State(), nil
+}
+
+// Disable accepts writes again, unless the configuration forces the read-only mode
+func (s *Switch) Disable(ctx context.Context) (State, error) {
+	if s.forced {
+		return s.State(), ErrForced
+	}
+
+	if err := s.client.Del(ctx, redisKey).Err(); err != nil {
+		return State{}, fmt.Errorf("failed to disable read-only mode: %w", err)
+	}
+
+	s.set(State{})
+	return s.State(), nil
+}
+
+// Refresh reads the state set by admins. On failure the switch keeps its last state.
+func (s *Switch) Refresh(ctx context.Context) error {
+	if s.forced {
+		return nil
+	}
+
+	value, err := s.client.Get(ctx, redisKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		s.set(State{})
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read read-only state: %w", err)
+	}
+
+	var state State
+	if err := json.Unmarshal(value, &state); err != nil {
+		return fmt.Errorf("invalid read-only state: %w", err)
+	}
+	s.set(state)
+	return nil
+}
+
+// Run refreshes the state every interval until ctx is done
+func (s *Switch) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultRefreshInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Refresh(ctx); err != nil {
+			logger.Warning(ctx, "Failed to refresh read-only state", logger.F("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Switch) set(state State) {
+	s.mu.Lock()
+	previous := s.state
+	s.state = state
+	s.mu.Unlock()
+
+	if state.Enabled {
+		readOnlyGauge.Set(1)
+	} else {
+		readOnlyGauge.Set(0)
+	}
+
+	if previous.Enabled != state.Enabled {
+		logger.Warning(context.Background(), "Read-only mode changed",
+			logger.F("enabled", state.Enabled), logger.F("source", state.Source), logger.F("reason", state.Reason))
+	}
+}
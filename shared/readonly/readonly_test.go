@@ -0,0 +1,114 @@
+package readonly
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/duongptryu/gox/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init(&logger.Config{Output: io.Discard})
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+func newTestClient(t *testing.T) redis.UniversalClient {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestSwitch(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("shares the state between instances", func(t *testing.T) {
+		client := newTestClient(t)
+		admin, other := NewSwitch(client, false), NewSwitch(client, false)
+
+		state, err := admin.Enable(ctx, "primary failover", 7)
+		require.NoError(t, err)
+		assert.True(t, state.Enabled)
+		assert.Equal(t, SourceAdmin, state.Source)
+		assert.False(t, other.Enabled())
+
+		require.NoError(t, other.Refresh(ctx))
+		assert.Equal(t, "primary failover", other.State().Reason)
+		assert.Equal(t, int64(7), other.State().By)
+		assert.True(t, other.Enabled())
+
+		_, err = admin.Disable(ctx)
+		require.NoError(t, err)
+		require.NoError(t, other.Refresh(ctx))
+		assert.False(t, other.Enabled())
+	})
+
+	t.Run("keeps the last state when redis fails", func(t *testing.T) {
+		client := newTestClient(t)
+		s := NewSwitch(client, false)
+		_, err := s.Enable(ctx, "", 1)
+		require.NoError(t, err)
+
+		client.Close()
+		assert.Error(t, s.Refresh(ctx))
+		assert.True(t, s.Enabled())
+	})
+
+	t.Run("cannot be lifted when forced by the configuration", func(t *testing.T) {
+		s := NewSwitch(newTestClient(t), true)
+		assert.Equal(t, SourceConfig, s.State().Source)
+
+		_, err := s.Disable(ctx)
+		assert.ErrorIs(t, err, ErrForced)
+		require.NoError(t, s.Refresh(ctx))
+		assert.True(t, s.Enabled())
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	s := NewSwitch(newTestClient(t), false)
+	_, err := s.Enable(context.Background(), "migrating orders", 1)
+	require.NoError(t, err)
+
+	router := gin.New()
+	api := router.Group("/api/v1")
+	api.Use(Middleware(s, api.BasePath(), "/admin/read-only"))
+	ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+	api.GET("/events", ok)
+	api.POST("/events", ok)
+	api.PUT("/admin/read-only", ok)
+
+	tests := []struct {
+		method, path string
+		want         int
+	}{
+		{method: http.MethodGet, path: "/api/v1/events", want: http.StatusNoContent},
+		{method: http.MethodPost, path: "/api/v1/events", want: http.StatusServiceUnavailable},
+		{method: http.MethodPut, path: "/api/v1/admin/read-only", want: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.want, rec.Code)
+			if tt.want == http.StatusServiceUnavailable {
+				assert.Equal(t, "30", rec.Header().Get("Retry-After"))
+				assert.Contains(t, rec.Body.String(), `"code":"read_only"`)
+				assert.Contains(t, rec.Body.String(), "migrating orders")
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package requestid
+
+import (
+	"net"
+	"net/netip"
+
+	pkgContext "github.com/duongptryu/gox/context"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Header carries the request ID, both on requests from a trusted proxy and on every response
+const Header = "X-Request-ID"
+
+// maxLength bounds the request IDs accepted from proxies, as they end up in every log line
+const maxLength = 128
+
+// Middleware assigns the request ID of every request: the one of a trusted proxy in front of the API
+// is kept so the logs of the proxy and of the API can be joined, any other request gets a new one.
+// It must run after the request context middleware of gox, which takes the header from anyone.
+func Middleware(trustedProxies []netip.Prefix) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(Header)
+		if !isValid(requestID) || !isTrusted(c.Request.RemoteAddr, trustedProxies) {
+			requestID = uuid.New().String()
+		}
+
+		c.Request = c.Request.WithContext(pkgContext.WithRequestID(c.Request.Context(), requestID))
+		c.Header(Header, requestID)
+		c.Next()
+	}
+}
+
+// ParseTrustedProxies parses the addresses and CIDR ranges of the trusted proxies
+func ParseTrustedProxies(proxies []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, proxy := range proxies {
+		if addr, err := netip.ParseAddr(proxy); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// isTrusted reports whether the peer at remoteAddr is one of the trusted proxies
+func isTrusted(remoteAddr string, trustedProxies []netip.Prefix) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+
+	addr = addr.Unmap()
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// isValid reports whether requestID is safe to log: short and made of url-safe characters only
+func isValid(requestID string) bool {
+	if requestID == "" || len(requestID) > maxLength {
+		return false
+	}
+	for _, r := range requestID {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package requestid
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	pkgContext "github.com/duongptryu/gox/context"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	trustedProxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", "::1"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		header     string
+		kept       bool
+	}{
+		{name: "trusted proxy", remoteAddr: "10.1.2.3:4000", header: "proxy-req-1", kept: true},
+		{name: "trusted ipv6 proxy", remoteAddr: "[::1]:4000", header: "proxy-req-1", kept: true},
+		{name: "untrusted client", remoteAddr: "203.0.113.7:4000", header: "client-req-1"},
+		{name: "trusted proxy without id", remoteAddr: "10.1.2.3:4000"},
+		{name: "unsafe id", remoteAddr: "10.1.2.3:4000", header: "req\nfake log line"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			router := gin.New()
+			router.Use(Middleware(trustedProxies))
+			router.GET("/", func(c *gin.Context) {
+				got = pkgContext.GetRequestID(c.Request.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.header != "" {
+				req.Header.Set(Header, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.NotEmpty(t, got)
+			assert.Equal(t, got, rec.Header().Get(Header))
+			assert.Equal(t, tt.kept, got == tt.header)
+		})
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	prefixes, err := ParseTrustedProxies([]string{"192.168.1.7", "172.16.5.0/12"})
+	require.NoError(t, err)
+	assert.Equal(t, []netip.Prefix{netip.MustParsePrefix("192.168.1.7/32"), netip.MustParsePrefix("172.16.0.0/12")}, prefixes)
+
+	_, err = ParseTrustedProxies([]string{"proxy.local"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,240 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"tixgo/shared/lock"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/robfig/cron/v3"
+)
+
+// lockTTL bounds how long a crashed instance keeps a job locked; the lock is refreshed while the job runs
+const lockTTL = time.Minute
+
+// ErrJobNotFound is returned when triggering a job that is not registered
+var ErrJobNotFound = errors.New("scheduled job not found")
+
+// Trigger tells how a run was started
+type Trigger string
+
+const (
+	TriggerSchedule Trigger = "schedule"
+	TriggerManual   Trigger = "manual"
+)
+
+// Job is a unit of periodic work hosted by the worker
+type Job struct {
+	Name string
+	// Schedule is a standard cron expression or descriptor such as "@every 5m" or "@daily"
+	Schedule string
+	// Timeout cancels the run when exceeded, zero means no timeout
+	Timeout time.Duration
+	Run     func(ctx context.Context) error
+}
+
+// NextRun returns the next time the job is due after the given time
+func (j Job) NextRun(after time.Time) (time.Time, error) {
+	schedule, err := cron.ParseStandard(j.Schedule)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid schedule %q for job %s: %w", j.Schedule, j.Name, err)
+	}
+	return schedule.Next(after), nil
+}
+
+// Run is a single execution of a job
+type Run struct {
+	ID         int64
+	JobName    string
+	Trigger    Trigger
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Err        error
+}
+
+// RunRecorder persists the run history of jobs
+type RunRecorder interface {
+	RecordStart(ctx context.Context, run *Run) error
+	RecordFinish(ctx context.Context, run *Run) error
+}
+
+// Scheduler runs registered jobs on their schedule. Every run holds a distributed lock
+// on the job so only one instance executes it at a time.
+type Scheduler struct {
+	cron     *cron.Cron
+	locker   lock.Locker
+	recorder RunRecorder
+
+	mu   sync.RWMutex
+	jobs map[string]Job
+	// paused skips the runs while it reports true
+	paused func() bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a scheduler
+func New(locker lock.Locker, recorder RunRecorder) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		cron:     cron.New(),
+		locker:   locker,
+		recorder: recorder,
+		jobs:     make(map[string]Job),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Register adds a job to the scheduler
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" || job.Run == nil {
+		return fmt.Errorf("job name and run function are required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[job.Name]; ok {
+		return fmt.Errorf("job %s is already registered", job.Name)
+	}
+
+	if _, err := s.cron.AddFunc(job.Schedule, func() { s.execute(job, TriggerSchedule) }); err != nil {
+		return fmt.Errorf("invalid schedule %q for job %s: %w", job.Schedule, job.Name, err)
+	}
+
+	s.jobs[job.Name] = job
+	return nil
+}
+
+// PauseWhile skips the runs of the jobs, scheduled or triggered, while paused reports true, like while
+// the database refuses writes. It must be set before Start.
+func (s *Scheduler) PauseWhile(paused func() bool) {
+	s.paused = paused
+}
+
+// Jobs returns the registered jobs sorted by name
+func (s *Scheduler) Jobs() []Job {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	jobs := make([]Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	return jobs
+}
+
+// Trigger starts a run of the job right away without waiting for its schedule
+func (s *Scheduler) Trigger(name string) error {
+	s.mu.RLock()
+	job, ok := s.jobs[name]
+	s.mu.RUnlock()
+
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.execute(job, TriggerManual)
+	}()
+	return nil
+}
+
+// Start begins running jobs on their schedule
+func (s *Scheduler) Start() {
+	s.cron.Start()
+}
+
+// Stop stops scheduling new runs and waits for running ones until ctx is done, after which they are cancelled
+func (s *Scheduler) Stop(ctx context.Context) error {
+	cronCtx := s.cron.Stop()
+
+	done := make(chan struct{})
+	go func() {
+		<-cronCtx.Done()
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.cancel()
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) execute(job Job, trigger Trigger) {
+	ctx := s.ctx
+	if s.paused != nil && s.paused() {
+		logger.Info(ctx, "Skipping job while the scheduler is paused", logger.F("job", job.Name), logger.F("trigger", trigger))
+		return
+	}
+
+	if job.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, job.Timeout)
+		defer cancel()
+	}
+
+	err := s.locker.WithLock(ctx, "scheduler:"+job.Name, lockTTL, func(ctx context.Context) error {
+		return s.run(ctx, job, trigger)
+	})
+	if errors.Is(err, lock.ErrNotAcquired) {
+		logger.Debug(ctx, "Skipping job already running on another instance", logger.F("job", job.Name))
+		return
+	}
+	if err != nil {
+		logger.Error(ctx, "Failed to lock scheduled job", logger.F("job", job.Name), logger.F("trigger", trigger), logger.F("error", err))
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, job Job, trigger Trigger) error {
+	run := &Run{
+		JobName:   job.Name,
+		Trigger:   trigger,
+		StartedAt: time.Now(),
+	}
+	if err := s.recorder.RecordStart(ctx, run); err != nil {
+		logger.Error(ctx, "Failed to record job run start", logger.F("job", job.Name), logger.F("error", err))
+	}
+
+	run.Err = safeRun(ctx, job)
+	run.FinishedAt = time.Now()
+
+	if err := s.recorder.RecordFinish(context.WithoutCancel(ctx), run); err != nil {
+		logger.Error(ctx, "Failed to record job run finish", logger.F("job", job.Name), logger.F("error", err))
+	}
+
+	logger.Info(ctx, "Scheduled job finished",
+		logger.F("job", job.Name),
+		logger.F("trigger", trigger),
+		logger.F("duration", run.FinishedAt.Sub(run.StartedAt)),
+		logger.F("err", run.Err))
+
+	// the outcome is part of the run history, only locking errors are reported to the caller
+	return nil
+}
+
+// safeRun turns a panicking job into a failed run instead of crashing the worker
+func safeRun(ctx context.Context, job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job %s panicked: %v", job.Name, r)
+		}
+	}()
+	return job.Run(ctx)
+}
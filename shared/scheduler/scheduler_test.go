@@ -0,0 +1,176 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"tixgo/shared/lock"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init(&logger.Config{Output: io.Discard})
+	os.Exit(m.Run())
+}
+
+type fakeLocker struct {
+	mu   sync.Mutex
+	held map[string]bool
+}
+
+func (l *fakeLocker) WithLock(ctx context.Context, key string, ttl time.Duration, fn func(ctx context.Context) error) error {
+	l.mu.Lock()
+	if l.held[key] {
+		l.mu.Unlock()
+		return lock.ErrNotAcquired
+	}
+	l.held[key] = true
+	l.mu.Unlock()
+
+	defer func() {
+		l.mu.Lock()
+		delete(l.held, key)
+		l.mu.Unlock()
+	}()
+	return fn(ctx)
+}
+
+type fakeRecorder struct {
+	mu       sync.Mutex
+	finished []Run
+}
+
+func (r *fakeRecorder) RecordStart(ctx context.Context, run *Run) error {
+	run.ID = 1
+	return nil
+}
+
+func (r *fakeRecorder) RecordFinish(ctx context.Context, run *Run) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.finished = append(r.finished, *run)
+	return nil
+}
+
+func (r *fakeRecorder) runs() []Run {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Run(nil), r.finished...)
+}
+
+func newTestScheduler() (*Scheduler, *fakeLocker, *fakeRecorder) {
+	locker := &fakeLocker{held: make(map[string]bool)}
+	recorder := &fakeRecorder{}
+	return New(locker, recorder), locker, recorder
+}
+
+func TestScheduler_Register(t *testing.T) {
+	s, _, _ := newTestScheduler()
+	run := func(context.Context) error { return nil }
+
+	require.NoError(t, s.Register(Job{Name: "b", Schedule: "@daily", Run: run}))
+	require.NoError(t, s.Register(Job{Name: "a", Schedule: "*/5 * * * *", Run: run}))
+
+	assert.Error(t, s.Register(Job{Name: "a", Schedule: "@daily", Run: run}), "duplicate name")
+	assert.Error(t, s.Register(Job{Name: "c", Schedule: "not a schedule", Run: run}), "invalid schedule")
+	assert.Error(t, s.Register(Job{Name: "d", Schedule: "@daily"}), "missing run function")
+
+	jobs := s.Jobs()
+	require.Len(t, jobs, 2)
+	assert.Equal(t, "a", jobs[0].Name)
+	assert.Equal(t, "b", jobs[1].Name)
+}
+
+func TestScheduler_Trigger(t *testing.T) {
+	t.Run("records the run outcome", func(t *testing.T) {
+		s, _, recorder := newTestScheduler()
+		jobErr := errors.New("boom")
+		require.NoError(t, s.Register(Job{Name: "ok", Schedule: "@daily", Run: func(context.Context) error { return nil }}))
+		require.NoError(t, s.Register(Job{Name: "fail", Schedule: "@daily", Run: func(context.Context) error { return jobErr }}))
+		require.NoError(t, s.Register(Job{Name: "panic", Schedule: "@daily", Run: func(context.Context) error { panic("oops") }}))
+
+		require.NoError(t, s.Trigger("ok"))
+		require.NoError(t, s.Trigger("fail"))
+		require.NoError(t, s.Trigger("panic"))
+		require.NoError(t, s.Stop(context.Background()))
+
+		runs := map[string]Run{}
+		for _, run := range recorder.runs() {
+			runs[run.JobName] = run
+		}
+		require.Len(t, runs, 3)
+		assert.NoError(t, runs["ok"].Err)
+		assert.Equal(t, TriggerManual, runs["ok"].Trigger)
+		assert.ErrorIs(t, runs["fail"].Err, jobErr)
+		assert.ErrorContains(t, runs["panic"].Err, "panicked")
+	})
+
+	t.Run("skips a job locked by another instance", func(t *testing.T) {
+		s, locker, recorder := newTestScheduler()
+		require.NoError(t, s.Register(Job{Name: "job", Schedule: "@daily", Run: func(context.Context) error {
+			t.Error("job must not run while locked")
+			return nil
+		}}))
+		locker.held["scheduler:job"] = true
+
+		require.NoError(t, s.Trigger("job"))
+		require.NoError(t, s.Stop(context.Background()))
+
+		assert.Empty(t, recorder.runs())
+	})
+
+	t.Run("skips jobs while paused", func(t *testing.T) {
+		s, _, recorder := newTestScheduler()
+		require.NoError(t, s.Register(Job{Name: "job", Schedule: "@daily", Run: func(context.Context) error {
+			t.Error("job must not run while paused")
+			return nil
+		}}))
+		s.PauseWhile(func() bool { return true })
+
+		require.NoError(t, s.Trigger("job"))
+		require.NoError(t, s.Stop(context.Background()))
+
+		assert.Empty(t, recorder.runs())
+	})
+
+	t.Run("rejects unknown jobs", func(t *testing.T) {
+		s, _, _ := newTestScheduler()
+		assert.ErrorIs(t, s.Trigger("missing"), ErrJobNotFound)
+	})
+}
+
+func TestScheduler_Stop(t *testing.T) {
+	s, _, recorder := newTestScheduler()
+	require.NoError(t, s.Register(Job{Name: "slow", Schedule: "@daily", Run: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}))
+	require.NoError(t, s.Trigger("slow"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, s.Stop(ctx), context.DeadlineExceeded)
+
+	// running jobs are cancelled once the shutdown deadline passes
+	assert.Eventually(t, func() bool { return len(recorder.runs()) == 1 }, time.Second, 5*time.Millisecond)
+	assert.ErrorIs(t, recorder.runs()[0].Err, context.Canceled)
+}
+
+func TestJob_NextRun(t *testing.T) {
+	after := time.Date(2025, 1, 1, 10, 30, 0, 0, time.UTC)
+
+	next, err := Job{Name: "hourly", Schedule: "0 * * * *"}.NextRun(after)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 1, 11, 0, 0, 0, time.UTC), next)
+
+	_, err = Job{Name: "broken", Schedule: "bogus"}.NextRun(after)
+	assert.Error(t, err)
+}
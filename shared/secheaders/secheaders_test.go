@@ -0,0 +1,51 @@
+package secheaders
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func serve(cfg Config) http.Header {
+	router := gin.New()
+	router.Use(Middleware(cfg))
+	router.GET("/", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{})
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	return rec.Header()
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("sets the default headers", func(t *testing.T) {
+		header := serve(DefaultConfig())
+
+		assert.Equal(t, "nosniff", header.Get("X-Content-Type-Options"))
+		assert.Equal(t, "max-age=31536000; includeSubDomains", header.Get("Strict-Transport-Security"))
+		assert.Equal(t, "DENY", header.Get("X-Frame-Options"))
+		assert.Equal(t, "no-referrer", header.Get("Referrer-Policy"))
+		assert.Contains(t, header.Get("Content-Security-Policy"), "default-src 'none'")
+	})
+
+	t.Run("leaves the empty headers out", func(t *testing.T) {
+		header := serve(Config{HSTSMaxAge: time.Hour})
+
+		assert.Equal(t, "nosniff", header.Get("X-Content-Type-Options"))
+		assert.Equal(t, "max-age=3600", header.Get("Strict-Transport-Security"))
+		assert.Empty(t, header.Values("X-Frame-Options"))
+		assert.Empty(t, header.Values("Referrer-Policy"))
+		assert.Empty(t, header.Values("Content-Security-Policy"))
+	})
+
+	t.Run("no hsts without a max age", func(t *testing.T) {
+		assert.Empty(t, serve(Config{}).Values("Strict-Transport-Security"))
+	})
+}
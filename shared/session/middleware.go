@@ -0,0 +1,73 @@
+package session
+
+import (
+	stdContext "context"
+	"strings"
+
+	"tixgo/shared/errstack"
+
+	"github.com/duongptryu/gox/context"
+	"github.com/duongptryu/gox/syserr"
+
+	"github.com/gin-gonic/gin"
+)
+
+type apiKeyIDKey struct{}
+
+type testModeKey struct{}
+
+// WithAPIKey marks the context of a request authenticated with the API key of id rather than a session
+func WithAPIKey(ctx stdContext.Context, id int64) stdContext.Context {
+	return stdContext.WithValue(ctx, apiKeyIDKey{}, id)
+}
+
+// APIKeyID returns the API key the request was authenticated with, if it was
+func APIKeyID(ctx stdContext.Context) (int64, bool) {
+	id, ok := ctx.Value(apiKeyIDKey{}).(int64)
+	return id, ok
+}
+
+// WithTestMode marks the context of a request authenticated with a test mode API key
+func WithTestMode(ctx stdContext.Context) stdContext.Context {
+	return stdContext.WithValue(ctx, testModeKey{}, true)
+}
+
+// IsTestMode tells whether the request was authenticated with a test mode API key
+func IsTestMode(ctx stdContext.Context) bool {
+	testMode, _ := ctx.Value(testModeKey{}).(bool)
+	return testMode
+}
+
+// RequireAuth only lets through requests bearing an access token of the service, and puts the user
+// and the claims of the token into the request context like middleware.RequireAuth of gox does.
+// Requests already authenticated with an API key are let through as they are.
+func RequireAuth(service *Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := APIKeyID(c.Request.Context()); ok {
+			c.Next()
+			return
+		}
+
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			c.Error(errstack.New(syserr.UnauthorizedCode, "authorization token required"))
+			c.Abort()
+			return
+		}
+
+		claims, err := service.ValidateAccessToken(token)
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		ctx := c.Request.Context()
+		ctx = context.WithUserID(ctx, claims.UserID)
+		ctx = context.WithUserType(ctx, claims.UserType)
+		ctx = context.WithAuthClaims(ctx, &claims.Claims)
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,20 @@
+package session
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTestMode(t *testing.T) {
+	ctx := WithAPIKey(context.Background(), 3)
+	assert.False(t, IsTestMode(ctx), "live keys are the default")
+	assert.False(t, IsTestMode(context.Background()))
+
+	ctx = WithTestMode(ctx)
+	assert.True(t, IsTestMode(ctx))
+	id, ok := APIKeyID(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, int64(3), id)
+}
@@ -0,0 +1,215 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"tixgo/shared/errstack"
+
+	"github.com/duongptryu/gox/auth"
+	"github.com/duongptryu/gox/syserr"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Client is the kind of client a session is opened from, it picks the lifetime of the tokens
+type Client string
+
+const (
+	ClientWeb    Client = "web"
+	ClientMobile Client = "mobile"
+)
+
+// IsValid checks if the client is known
+func (c Client) IsValid() bool {
+	return c == ClientWeb || c == ClientMobile
+}
+
+// Lifetime is how long the tokens of a session are valid
+type Lifetime struct {
+	Access  time.Duration
+	Refresh time.Duration
+}
+
+// Policy decides the lifetime of the tokens of a session
+type Policy struct {
+	// Default applies to the clients without a lifetime of their own
+	Default Lifetime
+	// Clients overrides Default per client, a zero part keeps the default
+	Clients map[Client]Lifetime
+	// RememberMe extends the session when the user asks to be remembered, a zero part keeps the client's
+	RememberMe Lifetime
+	// Max caps every lifetime, a zero part caps nothing
+	Max Lifetime
+}
+
+// Lifetime returns the lifetime of the tokens of a session opened from client
+func (p Policy) Lifetime(client Client, rememberMe bool) Lifetime {
+	lifetime := p.Default
+	if override, ok := p.Clients[client]; ok {
+		if override.Access > 0 {
+			lifetime.Access = override.Access
+		}
+		if override.Refresh > 0 {
+			lifetime.Refresh = override.Refresh
+		}
+	}
+
+	if rememberMe {
+		lifetime.Access = max(lifetime.Access, p.RememberMe.Access)
+		lifetime.Refresh = max(lifetime.Refresh, p.RememberMe.Refresh)
+	}
+
+	if p.Max.Access > 0 {
+		lifetime.Access = min(lifetime.Access, p.Max.Access)
+	}
+	if p.Max.Refresh > 0 {
+		lifetime.Refresh = min(lifetime.Refresh, p.Max.Refresh)
+	}
+
+	return lifetime
+}
+
+// Claims are the claims of the session tokens. They extend auth.Claims, so the claims of a session
+// read the same as the claims of gox once validated.
+type Claims struct {
+	auth.Claims
+	Client     Client `json:"client"`
+	RememberMe bool   `json:"remember_me,omitempty"`
+}
+
+// TokenPair is the access and refresh tokens of a session
+type TokenPair struct {
+	AccessToken      string
+	RefreshToken     string
+	ExpiresIn        int64
+	RefreshExpiresIn int64
+}
+
+// Service issues the tokens of sessions with the lifetime its policy gives their client, and validates them.
+// Its tokens name the issuer and audience of the service, so a token signed for another environment
+// or service sharing the secret key is rejected.
+type Service struct {
+	secretKey []byte
+	issuer    string
+	audience  string
+	policy    Policy
+}
+
+// NewService creates a session service signing with secretKey the tokens issued by issuer for audience
+func NewService(secretKey, issuer, audience string, policy Policy) *Service {
+	return &Service{
+		secretKey: []byte(secretKey),
+		issuer:    issuer,
+		audience:  audience,
+		policy:    policy,
+	}
+}
+
+// GenerateTokenPair issues the tokens of a session of a user opened from client
+func (s *Service) GenerateTokenPair(ctx context.Context, userID, userType string, client Client, rememberMe bool) (*TokenPair, error) {
+	lifetime := s.policy.Lifetime(client, rememberMe)
+	now := time.Now()
+
+	accessToken, err := s.sign(userID, userType, "access", client, rememberMe, now, lifetime.Access)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to generate access token")
+	}
+
+	refreshToken, err := s.sign(userID, userType, "refresh", client, rememberMe, now, lifetime.Refresh)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InternalCode, "failed to generate refresh token")
+	}
+
+	return &TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		ExpiresIn:        int64(lifetime.Access.Seconds()),
+		RefreshExpiresIn: int64(lifetime.Refresh.Seconds()),
+	}, nil
+}
+
+// ValidateToken validates a token issued by the service for its audience and returns its claims
+func (s *Service) ValidateToken(tokenString string) (*Claims, error) {
+	var claims Claims
+	_, err := jwt.ParseWithClaims(tokenString, &claims, func(*jwt.Token) (interface{}, error) {
+		return s.secretKey, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(s.issuer),
+		jwt.WithAudience(s.audience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, errstack.Wrap(err, syserr.UnauthorizedCode, "invalid token")
+	}
+
+	return &claims, nil
+}
+
+// ValidateAccessToken validates specifically an access token
+func (s *Service) ValidateAccessToken(tokenString string) (*Claims, error) {
+	claims, err := s.ValidateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Type != "access" {
+		return nil, errstack.New(syserr.UnauthorizedCode, "token is not an access token")
+	}
+
+	return claims, nil
+}
+
+// ValidateRefreshToken validates specifically a refresh token
+func (s *Service) ValidateRefreshToken(tokenString string) (*Claims, error) {
+	claims, err := s.ValidateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Type != "refresh" {
+		return nil, errstack.New(syserr.UnauthorizedCode, "token is not a refresh token")
+	}
+
+	return claims, nil
+}
+
+// sign signs a token of tokenType valid for ttl. Every token gets a unique id (jti),
+// the handle to revoke it by.
+func (s *Service) sign(userID, userType, tokenType string, client Client, rememberMe bool, now time.Time, ttl time.Duration) (string, error) {
+	id, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+
+	claims := Claims{
+		Claims: auth.Claims{
+			UserID:   userID,
+			UserType: userType,
+			Type:     tokenType,
+			RegisteredClaims: jwt.RegisteredClaims{
+				ID:        id,
+				Issuer:    s.issuer,
+				Audience:  jwt.ClaimStrings{s.audience},
+				ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+				IssuedAt:  jwt.NewNumericDate(now),
+				Subject:   userID,
+			},
+		},
+		Client:     client,
+		RememberMe: rememberMe,
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secretKey)
+}
+
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
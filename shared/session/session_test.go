@@ -0,0 +1,114 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/duongptryu/gox/auth"
+	"github.com/duongptryu/gox/syserr"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testPolicy = Policy{
+	Default: Lifetime{Access: 15 * time.Minute, Refresh: 24 * time.Hour},
+	Clients: map[Client]Lifetime{
+		ClientMobile: {Access: time.Hour, Refresh: 30 * 24 * time.Hour},
+	},
+	RememberMe: Lifetime{Refresh: 90 * 24 * time.Hour},
+	Max:        Lifetime{Access: time.Hour, Refresh: 60 * 24 * time.Hour},
+}
+
+func TestPolicy_Lifetime(t *testing.T) {
+	tests := []struct {
+		name       string
+		client     Client
+		rememberMe bool
+		want       Lifetime
+	}{
+		{name: "web uses the default", client: ClientWeb, want: Lifetime{Access: 15 * time.Minute, Refresh: 24 * time.Hour}},
+		{name: "mobile lives longer", client: ClientMobile, want: Lifetime{Access: time.Hour, Refresh: 30 * 24 * time.Hour}},
+		{name: "remember me is capped by the max", client: ClientWeb, rememberMe: true, want: Lifetime{Access: 15 * time.Minute, Refresh: 60 * 24 * time.Hour}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, testPolicy.Lifetime(tt.client, tt.rememberMe))
+		})
+	}
+
+	t.Run("a zero client part keeps the default", func(t *testing.T) {
+		policy := Policy{
+			Default: Lifetime{Access: time.Minute, Refresh: time.Hour},
+			Clients: map[Client]Lifetime{ClientMobile: {Refresh: 2 * time.Hour}},
+		}
+		assert.Equal(t, Lifetime{Access: time.Minute, Refresh: 2 * time.Hour}, policy.Lifetime(ClientMobile, false))
+	})
+}
+
+func TestService_GenerateTokenPair(t *testing.T) {
+	service := NewService("secret", "tixgo-test", "tixgo-api", testPolicy)
+
+	tokens, err := service.GenerateTokenPair(context.Background(), "42", "customer", ClientMobile, true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(time.Hour.Seconds()), tokens.ExpiresIn)
+	assert.Equal(t, int64((60 * 24 * time.Hour).Seconds()), tokens.RefreshExpiresIn)
+
+	access, err := service.ValidateAccessToken(tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "42", access.UserID)
+	assert.Equal(t, "customer", access.UserType)
+	assert.Equal(t, "tixgo-test", access.Issuer)
+	assert.Equal(t, jwt.ClaimStrings{"tixgo-api"}, access.Audience)
+
+	refresh, err := service.ValidateRefreshToken(tokens.RefreshToken)
+	require.NoError(t, err)
+	assert.Equal(t, ClientMobile, refresh.Client)
+	assert.True(t, refresh.RememberMe)
+	assert.WithinDuration(t, time.Now().Add(60*24*time.Hour), refresh.ExpiresAt.Time, time.Minute)
+
+	// every token can be revoked on its own
+	assert.Len(t, access.ID, 32)
+	assert.NotEqual(t, access.ID, refresh.ID)
+}
+
+func TestService_ValidateToken(t *testing.T) {
+	service := NewService("secret", "tixgo-test", "tixgo-api", testPolicy)
+	tokens, err := service.GenerateTokenPair(context.Background(), "42", "customer", ClientWeb, false)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		validator *Service
+		token     string
+	}{
+		{name: "another environment", validator: NewService("secret", "tixgo-prod", "tixgo-api", testPolicy), token: tokens.AccessToken},
+		{name: "another service", validator: NewService("secret", "tixgo-test", "tixgo-admin", testPolicy), token: tokens.AccessToken},
+		{name: "another secret", validator: NewService("other", "tixgo-test", "tixgo-api", testPolicy), token: tokens.AccessToken},
+		{name: "a token without issuer nor audience", validator: service, token: goxToken(t)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.validator.ValidateToken(tt.token)
+			require.Error(t, err)
+			assert.Equal(t, syserr.UnauthorizedCode, syserr.GetCodeFromGenericError(err))
+		})
+	}
+
+	t.Run("a refresh token is not an access token", func(t *testing.T) {
+		_, err := service.ValidateAccessToken(tokens.RefreshToken)
+		assert.Error(t, err)
+		_, err = service.ValidateRefreshToken(tokens.AccessToken)
+		assert.Error(t, err)
+	})
+}
+
+// goxToken signs an access token with the plain auth.JWTService of gox
+func goxToken(t *testing.T) string {
+	token, _, _, err := auth.NewJWTService("secret", time.Minute, time.Minute).GenerateTokenPair(context.Background(), "42", "customer")
+	require.NoError(t, err)
+	return token
+}
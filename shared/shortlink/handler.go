@@ -0,0 +1,33 @@
+package shortlink
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// Handler redirects the visitors of the short link of the :code parameter to its target. Visitors are
+// people following a link in a browser, so failures are answered with plain text rather than JSON.
+func Handler(service *Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		link, err := service.Resolve(c.Request.Context(), c.Param("code"))
+		switch {
+		case errors.Is(err, ErrNotFound):
+			c.String(http.StatusNotFound, "This link does not exist.")
+			return
+		case errors.Is(err, ErrExpired):
+			c.String(http.StatusGone, "This link has expired.")
+			return
+		case err != nil:
+			logger.Error(c.Request.Context(), "Failed to resolve short link", logger.F("code", c.Param("code")), logger.F("error", err))
+			c.String(http.StatusInternalServerError, "This link cannot be opened right now, please try again later.")
+			return
+		}
+
+		// every visit must reach us to be counted and to honor the expiry
+		c.Header("Cache-Control", "no-store")
+		c.Redirect(http.StatusFound, link.TargetURL)
+	}
+}
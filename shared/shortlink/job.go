@@ -0,0 +1,39 @@
+package shortlink
+
+import (
+	"context"
+	"time"
+
+	"tixgo/shared/scheduler"
+
+	"github.com/duongptryu/gox/logger"
+)
+
+const (
+	// JobPurge is the name of the job deleting the expired links
+	JobPurge = "shortlink.purge"
+
+	// ExpiredRetention is how long expired links are kept, answering they expired rather than
+	// that they do not exist
+	ExpiredRetention = 30 * 24 * time.Hour
+)
+
+// NewPurgeJob returns the job deleting the links expired for ExpiredRetention, daily
+func NewPurgeJob(store Store) scheduler.Job {
+	return scheduler.Job{
+		Name:     JobPurge,
+		Schedule: "@daily",
+		Timeout:  5 * time.Minute,
+		Run: func(ctx context.Context) error {
+			deleted, err := store.DeleteExpired(ctx, time.Now().Add(-ExpiredRetention))
+			if err != nil {
+				return err
+			}
+
+			if deleted > 0 {
+				logger.Info(ctx, "Purged expired short links", logger.F("count", deleted))
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,89 @@
+package shortlink
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"tixgo/shared/dbtimeout"
+	"tixgo/shared/pgerr"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgresStore implements Store with the short_links table
+type PostgresStore struct {
+	db *sqlx.DB
+}
+
+// NewPostgresStore creates a short link store backed by postgres
+func NewPostgresStore(db *sqlx.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) Create(ctx context.Context, link *Link) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		INSERT INTO short_links (code, target_url, expires_at)
+		VALUES ($1, $2, $3)
+		RETURNING created_at`
+
+	err := s.db.QueryRowContext(ctx, query, link.Code, link.TargetURL, link.ExpiresAt).Scan(&link.CreatedAt)
+	if err != nil {
+		if pgerr.IsUniqueViolation(err) {
+			return ErrCodeTaken
+		}
+		return fmt.Errorf("failed to create short link: %w", err)
+	}
+
+	return nil
+}
+
+func (s *PostgresStore) Get(ctx context.Context, code string) (*Link, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT code, target_url, expires_at, clicks, last_clicked_at, created_at
+		FROM short_links
+		WHERE code = $1`
+
+	link := &Link{}
+	err := s.db.QueryRowContext(ctx, query, code).
+		Scan(&link.Code, &link.TargetURL, &link.ExpiresAt, &link.Clicks, &link.LastClickedAt, &link.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get short link %s: %w", code, err)
+	}
+
+	return link, nil
+}
+
+func (s *PostgresStore) RecordClick(ctx context.Context, code string, at time.Time) error {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	query := `UPDATE short_links SET clicks = clicks + 1, last_clicked_at = $2 WHERE code = $1`
+	if _, err := s.db.ExecContext(ctx, query, code, at); err != nil {
+		return fmt.Errorf("failed to record click of short link %s: %w", code, err)
+	}
+
+	return nil
+}
+
+func (s *PostgresStore) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	ctx, cancel := dbtimeout.WithQueryTimeout(ctx)
+	defer cancel()
+
+	result, err := s.db.ExecContext(ctx, `DELETE FROM short_links WHERE expires_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired short links: %w", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,167 @@
+// Package shortlink shortens the links sent where characters are counted, e.g. SMS, and redirects
+// their visitors to the target, counting the clicks. Links may expire.
+package shortlink
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// CodeLength is the length of generated codes, 62^7 of them leave collisions unlikely for years
+	CodeLength = 7
+	// PathPrefix is where the redirect endpoint is served, short URLs are the base URL, the prefix and the code
+	PathPrefix = "/s/"
+
+	codeAlphabet   = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	createAttempts = 3
+)
+
+var (
+	// ErrNotFound is returned for unknown codes
+	ErrNotFound = errors.New("short link not found")
+	// ErrExpired is returned for links visited after their expiry
+	ErrExpired = errors.New("short link expired")
+	// ErrCodeTaken is returned by stores when a code is already used
+	ErrCodeTaken = errors.New("short link code already taken")
+	// ErrInvalidTarget is returned when shortening anything but an absolute http(s) URL
+	ErrInvalidTarget = errors.New("short link target must be an absolute http or https URL")
+)
+
+// Link is a short link to a target URL
+type Link struct {
+	Code      string
+	TargetURL string
+	// ExpiresAt is nil for links that never expire
+	ExpiresAt     *time.Time
+	Clicks        int64
+	LastClickedAt *time.Time
+	CreatedAt     time.Time
+}
+
+// IsExpired tells whether the link expired at the given time
+func (l *Link) IsExpired(now time.Time) bool {
+	return l.ExpiresAt != nil && !now.Before(*l.ExpiresAt)
+}
+
+// Store keeps the short links
+type Store interface {
+	// Create saves a link, failing with ErrCodeTaken if its code is used
+	Create(ctx context.Context, link *Link) error
+
+	// Get retrieves a link by code, failing with ErrNotFound if there is none
+	Get(ctx context.Context, code string) (*Link, error)
+
+	// RecordClick counts a click on a link
+	RecordClick(ctx context.Context, code string, at time.Time) error
+
+	// DeleteExpired deletes the links that expired before the given time and returns how many
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Service shortens links and resolves them
+type Service struct {
+	store   Store
+	baseURL string
+}
+
+// NewService creates a service whose short URLs start with baseURL, the public scheme and host of the API
+func NewService(store Store, baseURL string) *Service {
+	return &Service{
+		store:   store,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// Shorten creates a short link to targetURL and returns its short URL. The link expires after ttl,
+// never when ttl is zero.
+func (s *Service) Shorten(ctx context.Context, targetURL string, ttl time.Duration) (string, error) {
+	target, err := url.Parse(targetURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return "", ErrInvalidTarget
+	}
+
+	link := &Link{TargetURL: target.String()}
+	if ttl > 0 {
+		expiresAt := time.Now().Add(ttl)
+		link.ExpiresAt = &expiresAt
+	}
+
+	for attempt := 1; ; attempt++ {
+		link.Code, err = newCode()
+		if err != nil {
+			return "", err
+		}
+
+		err = s.store.Create(ctx, link)
+		if err == nil {
+			return s.URL(link.Code), nil
+		}
+		if !errors.Is(err, ErrCodeTaken) || attempt == createAttempts {
+			return "", fmt.Errorf("failed to create short link: %w", err)
+		}
+	}
+}
+
+// URL returns the short URL of a code
+func (s *Service) URL(code string) string {
+	return s.baseURL + PathPrefix + code
+}
+
+// Resolve returns the link of a code and counts the click, failing with ErrNotFound or ErrExpired
+func (s *Service) Resolve(ctx context.Context, code string) (*Link, error) {
+	if !validCode(code) {
+		return nil, ErrNotFound
+	}
+
+	link, err := s.store.Get(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if link.IsExpired(now) {
+		return nil, ErrExpired
+	}
+
+	if err := s.store.RecordClick(ctx, code, now); err != nil {
+		return nil, err
+	}
+	link.Clicks++
+	link.LastClickedAt = &now
+
+	return link, nil
+}
+
+// newCode generates a random code of CodeLength characters
+func newCode() (string, error) {
+	random := make([]byte, CodeLength)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("failed to generate short link code: %w", err)
+	}
+
+	code := make([]byte, CodeLength)
+	for i, b := range random {
+		// the modulo bias of 256 over 62 symbols is negligible for codes
+		code[i] = codeAlphabet[int(b)%len(codeAlphabet)]
+	}
+	return string(code), nil
+}
+
+// validCode tells whether code could have been generated, so others are not looked up
+func validCode(code string) bool {
+	if len(code) != CodeLength {
+		return false
+	}
+	for i := 0; i < len(code); i++ {
+		if strings.IndexByte(codeAlphabet, code[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,242 @@
+package shortlink
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init(&logger.Config{Output: io.Discard})
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+// memoryStore is a Store keeping the links in memory. taken makes the next creations fail with ErrCodeTaken.
+type memoryStore struct {
+	mu    sync.Mutex
+	links map[string]*Link
+	taken int
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{links: map[string]*Link{}}
+}
+
+func (s *memoryStore) Create(_ context.Context, link *Link) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.taken > 0 {
+		s.taken--
+		return ErrCodeTaken
+	}
+	if _, ok := s.links[link.Code]; ok {
+		return ErrCodeTaken
+	}
+	stored := *link
+	stored.CreatedAt = time.Now()
+	s.links[link.Code] = &stored
+	return nil
+}
+
+func (s *memoryStore) Get(_ context.Context, code string) (*Link, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link, ok := s.links[code]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	copied := *link
+	return &copied, nil
+}
+
+func (s *memoryStore) RecordClick(_ context.Context, code string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if link, ok := s.links[code]; ok {
+		link.Clicks++
+		link.LastClickedAt = &at
+	}
+	return nil
+}
+
+func (s *memoryStore) DeleteExpired(_ context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for code, link := range s.links {
+		if link.ExpiresAt != nil && link.ExpiresAt.Before(before) {
+			delete(s.links, code)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func codeOf(t *testing.T, shortURL string) string {
+	t.Helper()
+	code, ok := strings.CutPrefix(shortURL, "https://tix.example"+PathPrefix)
+	require.True(t, ok, shortURL)
+	return code
+}
+
+func TestService_Shorten(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("creates a link to the target", func(t *testing.T) {
+		store := newMemoryStore()
+		service := NewService(store, "https://tix.example/")
+
+		shortURL, err := service.Shorten(ctx, "https://tix.example/tickets/transfer?token=abc", time.Hour)
+		require.NoError(t, err)
+
+		code := codeOf(t, shortURL)
+		assert.Len(t, code, CodeLength)
+		assert.True(t, validCode(code))
+
+		link, err := store.Get(ctx, code)
+		require.NoError(t, err)
+		assert.Equal(t, "https://tix.example/tickets/transfer?token=abc", link.TargetURL)
+		require.NotNil(t, link.ExpiresAt)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), *link.ExpiresAt, time.Minute)
+	})
+
+	t.Run("never expires without a ttl", func(t *testing.T) {
+		store := newMemoryStore()
+		shortURL, err := NewService(store, "https://tix.example").Shorten(ctx, "https://tix.example/e/1", 0)
+		require.NoError(t, err)
+
+		link, err := store.Get(ctx, codeOf(t, shortURL))
+		require.NoError(t, err)
+		assert.Nil(t, link.ExpiresAt)
+	})
+
+	t.Run("retries a taken code", func(t *testing.T) {
+		store := newMemoryStore()
+		store.taken = createAttempts - 1
+
+		_, err := NewService(store, "https://tix.example").Shorten(ctx, "https://tix.example/e/1", 0)
+		assert.NoError(t, err)
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		store := newMemoryStore()
+		store.taken = createAttempts
+
+		_, err := NewService(store, "https://tix.example").Shorten(ctx, "https://tix.example/e/1", 0)
+		assert.ErrorIs(t, err, ErrCodeTaken)
+	})
+
+	t.Run("rejects targets that are not absolute http urls", func(t *testing.T) {
+		service := NewService(newMemoryStore(), "https://tix.example")
+		for _, target := range []string{"", "/tickets/1", "javascript:alert(1)", "ftp://tix.example/file", "https://"} {
+			_, err := service.Shorten(ctx, target, 0)
+			assert.ErrorIs(t, err, ErrInvalidTarget, target)
+		}
+	})
+}
+
+func TestService_Resolve(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	service := NewService(store, "https://tix.example")
+
+	shortURL, err := service.Shorten(ctx, "https://tix.example/e/1", time.Hour)
+	require.NoError(t, err)
+	code := codeOf(t, shortURL)
+
+	t.Run("counts the clicks", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			link, err := service.Resolve(ctx, code)
+			require.NoError(t, err)
+			assert.Equal(t, "https://tix.example/e/1", link.TargetURL)
+		}
+
+		link, err := store.Get(ctx, code)
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, link.Clicks)
+		assert.NotNil(t, link.LastClickedAt)
+	})
+
+	t.Run("does not look malformed codes up", func(t *testing.T) {
+		for _, code := range []string{"", "abc", "abcdefgh", "abc-efg", "abc/efg"} {
+			_, err := service.Resolve(ctx, code)
+			assert.ErrorIs(t, err, ErrNotFound, code)
+		}
+	})
+
+	t.Run("refuses expired links", func(t *testing.T) {
+		expiredAt := time.Now().Add(-time.Minute)
+		require.NoError(t, store.Create(ctx, &Link{Code: "Expired", TargetURL: "https://tix.example/e/2", ExpiresAt: &expiredAt}))
+
+		_, err := service.Resolve(ctx, "Expired")
+		assert.ErrorIs(t, err, ErrExpired)
+
+		link, err := store.Get(ctx, "Expired")
+		require.NoError(t, err)
+		assert.Zero(t, link.Clicks)
+	})
+}
+
+func TestHandler(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	service := NewService(store, "https://tix.example")
+
+	shortURL, err := service.Shorten(ctx, "https://tix.example/e/1", time.Hour)
+	require.NoError(t, err)
+	expiredAt := time.Now().Add(-time.Minute)
+	require.NoError(t, store.Create(ctx, &Link{Code: "Expired", TargetURL: "https://tix.example/e/2", ExpiresAt: &expiredAt}))
+
+	router := gin.New()
+	router.GET(PathPrefix+":code", Handler(service))
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		return recorder
+	}
+
+	recorder := serve(PathPrefix + codeOf(t, shortURL))
+	assert.Equal(t, http.StatusFound, recorder.Code)
+	assert.Equal(t, "https://tix.example/e/1", recorder.Header().Get("Location"))
+	assert.Equal(t, "no-store", recorder.Header().Get("Cache-Control"))
+
+	assert.Equal(t, http.StatusGone, serve(PathPrefix+"Expired").Code)
+	assert.Equal(t, http.StatusNotFound, serve(PathPrefix+"Unknown").Code)
+}
+
+func TestNewPurgeJob(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+
+	longExpired := time.Now().Add(-ExpiredRetention - time.Hour)
+	recentlyExpired := time.Now().Add(-time.Hour)
+	require.NoError(t, store.Create(ctx, &Link{Code: "LongExp", TargetURL: "https://tix.example", ExpiresAt: &longExpired}))
+	require.NoError(t, store.Create(ctx, &Link{Code: "RecExpd", TargetURL: "https://tix.example", ExpiresAt: &recentlyExpired}))
+	require.NoError(t, store.Create(ctx, &Link{Code: "Forever", TargetURL: "https://tix.example"}))
+
+	require.NoError(t, NewPurgeJob(store).Run(ctx))
+
+	_, err := store.Get(ctx, "LongExp")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = store.Get(ctx, "RecExpd")
+	assert.NoError(t, err)
+	_, err = store.Get(ctx, "Forever")
+	assert.NoError(t, err)
+}
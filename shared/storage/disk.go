@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// DiskStore keeps the files in a local directory, which instances must share, e.g. a mounted volume
+type DiskStore struct {
+	root string
+}
+
+// NewDiskStore creates a store of the files under root, created on the first write
+func NewDiskStore(root string) *DiskStore {
+	return &DiskStore{root: root}
+}
+
+// Put writes to a temporary file renamed over the key once complete, so readers never see a partial file
+func (s *DiskStore) Put(_ context.Context, key string, r io.Reader) (int64, error) {
+	name, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(name), 0o750); err != nil {
+		return 0, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create stored file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	size, err := io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to write stored file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return 0, fmt.Errorf("failed to save stored file: %w", err)
+	}
+
+	return size, nil
+}
+
+// Open reads the file under key
+func (s *DiskStore) Open(_ context.Context, key string) (io.ReadSeekCloser, error) {
+	name, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open stored file: %w", err)
+	}
+
+	return file, nil
+}
+
+// Delete removes the file under key
+func (s *DiskStore) Delete(_ context.Context, key string) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete stored file: %w", err)
+	}
+	return nil
+}
+
+func (s *DiskStore) path(key string) (string, error) {
+	if err := ValidateKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewDiskStore(t.TempDir())
+
+	size, err := store.Put(ctx, "kyc/42/1/id.pdf", strings.NewReader("first"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), size)
+
+	_, err = store.Put(ctx, "kyc/42/1/id.pdf", strings.NewReader("second"))
+	require.NoError(t, err)
+
+	file, err := store.Open(ctx, "kyc/42/1/id.pdf")
+	require.NoError(t, err)
+	content, err := io.ReadAll(file)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	assert.Equal(t, "second", string(content))
+
+	entries, err := os.ReadDir(store.root + "/kyc/42/1")
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary file is left behind")
+
+	require.NoError(t, store.Delete(ctx, "kyc/42/1/id.pdf"))
+	require.NoError(t, store.Delete(ctx, "kyc/42/1/id.pdf"))
+	_, err = store.Open(ctx, "kyc/42/1/id.pdf")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestValidateKey(t *testing.T) {
+	for _, key := range []string{"a.pdf", "kyc/42/a.pdf", "..a/b"} {
+		assert.NoError(t, ValidateKey(key), key)
+	}
+	for _, key := range []string{"", "/etc/passwd", "../a", "..", "a/../../b", "a//b", "a/./b", `a\b`, "a/"} {
+		assert.ErrorIs(t, ValidateKey(key), ErrInvalidKey, key)
+	}
+}
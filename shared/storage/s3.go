@@ -0,0 +1,355 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"tixgo/shared/httpclient"
+)
+
+// Metadata describes a file to the stores able to keep it along, like S3 keeping object tags that the
+// lifecycle rules of a bucket filter on
+type Metadata struct {
+	ContentType string
+	// Tags are matched by lifecycle rules, S3 allows at most 10
+	Tags map[string]string
+	// Values are returned with the file, as user metadata on S3
+	Values map[string]string
+}
+
+// MetadataStore is a Store keeping the metadata of the files it is given
+type MetadataStore interface {
+	Store
+
+	// PutWithMetadata writes the content of r under key along with metadata and returns its size
+	PutWithMetadata(ctx context.Context, key string, r io.Reader, metadata Metadata) (int64, error)
+}
+
+// S3Config locates a bucket of an S3 compatible object storage
+type S3Config struct {
+	// Endpoint is the URL of the storage, the AWS endpoint of Region when empty
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PathStyle addresses the bucket in the path rather than the host, as MinIO and most local setups need
+	PathStyle bool
+}
+
+// emptyPayloadHash is the SHA-256 of an empty body, signed for the requests without one
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// S3Store keeps the files as objects of an S3 bucket, requests being signed with AWS Signature Version 4
+type S3Store struct {
+	cfg    S3Config
+	client *httpclient.Client
+	now    func() time.Time
+}
+
+// NewS3Store creates a store of the files in the bucket of cfg
+func NewS3Store(cfg S3Config) *S3Store {
+	clientCfg := httpclient.DefaultConfig("s3")
+	// bodies are whole exports, the default bounds a provider API call
+	clientCfg.Timeout = 5 * time.Minute
+
+	return &S3Store{cfg: cfg, client: httpclient.New(clientCfg), now: time.Now}
+}
+
+// Put writes the content of r under key
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	return s.PutWithMetadata(ctx, key, r, Metadata{})
+}
+
+// PutWithMetadata writes the content of r under key, tagged and with the user metadata of metadata.
+// The content is spooled to a temporary file first, S3 needs its length and hash before the upload.
+func (s *S3Store) PutWithMetadata(ctx context.Context, key string, r io.Reader, metadata Metadata) (int64, error) {
+	if err := ValidateKey(key); err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp("", "s3-upload-*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create upload file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), r)
+	if err != nil {
+		return 0, fmt.Errorf("failed to spool upload: %w", err)
+	}
+
+	req, err := s.newRequest(ctx, http.MethodPut, key, io.NewSectionReader(tmp, 0, size))
+	if err != nil {
+		return 0, err
+	}
+	req.ContentLength = size
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(io.NewSectionReader(tmp, 0, size)), nil
+	}
+
+	if metadata.ContentType != "" {
+		req.Header.Set("Content-Type", metadata.ContentType)
+	}
+	for name, value := range metadata.Values {
+		req.Header.Set("X-Amz-Meta-"+name, value)
+	}
+	if len(metadata.Tags) > 0 {
+		tags := url.Values{}
+		for name, value := range metadata.Tags {
+			tags.Set(name, value)
+		}
+		req.Header.Set("X-Amz-Tagging", tags.Encode())
+	}
+
+	resp, err := s.do(req, hex.EncodeToString(hash.Sum(nil)))
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+
+	return size, nil
+}
+
+// Open reads the object under key, downloaded to a temporary file removed once closed
+func (s *S3Store) Open(ctx context.Context, key string) (io.ReadSeekCloser, error) {
+	if err := ValidateKey(key); err != nil {
+		return nil, err
+	}
+
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	tmp, err := os.CreateTemp("", "s3-download-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create download file: %w", err)
+	}
+	file := &tempFile{File: tmp}
+
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to download %s: %w", key, err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to rewind download: %w", err)
+	}
+
+	return file, nil
+}
+
+// Delete removes the object under key
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req, emptyPayloadHash)
+	if err == ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// newRequest creates a request of the object under key, addressing the bucket as configured
+func (s *S3Store) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	endpoint := s.cfg.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + s.cfg.Region + ".amazonaws.com"
+	}
+	base, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint: %w", err)
+	}
+
+	objectPath := "/" + key
+	if s.cfg.PathStyle {
+		objectPath = "/" + s.cfg.Bucket + objectPath
+	} else {
+		base.Host = s.cfg.Bucket + "." + base.Host
+	}
+	base.Path = strings.TrimSuffix(base.Path, "/") + objectPath
+	base.RawPath = uriEncode(base.Path, false)
+
+	req, err := http.NewRequestWithContext(ctx, method, base.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 request: %w", err)
+	}
+	return req, nil
+}
+
+// s3Error is the body of the error responses of S3
+type s3Error struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// do signs and sends the request, failing with ErrNotFound on 404 and with the S3 error code on other
+// error responses
+func (s *S3Store) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	signV4(req, s.cfg, payloadHash, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("S3 %s %s failed: %w", req.Method, req.URL.Path, err)
+	}
+	if resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+
+	var body s3Error
+	xml.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&body)
+	return nil, fmt.Errorf("S3 %s %s failed with %d %s: %s", req.Method, req.URL.Path, resp.StatusCode, body.Code, body.Message)
+}
+
+// signV4 sets the Authorization of the request as AWS Signature Version 4 defines it, signing the host
+// and every header set on the request
+func signV4(req *http.Request, cfg S3Config, payloadHash string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	scope := now.Format("20060102") + "/" + cfg.Region + "/s3/aws4_request"
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		switch name {
+		case "authorization", "user-agent", "content-length", "accept-encoding":
+			continue
+		}
+		headers[name] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		uriEncode(req.URL.Path, false),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+cfg.SecretAccessKey), now.Format("20060102"))
+	key = hmacSHA256(key, cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		cfg.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes every byte but the unreserved characters, and slashes unless encodeSlash
+func uriEncode(value string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			b.WriteString("%" + strings.ToUpper(strconv.FormatUint(uint64(c)|0x100, 16)[1:]))
+		}
+	}
+	return b.String()
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// tempFile is a downloaded file, removed once closed
+type tempFile struct {
+	*os.File
+}
+
+func (f *tempFile) Close() error {
+	err := f.File.Close()
+	os.Remove(f.File.Name())
+	return err
+}
@@ -0,0 +1,39 @@
+// Package storage keeps uploaded files, like the documents of organizers, under keys chosen by the
+// modules owning them.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+)
+
+var (
+	// ErrNotFound is returned for keys without a file
+	ErrNotFound = errors.New("stored file not found")
+	// ErrInvalidKey is returned for keys that are not a clean relative path
+	ErrInvalidKey = errors.New("storage keys must be clean relative paths")
+)
+
+// Store keeps files by key. Keys are slash separated relative paths, e.g. "kyc/42/1/id.pdf".
+type Store interface {
+	// Put writes the content of r under key, replacing the file there if any, and returns its size
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+
+	// Open reads the file under key, failing with ErrNotFound if there is none
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, error)
+
+	// Delete removes the file under key, deleting a missing file is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// ValidateKey rejects keys escaping the root of a store
+func ValidateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, `\`) || path.Clean(key) != key ||
+		key == ".." || strings.HasPrefix(key, "../") {
+		return ErrInvalidKey
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package stream
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Encoder writes rows one at a time
+type Encoder interface {
+	Encode(row any) error
+	// Flush writes buffered rows to the underlying writer
+	Flush() error
+}
+
+// NewEncoder creates an encoder writing rows to w in format
+func NewEncoder(w io.Writer, format Format) Encoder {
+	if format == FormatCSV {
+		return &csvEncoder{writer: csv.NewWriter(w)}
+	}
+	return &ndjsonEncoder{encoder: json.NewEncoder(w)}
+}
+
+// ndjsonEncoder writes one JSON document per line
+type ndjsonEncoder struct {
+	encoder *json.Encoder
+}
+
+func (e *ndjsonEncoder) Encode(row any) error {
+	return e.encoder.Encode(row)
+}
+
+func (e *ndjsonEncoder) Flush() error {
+	return nil
+}
+
+// csvEncoder writes struct rows as CSV records, with a header row named after the json tags.
+// Slices are joined with ";", nested values are written as JSON and nil pointers as empty cells.
+type csvEncoder struct {
+	writer  *csv.Writer
+	rowType reflect.Type
+	fields  []int
+}
+
+func (e *csvEncoder) Encode(row any) error {
+	value := reflect.Indirect(reflect.ValueOf(row))
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("csv rows must be structs, got %T", row)
+	}
+
+	if e.rowType == nil {
+		e.rowType = value.Type()
+		header := e.header()
+		if err := e.writer.Write(header); err != nil {
+			return err
+		}
+	} else if value.Type() != e.rowType {
+		return fmt.Errorf("csv rows must share a type, got %s after %s", value.Type(), e.rowType)
+	}
+
+	record := make([]string, len(e.fields))
+	for i, field := range e.fields {
+		cell, err := formatCell(value.Field(field))
+		if err != nil {
+			return err
+		}
+		record[i] = cell
+	}
+	return e.writer.Write(record)
+}
+
+func (e *csvEncoder) Flush() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// header picks the exported fields not tagged json:"-" and names them after their json tag
+func (e *csvEncoder) header() []string {
+	var header []string
+	for i := 0; i < e.rowType.NumField(); i++ {
+		field := e.rowType.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		e.fields = append(e.fields, i)
+		header = append(header, name)
+	}
+	return header
+}
+
+func formatCell(value reflect.Value) (string, error) {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return "", nil
+		}
+		value = value.Elem()
+	}
+
+	if t, ok := value.Interface().(time.Time); ok {
+		return t.Format(time.RFC3339), nil
+	}
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			return string(value.Bytes()), nil
+		}
+		cells := make([]string, value.Len())
+		for i := range cells {
+			cell, err := formatCell(value.Index(i))
+			if err != nil {
+				return "", err
+			}
+			cells[i] = cell
+		}
+		return strings.Join(cells, ";"), nil
+	case reflect.Struct, reflect.Map:
+		data, err := json.Marshal(value.Interface())
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	default:
+		return fmt.Sprint(value.Interface()), nil
+	}
+}
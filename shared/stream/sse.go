@@ -0,0 +1,59 @@
+package stream
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HeartbeatInterval is how often an idle event stream sends a comment so proxies keep it open
+const HeartbeatInterval = 15 * time.Second
+
+// Event is a server-sent event; Data is sent as JSON
+type Event struct {
+	Name string
+	Data any
+}
+
+// SSE serves a server-sent event stream: it sends initial, then an event for every value of values
+// until values is closed or the client goes away. The write timeout of the server is lifted for the
+// connection, which stays open.
+func SSE[T any](c *gin.Context, initial []Event, values <-chan T, toEvent func(T) Event) {
+	// not every writer supports deadlines, those don't have one to lift
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// tell nginx not to buffer the stream
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	for _, event := range initial {
+		c.SSEvent(event.Name, event.Data)
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(HeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case value, ok := <-values:
+			if !ok {
+				return
+			}
+			event := toEvent(value)
+			c.SSEvent(event.Name, event.Data)
+		case <-heartbeat.C:
+			if _, err := io.WriteString(c.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
@@ -0,0 +1,99 @@
+package stream
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// Format is the media type of a streamed list
+type Format string
+
+const (
+	FormatNDJSON Format = "application/x-ndjson"
+	FormatCSV    Format = "text/csv"
+)
+
+// flushEvery is how many rows are buffered before they are sent to the client
+const flushEvery = 100
+
+// Negotiate returns the streaming format asked for with the Accept header, or with the format
+// query parameter ("ndjson" or "csv") for clients that cannot set headers. ok is false when the
+// client wants the regular JSON response.
+func Negotiate(c *gin.Context) (format Format, ok bool) {
+	switch c.Query("format") {
+	case "ndjson":
+		return FormatNDJSON, true
+	case "csv":
+		return FormatCSV, true
+	}
+
+	switch c.NegotiateFormat(gin.MIMEJSON, string(FormatNDJSON), string(FormatCSV)) {
+	case string(FormatNDJSON):
+		return FormatNDJSON, true
+	case string(FormatCSV):
+		return FormatCSV, true
+	}
+	return "", false
+}
+
+// EncodeFunc sends a row to the client
+type EncodeFunc func(row any) error
+
+// Write streams the rows produced by produce to the client, flushing as it goes so memory stays
+// flat whatever the number of rows. Headers are sent with the first row: if produce fails before,
+// the error goes through the regular error response. A failure after that can only cut the
+// stream short, it is logged.
+func Write(c *gin.Context, format Format, name string, produce func(encode EncodeFunc) error) {
+	var encoder Encoder
+	rows := 0
+
+	start := func() {
+		c.Header("Content-Type", string(format)+"; charset=utf-8")
+		if format == FormatCSV {
+			c.Header("Content-Disposition", `attachment; filename="`+name+`.csv"`)
+		}
+		c.Status(http.StatusOK)
+		encoder = NewEncoder(c.Writer, format)
+	}
+
+	err := produce(func(row any) error {
+		if encoder == nil {
+			start()
+		}
+		if err := encoder.Encode(row); err != nil {
+			return err
+		}
+
+		rows++
+		if rows%flushEvery == 0 {
+			if err := encoder.Flush(); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+		}
+		return c.Request.Context().Err()
+	})
+
+	if encoder == nil {
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		start()
+	}
+
+	if flushErr := encoder.Flush(); flushErr != nil {
+		err = errors.Join(err, flushErr)
+	}
+	c.Writer.Flush()
+
+	if err != nil {
+		logger.Error(c.Request.Context(), "List stream interrupted",
+			logger.F("stream", name),
+			logger.F("rows", rows),
+			logger.F("error", err))
+	}
+}
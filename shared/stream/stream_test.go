@@ -0,0 +1,128 @@
+package stream
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init(&logger.Config{Output: io.Discard})
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+type row struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Tags      []string  `json:"tags"`
+	Note      *string   `json:"note,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Secret    string    `json:"-"`
+}
+
+func serve(t *testing.T, target, accept string, produce func(encode EncodeFunc) error) *httptest.ResponseRecorder {
+	t.Helper()
+	router := gin.New()
+	router.GET("/rows", func(c *gin.Context) {
+		format, ok := Negotiate(c)
+		if !ok {
+			c.JSON(http.StatusOK, "json")
+			return
+		}
+		Write(c, format, "rows", produce)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func rows(n int) func(encode EncodeFunc) error {
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	return func(encode EncodeFunc) error {
+		for i := 1; i <= n; i++ {
+			if err := encode(row{ID: int64(i), Name: "row, quoted", Tags: []string{"a", "b"}, CreatedAt: createdAt, Secret: "x"}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestWrite(t *testing.T) {
+	t.Run("ndjson", func(t *testing.T) {
+		rec := serve(t, "/rows", "application/x-ndjson", rows(2))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/x-ndjson; charset=utf-8", rec.Header().Get("Content-Type"))
+		line := `{"id":%d,"name":"row, quoted","tags":["a","b"],"created_at":"2025-01-02T03:04:05Z"}` + "\n"
+		assert.Equal(t, fmt.Sprintf(line, 1)+fmt.Sprintf(line, 2), rec.Body.String())
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		rec := serve(t, "/rows?format=csv", "", rows(2))
+
+		assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="rows.csv"`, rec.Header().Get("Content-Disposition"))
+		assert.Equal(t, "id,name,tags,note,created_at\n"+
+			"1,\"row, quoted\",a;b,,2025-01-02T03:04:05Z\n"+
+			"2,\"row, quoted\",a;b,,2025-01-02T03:04:05Z\n", rec.Body.String())
+	})
+
+	t.Run("json stays the default", func(t *testing.T) {
+		rec := serve(t, "/rows", "*/*", rows(1))
+		assert.Equal(t, `"json"`, rec.Body.String())
+	})
+
+	t.Run("errors before the first row use the error response", func(t *testing.T) {
+		var gotErr error
+		router := gin.New()
+		router.GET("/rows", func(c *gin.Context) {
+			Write(c, FormatNDJSON, "rows", func(encode EncodeFunc) error {
+				return errors.New("invalid filter")
+			})
+			gotErr = c.Errors.Last()
+		})
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rows", nil))
+
+		assert.EqualError(t, gotErr, "invalid filter")
+		assert.Empty(t, rec.Header().Get("Content-Type"))
+	})
+}
+
+func TestSSE(t *testing.T) {
+	values := make(chan int, 2)
+	values <- 1
+	values <- 2
+	close(values)
+
+	router := gin.New()
+	router.GET("/events", func(c *gin.Context) {
+		SSE(c, []Event{{Name: "snapshot", Data: map[string]int{"count": 0}}}, values, func(v int) Event {
+			return Event{Name: "update", Data: map[string]int{"value": v}}
+		})
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events", nil))
+
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "event:snapshot\ndata:{\"count\":0}\n\n"+
+		"event:update\ndata:{\"value\":1}\n\n"+
+		"event:update\ndata:{\"value\":2}\n\n", rec.Body.String())
+}
@@ -0,0 +1,283 @@
+// Package strictjson binds JSON request bodies in place of the JSON binding of gin. Decoding errors
+// are reported with the path of the offending field rather than as internal errors, and strict
+// requests reject the fields their payload does not have, so a typo like "contnet" is not silently
+// dropped.
+package strictjson
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"tixgo/shared/errstack"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// strictAll makes every request strict, see Install
+var strictAll atomic.Bool
+
+// Install replaces the JSON binding of gin, used by ShouldBindJSON and ShouldBind, with Binding.
+// strict makes every request strict, otherwise only the routes behind Require are.
+func Install(strict bool) {
+	strictAll.Store(strict)
+	binding.JSON = Binding{}
+}
+
+type strictKey struct{}
+
+// Require makes the requests of the routes it guards strict whatever the configuration
+func Require() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), strictKey{}, true))
+		c.Next()
+	}
+}
+
+// IsStrict tells whether the request of ctx rejects unknown fields
+func IsStrict(ctx context.Context) bool {
+	strict, _ := ctx.Value(strictKey{}).(bool)
+	return strict || strictAll.Load()
+}
+
+// Binding decodes JSON bodies, rejecting unknown fields and trailing data for strict requests, then
+// validates them like the binding of gin
+type Binding struct{}
+
+// Name returns the name of the binding
+func (Binding) Name() string {
+	return "json"
+}
+
+// Bind decodes the body of the request into obj
+func (Binding) Bind(req *http.Request, obj any) error {
+	if req == nil || req.Body == nil {
+		return errstack.New(syserr.ValidationCode, "a JSON body is required")
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return errstack.Wrap(err, syserr.InvalidArgumentCode, "failed to read the request body")
+	}
+	return decode(body, obj, IsStrict(req.Context()))
+}
+
+// BindBody decodes a body already read into obj, strict only when every request is
+func (Binding) BindBody(body []byte, obj any) error {
+	return decode(body, obj, strictAll.Load())
+}
+
+func decode(body []byte, obj any, strict bool) error {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return errstack.New(syserr.ValidationCode, "a JSON body is required")
+	}
+
+	if strict {
+		if err := checkFields(body, obj); err != nil {
+			return err
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if err := decoder.Decode(obj); err != nil {
+		return decodeError(err)
+	}
+	if strict {
+		if _, err := decoder.Token(); err != io.EOF {
+			return errstack.New(syserr.ValidationCode, "unexpected data after the JSON body")
+		}
+	}
+
+	if binding.Validator == nil {
+		return nil
+	}
+	return binding.Validator.ValidateStruct(obj)
+}
+
+// decodeError reports a decoding error with where it happened, without the Go types encoding/json names
+func decodeError(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return errstack.New(syserr.ValidationCode, fmt.Sprintf("malformed JSON at offset %d", syntaxErr.Offset))
+	case errors.As(err, &typeErr):
+		if typeErr.Field == "" {
+			return errstack.New(syserr.ValidationCode, fmt.Sprintf("the body must be %s, not %s", describe(typeErr.Type), typeErr.Value))
+		}
+		return errstack.New(syserr.ValidationCode, fmt.Sprintf("%s must be %s, not %s", typeErr.Field, describe(typeErr.Type), typeErr.Value))
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return errstack.New(syserr.ValidationCode, "the JSON body ends unexpectedly")
+	default:
+		return errstack.Wrap(err, syserr.ValidationCode, "invalid JSON body")
+	}
+}
+
+// describe names the JSON kind a Go type is decoded from
+func describe(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Struct, reflect.Map:
+		return "an object"
+	default:
+		return t.String()
+	}
+}
+
+// checkFields rejects the fields of the body obj has no field for, listing the path of each dotted
+// like encoding/json does, e.g. items.1.qty
+func checkFields(body []byte, obj any) error {
+	var value any
+	if err := json.NewDecoder(bytes.NewReader(body)).Decode(&value); err != nil {
+		return decodeError(err)
+	}
+
+	var unknown []string
+	walk(value, reflect.TypeOf(obj), "", &unknown)
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	sort.Strings(unknown)
+	if len(unknown) == 1 {
+		return errstack.New(syserr.ValidationCode, "unknown field "+unknown[0])
+	}
+	return errstack.New(syserr.ValidationCode, "unknown fields "+strings.Join(unknown, ", "))
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// walk collects the paths of the keys of value that t has no field for
+func walk(value any, t reflect.Type, path string, unknown *[]string) {
+	if t == nil {
+		return
+	}
+	for t.Kind() == reflect.Pointer {
+		if implementsUnmarshaler(t) {
+			return
+		}
+		t = t.Elem()
+	}
+	// types decoding themselves take whatever they are given
+	if implementsUnmarshaler(t) {
+		return
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		switch t.Kind() {
+		case reflect.Struct:
+			fields := jsonFields(t)
+			for key, field := range value {
+				fieldType, ok := lookupField(fields, key)
+				if !ok {
+					*unknown = append(*unknown, join(path, key))
+					continue
+				}
+				walk(field, fieldType, join(path, key), unknown)
+			}
+		case reflect.Map:
+			for key, field := range value {
+				walk(field, t.Elem(), join(path, key), unknown)
+			}
+		}
+
+	case []any:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, item := range value {
+				walk(item, t.Elem(), join(path, strconv.Itoa(i)), unknown)
+			}
+		}
+	}
+}
+
+func implementsUnmarshaler(t reflect.Type) bool {
+	return t.Implements(jsonUnmarshalerType) || t.Implements(textUnmarshalerType) ||
+		reflect.PointerTo(t).Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// jsonFields returns the types of the fields of a struct by JSON name, promoting the fields of
+// embedded structs like encoding/json does
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for key, fieldType := range jsonFields(embedded) {
+					if _, ok := fields[key]; !ok {
+						fields[key] = fieldType
+					}
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// lookupField finds the field of a key, an exact match first then case-insensitively like encoding/json
+func lookupField(fields map[string]reflect.Type, key string) (reflect.Type, bool) {
+	if fieldType, ok := fields[key]; ok {
+		return fieldType, true
+	}
+	for name, fieldType := range fields {
+		if strings.EqualFold(name, key) {
+			return fieldType, true
+		}
+	}
+	return nil, false
+}
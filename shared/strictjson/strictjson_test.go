@@ -0,0 +1,108 @@
+package strictjson
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/duongptryu/gox/syserr"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type item struct {
+	TicketTypeID int64 `json:"ticket_type_id"`
+	Quantity     int   `json:"quantity" binding:"min=1"`
+}
+
+type audit struct {
+	Note string `json:"note"`
+}
+
+type payload struct {
+	audit
+	Name      string            `json:"name" binding:"required"`
+	Items     []item            `json:"items"`
+	Labels    map[string]string `json:"labels"`
+	StartsAt  time.Time         `json:"starts_at"`
+	CreatedBy int64             `json:"-"`
+	Legacy    string
+}
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+// bind binds body as a request of a route behind Require when strict
+func bind(t *testing.T, body string, strict bool) (*payload, error) {
+	t.Helper()
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	if strict {
+		Require()(c)
+	}
+
+	var p payload
+	return &p, Binding{}.Bind(c.Request, &p)
+}
+
+func requireValidation(t *testing.T, err error, message string) {
+	t.Helper()
+	var sysErr *syserr.Error
+	require.ErrorAs(t, err, &sysErr)
+	assert.Equal(t, syserr.ValidationCode, sysErr.Code())
+	assert.Equal(t, message, sysErr.Error())
+}
+
+func TestBind_UnknownFields(t *testing.T) {
+	body := `{"name":"Launch","contnet":"x","note":"ok","Legacy":"y","items":[{"ticket_type_id":1,"quantity":2},{"qty":1,"quantity":1}],"labels":{"any":"thing"},"starts_at":"2026-01-02T15:04:05Z"}`
+
+	p, err := bind(t, body, false)
+	require.NoError(t, err, "unknown fields are dropped by default")
+	assert.Equal(t, "Launch", p.Name)
+
+	_, err = bind(t, body, true)
+	requireValidation(t, err, "unknown fields contnet, items.1.qty")
+
+	_, err = bind(t, `{"name":"Launch","NAME":"again","created_by":1}`, true)
+	requireValidation(t, err, "unknown field created_by")
+}
+
+func TestBind_Errors(t *testing.T) {
+	_, err := bind(t, `{"name":"Launch","items":[{"quantity":"two"}]}`, false)
+	requireValidation(t, err, "items.0.quantity must be an integer, not string")
+
+	_, err = bind(t, `["Launch"]`, false)
+	requireValidation(t, err, "the body must be an object, not array")
+
+	_, err = bind(t, `{"name":`, false)
+	requireValidation(t, err, "the JSON body ends unexpectedly")
+
+	_, err = bind(t, `{"name" "Launch"}`, false)
+	requireValidation(t, err, "malformed JSON at offset 9")
+
+	_, err = bind(t, "  ", false)
+	requireValidation(t, err, "a JSON body is required")
+
+	_, err = bind(t, `{"name":"Launch"} {"name":"Other"}`, true)
+	requireValidation(t, err, "unexpected data after the JSON body")
+}
+
+func TestBind_Validates(t *testing.T) {
+	_, err := bind(t, `{"items":[{"quantity":1}]}`, true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Name")
+}
+
+func TestInstall(t *testing.T) {
+	t.Cleanup(func() { strictAll.Store(false) })
+
+	Install(true)
+	_, err := bind(t, `{"name":"Launch","contnet":"x"}`, false)
+	requireValidation(t, err, "unknown field contnet")
+}
@@ -0,0 +1,165 @@
+package webhook
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HMACVerifier verifies hex encoded HMAC-SHA256 signatures, the scheme of the payment provider.
+// With a timestamp header, "<timestamp>.<body>" is signed so the timestamp can't be replaced.
+type HMACVerifier struct {
+	secret          []byte
+	signatureHeader string
+	timestampHeader string
+}
+
+// NewHMACVerifier creates a verifier of the signature in signatureHeader. timestampHeader holds the
+// unix time the delivery was signed at; leave it empty if the provider does not send one.
+func NewHMACVerifier(secret, signatureHeader, timestampHeader string) *HMACVerifier {
+	return &HMACVerifier{
+		secret:          []byte(secret),
+		signatureHeader: signatureHeader,
+		timestampHeader: timestampHeader,
+	}
+}
+
+func (v *HMACVerifier) Verify(r *http.Request, body []byte) (time.Time, error) {
+	// tolerate the "sha256=" prefix some providers add
+	signature, err := hex.DecodeString(strings.TrimPrefix(r.Header.Get(v.signatureHeader), "sha256="))
+	if err != nil || len(signature) == 0 {
+		return time.Time{}, ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, v.secret)
+	var signedAt time.Time
+	if v.timestampHeader != "" {
+		timestamp := r.Header.Get(v.timestampHeader)
+		if signedAt, err = parseUnix(timestamp); err != nil {
+			return time.Time{}, err
+		}
+		mac.Write([]byte(timestamp + "."))
+	}
+	mac.Write(body)
+
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return time.Time{}, ErrInvalidSignature
+	}
+	return signedAt, nil
+}
+
+const (
+	sendGridSignatureHeader = "X-Twilio-Email-Event-Webhook-Signature"
+	sendGridTimestampHeader = "X-Twilio-Email-Event-Webhook-Timestamp"
+)
+
+// SendGridVerifier verifies the signed event webhook of SendGrid: an ECDSA signature of the
+// timestamp followed by the body
+type SendGridVerifier struct {
+	publicKey *ecdsa.PublicKey
+}
+
+// NewSendGridVerifier creates a verifier from the base64 public key shown in the SendGrid mail settings
+func NewSendGridVerifier(publicKey string) (*SendGridVerifier, error) {
+	der, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode sendgrid public key: %w", err)
+	}
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sendgrid public key: %w", err)
+	}
+	ecdsaKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("sendgrid public key is a %T, not an ECDSA key", key)
+	}
+	return &SendGridVerifier{publicKey: ecdsaKey}, nil
+}
+
+func (v *SendGridVerifier) Verify(r *http.Request, body []byte) (time.Time, error) {
+	signature, err := base64.StdEncoding.DecodeString(r.Header.Get(sendGridSignatureHeader))
+	if err != nil || len(signature) == 0 {
+		return time.Time{}, ErrInvalidSignature
+	}
+	timestamp := r.Header.Get(sendGridTimestampHeader)
+	signedAt, err := parseUnix(timestamp)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	digest := sha256.New()
+	digest.Write([]byte(timestamp))
+	digest.Write(body)
+	if !ecdsa.VerifyASN1(v.publicKey, digest.Sum(nil), signature) {
+		return time.Time{}, ErrInvalidSignature
+	}
+	return signedAt, nil
+}
+
+const twilioSignatureHeader = "X-Twilio-Signature"
+
+// TwilioVerifier verifies the status callbacks of Twilio SMS: a base64 HMAC-SHA1 of the URL it called
+// followed by the sorted form parameters. Twilio signs no timestamp, so replays are only caught
+// by deduplication.
+type TwilioVerifier struct {
+	authToken []byte
+	publicURL string
+}
+
+// NewTwilioVerifier creates a verifier for the account of authToken. publicURL is the scheme and host
+// Twilio calls, e.g. "https://api.tixgo.io", since the server may sit behind a proxy.
+func NewTwilioVerifier(authToken, publicURL string) *TwilioVerifier {
+	return &TwilioVerifier{
+		authToken: []byte(authToken),
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+	}
+}
+
+func (v *TwilioVerifier) Verify(r *http.Request, body []byte) (time.Time, error) {
+	signature, err := base64.StdEncoding.DecodeString(r.Header.Get(twilioSignatureHeader))
+	if err != nil || len(signature) == 0 {
+		return time.Time{}, ErrInvalidSignature
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return time.Time{}, ErrInvalidSignature
+	}
+
+	keys := make([]string, 0, len(form))
+	for key := range form {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	mac := hmac.New(sha1.New, v.authToken)
+	mac.Write([]byte(v.publicURL + r.URL.RequestURI()))
+	for _, key := range keys {
+		for _, value := range form[key] {
+			mac.Write([]byte(key + value))
+		}
+	}
+
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return time.Time{}, ErrInvalidSignature
+	}
+	return time.Time{}, nil
+}
+
+func parseUnix(timestamp string) (time.Time, error) {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return time.Time{}, ErrInvalidSignature
+	}
+	return time.Unix(seconds, 0), nil
+}
@@ -0,0 +1,107 @@
+package webhook
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func hmacHex(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestHMACVerifier(t *testing.T) {
+	body := []byte(`{"id":"pay_1"}`)
+
+	t.Run("without timestamp", func(t *testing.T) {
+		v := NewHMACVerifier("secret", "X-Signature", "")
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+
+		req.Header.Set("X-Signature", hmacHex("secret", string(body)))
+		signedAt, err := v.Verify(req, body)
+		require.NoError(t, err)
+		assert.True(t, signedAt.IsZero())
+
+		_, err = v.Verify(req, []byte(`{"id":"pay_2"}`))
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("with timestamp", func(t *testing.T) {
+		v := NewHMACVerifier("secret", "X-Signature", "X-Timestamp")
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set("X-Timestamp", "1700000000")
+		req.Header.Set("X-Signature", hmacHex("secret", "1700000000."+string(body)))
+
+		signedAt, err := v.Verify(req, body)
+		require.NoError(t, err)
+		assert.Equal(t, time.Unix(1700000000, 0), signedAt)
+
+		req.Header.Set("X-Timestamp", "1800000000")
+		_, err = v.Verify(req, body)
+		assert.ErrorIs(t, err, ErrInvalidSignature, "the timestamp is signed")
+	})
+}
+
+func TestSendGridVerifier(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	v, err := NewSendGridVerifier(base64.StdEncoding.EncodeToString(der))
+	require.NoError(t, err)
+
+	body := []byte(`[{"email":"a@example.com","event":"delivered"}]`)
+	digest := sha256.Sum256(append([]byte("1700000000"), body...))
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set(sendGridTimestampHeader, "1700000000")
+	req.Header.Set(sendGridSignatureHeader, base64.StdEncoding.EncodeToString(signature))
+
+	signedAt, err := v.Verify(req, body)
+	require.NoError(t, err)
+	assert.Equal(t, time.Unix(1700000000, 0), signedAt)
+
+	_, err = v.Verify(req, []byte(`[]`))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = NewSendGridVerifier("not a key")
+	assert.Error(t, err)
+}
+
+func TestTwilioVerifier(t *testing.T) {
+	v := NewTwilioVerifier("token", "https://api.tixgo.io/")
+	body := "MessageStatus=delivered&MessageSid=SM1&To=%2B84901234567"
+
+	mac := hmac.New(sha1.New, []byte("token"))
+	mac.Write([]byte("https://api.tixgo.io/webhooks/sms?x=1" + "MessageSidSM1" + "MessageStatusdelivered" + "To+84901234567"))
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/sms?x=1", strings.NewReader(body))
+	req.Header.Set(twilioSignatureHeader, signature)
+
+	signedAt, err := v.Verify(req, []byte(body))
+	require.NoError(t, err)
+	assert.True(t, signedAt.IsZero(), "twilio signs no timestamp")
+
+	_, err = v.Verify(req, []byte("MessageStatus=failed&MessageSid=SM1&To=%2B84901234567"))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
@@ -0,0 +1,162 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"tixgo/shared/dedup"
+	"tixgo/shared/httpresponse"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/messaging"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// DefaultReplayWindow bounds how old a signed delivery may be, and how long a delivery is remembered
+	DefaultReplayWindow = 5 * time.Minute
+	// DefaultMaxBodySize is the largest delivery accepted
+	DefaultMaxBodySize int64 = 1 << 20
+)
+
+var (
+	// ErrInvalidSignature is returned by verifiers when a delivery is not signed by the provider
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrStale is returned when a delivery was signed outside the replay window
+	ErrStale = errors.New("webhook signed outside the replay window")
+)
+
+// Verifier authenticates the deliveries of a provider
+type Verifier interface {
+	// Verify checks the signature of a delivery and returns when the provider signed it,
+	// the zero time if the provider does not sign a timestamp
+	Verify(r *http.Request, body []byte) (time.Time, error)
+}
+
+// EventWebhookReceived is published for every verified delivery. Integrations subscribe to it
+// and process the deliveries of their Source; the provider already got its acknowledgement.
+type EventWebhookReceived struct {
+	Source      string    `json:"source"`
+	DeliveryID  string    `json:"delivery_id"`
+	ReceivedAt  time.Time `json:"received_at"`
+	ContentType string    `json:"content_type"`
+	// Payload is the raw body, exactly as signed
+	Payload []byte `json:"payload"`
+}
+
+// Config configures the endpoint of a provider
+type Config struct {
+	// Source names the provider in published events, e.g. "sendgrid"
+	Source   string
+	Verifier Verifier
+	// ReplayWindow rejects deliveries signed longer ago and accepts a delivery once within it,
+	// DefaultReplayWindow if zero
+	ReplayWindow time.Duration
+	// MaxBodySize is DefaultMaxBodySize if zero
+	MaxBodySize int64
+}
+
+// Handler receives the deliveries of a provider: it verifies them, drops replays and publishes them
+// on the event bus, acknowledging with 202. Failures are answered with a real status code since
+// providers retry on non 2xx responses.
+func Handler(cfg Config, deduplicator dedup.Deduplicator, eventBus messaging.EventBus) gin.HandlerFunc {
+	if cfg.ReplayWindow <= 0 {
+		cfg.ReplayWindow = DefaultReplayWindow
+	}
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = DefaultMaxBodySize
+	}
+
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		body, err := readBody(c, cfg.MaxBodySize)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				httpresponse.Error(c, http.StatusRequestEntityTooLarge, "payload_too_large", "webhook payload is too large", nil)
+				return
+			}
+			httpresponse.Error(c, http.StatusBadRequest, "invalid_argument", "failed to read webhook payload", nil)
+			return
+		}
+
+		signedAt, err := cfg.Verifier.Verify(c.Request, body)
+		if err == nil && !signedAt.IsZero() {
+			if age := time.Since(signedAt); age > cfg.ReplayWindow || age < -cfg.ReplayWindow {
+				err = ErrStale
+			}
+		}
+		if err != nil {
+			logger.Warning(ctx, "Rejected webhook", logger.F("source", cfg.Source), logger.F("error", err))
+			httpresponse.Error(c, http.StatusUnauthorized, "unauthenticated", err.Error(), nil)
+			return
+		}
+
+		deliveryID := deliveryID(body)
+		event := &EventWebhookReceived{
+			Source:      cfg.Source,
+			DeliveryID:  deliveryID,
+			ReceivedAt:  time.Now().UTC(),
+			ContentType: c.ContentType(),
+			Payload:     body,
+		}
+
+		if !claim(ctx, deduplicator, cfg, deliveryID) {
+			// already handed off, acknowledge so the provider stops retrying
+			httpresponse.Success(c, http.StatusOK, gin.H{"delivery_id": deliveryID})
+			return
+		}
+
+		if err := eventBus.PublishEvent(ctx, event); err != nil {
+			logger.Error(ctx, "Failed to publish webhook", logger.F("source", cfg.Source), logger.F("delivery_id", deliveryID), logger.F("error", err))
+			if err := deduplicator.Forget(ctx, dedupKey(cfg.Source, deliveryID)); err != nil {
+				logger.Warning(ctx, "Failed to forget webhook delivery", logger.F("delivery_id", deliveryID), logger.F("error", err))
+			}
+			httpresponse.Error(c, http.StatusServiceUnavailable, "unavailable", "webhook could not be accepted, retry later", nil)
+			return
+		}
+
+		httpresponse.Success(c, http.StatusAccepted, gin.H{"delivery_id": deliveryID})
+	}
+}
+
+// readBody captures the raw body, which signatures are computed over, and restores it for the verifier
+func readBody(c *gin.Context, maxBodySize int64) ([]byte, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBodySize))
+	if err != nil {
+		return nil, err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// claim reports whether the delivery is seen for the first time within the replay window.
+// When redis is unavailable the delivery is let through: integrations must be idempotent anyway.
+func claim(ctx context.Context, deduplicator dedup.Deduplicator, cfg Config, deliveryID string) bool {
+	err := deduplicator.Claim(ctx, dedupKey(cfg.Source, deliveryID), cfg.ReplayWindow)
+	if errors.Is(err, dedup.ErrDuplicate) {
+		logger.Info(ctx, "Dropped replayed webhook", logger.F("source", cfg.Source), logger.F("delivery_id", deliveryID))
+		return false
+	}
+	if err != nil {
+		logger.Warning(ctx, "Webhook deduplication failed", logger.F("source", cfg.Source), logger.F("error", err))
+	}
+	return true
+}
+
+// deliveryID identifies a delivery by its content, so a provider retry of the same payload is a replay
+func deliveryID(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:16])
+}
+
+func dedupKey(source, deliveryID string) string {
+	return dedup.Key("webhook_"+source, deliveryID)
+}
@@ -0,0 +1,113 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"tixgo/shared/dedup"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/duongptryu/gox/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init(&logger.Config{Output: io.Discard})
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+type fakeEventBus struct {
+	published []any
+	err       error
+}
+
+func (b *fakeEventBus) PublishEvent(_ context.Context, evt any) error {
+	if b.err != nil {
+		return b.err
+	}
+	b.published = append(b.published, evt)
+	return nil
+}
+
+func newTestRouter(t *testing.T, eventBus *fakeEventBus) *gin.Engine {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	router := gin.New()
+	router.POST("/webhooks/payment", Handler(Config{
+		Source:      "payment",
+		Verifier:    NewHMACVerifier("secret", "X-Signature", "X-Timestamp"),
+		MaxBodySize: 64,
+	}, dedup.NewRedisDeduplicator(client), eventBus))
+	return router
+}
+
+func deliver(router *gin.Engine, body string, signedAt time.Time, secret string) *httptest.ResponseRecorder {
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/payment", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Timestamp", timestamp)
+	req.Header.Set("X-Signature", "sha256="+hmacHex(secret, timestamp+"."+body))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandler(t *testing.T) {
+	body := `{"id":"pay_1","status":"succeeded"}`
+
+	t.Run("publishes verified deliveries once", func(t *testing.T) {
+		eventBus := &fakeEventBus{}
+		router := newTestRouter(t, eventBus)
+
+		rec := deliver(router, body, time.Now(), "secret")
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		require.Len(t, eventBus.published, 1)
+
+		event := eventBus.published[0].(*EventWebhookReceived)
+		assert.Equal(t, "payment", event.Source)
+		assert.Equal(t, body, string(event.Payload))
+		assert.Equal(t, "application/json", event.ContentType)
+		assert.NotEmpty(t, event.DeliveryID)
+
+		rec = deliver(router, body, time.Now(), "secret")
+		assert.Equal(t, http.StatusOK, rec.Code, "a retry is acknowledged")
+		assert.Len(t, eventBus.published, 1, "a retry is not published again")
+	})
+
+	t.Run("rejects unverified deliveries", func(t *testing.T) {
+		eventBus := &fakeEventBus{}
+		router := newTestRouter(t, eventBus)
+
+		assert.Equal(t, http.StatusUnauthorized, deliver(router, body, time.Now(), "other").Code)
+		assert.Equal(t, http.StatusUnauthorized, deliver(router, body, time.Now().Add(-10*time.Minute), "secret").Code, "stale")
+		assert.Equal(t, http.StatusUnauthorized, deliver(router, body, time.Now().Add(10*time.Minute), "secret").Code, "from the future")
+		assert.Equal(t, http.StatusRequestEntityTooLarge, deliver(router, strings.Repeat("x", 65), time.Now(), "secret").Code)
+		assert.Empty(t, eventBus.published)
+	})
+
+	t.Run("lets the provider retry when publishing fails", func(t *testing.T) {
+		eventBus := &fakeEventBus{err: errors.New("kafka down")}
+		router := newTestRouter(t, eventBus)
+
+		assert.Equal(t, http.StatusServiceUnavailable, deliver(router, body, time.Now(), "secret").Code)
+
+		eventBus.err = nil
+		assert.Equal(t, http.StatusAccepted, deliver(router, body, time.Now(), "secret").Code)
+		assert.Len(t, eventBus.published, 1)
+	})
+}
@@ -0,0 +1,200 @@
+package syserr
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+
+	pkgError "github.com/pkg/errors"
+)
+
+// MaxStackDepth is the most frames the stack of an error has, from where it was created
+const MaxStackDepth = 32
+
+type Error struct {
+	Message string
+	code    Code
+	// Stack is a stack set by hand; the one captured by New is only resolved by StackTrace
+	Stack        []*ErrorStackItem
+	fields       []*Field
+	WrappedError error
+	// pcs are the program counters of the stack captured by New
+	pcs []uintptr
+}
+
+type ErrorStackItem struct {
+	File     string
+	Line     string
+	Function string
+}
+
+type Field struct {
+	Key   string
+	Value any
+}
+
+func F(key string, value any) *Field {
+	return &Field{
+		Key:   key,
+		Value: value,
+	}
+}
+
+// New creates an error with the stack of its caller. Only the program counters are captured, the
+// frames are resolved when the stack is read.
+func New(code Code, message string, fields ...*Field) *Error {
+	return NewSkip(1, code, message, fields...)
+}
+
+// NewSkip creates an error with a stack starting skip frames above the caller of NewSkip, for the
+// helpers creating errors on behalf of their own caller
+func NewSkip(skip int, code Code, message string, fields ...*Field) *Error {
+	err := NewWithoutStack(code, message, fields...)
+	pcs := make([]uintptr, MaxStackDepth)
+	// skip runtime.Callers and NewSkip
+	err.pcs = pcs[:runtime.Callers(skip+2, pcs)]
+	return err
+}
+
+// NewWithoutStack creates an error without a stack, for the errors of expected conditions and the
+// package-level errors, whose stack tells nothing
+func NewWithoutStack(code Code, message string, fields ...*Field) *Error {
+	return &Error{
+		Message: message,
+		code:    code,
+		fields:  fields,
+	}
+}
+
+func Wrap(err error, code Code, message string, fields ...*Field) *Error {
+	newError := NewSkip(1, code, message, fields...)
+	newError.WrappedError = err
+	return newError
+}
+func WrapAsIs(err error, message string, fields ...*Field) *Error {
+	newError := NewSkip(1, extractCodeFromGenericError(err), message, fields...)
+	newError.WrappedError = err
+	return newError
+}
+
+func (e *Error) Unwrap() error {
+	return e.WrappedError
+}
+
+func (e *Error) Code() Code {
+	return e.code
+}
+
+func (e *Error) Fields() []*Field {
+	return e.fields
+}
+
+// StackTrace returns the stack of the error, resolving the one captured by New on each call
+func (e *Error) StackTrace() []*ErrorStackItem {
+	if e.Stack != nil || len(e.pcs) == 0 {
+		return e.Stack
+	}
+	return resolveStack(e.pcs)
+}
+
+func (e *Error) Error() string {
+	if e.WrappedError != nil {
+		return fmt.Sprintf("%s: %s", e.Message, e.WrappedError.Error())
+	}
+	return e.Message
+}
+
+func (e *Error) StackFormatted() []string {
+	return formatStack(e.StackTrace())
+}
+
+func formatStack(stack []*ErrorStackItem) []string {
+	result := make([]string, len(stack))
+
+	for index, stackItem := range stack {
+		result[index] = fmt.Sprintf("%s:%s %s", stackItem.File, stackItem.Line, stackItem.Function)
+	}
+
+	return result
+}
+
+// resolveStack resolves program counters to the files, lines and functions of their frames
+func resolveStack(pcs []uintptr) []*ErrorStackItem {
+	stack := make([]*ErrorStackItem, 0, len(pcs))
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, &ErrorStackItem{
+			File:     frame.File,
+			Line:     strconv.Itoa(frame.Line),
+			Function: frame.Function,
+		})
+		if !more {
+			return stack
+		}
+	}
+}
+
+// extractStackFromGenericError returns the stack of an error of github.com/pkg/errors, if err wraps one
+func extractStackFromGenericError(err error) []*ErrorStackItem {
+	stackTrace := extractStackTraceFromGenericError(err)
+
+	result := make([]*ErrorStackItem, len(stackTrace))
+
+	for index, frame := range stackTrace {
+		result[index] = &ErrorStackItem{
+			File:     getFrameFilePath(frame),
+			Line:     fmt.Sprintf("%d", frame),
+			Function: fmt.Sprintf("%s", frame),
+		}
+	}
+
+	return result
+}
+
+type stackTracer interface {
+	StackTrace() pkgError.StackTrace
+}
+
+func extractStackTraceFromGenericError(err error) pkgError.StackTrace {
+	var result pkgError.StackTrace
+
+	var traceableError stackTracer
+	ok := errors.As(err, &traceableError)
+	if ok {
+		result = traceableError.StackTrace()
+	}
+
+	return result
+}
+
+func getFrameFilePath(frame pkgError.Frame) string {
+	frameString := strings.Split(fmt.Sprintf("%+s", frame), "\n\t")
+	return frameString[1]
+}
+
+func extractCodeFromGenericError(err error) Code {
+	if err == nil {
+		return InternalCode
+	}
+
+	for {
+		var sErr *Error
+		if errors.As(err, &sErr) {
+			return sErr.Code()
+		}
+
+		var unwrapError interface{ Unwrap() error }
+		if errors.As(err, &unwrapError) {
+			err = unwrapError.Unwrap()
+			if err == nil {
+				return InternalCode
+			}
+			continue
+		}
+
+		return InternalCode
+	}
+}